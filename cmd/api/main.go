@@ -1,22 +1,66 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/gofiber/fiber/v2"                                                // Framework web Fiber
-	"github.com/rakaarfi/attendance-system-be/configs"                           // Paket lokal untuk konfigurasi
-	v1 "github.com/rakaarfi/attendance-system-be/internal/api/v1"                // Paket lokal untuk routing API v1
-	"github.com/rakaarfi/attendance-system-be/internal/api/v1/handlers"          // Paket lokal untuk handler API v1
-	"github.com/rakaarfi/attendance-system-be/internal/database"                 // Paket lokal untuk koneksi database
-	applogger "github.com/rakaarfi/attendance-system-be/internal/logger"         // Paket lokal untuk setup logger (Zerolog)
-	appmiddleware "github.com/rakaarfi/attendance-system-be/internal/middleware" // Paket lokal untuk middleware global
-	"github.com/rakaarfi/attendance-system-be/internal/repository"               // Paket lokal untuk repository (akses data)
-	zlog "github.com/rs/zerolog/log"                                             // Logger global Zerolog (aliased as zlog)
+	"github.com/gofiber/fiber/v2"                                                    // Framework web Fiber
+	"github.com/jackc/pgx/v5/pgxpool"                                                // Connection pool PostgreSQL
+	"github.com/rakaarfi/attendance-system-be/configs"                               // Paket lokal untuk konfigurasi
+	v1 "github.com/rakaarfi/attendance-system-be/internal/api/v1"                    // Paket lokal untuk routing API v1
+	"github.com/rakaarfi/attendance-system-be/internal/api/v1/handlers"              // Paket lokal untuk handler API v1
+	"github.com/rakaarfi/attendance-system-be/internal/backup"                       // Paket lokal untuk backup database ke storage
+	"github.com/rakaarfi/attendance-system-be/internal/buildinfo"                    // Paket lokal untuk versi & info build (-ldflags)
+	"github.com/rakaarfi/attendance-system-be/internal/captcha"                      // Paket lokal untuk verifikasi CAPTCHA (opsional)
+	"github.com/rakaarfi/attendance-system-be/internal/database"                     // Paket lokal untuk koneksi database
+	"github.com/rakaarfi/attendance-system-be/internal/debugcapture"                 // Paket lokal untuk perekaman request/response debug
+	"github.com/rakaarfi/attendance-system-be/internal/degraded"                     // Paket lokal untuk mode degraded saat database tidak tersedia
+	"github.com/rakaarfi/attendance-system-be/internal/diagnostics"                  // Paket lokal untuk self-check startup & diagnostik sistem
+	"github.com/rakaarfi/attendance-system-be/internal/emailchange"                  // Paket lokal untuk konfirmasi ganti email
+	"github.com/rakaarfi/attendance-system-be/internal/errorreport"                  // Paket lokal untuk pelaporan panic ke Sentry/Glitchtip (opsional)
+	"github.com/rakaarfi/attendance-system-be/internal/events"                       // Paket lokal untuk bus domain event
+	"github.com/rakaarfi/attendance-system-be/internal/events/stream"                // Paket lokal untuk streaming event ke Kafka/NATS (opsional)
+	"github.com/rakaarfi/attendance-system-be/internal/events/subscribers"           // Paket lokal untuk subscriber event (audit, notifikasi, webhook)
+	"github.com/rakaarfi/attendance-system-be/internal/faceverify"                   // Paket lokal untuk verifikasi wajah saat check-in (opsional)
+	"github.com/rakaarfi/attendance-system-be/internal/geoip"                        // Paket lokal untuk lookup negara dari IP (opsional)
+	"github.com/rakaarfi/attendance-system-be/internal/inbox"                        // Paket lokal untuk inbox notifikasi in-app user
+	"github.com/rakaarfi/attendance-system-be/internal/instances"                    // Paket lokal untuk heartbeat versi aplikasi per instance
+	"github.com/rakaarfi/attendance-system-be/internal/invitation"                   // Paket lokal untuk onboarding lewat undangan
+	"github.com/rakaarfi/attendance-system-be/internal/jobs"                         // Paket lokal untuk job latar belakang periodik
+	"github.com/rakaarfi/attendance-system-be/internal/lock"                         // Paket lokal untuk lock terdistribusi antar instance
+	applogger "github.com/rakaarfi/attendance-system-be/internal/logger"             // Paket lokal untuk setup logger (Zerolog)
+	"github.com/rakaarfi/attendance-system-be/internal/loginalert"                   // Paket lokal untuk peringatan login tidak biasa
+	"github.com/rakaarfi/attendance-system-be/internal/maintenance"                  // Paket lokal untuk mode maintenance (503 untuk route non-admin)
+	"github.com/rakaarfi/attendance-system-be/internal/metrics"                      // Paket lokal untuk counter aplikasi (/admin/metrics)
+	appmiddleware "github.com/rakaarfi/attendance-system-be/internal/middleware"     // Paket lokal untuk middleware global
+	"github.com/rakaarfi/attendance-system-be/internal/models"                       // Paket lokal untuk model data
+	"github.com/rakaarfi/attendance-system-be/internal/notify"                       // Paket lokal untuk notifikasi HR
+	"github.com/rakaarfi/attendance-system-be/internal/openapi"                      // Paket lokal untuk spesifikasi OpenAPI runtime & validasi request
+	"github.com/rakaarfi/attendance-system-be/internal/otp"                          // Paket lokal untuk kode OTP SMS (verifikasi nomor HP, 2FA)
+	"github.com/rakaarfi/attendance-system-be/internal/outbox"                       // Paket lokal untuk transactional outbox efek samping event
+	"github.com/rakaarfi/attendance-system-be/internal/photos"                       // Paket lokal untuk pemrosesan & retensi foto check-in
+	"github.com/rakaarfi/attendance-system-be/internal/pii"                          // Paket lokal untuk enkripsi data pribadi (PII)
+	"github.com/rakaarfi/attendance-system-be/internal/push"                         // Paket lokal untuk push notification FCM/APNs
+	"github.com/rakaarfi/attendance-system-be/internal/rbac"                         // Paket lokal untuk hierarki role (pewarisan akses)
+	"github.com/rakaarfi/attendance-system-be/internal/reports"                      // Paket lokal untuk ekspor laporan di background & retensinya
+	"github.com/rakaarfi/attendance-system-be/internal/repository"                   // Paket lokal untuk repository (akses data)
+	"github.com/rakaarfi/attendance-system-be/internal/repository/memory"            // Paket lokal untuk repository di memori (SANDBOX_MODE)
+	sqliterepo "github.com/rakaarfi/attendance-system-be/internal/repository/sqlite" // Paket lokal untuk repository SQLite (DB_DRIVER=sqlite)
+	"github.com/rakaarfi/attendance-system-be/internal/session"                      // Paket lokal untuk pencabutan sesi (token_version)
+	"github.com/rakaarfi/attendance-system-be/internal/settings"                     // Paket lokal untuk pengaturan sistem runtime
+	"github.com/rakaarfi/attendance-system-be/internal/sms"                          // Paket lokal untuk pengiriman SMS (Twilio/Vonage, opsional)
+	"github.com/rakaarfi/attendance-system-be/internal/storage"                      // Paket lokal untuk penyimpanan file upload
+	"github.com/rakaarfi/attendance-system-be/internal/utils"                        // Paket lokal untuk utilitas (JWT, pagination)
+	"github.com/rakaarfi/attendance-system-be/internal/virusscan"                    // Paket lokal untuk pemindaian malware upload (opsional)
+	zlog "github.com/rs/zerolog/log"                                                 // Logger global Zerolog (aliased as zlog)
 
 	// Import untuk Swagger/OpenAPI documentation
-	_ "github.com/rakaarfi/attendance-system-be/docs" // Import side effect untuk registrasi docs Swagger yang digenerate (penting!)
-	fiberSwagger "github.com/swaggo/fiber-swagger"    // Middleware Fiber untuk menyajikan Swagger UI
+	"github.com/rakaarfi/attendance-system-be/docs" // Registrasi docs Swagger yang digenerate (penting!), juga sumber /api/v1/openapi.json
+	fiberSwagger "github.com/swaggo/fiber-swagger"  // Middleware Fiber untuk menyajikan Swagger UI
 )
 
 // --- Anotasi Global Swagger/OpenAPI ---
@@ -54,7 +98,14 @@ func main() {
 	// --- Langkah 1: Setup Logger (Zerolog) ---
 	// Menginisialisasi logger global (Zerolog) berdasarkan konfigurasi env vars (LOG_LEVEL, dll.).
 	// Mengembalikan io.Closer jika file logging diaktifkan.
-	logCloser := applogger.SetupLogger()
+	// Level per modul (LOG_LEVEL_<MODUL>) dan sampling dibaca lewat configs.LogConfig.
+	logCfg, err := configs.LoadLogConfig()
+	if err != nil {
+		// Logger belum siap; tulis ke Stderr lalu hentikan aplikasi.
+		fmt.Fprintf(os.Stderr, "[FATAL] Invalid logging configuration: %v\n", err)
+		os.Exit(1)
+	}
+	logCloser := applogger.SetupLogger(logCfg)
 	// Menjadwalkan penutupan file log (jika ada) saat fungsi main selesai.
 	if logCloser != nil {
 		defer func() {
@@ -67,48 +118,405 @@ func main() {
 	}
 	// Log pertama menggunakan Zerolog setelah setup selesai.
 	zlog.Info().Msg("Configuration loaded")
+	// Info build (versi, commit git, waktu build dari -ldflags) untuk log, /version, dan /admin/metrics.
+	build := buildinfo.Get()
+	metrics.SetBuildInfo(build)
+	zlog.Info().Str("commit", build.Commit).Str("build_time", build.BuildTime).Str("go_version", build.GoVersion).Msg("Build info")
+
+	// Masa berlaku, issuer, audience & toleransi jam token sesi (JWT_*).
+	jwtCfg, err := configs.LoadJWTConfig()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid JWT configuration")
+	}
+	if err := utils.ConfigureJWT(jwtCfg); err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid JWT configuration")
+	}
+
+	// Parameter hashing password Argon2id (ARGON2_*), divalidasi sekali di sini.
+	passwordHashCfg, err := configs.LoadPasswordHashConfig()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid password hashing configuration")
+	}
+	utils.ConfigurePasswordHashing(passwordHashCfg)
+
+	// Kunci enkripsi PII dari env/KMS; juga dipakai pipeline foto check-in, termasuk di sandbox.
+	piiProtector, err := pii.NewProtectorFromEnv()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid PII encryption configuration")
+	}
 
-	// --- Langkah 2: Koneksi ke Database (PostgreSQL) ---
-	// Membuat connection pool ke database PostgreSQL menggunakan konfigurasi dari env vars.
-	dbPool, err := database.NewPgxPool()
-	if err != nil {
-		// Jika koneksi gagal, log error fatal dan hentikan aplikasi.
-		zlog.Fatal().Err(err).Msg("Could not connect to the database")
-	}
-	// Menjadwalkan penutupan connection pool saat fungsi main selesai.
-	// Defer ini diletakkan setelah defer logger agar error penutupan DB masih bisa di-log.
-	defer dbPool.Close()
-	zlog.Info().Msg("Database connection pool established")
-
-	// --- Langkah 3: Inisialisasi Lapisan Repository ---
-	// Membuat instance konkret dari setiap repository, menyuntikkan (injecting)
-	// connection pool (dbPool) sebagai dependensi.
-	userRepo := repository.NewUserRepository(dbPool)
-	roleRepo := repository.NewRoleRepository(dbPool)
-	shiftRepo := repository.NewShiftRepository(dbPool)
-	scheduleRepo := repository.NewScheduleRepository(dbPool)
-	attendanceRepo := repository.NewAttendanceRepository(dbPool)
+	// SANDBOX_MODE menjalankan API tanpa PostgreSQL: semua repository memakai store memori berisi
+	// data contoh (lihat internal/repository/memory) yang hilang saat proses berhenti.
+	sandboxMode := configs.GetEnvBool("SANDBOX_MODE", false)
+	// DB_DRIVER memilih backend database: postgres (default) atau sqlite untuk instalasi kecil
+	// dengan satu instance (hanya pada binary yang di-build dengan tag sqlite).
+	dbDriver, err := database.DriverFromEnv()
+	if err != nil && !sandboxMode {
+		zlog.Fatal().Err(err).Msg("Invalid database configuration")
+	}
+	var dbPool *pgxpool.Pool
+	var repos repository.Repositories
+	switch {
+	case sandboxMode:
+		repos = memory.NewRepositories()
+		zlog.Warn().Msg("SANDBOX_MODE enabled: using in-memory repositories with seed data; all changes are lost on restart")
+	case dbDriver == database.DriverSQLite:
+		// --- Langkah 2 & 3 (SQLite): buka file database, terapkan migrations/sqlite, lalu
+		// buat repository SQLite. dbPool tetap nil, sehingga fitur yang butuh PostgreSQL
+		// (mode degraded, backup COPY, lock advisory) nonaktif seperti di sandbox.
+		sqliteDB, err := database.NewSQLiteDB()
+		if err != nil {
+			zlog.Fatal().Err(err).Msg("Could not open the SQLite database")
+		}
+		defer sqliteDB.Close()
+		repos = sqliterepo.NewRepositories(sqliteDB, piiProtector)
+	default:
+		// --- Langkah 2: Koneksi ke Database (PostgreSQL) ---
+		// Membuat connection pool ke database PostgreSQL menggunakan konfigurasi dari env vars.
+		// DATABASE_URLS dengan beberapa DSN (primary lalu standby) mengaktifkan failover otomatis.
+		var dbFailover *database.Failover
+		dbPool, dbFailover, err = database.NewPgxPoolWithFailover()
+		if err != nil {
+			// Jika koneksi gagal, log error fatal dan hentikan aplikasi.
+			zlog.Fatal().Err(err).Msg("Could not connect to the database")
+		}
+		// Menjadwalkan penutupan connection pool saat fungsi main selesai.
+		// Defer ini diletakkan setelah defer logger agar error penutupan DB masih bisa di-log.
+		defer dbPool.Close()
+		zlog.Info().Msg("Database connection pool established")
+		if dbFailover != nil {
+			go dbFailover.Start(context.Background())
+		}
+
+		// Read-replica opsional (DB_REPLICA_URL) untuk query laporan/listing.
+		replicaPool, err := database.NewReplicaPgxPool()
+		if err != nil {
+			zlog.Fatal().Err(err).Msg("Could not connect to the read-replica database")
+		}
+		if replicaPool != nil {
+			defer replicaPool.Close()
+			zlog.Info().Msg("Read-replica connection pool established")
+		}
+		dbPools := repository.Pools{Primary: dbPool, Replica: replicaPool}
+
+		// --- Langkah 3: Inisialisasi Lapisan Repository ---
+		// Membuat instance konkret dari setiap repository, menyuntikkan (injecting)
+		// connection pool (dbPools: primary + replica opsional) sebagai dependensi.
+		// Repository yang menyentuh data pribadi user juga menerima Protector untuk
+		// enkripsi kolom PII (email, phone, national_id).
+		repos = repository.NewRepositories(dbPools, piiProtector)
+	}
+	userRepo := repos.Users
+	roleRepo := repos.Roles
+	shiftRepo := repos.Shifts
+	scheduleRepo := repos.Schedules
+	attendanceRepo := repos.Attendance
+	settingsRepo := repos.Settings
+	announcementRepo := repos.Announcements
+	documentRepo := repos.Documents
+	auditRepo := repos.Audit
+	payrollRepo := repos.Payroll
+	projectRepo := repos.Projects
+	signOffRepo := repos.SignOffs
+	disputeRepo := repos.Disputes
+	deviceRepo := repos.Devices
+	delegationRepo := repos.Delegations
+	escalationRepo := repos.Escalations
+	jobRepo := repos.Jobs
+	laborRepo := repos.Labor
+	notificationRepo := repos.Notifications
+	outboxRepo := repos.Outbox
+	verificationRepo := repos.Verification
+	kioskRepo := repos.Kiosks
+	attendancePhotoRepo := repos.AttendancePhotos
+	reportExportRepo := repos.ReportExports
+	debugCaptureRepo := repos.DebugCaptures
+	txManager := repos.Tx
 	zlog.Info().Msg("Repositories initialized")
 
+	// Mode degraded (DEGRADED_*): saat database primary tidak tersedia, shift & role dilayani
+	// dari cache memori dan check-in ditampung lalu dicatat setelah database pulih.
+	// Hanya untuk PostgreSQL: sandbox tidak punya database dan SQLite berupa file lokal.
+	var degradedMode *degraded.Controller
+	if dbPool != nil {
+		degradedMode = degraded.NewControllerFromEnv(dbPool)
+	}
+	if degradedMode != nil {
+		shiftRepo = degraded.CacheShifts(shiftRepo, degradedMode)
+		roleRepo = degraded.CacheRoles(roleRepo, degradedMode)
+	}
+
+	// Pengaturan sistem runtime (tabel settings) dengan cache in-process.
+	settingsStore := settings.NewStore(settingsRepo)
+	// Mode maintenance (pengaturan maintenance.*, MAINTENANCE_*): route non-admin dijawab 503,
+	// check-in bisa ditampung dan dicatat setelah maintenance selesai.
+	maintenanceMode := maintenance.NewControllerFromEnv(settingsStore)
+
+	// Enkripsi data PII lama (plaintext atau kunci lama) agar sesuai dengan kunci aktif.
+	if migrated, err := userRepo.EncryptLegacyPII(context.Background()); err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to encrypt legacy user PII")
+	} else if migrated > 0 {
+		zlog.Info().Int("users", migrated).Msg("Encrypted legacy user PII with active key")
+	}
+
+	// Bus domain event: handler & job mem-publish event, audit log dicatat langsung. Efek samping
+	// (notifikasi, webhook EVENTS_WEBHOOK_URL, streaming Kafka/NATS EVENTS_STREAM_PROVIDER) disimpan
+	// ke outbox dan dikirim dispatcher dengan retry (OUTBOX_*). Tujuan notifikasi didaftarkan
+	// setelah inbox dibuat; dispatcher dijalankan setelah semua tujuan terdaftar.
+	eventBus := events.NewInProcessBus()
+	eventBus.Subscribe("audit", subscribers.Audit(auditRepo), events.Audited...)
+	outboxDispatcher := outbox.NewDispatcherFromEnv(outboxRepo)
+	eventBus.Subscribe("outbox", outboxDispatcher.Enqueue)
+	if webhook := subscribers.NewWebhookFromEnv(); webhook != nil {
+		outboxDispatcher.Register("webhook", outbox.EventHandler(webhook.Handle), webhook.Events()...)
+	}
+	eventStream, err := stream.NewFromEnv()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid event streaming configuration")
+	}
+	if eventStream != nil {
+		outboxDispatcher.Register("stream", eventStream.Deliver, eventStream.Events()...)
+	}
+
+	// Penyimpanan file upload (STORAGE_BACKEND).
+	fileStorage, err := storage.NewStorageFromEnv()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid file storage configuration")
+	}
+	// Foto check-in (ATTENDANCE_PHOTO_*): disimpan terenkripsi dengan kunci PII, diproses dan
+	// dihapus sesuai retensi oleh job di bawah.
+	photoPipeline, err := photos.NewPipelineFromEnv(attendancePhotoRepo, fileStorage, piiProtector)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid attendance photo configuration")
+	}
+	// Ekspor laporan di background (REPORT_EXPORT_*): file disimpan di storage dan dihapus
+	// setelah masa retensinya oleh job cleanup. Renderer per jenis laporan didaftarkan di bawah.
+	reportService, err := reports.NewServiceFromEnv(reportExportRepo, fileStorage)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid report export configuration")
+	}
+	// Backup database (BACKUP_*): arsip COPY seluruh tabel ditulis job backups ke storage dan
+	// dipulihkan dengan cmd/restore. Tidak tersedia di sandbox maupun SQLite (tanpa PostgreSQL).
+	backupService, err := backup.NewServiceFromEnv(dbPool, repos.Backups, fileStorage)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid backup configuration")
+	}
+
+	// Job latar belakang dijalankan scheduler (SCHEDULER_POLL_INTERVAL): jadwal & status run
+	// tersimpan di scheduled_jobs dan lock terdistribusi per job (LOCK_BACKEND) mencegah run
+	// ganda antar replika.
+	// Nonaktifkan contractor yang masa aksesnya berakhir (CONTRACTOR_EXPIRY_INTERVAL).
+	// Sandbox dan SQLite hanya satu proses, sehingga cukup lock lokal.
+	jobLocker := lock.NewLocal()
+	if dbPool != nil {
+		if jobLocker, err = lock.NewFromEnv(dbPool); err != nil {
+			zlog.Fatal().Err(err).Msg("Invalid lock configuration")
+		}
+	}
+	jobScheduler := jobs.NewSchedulerFromEnv(jobRepo, jobLocker)
+	if contractorExpiry := jobs.NewContractorExpiryFromEnv(userRepo, settingsStore, eventBus); contractorExpiry != nil {
+		jobScheduler.Register(contractorExpiry)
+	}
+
+	// Notifikasi HR (NOTIFY_PROVIDER) dan job pengingat akhir probation (PROBATION_REVIEW_INTERVAL).
+	hrNotifier, err := notify.NewNotifierFromEnv()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid notification configuration")
+	}
+	if probationReview := jobs.NewProbationReviewFromEnv(userRepo, settingsStore, hrNotifier); probationReview != nil {
+		jobScheduler.Register(probationReview)
+	}
+
+	// SMS (SMS_PROVIDER, opsional): kode OTP verifikasi nomor HP & 2FA login, dan pengingat shift
+	// untuk karyawan tanpa email kantor.
+	smsSender, err := sms.NewSenderFromEnv()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid SMS configuration")
+	}
+	otpService, err := otp.NewServiceFromEnv(userRepo, smsSender)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid OTP configuration")
+	}
+
+	// Notifikasi ke user: inbox in-app (/user/notifications) diteruskan ke perangkat mobile
+	// (push FCM/APNs, opsional), plus job pengingat shift (SHIFT_REMINDER_INTERVAL).
+	pushService, err := push.NewServiceFromEnv(deviceRepo)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid push notification configuration")
+	}
+	userInbox := inbox.NewDispatcher(notificationRepo, pushService)
+	outboxDispatcher.Register("notifications", outbox.EventHandler(subscribers.NewNotifications(userRepo, userInbox, hrNotifier).Handle), subscribers.NotificationEvents...)
+	go outboxDispatcher.Start(context.Background())
+	// Eskalasi item approval yang melewati SLA ke atasan berikutnya/admin (APPROVAL_*).
+	if approvalEscalation := jobs.NewApprovalEscalationFromEnv(disputeRepo, escalationRepo, userRepo, txManager, eventBus); approvalEscalation != nil {
+		jobScheduler.Register(approvalEscalation)
+	}
+	if shiftReminder := jobs.NewShiftReminderFromEnv(deviceRepo, userRepo, userInbox, otpService); shiftReminder != nil {
+		jobScheduler.Register(shiftReminder)
+	}
+	if photoProcessing := jobs.NewAttendancePhotoProcessingFromEnv(photoPipeline); photoProcessing != nil {
+		jobScheduler.Register(photoProcessing)
+	}
+	if photoRetention := jobs.NewAttendancePhotoRetentionFromEnv(photoPipeline); photoRetention != nil {
+		jobScheduler.Register(photoRetention)
+	}
+	if routeRetention := jobs.NewAttendanceRouteRetentionFromEnv(attendanceRepo, settingsStore); routeRetention != nil {
+		jobScheduler.Register(routeRetention)
+	}
+	if reportGeneration := jobs.NewReportExportGenerationFromEnv(reportService); reportGeneration != nil {
+		jobScheduler.Register(reportGeneration)
+	}
+	if reportCleanup := jobs.NewReportExportCleanupFromEnv(reportService); reportCleanup != nil {
+		jobScheduler.Register(reportCleanup)
+	}
+	if captureRetention := jobs.NewDebugCaptureRetentionFromEnv(debugCaptureRepo); captureRetention != nil {
+		jobScheduler.Register(captureRetention)
+	}
+	if backupRun := jobs.NewBackupRunFromEnv(backupService); backupRun != nil {
+		jobScheduler.Register(backupRun)
+	}
+	go jobScheduler.Start(context.Background())
+
+	// Pencabutan sesi (users.token_version, di-cache SESSION_VERSION_CACHE_TTL) dan peringatan
+	// login dari perangkat/negara baru (LOGIN_ALERT_*, lookup negara opsional via GEOIP_PROVIDER).
+	sessionVersions := session.NewVersionCacheFromEnv(userRepo)
+	// Hierarki role (roles.parent_id) untuk middleware Authorize, di-cache ROLE_HIERARCHY_CACHE_TTL.
+	roleHierarchy := rbac.NewResolverFromEnv(roleRepo)
+	if degradedMode != nil {
+		sessionVersions.ServeStaleWhen(degradedMode.ReportError)
+		roleHierarchy.ServeStaleWhen(degradedMode.ReportError)
+	}
+	geoLocator, err := geoip.NewLocatorFromEnv()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid GeoIP configuration")
+	}
+	loginAlerts, err := loginalert.NewAlerterFromEnv(auditRepo, geoLocator, hrNotifier)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid login alert configuration")
+	}
+	// Ganti email oleh user menunggu konfirmasi dari alamat baru (EMAIL_CHANGE_*).
+	emailChanges, err := emailchange.NewServiceFromEnv(userRepo, hrNotifier)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid email change configuration")
+	}
+	// Onboarding lewat undangan admin (INVITATION_*); wajib saat auth.registration_mode = invite_only.
+	invitations, err := invitation.NewServiceFromEnv(userRepo, hrNotifier)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid invitation configuration")
+	}
+
+	// Pemindai malware opsional untuk upload (VIRUS_SCAN_PROVIDER).
+	virusScanner, err := virusscan.NewScannerFromEnv()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid virus scan configuration")
+	}
+
 	// --- Langkah 4: Inisialisasi Lapisan Handler ---
 	// Membuat instance konkret dari setiap handler, menyuntikkan repository
 	// yang relevan sebagai dependensi.
-	authHandler := handlers.NewAuthHandler(userRepo, roleRepo)
-	adminHandler := handlers.NewAdminHandler(shiftRepo, scheduleRepo, attendanceRepo, userRepo, roleRepo)
-	userHandler := handlers.NewUserHandler(attendanceRepo, scheduleRepo, userRepo, shiftRepo)
+	authHandler := handlers.NewAuthHandler(userRepo, roleRepo, settingsStore, eventBus, loginAlerts, sessionVersions, otpService)
+	adminHandler := handlers.NewAdminHandler(shiftRepo, scheduleRepo, attendanceRepo, userRepo, roleRepo, roleHierarchy, settingsStore, auditRepo, eventBus, txManager, sessionVersions)
+	documentHandler := handlers.NewDocumentHandler(documentRepo, attendanceRepo, fileStorage, virusScanner)
+	// Verifikasi wajah check-in opsional (FACE_VERIFY_PROVIDER); foto acuan = foto profil di storage.
+	faceHook, err := faceverify.NewHookFromEnv(documentHandler)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid face verification configuration")
+	}
+	userHandler := handlers.NewUserHandler(attendanceRepo, scheduleRepo, userRepo, shiftRepo, eventBus, txManager, degradedMode, maintenanceMode, faceHook, photoPipeline, emailChanges, settingsStore)
+	announcementHandler := handlers.NewAnnouncementHandler(announcementRepo, roleRepo)
+	orgHandler := handlers.NewOrgHandler(userRepo)
+	payrollHandler := handlers.NewPayrollHandler(payrollRepo, userRepo, eventBus)
+	projectHandler := handlers.NewProjectHandler(projectRepo)
+	signOffHandler := handlers.NewSignOffHandler(signOffRepo, delegationRepo, settingsStore, eventBus)
+	routeHandler := handlers.NewRouteHandler(attendanceRepo, userRepo, settingsStore, eventBus)
+	syncHandler := handlers.NewSyncHandler(userRepo, attendanceRepo, scheduleRepo)
+	debugCaptureHandler := handlers.NewDebugCaptureHandler(debugCaptureRepo)
+	// Perekaman request/response untuk debugging (DEBUG_CAPTURE_*), aktif per route/user lewat pengaturan debug.capture_*.
+	var requestCapturer appmiddleware.RequestCapturer
+	if recorder := debugcapture.NewRecorderFromEnv(debugCaptureRepo, settingsStore); recorder != nil {
+		requestCapturer = recorder
+	}
+	// Spesifikasi OpenAPI yang disajikan di /api/v1/openapi.json; OPENAPI_VALIDATION mengaktifkan validasi request.
+	apiSpec, err := openapi.NewFromEnv(docs.SwaggerInfo.ReadDoc())
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid OpenAPI configuration")
+	}
+	delegationHandler := handlers.NewDelegationHandler(delegationRepo, eventBus)
+	disputeHandler := handlers.NewDisputeHandler(disputeRepo, eventBus, txManager)
+	approvalHandler := handlers.NewApprovalHandler(disputeRepo, escalationRepo, eventBus, txManager)
+	deviceHandler := handlers.NewDeviceHandler(deviceRepo)
+	notificationHandler := handlers.NewNotificationHandler(notificationRepo)
+	outboxHandler := handlers.NewOutboxHandler(outboxRepo)
+	laborHandler := handlers.NewLaborHandler(laborRepo)
+	forecastHandler := handlers.NewForecastHandler(scheduleRepo, settingsStore)
+	jobHandler := handlers.NewJobHandler(jobScheduler)
+	backupHandler := handlers.NewBackupHandler(repos.Backups, backupService, jobScheduler, fileStorage)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceMode, repos.AppInstances)
+	// Diagnostik (DIAGNOSTICS_*): database, migrasi, storage, SMTP, jam vs NTP, dan antrean job.
+	// Hasilnya juga ditulis ke log sekali saat startup.
+	diagnosticsService := diagnostics.NewServiceFromEnv(dbPool, fileStorage, jobScheduler)
+	systemHandler := handlers.NewSystemHandler(diagnosticsService)
+	verificationHandler := handlers.NewVerificationHandler(verificationRepo, eventBus)
+	kioskHandler := handlers.NewKioskHandler(kioskRepo, userRepo, settingsStore, eventBus)
+	photoHandler := handlers.NewPhotoHandler(attendancePhotoRepo, photoPipeline)
+	reportHandler := handlers.NewReportHandler(reportExportRepo, payrollRepo, reportService, fileStorage, settingsStore)
+	emailChangeHandler := handlers.NewEmailChangeHandler(userRepo, emailChanges, eventBus)
+	usernameChangeHandler := handlers.NewUsernameChangeHandler(userRepo, eventBus, txManager)
+	phoneHandler := handlers.NewPhoneHandler(userRepo, otpService, eventBus)
+	invitationHandler := handlers.NewInvitationHandler(userRepo, invitations, eventBus, txManager)
 	zlog.Info().Msg("Handlers initialized")
 
+	// Check-in yang ditampung selama mode degraded dicatat dengan logika check-in UserHandler.
+	if degradedMode != nil {
+		degradedMode.SetFlusher(userHandler.ReplayCheckIn, userHandler.RejectQueuedCheckIn)
+		go degradedMode.Start(context.Background())
+	}
+	// Begitu juga check-in yang ditampung selama mode maintenance.
+	maintenanceMode.SetFlusher(userHandler.ReplayCheckIn, userHandler.RejectQueuedCheckIn)
+	go maintenanceMode.Start(context.Background())
+	// Heartbeat instance ini (versi aplikasi) di app_instances; dibaca cmd/migrate agar migrasi
+	// destruktif ditolak selama versi lama masih berjalan. Bernilai nil jika APP_HEARTBEAT_INTERVAL=0.
+	heartbeat := instances.NewHeartbeatFromEnv(repos.AppInstances)
+	if heartbeat != nil {
+		go heartbeat.Start(context.Background())
+	}
+	// Self-check startup (DIAGNOSTICS_STARTUP_CHECK): hasil tiap pemeriksaan ditulis ke log.
+	if configs.GetEnvBool("DIAGNOSTICS_STARTUP_CHECK", true) {
+		go diagnosticsService.SelfCheck(context.Background())
+	}
+	// Isi laporan users sama dengan ekspor langsung GET /admin/users/export.
+	reportService.Register(models.ReportTypeUsers, adminHandler.RenderUserReport)
+
+	// Verifier CAPTCHA untuk endpoint auth publik. Bernilai nil jika CAPTCHA_PROVIDER tidak di-set.
+	captchaVerifier, err := captcha.NewVerifierFromEnv()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid CAPTCHA configuration")
+	}
+
 	// --- Langkah 5: Setup Aplikasi Fiber ---
 	// Membuat instance baru dari aplikasi web Fiber.
 	// Mengkonfigurasi ErrorHandler global kustom dari paket handlers.
+	// BodyLimit global (MAX_BODY_SIZE_BYTES); request yang melebihi batas dijawab 413
+	// oleh ErrorHandler dalam format problem+json (RFC 7807). Route upload bisa memasang batas sendiri.
 	app := fiber.New(fiber.Config{
 		ErrorHandler: handlers.ErrorHandler,
+		BodyLimit:    configs.GetEnvInt("MAX_BODY_SIZE_BYTES", 4*1024*1024),
 	})
 	zlog.Info().Msg("Fiber app initialized")
 
 	// --- Langkah 6: Setup Middleware Global dan Rute ---
+	// Konfigurasi CORS & security headers dimuat sesuai profil APP_ENV (development/production).
+	securityCfg, err := configs.LoadSecurityConfig()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid security configuration")
+	}
+	// Panic yang tertangkap middleware recover dikirim ke Sentry/Glitchtip jika SENTRY_DSN di-set.
+	panicReporter, err := errorreport.NewReporterFromEnv()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid error reporting configuration")
+	}
 	// Mendaftarkan middleware global (seperti logger request, CORS, recover) ke aplikasi Fiber.
-	appmiddleware.SetupGlobalMiddleware(app)
+	appmiddleware.SetupGlobalMiddleware(app, securityCfg, panicReporter)
 
 	// Mendaftarkan endpoint untuk Swagger UI.
 	// Harus didaftarkan *sebelum* rute API utama jika prefix-nya sama atau tumpang tindih.
@@ -116,8 +524,11 @@ func main() {
 	app.Get("/swagger/*", fiberSwagger.WrapHandler)
 	zlog.Info().Msg("Swagger UI endpoint registered at /swagger/*")
 
+	// Kunci publik token sesi untuk layanan lain (kosong jika JWT_SIGNING_ALG=HS256).
+	app.Get("/.well-known/jwks.json", handlers.JWKS)
+
 	// Mendaftarkan semua rute API versi 1 (/api/v1/...) dengan menyuntikkan handler yang sesuai.
-	v1.SetupRoutes(app, authHandler, adminHandler, userHandler)
+	v1.SetupRoutes(app, authHandler, adminHandler, userHandler, announcementHandler, documentHandler, orgHandler, payrollHandler, projectHandler, signOffHandler, delegationHandler, disputeHandler, approvalHandler, deviceHandler, notificationHandler, outboxHandler, laborHandler, forecastHandler, jobHandler, verificationHandler, kioskHandler, photoHandler, reportHandler, emailChangeHandler, usernameChangeHandler, phoneHandler, invitationHandler, routeHandler, syncHandler, debugCaptureHandler, backupHandler, maintenanceHandler, systemHandler, captchaVerifier, sessionVersions, roleHierarchy, kioskRepo, requestCapturer, maintenanceMode, degradedMode, apiSpec)
 	zlog.Info().Msg("API v1 routes registered")
 
 	// --- Langkah 7: Start Server HTTP ---
@@ -127,6 +538,21 @@ func main() {
 		appPort = "3000"
 	}
 
+	// Saat SIGINT/SIGTERM (mis. rolling deploy), instance ini dihapus dari app_instances lalu
+	// server dihentikan dengan menunggu request yang sedang berjalan.
+	go func() {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		<-ctx.Done()
+		zlog.Info().Msg("Shutdown signal received")
+		stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		heartbeat.Stop(stopCtx)
+		if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+			zlog.Error().Err(err).Msg("Error during server shutdown")
+		}
+	}()
+
 	// Mencatat bahwa server akan dimulai pada port yang ditentukan.
 	zlog.Info().Msgf("Server is starting on port %s...", appPort)
 	// Mulai mendengarkan request HTTP pada port yang ditentukan.
@@ -137,4 +563,5 @@ func main() {
 		// log error fatal dan hentikan aplikasi.
 		zlog.Fatal().Err(startErr).Msg("Failed to start server")
 	}
+	zlog.Info().Msg("Server stopped")
 }
@@ -0,0 +1,152 @@
+// Command migrate menjalankan migrasi database (migrations/*.up.sql) dengan pengaman untuk
+// rolling deploy tanpa downtime (lihat internal/migrations dan README bagian Database Setup).
+//
+// Pemakaian:
+//
+//	go run ./cmd/migrate -dry-run                   # tampilkan migrasi yang belum dijalankan
+//	APP_VERSION=v1.5.0 go run ./cmd/migrate         # jalankan migrasi untuk versi yang akan dirilis
+//	go run ./cmd/migrate -zero-downtime=false       # tanpa pengaman (mis. saat mode maintenance)
+//
+// Dalam mode zero-downtime (default), migrasi destruktif (DROP/RENAME tabel atau kolom, ganti
+// tipe kolom, NOT NULL baru) ditolak selama masih ada instance API hidup dengan versi selain
+// -app-version, karena versi lama itu masih membaca skema lama. Migrasi aditif tetap berjalan.
+// Versi migrasi dicatat di schema_migrations dengan format golang-migrate.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/joho/godotenv"
+	"github.com/rakaarfi/attendance-system-be/internal/buildinfo"
+	"github.com/rakaarfi/attendance-system-be/internal/database"
+	"github.com/rakaarfi/attendance-system-be/internal/instances"
+	"github.com/rakaarfi/attendance-system-be/internal/migrations"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+func main() {
+	_ = godotenv.Load() // .env opsional, sama seperti API
+
+	dir := flag.String("dir", "migrations", "Direktori file migrasi *.up.sql")
+	dryRun := flag.Bool("dry-run", false, "Hanya tampilkan migrasi yang akan dijalankan dan pemeriksaannya")
+	zeroDowntime := flag.Bool("zero-downtime", true, "Tolak migrasi destruktif selama instance dengan versi lain masih hidup")
+	appVersion := flag.String("app-version", buildinfo.AppVersion(), "Versi aplikasi yang dirilis bersama migrasi ini (default APP_VERSION)")
+	flag.Parse()
+
+	if err := run(*dir, *dryRun, *zeroDowntime, *appVersion); err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string, dryRun, zeroDowntime bool, appVersion string) error {
+	files, err := migrations.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	pool, err := database.NewPgxPool()
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring database connection: %w", err)
+	}
+	defer conn.Release()
+
+	unlock, err := migrations.Lock(ctx, conn.Conn())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, dirty, err := migrations.CurrentVersion(ctx, conn.Conn())
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty: fix the failed migration, then set schema_migrations.dirty = false", current)
+	}
+	pending := migrations.Pending(files, current)
+	if len(pending) == 0 {
+		fmt.Printf("Schema is up to date (version %d).\n", current)
+		return nil
+	}
+
+	var findings []migrations.Finding
+	fmt.Printf("Schema version %d, %d pending migration(s):\n", current, len(pending))
+	for _, f := range pending {
+		destructive := migrations.Destructive(f)
+		marker := ""
+		if len(destructive) > 0 {
+			marker = "  [destructive]"
+		}
+		fmt.Printf("  %s%s\n", f.Name, marker)
+		for _, finding := range destructive {
+			fmt.Printf("      line %d: %s\n", finding.Line, finding.Reason)
+		}
+		findings = append(findings, destructive...)
+	}
+
+	if len(findings) > 0 && zeroDowntime {
+		old, err := otherVersions(ctx, repository.Pools{Primary: pool}, appVersion)
+		if err != nil {
+			return err
+		}
+		if len(old) > 0 {
+			fmt.Printf("\nInstances of other app versions are still alive (this release: %s):\n", appVersion)
+			for _, v := range instances.Summarize(old) {
+				fmt.Printf("  %-20s %d instance(s), last seen %s\n", v.AppVersion, v.Instances, v.LastSeenAt.Format("2006-01-02 15:04:05 MST"))
+			}
+			return errors.New("refusing destructive migrations while older app versions are running: finish the rollout first " +
+				"(stopped instances expire after APP_HEARTBEAT_TTL), or enable maintenance mode and re-run with -zero-downtime=false")
+		}
+	}
+	if dryRun {
+		fmt.Println("\nDry run: nothing was changed.")
+		return nil
+	}
+
+	for _, f := range pending {
+		if err := migrations.Apply(ctx, conn.Conn(), f); err != nil {
+			return err
+		}
+		fmt.Printf("Applied %s\n", f.Name)
+	}
+	fmt.Printf("Schema is now at version %d.\n", pending[len(pending)-1].Version)
+	return nil
+}
+
+// otherVersions mengembalikan instance hidup dengan versi selain appVersion. Sebelum tabel
+// app_instances dibuat (migrasi 000049) belum ada instance yang terdaftar.
+func otherVersions(ctx context.Context, pools repository.Pools, appVersion string) ([]models.AppInstance, error) {
+	alive, err := instances.Alive(ctx, repository.NewAppInstanceRepository(pools), instances.TTLFromEnv())
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "42P01" { // undefined_table
+		fmt.Println("\nNo app instance heartbeats yet (app_instances does not exist); skipping the running-versions check.")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing running app versions: %w", err)
+	}
+	var old []models.AppInstance
+	for _, i := range alive {
+		if !strings.EqualFold(i.AppVersion, appVersion) {
+			old = append(old, i)
+		}
+	}
+	return old, nil
+}
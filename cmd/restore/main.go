@@ -0,0 +1,164 @@
+// Command restore memulihkan database dari backup yang dibuat lewat
+// POST /api/v1/admin/maintenance/backup (lihat internal/backup dan README bagian Backup & Restore).
+//
+// Pemakaian:
+//
+//	go run ./cmd/restore -file backup-20250101-020000.tar.gz        # tampilkan isi backup saja
+//	go run ./cmd/restore -file backup-20250101-020000.tar.gz -yes   # ganti isi database
+//	go run ./cmd/restore -id 12 -yes                                # ambil arsip backup #12 dari storage
+//
+// Koneksi database dan storage dibaca dari env/.env yang sama dengan API (DATABASE_URL atau
+// DB_*, STORAGE_*). Semua tabel di backup dikosongkan lalu diisi ulang dalam satu transaksi;
+// hentikan API selama restore dan pastikan versi migrasi database sama dengan versi backup.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/rakaarfi/attendance-system-be/internal/backup"
+	"github.com/rakaarfi/attendance-system-be/internal/database"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/storage"
+)
+
+func main() {
+	file := flag.String("file", "", "Path arsip backup (.tar.gz) lokal")
+	id := flag.Int("id", 0, "ID backup di tabel backups; arsipnya dibaca dari storage (STORAGE_*)")
+	key := flag.String("key", "", "Storage key arsip backup (alternatif -id)")
+	yes := flag.Bool("yes", false, "Benar-benar mengganti isi database; tanpa flag ini hanya menampilkan isi backup")
+	flag.Parse()
+
+	if err := run(*file, *id, *key, *yes); err != nil {
+		fmt.Fprintf(os.Stderr, "restore: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(file string, id int, key string, yes bool) error {
+	sources := 0
+	for _, set := range []bool{file != "", id > 0, key != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return errors.New("specify exactly one of -file, -id or -key")
+	}
+	_ = godotenv.Load() // .env opsional, sama seperti API
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var pool *pgxpool.Pool
+	connect := func() (*pgxpool.Pool, error) {
+		if pool != nil {
+			return pool, nil
+		}
+		var err error
+		pool, err = database.NewPgxPool()
+		return pool, err
+	}
+	defer func() {
+		if pool != nil {
+			pool.Close()
+		}
+	}()
+
+	open, err := archiveOpener(ctx, file, id, key, connect)
+	if err != nil {
+		return err
+	}
+
+	// Tampilkan isi backup dulu agar operator bisa memastikan arsip yang benar.
+	r, err := open()
+	if err != nil {
+		return err
+	}
+	manifest, _, err := backup.ReadManifest(r)
+	r.Close()
+	if err != nil {
+		return err
+	}
+	printManifest(manifest)
+	if !yes {
+		fmt.Println("\nDry run: nothing was changed. Re-run with -yes to replace the database contents with this backup.")
+		return nil
+	}
+
+	db, err := connect()
+	if err != nil {
+		return err
+	}
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring database connection: %w", err)
+	}
+	defer conn.Release()
+	if r, err = open(); err != nil {
+		return err
+	}
+	defer r.Close()
+	if _, err := backup.Restore(ctx, conn.Conn(), r); err != nil {
+		return err
+	}
+	fmt.Printf("\nRestored %d tables.\n", len(manifest.Tables))
+	return nil
+}
+
+// archiveOpener mengembalikan fungsi yang membuka arsip backup dari sumber yang dipilih.
+// Arsip dibaca dua kali (manifest, lalu restore), sehingga sumber dibuka ulang setiap kali.
+func archiveOpener(ctx context.Context, file string, id int, key string, connect func() (*pgxpool.Pool, error)) (func() (io.ReadCloser, error), error) {
+	if file != "" {
+		return func() (io.ReadCloser, error) { return os.Open(file) }, nil
+	}
+	if id > 0 {
+		db, err := connect()
+		if err != nil {
+			return nil, err
+		}
+		b, err := repository.NewBackupRepository(repository.Pools{Primary: db}).GetBackupByID(ctx, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("backup %d not found", id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if b.Status != models.BackupCompleted || b.StorageKey == nil {
+			return nil, fmt.Errorf("backup %d is %s, not completed", id, b.Status)
+		}
+		key = *b.StorageKey
+	}
+	store, err := storage.NewStorageFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("error configuring storage: %w", err)
+	}
+	return func() (io.ReadCloser, error) {
+		r, err := store.Open(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("error opening %s from %s storage: %w", key, store.Backend(), err)
+		}
+		return r, nil
+	}, nil
+}
+
+func printManifest(m *backup.Manifest) {
+	var rows int64
+	for _, t := range m.Tables {
+		rows += t.Rows
+	}
+	fmt.Printf("Backup created at %s, schema version %d: %d tables, %d rows\n",
+		m.CreatedAt.Format("2006-01-02 15:04:05 MST"), m.SchemaVersion, len(m.Tables), rows)
+	for _, t := range m.Tables {
+		fmt.Printf("  %-40s %10d rows\n", t.Name, t.Rows)
+	}
+}
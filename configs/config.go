@@ -2,6 +2,7 @@ package configs
 
 import (
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 	zlog "github.com/rs/zerolog/log"
@@ -16,7 +17,13 @@ func LoadConfig() {
 	}
 
 	// Anda bisa menambahkan validasi di sini untuk memastikan variabel penting ada
-	requiredVars := []string{"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "APP_PORT", "JWT_SECRET"}
+	requiredVars := []string{"APP_PORT", "JWT_SECRET"}
+	// DATABASE_URL (satu DSN) atau DATABASE_URLS (primary + standby) menggantikan variabel DB_* terpisah.
+	// SANDBOX_MODE tidak memakai database, dan DB_DRIVER=sqlite cukup dengan SQLITE_PATH (ada default).
+	usesPostgres := !GetEnvBool("SANDBOX_MODE", false) && !strings.EqualFold(GetEnv("DB_DRIVER", "postgres"), "sqlite")
+	if os.Getenv("DATABASE_URL") == "" && os.Getenv("DATABASE_URLS") == "" && usesPostgres {
+		requiredVars = append(requiredVars, "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME")
+	}
 	for _, v := range requiredVars {
 		if os.Getenv(v) == "" {
 			zlog.Fatal().Str("var", v).Msg("Environment variable is not set.")
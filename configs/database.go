@@ -0,0 +1,67 @@
+// configs/database.go
+package configs
+
+import (
+	"fmt"
+	"time"
+)
+
+// DBPoolConfig menampung parameter connection pool pgx untuk satu database (primary/replica).
+// Semua nilai bisa di-override per environment agar pool bisa disesuaikan dengan jumlah instance
+// dan batas max_connections Postgres.
+type DBPoolConfig struct {
+	MaxConns              int32         // Jumlah maksimum koneksi (aktif + idle) (<PREFIX>MAX_CONNS).
+	MinConns              int32         // Jumlah minimum koneksi yang dipertahankan (<PREFIX>MIN_CONNS).
+	MinIdleConns          int32         // Jumlah minimum koneksi idle yang dipertahankan (<PREFIX>MIN_IDLE_CONNS).
+	MaxConnLifetime       time.Duration // Umur maksimum koneksi sebelum ditutup (<PREFIX>MAX_CONN_LIFETIME).
+	MaxConnLifetimeJitter time.Duration // Jitter acak di atas MaxConnLifetime agar koneksi tidak ditutup serentak (<PREFIX>MAX_CONN_LIFETIME_JITTER).
+	MaxConnIdleTime       time.Duration // Lama koneksi idle sebelum ditutup (<PREFIX>MAX_CONN_IDLE_TIME).
+	HealthCheckPeriod     time.Duration // Interval pemeriksaan koneksi idle yang rusak (<PREFIX>HEALTH_CHECK_PERIOD).
+	ConnectTimeout        time.Duration // Batas waktu membuat satu koneksi baru (<PREFIX>CONNECT_TIMEOUT).
+}
+
+// DefaultDBPoolConfig mengembalikan parameter pool bawaan (cukup untuk development dan satu instance).
+func DefaultDBPoolConfig() DBPoolConfig {
+	return DBPoolConfig{
+		MaxConns:          10,
+		MinConns:          2,
+		MaxConnLifetime:   time.Hour,
+		MaxConnIdleTime:   30 * time.Minute,
+		HealthCheckPeriod: time.Minute,
+		ConnectTimeout:    5 * time.Second,
+	}
+}
+
+// LoadDBPoolConfig membaca DBPoolConfig dari env var dengan prefix tertentu (mis. DB_POOL_ untuk
+// primary, DB_REPLICA_POOL_ untuk read-replica), memakai defaults untuk variabel yang tidak di-set.
+// Mengembalikan error jika kombinasi nilai tidak valid.
+func LoadDBPoolConfig(prefix string, defaults DBPoolConfig) (DBPoolConfig, error) {
+	cfg := DBPoolConfig{
+		MaxConns:              int32(GetEnvInt(prefix+"MAX_CONNS", int(defaults.MaxConns))),
+		MinConns:              int32(GetEnvInt(prefix+"MIN_CONNS", int(defaults.MinConns))),
+		MinIdleConns:          int32(GetEnvInt(prefix+"MIN_IDLE_CONNS", int(defaults.MinIdleConns))),
+		MaxConnLifetime:       GetEnvDuration(prefix+"MAX_CONN_LIFETIME", defaults.MaxConnLifetime),
+		MaxConnLifetimeJitter: GetEnvDuration(prefix+"MAX_CONN_LIFETIME_JITTER", defaults.MaxConnLifetimeJitter),
+		MaxConnIdleTime:       GetEnvDuration(prefix+"MAX_CONN_IDLE_TIME", defaults.MaxConnIdleTime),
+		HealthCheckPeriod:     GetEnvDuration(prefix+"HEALTH_CHECK_PERIOD", defaults.HealthCheckPeriod),
+		ConnectTimeout:        GetEnvDuration(prefix+"CONNECT_TIMEOUT", defaults.ConnectTimeout),
+	}
+
+	// --- Validasi ---
+	if cfg.MaxConns < 1 {
+		return DBPoolConfig{}, fmt.Errorf("%sMAX_CONNS must be at least 1", prefix)
+	}
+	if cfg.MinConns < 0 || cfg.MinConns > cfg.MaxConns {
+		return DBPoolConfig{}, fmt.Errorf("%sMIN_CONNS must be between 0 and %sMAX_CONNS (%d)", prefix, prefix, cfg.MaxConns)
+	}
+	if cfg.MinIdleConns < 0 || cfg.MinIdleConns > cfg.MaxConns {
+		return DBPoolConfig{}, fmt.Errorf("%sMIN_IDLE_CONNS must be between 0 and %sMAX_CONNS (%d)", prefix, prefix, cfg.MaxConns)
+	}
+	if cfg.MaxConnLifetime <= 0 || cfg.MaxConnIdleTime <= 0 || cfg.HealthCheckPeriod <= 0 || cfg.ConnectTimeout <= 0 {
+		return DBPoolConfig{}, fmt.Errorf("%sMAX_CONN_LIFETIME, %sMAX_CONN_IDLE_TIME, %sHEALTH_CHECK_PERIOD and %sCONNECT_TIMEOUT must be positive", prefix, prefix, prefix, prefix)
+	}
+	if cfg.MaxConnLifetimeJitter < 0 {
+		return DBPoolConfig{}, fmt.Errorf("%sMAX_CONN_LIFETIME_JITTER cannot be negative", prefix)
+	}
+	return cfg, nil
+}
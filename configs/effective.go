@@ -0,0 +1,99 @@
+// configs/effective.go
+package configs
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// directKeys adalah variabel yang dibaca langsung dengan os.Getenv (bukan lewat helper GetEnv*),
+// sehingga tidak tercatat otomatis oleh Effective.
+var directKeys = []string{
+	"APP_PORT", "JWT_SECRET", "DATABASE_URL", "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME",
+	"DB_SSLMODE", "DB_REPLICA_URL", "PII_ENCRYPTION_KEYS", "PII_ACTIVE_KEY_ID", "PII_INDEX_KEY",
+	"LOG_LEVEL", "LOG_FORMAT", "LOG_FILE_ENABLED", "LOG_FILE_PATH", "LOG_FILE_MAX_SIZE_MB",
+	"LOG_FILE_MAX_BACKUPS", "LOG_FILE_MAX_AGE_DAYS", "LOG_FILE_COMPRESS",
+}
+
+// secretSuffixes menandai variabel rahasia berdasarkan segmen terakhir namanya (mis. S3_SECRET_ACCESS_KEY,
+// SMS_TWILIO_AUTH_TOKEN), sehingga KIOSK_TOKEN_TTL atau ARGON2_KEY_LENGTH tetap terlihat.
+// DATABASE_URLS berisi beberapa DSN sekaligus, sehingga tidak bisa disamarkan per URL.
+var secretSuffixes = []string{"SECRET", "PASSWORD", "TOKEN", "KEY", "KEYS", "DSN", "URLS"}
+
+// secretMarkers menandai variabel rahasia di bagian mana pun namanya. URL webhook sering memuat
+// token di path-nya.
+var secretMarkers = []string{"SECRET", "PASSWORD", "WEBHOOK_URL"}
+
+var (
+	lookupsMu sync.Mutex
+	lookups   = map[string]string{} // key -> nilai default (diformat) dari pemanggilan GetEnv* pertama
+)
+
+// remember mencatat bahwa key dibaca dengan default fallback.
+func remember(key string, fallback any) {
+	lookupsMu.Lock()
+	defer lookupsMu.Unlock()
+	if _, ok := lookups[key]; ok {
+		return
+	}
+	switch v := fallback.(type) {
+	case []string:
+		lookups[key] = strings.Join(v, ",")
+	default:
+		lookups[key] = fmt.Sprint(v)
+	}
+}
+
+// Effective mengembalikan konfigurasi efektif: setiap variabel yang sudah dibaca lewat helper
+// GetEnv* (beserta default-nya jika tidak di-set) dan variabel yang dibaca langsung, urut nama.
+// Nilai rahasia (nama berakhiran _KEY, _TOKEN, _SECRET, dll.) disamarkan, begitu juga password
+// di dalam URL.
+func Effective() []models.ConfigValue {
+	lookupsMu.Lock()
+	defaults := make(map[string]string, len(lookups)+len(directKeys))
+	for k, v := range lookups {
+		defaults[k] = v
+	}
+	lookupsMu.Unlock()
+	for _, k := range directKeys {
+		if _, ok := defaults[k]; !ok {
+			defaults[k] = ""
+		}
+	}
+
+	values := make([]models.ConfigValue, 0, len(defaults))
+	for key, fallback := range defaults {
+		v := models.ConfigValue{Key: key, Value: fallback, Source: models.ConfigSourceDefault}
+		if raw := strings.TrimSpace(os.Getenv(key)); raw != "" {
+			v.Value, v.Source = raw, models.ConfigSourceEnv
+		}
+		v.Value, v.Masked = mask(key, v.Value)
+		values = append(values, v)
+	}
+	slices.SortFunc(values, func(a, b models.ConfigValue) int { return strings.Compare(a.Key, b.Key) })
+	return values
+}
+
+// mask menyamarkan nilai rahasia. Nilai kosong dibiarkan agar terlihat bahwa rahasia belum di-set.
+func mask(key, value string) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+	upper := strings.ToUpper(key)
+	last := upper[strings.LastIndex(upper, "_")+1:]
+	if slices.Contains(secretSuffixes, last) || slices.ContainsFunc(secretMarkers, func(m string) bool { return strings.Contains(upper, m) }) {
+		return "********", true
+	}
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			return u.Redacted(), true
+		}
+	}
+	return value, false
+}
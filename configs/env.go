@@ -0,0 +1,83 @@
+// configs/env.go
+package configs
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Helper untuk membaca environment variable dengan tipe tertentu.
+// Semua helper mengembalikan nilai default jika variabel kosong atau tidak valid,
+// sehingga pemanggil tidak perlu mengulang pola os.Getenv + strconv di setiap paket.
+// Setiap key yang dibaca dicatat beserta default-nya untuk laporan konfigurasi efektif (Effective).
+
+// GetEnv mengembalikan nilai env var 'key', atau 'fallback' jika kosong.
+func GetEnv(key, fallback string) string {
+	remember(key, fallback)
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// GetEnvInt membaca env var sebagai integer.
+func GetEnvInt(key string, fallback int) int {
+	remember(key, fallback)
+	v, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// GetEnvBool membaca env var sebagai boolean ('true', '1', 'false', '0', dll.).
+func GetEnvBool(key string, fallback bool) bool {
+	remember(key, fallback)
+	v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(key)))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// GetEnvFloat membaca env var sebagai float64.
+func GetEnvFloat(key string, fallback float64) float64 {
+	remember(key, fallback)
+	v, err := strconv.ParseFloat(strings.TrimSpace(os.Getenv(key)), 64)
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// GetEnvDuration membaca env var sebagai time.Duration (format Go, misal: "30s", "5m").
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	remember(key, fallback)
+	v, err := time.ParseDuration(strings.TrimSpace(os.Getenv(key)))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// GetEnvList membaca env var berisi daftar yang dipisahkan koma.
+// Elemen kosong dibuang dan spasi di sekitar elemen dihapus.
+func GetEnvList(key string, fallback []string) []string {
+	remember(key, fallback)
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return fallback
+	}
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if p := strings.TrimSpace(part); p != "" {
+			out = append(out, p)
+		}
+	}
+	if len(out) == 0 {
+		return fallback
+	}
+	return out
+}
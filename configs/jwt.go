@@ -0,0 +1,71 @@
+// configs/jwt.go
+package configs
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxJWTClockSkew membatasi toleransi selisih jam agar token kedaluwarsa tidak diterima terlalu lama.
+const maxJWTClockSkew = 5 * time.Minute
+
+// Algoritma tanda tangan token sesi (JWT_SIGNING_ALG).
+const (
+	JWTAlgHS256 = "HS256" // HMAC dengan JWT_SECRET (default); token hanya bisa divalidasi aplikasi ini
+	JWTAlgRS256 = "RS256" // RSA; kunci publik dipublikasikan di /.well-known/jwks.json
+	JWTAlgEdDSA = "EdDSA" // Ed25519; kunci publik dipublikasikan di /.well-known/jwks.json
+)
+
+// JWTConfig menampung konfigurasi token sesi (login, token terbatas, token kiosk).
+type JWTConfig struct {
+	TTL       time.Duration // Masa berlaku token login (JWT_EXPIRATION_HOURS).
+	Issuer    string        // Claim iss yang ditulis ke semua token dan diwajibkan saat validasi (JWT_ISSUER).
+	Audience  string        // Claim aud token sesi yang diwajibkan (JWT_AUDIENCE). Kosong = token sesi tanpa aud.
+	ClockSkew time.Duration // Toleransi selisih jam antar server untuk exp/nbf/iat (JWT_CLOCK_SKEW).
+
+	// --- Tanda Tangan Asimetris (token sesi) ---
+	SigningAlg             string   // HS256, RS256 atau EdDSA (JWT_SIGNING_ALG).
+	PrivateKeyFile         string   // Kunci privat PEM untuk RS256/EdDSA (JWT_PRIVATE_KEY_FILE).
+	KeyID                  string   // Header kid (JWT_KEY_ID). Kosong = thumbprint RFC 7638 kunci publik.
+	PreviousPublicKeyFiles []string // Kunci publik PEM lama yang masih diterima & dipublikasikan saat rotasi (JWT_PREVIOUS_PUBLIC_KEY_FILES).
+}
+
+// DefaultJWTConfig mengembalikan konfigurasi bawaan (dipakai juga jika LoadJWTConfig tidak dipanggil).
+func DefaultJWTConfig() JWTConfig {
+	return JWTConfig{TTL: 72 * time.Hour, Issuer: "absensi-app", ClockSkew: 30 * time.Second, SigningAlg: JWTAlgHS256}
+}
+
+// LoadJWTConfig membaca JWTConfig dari env var.
+// Mengembalikan error jika nilai di luar batas yang masuk akal.
+func LoadJWTConfig() (JWTConfig, error) {
+	cfg := DefaultJWTConfig()
+	cfg.TTL = time.Duration(GetEnvInt("JWT_EXPIRATION_HOURS", int(cfg.TTL/time.Hour))) * time.Hour
+	cfg.Issuer = GetEnv("JWT_ISSUER", cfg.Issuer)
+	cfg.Audience = GetEnv("JWT_AUDIENCE", cfg.Audience)
+	cfg.ClockSkew = GetEnvDuration("JWT_CLOCK_SKEW", cfg.ClockSkew)
+	cfg.SigningAlg = GetEnv("JWT_SIGNING_ALG", cfg.SigningAlg)
+	cfg.PrivateKeyFile = GetEnv("JWT_PRIVATE_KEY_FILE", "")
+	cfg.KeyID = GetEnv("JWT_KEY_ID", "")
+	cfg.PreviousPublicKeyFiles = GetEnvList("JWT_PREVIOUS_PUBLIC_KEY_FILES", nil)
+
+	// --- Validasi ---
+	if cfg.TTL <= 0 {
+		return JWTConfig{}, fmt.Errorf("JWT_EXPIRATION_HOURS must be positive")
+	}
+	if cfg.Issuer == "" {
+		return JWTConfig{}, fmt.Errorf("JWT_ISSUER cannot be empty")
+	}
+	if cfg.ClockSkew < 0 || cfg.ClockSkew > maxJWTClockSkew {
+		return JWTConfig{}, fmt.Errorf("JWT_CLOCK_SKEW must be between 0 and %s", maxJWTClockSkew)
+	}
+	switch cfg.SigningAlg {
+	case JWTAlgHS256:
+	case JWTAlgRS256, JWTAlgEdDSA:
+		if cfg.PrivateKeyFile == "" {
+			return JWTConfig{}, fmt.Errorf("JWT_PRIVATE_KEY_FILE is required when JWT_SIGNING_ALG=%s", cfg.SigningAlg)
+		}
+	default:
+		return JWTConfig{}, fmt.Errorf("unknown JWT_SIGNING_ALG '%s' (expected %s, %s or %s)", cfg.SigningAlg, JWTAlgHS256, JWTAlgRS256, JWTAlgEdDSA)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,67 @@
+// configs/logging.go
+package configs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// moduleLevelEnvPrefix adalah prefix env var untuk override level per modul,
+// misal LOG_LEVEL_REPOSITORY=warn atau LOG_LEVEL_HTTP=error.
+const moduleLevelEnvPrefix = "LOG_LEVEL_"
+
+// LogConfig menampung konfigurasi level dan sampling log.
+// Konfigurasi output (format, file, rotasi) tetap dibaca langsung oleh logger.SetupLogger.
+type LogConfig struct {
+	Level        string                   // Level default semua log (LOG_LEVEL). Kosong/invalid = info.
+	ModuleLevels map[string]zerolog.Level // Override level per modul, key lowercase (LOG_LEVEL_<MODUL>).
+
+	// --- Sampling (log info/debug bervolume tinggi) ---
+	SampleEvery    int           // Tulis 1 dari N event info/debug (LOG_SAMPLE_EVERY). <= 1 berarti tanpa sampling.
+	SampleBurst    int           // Event per periode yang selalu ditulis sebelum sampling berlaku (LOG_SAMPLE_BURST).
+	SamplePeriod   time.Duration // Periode burst (LOG_SAMPLE_PERIOD).
+	SampledModules []string      // Modul yang disampling (LOG_SAMPLE_MODULES). Warn ke atas tidak pernah disampling.
+}
+
+// SamplingEnabled mengembalikan true jika sampling log aktif.
+func (c LogConfig) SamplingEnabled() bool {
+	return c.SampleEvery > 1
+}
+
+// LoadLogConfig membaca LogConfig dari env var.
+// Mengembalikan error jika override level modul tidak valid.
+func LoadLogConfig() (LogConfig, error) {
+	cfg := LogConfig{
+		Level:          os.Getenv("LOG_LEVEL"),
+		ModuleLevels:   map[string]zerolog.Level{},
+		SampleEvery:    GetEnvInt("LOG_SAMPLE_EVERY", 1),
+		SampleBurst:    GetEnvInt("LOG_SAMPLE_BURST", 0),
+		SamplePeriod:   GetEnvDuration("LOG_SAMPLE_PERIOD", 1*time.Second),
+		SampledModules: GetEnvList("LOG_SAMPLE_MODULES", []string{"http", "repository"}),
+	}
+
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, moduleLevelEnvPrefix) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		module := strings.ToLower(strings.TrimPrefix(key, moduleLevelEnvPrefix))
+		level, err := zerolog.ParseLevel(strings.TrimSpace(value))
+		if err != nil || level == zerolog.NoLevel {
+			return LogConfig{}, fmt.Errorf("invalid %s value '%s'", key, value)
+		}
+		cfg.ModuleLevels[module] = level
+	}
+	for i, module := range cfg.SampledModules {
+		cfg.SampledModules[i] = strings.ToLower(module)
+	}
+
+	if cfg.SampleBurst < 0 || cfg.SamplePeriod <= 0 {
+		return LogConfig{}, fmt.Errorf("LOG_SAMPLE_BURST must be >= 0 and LOG_SAMPLE_PERIOD must be positive")
+	}
+	return cfg, nil
+}
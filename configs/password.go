@@ -0,0 +1,68 @@
+// configs/password.go
+package configs
+
+import "fmt"
+
+// Batas parameter Argon2id. Nilai di luar batas ini hampir pasti salah ketik (mis. ARGON2_MEMORY_KB=-1
+// yang jika di-cast ke uint32 menjadi ~4 TiB per hash) dan ditolak saat startup.
+const (
+	maxArgon2MemoryKB    = 1024 * 1024 // 1 GiB per hash
+	maxArgon2Iterations  = 100
+	maxArgon2Parallelism = 255 // Batas tipe uint8 di golang.org/x/crypto/argon2
+	minArgon2SaltLength  = 8
+	maxArgon2SaltLength  = 64
+	minArgon2KeyLength   = 16
+	maxArgon2KeyLength   = 64
+)
+
+// PasswordHashConfig menampung parameter hashing password Argon2id.
+type PasswordHashConfig struct {
+	MemoryKB    uint32 // Memori per hash dalam KiB (ARGON2_MEMORY_KB).
+	Iterations  uint32 // Jumlah iterasi / time cost (ARGON2_ITERATIONS).
+	Parallelism uint8  // Jumlah thread (ARGON2_PARALLELISM).
+	SaltLength  uint32 // Panjang salt dalam byte (ARGON2_SALT_LENGTH).
+	KeyLength   uint32 // Panjang hash yang dihasilkan dalam byte (ARGON2_KEY_LENGTH).
+}
+
+// DefaultPasswordHashConfig mengembalikan parameter bawaan sesuai rekomendasi OWASP (m=64MiB, t=3, p=2)
+// (dipakai juga jika LoadPasswordHashConfig tidak dipanggil).
+func DefaultPasswordHashConfig() PasswordHashConfig {
+	return PasswordHashConfig{MemoryKB: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+}
+
+// LoadPasswordHashConfig membaca PasswordHashConfig dari env var ARGON2_*.
+// Nilai divalidasi sebelum dikonversi ke tipe unsigned, sehingga nilai negatif atau terlalu besar
+// mengembalikan error alih-alih terpotong diam-diam.
+func LoadPasswordHashConfig() (PasswordHashConfig, error) {
+	def := DefaultPasswordHashConfig()
+	memory := GetEnvInt("ARGON2_MEMORY_KB", int(def.MemoryKB))
+	iterations := GetEnvInt("ARGON2_ITERATIONS", int(def.Iterations))
+	parallelism := GetEnvInt("ARGON2_PARALLELISM", int(def.Parallelism))
+	saltLength := GetEnvInt("ARGON2_SALT_LENGTH", int(def.SaltLength))
+	keyLength := GetEnvInt("ARGON2_KEY_LENGTH", int(def.KeyLength))
+
+	// --- Validasi ---
+	if parallelism < 1 || parallelism > maxArgon2Parallelism {
+		return PasswordHashConfig{}, fmt.Errorf("ARGON2_PARALLELISM must be between 1 and %d", maxArgon2Parallelism)
+	}
+	// Argon2 membutuhkan minimal 8 KiB per thread.
+	if memory < 8*parallelism || memory > maxArgon2MemoryKB {
+		return PasswordHashConfig{}, fmt.Errorf("ARGON2_MEMORY_KB must be between %d (8 x ARGON2_PARALLELISM) and %d", 8*parallelism, maxArgon2MemoryKB)
+	}
+	if iterations < 1 || iterations > maxArgon2Iterations {
+		return PasswordHashConfig{}, fmt.Errorf("ARGON2_ITERATIONS must be between 1 and %d", maxArgon2Iterations)
+	}
+	if saltLength < minArgon2SaltLength || saltLength > maxArgon2SaltLength {
+		return PasswordHashConfig{}, fmt.Errorf("ARGON2_SALT_LENGTH must be between %d and %d", minArgon2SaltLength, maxArgon2SaltLength)
+	}
+	if keyLength < minArgon2KeyLength || keyLength > maxArgon2KeyLength {
+		return PasswordHashConfig{}, fmt.Errorf("ARGON2_KEY_LENGTH must be between %d and %d", minArgon2KeyLength, maxArgon2KeyLength)
+	}
+	return PasswordHashConfig{
+		MemoryKB:    uint32(memory),
+		Iterations:  uint32(iterations),
+		Parallelism: uint8(parallelism),
+		SaltLength:  uint32(saltLength),
+		KeyLength:   uint32(keyLength),
+	}, nil
+}
@@ -0,0 +1,47 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPasswordHashConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    PasswordHashConfig
+		wantErr string
+	}{
+		{name: "defaults", want: DefaultPasswordHashConfig()},
+		{
+			name: "custom",
+			env:  map[string]string{"ARGON2_MEMORY_KB": "19456", "ARGON2_ITERATIONS": "2", "ARGON2_PARALLELISM": "1"},
+			want: PasswordHashConfig{MemoryKB: 19456, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		},
+		{name: "negative memory", env: map[string]string{"ARGON2_MEMORY_KB": "-1"}, wantErr: "ARGON2_MEMORY_KB"},
+		{name: "memory too large", env: map[string]string{"ARGON2_MEMORY_KB": "4194304"}, wantErr: "ARGON2_MEMORY_KB"},
+		{name: "memory below 8 KiB per thread", env: map[string]string{"ARGON2_MEMORY_KB": "16", "ARGON2_PARALLELISM": "4"}, wantErr: "ARGON2_MEMORY_KB"},
+		{name: "zero iterations", env: map[string]string{"ARGON2_ITERATIONS": "0"}, wantErr: "ARGON2_ITERATIONS"},
+		{name: "parallelism overflows uint8", env: map[string]string{"ARGON2_PARALLELISM": "300"}, wantErr: "ARGON2_PARALLELISM"},
+		{name: "negative parallelism", env: map[string]string{"ARGON2_PARALLELISM": "-2"}, wantErr: "ARGON2_PARALLELISM"},
+		{name: "short salt", env: map[string]string{"ARGON2_SALT_LENGTH": "4"}, wantErr: "ARGON2_SALT_LENGTH"},
+		{name: "long key", env: map[string]string{"ARGON2_KEY_LENGTH": "1024"}, wantErr: "ARGON2_KEY_LENGTH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"ARGON2_MEMORY_KB", "ARGON2_ITERATIONS", "ARGON2_PARALLELISM", "ARGON2_SALT_LENGTH", "ARGON2_KEY_LENGTH"} {
+				t.Setenv(key, tt.env[key])
+			}
+			got, err := LoadPasswordHashConfig()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
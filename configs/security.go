@@ -0,0 +1,132 @@
+// configs/security.go
+package configs
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// Profil environment aplikasi, dibaca dari env var APP_ENV.
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// SecurityConfig menampung konfigurasi CORS dan security headers.
+// Nilai default dipilih berdasarkan profil APP_ENV (development/production),
+// lalu masing-masing field bisa di-override lewat env var.
+type SecurityConfig struct {
+	Environment string // Profil aktif: "development" atau "production".
+
+	// --- CORS ---
+	CORSAllowOrigins     []string // Daftar origin yang diizinkan (CORS_ALLOW_ORIGINS, pisahkan koma).
+	CORSAllowMethods     []string // Metode HTTP yang diizinkan (CORS_ALLOW_METHODS).
+	CORSAllowHeaders     []string // Header request yang diizinkan (CORS_ALLOW_HEADERS).
+	CORSExposeHeaders    []string // Header response yang boleh dibaca klien (CORS_EXPOSE_HEADERS).
+	CORSAllowCredentials bool     // Izinkan cookie/credential lintas origin (CORS_ALLOW_CREDENTIALS).
+	CORSMaxAgeSeconds    int      // Cache preflight di browser (CORS_MAX_AGE_SECONDS).
+
+	// --- Security Headers ---
+	HSTSEnabled           bool   // Kirim Strict-Transport-Security (HSTS_ENABLED).
+	HSTSMaxAgeSeconds     int    // max-age HSTS (HSTS_MAX_AGE_SECONDS).
+	HSTSIncludeSubdomains bool   // includeSubDomains pada HSTS (HSTS_INCLUDE_SUBDOMAINS).
+	FrameOptions          string // Nilai X-Frame-Options (SECURITY_FRAME_OPTIONS).
+	ReferrerPolicy        string // Nilai Referrer-Policy (SECURITY_REFERRER_POLICY).
+	ContentSecurityPolicy string // Nilai Content-Security-Policy untuk response API (SECURITY_CSP).
+
+	// --- Proxy ---
+	// Reverse proxy/load balancer tepercaya (TRUSTED_PROXIES, IP atau CIDR, pisahkan koma).
+	// Hanya dari alamat ini header X-Request-ID request diteruskan; kosong = selalu buat ID baru.
+	TrustedProxies []netip.Prefix
+}
+
+// IsProduction mengembalikan true jika profil aktif adalah production.
+func (c SecurityConfig) IsProduction() bool {
+	return c.Environment == EnvProduction
+}
+
+// LoadSecurityConfig membangun SecurityConfig dari profil APP_ENV dan override env var.
+// Mengembalikan error jika kombinasi konfigurasi tidak valid (misal: wildcard origin
+// bersamaan dengan credentials, yang ditolak oleh browser).
+func LoadSecurityConfig() (SecurityConfig, error) {
+	env := strings.ToLower(GetEnv("APP_ENV", EnvDevelopment))
+
+	// --- Default per Profil ---
+	var cfg SecurityConfig
+	switch env {
+	case EnvProduction:
+		cfg = SecurityConfig{
+			Environment:       EnvProduction,
+			CORSAllowOrigins:  nil, // Production WAJIB men-set CORS_ALLOW_ORIGINS secara eksplisit.
+			CORSMaxAgeSeconds: 600,
+			HSTSEnabled:       true,
+			HSTSMaxAgeSeconds: 31536000, // 1 tahun
+		}
+	case EnvDevelopment:
+		cfg = SecurityConfig{
+			Environment:       EnvDevelopment,
+			CORSAllowOrigins:  []string{"http://localhost:5173", "http://127.0.0.1:5173", "http://localhost:3001"},
+			CORSMaxAgeSeconds: 0,
+			HSTSEnabled:       false, // Development biasanya tanpa TLS.
+			HSTSMaxAgeSeconds: 0,
+		}
+	default:
+		return SecurityConfig{}, fmt.Errorf("unknown APP_ENV '%s' (expected '%s' or '%s')", env, EnvDevelopment, EnvProduction)
+	}
+	cfg.CORSAllowMethods = []string{"GET", "POST", "HEAD", "PUT", "DELETE", "PATCH", "OPTIONS"}
+	cfg.CORSAllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Captcha-Token", "If-Match", "X-Device-Fingerprint", "X-Request-ID"}
+	// ETag: versi record untuk optimistic locking (If-Match); X-Rotated-Token: token kiosk pengganti;
+	// X-Request-ID: ID request yang bisa dikutip user ke support.
+	cfg.CORSExposeHeaders = []string{"ETag", "X-Rotated-Token", "X-Request-ID"}
+	cfg.FrameOptions = "DENY"
+	cfg.ReferrerPolicy = "no-referrer"
+	cfg.ContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+
+	// --- Override dari Env Var ---
+	cfg.CORSAllowOrigins = GetEnvList("CORS_ALLOW_ORIGINS", cfg.CORSAllowOrigins)
+	cfg.CORSAllowMethods = GetEnvList("CORS_ALLOW_METHODS", cfg.CORSAllowMethods)
+	cfg.CORSAllowHeaders = GetEnvList("CORS_ALLOW_HEADERS", cfg.CORSAllowHeaders)
+	cfg.CORSExposeHeaders = GetEnvList("CORS_EXPOSE_HEADERS", cfg.CORSExposeHeaders)
+	cfg.CORSAllowCredentials = GetEnvBool("CORS_ALLOW_CREDENTIALS", cfg.CORSAllowCredentials)
+	cfg.CORSMaxAgeSeconds = GetEnvInt("CORS_MAX_AGE_SECONDS", cfg.CORSMaxAgeSeconds)
+	cfg.HSTSEnabled = GetEnvBool("HSTS_ENABLED", cfg.HSTSEnabled)
+	cfg.HSTSMaxAgeSeconds = GetEnvInt("HSTS_MAX_AGE_SECONDS", cfg.HSTSMaxAgeSeconds)
+	cfg.HSTSIncludeSubdomains = GetEnvBool("HSTS_INCLUDE_SUBDOMAINS", cfg.HSTSIncludeSubdomains)
+	cfg.FrameOptions = GetEnv("SECURITY_FRAME_OPTIONS", cfg.FrameOptions)
+	cfg.ReferrerPolicy = GetEnv("SECURITY_REFERRER_POLICY", cfg.ReferrerPolicy)
+	cfg.ContentSecurityPolicy = GetEnv("SECURITY_CSP", cfg.ContentSecurityPolicy)
+
+	for _, entry := range GetEnvList("TRUSTED_PROXIES", nil) {
+		prefix, err := parsePrefix(entry)
+		if err != nil {
+			return SecurityConfig{}, fmt.Errorf("invalid TRUSTED_PROXIES entry '%s': expected an IP or CIDR", entry)
+		}
+		cfg.TrustedProxies = append(cfg.TrustedProxies, prefix)
+	}
+
+	// --- Validasi ---
+	for _, origin := range cfg.CORSAllowOrigins {
+		if origin == "*" && cfg.CORSAllowCredentials {
+			return SecurityConfig{}, fmt.Errorf("CORS_ALLOW_ORIGINS cannot contain '*' when CORS_ALLOW_CREDENTIALS=true")
+		}
+		if origin == "*" && cfg.IsProduction() {
+			return SecurityConfig{}, fmt.Errorf("wildcard CORS origin is not allowed in production")
+		}
+	}
+
+	return cfg, nil
+}
+
+// parsePrefix membaca CIDR ("10.0.0.0/8") atau satu alamat IP ("10.0.0.1" = /32 atau /128).
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
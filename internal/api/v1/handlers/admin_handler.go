@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -13,10 +16,15 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/export"
+	"github.com/rakaarfi/attendance-system-be/internal/metrics"
 	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/rbac"
 	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/session"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
 	"github.com/rakaarfi/attendance-system-be/internal/utils"
-	zlog "github.com/rs/zerolog/log"
 )
 
 type AdminHandler struct {
@@ -25,6 +33,12 @@ type AdminHandler struct {
 	AttendanceRepo repository.AttendanceRepository
 	UserRepo       repository.UserRepository
 	RoleRepo       repository.RoleRepository
+	RoleHierarchy  *rbac.Resolver        // Cache role efektif; dibuang setelah hierarki/nama role berubah
+	Sessions       *session.VersionCache // Di-invalidate saat role/status aktif user berubah; nil = tanpa cache
+	AuditRepo      repository.AuditRepository
+	Settings       *settings.Store
+	Events         events.Publisher
+	Tx             repository.TxManager // Perubahan jadwal/akses & event outbox dalam satu transaksi
 	Validate       *validator.Validate
 }
 
@@ -34,6 +48,12 @@ func NewAdminHandler(
 	attRepo repository.AttendanceRepository,
 	userRepo repository.UserRepository,
 	roleRepo repository.RoleRepository,
+	roleHierarchy *rbac.Resolver,
+	settingsStore *settings.Store,
+	auditRepo repository.AuditRepository,
+	eventBus events.Publisher,
+	txManager repository.TxManager,
+	sessions *session.VersionCache,
 ) *AdminHandler {
 	return &AdminHandler{
 		ShiftRepo:      shiftRepo,
@@ -41,10 +61,27 @@ func NewAdminHandler(
 		AttendanceRepo: attRepo,
 		UserRepo:       userRepo,
 		RoleRepo:       roleRepo,
+		RoleHierarchy:  roleHierarchy,
+		Sessions:       sessions,
+		AuditRepo:      auditRepo,
+		Settings:       settingsStore,
+		Events:         eventBus,
+		Tx:             txManager,
 		Validate:       validator.New(),
 	}
 }
 
+// invalidateSessions membuang token_version user dari cache setelah perubahan yang menaikkannya
+// (trigger database: ganti role, nonaktif, anonimisasi) agar token lama langsung ditolak.
+func (h *AdminHandler) invalidateSessions(userIDs ...int) {
+	if h.Sessions == nil {
+		return
+	}
+	for _, id := range userIDs {
+		h.Sessions.Invalidate(id)
+	}
+}
+
 func parseAdminDateQueryParams(c *fiber.Ctx) (startDate time.Time, endDate time.Time, err error) {
 	now := time.Now()
 	// Default rentang: Awal bulan ini sampai akhir hari ini
@@ -57,7 +94,7 @@ func parseAdminDateQueryParams(c *fiber.Ctx) (startDate time.Time, endDate time.
 	if startDateStr != "" {
 		startDate, err = time.Parse(defaultDateFormat, startDateStr)
 		if err != nil {
-			zlog.Warn().Err(err).Str("start_date_query", startDateStr).Msg("Invalid start_date format, using default")
+			reqLogger(c).Warn().Err(err).Str("start_date_query", startDateStr).Msg("Invalid start_date format, using default")
 			startDate = startOfMonth // Fallback
 			err = nil                // Reset error agar tidak stop proses
 		} else {
@@ -71,7 +108,7 @@ func parseAdminDateQueryParams(c *fiber.Ctx) (startDate time.Time, endDate time.
 	if endDateStr != "" {
 		endDate, err = time.Parse(defaultDateFormat, endDateStr)
 		if err != nil {
-			zlog.Warn().Err(err).Str("end_date_query", endDateStr).Msg("Invalid end_date format, using default")
+			reqLogger(c).Warn().Err(err).Str("end_date_query", endDateStr).Msg("Invalid end_date format, using default")
 			endDate = todayEnd // Fallback
 			err = nil          // Reset error
 		} else {
@@ -109,7 +146,7 @@ func (h *AdminHandler) CreateShift(c *fiber.Ctx) error {
 	input := new(models.Shift)
 
 	if err := c.BodyParser(input); err != nil {
-		zlog.Error().Err(err).Msg("Error parsing create shift input")
+		reqLogger(c).Error().Err(err).Msg("Error parsing create shift input")
 		// Pastikan Data ada di error response
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false,
@@ -119,7 +156,7 @@ func (h *AdminHandler) CreateShift(c *fiber.Ctx) error {
 	}
 
 	if err := h.Validate.Struct(input); err != nil {
-		zlog.Warn().Err(err).Msg("Validation failed during shift creation")
+		reqLogger(c).Warn().Err(err).Msg("Validation failed during shift creation")
 		// Pastikan Data ada di error response
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false,
@@ -128,13 +165,13 @@ func (h *AdminHandler) CreateShift(c *fiber.Ctx) error {
 		})
 	}
 
-	zlog.Debug().Msg("Attempting to create shift in DB")
-	shiftID, err := h.ShiftRepo.CreateShift(context.Background(), input)
+	reqLogger(c).Debug().Msg("Attempting to create shift in DB")
+	shiftID, err := h.ShiftRepo.CreateShift(c.UserContext(), input)
 	if err != nil {
 		// Handle specific errors like invalid time format
 		// Pesan error ini harusnya datang dari repo
 		if err.Error() == "invalid time format, use HH:MM:SS" {
-			zlog.Warn().Err(err).Msg("Invalid time format during shift creation")
+			reqLogger(c).Warn().Err(err).Msg("Invalid time format during shift creation")
 			// Pastikan Data ada di error response
 			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 				Success: false,
@@ -142,14 +179,14 @@ func (h *AdminHandler) CreateShift(c *fiber.Ctx) error {
 				Data:    err.Error(),                         // Sertakan error asli di Data
 			})
 		}
-		zlog.Error().Err(err).Msg("Error creating shift in DB")
+		reqLogger(c).Error().Err(err).Msg("Error creating shift in DB")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false,
 			Message: "Failed to create shift", // Pesan generik untuk 500
 		})
 	}
 
-	zlog.Info().Int("shift_id", shiftID).Msg("Shift created successfully")
+	reqLogger(c).Info().Int("shift_id", shiftID).Msg("Shift created successfully")
 	return c.Status(http.StatusCreated).JSON(models.Response{ // Gunakan 201 Created
 		Success: true,
 		Message: "Shift created successfully",
@@ -159,24 +196,30 @@ func (h *AdminHandler) CreateShift(c *fiber.Ctx) error {
 
 // GetAllShifts godoc
 // @Summary Get all shifts
-// @Description Retrieves a list of all shifts.
+// @Description Retrieves a list of all shifts. Supports If-None-Match: 304 Not Modified while no shift was added, changed or deleted.
 // @Tags Admin - Shift Management
 // @Accept json
 // @Produce json
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} models.Response{data=[]models.Shift} "Shifts retrieved successfully"
+// @Success 304 "Shifts not modified"
 // @Failure 500 {object} models.Response "Failed to retrieve shifts"
 // @Security ApiKeyAuth
 // @Router /admin/shifts [get]
 func (h *AdminHandler) GetAllShifts(c *fiber.Ctx) error {
-	shifts, err := h.ShiftRepo.GetAllShifts(context.Background())
+	shifts, err := h.ShiftRepo.GetAllShifts(c.UserContext())
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error getting all shifts")
+		reqLogger(c).Error().Err(err).Msg("Error getting all shifts")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to retrieve shifts",
 		})
 	}
 
-	zlog.Info().Msg("Shifts retrieved successfully")
+	if notModified(c, shiftsETag(shifts), time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	reqLogger(c).Info().Msg("Shifts retrieved successfully")
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Shifts retrieved successfully", Data: shifts,
 	})
@@ -199,27 +242,28 @@ func (h *AdminHandler) GetShiftByID(c *fiber.Ctx) error {
 	idStr := c.Params("shiftId")
 	shiftID, err := strconv.Atoi(idStr)
 	if err != nil {
-		zlog.Warn().Err(err).Str("shiftId_param", idStr).Msg("Invalid Shift ID parameter")
+		reqLogger(c).Warn().Err(err).Str("shiftId_param", idStr).Msg("Invalid Shift ID parameter")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid Shift ID parameter", Data: err.Error(),
 		})
 	}
 
-	shift, err := h.ShiftRepo.GetShiftByID(context.Background(), shiftID)
+	shift, err := h.ShiftRepo.GetShiftByID(c.UserContext(), shiftID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Info().Int("shift_id", shiftID).Msg("Shift with ID not found")
+			reqLogger(c).Info().Int("shift_id", shiftID).Msg("Shift with ID not found")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: fmt.Sprintf("Shift with ID %d not found", shiftID),
 			})
 		}
-		zlog.Error().Err(err).Int("shift_id", shiftID).Msg("Error getting shift by ID")
+		reqLogger(c).Error().Err(err).Int("shift_id", shiftID).Msg("Error getting shift by ID")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to retrieve shift",
 		})
 	}
 
-	zlog.Info().Int("shift_id", shiftID).Msg("Shift retrieved successfully")
+	reqLogger(c).Info().Int("shift_id", shiftID).Msg("Shift retrieved successfully")
+	setVersionETag(c, shift.Version) // Dipakai klien sebagai If-Match saat update
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Shift retrieved successfully", Data: shift,
 	})
@@ -232,10 +276,12 @@ func (h *AdminHandler) GetShiftByID(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param shiftId path int true "Shift ID"
+// @Param If-Match header string false "Expected shift version (ETag from GET), alternative to body field version"
 // @Param update_shift body models.Shift true "Updated shift details"
 // @Success 200 {object} models.Response "Shift updated successfully"
 // @Failure 400 {object} models.Response "Invalid Shift ID parameter or request body"
 // @Failure 404 {object} models.Response "Shift not found"
+// @Failure 412 {object} models.Response "Shift was modified by another request (version mismatch)"
 // @Failure 500 {object} models.Response "Internal server error during shift update"
 // @Security ApiKeyAuth
 // @Router /admin/shifts/{shiftId} [put]
@@ -243,7 +289,7 @@ func (h *AdminHandler) UpdateShift(c *fiber.Ctx) error {
 	idStr := c.Params("shiftId")
 	shiftID, err := strconv.Atoi(idStr)
 	if err != nil {
-		zlog.Warn().Err(err).Str("shiftId_param", idStr).Msg("Invalid Shift ID parameter")
+		reqLogger(c).Warn().Err(err).Str("shiftId_param", idStr).Msg("Invalid Shift ID parameter")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid Shift ID parameter", Data: err.Error(),
 		})
@@ -251,7 +297,7 @@ func (h *AdminHandler) UpdateShift(c *fiber.Ctx) error {
 
 	input := new(models.Shift)
 	if err := c.BodyParser(input); err != nil {
-		zlog.Warn().Err(err).Msg("Invalid request body for update shift")
+		reqLogger(c).Warn().Err(err).Msg("Invalid request body for update shift")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid request body", Data: err.Error(),
 		})
@@ -260,34 +306,44 @@ func (h *AdminHandler) UpdateShift(c *fiber.Ctx) error {
 	input.ID = shiftID
 
 	if err := h.Validate.Struct(input); err != nil {
-		zlog.Warn().Err(err).Int("shift_id", shiftID).Msg("Validation failed during shift update")
+		reqLogger(c).Warn().Err(err).Int("shift_id", shiftID).Msg("Validation failed during shift update")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Validation failed", Data: err.Error(),
 		})
 	}
 
-	err = h.ShiftRepo.UpdateShift(context.Background(), input)
+	// Optimistic locking: versi dari If-Match atau field version di body
+	if input.Version, err = resolveExpectedVersion(c, input.Version); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
+
+	err = h.ShiftRepo.UpdateShift(c.UserContext(), input)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Info().Int("shift_id", shiftID).Msg("Shift with ID not found for update")
+			reqLogger(c).Info().Int("shift_id", shiftID).Msg("Shift with ID not found for update")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: fmt.Sprintf("Shift with ID %d not found", shiftID),
 			})
 		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			reqLogger(c).Warn().Int("shift_id", shiftID).Msg("Shift update rejected: version mismatch")
+			return preconditionFailed(c, "Shift")
+		}
 		// Asumsi repo UpdateShift juga bisa mengembalikan error format waktu
 		if err.Error() == "invalid time format, use HH:MM:SS" {
-			zlog.Warn().Err(err).Int("shift_id", shiftID).Msg("Invalid time format during shift update")
+			reqLogger(c).Warn().Err(err).Int("shift_id", shiftID).Msg("Invalid time format during shift update")
 			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 				Success: false, Message: "Invalid time format, use HH:MM:SS", Data: err.Error(),
 			})
 		}
-		zlog.Error().Err(err).Int("shift_id", shiftID).Msg("Error updating shift")
+		reqLogger(c).Error().Err(err).Int("shift_id", shiftID).Msg("Error updating shift")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to update shift",
 		})
 	}
 
-	zlog.Info().Int("shift_id", shiftID).Msg("Shift updated successfully")
+	reqLogger(c).Info().Int("shift_id", shiftID).Msg("Shift updated successfully")
+	setVersionETag(c, input.Version) // Versi baru setelah update
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Shift updated successfully",
 	})
@@ -311,40 +367,231 @@ func (h *AdminHandler) DeleteShift(c *fiber.Ctx) error {
 	idStr := c.Params("shiftId")
 	shiftID, err := strconv.Atoi(idStr)
 	if err != nil {
-		zlog.Warn().Err(err).Str("shiftId_param", idStr).Msg("Invalid Shift ID parameter")
+		reqLogger(c).Warn().Err(err).Str("shiftId_param", idStr).Msg("Invalid Shift ID parameter")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid Shift ID parameter", Data: err.Error(),
 		})
 	}
 
-	err = h.ShiftRepo.DeleteShift(context.Background(), shiftID)
+	err = h.ShiftRepo.DeleteShift(c.UserContext(), shiftID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Info().Int("shift_id", shiftID).Msg("Shift with ID not found for delete")
+			reqLogger(c).Info().Int("shift_id", shiftID).Msg("Shift with ID not found for delete")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: fmt.Sprintf("Shift with ID %d not found", shiftID),
 			})
 		}
 		if err.Error() == "cannot delete shift: it is still referenced by user schedules" {
-			zlog.Warn().Err(err).Int("shift_id", shiftID).Msg("Cannot delete shift due to FK constraint")
+			reqLogger(c).Warn().Err(err).Int("shift_id", shiftID).Msg("Cannot delete shift due to FK constraint")
 			return c.Status(fiber.StatusConflict).JSON(models.Response{
 				Success: false, Message: err.Error(),
 			})
 		}
-		zlog.Error().Err(err).Int("shift_id", shiftID).Msg("Error deleting shift")
+		reqLogger(c).Error().Err(err).Int("shift_id", shiftID).Msg("Error deleting shift")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to delete shift",
 		})
 	}
 
-	zlog.Info().Int("shift_id", shiftID).Msg("Shift deleted successfully")
+	reqLogger(c).Info().Int("shift_id", shiftID).Msg("Shift deleted successfully")
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Shift deleted successfully",
 	})
 }
 
+// validateShiftOverride memeriksa aturan yang tidak bisa diungkapkan tag validator.
+func validateShiftOverride(input *models.ShiftOverrideInput) string {
+	if input.EndDate < input.StartDate {
+		return "end_date must not be before start_date"
+	}
+	if input.StartTime != nil {
+		if input.ShiftID == nil {
+			return "start_time/end_time require shift_id; use offsets for an override of all shifts"
+		}
+		if input.StartOffsetMinutes != 0 || input.EndOffsetMinutes != 0 {
+			return "Use either start_time/end_time or offsets, not both"
+		}
+	} else if input.StartOffsetMinutes == 0 && input.EndOffsetMinutes == 0 {
+		return "Override must change the start or end time"
+	}
+	return ""
+}
+
+// CreateShiftOverride godoc
+// @Summary Create seasonal shift override
+// @Description Changes shift hours for every schedule dated within [start_date, end_date] (e.g. shortened Ramadan hours) without editing shifts or schedules. Without shift_id the override applies to all shifts and shifts their start/end by the given offsets in minutes (negative = earlier); with shift_id it may instead set absolute start_time/end_time. A shift-specific override wins over an all-shift one; among equals the newest wins. Schedules, late check-in detection, sign-off exceptions, shift reminders, overlap checks and labor cost all use the overridden hours; schedules show the applied override as shift.override_id.
+// @Tags Admin - Shift Management
+// @Accept json
+// @Produce json
+// @Param override body models.ShiftOverrideInput true "Override details"
+// @Success 201 {object} models.Response{data=models.ShiftOverride} "Shift override created successfully"
+// @Failure 400 {object} models.Response "Validation failed or invalid request body"
+// @Failure 404 {object} models.Response "Shift not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/shift-overrides [post]
+func (h *AdminHandler) CreateShiftOverride(c *fiber.Ctx) error {
+	input := new(models.ShiftOverrideInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid request body", Data: err.Error(),
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	if msg := validateShiftOverride(input); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: msg})
+	}
+
+	adminUserID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting admin userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+
+	override := &models.ShiftOverride{
+		Name: input.Name, ShiftID: input.ShiftID, StartDate: input.StartDate, EndDate: input.EndDate,
+		StartTime: input.StartTime, EndTime: input.EndTime,
+		StartOffsetMinutes: input.StartOffsetMinutes, EndOffsetMinutes: input.EndOffsetMinutes,
+		CreatedBy: &adminUserID,
+	}
+	if _, err := h.ShiftRepo.CreateShiftOverride(c.UserContext(), override); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Shift with ID %d not found", *input.ShiftID),
+			})
+		}
+		reqLogger(c).Error().Err(err).Msg("Error creating shift override")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to create shift override",
+		})
+	}
+
+	reqLogger(c).Info().Int("override_id", override.ID).Int("admin_id", adminUserID).Msg("Shift override created")
+	return c.Status(http.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Shift override created successfully", Data: override,
+	})
+}
+
+// GetShiftOverrides godoc
+// @Summary List seasonal shift overrides
+// @Description Lists shift overrides, latest start date first. With active_from only overrides that have not ended by that date are returned.
+// @Tags Admin - Shift Management
+// @Produce json
+// @Param active_from query string false "Only overrides ending on or after this date (YYYY-MM-DD)"
+// @Success 200 {object} models.Response{data=[]models.ShiftOverride} "Shift overrides retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid active_from"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/shift-overrides [get]
+func (h *AdminHandler) GetShiftOverrides(c *fiber.Ctx) error {
+	var activeFrom *time.Time
+	if raw := c.Query("active_from"); raw != "" {
+		date, err := time.Parse(defaultDateFormat, raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "Invalid active_from format, use YYYY-MM-DD",
+			})
+		}
+		activeFrom = &date
+	}
+	overrides, err := h.ShiftRepo.GetShiftOverrides(c.UserContext(), activeFrom)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error getting shift overrides")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve shift overrides",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Shift overrides retrieved successfully", Data: overrides,
+	})
+}
+
+// DeleteShiftOverride godoc
+// @Summary Delete seasonal shift override
+// @Description Deletes a shift override; schedules in its range return to the regular shift hours.
+// @Tags Admin - Shift Management
+// @Produce json
+// @Param overrideId path int true "Override ID"
+// @Success 200 {object} models.Response "Shift override deleted successfully"
+// @Failure 400 {object} models.Response "Invalid override ID parameter"
+// @Failure 404 {object} models.Response "Shift override not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/shift-overrides/{overrideId} [delete]
+func (h *AdminHandler) DeleteShiftOverride(c *fiber.Ctx) error {
+	overrideID, err := strconv.Atoi(c.Params("overrideId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid override ID parameter", Data: err.Error(),
+		})
+	}
+	if err := h.ShiftRepo.DeleteShiftOverride(c.UserContext(), overrideID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Shift override with ID %d not found", overrideID),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("override_id", overrideID).Msg("Error deleting shift override")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to delete shift override",
+		})
+	}
+	reqLogger(c).Info().Int("override_id", overrideID).Msg("Shift override deleted")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Shift override deleted successfully",
+	})
+}
+
 // -------------------------------------------------------------------------
 // Schedule Management
+// scheduleOverlapResponse menjawab 409 beserta daftar jadwal yang jam shift-nya bertabrakan.
+func scheduleOverlapResponse(c *fiber.Ctx, overlap *repository.ScheduleConflictError) error {
+	reqLogger(c).Warn().Int("conflicts", len(overlap.Conflicts)).Msg("Schedule rejected: overlapping shift")
+	return c.Status(fiber.StatusConflict).JSON(models.Response{
+		Success: false, Message: overlap.Error(), Data: fiber.Map{"conflicts": overlap.Conflicts},
+	})
+}
+
+// publishScheduleEvent mem-publish perubahan jadwal. previousUserID adalah pemilik jadwal
+// sebelum perubahan, agar subscriber notifikasi juga memberi tahu pemilik lama jika jadwal dipindahkan.
+func (h *AdminHandler) publishScheduleEvent(c *fiber.Ctx, name string, userID, scheduleID int, date string, previousUserID int) {
+	publishEvent(c, h.Events, events.Event{
+		Name: name, UserID: userID,
+		Data: map[string]any{"schedule_id": scheduleID, "date": date, "previous_user_id": previousUserID},
+	})
+}
+
+// scheduleBeforeChange mengambil jadwal sebelum diubah/dihapus agar pemilik lamanya bisa
+// dinotifikasi. Mengembalikan nil jika event nonaktif atau jadwal tidak ditemukan.
+func (h *AdminHandler) scheduleBeforeChange(c *fiber.Ctx, scheduleID int) *models.UserSchedule {
+	if h.Events == nil {
+		return nil
+	}
+	schedule, err := h.ScheduleRepo.GetScheduleByID(c.UserContext(), scheduleID)
+	if err != nil {
+		return nil
+	}
+	return schedule
+}
+
+// publishScheduleUpdate mem-publish ScheduleUpdated untuk kondisi jadwal setelah update.
+func (h *AdminHandler) publishScheduleUpdate(c *fiber.Ctx, before *models.UserSchedule, after *models.UserSchedule) {
+	if after == nil {
+		return
+	}
+	previousUserID := after.UserID
+	if before != nil {
+		previousUserID = before.UserID
+	}
+	h.publishScheduleEvent(c, events.ScheduleUpdated, after.UserID, after.ID, after.Date, previousUserID)
+}
+
 // -------------------------------------------------------------------------
 // CreateSchedule godoc
 // @Summary Create new schedule
@@ -355,7 +602,7 @@ func (h *AdminHandler) DeleteShift(c *fiber.Ctx) error {
 // @Param create_schedule body models.UserSchedule true "Schedule details"
 // @Success 201 {object} models.Response{data=int} "Schedule created successfully, returns schedule ID"
 // @Failure 400 {object} models.Response "Validation failed or invalid request body"
-// @Failure 409 {object} models.Response "User already has a schedule on same date and time"
+// @Failure 409 {object} models.Response{data=map[string][]models.ScheduleConflict} "User already has a schedule on that date, or the shift overlaps another schedule (conflicts listed)"
 // @Failure 500 {object} models.Response "Internal server error during schedule creation"
 // @Security ApiKeyAuth
 // @Router /admin/schedules [post]
@@ -363,31 +610,43 @@ func (h *AdminHandler) CreateSchedule(c *fiber.Ctx) error {
 	input := new(models.UserSchedule)
 
 	if err := c.BodyParser(input); err != nil {
-		zlog.Warn().Err(err).Msg("Invalid request body for create schedule")
+		reqLogger(c).Warn().Err(err).Msg("Invalid request body for create schedule")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid request body", Data: err.Error(),
 		})
 	}
 
 	if err := h.Validate.Struct(input); err != nil {
-		zlog.Warn().Err(err).Msg("Validation failed during schedule creation")
+		reqLogger(c).Warn().Err(err).Msg("Validation failed during schedule creation")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Validation failed", Data: err.Error(),
 		})
 	}
 
 	// Optional: Validasi user/shift ID di sini (jika di-enable, tambahkan mock di test)
-	// _, errUser := h.UserRepo.GetUserByID(context.Background(), input.UserID)
-	// _, errShift := h.ShiftRepo.GetShiftByID(context.Background(), input.ShiftID)
+	// _, errUser := h.UserRepo.GetUserByID(c.UserContext(), input.UserID)
+	// _, errShift := h.ShiftRepo.GetShiftByID(c.UserContext(), input.ShiftID)
 	// if errUser != nil || errShift != nil {
-	//     zlog.Warn().Msgf("Validation failed for user/shift ID in create schedule: UserID=%d, ShiftID=%d, ErrUser=%v, ErrShift=%v", input.UserID, input.ShiftID, errUser, errShift)
+	//     reqLogger(c).Warn().Msgf("Validation failed for user/shift ID in create schedule: UserID=%d, ShiftID=%d, ErrUser=%v, ErrShift=%v", input.UserID, input.ShiftID, errUser, errShift)
 	// 	return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 	// 			Success: false, Message: "Invalid User ID or Shift ID provided",
 	// 		})
 	// }
 
-	scheduleID, err := h.ScheduleRepo.CreateSchedule(context.Background(), input)
+	var scheduleID int
+	err := runInTx(c, h.Tx, func() error {
+		var err error
+		if scheduleID, err = h.ScheduleRepo.CreateSchedule(c.UserContext(), input); err != nil {
+			return err
+		}
+		h.publishScheduleEvent(c, events.ScheduleCreated, input.UserID, scheduleID, input.Date, input.UserID)
+		return nil
+	})
 	if err != nil {
+		var overlap *repository.ScheduleConflictError
+		if errors.As(err, &overlap) {
+			return scheduleOverlapResponse(c, overlap)
+		}
 		errMsg := "Failed to create schedule"
 		status := fiber.StatusInternalServerError
 		data := interface{}(nil) // Default data nil
@@ -409,14 +668,14 @@ func (h *AdminHandler) CreateSchedule(c *fiber.Ctx) error {
 			status = fiber.StatusBadRequest
 			data = err.Error() // Kirim error asli di data
 		} else {
-			zlog.Error().Err(err).Int("user_id", input.UserID).Int("shift_id", input.ShiftID).Msg("Error creating schedule")
+			reqLogger(c).Error().Err(err).Int("user_id", input.UserID).Int("shift_id", input.ShiftID).Msg("Error creating schedule")
 		}
 		return c.Status(status).JSON(models.Response{
 			Success: false, Message: errMsg, Data: data, // Sertakan Data
 		})
 	}
 
-	zlog.Info().Int("scheduleId", scheduleID).Int("user_id", input.UserID).Int("shift_id", input.ShiftID).Msg("Schedule created successfully")
+	reqLogger(c).Info().Int("scheduleId", scheduleID).Int("user_id", input.UserID).Int("shift_id", input.ShiftID).Msg("Schedule created successfully")
 	return c.Status(http.StatusCreated).JSON(models.Response{ // Gunakan 201 Created
 		Success: true, Message: "Schedule created successfully", Data: fiber.Map{"scheduleId": scheduleID},
 	})
@@ -424,7 +683,7 @@ func (h *AdminHandler) CreateSchedule(c *fiber.Ctx) error {
 
 // GetUserSchedules godoc
 // @Summary Get schedules for user
-// @Description Retrieves a list of schedules for a specific user.
+// @Description Retrieves a list of schedules for a specific user. With include=attendance each schedule carries the attendance recorded on its date (first check-in) and attendance_status "present" or "missing". Supports If-None-Match: 304 Not Modified while the page is unchanged.
 // @Tags Admin - Schedule Management
 // @Accept json
 // @Produce json
@@ -433,7 +692,10 @@ func (h *AdminHandler) CreateSchedule(c *fiber.Ctx) error {
 // @Param end_date query string false "End date for schedule retrieval (YYYY-MM-DD)"
 // @Param page query int false "Page number for pagination"
 // @Param limit query int false "Limit of schedules per page"
+// @Param include query string false "Related data to embed, comma-separated (supported: user, shift, attendance; default: shift)"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} models.Response{data=[]models.UserSchedule} "Schedules retrieved successfully"
+// @Success 304 "Schedules not modified"
 // @Failure 400 {object} models.Response "Validation failed or invalid request body"
 // @Failure 404 {object} models.Response "User not found"
 // @Failure 500 {object} models.Response "Internal server error during schedule retrieval"
@@ -444,20 +706,25 @@ func (h *AdminHandler) GetUserSchedules(c *fiber.Ctx) error {
 	targetUserIdStr := c.Params("userId")
 	targetUserId, err := strconv.Atoi(targetUserIdStr)
 	if err != nil {
-		zlog.Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for getting schedules")
+		reqLogger(c).Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for getting schedules")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid User ID parameter",
 		})
 	}
 
-	// 2. Parse Tanggal
+	// 2. Parse Tanggal & include
 	startDate, endDate, dateErr := parseAdminDateQueryParams(c)
 	if dateErr != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: dateErr.Error()})
 	}
+	includes, err := parseIncludes(c, userScheduleIncludes, models.IncludeShift)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
+	withAttendance := includes.Has(models.IncludeAttendance)
 
 	// 3. Verifikasi User ID (opsional)
-	_, errUser := h.UserRepo.GetUserByID(context.Background(), targetUserId)
+	_, errUser := h.UserRepo.GetUserByID(c.UserContext(), targetUserId)
 	if errUser != nil { /* ... handle user not found (404) atau error lain (500) ... */
 		if errors.Is(errUser, pgx.ErrNoRows) {
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: fmt.Sprintf("User with ID %d not found", targetUserId)})
@@ -469,14 +736,26 @@ func (h *AdminHandler) GetUserSchedules(c *fiber.Ctx) error {
 	pagination := utils.ParsePaginationParams(c)
 
 	// 5. Panggil Repository (Asumsi repo sudah diupdate untuk pagination)
-	schedules, totalCount, err := h.ScheduleRepo.GetSchedulesByUser(context.Background(), targetUserId, startDate, endDate, pagination.Page, pagination.Limit)
+	var schedules []models.UserSchedule
+	var totalCount int
+	if withAttendance {
+		schedules, totalCount, err = h.ScheduleRepo.GetSchedulesByUserWithAttendance(c.UserContext(), targetUserId, startDate, endDate, pagination.Page, pagination.Limit)
+	} else {
+		schedules, totalCount, err = h.ScheduleRepo.GetSchedulesByUser(c.UserContext(), targetUserId, startDate, endDate, pagination.Page, pagination.Limit)
+	}
+	if err == nil {
+		err = includeSchedules(c.UserContext(), h.UserRepo, schedules, includes)
+	}
 	if err != nil {
-		zlog.Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to get user schedules from repository")
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to get user schedules from repository")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve schedules for the user"})
 	}
 
-	// 6. Bangun Metadata dan Response
+	// 6. Bangun Metadata dan Response (304 jika halaman ini belum berubah)
 	meta := utils.BuildPaginationMeta(totalCount, pagination.Limit, pagination.Page)
+	if notModified(c, schedulesETag(schedules, meta), time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 	// response := utils.NewPaginatedResponse("User schedules retrieved successfully", schedules, meta)
 	// Versi non-generic:
 	response := struct {
@@ -492,10 +771,11 @@ func (h *AdminHandler) GetUserSchedules(c *fiber.Ctx) error {
 	}
 
 	adminUserId, _ := utils.ExtractUserIDFromJWT(c) // Untuk log
-	zlog.Info().
+	reqLogger(c).Info().
 		Int("admin_id", adminUserId).
 		Int("target_user_id", targetUserId).
 		Int("schedule_count", len(schedules)).
+		Bool("include_attendance", withAttendance).
 		Time("start_date", startDate).
 		Time("end_date", endDate).
 		Msg("Admin successfully retrieved schedules for user")
@@ -505,7 +785,7 @@ func (h *AdminHandler) GetUserSchedules(c *fiber.Ctx) error {
 
 // GetAllSchedules godoc
 // @Summary Get all schedules
-// @Description Retrieves a list of all schedules for all users.
+// @Description Retrieves a list of all schedules for all users. Supports If-None-Match: 304 Not Modified while the page is unchanged.
 // @Tags Admin - Schedule Management
 // @Accept json
 // @Produce json
@@ -513,7 +793,10 @@ func (h *AdminHandler) GetUserSchedules(c *fiber.Ctx) error {
 // @Param end_date query string false "End date for schedule retrieval (YYYY-MM-DD)"
 // @Param page query int false "Page number for pagination"
 // @Param limit query int false "Limit of schedules per page"
+// @Param include query string false "Related data to embed, comma-separated (supported: user, shift; default: user,shift)"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} models.Response{data=[]models.UserSchedule} "Schedules retrieved successfully"
+// @Success 304 "Schedules not modified"
 // @Failure 400 {object} models.Response "Validation failed or invalid request body"
 // @Failure 500 {object} models.Response "Internal server error during schedule retrieval"
 // @Security ApiKeyAuth
@@ -525,18 +808,28 @@ func (h *AdminHandler) GetAllSchedules(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: dateErr.Error()})
 	}
 
-	// 2. Parse Pagination
+	// 2. Parse Pagination & include
 	pagination := utils.ParsePaginationParams(c)
+	includes, err := parseIncludes(c, scheduleIncludes, models.IncludeUser, models.IncludeShift)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
 
 	// 3. Panggil Repository (Asumsi repo sudah diupdate)
-	schedules, totalCount, err := h.ScheduleRepo.GetSchedulesByDateRangeForAllUsers(context.Background(), startDate, endDate, pagination.Page, pagination.Limit)
+	schedules, totalCount, err := h.ScheduleRepo.GetSchedulesByDateRangeForAllUsers(c.UserContext(), startDate, endDate, pagination.Page, pagination.Limit)
+	if err == nil {
+		err = includeSchedules(c.UserContext(), h.UserRepo, schedules, includes)
+	}
 	if err != nil {
-		zlog.Error().Err(err).Msg("Failed to get all schedules from repository")
+		reqLogger(c).Error().Err(err).Msg("Failed to get all schedules from repository")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve schedules"})
 	}
 
-	// 4. Bangun Metadata dan Response
+	// 4. Bangun Metadata dan Response (304 jika halaman ini belum berubah)
 	meta := utils.BuildPaginationMeta(totalCount, pagination.Limit, pagination.Page)
+	if notModified(c, schedulesETag(schedules, meta), time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 	// response := utils.NewPaginatedResponse("Schedules retrieved successfully", schedules, meta)
 	// Versi non-generic:
 	response := struct {
@@ -552,7 +845,7 @@ func (h *AdminHandler) GetAllSchedules(c *fiber.Ctx) error {
 	}
 
 	adminUserId, _ := utils.ExtractUserIDFromJWT(c) // Untuk log
-	zlog.Info().
+	reqLogger(c).Info().
 		Int("admin_id", adminUserId).
 		Time("start_date", startDate).
 		Time("end_date", endDate).
@@ -566,24 +859,26 @@ func (h *AdminHandler) GetAllSchedules(c *fiber.Ctx) error {
 
 // UpdateSchedule godoc
 // @Summary Update schedule
-// @Description Updates an existing schedule by its ID.
+// @Description Replaces an existing schedule by its ID (all fields required). Use PATCH for partial updates.
 // @Tags Admin - Schedule Management
 // @Accept json
 // @Produce json
 // @Param scheduleId path int true "Schedule ID"
+// @Param If-Match header string false "Expected schedule version, alternative to body field version"
 // @Param update_schedule body models.UserSchedule true "Schedule details"
 // @Success 200 {object} models.Response "Schedule updated successfully"
 // @Failure 400 {object} models.Response "Validation failed or invalid request body"
 // @Failure 404 {object} models.Response "Schedule not found"
-// @Failure 409 {object} models.Response "User already has a schedule on same date and time"
+// @Failure 409 {object} models.Response{data=map[string][]models.ScheduleConflict} "User already has a schedule on that date, or the shift overlaps another schedule (conflicts listed)"
+// @Failure 412 {object} models.Response "Schedule was modified by another request (version mismatch)"
 // @Failure 500 {object} models.Response "Internal server error during schedule update"
 // @Security ApiKeyAuth
-// @Router /admin/schedules/{scheduleId} [patch]
+// @Router /admin/schedules/{scheduleId} [put]
 func (h *AdminHandler) UpdateSchedule(c *fiber.Ctx) error {
 	scheduleIDStr := c.Params("scheduleId") // Sesuaikan nama param
 	scheduleID, err := strconv.Atoi(scheduleIDStr)
 	if err != nil {
-		zlog.Warn().Err(err).Msg("Invalid schedule ID")
+		reqLogger(c).Warn().Err(err).Msg("Invalid schedule ID")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid schedule ID",
 		})
@@ -591,7 +886,7 @@ func (h *AdminHandler) UpdateSchedule(c *fiber.Ctx) error {
 
 	input := new(models.UserSchedule)
 	if err := c.BodyParser(input); err != nil {
-		zlog.Warn().Err(err).Msg("Invalid request body for update schedule")
+		reqLogger(c).Warn().Err(err).Msg("Invalid request body for update schedule")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid request body", Data: err.Error(),
 		})
@@ -599,52 +894,172 @@ func (h *AdminHandler) UpdateSchedule(c *fiber.Ctx) error {
 
 	// --- Validasi Input Struct ---
 	if err := h.Validate.Struct(input); err != nil {
-		zlog.Warn().Err(err).Msg("Update schedule validation failed")
+		reqLogger(c).Warn().Err(err).Msg("Update schedule validation failed")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Validation failed", Data: err.Error(),
 		})
 	}
 
 	// --- Validasi User ID dan Shift ID ---
-	_, errUser := h.UserRepo.GetUserByID(context.Background(), input.UserID)
-	_, errShift := h.ShiftRepo.GetShiftByID(context.Background(), input.ShiftID)
+	_, errUser := h.UserRepo.GetUserByID(c.UserContext(), input.UserID)
+	_, errShift := h.ShiftRepo.GetShiftByID(c.UserContext(), input.ShiftID)
 	if errUser != nil || errShift != nil {
-		zlog.Warn().Msgf("Validation failed for user/shift ID in update schedule: UserID=%d, ShiftID=%d, ErrUser=%v, ErrShift=%v", input.UserID, input.ShiftID, errUser, errShift)
+		reqLogger(c).Warn().Msgf("Validation failed for user/shift ID in update schedule: UserID=%d, ShiftID=%d, ErrUser=%v, ErrShift=%v", input.UserID, input.ShiftID, errUser, errShift)
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid User ID or Shift ID provided",
 		})
 	}
 
 	input.ID = scheduleID // Set ID dari parameter URL
-	err = h.ScheduleRepo.UpdateSchedule(context.Background(), input)
+	// Optimistic locking: versi dari If-Match atau field version di body
+	if input.Version, err = resolveExpectedVersion(c, input.Version); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
+	before := h.scheduleBeforeChange(c, scheduleID)
+	err = runInTx(c, h.Tx, func() error {
+		if err := h.ScheduleRepo.UpdateSchedule(c.UserContext(), input); err != nil {
+			return err
+		}
+		h.publishScheduleUpdate(c, before, input)
+		return nil
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Warn().Int("schedule_id", scheduleID).Msg("Attempted to update non-existent schedule")
+			reqLogger(c).Warn().Int("schedule_id", scheduleID).Msg("Attempted to update non-existent schedule")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: fmt.Sprintf("Schedule with ID %d not found", scheduleID),
 			})
 		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			reqLogger(c).Warn().Int("schedule_id", scheduleID).Msg("Schedule update rejected: version mismatch")
+			return preconditionFailed(c, "Schedule")
+		}
+		var overlap *repository.ScheduleConflictError
+		if errors.As(err, &overlap) {
+			return scheduleOverlapResponse(c, overlap)
+		}
 		if strings.Contains(err.Error(), "already has a schedule on") { // Cek error unique constraint
-			zlog.Warn().Err(err).Int("schedule_id", scheduleID).Msg("Unique constraint violation during schedule update")
+			reqLogger(c).Warn().Err(err).Int("schedule_id", scheduleID).Msg("Unique constraint violation during schedule update")
 			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
 		}
 		if strings.Contains(err.Error(), "invalid user_id") || strings.Contains(err.Error(), "invalid shift_id") { // Cek error FK
-			zlog.Warn().Err(err).Int("schedule_id", scheduleID).Msg("Foreign key violation during schedule update")
+			reqLogger(c).Warn().Err(err).Int("schedule_id", scheduleID).Msg("Foreign key violation during schedule update")
 			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
 		}
 		if strings.Contains(err.Error(), "invalid date format") { // Cek error format tanggal
-			zlog.Warn().Err(err).Int("schedule_id", scheduleID).Msg("Invalid date format during schedule update")
+			reqLogger(c).Warn().Err(err).Int("schedule_id", scheduleID).Msg("Invalid date format during schedule update")
 			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid date format, use YYYY-MM-DD"})
 		}
 
 		// Error fallback
-		zlog.Error().Err(err).Int("schedule_id", scheduleID).Msg("Error updating schedule")
+		reqLogger(c).Error().Err(err).Int("schedule_id", scheduleID).Msg("Error updating schedule")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to update schedule",
+		})
+	}
+
+	reqLogger(c).Info().Int("scheduleId", scheduleID).Msg("Schedule updated successfully")
+	setVersionETag(c, input.Version) // Versi baru setelah update
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Success: true, Message: "Schedule updated successfully",
+	})
+}
+
+// PatchSchedule godoc
+// @Summary Partially update schedule
+// @Description Updates only the provided fields (user_id, shift_id, date) of an existing schedule.
+// @Tags Admin - Schedule Management
+// @Accept json
+// @Produce json
+// @Param scheduleId path int true "Schedule ID"
+// @Param If-Match header string false "Expected schedule version, alternative to body field version"
+// @Param patch_schedule body models.PatchScheduleInput true "Fields to change"
+// @Success 200 {object} models.Response "Schedule updated successfully"
+// @Failure 400 {object} models.Response "Validation failed, no fields provided, or invalid user/shift ID"
+// @Failure 404 {object} models.Response "Schedule not found"
+// @Failure 409 {object} models.Response{data=map[string][]models.ScheduleConflict} "User already has a schedule on that date, or the shift overlaps another schedule (conflicts listed)"
+// @Failure 412 {object} models.Response "Schedule was modified by another request (version mismatch)"
+// @Failure 500 {object} models.Response "Internal server error during schedule update"
+// @Security ApiKeyAuth
+// @Router /admin/schedules/{scheduleId} [patch]
+func (h *AdminHandler) PatchSchedule(c *fiber.Ctx) error {
+	scheduleIDStr := c.Params("scheduleId")
+	scheduleID, err := strconv.Atoi(scheduleIDStr)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Invalid schedule ID")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid schedule ID",
+		})
+	}
+
+	input := new(models.PatchScheduleInput)
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Invalid request body for patch schedule")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid request body", Data: err.Error(),
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Patch schedule validation failed")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	// Validasi User ID / Shift ID hanya jika ikut diubah
+	if input.UserID != nil {
+		if _, errUser := h.UserRepo.GetUserByID(c.UserContext(), *input.UserID); errUser != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid User ID provided"})
+		}
+	}
+	if input.ShiftID != nil {
+		if _, errShift := h.ShiftRepo.GetShiftByID(c.UserContext(), *input.ShiftID); errShift != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Shift ID provided"})
+		}
+	}
+
+	// Optimistic locking: versi dari If-Match atau field version di body
+	if input.Version, err = resolveExpectedVersion(c, input.Version); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
+
+	before := h.scheduleBeforeChange(c, scheduleID)
+	var version int
+	err = runInTx(c, h.Tx, func() error {
+		var err error
+		if version, err = h.ScheduleRepo.PatchSchedule(c.UserContext(), scheduleID, input); err != nil {
+			return err
+		}
+		h.publishScheduleUpdate(c, before, h.scheduleBeforeChange(c, scheduleID)) // Kondisi setelah patch
+		return nil
+	})
+	if err != nil {
+		var overlap *repository.ScheduleConflictError
+		switch {
+		case errors.As(err, &overlap):
+			return scheduleOverlapResponse(c, overlap)
+		case errors.Is(err, repository.ErrNoFieldsToUpdate):
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "No fields to update"})
+		case errors.Is(err, pgx.ErrNoRows):
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Schedule with ID %d not found", scheduleID),
+			})
+		case errors.Is(err, repository.ErrVersionConflict):
+			reqLogger(c).Warn().Int("schedule_id", scheduleID).Msg("Schedule patch rejected: version mismatch")
+			return preconditionFailed(c, "Schedule")
+		case strings.Contains(err.Error(), "already has a schedule on"):
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+		case strings.Contains(err.Error(), "invalid user_id"), strings.Contains(err.Error(), "invalid date format"):
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+		}
+		reqLogger(c).Error().Err(err).Int("schedule_id", scheduleID).Msg("Error patching schedule")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to update schedule",
 		})
 	}
 
-	zlog.Info().Int("scheduleId", scheduleID).Msg("Schedule updated successfully")
+	reqLogger(c).Info().Int("scheduleId", scheduleID).Msg("Schedule patched successfully")
+	setVersionETag(c, version)
 	return c.Status(fiber.StatusOK).JSON(models.Response{
 		Success: true, Message: "Schedule updated successfully",
 	})
@@ -667,33 +1082,110 @@ func (h *AdminHandler) DeleteSchedule(c *fiber.Ctx) error {
 	scheduleIDStr := c.Params("scheduleId") // Sesuaikan nama param
 	scheduleID, err := strconv.Atoi(scheduleIDStr)
 	if err != nil {
-		zlog.Warn().Err(err).Msg("Invalid schedule ID")
+		reqLogger(c).Warn().Err(err).Msg("Invalid schedule ID")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid schedule ID",
 		})
 	}
 
-	err = h.ScheduleRepo.DeleteSchedule(context.Background(), scheduleID)
+	before := h.scheduleBeforeChange(c, scheduleID)
+	err = runInTx(c, h.Tx, func() error {
+		if err := h.ScheduleRepo.DeleteSchedule(c.UserContext(), scheduleID); err != nil {
+			return err
+		}
+		if before != nil {
+			h.publishScheduleEvent(c, events.ScheduleDeleted, before.UserID, before.ID, before.Date, before.UserID)
+		}
+		return nil
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Warn().Int("schedule_id", scheduleID).Msg("Attempted to delete non-existent schedule")
+			reqLogger(c).Warn().Int("schedule_id", scheduleID).Msg("Attempted to delete non-existent schedule")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: fmt.Sprintf("Schedule with ID %d not found", scheduleID),
 			})
 		}
 
-		zlog.Error().Err(err).Int("schedule_id", scheduleID).Msg("Error deleting schedule")
+		reqLogger(c).Error().Err(err).Int("schedule_id", scheduleID).Msg("Error deleting schedule")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to delete schedule",
 		})
 	}
 
-	zlog.Info().Int("schedule_id", scheduleID).Msg("Schedule deleted successfully")
+	reqLogger(c).Info().Int("schedule_id", scheduleID).Msg("Schedule deleted successfully")
 	return c.Status(fiber.StatusOK).JSON(models.Response{
 		Success: true, Message: "Schedule deleted successfully",
 	})
 }
 
+// BulkDeleteSchedules godoc
+// @Summary Bulk delete schedules
+// @Description Deletes many schedules in one transaction, selected either by schedule_ids or by a date range (start_date, end_date) optionally limited to user_ids. Schedules that already have attendance recorded on their date are kept. Returns a summary of deleted and kept rows.
+// @Tags Admin - Schedule Management
+// @Accept json
+// @Produce json
+// @Param bulk_delete body models.BulkDeleteSchedulesInput true "Schedule IDs or user/date-range filter"
+// @Success 200 {object} models.Response{data=models.BulkDeleteSchedulesResult} "Bulk delete summary"
+// @Failure 400 {object} models.Response "Validation failed or invalid selection"
+// @Failure 500 {object} models.Response "Internal server error during bulk delete"
+// @Security ApiKeyAuth
+// @Router /admin/schedules/bulk-delete [post]
+func (h *AdminHandler) BulkDeleteSchedules(c *fiber.Ctx) error {
+	input := new(models.BulkDeleteSchedulesInput)
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Invalid request body for bulk delete schedules")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid request body", Data: err.Error(),
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Bulk delete schedules validation failed")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	// Tepat satu mode: schedule_ids ATAU filter rentang tanggal
+	byIDs := len(input.ScheduleIDs) > 0
+	byFilter := input.StartDate != "" || input.EndDate != "" || len(input.UserIDs) > 0
+	if byIDs == byFilter {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Provide either schedule_ids or a start_date/end_date filter (optionally with user_ids), not both",
+		})
+	}
+
+	var startDate, endDate *time.Time
+	if byFilter {
+		start, errStart := time.Parse(defaultDateFormat, input.StartDate)
+		end, errEnd := time.Parse(defaultDateFormat, input.EndDate)
+		if errStart != nil || errEnd != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "start_date and end_date are required in YYYY-MM-DD format",
+			})
+		}
+		if end.Before(start) {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "end_date cannot be before start_date",
+			})
+		}
+		startDate, endDate = &start, &end
+	}
+
+	result, err := h.ScheduleRepo.BulkDeleteSchedules(c.UserContext(), input.ScheduleIDs, input.UserIDs, startDate, endDate)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to bulk delete schedules")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to delete schedules",
+		})
+	}
+
+	adminUserId, _ := utils.ExtractUserIDFromJWT(c) // Untuk log
+	reqLogger(c).Info().Int("admin_id", adminUserId).Int("deleted", result.DeletedCount).Int("kept", result.KeptCount).Msg("Admin bulk deleted schedules")
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Success: true, Message: fmt.Sprintf("%d schedule(s) deleted, %d kept", result.DeletedCount, result.KeptCount), Data: result,
+	})
+}
+
 // -------------------------------------------------------------------------
 // Attendance Reporting
 // -------------------------------------------------------------------------
@@ -703,24 +1195,24 @@ const defaultDateFormat = "2006-01-02"
 func parseDateQueryParam(c *fiber.Ctx, paramName string, defaultValue time.Time) time.Time {
 	dateStr := c.Query(paramName)
 	if dateStr == "" {
-		zlog.Debug().Str("param", paramName).Msg("Query param empty, using default value")
+		reqLogger(c).Debug().Str("param", paramName).Msg("Query param empty, using default value")
 		return defaultValue
 	}
 	t, err := time.Parse(defaultDateFormat, dateStr)
 	if err != nil {
-		zlog.Warn().Err(err).Str("param", paramName).Str("value", dateStr).Msg("Invalid date format in query param, using default value")
+		reqLogger(c).Warn().Err(err).Str("param", paramName).Str("value", dateStr).Msg("Invalid date format in query param, using default value")
 		return defaultValue
 	}
 	localLoc, _ := time.LoadLocation("Local")
 	parsedDate := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, localLoc)
-	zlog.Debug().Str("param", paramName).Time("parsed_date", parsedDate).Msg("Date query param parsed successfully")
+	reqLogger(c).Debug().Str("param", paramName).Time("parsed_date", parsedDate).Msg("Date query param parsed successfully")
 	return parsedDate
 
 }
 
 // GetUserAttendance godoc
 // @Summary Get user attendance
-// @Description Retrieves attendance records for a specific user within a date range.
+// @Description Retrieves attendance records for a specific user within a date range. Completed sessions include an hours breakdown (regular, night, weekend, holiday) based on the payroll.* settings.
 // @Tags Admin - Attendance Management
 // @Accept json
 // @Produce json
@@ -729,6 +1221,7 @@ func parseDateQueryParam(c *fiber.Ctx, paramName string, defaultValue time.Time)
 // @Param end_date query string false "End date for attendance retrieval (YYYY-MM-DD)"
 // @Param page query int false "Page number for pagination"
 // @Param limit query int false "Limit of attendance records per page"
+// @Param include query string false "Related data to embed, comma-separated (supported: user, schedule, shift; default: none)"
 // @Success 200 {object} models.Response{data=[]models.Attendance} "Attendance retrieved successfully"
 // @Failure 400 {object} models.Response "Validation failed or invalid request parameters"
 // @Failure 404 {object} models.Response "User not found"
@@ -740,20 +1233,24 @@ func (h *AdminHandler) GetUserAttendance(c *fiber.Ctx) error {
 	targetUserIdStr := c.Params("userId")
 	targetUserId, err := strconv.Atoi(targetUserIdStr)
 	if err != nil {
-		zlog.Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for getting attendance")
+		reqLogger(c).Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for getting attendance")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid User ID parameter",
 		})
 	}
 
-	// 2. Parse Tanggal
+	// 2. Parse Tanggal & include
 	startDate, endDate, dateErr := parseAdminDateQueryParams(c)
 	if dateErr != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: dateErr.Error()})
 	}
+	includes, err := parseIncludes(c, attendanceIncludes)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
 
 	// 3. (Opsional tapi bagus) Verifikasi User ID target
-	_, errUser := h.UserRepo.GetUserByID(context.Background(), targetUserId)
+	_, errUser := h.UserRepo.GetUserByID(c.UserContext(), targetUserId)
 	if errUser != nil { /* ... handle user not found (404) atau error lain (500) ... */
 		if errors.Is(errUser, pgx.ErrNoRows) {
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: fmt.Sprintf("User with ID %d not found", targetUserId)})
@@ -765,15 +1262,19 @@ func (h *AdminHandler) GetUserAttendance(c *fiber.Ctx) error {
 	pagination := utils.ParsePaginationParams(c)
 
 	// 5. Panggil Repository
-	attendances, totalCount, err := h.AttendanceRepo.GetAttendancesByUser(context.Background(), targetUserId, startDate, endDate, pagination.Page, pagination.Limit)
+	attendances, totalCount, err := h.AttendanceRepo.GetAttendancesByUser(c.UserContext(), targetUserId, startDate, endDate, pagination.Page, pagination.Limit)
+	if err == nil {
+		err = includeAttendances(c.UserContext(), h.UserRepo, h.ScheduleRepo, attendances, includes)
+	}
 	if err != nil {
-		zlog.Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to get user attendance from repository")
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to get user attendance from repository")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to retrieve attendance records for the user",
 		})
 	}
 
 	// 6. Bangun Metadata dan Response
+	h.annotateHours(c, attendances)
 	meta := utils.BuildPaginationMeta(totalCount, pagination.Limit, pagination.Page)
 	// response := utils.NewPaginatedResponse("User attendance records retrieved successfully", attendances, meta)
 	// Versi non-generic:
@@ -790,7 +1291,7 @@ func (h *AdminHandler) GetUserAttendance(c *fiber.Ctx) error {
 	}
 
 	adminUserId, _ := utils.ExtractUserIDFromJWT(c) // Untuk log
-	zlog.Info().
+	reqLogger(c).Info().
 		Int("admin_id", adminUserId).
 		Int("target_user_id", targetUserId).
 		Int("page", pagination.Page).
@@ -802,16 +1303,30 @@ func (h *AdminHandler) GetUserAttendance(c *fiber.Ctx) error {
 	return c.Status(http.StatusOK).JSON(response)
 }
 
+// annotateHours mengisi pembagian jam kerja (Attendance.Hours) untuk sesi yang sudah check-out.
+func (h *AdminHandler) annotateHours(c *fiber.Ctx, attendances []models.Attendance) {
+	rules := h.Settings.HourRules(c.UserContext())
+	for i := range attendances {
+		if checkOut := attendances[i].CheckOutAt; checkOut != nil {
+			hours := rules.Split(attendances[i].CheckInAt, *checkOut)
+			attendances[i].Hours = &hours
+		}
+	}
+}
+
 // GetAttendanceReport godoc
 // @Summary Get attendance report
-// @Description Retrieves a report of attendance records within a specified date range for all users.
+// @Description Retrieves a report of attendance records within a specified date range for all users. Each row includes the user's employment status; employment_status filters rows by it. Completed sessions include an hours breakdown (regular, night, weekend, holiday) based on the payroll.* settings. Rows with an open employee dispute carry open_dispute_id; disputed=true returns only those rows.
 // @Tags Admin - Attendance Management
 // @Accept json
 // @Produce json
 // @Param start_date query string false "Start date for attendance retrieval (YYYY-MM-DD)"
 // @Param end_date query string false "End date for attendance retrieval (YYYY-MM-DD)"
+// @Param employment_status query string false "Only users with this employment status" Enums(probation, permanent, fixed_term, intern, terminated)
+// @Param disputed query bool false "Only records with an open dispute"
 // @Param page query int false "Page number for pagination"
 // @Param limit query int false "Limit of attendance records per page"
+// @Param include query string false "Related data to embed, comma-separated (supported: user, schedule, shift; default: user)"
 // @Success 200 {object} models.Response{data=[]models.Attendance} "Attendance report retrieved successfully"
 // @Failure 400 {object} models.Response "Validation failed or invalid request parameters"
 // @Failure 500 {object} models.Response "Internal server error during attendance retrieval"
@@ -824,19 +1339,33 @@ func (h *AdminHandler) GetAttendanceReport(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: dateErr.Error()})
 	}
 
-	// 2. Parse Pagination
+	// 2. Parse Filter & Pagination
+	filter := models.AttendanceReportFilter{EmploymentStatus: c.Query("employment_status"), DisputedOnly: c.QueryBool("disputed")}
+	if filter.EmploymentStatus != "" && !slices.Contains(models.EmploymentStatuses, filter.EmploymentStatus) {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Invalid employment_status, expected one of: %s", strings.Join(models.EmploymentStatuses, ", ")),
+		})
+	}
 	pagination := utils.ParsePaginationParams(c)
+	includes, err := parseIncludes(c, attendanceIncludes, models.IncludeUser)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
 
 	// 3. Panggil Repository
-	attendances, totalCount, err := h.AttendanceRepo.GetAllAttendances(context.Background(), startDate, endDate, pagination.Page, pagination.Limit)
+	attendances, totalCount, err := h.AttendanceRepo.GetAllAttendances(c.UserContext(), startDate, endDate, filter, pagination.Page, pagination.Limit)
+	if err == nil {
+		err = includeAttendances(c.UserContext(), h.UserRepo, h.ScheduleRepo, attendances, includes)
+	}
 	if err != nil {
-		zlog.Error().Err(err).Msg("Failed to get attendance report from repository")
+		reqLogger(c).Error().Err(err).Msg("Failed to get attendance report from repository")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to retrieve attendance report",
 		})
 	}
 
 	// 4. Bangun Metadata dan Response
+	h.annotateHours(c, attendances)
 	meta := utils.BuildPaginationMeta(totalCount, pagination.Limit, pagination.Page)
 	// Gunakan tipe spesifik jika tidak pakai generic, atau gunakan generic helper
 	// response := utils.NewPaginatedResponse("Attendance report retrieved successfully", attendances, meta)
@@ -854,7 +1383,7 @@ func (h *AdminHandler) GetAttendanceReport(c *fiber.Ctx) error {
 	}
 
 	adminUserId, _ := utils.ExtractUserIDFromJWT(c) // Untuk log
-	zlog.Info().
+	reqLogger(c).Info().
 		Int("admin_id", adminUserId).
 		Int("page", pagination.Page).
 		Int("limit", pagination.Limit).
@@ -865,298 +1394,1655 @@ func (h *AdminHandler) GetAttendanceReport(c *fiber.Ctx) error {
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-// -------------------------------------------------------------------------
-// User Management
-// -------------------------------------------------------------------------
-// GetAllUsers godoc
-// @Summary Get All Users (Admin)
-// @Description Retrieves a paginated list of all users. Requires Admin role.
-// @Tags Admin - Users Management
-// @Accept json
+// GetProjectHoursReport godoc
+// @Summary Get hours per project
+// @Description Aggregates the worked hours of completed attendance sessions (check-in within the date range) per project / cost center, following mid-shift project switches. Time without a project is summed in a final row with a null project_id.
+// @Tags Admin - Attendance Management
 // @Produce json
-// @Param page query int false "Page number for pagination" default(1)
-// @Param limit query int false "Number of items per page" default(10) maximum(100)
-// @Success 200 {object} map[string]interface{} "Successfully retrieved users with pagination metadata"
-// @Failure 400 {object} models.Response "Invalid query parameters"
-// @Failure 401 {object} models.Response "Unauthorized (Invalid or missing token)"
-// @Failure 403 {object} models.Response "Forbidden (User is not an Admin)"
-// @Failure 500 {object} models.Response "Internal server error"
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to the start of the month"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param user_id query int false "Only sessions of this user"
+// @Success 200 {object} models.Response{data=[]models.ProjectHours} "Project hours retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid date range or user_id"
+// @Failure 500 {object} models.Response "Internal server error during aggregation"
 // @Security ApiKeyAuth
-// @Router /admin/users [get]
-func (h *AdminHandler) GetAllUsers(c *fiber.Ctx) error {
-	// --- 1. Baca dan Validasi Parameter Pagination ---
-	page, err := strconv.Atoi(c.Query("page", "1")) // Default page 1
-	if err != nil || page < 1 {
-		zlog.Warn().Str("page_query", c.Query("page", "1")).Msg("Invalid page query parameter, using default 1")
-		page = 1
+// @Router /admin/attendance/report/projects [get]
+func (h *AdminHandler) GetProjectHoursReport(c *fiber.Ctx) error {
+	startDate, endDate, dateErr := parseAdminDateQueryParams(c)
+	if dateErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: dateErr.Error()})
 	}
-
+	var userID *int
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "Invalid user_id query parameter",
+			})
+		}
+		userID = &id
+	}
+
+	totals, err := h.AttendanceRepo.GetProjectHours(c.UserContext(), startDate, endDate, userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to aggregate hours per project")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve project hours",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Project hours retrieved successfully", Data: totals,
+	})
+}
+
+// GetTagHoursReport godoc
+// @Summary Get hours per attendance tag
+// @Description Aggregates completed attendance sessions (check-in within the date range) per tag (e.g. wfh, client_visit, sick): number of sessions, distinct users and worked hours. A session with several tags counts towards each of them; untagged sessions are not listed.
+// @Tags Admin - Attendance Management
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to the start of the month"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param user_id query int false "Only sessions of this user"
+// @Success 200 {object} models.Response{data=[]models.TagHours} "Tag hours retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid date range or user_id"
+// @Failure 500 {object} models.Response "Internal server error during aggregation"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/report/tags [get]
+func (h *AdminHandler) GetTagHoursReport(c *fiber.Ctx) error {
+	startDate, endDate, dateErr := parseAdminDateQueryParams(c)
+	if dateErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: dateErr.Error()})
+	}
+	var userID *int
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "Invalid user_id query parameter",
+			})
+		}
+		userID = &id
+	}
+
+	totals, err := h.AttendanceRepo.GetTagHours(c.UserContext(), startDate, endDate, userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to aggregate hours per tag")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve tag hours",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Tag hours retrieved successfully", Data: totals,
+	})
+}
+
+// GetModeHoursReport godoc
+// @Summary Get hours per work mode
+// @Description Aggregates completed attendance sessions (check-in within the date range) per work mode (onsite, remote, field): number of sessions, distinct users and worked hours.
+// @Tags Admin - Attendance Management
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to the start of the month"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param user_id query int false "Only sessions of this user"
+// @Success 200 {object} models.Response{data=[]models.ModeHours} "Mode hours retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid date range or user_id"
+// @Failure 500 {object} models.Response "Internal server error during aggregation"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/report/modes [get]
+func (h *AdminHandler) GetModeHoursReport(c *fiber.Ctx) error {
+	startDate, endDate, dateErr := parseAdminDateQueryParams(c)
+	if dateErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: dateErr.Error()})
+	}
+	var userID *int
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "Invalid user_id query parameter",
+			})
+		}
+		userID = &id
+	}
+
+	totals, err := h.AttendanceRepo.GetModeHours(c.UserContext(), startDate, endDate, userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to aggregate hours per work mode")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve mode hours",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Mode hours retrieved successfully", Data: totals,
+	})
+}
+
+// occupancyMaxDays membatasi rentang heatmap okupansi per granularitas.
+var occupancyMaxDays = map[string]int{
+	models.OccupancyGranularityHour: 31,
+	models.OccupancyGranularityDay:  366,
+}
+
+// GetOccupancy godoc
+// @Summary Get office occupancy heatmap
+// @Description Counts distinct checked-in users per hour or day slot and per location over a date range, for desk and space planning. The location is the session's work mode (onsite = office, remote, field). A session counts in every slot it overlaps; open sessions count until now. Slots follow the general.default_timezone setting and empty slots are included with headcount 0. Ranges are limited to 31 days for hour and 366 days for day granularity.
+// @Tags Admin - Attendance Management
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to the start of the month"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param granularity query string false "Slot size, defaults to hour" Enums(hour, day)
+// @Param location query string false "Only this location (work mode)" Enums(onsite, remote, field)
+// @Success 200 {object} models.Response{data=models.OccupancyReport} "Occupancy retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid date range, granularity or location"
+// @Failure 500 {object} models.Response "Internal server error during aggregation"
+// @Security ApiKeyAuth
+// @Router /admin/analytics/occupancy [get]
+func (h *AdminHandler) GetOccupancy(c *fiber.Ctx) error {
+	startDate, endDate, dateErr := parseAdminDateQueryParams(c)
+	if dateErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: dateErr.Error()})
+	}
+	granularity := c.Query("granularity", models.OccupancyGranularityHour)
+	maxDays, ok := occupancyMaxDays[granularity]
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid granularity, expected one of: hour, day",
+		})
+	}
+	if days := int(endDate.Sub(startDate).Hours()/24) + 1; days > maxDays {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Date range is limited to %d days for %s granularity", maxDays, granularity),
+		})
+	}
+	var location *string
+	if raw := c.Query("location"); raw != "" {
+		switch raw {
+		case models.AttendanceModeOnsite, models.AttendanceModeRemote, models.AttendanceModeField:
+			location = &raw
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "Invalid location, expected one of: onsite, remote, field",
+			})
+		}
+	}
+
+	timezone := h.Settings.DefaultLocation(c.UserContext()).String()
+	buckets, err := h.AttendanceRepo.GetOccupancy(c.UserContext(), startDate, endDate, granularity, timezone, location)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Str("granularity", granularity).Msg("Failed to aggregate occupancy")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve occupancy",
+		})
+	}
+	report := models.OccupancyReport{
+		StartDate: startDate.Format(defaultDateFormat), EndDate: endDate.Format(defaultDateFormat),
+		Granularity: granularity, Timezone: timezone, Buckets: buckets, Peak: map[string]models.OccupancyBucket{},
+	}
+	for _, b := range buckets {
+		if peak, seen := report.Peak[b.Location]; !seen || b.Headcount > peak.Headcount {
+			report.Peak[b.Location] = b
+		}
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Occupancy retrieved successfully", Data: report,
+	})
+}
+
+// CorrectAttendance godoc
+// @Summary Correct an attendance record
+// @Description Applies an admin correction to an attendance record. The record is never edited in place: a "correction" event (with reason and acting admin) is appended to the attendance ledger and the current record is derived from it. Omitted fields are left unchanged.
+// @Tags Admin - Attendance Management
+// @Accept json
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Param correction body models.AttendanceCorrectionInput true "Correction details (reason is required)"
+// @Success 200 {object} models.Response{data=models.Attendance} "Attendance corrected successfully"
+// @Failure 400 {object} models.Response "Validation failed or invalid request body"
+// @Failure 404 {object} models.Response "Attendance record not found"
+// @Failure 409 {object} models.Response "Attendance falls in a closed payroll period (data.code PAYROLL_PERIOD_CLOSED)"
+// @Failure 500 {object} models.Response "Internal server error during correction"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/{attendanceId}/corrections [post]
+func (h *AdminHandler) CorrectAttendance(c *fiber.Ctx) error {
+	attendanceIdStr := c.Params("attendanceId")
+	attendanceId, err := strconv.Atoi(attendanceIdStr)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Str("param", attendanceIdStr).Msg("Invalid Attendance ID parameter for correction")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid Attendance ID parameter",
+		})
+	}
+
+	adminUserId, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to extract admin user ID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify requesting admin",
+		})
+	}
+
+	input := new(models.AttendanceCorrectionInput)
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Error parsing attendance correction request body")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Failed to parse request body",
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		reqLogger(c).Warn().Err(err).Int("attendance_id", attendanceId).Msg("Attendance correction validation failed")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	if input.CheckInAt == nil && input.CheckOutAt == nil && input.Notes == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "At least one of check_in_at, check_out_at or notes must be provided",
+		})
+	}
+	if input.Notes != nil {
+		if err := noteContentError(*input.Notes); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "Validation failed", Data: err.Error(),
+			})
+		}
+	}
+
+	attendance, err := h.AttendanceRepo.CorrectAttendance(c.UserContext(), attendanceId, input, adminUserId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Attendance record with ID %d not found", attendanceId),
+			})
+		}
+		if errors.Is(err, repository.ErrInvalidAttendanceTimes) {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: err.Error(),
+			})
+		}
+		if handled, resp := attendanceLockedResponse(c, err); handled {
+			return resp
+		}
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceId).Msg("Failed to correct attendance")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to correct attendance record",
+		})
+	}
+
+	reqLogger(c).Info().Int("admin_id", adminUserId).Int("attendance_id", attendanceId).Msg("Admin corrected attendance record")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Attendance corrected successfully", Data: attendance,
+	})
+}
+
+// GetAttendanceHistory godoc
+// @Summary Get attendance change history
+// @Description Retrieves the append-only ledger of events (check-in, check-out, corrections) for an attendance record, oldest first.
+// @Tags Admin - Attendance Management
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Success 200 {object} models.Response{data=[]models.AttendanceEvent} "Attendance history retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid Attendance ID parameter"
+// @Failure 404 {object} models.Response "Attendance record not found"
+// @Failure 500 {object} models.Response "Internal server error during history retrieval"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/{attendanceId}/history [get]
+func (h *AdminHandler) GetAttendanceHistory(c *fiber.Ctx) error {
+	attendanceIdStr := c.Params("attendanceId")
+	attendanceId, err := strconv.Atoi(attendanceIdStr)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Str("param", attendanceIdStr).Msg("Invalid Attendance ID parameter for history")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid Attendance ID parameter",
+		})
+	}
+
+	events, err := h.AttendanceRepo.GetAttendanceEvents(c.UserContext(), attendanceId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Attendance record with ID %d not found", attendanceId),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceId).Msg("Failed to get attendance history")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve attendance history",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Attendance history retrieved successfully", Data: events,
+	})
+}
+
+// GetAttendanceSegments godoc
+// @Summary Get attendance project segments
+// @Description Retrieves the project segments of an attendance record, oldest first. Check-in opens the first segment and each mid-shift project switch closes the running segment and opens a new one.
+// @Tags Admin - Attendance Management
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Success 200 {object} models.Response{data=[]models.AttendanceSegment} "Attendance segments retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid Attendance ID parameter"
+// @Failure 404 {object} models.Response "Attendance record not found"
+// @Failure 500 {object} models.Response "Internal server error during segment retrieval"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/{attendanceId}/segments [get]
+func (h *AdminHandler) GetAttendanceSegments(c *fiber.Ctx) error {
+	attendanceIdStr := c.Params("attendanceId")
+	attendanceId, err := strconv.Atoi(attendanceIdStr)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Str("param", attendanceIdStr).Msg("Invalid Attendance ID parameter for segments")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid Attendance ID parameter",
+		})
+	}
+
+	segments, err := h.AttendanceRepo.GetAttendanceSegments(c.UserContext(), attendanceId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Attendance record with ID %d not found", attendanceId),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceId).Msg("Failed to get attendance segments")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve attendance segments",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Attendance segments retrieved successfully", Data: segments,
+	})
+}
+
+// GetFaceChecks godoc
+// @Summary List check-in face verification results
+// @Description Lists face verification results of check-ins, newest first, with the user and check-in time. review_status=pending (the default) is the review queue of punches flagged for a low match score, a missing selfie or a failed verification; use review_status=all for every result.
+// @Tags Admin - Attendance Management
+// @Produce json
+// @Param review_status query string false "pending (default), approved, rejected or all"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Items per page (default 10)"
+// @Success 200 {object} models.Response{data=[]models.FaceCheck} "Face checks retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid review_status"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/face-checks [get]
+func (h *AdminHandler) GetFaceChecks(c *fiber.Ctx) error {
+	reviewStatus := c.Query("review_status", models.FaceReviewPending)
+	switch reviewStatus {
+	case "all":
+		reviewStatus = ""
+	case models.FaceReviewPending, models.FaceReviewApproved, models.FaceReviewRejected:
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid review_status, expected one of: pending, approved, rejected, all",
+		})
+	}
+	pagination := utils.ParsePaginationParams(c)
+	checks, totalCount, err := h.AttendanceRepo.GetFaceChecks(c.UserContext(), reviewStatus, pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get face checks")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve face checks",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(struct {
+		Success bool                 `json:"success"`
+		Message string               `json:"message"`
+		Data    []models.FaceCheck   `json:"data"`
+		Meta    utils.PaginationMeta `json:"meta"`
+	}{
+		Success: true, Message: "Face checks retrieved successfully",
+		Data: checks, Meta: utils.BuildPaginationMeta(totalCount, pagination.Limit, pagination.Page),
+	})
+}
+
+// ReviewFaceCheck godoc
+// @Summary Review a flagged check-in
+// @Description Resolves the review of a check-in flagged by face verification as approved (the employee checked in) or rejected (someone else punched). Rejecting does not change the attendance record; correct or remove it separately.
+// @Tags Admin - Attendance Management
+// @Accept json
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Param review body models.FaceReviewInput true "Review decision"
+// @Success 200 {object} models.Response{data=models.FaceCheck} "Face check reviewed successfully"
+// @Failure 400 {object} models.Response "Invalid attendance ID or request body"
+// @Failure 404 {object} models.Response "No pending face check for this attendance"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/{attendanceId}/face-review [put]
+func (h *AdminHandler) ReviewFaceCheck(c *fiber.Ctx) error {
+	attendanceID, err := strconv.Atoi(c.Params("attendanceId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid Attendance ID parameter",
+		})
+	}
+	input := new(models.FaceReviewInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid request body", Data: err.Error(),
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed: status must be approved or rejected", Data: err.Error(),
+		})
+	}
+	adminUserID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting admin userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+
+	check, err := h.AttendanceRepo.ReviewFaceCheck(c.UserContext(), attendanceID, input.Status, adminUserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("No pending face check for attendance record %d", attendanceID),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceID).Msg("Failed to review face check")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to review face check",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Face check reviewed successfully", Data: check,
+	})
+}
+
+// GetQueuedCheckInFailures godoc
+// @Summary List queued check-ins that failed to replay
+// @Description Lists check-ins accepted while the system was in degraded or maintenance mode that could not be recorded when they were replayed (dead letters), newest first. The user was notified; HR should re-enter or correct the attendance manually and then resolve the entry. status=open (the default) lists unresolved entries only.
+// @Tags Admin - Attendance Management
+// @Produce json
+// @Param status query string false "open (default), resolved or all"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Items per page (default 10)"
+// @Success 200 {object} models.Response{data=[]models.QueuedCheckInFailure} "Queued check-in failures retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid status"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/queued-check-in-failures [get]
+func (h *AdminHandler) GetQueuedCheckInFailures(c *fiber.Ctx) error {
+	var resolved *bool
+	switch c.Query("status", "open") {
+	case "open":
+		resolved = new(bool)
+	case "resolved":
+		resolved = new(bool)
+		*resolved = true
+	case "all":
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid status, expected one of: open, resolved, all",
+		})
+	}
+	pagination := utils.ParsePaginationParams(c)
+	failures, totalCount, err := h.AttendanceRepo.GetQueuedCheckInFailures(c.UserContext(), resolved, pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get queued check-in failures")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve queued check-in failures",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(struct {
+		Success bool                          `json:"success"`
+		Message string                        `json:"message"`
+		Data    []models.QueuedCheckInFailure `json:"data"`
+		Meta    utils.PaginationMeta          `json:"meta"`
+	}{
+		Success: true, Message: "Queued check-in failures retrieved successfully",
+		Data: failures, Meta: utils.BuildPaginationMeta(totalCount, pagination.Limit, pagination.Page),
+	})
+}
+
+// ResolveQueuedCheckInFailure godoc
+// @Summary Resolve a queued check-in failure
+// @Description Marks a dead-lettered queued check-in as handled, with a note describing what was done (e.g. attendance entered manually). Resolving does not create an attendance record.
+// @Tags Admin - Attendance Management
+// @Accept json
+// @Produce json
+// @Param failureId path int true "Queued check-in failure ID"
+// @Param resolution body models.ResolveQueuedCheckInFailureInput true "Resolution note"
+// @Success 200 {object} models.Response{data=models.QueuedCheckInFailure} "Queued check-in failure resolved successfully"
+// @Failure 400 {object} models.Response "Invalid failure ID or request body"
+// @Failure 404 {object} models.Response "No open queued check-in failure with this ID"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/queued-check-in-failures/{failureId}/resolve [post]
+func (h *AdminHandler) ResolveQueuedCheckInFailure(c *fiber.Ctx) error {
+	failureID, err := strconv.Atoi(c.Params("failureId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid failure ID parameter",
+		})
+	}
+	input := new(models.ResolveQueuedCheckInFailureInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid request body", Data: err.Error(),
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed: note is required (max 500 characters)", Data: err.Error(),
+		})
+	}
+	adminUserID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting admin userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+
+	failure, err := h.AttendanceRepo.ResolveQueuedCheckInFailure(c.UserContext(), failureID, adminUserID, input.Note)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("No open queued check-in failure with ID %d", failureID),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("failure_id", failureID).Msg("Failed to resolve queued check-in failure")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to resolve queued check-in failure",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Queued check-in failure resolved successfully", Data: failure,
+	})
+}
+
+// -------------------------------------------------------------------------
+// User Management
+// -------------------------------------------------------------------------
+// GetAllUsers godoc
+// @Summary Get All Users (Admin)
+// @Description Retrieves a paginated list of all users. Requires Admin role.
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(10) maximum(100)
+// @Success 200 {object} map[string]interface{} "Successfully retrieved users with pagination metadata"
+// @Failure 400 {object} models.Response "Invalid query parameters"
+// @Failure 401 {object} models.Response "Unauthorized (Invalid or missing token)"
+// @Failure 403 {object} models.Response "Forbidden (User is not an Admin)"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/users [get]
+func (h *AdminHandler) GetAllUsers(c *fiber.Ctx) error {
+	// --- 1. Baca dan Validasi Parameter Pagination ---
+	page, err := strconv.Atoi(c.Query("page", "1")) // Default page 1
+	if err != nil || page < 1 {
+		reqLogger(c).Warn().Str("page_query", c.Query("page", "1")).Msg("Invalid page query parameter, using default 1")
+		page = 1
+	}
+
 	limit, err := strconv.Atoi(c.Query("limit", "10")) // Default limit 10
 	if err != nil || limit < 1 {
-		zlog.Warn().Str("limit_query", c.Query("limit", "10")).Msg("Invalid limit query parameter, using default 10")
+		reqLogger(c).Warn().Str("limit_query", c.Query("limit", "10")).Msg("Invalid limit query parameter, using default 10")
 		limit = 10
 	}
-	// Opsional: Batasi limit maksimum
-	const maxLimit = 100
-	if limit > maxLimit {
-		zlog.Warn().Int("requested_limit", limit).Int("max_limit", maxLimit).Msg("Requested limit exceeds maximum, capping")
-		limit = maxLimit
+	// Opsional: Batasi limit maksimum
+	const maxLimit = 100
+	if limit > maxLimit {
+		reqLogger(c).Warn().Int("requested_limit", limit).Int("max_limit", maxLimit).Msg("Requested limit exceeds maximum, capping")
+		limit = maxLimit
+	}
+
+	// --- 2. Panggil Repository dengan Parameter Pagination ---
+	users, totalCount, err := h.UserRepo.GetAllUsers(c.UserContext(), page, limit)
+	if err != nil {
+		// Error sudah di-log di repo, tapi log di handler juga baik untuk konteks request
+		reqLogger(c).Error().Err(err).Int("page", page).Int("limit", limit).Msg("Failed to get users from repository (paginated)")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve users",
+		})
+	}
+
+	// --- 3. Siapkan Response dengan Metadata ---
+	totalPages := 0
+	if totalCount > 0 && limit > 0 { // Hindari pembagian dengan nol
+		totalPages = int(math.Ceil(float64(totalCount) / float64(limit)))
+	}
+
+	// Buat struktur data response baru yang menyertakan metadata
+	paginatedResponse := struct {
+		Success bool          `json:"success"`
+		Message string        `json:"message"`
+		Data    []models.User `json:"data"`
+		Meta    struct {
+			CurrentPage int `json:"current_page"`
+			PerPage     int `json:"per_page"`
+			TotalItems  int `json:"total_items"`
+			TotalPages  int `json:"total_pages"`
+		} `json:"meta"`
+	}{
+		Success: true,
+		Message: "Users retrieved successfully",
+		Data:    users, // Data user untuk halaman ini
+		Meta: struct {
+			CurrentPage int `json:"current_page"`
+			PerPage     int `json:"per_page"`
+			TotalItems  int `json:"total_items"`
+			TotalPages  int `json:"total_pages"`
+		}{
+			CurrentPage: page,
+			PerPage:     limit,
+			TotalItems:  totalCount,
+			TotalPages:  totalPages,
+		},
+	}
+
+	reqLogger(c).Info().
+		Int("page", page).
+		Int("limit", limit).
+		Int("returned_count", len(users)).
+		Int("total_count", totalCount).
+		Msg("Successfully retrieved paginated users for admin request")
+
+		// Kirim response terstruktur
+	return c.Status(http.StatusOK).JSON(paginatedResponse)
+}
+
+// userExportHeader adalah header kolom ekspor user; urutannya harus sama dengan userExportRecord.
+var userExportHeader = []string{
+	"id", "username", "email", "first_name", "last_name", "role", "user_type", "employment_status",
+	"hire_date", "status", "access_valid_until", "manager_id", "last_activity_at", "previous_usernames",
+}
+
+// userExportRecord mengubah satu baris ekspor menjadi nilai sel. Waktu ditulis dalam zona waktu loc.
+func userExportRecord(row *models.UserExportRow, loc *time.Location) []string {
+	u := &row.User
+	derefOr := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	role, status, managerID, lastActivity := "", "inactive", "", ""
+	if u.Role != nil {
+		role = u.Role.Name
+	}
+	if u.IsActive {
+		status = "active"
+	}
+	if u.ManagerID != nil {
+		managerID = strconv.Itoa(*u.ManagerID)
+	}
+	if row.LastActivityAt != nil {
+		lastActivity = row.LastActivityAt.In(loc).Format(time.RFC3339)
+	}
+	return []string{
+		strconv.Itoa(u.ID), u.Username, u.Email, u.FirstName, u.LastName, role, u.UserType, u.EmploymentStatus,
+		derefOr(u.HireDate), status, derefOr(u.ValidUntil), managerID, lastActivity, strings.Join(row.PreviousUsernames, ";"),
+	}
+}
+
+// parseUserExportFilter membaca filter ekspor user dari query string.
+func parseUserExportFilter(c *fiber.Ctx) (models.UserExportFilter, error) {
+	filter := models.UserExportFilter{
+		UserType:         c.Query("user_type"),
+		EmploymentStatus: c.Query("employment_status"),
+	}
+	if raw := c.Query("role_id"); raw != "" {
+		roleID, err := strconv.Atoi(raw)
+		if err != nil || roleID <= 0 {
+			return filter, errors.New("invalid role_id, expected a positive integer")
+		}
+		filter.RoleID = &roleID
+	}
+	if filter.UserType != "" && filter.UserType != models.UserTypeEmployee && filter.UserType != models.UserTypeContractor {
+		return filter, fmt.Errorf("invalid user_type, expected one of: %s, %s", models.UserTypeEmployee, models.UserTypeContractor)
+	}
+	if filter.EmploymentStatus != "" && !slices.Contains(models.EmploymentStatuses, filter.EmploymentStatus) {
+		return filter, fmt.Errorf("invalid employment_status, expected one of: %s", strings.Join(models.EmploymentStatuses, ", "))
+	}
+	if raw := c.Query("is_active"); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, errors.New("invalid is_active, expected true or false")
+		}
+		filter.IsActive = &active
+	}
+	return filter, nil
+}
+
+// ExportUsers godoc
+// @Summary Export users (Admin)
+// @Description Downloads the full (filtered) user list with role, employment status, account status, last activity and previous usernames (newest first, separated by ";") as CSV or XLSX. The file is streamed; if the export fails midway the download is truncated (XLSX files are then unreadable).
+// @Tags Admin - Users Management
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format" Enums(csv, xlsx) default(csv)
+// @Param role_id query int false "Only users with this role"
+// @Param user_type query string false "Only users of this type" Enums(employee, contractor)
+// @Param employment_status query string false "Only users with this employment status" Enums(probation, permanent, fixed_term, intern, terminated)
+// @Param is_active query bool false "Only active (true) or deactivated (false) users"
+// @Success 200 {file} file "User export file"
+// @Failure 400 {object} models.Response "Invalid query parameters"
+// @Failure 401 {object} models.Response "Unauthorized (Invalid or missing token)"
+// @Failure 403 {object} models.Response "Forbidden (User is not an Admin)"
+// @Security ApiKeyAuth
+// @Router /admin/users/export [get]
+func (h *AdminHandler) ExportUsers(c *fiber.Ctx) error {
+	format := strings.ToLower(c.Query("format", export.FormatCSV))
+	contentType, ok := export.ContentType(format)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Invalid format, expected one of: %s, %s", export.FormatCSV, export.FormatXLSX),
+		})
+	}
+	filter, err := parseUserExportFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
+
+	// Context & logger diambil sekarang: stream writer berjalan setelah handler selesai,
+	// saat fiber.Ctx sudah dikembalikan ke pool.
+	ctx := c.UserContext()
+	logger := reqLogger(c)
+	loc := h.Settings.DefaultLocation(ctx)
+	now := time.Now().In(loc)
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Attachment(fmt.Sprintf("users-%s.%s", now.Format("20060102-150405"), format))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// Status 200 sudah terkirim; error di tengah jalan hanya bisa di-log (file terpotong).
+		exportWriter, err := export.NewWriter(format, w, "Users")
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to start user export")
+			return
+		}
+		count := 0
+		err = exportWriter.WriteRow(userExportHeader)
+		if err == nil {
+			err = h.UserRepo.ExportUsers(ctx, filter, func(row *models.UserExportRow) error {
+				count++
+				return exportWriter.WriteRow(userExportRecord(row, loc))
+			})
+		}
+		if err != nil {
+			logger.Error().Err(err).Int("rows_written", count).Msg("User export aborted")
+			return
+		}
+		if err := exportWriter.Close(); err != nil {
+			logger.Error().Err(err).Msg("Failed to finish user export")
+			return
+		}
+		if err := w.Flush(); err != nil {
+			logger.Warn().Err(err).Msg("Failed to flush user export (client disconnected?)")
+			return
+		}
+		logger.Info().Str("format", format).Int("rows", count).Msg("Users exported")
+	})
+	return nil
+}
+
+// RenderUserReport menulis laporan users untuk ekspor di background (reports.Renderer).
+// report.Params berisi models.UserExportFilter; isinya sama dengan GET /admin/users/export.
+func (h *AdminHandler) RenderUserReport(ctx context.Context, report *models.ReportExport, w export.Writer) (int, error) {
+	var filter models.UserExportFilter
+	if err := json.Unmarshal(report.Params, &filter); err != nil {
+		return 0, fmt.Errorf("invalid user report filter: %w", err)
+	}
+	loc := h.Settings.DefaultLocation(ctx)
+	if err := w.WriteRow(userExportHeader); err != nil {
+		return 0, err
+	}
+	count := 0
+	err := h.UserRepo.ExportUsers(ctx, filter, func(row *models.UserExportRow) error {
+		count++
+		return w.WriteRow(userExportRecord(row, loc))
+	})
+	return count, err
+}
+
+// GetUserByID godoc
+// @Summary Get user by ID
+// @Description Retrieves a user by its ID.
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID"
+// @Success 200 {object} models.Response{data=models.User} "User retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid User ID parameter"
+// @Failure 404 {object} models.Response "User not found"
+// @Failure 500 {object} models.Response "Internal server error during user retrieval"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId} [get]
+func (h *AdminHandler) GetUserByID(c *fiber.Ctx) error {
+	userIdStr := c.Params("userId")
+	userId, err := strconv.Atoi(userIdStr)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Str("param", userIdStr).Msg("Invalid User ID parameter")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid User ID parameter",
+		})
+	}
+
+	adminUserId, _ := utils.ExtractUserIDFromJWT(c) // Abaikan error sementara jika hanya untuk log
+
+	user, err := h.UserRepo.GetUserByID(c.UserContext(), userId)
+	if err != nil {
+		// --- CEK NOT FOUND ---
+		if errors.Is(err, pgx.ErrNoRows) {
+			reqLogger(c).Warn().Int("requested_user_id", userId).Msg("Admin requested non-existent user")
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("User with ID %d not found", userId),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", userId).Msg("Failed to get user from repository")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve user",
+		})
+	}
+	// Logging sukses
+	reqLogger(c).Info().Int("user_id", userId).Int("admin_id", adminUserId).Msg("Successfully retrieved user for admin request")
+	setVersionETag(c, user.Version) // Dipakai klien sebagai If-Match saat update
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "User retrieved successfully", Data: user,
+	})
+}
+
+// UpdateUser godoc
+// @Summary Update user
+// @Description Replaces an existing user's data by its ID (all fields required). Use PATCH for partial updates.
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param If-Match header string false "Expected user version (ETag from GET), alternative to body field version"
+// @Param update_user body models.AdminUpdateUserInput true "User details"
+// @Success 200 {object} models.Response "User updated successfully"
+// @Failure 400 {object} models.Response "Validation failed or invalid request body"
+// @Failure 404 {object} models.Response "User not found"
+// @Failure 412 {object} models.Response "User was modified by another request (version mismatch)"
+// @Failure 500 {object} models.Response "Internal server error during user update"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId} [put]
+func (h *AdminHandler) UpdateUser(c *fiber.Ctx) error {
+	// 1. Dapatkan ID user target dari URL
+	targetUserIdStr := c.Params("userId")
+	targetUserId, err := strconv.Atoi(targetUserIdStr)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for update")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid User ID parameter",
+		})
+	}
+
+	// 2. Dapatkan ID admin yang sedang login (opsional, tapi bisa berguna untuk log)
+	adminUserId, _ := utils.ExtractUserIDFromJWT(c) // Abaikan error sementara jika hanya untuk log
+
+	// 3. Parse & Validasi Input Body (Gunakan struct input baru)
+	input := new(models.AdminUpdateUserInput) // <-- Gunakan input model baru
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error parsing update user request body")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Failed to parse request body",
+		})
+	}
+
+	// 4. Validasi data input menggunakan validator
+	if err := h.Validate.Struct(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Update user validation failed")
+		// Berikan detail error validasi jika perlu (hati-hati info sensitif)
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	// 5. (Opsional tapi direkomendasikan) Validasi Role ID
+	_, errRole := h.RoleRepo.GetRoleByID(c.UserContext(), input.RoleID)
+	if errRole != nil {
+		// Handle jika role ID tidak valid
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Role ID"})
+	}
+
+	// 6. Optimistic locking: versi dari If-Match atau field version di body
+	if input.Version, err = resolveExpectedVersion(c, input.Version); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
+
+	// 7. Panggil repository untuk update user
+	err = h.UserRepo.UpdateUserByID(c.UserContext(), targetUserId, input) // <-- Pass input model baru
+	if err != nil {
+		// Cek apakah error karena user tidak ditemukan
+		if errors.Is(err, pgx.ErrNoRows) {
+			reqLogger(c).Warn().Int("target_user_id", targetUserId).Msg("Attempted to update non-existent user")
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("User with ID %d not found", targetUserId),
+			})
+		}
+		// Cek apakah versi yang dikirim klien sudah usang
+		if errors.Is(err, repository.ErrVersionConflict) {
+			reqLogger(c).Warn().Int("target_user_id", targetUserId).Msg("User update rejected: version mismatch")
+			return preconditionFailed(c, "User")
+		}
+		// Cek apakah error karena unique constraint
+		if strings.Contains(err.Error(), "already exists") {
+			reqLogger(c).Warn().Err(err).Int("target_user_id", targetUserId).Msg("Unique constraint violation during user update by admin")
+			return c.Status(fiber.StatusConflict).JSON(models.Response{ // 409 Conflict
+				Success: false, Message: err.Error(),
+			})
+		}
+
+		// Error lain saat update
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to update user by admin")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to update user",
+		})
+	}
+
+	// 8. Kirim response sukses
+	h.invalidateSessions(targetUserId) // Role/status aktif mungkin berubah
+	reqLogger(c).Info().Int("admin_id", adminUserId).Int("updated_user_id", targetUserId).Msg("Admin successfully updated user")
+	publishEvent(c, h.Events, events.Event{Name: events.ProfileUpdated, UserID: targetUserId, Data: map[string]any{"fields": submittedFields(c)}})
+	setVersionETag(c, input.Version) // Versi baru setelah update
+	// Pertimbangkan untuk mengembalikan data user yang sudah diupdate (ambil lagi dari DB)
+	// atau cukup pesan sukses
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: fmt.Sprintf("User with ID %d updated successfully", targetUserId),
+	})
+}
+
+// PatchUser godoc
+// @Summary Partially update user
+// @Description Updates only the provided fields of an existing user (e.g. only role_id). Send an empty string for phone/national_id to clear them.
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param If-Match header string false "Expected user version (ETag from GET), alternative to body field version"
+// @Param patch_user body models.AdminPatchUserInput true "Fields to change"
+// @Success 200 {object} models.Response "User updated successfully"
+// @Failure 400 {object} models.Response "Validation failed, no fields provided, or invalid role ID"
+// @Failure 404 {object} models.Response "User not found"
+// @Failure 409 {object} models.Response "Username, email or national ID already exists"
+// @Failure 412 {object} models.Response "User was modified by another request (version mismatch)"
+// @Failure 500 {object} models.Response "Internal server error during user update"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId} [patch]
+func (h *AdminHandler) PatchUser(c *fiber.Ctx) error {
+	targetUserIdStr := c.Params("userId")
+	targetUserId, err := strconv.Atoi(targetUserIdStr)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for patch")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid User ID parameter",
+		})
+	}
+
+	adminUserId, _ := utils.ExtractUserIDFromJWT(c) // Untuk log
+
+	input := new(models.AdminPatchUserInput)
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error parsing patch user request body")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Failed to parse request body",
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Patch user validation failed")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	// Validasi Role ID hanya jika ikut diubah
+	if input.RoleID != nil {
+		if _, errRole := h.RoleRepo.GetRoleByID(c.UserContext(), *input.RoleID); errRole != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Role ID"})
+		}
+	}
+
+	// Optimistic locking: versi dari If-Match atau field version di body
+	if input.Version, err = resolveExpectedVersion(c, input.Version); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
+
+	version, err := h.UserRepo.PatchUserByID(c.UserContext(), targetUserId, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNoFieldsToUpdate):
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "No fields to update"})
+		case errors.Is(err, pgx.ErrNoRows):
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("User with ID %d not found", targetUserId),
+			})
+		case errors.Is(err, repository.ErrVersionConflict):
+			reqLogger(c).Warn().Int("target_user_id", targetUserId).Msg("User patch rejected: version mismatch")
+			return preconditionFailed(c, "User")
+		case strings.Contains(err.Error(), "already exists"):
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+		case strings.Contains(err.Error(), "invalid role_id"):
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Role ID"})
+		}
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to patch user by admin")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to update user",
+		})
+	}
+
+	if input.RoleID != nil {
+		h.invalidateSessions(targetUserId)
 	}
+	reqLogger(c).Info().Int("admin_id", adminUserId).Int("updated_user_id", targetUserId).Msg("Admin successfully patched user")
+	publishEvent(c, h.Events, events.Event{Name: events.ProfileUpdated, UserID: targetUserId, Data: map[string]any{"fields": submittedFields(c)}})
+	setVersionETag(c, version)
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: fmt.Sprintf("User with ID %d updated successfully", targetUserId),
+	})
+}
 
-	// --- 2. Panggil Repository dengan Parameter Pagination ---
-	users, totalCount, err := h.UserRepo.GetAllUsers(context.Background(), page, limit)
+// BulkAssignRole godoc
+// @Summary Bulk re-assign user role
+// @Description Assigns one role to many users in a single transaction and returns a per-user result (updated, unchanged, not_found, skipped). The requesting admin cannot remove their own Admin role this way.
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param bulk_role body models.BulkRoleAssignInput true "User IDs and target role"
+// @Success 200 {object} models.Response{data=map[string]interface{}} "Per-user results and summary"
+// @Failure 400 {object} models.Response "Validation failed or invalid role ID"
+// @Failure 500 {object} models.Response "Internal server error during bulk update"
+// @Security ApiKeyAuth
+// @Router /admin/users/bulk/role [post]
+func (h *AdminHandler) BulkAssignRole(c *fiber.Ctx) error {
+	adminUserId, err := utils.ExtractUserIDFromJWT(c)
 	if err != nil {
-		// Error sudah di-log di repo, tapi log di handler juga baik untuk konteks request
-		zlog.Error().Err(err).Int("page", page).Int("limit", limit).Msg("Failed to get users from repository (paginated)")
+		reqLogger(c).Error().Err(err).Msg("Failed to extract admin user ID from JWT")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
-			Success: false, Message: "Failed to retrieve users",
+			Success: false, Message: "Failed to identify requesting admin",
 		})
 	}
 
-	// --- 3. Siapkan Response dengan Metadata ---
-	totalPages := 0
-	if totalCount > 0 && limit > 0 { // Hindari pembagian dengan nol
-		totalPages = int(math.Ceil(float64(totalCount) / float64(limit)))
+	// 1. Parse & validasi input
+	input := new(models.BulkRoleAssignInput)
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Error parsing bulk role request body")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Failed to parse request body",
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Bulk role validation failed")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
 	}
 
-	// Buat struktur data response baru yang menyertakan metadata
-	paginatedResponse := struct {
-		Success bool          `json:"success"`
-		Message string        `json:"message"`
-		Data    []models.User `json:"data"`
-		Meta    struct {
-			CurrentPage int `json:"current_page"`
-			PerPage     int `json:"per_page"`
-			TotalItems  int `json:"total_items"`
-			TotalPages  int `json:"total_pages"`
-		} `json:"meta"`
-	}{
-		Success: true,
-		Message: "Users retrieved successfully",
-		Data:    users, // Data user untuk halaman ini
-		Meta: struct {
-			CurrentPage int `json:"current_page"`
-			PerPage     int `json:"per_page"`
-			TotalItems  int `json:"total_items"`
-			TotalPages  int `json:"total_pages"`
-		}{
-			CurrentPage: page,
-			PerPage:     limit,
-			TotalItems:  totalCount,
-			TotalPages:  totalPages,
-		},
+	// 2. Validasi role target
+	role, err := h.RoleRepo.GetRoleByID(c.UserContext(), input.RoleID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Role ID"})
 	}
 
-	zlog.Info().
-		Int("page", page).
-		Int("limit", limit).
-		Int("returned_count", len(users)).
-		Int("total_count", totalCount).
-		Msg("Successfully retrieved paginated users for admin request")
+	// 3. Admin tidak boleh mencabut role Admin miliknya sendiri (mencegah terkunci)
+	var skipped []models.BulkItemResult
+	userIDs := make([]int, 0, len(input.UserIDs))
+	for _, id := range input.UserIDs {
+		if id == adminUserId && role.Name != "Admin" {
+			if len(skipped) == 0 {
+				skipped = append(skipped, models.BulkItemResult{
+					ID: id, Status: models.BulkStatusSkipped, Message: "Admin cannot change their own role",
+				})
+			}
+			continue
+		}
+		userIDs = append(userIDs, id)
+	}
 
-		// Kirim response terstruktur
-	return c.Status(http.StatusOK).JSON(paginatedResponse)
+	// 4. Terapkan dalam satu transaksi
+	results := []models.BulkItemResult{}
+	if len(userIDs) > 0 {
+		results, err = h.UserRepo.BulkUpdateUserRole(c.UserContext(), userIDs, input.RoleID)
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid role_id") {
+				return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Role ID"})
+			}
+			reqLogger(c).Error().Err(err).Int("role_id", input.RoleID).Msg("Failed to bulk update user roles")
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+				Success: false, Message: "Failed to update user roles",
+			})
+		}
+	}
+	results = append(results, skipped...)
+
+	// 5. Ringkasan per status
+	summary := map[string]int{
+		models.BulkStatusUpdated:   0,
+		models.BulkStatusUnchanged: 0,
+		models.BulkStatusNotFound:  0,
+		models.BulkStatusSkipped:   0,
+	}
+	for _, r := range results {
+		summary[r.Status]++
+		if r.Status == models.BulkStatusUpdated {
+			h.invalidateSessions(r.ID)
+			publishEvent(c, h.Events, events.Event{Name: events.RoleChanged, UserID: r.ID, Data: map[string]any{"role_id": input.RoleID}})
+		}
+	}
+
+	reqLogger(c).Info().
+		Int("admin_id", adminUserId).
+		Int("role_id", input.RoleID).
+		Int("updated", summary[models.BulkStatusUpdated]).
+		Int("not_found", summary[models.BulkStatusNotFound]).
+		Msg("Admin bulk re-assigned user roles")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "User roles updated",
+		Data: fiber.Map{"role_id": input.RoleID, "summary": summary, "results": results},
+	})
 }
 
-// GetUserByID godoc
-// @Summary Get user by ID
-// @Description Retrieves a user by its ID.
+// DeleteUser godoc
+// @Summary Delete User (Admin)
+// @Description Deletes a specific user by ID. Requires Admin role. Admin cannot delete themselves.
 // @Tags Admin - Users Management
 // @Accept json
 // @Produce json
-// @Param userId path int true "User ID"
-// @Success 200 {object} models.Response{data=models.User} "User retrieved successfully"
+// @Param userId path int true "User ID to delete"
+// @Success 200 {object} models.Response "User deleted successfully"
 // @Failure 400 {object} models.Response "Invalid User ID parameter"
+// @Failure 401 {object} models.Response "Unauthorized"
+// @Failure 403 {object} models.Response "Forbidden (Not Admin or attempting self-delete)"
 // @Failure 404 {object} models.Response "User not found"
-// @Failure 500 {object} models.Response "Internal server error during user retrieval"
+// @Failure 500 {object} models.Response "Internal server error"
 // @Security ApiKeyAuth
-// @Router /admin/users/{userId} [get]
-func (h *AdminHandler) GetUserByID(c *fiber.Ctx) error {
-	userIdStr := c.Params("userId")
-	userId, err := strconv.Atoi(userIdStr)
+// @Router /admin/users/{userId} [delete]
+func (h *AdminHandler) DeleteUser(c *fiber.Ctx) error {
+	// 1. Dapatkan ID user yang akan dihapus dari parameter URL
+	targetUserIdStr := c.Params("userId") // Sesuaikan nama param dengan route nanti
+	targetUserId, err := strconv.Atoi(targetUserIdStr)
 	if err != nil {
-		zlog.Warn().Err(err).Str("param", userIdStr).Msg("Invalid User ID parameter")
+		reqLogger(c).Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for deletion")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid User ID parameter",
 		})
 	}
 
-	adminUserId, _ := utils.ExtractUserIDFromJWT(c) // Abaikan error sementara jika hanya untuk log
+	// 2. Dapatkan ID admin yang sedang login dari JWT (PENTING: untuk mencegah hapus diri sendiri)
+	adminUserId, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to extract admin user ID from JWT")
+		// Ini seharusnya tidak terjadi jika middleware auth bekerja, tapi handle untuk keamanan
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify requesting admin",
+		})
+	}
+
+	// 3. Validasi: Admin tidak boleh menghapus dirinya sendiri
+	if targetUserId == adminUserId {
+		reqLogger(c).Warn().Int("admin_id", adminUserId).Msg("Admin attempted to delete themselves")
+		return c.Status(fiber.StatusForbidden).JSON(models.Response{
+			Success: false, Message: "Admin cannot delete their own account",
+		})
+	}
+
+	// 4. Panggil repository untuk menghapus user
+	err = h.UserRepo.DeleteUserByID(c.UserContext(), targetUserId)
+	if err != nil {
+		// Cek apakah error karena user tidak ditemukan
+		if errors.Is(err, pgx.ErrNoRows) {
+			reqLogger(c).Warn().Int("target_user_id", targetUserId).Msg("Attempted to delete non-existent user")
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("User with ID %d not found", targetUserId),
+			})
+		}
+		// Error lain saat menghapus
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to delete user")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to delete user",
+		})
+	}
+
+	// 5. Kirim response sukses
+	reqLogger(c).Info().Int("admin_id", adminUserId).Int("deleted_user_id", targetUserId).Msg("Admin successfully deleted user")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: fmt.Sprintf("User with ID %d deleted successfully", targetUserId),
+	})
+}
+
+// AnonymizeUser godoc
+// @Summary Anonymize user (GDPR erasure)
+// @Description Irreversibly pseudonymizes a user's personal data (username, email, name, phone, national ID, attendance notes) and disables login. Schedules and check-in/check-out times are kept for payroll aggregates.
+// @Tags Admin - Users Management
+// @Produce json
+// @Param userId path int true "User ID to anonymize"
+// @Success 200 {object} models.Response "User anonymized successfully"
+// @Failure 400 {object} models.Response "Invalid User ID parameter"
+// @Failure 401 {object} models.Response "Unauthorized"
+// @Failure 403 {object} models.Response "Forbidden (Not Admin or attempting self-anonymize)"
+// @Failure 404 {object} models.Response "User not found"
+// @Failure 409 {object} models.Response "User already anonymized"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId}/anonymize [post]
+func (h *AdminHandler) AnonymizeUser(c *fiber.Ctx) error {
+	// 1. Dapatkan ID user target dari parameter URL
+	targetUserIdStr := c.Params("userId")
+	targetUserId, err := strconv.Atoi(targetUserIdStr)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for anonymization")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid User ID parameter",
+		})
+	}
+
+	// 2. Dapatkan ID admin dari JWT (untuk mencegah anonimisasi diri sendiri)
+	adminUserId, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to extract admin user ID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify requesting admin",
+		})
+	}
+	if targetUserId == adminUserId {
+		reqLogger(c).Warn().Int("admin_id", adminUserId).Msg("Admin attempted to anonymize themselves")
+		return c.Status(fiber.StatusForbidden).JSON(models.Response{
+			Success: false, Message: "Admin cannot anonymize their own account",
+		})
+	}
+
+	// 3. Panggil repository (irreversible)
+	err = h.UserRepo.AnonymizeUser(c.UserContext(), targetUserId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			reqLogger(c).Warn().Int("target_user_id", targetUserId).Msg("Attempted to anonymize non-existent user")
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("User with ID %d not found", targetUserId),
+			})
+		}
+		if errors.Is(err, repository.ErrUserAlreadyAnonymized) {
+			return c.Status(fiber.StatusConflict).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("User with ID %d is already anonymized", targetUserId),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to anonymize user")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to anonymize user",
+		})
+	}
+
+	// 4. Kirim response sukses
+	h.invalidateSessions(targetUserId)
+	reqLogger(c).Info().Int("admin_id", adminUserId).Int("anonymized_user_id", targetUserId).Msg("Admin successfully anonymized user")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: fmt.Sprintf("User with ID %d anonymized successfully", targetUserId),
+	})
+}
+
+// RevokeUserSessions godoc
+// @Summary Revoke all sessions of a user
+// @Description Signs a (possibly compromised) account out everywhere by bumping its token version: every JWT issued before, including scoped and kiosk tokens, is rejected with 401 on its next request. The user can log in again with their password. The action is written to the audit log with the optional reason; with notify_user the user is also told by email and in-app notification.
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param revoke body models.AdminRevokeSessionsInput false "Optional reason and user notification"
+// @Success 200 {object} models.Response{data=map[string]int} "Sessions revoked, returns the new token_version"
+// @Failure 400 {object} models.Response "Invalid User ID or validation failed"
+// @Failure 404 {object} models.Response "User not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId}/revoke-sessions [post]
+func (h *AdminHandler) RevokeUserSessions(c *fiber.Ctx) error {
+	targetUserId, err := idParam(c, "userId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid User ID parameter"})
+	}
+	input := new(models.AdminRevokeSessionsInput)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+		}
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	// Pencabutan & event (audit + notifikasi via outbox) dalam satu transaksi
+	var version int
+	err = runInTx(c, h.Tx, func() error {
+		var err error
+		if version, err = h.UserRepo.ForceRevokeSessions(c.UserContext(), targetUserId); err != nil {
+			return err
+		}
+		details := clientDetails(c)
+		details["notify_user"] = input.NotifyUser
+		if input.Reason != "" {
+			details["reason"] = input.Reason
+		}
+		publishEvent(c, h.Events, events.Event{Name: events.SessionsRevoked, UserID: targetUserId, Data: details})
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("User with ID %d not found", targetUserId),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to revoke user sessions")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to revoke sessions",
+		})
+	}
+	h.invalidateSessions(targetUserId)
+
+	reqLogger(c).Info().Int("target_user_id", targetUserId).Int("token_version", version).Bool("notify_user", input.NotifyUser).Msg("Admin revoked all user sessions")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "All sessions of the user have been revoked",
+		Data: fiber.Map{"user_id": targetUserId, "token_version": version},
+	})
+}
+
+// UpdateUserEmployment godoc
+// @Summary Set user employment data
+// @Description Sets hire date, employment status and probation end date. Status probation requires probation_end; HR is notified by the probation review job as the end date approaches. Changing probation_end re-arms the notification.
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param employment body models.UpdateEmploymentInput true "Employment data"
+// @Success 200 {object} models.Response{data=models.User} "User employment updated successfully"
+// @Failure 400 {object} models.Response "Validation failed or inconsistent dates"
+// @Failure 404 {object} models.Response "User not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId}/employment [put]
+func (h *AdminHandler) UpdateUserEmployment(c *fiber.Ctx) error {
+	targetUserIdStr := c.Params("userId")
+	targetUserId, err := strconv.Atoi(targetUserIdStr)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for employment update")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid User ID parameter",
+		})
+	}
+
+	input := new(models.UpdateEmploymentInput)
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error parsing employment request body")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Failed to parse request body",
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Employment validation failed")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	switch {
+	case input.EmploymentStatus == models.EmploymentStatusProbation && input.ProbationEnd == nil:
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "probation_end is required for employment_status probation",
+		})
+	case input.HireDate != nil && input.ProbationEnd != nil && *input.ProbationEnd < *input.HireDate:
+		// Format YYYY-MM-DD sudah divalidasi, sehingga perbandingan string setara perbandingan tanggal.
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "probation_end must not be before hire_date",
+		})
+	}
 
-	user, err := h.UserRepo.GetUserByID(context.Background(), userId)
-	if err != nil {
-		// --- CEK NOT FOUND ---
+	if err := h.UserRepo.UpdateUserEmployment(c.UserContext(), targetUserId, input); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Warn().Int("requested_user_id", userId).Msg("Admin requested non-existent user")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
-				Success: false, Message: fmt.Sprintf("User with ID %d not found", userId),
+				Success: false, Message: fmt.Sprintf("User with ID %d not found", targetUserId),
 			})
 		}
-		zlog.Error().Err(err).Int("user_id", userId).Msg("Failed to get user from repository")
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to update user employment")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
-			Success: false, Message: "Failed to retrieve user",
+			Success: false, Message: "Failed to update user employment",
 		})
 	}
-	// Logging sukses
-	zlog.Info().Int("user_id", userId).Int("admin_id", adminUserId).Msg("Successfully retrieved user for admin request")
-	// Logging sukses
+
+	user, err := h.UserRepo.GetUserByID(c.UserContext(), targetUserId)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to reload user after employment update")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "User employment updated, but failed to reload user",
+		})
+	}
+	reqLogger(c).Info().Int("target_user_id", targetUserId).Str("employment_status", input.EmploymentStatus).Msg("Admin updated user employment")
+	publishEvent(c, h.Events, events.Event{Name: events.EmploymentUpdated, UserID: targetUserId, Data: map[string]any{"employment_status": input.EmploymentStatus}})
 	return c.Status(http.StatusOK).JSON(models.Response{
-		Success: true, Message: "User retrieved successfully", Data: user,
+		Success: true, Message: "User employment updated successfully", Data: user,
 	})
 }
 
-// UpdateUser godoc
-// @Summary Update user
-// @Description Updates an existing user by its ID.
+// UpdateUserAccess godoc
+// @Summary Set user type and access period
+// @Description Marks a user as a regular employee or as a time-limited contractor/visitor. Contractors require valid_until (last day of access, inclusive, in the system default timezone); they cannot log in outside [valid_from, valid_until] and are deactivated automatically after valid_until. Extending valid_until reactivates the user.
 // @Tags Admin - Users Management
 // @Accept json
 // @Produce json
 // @Param userId path int true "User ID"
-// @Param update_user body models.AdminUpdateUserInput true "User details"
-// @Success 200 {object} models.Response "User updated successfully"
-// @Failure 400 {object} models.Response "Validation failed or invalid request body"
+// @Param access body models.UpdateUserAccessInput true "User type and access period"
+// @Success 200 {object} models.Response{data=models.User} "User access updated successfully"
+// @Failure 400 {object} models.Response "Validation failed or invalid access period"
 // @Failure 404 {object} models.Response "User not found"
-// @Failure 500 {object} models.Response "Internal server error during user update"
+// @Failure 500 {object} models.Response "Internal server error"
 // @Security ApiKeyAuth
-// @Router /admin/users/{userId} [patch]
-func (h *AdminHandler) UpdateUser(c *fiber.Ctx) error {
-	// 1. Dapatkan ID user target dari URL
+// @Router /admin/users/{userId}/access [put]
+func (h *AdminHandler) UpdateUserAccess(c *fiber.Ctx) error {
 	targetUserIdStr := c.Params("userId")
 	targetUserId, err := strconv.Atoi(targetUserIdStr)
 	if err != nil {
-		zlog.Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for update")
+		reqLogger(c).Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for access update")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid User ID parameter",
 		})
 	}
 
-	// 2. Dapatkan ID admin yang sedang login (opsional, tapi bisa berguna untuk log)
-	adminUserId, _ := utils.ExtractUserIDFromJWT(c) // Abaikan error sementara jika hanya untuk log
-
-	// 3. Parse & Validasi Input Body (Gunakan struct input baru)
-	input := new(models.AdminUpdateUserInput) // <-- Gunakan input model baru
+	input := new(models.UpdateUserAccessInput)
 	if err := c.BodyParser(input); err != nil {
-		zlog.Error().Err(err).Msg("Error parsing update user request body")
+		reqLogger(c).Error().Err(err).Msg("Error parsing user access request body")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Failed to parse request body",
 		})
 	}
-
-	// 4. Validasi data input menggunakan validator
 	if err := h.Validate.Struct(input); err != nil {
-		zlog.Warn().Err(err).Msg("Update user validation failed")
-		// Berikan detail error validasi jika perlu (hati-hati info sensitif)
+		reqLogger(c).Warn().Err(err).Msg("User access validation failed")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Validation failed", Data: err.Error(),
 		})
 	}
-
-	// 5. (Opsional tapi direkomendasikan) Validasi Role ID
-	_, errRole := h.RoleRepo.GetRoleByID(context.Background(), input.RoleID)
-	if errRole != nil {
-		// Handle jika role ID tidak valid
-		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Role ID"})
+	switch {
+	case input.UserType == models.UserTypeContractor && input.ValidUntil == nil:
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "valid_until is required for contractors",
+		})
+	case input.UserType == models.UserTypeEmployee && (input.ValidFrom != nil || input.ValidUntil != nil):
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Employees do not have an access period; omit valid_from and valid_until",
+		})
+	case input.ValidFrom != nil && input.ValidUntil != nil && *input.ValidFrom > *input.ValidUntil:
+		// Format YYYY-MM-DD sudah divalidasi, sehingga perbandingan string setara perbandingan tanggal.
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "valid_from must not be after valid_until",
+		})
 	}
 
-	// 6. Panggil repository untuk update user
-	err = h.UserRepo.UpdateUserByID(context.Background(), targetUserId, input) // <-- Pass input model baru
+	today := time.Now().In(h.Settings.DefaultLocation(c.UserContext()))
+	var user *models.User
+	err = runInTx(c, h.Tx, func() error {
+		if err := h.UserRepo.UpdateUserAccess(c.UserContext(), targetUserId, input, today); err != nil {
+			return err
+		}
+		var err error
+		if user, err = h.UserRepo.GetUserByID(c.UserContext(), targetUserId); err != nil {
+			return fmt.Errorf("error reloading user after access update: %w", err)
+		}
+		publishEvent(c, h.Events, events.Event{Name: events.AccessUpdated, UserID: targetUserId, Data: map[string]any{"user_type": input.UserType, "is_active": user.IsActive}})
+		if !user.IsActive {
+			publishEvent(c, h.Events, events.Event{Name: events.UserDeactivated, UserID: targetUserId, Data: map[string]any{"reason": "access_updated"}})
+		}
+		return nil
+	})
 	if err != nil {
-		// Cek apakah error karena user tidak ditemukan
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Warn().Int("target_user_id", targetUserId).Msg("Attempted to update non-existent user")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: fmt.Sprintf("User with ID %d not found", targetUserId),
 			})
 		}
-		// Cek apakah error karena unique constraint
-		if strings.Contains(err.Error(), "already exists") {
-			zlog.Warn().Err(err).Int("target_user_id", targetUserId).Msg("Unique constraint violation during user update by admin")
-			return c.Status(fiber.StatusConflict).JSON(models.Response{ // 409 Conflict
-				Success: false, Message: err.Error(),
-			})
-		}
-
-		// Error lain saat update
-		zlog.Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to update user by admin")
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to update user access")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
-			Success: false, Message: "Failed to update user",
+			Success: false, Message: "Failed to update user access",
 		})
 	}
 
-	// 7. Kirim response sukses
-	zlog.Info().Int("admin_id", adminUserId).Int("updated_user_id", targetUserId).Msg("Admin successfully updated user")
-	// Pertimbangkan untuk mengembalikan data user yang sudah diupdate (ambil lagi dari DB)
-	// atau cukup pesan sukses
+	h.invalidateSessions(targetUserId)
+	reqLogger(c).Info().Int("target_user_id", targetUserId).Str("user_type", input.UserType).Bool("is_active", user.IsActive).Msg("Admin updated user access")
 	return c.Status(http.StatusOK).JSON(models.Response{
-		Success: true, Message: fmt.Sprintf("User with ID %d updated successfully", targetUserId),
+		Success: true, Message: "User access updated successfully", Data: user,
 	})
 }
 
-// DeleteUser godoc
-// @Summary Delete User (Admin)
-// @Description Deletes a specific user by ID. Requires Admin role. Admin cannot delete themselves.
+// UpdateUserWorkModes godoc
+// @Summary Set a user's remote/field work policy
+// @Description Sets on which days of the week (sun..sat, comma-separated; empty = never) the user may check in with mode remote, and whether mode field is allowed. Onsite check-ins are always allowed. Omitted fields are unchanged.
 // @Tags Admin - Users Management
 // @Accept json
 // @Produce json
-// @Param userId path int true "User ID to delete"
-// @Success 200 {object} models.Response "User deleted successfully"
-// @Failure 400 {object} models.Response "Invalid User ID parameter"
-// @Failure 401 {object} models.Response "Unauthorized"
-// @Failure 403 {object} models.Response "Forbidden (Not Admin or attempting self-delete)"
+// @Param userId path int true "User ID"
+// @Param policy body models.UpdateWorkModesInput true "Remote days and field work permission"
+// @Success 200 {object} models.Response{data=models.User} "User work modes updated successfully"
+// @Failure 400 {object} models.Response "Validation failed or invalid day"
 // @Failure 404 {object} models.Response "User not found"
 // @Failure 500 {object} models.Response "Internal server error"
 // @Security ApiKeyAuth
-// @Router /admin/users/{userId} [delete]
-func (h *AdminHandler) DeleteUser(c *fiber.Ctx) error {
-	// 1. Dapatkan ID user yang akan dihapus dari parameter URL
-	targetUserIdStr := c.Params("userId") // Sesuaikan nama param dengan route nanti
-	targetUserId, err := strconv.Atoi(targetUserIdStr)
+// @Router /admin/users/{userId}/work-modes [put]
+func (h *AdminHandler) UpdateUserWorkModes(c *fiber.Ctx) error {
+	targetUserId, err := idParam(c, "userId")
 	if err != nil {
-		zlog.Warn().Err(err).Str("param", targetUserIdStr).Msg("Invalid User ID parameter for deletion")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid User ID parameter"})
+	}
+	input := new(models.UpdateWorkModesInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if input.RemoteDays == nil && input.FieldWorkAllowed == nil {
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
-			Success: false, Message: "Invalid User ID parameter",
+			Success: false, Message: "At least one of remote_days or field_work_allowed must be provided",
 		})
 	}
+	if input.RemoteDays != nil {
+		// Format sama dengan pengaturan hari (mis. calendar.working_days): huruf kecil, terurut.
+		days, err := settings.Definition{Type: settings.TypeWeekdays}.Normalize(*input.RemoteDays)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "Validation failed", Data: "remote_days: " + err.Error(),
+			})
+		}
+		input.RemoteDays = &days
+	}
 
-	// 2. Dapatkan ID admin yang sedang login dari JWT (PENTING: untuk mencegah hapus diri sendiri)
-	adminUserId, err := utils.ExtractUserIDFromJWT(c)
+	var user *models.User
+	err = runInTx(c, h.Tx, func() error {
+		if err := h.UserRepo.UpdateWorkModes(c.UserContext(), targetUserId, input.RemoteDays, input.FieldWorkAllowed); err != nil {
+			return err
+		}
+		var err error
+		if user, err = h.UserRepo.GetUserByID(c.UserContext(), targetUserId); err != nil {
+			return fmt.Errorf("error reloading user after work modes update: %w", err)
+		}
+		publishEvent(c, h.Events, events.Event{
+			Name: events.WorkModesUpdated, UserID: targetUserId,
+			Data: map[string]any{"remote_days": user.RemoteDays, "field_work_allowed": user.FieldWorkAllowed},
+		})
+		return nil
+	})
 	if err != nil {
-		zlog.Error().Err(err).Msg("Failed to extract admin user ID from JWT")
-		// Ini seharusnya tidak terjadi jika middleware auth bekerja, tapi handle untuk keamanan
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("User with ID %d not found", targetUserId),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to update user work modes")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
-			Success: false, Message: "Failed to identify requesting admin",
+			Success: false, Message: "Failed to update user work modes",
 		})
 	}
+	reqLogger(c).Info().Int("target_user_id", targetUserId).Str("remote_days", user.RemoteDays).Bool("field_work_allowed", user.FieldWorkAllowed).Msg("Admin updated user work modes")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "User work modes updated successfully", Data: user,
+	})
+}
+
+// Batas jumlah entri feed aktivitas per request.
+const (
+	defaultActivityLimit = 50
+	maxActivityLimit     = 200
+)
+
+// GetUserActivity godoc
+// @Summary Get user activity feed
+// @Description Retrieves a single chronological feed (newest first) of a user's attendance events, schedule changes, profile/account edits and login events, backed by the audit log and the attendance ledger. Pass next_cursor from the response as the cursor parameter to load older entries; next_cursor is null on the last page.
+// @Tags Admin - Users Management
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param cursor query string false "Opaque cursor from a previous response (next_cursor)"
+// @Param limit query int false "Maximum number of entries" default(50) maximum(200)
+// @Success 200 {object} models.Response{data=map[string]interface{}} "Activity items and next_cursor"
+// @Failure 400 {object} models.Response "Invalid user ID or query parameters"
+// @Failure 404 {object} models.Response "User not found"
+// @Failure 500 {object} models.Response "Internal server error during activity retrieval"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId}/activity [get]
+func (h *AdminHandler) GetUserActivity(c *fiber.Ctx) error {
+	targetUserId, err := idParam(c, "userId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid User ID parameter"})
+	}
 
-	// 3. Validasi: Admin tidak boleh menghapus dirinya sendiri
-	if targetUserId == adminUserId {
-		zlog.Warn().Int("admin_id", adminUserId).Msg("Admin attempted to delete themselves")
-		return c.Status(fiber.StatusForbidden).JSON(models.Response{
-			Success: false, Message: "Admin cannot delete their own account",
-		})
+	var after *models.ActivityCursor
+	if raw := c.Query("cursor"); raw != "" {
+		if after, err = decodeActivityCursor(raw); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid cursor parameter"})
+		}
+	}
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(defaultActivityLimit)))
+	if err != nil || limit < 1 {
+		limit = defaultActivityLimit
 	}
+	limit = min(limit, maxActivityLimit)
 
-	// 4. Panggil repository untuk menghapus user
-	err = h.UserRepo.DeleteUserByID(context.Background(), targetUserId)
-	if err != nil {
-		// Cek apakah error karena user tidak ditemukan
+	if _, err := h.UserRepo.GetUserByID(c.UserContext(), targetUserId); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Warn().Int("target_user_id", targetUserId).Msg("Attempted to delete non-existent user")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: fmt.Sprintf("User with ID %d not found", targetUserId),
 			})
 		}
-		// Error lain saat menghapus
-		zlog.Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to delete user")
-		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
-			Success: false, Message: "Failed to delete user",
-		})
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to get user for activity feed")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve user activity"})
 	}
 
-	// 5. Kirim response sukses
-	zlog.Info().Int("admin_id", adminUserId).Int("deleted_user_id", targetUserId).Msg("Admin successfully deleted user")
+	items, err := h.AuditRepo.GetUserActivity(c.UserContext(), targetUserId, after, limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("target_user_id", targetUserId).Msg("Failed to get user activity")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve user activity"})
+	}
+
+	// Kursor halaman berikutnya hanya jika halaman ini penuh.
+	var nextCursor *string
+	if len(items) == limit {
+		last := items[len(items)-1]
+		cursor := encodeActivityCursor(models.ActivityCursor{OccurredAt: last.OccurredAt, Source: last.Source, SourceID: last.SourceID})
+		nextCursor = &cursor
+	}
 	return c.Status(http.StatusOK).JSON(models.Response{
-		Success: true, Message: fmt.Sprintf("User with ID %d deleted successfully", targetUserId),
+		Success: true, Message: "Activity retrieved successfully",
+		Data: fiber.Map{"items": items, "next_cursor": nextCursor},
 	})
 }
 
@@ -1186,27 +3072,33 @@ func (h *AdminHandler) CreateRole(c *fiber.Ctx) error {
 
 	// Validasi input Name (gunakan tag validate di models.Role)
 	if err := h.Validate.Struct(input); err != nil {
-		zlog.Warn().Err(err).Msg("Create role validation failed")
+		reqLogger(c).Warn().Err(err).Msg("Create role validation failed")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Validation failed: role name is required", Data: err.Error(),
 		})
 	}
 
-	roleID, err := h.RoleRepo.CreateRole(context.Background(), input)
+	roleID, err := h.RoleRepo.CreateRole(c.UserContext(), input)
 	if err != nil {
+		if errors.Is(err, repository.ErrParentRoleNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Parent role with ID %d not found", *input.ParentID),
+			})
+		}
 		// Handle error nama sudah ada
 		if strings.Contains(err.Error(), "already exists") {
-			zlog.Warn().Err(err).Str("role_name", input.Name).Msg("Attempted to create duplicate role name")
+			reqLogger(c).Warn().Err(err).Str("role_name", input.Name).Msg("Attempted to create duplicate role name")
 			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
 		}
 		// Error lain
-		zlog.Error().Err(err).Str("role_name", input.Name).Msg("Failed to create role")
+		reqLogger(c).Error().Err(err).Str("role_name", input.Name).Msg("Failed to create role")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to create role",
 		})
 	}
 
-	zlog.Info().Int("role_id", roleID).Str("role_name", input.Name).Msg("Role created successfully")
+	h.RoleHierarchy.Invalidate()
+	reqLogger(c).Info().Int("role_id", roleID).Str("role_name", input.Name).Msg("Role created successfully")
 	return c.Status(fiber.StatusCreated).JSON(models.Response{
 		Success: true, Message: "Role created successfully", Data: fiber.Map{"role_id": roleID},
 	})
@@ -1214,24 +3106,30 @@ func (h *AdminHandler) CreateRole(c *fiber.Ctx) error {
 
 // GetAllRoles godoc
 // @Summary Get all roles
-// @Description Retrieves all available roles and their respective IDs.
+// @Description Retrieves all available roles and their respective IDs. Supports If-None-Match: 304 Not Modified while no role was added, renamed, re-parented or deleted.
 // @Tags Admin - Roles Management
 // @Accept json
 // @Produce json
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} models.Response{data=[]models.Role} "Roles retrieved successfully"
+// @Success 304 "Roles not modified"
 // @Failure 500 {object} models.Response "Internal server error during role retrieval"
 // @Security ApiKeyAuth
 // @Router /admin/roles [get]
 func (h *AdminHandler) GetAllRoles(c *fiber.Ctx) error {
-	roles, err := h.RoleRepo.GetAllRoles(context.Background())
+	roles, err := h.RoleRepo.GetAllRoles(c.UserContext())
 	if err != nil {
-		zlog.Error().Err(err).Msg("Failed to get all roles from repository")
+		reqLogger(c).Error().Err(err).Msg("Failed to get all roles from repository")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to retrieve roles",
 		})
 	}
 
-	zlog.Info().Int("role_count", len(roles)).Msg("Successfully retrieved all roles")
+	if notModified(c, rolesETag(roles), time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	reqLogger(c).Info().Int("role_count", len(roles)).Msg("Successfully retrieved all roles")
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Roles retrieved successfully", Data: roles,
 	})
@@ -1254,27 +3152,27 @@ func (h *AdminHandler) GetRoleByID(c *fiber.Ctx) error {
 	roleIDStr := c.Params("roleId")
 	roleID, err := strconv.Atoi(roleIDStr)
 	if err != nil {
-		zlog.Warn().Err(err).Str("param", roleIDStr).Msg("Invalid Role ID parameter")
+		reqLogger(c).Warn().Err(err).Str("param", roleIDStr).Msg("Invalid Role ID parameter")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid Role ID parameter",
 		})
 	}
 
-	role, err := h.RoleRepo.GetRoleByID(context.Background(), roleID)
+	role, err := h.RoleRepo.GetRoleByID(c.UserContext(), roleID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Warn().Int("role_id", roleID).Msg("Role not found")
+			reqLogger(c).Warn().Int("role_id", roleID).Msg("Role not found")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: fmt.Sprintf("Role with ID %d not found", roleID),
 			})
 		}
-		zlog.Error().Err(err).Int("role_id", roleID).Msg("Failed to get role by ID")
+		reqLogger(c).Error().Err(err).Int("role_id", roleID).Msg("Failed to get role by ID")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to retrieve role",
 		})
 	}
 
-	zlog.Info().Int("role_id", roleID).Msg("Role retrieved successfully")
+	reqLogger(c).Info().Int("role_id", roleID).Msg("Role retrieved successfully")
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Role retrieved successfully", Data: role,
 	})
@@ -1310,7 +3208,7 @@ func (h *AdminHandler) UpdateRole(c *fiber.Ctx) error {
 
 	// Validasi input Name
 	if err := h.Validate.Struct(input); err != nil {
-		zlog.Warn().Err(err).Int("role_id", roleID).Msg("Update role validation failed")
+		reqLogger(c).Warn().Err(err).Int("role_id", roleID).Msg("Update role validation failed")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Validation failed: role name is required", Data: err.Error(),
 		})
@@ -1318,25 +3216,29 @@ func (h *AdminHandler) UpdateRole(c *fiber.Ctx) error {
 
 	// Set ID dari URL dan panggil repo
 	input.ID = roleID
-	err = h.RoleRepo.UpdateRole(context.Background(), input)
+	err = h.RoleRepo.UpdateRole(c.UserContext(), input)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Warn().Int("role_id", roleID).Msg("Attempted to update non-existent role")
+			reqLogger(c).Warn().Int("role_id", roleID).Msg("Attempted to update non-existent role")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: fmt.Sprintf("Role with ID %d not found", roleID),
 			})
 		}
 		if strings.Contains(err.Error(), "already exists") {
-			zlog.Warn().Err(err).Int("role_id", roleID).Str("role_name", input.Name).Msg("Role name conflict during update")
+			reqLogger(c).Warn().Err(err).Int("role_id", roleID).Str("role_name", input.Name).Msg("Role name conflict during update")
 			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
 		}
-		zlog.Error().Err(err).Int("role_id", roleID).Msg("Failed to update role")
+		reqLogger(c).Error().Err(err).Int("role_id", roleID).Msg("Failed to update role")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to update role",
 		})
 	}
 
-	zlog.Info().Int("role_id", roleID).Str("new_name", input.Name).Msg("Role updated successfully")
+	h.RoleHierarchy.Invalidate() // Nama role dipakai pada rantai pewarisan
+	if h.Sessions != nil {
+		h.Sessions.InvalidateAll() // Token anggota role dicabut (klaim role berisi nama lama)
+	}
+	reqLogger(c).Info().Int("role_id", roleID).Str("new_name", input.Name).Msg("Role updated successfully")
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Role updated successfully",
 	})
@@ -1366,33 +3268,282 @@ func (h *AdminHandler) DeleteRole(c *fiber.Ctx) error {
 
 	// Hindari menghapus role dasar (opsional tapi aman)
 	if roleID == 1 || roleID == 2 { // Asumsi ID 1=Admin, 2=Employee
-		zlog.Warn().Int("role_id", roleID).Msg("Attempted to delete base role")
+		reqLogger(c).Warn().Int("role_id", roleID).Msg("Attempted to delete base role")
 		return c.Status(fiber.StatusForbidden).JSON(models.Response{
 			Success: false, Message: "Cannot delete base roles (Admin/Employee)",
 		})
 	}
 
-	err = h.RoleRepo.DeleteRole(context.Background(), roleID)
+	err = h.RoleRepo.DeleteRole(c.UserContext(), roleID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Warn().Int("role_id", roleID).Msg("Attempted to delete non-existent role")
+			reqLogger(c).Warn().Int("role_id", roleID).Msg("Attempted to delete non-existent role")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: fmt.Sprintf("Role with ID %d not found", roleID),
 			})
 		}
-		// Handle error jika role masih digunakan
-		if strings.Contains(err.Error(), "still assigned to this role") {
-			zlog.Warn().Err(err).Int("role_id", roleID).Msg("Attempted to delete role still in use")
+		// Handle error jika role masih digunakan user atau diwarisi role lain
+		if strings.Contains(err.Error(), "still assigned to this role") || strings.Contains(err.Error(), "still inherit from this role") {
+			reqLogger(c).Warn().Err(err).Int("role_id", roleID).Msg("Attempted to delete role still in use")
 			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
 		}
-		zlog.Error().Err(err).Int("role_id", roleID).Msg("Failed to delete role")
+		reqLogger(c).Error().Err(err).Int("role_id", roleID).Msg("Failed to delete role")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to delete role",
 		})
 	}
 
-	zlog.Info().Int("role_id", roleID).Msg("Role deleted successfully")
+	h.RoleHierarchy.Invalidate()
+	reqLogger(c).Info().Int("role_id", roleID).Msg("Role deleted successfully")
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Role deleted successfully",
 	})
 }
+
+// SetRoleParent godoc
+// @Summary Set the role a role inherits from
+// @Description Makes the role inherit the access of parent_id, transitively (e.g. Admin -> Manager -> Supervisor lets Admin pass checks for Manager and Supervisor). parent_id null removes the inheritance. Changes that would create a cycle are rejected. Other API instances apply the change within ROLE_HIERARCHY_CACHE_TTL.
+// @Tags Admin - Roles Management
+// @Accept json
+// @Produce json
+// @Param roleId path int true "Role ID"
+// @Param parent body models.RoleParentInput true "Parent role ID (null to remove)"
+// @Success 200 {object} models.Response{data=models.Role} "Role parent updated successfully"
+// @Failure 400 {object} models.Response "Invalid role ID or validation failed"
+// @Failure 404 {object} models.Response "Role or parent role not found"
+// @Failure 409 {object} models.Response "Inheritance cycle"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/roles/{roleId}/parent [put]
+func (h *AdminHandler) SetRoleParent(c *fiber.Ctx) error {
+	roleID, err := strconv.Atoi(c.Params("roleId"))
+	if err != nil || roleID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Role ID parameter"})
+	}
+	input := new(models.RoleParentInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	role, err := h.RoleRepo.SetRoleParent(c.UserContext(), roleID, input.ParentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Role with ID %d not found", roleID),
+			})
+		case errors.Is(err, repository.ErrParentRoleNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Parent role with ID %d not found", *input.ParentID),
+			})
+		case errors.Is(err, repository.ErrRoleCycle):
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+		}
+		reqLogger(c).Error().Err(err).Int("role_id", roleID).Msg("Failed to set role parent")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to update role parent"})
+	}
+	h.RoleHierarchy.Invalidate()
+
+	reqLogger(c).Info().Int("role_id", roleID).Interface("parent_id", input.ParentID).Msg("Role parent updated")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Role parent updated successfully", Data: role,
+	})
+}
+
+// GetEffectiveRole godoc
+// @Summary Get a role's effective roles
+// @Description Returns the role with the chain of roles it inherits from (nearest first) and the effective role list used for access checks.
+// @Tags Admin - Roles Management
+// @Produce json
+// @Param roleId path int true "Role ID"
+// @Success 200 {object} models.Response{data=models.EffectiveRole} "Effective roles retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid role ID"
+// @Failure 404 {object} models.Response "Role not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/roles/{roleId}/effective [get]
+func (h *AdminHandler) GetEffectiveRole(c *fiber.Ctx) error {
+	roleID, err := strconv.Atoi(c.Params("roleId"))
+	if err != nil || roleID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Role ID parameter"})
+	}
+	role, err := h.RoleRepo.GetRoleByID(c.UserContext(), roleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Role with ID %d not found", roleID),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("role_id", roleID).Msg("Failed to get role by ID")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve effective roles"})
+	}
+	// Dibaca langsung dari database (bukan cache) agar admin melihat hierarki terbaru.
+	inherited, err := h.RoleRepo.GetInheritedRoles(c.UserContext(), role.Name)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		reqLogger(c).Error().Err(err).Int("role_id", roleID).Msg("Failed to get inherited roles")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve effective roles"})
+	}
+	if inherited == nil {
+		inherited = []string{}
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Effective roles retrieved successfully",
+		Data: models.EffectiveRole{Role: *role, Inherited: inherited, Effective: append([]string{role.Name}, inherited...)},
+	})
+}
+
+// GetMetrics godoc
+// @Summary Get application metrics
+// @Description Retrieves runtime counters such as database query totals, query errors, and slow queries (see DB_SLOW_QUERY_THRESHOLD), plus the state and counters of each outbound integration circuit breaker (SMTP, webhooks, push, GeoIP), and the build info (version, commit, build time, Go version) of the instance that answered.
+// @Tags Admin - Monitoring
+// @Produce json
+// @Success 200 {object} models.Response{data=map[string]object} "Metrics retrieved successfully"
+// @Security ApiKeyAuth
+// @Router /admin/metrics [get]
+func (h *AdminHandler) GetMetrics(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Metrics retrieved successfully", Data: metrics.Snapshot(),
+	})
+}
+
+// -------------------------------------------------------------------------
+// System Settings
+// -------------------------------------------------------------------------
+// GetSettings godoc
+// @Summary Get system settings
+// @Description Retrieves all runtime-tunable settings with their effective value, default, and last change.
+// @Tags Admin - Settings
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.SettingDetail} "Settings retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error during settings retrieval"
+// @Security ApiKeyAuth
+// @Router /admin/settings [get]
+func (h *AdminHandler) GetSettings(c *fiber.Ctx) error {
+	details, err := h.Settings.All(c.UserContext())
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get settings")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve settings",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Settings retrieved successfully", Data: details,
+	})
+}
+
+// UpdateSettings godoc
+// @Summary Update system settings
+// @Description Updates one or more settings atomically. Unknown keys or invalid values reject the whole request. Changes apply without restart.
+// @Tags Admin - Settings
+// @Accept json
+// @Produce json
+// @Param settings body models.UpdateSettingsInput true "Setting keys and new values"
+// @Success 200 {object} models.Response{data=[]models.SettingDetail} "Settings updated successfully"
+// @Failure 400 {object} models.Response "Invalid request body, unknown key, or invalid value"
+// @Failure 500 {object} models.Response "Internal server error during settings update"
+// @Security ApiKeyAuth
+// @Router /admin/settings [put]
+func (h *AdminHandler) UpdateSettings(c *fiber.Ctx) error {
+	input := new(models.UpdateSettingsInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid request body", Data: err.Error(),
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed: settings must contain at least one key", Data: err.Error(),
+		})
+	}
+
+	adminUserID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting admin userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+
+	if err := h.Settings.Update(c.UserContext(), input.Settings, adminUserID); err != nil {
+		var validationErr *settings.ValidationError
+		if errors.As(err, &validationErr) {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "Invalid settings", Data: validationErr.Fields,
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("admin_id", adminUserID).Msg("Failed to update settings")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to update settings",
+		})
+	}
+
+	keys := make([]string, 0, len(input.Settings))
+	for key := range input.Settings {
+		keys = append(keys, key)
+	}
+	reqLogger(c).Info().Int("admin_id", adminUserID).Strs("keys", keys).Msg("Settings updated")
+
+	details, err := h.Settings.All(c.UserContext())
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to reload settings after update")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Settings updated but could not be reloaded",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Settings updated successfully", Data: details,
+	})
+}
+
+// maxCalendarDays membatasi rentang GET /admin/calendar.
+const maxCalendarDays = 366
+
+// GetWorkingCalendar godoc
+// @Summary Get the working calendar
+// @Description Lists every date in a range with its type from the organization working calendar (settings calendar.working_days, calendar.half_days and the payroll.holidays calendar): working, half_day, off, or holiday, plus the total number of working days (half days count 0.5). Staffing suggestions use the same calendar.
+// @Tags Admin - Settings
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to the start of the month"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} models.Response{data=models.WorkingCalendarView} "Working calendar retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid date range or range longer than 366 days"
+// @Security ApiKeyAuth
+// @Router /admin/calendar [get]
+func (h *AdminHandler) GetWorkingCalendar(c *fiber.Ctx) error {
+	startDate, endDate, dateErr := parseAdminDateQueryParams(c)
+	if dateErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: dateErr.Error()})
+	}
+	if endDate.Sub(startDate) > maxCalendarDays*24*time.Hour {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Date range must not exceed %d days", maxCalendarDays),
+		})
+	}
+	calendar := h.Settings.WorkingCalendar(c.UserContext())
+	view := models.WorkingCalendarView{
+		StartDate: startDate.Format(defaultDateFormat), EndDate: endDate.Format(defaultDateFormat),
+		WorkingDays: []string{}, HalfDays: []string{},
+		Days: calendar.Days(startDate, endDate),
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String()[:3])
+		if calendar.WorkingDays[d] {
+			view.WorkingDays = append(view.WorkingDays, name)
+		}
+		if calendar.HalfDays[d] {
+			view.HalfDays = append(view.HalfDays, name)
+		}
+	}
+	for _, day := range view.Days {
+		view.TotalDays += day.WorkFraction
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Working calendar retrieved successfully", Data: view,
+	})
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/mocks"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newAdminTestApp memasang route handler admin di app Fiber dengan claims user adminID,
+// menggantikan middleware Protected/Authorize.
+func newAdminTestApp(h *AdminHandler, adminID int) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &utils.JwtClaims{UserID: adminID, Role: "Admin"})
+		return c.Next()
+	})
+	app.Get("/attendance/queued-check-in-failures", h.GetQueuedCheckInFailures)
+	app.Post("/attendance/queued-check-in-failures/:failureId/resolve", h.ResolveQueuedCheckInFailure)
+	app.Put("/attendance/:attendanceId/face-review", h.ReviewFaceCheck)
+	return app
+}
+
+// doJSON mengirim request ke app dan mengembalikan status serta body yang sudah di-decode.
+func doJSON(t *testing.T, app *fiber.App, method, target, body string) (int, map[string]any) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var decoded map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return resp.StatusCode, decoded
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestGetQueuedCheckInFailures(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		wantResolved *bool
+		wantStatus   int
+	}{
+		{name: "default lists open entries", query: "", wantResolved: boolPtr(false), wantStatus: http.StatusOK},
+		{name: "resolved", query: "?status=resolved", wantResolved: boolPtr(true), wantStatus: http.StatusOK},
+		{name: "all", query: "?status=all&page=2&limit=5", wantResolved: nil, wantStatus: http.StatusOK},
+		{name: "invalid status", query: "?status=pending", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockAttendanceRepository(t)
+			if tt.wantStatus == http.StatusOK {
+				repo.EXPECT().GetQueuedCheckInFailures(mock.Anything, tt.wantResolved, mock.AnythingOfType("int"), mock.AnythingOfType("int")).
+					Return([]models.QueuedCheckInFailure{{ID: 3, UserID: 7, Source: models.QueuedCheckInSourceDegraded}}, 1, nil)
+			}
+			app := newAdminTestApp(&AdminHandler{AttendanceRepo: repo, Validate: validator.New()}, 1)
+
+			status, body := doJSON(t, app, http.MethodGet, "/attendance/queued-check-in-failures"+tt.query, "")
+			assert.Equal(t, tt.wantStatus, status)
+			if tt.wantStatus == http.StatusOK {
+				assert.Len(t, body["data"], 1)
+				assert.Equal(t, float64(1), body["meta"].(map[string]any)["total_items"])
+			}
+		})
+	}
+}
+
+func TestGetQueuedCheckInFailuresRepositoryError(t *testing.T) {
+	repo := mocks.NewMockAttendanceRepository(t)
+	repo.EXPECT().GetQueuedCheckInFailures(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, 0, errors.New("connection reset"))
+	app := newAdminTestApp(&AdminHandler{AttendanceRepo: repo, Validate: validator.New()}, 1)
+
+	status, body := doJSON(t, app, http.MethodGet, "/attendance/queued-check-in-failures", "")
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, false, body["success"])
+}
+
+func TestResolveQueuedCheckInFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		repoErr    error
+		callsRepo  bool
+		wantStatus int
+	}{
+		{name: "resolved", path: "/attendance/queued-check-in-failures/3/resolve", body: `{"note":"entered manually"}`, callsRepo: true, wantStatus: http.StatusOK},
+		{name: "missing or already resolved", path: "/attendance/queued-check-in-failures/3/resolve", body: `{"note":"entered manually"}`, repoErr: pgx.ErrNoRows, callsRepo: true, wantStatus: http.StatusNotFound},
+		{name: "repository error", path: "/attendance/queued-check-in-failures/3/resolve", body: `{"note":"entered manually"}`, repoErr: errors.New("boom"), callsRepo: true, wantStatus: http.StatusInternalServerError},
+		{name: "note required", path: "/attendance/queued-check-in-failures/3/resolve", body: `{"note":""}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid id", path: "/attendance/queued-check-in-failures/abc/resolve", body: `{"note":"x"}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockAttendanceRepository(t)
+			if tt.callsRepo {
+				var failure *models.QueuedCheckInFailure
+				if tt.repoErr == nil {
+					failure = &models.QueuedCheckInFailure{ID: 3, UserID: 7}
+				}
+				repo.EXPECT().ResolveQueuedCheckInFailure(mock.Anything, 3, 42, "entered manually").Return(failure, tt.repoErr)
+			}
+			app := newAdminTestApp(&AdminHandler{AttendanceRepo: repo, Validate: validator.New()}, 42)
+
+			status, _ := doJSON(t, app, http.MethodPost, tt.path, tt.body)
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func TestReviewFaceCheckNotPending(t *testing.T) {
+	repo := mocks.NewMockAttendanceRepository(t)
+	repo.EXPECT().ReviewFaceCheck(mock.Anything, 11, models.FaceReviewApproved, 42).Return(nil, pgx.ErrNoRows)
+	app := newAdminTestApp(&AdminHandler{AttendanceRepo: repo, Validate: validator.New()}, 42)
+
+	status, body := doJSON(t, app, http.MethodPut, "/attendance/11/face-review", `{"status":"approved"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, body["message"], "No pending face check")
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// AnnouncementHandler melayani pengumuman: CRUD oleh admin dan daftar pengumuman aktif untuk user.
+type AnnouncementHandler struct {
+	AnnouncementRepo repository.AnnouncementRepository
+	RoleRepo         repository.RoleRepository
+	Validate         *validator.Validate
+}
+
+func NewAnnouncementHandler(announcementRepo repository.AnnouncementRepository, roleRepo repository.RoleRepository) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		AnnouncementRepo: announcementRepo,
+		RoleRepo:         roleRepo,
+		Validate:         validator.New(),
+	}
+}
+
+// parseAnnouncementInput membaca & memvalidasi body, lalu membangun models.Announcement.
+// Error dikembalikan sebagai *fiber.Error berisi status & pesan untuk klien.
+func (h *AnnouncementHandler) parseAnnouncementInput(c *fiber.Ctx) (*models.Announcement, *fiber.Error) {
+	input := new(models.AnnouncementInput)
+	if err := c.BodyParser(input); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	announcement := &models.Announcement{
+		Title: input.Title, Body: input.Body, PublishAt: time.Now(),
+		ExpiresAt: input.ExpiresAt, AudienceRoleIDs: input.AudienceRoleIDs,
+	}
+	if input.PublishAt != nil {
+		announcement.PublishAt = *input.PublishAt
+	}
+	if announcement.ExpiresAt != nil && !announcement.ExpiresAt.After(announcement.PublishAt) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "expires_at must be after publish_at")
+	}
+	for _, roleID := range announcement.AudienceRoleIDs {
+		if _, err := h.RoleRepo.GetRoleByID(c.UserContext(), roleID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Role with ID %d not found", roleID))
+			}
+			reqLogger(c).Error().Err(err).Int("role_id", roleID).Msg("Error validating announcement audience role")
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to validate audience roles")
+		}
+	}
+	return announcement, nil
+}
+
+// invalidAnnouncement menulis response error dari parseAnnouncementInput.
+func invalidAnnouncement(c *fiber.Ctx, ferr *fiber.Error) error {
+	return c.Status(ferr.Code).JSON(models.Response{Success: false, Message: ferr.Message})
+}
+
+// announcementIDParam membaca path param :announcementId.
+func announcementIDParam(c *fiber.Ctx) (int, error) {
+	idStr := c.Params("announcementId")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		reqLogger(c).Warn().Str("announcementId_param", idStr).Msg("Invalid Announcement ID parameter")
+		return 0, fmt.Errorf("invalid announcement ID parameter %q", idStr)
+	}
+	return id, nil
+}
+
+// invalidAnnouncementID adalah response 400 untuk path param ID yang tidak valid.
+func invalidAnnouncementID(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+		Success: false, Message: "Invalid Announcement ID parameter", Data: err.Error(),
+	})
+}
+
+// CreateAnnouncement godoc
+// @Summary Create announcement
+// @Description Creates an announcement shown to employees within its publish window. An empty audience_role_ids targets all users.
+// @Tags Admin - Announcements
+// @Accept json
+// @Produce json
+// @Param announcement body models.AnnouncementInput true "Announcement details"
+// @Success 201 {object} models.Response{data=models.Announcement} "Announcement created successfully"
+// @Failure 400 {object} models.Response "Validation failed or unknown audience role"
+// @Failure 500 {object} models.Response "Internal server error during announcement creation"
+// @Security ApiKeyAuth
+// @Router /admin/announcements [post]
+func (h *AnnouncementHandler) CreateAnnouncement(c *fiber.Ctx) error {
+	announcement, ferr := h.parseAnnouncementInput(c)
+	if ferr != nil {
+		return invalidAnnouncement(c, ferr)
+	}
+
+	adminUserID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting admin userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+	announcement.CreatedBy = &adminUserID
+
+	id, err := h.AnnouncementRepo.CreateAnnouncement(c.UserContext(), announcement)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to create announcement")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to create announcement",
+		})
+	}
+	created, err := h.AnnouncementRepo.GetAnnouncementByID(c.UserContext(), id)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("announcement_id", id).Msg("Failed to reload created announcement")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Announcement created but could not be reloaded",
+		})
+	}
+
+	reqLogger(c).Info().Int("announcement_id", id).Int("admin_id", adminUserID).Msg("Announcement created successfully")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Announcement created successfully", Data: created,
+	})
+}
+
+// GetAllAnnouncements godoc
+// @Summary Get all announcements
+// @Description Retrieves all announcements (including scheduled and expired ones), newest publish time first.
+// @Tags Admin - Announcements
+// @Produce json
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Limit of announcements per page"
+// @Success 200 {object} models.Response{data=[]models.Announcement} "Announcements retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error during announcement retrieval"
+// @Security ApiKeyAuth
+// @Router /admin/announcements [get]
+func (h *AnnouncementHandler) GetAllAnnouncements(c *fiber.Ctx) error {
+	pagination := utils.ParsePaginationParams(c)
+	announcements, total, err := h.AnnouncementRepo.GetAllAnnouncements(c.UserContext(), pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get announcements")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve announcements",
+		})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Announcements retrieved successfully", announcements, meta))
+}
+
+// GetAnnouncementByID godoc
+// @Summary Get announcement by ID
+// @Description Retrieves a single announcement.
+// @Tags Admin - Announcements
+// @Produce json
+// @Param announcementId path int true "Announcement ID"
+// @Success 200 {object} models.Response{data=models.Announcement} "Announcement retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid Announcement ID parameter"
+// @Failure 404 {object} models.Response "Announcement not found"
+// @Failure 500 {object} models.Response "Internal server error during announcement retrieval"
+// @Security ApiKeyAuth
+// @Router /admin/announcements/{announcementId} [get]
+func (h *AnnouncementHandler) GetAnnouncementByID(c *fiber.Ctx) error {
+	id, err := announcementIDParam(c)
+	if err != nil {
+		return invalidAnnouncementID(c, err)
+	}
+	announcement, err := h.AnnouncementRepo.GetAnnouncementByID(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Announcement with ID %d not found", id),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("announcement_id", id).Msg("Error getting announcement by ID")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve announcement",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Announcement retrieved successfully", Data: announcement,
+	})
+}
+
+// UpdateAnnouncement godoc
+// @Summary Update announcement
+// @Description Replaces the content, publish window, and audience of an announcement.
+// @Tags Admin - Announcements
+// @Accept json
+// @Produce json
+// @Param announcementId path int true "Announcement ID"
+// @Param announcement body models.AnnouncementInput true "Announcement details"
+// @Success 200 {object} models.Response "Announcement updated successfully"
+// @Failure 400 {object} models.Response "Invalid ID, validation failed, or unknown audience role"
+// @Failure 404 {object} models.Response "Announcement not found"
+// @Failure 500 {object} models.Response "Internal server error during announcement update"
+// @Security ApiKeyAuth
+// @Router /admin/announcements/{announcementId} [put]
+func (h *AnnouncementHandler) UpdateAnnouncement(c *fiber.Ctx) error {
+	id, err := announcementIDParam(c)
+	if err != nil {
+		return invalidAnnouncementID(c, err)
+	}
+	announcement, ferr := h.parseAnnouncementInput(c)
+	if ferr != nil {
+		return invalidAnnouncement(c, ferr)
+	}
+	announcement.ID = id
+
+	if err := h.AnnouncementRepo.UpdateAnnouncement(c.UserContext(), announcement); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Announcement with ID %d not found", id),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("announcement_id", id).Msg("Error updating announcement")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to update announcement",
+		})
+	}
+
+	reqLogger(c).Info().Int("announcement_id", id).Msg("Announcement updated successfully")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Announcement updated successfully",
+	})
+}
+
+// DeleteAnnouncement godoc
+// @Summary Delete announcement
+// @Description Deletes an announcement.
+// @Tags Admin - Announcements
+// @Produce json
+// @Param announcementId path int true "Announcement ID"
+// @Success 200 {object} models.Response "Announcement deleted successfully"
+// @Failure 400 {object} models.Response "Invalid Announcement ID parameter"
+// @Failure 404 {object} models.Response "Announcement not found"
+// @Failure 500 {object} models.Response "Internal server error during announcement deletion"
+// @Security ApiKeyAuth
+// @Router /admin/announcements/{announcementId} [delete]
+func (h *AnnouncementHandler) DeleteAnnouncement(c *fiber.Ctx) error {
+	id, err := announcementIDParam(c)
+	if err != nil {
+		return invalidAnnouncementID(c, err)
+	}
+	if err := h.AnnouncementRepo.DeleteAnnouncement(c.UserContext(), id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Announcement with ID %d not found", id),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("announcement_id", id).Msg("Error deleting announcement")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to delete announcement",
+		})
+	}
+
+	reqLogger(c).Info().Int("announcement_id", id).Msg("Announcement deleted successfully")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Announcement deleted successfully",
+	})
+}
+
+// GetMyAnnouncements godoc
+// @Summary Get my announcements
+// @Description Retrieves announcements currently in their publish window and addressed to the current user's role (or to everyone).
+// @Tags User - Announcements
+// @Produce json
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Limit of announcements per page"
+// @Success 200 {object} models.Response{data=[]models.Announcement} "Announcements retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error during announcement retrieval"
+// @Security ApiKeyAuth
+// @Router /user/announcements [get]
+func (h *AnnouncementHandler) GetMyAnnouncements(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+
+	pagination := utils.ParsePaginationParams(c)
+	announcements, total, err := h.AnnouncementRepo.GetActiveAnnouncementsForUser(c.UserContext(), userID, time.Now(), pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get active announcements")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve announcements",
+		})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Announcements retrieved successfully", announcements, meta))
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// errApprovalNotPending dikembalikan approvalSource.decide untuk item yang sudah diputuskan.
+var errApprovalNotPending = errors.New("item is no longer pending")
+
+// approvalSource adalah satu tipe item yang menunggu keputusan admin di approval inbox.
+type approvalSource struct {
+	// list mengembalikan maksimal limit item pending terlama dulu, beserta jumlah totalnya.
+	list func(c *fiber.Ctx, limit int) ([]models.ApprovalItem, int, error)
+	// decide menyetujui/menolak satu item; pgx.ErrNoRows jika tidak ada, errApprovalNotPending jika sudah diputuskan.
+	decide func(c *fiber.Ctx, id, actorUserID int, approve bool, note string) error
+}
+
+// ApprovalHandler melayani approval inbox admin: satu antrean untuk semua item yang menunggu
+// keputusan (saat ini dispute absensi), dengan jumlah per tipe dan approve/reject massal.
+// Tipe baru cukup didaftarkan sebagai approvalSource di NewApprovalHandler. Item menyertakan
+// riwayat eskalasi SLA (lihat jobs.ApprovalEscalation).
+type ApprovalHandler struct {
+	sources        map[string]approvalSource
+	EscalationRepo repository.EscalationRepository
+	Validate       *validator.Validate
+}
+
+func NewApprovalHandler(disputeRepo repository.DisputeRepository, escalationRepo repository.EscalationRepository, eventBus events.Publisher, txManager repository.TxManager) *ApprovalHandler {
+	return &ApprovalHandler{
+		sources: map[string]approvalSource{
+			models.ApprovalTypeDispute: disputeApprovals(disputeRepo, eventBus, txManager),
+		},
+		EscalationRepo: escalationRepo,
+		Validate:       validator.New(),
+	}
+}
+
+// disputeApprovals memetakan dispute open ke approval inbox: approve = resolved, reject = rejected.
+func disputeApprovals(repo repository.DisputeRepository, bus events.Publisher, tx repository.TxManager) approvalSource {
+	return approvalSource{
+		list: func(c *fiber.Ctx, limit int) ([]models.ApprovalItem, int, error) {
+			disputes, total, err := repo.GetAllDisputes(c.UserContext(), models.DisputeOpen, 1, limit)
+			if err != nil {
+				return nil, 0, err
+			}
+			items := make([]models.ApprovalItem, 0, len(disputes))
+			for _, d := range disputes {
+				items = append(items, models.ApprovalItem{
+					Type: models.ApprovalTypeDispute, ID: d.ID, UserID: d.UserID,
+					Summary:     fmt.Sprintf("Attendance record %d disputed: %s", d.AttendanceID, d.Comment),
+					SubmittedAt: d.CreatedAt, Details: d,
+				})
+			}
+			return items, total, nil
+		},
+		decide: func(c *fiber.Ctx, id, actorUserID int, approve bool, note string) error {
+			status := models.DisputeRejected
+			if approve {
+				status = models.DisputeResolved
+			}
+			_, err := resolveDispute(c, repo, bus, tx, id, actorUserID, status, note)
+			if errors.Is(err, repository.ErrDisputeNotOpen) {
+				return errApprovalNotPending
+			}
+			return err
+		},
+	}
+}
+
+// approvalTypes mengembalikan tipe terdaftar secara terurut (untuk pesan error & urutan stabil).
+func (h *ApprovalHandler) approvalTypes() []string {
+	types := make([]string, 0, len(h.sources))
+	for t := range h.sources {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// GetApprovals godoc
+// @Summary Get approval inbox
+// @Description Returns one queue of everything waiting for an admin decision (currently open attendance disputes, i.e. employee correction requests), oldest first, with the number of pending items per type. Each item carries its escalation history when it waited past its SLA. Filter to one type with the type query parameter.
+// @Tags Admin - Approvals
+// @Produce json
+// @Param type query string false "Only items of this type" Enums(attendance_dispute)
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Limit of items per page"
+// @Success 200 {object} models.Response{data=map[string]interface{}} "Items, counts per type and pagination meta"
+// @Failure 400 {object} models.Response "Unknown type"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/approvals [get]
+func (h *ApprovalHandler) GetApprovals(c *fiber.Ctx) error {
+	filter := c.Query("type")
+	if _, ok := h.sources[filter]; filter != "" && !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Invalid type, expected one of: %v", h.approvalTypes()),
+		})
+	}
+	pagination := utils.ParsePaginationParams(c)
+
+	// Setiap sumber mengembalikan item terlama sampai akhir halaman yang diminta; hasilnya
+	// digabung berdasarkan waktu pengajuan lalu dipotong ke halaman tersebut.
+	window := pagination.Offset + pagination.Limit
+	counts := map[string]int{}
+	items := []models.ApprovalItem{}
+	total := 0
+	for _, t := range h.approvalTypes() {
+		limit := window
+		if filter != "" && t != filter {
+			limit = 0 // Hanya jumlahnya yang dibutuhkan
+		}
+		list, count, err := h.sources[t].list(c, limit)
+		if err != nil {
+			reqLogger(c).Error().Err(err).Str("type", t).Msg("Failed to get pending approvals")
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve approvals"})
+		}
+		counts[t] = count
+		if limit > 0 {
+			total += count
+			items = append(items, list...)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].SubmittedAt.Before(items[j].SubmittedAt) })
+	items = items[min(pagination.Offset, len(items)):min(window, len(items))]
+	if err := h.attachEscalations(c, items); err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get approval escalations")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve approvals"})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Approvals retrieved successfully",
+		Data: fiber.Map{"counts": counts, "items": items, "meta": utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)},
+	})
+}
+
+// attachEscalations mengisi riwayat eskalasi setiap item (kosong jika belum pernah dieskalasi).
+func (h *ApprovalHandler) attachEscalations(c *fiber.Ctx, items []models.ApprovalItem) error {
+	idsByType := map[string][]int{}
+	for _, item := range items {
+		idsByType[item.Type] = append(idsByType[item.Type], item.ID)
+	}
+	for itemType, ids := range idsByType {
+		history, err := h.EscalationRepo.GetEscalations(c.UserContext(), itemType, ids)
+		if err != nil {
+			return err
+		}
+		for i := range items {
+			if items[i].Type == itemType {
+				items[i].Escalations = history[items[i].ID]
+			}
+		}
+	}
+	for i := range items {
+		if items[i].Escalations == nil {
+			items[i].Escalations = []models.ApprovalEscalation{}
+		}
+	}
+	return nil
+}
+
+// BulkDecideApprovals godoc
+// @Summary Bulk approve or reject approval items
+// @Description Approves or rejects many approval inbox items of any type with one note. Each item is decided in its own transaction and gets its own result: updated, not_found, or skipped (unknown type or already decided). Approving an attendance dispute resolves it; rejecting it rejects it.
+// @Tags Admin - Approvals
+// @Accept json
+// @Produce json
+// @Param decision body models.BulkApprovalInput true "Action, note and items"
+// @Success 200 {object} models.Response{data=map[string]interface{}} "Per-item results and summary"
+// @Failure 400 {object} models.Response "Validation failed"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/approvals/bulk [post]
+func (h *ApprovalHandler) BulkDecideApprovals(c *fiber.Ctx) error {
+	adminUserID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting admin userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	input := new(models.BulkApprovalInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	approve := input.Action == models.ApprovalApprove
+
+	summary := map[string]int{
+		models.BulkStatusUpdated:  0,
+		models.BulkStatusNotFound: 0,
+		models.BulkStatusSkipped:  0,
+	}
+	results := make([]models.ApprovalItemResult, 0, len(input.Items))
+	for _, item := range input.Items {
+		result := models.ApprovalItemResult{Type: item.Type, BulkItemResult: models.BulkItemResult{ID: item.ID, Status: models.BulkStatusUpdated}}
+		if source, ok := h.sources[item.Type]; !ok {
+			result.Status, result.Message = models.BulkStatusSkipped, "Unknown approval type"
+		} else if err := source.decide(c, item.ID, adminUserID, approve, input.Note); err != nil {
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				result.Status = models.BulkStatusNotFound
+			case errors.Is(err, errApprovalNotPending):
+				result.Status, result.Message = models.BulkStatusSkipped, errApprovalNotPending.Error()
+			default:
+				reqLogger(c).Error().Err(err).Str("type", item.Type).Int("id", item.ID).Msg("Failed to decide approval item")
+				return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+					Success: false, Message: "Failed to process approvals",
+					Data: fiber.Map{"summary": summary, "results": results},
+				})
+			}
+		}
+		summary[result.Status]++
+		results = append(results, result)
+	}
+
+	reqLogger(c).Info().
+		Int("admin_id", adminUserID).
+		Str("action", input.Action).
+		Int("updated", summary[models.BulkStatusUpdated]).
+		Int("skipped", summary[models.BulkStatusSkipped]).
+		Msg("Admin bulk decided approval items")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Approvals processed",
+		Data: fiber.Map{"action": input.Action, "summary": summary, "results": results},
+	})
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// workModeError memeriksa mode kerja check-in terhadap kebijakan user pada waktu at (sudah di
+// zona waktu default sistem). Onsite selalu boleh; remote hanya pada hari di remote_days; field
+// hanya jika field_work_allowed. Pesan error ditujukan ke klien.
+func workModeError(user *models.User, mode string, at time.Time) error {
+	switch mode {
+	case models.AttendanceModeRemote:
+		day := strings.ToLower(at.Weekday().String()[:3])
+		if !slices.Contains(strings.Split(user.RemoteDays, ","), day) {
+			return fmt.Errorf("remote work is not allowed for you on %s", at.Weekday())
+		}
+	case models.AttendanceModeField:
+		if !user.FieldWorkAllowed {
+			return errors.New("field work is not allowed for you")
+		}
+	}
+	return nil
+}
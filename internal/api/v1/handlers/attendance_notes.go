@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+)
+
+// Catatan & tag absensi: catatan tetap teks bebas (panjang dibatasi tag validate), sedangkan tag
+// dipilih dari daftar di pengaturan attendance.tags agar laporan bisa merekap per tag.
+
+// attendanceNote merapikan catatan check-in/check-out: spasi di awal/akhir dibuang dan catatan
+// kosong menjadi nil.
+func attendanceNote(notes *string) (*string, error) {
+	if notes == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*notes)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if err := noteContentError(trimmed); err != nil {
+		return nil, err
+	}
+	return &trimmed, nil
+}
+
+// noteContentError menolak karakter kontrol (selain baris baru & tab) di catatan absensi.
+func noteContentError(notes string) error {
+	for _, r := range notes {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return fmt.Errorf("notes must not contain control characters")
+		}
+	}
+	return nil
+}
+
+// attendanceTags menormalkan tag (huruf kecil, tanpa spasi di tepi & duplikat) dan memastikan
+// semuanya terdaftar di pengaturan attendance.tags.
+func attendanceTags(ctx context.Context, store *settings.Store, tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	var allowed []string
+	if store != nil {
+		allowed = store.AttendanceTags(ctx)
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("attendance tags are disabled")
+	}
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !slices.Contains(allowed, tag) {
+			return nil, fmt.Errorf("unknown tag %q, allowed: %s", tag, strings.Join(allowed, ", "))
+		}
+		if !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out, nil
+}
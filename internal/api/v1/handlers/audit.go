@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// maxAuditUserAgent membatasi panjang user agent yang disimpan di audit log.
+const maxAuditUserAgent = 255
+
+// publishEvent mem-publish domain event ke bus; audit log, notifikasi, dan webhook memprosesnya
+// sebagai subscriber (best-effort: kegagalan subscriber hanya di-log dan tidak menggagalkan
+// request yang sudah berhasil). Actor diisi dari JWT jika belum di-set.
+// bus nil berarti event tidak dipasang (mis. di test handler).
+func publishEvent(c *fiber.Ctx, bus events.Publisher, ev events.Event) {
+	if bus == nil {
+		return
+	}
+	if ev.ActorUserID == nil {
+		if claims, ok := c.Locals("user").(*utils.JwtClaims); ok {
+			actorID := claims.UserID
+			ev.ActorUserID = &actorID
+		}
+	}
+	bus.Publish(c.UserContext(), ev)
+}
+
+// runInTx menjalankan fn dalam satu transaksi database (tx nil = tanpa transaksi). Selama fn
+// berjalan c.UserContext() membawa transaksi, sehingga tulisan repository dan pesan outbox dari
+// publishEvent di dalamnya ter-commit atau di-rollback bersama.
+func runInTx(c *fiber.Ctx, tx repository.TxManager, fn func() error) error {
+	if tx == nil {
+		return fn()
+	}
+	parent := c.UserContext()
+	defer c.SetUserContext(parent)
+	return tx.RunInTx(parent, func(ctx context.Context) error {
+		c.SetUserContext(ctx)
+		return fn()
+	})
+}
+
+// clientDetails mengembalikan IP & user agent request untuk entri audit login.
+func clientDetails(c *fiber.Ctx) map[string]any {
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	if len(userAgent) > maxAuditUserAgent {
+		userAgent = userAgent[:maxAuditUserAgent]
+	}
+	return map[string]any{"ip": c.IP(), "user_agent": userAgent}
+}
+
+// submittedFields mengembalikan nama field JSON yang dikirim di body (terurut), tanpa nilainya.
+// Dipakai agar audit log mencatat field apa yang diubah tanpa menyimpan data pribadi.
+func submittedFields(c *fiber.Ctx) []string {
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return nil
+	}
+	fields := make([]string, 0, len(body))
+	for name := range body {
+		if name != "version" {
+			fields = append(fields, name)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// encodeActivityCursor mengubah posisi feed aktivitas menjadi token opaque (base64url).
+func encodeActivityCursor(cur models.ActivityCursor) string {
+	raw := cur.OccurredAt.UTC().Format(time.RFC3339Nano) + "|" + cur.Source + "|" + strconv.FormatInt(cur.SourceID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeActivityCursor membaca token dari encodeActivityCursor.
+func decodeActivityCursor(token string) (*models.ActivityCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 || (parts[1] != models.ActivitySourceAuditLog && parts[1] != models.ActivitySourceAttendanceEvents) {
+		return nil, errors.New("malformed activity cursor")
+	}
+	occurredAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, err
+	}
+	sourceID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ActivityCursor{OccurredAt: occurredAt, Source: parts[1], SourceID: sourceID}, nil
+}
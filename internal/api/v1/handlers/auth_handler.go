@@ -2,54 +2,78 @@
 package handlers
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/loginalert"
 	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/otp"
 	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/session"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
 	"github.com/rakaarfi/attendance-system-be/internal/utils"
-	zlog "github.com/rs/zerolog/log"
 )
 
+// resetTokenTTL adalah masa berlaku token reset password setelah tautan "bukan saya" dipakai.
+const resetTokenTTL = 30 * time.Minute
+
 type AuthHandler struct {
-	UserRepo repository.UserRepository
-	RoleRepo repository.RoleRepository
-	Validate *validator.Validate
+	UserRepo    repository.UserRepository
+	RoleRepo    repository.RoleRepository
+	Settings    *settings.Store       // Zona waktu default untuk masa akses contractor
+	Events      events.Publisher      // Riwayat login (feed aktivitas admin)
+	LoginAlerts *loginalert.Alerter   // nil = peringatan login tidak biasa dimatikan
+	Sessions    *session.VersionCache // Di-invalidate saat sesi dicabut; nil = tanpa cache
+	OTP         *otp.Service          // Kode SMS untuk 2FA login; nil = SMS tidak dikonfigurasi
+	Validate    *validator.Validate
 }
 
-func NewAuthHandler(userRepo repository.UserRepository, roleRepo repository.RoleRepository) *AuthHandler {
+func NewAuthHandler(userRepo repository.UserRepository, roleRepo repository.RoleRepository, settingsStore *settings.Store, eventBus events.Publisher, loginAlerts *loginalert.Alerter, sessions *session.VersionCache, otpService *otp.Service) *AuthHandler {
 	return &AuthHandler{
-		UserRepo: userRepo,
-		RoleRepo: roleRepo,
-		Validate: validator.New(),
+		UserRepo:    userRepo,
+		RoleRepo:    roleRepo,
+		Settings:    settingsStore,
+		Events:      eventBus,
+		LoginAlerts: loginAlerts,
+		Sessions:    sessions,
+		OTP:         otpService,
+		Validate:    validator.New(),
 	}
 }
 
 // Register godoc
 // @Summary Register New User
-// @Description Creates a new user account.
+// @Description Creates a new user account. Disabled (403) when the auth.registration_mode setting is invite_only; invited users register through POST /auth/invitations/accept instead. Without role_id the user gets the auth.registration_default_role role; other roles must be listed in auth.registration_roles.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param register body models.RegisterUserInput true "User Registration Details"
 // @Success 201 {object} models.Response{data=map[string]int} "User registered successfully, returns user ID"
 // @Failure 400 {object} models.Response "Validation failed or invalid request body"
+// @Failure 403 {object} models.Response "Public registration is disabled or the role cannot be self-assigned"
 // @Failure 409 {object} models.Response "Username or Email already exists" // Tambahkan jika ada penanganan conflict
 // @Failure 500 {object} models.Response "Internal server error during registration"
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
+	if h.Settings.RegistrationMode(c.UserContext()) == models.RegistrationInviteOnly {
+		return c.Status(fiber.StatusForbidden).JSON(models.Response{
+			Success: false, Message: "Public registration is disabled; ask an administrator for an invitation",
+		})
+	}
 	input := new(models.RegisterUserInput)
 
 	// Parse body
 	if err := c.BodyParser(input); err != nil {
-		zlog.Error().Err(err).Msg("Error parsing register input")
+		reqLogger(c).Error().Err(err).Msg("Error parsing register input")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false,
 			Message: "Invalid request body",
@@ -58,7 +82,7 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 
 	// Validate input
 	if err := h.Validate.Struct(input); err != nil {
-		zlog.Warn().Err(err).Msg("Validation failed during registration") // Log warning
+		reqLogger(c).Warn().Err(err).Msg("Validation failed during registration") // Log warning
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false,
 			Message: "Validation failed",
@@ -66,42 +90,62 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 		})
 	}
 
-	// --- Optional: Validasi Role ID ---
-	_, err := h.RoleRepo.GetRoleByID(context.Background(), input.RoleID)
-	if err != nil {
-		log.Printf("Error getting role ID %d: %v", input.RoleID, err)
-		// Handle jika role tidak ditemukan (pgx.ErrNoRows)
-		if errors.Is(err, pgx.ErrNoRows) {
-			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+	// Role registrasi publik ditentukan server: role default (auth.registration_default_role)
+	// jika role_id kosong, selain itu hanya role yang boleh dipilih sendiri (auth.registration_roles).
+	if input.RoleID == 0 {
+		role, err := h.roleByName(c, h.Settings.String(c.UserContext(), settings.KeyRegistrationRole))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+					Success: false, Message: "role_id is required",
+				})
+			}
+			reqLogger(c).Error().Err(err).Msg("Failed to resolve default registration role")
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+				Success: false, Message: "Failed to validate role",
+			})
+		}
+		input.RoleID = role.ID
+	} else {
+		role, err := h.RoleRepo.GetRoleByID(c.UserContext(), input.RoleID)
+		if err != nil {
+			log.Printf("Error getting role ID %d: %v", input.RoleID, err)
+			// Handle jika role tidak ditemukan (pgx.ErrNoRows)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+					Success: false,
+					Message: fmt.Sprintf("Role with ID %d not found", input.RoleID),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 				Success: false,
-				Message: fmt.Sprintf("Role with ID %d not found", input.RoleID),
+				Message: "Failed to validate role",
+			})
+		}
+		if !h.Settings.SelfAssignableRole(c.UserContext(), role.Name) {
+			reqLogger(c).Warn().Int("role_id", role.ID).Str("role", role.Name).Msg("Registration with a role that cannot be self-assigned")
+			return c.Status(fiber.StatusForbidden).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Role %s cannot be chosen at registration", role.Name),
 			})
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
-			Success: false,
-			Message: "Failed to validate role",
-		})
 	}
-	// --- End Optional ---
 
 	// Hash password
 	hashedPassword, err := utils.HashPassword(input.Password)
 	if err != nil {
-		zlog.Warn().Err(err).Msg("Validation failed during registration") // Log warning
+		reqLogger(c).Warn().Err(err).Msg("Validation failed during registration") // Log warning
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false,
 			Message: "Failed to process registration",
 		})
 	}
-	// --- CETAK HASH SAAT REGISTRASI (Sementara) ---
-	zlog.Debug().Str("username", input.Username).Str("plaintext", input.Password).Str("generated_hash", hashedPassword).Msg("Password hashed during registration")
-	// --- AKHIR CETAK ---
+	reqLogger(c).Debug().Str("username", input.Username).Msg("Password hashed during registration")
 
 	// Create user in database
-	zlog.Debug().Str("username", input.Username).Msg("Attempting to create user in DB") // Log debug
-	userID, err := h.UserRepo.CreateUser(context.Background(), input, hashedPassword)
+	reqLogger(c).Debug().Str("username", input.Username).Msg("Attempting to create user in DB") // Log debug
+	userID, err := h.UserRepo.CreateUser(c.UserContext(), input, hashedPassword)
 	if err != nil {
-		zlog.Error().Err(err).Str("username", input.Username).Msg("Error creating user in DB")
+		reqLogger(c).Error().Err(err).Str("username", input.Username).Msg("Error creating user in DB")
 		// Cek error spesifik (misal: username/email sudah ada - unique constraint violation)
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
 			return c.Status(fiber.StatusConflict).JSON(models.Response{ // 409 Conflict
@@ -115,7 +159,7 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 		})
 	}
 
-	zlog.Info().Int("userID", userID).Str("username", input.Username).Msg("User registered successfully")
+	reqLogger(c).Info().Int("userID", userID).Str("username", input.Username).Msg("User registered successfully")
 	// Jangan kirim data user lengkap atau password hash di response registrasi
 	return c.Status(fiber.StatusCreated).JSON(models.Response{
 		Success: true,
@@ -124,9 +168,26 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	})
 }
 
+// roleByName mencari role dengan nama name (tanpa membedakan huruf besar/kecil), atau pgx.ErrNoRows.
+func (h *AuthHandler) roleByName(c *fiber.Ctx, name string) (*models.Role, error) {
+	if name == "" {
+		return nil, pgx.ErrNoRows
+	}
+	roles, err := h.RoleRepo.GetAllRoles(c.UserContext())
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		if strings.EqualFold(roles[i].Name, name) {
+			return &roles[i], nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
 // Login godoc
 // @Summary User Login
-// @Description Authenticates a user and returns a JWT token upon successful login.
+// @Description Authenticates a user and returns a JWT token upon successful login. Users with SMS two-factor authentication instead receive two_factor_required, a challenge_token and its expires_at; the code sent by SMS is then submitted to POST /auth/login/otp. Users created by an admin with require_password_change receive password_change_required and a reset_token for POST /auth/password/reset instead of a session token.
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -134,31 +195,33 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 // @Success 200 {object} models.Response{data=map[string]string} "Login successful, returns JWT token"
 // @Failure 400 {object} models.Response "Validation failed or invalid request body"
 // @Failure 401 {object} models.Response "Invalid username or password"
+// @Failure 403 {object} models.Response "Account inactive, outside its access period (contractors), or password reset required"
 // @Failure 500 {object} models.Response "Internal server error during login"
+// @Failure 503 {object} models.Response "Login code could not be sent by SMS"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	input := new(models.LoginUserInput)
 
 	if err := c.BodyParser(input); err != nil {
-		zlog.Warn().Err(err).Msg("Invalid request body during login")
+		reqLogger(c).Warn().Err(err).Msg("Invalid request body during login")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Invalid request body",
 		})
 	}
 
 	if err := h.Validate.Struct(input); err != nil {
-		zlog.Warn().Err(err).Msg("Validation failed during login")
+		reqLogger(c).Warn().Err(err).Msg("Validation failed during login")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Validation failed", Data: err.Error(),
 		})
 	}
 
 	// Get user by username
-	user, err := h.UserRepo.GetUserByUsername(context.Background(), input.Username)
+	user, err := h.UserRepo.GetUserByUsername(c.UserContext(), input.Username)
 	if err != nil {
-		zlog.Error().Err(err).Str("username", input.Username).Msg("Error getting user during login")
+		reqLogger(c).Error().Err(err).Str("username", input.Username).Msg("Error getting user during login")
 		if err == pgx.ErrNoRows { // User tidak ditemukan
-			zlog.Info().Str("username", input.Username).Msg("User not found during login")
+			reqLogger(c).Info().Str("username", input.Username).Msg("User not found during login")
 			return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
 				Success: false, Message: "Invalid username or password",
 			})
@@ -170,31 +233,431 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 
 	// Check password
 	if !utils.CheckPasswordHash(input.Password, user.Password) {
-		zlog.Info().Str("username", input.Username).Msg("Invalid password during login")
+		reqLogger(c).Info().Str("username", input.Username).Msg("Invalid password during login")
+		details := clientDetails(c)
+		details["reason"] = "invalid_password"
+		publishEvent(c, h.Events, events.Event{Name: events.LoginFailed, UserID: user.ID, ActorUserID: &user.ID, Data: details})
 		return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
 			Success: false, Message: "Invalid username or password",
 		})
 	}
 
-	// Generate JWT
+	// Akun nonaktif (mis. kontrak berakhir) atau di luar masa akses contractor tidak boleh login.
+	// Batas tanggal dihitung di zona waktu default sistem (general.default_timezone).
+	now := time.Now()
+	accessStart, accessEnd := user.AccessWindow(h.Settings.DefaultLocation(c.UserContext()))
+	if !user.IsActive || (accessEnd != nil && !now.Before(*accessEnd)) {
+		reqLogger(c).Info().Int("user_id", user.ID).Str("user_type", user.UserType).Msg("Login rejected: account inactive or access period ended")
+		details := clientDetails(c)
+		details["reason"] = "access_ended"
+		publishEvent(c, h.Events, events.Event{Name: events.LoginRejected, UserID: user.ID, ActorUserID: &user.ID, Data: details})
+		return c.Status(fiber.StatusForbidden).JSON(models.Response{
+			Success: false, Message: "Account access has ended",
+		})
+	}
+	if accessStart != nil && now.Before(*accessStart) {
+		reqLogger(c).Info().Int("user_id", user.ID).Str("valid_from", *user.ValidFrom).Msg("Login rejected: access period not started")
+		details := clientDetails(c)
+		details["reason"] = "access_not_started"
+		publishEvent(c, h.Events, events.Event{Name: events.LoginRejected, UserID: user.ID, ActorUserID: &user.ID, Data: details})
+		return c.Status(fiber.StatusForbidden).JSON(models.Response{
+			Success: false, Message: "Account access has not started yet",
+		})
+	}
+
+	// Sesi dicabut lewat tautan "bukan saya": login ditolak sampai password di-reset.
+	if user.PasswordResetRequired {
+		reqLogger(c).Info().Int("user_id", user.ID).Msg("Login rejected: password reset required")
+		details := clientDetails(c)
+		details["reason"] = "password_reset_required"
+		publishEvent(c, h.Events, events.Event{Name: events.LoginRejected, UserID: user.ID, ActorUserID: &user.ID, Data: details})
+		return c.Status(fiber.StatusForbidden).JSON(models.Response{
+			Success: false, Message: "Password reset required",
+		})
+	}
+
+	// Rehash transparan: hash lama (bcrypt) atau parameter Argon2id yang sudah usang
+	// diperbarui di sini, selagi password plaintext masih tersedia.
+	// Kegagalan rehash tidak menggagalkan login.
+	if utils.PasswordNeedsRehash(user.Password) {
+		if newHash, errHash := utils.HashPassword(input.Password); errHash != nil {
+			reqLogger(c).Warn().Err(errHash).Int("user_id", user.ID).Msg("Failed to rehash password during login")
+		} else if errUpdate := h.UserRepo.UpdateUserPassword(c.UserContext(), user.ID, newHash); errUpdate != nil {
+			reqLogger(c).Warn().Err(errUpdate).Int("user_id", user.ID).Msg("Failed to store rehashed password during login")
+		} else {
+			reqLogger(c).Info().Int("user_id", user.ID).Msg("Password hash upgraded to current Argon2id parameters")
+		}
+	}
+
+	// Password sementara dari admin: belum ada token sesi, hanya token reset untuk /auth/password/reset.
+	if user.MustChangePassword {
+		resetToken, err := utils.GeneratePurposeToken(utils.PurposePasswordReset, user.ID, user.TokenVersion, resetTokenTTL)
+		if err != nil {
+			reqLogger(c).Error().Err(err).Int("user_id", user.ID).Msg("Failed to generate password change token")
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Login failed"})
+		}
+		reqLogger(c).Info().Int("user_id", user.ID).Msg("Password accepted, password change required")
+		return c.Status(http.StatusOK).JSON(models.Response{
+			Success: true,
+			Message: "Please set a new password",
+			Data:    fiber.Map{"password_change_required": true, "reset_token": resetToken},
+		})
+	}
+
+	// Faktor kedua: kode OTP SMS ke nomor terverifikasi; token sesi baru terbit di /auth/login/otp.
+	// Tanpa provider SMS (SMS_PROVIDER kosong) 2FA SMS tidak bisa dijalankan dan dilewati.
+	if user.SMSTwoFactor && user.PhoneVerifiedAt != nil {
+		if h.OTP != nil {
+			return h.startLoginOTP(c, user)
+		}
+		reqLogger(c).Warn().Int("user_id", user.ID).Msg("SMS two-factor enabled for user but SMS is not configured; skipping second factor")
+	}
+	return h.completeLogin(c, user, accessEnd, nil)
+}
+
+// startLoginOTP mengirim kode login lewat SMS dan mengembalikan challenge_token untuk /auth/login/otp.
+func (h *AuthHandler) startLoginOTP(c *fiber.Ctx, user *models.User) error {
+	otpCode, err := h.OTP.Send(c.UserContext(), user, models.OTPPurposeLogin)
+	if err != nil && !errors.Is(err, otp.ErrResendTooSoon) {
+		reqLogger(c).Error().Err(err).Int("user_id", user.ID).Msg("Failed to send login code")
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.Response{
+			Success: false, Message: "Failed to send login code, please try again",
+		})
+	}
+	// Kode yang baru saja dikirim tetap berlaku; challenge baru mengikuti masa berlakunya.
+	challenge, err := utils.GeneratePurposeToken(utils.PurposeLoginOTP, user.ID, user.TokenVersion, time.Until(otpCode.ExpiresAt))
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", user.ID).Msg("Error generating login challenge token")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Login failed"})
+	}
+	reqLogger(c).Info().Int("user_id", user.ID).Msg("Password accepted, login code sent by SMS")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true,
+		Message: "Enter the code sent to your phone",
+		Data:    fiber.Map{"two_factor_required": true, "challenge_token": challenge, "expires_at": otpCode.ExpiresAt},
+	})
+}
+
+// VerifyLoginOTP godoc
+// @Summary Complete login with SMS code
+// @Description Second step of the login for users with SMS two-factor authentication: exchanges the challenge_token returned by POST /auth/login and the code sent by SMS (valid for OTP_TTL, default 5m; OTP_MAX_ATTEMPTS wrong tries) for a session token.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param otp body models.LoginOTPInput true "Challenge token and SMS code"
+// @Success 200 {object} models.Response{data=map[string]string} "Login successful, returns JWT token"
+// @Failure 400 {object} models.Response "Validation failed or invalid request body"
+// @Failure 401 {object} models.Response "Invalid or expired code"
+// @Failure 403 {object} models.Response "Account inactive or outside its access period"
+// @Failure 404 {object} models.Response "SMS is not configured"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Router /auth/login/otp [post]
+func (h *AuthHandler) VerifyLoginOTP(c *fiber.Ctx) error {
+	if h.OTP == nil {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "SMS is not configured"})
+	}
+	input := new(models.LoginOTPInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	invalid := func() error {
+		return c.Status(fiber.StatusUnauthorized).JSON(models.Response{Success: false, Message: "Invalid or expired code"})
+	}
+	claims, err := utils.ValidatePurposeToken(input.ChallengeToken, utils.PurposeLoginOTP)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Invalid login challenge token")
+		return invalid()
+	}
+	user, accessEnd, handled, resp := tokenOwner(c, h.UserRepo, h.Settings, claims.UserID)
+	if handled {
+		return resp
+	}
+	if user.TokenVersion != claims.TokenVersion || !user.SMSTwoFactor {
+		return invalid()
+	}
+	if err := h.OTP.Verify(c.UserContext(), user.ID, models.OTPPurposeLogin, input.Code); err != nil {
+		if errors.Is(err, otp.ErrInvalidCode) {
+			reqLogger(c).Info().Int("user_id", user.ID).Msg("Invalid login code")
+			details := clientDetails(c)
+			details["reason"] = "invalid_otp"
+			publishEvent(c, h.Events, events.Event{Name: events.LoginFailed, UserID: user.ID, ActorUserID: &user.ID, Data: details})
+			return invalid()
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", user.ID).Msg("Failed to verify login code")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Login failed"})
+	}
+	return h.completeLogin(c, user, accessEnd, map[string]any{"second_factor": "sms"})
+}
+
+// completeLogin menerbitkan token sesi, menjalankan peringatan login tidak biasa, dan mencatat
+// login berhasil (ditambah extra di detail audit).
+func (h *AuthHandler) completeLogin(c *fiber.Ctx, user *models.User, accessEnd *time.Time, extra map[string]any) error {
 	if user.Role == nil { // Pastikan role sudah di-load
-		zlog.Warn().Int("user_id", user.ID).Msg("Role not loaded for user during login")
+		reqLogger(c).Warn().Int("user_id", user.ID).Msg("Role not loaded for user during login")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Login failed: User role missing",
 		})
 	}
-	token, err := utils.GenerateJWT(user.ID, user.Username, user.Role.Name) // Gunakan nama role
+	token, err := utils.GenerateJWT(user.ID, user.Username, user.Role.Name, accessEnd, user.TokenVersion) // Gunakan nama role
 	if err != nil {
-		zlog.Error().Err(err).Str("username", input.Username).Msg("Error generating JWT for user during login")
+		reqLogger(c).Error().Err(err).Str("username", user.Username).Msg("Error generating JWT for user during login")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Login failed",
 		})
 	}
 
-	zlog.Info().Str("username", input.Username).Msg("User logged in successfully")
+	reqLogger(c).Info().Str("username", user.Username).Msg("User logged in successfully")
+	details := clientDetails(c)
+	for key, value := range extra {
+		details[key] = value
+	}
+	if h.LoginAlerts != nil {
+		// Dibandingkan dengan riwayat sebelum login ini dicatat.
+		for key, value := range h.LoginAlerts.Inspect(c.UserContext(), user, c.IP(), c.Get(fiber.HeaderUserAgent)) {
+			details[key] = value
+		}
+	}
+	publishEvent(c, h.Events, events.Event{Name: events.LoginSucceeded, UserID: user.ID, ActorUserID: &user.ID, Data: details})
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true,
 		Message: "Login successful",
 		Data:    fiber.Map{"token": token},
 	})
 }
+
+// DenyLogin godoc
+// @Summary Deny Unusual Login ("It wasn't me")
+// @Description Handles the link from an unusual-login alert email: revokes all sessions of the user, requires a password reset, and returns a short-lived reset token.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param deny body models.DenyLoginInput true "Token from the alert link"
+// @Success 200 {object} models.Response{data=map[string]string} "Sessions revoked, returns reset_token"
+// @Failure 400 {object} models.Response "Validation failed or invalid request body"
+// @Failure 401 {object} models.Response "Invalid, expired or already used link"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Router /auth/login-alerts/deny [post]
+func (h *AuthHandler) DenyLogin(c *fiber.Ctx) error {
+	input := new(models.DenyLoginInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid request body",
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	claims, err := utils.ValidatePurposeToken(input.Token, utils.PurposeLoginAlert)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Invalid login alert token")
+		return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
+			Success: false, Message: "Invalid or expired link",
+		})
+	}
+
+	// Hanya berhasil jika versi token masih sama dengan saat email dikirim,
+	// sehingga tautan yang sama tidak bisa dipakai ulang.
+	newVersion, err := h.UserRepo.RevokeSessions(c.UserContext(), claims.UserID, claims.TokenVersion)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			reqLogger(c).Info().Int("user_id", claims.UserID).Msg("Login alert link already used or sessions already revoked")
+			return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
+				Success: false, Message: "Invalid or expired link",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to revoke sessions",
+		})
+	}
+	if h.Sessions != nil {
+		h.Sessions.Invalidate(claims.UserID)
+	}
+	publishEvent(c, h.Events, events.Event{Name: events.LoginDenied, UserID: claims.UserID, ActorUserID: &claims.UserID, Data: clientDetails(c)})
+
+	resetToken, err := utils.GeneratePurposeToken(utils.PurposePasswordReset, claims.UserID, newVersion, resetTokenTTL)
+	if err != nil {
+		// Sesi sudah dicabut; user masih bisa meminta admin mereset password.
+		reqLogger(c).Error().Err(err).Int("user_id", claims.UserID).Msg("Failed to generate password reset token")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Sessions revoked, but failed to issue a password reset token",
+		})
+	}
+
+	reqLogger(c).Info().Int("user_id", claims.UserID).Msg("User denied login; sessions revoked")
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Success: true,
+		Message: "All sessions have been signed out. Please set a new password.",
+		Data:    fiber.Map{"reset_token": resetToken},
+	})
+}
+
+// ResetPassword godoc
+// @Summary Reset Password
+// @Description Sets a new password using the reset token returned by the deny-login endpoint, by a login that requires a password change, or from the account setup email. Revokes all sessions again and re-enables login.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param reset body models.ResetPasswordInput true "Reset token and new password"
+// @Success 200 {object} models.Response "Password reset successfully"
+// @Failure 400 {object} models.Response "Validation failed or invalid request body"
+// @Failure 401 {object} models.Response "Invalid, expired or already used token"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	input := new(models.ResetPasswordInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid request body",
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	claims, err := utils.ValidatePurposeToken(input.Token, utils.PurposePasswordReset)
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Invalid password reset token")
+		return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
+			Success: false, Message: "Invalid or expired token",
+		})
+	}
+
+	hashedPassword, err := utils.HashPassword(input.NewPassword)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", claims.UserID).Msg("Error hashing password during reset")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to reset password",
+		})
+	}
+	if err := h.UserRepo.ResetPassword(c.UserContext(), claims.UserID, hashedPassword, claims.TokenVersion); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
+				Success: false, Message: "Invalid or expired token",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to reset password",
+		})
+	}
+	if h.Sessions != nil {
+		h.Sessions.Invalidate(claims.UserID)
+	}
+	publishEvent(c, h.Events, events.Event{Name: events.PasswordReset, UserID: claims.UserID, ActorUserID: &claims.UserID, Data: clientDetails(c)})
+
+	reqLogger(c).Info().Int("user_id", claims.UserID).Msg("Password reset via token")
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Success: true, Message: "Password reset successfully. Please log in with your new password.",
+	})
+}
+
+// IssueScopedToken godoc
+// @Summary Issue Scoped Token
+// @Description Issues a limited token for a client or integration acting as the logged-in user, e.g. a kiosk that may only punch attendance (scope attendance:punch: check-in, check-out, project switch and the project list) or a reporting integration that may only read (scope read: every GET endpoint the user's role allows). Other endpoints reject the token with 403. The token expires after ttl_hours (default 720, max 2160) or at the end of the user's access period, whichever is first, and is revoked together with all of the user's sessions. Requires a full session token.
+// @Tags User - Profile Management
+// @Accept json
+// @Produce json
+// @Param token body models.ScopedTokenInput true "Scopes, optional label and lifetime"
+// @Success 201 {object} models.Response{data=models.ScopedToken} "Scoped token issued"
+// @Failure 400 {object} models.Response "Validation failed or invalid request body"
+// @Failure 401 {object} models.Response "Unauthorized"
+// @Failure 403 {object} models.Response "Account inactive, access period ended or password reset required"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/tokens [post]
+func (h *AuthHandler) IssueScopedToken(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+
+	input := new(models.ScopedTokenInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid request body",
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	user, accessEnd, handled, resp := tokenOwner(c, h.UserRepo, h.Settings, userID)
+	if handled {
+		return resp
+	}
+
+	now := time.Now()
+	ttlHours := input.TTLHours
+	if ttlHours == 0 {
+		ttlHours = models.ScopedTokenDefaultTTLHours
+	}
+	expiresAt := now.Add(time.Duration(ttlHours) * time.Hour)
+	if accessEnd != nil && accessEnd.Before(expiresAt) {
+		expiresAt = *accessEnd
+	}
+	token, err := utils.GenerateScopedJWT(user.ID, user.Username, user.Role.Name, accessEnd, user.TokenVersion, input.Scopes, expiresAt)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", user.ID).Msg("Error generating scoped JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to issue token",
+		})
+	}
+
+	details := clientDetails(c)
+	details["scopes"] = input.Scopes
+	details["expires_at"] = expiresAt.UTC().Format(time.RFC3339)
+	if input.Label != "" {
+		details["label"] = input.Label
+	}
+	publishEvent(c, h.Events, events.Event{Name: events.ScopedTokenIssued, UserID: user.ID, ActorUserID: &user.ID, Data: details})
+
+	reqLogger(c).Info().Int("user_id", user.ID).Strs("scopes", input.Scopes).Time("expires_at", expiresAt).Msg("Scoped token issued")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Scoped token issued",
+		Data: models.ScopedToken{Token: token, Scopes: input.Scopes, Label: input.Label, ExpiresAt: expiresAt},
+	})
+}
+
+// tokenOwner membaca ulang user pemilik token turunan (token terbatas, token kiosk) agar role,
+// masa akses, dan token_version yang dipakai adalah yang terkini, bukan dari claims. Jika user
+// tidak boleh menerima token (nonaktif, masa akses berakhir, wajib reset password), response
+// error sudah ditulis dan handled bernilai true.
+func tokenOwner(c *fiber.Ctx, users repository.UserRepository, settingsStore *settings.Store, userID int) (user *models.User, accessEnd *time.Time, handled bool, resp error) {
+	user, err := users.GetUserByID(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, true, c.Status(fiber.StatusUnauthorized).JSON(models.Response{
+				Success: false, Message: "Unauthorized: User not found",
+			})
+		}
+		return nil, nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to issue token",
+		})
+	}
+	if user.Role == nil {
+		reqLogger(c).Warn().Int("user_id", user.ID).Msg("Role not loaded for user while issuing token")
+		return nil, nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to issue token",
+		})
+	}
+	_, accessEnd = user.AccessWindow(settingsStore.DefaultLocation(c.UserContext()))
+	if !user.IsActive || (accessEnd != nil && !time.Now().Before(*accessEnd)) || user.PasswordResetRequired {
+		return nil, nil, true, c.Status(fiber.StatusForbidden).JSON(models.Response{
+			Success: false, Message: "Account is not allowed to issue tokens",
+		})
+	}
+	return user, accessEnd, false, nil
+}
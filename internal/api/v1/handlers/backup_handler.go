@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/backup"
+	"github.com/rakaarfi/attendance-system-be/internal/jobs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/storage"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// BackupHandler melayani backup database: admin meminta backup, job backups menulis arsipnya
+// ke storage, dan arsip bisa diunduh untuk dipulihkan dengan cmd/restore.
+type BackupHandler struct {
+	BackupRepo repository.BackupRepository
+	Backups    *backup.Service
+	Scheduler  *jobs.Scheduler
+	Storage    storage.Storage
+	PresignTTL time.Duration // Masa berlaku URL unduhan langsung (STORAGE_PRESIGN_TTL)
+}
+
+// backupJobName adalah nama job backups di scheduler (jobs.BackupRun).
+const backupJobName = "backups"
+
+func NewBackupHandler(backupRepo repository.BackupRepository, service *backup.Service, scheduler *jobs.Scheduler, store storage.Storage) *BackupHandler {
+	return &BackupHandler{
+		BackupRepo: backupRepo,
+		Backups:    service,
+		Scheduler:  scheduler,
+		Storage:    store,
+		PresignTTL: configs.GetEnvDuration("STORAGE_PRESIGN_TTL", defaultPresignTTL),
+	}
+}
+
+// CreateBackup godoc
+// @Summary Request a database backup (Admin)
+// @Description Queues a consistent logical backup of the whole database, written in the background by job backups to the storage backend: a tar.gz with manifest.json and every table in PostgreSQL COPY format, read from a single REPEATABLE READ snapshot so the API keeps running. Poll GET /admin/maintenance/backups/{backupId} until status is completed, then download it and restore it with `go run ./cmd/restore` (see README). Only one backup can be pending or running at a time.
+// @Tags Admin - Maintenance
+// @Produce json
+// @Success 202 {object} models.Response{data=models.Backup} "Backup queued"
+// @Failure 409 {object} models.Response "Another backup is already pending or running"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Failure 501 {object} models.Response "Backups are not available (SANDBOX_MODE)"
+// @Security ApiKeyAuth
+// @Router /admin/maintenance/backup [post]
+func (h *BackupHandler) CreateBackup(c *fiber.Ctx) error {
+	if !h.Backups.Available() {
+		return c.Status(fiber.StatusNotImplemented).JSON(models.Response{Success: false, Message: "Backups are not available without a PostgreSQL database"})
+	}
+	adminID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	b := &models.Backup{RequestedBy: &adminID}
+	if err := h.BackupRepo.CreateBackup(c.UserContext(), b); err != nil {
+		if errors.Is(err, repository.ErrBackupInProgress) {
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Another backup is already pending or running"})
+		}
+		reqLogger(c).Error().Err(err).Msg("Failed to queue backup")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to queue backup"})
+	}
+	// Mulai segera alih-alih menunggu jadwal job; jika job dinonaktifkan di instance ini,
+	// backup tetap pending sampai diambil instance lain.
+	if err := h.Scheduler.Trigger(c.UserContext(), backupJobName); err != nil && !errors.Is(err, jobs.ErrUnknownJob) {
+		reqLogger(c).Warn().Err(err).Int("backup_id", b.ID).Msg("Failed to trigger backup job")
+	}
+	reqLogger(c).Info().Int("backup_id", b.ID).Msg("Backup queued")
+	return c.Status(fiber.StatusAccepted).JSON(models.Response{
+		Success: true, Message: "Backup queued", Data: b,
+	})
+}
+
+// GetBackups godoc
+// @Summary List database backups (Admin)
+// @Description Lists requested backups, newest first, with their status, size, table and row counts.
+// @Tags Admin - Maintenance
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.Response{data=[]models.Backup} "Backups retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/maintenance/backups [get]
+func (h *BackupHandler) GetBackups(c *fiber.Ctx) error {
+	pagination := utils.ParsePaginationParams(c)
+	backups, total, err := h.BackupRepo.GetBackups(c.UserContext(), pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get backups")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve backups"})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Backups retrieved successfully", backups, meta))
+}
+
+// GetBackup godoc
+// @Summary Get a database backup (Admin)
+// @Description Returns the status of one backup (pending, running, completed or failed with an error).
+// @Tags Admin - Maintenance
+// @Produce json
+// @Param backupId path int true "Backup ID"
+// @Success 200 {object} models.Response{data=models.Backup} "Backup retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid backup ID"
+// @Failure 404 {object} models.Response "Backup not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/maintenance/backups/{backupId} [get]
+func (h *BackupHandler) GetBackup(c *fiber.Ctx) error {
+	b, handled, resp := h.loadBackup(c)
+	if handled {
+		return resp
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Backup retrieved successfully", Data: b,
+	})
+}
+
+// DownloadBackup godoc
+// @Summary Download a database backup (Admin)
+// @Description Downloads a completed backup archive (tar.gz). With object storage (STORAGE_BACKEND=s3) the response is a 302 redirect to a short-lived presigned URL. Returns 409 while the backup is pending or running, or if it failed, and 410 Gone if the file was removed from storage.
+// @Tags Admin - Maintenance
+// @Produce application/gzip
+// @Param backupId path int true "Backup ID"
+// @Success 200 {file} file "Backup archive"
+// @Success 302 {string} string "Redirect to a presigned storage URL"
+// @Failure 400 {object} models.Response "Invalid backup ID"
+// @Failure 404 {object} models.Response "Backup not found"
+// @Failure 409 {object} models.Response "Backup is not ready or failed"
+// @Failure 410 {object} models.Response "Backup file no longer exists"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/maintenance/backups/{backupId}/download [get]
+func (h *BackupHandler) DownloadBackup(c *fiber.Ctx) error {
+	b, handled, resp := h.loadBackup(c)
+	if handled {
+		return resp
+	}
+	switch b.Status {
+	case models.BackupPending, models.BackupRunning:
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Backup is not ready yet", Data: b})
+	case models.BackupFailed:
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Backup failed", Data: b})
+	}
+	if b.StorageKey == nil || b.FileName == nil {
+		reqLogger(c).Error().Int("backup_id", b.ID).Msg("Completed backup has no file")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to download backup"})
+	}
+
+	if presigner, ok := h.Storage.(storage.Presigner); ok {
+		url, err := presigner.PresignGet(c.UserContext(), *b.StorageKey, h.PresignTTL, storage.GetOptions{FileName: *b.FileName, ContentType: backup.ContentType})
+		if err != nil {
+			reqLogger(c).Error().Err(err).Int("backup_id", b.ID).Str("backend", h.Storage.Backend()).Msg("Failed to presign backup download")
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to download backup"})
+		}
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+		return c.Redirect(url, fiber.StatusFound)
+	}
+
+	body, err := h.Storage.Open(c.UserContext(), *b.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return c.Status(fiber.StatusGone).JSON(models.Response{Success: false, Message: "Backup file no longer exists"})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("backup_id", b.ID).Str("backend", h.Storage.Backend()).Msg("Failed to open backup from storage")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to download backup"})
+	}
+	size := -1
+	if b.SizeBytes != nil {
+		size = int(*b.SizeBytes)
+	}
+	c.Attachment(*b.FileName)
+	c.Set(fiber.HeaderContentType, backup.ContentType)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	// Fiber menutup body (io.Closer) setelah response terkirim.
+	return c.Status(http.StatusOK).SendStream(body, size)
+}
+
+// loadBackup membaca backup dari path param backupId.
+func (h *BackupHandler) loadBackup(c *fiber.Ctx) (b *models.Backup, handled bool, resp error) {
+	id, err := idParam(c, "backupId")
+	if err != nil {
+		return nil, true, c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Backup ID parameter"})
+	}
+	b, err = h.BackupRepo.GetBackupByID(c.UserContext(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, true, c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Backup with ID %d not found", id),
+		})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("backup_id", id).Msg("Error loading backup")
+		return nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve backup"})
+	}
+	return b, false, nil
+}
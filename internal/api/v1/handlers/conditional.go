@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// Conditional GET untuk endpoint baca yang sering di-poll klien mobile.
+// Response membawa ETag (weak) yang dihitung dari id, versi, dan updated_at record (plus data
+// JOIN yang ikut tampil), dan Last-Modified untuk resource tunggal. Klien yang mengirim ulang
+// nilai itu lewat If-None-Match / If-Modified-Since menerima 304 tanpa body.
+// Listing tidak memakai Last-Modified karena record yang dihapus tidak memajukan updated_at.
+
+// etagOf menghitung weak ETag dari bagian-bagian validator. Pointer di-dereference (nil
+// ditulis sebagai "-") dan waktu ditulis dalam UnixNano agar zona waktu tidak berpengaruh.
+func etagOf(parts ...any) string {
+	h := sha256.New()
+	for _, part := range parts {
+		switch v := part.(type) {
+		case time.Time:
+			fmt.Fprintf(h, "%d|", v.UnixNano())
+		case *time.Time:
+			if v == nil {
+				fmt.Fprint(h, "-|")
+			} else {
+				fmt.Fprintf(h, "%d|", v.UnixNano())
+			}
+		case *int:
+			if v == nil {
+				fmt.Fprint(h, "-|")
+			} else {
+				fmt.Fprintf(h, "%d|", *v)
+			}
+		case *string:
+			if v == nil {
+				fmt.Fprint(h, "-|")
+			} else {
+				fmt.Fprintf(h, "%q|", *v)
+			}
+		case string:
+			fmt.Fprintf(h, "%q|", v)
+		default:
+			fmt.Fprintf(h, "%v|", v)
+		}
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// notModified menulis ETag, Last-Modified (jika lastModified diisi) dan Cache-Control, lalu
+// melaporkan apakah salinan klien masih berlaku. If-None-Match diutamakan; If-Modified-Since
+// hanya dipakai jika If-None-Match tidak dikirim (RFC 9110).
+func notModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+	if ifModifiedSince := c.Get(fiber.HeaderIfModifiedSince); ifModifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// etagMatches membandingkan daftar ETag If-None-Match dengan etag (perbandingan weak).
+func etagMatches(header, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// shiftsETag adalah ETag daftar shift (id, versi, updated_at).
+func shiftsETag(shifts []models.Shift) string {
+	parts := make([]any, 0, len(shifts)*3)
+	for _, s := range shifts {
+		parts = append(parts, s.ID, s.Version, s.UpdatedAt)
+	}
+	return etagOf(parts...)
+}
+
+// rolesETag adalah ETag daftar role. Role tidak punya versi/updated_at, jadi seluruh kolomnya dipakai.
+func rolesETag(roles []models.Role) string {
+	parts := make([]any, 0, len(roles)*3)
+	for _, r := range roles {
+		parts = append(parts, r.ID, r.Name, r.ParentID)
+	}
+	return etagOf(parts...)
+}
+
+// schedulesETag adalah ETag satu halaman jadwal: metadata pagination, id/versi/updated_at
+// jadwal, serta ringkasan shift (jam efektif termasuk override), user, dan absensi yang ikut
+// tampil di listing.
+func schedulesETag(schedules []models.UserSchedule, meta utils.PaginationMeta) string {
+	parts := []any{meta.CurrentPage, meta.PerPage, meta.TotalItems}
+	for _, s := range schedules {
+		parts = append(parts, s.ID, s.Version, s.UpdatedAt, s.UserID, s.ShiftID, s.Date, s.AttendanceStatus)
+		if sh := s.Shift; sh != nil {
+			parts = append(parts, sh.Name, sh.StartTime, sh.EndTime, sh.OverrideID)
+		}
+		if u := s.User; u != nil {
+			parts = append(parts, u.Username, u.Email, u.FirstName, u.LastName, u.UserType, u.ValidUntil, u.IsActive, u.EmploymentStatus)
+		}
+		if a := s.Attendance; a != nil {
+			parts = append(parts, a.ID, a.UpdatedAt)
+		}
+	}
+	return etagOf(parts...)
+}
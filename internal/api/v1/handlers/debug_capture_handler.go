@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// DebugCaptureHandler menampilkan rekaman request/response yang diambil middleware
+// CaptureRequests untuk route/user yang diaktifkan lewat pengaturan debug.capture_*.
+type DebugCaptureHandler struct {
+	Repo repository.DebugCaptureRepository
+}
+
+func NewDebugCaptureHandler(repo repository.DebugCaptureRepository) *DebugCaptureHandler {
+	return &DebugCaptureHandler{Repo: repo}
+}
+
+// GetDebugCaptures godoc
+// @Summary List debug captures (Admin)
+// @Description Lists recorded API requests that have not expired, newest first, without headers and bodies. Recording is switched on at runtime through the settings debug.capture_routes (route patterns such as "POST /api/v1/user/attendance/checkin" or "/api/v1/user/*") and debug.capture_users (user IDs); records are kept for debug.capture_ttl_hours.
+// @Tags Admin - Monitoring
+// @Produce json
+// @Param user_id query int false "Only requests made by this user"
+// @Param route query string false "Only this route pattern, as registered (e.g. /api/v1/user/schedules/:scheduleId)"
+// @Param method query string false "Only this HTTP method"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.Response{data=[]models.DebugCapture} "Debug captures retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid filter"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/debug/captures [get]
+func (h *DebugCaptureHandler) GetDebugCaptures(c *fiber.Ctx) error {
+	filter := models.DebugCaptureFilter{Route: c.Query("route"), Method: strings.ToUpper(c.Query("method"))}
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "Invalid user_id query parameter",
+			})
+		}
+		filter.UserID = &id
+	}
+
+	pagination := utils.ParsePaginationParams(c)
+	captures, total, err := h.Repo.GetDebugCaptures(c.UserContext(), filter, pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get debug captures")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve debug captures"})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Debug captures retrieved successfully", captures, meta))
+}
+
+// GetDebugCapture godoc
+// @Summary Get a debug capture (Admin)
+// @Description Returns one recorded API request with its request and response headers and bodies. Credentials (authorization, cookies, passwords, tokens, ...) and personal data (email, phone) are redacted; JSON and form bodies are redacted per field, other binary or file bodies are replaced by their size, and long bodies are truncated.
+// @Tags Admin - Monitoring
+// @Produce json
+// @Param captureId path int true "Debug capture ID"
+// @Success 200 {object} models.Response{data=models.DebugCapture} "Debug capture retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid capture ID"
+// @Failure 404 {object} models.Response "Debug capture not found or expired"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/debug/captures/{captureId} [get]
+func (h *DebugCaptureHandler) GetDebugCapture(c *fiber.Ctx) error {
+	id, err := idParam(c, "captureId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Capture ID parameter"})
+	}
+	capture, err := h.Repo.GetDebugCaptureByID(c.UserContext(), int64(id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Debug capture with ID %d not found", id),
+		})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("debug_capture_id", id).Msg("Error loading debug capture")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve debug capture"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Debug capture retrieved successfully", Data: capture,
+	})
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// DelegationHandler melayani delegasi wewenang approval: atasan yang cuti melimpahkan
+// sign-off timnya ke user lain untuk rentang tanggal. Endpoint approval atasan menerima
+// on_behalf_of dari pemegang delegasi aktif (lihat SignOffHandler.actingManager).
+type DelegationHandler struct {
+	DelegationRepo repository.DelegationRepository
+	Events         events.Publisher // Audit pembuatan & pencabutan delegasi
+	Validate       *validator.Validate
+}
+
+func NewDelegationHandler(delegationRepo repository.DelegationRepository, eventBus events.Publisher) *DelegationHandler {
+	return &DelegationHandler{
+		DelegationRepo: delegationRepo,
+		Events:         eventBus,
+		Validate:       validator.New(),
+	}
+}
+
+// CreateDelegation godoc
+// @Summary Delegate my approvals
+// @Description Hands the current user's approval authority (team attendance sign-off and the unsigned days list) to another active user from start_date to end_date inclusive, e.g. during a vacation. The delegate acts with on_behalf_of set to the delegating user; every delegated action is audited with both users.
+// @Tags User - Delegations
+// @Accept json
+// @Produce json
+// @Param delegation body models.DelegationInput true "Delegate and date range"
+// @Success 201 {object} models.Response{data=models.ApprovalDelegation} "Delegation created successfully"
+// @Failure 400 {object} models.Response "Validation failed, invalid date range or self-delegation"
+// @Failure 404 {object} models.Response "Delegate not found or inactive"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/delegations [post]
+func (h *DelegationHandler) CreateDelegation(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	input := new(models.DelegationInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	if input.DelegateID == userID {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Cannot delegate to yourself"})
+	}
+	startDate, _ := time.ParseInLocation(defaultDateFormat, input.StartDate, time.Local)
+	endDate, _ := time.ParseInLocation(defaultDateFormat, input.EndDate, time.Local)
+	if endDate.Before(startDate) {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "end_date must not be before start_date"})
+	}
+	if endDate.Before(startOfToday()) {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "end_date must not be in the past"})
+	}
+
+	delegation, err := h.DelegationRepo.CreateDelegation(c.UserContext(), userID, *input)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Active user with ID %d not found", input.DelegateID),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("delegate_id", input.DelegateID).Msg("Failed to create approval delegation")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create delegation"})
+	}
+	publishEvent(c, h.Events, events.Event{
+		Name: events.DelegationCreated, UserID: userID,
+		Data: map[string]any{
+			"delegation_id": delegation.ID, "delegate_id": delegation.DelegateID,
+			"start_date": delegation.StartDate, "end_date": delegation.EndDate,
+		},
+	})
+
+	reqLogger(c).Info().Int("delegation_id", delegation.ID).Int("delegate_id", delegation.DelegateID).Msg("Approval delegation created")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Delegation created successfully", Data: delegation,
+	})
+}
+
+// GetMyDelegations godoc
+// @Summary Get my delegations
+// @Description Lists approval delegations the current user has given or received, newest first, including revoked ones.
+// @Tags User - Delegations
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.ApprovalDelegation} "Delegations retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/delegations [get]
+func (h *DelegationHandler) GetMyDelegations(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	delegations, err := h.DelegationRepo.GetDelegationsByUser(c.UserContext(), userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get approval delegations")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve delegations"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Delegations retrieved successfully", Data: delegations,
+	})
+}
+
+// RevokeDelegation godoc
+// @Summary Revoke a delegation
+// @Description Ends a delegation the current user gave before its end date; the delegate loses the authority immediately.
+// @Tags User - Delegations
+// @Produce json
+// @Param delegationId path int true "Delegation ID"
+// @Success 200 {object} models.Response{data=models.ApprovalDelegation} "Delegation revoked successfully"
+// @Failure 400 {object} models.Response "Invalid delegation ID"
+// @Failure 404 {object} models.Response "Delegation not found"
+// @Failure 409 {object} models.Response "Delegation already revoked"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/delegations/{delegationId} [delete]
+func (h *DelegationHandler) RevokeDelegation(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	id, err := strconv.Atoi(c.Params("delegationId"))
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Delegation ID parameter"})
+	}
+
+	delegation, err := h.DelegationRepo.RevokeDelegation(c.UserContext(), id, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Delegation with ID %d not found", id),
+			})
+		case errors.Is(err, repository.ErrDelegationRevoked):
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+		}
+		reqLogger(c).Error().Err(err).Int("delegation_id", id).Msg("Failed to revoke approval delegation")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to revoke delegation"})
+	}
+	publishEvent(c, h.Events, events.Event{
+		Name: events.DelegationRevoked, UserID: userID,
+		Data: map[string]any{"delegation_id": delegation.ID, "delegate_id": delegation.DelegateID},
+	})
+
+	reqLogger(c).Info().Int("delegation_id", id).Msg("Approval delegation revoked")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Delegation revoked successfully", Data: delegation,
+	})
+}
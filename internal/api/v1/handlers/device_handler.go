@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// DeviceHandler melayani pendaftaran token perangkat untuk push notification (FCM/APNs).
+type DeviceHandler struct {
+	DeviceRepo repository.DeviceRepository
+	Validate   *validator.Validate
+}
+
+func NewDeviceHandler(deviceRepo repository.DeviceRepository) *DeviceHandler {
+	return &DeviceHandler{
+		DeviceRepo: deviceRepo,
+		Validate:   validator.New(),
+	}
+}
+
+// RegisterDevice godoc
+// @Summary Register a device for push notifications
+// @Description Stores the FCM or APNs token of the current user's device. Schedule changes, shift reminders and dispute decisions are pushed to every registered device. Registering a token that belongs to another account moves it to the current user.
+// @Tags User - Devices
+// @Accept json
+// @Produce json
+// @Param device body models.RegisterDeviceInput true "Platform and device token"
+// @Success 201 {object} models.Response{data=models.DeviceToken} "Device registered successfully"
+// @Failure 400 {object} models.Response "Validation failed"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/devices [post]
+func (h *DeviceHandler) RegisterDevice(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+
+	input := new(models.RegisterDeviceInput)
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Error parsing register device request body")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	device, err := h.DeviceRepo.RegisterDeviceToken(c.UserContext(), userID, input.Platform, input.Token)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to register device")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to register device"})
+	}
+
+	reqLogger(c).Info().Int("user_id", userID).Int("device_id", device.ID).Str("platform", device.Platform).Msg("Device registered for push notifications")
+	return c.Status(http.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Device registered successfully", Data: device,
+	})
+}
+
+// UnregisterDevice godoc
+// @Summary Unregister a device
+// @Description Removes a device token of the current user (e.g. on logout) so it no longer receives push notifications.
+// @Tags User - Devices
+// @Produce json
+// @Param token path string true "Device token"
+// @Success 200 {object} models.Response "Device unregistered successfully"
+// @Failure 404 {object} models.Response "Device token not registered for this user"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/devices/{token} [delete]
+func (h *DeviceHandler) UnregisterDevice(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+
+	if err := h.DeviceRepo.DeleteDeviceToken(c.UserContext(), userID, c.Params("token")); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "Device not registered"})
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to unregister device")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to unregister device"})
+	}
+
+	reqLogger(c).Info().Int("user_id", userID).Msg("Device unregistered")
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Device unregistered successfully"})
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// DisputeHandler melayani dispute absensi: karyawan menyanggah record absensinya sendiri
+// (atasan langsung dinotifikasi), admin meninjau antrean dispute dan menyelesaikannya.
+type DisputeHandler struct {
+	DisputeRepo repository.DisputeRepository
+	Events      events.Publisher     // Notifikasi atasan/karyawan & audit lewat subscriber
+	Tx          repository.TxManager // Dispute & event outbox dalam satu transaksi
+	Validate    *validator.Validate
+}
+
+func NewDisputeHandler(disputeRepo repository.DisputeRepository, eventBus events.Publisher, txManager repository.TxManager) *DisputeHandler {
+	return &DisputeHandler{
+		DisputeRepo: disputeRepo,
+		Events:      eventBus,
+		Tx:          txManager,
+		Validate:    validator.New(),
+	}
+}
+
+// disputeIDParam membaca path param :disputeId.
+func disputeIDParam(c *fiber.Ctx) (int, error) {
+	idStr := c.Params("disputeId")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		reqLogger(c).Warn().Str("disputeId_param", idStr).Msg("Invalid Dispute ID parameter")
+		return 0, fmt.Errorf("invalid dispute ID parameter %q", idStr)
+	}
+	return id, nil
+}
+
+// CreateDispute godoc
+// @Summary Dispute an attendance record
+// @Description Flags one of the current user's attendance records as disputed with a comment. The user's manager (or HR when the user has no manager) is notified. A record can have only one open dispute at a time.
+// @Tags User - Check In/Out
+// @Accept json
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Param dispute body models.DisputeInput true "Why the record is wrong"
+// @Success 201 {object} models.Response{data=models.AttendanceDispute} "Dispute created successfully"
+// @Failure 400 {object} models.Response "Invalid ID or validation failed"
+// @Failure 404 {object} models.Response "Attendance record not found"
+// @Failure 409 {object} models.Response "Record already has an open dispute"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/attendance/{attendanceId}/dispute [post]
+func (h *DisputeHandler) CreateDispute(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	attendanceID, err := strconv.Atoi(c.Params("attendanceId"))
+	if err != nil || attendanceID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Attendance ID parameter"})
+	}
+	input := new(models.DisputeInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	var dispute *models.AttendanceDispute
+	err = runInTx(c, h.Tx, func() error {
+		var err error
+		if dispute, err = h.DisputeRepo.CreateDispute(c.UserContext(), attendanceID, userID, input.Comment); err != nil {
+			return err
+		}
+		publishEvent(c, h.Events, events.Event{
+			Name: events.DisputeOpened, UserID: userID,
+			Data: map[string]any{"dispute_id": dispute.ID, "attendance_id": dispute.AttendanceID, "comment": dispute.Comment},
+		})
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			// Record milik user lain diperlakukan sama dengan record yang tidak ada.
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Attendance record with ID %d not found", attendanceID),
+			})
+		case errors.Is(err, repository.ErrDisputeAlreadyOpen):
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+		}
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceID).Msg("Failed to create attendance dispute")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create dispute"})
+	}
+
+	reqLogger(c).Info().Int("dispute_id", dispute.ID).Int("attendance_id", attendanceID).Msg("Attendance dispute created")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Dispute created successfully", Data: dispute,
+	})
+}
+
+// GetMyDisputes godoc
+// @Summary Get my attendance disputes
+// @Description Lists the current user's attendance disputes with their resolution status, newest first.
+// @Tags User - Check In/Out
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.AttendanceDispute} "Disputes retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/attendance/disputes [get]
+func (h *DisputeHandler) GetMyDisputes(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	disputes, err := h.DisputeRepo.GetDisputesByUser(c.UserContext(), userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get user disputes")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve disputes"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Disputes retrieved successfully", Data: disputes,
+	})
+}
+
+// GetAllDisputes godoc
+// @Summary Get attendance disputes
+// @Description Lists attendance disputes, oldest first so the queue is worked in order. Defaults to open disputes; status=all returns every status.
+// @Tags Admin - Attendance Management
+// @Produce json
+// @Param status query string false "Dispute status (default open)" Enums(open, resolved, rejected, all)
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Limit of disputes per page"
+// @Success 200 {object} models.Response{data=[]models.AttendanceDispute} "Disputes retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid status"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/disputes [get]
+func (h *DisputeHandler) GetAllDisputes(c *fiber.Ctx) error {
+	status := c.Query("status", models.DisputeOpen)
+	switch status {
+	case models.DisputeOpen, models.DisputeResolved, models.DisputeRejected:
+	case "all":
+		status = ""
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid status, expected one of: open, resolved, rejected, all",
+		})
+	}
+	pagination := utils.ParsePaginationParams(c)
+
+	disputes, total, err := h.DisputeRepo.GetAllDisputes(c.UserContext(), status, pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get disputes")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve disputes"})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Disputes retrieved successfully", disputes, meta))
+}
+
+// ResolveDispute godoc
+// @Summary Resolve attendance dispute
+// @Description Closes an open dispute as resolved or rejected with a note; the employee is notified. Fix the record itself through the corrections endpoint.
+// @Tags Admin - Attendance Management
+// @Accept json
+// @Produce json
+// @Param disputeId path int true "Dispute ID"
+// @Param resolution body models.ResolveDisputeInput true "Outcome and note"
+// @Success 200 {object} models.Response{data=models.AttendanceDispute} "Dispute resolved successfully"
+// @Failure 400 {object} models.Response "Invalid ID or validation failed"
+// @Failure 404 {object} models.Response "Dispute not found"
+// @Failure 409 {object} models.Response "Dispute is no longer open"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/disputes/{disputeId}/resolve [post]
+func (h *DisputeHandler) ResolveDispute(c *fiber.Ctx) error {
+	id, err := disputeIDParam(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Dispute ID parameter", Data: err.Error()})
+	}
+	adminUserID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting admin userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	input := new(models.ResolveDisputeInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	dispute, err := resolveDispute(c, h.DisputeRepo, h.Events, h.Tx, id, adminUserID, input.Status, input.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Dispute with ID %d not found", id),
+			})
+		case errors.Is(err, repository.ErrDisputeNotOpen):
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+		}
+		reqLogger(c).Error().Err(err).Int("dispute_id", id).Msg("Failed to resolve dispute")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to resolve dispute"})
+	}
+
+	reqLogger(c).Info().Int("dispute_id", id).Str("status", dispute.Status).Int("admin_id", adminUserID).Msg("Attendance dispute resolved")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Dispute resolved successfully", Data: dispute,
+	})
+}
+
+// resolveDispute menutup dispute dan mem-publish DisputeResolved dalam satu transaksi.
+// Dipakai ResolveDispute dan bulk approval inbox.
+func resolveDispute(c *fiber.Ctx, repo repository.DisputeRepository, bus events.Publisher, tx repository.TxManager, id, actorUserID int, status, note string) (*models.AttendanceDispute, error) {
+	var dispute *models.AttendanceDispute
+	err := runInTx(c, tx, func() error {
+		var err error
+		if dispute, err = repo.ResolveDispute(c.UserContext(), id, actorUserID, status, note); err != nil {
+			return err
+		}
+		publishEvent(c, bus, events.Event{
+			Name: events.DisputeResolved, UserID: dispute.UserID,
+			Data: map[string]any{"dispute_id": dispute.ID, "attendance_id": dispute.AttendanceID, "status": dispute.Status, "note": note},
+		})
+		return nil
+	})
+	return dispute, err
+}
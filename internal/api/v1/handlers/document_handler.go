@@ -0,0 +1,738 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/faceverify"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/storage"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+	"github.com/rakaarfi/attendance-system-be/internal/virusscan"
+)
+
+// DocumentUploadField adalah nama field form multipart untuk file dokumen.
+const DocumentUploadField = "file"
+
+// Default unggahan/unduhan langsung ke storage (backend yang mendukung storage.Presigner).
+const (
+	defaultPresignTTL            = 15 * time.Minute
+	defaultDocumentDirectMaxSize = 50 * 1024 * 1024
+)
+
+// DocumentHandler melayani dokumen pendukung (surat sakit, izin) yang dilampirkan user
+// ke record absensi, serta akses admin untuk melihat/mengunduhnya saat meninjau koreksi.
+// Validasi tipe & ukuran file dilakukan middleware.ValidateUpload pada route. Jika storage
+// mendukung URL presigned (S3), file besar bisa diunggah/diunduh langsung tanpa melewati API.
+type DocumentHandler struct {
+	DocumentRepo   repository.DocumentRepository
+	AttendanceRepo repository.AttendanceRepository
+	Storage        storage.Storage
+	Scanner        virusscan.Scanner // nil = pemindaian dinonaktifkan
+	PresignTTL     time.Duration     // Masa berlaku URL unggah/unduh langsung (STORAGE_PRESIGN_TTL)
+	DirectMaxBytes int64             // Batas ukuran unggahan langsung (DOCUMENT_DIRECT_MAX_BYTES)
+	Validate       *validator.Validate
+}
+
+func NewDocumentHandler(docRepo repository.DocumentRepository, attRepo repository.AttendanceRepository, store storage.Storage, scanner virusscan.Scanner) *DocumentHandler {
+	return &DocumentHandler{
+		DocumentRepo:   docRepo,
+		AttendanceRepo: attRepo,
+		Storage:        store,
+		Scanner:        scanner,
+		PresignTTL:     configs.GetEnvDuration("STORAGE_PRESIGN_TTL", defaultPresignTTL),
+		DirectMaxBytes: int64(configs.GetEnvInt("DOCUMENT_DIRECT_MAX_BYTES", defaultDocumentDirectMaxSize)),
+		Validate:       validator.New(),
+	}
+}
+
+// idParam membaca path param numerik positif.
+func idParam(c *fiber.Ctx, name string) (int, error) {
+	id, err := strconv.Atoi(c.Params(name))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s parameter", name)
+	}
+	return id, nil
+}
+
+// newDocumentKey membuat storage key acak; nama file asli tidak dipakai di key.
+func newDocumentKey(now time.Time) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("documents/%s/%s", now.UTC().Format("2006/01"), hex.EncodeToString(b)), nil
+}
+
+// sanitizeFileName membuang path & karakter kontrol dari nama file klien.
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		name = "document"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
+
+// sniffContentType mendeteksi tipe MIME dari byte awal file lalu kembali ke awal file.
+func sniffContentType(f multipart.File) (string, error) {
+	head := make([]byte, 512)
+	n, err := f.Read(head)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	return contentType, nil
+}
+
+// saveUpload memindai (jika ada pemindai) lalu menyimpan file field DocumentUploadField ke
+// storage beserta metadatanya sebagai dokumen subjectType/subjectID milik userID. Jika gagal,
+// response error sudah dikirim dan doc nil; kembalikan error-nya dari handler.
+func (h *DocumentHandler) saveUpload(c *fiber.Ctx, userID int, subjectType string, subjectID int) (*models.Document, error) {
+	fh, err := c.FormFile(DocumentUploadField)
+	if err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("File field '%s' is required", DocumentUploadField),
+		})
+	}
+	f, err := fh.Open()
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to open uploaded document")
+		return nil, c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to read uploaded file"})
+	}
+	defer f.Close()
+
+	contentType, err := sniffContentType(f)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to read uploaded document")
+		return nil, c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to read uploaded file"})
+	}
+
+	// --- Pemindaian malware (hook opsional) ---
+	scanStatus := models.DocumentScanSkipped
+	if h.Scanner != nil {
+		if err := h.Scanner.Scan(c.UserContext(), f); err != nil {
+			return nil, h.scanFailed(c, err, userID, fh.Filename)
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			reqLogger(c).Error().Err(err).Msg("Failed to rewind uploaded document after scan")
+			return nil, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to upload document"})
+		}
+		scanStatus = models.DocumentScanClean
+	}
+
+	// --- Simpan ke storage (hash dihitung sambil menulis) ---
+	key, err := newDocumentKey(time.Now())
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to generate document storage key")
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to upload document"})
+	}
+	hash := sha256.New()
+	if err := h.Storage.Put(c.UserContext(), key, io.TeeReader(f, hash), contentType); err != nil {
+		reqLogger(c).Error().Err(err).Str("backend", h.Storage.Backend()).Msg("Failed to store uploaded document")
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to upload document"})
+	}
+
+	doc := &models.Document{
+		OwnerUserID: userID,
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		FileName:    sanitizeFileName(fh.Filename),
+		ContentType: contentType,
+		SizeBytes:   fh.Size,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:  key,
+		ScanStatus:  scanStatus,
+	}
+	if _, err := h.DocumentRepo.CreateDocument(c.UserContext(), doc); err != nil {
+		// Jangan tinggalkan file yatim di storage jika metadata gagal disimpan.
+		if delErr := h.Storage.Delete(c.UserContext(), key); delErr != nil {
+			reqLogger(c).Warn().Err(delErr).Str("storage_key", key).Msg("Failed to remove orphaned document from storage")
+		}
+		reqLogger(c).Error().Err(err).Str("subject_type", subjectType).Int("subject_id", subjectID).Msg("Failed to save document metadata")
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to upload document"})
+	}
+	return doc, nil
+}
+
+// scanFailed mengirim response untuk file yang ditolak pemindai (422) atau gagal dipindai (503).
+func (h *DocumentHandler) scanFailed(c *fiber.Ctx, err error, userID int, fileName string) error {
+	if errors.Is(err, virusscan.ErrInfected) {
+		reqLogger(c).Warn().Err(err).Int("user_id", userID).Str("filename", fileName).Msg("Uploaded document rejected by malware scan")
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(models.Response{
+			Success: false, Message: "File was rejected by the malware scan",
+		})
+	}
+	reqLogger(c).Error().Err(err).Str("scanner", h.Scanner.Provider()).Msg("Malware scan failed")
+	return c.Status(fiber.StatusServiceUnavailable).JSON(models.Response{
+		Success: false, Message: "File could not be scanned, please try again later",
+	})
+}
+
+// ownAttendance memastikan record absensi ada dan milik userID; record milik user lain
+// dijawab 404. handled true berarti response sudah dikirim.
+func (h *DocumentHandler) ownAttendance(c *fiber.Ctx, userID, attendanceID int, failMessage string) (handled bool, resp error) {
+	att, err := h.AttendanceRepo.GetAttendanceByID(c.UserContext(), attendanceID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceID).Msg("Error loading attendance for document upload")
+		return true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: failMessage})
+	}
+	if att == nil || att.UserID != userID {
+		return true, c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Attendance with ID %d not found", attendanceID),
+		})
+	}
+	return false, nil
+}
+
+// UploadAttendanceDocument godoc
+// @Summary Attach a document to my attendance record
+// @Description Uploads a supporting document (PDF, JPEG, PNG) such as a sick note or permit for one of the current user's attendance records. Files are scanned for malware when a scanner is configured.
+// @Tags User - Documents
+// @Accept multipart/form-data
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Param file formData file true "Document file"
+// @Success 201 {object} models.Response{data=models.Document} "Document uploaded successfully"
+// @Failure 400 {object} models.Response "Invalid attendance ID or missing file"
+// @Failure 404 {object} models.Response "Attendance not found"
+// @Failure 413 {object} models.Response "File too large"
+// @Failure 415 {object} models.Response "Unsupported file type"
+// @Failure 422 {object} models.Response "File rejected by malware scan"
+// @Failure 503 {object} models.Response "Malware scanner unavailable"
+// @Security ApiKeyAuth
+// @Router /user/attendance/{attendanceId}/documents [post]
+func (h *DocumentHandler) UploadAttendanceDocument(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	attendanceID, err := idParam(c, "attendanceId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Attendance ID parameter"})
+	}
+
+	// Hanya pemilik record yang boleh melampirkan dokumen.
+	if handled, resp := h.ownAttendance(c, userID, attendanceID, "Failed to upload document"); handled {
+		return resp
+	}
+
+	doc, err := h.saveUpload(c, userID, models.DocumentSubjectAttendance, attendanceID)
+	if doc == nil {
+		return err // Response error sudah dikirim saveUpload
+	}
+
+	reqLogger(c).Info().Int("document_id", doc.ID).Int("attendance_id", attendanceID).Int64("size", doc.SizeBytes).Str("scan_status", doc.ScanStatus).Msg("Document uploaded")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Document uploaded successfully", Data: doc,
+	})
+}
+
+// CreateAttendanceDocumentUpload godoc
+// @Summary Request a direct upload URL for a document
+// @Description For large files: returns a presigned request that uploads the file straight to object storage, bypassing the API. Send it exactly as given (method, url and all headers); storage rejects a file whose size or SHA-256 differs from what was declared. Then call POST /user/attendance/{attendanceId}/documents/complete with the upload_token before it expires (STORAGE_PRESIGN_TTL, default 15 minutes). Files up to DOCUMENT_DIRECT_MAX_BYTES (default 50 MiB). Returns 501 when the storage backend does not support direct uploads (local disk); use the multipart upload instead.
+// @Tags User - Documents
+// @Accept json
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Param upload body models.DocumentUploadInput true "File name, type, size and SHA-256 (hex)"
+// @Success 200 {object} models.Response{data=models.DocumentUpload} "Upload URL created successfully"
+// @Failure 400 {object} models.Response "Invalid attendance ID or validation failed"
+// @Failure 404 {object} models.Response "Attendance not found"
+// @Failure 413 {object} models.Response "File too large"
+// @Failure 501 {object} models.Response "Direct uploads not supported by the storage backend"
+// @Security ApiKeyAuth
+// @Router /user/attendance/{attendanceId}/documents/upload-url [post]
+func (h *DocumentHandler) CreateAttendanceDocumentUpload(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	attendanceID, err := idParam(c, "attendanceId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Attendance ID parameter"})
+	}
+	presigner, ok := h.Storage.(storage.Presigner)
+	if !ok {
+		return directUploadUnsupported(c)
+	}
+	input := new(models.DocumentUploadInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Cannot parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Validation failed", Data: err.Error()})
+	}
+	if input.SizeBytes > h.DirectMaxBytes {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("File '%s' exceeds the maximum size of %d bytes", input.FileName, h.DirectMaxBytes),
+		})
+	}
+	if handled, resp := h.ownAttendance(c, userID, attendanceID, "Failed to create upload URL"); handled {
+		return resp
+	}
+
+	key, err := newDocumentKey(time.Now())
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to generate document storage key")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create upload URL"})
+	}
+	sha := strings.ToLower(input.SHA256)
+	upload, err := presigner.PresignPut(c.UserContext(), key, h.PresignTTL, storage.PutOptions{
+		ContentType: input.ContentType, SizeBytes: input.SizeBytes, SHA256: sha,
+	})
+	if err != nil {
+		reqLogger(c).Error().Err(err).Str("backend", h.Storage.Backend()).Msg("Failed to presign document upload")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create upload URL"})
+	}
+	token, err := utils.GenerateUploadToken(utils.UploadClaims{
+		UserID: userID, SubjectType: models.DocumentSubjectAttendance, SubjectID: attendanceID,
+		FileName: sanitizeFileName(input.FileName), ContentType: input.ContentType, SizeBytes: input.SizeBytes, SHA256: sha,
+	}, key, upload.ExpiresAt)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to sign document upload token")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create upload URL"})
+	}
+
+	reqLogger(c).Info().Int("attendance_id", attendanceID).Int64("size", input.SizeBytes).Msg("Direct document upload URL issued")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Upload URL created successfully",
+		Data: models.DocumentUpload{UploadToken: token, Method: upload.Method, URL: upload.URL, Headers: upload.Headers, ExpiresAt: upload.ExpiresAt},
+	})
+}
+
+// CompleteAttendanceDocumentUpload godoc
+// @Summary Complete a direct document upload
+// @Description Registers a document uploaded through POST /user/attendance/{attendanceId}/documents/upload-url. The stored file is checked against the declared size and type and scanned for malware when a scanner is configured; rejected files are deleted from storage.
+// @Tags User - Documents
+// @Accept json
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Param upload body models.CompleteDocumentUploadInput true "Upload token"
+// @Success 201 {object} models.Response{data=models.Document} "Document uploaded successfully"
+// @Failure 400 {object} models.Response "Invalid attendance ID, validation failed or invalid/expired upload token"
+// @Failure 404 {object} models.Response "Attendance not found"
+// @Failure 409 {object} models.Response "File not uploaded yet, or upload already completed"
+// @Failure 415 {object} models.Response "File content does not match the declared type"
+// @Failure 422 {object} models.Response "File rejected by malware scan or size mismatch"
+// @Failure 501 {object} models.Response "Direct uploads not supported by the storage backend"
+// @Failure 503 {object} models.Response "Malware scanner unavailable"
+// @Security ApiKeyAuth
+// @Router /user/attendance/{attendanceId}/documents/complete [post]
+func (h *DocumentHandler) CompleteAttendanceDocumentUpload(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	attendanceID, err := idParam(c, "attendanceId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Attendance ID parameter"})
+	}
+	presigner, ok := h.Storage.(storage.Presigner)
+	if !ok {
+		return directUploadUnsupported(c)
+	}
+	input := new(models.CompleteDocumentUploadInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Cannot parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Validation failed", Data: err.Error()})
+	}
+	claims, err := utils.ParseUploadToken(input.UploadToken)
+	if err != nil || claims.UserID != userID || claims.SubjectType != models.DocumentSubjectAttendance || claims.SubjectID != attendanceID {
+		reqLogger(c).Warn().Err(err).Int("attendance_id", attendanceID).Msg("Invalid document upload token")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid or expired upload token"})
+	}
+	if handled, resp := h.ownAttendance(c, userID, attendanceID, "Failed to upload document"); handled {
+		return resp
+	}
+	key := claims.Subject
+	ctx := c.UserContext()
+
+	info, err := presigner.Stat(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "File has not been uploaded yet"})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Str("backend", h.Storage.Backend()).Msg("Failed to stat directly uploaded document")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to upload document"})
+	}
+	reject := func(status int, message string) error {
+		if err := h.Storage.Delete(ctx, key); err != nil {
+			reqLogger(c).Warn().Err(err).Str("storage_key", key).Msg("Failed to remove rejected document from storage")
+		}
+		return c.Status(status).JSON(models.Response{Success: false, Message: message})
+	}
+	if info.Size != claims.SizeBytes {
+		return reject(fiber.StatusUnprocessableEntity, "Uploaded file size does not match the declared size")
+	}
+
+	// Tipe konten dari header unggahan tidak dipercaya; sniff byte awal file seperti ValidateUpload.
+	contentType, err := h.sniffStored(ctx, key)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to read directly uploaded document")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to upload document"})
+	}
+	if contentType != claims.ContentType {
+		reqLogger(c).Warn().Str("declared", claims.ContentType).Str("detected", contentType).Msg("Directly uploaded document type mismatch")
+		return reject(fiber.StatusUnsupportedMediaType, "File content does not match the declared type")
+	}
+
+	scanStatus := models.DocumentScanSkipped
+	if h.Scanner != nil {
+		// Pemindaian membaca file dari storage; ini satu-satunya saat isinya melewati API.
+		body, err := h.Storage.Open(ctx, key)
+		if err != nil {
+			reqLogger(c).Error().Err(err).Msg("Failed to open directly uploaded document for scanning")
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to upload document"})
+		}
+		err = h.Scanner.Scan(ctx, body)
+		body.Close()
+		if err != nil {
+			if errors.Is(err, virusscan.ErrInfected) {
+				if delErr := h.Storage.Delete(ctx, key); delErr != nil {
+					reqLogger(c).Warn().Err(delErr).Str("storage_key", key).Msg("Failed to remove rejected document from storage")
+				}
+			}
+			return h.scanFailed(c, err, userID, claims.FileName)
+		}
+		scanStatus = models.DocumentScanClean
+	}
+
+	doc := &models.Document{
+		OwnerUserID: userID,
+		SubjectType: claims.SubjectType,
+		SubjectID:   claims.SubjectID,
+		FileName:    claims.FileName,
+		ContentType: contentType,
+		SizeBytes:   info.Size,
+		SHA256:      claims.SHA256, // Diverifikasi storage saat unggah (checksum ditandatangani)
+		StorageKey:  key,
+		ScanStatus:  scanStatus,
+	}
+	if _, err := h.DocumentRepo.CreateDocument(ctx, doc); err != nil {
+		if errors.Is(err, repository.ErrDocumentExists) {
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Upload has already been completed"})
+		}
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceID).Msg("Failed to save directly uploaded document metadata")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to upload document"})
+	}
+
+	reqLogger(c).Info().Int("document_id", doc.ID).Int("attendance_id", attendanceID).Int64("size", doc.SizeBytes).Str("scan_status", doc.ScanStatus).Msg("Document uploaded directly to storage")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Document uploaded successfully", Data: doc,
+	})
+}
+
+// sniffStored mendeteksi tipe MIME dari 512 byte pertama objek di storage.
+func (h *DocumentHandler) sniffStored(ctx context.Context, key string) (string, error) {
+	body, err := h.Storage.Open(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	return contentType, nil
+}
+
+// directUploadUnsupported menjawab 501 untuk backend storage tanpa URL presigned (local).
+func directUploadUnsupported(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotImplemented).JSON(models.Response{
+		Success: false, Message: "Direct uploads are not supported by the configured storage backend; upload the file as multipart form data instead",
+	})
+}
+
+// listAttendanceDocuments mengembalikan dokumen satu record absensi; ownerID > 0 membatasi ke pemilik.
+func (h *DocumentHandler) listAttendanceDocuments(c *fiber.Ctx, ownerID int) error {
+	attendanceID, err := idParam(c, "attendanceId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Attendance ID parameter"})
+	}
+	att, err := h.AttendanceRepo.GetAttendanceByID(c.UserContext(), attendanceID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceID).Msg("Error loading attendance for document listing")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve documents"})
+	}
+	if att == nil || (ownerID > 0 && att.UserID != ownerID) {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Attendance with ID %d not found", attendanceID),
+		})
+	}
+
+	docs, err := h.DocumentRepo.GetDocumentsBySubject(c.UserContext(), models.DocumentSubjectAttendance, attendanceID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceID).Msg("Failed to get attendance documents")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve documents"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Documents retrieved successfully", Data: docs,
+	})
+}
+
+// downloadDocument mengirim isi dokumen sebagai attachment; ownerID > 0 membatasi ke pemilik.
+func (h *DocumentHandler) downloadDocument(c *fiber.Ctx, ownerID int) error {
+	documentID, err := idParam(c, "documentId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Document ID parameter"})
+	}
+	doc, err := h.DocumentRepo.GetDocumentByID(c.UserContext(), documentID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		reqLogger(c).Error().Err(err).Int("document_id", documentID).Msg("Error loading document")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to download document"})
+	}
+	if doc == nil || (ownerID > 0 && doc.OwnerUserID != ownerID) {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Document with ID %d not found", documentID),
+		})
+	}
+
+	// Backend dengan URL presigned (S3): klien diarahkan mengunduh langsung dari storage.
+	if presigner, ok := h.Storage.(storage.Presigner); ok {
+		url, err := presigner.PresignGet(c.UserContext(), doc.StorageKey, h.PresignTTL, storage.GetOptions{FileName: doc.FileName, ContentType: doc.ContentType})
+		if err != nil {
+			reqLogger(c).Error().Err(err).Int("document_id", documentID).Str("backend", h.Storage.Backend()).Msg("Failed to presign document download")
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to download document"})
+		}
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+		reqLogger(c).Info().Int("document_id", documentID).Msg("Document download redirected to storage")
+		return c.Redirect(url, fiber.StatusFound)
+	}
+
+	body, err := h.Storage.Open(c.UserContext(), doc.StorageKey)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("document_id", documentID).Str("backend", h.Storage.Backend()).Msg("Failed to open document from storage")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to download document"})
+	}
+
+	c.Attachment(doc.FileName)
+	c.Set(fiber.HeaderContentType, doc.ContentType)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	reqLogger(c).Info().Int("document_id", documentID).Msg("Document downloaded")
+	// Fiber menutup body (io.Closer) setelah response terkirim.
+	return c.Status(http.StatusOK).SendStream(body, int(doc.SizeBytes))
+}
+
+// GetMyAttendanceDocuments godoc
+// @Summary List documents of my attendance record
+// @Description Lists documents attached to one of the current user's attendance records.
+// @Tags User - Documents
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Success 200 {object} models.Response{data=[]models.Document} "Documents retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid Attendance ID parameter"
+// @Failure 404 {object} models.Response "Attendance not found"
+// @Security ApiKeyAuth
+// @Router /user/attendance/{attendanceId}/documents [get]
+func (h *DocumentHandler) GetMyAttendanceDocuments(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	return h.listAttendanceDocuments(c, userID)
+}
+
+// DownloadMyDocument godoc
+// @Summary Download my document
+// @Description Downloads a document uploaded by the current user. With object storage (STORAGE_BACKEND=s3) the response is a 302 redirect to a short-lived presigned URL.
+// @Tags User - Documents
+// @Produce application/octet-stream
+// @Param documentId path int true "Document ID"
+// @Success 200 {file} file "Document content"
+// @Success 302 {string} string "Redirect to a presigned storage URL"
+// @Failure 400 {object} models.Response "Invalid Document ID parameter"
+// @Failure 404 {object} models.Response "Document not found"
+// @Security ApiKeyAuth
+// @Router /user/documents/{documentId}/download [get]
+func (h *DocumentHandler) DownloadMyDocument(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	return h.downloadDocument(c, userID)
+}
+
+// GetAttendanceDocuments godoc
+// @Summary List documents of an attendance record
+// @Description Lists supporting documents attached to an attendance record, e.g. while reviewing a correction.
+// @Tags Admin - Attendance Management
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Success 200 {object} models.Response{data=[]models.Document} "Documents retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid Attendance ID parameter"
+// @Failure 404 {object} models.Response "Attendance not found"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/{attendanceId}/documents [get]
+func (h *DocumentHandler) GetAttendanceDocuments(c *fiber.Ctx) error {
+	return h.listAttendanceDocuments(c, 0)
+}
+
+// DownloadDocument godoc
+// @Summary Download a document
+// @Description Downloads any uploaded supporting document. With object storage (STORAGE_BACKEND=s3) the response is a 302 redirect to a short-lived presigned URL.
+// @Tags Admin - Attendance Management
+// @Produce application/octet-stream
+// @Param documentId path int true "Document ID"
+// @Success 200 {file} file "Document content"
+// @Success 302 {string} string "Redirect to a presigned storage URL"
+// @Failure 400 {object} models.Response "Invalid Document ID parameter"
+// @Failure 404 {object} models.Response "Document not found"
+// @Security ApiKeyAuth
+// @Router /admin/documents/{documentId}/download [get]
+func (h *DocumentHandler) DownloadDocument(c *fiber.Ctx) error {
+	return h.downloadDocument(c, 0)
+}
+
+// DeleteDocument godoc
+// @Summary Delete a document
+// @Description Deletes a document's metadata and its stored file (e.g. a wrong or inappropriate upload).
+// @Tags Admin - Attendance Management
+// @Produce json
+// @Param documentId path int true "Document ID"
+// @Success 200 {object} models.Response "Document deleted successfully"
+// @Failure 400 {object} models.Response "Invalid Document ID parameter"
+// @Failure 404 {object} models.Response "Document not found"
+// @Security ApiKeyAuth
+// @Router /admin/documents/{documentId} [delete]
+func (h *DocumentHandler) DeleteDocument(c *fiber.Ctx) error {
+	documentID, err := idParam(c, "documentId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Document ID parameter"})
+	}
+	doc, err := h.DocumentRepo.GetDocumentByID(c.UserContext(), documentID)
+	if err == nil {
+		err = h.DocumentRepo.DeleteDocument(c.UserContext(), documentID)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Document with ID %d not found", documentID),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("document_id", documentID).Msg("Failed to delete document")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to delete document"})
+	}
+	// Metadata sudah terhapus; kegagalan hapus file hanya dicatat (file yatim tidak bisa diakses lagi).
+	if err := h.Storage.Delete(c.UserContext(), doc.StorageKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		reqLogger(c).Warn().Err(err).Int("document_id", documentID).Str("storage_key", doc.StorageKey).Msg("Failed to delete document file from storage")
+	}
+
+	reqLogger(c).Info().Int("document_id", documentID).Msg("Document deleted")
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Document deleted successfully"})
+}
+
+// profilePhotoMaxBytes membatasi foto profil yang dibaca sebagai foto acuan verifikasi wajah.
+const profilePhotoMaxBytes = 10 * 1024 * 1024
+
+// UploadProfilePhoto godoc
+// @Summary Upload my profile photo
+// @Description Uploads (or replaces) the current user's profile photo (JPEG, PNG, WebP). When face verification is enabled, check-in selfies are compared against this photo; users without one are recorded as no_reference.
+// @Tags User - Documents
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Profile photo"
+// @Success 201 {object} models.Response{data=models.Document} "Profile photo uploaded successfully"
+// @Failure 400 {object} models.Response "Missing file"
+// @Failure 413 {object} models.Response "File too large"
+// @Failure 415 {object} models.Response "Unsupported file type"
+// @Failure 422 {object} models.Response "File rejected by malware scan"
+// @Failure 503 {object} models.Response "Malware scanner unavailable"
+// @Security ApiKeyAuth
+// @Router /user/profile/photo [put]
+func (h *DocumentHandler) UploadProfilePhoto(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	previous, err := h.DocumentRepo.GetDocumentsBySubject(c.UserContext(), models.DocumentSubjectProfilePhoto, userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get previous profile photos")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to upload profile photo"})
+	}
+
+	doc, err := h.saveUpload(c, userID, models.DocumentSubjectProfilePhoto, userID)
+	if doc == nil {
+		return err // Response error sudah dikirim saveUpload
+	}
+	// Foto lama diganti; kegagalan menghapusnya hanya dicatat (foto terbaru yang dipakai).
+	for _, old := range previous {
+		if err := h.DocumentRepo.DeleteDocument(c.UserContext(), old.ID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			reqLogger(c).Warn().Err(err).Int("document_id", old.ID).Msg("Failed to delete previous profile photo")
+			continue
+		}
+		if err := h.Storage.Delete(c.UserContext(), old.StorageKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			reqLogger(c).Warn().Err(err).Int("document_id", old.ID).Str("storage_key", old.StorageKey).Msg("Failed to delete previous profile photo file from storage")
+		}
+	}
+
+	reqLogger(c).Info().Int("document_id", doc.ID).Int("user_id", userID).Int64("size", doc.SizeBytes).Msg("Profile photo uploaded")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Profile photo uploaded successfully", Data: doc,
+	})
+}
+
+// ProfilePhoto mengembalikan isi foto profil terbaru userID sebagai foto acuan verifikasi
+// wajah (faceverify.ReferenceSource). Mengembalikan faceverify.ErrNoReference jika tidak ada.
+func (h *DocumentHandler) ProfilePhoto(ctx context.Context, userID int) ([]byte, error) {
+	docs, err := h.DocumentRepo.GetDocumentsBySubject(ctx, models.DocumentSubjectProfilePhoto, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, faceverify.ErrNoReference
+	}
+	doc := docs[len(docs)-1] // Urut ID naik: terakhir = terbaru
+	body, err := h.Storage.Open(ctx, doc.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, faceverify.ErrNoReference
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening profile photo of user %d: %w", userID, err)
+	}
+	defer body.Close()
+	photo, err := io.ReadAll(io.LimitReader(body, profilePhotoMaxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error reading profile photo of user %d: %w", userID, err)
+	}
+	return photo, nil
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/emailchange"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// EmailChangeHandler melayani ganti email oleh user sendiri: email baru berlaku setelah
+// dikonfirmasi lewat tautan yang dikirim ke alamat baru.
+type EmailChangeHandler struct {
+	UserRepo     repository.UserRepository
+	EmailChanges *emailchange.Service // nil = konfirmasi nonaktif (endpoint menjawab 404)
+	Events       events.Publisher
+	Validate     *validator.Validate
+}
+
+func NewEmailChangeHandler(userRepo repository.UserRepository, service *emailchange.Service, eventBus events.Publisher) *EmailChangeHandler {
+	return &EmailChangeHandler{UserRepo: userRepo, EmailChanges: service, Events: eventBus, Validate: validator.New()}
+}
+
+// RequestEmailChange godoc
+// @Summary Request an email change
+// @Description Sends a confirmation link to the new address (valid for EMAIL_CHANGE_TOKEN_TTL, default 24h) and notifies the current address. The email only changes once the link is confirmed (POST /auth/email/confirm). A new request replaces any pending one.
+// @Tags User - Profile Management
+// @Accept json
+// @Produce json
+// @Param email body models.RequestEmailChangeInput true "New email address"
+// @Success 202 {object} models.Response{data=models.EmailChangeRequest} "Confirmation link sent"
+// @Failure 400 {object} models.Response "Validation failed or same as the current email"
+// @Failure 404 {object} models.Response "Email change confirmation is disabled"
+// @Failure 409 {object} models.Response "Email already exists"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/email [post]
+func (h *EmailChangeHandler) RequestEmailChange(c *fiber.Ctx) error {
+	if h.EmailChanges == nil {
+		return emailChangeDisabled(c)
+	}
+	user, handled, resp := currentUser(c, h.UserRepo)
+	if handled {
+		return resp
+	}
+	input := new(models.RequestEmailChangeInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	request, handled, resp := requestEmailChange(c, h.EmailChanges, h.Events, user, input.Email)
+	if handled {
+		return resp
+	}
+	return c.Status(fiber.StatusAccepted).JSON(models.Response{
+		Success: true, Message: "Confirmation link sent to the new email address", Data: request,
+	})
+}
+
+// GetPendingEmailChange godoc
+// @Summary Get the pending email change
+// @Description Returns the email change waiting for confirmation, including its expiry.
+// @Tags User - Profile Management
+// @Produce json
+// @Success 200 {object} models.Response{data=models.EmailChangeRequest} "Pending email change"
+// @Failure 404 {object} models.Response "No pending email change"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/email/pending [get]
+func (h *EmailChangeHandler) GetPendingEmailChange(c *fiber.Ctx) error {
+	if h.EmailChanges == nil {
+		return emailChangeDisabled(c)
+	}
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	request, err := h.EmailChanges.Pending(c.UserContext(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "No pending email change"})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get pending email change")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve pending email change"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Pending email change retrieved successfully", Data: request})
+}
+
+// ResendEmailChange godoc
+// @Summary Resend the email change confirmation
+// @Description Sends a new confirmation link for the pending email change and extends its expiry. Allowed once per EMAIL_CHANGE_RESEND_INTERVAL (default 1 minute).
+// @Tags User - Profile Management
+// @Produce json
+// @Success 200 {object} models.Response{data=models.EmailChangeRequest} "Confirmation link sent again"
+// @Failure 404 {object} models.Response "No pending email change"
+// @Failure 429 {object} models.Response "Confirmation link was sent recently"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/email/resend [post]
+func (h *EmailChangeHandler) ResendEmailChange(c *fiber.Ctx) error {
+	if h.EmailChanges == nil {
+		return emailChangeDisabled(c)
+	}
+	user, handled, resp := currentUser(c, h.UserRepo)
+	if handled {
+		return resp
+	}
+	request, err := h.EmailChanges.Resend(c.UserContext(), user)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "No pending email change"})
+	case errors.Is(err, emailchange.ErrResendTooSoon):
+		return c.Status(fiber.StatusTooManyRequests).JSON(models.Response{Success: false, Message: "Confirmation link was sent recently, please wait before resending", Data: request})
+	case err != nil:
+		reqLogger(c).Error().Err(err).Msg("Failed to resend email change confirmation")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to send confirmation email"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Confirmation link sent again", Data: request})
+}
+
+// CancelEmailChange godoc
+// @Summary Cancel the pending email change
+// @Description Cancels the pending email change; its confirmation link stops working.
+// @Tags User - Profile Management
+// @Produce json
+// @Success 200 {object} models.Response "Email change cancelled"
+// @Failure 404 {object} models.Response "No pending email change"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/email/pending [delete]
+func (h *EmailChangeHandler) CancelEmailChange(c *fiber.Ctx) error {
+	if h.EmailChanges == nil {
+		return emailChangeDisabled(c)
+	}
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	if err := h.EmailChanges.Cancel(c.UserContext(), userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "No pending email change"})
+		}
+		reqLogger(c).Error().Err(err).Msg("Failed to cancel email change")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to cancel email change"})
+	}
+	reqLogger(c).Info().Msg("Email change cancelled")
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Email change cancelled"})
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm an email change
+// @Description Applies the email change from the link sent to the new address. Does not require login. The link stops working once used, after it expires, when the change is cancelled or replaced, and when the user's sessions are revoked. The previous address is notified.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param confirm body models.ConfirmEmailChangeInput true "Token from the confirmation link"
+// @Success 200 {object} models.Response "Email changed"
+// @Failure 400 {object} models.Response "Invalid request body"
+// @Failure 404 {object} models.Response "Email change confirmation is disabled"
+// @Failure 409 {object} models.Response "Email already exists"
+// @Failure 410 {object} models.Response "Invalid, expired or already used link"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Router /auth/email/confirm [post]
+func (h *EmailChangeHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	if h.EmailChanges == nil {
+		return emailChangeDisabled(c)
+	}
+	input := new(models.ConfirmEmailChangeInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	request, err := h.EmailChanges.Confirm(c.UserContext(), input.Token)
+	switch {
+	case errors.Is(err, emailchange.ErrInvalidLink):
+		return c.Status(fiber.StatusGone).JSON(models.Response{Success: false, Message: "Invalid or expired link"})
+	case errors.Is(err, repository.ErrEmailTaken):
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "email already exists"})
+	case err != nil:
+		reqLogger(c).Error().Err(err).Msg("Failed to confirm email change")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to change email"})
+	}
+	publishEvent(c, h.Events, events.Event{Name: events.EmailChanged, UserID: request.UserID, ActorUserID: &request.UserID, Data: map[string]any{"email_change_id": request.ID}})
+	reqLogger(c).Info().Int("user_id", request.UserID).Int("email_change_id", request.ID).Msg("Email change confirmed")
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Email changed successfully"})
+}
+
+// requestEmailChange membuat permintaan ganti email untuk user dan mencatatnya di audit log.
+// Jika tautan gagal terkirim permintaan tetap dikembalikan (bisa dikirim ulang).
+func requestEmailChange(c *fiber.Ctx, service *emailchange.Service, bus events.Publisher, user *models.User, email string) (request *models.EmailChangeRequest, handled bool, resp error) {
+	request, err := service.Request(c.UserContext(), user, email)
+	switch {
+	case errors.Is(err, emailchange.ErrSameEmail):
+		return nil, true, c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "New email is the same as the current email"})
+	case errors.Is(err, repository.ErrEmailTaken):
+		return nil, true, c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "email already exists"})
+	case err != nil && request == nil:
+		reqLogger(c).Error().Err(err).Int("user_id", user.ID).Msg("Failed to create email change request")
+		return nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to request email change"})
+	case err != nil:
+		reqLogger(c).Error().Err(err).Int("user_id", user.ID).Int("email_change_id", request.ID).Msg("Failed to send email change confirmation")
+		return nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Email change saved, but the confirmation email could not be sent. Please resend it.", Data: request,
+		})
+	}
+	publishEvent(c, bus, events.Event{Name: events.EmailChangeRequested, UserID: user.ID, Data: map[string]any{"email_change_id": request.ID}})
+	reqLogger(c).Info().Int("user_id", user.ID).Int("email_change_id", request.ID).Msg("Email change requested")
+	return request, false, nil
+}
+
+// currentUser memuat user yang sedang login dari JWT.
+func currentUser(c *fiber.Ctx, users repository.UserRepository) (user *models.User, handled bool, resp error) {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		return nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	user, err = users.GetUserByID(c.UserContext(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, true, c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "User not found"})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to load current user")
+		return nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve user"})
+	}
+	return user, false, nil
+}
+
+func emailChangeDisabled(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(models.Response{
+		Success: false, Message: "Email change confirmation is disabled; update the email through PUT /user/profile",
+	})
+}
@@ -2,48 +2,90 @@ package handlers
 
 import (
 	"errors"
+	"net/http"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rakaarfi/attendance-system-be/internal/models"
 	"github.com/rs/zerolog/log"
-	// Import error spesifik jika perlu dicek (misal: validator.ValidationErrors)
 )
 
-// ErrorHandler custom untuk Fiber
+// Kode error_code yang tidak diturunkan dari status HTTP.
+const (
+	errorCodeValidationFailed = "VALIDATION_FAILED"
+)
+
+// ErrorHandler custom untuk Fiber. Error yang dikembalikan handler/middleware (termasuk
+// *fiber.Error dan panic dari middleware Recover) dijawab dengan body RFC 7807
+// (application/problem+json). Response sukses v1 tetap memakai envelope models.Response.
 func ErrorHandler(ctx *fiber.Ctx, err error) error {
-	// Default error code
+	// Default: error internal, detailnya tidak dikirim ke klien
 	code := fiber.StatusInternalServerError
-	message := "Internal Server Error"
+	detail := ""
+	errorCode := ""
 
-	// Ambil status code dari fiber.Error jika ada
+	// Ambil status code dari fiber.Error jika ada; pesannya memang ditujukan untuk klien
 	var e *fiber.Error
 	if errors.As(err, &e) {
 		code = e.Code
-		message = e.Message
+		detail = e.Message
 	}
 
-	// Handle error spesifik lain jika perlu
-	// Misalnya:
+	// Error validasi yang tidak ditangani handler
 	var ve validator.ValidationErrors
 	if errors.As(err, &ve) {
 		code = fiber.StatusBadRequest
-		// Format pesan error validasi
-		message = "Validation Failed" // Atau buat pesan yg lebih detail
+		errorCode = errorCodeValidationFailed
+		fields := make([]string, 0, len(ve))
+		for _, fe := range ve {
+			fields = append(fields, fe.Field())
+		}
+		detail = "Validation failed for: " + strings.Join(fields, ", ")
+	}
+	if errorCode == "" {
+		errorCode = errorCodeForStatus(code)
 	}
 
 	// Log error dengan zerolog (sebelumnya sudah dilog oleh middleware, tapi ini untuk detail)
-	log.Error().Err(err).
-		Str("method", ctx.Method()).
-		Str("path", ctx.Path()).
+	// Logger per-request sudah membawa request_id, method, path, dan user (jika ada).
+	log.Ctx(ctx.UserContext()).Error().Err(err).
 		Int("status_sent", code).
+		Str("error_code", errorCode).
 		Msg("Error occurred during request processing")
 
-	// Kirim response JSON error
-	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
-	return ctx.Status(code).JSON(models.Response{
-		Success: false,
-		Message: message,
-		// Data: err.Error(), // Hati-hati mengirim detail error ke client
-	})
+	// request_id sama dengan header X-Request-ID dan field request_id di log.
+	requestID, _ := ctx.Locals("requestid").(string)
+	return ctx.Status(code).JSON(models.Problem{
+		Type:      "about:blank",
+		Title:     statusTitle(code),
+		Status:    code,
+		Detail:    detail,
+		Instance:  ctx.Path(),
+		ErrorCode: errorCode,
+		RequestID: requestID,
+	}, models.MIMEApplicationProblemJSON)
+}
+
+// statusTitle mengembalikan teks status HTTP, atau "Error" untuk status yang tidak dikenal.
+func statusTitle(code int) string {
+	if text := http.StatusText(code); text != "" {
+		return text
+	}
+	return "Error"
+}
+
+// errorCodeForStatus menurunkan error_code dari teks status HTTP,
+// mis. 404 -> NOT_FOUND, 413 -> REQUEST_ENTITY_TOO_LARGE.
+func errorCodeForStatus(code int) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(statusTitle(code)) {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		case r == ' ' || r == '-':
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
 }
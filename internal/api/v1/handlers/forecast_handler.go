@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	"github.com/rakaarfi/attendance-system-be/internal/worktime"
+)
+
+const (
+	defaultForecastWeeks = 2
+	maxForecastWeeks     = 8
+	defaultHistoryWeeks  = 4
+	maxHistoryWeeks      = 26
+)
+
+// ForecastHandler menyarankan jumlah orang per shift untuk minggu-minggu mendatang dari
+// riwayat kehadiran (moving average sederhana per hari dalam minggu & shift), dan
+// membandingkannya dengan jadwal yang sudah dibuat. Kalender kerja organisasi (settings
+// calendar.*) menandai jenis setiap hari dan mengecualikan hari libur dari riwayat.
+type ForecastHandler struct {
+	ScheduleRepo repository.ScheduleRepository
+	Settings     *settings.Store
+}
+
+func NewForecastHandler(scheduleRepo repository.ScheduleRepository, settingsStore *settings.Store) *ForecastHandler {
+	return &ForecastHandler{ScheduleRepo: scheduleRepo, Settings: settingsStore}
+}
+
+// parseWeeksQuery membaca query param jumlah minggu (1..limit), def jika kosong.
+func parseWeeksQuery(c *fiber.Ctx, key string, def, limit int) (int, bool) {
+	raw := c.Query(key)
+	if raw == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 || n > limit {
+		return 0, false
+	}
+	return n, true
+}
+
+// forecastKey mengidentifikasi satu shift pada satu hari dalam minggu.
+type forecastKey struct {
+	weekday time.Weekday
+	shiftID int
+}
+
+// buildStaffingSuggestions menghitung saran per tanggal dalam [start, end] dan per shift:
+// rata-rata kehadiran shift tersebut pada hari yang sama di riwayat (hanya hari ketika shift
+// dijadwalkan), dibulatkan ke atas. Shift tanpa riwayat pada hari itu tidak disarankan.
+// Hari libur di riwayat tidak ikut dirata-rata karena kehadirannya tidak mewakili hari biasa.
+func buildStaffingSuggestions(history, planned []models.ShiftHeadcount, start, end time.Time, calendar worktime.Calendar) []models.StaffingSuggestion {
+	type average struct {
+		attended, samples int
+	}
+	averages := map[forecastKey]*average{}
+	names := map[int]string{}
+	shiftIDs := []int{}
+	for _, hc := range history {
+		day, err := time.ParseInLocation(defaultDateFormat, hc.Date, start.Location())
+		if err != nil || calendar.DayType(day) == models.CalendarDayHoliday {
+			continue
+		}
+		key := forecastKey{weekday: day.Weekday(), shiftID: hc.ShiftID}
+		if averages[key] == nil {
+			averages[key] = &average{}
+		}
+		averages[key].attended += hc.Attended
+		averages[key].samples++
+		if _, ok := names[hc.ShiftID]; !ok {
+			shiftIDs = append(shiftIDs, hc.ShiftID)
+		}
+		names[hc.ShiftID] = hc.ShiftName
+	}
+	scheduled := map[string]int{}
+	for _, hc := range planned {
+		scheduled[hc.Date+"/"+strconv.Itoa(hc.ShiftID)] = hc.Scheduled
+	}
+	slices.Sort(shiftIDs)
+
+	suggestions := []models.StaffingSuggestion{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(defaultDateFormat)
+		for _, shiftID := range shiftIDs {
+			avg := averages[forecastKey{weekday: day.Weekday(), shiftID: shiftID}]
+			if avg == nil {
+				continue
+			}
+			mean := float64(avg.attended) / float64(avg.samples)
+			s := models.StaffingSuggestion{
+				Date: date, Weekday: day.Weekday().String(), ShiftID: shiftID, ShiftName: names[shiftID],
+				SuggestedHeadcount: int(math.Ceil(mean)),
+				AverageAttended:    math.Round(mean*100) / 100,
+				Samples:            avg.samples,
+				ScheduledHeadcount: scheduled[date+"/"+strconv.Itoa(shiftID)],
+				DayType:            calendar.DayType(day),
+			}
+			s.Gap = s.SuggestedHeadcount - s.ScheduledHeadcount
+			suggestions = append(suggestions, s)
+		}
+	}
+	return suggestions
+}
+
+// GetStaffingSuggestions godoc
+// @Summary Get staffing suggestions
+// @Description Suggests the headcount per shift for each day of the upcoming weeks from a simple moving average: for every weekday and shift, the number of scheduled employees who actually checked in on that weekday during the previous history_weeks weeks (only days the shift ran), rounded up. Days that were holidays in the working calendar are left out of the history, and each suggestion carries the day_type of its date (working, half_day, off, holiday). Each suggestion is compared with the schedules already created for that day; a positive gap means the day is understaffed. Shifts that never ran on a weekday in the history get no suggestion for it.
+// @Tags Admin - Schedule Management
+// @Produce json
+// @Param start_date query string false "First day to suggest for (YYYY-MM-DD), defaults to today"
+// @Param weeks query int false "Number of weeks to suggest for (1-8), defaults to 2"
+// @Param history_weeks query int false "Number of past weeks to average (1-26), defaults to 4"
+// @Success 200 {object} models.Response{data=map[string]interface{}} "Range, history window and suggestions"
+// @Failure 400 {object} models.Response "Invalid start_date, weeks or history_weeks"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/schedules/suggestions [get]
+func (h *ForecastHandler) GetStaffingSuggestions(c *fiber.Ctx) error {
+	start := startOfToday()
+	if raw := c.Query("start_date"); raw != "" {
+		parsed, err := time.ParseInLocation(defaultDateFormat, raw, time.Local)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid start_date format, expected YYYY-MM-DD"})
+		}
+		start = parsed
+	}
+	weeks, ok := parseWeeksQuery(c, "weeks", defaultForecastWeeks, maxForecastWeeks)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid weeks parameter, expected 1-8"})
+	}
+	historyWeeks, ok := parseWeeksQuery(c, "history_weeks", defaultHistoryWeeks, maxHistoryWeeks)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid history_weeks parameter, expected 1-26"})
+	}
+	end := start.AddDate(0, 0, weeks*7-1)
+	historyStart, historyEnd := start.AddDate(0, 0, -historyWeeks*7), start.AddDate(0, 0, -1)
+
+	history, err := h.ScheduleRepo.GetShiftHeadcounts(c.UserContext(), historyStart, historyEnd)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get historical shift headcounts")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to build staffing suggestions"})
+	}
+	planned, err := h.ScheduleRepo.GetShiftHeadcounts(c.UserContext(), start, end)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get planned shift headcounts")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to build staffing suggestions"})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Staffing suggestions retrieved successfully",
+		Data: fiber.Map{
+			"start_date":    start.Format(defaultDateFormat),
+			"end_date":      end.Format(defaultDateFormat),
+			"history_start": historyStart.Format(defaultDateFormat),
+			"history_end":   historyEnd.Format(defaultDateFormat),
+			"suggestions":   buildStaffingSuggestions(history, planned, start, end, h.Settings.WorkingCalendar(c.UserContext())),
+		},
+	})
+}
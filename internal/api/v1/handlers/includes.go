@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// Embed resource terkait lewat ?include=user,shift,schedule (dipisah koma).
+// Tanpa parameter include, listing memakai embed bawaannya (agar klien lama tidak berubah);
+// jika include dikirim, hanya resource yang disebut yang di-embed (include= kosong = tanpa embed).
+// Resource di-embed dengan satu query batch per jenis untuk seluruh halaman.
+
+// Nilai ?include= yang didukung tiap jenis listing.
+var (
+	attendanceIncludes   = []string{models.IncludeUser, models.IncludeSchedule, models.IncludeShift}
+	scheduleIncludes     = []string{models.IncludeUser, models.IncludeShift}
+	userScheduleIncludes = []string{models.IncludeUser, models.IncludeShift, models.IncludeAttendance}
+)
+
+// parseIncludes membaca ?include=. Nilai di luar allowed menghasilkan error (400).
+func parseIncludes(c *fiber.Ctx, allowed []string, defaults ...string) (models.Includes, error) {
+	includes := models.Includes{}
+	if !c.Context().QueryArgs().Has("include") {
+		for _, name := range defaults {
+			includes[name] = true
+		}
+		return includes, nil
+	}
+	for _, part := range strings.Split(c.Query("include"), ",") {
+		name := strings.TrimSpace(part)
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("unsupported include value '%s', use: %s", name, strings.Join(allowed, ", "))
+		}
+		includes[name] = true
+	}
+	return includes, nil
+}
+
+// includeAttendances mengisi Attendance.User, Attendance.Schedule, dan Attendance.Shift sesuai
+// includes, dan mengosongkan embed bawaan repository yang tidak diminta.
+func includeAttendances(ctx context.Context, userRepo repository.UserRepository, scheduleRepo repository.ScheduleRepository, attendances []models.Attendance, includes models.Includes) error {
+	if includes.Has(models.IncludeUser) {
+		var missing []int
+		for _, a := range attendances {
+			if a.User == nil {
+				missing = append(missing, a.UserID)
+			}
+		}
+		users, err := userSummaries(ctx, userRepo, missing)
+		if err != nil {
+			return err
+		}
+		for i := range attendances {
+			if attendances[i].User == nil {
+				attendances[i].User = users[attendances[i].UserID]
+			}
+		}
+	} else {
+		for i := range attendances {
+			attendances[i].User = nil
+		}
+	}
+
+	if !includes.Has(models.IncludeSchedule) && !includes.Has(models.IncludeShift) {
+		return nil
+	}
+	var ids []int
+	for _, a := range attendances {
+		if a.ScheduleID != nil {
+			ids = append(ids, *a.ScheduleID)
+		}
+	}
+	schedules, err := scheduleRepo.GetSchedulesByIDs(ctx, uniqueIDs(ids))
+	if err != nil {
+		return err
+	}
+	byID := make(map[int]*models.UserSchedule, len(schedules))
+	for i := range schedules {
+		byID[schedules[i].ID] = &schedules[i]
+	}
+	for i := range attendances {
+		if attendances[i].ScheduleID == nil {
+			continue
+		}
+		schedule := byID[*attendances[i].ScheduleID]
+		if schedule == nil {
+			continue
+		}
+		if includes.Has(models.IncludeShift) {
+			attendances[i].Shift = schedule.Shift
+		}
+		if includes.Has(models.IncludeSchedule) {
+			embedded := *schedule
+			embedded.Shift = nil // Shift efektif ada di Attendance.Shift (include=shift)
+			attendances[i].Schedule = &embedded
+		}
+	}
+	return nil
+}
+
+// includeSchedules mengisi UserSchedule.User sesuai includes dan mengosongkan embed bawaan
+// repository (User, Shift, Attendance) yang tidak diminta.
+func includeSchedules(ctx context.Context, userRepo repository.UserRepository, schedules []models.UserSchedule, includes models.Includes) error {
+	if includes.Has(models.IncludeUser) {
+		var missing []int
+		for _, s := range schedules {
+			if s.User == nil {
+				missing = append(missing, s.UserID)
+			}
+		}
+		users, err := userSummaries(ctx, userRepo, missing)
+		if err != nil {
+			return err
+		}
+		for i := range schedules {
+			if schedules[i].User == nil {
+				schedules[i].User = users[schedules[i].UserID]
+			}
+		}
+	}
+	for i := range schedules {
+		if !includes.Has(models.IncludeUser) {
+			schedules[i].User = nil
+		}
+		if !includes.Has(models.IncludeShift) {
+			schedules[i].Shift = nil
+		}
+		if !includes.Has(models.IncludeAttendance) {
+			schedules[i].Attendance, schedules[i].AttendanceStatus = nil, ""
+		}
+	}
+	return nil
+}
+
+// userSummaries memuat ringkasan user untuk ids (boleh duplikat) sebagai map per ID.
+func userSummaries(ctx context.Context, userRepo repository.UserRepository, ids []int) (map[int]*models.User, error) {
+	byID := map[int]*models.User{}
+	if len(ids) == 0 {
+		return byID, nil
+	}
+	users, err := userRepo.GetUserSummariesByIDs(ctx, uniqueIDs(ids))
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		byID[users[i].ID] = &users[i]
+	}
+	return byID, nil
+}
+
+// uniqueIDs mengembalikan ids tanpa duplikat (urut naik).
+func uniqueIDs(ids []int) []int {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
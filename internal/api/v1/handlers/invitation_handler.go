@@ -0,0 +1,428 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/invitation"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// InvitationHandler melayani onboarding lewat undangan: admin mengundang email dengan role yang
+// sudah ditentukan, dan penerima membuat akun lewat tautan undangan (tanpa login). Undangan
+// tetap bisa dipakai saat registrasi publik terbuka (auth.registration_mode = open). Admin juga
+// bisa membuat akun langsung (POST /admin/users) dengan email pengaturan password opsional.
+type InvitationHandler struct {
+	UserRepo    repository.UserRepository
+	Invitations *invitation.Service // nil = undangan tidak tersedia (endpoint undangan menjawab 404)
+	Events      events.Publisher
+	Tx          repository.TxManager // Akun baru, status undangan & event outbox dalam satu transaksi
+	Validate    *validator.Validate
+}
+
+func NewInvitationHandler(userRepo repository.UserRepository, service *invitation.Service, eventBus events.Publisher, txManager repository.TxManager) *InvitationHandler {
+	return &InvitationHandler{UserRepo: userRepo, Invitations: service, Events: eventBus, Tx: txManager, Validate: validator.New()}
+}
+
+// CreateInvitation godoc
+// @Summary Invite a user (Admin)
+// @Description Emails a registration link to the address with the role pre-assigned. An open invitation for the same address is replaced. The link expires after INVITATION_TTL (default 7 days).
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param invitation body models.CreateInvitationInput true "Email and role"
+// @Success 201 {object} models.Response{data=models.Invitation} "Invitation sent"
+// @Failure 400 {object} models.Response "Validation failed or role not found"
+// @Failure 404 {object} models.Response "Invitations are not available"
+// @Failure 409 {object} models.Response "Email already belongs to a user"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Failure 502 {object} models.Response "Invitation saved but the email could not be sent"
+// @Security ApiKeyAuth
+// @Router /admin/invitations [post]
+func (h *InvitationHandler) CreateInvitation(c *fiber.Ctx) error {
+	if h.Invitations == nil {
+		return invitationsDisabled(c)
+	}
+	adminID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	input := new(models.CreateInvitationInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Validation failed", Data: err.Error()})
+	}
+	inv, err := h.Invitations.Invite(c.UserContext(), input.Email, input.RoleID, adminID)
+	switch {
+	case errors.Is(err, repository.ErrEmailTaken):
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Email already belongs to a user"})
+	case errors.Is(err, pgx.ErrNoRows):
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Role not found"})
+	case err != nil && inv == nil:
+		reqLogger(c).Error().Err(err).Msg("Failed to create invitation")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create invitation"})
+	}
+	publishEvent(c, h.Events, events.Event{
+		Name: events.InvitationCreated, UserID: adminID,
+		Data: map[string]any{"invitation_id": inv.ID, "role_id": inv.RoleID},
+	})
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("invitation_id", inv.ID).Msg("Failed to send invitation")
+		return c.Status(fiber.StatusBadGateway).JSON(models.Response{
+			Success: false, Message: "Invitation saved but the email could not be sent, try resending it", Data: inv,
+		})
+	}
+	reqLogger(c).Info().Int("invitation_id", inv.ID).Int("role_id", inv.RoleID).Msg("Invitation sent")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{Success: true, Message: "Invitation sent", Data: inv})
+}
+
+// GetInvitations godoc
+// @Summary List invitations (Admin)
+// @Description Lists invitations, newest first, with their role and status.
+// @Tags Admin - Users Management
+// @Produce json
+// @Param status query string false "pending, accepted, revoked, expired or all (default)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.Response{data=[]models.Invitation} "Invitations retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid status"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/invitations [get]
+func (h *InvitationHandler) GetInvitations(c *fiber.Ctx) error {
+	status := c.Query("status", "all")
+	switch status {
+	case models.InvitationPending, models.InvitationAccepted, models.InvitationRevoked, models.InvitationExpired:
+	case "all":
+		status = ""
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid status, expected pending, accepted, revoked, expired or all",
+		})
+	}
+	pagination := utils.ParsePaginationParams(c)
+	invitations, total, err := h.UserRepo.GetInvitations(c.UserContext(), status, pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get invitations")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve invitations"})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Invitations retrieved successfully", invitations, meta))
+}
+
+// ResendInvitation godoc
+// @Summary Resend an invitation (Admin)
+// @Description Sends a new registration link for a pending or expired invitation and extends its expiry. Allowed once per INVITATION_RESEND_INTERVAL (default 1 minute).
+// @Tags Admin - Users Management
+// @Produce json
+// @Param invitationId path int true "Invitation ID"
+// @Success 200 {object} models.Response{data=models.Invitation} "Invitation sent again"
+// @Failure 400 {object} models.Response "Invalid invitation ID"
+// @Failure 404 {object} models.Response "Invitation not found, accepted or revoked"
+// @Failure 429 {object} models.Response "Invitation was sent recently"
+// @Failure 502 {object} models.Response "Invitation email could not be sent"
+// @Security ApiKeyAuth
+// @Router /admin/invitations/{invitationId}/resend [post]
+func (h *InvitationHandler) ResendInvitation(c *fiber.Ctx) error {
+	if h.Invitations == nil {
+		return invitationsDisabled(c)
+	}
+	id, err := idParam(c, "invitationId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid invitation ID"})
+	}
+	inv, err := h.Invitations.Resend(c.UserContext(), id)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "Invitation not found, accepted or revoked"})
+	case errors.Is(err, invitation.ErrResendTooSoon):
+		return c.Status(fiber.StatusTooManyRequests).JSON(models.Response{Success: false, Message: "Invitation was sent recently, please wait before resending", Data: inv})
+	case err != nil && inv == nil:
+		reqLogger(c).Error().Err(err).Int("invitation_id", id).Msg("Failed to resend invitation")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to resend invitation"})
+	case err != nil:
+		reqLogger(c).Error().Err(err).Int("invitation_id", id).Msg("Failed to send invitation")
+		return c.Status(fiber.StatusBadGateway).JSON(models.Response{Success: false, Message: "Failed to send invitation email", Data: inv})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Invitation sent again", Data: inv})
+}
+
+// RevokeInvitation godoc
+// @Summary Revoke an invitation (Admin)
+// @Description Revokes an invitation that has not been accepted; its link stops working.
+// @Tags Admin - Users Management
+// @Produce json
+// @Param invitationId path int true "Invitation ID"
+// @Success 200 {object} models.Response "Invitation revoked"
+// @Failure 400 {object} models.Response "Invalid invitation ID"
+// @Failure 404 {object} models.Response "Invitation not found, accepted or already revoked"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/invitations/{invitationId} [delete]
+func (h *InvitationHandler) RevokeInvitation(c *fiber.Ctx) error {
+	adminID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	id, err := idParam(c, "invitationId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid invitation ID"})
+	}
+	err = runInTx(c, h.Tx, func() error {
+		if err := h.UserRepo.RevokeInvitation(c.UserContext(), id); err != nil {
+			return err
+		}
+		publishEvent(c, h.Events, events.Event{Name: events.InvitationRevoked, UserID: adminID, Data: map[string]any{"invitation_id": id}})
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "Invitation not found, accepted or already revoked"})
+		}
+		reqLogger(c).Error().Err(err).Int("invitation_id", id).Msg("Failed to revoke invitation")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to revoke invitation"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Invitation revoked"})
+}
+
+// PreviewInvitation godoc
+// @Summary Show an invitation
+// @Description Returns the email and role of the invitation in a registration link so the sign-up page can show them. Does not require login.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param token body models.InvitationTokenInput true "Token from the invitation link"
+// @Success 200 {object} models.Response{data=map[string]string} "Invitation is valid, returns email, role_name and expires_at"
+// @Failure 400 {object} models.Response "Invalid, expired, used or revoked link"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Router /auth/invitations/preview [post]
+func (h *InvitationHandler) PreviewInvitation(c *fiber.Ctx) error {
+	if h.Invitations == nil {
+		return invitationsDisabled(c)
+	}
+	input := new(models.InvitationTokenInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Validation failed", Data: err.Error()})
+	}
+	inv, handled, resp := h.openInvitation(c, input.Token)
+	if handled {
+		return resp
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Invitation is valid",
+		Data: fiber.Map{"email": inv.Email, "role_name": inv.RoleName, "expires_at": inv.ExpiresAt},
+	})
+}
+
+// AcceptInvitation godoc
+// @Summary Register with an invitation
+// @Description Creates the account of an invited user with the email and role from the invitation; the link can only be used once. Works whatever the auth.registration_mode setting is. Does not require login.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param registration body models.AcceptInvitationInput true "Invitation token and account details"
+// @Success 201 {object} models.Response{data=map[string]int} "User registered successfully, returns user ID"
+// @Failure 400 {object} models.Response "Validation failed or invalid, expired, used or revoked link"
+// @Failure 409 {object} models.Response "Username or Email already exists"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Router /auth/invitations/accept [post]
+func (h *InvitationHandler) AcceptInvitation(c *fiber.Ctx) error {
+	if h.Invitations == nil {
+		return invitationsDisabled(c)
+	}
+	input := new(models.AcceptInvitationInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Validation failed", Data: err.Error()})
+	}
+	inv, handled, resp := h.openInvitation(c, input.Token)
+	if handled {
+		return resp
+	}
+	hashedPassword, err := utils.HashPassword(input.Password)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to hash password during invited registration")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to process registration"})
+	}
+	register := &models.RegisterUserInput{
+		Username: input.Username, Password: input.Password, Email: inv.Email,
+		FirstName: input.FirstName, LastName: input.LastName, Phone: input.Phone, RoleID: inv.RoleID,
+	}
+
+	var userID int
+	err = runInTx(c, h.Tx, func() error {
+		var err error
+		if userID, err = h.UserRepo.CreateUser(c.UserContext(), register, hashedPassword); err != nil {
+			return err
+		}
+		if err := h.UserRepo.AcceptInvitation(c.UserContext(), inv.ID, userID); err != nil {
+			return err
+		}
+		publishEvent(c, h.Events, events.Event{
+			Name: events.InvitationAccepted, UserID: userID, ActorUserID: &userID,
+			Data: map[string]any{"invitation_id": inv.ID, "role_id": inv.RoleID},
+		})
+		return nil
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return invalidInvitation(c)
+		case errors.As(err, &pgErr) && pgErr.Code == "23505":
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Username or Email already exists"})
+		}
+		reqLogger(c).Error().Err(err).Int("invitation_id", inv.ID).Msg("Failed to register invited user")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to register user"})
+	}
+	reqLogger(c).Info().Int("user_id", userID).Int("invitation_id", inv.ID).Msg("Invited user registered successfully")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "User registered successfully", Data: fiber.Map{"user_id": userID},
+	})
+}
+
+// CreateUser godoc
+// @Summary Create a user (Admin)
+// @Description Creates an account directly with any role, unlike the self-service POST /auth/register. With send_invite the user receives an email with a link to set their own password (valid for INVITATION_TTL, default 7 days), and password may be omitted. With require_password_change, logging in with the given password only returns a reset_token for POST /auth/password/reset until the password is changed.
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param user body models.AdminCreateUserInput true "Account details and onboarding options"
+// @Success 201 {object} models.Response{data=map[string]int} "User created, returns user ID"
+// @Failure 400 {object} models.Response "Validation failed or role not found"
+// @Failure 404 {object} models.Response "Invitations are not available (send_invite)"
+// @Failure 409 {object} models.Response "Username or Email already exists"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Failure 502 {object} models.Response "User created but the email could not be sent"
+// @Security ApiKeyAuth
+// @Router /admin/users [post]
+func (h *InvitationHandler) CreateUser(c *fiber.Ctx) error {
+	adminID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	input := new(models.AdminCreateUserInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Validation failed", Data: err.Error()})
+	}
+	if input.SendInvite && h.Invitations == nil {
+		return invitationsDisabled(c)
+	}
+	password := input.Password
+	if password == "" {
+		// Password acak yang tidak diketahui siapa pun; user mengaturnya lewat tautan di email.
+		if password, err = randomPassword(); err != nil {
+			reqLogger(c).Error().Err(err).Msg("Failed to generate password for new user")
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create user"})
+		}
+	}
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to hash password for new user")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create user"})
+	}
+	register := &models.RegisterUserInput{
+		Username: input.Username, Password: password, Email: input.Email,
+		FirstName: input.FirstName, LastName: input.LastName, Phone: input.Phone, RoleID: input.RoleID,
+	}
+
+	var userID int
+	err = runInTx(c, h.Tx, func() error {
+		var err error
+		if userID, err = h.UserRepo.CreateUser(c.UserContext(), register, hashedPassword); err != nil {
+			return err
+		}
+		if input.RequirePasswordChange {
+			if err := h.UserRepo.RequirePasswordChange(c.UserContext(), userID); err != nil {
+				return err
+			}
+		}
+		publishEvent(c, h.Events, events.Event{
+			Name: events.UserCreated, UserID: userID, ActorUserID: &adminID,
+			Data: map[string]any{
+				"role_id": input.RoleID, "send_invite": input.SendInvite, "require_password_change": input.RequirePasswordChange,
+			},
+		})
+		return nil
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Username or Email already exists"})
+			case "23503":
+				return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Role not found"})
+			}
+		}
+		reqLogger(c).Error().Err(err).Msg("Failed to create user")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create user"})
+	}
+	reqLogger(c).Info().Int("user_id", userID).Int("admin_id", adminID).Int("role_id", input.RoleID).Msg("User created by admin")
+
+	if input.SendInvite {
+		user, err := h.UserRepo.GetUserByID(c.UserContext(), userID)
+		if err == nil {
+			err = h.Invitations.SendAccountSetup(c.UserContext(), user)
+		}
+		if err != nil {
+			// Akun tetap dibuat (transaksi sudah commit); hanya email yang gagal.
+			reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to send account setup email")
+			return c.Status(fiber.StatusBadGateway).JSON(models.Response{
+				Success: false, Message: "User created but the email could not be sent", Data: fiber.Map{"user_id": userID},
+			})
+		}
+	}
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "User created successfully", Data: fiber.Map{"user_id": userID},
+	})
+}
+
+// randomPassword membuat password acak untuk akun yang passwordnya diatur lewat email.
+func randomPassword() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// openInvitation membuka undangan dari token tautan; response error sudah ditulis jika handled.
+func (h *InvitationHandler) openInvitation(c *fiber.Ctx, token string) (inv *models.Invitation, handled bool, resp error) {
+	inv, err := h.Invitations.Open(c.UserContext(), token)
+	if errors.Is(err, invitation.ErrInvalidLink) {
+		return nil, true, invalidInvitation(c)
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to open invitation")
+		return nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to process invitation"})
+	}
+	return inv, false, nil
+}
+
+func invalidInvitation(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid or expired invitation link"})
+}
+
+func invitationsDisabled(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "Invitations are not available"})
+}
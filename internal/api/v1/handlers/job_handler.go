@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/jobs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// JobHandler melayani pemantauan dan pemicuan manual job latar belakang (lihat jobs.Scheduler).
+type JobHandler struct {
+	Scheduler *jobs.Scheduler
+}
+
+func NewJobHandler(scheduler *jobs.Scheduler) *JobHandler {
+	return &JobHandler{Scheduler: scheduler}
+}
+
+// GetJobs godoc
+// @Summary Get background jobs
+// @Description Lists the scheduled background jobs (contractor expiry, probation review, approval escalation, shift reminders) with their interval, next run, and the status, trigger, result, error and duration of the last run. The schedule is shared by all API instances; registered tells whether the job is enabled on the instance that answered.
+// @Tags Admin - Monitoring
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.ScheduledJob} "Jobs retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/jobs [get]
+func (h *JobHandler) GetJobs(c *fiber.Ctx) error {
+	list, err := h.Scheduler.Jobs(c.UserContext())
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get scheduled jobs")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve jobs"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Jobs retrieved successfully", Data: list,
+	})
+}
+
+// TriggerJob godoc
+// @Summary Run a background job now
+// @Description Starts one run of a background job in the background, outside its schedule, and moves its next scheduled run one interval ahead. The run is skipped when the job is already running on another instance. Check GET /admin/jobs for the result.
+// @Tags Admin - Monitoring
+// @Produce json
+// @Param name path string true "Job name"
+// @Success 202 {object} models.Response "Job run started"
+// @Failure 404 {object} models.Response "Job not registered on this instance"
+// @Security ApiKeyAuth
+// @Router /admin/jobs/{name}/run [post]
+func (h *JobHandler) TriggerJob(c *fiber.Ctx) error {
+	name := c.Params("name")
+	if err := h.Scheduler.Trigger(c.UserContext(), name); err != nil {
+		if errors.Is(err, jobs.ErrUnknownJob) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Job '%s' is not registered on this instance", name),
+			})
+		}
+		reqLogger(c).Error().Err(err).Str("job", name).Msg("Failed to trigger job")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to trigger job"})
+	}
+	reqLogger(c).Info().Str("job", name).Msg("Admin triggered background job")
+	return c.Status(fiber.StatusAccepted).JSON(models.Response{
+		Success: true, Message: "Job run started", Data: fiber.Map{"name": name},
+	})
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// JWKS menyajikan kunci publik token sesi (RFC 7517) di /.well-known/jwks.json agar layanan
+// internal lain bisa memvalidasi token tanpa mengetahui JWT_SECRET. Daftar kunci kosong jika
+// token ditandatangani HS256 (JWT_SIGNING_ALG default). Endpoint berada di luar /api/v1 dan
+// tidak butuh login.
+func JWKS(c *fiber.Ctx) error {
+	// Cache singkat: kunci lama tetap dipublikasikan selama rotasi (JWT_PREVIOUS_PUBLIC_KEY_FILES).
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(http.StatusOK).JSON(utils.PublicJWKS())
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// KioskHandler melayani perangkat kiosk/terminal absensi. Token kiosk berumur pendek, hanya
+// boleh absen (scope attendance:punch), terikat ke fingerprint perangkat yang didaftarkan, dan
+// dirotasi otomatis selama dipakai (middleware DeviceBound), sehingga tablet yang dicuri hanya
+// berguna sampai perangkatnya dicabut.
+type KioskHandler struct {
+	KioskRepo repository.KioskRepository
+	UserRepo  repository.UserRepository
+	Settings  *settings.Store
+	Events    events.Publisher // Audit pendaftaran & pencabutan perangkat
+	Validate  *validator.Validate
+	TokenTTL  time.Duration
+}
+
+// NewKioskHandler membuat handler kiosk berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - KIOSK_TOKEN_TTL: Masa berlaku token kiosk (dirotasi setelah setengahnya lewat). Default: 1h.
+func NewKioskHandler(kioskRepo repository.KioskRepository, userRepo repository.UserRepository, settingsStore *settings.Store, eventBus events.Publisher) *KioskHandler {
+	return &KioskHandler{
+		KioskRepo: kioskRepo,
+		UserRepo:  userRepo,
+		Settings:  settingsStore,
+		Events:    eventBus,
+		Validate:  validator.New(),
+		TokenTTL:  max(configs.GetEnvDuration("KIOSK_TOKEN_TTL", time.Hour), time.Minute),
+	}
+}
+
+// kioskScopes adalah scope token kiosk.
+var kioskScopes = []string{models.ScopeAttendancePunch}
+
+// issueToken menerbitkan token kiosk untuk device milik userID. Jika gagal, response error sudah
+// ditulis dan handled bernilai true.
+func (h *KioskHandler) issueToken(c *fiber.Ctx, userID int, device *models.KioskDevice) (token *models.KioskToken, handled bool, resp error) {
+	user, accessEnd, handled, resp := tokenOwner(c, h.UserRepo, h.Settings, userID)
+	if handled {
+		return nil, true, resp
+	}
+	expiresAt := time.Now().Add(h.TokenTTL)
+	if accessEnd != nil && accessEnd.Before(expiresAt) {
+		expiresAt = *accessEnd
+	}
+	signed, err := utils.GenerateDeviceJWT(user.ID, user.Username, user.Role.Name, accessEnd, user.TokenVersion, kioskScopes, device.ID, device.FingerprintHash, expiresAt)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("kiosk_id", device.ID).Msg("Error generating kiosk token")
+		return nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to issue kiosk token",
+		})
+	}
+	return &models.KioskToken{Device: device, Token: signed, ExpiresAt: expiresAt}, false, nil
+}
+
+// EnrollKiosk godoc
+// @Summary Enroll a kiosk device
+// @Description Enrolls the device running the attendance kiosk app for the current user and returns its first kiosk token. The token only allows punching attendance (check-in, check-out, project switch and the project list), is accepted only with the same fingerprint in the X-Device-Fingerprint header, expires after KIOSK_TOKEN_TTL (default 1h) and is replaced through the X-Rotated-Token response header once half of its lifetime has passed. Only the SHA-256 hash of the fingerprint is stored. Requires a full session token.
+// @Tags User - Kiosks
+// @Accept json
+// @Produce json
+// @Param kiosk body models.EnrollKioskInput true "Device name and fingerprint"
+// @Success 201 {object} models.Response{data=models.KioskToken} "Kiosk enrolled"
+// @Failure 400 {object} models.Response "Validation failed or invalid request body"
+// @Failure 403 {object} models.Response "Account not allowed to issue tokens"
+// @Failure 409 {object} models.Response "Device already enrolled"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/kiosks [post]
+func (h *KioskHandler) EnrollKiosk(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	input := new(models.EnrollKioskInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	// Pastikan user boleh menerima token sebelum perangkat disimpan.
+	if _, _, handled, resp := tokenOwner(c, h.UserRepo, h.Settings, userID); handled {
+		return resp
+	}
+
+	device := &models.KioskDevice{UserID: userID, Name: input.Name, FingerprintHash: utils.HashDeviceFingerprint(input.Fingerprint)}
+	if err := h.KioskRepo.CreateKioskDevice(c.UserContext(), device); err != nil {
+		if errors.Is(err, repository.ErrKioskAlreadyEnrolled) {
+			return c.Status(fiber.StatusConflict).JSON(models.Response{
+				Success: false, Message: "Device is already enrolled; request a new token for it instead",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to enroll kiosk"})
+	}
+	details := clientDetails(c)
+	details["kiosk_id"] = device.ID
+	details["name"] = device.Name
+	publishEvent(c, h.Events, events.Event{Name: events.KioskEnrolled, UserID: userID, ActorUserID: &userID, Data: details})
+
+	token, handled, resp := h.issueToken(c, userID, device)
+	if handled {
+		return resp
+	}
+	return c.Status(http.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Kiosk enrolled", Data: token,
+	})
+}
+
+// GetMyKiosks godoc
+// @Summary List my kiosk devices
+// @Description Lists the kiosk devices enrolled by the current user, newest first, including revoked ones.
+// @Tags User - Kiosks
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.KioskDevice} "Kiosk devices retrieved"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/kiosks [get]
+func (h *KioskHandler) GetMyKiosks(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	devices, err := h.KioskRepo.GetKioskDevicesByUser(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve kiosk devices"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Kiosk devices retrieved", Data: devices})
+}
+
+// IssueKioskToken godoc
+// @Summary Issue a new kiosk token
+// @Description Issues a new token for an enrolled kiosk of the current user, e.g. after the kiosk was offline longer than the token lifetime. The fingerprint must match the one used at enrollment. Requires a full session token.
+// @Tags User - Kiosks
+// @Accept json
+// @Produce json
+// @Param kioskId path int true "Kiosk device ID"
+// @Param kiosk body models.KioskTokenInput true "Device fingerprint"
+// @Success 201 {object} models.Response{data=models.KioskToken} "Kiosk token issued"
+// @Failure 400 {object} models.Response "Invalid kiosk ID or request body"
+// @Failure 403 {object} models.Response "Fingerprint mismatch or account not allowed to issue tokens"
+// @Failure 404 {object} models.Response "Kiosk not found or revoked"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/kiosks/{kioskId}/token [post]
+func (h *KioskHandler) IssueKioskToken(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	kioskID, err := idParam(c, "kioskId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid kiosk ID"})
+	}
+	input := new(models.KioskTokenInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	device, err := h.KioskRepo.GetKioskDevice(c.UserContext(), kioskID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to issue kiosk token"})
+	}
+	if err != nil || device.UserID != userID || device.RevokedAt != nil {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "Kiosk not found or revoked"})
+	}
+	if utils.HashDeviceFingerprint(input.Fingerprint) != device.FingerprintHash {
+		reqLogger(c).Warn().Int("kiosk_id", kioskID).Str("ip", c.IP()).Msg("Kiosk token requested with a different device fingerprint")
+		return c.Status(fiber.StatusForbidden).JSON(models.Response{Success: false, Message: "Fingerprint does not match the enrolled device"})
+	}
+
+	token, handled, resp := h.issueToken(c, userID, device)
+	if handled {
+		return resp
+	}
+	if err := h.KioskRepo.TouchKioskDevice(c.UserContext(), device.ID); err != nil {
+		reqLogger(c).Warn().Err(err).Int("kiosk_id", device.ID).Msg("Failed to update kiosk device last seen")
+	}
+	return c.Status(http.StatusCreated).JSON(models.Response{Success: true, Message: "Kiosk token issued", Data: token})
+}
+
+// RevokeMyKiosk godoc
+// @Summary Revoke my kiosk device
+// @Description Revokes a kiosk device of the current user (e.g. a lost or stolen tablet). Its tokens are rejected immediately; other sessions are not affected.
+// @Tags User - Kiosks
+// @Produce json
+// @Param kioskId path int true "Kiosk device ID"
+// @Success 200 {object} models.Response{data=models.KioskDevice} "Kiosk revoked"
+// @Failure 400 {object} models.Response "Invalid kiosk ID"
+// @Failure 404 {object} models.Response "Kiosk not found or already revoked"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/kiosks/{kioskId} [delete]
+func (h *KioskHandler) RevokeMyKiosk(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	return h.revoke(c, &userID, userID)
+}
+
+// RevokeKiosk godoc
+// @Summary Revoke a kiosk device
+// @Description Revokes any user's kiosk device (e.g. a stolen tablet reported to the admin). Its tokens are rejected immediately.
+// @Tags Admin - Users Management
+// @Produce json
+// @Param kioskId path int true "Kiosk device ID"
+// @Success 200 {object} models.Response{data=models.KioskDevice} "Kiosk revoked"
+// @Failure 400 {object} models.Response "Invalid kiosk ID"
+// @Failure 404 {object} models.Response "Kiosk not found or already revoked"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/kiosks/{kioskId} [delete]
+func (h *KioskHandler) RevokeKiosk(c *fiber.Ctx) error {
+	adminID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	return h.revoke(c, nil, adminID)
+}
+
+func (h *KioskHandler) revoke(c *fiber.Ctx, ownerID *int, actorID int) error {
+	kioskID, err := idParam(c, "kioskId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid kiosk ID"})
+	}
+	device, err := h.KioskRepo.RevokeKioskDevice(c.UserContext(), kioskID, ownerID, actorID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "Kiosk not found or already revoked"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to revoke kiosk"})
+	}
+	details := clientDetails(c)
+	details["kiosk_id"] = device.ID
+	details["name"] = device.Name
+	publishEvent(c, h.Events, events.Event{Name: events.KioskRevoked, UserID: device.UserID, ActorUserID: &actorID, Data: details})
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Kiosk revoked", Data: device})
+}
+
+// GetUserKiosks godoc
+// @Summary List a user's kiosk devices
+// @Description Lists the kiosk devices enrolled by a user, newest first, including revoked ones.
+// @Tags Admin - Users Management
+// @Produce json
+// @Param userId path int true "User ID"
+// @Success 200 {object} models.Response{data=[]models.KioskDevice} "Kiosk devices retrieved"
+// @Failure 400 {object} models.Response "Invalid user ID"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId}/kiosks [get]
+func (h *KioskHandler) GetUserKiosks(c *fiber.Ctx) error {
+	userID, err := idParam(c, "userId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid user ID"})
+	}
+	devices, err := h.KioskRepo.GetKioskDevicesByUser(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve kiosk devices"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Kiosk devices retrieved", Data: devices})
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// LaborHandler melayani tarif per jam (per user atau role) dan estimasi biaya tenaga kerja
+// terjadwal vs aktual, agar roster bisa dijaga tetap dalam anggaran.
+type LaborHandler struct {
+	LaborRepo repository.LaborRepository
+	Validate  *validator.Validate
+}
+
+func NewLaborHandler(laborRepo repository.LaborRepository) *LaborHandler {
+	return &LaborHandler{
+		LaborRepo: laborRepo,
+		Validate:  validator.New(),
+	}
+}
+
+// SetUserHourlyRate godoc
+// @Summary Set a user's hourly rate
+// @Description Sets the hourly rate used for labor cost estimates of one user, overriding the rate of the user's role. Send hourly_rate null to fall back to the role rate.
+// @Tags Admin - Labor Cost
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param rate body models.HourlyRateInput true "Hourly rate"
+// @Success 200 {object} models.Response{data=map[string]interface{}} "Hourly rate updated successfully"
+// @Failure 400 {object} models.Response "Invalid input"
+// @Failure 404 {object} models.Response "User not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId}/hourly-rate [put]
+func (h *LaborHandler) SetUserHourlyRate(c *fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("userId"))
+	if err != nil || userID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid User ID parameter"})
+	}
+	input := new(models.HourlyRateInput)
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Error parsing hourly rate request body")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	if err := h.LaborRepo.SetUserHourlyRate(c.UserContext(), userID, input.HourlyRate); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("User with ID %d not found", userID),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to set user hourly rate")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to update hourly rate"})
+	}
+	reqLogger(c).Info().Int("user_id", userID).Interface("hourly_rate", input.HourlyRate).Msg("Admin updated user hourly rate")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Hourly rate updated successfully",
+		Data: fiber.Map{"user_id": userID, "hourly_rate": input.HourlyRate},
+	})
+}
+
+// SetRoleHourlyRate godoc
+// @Summary Set a role's hourly rate
+// @Description Sets the default hourly rate for users of a role in labor cost estimates. Users with their own rate keep it. Send hourly_rate null to remove the rate.
+// @Tags Admin - Labor Cost
+// @Accept json
+// @Produce json
+// @Param roleId path int true "Role ID"
+// @Param rate body models.HourlyRateInput true "Hourly rate"
+// @Success 200 {object} models.Response{data=map[string]interface{}} "Hourly rate updated successfully"
+// @Failure 400 {object} models.Response "Invalid input"
+// @Failure 404 {object} models.Response "Role not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/roles/{roleId}/hourly-rate [put]
+func (h *LaborHandler) SetRoleHourlyRate(c *fiber.Ctx) error {
+	roleID, err := strconv.Atoi(c.Params("roleId"))
+	if err != nil || roleID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Role ID parameter"})
+	}
+	input := new(models.HourlyRateInput)
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Error parsing hourly rate request body")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	if err := h.LaborRepo.SetRoleHourlyRate(c.UserContext(), roleID, input.HourlyRate); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Role with ID %d not found", roleID),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("role_id", roleID).Msg("Failed to set role hourly rate")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to update hourly rate"})
+	}
+	reqLogger(c).Info().Int("role_id", roleID).Interface("hourly_rate", input.HourlyRate).Msg("Admin updated role hourly rate")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Hourly rate updated successfully",
+		Data: fiber.Map{"role_id": roleID, "hourly_rate": input.HourlyRate},
+	})
+}
+
+// GetLaborCost godoc
+// @Summary Get labor cost estimate
+// @Description Estimates scheduled vs actual labor hours and cost in a date range, per day, per role, or per team (direct reports of one manager). Scheduled hours come from the roster (overnight shifts count into the next day), actual hours from completed attendance sessions by check-in date. Cost uses the user's hourly rate, else the role's; hours of users without any rate are reported as unrated and left out of the cost. With budget, the totals are compared against it.
+// @Tags Admin - Labor Cost
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to the start of the month"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param group_by query string false "Grouping, defaults to day" Enums(day, role, manager)
+// @Param budget query number false "Labor budget for the whole range"
+// @Success 200 {object} models.Response{data=models.LaborCostReport} "Labor cost retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid date range, group_by or budget"
+// @Failure 500 {object} models.Response "Internal server error during aggregation"
+// @Security ApiKeyAuth
+// @Router /admin/analytics/labor-cost [get]
+func (h *LaborHandler) GetLaborCost(c *fiber.Ctx) error {
+	startDate, endDate, dateErr := parseAdminDateQueryParams(c)
+	if dateErr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: dateErr.Error()})
+	}
+	groupBy := c.Query("group_by", models.LaborGroupDay)
+	switch groupBy {
+	case models.LaborGroupDay, models.LaborGroupRole, models.LaborGroupManager:
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid group_by, expected one of: day, role, manager",
+		})
+	}
+	var budget *float64
+	if raw := c.Query("budget"); raw != "" {
+		amount, err := strconv.ParseFloat(raw, 64)
+		if err != nil || amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid budget query parameter"})
+		}
+		budget = &amount
+	}
+
+	rows, totals, err := h.LaborRepo.GetLaborCost(c.UserContext(), startDate, endDate, groupBy)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Str("group_by", groupBy).Msg("Failed to estimate labor cost")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve labor cost",
+		})
+	}
+	report := models.LaborCostReport{
+		StartDate: startDate.Format(defaultDateFormat), EndDate: endDate.Format(defaultDateFormat),
+		GroupBy: groupBy, Rows: rows, Totals: *totals,
+	}
+	if budget != nil {
+		report.Budget = &models.LaborBudget{
+			Amount:             *budget,
+			ScheduledRemaining: math.Round((*budget-totals.ScheduledCost)*100) / 100,
+			ActualRemaining:    math.Round((*budget-totals.ActualCost)*100) / 100,
+			OverBudget:         totals.ScheduledCost > *budget || totals.ActualCost > *budget,
+		}
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Labor cost retrieved successfully", Data: report,
+	})
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	applogger "github.com/rakaarfi/attendance-system-be/internal/logger"
+	"github.com/rs/zerolog"
+)
+
+// reqLogger mengembalikan logger per-request (request_id, user_id, role) yang dipasang
+// middleware RequestLogContext/Protected di c.UserContext().
+// Level dan sampling mengikuti modul "handler" (LOG_LEVEL_HANDLER).
+// Jatuh ke logger global jika request tidak melewati middleware tersebut.
+func reqLogger(c *fiber.Ctx) *zerolog.Logger {
+	return applogger.Module(c.UserContext(), applogger.ModuleHandler)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/buildinfo"
+	"github.com/rakaarfi/attendance-system-be/internal/instances"
+	"github.com/rakaarfi/attendance-system-be/internal/maintenance"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// MaintenanceHandler menyalakan dan mematikan mode maintenance (pengaturan maintenance.*) dan
+// menampilkan versi aplikasi yang sedang berjalan (heartbeat app_instances).
+type MaintenanceHandler struct {
+	Maintenance  *maintenance.Controller
+	AppInstances repository.AppInstanceRepository
+	Validate     *validator.Validate
+}
+
+func NewMaintenanceHandler(controller *maintenance.Controller, appInstances repository.AppInstanceRepository) *MaintenanceHandler {
+	return &MaintenanceHandler{Maintenance: controller, AppInstances: appInstances, Validate: validator.New()}
+}
+
+// GetMaintenance godoc
+// @Summary Get maintenance mode status (Admin)
+// @Description Returns whether maintenance mode is on, the message and Retry-After sent to clients, whether check-ins are buffered, and how many check-ins this instance is holding.
+// @Tags Admin - Maintenance
+// @Produce json
+// @Success 200 {object} models.Response{data=models.MaintenanceStatus} "Maintenance status retrieved successfully"
+// @Security ApiKeyAuth
+// @Router /admin/maintenance [get]
+func (h *MaintenanceHandler) GetMaintenance(c *fiber.Ctx) error {
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Maintenance status retrieved successfully", Data: h.Maintenance.Status(c.UserContext()),
+	})
+}
+
+// EnableMaintenance godoc
+// @Summary Enable maintenance mode (Admin)
+// @Description Puts the API into maintenance mode, e.g. to run migrations safely during working hours: every non-admin route answers 503 with a Retry-After header and the message (admin routes, login and /health keep working). With buffer_checkins, check-ins are accepted with 202 instead and recorded with their original time once maintenance is disabled. Stored in the maintenance.* settings; other instances follow within SETTINGS_CACHE_TTL. Omitted fields use their defaults (no message, Retry-After 300 seconds, check-ins rejected).
+// @Tags Admin - Maintenance
+// @Accept json
+// @Produce json
+// @Param maintenance body models.EnableMaintenanceInput false "Message, Retry-After and check-in handling"
+// @Success 200 {object} models.Response{data=models.MaintenanceStatus} "Maintenance mode enabled"
+// @Failure 400 {object} models.Response "Invalid input"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/maintenance/enable [post]
+func (h *MaintenanceHandler) EnableMaintenance(c *fiber.Ctx) error {
+	input := new(models.EnableMaintenanceInput)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body", Data: err.Error()})
+		}
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Validation failed", Data: err.Error()})
+	}
+	adminID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	if err := h.Maintenance.Enable(c.UserContext(), *input, adminID); err != nil {
+		return h.updateFailed(c, err, adminID)
+	}
+	status := h.Maintenance.Status(c.UserContext())
+	reqLogger(c).Warn().Int("admin_id", adminID).Int("retry_after_seconds", status.RetryAfterSeconds).
+		Bool("buffer_checkins", status.BufferCheckIns).Msg("Maintenance mode enabled")
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Maintenance mode enabled", Data: status})
+}
+
+// DisableMaintenance godoc
+// @Summary Disable maintenance mode (Admin)
+// @Description Ends maintenance mode; all routes are served again and check-ins buffered during maintenance are recorded shortly after (MAINTENANCE_FLUSH_INTERVAL).
+// @Tags Admin - Maintenance
+// @Produce json
+// @Success 200 {object} models.Response{data=models.MaintenanceStatus} "Maintenance mode disabled"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/maintenance/disable [post]
+func (h *MaintenanceHandler) DisableMaintenance(c *fiber.Ctx) error {
+	adminID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	if err := h.Maintenance.Disable(c.UserContext(), adminID); err != nil {
+		return h.updateFailed(c, err, adminID)
+	}
+	status := h.Maintenance.Status(c.UserContext())
+	reqLogger(c).Warn().Int("admin_id", adminID).Int("queued_checkins", status.QueuedCheckIns).Msg("Maintenance mode disabled")
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Maintenance mode disabled", Data: status})
+}
+
+// GetAppVersions godoc
+// @Summary List running app versions (Admin)
+// @Description Lists the API instances that sent a heartbeat within APP_HEARTBEAT_TTL, grouped by app version. During a rolling deploy more than one version is alive (mixed = true); while that lasts `cmd/migrate` refuses destructive migrations (dropping or renaming tables/columns, changing column types, new NOT NULL constraints) because the older version still uses the old schema.
+// @Tags Admin - Maintenance
+// @Produce json
+// @Success 200 {object} models.Response{data=models.AppVersionsReport} "App versions retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/maintenance/app-versions [get]
+func (h *MaintenanceHandler) GetAppVersions(c *fiber.Ctx) error {
+	alive, err := instances.Alive(c.UserContext(), h.AppInstances, instances.TTLFromEnv())
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to list app instances")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve app versions"})
+	}
+	if alive == nil {
+		alive = []models.AppInstance{}
+	}
+	versions := instances.Summarize(alive)
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true,
+		Message: "App versions retrieved successfully",
+		Data: models.AppVersionsReport{
+			CurrentVersion: buildinfo.AppVersion(),
+			Mixed:          len(versions) > 1,
+			Versions:       versions,
+			Instances:      alive,
+		},
+	})
+}
+
+// updateFailed menjawab error dari Enable/Disable.
+func (h *MaintenanceHandler) updateFailed(c *fiber.Ctx, err error, adminID int) error {
+	var validationErr *settings.ValidationError
+	if errors.As(err, &validationErr) {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid maintenance settings", Data: validationErr.Fields})
+	}
+	reqLogger(c).Error().Err(err).Int("admin_id", adminID).Msg("Failed to update maintenance mode")
+	return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to update maintenance mode"})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// NotificationHandler melayani inbox notifikasi in-app user (lihat internal/inbox).
+type NotificationHandler struct {
+	NotificationRepo repository.NotificationRepository
+}
+
+func NewNotificationHandler(notificationRepo repository.NotificationRepository) *NotificationHandler {
+	return &NotificationHandler{NotificationRepo: notificationRepo}
+}
+
+// GetMyNotifications godoc
+// @Summary Get my notifications
+// @Description Retrieves the current user's in-app notification inbox, newest first. The same notifications are also delivered as push notifications when the user has registered devices.
+// @Tags User - Notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Limit of notifications per page"
+// @Success 200 {object} models.Response{data=[]models.Notification} "Notifications retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid unread parameter"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/notifications [get]
+func (h *NotificationHandler) GetMyNotifications(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	unreadOnly := false
+	if raw := c.Query("unread"); raw != "" {
+		if unreadOnly, err = strconv.ParseBool(raw); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid unread parameter, use true or false"})
+		}
+	}
+
+	pagination := utils.ParsePaginationParams(c)
+	notifications, total, err := h.NotificationRepo.GetNotificationsByUser(c.UserContext(), userID, unreadOnly, pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get notifications")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve notifications"})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Notifications retrieved successfully", notifications, meta))
+}
+
+// GetUnreadCount godoc
+// @Summary Get unread notification count
+// @Description Returns the number of unread notifications in the current user's inbox (for a badge).
+// @Tags User - Notifications
+// @Produce json
+// @Success 200 {object} models.Response{data=map[string]int} "Unread count retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	count, err := h.NotificationRepo.CountUnreadNotifications(c.UserContext(), userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to count unread notifications")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to count unread notifications"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Unread count retrieved successfully", Data: fiber.Map{"unread": count},
+	})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark a notification as read
+// @Description Marks one of the current user's notifications as read. Marking an already read notification keeps its original read time.
+// @Tags User - Notifications
+// @Produce json
+// @Param notificationId path int true "Notification ID"
+// @Success 200 {object} models.Response{data=models.Notification} "Notification marked as read"
+// @Failure 400 {object} models.Response "Invalid notification ID"
+// @Failure 404 {object} models.Response "Notification not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/notifications/{notificationId}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	id, err := strconv.Atoi(c.Params("notificationId"))
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid notification ID"})
+	}
+
+	notification, err := h.NotificationRepo.MarkNotificationRead(c.UserContext(), userID, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "Notification not found"})
+		}
+		reqLogger(c).Error().Err(err).Int("notification_id", id).Msg("Failed to mark notification read")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to mark notification as read"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Notification marked as read", Data: notification,
+	})
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all notifications as read
+// @Description Marks every unread notification of the current user as read and returns how many were updated.
+// @Tags User - Notifications
+// @Produce json
+// @Success 200 {object} models.Response{data=map[string]int} "Notifications marked as read"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	updated, err := h.NotificationRepo.MarkAllNotificationsRead(c.UserContext(), userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to mark all notifications read")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to mark notifications as read"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Notifications marked as read", Data: fiber.Map{"updated": updated},
+	})
+}
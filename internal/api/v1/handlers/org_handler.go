@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// OrgHandler melayani garis pelaporan: pengaturan atasan oleh admin, org-chart organisasi,
+// dan tim (bawahan langsung & tidak langsung) milik user yang login.
+type OrgHandler struct {
+	UserRepo repository.UserRepository
+	Validate *validator.Validate
+}
+
+func NewOrgHandler(userRepo repository.UserRepository) *OrgHandler {
+	return &OrgHandler{
+		UserRepo: userRepo,
+		Validate: validator.New(),
+	}
+}
+
+// startOfToday mengembalikan awal hari ini (zona waktu lokal server, sama seperti check-in).
+func startOfToday() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// buildOrgTree menyusun node datar (terurut depth) menjadi pohon dan mengembalikan akar-akarnya.
+func buildOrgTree(nodes []models.OrgChartNode) []*models.OrgChartNode {
+	byID := make(map[int]*models.OrgChartNode, len(nodes))
+	roots := []*models.OrgChartNode{}
+	for i := range nodes {
+		node := &nodes[i]
+		byID[node.UserID] = node
+		if node.Depth == 0 {
+			roots = append(roots, node)
+			continue
+		}
+		// Parent selalu sudah ada karena node terurut berdasarkan depth.
+		if parent, ok := byID[*node.ManagerID]; ok {
+			parent.Reports = append(parent.Reports, node)
+		}
+	}
+	return roots
+}
+
+// SetManager godoc
+// @Summary Set a user's manager
+// @Description Sets the direct manager of a user (reporting line). Send manager_id null to remove the manager. Assignments that would create a reporting cycle are rejected.
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param manager body models.SetManagerInput true "Manager to assign"
+// @Success 200 {object} models.Response "Manager updated successfully"
+// @Failure 400 {object} models.Response "Invalid input"
+// @Failure 404 {object} models.Response "User or manager not found"
+// @Failure 409 {object} models.Response "Assignment would create a reporting cycle"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId}/manager [put]
+func (h *OrgHandler) SetManager(c *fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("userId"))
+	if err != nil || userID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid User ID parameter"})
+	}
+	input := new(models.SetManagerInput)
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Error parsing set manager request body")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	if err := h.UserRepo.SetUserManager(c.UserContext(), userID, input.ManagerID); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "User or manager not found"})
+		case errors.Is(err, repository.ErrManagerCycle):
+			return c.Status(fiber.StatusConflict).JSON(models.Response{
+				Success: false, Message: "Manager cannot be the user or one of the user's reports",
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to set user manager")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to update manager"})
+	}
+	reqLogger(c).Info().Int("user_id", userID).Interface("manager_id", input.ManagerID).Msg("Admin updated user manager")
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Manager updated successfully"})
+}
+
+// GetOrgChart godoc
+// @Summary Get organization chart
+// @Description Returns the reporting tree of active users with today's presence status (present, checked_out, absent, off). Without root_id the whole organization is returned, rooted at users without a manager.
+// @Tags Admin - Users Management
+// @Produce json
+// @Param root_id query int false "Only return the subtree under this user"
+// @Success 200 {object} models.Response{data=[]models.OrgChartNode} "Org chart retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid root_id"
+// @Failure 404 {object} models.Response "Root user not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/org-chart [get]
+func (h *OrgHandler) GetOrgChart(c *fiber.Ctx) error {
+	var rootID *int
+	if raw := c.Query("root_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid root_id parameter"})
+		}
+		rootID = &id
+	}
+
+	nodes, err := h.UserRepo.GetReportingTree(c.UserContext(), rootID, startOfToday())
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get org chart")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve org chart"})
+	}
+	if rootID != nil && len(nodes) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Active user with ID %d not found", *rootID),
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Org chart retrieved successfully", Data: buildOrgTree(nodes),
+	})
+}
+
+// GetMyTeam godoc
+// @Summary Get my team
+// @Description Returns the current user's direct and indirect reports as a tree (rooted at the current user) with today's presence status.
+// @Tags User - Team
+// @Produce json
+// @Success 200 {object} models.Response{data=models.OrgChartNode} "Team retrieved successfully"
+// @Failure 404 {object} models.Response "User inactive or not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/team [get]
+func (h *OrgHandler) GetMyTeam(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+
+	nodes, err := h.UserRepo.GetReportingTree(c.UserContext(), &userID, startOfToday())
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get user team")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve team"})
+	}
+	roots := buildOrgTree(nodes)
+	if len(roots) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "User not found or inactive"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Team retrieved successfully", Data: roots[0],
+	})
+}
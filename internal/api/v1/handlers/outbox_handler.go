@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// OutboxHandler melayani peninjauan dead letter outbox efek samping (lihat internal/outbox).
+type OutboxHandler struct {
+	OutboxRepo repository.OutboxRepository
+}
+
+func NewOutboxHandler(outboxRepo repository.OutboxRepository) *OutboxHandler {
+	return &OutboxHandler{OutboxRepo: outboxRepo}
+}
+
+// GetDeadLetters godoc
+// @Summary Get dead-lettered side effects
+// @Description Lists outbox messages (notifications, webhooks, event stream publishes) that kept failing until OUTBOX_MAX_ATTEMPTS and were moved to the dead letter queue, newest first, with the last delivery error.
+// @Tags Admin - Outbox
+// @Produce json
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Limit of messages per page"
+// @Success 200 {object} models.Response{data=[]models.OutboxMessage} "Dead letters retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/outbox/dead-letters [get]
+func (h *OutboxHandler) GetDeadLetters(c *fiber.Ctx) error {
+	pagination := utils.ParsePaginationParams(c)
+	msgs, total, err := h.OutboxRepo.GetDeadOutboxMessages(c.UserContext(), pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get dead outbox messages")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve dead letters"})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Dead letters retrieved successfully", msgs, meta))
+}
+
+// RetryDeadLetter godoc
+// @Summary Retry a dead-lettered side effect
+// @Description Puts a dead-lettered outbox message back in the queue with its attempt count reset; the dispatcher delivers it on its next run.
+// @Tags Admin - Outbox
+// @Produce json
+// @Param messageId path int true "Outbox message ID"
+// @Success 200 {object} models.Response{data=models.OutboxMessage} "Message queued for retry"
+// @Failure 400 {object} models.Response "Invalid message ID"
+// @Failure 404 {object} models.Response "Message not found"
+// @Failure 409 {object} models.Response "Message is not dead-lettered"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/outbox/{messageId}/retry [post]
+func (h *OutboxHandler) RetryDeadLetter(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("messageId"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid message ID"})
+	}
+	msg, err := h.OutboxRepo.RetryOutboxMessage(c.UserContext(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "Outbox message not found"})
+		case errors.Is(err, repository.ErrOutboxMessageNotDead):
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+		}
+		reqLogger(c).Error().Err(err).Int64("outbox_id", id).Msg("Failed to retry outbox message")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retry message"})
+	}
+	reqLogger(c).Info().Int64("outbox_id", id).Str("destination", msg.Destination).Msg("Dead-lettered outbox message queued for retry")
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Message queued for retry", Data: msg})
+}
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// PayrollHandler melayani periode payroll: pembuatan, penutupan, dan alur reopen.
+// Absensi dalam periode yang sudah ditutup tidak bisa diubah (lihat attendanceLockedResponse).
+type PayrollHandler struct {
+	PayrollRepo repository.PayrollRepository
+	UserRepo    repository.UserRepository
+	Events      events.Publisher
+	Validate    *validator.Validate
+}
+
+func NewPayrollHandler(payrollRepo repository.PayrollRepository, userRepo repository.UserRepository, eventBus events.Publisher) *PayrollHandler {
+	return &PayrollHandler{
+		PayrollRepo: payrollRepo,
+		UserRepo:    userRepo,
+		Events:      eventBus,
+		Validate:    validator.New(),
+	}
+}
+
+// attendanceLockedResponse menulis response 409 jika err menandakan absensi terkunci:
+// *repository.PayrollPeriodClosedError (data.code PAYROLL_PERIOD_CLOSED) atau
+// *repository.AttendanceSignedOffError (data.code ATTENDANCE_SIGNED_OFF).
+// Mengembalikan handled=false untuk error lain.
+func attendanceLockedResponse(c *fiber.Ctx, err error) (handled bool, resp error) {
+	var closed *repository.PayrollPeriodClosedError
+	if errors.As(err, &closed) {
+		return true, c.Status(fiber.StatusConflict).JSON(models.Response{
+			Success: false,
+			Message: fmt.Sprintf("Attendance in payroll period %s to %s is locked because the period is closed", closed.Period.StartDate, closed.Period.EndDate),
+			Data:    fiber.Map{"code": models.PayrollPeriodClosedCode, "period": closed.Period},
+		})
+	}
+	var signed *repository.AttendanceSignedOffError
+	if errors.As(err, &signed) {
+		return true, c.Status(fiber.StatusConflict).JSON(models.Response{
+			Success: false,
+			Message: fmt.Sprintf("Attendance on %s is locked because it has been signed off", signed.SignOff.WorkDate),
+			Data:    fiber.Map{"code": models.AttendanceSignedOffCode, "sign_off": signed.SignOff},
+		})
+	}
+	return false, nil
+}
+
+// payrollPeriodIDParam membaca path param :periodId.
+func payrollPeriodIDParam(c *fiber.Ctx) (int, error) {
+	idStr := c.Params("periodId")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		reqLogger(c).Warn().Str("periodId_param", idStr).Msg("Invalid Payroll Period ID parameter")
+		return 0, fmt.Errorf("invalid payroll period ID parameter %q", idStr)
+	}
+	return id, nil
+}
+
+// invalidPayrollPeriodID adalah response 400 untuk path param ID yang tidak valid.
+func invalidPayrollPeriodID(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+		Success: false, Message: "Invalid Payroll Period ID parameter", Data: err.Error(),
+	})
+}
+
+// payrollTransitionError menulis response untuk kegagalan close/reopen.
+func payrollTransitionError(c *fiber.Ctx, id int, err error, action string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Payroll period with ID %d not found", id),
+		})
+	}
+	if errors.Is(err, repository.ErrPayrollPeriodStatus) {
+		return c.Status(fiber.StatusConflict).JSON(models.Response{
+			Success: false, Message: err.Error(),
+		})
+	}
+	reqLogger(c).Error().Err(err).Int("payroll_period_id", id).Msgf("Failed to %s payroll period", action)
+	return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+		Success: false, Message: fmt.Sprintf("Failed to %s payroll period", action),
+	})
+}
+
+// CreatePayrollPeriod godoc
+// @Summary Create payroll period
+// @Description Creates an open payroll period covering start_date..end_date (inclusive). Periods cannot overlap.
+// @Tags Admin - Payroll
+// @Accept json
+// @Produce json
+// @Param period body models.PayrollPeriodInput true "Payroll period dates"
+// @Success 201 {object} models.Response{data=models.PayrollPeriod} "Payroll period created successfully"
+// @Failure 400 {object} models.Response "Validation failed or end_date before start_date"
+// @Failure 409 {object} models.Response "Period overlaps an existing payroll period"
+// @Failure 500 {object} models.Response "Internal server error during payroll period creation"
+// @Security ApiKeyAuth
+// @Router /admin/payroll/periods [post]
+func (h *PayrollHandler) CreatePayrollPeriod(c *fiber.Ctx) error {
+	input := new(models.PayrollPeriodInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid request body: " + err.Error(),
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	start, _ := time.Parse(defaultDateFormat, input.StartDate)
+	end, _ := time.Parse(defaultDateFormat, input.EndDate)
+	if end.Before(start) {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "end_date cannot be before start_date",
+		})
+	}
+
+	adminUserID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting admin userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+
+	period := &models.PayrollPeriod{StartDate: input.StartDate, EndDate: input.EndDate, CreatedBy: &adminUserID}
+	id, err := h.PayrollRepo.CreatePayrollPeriod(c.UserContext(), period)
+	if err != nil {
+		if errors.Is(err, repository.ErrPayrollPeriodOverlap) {
+			return c.Status(fiber.StatusConflict).JSON(models.Response{
+				Success: false, Message: err.Error(),
+			})
+		}
+		reqLogger(c).Error().Err(err).Msg("Failed to create payroll period")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to create payroll period",
+		})
+	}
+	created, err := h.PayrollRepo.GetPayrollPeriodByID(c.UserContext(), id)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("payroll_period_id", id).Msg("Failed to reload created payroll period")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Payroll period created but could not be reloaded",
+		})
+	}
+
+	reqLogger(c).Info().Int("payroll_period_id", id).Int("admin_id", adminUserID).Msg("Payroll period created successfully")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Payroll period created successfully", Data: created,
+	})
+}
+
+// GetAllPayrollPeriods godoc
+// @Summary Get payroll periods
+// @Description Retrieves payroll periods with their open/closed status, newest first.
+// @Tags Admin - Payroll
+// @Produce json
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Limit of payroll periods per page"
+// @Success 200 {object} models.Response{data=[]models.PayrollPeriod} "Payroll periods retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error during payroll period retrieval"
+// @Security ApiKeyAuth
+// @Router /admin/payroll/periods [get]
+func (h *PayrollHandler) GetAllPayrollPeriods(c *fiber.Ctx) error {
+	pagination := utils.ParsePaginationParams(c)
+	periods, total, err := h.PayrollRepo.GetAllPayrollPeriods(c.UserContext(), pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get payroll periods")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve payroll periods",
+		})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Payroll periods retrieved successfully", periods, meta))
+}
+
+// GetPayrollPeriodByID godoc
+// @Summary Get payroll period by ID
+// @Description Retrieves a single payroll period, including who closed or last reopened it.
+// @Tags Admin - Payroll
+// @Produce json
+// @Param periodId path int true "Payroll Period ID"
+// @Success 200 {object} models.Response{data=models.PayrollPeriod} "Payroll period retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid Payroll Period ID parameter"
+// @Failure 404 {object} models.Response "Payroll period not found"
+// @Failure 500 {object} models.Response "Internal server error during payroll period retrieval"
+// @Security ApiKeyAuth
+// @Router /admin/payroll/periods/{periodId} [get]
+func (h *PayrollHandler) GetPayrollPeriodByID(c *fiber.Ctx) error {
+	id, err := payrollPeriodIDParam(c)
+	if err != nil {
+		return invalidPayrollPeriodID(c, err)
+	}
+	period, err := h.PayrollRepo.GetPayrollPeriodByID(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Payroll period with ID %d not found", id),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("payroll_period_id", id).Msg("Error getting payroll period by ID")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve payroll period",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Payroll period retrieved successfully", Data: period,
+	})
+}
+
+// ClosePayrollPeriod godoc
+// @Summary Close payroll period
+// @Description Closes a payroll period. Afterwards check-ins, check-outs and corrections of attendance whose check-in date falls in the period are rejected with 409 (data.code PAYROLL_PERIOD_CLOSED) until the period is reopened.
+// @Tags Admin - Payroll
+// @Produce json
+// @Param periodId path int true "Payroll Period ID"
+// @Success 200 {object} models.Response{data=models.PayrollPeriod} "Payroll period closed successfully"
+// @Failure 400 {object} models.Response "Invalid Payroll Period ID parameter"
+// @Failure 404 {object} models.Response "Payroll period not found"
+// @Failure 409 {object} models.Response "Payroll period is already closed"
+// @Failure 500 {object} models.Response "Internal server error while closing payroll period"
+// @Security ApiKeyAuth
+// @Router /admin/payroll/periods/{periodId}/close [post]
+func (h *PayrollHandler) ClosePayrollPeriod(c *fiber.Ctx) error {
+	id, err := payrollPeriodIDParam(c)
+	if err != nil {
+		return invalidPayrollPeriodID(c, err)
+	}
+	adminUserID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting admin userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+
+	period, err := h.PayrollRepo.ClosePayrollPeriod(c.UserContext(), id, adminUserID)
+	if err != nil {
+		return payrollTransitionError(c, id, err, "close")
+	}
+	publishEvent(c, h.Events, events.Event{
+		Name: events.PayrollClosed, UserID: adminUserID,
+		Data: map[string]any{"payroll_period_id": period.ID, "start_date": period.StartDate, "end_date": period.EndDate},
+	})
+
+	reqLogger(c).Info().Int("payroll_period_id", id).Int("admin_id", adminUserID).Msg("Payroll period closed")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Payroll period closed successfully", Data: period,
+	})
+}
+
+// ReopenPayrollPeriod godoc
+// @Summary Reopen payroll period
+// @Description Privileged flow to reopen a closed payroll period so its attendance can be changed again. The admin must re-enter their password and give a reason; both the reopen and the reason are recorded.
+// @Tags Admin - Payroll
+// @Accept json
+// @Produce json
+// @Param periodId path int true "Payroll Period ID"
+// @Param reopen body models.ReopenPayrollPeriodInput true "Reason and the admin's current password"
+// @Success 200 {object} models.Response{data=models.PayrollPeriod} "Payroll period reopened successfully"
+// @Failure 400 {object} models.Response "Invalid ID or validation failed"
+// @Failure 403 {object} models.Response "Password confirmation failed"
+// @Failure 404 {object} models.Response "Payroll period not found"
+// @Failure 409 {object} models.Response "Payroll period is not closed"
+// @Failure 500 {object} models.Response "Internal server error while reopening payroll period"
+// @Security ApiKeyAuth
+// @Router /admin/payroll/periods/{periodId}/reopen [post]
+func (h *PayrollHandler) ReopenPayrollPeriod(c *fiber.Ctx) error {
+	id, err := payrollPeriodIDParam(c)
+	if err != nil {
+		return invalidPayrollPeriodID(c, err)
+	}
+	input := new(models.ReopenPayrollPeriodInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid request body: " + err.Error(),
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	adminUserID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting admin userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+	// Konfirmasi ulang password: reopen membuka kembali data yang sudah dibayar
+	admin, err := h.UserRepo.GetUserByID(c.UserContext(), adminUserID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("admin_id", adminUserID).Msg("Failed to load admin for payroll reopen")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to verify user",
+		})
+	}
+	if !utils.CheckPasswordHash(input.Password, admin.Password) {
+		reqLogger(c).Warn().Int("admin_id", adminUserID).Int("payroll_period_id", id).Msg("Payroll reopen rejected: password confirmation failed")
+		return c.Status(fiber.StatusForbidden).JSON(models.Response{
+			Success: false, Message: "Password confirmation failed",
+		})
+	}
+
+	period, err := h.PayrollRepo.ReopenPayrollPeriod(c.UserContext(), id, adminUserID, input.Reason)
+	if err != nil {
+		return payrollTransitionError(c, id, err, "reopen")
+	}
+	publishEvent(c, h.Events, events.Event{
+		Name: events.PayrollReopened, UserID: adminUserID,
+		Data: map[string]any{"payroll_period_id": period.ID, "start_date": period.StartDate, "end_date": period.EndDate},
+	})
+
+	reqLogger(c).Warn().Int("payroll_period_id", id).Int("admin_id", adminUserID).Msg("Payroll period reopened")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Payroll period reopened successfully", Data: period,
+	})
+}
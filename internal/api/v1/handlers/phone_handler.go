@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/otp"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// PhoneHandler melayani verifikasi nomor HP dengan kode OTP SMS serta preferensi SMS user
+// (2FA login dan pengingat shift) untuk karyawan tanpa email kantor.
+type PhoneHandler struct {
+	UserRepo repository.UserRepository
+	OTP      *otp.Service // nil = SMS tidak dikonfigurasi (endpoint menjawab 404)
+	Events   events.Publisher
+	Validate *validator.Validate
+}
+
+func NewPhoneHandler(userRepo repository.UserRepository, otpService *otp.Service, eventBus events.Publisher) *PhoneHandler {
+	return &PhoneHandler{UserRepo: userRepo, OTP: otpService, Events: eventBus, Validate: validator.New()}
+}
+
+// SendPhoneVerification godoc
+// @Summary Send a phone verification code
+// @Description Sends a 6-digit code by SMS to the phone number on the current user's profile. The code expires after OTP_TTL (default 5 minutes); a new code can be requested once per OTP_RESEND_INTERVAL (default 1 minute) and replaces the previous one.
+// @Tags User - Profile Management
+// @Produce json
+// @Success 202 {object} models.Response{data=map[string]string} "Verification code sent, returns expires_at"
+// @Failure 400 {object} models.Response "No phone number on the profile"
+// @Failure 404 {object} models.Response "SMS is not configured"
+// @Failure 409 {object} models.Response "Phone number is already verified"
+// @Failure 429 {object} models.Response "Code was sent recently"
+// @Failure 503 {object} models.Response "SMS provider unavailable"
+// @Security ApiKeyAuth
+// @Router /user/phone/verification [post]
+func (h *PhoneHandler) SendPhoneVerification(c *fiber.Ctx) error {
+	if h.OTP == nil {
+		return smsDisabled(c)
+	}
+	user, handled, resp := currentUser(c, h.UserRepo)
+	if handled {
+		return resp
+	}
+	if user.PhoneVerifiedAt != nil {
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Phone number is already verified"})
+	}
+	code, err := h.OTP.Send(c.UserContext(), user, models.OTPPurposeVerifyPhone)
+	switch {
+	case errors.Is(err, otp.ErrNoPhone):
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Add a phone number to your profile first"})
+	case errors.Is(err, otp.ErrResendTooSoon):
+		return c.Status(fiber.StatusTooManyRequests).JSON(models.Response{
+			Success: false, Message: "Verification code was sent recently, please wait before resending", Data: fiber.Map{"expires_at": code.ExpiresAt},
+		})
+	case err != nil:
+		reqLogger(c).Error().Err(err).Int("user_id", user.ID).Msg("Failed to send phone verification code")
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.Response{Success: false, Message: "Failed to send verification code"})
+	}
+	return c.Status(fiber.StatusAccepted).JSON(models.Response{
+		Success: true, Message: "Verification code sent", Data: fiber.Map{"expires_at": code.ExpiresAt},
+	})
+}
+
+// ConfirmPhoneVerification godoc
+// @Summary Confirm the phone number
+// @Description Marks the phone number on the profile as verified using the code sent by SMS. The code stops working after OTP_MAX_ATTEMPTS (default 5) wrong tries or when the phone number is changed.
+// @Tags User - Profile Management
+// @Accept json
+// @Produce json
+// @Param code body models.ConfirmPhoneInput true "Verification code"
+// @Success 200 {object} models.Response{data=models.User} "Phone number verified"
+// @Failure 400 {object} models.Response "Validation failed or invalid/expired code"
+// @Failure 404 {object} models.Response "SMS is not configured"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/phone/verification/confirm [post]
+func (h *PhoneHandler) ConfirmPhoneVerification(c *fiber.Ctx) error {
+	if h.OTP == nil {
+		return smsDisabled(c)
+	}
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	input := new(models.ConfirmPhoneInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Validation failed", Data: err.Error()})
+	}
+	if err := h.OTP.Verify(c.UserContext(), userID, models.OTPPurposeVerifyPhone, input.Code); err != nil {
+		if errors.Is(err, otp.ErrInvalidCode) {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid or expired verification code"})
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to verify phone number")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to verify phone number"})
+	}
+	publishEvent(c, h.Events, events.Event{Name: events.PhoneVerified, UserID: userID, ActorUserID: &userID})
+
+	user, handled, resp := currentUser(c, h.UserRepo)
+	if handled {
+		return resp
+	}
+	reqLogger(c).Info().Int("user_id", userID).Msg("Phone number verified")
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "Phone number verified", Data: user})
+}
+
+// UpdateSMSPreferences godoc
+// @Summary Update my SMS preferences
+// @Description Turns SMS two-factor login and SMS shift reminders on or off. Turning either on requires a verified phone number; changing the phone number turns both off until the new number is verified.
+// @Tags User - Profile Management
+// @Accept json
+// @Produce json
+// @Param preferences body models.UpdateSMSPreferencesInput true "SMS preferences (omitted fields are unchanged)"
+// @Success 200 {object} models.Response{data=models.User} "SMS preferences updated"
+// @Failure 400 {object} models.Response "Invalid request body"
+// @Failure 404 {object} models.Response "SMS is not configured"
+// @Failure 409 {object} models.Response "Phone number is not verified"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/sms-preferences [put]
+func (h *PhoneHandler) UpdateSMSPreferences(c *fiber.Ctx) error {
+	if h.OTP == nil {
+		return smsDisabled(c)
+	}
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	input := new(models.UpdateSMSPreferencesInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	user, err := h.UserRepo.UpdateSMSPreferences(c.UserContext(), userID, input)
+	if err != nil {
+		if errors.Is(err, repository.ErrPhoneNotVerified) {
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Verify your phone number before enabling SMS features"})
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to update SMS preferences")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to update SMS preferences"})
+	}
+	publishEvent(c, h.Events, events.Event{
+		Name: events.SMSPreferences, UserID: userID, ActorUserID: &userID,
+		Data: map[string]any{"two_factor": user.SMSTwoFactor, "reminders": user.SMSReminders},
+	})
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: "SMS preferences updated", Data: user})
+}
+
+func smsDisabled(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "SMS is not configured"})
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/photos"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// mediaPhotoPath adalah path publik (relatif host API) untuk membuka foto lewat token.
+const mediaPhotoPath = "/api/v1/media/attendance-photos/"
+
+// PhotoHandler membuka foto check-in untuk admin. Admin menerima URL bertanda tangan yang
+// berlaku singkat; file dilayani lewat URL itu tanpa header Authorization agar bisa dipakai
+// langsung di tag <img>.
+type PhotoHandler struct {
+	PhotoRepo repository.AttendancePhotoRepository
+	Photos    *photos.Pipeline // nil = penyimpanan foto nonaktif (endpoint menjawab 404)
+}
+
+func NewPhotoHandler(photoRepo repository.AttendancePhotoRepository, pipeline *photos.Pipeline) *PhotoHandler {
+	return &PhotoHandler{PhotoRepo: photoRepo, Photos: pipeline}
+}
+
+// GetAttendancePhoto godoc
+// @Summary Get the check-in photo of an attendance record
+// @Description Returns the status of the check-in photo and, once processed, signed URLs for its thumbnail and original (EXIF stripped) that expire after ATTENDANCE_PHOTO_URL_TTL (default 5 minutes). The URLs need no Authorization header. Photos are deleted after the retention period (status purged). 404 when attendance photos are disabled or the record has no photo.
+// @Tags Admin - Attendance
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Success 200 {object} models.Response{data=models.AttendancePhotoLinks} "Attendance photo retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid attendance ID"
+// @Failure 404 {object} models.Response "Photo not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/{attendanceId}/photo [get]
+func (h *PhotoHandler) GetAttendancePhoto(c *fiber.Ctx) error {
+	attendanceID, err := idParam(c, "attendanceId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Attendance ID parameter"})
+	}
+	notFound := func() error {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Photo of attendance %d not found", attendanceID),
+		})
+	}
+	if h.Photos == nil {
+		return notFound()
+	}
+	photo, err := h.PhotoRepo.GetAttendancePhoto(c.UserContext(), attendanceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return notFound()
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceID).Msg("Error loading attendance photo")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve photo"})
+	}
+
+	links := models.AttendancePhotoLinks{Photo: photo}
+	if photo.Status == models.AttendancePhotoProcessed {
+		expiresAt := time.Now().Add(h.Photos.URLTTL())
+		thumbnail, errThumb := utils.GenerateMediaToken(photo.ID, models.AttendancePhotoThumbnail, expiresAt)
+		original, errOrig := utils.GenerateMediaToken(photo.ID, models.AttendancePhotoOriginal, expiresAt)
+		if err := errors.Join(errThumb, errOrig); err != nil {
+			reqLogger(c).Error().Err(err).Int("photo_id", photo.ID).Msg("Error signing attendance photo URLs")
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve photo"})
+		}
+		links.ThumbnailURL = mediaPhotoPath + thumbnail
+		links.OriginalURL = mediaPhotoPath + original
+		links.ExpiresAt = &expiresAt
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Attendance photo retrieved successfully", Data: links,
+	})
+}
+
+// ServeAttendancePhoto godoc
+// @Summary Open a check-in photo through a signed URL
+// @Description Public endpoint behind the signed URLs returned by GET /admin/attendance/{attendanceId}/photo. Streams the JPEG while the token is valid; expired, tampered or purged links return 404.
+// @Tags Public
+// @Produce jpeg
+// @Param token path string true "Signed photo token"
+// @Success 200 {file} binary "JPEG image"
+// @Failure 404 {object} models.Response "Photo not found or link expired"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Router /media/attendance-photos/{token} [get]
+func (h *PhotoHandler) ServeAttendancePhoto(c *fiber.Ctx) error {
+	notFound := func() error {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "Photo not found or link expired"})
+	}
+	if h.Photos == nil {
+		return notFound()
+	}
+	photoID, variant, err := utils.ParseMediaToken(c.Params("token"))
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Invalid attendance photo token")
+		return notFound()
+	}
+	photo, err := h.PhotoRepo.GetAttendancePhotoByID(c.UserContext(), photoID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return notFound()
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("photo_id", photoID).Msg("Error loading attendance photo")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to load photo"})
+	}
+	data, err := h.Photos.Open(c.UserContext(), photo, variant)
+	if errors.Is(err, photos.ErrNotProcessed) {
+		return notFound()
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("photo_id", photoID).Msg("Failed to open attendance photo")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to load photo"})
+	}
+
+	c.Set(fiber.HeaderContentType, "image/jpeg")
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	return c.Status(http.StatusOK).Send(data)
+}
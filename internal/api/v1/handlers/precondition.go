@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// Optimistic locking via HTTP precondition.
+// Klien mengirim versi yang terakhir dibaca lewat header If-Match (nilai ETag dari GET)
+// atau field "version" di body. Jika versi sudah usang, handler mengembalikan 412.
+
+// setVersionETag menulis header ETag berisi versi record (format: "3").
+func setVersionETag(c *fiber.Ctx, version int) {
+	if version > 0 {
+		c.Set(fiber.HeaderETag, strconv.Quote(strconv.Itoa(version)))
+	}
+}
+
+// resolveExpectedVersion mengembalikan versi yang diharapkan klien.
+// Header If-Match diutamakan; jika tidak ada, dipakai bodyVersion. 0 berarti tanpa precondition
+// (juga untuk If-Match: *).
+func resolveExpectedVersion(c *fiber.Ctx, bodyVersion int) (int, error) {
+	ifMatch := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if ifMatch == "" || ifMatch == "*" {
+		return bodyVersion, nil
+	}
+	tag := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid If-Match header, expected a version ETag such as \"3\"")
+	}
+	return version, nil
+}
+
+// preconditionFailed adalah response 412 standar untuk konflik versi.
+func preconditionFailed(c *fiber.Ctx, resource string) error {
+	return c.Status(fiber.StatusPreconditionFailed).JSON(models.Response{
+		Success: false, Message: fmt.Sprintf("%s was modified by another request; reload it and retry", resource),
+	})
+}
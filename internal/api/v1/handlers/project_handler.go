@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// ProjectHandler melayani project/cost center: CRUD oleh admin dan daftar project aktif
+// yang bisa dipilih user saat check-in.
+type ProjectHandler struct {
+	ProjectRepo repository.ProjectRepository
+	Validate    *validator.Validate
+}
+
+func NewProjectHandler(projectRepo repository.ProjectRepository) *ProjectHandler {
+	return &ProjectHandler{
+		ProjectRepo: projectRepo,
+		Validate:    validator.New(),
+	}
+}
+
+// parseProjectInput membaca & memvalidasi body, lalu membangun models.Project.
+// Error dikembalikan sebagai *fiber.Error berisi status & pesan untuk klien.
+func (h *ProjectHandler) parseProjectInput(c *fiber.Ctx) (*models.Project, *fiber.Error) {
+	input := new(models.ProjectInput)
+	if err := c.BodyParser(input); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+	input.Code = strings.TrimSpace(input.Code)
+	if err := h.Validate.Struct(input); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+	project := &models.Project{Code: input.Code, Name: input.Name, CostCenter: input.CostCenter, IsActive: true}
+	if input.IsActive != nil {
+		project.IsActive = *input.IsActive
+	}
+	return project, nil
+}
+
+// projectIDParam membaca path param :projectId.
+func projectIDParam(c *fiber.Ctx) (int, error) {
+	idStr := c.Params("projectId")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		reqLogger(c).Warn().Str("projectId_param", idStr).Msg("Invalid Project ID parameter")
+		return 0, fmt.Errorf("invalid project ID parameter %q", idStr)
+	}
+	return id, nil
+}
+
+// invalidProjectID adalah response 400 untuk path param ID yang tidak valid.
+func invalidProjectID(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+		Success: false, Message: "Invalid Project ID parameter", Data: err.Error(),
+	})
+}
+
+// CreateProject godoc
+// @Summary Create project
+// @Description Creates a project / cost center that employees can tag their attendance sessions with at check-in. Codes are unique.
+// @Tags Admin - Projects
+// @Accept json
+// @Produce json
+// @Param project body models.ProjectInput true "Project details"
+// @Success 201 {object} models.Response{data=models.Project} "Project created successfully"
+// @Failure 400 {object} models.Response "Validation failed"
+// @Failure 409 {object} models.Response "Project code already exists"
+// @Failure 500 {object} models.Response "Internal server error during project creation"
+// @Security ApiKeyAuth
+// @Router /admin/projects [post]
+func (h *ProjectHandler) CreateProject(c *fiber.Ctx) error {
+	project, ferr := h.parseProjectInput(c)
+	if ferr != nil {
+		return c.Status(ferr.Code).JSON(models.Response{Success: false, Message: ferr.Message})
+	}
+
+	id, err := h.ProjectRepo.CreateProject(c.UserContext(), project)
+	if err != nil {
+		if errors.Is(err, repository.ErrProjectCodeTaken) {
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+		}
+		reqLogger(c).Error().Err(err).Msg("Failed to create project")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to create project",
+		})
+	}
+	created, err := h.ProjectRepo.GetProjectByID(c.UserContext(), id)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("project_id", id).Msg("Failed to reload created project")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Project created but could not be reloaded",
+		})
+	}
+
+	reqLogger(c).Info().Int("project_id", id).Str("code", project.Code).Msg("Project created successfully")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Project created successfully", Data: created,
+	})
+}
+
+// GetAllProjects godoc
+// @Summary Get all projects
+// @Description Retrieves all projects, including inactive ones, ordered by code.
+// @Tags Admin - Projects
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.Project} "Projects retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error during project retrieval"
+// @Security ApiKeyAuth
+// @Router /admin/projects [get]
+func (h *ProjectHandler) GetAllProjects(c *fiber.Ctx) error {
+	return h.listProjects(c, false)
+}
+
+// GetActiveProjects godoc
+// @Summary Get projects available for check-in
+// @Description Retrieves the active projects an employee can pass as project_id when checking in.
+// @Tags User - Check In/Out
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.Project} "Projects retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error during project retrieval"
+// @Security ApiKeyAuth
+// @Router /user/projects [get]
+func (h *ProjectHandler) GetActiveProjects(c *fiber.Ctx) error {
+	return h.listProjects(c, true)
+}
+
+func (h *ProjectHandler) listProjects(c *fiber.Ctx, activeOnly bool) error {
+	projects, err := h.ProjectRepo.GetAllProjects(c.UserContext(), activeOnly)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Bool("active_only", activeOnly).Msg("Failed to get projects")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve projects",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Projects retrieved successfully", Data: projects,
+	})
+}
+
+// GetProjectByID godoc
+// @Summary Get project by ID
+// @Description Retrieves a single project.
+// @Tags Admin - Projects
+// @Produce json
+// @Param projectId path int true "Project ID"
+// @Success 200 {object} models.Response{data=models.Project} "Project retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid Project ID parameter"
+// @Failure 404 {object} models.Response "Project not found"
+// @Failure 500 {object} models.Response "Internal server error during project retrieval"
+// @Security ApiKeyAuth
+// @Router /admin/projects/{projectId} [get]
+func (h *ProjectHandler) GetProjectByID(c *fiber.Ctx) error {
+	id, err := projectIDParam(c)
+	if err != nil {
+		return invalidProjectID(c, err)
+	}
+	project, err := h.ProjectRepo.GetProjectByID(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Project with ID %d not found", id),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("project_id", id).Msg("Error getting project by ID")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve project",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Project retrieved successfully", Data: project,
+	})
+}
+
+// UpdateProject godoc
+// @Summary Update project
+// @Description Replaces the code, name, cost center and active flag of a project. Inactive projects can no longer be chosen at check-in; existing sessions keep their tag.
+// @Tags Admin - Projects
+// @Accept json
+// @Produce json
+// @Param projectId path int true "Project ID"
+// @Param project body models.ProjectInput true "Project details"
+// @Success 200 {object} models.Response "Project updated successfully"
+// @Failure 400 {object} models.Response "Invalid ID or validation failed"
+// @Failure 404 {object} models.Response "Project not found"
+// @Failure 409 {object} models.Response "Project code already exists"
+// @Failure 500 {object} models.Response "Internal server error during project update"
+// @Security ApiKeyAuth
+// @Router /admin/projects/{projectId} [put]
+func (h *ProjectHandler) UpdateProject(c *fiber.Ctx) error {
+	id, err := projectIDParam(c)
+	if err != nil {
+		return invalidProjectID(c, err)
+	}
+	project, ferr := h.parseProjectInput(c)
+	if ferr != nil {
+		return c.Status(ferr.Code).JSON(models.Response{Success: false, Message: ferr.Message})
+	}
+	project.ID = id
+
+	if err := h.ProjectRepo.UpdateProject(c.UserContext(), project); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Project with ID %d not found", id),
+			})
+		}
+		if errors.Is(err, repository.ErrProjectCodeTaken) {
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+		}
+		reqLogger(c).Error().Err(err).Int("project_id", id).Msg("Error updating project")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to update project",
+		})
+	}
+
+	reqLogger(c).Info().Int("project_id", id).Msg("Project updated successfully")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Project updated successfully",
+	})
+}
+
+// DeleteProject godoc
+// @Summary Delete project
+// @Description Deletes a project that no attendance session is tagged with. Used projects must be deactivated instead.
+// @Tags Admin - Projects
+// @Produce json
+// @Param projectId path int true "Project ID"
+// @Success 200 {object} models.Response "Project deleted successfully"
+// @Failure 400 {object} models.Response "Invalid Project ID parameter"
+// @Failure 404 {object} models.Response "Project not found"
+// @Failure 409 {object} models.Response "Project is referenced by attendance records"
+// @Failure 500 {object} models.Response "Internal server error during project deletion"
+// @Security ApiKeyAuth
+// @Router /admin/projects/{projectId} [delete]
+func (h *ProjectHandler) DeleteProject(c *fiber.Ctx) error {
+	id, err := projectIDParam(c)
+	if err != nil {
+		return invalidProjectID(c, err)
+	}
+	if err := h.ProjectRepo.DeleteProject(c.UserContext(), id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Project with ID %d not found", id),
+			})
+		}
+		if errors.Is(err, repository.ErrProjectInUse) {
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+		}
+		reqLogger(c).Error().Err(err).Int("project_id", id).Msg("Error deleting project")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to delete project",
+		})
+	}
+
+	reqLogger(c).Info().Int("project_id", id).Msg("Project deleted successfully")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Project deleted successfully",
+	})
+}
@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/reports"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	"github.com/rakaarfi/attendance-system-be/internal/storage"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// ReportHandler melayani ekspor laporan di background: admin meminta ekspor, job
+// report_exports membuat filenya, dan file bisa diunduh sampai masa retensinya habis.
+// Handler ini juga membuat snapshot laporan (ringkasan absensi yang dibekukan untuk payroll).
+type ReportHandler struct {
+	ReportRepo  repository.ReportExportRepository
+	PayrollRepo repository.PayrollRepository
+	Reports     *reports.Service
+	Storage     storage.Storage
+	Settings    *settings.Store
+	PresignTTL  time.Duration // Masa berlaku URL unduhan langsung (STORAGE_PRESIGN_TTL)
+	Validate    *validator.Validate
+}
+
+// snapshotMaxDays membatasi panjang periode satu snapshot laporan.
+const snapshotMaxDays = 366
+
+func NewReportHandler(reportRepo repository.ReportExportRepository, payrollRepo repository.PayrollRepository, service *reports.Service, store storage.Storage, settingsStore *settings.Store) *ReportHandler {
+	return &ReportHandler{
+		ReportRepo:  reportRepo,
+		PayrollRepo: payrollRepo,
+		Reports:     service,
+		Storage:     store,
+		Settings:    settingsStore,
+		PresignTTL:  configs.GetEnvDuration("STORAGE_PRESIGN_TTL", defaultPresignTTL),
+		Validate:    validator.New(),
+	}
+}
+
+// CreateReportExport godoc
+// @Summary Request a report export (Admin)
+// @Description Queues a CSV/XLSX report that is generated in the background (job report_exports). Poll GET /admin/reports/exports/{exportId} until status is completed, then download it. The file is kept for retention_hours (default REPORT_EXPORT_RETENTION, 7 days; max 720) after it is generated and then deleted; its download link then returns 410 Gone.
+// @Tags Admin - Reports
+// @Accept json
+// @Produce json
+// @Param export body models.ReportExportInput true "Report type, format, filter and retention"
+// @Success 202 {object} models.Response{data=models.ReportExport} "Report export queued"
+// @Failure 400 {object} models.Response "Invalid request body or validation failed"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/reports/exports [post]
+func (h *ReportHandler) CreateReportExport(c *fiber.Ctx) error {
+	adminID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	input := new(models.ReportExportInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	params, err := json.Marshal(input.Filter)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error encoding report export filter")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to queue report export"})
+	}
+	retention := h.Reports.DefaultRetention()
+	if input.RetentionHours > 0 {
+		retention = time.Duration(input.RetentionHours) * time.Hour
+	}
+
+	report := &models.ReportExport{
+		ReportType:       input.ReportType,
+		Format:           input.Format,
+		Params:           params,
+		RequestedBy:      &adminID,
+		RetentionSeconds: int(retention / time.Second),
+	}
+	if err := h.ReportRepo.CreateReportExport(c.UserContext(), report); err != nil {
+		reqLogger(c).Error().Err(err).Str("report_type", input.ReportType).Msg("Failed to queue report export")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to queue report export"})
+	}
+	reqLogger(c).Info().Int("report_export_id", report.ID).Str("report_type", report.ReportType).Str("format", report.Format).Msg("Report export queued")
+	return c.Status(fiber.StatusAccepted).JSON(models.Response{
+		Success: true, Message: "Report export queued", Data: report,
+	})
+}
+
+// GetReportExports godoc
+// @Summary List report exports (Admin)
+// @Description Lists requested report exports, newest first, with their status and expiry.
+// @Tags Admin - Reports
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.Response{data=[]models.ReportExport} "Report exports retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/reports/exports [get]
+func (h *ReportHandler) GetReportExports(c *fiber.Ctx) error {
+	pagination := utils.ParsePaginationParams(c)
+	exports, total, err := h.ReportRepo.GetReportExports(c.UserContext(), pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get report exports")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve report exports"})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Report exports retrieved successfully", exports, meta))
+}
+
+// GetReportExport godoc
+// @Summary Get a report export (Admin)
+// @Description Returns the status of one report export.
+// @Tags Admin - Reports
+// @Produce json
+// @Param exportId path int true "Report export ID"
+// @Success 200 {object} models.Response{data=models.ReportExport} "Report export retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid export ID"
+// @Failure 404 {object} models.Response "Report export not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/reports/exports/{exportId} [get]
+func (h *ReportHandler) GetReportExport(c *fiber.Ctx) error {
+	report, handled, resp := h.loadReportExport(c)
+	if handled {
+		return resp
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Report export retrieved successfully", Data: report,
+	})
+}
+
+// DownloadReportExport godoc
+// @Summary Download a report export (Admin)
+// @Description Downloads a completed report export. With object storage (STORAGE_BACKEND=s3) the response is a 302 redirect to a short-lived presigned URL that never outlives the export. Returns 409 while the export is pending or if it failed, and 410 Gone once its retention period has passed.
+// @Tags Admin - Reports
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param exportId path int true "Report export ID"
+// @Success 200 {file} file "Report export file"
+// @Success 302 {string} string "Redirect to a presigned storage URL"
+// @Failure 400 {object} models.Response "Invalid export ID"
+// @Failure 404 {object} models.Response "Report export not found"
+// @Failure 409 {object} models.Response "Report export is not ready or failed"
+// @Failure 410 {object} models.Response "Report export has expired"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/reports/exports/{exportId}/download [get]
+func (h *ReportHandler) DownloadReportExport(c *fiber.Ctx) error {
+	report, handled, resp := h.loadReportExport(c)
+	if handled {
+		return resp
+	}
+	now := time.Now()
+	if reports.Expired(report, now) {
+		return c.Status(fiber.StatusGone).JSON(models.Response{Success: false, Message: "Report export has expired"})
+	}
+	switch report.Status {
+	case models.ReportExportPending:
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Report export is not ready yet", Data: report})
+	case models.ReportExportFailed:
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Report export failed", Data: report})
+	}
+	if report.StorageKey == nil || report.FileName == nil || report.ContentType == nil {
+		reqLogger(c).Error().Int("report_export_id", report.ID).Msg("Completed report export has no file")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to download report export"})
+	}
+
+	// Backend dengan URL presigned (S3): URL tidak boleh berlaku melewati expires_at.
+	if presigner, ok := h.Storage.(storage.Presigner); ok {
+		ttl := h.PresignTTL
+		if report.ExpiresAt != nil {
+			ttl = min(ttl, report.ExpiresAt.Sub(now))
+		}
+		url, err := presigner.PresignGet(c.UserContext(), *report.StorageKey, ttl, storage.GetOptions{FileName: *report.FileName, ContentType: *report.ContentType})
+		if err != nil {
+			reqLogger(c).Error().Err(err).Int("report_export_id", report.ID).Str("backend", h.Storage.Backend()).Msg("Failed to presign report export download")
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to download report export"})
+		}
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+		return c.Redirect(url, fiber.StatusFound)
+	}
+
+	body, err := h.Storage.Open(c.UserContext(), *report.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return c.Status(fiber.StatusGone).JSON(models.Response{Success: false, Message: "Report export has expired"})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("report_export_id", report.ID).Str("backend", h.Storage.Backend()).Msg("Failed to open report export from storage")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to download report export"})
+	}
+	size := -1
+	if report.SizeBytes != nil {
+		size = int(*report.SizeBytes)
+	}
+	c.Attachment(*report.FileName)
+	c.Set(fiber.HeaderContentType, *report.ContentType)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	// Fiber menutup body (io.Closer) setelah response terkirim.
+	return c.Status(http.StatusOK).SendStream(body, size)
+}
+
+// loadReportExport membaca ekspor dari path param exportId.
+func (h *ReportHandler) loadReportExport(c *fiber.Ctx) (report *models.ReportExport, handled bool, resp error) {
+	id, err := idParam(c, "exportId")
+	if err != nil {
+		return nil, true, c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Export ID parameter"})
+	}
+	report, err = h.ReportRepo.GetReportExportByID(c.UserContext(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, true, c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Report export with ID %d not found", id),
+		})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("report_export_id", id).Msg("Error loading report export")
+		return nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve report export"})
+	}
+	return report, false, nil
+}
+
+// CreateReportSnapshot godoc
+// @Summary Freeze a report snapshot (Admin)
+// @Description Captures a frozen copy of the attendance summary of a period: per user the completed sessions, days worked, open sessions and hours breakdown (payroll.* settings), based on check-in date. The period comes from payroll_period_id, or from start_date and end_date (max 366 days). The rows are stored with a SHA-256 checksum and never change afterwards, so the numbers given to payroll don't drift when attendance is corrected later.
+// @Tags Admin - Reports
+// @Accept json
+// @Produce json
+// @Param snapshot body models.ReportSnapshotInput true "Payroll period or date range, and an optional label"
+// @Success 201 {object} models.Response{data=models.ReportSnapshot} "Report snapshot created"
+// @Failure 400 {object} models.Response "Invalid request body, validation failed or invalid period"
+// @Failure 404 {object} models.Response "Payroll period not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/reports/snapshots [post]
+func (h *ReportHandler) CreateReportSnapshot(c *fiber.Ctx) error {
+	adminID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	input := new(models.ReportSnapshotInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	startStr, endStr := input.StartDate, input.EndDate
+	if input.PayrollPeriodID != nil {
+		period, err := h.PayrollRepo.GetPayrollPeriodByID(c.UserContext(), *input.PayrollPeriodID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Payroll period with ID %d not found", *input.PayrollPeriodID),
+			})
+		}
+		if err != nil {
+			reqLogger(c).Error().Err(err).Int("payroll_period_id", *input.PayrollPeriodID).Msg("Error loading payroll period for report snapshot")
+			return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create report snapshot"})
+		}
+		startStr, endStr = period.StartDate, period.EndDate
+	}
+	startDate, errStart := time.Parse(defaultDateFormat, startStr)
+	endDate, errEnd := time.Parse(defaultDateFormat, endStr)
+	if errStart != nil || errEnd != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid date format. Please use YYYY-MM-DD."})
+	}
+	if endDate.Before(startDate) {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "end_date must not be before start_date"})
+	}
+	if int(endDate.Sub(startDate).Hours()/24)+1 > snapshotMaxDays {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Snapshot period must not exceed %d days", snapshotMaxDays),
+		})
+	}
+
+	sessions, err := h.ReportRepo.GetSnapshotSessions(c.UserContext(), startDate, endDate)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Str("start_date", startStr).Str("end_date", endStr).Msg("Failed to get sessions for report snapshot")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create report snapshot"})
+	}
+	rows := reports.SummarizeSessions(sessions, h.Settings.HourRules(c.UserContext()))
+	checksum, err := reports.SnapshotChecksum(rows)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to compute report snapshot checksum")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create report snapshot"})
+	}
+
+	snapshot := &models.ReportSnapshot{
+		Label:           input.Label,
+		StartDate:       startStr,
+		EndDate:         endStr,
+		PayrollPeriodID: input.PayrollPeriodID,
+		Checksum:        checksum,
+		CreatedBy:       &adminID,
+		Rows:            rows,
+	}
+	err = h.ReportRepo.CreateReportSnapshot(c.UserContext(), snapshot)
+	if errors.Is(err, pgx.ErrNoRows) && input.PayrollPeriodID != nil { // Periode dihapus setelah dibaca
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Payroll period with ID %d not found", *input.PayrollPeriodID),
+		})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Str("start_date", startStr).Str("end_date", endStr).Msg("Failed to create report snapshot")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create report snapshot"})
+	}
+	valid := true
+	totals := reports.SnapshotTotals(rows)
+	snapshot.ChecksumValid, snapshot.Totals = &valid, &totals
+
+	reqLogger(c).Info().Int("report_snapshot_id", snapshot.ID).Str("start_date", startStr).Str("end_date", endStr).Int("row_count", snapshot.RowCount).Msg("Report snapshot created")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Report snapshot created", Data: snapshot,
+	})
+}
+
+// GetReportSnapshots godoc
+// @Summary List report snapshots (Admin)
+// @Description Lists frozen report snapshots, newest first, without their rows.
+// @Tags Admin - Reports
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.Response{data=[]models.ReportSnapshot} "Report snapshots retrieved successfully"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/reports/snapshots [get]
+func (h *ReportHandler) GetReportSnapshots(c *fiber.Ctx) error {
+	pagination := utils.ParsePaginationParams(c)
+	snapshots, total, err := h.ReportRepo.GetReportSnapshots(c.UserContext(), pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get report snapshots")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve report snapshots"})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Report snapshots retrieved successfully", snapshots, meta))
+}
+
+// GetReportSnapshot godoc
+// @Summary Get a report snapshot (Admin)
+// @Description Returns one frozen report snapshot with its rows and totals exactly as captured. checksum_valid is recomputed from the stored rows on every read; false means the stored copy no longer matches its checksum.
+// @Tags Admin - Reports
+// @Produce json
+// @Param snapshotId path int true "Report snapshot ID"
+// @Success 200 {object} models.Response{data=models.ReportSnapshot} "Report snapshot retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid snapshot ID"
+// @Failure 404 {object} models.Response "Report snapshot not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/reports/snapshots/{snapshotId} [get]
+func (h *ReportHandler) GetReportSnapshot(c *fiber.Ctx) error {
+	id, err := idParam(c, "snapshotId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Snapshot ID parameter"})
+	}
+	snapshot, err := h.ReportRepo.GetReportSnapshotByID(c.UserContext(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Report snapshot with ID %d not found", id),
+		})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("report_snapshot_id", id).Msg("Error loading report snapshot")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve report snapshot"})
+	}
+	checksum, err := reports.SnapshotChecksum(snapshot.Rows)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("report_snapshot_id", id).Msg("Failed to recompute report snapshot checksum")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve report snapshot"})
+	}
+	valid := checksum == snapshot.Checksum
+	if !valid {
+		reqLogger(c).Warn().Int("report_snapshot_id", id).Msg("Report snapshot checksum mismatch")
+	}
+	totals := reports.SnapshotTotals(snapshot.Rows)
+	snapshot.ChecksumValid, snapshot.Totals = &valid, &totals
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Report snapshot retrieved successfully", Data: snapshot,
+	})
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+const (
+	// maxRoutePoints membatasi titik pada ringkasan rute; titik lain dijarangkan.
+	maxRoutePoints = 200
+	// routeNoisyAccuracyM: ping dengan akurasi lebih buruk dari ini tidak dihitung ke jarak.
+	routeNoisyAccuracyM = 100
+	// pingClockSkew adalah toleransi jam perangkat yang lebih cepat dari server.
+	pingClockSkew = time.Minute
+)
+
+// RouteHandler melayani pelacakan rute sesi field: ping lokasi berkala dari karyawan selama
+// sesi terbuka, dan ringkasan rute untuk karyawan itu sendiri serta atasannya. Privasi:
+// ping hanya diterima untuk sesi field yang masih terbuka, atasan hanya melihat rute bawahannya
+// dengan koordinat dibulatkan (route.manager_precision) dan setiap aksesnya diaudit, dan ping
+// dihapus setelah route.retention_days (lihat jobs.AttendanceRouteRetention).
+type RouteHandler struct {
+	AttendanceRepo repository.AttendanceRepository
+	UserRepo       repository.UserRepository
+	Settings       *settings.Store
+	Events         events.Publisher
+	Validate       *validator.Validate
+}
+
+func NewRouteHandler(attRepo repository.AttendanceRepository, userRepo repository.UserRepository, settingsStore *settings.Store, eventBus events.Publisher) *RouteHandler {
+	return &RouteHandler{
+		AttendanceRepo: attRepo,
+		UserRepo:       userRepo,
+		Settings:       settingsStore,
+		Events:         eventBus,
+		Validate:       validator.New(),
+	}
+}
+
+// RecordPing godoc
+// @Summary Send a location ping
+// @Description Records the current location during the caller's open field-mode session (check-in with mode=field). Pings closer together than route.ping_interval_seconds (default 60) are rejected with 429; recorded_at may be set by the device for pings sent late (not before check-in, not in the future). Pings are deleted after route.retention_days (default 30).
+// @Tags User - Check In/Out
+// @Accept json
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Param ping body models.AttendancePingInput true "Location"
+// @Success 201 {object} models.Response{data=models.AttendancePing} "Ping recorded"
+// @Failure 400 {object} models.Response "Invalid ID, validation failed or recorded_at outside the session"
+// @Failure 404 {object} models.Response "Attendance record not found"
+// @Failure 409 {object} models.Response "Session is closed, not in field mode, or has too many pings"
+// @Failure 429 {object} models.Response "Ping sent too soon after the previous one"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/attendance/{attendanceId}/ping [post]
+func (h *RouteHandler) RecordPing(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	attendanceID, err := idParam(c, "attendanceId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Attendance ID parameter"})
+	}
+	input := new(models.AttendancePingInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Validation failed", Data: err.Error()})
+	}
+	now := time.Now()
+	recordedAt := now
+	if input.RecordedAt != nil {
+		if input.RecordedAt.After(now.Add(pingClockSkew)) {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "recorded_at cannot be in the future"})
+		}
+		recordedAt = *input.RecordedAt
+	}
+
+	ping := &models.AttendancePing{
+		AttendanceID: attendanceID,
+		RecordedAt:   recordedAt.UTC().Truncate(time.Second),
+		Latitude:     *input.Latitude,
+		Longitude:    *input.Longitude,
+		AccuracyM:    input.AccuracyM,
+	}
+	err = h.AttendanceRepo.RecordAttendancePing(c.UserContext(), userID, ping, h.Settings.RoutePingInterval(c.UserContext()))
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// Record milik user lain diperlakukan sama dengan record yang tidak ada.
+		return attendanceNotFound(c, attendanceID)
+	case errors.Is(err, repository.ErrNotCheckedIn):
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Attendance session is already checked out"})
+	case errors.Is(err, repository.ErrNotFieldSession), errors.Is(err, repository.ErrRouteLimitReached):
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+	case errors.Is(err, repository.ErrPingOutsideSession):
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "recorded_at is before check-in"})
+	case errors.Is(err, repository.ErrPingTooSoon):
+		return c.Status(fiber.StatusTooManyRequests).JSON(models.Response{Success: false, Message: err.Error()})
+	case err != nil:
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceID).Msg("Failed to record location ping")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to record location ping"})
+	}
+	return c.Status(fiber.StatusCreated).JSON(models.Response{Success: true, Message: "Ping recorded", Data: ping})
+}
+
+// GetMyRoute godoc
+// @Summary Get the route of my field session
+// @Description Summarizes the location pings of one of the caller's attendance sessions: number of pings, distance travelled (pings less accurate than 100 m are left out), first/last ping and up to 200 evenly spaced points at full precision.
+// @Tags User - Check In/Out
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Success 200 {object} models.Response{data=models.AttendanceRoute} "Route retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid ID"
+// @Failure 404 {object} models.Response "Attendance record not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/attendance/{attendanceId}/route [get]
+func (h *RouteHandler) GetMyRoute(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	att, handled, resp := h.attendance(c)
+	if handled {
+		return resp
+	}
+	if att.UserID != userID {
+		return attendanceNotFound(c, att.ID)
+	}
+	return h.route(c, att, 6)
+}
+
+// GetReportRoute godoc
+// @Summary Get the route of a team member's field session
+// @Description Route summary of an attendance session of one of the manager's direct or indirect reports. Coordinates are rounded to route.manager_precision decimals (default 3, about 100 m) and every view is recorded in the employee's audit log.
+// @Tags Manager - Attendance
+// @Produce json
+// @Param attendanceId path int true "Attendance ID"
+// @Success 200 {object} models.Response{data=models.AttendanceRoute} "Route retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid ID"
+// @Failure 404 {object} models.Response "Attendance record not found or not of a report"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /manager/attendance/{attendanceId}/route [get]
+func (h *RouteHandler) GetReportRoute(c *fiber.Ctx) error {
+	managerID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	att, handled, resp := h.attendance(c)
+	if handled {
+		return resp
+	}
+	ok, err := h.UserRepo.IsInReportingLine(c.UserContext(), managerID, att.UserID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("attendance_id", att.ID).Msg("Failed to check reporting line")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve route"})
+	}
+	if !ok {
+		// Sesi di luar tim tidak dibedakan dari sesi yang tidak ada.
+		return attendanceNotFound(c, att.ID)
+	}
+	publishEvent(c, h.Events, events.Event{
+		Name: events.RouteViewed, UserID: att.UserID, ActorUserID: &managerID,
+		Data: map[string]any{"attendance_id": att.ID},
+	})
+	return h.route(c, att, h.Settings.Int(c.UserContext(), settings.KeyRoutePrecision))
+}
+
+// attendance memuat record absensi dari parameter attendanceId.
+func (h *RouteHandler) attendance(c *fiber.Ctx) (*models.Attendance, bool, error) {
+	attendanceID, err := idParam(c, "attendanceId")
+	if err != nil {
+		return nil, true, c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Attendance ID parameter"})
+	}
+	att, err := h.AttendanceRepo.GetAttendanceByID(c.UserContext(), attendanceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, true, attendanceNotFound(c, attendanceID)
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceID).Msg("Failed to get attendance for route")
+		return nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve route"})
+	}
+	return att, false, nil
+}
+
+// route menulis ringkasan rute att dengan koordinat dibulatkan ke precision desimal.
+func (h *RouteHandler) route(c *fiber.Ctx, att *models.Attendance, precision int) error {
+	pings, err := h.AttendanceRepo.GetAttendancePings(c.UserContext(), att.ID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("attendance_id", att.ID).Msg("Failed to get attendance pings")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve route"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Route retrieved successfully", Data: summarizeRoute(att, pings, precision),
+	})
+}
+
+func attendanceNotFound(c *fiber.Ctx, attendanceID int) error {
+	return c.Status(fiber.StatusNotFound).JSON(models.Response{
+		Success: false, Message: fmt.Sprintf("Attendance record with ID %d not found", attendanceID),
+	})
+}
+
+// summarizeRoute menghitung jarak dari semua ping yang cukup akurat dan menjarangkan titik
+// menjadi paling banyak maxRoutePoints (titik pertama & terakhir selalu ikut).
+func summarizeRoute(att *models.Attendance, pings []models.AttendancePing, precision int) *models.AttendanceRoute {
+	route := &models.AttendanceRoute{
+		AttendanceID: att.ID,
+		UserID:       att.UserID,
+		CheckInAt:    att.CheckInAt,
+		CheckOutAt:   att.CheckOutAt,
+		PingCount:    len(pings),
+		Precision:    precision,
+		Points:       []models.RoutePoint{},
+	}
+	if len(pings) == 0 {
+		return route
+	}
+	route.FirstPingAt, route.LastPingAt = &pings[0].RecordedAt, &pings[len(pings)-1].RecordedAt
+
+	var distance float64
+	var prev *models.AttendancePing
+	for i := range pings {
+		p := &pings[i]
+		if p.AccuracyM != nil && *p.AccuracyM > routeNoisyAccuracyM {
+			continue
+		}
+		if prev != nil {
+			distance += haversineKm(prev.Latitude, prev.Longitude, p.Latitude, p.Longitude)
+		}
+		prev = p
+	}
+	route.DistanceKm = math.Round(distance*100) / 100
+
+	step := max((len(pings)+maxRoutePoints-3)/(maxRoutePoints-1), 1)
+	for i := 0; i < len(pings); i += step {
+		route.Points = append(route.Points, routePoint(pings[i], precision))
+	}
+	if last := len(pings) - 1; last%step != 0 {
+		route.Points = append(route.Points, routePoint(pings[last], precision))
+	}
+	return route
+}
+
+func routePoint(p models.AttendancePing, precision int) models.RoutePoint {
+	scale := math.Pow10(precision)
+	return models.RoutePoint{
+		Latitude:   math.Round(p.Latitude*scale) / scale,
+		Longitude:  math.Round(p.Longitude*scale) / scale,
+		RecordedAt: p.RecordedAt,
+	}
+}
+
+// haversineKm adalah jarak lingkaran besar antara dua koordinat dalam kilometer.
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	rad := math.Pi / 180
+	dLat, dLon := (lat2-lat1)*rad, (lon2-lon1)*rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
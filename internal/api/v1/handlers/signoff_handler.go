@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// errNotDelegated dikembalikan actingManager jika caller tidak memegang delegasi aktif.
+var errNotDelegated = errors.New("you have no active delegation from this manager")
+
+// SignOffHandler melayani sign-off harian absensi tim oleh atasan dan laporan tanggal yang
+// belum di-sign-off. Absensi yang sudah di-sign-off tidak bisa diubah (lihat attendanceLockedResponse).
+// Pemegang delegasi aktif (lihat DelegationHandler) dapat bertindak atas nama atasannya.
+type SignOffHandler struct {
+	SignOffRepo    repository.SignOffRepository
+	DelegationRepo repository.DelegationRepository
+	Settings       *settings.Store
+	Events         events.Publisher
+	Validate       *validator.Validate
+}
+
+func NewSignOffHandler(signOffRepo repository.SignOffRepository, delegationRepo repository.DelegationRepository, settingsStore *settings.Store, eventBus events.Publisher) *SignOffHandler {
+	return &SignOffHandler{
+		SignOffRepo:    signOffRepo,
+		DelegationRepo: delegationRepo,
+		Settings:       settingsStore,
+		Events:         eventBus,
+		Validate:       validator.New(),
+	}
+}
+
+// actingManager mengembalikan atasan yang wewenangnya dipakai caller: caller sendiri jika
+// onBehalfOf kosong, atau onBehalfOf jika caller memegang delegasi aktif darinya hari ini.
+func (h *SignOffHandler) actingManager(c *fiber.Ctx, callerID int, onBehalfOf *int) (int, error) {
+	if onBehalfOf == nil || *onBehalfOf == callerID {
+		return callerID, nil
+	}
+	active, err := h.DelegationRepo.HasActiveDelegation(c.UserContext(), *onBehalfOf, callerID, startOfToday())
+	if err != nil {
+		return 0, err
+	}
+	if !active {
+		return 0, errNotDelegated
+	}
+	return *onBehalfOf, nil
+}
+
+// delegationErrorResponse menulis response untuk error actingManager.
+func delegationErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errNotDelegated) {
+		return c.Status(fiber.StatusForbidden).JSON(models.Response{Success: false, Message: err.Error()})
+	}
+	reqLogger(c).Error().Err(err).Msg("Failed to check approval delegation")
+	return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to check delegation"})
+}
+
+// SignOffDay godoc
+// @Summary Sign off team attendance for a day
+// @Description Lets a manager verify the attendance of their direct and indirect reports for one day (all reports when user_ids is empty). A user holding an active delegation can sign off for the delegating manager's team with on_behalf_of; the signer and the manager are both recorded in the audit log. Signed-off days are locked: check-ins, check-outs, project switches and corrections on that date are rejected with 409 (data.code ATTENDANCE_SIGNED_OFF). Exceptions (no_show, late, unscheduled, corrected) are flagged per user. Users outside the team are reported as not_found, users with a still-open session as skipped.
+// @Tags Manager - Attendance
+// @Accept json
+// @Produce json
+// @Param sign_off body models.SignOffInput true "Day to sign off"
+// @Success 200 {object} models.Response{data=[]models.SignOffResult} "Attendance signed off"
+// @Failure 400 {object} models.Response "Validation failed or date in the future"
+// @Failure 403 {object} models.Response "Caller has no team members or no active delegation"
+// @Failure 500 {object} models.Response "Internal server error during sign-off"
+// @Security ApiKeyAuth
+// @Router /manager/attendance/sign-off [post]
+func (h *SignOffHandler) SignOffDay(c *fiber.Ctx) error {
+	callerID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+
+	input := new(models.SignOffInput)
+	if err := c.BodyParser(input); err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Error parsing sign-off request body")
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	// Tanggal kerja dalam zona waktu server, sama seperti check-in.
+	day, _ := time.ParseInLocation(defaultDateFormat, input.Date, time.Local)
+	if day.After(startOfToday()) {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Cannot sign off a future date"})
+	}
+	managerID, err := h.actingManager(c, callerID, input.OnBehalfOf)
+	if err != nil {
+		return delegationErrorResponse(c, err)
+	}
+
+	results, err := h.SignOffRepo.SignOffDay(c.UserContext(), managerID, callerID, day, input.UserIDs, h.Settings.GracePeriod(c.UserContext()), input.Note)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("manager_id", managerID).Str("date", input.Date).Msg("Failed to sign off attendance")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to sign off attendance"})
+	}
+	if len(results) == 0 {
+		return c.Status(fiber.StatusForbidden).JSON(models.Response{
+			Success: false, Message: "Only managers with team members can sign off attendance",
+		})
+	}
+
+	summary := map[string]int{
+		models.BulkStatusUpdated:   0,
+		models.BulkStatusUnchanged: 0,
+		models.BulkStatusNotFound:  0,
+		models.BulkStatusSkipped:   0,
+	}
+	for _, r := range results {
+		summary[r.Status]++
+		if r.Status == models.BulkStatusUpdated {
+			data := map[string]any{"work_date": input.Date, "exceptions": r.Exceptions}
+			if managerID != callerID {
+				data["on_behalf_of"] = managerID // Actor = pemegang delegasi
+			}
+			publishEvent(c, h.Events, events.Event{Name: events.AttendanceSignedOff, UserID: r.UserID, Data: data})
+		}
+	}
+
+	reqLogger(c).Info().
+		Int("manager_id", managerID).
+		Int("signer_id", callerID).
+		Str("date", input.Date).
+		Int("signed", summary[models.BulkStatusUpdated]).
+		Int("skipped", summary[models.BulkStatusSkipped]).
+		Msg("Manager signed off team attendance")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Attendance signed off",
+		Data: fiber.Map{"date": input.Date, "summary": summary, "results": results},
+	})
+}
+
+// GetMyUnsignedDays godoc
+// @Summary Get unsigned days of my team
+// @Description Lists work days (scheduled or checked in) of the manager's direct and indirect reports that have not been signed off yet. Defaults to the current month. With on_behalf_of, lists the team of a manager who delegated their approvals to the caller.
+// @Tags Manager - Attendance
+// @Produce json
+// @Param on_behalf_of query int false "Manager whose active delegation the caller holds"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} models.Response{data=[]models.UnsignedDay} "Unsigned days retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid date range or on_behalf_of"
+// @Failure 403 {object} models.Response "No active delegation from on_behalf_of"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /manager/attendance/unsigned [get]
+func (h *SignOffHandler) GetMyUnsignedDays(c *fiber.Ctx) error {
+	callerID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	var onBehalfOf *int
+	if raw := c.Query("on_behalf_of"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid on_behalf_of"})
+		}
+		onBehalfOf = &id
+	}
+	managerID, err := h.actingManager(c, callerID, onBehalfOf)
+	if err != nil {
+		return delegationErrorResponse(c, err)
+	}
+	return h.unsignedDays(c, &managerID)
+}
+
+// GetUnsignedDays godoc
+// @Summary Get unsigned days report
+// @Description Lists work days (scheduled or checked in) that no manager has signed off yet, across the organization or under one manager.
+// @Tags Admin - Attendance Management
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param manager_id query int false "Only the team under this manager"
+// @Success 200 {object} models.Response{data=[]models.UnsignedDay} "Unsigned days retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid date range or manager_id"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/attendance/report/unsigned [get]
+func (h *SignOffHandler) GetUnsignedDays(c *fiber.Ctx) error {
+	var managerID *int
+	if raw := c.Query("manager_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid manager_id"})
+		}
+		managerID = &id
+	}
+	return h.unsignedDays(c, managerID)
+}
+
+func (h *SignOffHandler) unsignedDays(c *fiber.Ctx, managerID *int) error {
+	startDate, endDate, err := parseAdminDateQueryParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
+	days, err := h.SignOffRepo.GetUnsignedDays(c.UserContext(), managerID, startDate, endDate)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get unsigned days")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve unsigned days"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Unsigned days retrieved successfully", Data: days,
+	})
+}
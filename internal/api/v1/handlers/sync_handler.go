@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// SyncHandler melayani sinkronisasi delta untuk sistem eksternal (payroll, HRIS, data
+// warehouse): setiap endpoint mengembalikan record yang berubah setelah watermark beserta
+// tombstone record yang dihapus, sehingga klien tidak perlu menarik ulang seluruh data.
+// Integrasi memakai token terbatas (scope read) milik admin.
+type SyncHandler struct {
+	UserRepo       repository.UserRepository
+	AttendanceRepo repository.AttendanceRepository
+	ScheduleRepo   repository.ScheduleRepository
+}
+
+func NewSyncHandler(userRepo repository.UserRepository, attRepo repository.AttendanceRepository, scheduleRepo repository.ScheduleRepository) *SyncHandler {
+	return &SyncHandler{UserRepo: userRepo, AttendanceRepo: attRepo, ScheduleRepo: scheduleRepo}
+}
+
+// GetUserChanges godoc
+// @Summary Sync changed users
+// @Description Incremental sync for external systems. Returns users created or updated after the watermark (updated_since, after_id) in (updated_at, id) order, plus tombstones for deleted users. Omit updated_since for a full initial sync. Apply items and deleted, then call again with next_updated_since and next_after_id; keep going while has_more is true. Changes from the last 30 seconds are returned on a later call so slow transactions are not skipped.
+// @Tags Sync
+// @Produce json
+// @Param updated_since query string false "Watermark (RFC 3339) from next_updated_since of the previous call"
+// @Param after_id query int false "Tie-breaker from next_after_id of the previous call" default(0)
+// @Param limit query int false "Maximum changes (items + deleted) per call" default(500) maximum(1000)
+// @Success 200 {object} models.Response{data=models.SyncBatch{items=[]models.User}} "Changes retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid watermark"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /sync/users [get]
+func (h *SyncHandler) GetUserChanges(c *fiber.Ctx) error {
+	return h.respondChanges(c, models.SyncEntityUsers, h.UserRepo.GetUserChanges)
+}
+
+// GetAttendanceChanges godoc
+// @Summary Sync changed attendance records
+// @Description Incremental sync for external systems. Returns attendance records created or updated (check-out, corrections, etc.) after the watermark (updated_since, after_id) in (updated_at, id) order, plus tombstones for deleted records. Omit updated_since for a full initial sync. Apply items and deleted, then call again with next_updated_since and next_after_id; keep going while has_more is true. Changes from the last 30 seconds are returned on a later call so slow transactions are not skipped.
+// @Tags Sync
+// @Produce json
+// @Param updated_since query string false "Watermark (RFC 3339) from next_updated_since of the previous call"
+// @Param after_id query int false "Tie-breaker from next_after_id of the previous call" default(0)
+// @Param limit query int false "Maximum changes (items + deleted) per call" default(500) maximum(1000)
+// @Success 200 {object} models.Response{data=models.SyncBatch{items=[]models.Attendance}} "Changes retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid watermark"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /sync/attendance [get]
+func (h *SyncHandler) GetAttendanceChanges(c *fiber.Ctx) error {
+	return h.respondChanges(c, models.SyncEntityAttendances, h.AttendanceRepo.GetAttendanceChanges)
+}
+
+// GetScheduleChanges godoc
+// @Summary Sync changed schedules
+// @Description Incremental sync for external systems. Returns user schedules created or updated after the watermark (updated_since, after_id) in (updated_at, id) order, plus tombstones for deleted schedules. Omit updated_since for a full initial sync. Apply items and deleted, then call again with next_updated_since and next_after_id; keep going while has_more is true. Changes from the last 30 seconds are returned on a later call so slow transactions are not skipped.
+// @Tags Sync
+// @Produce json
+// @Param updated_since query string false "Watermark (RFC 3339) from next_updated_since of the previous call"
+// @Param after_id query int false "Tie-breaker from next_after_id of the previous call" default(0)
+// @Param limit query int false "Maximum changes (items + deleted) per call" default(500) maximum(1000)
+// @Success 200 {object} models.Response{data=models.SyncBatch{items=[]models.UserSchedule}} "Changes retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid watermark"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /sync/schedules [get]
+func (h *SyncHandler) GetScheduleChanges(c *fiber.Ctx) error {
+	return h.respondChanges(c, models.SyncEntitySchedules, h.ScheduleRepo.GetScheduleChanges)
+}
+
+// respondChanges membaca watermark dari query params dan mengembalikan satu batch perubahan entity.
+func (h *SyncHandler) respondChanges(c *fiber.Ctx, entity string, load func(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error)) error {
+	var cursor models.SyncCursor
+	if raw := c.Query("updated_since"); raw != "" {
+		since, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid updated_since. Use RFC 3339, e.g. 2024-01-31T00:00:00Z."})
+		}
+		cursor.UpdatedSince = since
+	}
+	if raw := c.Query("after_id"); raw != "" {
+		afterID, err := strconv.Atoi(raw)
+		if err != nil || afterID < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "after_id must be a non-negative integer"})
+		}
+		cursor.AfterID = afterID
+	}
+	limit := c.QueryInt("limit", models.SyncDefaultLimit)
+	if limit < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "limit must be a positive integer"})
+	}
+	limit = min(limit, models.SyncMaxLimit)
+
+	batch, err := load(c.UserContext(), cursor, limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Str("entity", entity).Time("updated_since", cursor.UpdatedSince).Int("after_id", cursor.AfterID).Msg("Failed to get sync changes")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve changes"})
+	}
+	reqLogger(c).Debug().Str("entity", entity).Int("deleted", len(batch.Deleted)).Bool("has_more", batch.HasMore).Msg("Sync changes retrieved")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Changes retrieved successfully", Data: batch,
+	})
+}
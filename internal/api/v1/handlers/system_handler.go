@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/diagnostics"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// SystemHandler melayani diagnostik instance untuk support (lihat diagnostics.Service).
+type SystemHandler struct {
+	Diagnostics *diagnostics.Service
+}
+
+func NewSystemHandler(service *diagnostics.Service) *SystemHandler {
+	return &SystemHandler{Diagnostics: service}
+}
+
+// GetDiagnostics godoc
+// @Summary Get system diagnostics (Admin)
+// @Description First stop for support tickets. Runs the self-checks of the instance that answers and reports database latency, migration status (applied vs. bundled migration files), storage reachability, SMTP reachability (NOTIFY_SMTP_ADDR), clock offset against NTP (DIAGNOSTICS_NTP_SERVER), and the background job backlog, plus the effective configuration with secrets masked. Each check is ok, warn, fail or skipped (not configured); status is the worst of them. Checks time out after DIAGNOSTICS_TIMEOUT. The endpoint always answers 200.
+// @Tags Admin - Monitoring
+// @Produce json
+// @Success 200 {object} models.Response{data=models.SystemDiagnostics} "Diagnostics retrieved successfully"
+// @Security ApiKeyAuth
+// @Router /admin/system/diagnostics [get]
+func (h *SystemHandler) GetDiagnostics(c *fiber.Ctx) error {
+	report := h.Diagnostics.Run(c.UserContext())
+	if report.Status != models.DiagnosticOK {
+		reqLogger(c).Warn().Str("status", report.Status).Msg("System diagnostics reported problems")
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Diagnostics retrieved successfully", Data: report,
+	})
+}
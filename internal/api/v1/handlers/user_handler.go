@@ -2,19 +2,28 @@ package handlers
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/degraded"
+	"github.com/rakaarfi/attendance-system-be/internal/emailchange"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/faceverify"
+	applogger "github.com/rakaarfi/attendance-system-be/internal/logger"
+	"github.com/rakaarfi/attendance-system-be/internal/maintenance"
 	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/photos"
 	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
 	"github.com/rakaarfi/attendance-system-be/internal/utils"
-	zlog "github.com/rs/zerolog/log"
 )
 
 type UserHandler struct {
@@ -22,35 +31,54 @@ type UserHandler struct {
 	ScheduleRepo   repository.ScheduleRepository
 	UserRepo       repository.UserRepository
 	ShiftRepo      repository.ShiftRepository
+	Events         events.Publisher
+	Tx             repository.TxManager    // Check-in/out & event outbox dalam satu transaksi
+	Degraded       *degraded.Controller    // Opsional; check-in ditampung saat database tidak tersedia
+	Maintenance    *maintenance.Controller // Opsional; check-in ditampung selama maintenance (maintenance.checkins = buffer)
+	FaceVerify     *faceverify.Hook        // Opsional; nil = verifikasi wajah saat check-in dinonaktifkan
+	Photos         *photos.Pipeline        // Opsional; nil = selfie check-in tidak disimpan
+	EmailChanges   *emailchange.Service    // Opsional; nil = email di profil langsung berganti tanpa konfirmasi
+	Settings       *settings.Store         // Kebijakan ganti username; nil = username bebas diganti
 	Validate       *validator.Validate
 }
 
-func NewUserHandler(attRepo repository.AttendanceRepository, schedRepo repository.ScheduleRepository, userRepo repository.UserRepository, shiftRepo repository.ShiftRepository) *UserHandler {
+func NewUserHandler(attRepo repository.AttendanceRepository, schedRepo repository.ScheduleRepository, userRepo repository.UserRepository, shiftRepo repository.ShiftRepository, eventBus events.Publisher, txManager repository.TxManager, degradedMode *degraded.Controller, maintenanceMode *maintenance.Controller, faceHook *faceverify.Hook, photoPipeline *photos.Pipeline, emailChanges *emailchange.Service, settingsStore *settings.Store) *UserHandler {
 	return &UserHandler{
 		AttendanceRepo: attRepo,
 		ScheduleRepo:   schedRepo,
 		UserRepo:       userRepo,
 		ShiftRepo:      shiftRepo,
+		Events:         eventBus,
+		Tx:             txManager,
+		Degraded:       degradedMode,
+		Maintenance:    maintenanceMode,
+		FaceVerify:     faceHook,
+		Photos:         photoPipeline,
+		EmailChanges:   emailChanges,
+		Settings:       settingsStore,
 		Validate:       validator.New(),
 	}
 }
 
 // @Summary      Create a check-in record
-// @Description  Create a new record of check-in for the user. The request body should contain the notes for the check-in (optional).
+// @Description  Create a new record of check-in for the user. The request body may contain notes (at most 500 characters), up to 5 tags from the attendance.tags setting (e.g. wfh, client_visit, sick) and a project_id (an active project) to tag the session; all are optional. The work mode (onsite by default, remote or field) is checked against the user's policy: remote only on the user's remote days (in the system default timezone), field only if field work is allowed; otherwise 403. When face verification is enabled, a base64 JPEG/PNG selfie is compared with the user's profile photo; the check-in is always recorded, and punches with a low match score, a missing selfie or a failed verification are flagged for admin review (data.face_match_status). When attendance photos are enabled, the selfie is also kept as the check-in photo (EXIF stripped, encrypted at rest, deleted after the retention period). While the database is unavailable (degraded mode) the check-in is queued with its original time and recorded once the database recovers, without face verification; the response is then 202 with data.queued true. The same happens during maintenance mode when the maintenance.checkins setting is buffer (recorded once maintenance ends); otherwise check-ins get 503 with Retry-After during maintenance.
 // @Tags         User - Check In/Out
 // @Accept       json
 // @Produce      json
 // @Param        check_in_input  body     models.CheckInInput  true  "Check-in notes"
 // @Success      201             {object} models.Response
+// @Success      202             {object} models.Response "Check-in queued (degraded or maintenance mode)"
 // @Failure      400             {object} models.Response
 // @Failure      401             {object} models.Response
+// @Failure      403             {object} models.Response "No schedule today or work mode not allowed"
+// @Failure      409             {object} models.Response
 // @Failure      500             {object} models.Response
 // @Security ApiKeyAuth
 // @Router       /user/attendance/checkin       [post]
 func (h *UserHandler) CheckIn(c *fiber.Ctx) error {
 	userID, err := utils.ExtractUserIDFromJWT(c)
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error extracting userID from JWT")
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to identify user",
 		})
@@ -59,17 +87,40 @@ func (h *UserHandler) CheckIn(c *fiber.Ctx) error {
 	input := new(models.CheckInInput)
 	if err := c.BodyParser(input); err != nil {
 		// Allow empty body for check-in without notes
-		zlog.Warn().Err(err).Msg("Check-in body parsing warning (may be empty)")
+		reqLogger(c).Warn().Err(err).Msg("Check-in body parsing warning (may be empty)")
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	if input.Notes, input.Tags, err = h.cleanNotes(c, input.Notes, input.Tags); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	if input.Mode == "" {
+		input.Mode = models.AttendanceModeOnsite
 	}
-	// No validation needed for CheckInInput struct currently
 
 	now := time.Now()
+	punch := degraded.Punch{UserID: userID, At: now, Notes: input.Notes, Tags: input.Tags, ProjectID: input.ProjectID, Mode: input.Mode}
+	if h.Maintenance.BufferingCheckIns(c.UserContext()) {
+		return h.queueCheckIn(c, punch, h.Maintenance.QueuePunch, "maintenance")
+	}
+	if h.Degraded.Degraded() {
+		return h.queueCheckIn(c, punch, h.Degraded.QueuePunch, "degraded mode")
+	}
 
 	// 1. Check if user has an existing attendance record without checkout
-	lastAtt, err := h.AttendanceRepo.GetLastAttendance(context.Background(), userID)
+	lastAtt, err := h.AttendanceRepo.GetLastAttendance(c.UserContext(), userID)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		if h.Degraded.ReportError(err) {
+			return h.queueCheckIn(c, punch, h.Degraded.QueuePunch, "degraded mode")
+		}
 		// Handle errors other than "no attendance records at all"
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Error checking last attendance")
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Error checking last attendance")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to process check-in",
 		})
@@ -82,39 +133,299 @@ func (h *UserHandler) CheckIn(c *fiber.Ctx) error {
 		})
 	}
 
+	// Mode remote/field mengikuti kebijakan user (hari remote dihitung di zona waktu default).
+	if input.Mode != models.AttendanceModeOnsite {
+		if handled, resp := h.checkWorkMode(c, userID, input.Mode, now); handled {
+			return resp
+		}
+	}
+
 	// 2. (Optional) Check if user has a schedule for today
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	_, errSched := h.ScheduleRepo.GetScheduleByUserAndDate(context.Background(), userID, today)
+	schedule, errSched := h.ScheduleRepo.GetScheduleByUserAndDate(c.UserContext(), userID, today)
+	// Repository mengembalikan (nil, nil) jika tidak ada jadwal.
+	if errSched == nil && schedule == nil {
+		errSched = pgx.ErrNoRows
+	}
 	if errSched != nil {
 		// Handle if schedule not found vs other errors
 		if errors.Is(errSched, pgx.ErrNoRows) {
-			zlog.Info().Int("user_id", userID).Time("today", today).Msg("User checking in without a schedule for today")
+			reqLogger(c).Info().Int("user_id", userID).Time("today", today).Msg("User checking in without a schedule for today")
 			// Decide whether to allow check-in without schedule or return error
 			return c.Status(fiber.StatusForbidden).JSON(models.Response{Success: false, Message: "No schedule found for today"})
 		} else {
-			zlog.Error().Err(errSched).Int("user_id", userID).Msg("Error checking schedule")
+			reqLogger(c).Error().Err(errSched).Int("user_id", userID).Msg("Error checking schedule")
 			// Maybe still allow checkin? Or return server error?
 		}
 	}
 	// // (Optional) Validate check-in time against schedule start time?
 
-	// 3. Proceed to check-in
-	attendanceID, err := h.AttendanceRepo.CreateCheckIn(context.Background(), userID, now, input.Notes)
+	// Jadwal yang berlaku saat ini disimpan di record absensi (tetap walau jadwal diedit kemudian).
+	var scheduleID *int
+	if schedule != nil {
+		scheduleID = &schedule.ID
+	}
+
+	// 3. Verifikasi wajah (opsional) di luar transaksi: memanggil layanan eksternal.
+	var faceCheck *models.FaceCheck
+	if h.FaceVerify != nil {
+		faceCheck = h.verifyFace(c, userID, input.Selfie)
+	}
+
+	// 4. Proceed to check-in
+	var attendanceID int
+	err = runInTx(c, h.Tx, func() error {
+		var err error
+		attendanceID, err = h.AttendanceRepo.CreateCheckIn(c.UserContext(), userID, now, input.Notes, scheduleID, input.ProjectID, input.Mode)
+		if err != nil {
+			return err
+		}
+		if err := h.AttendanceRepo.AddAttendanceTags(c.UserContext(), attendanceID, input.Tags); err != nil {
+			return err
+		}
+		if faceCheck != nil {
+			faceCheck.AttendanceID = attendanceID
+			if err := h.AttendanceRepo.RecordFaceCheck(c.UserContext(), faceCheck); err != nil {
+				return err
+			}
+		}
+		publishEvent(c, h.Events, events.Event{
+			Name: events.AttendanceCheckedIn, UserID: userID,
+			Data: map[string]any{"attendance_id": attendanceID, "check_in_at": now, "schedule_id": scheduleID, "project_id": input.ProjectID, "tags": input.Tags, "mode": input.Mode},
+		})
+		return nil
+	})
 	if err != nil {
-		zlog.Error().Err(err).Int("user_id", userID).Time("check_in_at", now).Msg("Error creating check-in")
+		if errors.Is(err, repository.ErrProjectUnavailable) {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: err.Error(),
+			})
+		}
+		// Check-in paralel yang lolos pengecekan di atas ditolak oleh constraint database
+		if errors.Is(err, repository.ErrAlreadyCheckedIn) {
+			return c.Status(fiber.StatusConflict).JSON(models.Response{
+				Success: false, Message: "User already checked in",
+			})
+		}
+		if handled, resp := attendanceLockedResponse(c, err); handled {
+			return resp
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Time("check_in_at", now).Msg("Error creating check-in")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to record check-in",
 		})
 	}
 
-	zlog.Info().Int("user_id", userID).Int("attendance_id", attendanceID).Time("check_in_at", now).Msg("Check-in successful")
+	reqLogger(c).Info().Int("user_id", userID).Int("attendance_id", attendanceID).Time("check_in_at", now).Msg("Check-in successful")
+	if h.Photos != nil && input.Selfie != nil {
+		h.storePhoto(c, attendanceID, userID, *input.Selfie)
+	}
+	data := fiber.Map{"attendance_id": attendanceID, "check_in_at": now, "schedule_id": scheduleID, "project_id": input.ProjectID, "tags": input.Tags, "mode": input.Mode}
+	if faceCheck != nil {
+		data["face_match_status"] = faceCheck.Status
+	}
 	return c.Status(http.StatusOK).JSON(models.Response{
-		Success: true, Message: "Check-in successful", Data: fiber.Map{"attendance_id": attendanceID, "check_in_at": now},
+		Success: true, Message: "Check-in successful", Data: data,
+	})
+}
+
+// checkWorkMode memastikan user boleh check-in dengan mode remote/field saat at; response error
+// sudah ditulis jika handled.
+func (h *UserHandler) checkWorkMode(c *fiber.Ctx, userID int, mode string, at time.Time) (handled bool, resp error) {
+	user, err := h.UserRepo.GetUserByID(c.UserContext(), userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Error loading user work mode policy")
+		return true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to process check-in",
+		})
+	}
+	if err := workModeError(user, mode, at.In(h.location(c.UserContext()))); err != nil {
+		reqLogger(c).Info().Int("user_id", userID).Str("mode", mode).Msg("Check-in rejected by work mode policy")
+		return true, c.Status(fiber.StatusForbidden).JSON(models.Response{
+			Success: false, Message: err.Error(),
+		})
+	}
+	return false, nil
+}
+
+// location mengembalikan zona waktu default sistem (waktu lokal server tanpa pengaturan).
+func (h *UserHandler) location(ctx context.Context) *time.Location {
+	if h.Settings == nil {
+		return time.Local
+	}
+	return h.Settings.DefaultLocation(ctx)
+}
+
+// cleanNotes merapikan catatan dan memvalidasi tag check-in/check-out (lihat attendanceNote).
+func (h *UserHandler) cleanNotes(c *fiber.Ctx, notes *string, tags []string) (*string, []string, error) {
+	notes, err := attendanceNote(notes)
+	if err != nil {
+		return nil, nil, err
+	}
+	tags, err = attendanceTags(c.UserContext(), h.Settings, tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	return notes, tags, nil
+}
+
+// verifyFace membandingkan selfie check-in dengan foto profil user. Hasil yang perlu
+// ditinjau admin diberi review_status pending; check-in tidak pernah ditolak di sini.
+func (h *UserHandler) verifyFace(c *fiber.Ctx, userID int, selfie *string) *models.FaceCheck {
+	var image []byte
+	if selfie != nil {
+		image, _ = base64.StdEncoding.DecodeString(*selfie) // Format sudah divalidasi tag base64
+	}
+	result := h.FaceVerify.Check(c.UserContext(), userID, image)
+	fc := &models.FaceCheck{UserID: userID, Provider: result.Provider, Status: result.Status, Score: result.Score}
+	if result.Flagged() {
+		pending := models.FaceReviewPending
+		fc.ReviewStatus = &pending
+	}
+	if result.Status == models.FaceMatchError {
+		reqLogger(c).Warn().Err(result.Err).Int("user_id", userID).Msg("Check-in face verification failed, punch flagged for review")
+	} else {
+		reqLogger(c).Info().Int("user_id", userID).Str("status", result.Status).Interface("score", result.Score).Msg("Check-in face verification")
+	}
+	return fc
+}
+
+// storePhoto menyimpan selfie check-in untuk diproses di background. Kegagalan hanya dicatat:
+// check-in sudah tersimpan dan tidak dibatalkan karena foto.
+func (h *UserHandler) storePhoto(c *fiber.Ctx, attendanceID, userID int, selfie string) {
+	image, _ := base64.StdEncoding.DecodeString(selfie) // Format sudah divalidasi tag base64
+	if len(image) == 0 {
+		return
+	}
+	if _, err := h.Photos.Ingest(c.UserContext(), attendanceID, userID, image); err != nil {
+		reqLogger(c).Error().Err(err).Int("attendance_id", attendanceID).Msg("Failed to store check-in photo")
+	}
+}
+
+// queueCheckIn menampung check-in lewat queue (buffer mode degraded atau maintenance) dan
+// menjawab 202. reason hanya untuk log.
+func (h *UserHandler) queueCheckIn(c *fiber.Ctx, punch degraded.Punch, queue func(degraded.Punch) error, reason string) error {
+	if err := queue(punch); err != nil {
+		if errors.Is(err, degraded.ErrAlreadyQueued) {
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "User already checked in"})
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", punch.UserID).Msgf("Failed to queue check-in in %s", reason)
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.Response{
+			Success: false, Message: "Service temporarily unavailable, please retry",
+		})
+	}
+	reqLogger(c).Warn().Int("user_id", punch.UserID).Time("check_in_at", punch.At).Msgf("Check-in queued in %s", reason)
+	return c.Status(fiber.StatusAccepted).JSON(models.Response{
+		Success: true, Message: "Check-in queued, it will be recorded shortly",
+		Data: fiber.Map{"queued": true, "check_in_at": punch.At, "project_id": punch.ProjectID},
 	})
 }
 
+// queuedCheckInRejectedError adalah alasan punch tertunda ditolak saat dicatat ulang yang aman
+// ditampilkan ke karyawan (lihat queuedCheckInReason).
+type queuedCheckInRejectedError struct {
+	reason string
+}
+
+func (e *queuedCheckInRejectedError) Error() string {
+	return e.reason
+}
+
+// ReplayCheckIn mencatat check-in yang ditampung selama mode degraded dengan waktu aslinya
+// (degraded.FlushFunc). Jadwal dicocokkan dengan tanggal punch; user yang sementara itu sudah
+// check-in lagi menghasilkan repository.ErrAlreadyCheckedIn sehingga punch menjadi dead letter
+// (lihat RejectQueuedCheckIn).
+func (h *UserHandler) ReplayCheckIn(ctx context.Context, p degraded.Punch) error {
+	lastAtt, err := h.AttendanceRepo.GetLastAttendance(ctx, p.UserID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	if lastAtt != nil && lastAtt.CheckOutAt == nil {
+		return repository.ErrAlreadyCheckedIn
+	}
+	day := time.Date(p.At.Year(), p.At.Month(), p.At.Day(), 0, 0, 0, 0, p.At.Location())
+	schedule, err := h.ScheduleRepo.GetScheduleByUserAndDate(ctx, p.UserID, day)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	if schedule == nil {
+		return &queuedCheckInRejectedError{reason: "no schedule was found for the check-in date"}
+	}
+	if p.Mode != "" && p.Mode != models.AttendanceModeOnsite {
+		user, err := h.UserRepo.GetUserByID(ctx, p.UserID)
+		if err != nil {
+			return err
+		}
+		if err := workModeError(user, p.Mode, p.At.In(h.location(ctx))); err != nil {
+			return &queuedCheckInRejectedError{reason: err.Error()}
+		}
+	}
+	mode := p.Mode
+	if mode == "" { // Punch dari buffer sebelum mode kerja ada
+		mode = models.AttendanceModeOnsite
+	}
+
+	return h.Tx.RunInTx(ctx, func(ctx context.Context) error {
+		attendanceID, err := h.AttendanceRepo.CreateCheckIn(ctx, p.UserID, p.At, p.Notes, &schedule.ID, p.ProjectID, mode)
+		if err != nil {
+			return err
+		}
+		if err := h.AttendanceRepo.AddAttendanceTags(ctx, attendanceID, p.Tags); err != nil {
+			return err
+		}
+		if h.Events != nil {
+			actorID := p.UserID
+			h.Events.Publish(ctx, events.Event{
+				Name: events.AttendanceCheckedIn, UserID: p.UserID, ActorUserID: &actorID,
+				Data: map[string]any{"attendance_id": attendanceID, "check_in_at": p.At, "schedule_id": schedule.ID, "project_id": p.ProjectID, "mode": mode, "queued": true},
+			})
+		}
+		applogger.Module(ctx, applogger.ModuleHandler).Info().Int("user_id", p.UserID).Int("attendance_id", attendanceID).Time("check_in_at", p.At).Msg("Queued check-in recorded")
+		return nil
+	})
+}
+
+// RejectQueuedCheckIn menyimpan punch tertunda yang gagal dicatat ReplayCheckIn (cause) sebagai
+// dead letter dan mem-publish events.QueuedCheckInFailed dalam satu transaksi, sehingga karyawan
+// dan HR diberi tahu lewat outbox (degraded.RejectFunc). Punch sudah dijawab 202, jadi tidak
+// boleh hilang: error membuat punch tetap di buffer untuk dicoba lagi.
+func (h *UserHandler) RejectQueuedCheckIn(ctx context.Context, p degraded.Punch, source string, cause error) error {
+	failure := &models.QueuedCheckInFailure{
+		UserID: p.UserID, CheckInAt: p.At, Notes: p.Notes, Tags: p.Tags, ProjectID: p.ProjectID, Mode: p.Mode,
+		Source: source, Error: cause.Error(),
+	}
+	return h.Tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := h.AttendanceRepo.RecordQueuedCheckInFailure(ctx, failure); err != nil {
+			return err
+		}
+		if h.Events != nil {
+			h.Events.Publish(ctx, events.Event{
+				Name: events.QueuedCheckInFailed, UserID: p.UserID,
+				Data: map[string]any{"failure_id": failure.ID, "check_in_at": p.At, "source": source, "reason": queuedCheckInReason(cause)},
+			})
+		}
+		return nil
+	})
+}
+
+// queuedCheckInReason mengubah error pencatatan ulang menjadi alasan yang aman ditampilkan ke
+// karyawan; detail error lain hanya tersimpan di dead letter untuk admin.
+func queuedCheckInReason(err error) string {
+	var rejected *queuedCheckInRejectedError
+	var closed *repository.PayrollPeriodClosedError
+	switch {
+	case errors.As(err, &rejected):
+		return rejected.reason
+	case errors.Is(err, repository.ErrAlreadyCheckedIn):
+		return "you had already checked in again in the meantime"
+	case errors.As(err, &closed):
+		return "the payroll period of that day has been closed"
+	}
+	return "an unexpected error occurred"
+}
+
 // @Summary      Create a check-out record
-// @Description  Create a new record of check-out for the user. The request body should contain the notes for the check-out (optional).
+// @Description  Create a new record of check-out for the user. The request body may contain notes (at most 500 characters) and up to 5 tags from the attendance.tags setting, which are added to the session's tags; both are optional.
 // @Tags         User - Check In/Out
 // @Accept       json
 // @Produce      json
@@ -123,13 +434,14 @@ func (h *UserHandler) CheckIn(c *fiber.Ctx) error {
 // @Failure      400             {object} models.Response
 // @Failure      401             {object} models.Response
 // @Failure      404             {object} models.Response
+// @Failure      409             {object} models.Response
 // @Failure      500             {object} models.Response
 // @Security ApiKeyAuth
 // @Router       /user/attendance/checkout       [post]
 func (h *UserHandler) CheckOut(c *fiber.Ctx) error {
 	userID, err := utils.ExtractUserIDFromJWT(c)
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error extracting userID from JWT")
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to identify user",
 		})
@@ -138,23 +450,32 @@ func (h *UserHandler) CheckOut(c *fiber.Ctx) error {
 	input := new(models.CheckOutInput)
 	if err := c.BodyParser(input); err != nil {
 		// Allow empty body for check-out without notes
-		zlog.Warn().Err(err).Msg("Check-out body parsing warning (may be empty)")
+		reqLogger(c).Warn().Err(err).Msg("Check-out body parsing warning (may be empty)")
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	if input.Notes, input.Tags, err = h.cleanNotes(c, input.Notes, input.Tags); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
 	}
-	// No validation needed for CheckOutInput struct currently
 
 	now := time.Now()
 
 	// 1. Find the last attendance record for the user that hasn't been checked out
-	lastAtt, err := h.AttendanceRepo.GetLastAttendance(context.Background(), userID)
+	lastAtt, err := h.AttendanceRepo.GetLastAttendance(c.UserContext(), userID)
 	if err != nil {
 		// Handle "no records found" or other errors
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Info().Int("user_id", userID).Msg("No active check-in found to check out from")
+			reqLogger(c).Info().Int("user_id", userID).Msg("No active check-in found to check out from")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: "No active check-in found to check out from",
 			})
 		}
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Error finding last attendance for user checkout")
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Error finding last attendance for user checkout")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to process check-out",
 		})
@@ -162,7 +483,7 @@ func (h *UserHandler) CheckOut(c *fiber.Ctx) error {
 
 	// 2. Check if already checked out
 	if lastAtt.CheckOutAt != nil {
-		zlog.Info().Int("user_id", userID).Msg("User has already checked out for the last session")
+		reqLogger(c).Info().Int("user_id", userID).Msg("User has already checked out for the last session")
 		return c.Status(fiber.StatusConflict).JSON(models.Response{
 			Success: false, Message: "User has already checked out for the last session",
 		})
@@ -171,12 +492,27 @@ func (h *UserHandler) CheckOut(c *fiber.Ctx) error {
 	// 3. (Optional) Validate check-out time against schedule end time?
 
 	// 4. Proceed to check-out by updating the last record
-	err = h.AttendanceRepo.UpdateCheckOut(context.Background(), lastAtt.ID, now, input.Notes)
+	err = runInTx(c, h.Tx, func() error {
+		if err := h.AttendanceRepo.UpdateCheckOut(c.UserContext(), lastAtt.ID, now, input.Notes); err != nil {
+			return err
+		}
+		if err := h.AttendanceRepo.AddAttendanceTags(c.UserContext(), lastAtt.ID, input.Tags); err != nil {
+			return err
+		}
+		publishEvent(c, h.Events, events.Event{
+			Name: events.AttendanceCheckedOut, UserID: userID,
+			Data: map[string]any{"attendance_id": lastAtt.ID, "check_out_at": now},
+		})
+		return nil
+	})
 	if err != nil {
-		zlog.Error().Err(err).Int("attendance_id", lastAtt.ID).Msg("Error updating check-out for attendance ID")
+		if handled, resp := attendanceLockedResponse(c, err); handled {
+			return resp
+		}
+		reqLogger(c).Error().Err(err).Int("attendance_id", lastAtt.ID).Msg("Error updating check-out for attendance ID")
 		// Handle specific error from repo (e.g., already checked out)
 		if err.Error() == fmt.Sprintf("attendance record %d not found or already checked out", lastAtt.ID) {
-			zlog.Info().Int("attendance_id", lastAtt.ID).Msg(err.Error())
+			reqLogger(c).Info().Int("attendance_id", lastAtt.ID).Msg(err.Error())
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: err.Error(),
 			})
@@ -186,12 +522,69 @@ func (h *UserHandler) CheckOut(c *fiber.Ctx) error {
 		})
 	}
 
-	zlog.Info().Int("user_id", userID).Int("attendance_id", lastAtt.ID).Time("check_out_at", now).Msg("Check-out successful")
+	reqLogger(c).Info().Int("user_id", userID).Int("attendance_id", lastAtt.ID).Time("check_out_at", now).Msg("Check-out successful")
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Check-out successful", Data: fiber.Map{"attendance_id": lastAtt.ID, "check_out_at": now},
 	})
 }
 
+// @Summary      Switch project mid-shift
+// @Description  Switch the project of the current open attendance session without checking out. The running segment ends now and a new segment starts for the given active project; segment durations roll up per project in the project hours report.
+// @Tags         User - Check In/Out
+// @Accept       json
+// @Produce      json
+// @Param        switch_input  body     models.SwitchProjectInput  true  "Project to switch to"
+// @Success      200           {object} models.Response{data=models.AttendanceSegment}
+// @Failure      400           {object} models.Response
+// @Failure      401           {object} models.Response
+// @Failure      409           {object} models.Response
+// @Failure      500           {object} models.Response
+// @Security ApiKeyAuth
+// @Router       /user/attendance/switch       [post]
+func (h *UserHandler) SwitchProject(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+
+	input := new(models.SwitchProjectInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Failed to parse request body",
+		})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+
+	segment, err := h.AttendanceRepo.SwitchProject(c.UserContext(), userID, time.Now(), input.ProjectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrProjectUnavailable):
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+		case errors.Is(err, repository.ErrNotCheckedIn), errors.Is(err, repository.ErrAlreadyOnProject):
+			return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: err.Error()})
+		}
+		if handled, resp := attendanceLockedResponse(c, err); handled {
+			return resp
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Int("project_id", input.ProjectID).Msg("Error switching project")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to switch project",
+		})
+	}
+
+	reqLogger(c).Info().Int("user_id", userID).Int("attendance_id", segment.AttendanceID).Int("project_id", input.ProjectID).Msg("Project switched")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Project switched successfully", Data: segment,
+	})
+}
+
 // @Summary      Get attendance records for current user
 // @Description  Get attendance records for the current user within a date range.
 // @Tags User - Schedule/Attendance
@@ -199,6 +592,7 @@ func (h *UserHandler) CheckOut(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        start_date  query     time.Time  false  "Start date of attendance records (inclusive)"
 // @Param        end_date    query     time.Time  false  "End date of attendance records (inclusive)"
+// @Param        include     query     string     false  "Related data to embed, comma-separated (supported: user, schedule, shift; default: none)"
 // @Success      200         {object}  models.Response
 // @Failure      400         {object}  models.Response
 // @Failure      401         {object}  models.Response
@@ -208,7 +602,7 @@ func (h *UserHandler) CheckOut(c *fiber.Ctx) error {
 func (h *UserHandler) GetMyAttendance(c *fiber.Ctx) error {
 	userID, err := utils.ExtractUserIDFromJWT(c)
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error extracting userID from JWT")
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to identify user",
 		})
@@ -223,21 +617,32 @@ func (h *UserHandler) GetMyAttendance(c *fiber.Ctx) error {
 	endDate := parseDateQueryParam(c, "end_date", todayEnd)
 
 	if endDate.Before(startDate) {
-		zlog.Warn().Time("start_date", startDate).Time("end_date", endDate).Msg("Invalid date range")
+		reqLogger(c).Warn().Time("start_date", startDate).Time("end_date", endDate).Msg("Invalid date range")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "end_date cannot be before start_date",
 		})
 	}
 
-	zlog.Info().Int("user_id", userID).Time("start_date", startDate).Time("end_date", endDate).Msg("Retrieving attendance records for user")
+	includes, err := parseIncludes(c, attendanceIncludes)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
+
+	reqLogger(c).Info().Int("user_id", userID).Time("start_date", startDate).Time("end_date", endDate).Msg("Retrieving attendance records for user")
 
 	// 2. Parse Pagination Params
 	pagination := utils.ParsePaginationParams(c)
 
 	// 3. Panggil Repository
-	attendances, totalCount, err := h.AttendanceRepo.GetAttendancesByUser(context.Background(), userID, startDate, endDate, pagination.Page, pagination.Limit)
+	attendances, totalCount, err := h.AttendanceRepo.GetAttendancesByUser(c.UserContext(), userID, startDate, endDate, pagination.Page, pagination.Limit)
 	if err != nil {
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Failed to get my attendance from repository")
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get my attendance from repository")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to retrieve attendance records",
+		})
+	}
+	if err := includeAttendances(c.UserContext(), h.UserRepo, h.ScheduleRepo, attendances, includes); err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to embed related data in my attendance")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to retrieve attendance records",
 		})
@@ -247,13 +652,13 @@ func (h *UserHandler) GetMyAttendance(c *fiber.Ctx) error {
 	meta := utils.BuildPaginationMeta(totalCount, pagination.Limit, pagination.Page)
 	response := utils.NewPaginatedResponse("Attendance records retrieved successfully", attendances, meta)
 
-	zlog.Info().Int("user_id", userID).Int("count", len(attendances)).Int("total", totalCount).Msg("Successfully retrieved my attendance")
+	reqLogger(c).Info().Int("user_id", userID).Int("count", len(attendances)).Int("total", totalCount).Msg("Successfully retrieved my attendance")
 	return c.Status(http.StatusOK).JSON(response)
 }
 
 // GetMySchedules godoc
 // @Summary Get schedules for the current user
-// @Description Retrieves a list of schedules for the current user within a date range.
+// @Description Retrieves a list of schedules for the current user within a date range. Supports If-None-Match: 304 Not Modified while the page is unchanged (including shift time changes).
 // @Tags User - Schedule/Attendance
 // @Accept json
 // @Produce json
@@ -261,7 +666,10 @@ func (h *UserHandler) GetMyAttendance(c *fiber.Ctx) error {
 // @Param end_date query string false "End date for schedule retrieval (YYYY-MM-DD)"
 // @Param page query int false "Page number for pagination"
 // @Param limit query int false "Limit of schedules per page"
+// @Param include query string false "Related data to embed, comma-separated (supported: user, shift; default: shift)"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} models.Response{data=[]models.UserSchedule} "Schedules retrieved successfully"
+// @Success 304 "Schedules not modified"
 // @Failure 400 {object} models.Response "Validation failed or invalid request parameters"
 // @Failure 500 {object} models.Response "Internal server error during schedule retrieval"
 // @Security ApiKeyAuth
@@ -269,7 +677,7 @@ func (h *UserHandler) GetMyAttendance(c *fiber.Ctx) error {
 func (h *UserHandler) GetMySchedules(c *fiber.Ctx) error {
 	userID, err := utils.ExtractUserIDFromJWT(c)
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error extracting userID from JWT")
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to identify user",
 		})
@@ -284,34 +692,44 @@ func (h *UserHandler) GetMySchedules(c *fiber.Ctx) error {
 	endDate := parseDateQueryParam(c, "end_date", endOfMonth)
 
 	if endDate.Before(startDate) {
-		zlog.Warn().Time("start_date", startDate).Time("end_date", endDate).Msg("Invalid date range")
+		reqLogger(c).Warn().Time("start_date", startDate).Time("end_date", endDate).Msg("Invalid date range")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "end_date cannot be before start_date",
 		})
 	}
 
-	// 2. Parse Pagination Params
+	// 2. Parse Pagination Params & include
 	pagination := utils.ParsePaginationParams(c) // Gunakan helper
+	includes, err := parseIncludes(c, scheduleIncludes, models.IncludeShift)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: err.Error()})
+	}
 
-	schedules, totalCount, err := h.ScheduleRepo.GetSchedulesByUser(context.Background(), userID, startDate, endDate, pagination.Page, pagination.Limit)
+	schedules, totalCount, err := h.ScheduleRepo.GetSchedulesByUser(c.UserContext(), userID, startDate, endDate, pagination.Page, pagination.Limit)
+	if err == nil {
+		err = includeSchedules(c.UserContext(), h.UserRepo, schedules, includes)
+	}
 	if err != nil {
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Failed to get my schedules from repository")
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get my schedules from repository")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to retrieve schedule records",
 		})
 	}
 
-	// 4. Bangun Metadata dan Response
+	// 4. Bangun Metadata dan Response (304 jika halaman ini belum berubah)
 	meta := utils.BuildPaginationMeta(totalCount, pagination.Limit, pagination.Page)
+	if notModified(c, schedulesETag(schedules, meta), time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 	response := utils.NewPaginatedResponse("Schedules retrieved successfully", schedules, meta) // Gunakan helper response jika ada
 
-	zlog.Info().Int("user_id", userID).Int("count", len(schedules)).Int("total", totalCount).Msg("Successfully retrieved my schedules")
+	reqLogger(c).Info().Int("user_id", userID).Int("count", len(schedules)).Int("total", totalCount).Msg("Successfully retrieved my schedules")
 	return c.Status(http.StatusOK).JSON(response)
 }
 
 // UpdateMyProfile godoc
 // @Summary Update my profile
-// @Description Update the profile for the current user.
+// @Description Update the profile for the current user. When email change confirmation is enabled (EMAIL_CHANGE_CONFIRMATION, default), a new email is not applied immediately: a confirmation link is sent to it, the current address is notified, and data.pending_email_change is returned (see POST /user/email). A new username follows the profile.username_change_policy setting: allow applies it, deny rejects it with 403, and approval keeps the current username and returns data.pending_username_change until an admin approves it.
 // @Tags User - Profile Management
 // @Accept json
 // @Produce json
@@ -319,6 +737,8 @@ func (h *UserHandler) GetMySchedules(c *fiber.Ctx) error {
 // @Success 200 {object} models.Response "Profile updated successfully"
 // @Failure 400 {object} models.Response "Validation failed or invalid request body"
 // @Failure 401 {object} models.Response "Failed to identify user"
+// @Failure 403 {object} models.Response "Username changes are not allowed"
+// @Failure 409 {object} models.Response "Username or email already exists"
 // @Failure 500 {object} models.Response "Internal server error during profile update"
 // @Security ApiKeyAuth
 // @Router /user/profile [patch]
@@ -326,7 +746,7 @@ func (h *UserHandler) UpdateMyProfile(c *fiber.Ctx) error {
 	// 1. Dapatkan ID user dari JWT (bukan dari URL)
 	userID, err := utils.ExtractUserIDFromJWT(c)
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error extracting userID from JWT for profile update")
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT for profile update")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to identify user",
 		})
@@ -335,7 +755,7 @@ func (h *UserHandler) UpdateMyProfile(c *fiber.Ctx) error {
 	// 2. Parse & Validasi Input Body
 	input := new(models.UpdateProfileInput)
 	if err := c.BodyParser(input); err != nil {
-		zlog.Error().Err(err).Msg("Error parsing update profile request body")
+		reqLogger(c).Error().Err(err).Msg("Error parsing update profile request body")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Failed to parse request body",
 		})
@@ -343,39 +763,91 @@ func (h *UserHandler) UpdateMyProfile(c *fiber.Ctx) error {
 
 	// 3. Validasi data input menggunakan validator
 	if err := h.Validate.Struct(input); err != nil {
-		zlog.Warn().Err(err).Int("user_id", userID).Msg("Update profile validation failed")
+		reqLogger(c).Warn().Err(err).Int("user_id", userID).Msg("Update profile validation failed")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Validation failed", Data: err.Error(),
 		})
 	}
 
-	// 4. Panggil repository untuk update profil
-	err = h.UserRepo.UpdateUserProfile(context.Background(), userID, input) // Gunakan userID dari JWT
+	// 4. Email baru menunggu konfirmasi dari alamat baru dan username mengikuti kebijakan
+	//    profile.username_change_policy; field lain langsung diperbarui
+	usernamePolicy := models.UsernamePolicyAllow
+	if h.Settings != nil {
+		usernamePolicy = h.Settings.UsernameChangePolicy(c.UserContext())
+	}
+	var emailChange *models.EmailChangeRequest
+	var usernameChange *models.UsernameChangeRequest
+	if h.EmailChanges != nil || usernamePolicy != models.UsernamePolicyAllow {
+		current, handled, resp := currentUser(c, h.UserRepo)
+		if handled {
+			return resp
+		}
+		if usernamePolicy != models.UsernamePolicyAllow {
+			if input.Username != current.Username {
+				if usernameChange, handled, resp = requestUsernameChange(c, h.UserRepo, h.Events, usernamePolicy, current, input.Username); handled {
+					return resp
+				}
+			}
+			input.Username = current.Username
+		}
+		if h.EmailChanges != nil {
+			if !strings.EqualFold(input.Email, current.Email) {
+				if emailChange, handled, resp = requestEmailChange(c, h.EmailChanges, h.Events, current, input.Email); handled {
+					return resp
+				}
+			}
+			input.Email = current.Email
+		}
+	}
+
+	// 5. Panggil repository untuk update profil
+	err = h.UserRepo.UpdateUserProfile(c.UserContext(), userID, input) // Gunakan userID dari JWT
 	if err != nil {
 		// Cek error unique constraint
 		if strings.Contains(err.Error(), "already exists") {
-			zlog.Warn().Err(err).Int("user_id", userID).Msg("Unique constraint violation during user profile update")
+			reqLogger(c).Warn().Err(err).Int("user_id", userID).Msg("Unique constraint violation during user profile update")
 			return c.Status(fiber.StatusConflict).JSON(models.Response{ // 409 Conflict
 				Success: false, Message: err.Error(),
 			})
 		}
 		// Cek error user not found (seharusnya jarang terjadi di sini)
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Error().Err(err).Int("user_id", userID).Msg("User not found during profile update (inconsistency?)")
+			reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("User not found during profile update (inconsistency?)")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: "User not found",
 			})
 		}
 
 		// Error lain saat update
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Failed to update user profile")
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to update user profile")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to update profile",
 		})
 	}
 
-	// 5. Kirim response sukses
-	zlog.Info().Int("user_id", userID).Msg("User profile updated successfully")
+	// 6. Kirim response sukses
+	reqLogger(c).Info().Int("user_id", userID).Msg("User profile updated successfully")
+	fields := submittedFields(c)
+	if h.EmailChanges != nil {
+		fields = slices.DeleteFunc(fields, func(f string) bool { return f == "email" }) // Dicatat saat dikonfirmasi
+	}
+	if usernamePolicy != models.UsernamePolicyAllow {
+		fields = slices.DeleteFunc(fields, func(f string) bool { return f == "username" }) // Dicatat saat disetujui
+	}
+	publishEvent(c, h.Events, events.Event{Name: events.ProfileUpdated, UserID: userID, Data: map[string]any{"fields": fields}})
+	if emailChange != nil || usernameChange != nil {
+		message := "Profile updated successfully."
+		data := fiber.Map{}
+		if emailChange != nil {
+			message += " Confirm the new email address from the link sent to it."
+			data["pending_email_change"] = emailChange
+		}
+		if usernameChange != nil {
+			message += " The new username is waiting for administrator approval."
+			data["pending_username_change"] = usernameChange
+		}
+		return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: message, Data: data})
+	}
 	// Pertimbangkan untuk mengembalikan data profil yang sudah diupdate
 	// (ambil lagi dari DB atau kembalikan input yang sudah divalidasi?)
 	return c.Status(http.StatusOK).JSON(models.Response{
@@ -398,9 +870,9 @@ func (h *UserHandler) UpdateMyProfile(c *fiber.Ctx) error {
 func (h *UserHandler) UpdateMyPassword(c *fiber.Ctx) error {
 	// 1. Dapatkan ID user dari JWT
 	userID, err := utils.ExtractUserIDFromJWT(c)
-	zlog.Debug().Int("jwt_user_id", userID).Err(err).Msg("Extracted User ID from JWT")
+	reqLogger(c).Debug().Int("jwt_user_id", userID).Err(err).Msg("Extracted User ID from JWT")
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error extracting userID from JWT for password update")
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT for password update")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to identify user",
 		})
@@ -409,7 +881,7 @@ func (h *UserHandler) UpdateMyPassword(c *fiber.Ctx) error {
 	// 2. Parse & Validasi Input Body
 	input := new(models.UpdatePasswordInput)
 	if err := c.BodyParser(input); err != nil {
-		zlog.Error().Err(err).Msg("Error parsing update password request body")
+		reqLogger(c).Error().Err(err).Msg("Error parsing update password request body")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Failed to parse request body",
 		})
@@ -417,7 +889,7 @@ func (h *UserHandler) UpdateMyPassword(c *fiber.Ctx) error {
 
 	// 3. Validasi data input menggunakan validator
 	if err := h.Validate.Struct(input); err != nil {
-		zlog.Warn().Err(err).Int("user_id", userID).Msg("Update password validation failed")
+		reqLogger(c).Warn().Err(err).Int("user_id", userID).Msg("Update password validation failed")
 		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
 			Success: false, Message: "Validation failed", Data: err.Error(),
 		})
@@ -425,28 +897,24 @@ func (h *UserHandler) UpdateMyPassword(c *fiber.Ctx) error {
 
 	// 4. Dapatkan data user saat ini (termasuk hash password lama) dari repo
 	// Perlu method GetUserByID di UserRepo Anda!
-	currentUser, err := h.UserRepo.GetUserByID(context.Background(), userID)
+	currentUser, err := h.UserRepo.GetUserByID(c.UserContext(), userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Error().Err(err).Int("user_id", userID).Msg("User not found during password update (inconsistency?)")
+			reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("User not found during password update (inconsistency?)")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "User not found"})
 		}
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Failed to get current user data for password check")
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get current user data for password check")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to process password update",
 		})
 	}
-	zlog.Debug().
-		Int("user_id", userID).
-		Str("input_password", input.OldPassword).
-		Str("stored_hash", currentUser.Password).
-		Msg("Password update attempt details")
+	reqLogger(c).Debug().Int("user_id", userID).Msg("Password update attempt, verifying old password")
 
 	// 5. Verify old password
 	isMatch := utils.CheckPasswordHash(input.OldPassword, currentUser.Password)
-	zlog.Debug().Bool("password_match", isMatch).Msg("Result of CheckPasswordHash")
+	reqLogger(c).Debug().Bool("password_match", isMatch).Msg("Result of CheckPasswordHash")
 	if !isMatch {
-		zlog.Warn().Int("user_id", userID).Msg("Incorrect old password provided")
+		reqLogger(c).Warn().Int("user_id", userID).Msg("Incorrect old password provided")
 		return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
 			Success: false, Message: "Incorrect old password",
 		})
@@ -455,29 +923,30 @@ func (h *UserHandler) UpdateMyPassword(c *fiber.Ctx) error {
 	// 6. Hash password baru
 	newHashedPassword, err := utils.HashPassword(input.NewPassword)
 	if err != nil {
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Failed to hash new password")
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to hash new password")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to process password update",
 		})
 	}
 
 	// 7. Panggil repository untuk update password dengan hash baru
-	err = h.UserRepo.UpdateUserPassword(context.Background(), userID, newHashedPassword)
+	err = h.UserRepo.UpdateUserPassword(c.UserContext(), userID, newHashedPassword)
 	if err != nil {
 		// Cek not found (seharusnya jarang)
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Error().Err(err).Int("user_id", userID).Msg("User disappeared during password update?")
+			reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("User disappeared during password update?")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "User not found"})
 		}
 		// Error lain
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Failed to update password in repository")
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to update password in repository")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to update password",
 		})
 	}
 
 	// 8. Kirim response sukses
-	zlog.Info().Int("user_id", userID).Msg("User password updated successfully")
+	reqLogger(c).Info().Int("user_id", userID).Msg("User password updated successfully")
+	publishEvent(c, h.Events, events.Event{Name: events.PasswordChanged, UserID: userID, Data: clientDetails(c)})
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Password updated successfully",
 	})
@@ -485,10 +954,13 @@ func (h *UserHandler) UpdateMyPassword(c *fiber.Ctx) error {
 
 // GetMyProfile godoc
 // @Summary Get my profile
-// @Description Get the profile for the current user.
+// @Description Get the profile for the current user. Supports conditional requests: send the ETag back in If-None-Match (or Last-Modified in If-Modified-Since) to get 304 Not Modified while the profile is unchanged.
 // @Tags User - Profile Management
 // @Produce json
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response"
 // @Success 200 {object} models.Response{data=map[string]interface{}} "Profile data for current user"
+// @Success 304 "Profile not modified"
 // @Failure 400 {object} models.Response "Validation failed or invalid request body"
 // @Failure 401 {object} models.Response "Failed to identify user"
 // @Failure 500 {object} models.Response "Internal server error during profile retrieval"
@@ -498,7 +970,7 @@ func (h *UserHandler) GetMyProfile(c *fiber.Ctx) error {
 	// 1. Dapatkan ID user dari JWT
 	userID, err := utils.ExtractUserIDFromJWT(c)
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error extracting userID from JWT for get profile")
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT for get profile")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to identify user",
 		})
@@ -506,24 +978,33 @@ func (h *UserHandler) GetMyProfile(c *fiber.Ctx) error {
 
 	// 2. Panggil repository untuk mendapatkan data user
 	// Kita gunakan GetUserByID yang mengambil user *beserta role*-nya
-	userProfile, err := h.UserRepo.GetUserByID(context.Background(), userID)
+	userProfile, err := h.UserRepo.GetUserByID(c.UserContext(), userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			// Ini sangat aneh jika terjadi karena ID dari token JWT yang valid
-			zlog.Error().Err(err).Int("user_id", userID).Msg("User from valid JWT not found in DB for get profile")
+			reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("User from valid JWT not found in DB for get profile")
 			return c.Status(fiber.StatusNotFound).JSON(models.Response{
 				Success: false, Message: "User profile not found",
 			})
 		}
 		// Error lain
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Failed to get user profile from repository")
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get user profile from repository")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to retrieve profile",
 		})
 	}
 
-	// 3. Kirim response sukses (password sudah otomatis tidak ada karena repo GetUserByID tidak memilihnya)
-	zlog.Info().Int("user_id", userID).Msg("User profile retrieved successfully")
+	// 3. Conditional GET: 304 jika profil (termasuk role) belum berubah sejak salinan klien
+	etagParts := []any{userProfile.ID, userProfile.Version, userProfile.UpdatedAt, userProfile.RoleID}
+	if userProfile.Role != nil {
+		etagParts = append(etagParts, userProfile.Role.Name, userProfile.Role.ParentID)
+	}
+	if notModified(c, etagOf(etagParts...), userProfile.UpdatedAt) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	// 4. Kirim response sukses (password sudah otomatis tidak ada karena repo GetUserByID tidak memilihnya)
+	reqLogger(c).Info().Int("user_id", userID).Msg("User profile retrieved successfully")
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Profile retrieved successfully", Data: userProfile, // Kirim data user
 	})
@@ -531,26 +1012,100 @@ func (h *UserHandler) GetMyProfile(c *fiber.Ctx) error {
 
 // GetAllShifts godoc
 // @Summary Get all shifts
-// @Description Retrieves a list of all shifts.
+// @Description Retrieves a list of all shifts. Supports If-None-Match: 304 Not Modified while no shift was added, changed or deleted.
 // @Tags Public
 // @Produce json
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} models.Response{data=[]models.Shift} "Shifts retrieved successfully"
+// @Success 304 "Shifts not modified"
 // @Failure 500 {object} models.Response "Failed to retrieve shifts"
 // @Router /shifts [get]
 func (h *UserHandler) GetAllShifts(c *fiber.Ctx) error {
 	// Dapatkan ID user dari JWT (walaupun tidak dipakai di query, baik untuk log/konteks)
 	userID, _ := utils.ExtractUserIDFromJWT(c) // Abaikan error jika hanya untuk log
 
-	shifts, err := h.ShiftRepo.GetAllShifts(context.Background())
+	shifts, err := h.ShiftRepo.GetAllShifts(c.UserContext())
 	if err != nil {
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Failed to get all shifts from repository")
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get all shifts from repository")
 		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
 			Success: false, Message: "Failed to retrieve shifts",
 		})
 	}
 
-	zlog.Info().Int("user_id", userID).Int("shift_count", len(shifts)).Msg("Successfully retrieved all shifts")
+	if notModified(c, shiftsETag(shifts), time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	reqLogger(c).Info().Int("user_id", userID).Int("shift_count", len(shifts)).Msg("Successfully retrieved all shifts")
 	return c.Status(http.StatusOK).JSON(models.Response{
 		Success: true, Message: "Shifts retrieved successfully", Data: shifts,
 	})
 }
+
+// ExportMyData godoc
+// @Summary Export my personal data
+// @Description Downloads a machine-readable JSON archive of the current user's profile, all schedules, and all attendance records (GDPR data portability).
+// @Tags User - Profile Management
+// @Produce json
+// @Success 200 {object} models.UserDataExport "Personal data archive"
+// @Failure 401 {object} models.Response "Failed to identify user"
+// @Failure 404 {object} models.Response "User profile not found"
+// @Failure 500 {object} models.Response "Internal server error during data export"
+// @Security ApiKeyAuth
+// @Router /user/data-export [get]
+func (h *UserHandler) ExportMyData(c *fiber.Ctx) error {
+	// 1. Dapatkan ID user dari JWT
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT for data export")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to identify user",
+		})
+	}
+
+	ctx := c.UserContext()
+
+	// 2. Profil (PII sudah didekripsi oleh repository)
+	profile, err := h.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("User from valid JWT not found in DB for data export")
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: "User profile not found",
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get user profile for data export")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to export data",
+		})
+	}
+
+	// 3. Seluruh jadwal & absensi (tanpa pagination)
+	schedules, err := h.ScheduleRepo.ExportSchedulesByUser(ctx, userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get schedules for data export")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to export data",
+		})
+	}
+	attendances, err := h.AttendanceRepo.ExportAttendancesByUser(ctx, userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get attendances for data export")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{
+			Success: false, Message: "Failed to export data",
+		})
+	}
+
+	// 4. Kirim sebagai file unduhan JSON
+	export := models.UserDataExport{
+		ExportedAt:  time.Now().UTC(),
+		Profile:     profile,
+		Schedules:   schedules,
+		Attendances: attendances,
+	}
+	c.Attachment(fmt.Sprintf("data-export-user-%d.json", userID))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	reqLogger(c).Info().Int("user_id", userID).Int("schedules", len(schedules)).Int("attendances", len(attendances)).Msg("User data export generated")
+	return c.Status(http.StatusOK).JSON(export)
+}
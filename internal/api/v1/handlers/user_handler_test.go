@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/degraded"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPublisher menyimpan event yang dipublikasikan handler.
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, ev events.Event) {
+	p.events = append(p.events, ev)
+}
+
+// passthroughTx membuat MockTxManager yang langsung menjalankan fn tanpa transaksi.
+func passthroughTx(t *testing.T) *mocks.MockTxManager {
+	tx := mocks.NewMockTxManager(t)
+	tx.EXPECT().RunInTx(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	return tx
+}
+
+func TestRejectQueuedCheckIn(t *testing.T) {
+	project := 4
+	punch := degraded.Punch{UserID: 7, At: time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC), Tags: []string{"onsite"}, ProjectID: &project, Mode: "office"}
+
+	tests := []struct {
+		name       string
+		cause      error
+		wantReason string
+	}{
+		{"already checked in", repository.ErrAlreadyCheckedIn, "you had already checked in again in the meantime"},
+		{"payroll closed", &repository.PayrollPeriodClosedError{}, "the payroll period of that day has been closed"},
+		{"no schedule", &queuedCheckInRejectedError{reason: "no schedule was found for the check-in date"}, "no schedule was found for the check-in date"},
+		{"unexpected", errors.New("constraint violated: secret detail"), "an unexpected error occurred"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockAttendanceRepository(t)
+			repo.EXPECT().RecordQueuedCheckInFailure(mock.Anything, mock.AnythingOfType("*models.QueuedCheckInFailure")).
+				RunAndReturn(func(_ context.Context, f *models.QueuedCheckInFailure) error {
+					assert.Equal(t, punch.UserID, f.UserID)
+					assert.Equal(t, punch.At, f.CheckInAt)
+					assert.Equal(t, punch.Tags, f.Tags)
+					assert.Equal(t, models.QueuedCheckInSourceMaintenance, f.Source)
+					assert.Equal(t, tt.cause.Error(), f.Error)
+					f.ID = 9
+					return nil
+				})
+			publisher := &recordingPublisher{}
+			h := &UserHandler{AttendanceRepo: repo, Tx: passthroughTx(t), Events: publisher}
+
+			require.NoError(t, h.RejectQueuedCheckIn(context.Background(), punch, models.QueuedCheckInSourceMaintenance, tt.cause))
+			require.Len(t, publisher.events, 1)
+			ev := publisher.events[0]
+			assert.Equal(t, events.QueuedCheckInFailed, ev.Name)
+			assert.Equal(t, 9, ev.Data["failure_id"])
+			assert.Equal(t, tt.wantReason, ev.Data["reason"])
+		})
+	}
+}
+
+func TestRejectQueuedCheckInStoreFails(t *testing.T) {
+	repo := mocks.NewMockAttendanceRepository(t)
+	repo.EXPECT().RecordQueuedCheckInFailure(mock.Anything, mock.Anything).Return(errors.New("insert failed"))
+	publisher := &recordingPublisher{}
+	h := &UserHandler{AttendanceRepo: repo, Tx: passthroughTx(t), Events: publisher}
+
+	err := h.RejectQueuedCheckIn(context.Background(), degraded.Punch{UserID: 7, At: time.Now()}, models.QueuedCheckInSourceDegraded, repository.ErrAlreadyCheckedIn)
+	assert.Error(t, err, "punch must stay queued when the dead letter cannot be stored")
+	assert.Empty(t, publisher.events)
+}
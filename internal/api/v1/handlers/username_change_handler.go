@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// UsernameChangeHandler melayani kebijakan ganti username (pengaturan profile.username_change_policy):
+// antrian persetujuan admin, riwayat username lama, dan pencarian user dari username lama.
+type UsernameChangeHandler struct {
+	UserRepo repository.UserRepository
+	Events   events.Publisher
+	Tx       repository.TxManager // Persetujuan & event outbox dalam satu transaksi
+	Validate *validator.Validate
+}
+
+func NewUsernameChangeHandler(userRepo repository.UserRepository, eventBus events.Publisher, txManager repository.TxManager) *UsernameChangeHandler {
+	return &UsernameChangeHandler{UserRepo: userRepo, Events: eventBus, Tx: txManager, Validate: validator.New()}
+}
+
+// GetMyPendingUsernameChange godoc
+// @Summary Get my pending username change
+// @Description Returns the username change request of the current user that is waiting for administrator approval (profile.username_change_policy = approval).
+// @Tags User - Profile Management
+// @Produce json
+// @Success 200 {object} models.Response{data=models.UsernameChangeRequest} "Pending username change retrieved successfully"
+// @Failure 404 {object} models.Response "No pending username change"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /user/username/pending [get]
+func (h *UsernameChangeHandler) GetMyPendingUsernameChange(c *fiber.Ctx) error {
+	userID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	request, err := h.UserRepo.GetPendingUsernameChange(c.UserContext(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "No pending username change"})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get pending username change")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve username change"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Pending username change retrieved successfully", Data: request,
+	})
+}
+
+// GetUsernameChangeRequests godoc
+// @Summary List username change requests (Admin)
+// @Description Lists username change requests, oldest first, with the user's current username. Defaults to pending requests; status=all lists every request.
+// @Tags Admin - Users Management
+// @Produce json
+// @Param status query string false "pending (default), approved, rejected or all"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.Response{data=[]models.UsernameChangeRequest} "Username change requests retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid status"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/username-requests [get]
+func (h *UsernameChangeHandler) GetUsernameChangeRequests(c *fiber.Ctx) error {
+	status := c.Query("status", models.UsernameChangePending)
+	switch status {
+	case models.UsernameChangePending, models.UsernameChangeApproved, models.UsernameChangeRejected:
+	case "all":
+		status = ""
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Invalid status, expected pending, approved, rejected or all",
+		})
+	}
+	pagination := utils.ParsePaginationParams(c)
+	requests, total, err := h.UserRepo.GetUsernameChangeRequests(c.UserContext(), status, pagination.Page, pagination.Limit)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to get username change requests")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve username change requests"})
+	}
+	meta := utils.BuildPaginationMeta(total, pagination.Limit, pagination.Page)
+	return c.Status(http.StatusOK).JSON(utils.NewPaginatedResponse("Username change requests retrieved successfully", requests, meta))
+}
+
+// ApproveUsernameChange godoc
+// @Summary Approve a username change (Admin)
+// @Description Applies the requested username. The previous username is kept in the user's username history and the rename is recorded in the audit log. The user is notified in their inbox.
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param requestId path int true "Username change request ID"
+// @Param review body models.ReviewUsernameChangeInput false "Optional note for the user"
+// @Success 200 {object} models.Response{data=models.UsernameChangeRequest} "Username change approved"
+// @Failure 400 {object} models.Response "Invalid request ID or body"
+// @Failure 404 {object} models.Response "Username change request not found"
+// @Failure 409 {object} models.Response "Request already reviewed or username already taken"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/username-requests/{requestId}/approve [post]
+func (h *UsernameChangeHandler) ApproveUsernameChange(c *fiber.Ctx) error {
+	return h.review(c, true)
+}
+
+// RejectUsernameChange godoc
+// @Summary Reject a username change (Admin)
+// @Description Rejects the request; the user keeps their current username and is notified in their inbox.
+// @Tags Admin - Users Management
+// @Accept json
+// @Produce json
+// @Param requestId path int true "Username change request ID"
+// @Param review body models.ReviewUsernameChangeInput false "Optional note for the user"
+// @Success 200 {object} models.Response{data=models.UsernameChangeRequest} "Username change rejected"
+// @Failure 400 {object} models.Response "Invalid request ID or body"
+// @Failure 404 {object} models.Response "Username change request not found"
+// @Failure 409 {object} models.Response "Request already reviewed"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/username-requests/{requestId}/reject [post]
+func (h *UsernameChangeHandler) RejectUsernameChange(c *fiber.Ctx) error {
+	return h.review(c, false)
+}
+
+func (h *UsernameChangeHandler) review(c *fiber.Ctx, approve bool) error {
+	adminID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	requestID, err := idParam(c, "requestId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Request ID parameter"})
+	}
+	input := new(models.ReviewUsernameChangeInput)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+		}
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	var note *string
+	if input.Note != "" {
+		note = &input.Note
+	}
+
+	// Keputusan & event (audit + notifikasi via outbox) dalam satu transaksi
+	var request *models.UsernameChangeRequest
+	err = runInTx(c, h.Tx, func() error {
+		var err error
+		if request, err = h.UserRepo.ReviewUsernameChangeRequest(c.UserContext(), requestID, adminID, approve, note); err != nil {
+			return err
+		}
+		data := map[string]any{"request_id": request.ID, "status": request.Status}
+		if note != nil {
+			data["note"] = *note
+		}
+		publishEvent(c, h.Events, events.Event{Name: events.UsernameReviewed, UserID: request.UserID, Data: data})
+		return nil
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{
+			Success: false, Message: fmt.Sprintf("Username change request with ID %d not found", requestID),
+		})
+	case errors.Is(err, repository.ErrUsernameChangeReviewed):
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "Username change request has already been reviewed", Data: request})
+	case errors.Is(err, repository.ErrUsernameTaken):
+		return c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "username already taken"})
+	case err != nil:
+		reqLogger(c).Error().Err(err).Int("username_change_id", requestID).Msg("Failed to review username change")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to review username change"})
+	}
+
+	reqLogger(c).Info().Int("username_change_id", request.ID).Int("target_user_id", request.UserID).Str("status", request.Status).Msg("Username change reviewed")
+	message := "Username change rejected"
+	if approve {
+		message = "Username change approved"
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{Success: true, Message: message, Data: request})
+}
+
+// GetPreviousUsernames godoc
+// @Summary Get a user's previous usernames (Admin)
+// @Description Lists the usernames a user had before, newest first, with when each was replaced.
+// @Tags Admin - Users Management
+// @Produce json
+// @Param userId path int true "User ID"
+// @Success 200 {object} models.Response{data=[]models.PreviousUsername} "Previous usernames retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid user ID"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId}/previous-usernames [get]
+func (h *UsernameChangeHandler) GetPreviousUsernames(c *fiber.Ctx) error {
+	userID, err := idParam(c, "userId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid User ID parameter"})
+	}
+	names, err := h.UserRepo.GetPreviousUsernames(c.UserContext(), userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("target_user_id", userID).Msg("Failed to get previous usernames")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve previous usernames"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Previous usernames retrieved successfully", Data: names,
+	})
+}
+
+// ResolveUsername godoc
+// @Summary Find a user by current or previous username (Admin)
+// @Description Resolves a username found in an older export or report to the user it belonged to. The current username wins; otherwise the latest user that had it as a previous username is returned with matched_previous=true.
+// @Tags Admin - Users Management
+// @Produce json
+// @Param username query string true "Username to resolve"
+// @Success 200 {object} models.Response{data=models.UsernameResolution} "Username resolved successfully"
+// @Failure 400 {object} models.Response "Missing username"
+// @Failure 404 {object} models.Response "Username was never used"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/users/resolve [get]
+func (h *UsernameChangeHandler) ResolveUsername(c *fiber.Ctx) error {
+	username := c.Query("username")
+	if username == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Query parameter username is required"})
+	}
+	resolution, err := h.UserRepo.ResolveUsername(c.UserContext(), username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(models.Response{Success: false, Message: "No user has or had this username"})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Failed to resolve username")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to resolve username"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Username resolved successfully", Data: resolution,
+	})
+}
+
+// requestUsernameChange menerapkan kebijakan ganti username untuk username baru dari profil:
+// deny menolak dengan 403, approval membuat permintaan yang menunggu persetujuan admin.
+func requestUsernameChange(c *fiber.Ctx, users repository.UserRepository, bus events.Publisher, policy string, user *models.User, username string) (request *models.UsernameChangeRequest, handled bool, resp error) {
+	if policy != models.UsernamePolicyApproval {
+		return nil, true, c.Status(fiber.StatusForbidden).JSON(models.Response{Success: false, Message: "Username changes are not allowed"})
+	}
+	request, err := users.CreateUsernameChangeRequest(c.UserContext(), user.ID, username)
+	if errors.Is(err, repository.ErrUsernameTaken) {
+		return nil, true, c.Status(fiber.StatusConflict).JSON(models.Response{Success: false, Message: "username already taken"})
+	}
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", user.ID).Msg("Failed to create username change request")
+		return nil, true, c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to request username change"})
+	}
+	publishEvent(c, bus, events.Event{Name: events.UsernameRequested, UserID: user.ID, Data: map[string]any{"request_id": request.ID}})
+	reqLogger(c).Info().Int("user_id", user.ID).Int("username_change_id", request.ID).Msg("Username change requested")
+	return request, false, nil
+}
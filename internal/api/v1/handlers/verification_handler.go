@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+const (
+	defaultVerificationLinkTTL = 72 * time.Hour // Masa berlaku tautan jika expires_in_hours kosong
+	verificationAttendanceDays = 90             // Jendela ringkasan kehadiran pada verifikasi
+)
+
+// VerificationHandler melayani tautan publik verifikasi kepegawaian: admin membuat tautan
+// bertanda tangan dan berbatas waktu untuk pihak ketiga (bank, pemilik sewa), yang membukanya
+// tanpa login dan hanya melihat data terbatas. Setiap akses dicatat per tautan dan di audit log.
+type VerificationHandler struct {
+	VerificationRepo repository.VerificationRepository
+	Events           events.Publisher // Audit pembuatan, pencabutan, dan akses tautan
+	Validate         *validator.Validate
+}
+
+func NewVerificationHandler(verificationRepo repository.VerificationRepository, eventBus events.Publisher) *VerificationHandler {
+	return &VerificationHandler{
+		VerificationRepo: verificationRepo,
+		Events:           eventBus,
+		Validate:         validator.New(),
+	}
+}
+
+// CreateVerificationLink godoc
+// @Summary Create an employment verification link
+// @Description Creates a signed public link that lets a third party (bank, landlord) confirm the user's employment without logging in. The link expires after expires_in_hours (default 72, max 720) and can be revoked earlier. The third party sees only name, position, employment status and hire date, plus the number of days present in the last 90 days when include_attendance is true. The token is returned only once.
+// @Tags Admin - Employment Verification
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param link body models.EmploymentVerificationLinkInput true "Recipient, validity and attendance summary"
+// @Success 201 {object} models.Response{data=models.EmploymentVerificationLink} "Verification link created successfully"
+// @Failure 400 {object} models.Response "Invalid user ID or validation failed"
+// @Failure 404 {object} models.Response "User not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId}/verification-link [post]
+func (h *VerificationHandler) CreateVerificationLink(c *fiber.Ctx) error {
+	adminID, err := utils.ExtractUserIDFromJWT(c)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Msg("Error extracting userID from JWT")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to identify user"})
+	}
+	userID, err := strconv.Atoi(c.Params("userId"))
+	if err != nil || userID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid User ID parameter"})
+	}
+	input := new(models.EmploymentVerificationLinkInput)
+	if err := c.BodyParser(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Failed to parse request body"})
+	}
+	if err := h.Validate.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Validation failed", Data: err.Error(),
+		})
+	}
+	ttl := defaultVerificationLinkTTL
+	if input.ExpiresInHours > 0 {
+		ttl = time.Duration(input.ExpiresInHours) * time.Hour
+	}
+
+	link := &models.EmploymentVerificationLink{
+		UserID: userID, Recipient: input.Recipient, IncludeAttendance: input.IncludeAttendance,
+		ExpiresAt: time.Now().Add(ttl).Truncate(time.Second), CreatedBy: &adminID,
+	}
+	if err := h.VerificationRepo.CreateVerificationLink(c.UserContext(), link); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("User with ID %d not found", userID),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to create employment verification link")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create verification link"})
+	}
+	link.Token, err = utils.GenerateVerificationLinkToken(link.ID, link.UserID, link.ExpiresAt)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("link_id", link.ID).Msg("Failed to sign employment verification link")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to create verification link"})
+	}
+	link.Path = "/api/v1/verify/" + link.Token
+	publishEvent(c, h.Events, events.Event{
+		Name: events.VerificationLinkCreated, UserID: userID,
+		Data: map[string]any{
+			"link_id": link.ID, "recipient": link.Recipient, "expires_at": link.ExpiresAt,
+			"include_attendance": link.IncludeAttendance,
+		},
+	})
+
+	reqLogger(c).Info().Int("link_id", link.ID).Int("user_id", userID).Time("expires_at", link.ExpiresAt).Msg("Employment verification link created")
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Success: true, Message: "Verification link created successfully", Data: link,
+	})
+}
+
+// GetUserVerificationLinks godoc
+// @Summary Get a user's employment verification links
+// @Description Lists the verification links created for a user, newest first, with their recipient, expiry, revocation, number of served accesses and last access time. Tokens are not returned.
+// @Tags Admin - Employment Verification
+// @Produce json
+// @Param userId path int true "User ID"
+// @Success 200 {object} models.Response{data=[]models.EmploymentVerificationLink} "Verification links retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid user ID"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/users/{userId}/verification-links [get]
+func (h *VerificationHandler) GetUserVerificationLinks(c *fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("userId"))
+	if err != nil || userID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid User ID parameter"})
+	}
+	links, err := h.VerificationRepo.GetVerificationLinksByUser(c.UserContext(), userID)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("user_id", userID).Msg("Failed to get employment verification links")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve verification links"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Verification links retrieved successfully", Data: links,
+	})
+}
+
+// GetVerificationLinkAccesses godoc
+// @Summary Get accesses of an employment verification link
+// @Description Lists every request made with a verification link, newest first, with IP, user agent and outcome (granted, expired or revoked).
+// @Tags Admin - Employment Verification
+// @Produce json
+// @Param linkId path int true "Verification link ID"
+// @Success 200 {object} models.Response{data=[]models.EmploymentVerificationAccess} "Verification link accesses retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid link ID"
+// @Failure 404 {object} models.Response "Verification link not found"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/verification-links/{linkId}/accesses [get]
+func (h *VerificationHandler) GetVerificationLinkAccesses(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("linkId"))
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Link ID parameter"})
+	}
+	if _, err := h.VerificationRepo.GetVerificationLink(c.UserContext(), id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Verification link with ID %d not found", id),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("link_id", id).Msg("Failed to get employment verification link")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve verification link accesses"})
+	}
+	accesses, err := h.VerificationRepo.GetVerificationAccesses(c.UserContext(), id)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("link_id", id).Msg("Failed to get employment verification accesses")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to retrieve verification link accesses"})
+	}
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Verification link accesses retrieved successfully", Data: accesses,
+	})
+}
+
+// RevokeVerificationLink godoc
+// @Summary Revoke an employment verification link
+// @Description Revokes a verification link before it expires; later requests with its token get 410 and are still recorded.
+// @Tags Admin - Employment Verification
+// @Produce json
+// @Param linkId path int true "Verification link ID"
+// @Success 200 {object} models.Response{data=models.EmploymentVerificationLink} "Verification link revoked successfully"
+// @Failure 400 {object} models.Response "Invalid link ID"
+// @Failure 404 {object} models.Response "Verification link not found or already revoked"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Security ApiKeyAuth
+// @Router /admin/verification-links/{linkId} [delete]
+func (h *VerificationHandler) RevokeVerificationLink(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("linkId"))
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{Success: false, Message: "Invalid Link ID parameter"})
+	}
+	link, err := h.VerificationRepo.RevokeVerificationLink(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Active verification link with ID %d not found", id),
+			})
+		}
+		reqLogger(c).Error().Err(err).Int("link_id", id).Msg("Failed to revoke employment verification link")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to revoke verification link"})
+	}
+	publishEvent(c, h.Events, events.Event{
+		Name: events.VerificationLinkRevoked, UserID: link.UserID,
+		Data: map[string]any{"link_id": link.ID, "recipient": link.Recipient},
+	})
+
+	reqLogger(c).Info().Int("link_id", id).Int("user_id", link.UserID).Msg("Employment verification link revoked")
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Verification link revoked successfully", Data: link,
+	})
+}
+
+// VerifyEmployment godoc
+// @Summary Verify employment
+// @Description Public endpoint opened by a third party with a verification link created by an admin. Returns the employee's name, position, employment status, hire date and whether they are currently employed, plus days present in the last 90 days if the link includes attendance. Every request with a valid signature is recorded (IP, user agent, outcome) and audited; expired and revoked links answer 410. Rate limited per IP (RATE_LIMIT_VERIFY_*).
+// @Tags Public
+// @Produce json
+// @Param token path string true "Verification link token"
+// @Success 200 {object} models.Response{data=models.EmploymentVerification} "Employment verified"
+// @Failure 404 {object} models.Response "Invalid verification link"
+// @Failure 410 {object} models.Response "Verification link expired or revoked"
+// @Failure 500 {object} models.Response "Internal server error"
+// @Router /verify/{token} [get]
+func (h *VerificationHandler) VerifyEmployment(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	invalid := models.Response{Success: false, Message: "Verification link is invalid"}
+	linkID, userID, err := utils.ParseVerificationLinkToken(c.Params("token"))
+	if err != nil {
+		reqLogger(c).Warn().Err(err).Msg("Invalid employment verification token")
+		return c.Status(fiber.StatusNotFound).JSON(invalid)
+	}
+	link, err := h.VerificationRepo.GetVerificationLink(c.UserContext(), linkID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(invalid)
+		}
+		reqLogger(c).Error().Err(err).Int("link_id", linkID).Msg("Failed to get employment verification link")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to verify employment"})
+	}
+	if link.UserID != userID {
+		reqLogger(c).Warn().Int("link_id", linkID).Int("token_user_id", userID).Msg("Employment verification token does not match its link")
+		return c.Status(fiber.StatusNotFound).JSON(invalid)
+	}
+
+	switch {
+	case link.RevokedAt != nil:
+		h.recordAccess(c, link, models.VerificationAccessRevoked)
+		return c.Status(fiber.StatusGone).JSON(models.Response{Success: false, Message: "Verification link has been revoked"})
+	case !time.Now().Before(link.ExpiresAt):
+		h.recordAccess(c, link, models.VerificationAccessExpired)
+		return c.Status(fiber.StatusGone).JSON(models.Response{Success: false, Message: "Verification link has expired"})
+	}
+
+	var attendanceSince *time.Time
+	if link.IncludeAttendance {
+		since := startOfToday().AddDate(0, 0, -(verificationAttendanceDays - 1))
+		attendanceSince = &since
+	}
+	verification, err := h.VerificationRepo.GetEmploymentVerification(c.UserContext(), link.UserID, attendanceSince)
+	if err != nil {
+		reqLogger(c).Error().Err(err).Int("link_id", link.ID).Msg("Failed to get employment verification data")
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to verify employment"})
+	}
+	// Data hanya dilayani jika aksesnya berhasil dicatat.
+	if !h.recordAccess(c, link, models.VerificationAccessGranted) {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.Response{Success: false, Message: "Failed to verify employment"})
+	}
+	verification.Recipient = link.Recipient
+	verification.VerifiedAt = time.Now()
+	verification.ValidUntil = link.ExpiresAt
+	return c.Status(http.StatusOK).JSON(models.Response{
+		Success: true, Message: "Employment verified", Data: verification,
+	})
+}
+
+// recordAccess mencatat satu akses ke tautan (tabel akses & audit log). Mengembalikan false jika
+// akses gagal dicatat ke tabel akses.
+func (h *VerificationHandler) recordAccess(c *fiber.Ctx, link *models.EmploymentVerificationLink, outcome string) bool {
+	details := clientDetails(c)
+	ip, _ := details["ip"].(string)
+	access := &models.EmploymentVerificationAccess{LinkID: link.ID, IP: ip, Outcome: outcome}
+	if userAgent, _ := details["user_agent"].(string); userAgent != "" {
+		access.UserAgent = &userAgent
+	}
+	if err := h.VerificationRepo.RecordVerificationAccess(c.UserContext(), access); err != nil {
+		reqLogger(c).Error().Err(err).Int("link_id", link.ID).Str("outcome", outcome).Msg("Failed to record employment verification access")
+		return false
+	}
+	details["link_id"] = link.ID
+	details["recipient"] = link.Recipient
+	details["outcome"] = outcome
+	publishEvent(c, h.Events, events.Event{Name: events.VerificationAccessed, UserID: link.UserID, Data: details})
+	reqLogger(c).Info().Int("link_id", link.ID).Int("user_id", link.UserID).Str("outcome", outcome).Msg("Employment verification link accessed")
+	return true
+}
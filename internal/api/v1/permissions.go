@@ -0,0 +1,244 @@
+package v1
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/middleware"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/openapi"
+)
+
+// permission adalah syarat akses satu route. Setiap route v1 didaftarkan lewat routeGroup yang
+// terikat ke satu permission, dan middleware autentikasi/otorisasinya disusun dari permission
+// itu, sehingga route baru tidak bisa terdaftar tanpa keputusan akses yang eksplisit.
+type permission struct {
+	name  string
+	roles []string // Kosong = tidak dibatasi role
+	login bool     // Wajib token sesi yang valid
+}
+
+var (
+	// permPublic: tanpa login (mis. health, auth, tautan verifikasi bertanda tangan).
+	permPublic = permission{name: models.PermissionPublic}
+	// permAuthenticated: cukup login; handler membatasi data ke milik user sendiri atau
+	// bawahannya, sehingga semua role (termasuk role kustom) boleh mengakses.
+	permAuthenticated = permission{name: models.PermissionAuthenticated, login: true}
+)
+
+// requireRoles: login dengan salah satu role (atau role yang mewarisinya, lihat internal/rbac).
+func requireRoles(roles ...string) permission {
+	return permission{name: "role:" + strings.Join(roles, "|"), roles: roles, login: true}
+}
+
+// routeRegistry membuat routeGroup di bawah router API dan mencatat permission setiap route
+// yang didaftarkan lewat routeGroup.
+type routeRegistry struct {
+	api      fiber.Router
+	prefix   string // Prefix router API, mis. /api/v1
+	sessions middleware.TokenVersionSource
+	roles    middleware.RoleResolver
+	devices  middleware.KioskDeviceSource
+	capturer middleware.RequestCapturer // nil = perekaman request debug nonaktif
+	gate     middleware.MaintenanceGate // nil = mode maintenance tidak pernah aktif
+	spec     *openapi.Spec              // nil = validasi request terhadap spesifikasi OpenAPI nonaktif
+	timeout  time.Duration              // Batas waktu default setiap route (lihat middleware.Timeout); 0 = tanpa batas
+	routes   []models.RoutePermission
+}
+
+func newRouteRegistry(app *fiber.App, prefix string, sessions middleware.TokenVersionSource, roles middleware.RoleResolver, devices middleware.KioskDeviceSource, capturer middleware.RequestCapturer, gate middleware.MaintenanceGate, spec *openapi.Spec, timeout time.Duration) *routeRegistry {
+	return &routeRegistry{api: app.Group(prefix), prefix: prefix, sessions: sessions, roles: roles, devices: devices, capturer: capturer, gate: gate, spec: spec, timeout: timeout}
+}
+
+// maintenancePolicy menentukan perlakuan route selama mode maintenance.
+type maintenancePolicy int
+
+const (
+	maintenanceBlocked  maintenancePolicy = iota // Dijawab 503 + Retry-After (default)
+	maintenanceOpen                              // Tetap dilayani (admin, login, health)
+	maintenanceBuffered                          // Dilayani jika check-in sedang ditampung (maintenance.checkins = buffer)
+)
+
+// routeGroup mendaftarkan route di bawah satu prefix dengan satu permission. Middleware
+// dipasang per route (bukan Use pada grup) agar grup tanpa prefix tidak ikut menjaga route lain.
+type routeGroup struct {
+	router   fiber.Router
+	prefix   string // Prefix lengkap untuk registry, mis. /api/v1/admin
+	perm     permission
+	scopes   []string // Scope token terbatas yang diterima selain ScopeRead untuk GET
+	noRead   bool     // GET tidak menerima token ScopeRead (lihat withoutReadScope)
+	capture  bool     // Route boleh direkam untuk debugging (lihat middleware.CaptureRequests)
+	during   maintenancePolicy
+	timeout  time.Duration // Batas waktu proses route (middleware.Timeout); 0 = tanpa batas
+	handlers []fiber.Handler
+	registry *routeRegistry
+}
+
+// group membuat routeGroup untuk prefix dengan permission perm. handlers (rate limiter, batas
+// body, dll.) dijalankan setelah middleware autentikasi/otorisasi agar kunci rate limit bisa
+// per user.
+func (reg *routeRegistry) group(prefix string, perm permission, handlers ...fiber.Handler) *routeGroup {
+	return &routeGroup{router: reg.api.Group(prefix), prefix: reg.prefix + prefix, perm: perm, capture: true, timeout: reg.timeout, handlers: handlers, registry: reg}
+}
+
+// withScopes mengembalikan salinan grup yang juga menerima token terbatas dengan salah satu
+// scopes (mis. token kiosk untuk route absen).
+func (g *routeGroup) withScopes(scopes ...string) *routeGroup {
+	scoped := *g
+	scoped.scopes = append(slices.Clip(g.scopes), scopes...)
+	return &scoped
+}
+
+// withoutReadScope mengembalikan salinan grup yang route GET-nya tidak menerima token ScopeRead
+// (unduhan file, ekspor massal, rekaman debug, diagnostik): token baca berumur panjang dan tidak bisa
+// dicabut satu per satu, jadi hanya boleh membaca endpoint JSON biasa.
+func (g *routeGroup) withoutReadScope() *routeGroup {
+	unread := *g
+	unread.noRead = true
+	return &unread
+}
+
+// withoutCapture mengembalikan salinan grup yang route-nya tidak pernah direkam untuk debugging
+// (mis. route yang membaca rekaman itu sendiri).
+func (g *routeGroup) withoutCapture() *routeGroup {
+	uncaptured := *g
+	uncaptured.capture = false
+	return &uncaptured
+}
+
+// withTimeout mengembalikan salinan grup dengan batas waktu proses sendiri (mis. laporan berat
+// yang butuh budget lebih panjang dari default). 0 = tanpa batas, untuk route yang menulis body
+// secara streaming setelah handler selesai (unduhan, ekspor).
+func (g *routeGroup) withTimeout(timeout time.Duration) *routeGroup {
+	limited := *g
+	limited.timeout = timeout
+	return &limited
+}
+
+// duringMaintenance mengembalikan salinan grup yang route-nya tetap dilayani selama mode
+// maintenance (mis. route admin dan login agar admin bisa menonaktifkannya lagi).
+func (g *routeGroup) duringMaintenance() *routeGroup {
+	open := *g
+	open.during = maintenanceOpen
+	return &open
+}
+
+// bufferedDuringMaintenance mengembalikan salinan grup yang route-nya diteruskan ke handler
+// selama maintenance jika check-in sedang ditampung; handler yang menampung request-nya.
+func (g *routeGroup) bufferedDuringMaintenance() *routeGroup {
+	buffered := *g
+	buffered.during = maintenanceBuffered
+	return &buffered
+}
+
+// add mendaftarkan route dengan rantai CaptureRequests -> Timeout -> Maintenance -> Protected -> DeviceBound ->
+// RequireScope -> Authorize (sesuai permission grup) -> validasi OpenAPI (jika aktif), lalu handler
+// grup dan handler route. Route yang tidak dibuka lewat duringMaintenance dijawab 503 selama mode
+// maintenance. Token terbatas hanya diterima route GET (ScopeRead, kecuali grup withoutReadScope)
+// dan route yang scope-nya dicantumkan lewat withScopes.
+func (g *routeGroup) add(method, path string, handlers []fiber.Handler) {
+	var chain []fiber.Handler
+	var scopes []string
+	if g.capture && g.registry.capturer != nil {
+		chain = append(chain, middleware.CaptureRequests(g.registry.capturer, g.prefix+path))
+	}
+	if g.timeout > 0 {
+		// Dipasang sebelum autentikasi agar lookup sesi/role ikut dihitung dalam budget route.
+		chain = append(chain, middleware.Timeout(g.timeout))
+	}
+	if g.during != maintenanceOpen && g.registry.gate != nil {
+		chain = append(chain, middleware.Maintenance(g.registry.gate, g.during == maintenanceBuffered))
+	}
+	if g.perm.login {
+		scopes = slices.Clone(g.scopes)
+		if method == fiber.MethodGet && !g.noRead {
+			scopes = append(scopes, models.ScopeRead)
+		}
+		chain = append(chain, middleware.Protected(g.registry.sessions), middleware.DeviceBound(g.registry.devices), middleware.RequireScope(scopes...))
+	}
+	if len(g.perm.roles) > 0 {
+		chain = append(chain, middleware.Authorize(g.registry.roles, g.perm.roles...))
+	}
+	if validate := g.registry.spec.Validator(method, g.prefix+path); validate != nil {
+		chain = append(chain, validate)
+	}
+	chain = append(append(chain, g.handlers...), handlers...)
+	g.router.Add(method, path, chain...)
+	g.registry.routes = append(g.registry.routes, models.RoutePermission{
+		Method: method, Path: g.prefix + path, Permission: g.perm.name, Roles: g.perm.roles, Scopes: scopes,
+	})
+}
+
+func (g *routeGroup) Get(path string, handlers ...fiber.Handler) {
+	g.add(fiber.MethodGet, path, handlers)
+}
+
+func (g *routeGroup) Post(path string, handlers ...fiber.Handler) {
+	g.add(fiber.MethodPost, path, handlers)
+}
+
+func (g *routeGroup) Put(path string, handlers ...fiber.Handler) {
+	g.add(fiber.MethodPut, path, handlers)
+}
+
+func (g *routeGroup) Patch(path string, handlers ...fiber.Handler) {
+	g.add(fiber.MethodPatch, path, handlers)
+}
+
+func (g *routeGroup) Delete(path string, handlers ...fiber.Handler) {
+	g.add(fiber.MethodDelete, path, handlers)
+}
+
+// verify memastikan setiap route di bawah prefix API terdaftar lewat routeGroup (punya
+// permission). Route yang didaftarkan langsung pada router Fiber dianggap lupa otorisasi.
+func (reg *routeRegistry) verify(app *fiber.App) error {
+	known := make(map[string]bool, len(reg.routes))
+	for _, r := range reg.routes {
+		known[r.Method+" "+r.Path] = true
+	}
+	var missing []string
+	for _, r := range app.GetRoutes(true) {
+		if !strings.HasPrefix(r.Path, reg.prefix) || known[r.Method+" "+r.Path] {
+			continue
+		}
+		// Fiber menambahkan HEAD otomatis untuk setiap GET.
+		if r.Method == fiber.MethodHead && known[fiber.MethodGet+" "+r.Path] {
+			continue
+		}
+		missing = append(missing, r.Method+" "+r.Path)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("routes registered without a permission: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// sorted mengembalikan salinan daftar route urut path lalu method.
+func (reg *routeRegistry) sorted() []models.RoutePermission {
+	routes := slices.Clone(reg.routes)
+	slices.SortFunc(routes, func(a, b models.RoutePermission) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method))
+	})
+	return routes
+}
+
+// routePermissions godoc
+// @Summary Get route permissions
+// @Description Machine-readable map of every v1 route and the permission it requires: public, authenticated (any logged-in user; handlers scope data to the user or their team) or role:<A|B> (one of the roles, or a role inheriting one of them), plus the scopes of limited tokens it accepts (full session tokens are always accepted). The authentication and authorization middleware of each route is generated from this permission.
+// @Tags Admin - Monitoring
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.RoutePermission} "Route permissions retrieved successfully"
+// @Security ApiKeyAuth
+// @Router /admin/permissions/routes [get]
+func routePermissions(reg *routeRegistry) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.Status(http.StatusOK).JSON(models.Response{
+			Success: true, Message: "Route permissions retrieved successfully", Data: reg.sorted(),
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteGroupReadScope(t *testing.T) {
+	reg := newRouteRegistry(fiber.New(), "/api/v1", nil, nil, nil, nil, nil, nil, 0)
+	admin := reg.group("/admin", requireRoles("Admin"))
+	user := reg.group("/user", permAuthenticated)
+
+	admin.Get("/users", func(c *fiber.Ctx) error { return nil })
+	admin.Post("/users", func(c *fiber.Ctx) error { return nil })
+	admin.withoutReadScope().Get("/users/export", func(c *fiber.Ctx) error { return nil })
+	admin.withTimeout(0).withoutReadScope().Get("/maintenance/backups/:backupId/download", func(c *fiber.Ctx) error { return nil })
+	user.withScopes(models.ScopeAttendancePunch).Get("/projects", func(c *fiber.Ctx) error { return nil })
+	user.withScopes(models.ScopeAttendancePunch).withoutReadScope().Get("/kiosk", func(c *fiber.Ctx) error { return nil })
+	reg.group("/health", permPublic).Get("", func(c *fiber.Ctx) error { return nil })
+
+	scopes := make(map[string][]string)
+	for _, r := range reg.routes {
+		scopes[r.Method+" "+r.Path] = r.Scopes
+	}
+	tests := []struct {
+		route string
+		want  []string
+	}{
+		{"GET /api/v1/admin/users", []string{models.ScopeRead}},
+		{"POST /api/v1/admin/users", []string{}},
+		{"GET /api/v1/admin/users/export", []string{}},
+		{"GET /api/v1/admin/maintenance/backups/:backupId/download", []string{}},
+		{"GET /api/v1/user/projects", []string{models.ScopeAttendancePunch, models.ScopeRead}},
+		{"GET /api/v1/user/kiosk", []string{models.ScopeAttendancePunch}},
+		{"GET /api/v1/health", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			got, ok := scopes[tt.route]
+			require.True(t, ok, "route not registered")
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
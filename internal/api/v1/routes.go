@@ -1,33 +1,92 @@
 package v1
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/configs"
 	"github.com/rakaarfi/attendance-system-be/internal/api/v1/handlers" // Handler spesifik v1
+	"github.com/rakaarfi/attendance-system-be/internal/buildinfo"       // Versi & info build untuk /version
+	"github.com/rakaarfi/attendance-system-be/internal/captcha"         // Verifier CAPTCHA (opsional)
+	"github.com/rakaarfi/attendance-system-be/internal/degraded"        // Status mode degraded untuk healthcheck
 	"github.com/rakaarfi/attendance-system-be/internal/middleware"      // Middleware aplikasi (Auth, dll)
+	"github.com/rakaarfi/attendance-system-be/internal/models"          // Scope token terbatas
+	"github.com/rakaarfi/attendance-system-be/internal/openapi"         // Spesifikasi OpenAPI & validasi request
 )
 
-func SetupRoutes(app *fiber.App, authHandler *handlers.AuthHandler, adminHandler *handlers.AdminHandler, userHandler *handlers.UserHandler) {
+func SetupRoutes(app *fiber.App, authHandler *handlers.AuthHandler, adminHandler *handlers.AdminHandler, userHandler *handlers.UserHandler, announcementHandler *handlers.AnnouncementHandler, documentHandler *handlers.DocumentHandler, orgHandler *handlers.OrgHandler, payrollHandler *handlers.PayrollHandler, projectHandler *handlers.ProjectHandler, signOffHandler *handlers.SignOffHandler, delegationHandler *handlers.DelegationHandler, disputeHandler *handlers.DisputeHandler, approvalHandler *handlers.ApprovalHandler, deviceHandler *handlers.DeviceHandler, notificationHandler *handlers.NotificationHandler, outboxHandler *handlers.OutboxHandler, laborHandler *handlers.LaborHandler, forecastHandler *handlers.ForecastHandler, jobHandler *handlers.JobHandler, verificationHandler *handlers.VerificationHandler, kioskHandler *handlers.KioskHandler, photoHandler *handlers.PhotoHandler, reportHandler *handlers.ReportHandler, emailChangeHandler *handlers.EmailChangeHandler, usernameChangeHandler *handlers.UsernameChangeHandler, phoneHandler *handlers.PhoneHandler, invitationHandler *handlers.InvitationHandler, routeHandler *handlers.RouteHandler, syncHandler *handlers.SyncHandler, debugCaptureHandler *handlers.DebugCaptureHandler, backupHandler *handlers.BackupHandler, maintenanceHandler *handlers.MaintenanceHandler, systemHandler *handlers.SystemHandler, captchaVerifier captcha.Verifier, sessions middleware.TokenVersionSource, roles middleware.RoleResolver, kiosks middleware.KioskDeviceSource, capturer middleware.RequestCapturer, maintenanceGate middleware.MaintenanceGate, degradedMode *degraded.Controller, apiSpec *openapi.Spec) {
 	// -------------------------------------------------------------------------
 	// Grouping Rute API v1
 	// -------------------------------------------------------------------------
-	// Membuat grup rute dengan prefix /api/v1
-	api := app.Group("/api/v1")
+	// Membuat grup rute dengan prefix /api/v1. Setiap route didaftarkan lewat grup yang terikat
+	// ke satu permission (lihat permissions.go): middleware Protected/Authorize disusun dari
+	// permission itu, dan route yang terdaftar tanpa permission membuat startup gagal.
+	// Request ke route mana pun bisa direkam untuk debugging (capturer, pengaturan debug.capture_*).
+	// Selama mode maintenance (maintenanceGate, pengaturan maintenance.*) route dijawab 503 kecuali
+	// grup yang dibuka lewat duringMaintenance.
+	// Setiap route dibatasi REQUEST_TIMEOUT (query dibatalkan, 504); laporan berat memakai budget
+	// REQUEST_TIMEOUT_REPORTS, dan unduhan/ekspor streaming tidak dibatasi (lihat withTimeout).
+	reg := newRouteRegistry(app, "/api/v1", sessions, roles, kiosks, capturer, maintenanceGate, apiSpec,
+		configs.GetEnvDuration("REQUEST_TIMEOUT", 30*time.Second))
+	reportTimeout := configs.GetEnvDuration("REQUEST_TIMEOUT_REPORTS", 2*time.Minute)
+
+	// -------------------------------------------------------------------------
+	// Budget Rate Limit per Grup Route
+	// -------------------------------------------------------------------------
+	// Default di bawah bisa di-override via env RATE_LIMIT_<GROUP>_READ_MAX/_WRITE_MAX/_WINDOW.
+	// Grup auth sengaja ketat (per IP) untuk menahan brute-force/credential stuffing,
+	// sedangkan grup yang butuh login dihitung per user dan lebih longgar untuk request baca.
+	authLimiter := middleware.RateLimit("auth", middleware.RateLimitBudgetFromEnv("auth", middleware.RateLimitBudget{
+		ReadMax: 30, WriteMax: 10, Window: time.Minute,
+	}))
+	publicLimiter := middleware.RateLimit("public", middleware.RateLimitBudgetFromEnv("public", middleware.RateLimitBudget{
+		ReadMax: 120, WriteMax: 30, Window: time.Minute,
+	}))
+	userLimiter := middleware.RateLimit("user", middleware.RateLimitBudgetFromEnv("user", middleware.RateLimitBudget{
+		ReadMax: 300, WriteMax: 60, Window: time.Minute,
+	}))
+	// Tautan verifikasi kepegawaian publik: budget per IP lebih ketat dari grup public agar token
+	// tidak bisa ditebak/di-scrape massal.
+	verifyLimiter := middleware.RateLimit("verify", middleware.RateLimitBudgetFromEnv("verify", middleware.RateLimitBudget{
+		ReadMax: 20, WriteMax: 5, Window: time.Minute,
+	}))
+	adminLimiter := middleware.RateLimit("admin", middleware.RateLimitBudgetFromEnv("admin", middleware.RateLimitBudget{
+		ReadMax: 600, WriteMax: 120, Window: time.Minute,
+	}))
 
 	// =========================================================================
 	// Rute Autentikasi (Publik - Tidak Memerlukan Login)
 	// =========================================================================
 	// Grup untuk endpoint yang berkaitan dengan autentikasi (/api/v1/auth)
-	auth := api.Group("/auth")
-	auth.Post("/register", authHandler.Register) // Endpoint untuk registrasi user baru
-	auth.Post("/login", authHandler.Login)       // Endpoint untuk login dan mendapatkan token JWT
+	// Middleware Captcha() hanya aktif jika CAPTCHA_PROVIDER di-set (lihat internal/captcha).
+	// Endpoint auth hanya menerima JSON (415 untuk Content-Type lain) dengan body kecil.
+	auth := reg.group("/auth", permPublic, authLimiter, middleware.BodyLimit(16*1024), middleware.RequireContentType(fiber.MIMEApplicationJSON))
+	auth.Post("/register", middleware.Captcha(captchaVerifier), authHandler.Register) // Endpoint untuk registrasi user baru
+	// Login tetap dibuka selama maintenance agar admin bisa masuk dan menonaktifkannya
+	login := auth.duringMaintenance()
+	login.Post("/login", middleware.Captcha(captchaVerifier), authHandler.Login) // Endpoint untuk login dan mendapatkan token JWT
+	login.Post("/login/otp", authHandler.VerifyLoginOTP)                         // Langkah kedua login dengan kode SMS (user dengan 2FA SMS)
+	auth.Post("/login-alerts/deny", authHandler.DenyLogin)                       // Tautan "bukan saya" dari email peringatan login: cabut semua sesi
+	auth.Post("/password/reset", authHandler.ResetPassword)                      // Ganti password dengan token reset dari /login-alerts/deny
+	auth.Post("/email/confirm", emailChangeHandler.ConfirmEmailChange)           // Tautan konfirmasi email baru (tanpa login)
+	// Registrasi lewat undangan admin; tetap terbuka saat auth.registration_mode = invite_only
+	auth.Post("/invitations/preview", invitationHandler.PreviewInvitation) // Email & role dari tautan undangan
+	auth.Post("/invitations/accept", invitationHandler.AcceptInvitation)   // Buat akun dengan role dari undangan
 
 	// =========================================================================
 	// Rute Admin (Memerlukan Login & Role 'Admin')
 	// =========================================================================
 	// Grup untuk endpoint khusus Admin (/api/v1/admin)
-	// Middleware .Protected() memastikan user sudah login (valid JWT)
-	// Middleware .Authorize("Admin") memastikan user memiliki role 'Admin'
-	admin := api.Group("/admin", middleware.Protected(), middleware.Authorize("Admin"))
+	// requireRoles("Admin") memasang Protected() (user sudah login dengan JWT valid dan sesinya
+	// belum dicabut) dan Authorize("Admin") (role 'Admin' atau role yang mewarisinya).
+	// Middleware adminLimiter dipasang setelahnya agar kunci rate limit per user.
+	// Route admin tetap dilayani selama mode maintenance.
+	admin := reg.group("/admin", requireRoles("Admin"), adminLimiter).duringMaintenance()
+	// Laporan & analitik berat: budget waktu sendiri agar tidak memakai default route biasa.
+	adminReports := admin.withTimeout(reportTimeout)
+	// Unduhan/ekspor menulis body setelah handler selesai, jadi context-nya tidak boleh dibatalkan.
+	// Token baca (ScopeRead) tidak menjangkau unduhan file & ekspor massal.
+	adminDownloads := admin.withTimeout(0).withoutReadScope()
 
 	// --- Manajemen Shift ---
 	admin.Post("/shifts", adminHandler.CreateShift)            // Membuat definisi shift baru
@@ -35,27 +94,116 @@ func SetupRoutes(app *fiber.App, authHandler *handlers.AuthHandler, adminHandler
 	admin.Get("/shifts/:shiftId", adminHandler.GetShiftByID)   // Mendapatkan detail shift berdasarkan ID
 	admin.Put("/shifts/:shiftId", adminHandler.UpdateShift)    // Memperbarui definisi shift
 	admin.Delete("/shifts/:shiftId", adminHandler.DeleteShift) // Menghapus definisi shift
+	// Override jam shift musiman berbatas tanggal (mis. Ramadan), berlaku otomatis untuk jadwal dalam rentang
+	admin.Post("/shift-overrides", adminHandler.CreateShiftOverride)               // Membuat override shift
+	admin.Get("/shift-overrides", adminHandler.GetShiftOverrides)                  // Daftar override shift (?active_from=)
+	admin.Delete("/shift-overrides/:overrideId", adminHandler.DeleteShiftOverride) // Menghapus override shift
 
 	// --- Manajemen Jadwal (Penugasan Shift ke User) ---
 	admin.Post("/schedules", adminHandler.CreateSchedule)               // Membuat jadwal baru untuk user pada tanggal tertentu
 	admin.Get("/schedules", adminHandler.GetAllSchedules)               // Mendapatkan semua jadwal (bisa difilter tanggal)
 	admin.Put("/schedules/:scheduleId", adminHandler.UpdateSchedule)    // Memperbarui jadwal yang sudah ada
+	admin.Patch("/schedules/:scheduleId", adminHandler.PatchSchedule)   // Memperbarui sebagian field jadwal (user_id/shift_id/date)
 	admin.Delete("/schedules/:scheduleId", adminHandler.DeleteSchedule) // Menghapus jadwal
+	// Hapus banyak jadwal (by ID atau filter user & tanggal) dalam satu transaksi
+	admin.Post("/schedules/bulk-delete", adminHandler.BulkDeleteSchedules)
+	// Saran jumlah orang per shift untuk minggu mendatang (moving average kehadiran) vs jadwal yang ada
+	adminReports.Get("/schedules/suggestions", forecastHandler.GetStaffingSuggestions)
 
 	// --- Laporan Kehadiran (Admin View) ---
-	admin.Get("/attendance/report", adminHandler.GetAttendanceReport) // Mendapatkan laporan kehadiran semua user (bisa difilter tanggal)
+	adminReports.Get("/attendance/report", adminHandler.GetAttendanceReport)            // Mendapatkan laporan kehadiran semua user (bisa difilter tanggal & status kepegawaian)
+	adminReports.Get("/attendance/report/projects", adminHandler.GetProjectHoursReport) // Rekap jam kerja per project/cost center (bisa difilter tanggal & user)
+	adminReports.Get("/attendance/report/tags", adminHandler.GetTagHoursReport)         // Rekap sesi & jam kerja per tag absensi (wfh, client_visit, ...)
+	adminReports.Get("/attendance/report/modes", adminHandler.GetModeHoursReport)       // Rekap sesi & jam kerja per mode kerja (onsite/remote/field)
+	adminReports.Get("/attendance/report/unsigned", signOffHandler.GetUnsignedDays)     // Tanggal kerja yang belum di-sign-off atasan (bisa difilter manager_id)
+	// Dispute karyawan: antrean (default status open) dan penyelesaian; laporan absensi menandai record ber-dispute open
+	admin.Get("/attendance/disputes", disputeHandler.GetAllDisputes)                     // Daftar dispute (bisa difilter status)
+	admin.Post("/attendance/disputes/:disputeId/resolve", disputeHandler.ResolveDispute) // Tutup dispute (resolved/rejected, wajib catatan)
+	// Approval inbox: semua item yang menunggu keputusan (saat ini dispute) dalam satu antrean + jumlah per tipe
+	admin.Get("/approvals", approvalHandler.GetApprovals)              // Antrean gabungan (bisa difilter type)
+	admin.Post("/approvals/bulk", approvalHandler.BulkDecideApprovals) // Approve/reject banyak item sekaligus (hasil per item)
+	// Koreksi tidak menimpa record: dicatat sebagai event di ledger attendance_events beserta alasannya
+	admin.Post("/attendance/:attendanceId/corrections", adminHandler.CorrectAttendance) // Koreksi record absensi (wajib alasan)
+	admin.Get("/attendance/:attendanceId/history", adminHandler.GetAttendanceHistory)   // Riwayat perubahan record absensi
+	admin.Get("/attendance/:attendanceId/segments", adminHandler.GetAttendanceSegments) // Segmen project (perpindahan project) record absensi
+	// Verifikasi wajah check-in: antrean punch yang ditandai (skor rendah/tanpa selfie/gagal) dan keputusannya
+	admin.Get("/attendance/face-checks", adminHandler.GetFaceChecks)                 // Hasil verifikasi wajah (default review_status=pending)
+	admin.Put("/attendance/:attendanceId/face-review", adminHandler.ReviewFaceCheck) // Setujui/tolak punch yang ditandai
+	admin.Get("/attendance/:attendanceId/photo", photoHandler.GetAttendancePhoto)    // Status foto check-in & URL bertanda tangan (berlaku singkat)
+	// Check-in yang ditampung saat mode degraded/maintenance tetapi gagal dicatat saat replay (dead letter)
+	admin.Get("/attendance/queued-check-in-failures", adminHandler.GetQueuedCheckInFailures)                        // Daftar kegagalan (default status=open)
+	admin.Post("/attendance/queued-check-in-failures/:failureId/resolve", adminHandler.ResolveQueuedCheckInFailure) // Tandai sudah ditangani (wajib catatan)
+	// Dokumen pendukung (surat sakit, izin) yang dilampirkan karyawan, untuk ditinjau saat koreksi
+	admin.Get("/attendance/:attendanceId/documents", documentHandler.GetAttendanceDocuments) // Daftar dokumen satu record absensi
+	adminDownloads.Get("/documents/:documentId/download", documentHandler.DownloadDocument)  // Mengunduh dokumen
+	admin.Delete("/documents/:documentId", documentHandler.DeleteDocument)                   // Menghapus dokumen (metadata & file)
+
+	// --- Project / Cost Center (Tag Sesi Absensi) ---
+	admin.Post("/projects", projectHandler.CreateProject)              // Membuat project (kode unik)
+	admin.Get("/projects", projectHandler.GetAllProjects)              // Mendapatkan semua project (termasuk nonaktif)
+	admin.Get("/projects/:projectId", projectHandler.GetProjectByID)   // Mendapatkan detail project
+	admin.Put("/projects/:projectId", projectHandler.UpdateProject)    // Memperbarui project (termasuk aktif/nonaktif)
+	admin.Delete("/projects/:projectId", projectHandler.DeleteProject) // Menghapus project yang belum dipakai absensi
+
+	// --- Periode Payroll (Kunci Edit Absensi) ---
+	// Setelah periode ditutup, check-in/check-out/koreksi absensi di dalamnya ditolak 409 (PAYROLL_PERIOD_CLOSED)
+	admin.Post("/payroll/periods", payrollHandler.CreatePayrollPeriod)                  // Membuat periode payroll (status open, tidak boleh tumpang tindih)
+	admin.Get("/payroll/periods", payrollHandler.GetAllPayrollPeriods)                  // Mendapatkan semua periode payroll
+	admin.Get("/payroll/periods/:periodId", payrollHandler.GetPayrollPeriodByID)        // Mendapatkan detail periode payroll
+	admin.Post("/payroll/periods/:periodId/close", payrollHandler.ClosePayrollPeriod)   // Menutup periode (absensi di dalamnya terkunci)
+	admin.Post("/payroll/periods/:periodId/reopen", payrollHandler.ReopenPayrollPeriod) // Membuka kembali periode (wajib alasan & konfirmasi password)
 
 	// --- Manajemen Pengguna (oleh Admin) ---
-	admin.Get("/users", adminHandler.GetAllUsers)           // Mendapatkan daftar semua user (dengan pagination)
-	admin.Get("/users/:userId", adminHandler.GetUserByID)   // Mendapatkan detail user berdasarkan ID
-	admin.Put("/users/:userId", adminHandler.UpdateUser)    // Memperbarui data user (username, email, nama, role)
-	admin.Delete("/users/:userId", adminHandler.DeleteUser) // Menghapus user
+	// Cari user dari username saat ini atau username lama (rujukan di ekspor lama); harus sebelum /users/:userId
+	admin.Get("/users/resolve", usernameChangeHandler.ResolveUsername)
+	admin.Get("/users", adminHandler.GetAllUsers)                 // Mendapatkan daftar semua user (dengan pagination)
+	admin.Post("/users", invitationHandler.CreateUser)            // Membuat akun langsung (role apa pun, email pengaturan password opsional)
+	adminDownloads.Get("/users/export", adminHandler.ExportUsers) // Ekspor daftar user terfilter (CSV/XLSX); harus sebelum /users/:userId
+	admin.Get("/users/:userId", adminHandler.GetUserByID)         // Mendapatkan detail user berdasarkan ID
+	admin.Put("/users/:userId", adminHandler.UpdateUser)          // Memperbarui data user (username, email, nama, role)
+	admin.Patch("/users/:userId", adminHandler.PatchUser)         // Memperbarui sebagian field user (misal hanya role_id)
+	admin.Delete("/users/:userId", adminHandler.DeleteUser)       // Menghapus user
+	// Ganti role banyak user sekaligus (transaksional, hasil per user)
+	admin.Post("/users/bulk/role", adminHandler.BulkAssignRole)
 
 	// --- Endpoint Tambahan Terkait User Spesifik (oleh Admin) ---
-	// Melihat jadwal spesifik untuk user tertentu
+	// Melihat jadwal spesifik untuk user tertentu (?include=attendance menyertakan absensi & status present/missing)
 	admin.Get("/users/:userId/schedules", adminHandler.GetUserSchedules)
 	// Melihat rekap absensi spesifik untuk user tertentu
 	admin.Get("/users/:userId/attendance", adminHandler.GetUserAttendance)
+	// Pseudonimkan data pribadi user (GDPR erasure, irreversible); riwayat absensi tetap untuk payroll
+	admin.Post("/users/:userId/anonymize", adminHandler.AnonymizeUser)
+	// Tipe user (employee/contractor) & masa akses; contractor dinonaktifkan otomatis setelah masa akses
+	admin.Put("/users/:userId/access", adminHandler.UpdateUserAccess)
+	// Keluarkan user dari semua perangkat (akun disusupi): naikkan token_version, catat di audit log
+	admin.Post("/users/:userId/revoke-sessions", adminHandler.RevokeUserSessions)
+	// Data kepegawaian (hire date, status, akhir probation); HR dinotifikasi menjelang akhir probation
+	admin.Put("/users/:userId/employment", adminHandler.UpdateUserEmployment)
+	// Kebijakan mode kerja: hari boleh remote & izin tugas lapangan (onsite selalu boleh)
+	admin.Put("/users/:userId/work-modes", adminHandler.UpdateUserWorkModes)
+	// Garis pelaporan (atasan langsung) & org-chart dengan status kehadiran hari ini
+	admin.Put("/users/:userId/manager", orgHandler.SetManager)
+	admin.Get("/org-chart", orgHandler.GetOrgChart)
+	// Feed aktivitas user (absensi, jadwal, profil, login) dari audit log & ledger absensi
+	admin.Get("/users/:userId/activity", adminHandler.GetUserActivity)
+	// Tautan publik verifikasi kepegawaian untuk pihak ketiga (bank, pemilik sewa), setiap akses diaudit
+	admin.Post("/users/:userId/verification-link", verificationHandler.CreateVerificationLink)         // Membuat tautan bertanda tangan & berbatas waktu
+	admin.Get("/users/:userId/verification-links", verificationHandler.GetUserVerificationLinks)       // Daftar tautan user beserta jumlah akses
+	admin.Get("/verification-links/:linkId/accesses", verificationHandler.GetVerificationLinkAccesses) // Riwayat akses tautan (termasuk yang ditolak)
+	admin.Delete("/verification-links/:linkId", verificationHandler.RevokeVerificationLink)            // Mencabut tautan sebelum kedaluwarsa
+	// Perangkat kiosk absensi milik user; cabut tablet yang hilang/dicuri agar token-nya langsung ditolak
+	admin.Get("/users/:userId/kiosks", kioskHandler.GetUserKiosks) // Daftar perangkat kiosk user
+	admin.Delete("/kiosks/:kioskId", kioskHandler.RevokeKiosk)     // Mencabut perangkat kiosk
+	// Kebijakan ganti username (profile.username_change_policy): persetujuan & riwayat username lama
+	admin.Get("/users/:userId/previous-usernames", usernameChangeHandler.GetPreviousUsernames)       // Riwayat username lama user
+	admin.Get("/username-requests", usernameChangeHandler.GetUsernameChangeRequests)                 // Antrian permintaan ganti username
+	admin.Post("/username-requests/:requestId/approve", usernameChangeHandler.ApproveUsernameChange) // Setujui & terapkan username baru
+	admin.Post("/username-requests/:requestId/reject", usernameChangeHandler.RejectUsernameChange)   // Tolak permintaan
+	// Undangan onboarding: email dengan role yang sudah ditentukan (wajib saat auth.registration_mode = invite_only)
+	admin.Post("/invitations", invitationHandler.CreateInvitation)                      // Undang email & kirim tautan registrasi
+	admin.Get("/invitations", invitationHandler.GetInvitations)                         // Daftar undangan & statusnya
+	admin.Post("/invitations/:invitationId/resend", invitationHandler.ResendInvitation) // Kirim ulang & perpanjang undangan
+	admin.Delete("/invitations/:invitationId", invitationHandler.RevokeInvitation)      // Cabut undangan yang belum diterima
 
 	// --- Manajemen Role (oleh Admin) ---
 	admin.Post("/roles", adminHandler.CreateRole)           // Membuat role baru
@@ -63,45 +211,266 @@ func SetupRoutes(app *fiber.App, authHandler *handlers.AuthHandler, adminHandler
 	admin.Get("/roles/:roleId", adminHandler.GetRoleByID)   // Mendapatkan detail role berdasarkan ID
 	admin.Put("/roles/:roleId", adminHandler.UpdateRole)    // Memperbarui role
 	admin.Delete("/roles/:roleId", adminHandler.DeleteRole) // Menghapus role
+	// Hierarki role: role mewarisi akses parent-nya secara berantai (siklus ditolak)
+	admin.Put("/roles/:roleId/parent", adminHandler.SetRoleParent)       // Atur/lepas role yang diwarisi
+	admin.Get("/roles/:roleId/effective", adminHandler.GetEffectiveRole) // Role efektif (rantai pewarisan)
+
+	// --- Biaya Tenaga Kerja (tarif per jam & estimasi terjadwal vs aktual) ---
+	admin.Put("/users/:userId/hourly-rate", laborHandler.SetUserHourlyRate) // Tarif per jam user (null = ikut tarif role)
+	admin.Put("/roles/:roleId/hourly-rate", laborHandler.SetRoleHourlyRate) // Tarif per jam default role
+	adminReports.Get("/analytics/labor-cost", laborHandler.GetLaborCost)    // Jam & biaya per hari/role/tim, opsional dibandingkan anggaran
+	adminReports.Get("/analytics/occupancy", adminHandler.GetOccupancy)     // Heatmap jumlah user check-in per jam/hari & lokasi (onsite/remote/field)
+
+	// --- Ekspor Laporan di Background (file disimpan sampai masa retensi habis) ---
+	admin.Post("/reports/exports", reportHandler.CreateReportExport)                              // Antrekan ekspor laporan (CSV/XLSX)
+	admin.Get("/reports/exports", reportHandler.GetReportExports)                                 // Daftar ekspor beserta status & kedaluwarsa
+	admin.Get("/reports/exports/:exportId", reportHandler.GetReportExport)                        // Status satu ekspor
+	adminDownloads.Get("/reports/exports/:exportId/download", reportHandler.DownloadReportExport) // Unduh file (410 setelah kedaluwarsa)
+	adminReports.Post("/reports/snapshots", reportHandler.CreateReportSnapshot)                   // Bekukan ringkasan absensi satu periode untuk payroll
+	admin.Get("/reports/snapshots", reportHandler.GetReportSnapshots)                             // Daftar snapshot (tanpa baris)
+	admin.Get("/reports/snapshots/:snapshotId", reportHandler.GetReportSnapshot)                  // Detail snapshot + verifikasi checksum
+
+	// --- Monitoring ---
+	admin.Get("/metrics", adminHandler.GetMetrics)          // Counter aplikasi (query DB, query lambat, dll.)
+	admin.Get("/jobs", jobHandler.GetJobs)                  // Job latar belakang: jadwal & status run terakhir
+	admin.Post("/jobs/:name/run", jobHandler.TriggerJob)    // Jalankan job sekarang (di background)
+	admin.Get("/permissions/routes", routePermissions(reg)) // Peta permission setiap route v1
+	// Self-check dependensi & konfigurasi efektif (rahasia disamarkan); tidak menerima token baca
+	admin.withoutReadScope().Get("/system/diagnostics", systemHandler.GetDiagnostics)
+	// Rekaman request/response (redacted) untuk route/user di debug.capture_routes / debug.capture_users.
+	// Route ini sendiri tidak pernah direkam agar isi rekaman tidak tersalin ulang, dan tidak
+	// menerima token baca (ScopeRead).
+	debug := admin.withoutCapture().withoutReadScope()
+	debug.Get("/debug/captures", debugCaptureHandler.GetDebugCaptures)           // Daftar rekaman (tanpa body), filter user/route/method
+	debug.Get("/debug/captures/:captureId", debugCaptureHandler.GetDebugCapture) // Detail rekaman: header & body request/response
+
+	// --- Maintenance: backup database (dipulihkan dengan cmd/restore) ---
+	admin.Post("/maintenance/backup", backupHandler.CreateBackup)                               // Antrekan backup logis seluruh database ke storage
+	admin.Get("/maintenance/backups", backupHandler.GetBackups)                                 // Daftar backup beserta status
+	admin.Get("/maintenance/backups/:backupId", backupHandler.GetBackup)                        // Status satu backup
+	adminDownloads.Get("/maintenance/backups/:backupId/download", backupHandler.DownloadBackup) // Unduh arsip tar.gz
+	// Mode maintenance: route non-admin dijawab 503 + Retry-After (mis. untuk migrasi di jam kerja)
+	admin.Get("/maintenance", maintenanceHandler.GetMaintenance)              // Status maintenance & check-in yang ditampung
+	admin.Post("/maintenance/enable", maintenanceHandler.EnableMaintenance)   // Aktifkan maintenance (pesan, Retry-After, buffer check-in)
+	admin.Post("/maintenance/disable", maintenanceHandler.DisableMaintenance) // Nonaktifkan maintenance; check-in yang ditampung dicatat
+	admin.Get("/maintenance/app-versions", maintenanceHandler.GetAppVersions) // Versi aplikasi yang sedang berjalan (pengaman migrasi cmd/migrate)
+
+	// --- Outbox Efek Samping (notifikasi, webhook, event stream) ---
+	admin.Get("/outbox/dead-letters", outboxHandler.GetDeadLetters)       // Dead letter: pesan yang gagal sampai batas percobaan
+	admin.Post("/outbox/:messageId/retry", outboxHandler.RetryDeadLetter) // Kembalikan dead letter ke antrean
+
+	// --- Pengumuman (Broadcast ke Karyawan) ---
+	admin.Post("/announcements", announcementHandler.CreateAnnouncement)                   // Membuat pengumuman (jendela publikasi & audience role)
+	admin.Get("/announcements", announcementHandler.GetAllAnnouncements)                   // Mendapatkan semua pengumuman (termasuk terjadwal/kedaluwarsa)
+	admin.Get("/announcements/:announcementId", announcementHandler.GetAnnouncementByID)   // Mendapatkan detail pengumuman
+	admin.Put("/announcements/:announcementId", announcementHandler.UpdateAnnouncement)    // Memperbarui pengumuman
+	admin.Delete("/announcements/:announcementId", announcementHandler.DeleteAnnouncement) // Menghapus pengumuman
+
+	// --- Pengaturan Sistem (runtime, tanpa restart) ---
+	admin.Get("/settings", adminHandler.GetSettings)        // Mendapatkan semua pengaturan beserta nilai efektif & default
+	admin.Put("/settings", adminHandler.UpdateSettings)     // Mengubah satu/lebih pengaturan (atomik, divalidasi per key)
+	admin.Get("/calendar", adminHandler.GetWorkingCalendar) // Kalender kerja (hari kerja/setengah hari/libur) pada rentang tanggal
 
 	// =========================================================================
-	// Rute Pengguna (Memerlukan Login - Role 'Employee' atau 'Admin')
+	// Rute Pengguna (Memerlukan Login - Semua Role)
 	// =========================================================================
-	// Grup untuk endpoint yang bisa diakses oleh pengguna yang sudah login (/api/v1/user)
-	// .Authorize("Employee", "Admin") mengizinkan kedua role mengakses endpoint ini.
-	// Jika hanya Employee: middleware.Authorize("Employee")
-	user := api.Group("/user", middleware.Protected()) // Dihapus Authorize agar Admin juga bisa tes/akses jika perlu
+	// Grup untuk endpoint yang bisa diakses oleh pengguna yang sudah login (/api/v1/user).
+	// permAuthenticated sengaja tidak membatasi role: setiap handler hanya bekerja pada data
+	// user yang login, sehingga Admin dan role kustom juga bisa absen. Untuk membatasi role,
+	// daftarkan route di grup dengan requireRoles(...).
+	user := reg.group("/user", permAuthenticated, userLimiter)
 
 	// --- Kehadiran (Absensi) ---
-	user.Post("/attendance/checkin", userHandler.CheckIn)   // Melakukan check-in
-	user.Post("/attendance/checkout", userHandler.CheckOut) // Melakukan check-out
-	user.Get("/attendance/my", userHandler.GetMyAttendance) // Melihat riwayat kehadiran diri sendiri (bisa difilter tanggal)
+	// Juga menerima token kiosk (scope attendance:punch) yang tidak bisa mengakses endpoint lain
+	punch := user.withScopes(models.ScopeAttendancePunch)
+	// Check-in bisa ditampung selama maintenance (maintenance.checkins = buffer)
+	punch.bufferedDuringMaintenance().Post("/attendance/checkin", userHandler.CheckIn) // Melakukan check-in
+	punch.Post("/attendance/checkout", userHandler.CheckOut)                           // Melakukan check-out
+	punch.Post("/attendance/switch", userHandler.SwitchProject)                        // Pindah project di tengah sesi tanpa check-out (segmen baru)
+	punch.Get("/projects", projectHandler.GetActiveProjects)                           // Project aktif yang bisa dipilih (project_id) saat check-in
+	user.Get("/attendance/my", userHandler.GetMyAttendance)                            // Melihat riwayat kehadiran diri sendiri (bisa difilter tanggal)
+	// Dispute atas record absensi sendiri; atasan langsung dinotifikasi
+	user.Post("/attendance/:attendanceId/dispute", disputeHandler.CreateDispute) // Menyanggah record absensi sendiri (wajib komentar)
+	user.Get("/attendance/disputes", disputeHandler.GetMyDisputes)               // Status dispute milik sendiri
+	// Rute sesi field: ping lokasi berkala selama sesi terbuka (mode field), disimpan route.retention_days
+	user.Post("/attendance/:attendanceId/ping", routeHandler.RecordPing) // Kirim lokasi saat ini (jeda minimum route.ping_interval_seconds)
+	user.Get("/attendance/:attendanceId/route", routeHandler.GetMyRoute) // Ringkasan rute sesi sendiri (presisi penuh)
+
+	// --- Dokumen Pendukung (Surat Sakit, Izin) ---
+	// Ukuran & tipe file (PDF/JPEG/PNG hasil sniffing) divalidasi sebelum handler; DOCUMENT_MAX_BYTES
+	// harus lebih kecil dari batas global MAX_BODY_SIZE_BYTES.
+	documentMaxBytes := configs.GetEnvInt("DOCUMENT_MAX_BYTES", 3*1024*1024)
+	user.Post("/attendance/:attendanceId/documents",
+		middleware.BodyLimit(documentMaxBytes+64*1024), // Ruang untuk overhead multipart
+		middleware.ValidateUpload(middleware.UploadRule{
+			Field: handlers.DocumentUploadField, Required: true, MaxBytes: int64(documentMaxBytes), AllowedMIMEs: middleware.DocumentMIMETypes,
+		}),
+		documentHandler.UploadAttendanceDocument) // Melampirkan dokumen ke record absensi sendiri
+	// Unggahan langsung ke object storage (S3) untuk file besar: minta URL presigned, unggah, lalu selesaikan
+	user.Post("/attendance/:attendanceId/documents/upload-url", documentHandler.CreateAttendanceDocumentUpload) // URL unggah presigned (501 untuk storage local)
+	user.Post("/attendance/:attendanceId/documents/complete", documentHandler.CompleteAttendanceDocumentUpload) // Catat dokumen setelah file terunggah
+	user.Get("/attendance/:attendanceId/documents", documentHandler.GetMyAttendanceDocuments)                   // Daftar dokumen record absensi sendiri
+	// Mengunduh dokumen milik sendiri (tidak menerima token baca)
+	user.withoutReadScope().Get("/documents/:documentId/download", documentHandler.DownloadMyDocument)
+	// Foto profil = foto acuan verifikasi wajah saat check-in (foto baru menggantikan yang lama)
+	user.Put("/profile/photo",
+		middleware.BodyLimit(documentMaxBytes+64*1024),
+		middleware.ValidateUpload(middleware.UploadRule{
+			Field: handlers.DocumentUploadField, Required: true, MaxBytes: int64(documentMaxBytes), AllowedMIMEs: middleware.ImageMIMETypes,
+		}),
+		documentHandler.UploadProfilePhoto) // Mengunggah/mengganti foto profil sendiri
 
 	// --- Jadwal Pribadi ---
 	user.Get("/schedules/my", userHandler.GetMySchedules) // Melihat jadwal shift diri sendiri (bisa difilter tanggal)
 
+	// --- Tim (Bawahan Langsung & Tidak Langsung) ---
+	user.Get("/team", orgHandler.GetMyTeam) // Pohon tim di bawah user beserta status kehadiran hari ini
+
+	// --- Pengumuman ---
+	user.Get("/announcements", announcementHandler.GetMyAnnouncements) // Pengumuman yang sedang tayang untuk role user
+
 	// --- Manajemen Profil Pribadi ---
 	user.Get("/profile", userHandler.GetMyProfile)      // Mendapatkan profil sendiri
 	user.Put("/profile", userHandler.UpdateMyProfile)   // Memperbarui data profil diri sendiri (nama, email, username)
 	user.Put("/password", userHandler.UpdateMyPassword) // Mengubah password diri sendiri
+	// Mengunduh arsip data pribadi (profil, jadwal, absensi) dalam JSON; tidak menerima token baca
+	user.withoutReadScope().Get("/data-export", userHandler.ExportMyData)
+	// Ganti email: berlaku setelah dikonfirmasi dari alamat baru, alamat lama diberi tahu
+	user.Post("/email", emailChangeHandler.RequestEmailChange)           // Minta ganti email (mengirim tautan konfirmasi)
+	user.Get("/email/pending", emailChangeHandler.GetPendingEmailChange) // Permintaan ganti email yang belum dikonfirmasi
+	user.Post("/email/resend", emailChangeHandler.ResendEmailChange)     // Kirim ulang tautan konfirmasi
+	user.Delete("/email/pending", emailChangeHandler.CancelEmailChange)  // Batalkan permintaan ganti email
+	// Username baru yang menunggu persetujuan admin (profile.username_change_policy = approval)
+	user.Get("/username/pending", usernameChangeHandler.GetMyPendingUsernameChange)
+	// Verifikasi nomor HP dengan kode OTP SMS & preferensi SMS (404 jika SMS_PROVIDER tidak di-set)
+	user.Post("/phone/verification", phoneHandler.SendPhoneVerification)            // Kirim kode verifikasi ke nomor HP di profil
+	user.Post("/phone/verification/confirm", phoneHandler.ConfirmPhoneVerification) // Konfirmasi nomor HP dengan kode dari SMS
+	user.Put("/sms-preferences", phoneHandler.UpdateSMSPreferences)                 // Aktifkan/matikan 2FA login & pengingat shift lewat SMS
+
+	// --- Token Terbatas (Kiosk, Integrasi Laporan) ---
+	// Hanya bisa dibuat dengan token sesi penuh; dicabut bersama semua sesi user
+	user.Post("/tokens", authHandler.IssueScopedToken) // Menerbitkan token dengan scope terbatas (attendance:punch, read)
+	// Token kiosk: singkat, terikat fingerprint perangkat (X-Device-Fingerprint), dirotasi lewat X-Rotated-Token
+	user.Post("/kiosks", kioskHandler.EnrollKiosk)                    // Mendaftarkan perangkat kiosk & token pertamanya
+	user.Get("/kiosks", kioskHandler.GetMyKiosks)                     // Daftar perangkat kiosk milik sendiri
+	user.Post("/kiosks/:kioskId/token", kioskHandler.IssueKioskToken) // Token baru untuk kiosk terdaftar (mis. setelah lama offline)
+	user.Delete("/kiosks/:kioskId", kioskHandler.RevokeMyKiosk)       // Mencabut perangkat kiosk sendiri
+
+	// --- Inbox Notifikasi ---
+	user.Get("/notifications", notificationHandler.GetMyNotifications)                         // Inbox notifikasi in-app (?unread=true untuk yang belum dibaca)
+	user.Get("/notifications/unread-count", notificationHandler.GetUnreadCount)                // Jumlah belum dibaca (badge)
+	user.Post("/notifications/read-all", notificationHandler.MarkAllNotificationsRead)         // Tandai semua dibaca
+	user.Post("/notifications/:notificationId/read", notificationHandler.MarkNotificationRead) // Tandai satu notifikasi dibaca
+
+	// --- Delegasi Approval (mis. selama cuti) ---
+	// Pemegang delegasi aktif memakai on_behalf_of pada endpoint /manager; tercatat di audit log
+	user.Post("/delegations", delegationHandler.CreateDelegation)                 // Limpahkan wewenang approval untuk rentang tanggal
+	user.Get("/delegations", delegationHandler.GetMyDelegations)                  // Delegasi yang diberikan & diterima
+	user.Delete("/delegations/:delegationId", delegationHandler.RevokeDelegation) // Cabut delegasi lebih awal
+
+	// --- Perangkat Push Notification ---
+	user.Post("/devices", deviceHandler.RegisterDevice)            // Mendaftarkan token FCM/APNs perangkat
+	user.Delete("/devices/:token", deviceHandler.UnregisterDevice) // Melepas token perangkat (mis. saat logout)
+
+	// =========================================================================
+	// Rute Atasan (Memerlukan Login - User yang Memiliki Bawahan)
+	// =========================================================================
+	// Grup untuk endpoint atasan (/api/v1/manager). Tidak dibatasi role: handler hanya bekerja
+	// pada bawahan langsung & tidak langsung user yang login (users.manager_id), atau atasan
+	// yang mendelegasikan wewenangnya ke user tersebut (on_behalf_of).
+	manager := reg.group("/manager", permAuthenticated, userLimiter)
+
+	// --- Sign-off Harian Absensi Tim ---
+	// Absensi yang sudah di-sign-off terkunci (409 ATTENDANCE_SIGNED_OFF), pengecualian ditandai per karyawan
+	manager.Post("/attendance/sign-off", signOffHandler.SignOffDay)       // Sign-off absensi tim untuk satu tanggal
+	manager.Get("/attendance/unsigned", signOffHandler.GetMyUnsignedDays) // Tanggal kerja tim yang belum di-sign-off
+
+	// --- Rute Sesi Field Tim ---
+	// Koordinat dibulatkan (route.manager_precision) dan setiap akses dicatat di audit log karyawan
+	manager.Get("/attendance/:attendanceId/route", routeHandler.GetReportRoute) // Ringkasan rute sesi field bawahan
+
+	// =========================================================================
+	// Rute Sinkronisasi Delta (Hanya Admin)
+	// =========================================================================
+	// Grup untuk sistem eksternal (/api/v1/sync): perubahan setelah watermark updated_since
+	// beserta tombstone record yang dihapus. Integrasi cukup memakai token terbatas scope read.
+	sync := reg.group("/sync", requireRoles("Admin"), adminLimiter)
+	sync.Get("/users", syncHandler.GetUserChanges)            // User berubah/dihapus sejak watermark
+	sync.Get("/attendance", syncHandler.GetAttendanceChanges) // Absensi berubah/dihapus sejak watermark
+	sync.Get("/schedules", syncHandler.GetScheduleChanges)    // Jadwal berubah/dihapus sejak watermark
 
 	// =========================================================================
 	// Rute Lain-lain (Publik)
 	// =========================================================================
-	api.Get("/health", HealthCheck)
+	public := reg.group("", permPublic, publicLimiter)
+	// UP/DEGRADED/MAINTENANCE (mode degraded saat database tidak tersedia); tetap dilayani selama maintenance
+	public.duringMaintenance().Get("/health", HealthCheck(degradedMode, maintenanceGate))
+	// Versi & info build (commit, waktu build) untuk mengaitkan deployment dengan perubahan perilaku
+	public.duringMaintenance().Get("/version", Version)
 
 	// Endpoint untuk melihat semua shift
-	api.Get("/shifts", userHandler.GetAllShifts)
+	public.Get("/shifts", userHandler.GetAllShifts)
+
+	// Spesifikasi OpenAPI dari anotasi handler, dilengkapi route terdaftar (lihat openapi.Spec.Complete)
+	if apiSpec != nil {
+		public.Get("/openapi.json", apiSpec.Handler())
+	}
+
+	// Verifikasi kepegawaian oleh pihak ketiga lewat tautan dari admin (data terbatas, akses diaudit)
+	verify := reg.group("/verify", permPublic, verifyLimiter)
+	verify.Get("/:token", verificationHandler.VerifyEmployment)
+
+	// Foto check-in lewat URL bertanda tangan dari /admin/attendance/:attendanceId/photo (tanpa login)
+	media := reg.group("/media", permPublic, publicLimiter)
+	media.Get("/attendance-photos/:token", photoHandler.ServeAttendancePhoto)
+
+	// Gagal saat startup jika ada route v1 yang didaftarkan di luar reg.group (tanpa permission).
+	if err := reg.verify(app); err != nil {
+		panic(err)
+	}
+	if apiSpec != nil {
+		apiSpec.Complete(reg.prefix, reg.routes)
+	}
+}
+
+// Version godoc
+// @Summary Get version and build info
+// @Description Public endpoint returning the app version (APP_VERSION or the version set at build time), git commit, build time, and Go version of the instance that answers, so deployments can be correlated with behavior changes. Commit and build time come from -ldflags (see `make build`) or the VCS info embedded by `go build`; they are empty for `go run`. Also answered during maintenance mode.
+// @Tags Public
+// @ID get-version
+// @Produce json
+// @Success 200 {object} models.Response{data=models.BuildInfo} "Version retrieved successfully"
+// @Router /version [get]
+func Version(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Success: true, Message: "Version retrieved successfully", Data: buildinfo.Get(),
+	})
 }
 
 // HealthCheck godoc
 // @Summary Check Health
-// @Description Public endpoint to verify that the API is running and responsive.
+// @Description Public endpoint to verify that the API is running and responsive. Status is DEGRADED while the database is unavailable: shifts and roles are then served from cache and check-ins are queued until it recovers (see the degraded object). Status is MAINTENANCE while maintenance mode is on (see the maintenance object); the endpoint keeps answering 200 so load balancers keep the instance for admin routes.
 // @Tags Public
 // @ID health-check
 // @Produce json
-// @Success 200 {object} map[string]string `json:"status"`
+// @Success 200 {object} map[string]interface{} "status UP, DEGRADED or MAINTENANCE, plus degraded mode details when enabled"
 // @Router /health [get]
-func HealthCheck(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "UP"})
+func HealthCheck(degradedMode *degraded.Controller, maintenanceGate middleware.MaintenanceGate) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		body := fiber.Map{"status": "UP"}
+		if degradedMode != nil {
+			status := degradedMode.Status()
+			if status.Degraded {
+				body["status"] = "DEGRADED"
+			}
+			body["degraded"] = status
+		}
+		if maintenanceGate != nil {
+			if status := maintenanceGate.Status(c.UserContext()); status.Enabled {
+				body["status"] = "MAINTENANCE"
+				body["maintenance"] = status
+			}
+		}
+		return c.Status(fiber.StatusOK).JSON(body)
+	}
 }
@@ -0,0 +1,340 @@
+// internal/backup/backup.go
+
+// Package backup membuat backup logis database di background dan memulihkannya (dipakai
+// cmd/restore). Backup adalah arsip tar.gz berisi manifest.json diikuti isi tiap tabel dalam
+// format teks COPY, diambil dari satu transaksi REPEATABLE READ sehingga konsisten tanpa
+// menghentikan API. Backup hanya bisa dipulihkan ke database dengan versi migrasi yang sama.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/storage"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// FormatVersion adalah versi format arsip; Restore menolak versi lain.
+const FormatVersion = 1
+
+// ManifestName adalah nama entri manifest, selalu entri pertama arsip.
+const ManifestName = "manifest.json"
+
+// ContentType adalah content type file backup di storage.
+const ContentType = "application/gzip"
+
+// excludedTables tidak ikut di-backup: versi migrasi dicatat di manifest, status backup
+// milik database tujuan tidak ditimpa saat restore, dan heartbeat instance hanya berlaku
+// untuk deployment yang sedang berjalan.
+var excludedTables = []string{"schema_migrations", "backups", "app_instances"}
+
+// ErrUnavailable dikembalikan jika Service tidak punya koneksi PostgreSQL (SANDBOX_MODE).
+var ErrUnavailable = errors.New("database backups require PostgreSQL")
+
+// Manifest menjelaskan isi arsip backup.
+type Manifest struct {
+	FormatVersion int         `json:"format_version"`
+	CreatedAt     time.Time   `json:"created_at"`
+	SchemaVersion int64       `json:"schema_version"`
+	Tables        []TableDump `json:"tables"`
+}
+
+// TableDump adalah satu tabel di arsip: isi COPY (kolom sesuai Columns) ada di entri File.
+type TableDump struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Rows    int64    `json:"rows"`
+	File    string   `json:"file"`
+}
+
+// Service menjalankan backup yang diminta admin dan menyimpannya di storage.
+type Service struct {
+	db      *pgxpool.Pool
+	repo    repository.BackupRepository
+	store   storage.Storage
+	timeout time.Duration // Batas waktu satu backup; backup running lebih lama dianggap terhenti
+}
+
+// NewService membuat Service dengan pengaturan eksplisit. db nil (SANDBOX_MODE) membuat
+// Available false.
+func NewService(db *pgxpool.Pool, repo repository.BackupRepository, store storage.Storage, timeout time.Duration) *Service {
+	return &Service{db: db, repo: repo, store: store, timeout: timeout}
+}
+
+// NewServiceFromEnv membuat Service berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - BACKUP_TIMEOUT: Batas waktu satu backup; backup running yang melewatinya ditandai failed. Default: 1h.
+func NewServiceFromEnv(db *pgxpool.Pool, repo repository.BackupRepository, store storage.Storage) (*Service, error) {
+	timeout := configs.GetEnvDuration("BACKUP_TIMEOUT", time.Hour)
+	if timeout <= 0 {
+		return nil, fmt.Errorf("BACKUP_TIMEOUT must be positive")
+	}
+	return NewService(db, repo, store, timeout), nil
+}
+
+// Available melaporkan apakah backup bisa dibuat (butuh PostgreSQL).
+func (s *Service) Available() bool {
+	return s.db != nil
+}
+
+// Run menandai failed backup yang terhenti, lalu membuat satu backup pending (jika ada).
+// Mengembalikan jumlah backup yang selesai (berhasil maupun gagal). Error storage atau database
+// saat mencatat hasil menghentikan run agar dicoba lagi nanti.
+func (s *Service) Run(ctx context.Context) (int, error) {
+	stale, err := s.repo.FailStaleBackups(ctx, time.Now().Add(-s.timeout), "backup was interrupted or exceeded BACKUP_TIMEOUT")
+	if err != nil {
+		return 0, err
+	}
+	if stale > 0 {
+		zlog.Warn().Int("backups", stale).Msg("Marked stale running backups as failed")
+	}
+	backup, err := s.repo.ClaimPendingBackup(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return stale, nil
+	}
+	if err != nil {
+		return stale, err
+	}
+	if !s.Available() {
+		return stale + 1, s.repo.MarkBackupFailed(ctx, backup.ID, ErrUnavailable.Error())
+	}
+	if err := s.runOne(ctx, backup); err != nil {
+		return stale, err
+	}
+	return stale + 1, nil
+}
+
+func (s *Service) runOne(ctx context.Context, backup *models.Backup) error {
+	// Arsip ditulis ke disk dulu agar database besar tidak ditampung di memori.
+	tmp, err := os.CreateTemp("", "backup-*.tar.gz")
+	if err != nil {
+		return fmt.Errorf("error creating temp file for backup %d: %w", backup.ID, err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	dumpCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	manifest, err := Dump(dumpCtx, s.db, tmp)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		zlog.Error().Err(err).Int("backup_id", backup.ID).Msg("Backup failed")
+		return s.repo.MarkBackupFailed(ctx, backup.ID, err.Error())
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("error sizing backup %d: %w", backup.ID, err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error rewinding backup %d: %w", backup.ID, err)
+	}
+
+	key, err := newBackupKey(manifest.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Put(ctx, key, tmp, ContentType); err != nil {
+		zlog.Error().Err(err).Int("backup_id", backup.ID).Str("backend", s.store.Backend()).Msg("Failed to store backup")
+		return s.repo.MarkBackupFailed(ctx, backup.ID, fmt.Sprintf("error storing backup: %v", err))
+	}
+	var rows int64
+	for _, t := range manifest.Tables {
+		rows += t.Rows
+	}
+	result := models.BackupResult{
+		StorageKey:    key,
+		FileName:      fmt.Sprintf("backup-%s.tar.gz", manifest.CreatedAt.UTC().Format("20060102-150405")),
+		SizeBytes:     size,
+		SchemaVersion: manifest.SchemaVersion,
+		TableCount:    len(manifest.Tables),
+		RowCount:      rows,
+	}
+	if err := s.repo.MarkBackupCompleted(ctx, backup.ID, result); err != nil {
+		if delErr := s.store.Delete(ctx, key); delErr != nil && !errors.Is(delErr, storage.ErrNotFound) {
+			zlog.Warn().Err(delErr).Str("key", key).Msg("Failed to delete orphaned backup file")
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Sudah ditandai failed (mis. dianggap terhenti)
+		}
+		return err
+	}
+	zlog.Info().Int("backup_id", backup.ID).Int("tables", result.TableCount).Int64("rows", rows).Int64("size_bytes", size).Msg("Backup completed")
+	return nil
+}
+
+// Dump menulis backup seluruh tabel public (kecuali excludedTables) ke w sebagai tar.gz dan
+// mengembalikan manifest-nya. Semua tabel dibaca dari satu snapshot transaksi read-only.
+func Dump(ctx context.Context, db *pgxpool.Pool, w io.Writer) (*Manifest, error) {
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("error starting backup transaction: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	manifest := &Manifest{FormatVersion: FormatVersion, CreatedAt: time.Now()}
+	if manifest.SchemaVersion, err = SchemaVersion(ctx, tx); err != nil {
+		return nil, err
+	}
+	tables, err := listTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Isi tabel ditampung di file sementara karena ukurannya harus diketahui sebelum header tar
+	// ditulis, dan manifest (berisi jumlah baris) harus menjadi entri pertama.
+	dir, err := os.MkdirTemp("", "backup-tables-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp dir for backup: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range tables {
+		table, err := dumpTable(ctx, tx, name, dir)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Tables = append(manifest.Tables, *table)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding backup manifest: %w", err)
+	}
+	if err := writeEntry(tw, ManifestName, int64(len(raw)), manifest.CreatedAt, bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	for _, table := range manifest.Tables {
+		if err := writeFileEntry(tw, table.File, filepath.Join(dir, table.File), manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("error finishing backup archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("error finishing backup archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// SchemaVersion membaca versi migrasi (golang-migrate) database. Database dengan migrasi
+// dirty tidak bisa di-backup maupun dipulihkan.
+func SchemaVersion(ctx context.Context, q pgx.Tx) (int64, error) {
+	var version int64
+	var dirty bool
+	if err := q.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty); err != nil {
+		return 0, fmt.Errorf("error reading schema version: %w", err)
+	}
+	if dirty {
+		return 0, fmt.Errorf("schema version %d is dirty; fix the failed migration first", version)
+	}
+	return version, nil
+}
+
+// listTables mengembalikan tabel biasa di schema public, urut nama.
+func listTables(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	rows, err := tx.Query(ctx, `SELECT c.relname FROM pg_class c
+                                JOIN pg_namespace n ON n.oid = c.relnamespace
+                                WHERE n.nspname = 'public' AND c.relkind = 'r' AND c.relname <> ALL($1)
+                                ORDER BY c.relname`, excludedTables)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tables for backup: %w", err)
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("error listing tables for backup: %w", err)
+	}
+	return tables, nil
+}
+
+// tableColumns mengembalikan kolom tabel yang bisa di-COPY (tanpa kolom generated), urut posisi.
+func tableColumns(ctx context.Context, tx pgx.Tx, table string) ([]string, error) {
+	rows, err := tx.Query(ctx, `SELECT attname FROM pg_attribute
+                                WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped AND attgenerated = ''
+                                ORDER BY attnum`, qualified(table))
+	if err != nil {
+		return nil, fmt.Errorf("error listing columns of %s: %w", table, err)
+	}
+	columns, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("error listing columns of %s: %w", table, err)
+	}
+	return columns, nil
+}
+
+func dumpTable(ctx context.Context, tx pgx.Tx, name, dir string) (*TableDump, error) {
+	columns, err := tableColumns(ctx, tx, name)
+	if err != nil {
+		return nil, err
+	}
+	table := &TableDump{Name: name, Columns: columns, File: "tables/" + name + ".copy"}
+	path := filepath.Join(dir, table.File)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("error creating temp dir for backup: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp file for table %s: %w", name, err)
+	}
+	defer f.Close()
+	tag, err := tx.Conn().PgConn().CopyTo(ctx, f, "COPY "+qualified(name)+" ("+columnList(columns)+") TO STDOUT")
+	if err != nil {
+		return nil, fmt.Errorf("error copying table %s: %w", name, err)
+	}
+	table.Rows = tag.RowsAffected()
+	return table, f.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, size int64, modTime time.Time, r io.Reader) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: size, ModTime: modTime}); err != nil {
+		return fmt.Errorf("error writing backup entry %s: %w", name, err)
+	}
+	if _, err := io.Copy(tw, r); err != nil {
+		return fmt.Errorf("error writing backup entry %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeFileEntry(tw *tar.Writer, name, path string, modTime time.Time) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening temp file for backup entry %s: %w", name, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("error sizing backup entry %s: %w", name, err)
+	}
+	return writeEntry(tw, name, info.Size(), modTime, f)
+}
+
+// newBackupKey membuat storage key acak di bawah backups/YYYY/MM.
+func newBackupKey(now time.Time) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("backups/%s/%s.tar.gz", now.UTC().Format("2006/01"), hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,168 @@
+// internal/backup/restore.go
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ReadManifest membaca manifest dari awal arsip backup dan mengembalikan tar reader yang
+// posisinya tepat setelah manifest.
+func ReadManifest(r io.Reader) (*Manifest, *tar.Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading backup archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	header, err := tr.Next()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading backup archive: %w", err)
+	}
+	if header.Name != ManifestName {
+		return nil, nil, fmt.Errorf("invalid backup archive: first entry is %q, expected %s", header.Name, ManifestName)
+	}
+	manifest := &Manifest{}
+	if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+		return nil, nil, fmt.Errorf("error decoding backup manifest: %w", err)
+	}
+	if manifest.FormatVersion != FormatVersion {
+		return nil, nil, fmt.Errorf("unsupported backup format version %d (expected %d)", manifest.FormatVersion, FormatVersion)
+	}
+	return manifest, tr, nil
+}
+
+// Restore mengganti isi semua tabel di manifest dengan isi arsip r dalam satu transaksi:
+// tabel dikosongkan (TRUNCATE), diisi ulang lewat COPY, lalu sequence SERIAL disetel ke nilai
+// maksimum id. Jika ada langkah yang gagal, database tidak berubah.
+//
+// Selama restore session_replication_role = replica agar foreign key dan trigger (audit,
+// token_version, dst.) tidak dijalankan ulang untuk data lama; ini membutuhkan role superuser
+// (atau pemilik database di PostgreSQL 15+ dengan hak SET pada parameter tersebut). Versi
+// migrasi database harus sama dengan versi saat backup dibuat.
+func Restore(ctx context.Context, conn *pgx.Conn, r io.Reader) (*Manifest, error) {
+	manifest, tr, err := ReadManifest(r)
+	if err != nil {
+		return nil, err
+	}
+	tables := make(map[string]TableDump, len(manifest.Tables))
+	names := make([]string, 0, len(manifest.Tables))
+	for _, t := range manifest.Tables {
+		tables[t.File] = t
+		names = append(names, qualified(t.Name))
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting restore transaction: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	version, err := SchemaVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if version != manifest.SchemaVersion {
+		return nil, fmt.Errorf("schema version mismatch: backup is at %d, database is at %d; migrate the database to %d first",
+			manifest.SchemaVersion, version, manifest.SchemaVersion)
+	}
+	if _, err := tx.Exec(ctx, `SET LOCAL session_replication_role = replica`); err != nil {
+		return nil, fmt.Errorf("error disabling triggers for restore (superuser required): %w", err)
+	}
+	if len(names) > 0 {
+		if _, err := tx.Exec(ctx, `TRUNCATE `+strings.Join(names, ", ")); err != nil {
+			return nil, fmt.Errorf("error truncating tables: %w", err)
+		}
+	}
+
+	restored := map[string]bool{}
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading backup archive: %w", err)
+		}
+		table, ok := tables[header.Name]
+		if !ok {
+			return nil, fmt.Errorf("invalid backup archive: unexpected entry %q", header.Name)
+		}
+		tag, err := tx.Conn().PgConn().CopyFrom(ctx, tr, "COPY "+qualified(table.Name)+" ("+columnList(table.Columns)+") FROM STDIN")
+		if err != nil {
+			return nil, fmt.Errorf("error restoring table %s: %w", table.Name, err)
+		}
+		if tag.RowsAffected() != table.Rows {
+			return nil, fmt.Errorf("table %s: restored %d rows, manifest lists %d", table.Name, tag.RowsAffected(), table.Rows)
+		}
+		restored[header.Name] = true
+	}
+	for file, table := range tables {
+		if !restored[file] {
+			return nil, fmt.Errorf("invalid backup archive: missing data for table %s", table.Name)
+		}
+	}
+
+	for _, table := range manifest.Tables {
+		if err := resetSequences(ctx, tx, table.Name); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing restore: %w", err)
+	}
+	return manifest, nil
+}
+
+// resetSequences menyetel sequence kolom SERIAL/IDENTITY tabel agar nextval berikutnya
+// melewati id terbesar yang dipulihkan.
+func resetSequences(ctx context.Context, tx pgx.Tx, table string) error {
+	rows, err := tx.Query(ctx, `SELECT attname, pg_get_serial_sequence($1, attname) FROM pg_attribute
+                                WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped
+                                  AND pg_get_serial_sequence($1, attname) IS NOT NULL`, qualified(table))
+	if err != nil {
+		return fmt.Errorf("error listing sequences of %s: %w", table, err)
+	}
+	type sequence struct{ column, name string }
+	var sequences []sequence
+	for rows.Next() {
+		var seq sequence
+		if err := rows.Scan(&seq.column, &seq.name); err != nil {
+			rows.Close()
+			return fmt.Errorf("error listing sequences of %s: %w", table, err)
+		}
+		sequences = append(sequences, seq)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error listing sequences of %s: %w", table, err)
+	}
+	for _, seq := range sequences {
+		query := `SELECT setval($1, COALESCE((SELECT MAX(` + pgx.Identifier{seq.column}.Sanitize() + `) FROM ` + qualified(table) + `), 0) + 1, false)`
+		if _, err := tx.Exec(ctx, query, seq.name); err != nil {
+			return fmt.Errorf("error resetting sequence %s: %w", seq.name, err)
+		}
+	}
+	return nil
+}
+
+// qualified mengembalikan nama tabel schema public yang sudah di-quote.
+func qualified(table string) string {
+	return pgx.Identifier{"public", table}.Sanitize()
+}
+
+// columnList mengembalikan daftar kolom yang sudah di-quote, dipisah koma.
+func columnList(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = pgx.Identifier{c}.Sanitize()
+	}
+	return strings.Join(quoted, ", ")
+}
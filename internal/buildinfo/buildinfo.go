@@ -0,0 +1,67 @@
+// internal/buildinfo/buildinfo.go
+
+// Package buildinfo menyimpan versi, commit git, dan waktu build aplikasi. Nilainya diisi saat
+// build lewat -ldflags (lihat target build di Makefile):
+//
+//	go build -ldflags "-X github.com/rakaarfi/attendance-system-be/internal/buildinfo.Version=v1.4.0 \
+//	  -X github.com/rakaarfi/attendance-system-be/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/rakaarfi/attendance-system-be/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/api
+//
+// Tanpa ldflags, commit dan waktu build diambil dari info VCS yang disematkan `go build`.
+// Versi muncul di GET /api/v1/version, setiap baris log (field version), /admin/metrics, dan
+// heartbeat instance (internal/instances).
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// Diisi lewat -ldflags -X.
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = "" // RFC 3339, UTC
+)
+
+// AppVersion mengembalikan versi aplikasi: APP_VERSION jika di-set, selain itu Version.
+func AppVersion() string {
+	return configs.GetEnv("APP_VERSION", Version)
+}
+
+// vcs membaca info VCS yang disematkan `go build` (kosong untuk `go run` atau build di luar repo git).
+var vcs = sync.OnceValue(func() models.BuildInfo {
+	var info models.BuildInfo
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, s := range build.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Commit = s.Value
+		case "vcs.time":
+			info.BuildTime = s.Value // Waktu commit; lebih baik daripada kosong
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+})
+
+// Get mengembalikan info build lengkap.
+func Get() models.BuildInfo {
+	info := models.BuildInfo{Version: AppVersion(), Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	fromVCS := vcs()
+	if info.Commit == "" {
+		info.Commit, info.Modified = fromVCS.Commit, fromVCS.Modified
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = fromVCS.BuildTime
+	}
+	return info
+}
@@ -0,0 +1,128 @@
+// internal/captcha/captcha.go
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Verifier adalah kontrak untuk memverifikasi token CAPTCHA yang dikirim klien.
+// Implementasi konkret memanggil endpoint "siteverify" milik provider.
+type Verifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+	Provider() string
+}
+
+// Endpoint siteverify milik masing-masing provider.
+const (
+	hCaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+	turnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+// Error sentinel yang bisa dibedakan oleh middleware (400 vs 503).
+var (
+	ErrMissingToken       = errors.New("captcha token is required")   // Klien tidak mengirim token.
+	ErrVerificationFailed = errors.New("captcha verification failed") // Token ditolak provider.
+)
+
+// siteVerifier memverifikasi token ke provider yang kompatibel dengan API siteverify
+// (hCaptcha dan Cloudflare Turnstile memakai format request/response yang sama).
+type siteVerifier struct {
+	provider  string
+	verifyURL string
+	secret    string
+	client    *http.Client
+}
+
+// siteVerifyResponse adalah subset response JSON dari endpoint siteverify.
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// NewVerifierFromEnv membuat Verifier berdasarkan environment variables.
+// Mengembalikan nil jika CAPTCHA tidak diaktifkan (CAPTCHA_PROVIDER kosong atau 'none').
+//
+// Variabel Environment yang didukung:
+//   - CAPTCHA_PROVIDER: 'hcaptcha', 'turnstile', atau 'none' (default).
+//   - CAPTCHA_SECRET: Secret key dari provider (wajib jika provider aktif).
+//   - CAPTCHA_VERIFY_TIMEOUT: Timeout request ke provider. Default: 5s.
+func NewVerifierFromEnv() (Verifier, error) {
+	provider := strings.ToLower(configs.GetEnv("CAPTCHA_PROVIDER", "none"))
+	if provider == "none" {
+		return nil, nil
+	}
+
+	secret := configs.GetEnv("CAPTCHA_SECRET", "")
+	if secret == "" {
+		return nil, fmt.Errorf("CAPTCHA_SECRET must be set when CAPTCHA_PROVIDER=%s", provider)
+	}
+
+	timeout := configs.GetEnvDuration("CAPTCHA_VERIFY_TIMEOUT", 5*time.Second)
+	v := &siteVerifier{
+		provider: provider,
+		secret:   secret,
+		client:   &http.Client{Timeout: timeout},
+	}
+
+	switch provider {
+	case "hcaptcha":
+		v.verifyURL = hCaptchaVerifyURL
+	case "turnstile":
+		v.verifyURL = turnstileVerifyURL
+	default:
+		return nil, fmt.Errorf("unsupported CAPTCHA_PROVIDER '%s'", provider)
+	}
+
+	zlog.Info().Str("provider", provider).Msg("CAPTCHA verification enabled")
+	return v, nil
+}
+
+func (v *siteVerifier) Provider() string {
+	return v.provider
+}
+
+// Verify mengirim token ke provider dan mengembalikan error jika token tidak valid.
+func (v *siteVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if token == "" {
+		return ErrMissingToken
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.secret)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("error building captcha verify request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling %s siteverify: %w", v.provider, err)
+	}
+	defer resp.Body.Close()
+
+	var result siteVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("error decoding %s siteverify response: %w", v.provider, err)
+	}
+
+	if !result.Success {
+		return fmt.Errorf("%w: %s", ErrVerificationFailed, strings.Join(result.ErrorCodes, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// internal/database/driver.go
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+)
+
+// Backend database yang bisa dipilih lewat DB_DRIVER.
+const (
+	// DriverPostgres memakai PostgreSQL lewat pgx (default).
+	DriverPostgres = "postgres"
+	// DriverSQLite memakai satu file SQLite (SQLITE_PATH); hanya tersedia pada binary yang
+	// di-build dengan tag sqlite (go build -tags sqlite).
+	DriverSQLite = "sqlite"
+)
+
+// DriverFromEnv membaca DB_DRIVER (default postgres). Nilai selain postgres/sqlite ditolak agar
+// konfigurasi yang salah tidak diam-diam memakai PostgreSQL.
+func DriverFromEnv() (string, error) {
+	driver := strings.ToLower(configs.GetEnv("DB_DRIVER", DriverPostgres))
+	switch driver {
+	case DriverPostgres, DriverSQLite:
+		return driver, nil
+	default:
+		return "", fmt.Errorf("unsupported DB_DRIVER %q: use %s or %s", driver, DriverPostgres, DriverSQLite)
+	}
+}
@@ -0,0 +1,231 @@
+// internal/database/failover.go
+package database
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/metrics"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// failoverTarget adalah satu database kandidat dari DATABASE_URLS.
+type failoverTarget struct {
+	name   string // host:port/dbname untuk log & metrik (tanpa kredensial)
+	config *pgx.ConnConfig
+}
+
+// Failover mengarahkan koneksi baru pool primary ke salah satu DSN di DATABASE_URLS (primary
+// lebih dulu, lalu standby). Start memeriksa target aktif secara berkala; setelah beberapa
+// pemeriksaan gagal berturut-turut, pool dipindah ke target pertama yang bisa ditulis (bukan
+// pg_is_in_recovery(), yaitu standby yang sudah dipromosikan), dan selama memakai standby
+// primary diperiksa berkala untuk kembali begitu pulih. Koneksi lama ditutup saat berpindah
+// (pgxpool.Pool.Reset) sehingga pool yang sama tetap dipakai seluruh aplikasi.
+//
+// Tidak ada fencing: pastikan primary lama tidak menerima tulisan lagi setelah standby
+// dipromosikan, atau nonaktifkan failback (DB_FAILOVER_PRIMARY_PROBE_INTERVAL=0).
+type Failover struct {
+	targets       []failoverTarget
+	active        atomic.Int32
+	pool          *pgxpool.Pool // Diisi setelah pool terbentuk
+	interval      time.Duration
+	timeout       time.Duration
+	threshold     int
+	probeInterval time.Duration
+
+	activeVar    *expvar.String
+	failovers    *expvar.Int
+	failbacks    *expvar.Int
+	checkFailVar *expvar.Int
+}
+
+// newFailoverFromEnv mem-parse DSN kandidat dan membaca parameter pemantauan.
+//
+// Variabel Environment yang didukung:
+//   - DB_FAILOVER_CHECK_INTERVAL: Jeda antar pemeriksaan target aktif. Default: 5s. 0 = tanpa
+//     pemantauan (target hanya dipilih saat startup).
+//   - DB_FAILOVER_CHECK_TIMEOUT: Batas waktu satu pemeriksaan (konek + query). Default: 2s.
+//   - DB_FAILOVER_FAILURE_THRESHOLD: Pemeriksaan gagal berturut-turut sebelum pindah target. Default: 3.
+//   - DB_FAILOVER_PRIMARY_PROBE_INTERVAL: Jeda pemeriksaan primary selama memakai standby. Default: 30s.
+//     0 = tidak kembali otomatis ke primary.
+func newFailoverFromEnv(dsns []string) (*Failover, error) {
+	f := &Failover{
+		interval:      configs.GetEnvDuration("DB_FAILOVER_CHECK_INTERVAL", 5*time.Second),
+		timeout:       configs.GetEnvDuration("DB_FAILOVER_CHECK_TIMEOUT", 2*time.Second),
+		threshold:     configs.GetEnvInt("DB_FAILOVER_FAILURE_THRESHOLD", 3),
+		probeInterval: configs.GetEnvDuration("DB_FAILOVER_PRIMARY_PROBE_INTERVAL", 30*time.Second),
+		activeVar:     new(expvar.String),
+		failovers:     new(expvar.Int),
+		failbacks:     new(expvar.Int),
+		checkFailVar:  new(expvar.Int),
+	}
+	if f.timeout <= 0 || f.threshold < 1 {
+		return nil, fmt.Errorf("DB_FAILOVER_CHECK_TIMEOUT must be positive and DB_FAILOVER_FAILURE_THRESHOLD at least 1")
+	}
+	for i, dsn := range dsns {
+		config, err := pgx.ParseConfig(dsn)
+		if err != nil {
+			// DSN tidak di-log karena berisi password.
+			return nil, fmt.Errorf("unable to parse DATABASE_URLS entry %d: %w", i+1, err)
+		}
+		name := fmt.Sprintf("%s:%d/%s", config.Host, config.Port, config.Database)
+		f.targets = append(f.targets, failoverTarget{name: name, config: config})
+	}
+	f.activeVar.Set(f.targets[0].name)
+	stats := new(expvar.Map)
+	stats.Set("active_target", f.activeVar)
+	stats.Set("failovers_total", f.failovers)
+	stats.Set("failbacks_total", f.failbacks)
+	stats.Set("consecutive_check_failures", f.checkFailVar)
+	metrics.DBFailover.Set("primary", stats)
+	return f, nil
+}
+
+// beforeConnect (pgxpool.Config.BeforeConnect) mengganti target koneksi baru dengan target aktif.
+// Tracer dan parameter pool tetap dari konfigurasi pool; ConnectTimeout dari DB_POOL_CONNECT_TIMEOUT.
+func (f *Failover) beforeConnect(_ context.Context, cc *pgx.ConnConfig) error {
+	target := f.targets[f.active.Load()]
+	connectTimeout := cc.ConnectTimeout
+	cc.Config = *target.config.Config.Copy()
+	cc.ConnectTimeout = connectTimeout
+	return nil
+}
+
+// ActiveTarget mengembalikan nama target yang sedang dipakai (host:port/dbname).
+func (f *Failover) ActiveTarget() string {
+	return f.targets[f.active.Load()].name
+}
+
+// probe membuka koneksi langsung ke target i dan melaporkan apakah target bisa ditulis.
+func (f *Failover) probe(ctx context.Context, i int) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+	conn, err := pgx.ConnectConfig(ctx, f.targets[i].config.Copy())
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+	var inRecovery bool
+	if err := conn.QueryRow(ctx, `SELECT pg_is_in_recovery()`).Scan(&inRecovery); err != nil {
+		return false, err
+	}
+	return !inRecovery, nil
+}
+
+// selectWritable memilih target pertama yang bisa ditulis (urut dari primary), kecuali skip.
+// Mengembalikan -1 jika tidak ada.
+func (f *Failover) selectWritable(ctx context.Context, skip int) int {
+	for i, target := range f.targets {
+		if i == skip {
+			continue
+		}
+		writable, err := f.probe(ctx, i)
+		switch {
+		case err != nil:
+			zlog.Warn().Err(err).Str("target", target.name).Msg("Database failover candidate not reachable")
+		case !writable:
+			zlog.Warn().Str("target", target.name).Msg("Database failover candidate is a standby in recovery (not promoted), skipping")
+		default:
+			return i
+		}
+	}
+	return -1
+}
+
+// chooseInitial dipanggil sebelum setiap percobaan koneksi awal: jika target aktif tidak bisa
+// ditulis, pindah ke target lain yang bisa ditulis (mis. primary mati saat aplikasi start).
+func (f *Failover) chooseInitial(ctx context.Context) {
+	active := int(f.active.Load())
+	if writable, err := f.probe(ctx, active); err == nil && writable {
+		return
+	}
+	if i := f.selectWritable(ctx, active); i >= 0 {
+		f.active.Store(int32(i))
+		f.activeVar.Set(f.targets[i].name)
+		zlog.Warn().Str("target", f.targets[i].name).Int("index", i).Msg("Database primary unavailable at startup, using failover target")
+	}
+}
+
+// switchTo memindahkan pool ke target i dan menutup koneksi ke target lama.
+func (f *Failover) switchTo(i int, reason string) {
+	from := f.ActiveTarget()
+	f.active.Store(int32(i))
+	f.activeVar.Set(f.targets[i].name)
+	f.checkFailVar.Set(0)
+	if i == 0 {
+		f.failbacks.Add(1)
+	} else {
+		f.failovers.Add(1)
+	}
+	if f.pool != nil {
+		f.pool.Reset()
+	}
+	zlog.Warn().Str("from", from).Str("to", f.targets[i].name).Str("reason", reason).Msg("Database pool switched to another target")
+}
+
+// Start menjalankan pemantauan sampai ctx dibatalkan. Tidak melakukan apa pun jika
+// DB_FAILOVER_CHECK_INTERVAL = 0.
+func (f *Failover) Start(ctx context.Context) {
+	if f.interval <= 0 {
+		zlog.Info().Msg("Database failover monitoring disabled")
+		return
+	}
+	zlog.Info().Int("targets", len(f.targets)).Str("active", f.ActiveTarget()).Dur("interval", f.interval).Msg("Database failover monitoring started")
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	failures := 0
+	lastPrimaryProbe := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		active := int(f.active.Load())
+
+		// Selama memakai standby: kembali ke primary begitu primary bisa ditulis lagi.
+		if active != 0 && f.probeInterval > 0 && time.Since(lastPrimaryProbe) >= f.probeInterval {
+			lastPrimaryProbe = time.Now()
+			if writable, err := f.probe(ctx, 0); err == nil && writable {
+				f.switchTo(0, "primary recovered")
+				failures = 0
+				continue
+			}
+		}
+
+		writable, err := f.probe(ctx, active)
+		if err == nil && writable {
+			failures = 0
+			f.checkFailVar.Set(0)
+			continue
+		}
+		failures++
+		f.checkFailVar.Set(int64(failures))
+		logEvent := zlog.Warn().Str("target", f.ActiveTarget()).Int("failures", failures).Int("threshold", f.threshold)
+		if err != nil {
+			logEvent = logEvent.Err(err)
+		} else {
+			logEvent = logEvent.Bool("in_recovery", true)
+		}
+		logEvent.Msg("Database health check failed")
+		if failures < f.threshold {
+			continue
+		}
+		if i := f.selectWritable(ctx, active); i >= 0 {
+			reason := "active target unavailable"
+			if err == nil {
+				reason = "active target became read-only"
+			}
+			f.switchTo(i, reason)
+			failures = 0
+			continue
+		}
+		zlog.Error().Str("target", f.ActiveTarget()).Msg("No writable database target available for failover")
+	}
+}
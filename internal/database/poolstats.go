@@ -0,0 +1,44 @@
+// internal/database/poolstats.go
+package database
+
+import (
+	"expvar"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rakaarfi/attendance-system-be/internal/metrics"
+)
+
+// PoolStats mengembalikan statistik pool saat ini dalam bentuk yang siap di-encode JSON.
+// Waktu tunggu acquire dipisah antara semua acquire (acquire_wait_ms_total) dan acquire yang
+// harus menunggu karena tidak ada koneksi idle (empty_acquire_*): jika empty_acquire_count terus
+// naik sementara acquired_conns = max_conns, pool terlalu kecil untuk beban instance ini.
+func PoolStats(pool *pgxpool.Pool) map[string]any {
+	stat := pool.Stat()
+	stats := map[string]any{
+		"max_conns":                   stat.MaxConns(),
+		"total_conns":                 stat.TotalConns(),
+		"idle_conns":                  stat.IdleConns(),
+		"acquired_conns":              stat.AcquiredConns(),
+		"constructing_conns":          stat.ConstructingConns(),
+		"acquire_count":               stat.AcquireCount(),
+		"acquire_wait_ms_total":       stat.AcquireDuration().Milliseconds(),
+		"empty_acquire_count":         stat.EmptyAcquireCount(),
+		"empty_acquire_wait_ms_total": stat.EmptyAcquireWaitTime().Milliseconds(),
+		"canceled_acquire_count":      stat.CanceledAcquireCount(),
+		"new_conns_count":             stat.NewConnsCount(),
+		"max_lifetime_destroy_count":  stat.MaxLifetimeDestroyCount(),
+		"max_idle_destroy_count":      stat.MaxIdleDestroyCount(),
+	}
+	if count := stat.AcquireCount(); count > 0 {
+		stats["acquire_wait_ms_avg"] = float64(stat.AcquireDuration().Microseconds()) / float64(count) / 1000
+	}
+	return stats
+}
+
+// registerPoolMetrics mendaftarkan statistik pool di grup metrik db_pools dengan nama name.
+// Nilainya dihitung ulang setiap kali metrik dibaca.
+func registerPoolMetrics(name string, pool *pgxpool.Pool) {
+	metrics.DBPools.Set(name, expvar.Func(func() any {
+		return PoolStats(pool)
+	}))
+}
@@ -0,0 +1,121 @@
+// internal/database/sqlite.go
+
+//go:build sqlite
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/migrations"
+	sqlitemigrations "github.com/rakaarfi/attendance-system-be/migrations/sqlite"
+	zlog "github.com/rs/zerolog/log"
+	_ "modernc.org/sqlite" // Driver database/sql "sqlite" (pure Go, tanpa cgo).
+)
+
+// NewSQLiteDB membuka database SQLite untuk DB_DRIVER=sqlite dan menjalankan migrasi
+// migrations/sqlite yang belum diterapkan.
+//
+// Variabel Environment yang didukung:
+//   - SQLITE_PATH: Lokasi file database. Default: data/attendance.db (direktori dibuat jika belum ada).
+//   - SQLITE_BUSY_TIMEOUT: Lama menunggu lock tulis sebelum query gagal. Default: 5s.
+//
+// SQLite hanya mengizinkan satu penulis; transaksi langsung mengambil lock tulis (BEGIN
+// IMMEDIATE) dan journal WAL membuat pembaca tidak terblokir penulis. Cocok untuk instalasi
+// kecil dengan satu instance API; gunakan PostgreSQL untuk beberapa replika.
+func NewSQLiteDB() (*sql.DB, error) {
+	path := configs.GetEnv("SQLITE_PATH", "data/attendance.db")
+	busyTimeout := configs.GetEnvDuration("SQLITE_BUSY_TIMEOUT", 5*time.Second)
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("unable to create SQLite directory %s: %w", dir, err)
+		}
+	}
+	db, err := OpenSQLite(context.Background(), path, busyTimeout)
+	if err != nil {
+		return nil, err
+	}
+	zlog.Info().Str("path", path).Msg("Successfully opened SQLite database")
+	return db, nil
+}
+
+// OpenSQLite membuka file SQLite di path dengan foreign key aktif, lalu menerapkan migrasi.
+// Dipakai juga oleh test repository SQLite dengan file sementara.
+func OpenSQLite(ctx context.Context, path string, busyTimeout time.Duration) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "synchronous(NORMAL)")
+	params.Set("_txlock", "immediate")
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("unable to open SQLite database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to open SQLite database %s: %w", path, err)
+	}
+	if err := migrateSQLite(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// migrateSQLite menerapkan migrasi SQLite yang belum dijalankan, masing-masing dalam satu
+// transaksi, dan mencatat versinya di schema_migrations (satu baris version & dirty).
+func migrateSQLite(ctx context.Context, db *sql.DB) error {
+	files, err := migrations.LoadFS(sqlitemigrations.FS)
+	if err != nil {
+		return fmt.Errorf("error loading SQLite migrations: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`); err != nil {
+		return fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+	var current int64
+	var dirty bool
+	err = db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&current, &dirty)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("error reading schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("SQLite schema version %d is dirty: fix the failed migration, then set schema_migrations.dirty = false", current)
+	}
+	for _, f := range migrations.Pending(files, current) {
+		if err := applySQLiteMigration(ctx, db, f); err != nil {
+			return err
+		}
+		zlog.Info().Int64("version", f.Version).Str("file", f.Name).Msg("Applied SQLite migration")
+	}
+	return nil
+}
+
+// applySQLiteMigration menjalankan satu file migrasi beserta pencatatan versinya secara atomik.
+func applySQLiteMigration(ctx context.Context, db *sql.DB, f migrations.File) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting migration %s: %w", f.Name, err)
+	}
+	defer tx.Rollback() // No-op jika sudah di-commit
+	if _, err := tx.ExecContext(ctx, f.SQL); err != nil {
+		return fmt.Errorf("error applying migration %s: %w", f.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return fmt.Errorf("error recording migration %s: %w", f.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES (?, FALSE)`, f.Version); err != nil {
+		return fmt.Errorf("error recording migration %s: %w", f.Name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing migration %s: %w", f.Name, err)
+	}
+	return nil
+}
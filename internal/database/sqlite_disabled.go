@@ -0,0 +1,19 @@
+// internal/database/sqlite_disabled.go
+
+//go:build !sqlite
+
+package database
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// ErrSQLiteUnavailable dikembalikan NewSQLiteDB pada binary yang di-build tanpa tag sqlite.
+var ErrSQLiteUnavailable = errors.New("DB_DRIVER=sqlite requires a binary built with the sqlite build tag (go build -tags sqlite)")
+
+// NewSQLiteDB selalu gagal tanpa tag build sqlite, sehingga driver SQLite (modernc.org/sqlite)
+// tidak ikut ke binary PostgreSQL.
+func NewSQLiteDB() (*sql.DB, error) {
+	return nil, ErrSQLiteUnavailable
+}
@@ -0,0 +1,88 @@
+// internal/database/tracer.go
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	applogger "github.com/rakaarfi/attendance-system-be/internal/logger"
+	"github.com/rakaarfi/attendance-system-be/internal/metrics"
+)
+
+// queryTraceKey adalah key context untuk data query yang sedang berjalan.
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	start time.Time
+	sql   string
+	args  []any
+}
+
+// QueryTracer adalah pgx.QueryTracer yang menghitung metrik query (internal/metrics)
+// dan mencatat query yang lebih lambat dari SlowThreshold.
+// Nilai parameter tidak pernah di-log (bisa berisi PII/kredensial); hanya tipenya.
+type QueryTracer struct {
+	SlowThreshold time.Duration // 0 = nonaktifkan log query lambat (counter tetap jalan).
+}
+
+// NewQueryTracer membuat QueryTracer dengan ambang query lambat tertentu.
+func NewQueryTracer(slowThreshold time.Duration) *QueryTracer {
+	return &QueryTracer{SlowThreshold: slowThreshold}
+}
+
+// TraceQueryStart menyimpan waktu mulai query di context.
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, &queryTrace{start: time.Now(), sql: data.SQL, args: data.Args})
+}
+
+// TraceQueryEnd memperbarui counter dan mencatat query lambat/gagal.
+// Log memakai logger per-request dari ctx sehingga membawa request_id dan user_id.
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return
+	}
+	duration := time.Since(trace.start)
+
+	metrics.DB.Add(metrics.DBQueriesTotal, 1)
+	metrics.DB.Add(metrics.DBQueryTimeMsTotal, duration.Milliseconds())
+	if data.Err != nil {
+		metrics.DB.Add(metrics.DBQueryErrorsTotal, 1)
+	}
+	if t.SlowThreshold <= 0 || duration < t.SlowThreshold {
+		return
+	}
+	metrics.DB.Add(metrics.DBSlowQueriesTotal, 1)
+
+	event := applogger.Module(ctx, applogger.ModuleDatabase).Warn().
+		Str("sql", compactSQL(trace.sql)).
+		Strs("arg_types", redactedArgs(trace.args)).
+		Dur("duration", duration).
+		Dur("threshold", t.SlowThreshold).
+		Int64("rows_affected", data.CommandTag.RowsAffected())
+	if data.Err != nil {
+		event = event.Err(data.Err)
+	}
+	event.Msg("Slow database query")
+}
+
+// compactSQL merapikan whitespace query multi-baris agar satu baris di log.
+func compactSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// redactedArgs mengganti nilai parameter query dengan tipenya (mis. "int", "*string", "nil").
+func redactedArgs(args []any) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			out[i] = "nil"
+			continue
+		}
+		out[i] = fmt.Sprintf("%T", arg)
+	}
+	return out
+}
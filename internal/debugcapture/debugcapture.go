@@ -0,0 +1,265 @@
+// internal/debugcapture/debugcapture.go
+
+// Package debugcapture merekam request/response API lengkap untuk mendiagnosis masalah klien
+// yang sulit direproduksi, tanpa deploy ulang dengan logging tambahan. Perekaman diaktifkan
+// saat runtime per route (debug.capture_routes) atau per user (debug.capture_users) lewat
+// PUT /admin/settings; rekaman disimpan di tabel debug_captures sampai debug.capture_ttl_hours.
+//
+// Sebelum disimpan, header dan field body yang berisi kredensial (lihat logger.IsSensitiveField)
+// atau data pribadi (email, nomor telepon) di-redact, dan body yang panjang dipotong.
+package debugcapture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/logger"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// saveTimeout membatasi penyimpanan rekaman yang berjalan di luar siklus request.
+const saveTimeout = 10 * time.Second
+
+// maxParsedBodyBytes: body JSON/form yang lebih besar tidak di-parse untuk redaction dan
+// tidak disimpan sama sekali (mis. unduhan file besar).
+const maxParsedBodyBytes = 1 << 20
+
+// piiFieldMarkers adalah potongan nama field (lowercase, tanpa '_' dan '-') berisi data pribadi
+// yang ikut di-redact selain field kredensial.
+var piiFieldMarkers = []string{"email", "phone"}
+
+// Recorder memutuskan request mana yang direkam dan menyimpannya (implementasi
+// middleware.RequestCapturer).
+type Recorder struct {
+	repo         repository.DebugCaptureRepository
+	settings     *settings.Store
+	maxBodyBytes int
+}
+
+// NewRecorderFromEnv membuat Recorder berdasarkan environment variables.
+// Mengembalikan nil jika fitur dimatikan.
+//
+// Variabel Environment yang didukung:
+//   - DEBUG_CAPTURE_ENABLED: Izinkan perekaman request/response (tetap harus diaktifkan per
+//     route/user lewat pengaturan). Default: true.
+//   - DEBUG_CAPTURE_MAX_BODY_BYTES: Panjang maksimum body yang disimpan; sisanya dipotong. Default: 65536.
+func NewRecorderFromEnv(repo repository.DebugCaptureRepository, settingsStore *settings.Store) *Recorder {
+	if !configs.GetEnvBool("DEBUG_CAPTURE_ENABLED", true) {
+		zlog.Info().Msg("Debug request capture disabled")
+		return nil
+	}
+	maxBodyBytes := configs.GetEnvInt("DEBUG_CAPTURE_MAX_BODY_BYTES", 64*1024)
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = 64 * 1024
+	}
+	return &Recorder{repo: repo, settings: settingsStore, maxBodyBytes: maxBodyBytes}
+}
+
+// ShouldCapture melaporkan apakah request perlu direkam: userID ada di debug.capture_users,
+// atau method & route cocok dengan salah satu pola debug.capture_routes.
+func (r *Recorder) ShouldCapture(ctx context.Context, method, route string, userID int) bool {
+	if userID != 0 && r.settings.DebugCaptureUsers(ctx)[userID] {
+		return true
+	}
+	for _, pattern := range r.settings.DebugCaptureRoutes(ctx) {
+		if MatchRoute(pattern, method, route) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchRoute mencocokkan pola "[method] /path" (huruf besar/kecil diabaikan; * di akhir path
+// mencocokkan prefix) dengan method dan pola route yang didaftarkan.
+func MatchRoute(pattern, method, route string) bool {
+	patternMethod, path, hasMethod := strings.Cut(pattern, " ")
+	if !hasMethod {
+		patternMethod, path = "", pattern
+	}
+	if patternMethod != "" && !strings.EqualFold(patternMethod, method) {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(path, "*"); ok {
+		return len(route) >= len(prefix) && strings.EqualFold(route[:len(prefix)], prefix)
+	}
+	return strings.EqualFold(path, route)
+}
+
+// Capture menyalin request/response dari c (sudah di-redact) lalu menyimpannya di background
+// agar request tidak menunggu database.
+func (r *Recorder) Capture(c *fiber.Ctx, route string, userID int, latency time.Duration, handlerErr error) {
+	ctx := c.UserContext()
+	capture := &models.DebugCapture{
+		Method:          c.Method(),
+		Route:           route,
+		Path:            c.Path(),
+		Status:          c.Response().StatusCode(),
+		LatencyMs:       int(latency / time.Millisecond),
+		RequestHeaders:  headers(c.Request().Header.VisitAll),
+		ResponseHeaders: headers(c.Response().Header.VisitAll),
+		RequestBody:     r.body(c.Request().Header.ContentType(), c.Body()),
+		ExpiresAt:       time.Now().Add(r.settings.DebugCaptureTTL(ctx)),
+	}
+	if requestID, _ := c.Locals("requestid").(string); requestID != "" {
+		capture.RequestID = &requestID
+	}
+	if userID != 0 {
+		capture.UserID = &userID
+	}
+	if query := redactQuery(string(c.Request().URI().QueryString())); query != "" {
+		capture.Query = &query
+	}
+	if ip := c.IP(); ip != "" {
+		capture.IP = &ip
+	}
+	if handlerErr != nil {
+		// Response error belum ditulis: ErrorHandler global berjalan setelah middleware ini.
+		capture.Status = fiber.StatusInternalServerError
+		var fiberErr *fiber.Error
+		if errors.As(handlerErr, &fiberErr) {
+			capture.Status = fiberErr.Code
+		}
+		message := handlerErr.Error()
+		capture.Error = &message
+	} else if c.Response().IsBodyStream() {
+		// Body stream (mis. SSE) tidak dibaca: membacanya akan menghabiskan stream klien.
+		omitted := "[streamed body omitted]"
+		capture.ResponseBody = &omitted
+	} else {
+		capture.ResponseBody = r.body(c.Response().Header.ContentType(), c.Response().Body())
+	}
+
+	saveCtx := zerolog.Ctx(ctx).WithContext(context.WithoutCancel(ctx))
+	go func() {
+		saveCtx, cancel := context.WithTimeout(saveCtx, saveTimeout)
+		defer cancel()
+		if err := r.repo.CreateDebugCapture(saveCtx, capture); err != nil {
+			zerolog.Ctx(saveCtx).Warn().Err(err).Str("route", capture.Route).Msg("Failed to save debug capture")
+		}
+	}()
+}
+
+// headers menyalin header dari visitAll (VisitAll header request/response) dengan nilai header
+// kredensial (Authorization, Cookie, Set-Cookie, X-Captcha-Token, dll.) diganti logger.RedactedValue.
+func headers(visitAll func(func(key, value []byte))) map[string]string {
+	out := map[string]string{}
+	visitAll(func(key, value []byte) {
+		out[string(key)] = redactValue(string(key), string(value))
+	})
+	return out
+}
+
+// body mengembalikan salinan body yang sudah di-redact dan dipotong sesuai content type:
+// JSON dan form di-redact per field, text/plain disimpan apa adanya, selain itu (multipart,
+// CSV/file ekspor, gambar) hanya dicatat ukurannya. nil jika body kosong.
+func (r *Recorder) body(contentType []byte, body []byte) *string {
+	if len(body) == 0 {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(string(contentType))
+	var out string
+	switch {
+	case len(body) > maxParsedBodyBytes:
+		out = fmt.Sprintf("[%s body omitted, %d bytes]", mediaType, len(body))
+	case mediaType == fiber.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json"):
+		out = redactJSON(body)
+	case mediaType == fiber.MIMEApplicationForm:
+		out = redactQuery(string(body))
+	case mediaType == fiber.MIMETextPlain:
+		out = string(body)
+	default:
+		out = fmt.Sprintf("[%s body omitted, %d bytes]", mediaType, len(body))
+	}
+	if len(out) > r.maxBodyBytes {
+		out = fmt.Sprintf("%s...[truncated %d bytes]", out[:r.maxBodyBytes], len(out)-r.maxBodyBytes)
+	}
+	return &out
+}
+
+// redactJSON mengganti nilai field sensitif (di objek mana pun, termasuk di dalam array)
+// dengan logger.RedactedValue. Body yang bukan JSON valid dicatat ukurannya saja karena
+// field sensitifnya tidak bisa dipastikan.
+func redactJSON(body []byte) string {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber() // Pertahankan angka apa adanya (tanpa konversi float64)
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Sprintf("[invalid JSON body omitted, %d bytes]", len(body))
+	}
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(redactTree(value)); err != nil {
+		return fmt.Sprintf("[JSON body omitted, %d bytes]", len(body))
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+func redactTree(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			if isRedactedField(key) {
+				v[key] = logger.RedactedValue
+			} else {
+				v[key] = redactTree(nested)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = redactTree(v[i])
+		}
+	}
+	return value
+}
+
+// redactQuery me-redact parameter sensitif query string / body form (mis. ?token=).
+func redactQuery(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return fmt.Sprintf("[invalid query omitted, %d bytes]", len(raw))
+	}
+	for key, list := range values {
+		for i := range list {
+			list[i] = redactValue(key, list[i])
+		}
+	}
+	return values.Encode()
+}
+
+func redactValue(name, value string) string {
+	if isRedactedField(name) {
+		return logger.RedactedValue
+	}
+	return value
+}
+
+// isRedactedField melaporkan apakah nilai field/header/parameter name tidak boleh disimpan.
+func isRedactedField(name string) bool {
+	if logger.IsSensitiveField(name) {
+		return true
+	}
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	for _, marker := range piiFieldMarkers {
+		if strings.Contains(normalized, marker) {
+			return true
+		}
+	}
+	return false
+}
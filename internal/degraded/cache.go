@@ -0,0 +1,188 @@
+// internal/degraded/cache.go
+package degraded
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// readCache menyimpan hasil baca terakhir yang berhasil: daftar lengkap dan item per ID.
+type readCache[T any] struct {
+	mu   sync.RWMutex
+	all  []T
+	byID map[int]T
+}
+
+func newReadCache[T any]() *readCache[T] {
+	return &readCache[T]{byID: map[int]T{}}
+}
+
+func (rc *readCache[T]) storeAll(items []T, id func(T) int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.all = append([]T(nil), items...)
+	for _, item := range items {
+		rc.byID[id(item)] = item
+	}
+}
+
+func (rc *readCache[T]) store(id int, item T) {
+	rc.mu.Lock()
+	rc.byID[id] = item
+	rc.mu.Unlock()
+}
+
+// invalidate membuang data yang mungkin berubah setelah tulis (id 0 = hanya daftar).
+func (rc *readCache[T]) invalidate(id int) {
+	rc.mu.Lock()
+	rc.all = nil
+	delete(rc.byID, id)
+	rc.mu.Unlock()
+}
+
+func (rc *readCache[T]) getAll() ([]T, bool) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	return append([]T(nil), rc.all...), rc.all != nil
+}
+
+func (rc *readCache[T]) get(id int) (T, bool) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	item, ok := rc.byID[id]
+	return item, ok
+}
+
+// shiftCache membungkus ShiftRepository; lihat CacheShifts.
+type shiftCache struct {
+	repository.ShiftRepository
+	ctrl  *Controller
+	cache *readCache[models.Shift]
+}
+
+// CacheShifts membungkus repo agar daftar & detail shift yang terakhir berhasil dibaca
+// dilayani dari memori saat database tidak tersedia (error lain tetap diteruskan).
+// Tulisan yang berhasil membuang cache terkait agar tidak ada data basi.
+func CacheShifts(repo repository.ShiftRepository, ctrl *Controller) repository.ShiftRepository {
+	return &shiftCache{ShiftRepository: repo, ctrl: ctrl, cache: newReadCache[models.Shift]()}
+}
+
+func (s *shiftCache) GetAllShifts(ctx context.Context) ([]models.Shift, error) {
+	shifts, err := s.ShiftRepository.GetAllShifts(ctx)
+	if err == nil {
+		s.cache.storeAll(shifts, func(sh models.Shift) int { return sh.ID })
+		return shifts, nil
+	}
+	if cached, ok := s.cache.getAll(); ok && s.ctrl.ReportError(err) {
+		return cached, nil
+	}
+	return nil, err
+}
+
+func (s *shiftCache) GetShiftByID(ctx context.Context, id int) (*models.Shift, error) {
+	shift, err := s.ShiftRepository.GetShiftByID(ctx, id)
+	if err == nil {
+		s.cache.store(id, *shift)
+		return shift, nil
+	}
+	if cached, ok := s.cache.get(id); ok && s.ctrl.ReportError(err) {
+		return &cached, nil
+	}
+	return nil, err
+}
+
+func (s *shiftCache) CreateShift(ctx context.Context, shift *models.Shift) (int, error) {
+	id, err := s.ShiftRepository.CreateShift(ctx, shift)
+	if err == nil {
+		s.cache.invalidate(0)
+	}
+	return id, err
+}
+
+func (s *shiftCache) UpdateShift(ctx context.Context, shift *models.Shift) error {
+	err := s.ShiftRepository.UpdateShift(ctx, shift)
+	if err == nil {
+		s.cache.invalidate(shift.ID)
+	}
+	return err
+}
+
+func (s *shiftCache) DeleteShift(ctx context.Context, id int) error {
+	err := s.ShiftRepository.DeleteShift(ctx, id)
+	if err == nil {
+		s.cache.invalidate(id)
+	}
+	return err
+}
+
+// roleCache membungkus RoleRepository; lihat CacheRoles.
+type roleCache struct {
+	repository.RoleRepository
+	ctrl  *Controller
+	cache *readCache[models.Role]
+}
+
+// CacheRoles membungkus repo agar daftar & detail role dilayani dari memori saat database
+// tidak tersedia, sama seperti CacheShifts.
+func CacheRoles(repo repository.RoleRepository, ctrl *Controller) repository.RoleRepository {
+	return &roleCache{RoleRepository: repo, ctrl: ctrl, cache: newReadCache[models.Role]()}
+}
+
+func (r *roleCache) GetAllRoles(ctx context.Context) ([]models.Role, error) {
+	roles, err := r.RoleRepository.GetAllRoles(ctx)
+	if err == nil {
+		r.cache.storeAll(roles, func(role models.Role) int { return role.ID })
+		return roles, nil
+	}
+	if cached, ok := r.cache.getAll(); ok && r.ctrl.ReportError(err) {
+		return cached, nil
+	}
+	return nil, err
+}
+
+func (r *roleCache) GetRoleByID(ctx context.Context, id int) (*models.Role, error) {
+	role, err := r.RoleRepository.GetRoleByID(ctx, id)
+	if err == nil {
+		r.cache.store(id, *role)
+		return role, nil
+	}
+	if cached, ok := r.cache.get(id); ok && r.ctrl.ReportError(err) {
+		return &cached, nil
+	}
+	return nil, err
+}
+
+func (r *roleCache) CreateRole(ctx context.Context, role *models.Role) (int, error) {
+	id, err := r.RoleRepository.CreateRole(ctx, role)
+	if err == nil {
+		r.cache.invalidate(0)
+	}
+	return id, err
+}
+
+func (r *roleCache) UpdateRole(ctx context.Context, role *models.Role) error {
+	err := r.RoleRepository.UpdateRole(ctx, role)
+	if err == nil {
+		r.cache.invalidate(role.ID)
+	}
+	return err
+}
+
+func (r *roleCache) DeleteRole(ctx context.Context, id int) error {
+	err := r.RoleRepository.DeleteRole(ctx, id)
+	if err == nil {
+		r.cache.invalidate(id)
+	}
+	return err
+}
+
+func (r *roleCache) SetRoleParent(ctx context.Context, roleID int, parentID *int) (*models.Role, error) {
+	role, err := r.RoleRepository.SetRoleParent(ctx, roleID, parentID)
+	if err == nil {
+		r.cache.invalidate(roleID)
+	}
+	return role, err
+}
@@ -0,0 +1,291 @@
+// internal/degraded/degraded.go
+
+// Package degraded menjaga API tetap berguna saat database sempat tidak tersedia. Controller
+// memantau koneksi database (ping berkala, ditambah error koneksi yang dilaporkan handler).
+// Selama mode degraded, data baca shift & role dilayani dari cache memori (lihat CacheShifts,
+// CacheRoles) dan check-in ditampung di buffer lokal terbatas, lalu dikirim ulang berurutan
+// begitu database pulih. Buffer hanya ada di memori proses: punch yang belum terkirim hilang
+// jika instance berhenti. Punch yang gagal dicatat karena alasan lain (mis. jadwal dihapus)
+// diserahkan ke RejectFunc untuk disimpan sebagai dead letter, bukan dibuang.
+package degraded
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/metrics"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	zlog "github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrBufferFull dikembalikan QueuePunch saat buffer punch penuh.
+	ErrBufferFull = errors.New("check-in buffer is full")
+	// ErrAlreadyQueued dikembalikan QueuePunch jika user sudah punya check-in di buffer.
+	ErrAlreadyQueued = errors.New("a check-in for this user is already queued")
+)
+
+// Pinger memeriksa koneksi database (dipenuhi *pgxpool.Pool).
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Punch adalah check-in yang diterima saat mode degraded, menunggu dicatat ke database.
+type Punch struct {
+	UserID    int       `json:"user_id"`
+	At        time.Time `json:"at"` // Waktu check-in sebenarnya (saat request diterima)
+	Notes     *string   `json:"notes,omitempty"`
+	Tags      []string  `json:"tags,omitempty"` // Sudah divalidasi terhadap attendance.tags saat diterima
+	ProjectID *int      `json:"project_id,omitempty"`
+	Mode      string    `json:"mode,omitempty"` // Kebijakan mode kerja diperiksa saat punch dicatat
+}
+
+// FlushFunc mencatat satu punch ke database. Error yang memenuhi IsUnavailable membuat punch
+// tetap di buffer untuk dicoba lagi; error lain (mis. user sudah check-in) menyerahkan punch
+// ke RejectFunc.
+type FlushFunc func(ctx context.Context, p Punch) error
+
+// RejectFunc menyimpan punch yang gagal dicatat FlushFunc (cause) sebagai dead letter dan
+// memberi tahu user/admin. source adalah buffer asal punch (models.QueuedCheckInSource*).
+// Jika mengembalikan error, punch tetap di buffer dan dicoba lagi pada flush berikutnya.
+type RejectFunc func(ctx context.Context, p Punch, source string, cause error) error
+
+// Replay mengirim ulang satu punch tertunda lewat flush; punch yang gagal dicatat karena alasan
+// selain database tidak tersedia diserahkan ke reject. recorded = punch tercatat sebagai absensi.
+// Error berarti punch harus tetap di buffer: database tidak tersedia, atau dead letter gagal
+// disimpan. Dipakai juga oleh buffer check-in mode maintenance.
+func Replay(ctx context.Context, p Punch, flush FlushFunc, reject RejectFunc, source string) (recorded bool, err error) {
+	cause := flush(ctx, p)
+	if cause == nil {
+		metrics.QueuedCheckIns.Add(metrics.QueuedCheckInsRecordedTotal, 1)
+		return true, nil
+	}
+	if IsUnavailable(cause) {
+		return false, cause
+	}
+	err = errors.New("no dead letter store configured")
+	if reject != nil {
+		err = reject(ctx, p, source, cause)
+	}
+	if err != nil {
+		metrics.QueuedCheckIns.Add(metrics.QueuedCheckInsDeadLetterErrTotal, 1)
+		zlog.Error().Err(err).AnErr("cause", cause).Int("user_id", p.UserID).Time("at", p.At).Str("source", source).
+			Msg("Failed to store queued check-in as dead letter, keeping it queued")
+		return false, errors.Join(cause, err)
+	}
+	metrics.QueuedCheckIns.Add(metrics.QueuedCheckInsDeadLetteredTotal, 1)
+	zlog.Warn().Err(cause).Int("user_id", p.UserID).Time("at", p.At).Str("source", source).Msg("Queued check-in could not be recorded, stored as dead letter")
+	return false, nil
+}
+
+// Status adalah keadaan controller untuk healthcheck.
+type Status struct {
+	Degraded       bool       `json:"degraded"`
+	Since          *time.Time `json:"since,omitempty"` // Awal mode degraded
+	QueuedPunches  int        `json:"queued_punches"`
+	BufferCapacity int        `json:"buffer_capacity"`
+}
+
+// Controller melacak apakah database tersedia dan menyimpan check-in yang tertunda.
+// Semua method aman dipanggil pada Controller nil (mode degraded nonaktif).
+type Controller struct {
+	db       Pinger
+	interval time.Duration
+	timeout  time.Duration
+	capacity int
+
+	mu       sync.Mutex
+	degraded bool
+	since    time.Time
+	punches  []Punch
+	flush    FlushFunc
+	reject   RejectFunc
+}
+
+// NewControllerFromEnv membuat controller berdasarkan environment variables.
+// Mengembalikan nil jika mode degraded dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - DEGRADED_CHECK_INTERVAL: Jeda antar ping database. Default: 5s. 0 menonaktifkan mode degraded.
+//   - DEGRADED_CHECK_TIMEOUT: Batas waktu satu ping. Default: 2s.
+//   - DEGRADED_PUNCH_BUFFER_SIZE: Maksimum check-in yang ditampung selama database tidak tersedia. Default: 1000.
+func NewControllerFromEnv(db Pinger) *Controller {
+	interval := configs.GetEnvDuration("DEGRADED_CHECK_INTERVAL", 5*time.Second)
+	if interval <= 0 {
+		zlog.Info().Msg("Degraded mode disabled")
+		return nil
+	}
+	return &Controller{
+		db:       db,
+		interval: interval,
+		timeout:  max(configs.GetEnvDuration("DEGRADED_CHECK_TIMEOUT", 2*time.Second), 100*time.Millisecond),
+		capacity: max(configs.GetEnvInt("DEGRADED_PUNCH_BUFFER_SIZE", 1000), 1),
+	}
+}
+
+// IsUnavailable melaporkan apakah err berasal dari database yang tidak dapat dihubungi
+// (gagal konek, timeout, koneksi terputus, server sedang shutdown/startup), bukan dari query.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) || pgconn.Timeout(err) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Kelas 08 (connection exception), 57P01-57P03 (admin/crash shutdown, cannot connect now)
+		return strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// SetFlusher mendaftarkan fungsi pencatat punch dan penyimpan dead letter-nya. Dipanggil sebelum Start.
+func (c *Controller) SetFlusher(flush FlushFunc, reject RejectFunc) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.flush, c.reject = flush, reject
+	c.mu.Unlock()
+}
+
+// Degraded melaporkan apakah controller sedang dalam mode degraded.
+func (c *Controller) Degraded() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.degraded
+}
+
+// ReportError masuk ke mode degraded jika err menandakan database tidak tersedia, dan
+// mengembalikan true dalam kasus itu. Dipanggil oleh handler/decorator yang menerima error.
+func (c *Controller) ReportError(err error) bool {
+	if c == nil || !IsUnavailable(err) {
+		return false
+	}
+	c.enter(err)
+	return true
+}
+
+func (c *Controller) enter(cause error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.degraded {
+		return
+	}
+	c.degraded, c.since = true, time.Now()
+	zlog.Warn().Err(cause).Msg("Database unavailable, entering degraded mode")
+}
+
+// QueuePunch menampung check-in selama mode degraded.
+// Mengembalikan ErrBufferFull atau ErrAlreadyQueued jika tidak dapat ditampung.
+func (c *Controller) QueuePunch(p Punch) error {
+	if c == nil {
+		return ErrBufferFull // Tanpa controller tidak ada buffer
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.punches) >= c.capacity {
+		return ErrBufferFull
+	}
+	for _, queued := range c.punches {
+		if queued.UserID == p.UserID {
+			return ErrAlreadyQueued
+		}
+	}
+	c.punches = append(c.punches, p)
+	return nil
+}
+
+// Status mengembalikan keadaan controller saat ini.
+func (c *Controller) Status() Status {
+	if c == nil {
+		return Status{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := Status{Degraded: c.degraded, QueuedPunches: len(c.punches), BufferCapacity: c.capacity}
+	if c.degraded {
+		since := c.since
+		s.Since = &since
+	}
+	return s
+}
+
+// Start mem-ping database setiap interval sampai ctx dibatalkan. Ping yang gagal masuk ke mode
+// degraded; ping yang berhasil mengirim punch tertunda lalu keluar dari mode degraded.
+// Dipanggil sebagai goroutine.
+func (c *Controller) Start(ctx context.Context) {
+	zlog.Info().Dur("interval", c.interval).Int("buffer_capacity", c.capacity).Msg("Degraded mode controller started")
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.check(ctx)
+	}
+}
+
+// check menjalankan satu ping dan, jika berhasil, pemulihan.
+func (c *Controller) check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	err := c.db.Ping(pingCtx)
+	cancel()
+	if err != nil {
+		c.enter(err)
+		return
+	}
+	if !c.Degraded() {
+		return
+	}
+	since := c.Status().Since
+	flushed, err := c.flushPunches(ctx)
+	if err != nil {
+		zlog.Warn().Err(err).Int("flushed", flushed).Msg("Queued check-ins not fully flushed, retrying on the next check")
+		return
+	}
+	zlog.Info().Dur("downtime", time.Since(*since)).Int("flushed", flushed).Msg("Database recovered, leaving degraded mode")
+}
+
+// flushPunches mencatat punch tertunda berurutan sampai buffer kosong (termasuk punch yang
+// masuk selama flush), lalu keluar dari mode degraded di bawah lock yang sama sehingga tidak
+// ada punch yang tertinggal. Berhenti dengan error (punch tetap di buffer) jika database
+// kembali tidak tersedia atau dead letter gagal disimpan.
+func (c *Controller) flushPunches(ctx context.Context) (int, error) {
+	flushed := 0
+	for {
+		c.mu.Lock()
+		if len(c.punches) == 0 || c.flush == nil {
+			c.degraded = false
+			c.mu.Unlock()
+			return flushed, nil
+		}
+		p, flush, reject := c.punches[0], c.flush, c.reject
+		c.mu.Unlock()
+
+		recorded, err := Replay(ctx, p, flush, reject, models.QueuedCheckInSourceDegraded)
+		if err != nil {
+			return flushed, err
+		}
+		c.mu.Lock()
+		c.punches = c.punches[1:]
+		c.mu.Unlock()
+		if recorded {
+			flushed++
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package degraded
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	punch := Punch{UserID: 7, At: time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)}
+	errAlreadyCheckedIn := errors.New("already checked in")
+
+	tests := []struct {
+		name         string
+		flushErr     error
+		rejectErr    error
+		noReject     bool
+		wantRecorded bool
+		wantErr      bool // true = punch tetap di buffer
+		wantRejected bool
+	}{
+		{name: "recorded", wantRecorded: true},
+		{name: "database still unavailable", flushErr: &pgconn.PgError{Code: "57P03"}, wantErr: true},
+		{name: "rejected punch is dead-lettered", flushErr: errAlreadyCheckedIn, wantRejected: true},
+		{name: "dead letter store fails", flushErr: errAlreadyCheckedIn, rejectErr: errors.New("insert failed"), wantErr: true, wantRejected: true},
+		{name: "no dead letter store", flushErr: errAlreadyCheckedIn, noReject: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flush := func(context.Context, Punch) error { return tt.flushErr }
+			var rejected bool
+			var reject RejectFunc = func(_ context.Context, p Punch, source string, cause error) error {
+				rejected = true
+				assert.Equal(t, punch, p)
+				assert.Equal(t, models.QueuedCheckInSourceDegraded, source)
+				assert.ErrorIs(t, cause, tt.flushErr)
+				return tt.rejectErr
+			}
+			if tt.noReject {
+				reject = nil
+			}
+
+			recorded, err := Replay(context.Background(), punch, flush, reject, models.QueuedCheckInSourceDegraded)
+			assert.Equal(t, tt.wantRecorded, recorded)
+			assert.Equal(t, tt.wantRejected, rejected)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,321 @@
+// internal/diagnostics/diagnostics.go
+
+// Package diagnostics memeriksa kesehatan dependensi instance ini (database, migrasi, storage,
+// SMTP, jam vs NTP, antrean job) dan melaporkan konfigurasi efektif dengan rahasia disamarkan.
+// Laporan yang sama ditulis ke log saat startup (SelfCheck) dan disajikan lewat
+// GET /admin/system/diagnostics sebagai titik awal setiap tiket support.
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/buildinfo"
+	"github.com/rakaarfi/attendance-system-be/internal/jobs"
+	"github.com/rakaarfi/attendance-system-be/internal/migrations"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/storage"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// probeKey adalah key objek yang dibuka untuk memeriksa storage; tidak pernah dibuat.
+const probeKey = "diagnostics/probe"
+
+// Service menjalankan pemeriksaan diagnostik.
+type Service struct {
+	pool          *pgxpool.Pool // nil di SANDBOX_MODE dan DB_DRIVER=sqlite
+	sandbox       bool
+	storage       storage.Storage
+	scheduler     *jobs.Scheduler
+	migrationsDir string
+	smtpAddr      string
+	ntpServer     string
+	maxClockSkew  time.Duration
+	jobPoll       time.Duration
+	timeout       time.Duration
+	startedAt     time.Time
+}
+
+// NewServiceFromEnv membuat service diagnostik berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - DIAGNOSTICS_TIMEOUT: Batas waktu satu pemeriksaan. Default: 3s.
+//   - DIAGNOSTICS_MIGRATIONS_DIR: Direktori file migrasi pembanding versi database. Default: migrations.
+//   - DIAGNOSTICS_NTP_SERVER: Server NTP (host:port) pembanding jam. Default: pool.ntp.org:123. 'off' menonaktifkan.
+//   - DIAGNOSTICS_MAX_CLOCK_SKEW: Selisih jam yang masih dianggap wajar. Default: 2s.
+//   - NOTIFY_SMTP_ADDR: Server SMTP yang diperiksa (lihat notify). Kosong = pemeriksaan dilewati.
+//
+// Self-check saat startup diatur DIAGNOSTICS_STARTUP_CHECK (default true) di cmd/api.
+func NewServiceFromEnv(pool *pgxpool.Pool, fileStorage storage.Storage, scheduler *jobs.Scheduler) *Service {
+	ntpServer := configs.GetEnv("DIAGNOSTICS_NTP_SERVER", "pool.ntp.org:123")
+	if strings.EqualFold(ntpServer, "off") {
+		ntpServer = ""
+	}
+	return &Service{
+		pool:          pool,
+		sandbox:       configs.GetEnvBool("SANDBOX_MODE", false),
+		storage:       fileStorage,
+		scheduler:     scheduler,
+		migrationsDir: configs.GetEnv("DIAGNOSTICS_MIGRATIONS_DIR", "migrations"),
+		smtpAddr:      configs.GetEnv("NOTIFY_SMTP_ADDR", ""),
+		ntpServer:     ntpServer,
+		maxClockSkew:  max(configs.GetEnvDuration("DIAGNOSTICS_MAX_CLOCK_SKEW", 2*time.Second), time.Millisecond),
+		jobPoll:       max(configs.GetEnvDuration("SCHEDULER_POLL_INTERVAL", 30*time.Second), time.Second),
+		timeout:       max(configs.GetEnvDuration("DIAGNOSTICS_TIMEOUT", 3*time.Second), 100*time.Millisecond),
+		startedAt:     time.Now(),
+	}
+}
+
+// Run menjalankan semua pemeriksaan secara paralel dan menyusun laporan.
+func (s *Service) Run(ctx context.Context) models.SystemDiagnostics {
+	checks := []func(context.Context) models.DiagnosticCheck{
+		s.checkDatabase, s.checkMigrations, s.checkStorage, s.checkSMTP, s.checkClock, s.checkJobs,
+	}
+	results := make([]models.DiagnosticCheck, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+			results[i] = check(checkCtx)
+		}()
+	}
+	wg.Wait()
+
+	hostname, _ := os.Hostname()
+	now := time.Now()
+	report := models.SystemDiagnostics{
+		Status:        models.DiagnosticOK,
+		GeneratedAt:   now,
+		AppVersion:    buildinfo.AppVersion(),
+		GoVersion:     runtime.Version(),
+		Hostname:      hostname,
+		StartedAt:     s.startedAt,
+		UptimeSeconds: int64(now.Sub(s.startedAt) / time.Second),
+		SandboxMode:   s.sandbox,
+		Checks:        results,
+		Config:        configs.Effective(),
+	}
+	for _, c := range results {
+		if c.Status == models.DiagnosticFail {
+			report.Status = models.DiagnosticFail
+		} else if c.Status == models.DiagnosticWarn && report.Status == models.DiagnosticOK {
+			report.Status = models.DiagnosticWarn
+		}
+	}
+	return report
+}
+
+// SelfCheck menjalankan pemeriksaan saat startup dan menulis hasilnya ke log: satu baris per
+// pemeriksaan, warn/error untuk yang bermasalah. Dipanggil sebagai goroutine.
+func (s *Service) SelfCheck(ctx context.Context) {
+	report := s.Run(ctx)
+	for _, c := range report.Checks {
+		event := zlog.Info()
+		switch c.Status {
+		case models.DiagnosticWarn:
+			event = zlog.Warn()
+		case models.DiagnosticFail:
+			event = zlog.Error()
+		}
+		if c.LatencyMs != nil {
+			event = event.Int64("latency_ms", *c.LatencyMs)
+		}
+		event.Str("check", c.Name).Str("status", c.Status).Str("detail", c.Detail).Msg("Startup self-check")
+	}
+	zlog.Info().Str("status", report.Status).Str("app_version", report.AppVersion).Msg("Startup self-check finished (details: GET /api/v1/admin/system/diagnostics)")
+}
+
+func (s *Service) checkDatabase(ctx context.Context) models.DiagnosticCheck {
+	check := models.DiagnosticCheck{Name: "database"}
+	if s.pool == nil {
+		return skipped(check, "not using PostgreSQL (SANDBOX_MODE or DB_DRIVER=sqlite)")
+	}
+	start := time.Now()
+	err := s.pool.Ping(ctx)
+	check.LatencyMs = since(start)
+	if err != nil {
+		return failed(check, err)
+	}
+	stat := s.pool.Stat()
+	check.Status = models.DiagnosticOK
+	check.Detail = fmt.Sprintf("%d/%d connections in use", stat.AcquiredConns(), stat.MaxConns())
+	return check
+}
+
+func (s *Service) checkMigrations(ctx context.Context) models.DiagnosticCheck {
+	check := models.DiagnosticCheck{Name: "migrations"}
+	if s.pool == nil {
+		return skipped(check, "not using PostgreSQL (SANDBOX_MODE or DB_DRIVER=sqlite)")
+	}
+	applied, dirty, err := migrations.AppliedVersion(ctx, s.pool)
+	if err != nil {
+		return failed(check, err)
+	}
+	status := models.MigrationDiagnostics{AppliedVersion: applied, Dirty: dirty, Pending: []string{}}
+	check.Data = &status
+	files, err := migrations.Load(s.migrationsDir)
+	if err != nil {
+		check.Status = models.DiagnosticWarn
+		check.Detail = fmt.Sprintf("schema version %d; cannot compare with migration files: %v", applied, err)
+		return check
+	}
+	latest := files[len(files)-1].Version
+	status.LatestVersion = &latest
+	for _, f := range migrations.Pending(files, applied) {
+		status.Pending = append(status.Pending, f.Name)
+	}
+	switch {
+	case dirty:
+		check.Status = models.DiagnosticFail
+		check.Detail = fmt.Sprintf("schema version %d is dirty: a migration failed halfway", applied)
+	case len(status.Pending) > 0:
+		check.Status = models.DiagnosticWarn
+		check.Detail = fmt.Sprintf("schema version %d, %d migration(s) pending (latest %d)", applied, len(status.Pending), latest)
+	case applied > latest:
+		check.Status = models.DiagnosticWarn
+		check.Detail = fmt.Sprintf("schema version %d is newer than this build (latest %d)", applied, latest)
+	default:
+		check.Status = models.DiagnosticOK
+		check.Detail = fmt.Sprintf("schema version %d is up to date", applied)
+	}
+	return check
+}
+
+func (s *Service) checkStorage(ctx context.Context) models.DiagnosticCheck {
+	check := models.DiagnosticCheck{Name: "storage"}
+	if s.storage == nil {
+		return skipped(check, "no file storage configured")
+	}
+	start := time.Now()
+	r, err := s.storage.Open(ctx, probeKey)
+	check.LatencyMs = since(start)
+	if err == nil {
+		_ = r.Close()
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		check.Detail = s.storage.Backend() + ": "
+		return failed(check, err)
+	}
+	check.Status = models.DiagnosticOK
+	check.Detail = s.storage.Backend() + " reachable"
+	return check
+}
+
+func (s *Service) checkSMTP(ctx context.Context) models.DiagnosticCheck {
+	check := models.DiagnosticCheck{Name: "smtp"}
+	if s.smtpAddr == "" {
+		return skipped(check, "NOTIFY_SMTP_ADDR not set")
+	}
+	start := time.Now()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.smtpAddr)
+	if err != nil {
+		return failed(check, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	// Hanya membaca greeting 220 lalu QUIT; tidak ada login atau email yang dikirim.
+	text := textproto.NewConn(conn)
+	_, greeting, err := text.ReadResponse(220)
+	check.LatencyMs = since(start)
+	if err != nil {
+		return failed(check, err)
+	}
+	_ = text.PrintfLine("QUIT")
+	check.Status = models.DiagnosticOK
+	check.Detail = fmt.Sprintf("%s: %s", s.smtpAddr, greeting)
+	return check
+}
+
+func (s *Service) checkClock(ctx context.Context) models.DiagnosticCheck {
+	check := models.DiagnosticCheck{Name: "clock"}
+	if s.ntpServer == "" {
+		return skipped(check, "DIAGNOSTICS_NTP_SERVER=off")
+	}
+	start := time.Now()
+	serverTime, offset, err := queryNTP(ctx, s.ntpServer)
+	check.LatencyMs = since(start)
+	if err != nil {
+		check.Status = models.DiagnosticWarn // Jaringan keluar sering diblokir; jam belum tentu salah
+		check.Detail = fmt.Sprintf("cannot reach NTP server %s: %v", s.ntpServer, err)
+		return check
+	}
+	check.Data = models.ClockDiagnostics{
+		Server: s.ntpServer, OffsetMs: offset.Milliseconds(), ServerTime: serverTime, LocalTime: time.Now(),
+	}
+	check.Status = models.DiagnosticOK
+	check.Detail = fmt.Sprintf("clock offset %s", offset.Round(time.Millisecond))
+	if offset.Abs() > s.maxClockSkew {
+		// Token JWT, kode OTP, dan waktu check-in bergantung pada jam yang benar.
+		check.Status = models.DiagnosticWarn
+		check.Detail += fmt.Sprintf(" exceeds DIAGNOSTICS_MAX_CLOCK_SKEW (%s)", s.maxClockSkew)
+	}
+	return check
+}
+
+func (s *Service) checkJobs(ctx context.Context) models.DiagnosticCheck {
+	check := models.DiagnosticCheck{Name: "jobs"}
+	if s.scheduler == nil {
+		return skipped(check, "no job scheduler")
+	}
+	list, err := s.scheduler.Jobs(ctx)
+	if err != nil {
+		return failed(check, err)
+	}
+	backlog := models.JobBacklog{Jobs: len(list), Overdue: []string{}, Running: []string{}, Failing: []string{}}
+	cutoff := time.Now().Add(-2 * s.jobPoll)
+	for _, j := range list {
+		if !j.Registered {
+			continue // Dinonaktifkan di instance ini; jadwalnya memang tidak berjalan
+		}
+		if j.NextRunAt.Before(cutoff) {
+			backlog.Overdue = append(backlog.Overdue, j.Name)
+		}
+		if j.LastStatus == nil {
+			continue
+		}
+		switch *j.LastStatus {
+		case models.JobStatusRunning:
+			backlog.Running = append(backlog.Running, j.Name)
+		case models.JobStatusFailed:
+			backlog.Failing = append(backlog.Failing, j.Name)
+		}
+	}
+	check.Data = backlog
+	check.Status = models.DiagnosticOK
+	check.Detail = fmt.Sprintf("%d job(s), %d overdue, %d running, %d failing", backlog.Jobs, len(backlog.Overdue), len(backlog.Running), len(backlog.Failing))
+	if len(backlog.Overdue) > 0 || len(backlog.Failing) > 0 {
+		check.Status = models.DiagnosticWarn
+	}
+	return check
+}
+
+func skipped(check models.DiagnosticCheck, detail string) models.DiagnosticCheck {
+	check.Status = models.DiagnosticSkipped
+	check.Detail = detail
+	return check
+}
+
+func failed(check models.DiagnosticCheck, err error) models.DiagnosticCheck {
+	check.Status = models.DiagnosticFail
+	check.Detail += err.Error()
+	return check
+}
+
+func since(start time.Time) *int64 {
+	ms := time.Since(start).Milliseconds()
+	return &ms
+}
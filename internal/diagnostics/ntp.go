@@ -0,0 +1,65 @@
+// internal/diagnostics/ntp.go
+package diagnostics
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"time"
+)
+
+// ntpEpochOffset adalah selisih detik antara epoch NTP (1900) dan epoch Unix (1970).
+const ntpEpochOffset = 2208988800
+
+// queryNTP mengirim satu request SNTP (RFC 4330) ke server dan mengembalikan waktu server
+// serta offset jam lokal terhadapnya (positif = jam lokal tertinggal).
+func queryNTP(ctx context.Context, server string) (time.Time, time.Duration, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "udp", server)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := make([]byte, 48)
+	req[0] = 0x23 // LI 0, versi 4, mode 3 (client)
+	t1 := time.Now()
+	binary.BigEndian.PutUint64(req[40:], toNTP(t1)) // Transmit timestamp, dikembalikan server sebagai origin
+	if _, err := conn.Write(req); err != nil {
+		return time.Time{}, 0, err
+	}
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	t4 := time.Now()
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	if n < 48 {
+		return time.Time{}, 0, errors.New("short NTP response")
+	}
+	if mode := resp[0] & 0x07; mode != 4 {
+		return time.Time{}, 0, errors.New("unexpected NTP response mode")
+	}
+	if resp[1] == 0 {
+		return time.Time{}, 0, errors.New("NTP server sent kiss-o'-death (stratum 0)")
+	}
+	t2 := fromNTP(binary.BigEndian.Uint64(resp[32:])) // Receive timestamp
+	t3 := fromNTP(binary.BigEndian.Uint64(resp[40:])) // Transmit timestamp
+	offset := (t2.Sub(t1) + t3.Sub(t4)) / 2
+	return t3, offset, nil
+}
+
+func toNTP(t time.Time) uint64 {
+	secs := uint64(t.Unix() + ntpEpochOffset)
+	frac := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	return secs<<32 | frac
+}
+
+func fromNTP(v uint64) time.Time {
+	secs := int64(v>>32) - ntpEpochOffset
+	nanos := int64((v & 0xffffffff) * uint64(time.Second) >> 32)
+	return time.Unix(secs, nanos)
+}
@@ -0,0 +1,225 @@
+// internal/emailchange/emailchange.go
+
+// Package emailchange menjalankan alur ganti email oleh user sendiri: email baru baru dipakai
+// setelah user mengklik tautan konfirmasi yang dikirim ke alamat baru, dan alamat lama diberi
+// tahu saat permintaan dibuat maupun saat email benar-benar berganti.
+package emailchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/notify"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// sendTimeout membatasi pengiriman satu email.
+const sendTimeout = 30 * time.Second
+
+var (
+	// ErrSameEmail dikembalikan jika email baru sama dengan email saat ini.
+	ErrSameEmail = errors.New("new email is the same as the current email")
+	// ErrResendTooSoon dikembalikan jika tautan baru saja dikirim (lihat EMAIL_CHANGE_RESEND_INTERVAL).
+	ErrResendTooSoon = errors.New("confirmation email was sent recently")
+	// ErrInvalidLink dikembalikan untuk tautan konfirmasi yang tidak valid, kedaluwarsa, sudah
+	// dipakai, dibatalkan, atau terbit sebelum sesi user dicabut.
+	ErrInvalidLink = errors.New("invalid or expired email change link")
+)
+
+// Service mengelola permintaan ganti email.
+type Service struct {
+	users        repository.UserRepository
+	notifier     notify.Notifier
+	linkTemplate string
+	tokenTTL     time.Duration
+	resendAfter  time.Duration
+}
+
+// NewServiceFromEnv membuat Service berdasarkan environment variables. Mengembalikan nil jika
+// konfirmasi dimatikan; email lalu langsung berganti lewat PUT /user/profile seperti sebelumnya.
+//
+// Variabel Environment yang didukung:
+//   - EMAIL_CHANGE_CONFIRMATION: Wajibkan konfirmasi email baru. Default: true.
+//   - EMAIL_CHANGE_URL: URL frontend untuk tautan konfirmasi dengan placeholder {token}.
+//     Default: http://localhost:3000/confirm-email?token={token}.
+//   - EMAIL_CHANGE_TOKEN_TTL: Masa berlaku tautan. Default: 24h.
+//   - EMAIL_CHANGE_RESEND_INTERVAL: Jeda minimum antar pengiriman ulang. Default: 1m.
+func NewServiceFromEnv(users repository.UserRepository, notifier notify.Notifier) (*Service, error) {
+	if !strings.EqualFold(configs.GetEnv("EMAIL_CHANGE_CONFIRMATION", "true"), "true") {
+		zlog.Info().Msg("Email change confirmation disabled")
+		return nil, nil
+	}
+	if notifier == nil {
+		return nil, fmt.Errorf("email change confirmation requires a notifier (NOTIFY_PROVIDER)")
+	}
+	linkTemplate := configs.GetEnv("EMAIL_CHANGE_URL", "http://localhost:3000/confirm-email?token={token}")
+	if !strings.Contains(linkTemplate, "{token}") {
+		return nil, fmt.Errorf("EMAIL_CHANGE_URL must contain {token}")
+	}
+	s := &Service{
+		users:        users,
+		notifier:     notifier,
+		linkTemplate: linkTemplate,
+		tokenTTL:     configs.GetEnvDuration("EMAIL_CHANGE_TOKEN_TTL", 24*time.Hour),
+		resendAfter:  configs.GetEnvDuration("EMAIL_CHANGE_RESEND_INTERVAL", time.Minute),
+	}
+	if s.tokenTTL <= 0 {
+		return nil, fmt.Errorf("EMAIL_CHANGE_TOKEN_TTL must be positive")
+	}
+	if notifier.Provider() == "log" {
+		zlog.Warn().Msg("Email change confirmation links are not delivered with NOTIFY_PROVIDER=log (set NOTIFY_LOG_BODY=true in development to see them in the log)")
+	}
+	zlog.Info().Str("notifier", notifier.Provider()).Dur("token_ttl", s.tokenTTL).Msg("Email change confirmation enabled")
+	return s, nil
+}
+
+// Request membuat permintaan ganti email ke newEmail (menggantikan permintaan terbuka
+// sebelumnya), mengirim tautan konfirmasi ke alamat baru dan memberi tahu alamat lama.
+// repository.ErrEmailTaken jika email sudah dipakai user lain. Jika email konfirmasi gagal
+// terkirim, permintaan tetap tersimpan dan error dikembalikan (user bisa mengirim ulang).
+func (s *Service) Request(ctx context.Context, user *models.User, newEmail string) (*models.EmailChangeRequest, error) {
+	if strings.EqualFold(newEmail, user.Email) {
+		return nil, ErrSameEmail
+	}
+	request, err := s.users.CreateEmailChangeRequest(ctx, user.ID, newEmail, time.Now().Add(s.tokenTTL))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sendConfirmation(ctx, user, request); err != nil {
+		return request, err
+	}
+	if user.Email != "" {
+		s.notifyOldAddress(ctx, user, notify.Message{
+			Subject: "Email change requested",
+			Body: fmt.Sprintf("A request was made to change the email of your account %s to %s. The change only takes effect once it is confirmed from the new address. If this wasn't you, cancel it after logging in and change your password.",
+				user.Username, maskEmail(newEmail)),
+		})
+	}
+	return request, nil
+}
+
+// Pending mengembalikan permintaan ganti email terbuka milik user, atau pgx.ErrNoRows.
+func (s *Service) Pending(ctx context.Context, userID int) (*models.EmailChangeRequest, error) {
+	return s.users.GetPendingEmailChange(ctx, userID)
+}
+
+// Resend mengirim ulang tautan konfirmasi permintaan terbuka milik user dan memperpanjang masa
+// berlakunya. pgx.ErrNoRows jika tidak ada permintaan; ErrResendTooSoon jika terlalu cepat.
+func (s *Service) Resend(ctx context.Context, user *models.User) (*models.EmailChangeRequest, error) {
+	request, err := s.users.GetPendingEmailChange(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if now.Sub(request.SentAt) < s.resendAfter {
+		return request, ErrResendTooSoon
+	}
+	expiresAt := now.Add(s.tokenTTL)
+	if err := s.users.RenewEmailChangeRequest(ctx, request.ID, expiresAt); err != nil {
+		return nil, err
+	}
+	request.SentAt, request.ExpiresAt = now, expiresAt
+	return request, s.sendConfirmation(ctx, user, request)
+}
+
+// Cancel membatalkan permintaan ganti email terbuka milik user. pgx.ErrNoRows jika tidak ada.
+func (s *Service) Cancel(ctx context.Context, userID int) error {
+	return s.users.CancelEmailChange(ctx, userID)
+}
+
+// Confirm menerapkan permintaan dari token tautan konfirmasi dan memberi tahu alamat lama.
+// ErrInvalidLink jika tautan tidak berlaku lagi; repository.ErrEmailTaken jika email sudah
+// dipakai user lain sejak permintaan dibuat.
+func (s *Service) Confirm(ctx context.Context, token string) (*models.EmailChangeRequest, error) {
+	claims, requestID, err := utils.ValidateEmailChangeToken(token)
+	if err != nil {
+		zlog.Ctx(ctx).Warn().Err(err).Msg("Invalid email change token")
+		return nil, ErrInvalidLink
+	}
+	request, err := s.users.GetEmailChangeByID(ctx, requestID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidLink
+	}
+	if err != nil {
+		return nil, err
+	}
+	if request.UserID != claims.UserID || request.ConfirmedAt != nil || !time.Now().Before(request.ExpiresAt) {
+		return nil, ErrInvalidLink
+	}
+	user, err := s.users.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TokenVersion != claims.TokenVersion {
+		return nil, ErrInvalidLink
+	}
+	if err := s.users.ConfirmEmailChange(ctx, request.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidLink
+		}
+		return nil, err
+	}
+	now := time.Now()
+	request.ConfirmedAt = &now
+	if user.Email != "" {
+		s.notifyOldAddress(ctx, user, notify.Message{
+			Subject: "Your email address was changed",
+			Body: fmt.Sprintf("The email of your account %s was changed to %s. If this wasn't you, contact your administrator immediately.",
+				user.Username, maskEmail(request.NewEmail)),
+		})
+	}
+	return request, nil
+}
+
+// sendConfirmation mengirim tautan konfirmasi ke alamat baru.
+func (s *Service) sendConfirmation(ctx context.Context, user *models.User, request *models.EmailChangeRequest) error {
+	token, err := utils.GenerateEmailChangeToken(request.ID, user.ID, user.TokenVersion, request.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	link := strings.ReplaceAll(s.linkTemplate, "{token}", url.QueryEscape(token))
+	msg := notify.Message{
+		To:      request.NewEmail,
+		Topic:   notify.TopicEmailChangeConfirm,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Confirm that %s is the new email of your account %s by opening this link before %s:\n%s\nIf you didn't request this, ignore this email.",
+			request.NewEmail, user.Username, request.ExpiresAt.UTC().Format(time.RFC1123), link),
+		Data: map[string]any{"user_id": user.ID, "email_change_id": request.ID},
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	if err := s.notifier.Notify(sendCtx, msg); err != nil {
+		return fmt.Errorf("error sending email change confirmation via %s: %w", s.notifier.Provider(), err)
+	}
+	return nil
+}
+
+// notifyOldAddress memberi tahu alamat email lama; kegagalannya hanya dicatat.
+func (s *Service) notifyOldAddress(ctx context.Context, user *models.User, msg notify.Message) {
+	msg.To = user.Email
+	msg.Topic = notify.TopicEmailChangeNotice
+	msg.Data = map[string]any{"user_id": user.ID}
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	if err := s.notifier.Notify(sendCtx, msg); err != nil {
+		zlog.Ctx(ctx).Error().Err(err).Int("user_id", user.ID).Str("notifier", s.notifier.Provider()).Msg("Failed to notify previous email address about email change")
+	}
+}
+
+// maskEmail menyamarkan bagian lokal email (mis. j***@example.com) untuk email ke alamat lama.
+func maskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+	return local[:1] + "***@" + domain
+}
@@ -0,0 +1,268 @@
+// internal/errorreport/errorreport.go
+
+// Package errorreport mengirim panic yang tertangkap middleware Recover ke layanan error
+// tracking yang kompatibel dengan Sentry (Sentry, Glitchtip) lewat store API-nya, lengkap
+// dengan stack trace, request ID, dan user ID agar bisa dicocokkan dengan log.
+package errorreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/buildinfo"
+	"github.com/rakaarfi/attendance-system-be/internal/resilience"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// modulePath menandai frame milik aplikasi (in_app) di stack trace.
+const modulePath = "github.com/rakaarfi/attendance-system-be/"
+
+// Frame adalah satu frame stack trace.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Event adalah satu panic yang tertangkap saat memproses request.
+type Event struct {
+	Message   string  // Nilai panic (fmt.Sprint)
+	Frames    []Frame // Stack trace, frame terdalam lebih dulu (lihat Callers)
+	RequestID string
+	UserID    int // 0 = request tanpa login
+	Method    string
+	Route     string // Pola route yang cocok, mis. /api/v1/admin/users/:userId
+	Path      string
+	Time      time.Time
+}
+
+// Reporter mengirim Event ke layanan error tracking. Report tidak memblokir request:
+// pengiriman berjalan di background dan kegagalannya hanya dicatat ke log.
+type Reporter interface {
+	Report(ev Event)
+	Provider() string
+}
+
+// Callers mengembalikan stack trace goroutine pemanggil tanpa skip frame teratas
+// (0 = pemanggil Callers). Dipanggil dari fungsi defer yang melakukan recover(), stack
+// masih berisi frame tempat panic terjadi.
+func Callers(skip int) []Frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var out []Frame
+	for {
+		frame, more := frames.Next()
+		out = append(out, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+// NewReporterFromEnv membuat Reporter berdasarkan environment variables.
+// Mengembalikan nil jika SENTRY_DSN kosong (panic hanya dicatat ke log).
+//
+// Variabel Environment yang didukung:
+//   - SENTRY_DSN: DSN project Sentry/Glitchtip, mis. https://<key>@glitchtip.example.com/1.
+//   - SENTRY_ENVIRONMENT: Nama environment di event. Default: APP_ENV (development).
+//   - SENTRY_TIMEOUT: Batas waktu pengiriman satu event. Default: 5s.
+//   - SENTRY_MAX_PENDING: Jumlah event yang boleh dikirim bersamaan; event lain dibuang
+//     (tetap tercatat di log) agar panic beruntun tidak menumpuk goroutine. Default: 10.
+//
+// Pengiriman tidak dicoba ulang; circuit breaker (resilience.Get("errorreport.sentry"))
+// melewati layanan yang sedang mati.
+func NewReporterFromEnv() (Reporter, error) {
+	dsn := configs.GetEnv("SENTRY_DSN", "")
+	if dsn == "" {
+		return nil, nil
+	}
+	endpoint, key, err := parseDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SENTRY_DSN: %w", err)
+	}
+	maxPending := configs.GetEnvInt("SENTRY_MAX_PENDING", 10)
+	if maxPending < 1 {
+		return nil, fmt.Errorf("SENTRY_MAX_PENDING must be at least 1")
+	}
+	r := &sentryReporter{
+		endpoint:    endpoint,
+		key:         key,
+		environment: configs.GetEnv("SENTRY_ENVIRONMENT", strings.ToLower(configs.GetEnv("APP_ENV", configs.EnvDevelopment))),
+		release:     buildinfo.AppVersion(),
+		client:      &http.Client{},
+		breaker:     resilience.Get("errorreport.sentry"),
+		policy:      resilience.Policy{Timeout: configs.GetEnvDuration("SENTRY_TIMEOUT", 5*time.Second), MaxAttempts: 1},
+		pending:     make(chan struct{}, maxPending),
+	}
+	zlog.Info().Str("endpoint", endpoint).Str("environment", r.environment).Msg("Panic reporting to Sentry enabled")
+	return r, nil
+}
+
+// parseDSN mengubah DSN (scheme://key@host[/path]/project_id) menjadi URL store API dan public key.
+func parseDSN(dsn string) (endpoint, key string, err error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", fmt.Errorf("scheme must be http or https")
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", fmt.Errorf("missing public key")
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	idx := strings.LastIndex(path, "/")
+	if idx < 0 || path[idx+1:] == "" {
+		return "", "", fmt.Errorf("missing project ID")
+	}
+	prefix, projectID := path[:idx], path[idx+1:]
+	return fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, url.PathEscape(projectID)), u.User.Username(), nil
+}
+
+// sentryReporter mengirim event lewat store API Sentry (juga didukung Glitchtip).
+type sentryReporter struct {
+	endpoint    string
+	key         string
+	environment string
+	release     string
+	client      *http.Client
+	breaker     *resilience.Breaker
+	policy      resilience.Policy
+	pending     chan struct{} // Semaphore pengiriman yang sedang berjalan
+}
+
+func (r *sentryReporter) Provider() string {
+	return "sentry"
+}
+
+func (r *sentryReporter) Report(ev Event) {
+	select {
+	case r.pending <- struct{}{}:
+	default:
+		zlog.Warn().Str("request_id", ev.RequestID).Msg("Too many pending Sentry events, panic report dropped")
+		return
+	}
+	go func() {
+		defer func() { <-r.pending }()
+		eventID, err := r.send(context.Background(), ev)
+		if err != nil {
+			zlog.Error().Err(err).Str("request_id", ev.RequestID).Msg("Failed to report panic to Sentry")
+			return
+		}
+		zlog.Info().Str("request_id", ev.RequestID).Str("sentry_event_id", eventID).Msg("Panic reported to Sentry")
+	}()
+}
+
+// sentryFrame dan sentryEvent adalah subset payload event Sentry yang diisi.
+type sentryFrame struct {
+	Function string `json:"function"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Logger      string            `json:"logger"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Transaction string            `json:"transaction,omitempty"`
+	Tags        map[string]string `json:"tags"`
+	User        *sentryUser       `json:"user,omitempty"`
+	Request     sentryRequest     `json:"request"`
+	Exception   struct {
+		Values []sentryException `json:"values"`
+	} `json:"exception"`
+}
+
+type sentryUser struct {
+	ID string `json:"id"`
+}
+
+type sentryRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type sentryException struct {
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	Stacktrace struct {
+		Frames []sentryFrame `json:"frames"`
+	} `json:"stacktrace"`
+}
+
+// buildEvent menyusun payload Sentry. Sentry mengharapkan frame terluar lebih dulu.
+func (r *sentryReporter) buildEvent(eventID string, ev Event) sentryEvent {
+	out := sentryEvent{
+		EventID: eventID, Timestamp: ev.Time.UTC().Format(time.RFC3339Nano), Level: "fatal",
+		Platform: "go", Logger: "recover", Environment: r.environment, Release: r.release,
+		Transaction: ev.Method + " " + ev.Route,
+		Tags:        map[string]string{"request_id": ev.RequestID},
+		Request:     sentryRequest{Method: ev.Method, URL: ev.Path},
+	}
+	if ev.UserID != 0 {
+		out.User = &sentryUser{ID: fmt.Sprint(ev.UserID)}
+	}
+	exception := sentryException{Type: "panic", Value: ev.Message}
+	for i := len(ev.Frames) - 1; i >= 0; i-- {
+		f := ev.Frames[i]
+		exception.Stacktrace.Frames = append(exception.Stacktrace.Frames, sentryFrame{
+			Function: f.Function, Filename: f.File, AbsPath: f.File, Lineno: f.Line,
+			InApp: strings.HasPrefix(f.Function, modulePath),
+		})
+	}
+	out.Exception.Values = []sentryException{exception}
+	return out
+}
+
+// send mengirim satu event dan mengembalikan event ID-nya.
+func (r *sentryReporter) send(ctx context.Context, ev Event) (string, error) {
+	var raw [16]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", fmt.Errorf("error generating event ID: %w", err)
+	}
+	eventID := hex.EncodeToString(raw[:])
+	payload, err := json.Marshal(r.buildEvent(eventID, ev))
+	if err != nil {
+		return "", fmt.Errorf("error encoding sentry event: %w", err)
+	}
+	auth := fmt.Sprintf("Sentry sentry_version=7, sentry_client=attendance-system-be/%s, sentry_key=%s", r.release, r.key)
+	err = resilience.Do(ctx, r.breaker, r.policy, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return resilience.Permanent(fmt.Errorf("error building sentry request: %w", err))
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Sentry-Auth", auth)
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("error calling sentry: %w", err)
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return resilience.HTTPStatusError(fmt.Errorf("sentry returned status %d", resp.StatusCode), resp.StatusCode)
+		}
+		return nil
+	})
+	return eventID, err
+}
@@ -0,0 +1,168 @@
+// internal/events/events.go
+
+// Package events adalah bus domain event aplikasi. Handler dan job mem-publish event
+// (mis. AttendanceCheckedIn, ScheduleCreated) tanpa mengetahui siapa yang memprosesnya;
+// subsistem audit log, notifikasi, dan webhook berlangganan (lihat events/subscribers).
+// Implementasi saat ini in-process; adapter broker (NATS/Kafka) cukup memenuhi Bus.
+package events
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Nama event domain. Event yang namanya sama dengan aksi audit (models.Audit*) dicatat ke
+// audit log oleh subscriber audit (lihat Audited).
+const (
+	AttendanceCheckedIn  = "attendance.checked_in"
+	AttendanceCheckedOut = "attendance.checked_out"
+	// Check-in yang ditampung saat mode degraded/maintenance gagal dicatat (dead letter).
+	QueuedCheckInFailed = "attendance.queued_check_in_failed"
+	AttendanceSignedOff = models.AuditAttendanceSigned
+	RouteViewed         = models.AuditRouteViewed // Subjek = karyawan pemilik rute; pelaku = atasan
+	DisputeOpened       = "attendance.dispute_opened"
+	DisputeResolved     = models.AuditDisputeResolved
+	DelegationCreated   = models.AuditDelegationCreated
+	DelegationRevoked   = models.AuditDelegationRevoked
+	ApprovalEscalated   = "approval.escalated" // Subjek = pengaju item (mis. pemilik dispute)
+
+	// Audit jadwal ditulis trigger database (audit_log), bukan subscriber.
+	ScheduleCreated = models.AuditScheduleCreated
+	ScheduleUpdated = models.AuditScheduleUpdated
+	ScheduleDeleted = models.AuditScheduleDeleted
+
+	UserDeactivated      = "user.deactivated"
+	ProfileUpdated       = models.AuditProfileUpdated
+	AccessUpdated        = models.AuditAccessUpdated
+	EmploymentUpdated    = models.AuditEmploymentUpdated
+	WorkModesUpdated     = models.AuditWorkModesUpdated
+	EmailChangeRequested = models.AuditEmailChangeRequested
+	EmailChanged         = models.AuditEmailChanged
+	UsernameRequested    = models.AuditUsernameRequested
+	UsernameReviewed     = models.AuditUsernameReviewed
+	PhoneVerified        = models.AuditPhoneVerified
+	// profile.username_changed ditulis trigger database (audit_log) pada semua jalur rename.
+	UsernameChanged = models.AuditUsernameChanged
+	RoleChanged     = models.AuditRoleChanged
+
+	// Subjek undangan dibuat/dicabut adalah admin pelakunya (akun penerima belum ada).
+	InvitationCreated  = models.AuditInvitationCreated
+	InvitationRevoked  = models.AuditInvitationRevoked
+	InvitationAccepted = models.AuditInvitationAccepted
+	UserCreated        = models.AuditUserCreated // Subjek = akun baru; pelaku = admin
+
+	LoginSucceeded  = models.AuditLoginSucceeded
+	LoginFailed     = models.AuditLoginFailed
+	LoginRejected   = models.AuditLoginRejected
+	LoginDenied     = models.AuditLoginDenied
+	PasswordChanged = models.AuditPasswordChanged
+	PasswordReset   = models.AuditPasswordReset
+
+	ScopedTokenIssued = models.AuditScopedTokenIssued
+	KioskEnrolled     = models.AuditKioskEnrolled
+	KioskRevoked      = models.AuditKioskRevoked
+	SessionsRevoked   = models.AuditSessionsRevoked
+	SMSPreferences    = models.AuditSMSPreferences
+
+	PayrollClosed   = models.AuditPayrollClosed
+	PayrollReopened = models.AuditPayrollReopened
+
+	VerificationLinkCreated = models.AuditVerificationLinkCreated
+	VerificationLinkRevoked = models.AuditVerificationLinkRevoked
+	VerificationAccessed    = models.AuditVerificationAccessed
+)
+
+// Audited adalah event yang dicatat ke audit_log oleh subscriber audit.
+var Audited = []string{
+	AttendanceSignedOff, RouteViewed, DisputeResolved, DelegationCreated, DelegationRevoked,
+	ProfileUpdated, AccessUpdated, EmploymentUpdated, WorkModesUpdated, RoleChanged, EmailChangeRequested, EmailChanged,
+	UsernameRequested, UsernameReviewed, PhoneVerified, InvitationCreated, InvitationRevoked, InvitationAccepted, UserCreated,
+	LoginSucceeded, LoginFailed, LoginRejected, LoginDenied, PasswordChanged, PasswordReset,
+	ScopedTokenIssued, KioskEnrolled, KioskRevoked, SessionsRevoked, SMSPreferences,
+	PayrollClosed, PayrollReopened,
+	VerificationLinkCreated, VerificationLinkRevoked, VerificationAccessed,
+}
+
+// Event adalah satu kejadian domain. UserID adalah subjek (user yang terdampak),
+// ActorUserID pelakunya (nil = sistem). Data berisi detail yang aman untuk disimpan
+// di audit log dan dikirim ke webhook (tanpa password/token).
+type Event struct {
+	Name        string         `json:"name"`
+	UserID      int            `json:"user_id"`
+	ActorUserID *int           `json:"actor_user_id,omitempty"`
+	OccurredAt  time.Time      `json:"occurred_at"`
+	Data        map[string]any `json:"data,omitempty"`
+}
+
+// Handler memproses satu event. Error hanya dicatat; tidak menggagalkan publisher.
+type Handler func(ctx context.Context, ev Event) error
+
+// Publisher mem-publish event ke semua subscriber yang cocok.
+type Publisher interface {
+	Publish(ctx context.Context, ev Event)
+}
+
+// Bus adalah Publisher yang juga menerima subscriber.
+type Bus interface {
+	Publisher
+	// Subscribe mendaftarkan handler untuk event bernama names (kosong = semua event).
+	Subscribe(name string, handler Handler, names ...string)
+}
+
+type subscription struct {
+	name    string // Nama subscriber untuk log
+	handler Handler
+	names   []string
+}
+
+// InProcessBus mengirim event secara sinkron ke subscriber dalam proses yang sama, berurutan
+// sesuai pendaftaran. Subscriber yang melakukan I/O lambat (email, push, webhook) menjalankan
+// pengirimannya sendiri di background agar request tidak tertahan.
+type InProcessBus struct {
+	mu   sync.RWMutex
+	subs []subscription
+}
+
+func NewInProcessBus() *InProcessBus {
+	return &InProcessBus{}
+}
+
+func (b *InProcessBus) Subscribe(name string, handler Handler, names ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, subscription{name: name, handler: handler, names: names})
+}
+
+func (b *InProcessBus) Publish(ctx context.Context, ev Event) {
+	if ev.OccurredAt.IsZero() {
+		ev.OccurredAt = time.Now()
+	}
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+	for _, s := range subs {
+		if len(s.names) > 0 && !slices.Contains(s.names, ev.Name) {
+			continue
+		}
+		if err := dispatch(ctx, s, ev); err != nil {
+			zlog.Ctx(ctx).Error().Err(err).Str("event", ev.Name).Str("subscriber", s.name).Msg("Event subscriber failed")
+		}
+	}
+}
+
+// dispatch memanggil handler dan mengubah panic menjadi error agar satu subscriber yang
+// bermasalah tidak menjatuhkan request maupun subscriber lain.
+func dispatch(ctx context.Context, s subscription, ev Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in subscriber: %v", r)
+		}
+	}()
+	return s.handler(ctx, ev)
+}
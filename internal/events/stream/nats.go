@@ -0,0 +1,187 @@
+// internal/events/stream/nats.go
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATSBroker mengirim event ke NATS dengan protokol teks NATS di atas TCP (tanpa library
+// client). Setiap publish diikuti PING dan menunggu PONG, sehingga event dianggap terkirim
+// hanya setelah server memprosesnya. Jika server mendukung header, ID outbox dikirim sebagai
+// Nats-Msg-Id agar JetStream dapat membuang duplikat saat outbox mengirim ulang.
+// TLS tidak didukung; gunakan jaringan privat atau sidecar TLS.
+type NATSBroker struct {
+	addr    string
+	user    string
+	pass    string
+	token   string
+	prefix  string
+	timeout time.Duration
+
+	mu      sync.Mutex // Satu koneksi dipakai bergantian
+	conn    net.Conn
+	reader  *bufio.Reader
+	headers bool // Server mendukung HPUB
+}
+
+// NewNATSBroker membuat broker untuk server rawURL (nats://[user:pass@|token@]host[:port]).
+// Koneksi dibuka saat publish pertama dan dibuka ulang setelah error.
+func NewNATSBroker(rawURL, subjectPrefix string, timeout time.Duration) (*NATSBroker, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid EVENTS_STREAM_NATS_URL %q", rawURL)
+	}
+	if u.Scheme != "nats" {
+		return nil, fmt.Errorf("unsupported NATS URL scheme %q (only nats:// is supported)", u.Scheme)
+	}
+	b := &NATSBroker{addr: u.Host, prefix: subjectPrefix, timeout: timeout}
+	if u.Port() == "" {
+		b.addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			b.user, b.pass = u.User.Username(), pass
+		} else {
+			b.token = u.User.Username()
+		}
+	}
+	return b, nil
+}
+
+func (b *NATSBroker) Provider() string { return "nats" }
+
+func (b *NATSBroker) Publish(ctx context.Context, msg Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn == nil {
+		if err := b.connect(ctx); err != nil {
+			return err
+		}
+	}
+	if err := b.publish(ctx, msg); err != nil {
+		b.close()
+		return err
+	}
+	return nil
+}
+
+// connect membuka koneksi, membaca INFO, mengirim CONNECT, dan memastikan handshake diterima.
+func (b *NATSBroker) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: b.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", b.addr)
+	if err != nil {
+		return fmt.Errorf("error connecting to NATS %s: %w", b.addr, err)
+	}
+	b.conn, b.reader = conn, bufio.NewReader(conn)
+	b.setDeadline(ctx)
+
+	line, err := b.readLine()
+	if err != nil {
+		b.close()
+		return fmt.Errorf("error reading NATS INFO: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		b.close()
+		return fmt.Errorf("unexpected NATS greeting %q", line)
+	}
+	var info struct {
+		Headers bool `json:"headers"`
+	}
+	_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info)
+	b.headers = info.Headers
+
+	options := map[string]any{
+		"verbose": false, "pedantic": false, "headers": b.headers,
+		"name": "attendance-system-be", "lang": "go", "version": "1.0.0",
+	}
+	if b.user != "" {
+		options["user"], options["pass"] = b.user, b.pass
+	}
+	if b.token != "" {
+		options["auth_token"] = b.token
+	}
+	connect, _ := json.Marshal(options)
+	if _, err := fmt.Fprintf(b.conn, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		b.close()
+		return fmt.Errorf("error sending NATS CONNECT: %w", err)
+	}
+	if err := b.waitPong(); err != nil {
+		b.close()
+		return fmt.Errorf("NATS handshake failed: %w", err)
+	}
+	return nil
+}
+
+func (b *NATSBroker) publish(ctx context.Context, msg Message) error {
+	b.setDeadline(ctx)
+	subject := b.prefix + msg.Name
+	var err error
+	if b.headers && msg.ID != "" {
+		header := "NATS/1.0\r\nNats-Msg-Id: " + msg.ID + "\r\n\r\n"
+		_, err = fmt.Fprintf(b.conn, "HPUB %s %d %d\r\n%s%s\r\nPING\r\n", subject, len(header), len(header)+len(msg.Payload), header, msg.Payload)
+	} else {
+		_, err = fmt.Fprintf(b.conn, "PUB %s %d\r\n%s\r\nPING\r\n", subject, len(msg.Payload), msg.Payload)
+	}
+	if err != nil {
+		return fmt.Errorf("error publishing to NATS subject %s: %w", subject, err)
+	}
+	if err := b.waitPong(); err != nil {
+		return fmt.Errorf("NATS did not confirm subject %s: %w", subject, err)
+	}
+	return nil
+}
+
+// waitPong membaca balasan server sampai PONG, menjawab PING dari server dan
+// mengembalikan error untuk -ERR.
+func (b *NATSBroker) waitPong() error {
+	for {
+		line, err := b.readLine()
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := b.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		// +OK dan INFO susulan (perubahan cluster) diabaikan.
+	}
+}
+
+func (b *NATSBroker) readLine() (string, error) {
+	line, err := b.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// setDeadline membatasi I/O dengan timeout broker atau deadline ctx, mana yang lebih cepat.
+func (b *NATSBroker) setDeadline(ctx context.Context) {
+	deadline := time.Now().Add(b.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = b.conn.SetDeadline(deadline)
+}
+
+func (b *NATSBroker) close() {
+	if b.conn != nil {
+		_ = b.conn.Close()
+	}
+	b.conn, b.reader = nil, nil
+}
@@ -0,0 +1,104 @@
+// internal/events/stream/stream.go
+
+// Package stream meneruskan domain event ke broker streaming (NATS atau Kafka) agar sistem
+// hilir (analitik, payroll) bisa mengonsumsi event absensi. Event tidak dikirim langsung dari
+// request: Stream didaftarkan sebagai tujuan outbox (lihat internal/outbox), sehingga event
+// disimpan ke outbox_messages lalu dikirim lewat Stream.Deliver dengan jaminan at-least-once.
+package stream
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Message adalah satu event yang dikirim ke broker.
+type Message struct {
+	ID      string // ID outbox; dipakai konsumen/broker untuk deduplikasi
+	Name    string // Nama event, mis. attendance.checked_in
+	Key     string // Key partisi (user ID subjek event), kosong jika tanpa subjek
+	Payload []byte // events.Event dalam JSON
+}
+
+// Broker adalah adapter pengiriman ke satu jenis broker.
+type Broker interface {
+	Publish(ctx context.Context, msg Message) error
+	Provider() string
+}
+
+// Stream adalah broker yang dikonfigurasi beserta filter event yang diteruskan.
+type Stream struct {
+	broker Broker
+	names  []string
+}
+
+// NewStream membungkus broker; names kosong berarti semua event diteruskan.
+func NewStream(broker Broker, names []string) *Stream {
+	return &Stream{broker: broker, names: names}
+}
+
+// NewFromEnv membuat Stream berdasarkan environment variables.
+// Mengembalikan nil jika EVENTS_STREAM_PROVIDER tidak di-set.
+//
+// Variabel Environment yang didukung:
+//   - EVENTS_STREAM_PROVIDER: "nats" atau "kafka". Kosong menonaktifkan streaming.
+//   - EVENTS_STREAM_EVENTS: Daftar nama event dipisah koma. Default: semua event.
+//   - EVENTS_STREAM_TIMEOUT: Timeout koneksi/pengiriman ke broker. Default: 10s.
+//   - EVENTS_STREAM_NATS_URL: Alamat server NATS (nats://[user:pass@|token@]host:port). Default: nats://localhost:4222.
+//   - EVENTS_STREAM_NATS_SUBJECT_PREFIX: Prefix subject NATS, subject = prefix + nama event. Default: "events.".
+//   - EVENTS_STREAM_KAFKA_REST_URL: Base URL Kafka REST Proxy (API v2), wajib untuk provider kafka.
+//   - EVENTS_STREAM_KAFKA_TOPIC: Topic Kafka tujuan. Default: attendance-events.
+func NewFromEnv() (*Stream, error) {
+	provider := strings.ToLower(configs.GetEnv("EVENTS_STREAM_PROVIDER", ""))
+	timeout := configs.GetEnvDuration("EVENTS_STREAM_TIMEOUT", 10*time.Second)
+	var broker Broker
+	switch provider {
+	case "":
+		return nil, nil
+	case "nats":
+		b, err := NewNATSBroker(
+			configs.GetEnv("EVENTS_STREAM_NATS_URL", "nats://localhost:4222"),
+			configs.GetEnv("EVENTS_STREAM_NATS_SUBJECT_PREFIX", "events."),
+			timeout,
+		)
+		if err != nil {
+			return nil, err
+		}
+		broker = b
+	case "kafka":
+		restURL := configs.GetEnv("EVENTS_STREAM_KAFKA_REST_URL", "")
+		if restURL == "" {
+			return nil, fmt.Errorf("EVENTS_STREAM_KAFKA_REST_URL is required for EVENTS_STREAM_PROVIDER=kafka")
+		}
+		broker = NewKafkaRESTBroker(restURL, configs.GetEnv("EVENTS_STREAM_KAFKA_TOPIC", "attendance-events"), timeout)
+	default:
+		return nil, fmt.Errorf("unsupported EVENTS_STREAM_PROVIDER %q (use nats or kafka)", provider)
+	}
+	s := NewStream(broker, configs.GetEnvList("EVENTS_STREAM_EVENTS", nil))
+	zlog.Info().Str("provider", broker.Provider()).Strs("events", s.names).Msg("Event streaming enabled")
+	return s, nil
+}
+
+// Events mengembalikan nama event yang diteruskan (kosong = semua), untuk Dispatcher.Register.
+func (s *Stream) Events() []string {
+	return s.names
+}
+
+func (s *Stream) Provider() string {
+	return s.broker.Provider()
+}
+
+// Deliver mengirim satu pesan outbox ke broker; ID outbox dipakai untuk deduplikasi.
+func (s *Stream) Deliver(ctx context.Context, outboxMsg models.OutboxMessage) error {
+	msg := Message{ID: strconv.FormatInt(outboxMsg.ID, 10), Name: outboxMsg.EventName, Payload: outboxMsg.Payload}
+	if outboxMsg.UserID != nil {
+		msg.Key = strconv.Itoa(*outboxMsg.UserID)
+	}
+	return s.broker.Publish(ctx, msg)
+}
@@ -0,0 +1,22 @@
+// internal/events/subscribers/audit.go
+
+// Package subscribers berisi subsistem yang mengonsumsi domain event dari events.Bus:
+// audit log, notifikasi user/HR, dan webhook keluar.
+package subscribers
+
+import (
+	"context"
+
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// Audit mencatat event ke audit_log dengan aksi = nama event. Didaftarkan untuk events.Audited.
+func Audit(repo repository.AuditRepository) events.Handler {
+	return func(ctx context.Context, ev events.Event) error {
+		return repo.CreateAuditEntry(ctx, &models.AuditEntry{
+			UserID: ev.UserID, ActorUserID: ev.ActorUserID, Action: ev.Name, Details: ev.Data,
+		})
+	}
+}
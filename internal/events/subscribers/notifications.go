@@ -0,0 +1,290 @@
+// internal/events/subscribers/notifications.go
+package subscribers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/inbox"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/notify"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// notifyTimeout membatasi satu pengiriman email/webhook notifikasi.
+const notifyTimeout = 30 * time.Second
+
+// NotificationEvents adalah event yang memicu notifikasi ke user atau HR.
+var NotificationEvents = []string{
+	events.ScheduleCreated, events.ScheduleUpdated, events.ScheduleDeleted,
+	events.DisputeOpened, events.DisputeResolved, events.ApprovalEscalated,
+	events.SessionsRevoked, events.UsernameReviewed, events.QueuedCheckInFailed,
+}
+
+// Notifications menerjemahkan event menjadi notifikasi: inbox in-app + push (inbox.Dispatcher)
+// untuk user, dan email/webhook (notify.Notifier) untuk atasan, karyawan, atau HR.
+// Dijalankan oleh dispatcher outbox: pengiriman sinkron dan error dikembalikan agar dicoba
+// lagi (at-least-once; notifikasi bisa terkirim ganda jika sebagian kanal gagal).
+type Notifications struct {
+	users    repository.UserRepository
+	inbox    *inbox.Dispatcher // nil = tanpa inbox/push
+	notifier notify.Notifier   // nil = tanpa email/webhook
+}
+
+func NewNotifications(users repository.UserRepository, inboxDispatcher *inbox.Dispatcher, notifier notify.Notifier) *Notifications {
+	return &Notifications{users: users, inbox: inboxDispatcher, notifier: notifier}
+}
+
+// Handle memproses satu event dari NotificationEvents.
+func (n *Notifications) Handle(ctx context.Context, ev events.Event) error {
+	switch ev.Name {
+	case events.ScheduleCreated, events.ScheduleUpdated, events.ScheduleDeleted:
+		return n.scheduleChanged(ctx, ev)
+	case events.DisputeOpened:
+		return n.disputeOpened(ctx, ev)
+	case events.DisputeResolved:
+		return n.disputeResolved(ctx, ev)
+	case events.ApprovalEscalated:
+		return n.approvalEscalated(ctx, ev)
+	case events.SessionsRevoked:
+		return n.sessionsRevoked(ctx, ev)
+	case events.UsernameReviewed:
+		return n.usernameReviewed(ctx, ev)
+	case events.QueuedCheckInFailed:
+		return n.queuedCheckInFailed(ctx, ev)
+	}
+	return nil
+}
+
+// scheduleChanged memberi tahu pemilik jadwal, dan pemilik lama jika jadwal dipindahkan.
+func (n *Notifications) scheduleChanged(ctx context.Context, ev events.Event) error {
+	action := map[string]string{
+		events.ScheduleCreated: "created", events.ScheduleUpdated: "updated", events.ScheduleDeleted: "removed",
+	}[ev.Name]
+	date := fmt.Sprint(ev.Data["date"])
+	deliver := func(userID int, action string) error {
+		return n.inbox.Deliver(ctx, &models.Notification{
+			UserID: userID, Type: models.NotificationScheduleChanged,
+			Title: "Schedule " + action,
+			Body:  fmt.Sprintf("Your schedule on %s was %s", date, action),
+			Data:  map[string]string{"schedule_id": fmt.Sprint(ev.Data["schedule_id"]), "date": date, "action": action},
+		})
+	}
+	err := deliver(ev.UserID, action)
+	// Payload outbox di-decode dari JSON, sehingga angka menjadi float64.
+	if previous, ok := intValue(ev.Data["previous_user_id"]); ok && previous != ev.UserID {
+		err = errors.Join(err, deliver(previous, "removed"))
+	}
+	return err
+}
+
+// disputeOpened memberi tahu atasan langsung pemilik dispute, atau HR jika user tidak punya atasan.
+func (n *Notifications) disputeOpened(ctx context.Context, ev events.Event) error {
+	msg := notify.Message{
+		Topic:   notify.TopicDisputeOpened,
+		Subject: "Attendance record disputed",
+		Body:    fmt.Sprintf("User %d disputed attendance record %v: %v", ev.UserID, ev.Data["attendance_id"], ev.Data["comment"]),
+		Data:    map[string]any{"dispute_id": ev.Data["dispute_id"], "attendance_id": ev.Data["attendance_id"], "user_id": ev.UserID},
+	}
+	user, err := n.users.GetUserByID(ctx, ev.UserID)
+	if err != nil {
+		return fmt.Errorf("error loading user %d for dispute notification: %w", ev.UserID, err)
+	}
+	var inboxErr error
+	if user.ManagerID != nil {
+		manager, err := n.users.GetUserByID(ctx, *user.ManagerID)
+		if err != nil {
+			return fmt.Errorf("error loading manager %d for dispute notification: %w", *user.ManagerID, err)
+		}
+		msg.To = manager.Email
+		msg.Data["manager_id"] = manager.ID
+		inboxErr = n.inbox.Deliver(ctx, &models.Notification{
+			UserID: manager.ID, Type: models.NotificationDisputeOpened,
+			Title: msg.Subject, Body: msg.Body,
+			Data: map[string]string{
+				"dispute_id": fmt.Sprint(ev.Data["dispute_id"]), "attendance_id": fmt.Sprint(ev.Data["attendance_id"]), "user_id": fmt.Sprint(ev.UserID),
+			},
+		})
+	}
+	return errors.Join(inboxErr, n.send(ctx, msg))
+}
+
+// disputeResolved memberi tahu karyawan pemilik dispute lewat email dan inbox.
+func (n *Notifications) disputeResolved(ctx context.Context, ev events.Event) error {
+	status := fmt.Sprint(ev.Data["status"])
+	msg := notify.Message{
+		Topic:   notify.TopicDisputeResolved,
+		Subject: "Your attendance dispute was " + status,
+		Body:    fmt.Sprintf("Your dispute of attendance record %v was %s: %v", ev.Data["attendance_id"], status, ev.Data["note"]),
+		Data:    map[string]any{"dispute_id": ev.Data["dispute_id"], "attendance_id": ev.Data["attendance_id"], "user_id": ev.UserID, "status": status},
+	}
+	var emailErr error
+	if user, err := n.users.GetUserByID(ctx, ev.UserID); err != nil {
+		return fmt.Errorf("error loading user %d for dispute notification: %w", ev.UserID, err)
+	} else if user.Email != "" {
+		msg.To = user.Email
+		emailErr = n.send(ctx, msg)
+	}
+	return errors.Join(emailErr, n.inbox.Deliver(ctx, &models.Notification{
+		UserID: ev.UserID, Type: models.NotificationDisputeResolved,
+		Title: msg.Subject,
+		Body:  fmt.Sprintf("Attendance record %v: %v", ev.Data["attendance_id"], ev.Data["note"]),
+		Data: map[string]string{
+			"dispute_id": fmt.Sprint(ev.Data["dispute_id"]), "attendance_id": fmt.Sprint(ev.Data["attendance_id"]), "status": status,
+		},
+	}))
+}
+
+// approvalEscalated memberi tahu penyetuju baru item yang melewati SLA (inbox + email), atau
+// HR/admin lewat notifier jika eskalasi sudah sampai admin.
+func (n *Notifications) approvalEscalated(ctx context.Context, ev events.Event) error {
+	subject := fmt.Sprintf("Approval overdue: %v %v", ev.Data["item_type"], ev.Data["item_id"])
+	if ev.Data["action"] == models.EscalationReminded {
+		subject = "Reminder: " + subject
+	}
+	msg := notify.Message{
+		Topic:   notify.TopicApprovalEscalated,
+		Subject: subject,
+		Body:    fmt.Sprintf("%v %v submitted by user %d is still pending (escalation level %v). Please review it in the approval inbox.", ev.Data["item_type"], ev.Data["item_id"], ev.UserID, ev.Data["level"]),
+		Data:    map[string]any{"item_type": ev.Data["item_type"], "item_id": ev.Data["item_id"], "level": ev.Data["level"], "user_id": ev.UserID},
+	}
+	approverID, ok := intValue(ev.Data["escalated_to"])
+	if !ok {
+		return n.send(ctx, msg) // Admin/HR
+	}
+	approver, err := n.users.GetUserByID(ctx, approverID)
+	if err != nil {
+		return fmt.Errorf("error loading approver %d for escalation notification: %w", approverID, err)
+	}
+	var emailErr error
+	if approver.Email != "" {
+		msg.To = approver.Email
+		emailErr = n.send(ctx, msg)
+	}
+	return errors.Join(emailErr, n.inbox.Deliver(ctx, &models.Notification{
+		UserID: approverID, Type: models.NotificationApprovalEscalated,
+		Title: subject, Body: msg.Body,
+		Data: map[string]string{
+			"item_type": fmt.Sprint(ev.Data["item_type"]), "item_id": fmt.Sprint(ev.Data["item_id"]), "level": fmt.Sprint(ev.Data["level"]),
+		},
+	}))
+}
+
+// sessionsRevoked memberi tahu user bahwa admin mengeluarkan semua sesinya, hanya jika admin
+// memintanya (notify_user).
+func (n *Notifications) sessionsRevoked(ctx context.Context, ev events.Event) error {
+	if notifyUser, _ := ev.Data["notify_user"].(bool); !notifyUser {
+		return nil
+	}
+	body := "An administrator signed you out of all devices. Please log in again."
+	if reason, _ := ev.Data["reason"].(string); reason != "" {
+		body = fmt.Sprintf("An administrator signed you out of all devices (%s). Please log in again.", reason)
+	}
+	msg := notify.Message{
+		Topic:   notify.TopicSessionsRevoked,
+		Subject: "You have been signed out of all devices",
+		Body:    body,
+		Data:    map[string]any{"user_id": ev.UserID},
+	}
+	var emailErr error
+	if user, err := n.users.GetUserByID(ctx, ev.UserID); err != nil {
+		return fmt.Errorf("error loading user %d for session revocation notification: %w", ev.UserID, err)
+	} else if user.Email != "" {
+		msg.To = user.Email
+		emailErr = n.send(ctx, msg)
+	}
+	return errors.Join(emailErr, n.inbox.Deliver(ctx, &models.Notification{
+		UserID: ev.UserID, Type: models.NotificationSessionsRevoked,
+		Title: msg.Subject, Body: body,
+	}))
+}
+
+// usernameReviewed memberi tahu user (inbox) hasil permintaan ganti username-nya.
+func (n *Notifications) usernameReviewed(ctx context.Context, ev events.Event) error {
+	status := fmt.Sprint(ev.Data["status"])
+	title, body := "Username change rejected", "Your username change request was rejected."
+	if status == models.UsernameChangeApproved {
+		// Username tidak disimpan di payload event (ikut ke audit log); ambil nilai terkini.
+		user, err := n.users.GetUserByID(ctx, ev.UserID)
+		if err != nil {
+			return fmt.Errorf("error loading user %d for username review notification: %w", ev.UserID, err)
+		}
+		title = "Username changed"
+		body = fmt.Sprintf("Your username change was approved. Log in with %s from now on.", user.Username)
+	}
+	if note, _ := ev.Data["note"].(string); note != "" {
+		body += " Note: " + note
+	}
+	return n.inbox.Deliver(ctx, &models.Notification{
+		UserID: ev.UserID, Type: models.NotificationUsernameReviewed,
+		Title: title, Body: body,
+		Data: map[string]string{"request_id": fmt.Sprint(ev.Data["request_id"]), "status": status},
+	})
+}
+
+// queuedCheckInFailed memberi tahu karyawan (inbox + email) dan HR bahwa check-in yang sudah
+// dijawab 202 saat mode degraded/maintenance gagal dicatat, agar absensinya dikoreksi.
+func (n *Notifications) queuedCheckInFailed(ctx context.Context, ev events.Event) error {
+	// Payload outbox di-decode dari JSON, sehingga waktu menjadi string RFC 3339.
+	checkInAt := fmt.Sprint(ev.Data["check_in_at"])
+	switch at := ev.Data["check_in_at"].(type) {
+	case time.Time:
+		checkInAt = at.UTC().Format(time.RFC1123)
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, at); err == nil {
+			checkInAt = t.UTC().Format(time.RFC1123)
+		}
+	}
+	reason := fmt.Sprint(ev.Data["reason"])
+	userBody := fmt.Sprintf("Your check-in at %s was accepted while the system was unavailable, but it could not be recorded afterwards: %s. HR has been notified; please contact them to correct your attendance.", checkInAt, reason)
+
+	var emailErr error
+	if user, err := n.users.GetUserByID(ctx, ev.UserID); err != nil {
+		return fmt.Errorf("error loading user %d for queued check-in notification: %w", ev.UserID, err)
+	} else if user.Email != "" {
+		emailErr = n.send(ctx, notify.Message{
+			Topic: notify.TopicQueuedCheckInFailed, To: user.Email,
+			Subject: "Your check-in was not recorded", Body: userBody,
+			Data: map[string]any{"failure_id": ev.Data["failure_id"], "user_id": ev.UserID},
+		})
+	}
+	hrErr := n.send(ctx, notify.Message{
+		Topic:   notify.TopicQueuedCheckInFailed,
+		Subject: "Queued check-in could not be recorded",
+		Body: fmt.Sprintf("The check-in of user %d at %s, queued during %s mode, could not be recorded: %s. Review it under /admin/attendance/queued-check-in-failures and correct the attendance.",
+			ev.UserID, checkInAt, ev.Data["source"], reason),
+		Data: map[string]any{"failure_id": ev.Data["failure_id"], "user_id": ev.UserID, "source": ev.Data["source"]},
+	})
+	return errors.Join(emailErr, hrErr, n.inbox.Deliver(ctx, &models.Notification{
+		UserID: ev.UserID, Type: models.NotificationCheckInFailed,
+		Title: "Your check-in was not recorded", Body: userBody,
+		Data: map[string]string{"failure_id": fmt.Sprint(ev.Data["failure_id"]), "check_in_at": fmt.Sprint(ev.Data["check_in_at"])},
+	}))
+}
+
+// send mengirim notifikasi email/webhook; nil jika notifier tidak dikonfigurasi.
+func (n *Notifications) send(ctx context.Context, msg notify.Message) error {
+	if n.notifier == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	if err := n.notifier.Notify(ctx, msg); err != nil {
+		return fmt.Errorf("error sending %s notification via %s: %w", msg.Topic, n.notifier.Provider(), err)
+	}
+	return nil
+}
+
+// intValue membaca angka dari Data event, baik int (in-process) maupun float64 (JSON).
+func intValue(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,105 @@
+// internal/events/subscribers/webhook.go
+package subscribers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/resilience"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// WebhookSignatureHeader memuat HMAC-SHA256 (hex) body dengan EVENTS_WEBHOOK_SECRET.
+const WebhookSignatureHeader = "X-Event-Signature"
+
+// Webhook meneruskan domain event sebagai POST JSON ke sistem eksternal (mis. integrasi payroll).
+type Webhook struct {
+	url     string
+	secret  string
+	names   []string
+	client  *http.Client
+	breaker *resilience.Breaker
+	policy  resilience.Policy
+}
+
+// NewWebhookFromEnv membuat subscriber webhook berdasarkan environment variables.
+// Mengembalikan nil jika EVENTS_WEBHOOK_URL tidak di-set.
+//
+// Variabel Environment yang didukung:
+//   - EVENTS_WEBHOOK_URL: URL tujuan POST JSON events.Event.
+//   - EVENTS_WEBHOOK_SECRET: Kunci HMAC untuk header X-Event-Signature (opsional).
+//   - EVENTS_WEBHOOK_EVENTS: Daftar nama event dipisah koma. Default: semua event.
+//   - EVENTS_WEBHOOK_TIMEOUT: Timeout request webhook. Default: 10s.
+//
+// Retry ditangani dispatcher outbox, sehingga di sini hanya timeout dan circuit breaker
+// (resilience.Get("events.webhook")): target yang mati membuat event gagal seketika
+// alih-alih menahan worker dispatcher selama timeout.
+func NewWebhookFromEnv() *Webhook {
+	url := configs.GetEnv("EVENTS_WEBHOOK_URL", "")
+	if url == "" {
+		return nil
+	}
+	w := &Webhook{
+		url:     url,
+		secret:  configs.GetEnv("EVENTS_WEBHOOK_SECRET", ""),
+		names:   configs.GetEnvList("EVENTS_WEBHOOK_EVENTS", nil),
+		client:  &http.Client{},
+		breaker: resilience.Get("events.webhook"),
+		policy:  resilience.Policy{Timeout: configs.GetEnvDuration("EVENTS_WEBHOOK_TIMEOUT", 10*time.Second), MaxAttempts: 1},
+	}
+	zlog.Info().Strs("events", w.names).Msg("Event webhook enabled")
+	return w
+}
+
+// Events mengembalikan nama event yang diteruskan (kosong = semua), untuk Bus.Subscribe.
+func (w *Webhook) Events() []string {
+	return w.names
+}
+
+// Handle mengirim event ke webhook. Dijalankan oleh dispatcher outbox; error berarti dicoba lagi.
+func (w *Webhook) Handle(ctx context.Context, ev events.Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("error encoding event %s: %w", ev.Name, err)
+	}
+	return w.post(ctx, payload)
+}
+
+func (w *Webhook) post(ctx context.Context, payload []byte) error {
+	return resilience.Do(ctx, w.breaker, w.policy, func(ctx context.Context) error {
+		return w.send(ctx, payload)
+	})
+}
+
+func (w *Webhook) send(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error building event webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(payload)
+		req.Header.Set(WebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling event webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resilience.HTTPStatusError(fmt.Errorf("event webhook returned status %d", resp.StatusCode), resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// internal/faceverify/faceverify.go
+
+// Package faceverify adalah hook verifikasi wajah saat check-in (mis. kiosk absensi). Selfie
+// yang dikirim bersama check-in dibandingkan dengan foto profil user lewat layanan pengenalan
+// wajah eksternal; skornya disimpan dan punch dengan keyakinan rendah ditandai untuk ditinjau
+// admin. Verifikasi tidak pernah menolak check-in: kegagalan layanan juga hanya menandai punch.
+package faceverify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/resilience"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// ErrNoReference dikembalikan ReferenceSource jika user belum punya foto profil.
+var ErrNoReference = errors.New("user has no profile photo")
+
+// Verifier membandingkan dua foto wajah dan mengembalikan skor kecocokan 0..1.
+type Verifier interface {
+	Compare(ctx context.Context, selfie, reference []byte) (float64, error)
+	Provider() string
+}
+
+// ReferenceSource menyediakan foto profil (foto acuan) user.
+type ReferenceSource interface {
+	ProfilePhoto(ctx context.Context, userID int) ([]byte, error)
+}
+
+// Result adalah hasil verifikasi satu check-in.
+type Result struct {
+	Status   string   // models.FaceMatch*
+	Score    *float64 // nil jika layanan tidak dipanggil atau gagal
+	Provider string
+	Err      error // Penyebab status error/no_reference, untuk log
+}
+
+// Flagged melaporkan apakah punch perlu ditinjau admin.
+func (r Result) Flagged() bool {
+	return r.Status != models.FaceMatchMatched && r.Status != models.FaceMatchNoReference
+}
+
+// Hook menjalankan verifikasi wajah untuk check-in. Hook nil = verifikasi dinonaktifkan.
+type Hook struct {
+	verifier   Verifier
+	references ReferenceSource
+	minScore   float64
+}
+
+// NewHook membuat Hook; skor di bawah minScore ditandai low_confidence.
+func NewHook(verifier Verifier, references ReferenceSource, minScore float64) *Hook {
+	return &Hook{verifier: verifier, references: references, minScore: minScore}
+}
+
+// NewHookFromEnv membuat Hook berdasarkan environment variables.
+// Mengembalikan nil jika verifikasi wajah tidak diaktifkan (FACE_VERIFY_PROVIDER kosong atau 'none').
+//
+// Variabel Environment yang didukung:
+//   - FACE_VERIFY_PROVIDER: 'http' atau 'none' (default).
+//   - FACE_VERIFY_HTTP_URL: Endpoint pembanding wajah (wajib untuk http). Menerima POST JSON
+//     {"selfie": "<base64>", "reference": "<base64>"} dan menjawab {"score": 0.0-1.0}.
+//   - FACE_VERIFY_API_KEY: Dikirim sebagai header Authorization: Bearer (opsional).
+//   - FACE_VERIFY_MIN_SCORE: Skor minimum agar punch dianggap cocok. Default: 0.8.
+//   - FACE_VERIFY_TIMEOUT: Timeout satu perbandingan. Default: 5s.
+//
+// Perbandingan berjalan di jalur check-in, jadi tidak dicoba ulang; circuit breaker
+// (resilience.Get("faceverify")) melewati layanan yang sedang mati tanpa menunggu timeout.
+func NewHookFromEnv(references ReferenceSource) (*Hook, error) {
+	provider := strings.ToLower(configs.GetEnv("FACE_VERIFY_PROVIDER", "none"))
+	switch provider {
+	case "none", "":
+		return nil, nil
+	case "http":
+		endpoint := configs.GetEnv("FACE_VERIFY_HTTP_URL", "")
+		if endpoint == "" {
+			return nil, fmt.Errorf("FACE_VERIFY_HTTP_URL must be set when FACE_VERIFY_PROVIDER=http")
+		}
+		minScore := configs.GetEnvFloat("FACE_VERIFY_MIN_SCORE", 0.8)
+		if minScore < 0 || minScore > 1 {
+			return nil, fmt.Errorf("FACE_VERIFY_MIN_SCORE must be between 0 and 1")
+		}
+		verifier := &httpVerifier{
+			endpoint: endpoint, apiKey: configs.GetEnv("FACE_VERIFY_API_KEY", ""), client: &http.Client{},
+			breaker: resilience.Get("faceverify"),
+			policy:  resilience.Policy{Timeout: configs.GetEnvDuration("FACE_VERIFY_TIMEOUT", 5*time.Second), MaxAttempts: 1},
+		}
+		zlog.Info().Str("provider", provider).Float64("min_score", minScore).Msg("Check-in face verification enabled")
+		return NewHook(verifier, references, minScore), nil
+	default:
+		return nil, fmt.Errorf("unsupported FACE_VERIFY_PROVIDER '%s'", provider)
+	}
+}
+
+// Check memverifikasi selfie check-in userID terhadap foto profilnya. selfie kosong
+// menghasilkan missing_selfie (ditandai untuk ditinjau).
+func (h *Hook) Check(ctx context.Context, userID int, selfie []byte) Result {
+	result := Result{Provider: h.verifier.Provider()}
+	if len(selfie) == 0 {
+		result.Status = models.FaceMatchMissingSelfie
+		return result
+	}
+	reference, err := h.references.ProfilePhoto(ctx, userID)
+	if err != nil {
+		result.Status, result.Err = models.FaceMatchError, err
+		if errors.Is(err, ErrNoReference) {
+			result.Status = models.FaceMatchNoReference
+		}
+		return result
+	}
+	score, err := h.verifier.Compare(ctx, selfie, reference)
+	if err != nil {
+		result.Status, result.Err = models.FaceMatchError, err
+		return result
+	}
+	result.Score = &score
+	result.Status = models.FaceMatchMatched
+	if score < h.minScore {
+		result.Status = models.FaceMatchLowConfidence
+	}
+	return result
+}
+
+// httpVerifier memanggil layanan pembanding wajah lewat HTTP JSON.
+type httpVerifier struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+	breaker  *resilience.Breaker
+	policy   resilience.Policy
+}
+
+func (v *httpVerifier) Provider() string {
+	return "http"
+}
+
+func (v *httpVerifier) Compare(ctx context.Context, selfie, reference []byte) (float64, error) {
+	payload, err := json.Marshal(map[string]string{
+		"selfie":    base64.StdEncoding.EncodeToString(selfie),
+		"reference": base64.StdEncoding.EncodeToString(reference),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error encoding face verification request: %w", err)
+	}
+	var reply struct {
+		Score *float64 `json:"score"`
+	}
+	err = resilience.Do(ctx, v.breaker, v.policy, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return resilience.Permanent(fmt.Errorf("error building face verification request: %w", err))
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if v.apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+v.apiKey)
+		}
+		resp, err := v.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("error calling face verification service: %w", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if err != nil {
+			return fmt.Errorf("error reading face verification response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return resilience.HTTPStatusError(fmt.Errorf("face verification service returned status %d", resp.StatusCode), resp.StatusCode)
+		}
+		if err := json.Unmarshal(body, &reply); err != nil || reply.Score == nil {
+			return resilience.Permanent(fmt.Errorf("invalid face verification response: %s", strings.TrimSpace(string(body))))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return min(max(*reply.Score, 0), 1), nil
+}
@@ -0,0 +1,107 @@
+// internal/geoip/geoip.go
+
+// Package geoip menerjemahkan alamat IP klien ke kode negara (ISO 3166-1 alpha-2) untuk
+// mendeteksi login dari negara yang tidak biasa. Provider dipilih lewat environment.
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/resilience"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Locator adalah kontrak lookup negara dari alamat IP.
+// Country mengembalikan "" jika negara tidak diketahui (mis. IP privat).
+type Locator interface {
+	Country(ctx context.Context, ip string) (string, error)
+	Provider() string
+}
+
+// NewLocatorFromEnv membuat Locator berdasarkan environment variables.
+// Mengembalikan nil jika GeoIP tidak diaktifkan (GEOIP_PROVIDER kosong atau 'none').
+//
+// Variabel Environment yang didukung:
+//   - GEOIP_PROVIDER: 'http' atau 'none' (default).
+//   - GEOIP_HTTP_URL: URL lookup dengan placeholder {ip} yang mengembalikan kode negara
+//     sebagai teks polos, mis. https://ipapi.co/{ip}/country/ (wajib untuk http).
+//   - GEOIP_TIMEOUT: Timeout lookup. Default: 2s.
+//
+// Lookup berjalan di jalur login, jadi tidak dicoba ulang; circuit breaker
+// (resilience.Get("geoip")) melewati layanan yang sedang mati tanpa menunggu timeout.
+func NewLocatorFromEnv() (Locator, error) {
+	provider := strings.ToLower(configs.GetEnv("GEOIP_PROVIDER", "none"))
+	switch provider {
+	case "none", "":
+		return nil, nil
+	case "http":
+		urlTemplate := configs.GetEnv("GEOIP_HTTP_URL", "")
+		if !strings.Contains(urlTemplate, "{ip}") {
+			return nil, fmt.Errorf("GEOIP_HTTP_URL must be set and contain {ip} when GEOIP_PROVIDER=http")
+		}
+		timeout := configs.GetEnvDuration("GEOIP_TIMEOUT", 2*time.Second)
+		zlog.Info().Str("provider", provider).Msg("GeoIP lookup enabled")
+		return &httpLocator{
+			urlTemplate: urlTemplate, client: &http.Client{},
+			breaker: resilience.Get("geoip"), policy: resilience.Policy{Timeout: timeout, MaxAttempts: 1},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported GEOIP_PROVIDER '%s'", provider)
+	}
+}
+
+// httpLocator memanggil layanan lookup HTTP yang mengembalikan kode negara sebagai teks.
+type httpLocator struct {
+	urlTemplate string
+	client      *http.Client
+	breaker     *resilience.Breaker
+	policy      resilience.Policy
+}
+
+func (l *httpLocator) Provider() string {
+	return "http"
+}
+
+func (l *httpLocator) Country(ctx context.Context, ip string) (string, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.IsPrivate() || parsed.IsLoopback() || parsed.IsLinkLocalUnicast() || parsed.IsUnspecified() {
+		return "", nil
+	}
+	endpoint := strings.ReplaceAll(l.urlTemplate, "{ip}", url.PathEscape(parsed.String()))
+	var body []byte
+	err := resilience.Do(ctx, l.breaker, l.policy, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return resilience.Permanent(fmt.Errorf("error building geoip request: %w", err))
+		}
+		resp, err := l.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("error calling geoip service: %w", err)
+		}
+		defer resp.Body.Close()
+		body, err = io.ReadAll(io.LimitReader(resp.Body, 64))
+		if err != nil {
+			return fmt.Errorf("error reading geoip response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return resilience.HTTPStatusError(fmt.Errorf("geoip service returned status %d", resp.StatusCode), resp.StatusCode)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	country := strings.ToUpper(strings.TrimSpace(string(body)))
+	if len(country) != 2 || strings.IndexFunc(country, func(r rune) bool { return r < 'A' || r > 'Z' }) >= 0 {
+		return "", nil // Respons bukan kode negara (mis. "Undefined"): anggap tidak diketahui
+	}
+	return country, nil
+}
@@ -0,0 +1,56 @@
+// internal/inbox/inbox.go
+
+// Package inbox mengirim notifikasi untuk satu user ke kanal in-app: disimpan di inbox
+// (tabel notifications, dibaca lewat /user/notifications) lalu diteruskan sebagai push
+// notification ke perangkat user jika push aktif.
+package inbox
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/push"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Dispatcher menyimpan notifikasi ke inbox user dan meneruskannya ke push.
+type Dispatcher struct {
+	notifications repository.NotificationRepository
+	push          *push.Service // nil = push notification nonaktif
+}
+
+func NewDispatcher(notifications repository.NotificationRepository, pushService *push.Service) *Dispatcher {
+	return &Dispatcher{notifications: notifications, push: pushService}
+}
+
+// PushEnabled melaporkan apakah notifikasi juga dikirim sebagai push notification.
+func (d *Dispatcher) PushEnabled() bool {
+	return d != nil && d.push != nil
+}
+
+// Deliver menyimpan n ke inbox n.UserID, lalu mengirim push di background dengan
+// notification_id agar aplikasi bisa menandainya dibaca. Mengembalikan error jika notifikasi
+// gagal disimpan (push tidak dikirim), agar pemanggil dari outbox bisa mencoba lagi.
+// Aman dipanggil pada Dispatcher nil.
+func (d *Dispatcher) Deliver(ctx context.Context, n *models.Notification) error {
+	if d == nil {
+		return nil
+	}
+	if _, err := d.notifications.CreateNotification(ctx, n); err != nil {
+		zlog.Ctx(ctx).Error().Err(err).Int("user_id", n.UserID).Str("type", n.Type).Msg("Failed to store in-app notification")
+		return err
+	}
+	if d.push == nil {
+		return nil
+	}
+	data := make(map[string]string, len(n.Data)+2)
+	for k, v := range n.Data {
+		data[k] = v
+	}
+	data["type"] = n.Type
+	data["notification_id"] = strconv.Itoa(n.ID)
+	d.push.NotifyUser(ctx, n.UserID, push.Message{Title: n.Title, Body: n.Body, Data: data})
+	return nil
+}
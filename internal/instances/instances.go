@@ -0,0 +1,149 @@
+// internal/instances/instances.go
+
+// Package instances mencatat heartbeat setiap instance API beserta versi aplikasinya di tabel
+// app_instances. Daftar instance hidup dipakai runner migrasi (cmd/migrate) untuk menolak
+// migrasi destruktif selama versi lama masih melayani (rolling deploy multi-replika), dan
+// ditampilkan lewat GET /admin/maintenance/app-versions.
+package instances
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/buildinfo"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// TTLFromEnv mengembalikan berapa lama sebuah instance dianggap hidup sejak heartbeat
+// terakhirnya (APP_HEARTBEAT_TTL, default 60s).
+func TTLFromEnv() time.Duration {
+	return max(configs.GetEnvDuration("APP_HEARTBEAT_TTL", 60*time.Second), time.Second)
+}
+
+// Alive mengembalikan instance yang heartbeat terakhirnya masih dalam ttl.
+func Alive(ctx context.Context, repo repository.AppInstanceRepository, ttl time.Duration) ([]models.AppInstance, error) {
+	return repo.GetAppInstances(ctx, time.Now().Add(-ttl))
+}
+
+// Summarize merangkum instance per versi aplikasi, urut versi.
+func Summarize(instances []models.AppInstance) []models.AppVersionSummary {
+	byVersion := map[string]*models.AppVersionSummary{}
+	for _, i := range instances {
+		s := byVersion[i.AppVersion]
+		if s == nil {
+			s = &models.AppVersionSummary{AppVersion: i.AppVersion, FirstStarted: i.StartedAt, LastSeenAt: i.LastSeenAt}
+			byVersion[i.AppVersion] = s
+		}
+		s.Instances++
+		if i.StartedAt.Before(s.FirstStarted) {
+			s.FirstStarted = i.StartedAt
+		}
+		if i.LastSeenAt.After(s.LastSeenAt) {
+			s.LastSeenAt = i.LastSeenAt
+		}
+	}
+	summaries := make([]models.AppVersionSummary, 0, len(byVersion))
+	for _, s := range byVersion {
+		summaries = append(summaries, *s)
+	}
+	slices.SortFunc(summaries, func(a, b models.AppVersionSummary) int {
+		return a.FirstStarted.Compare(b.FirstStarted)
+	})
+	return summaries
+}
+
+// Heartbeat mencatat instance ini di app_instances secara berkala.
+type Heartbeat struct {
+	repo     repository.AppInstanceRepository
+	instance models.AppInstance
+	interval time.Duration
+	ttl      time.Duration
+}
+
+// NewHeartbeatFromEnv membuat heartbeat berdasarkan environment variables.
+// Mengembalikan nil jika heartbeat dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - APP_VERSION: Versi aplikasi yang dilaporkan. Default: versi build (lihat internal/buildinfo).
+//   - APP_HEARTBEAT_INTERVAL: Jeda antar heartbeat. Default: 15s. 0 menonaktifkan heartbeat
+//     (instance ini tidak terlihat oleh runner migrasi).
+//   - APP_HEARTBEAT_TTL: Instance tanpa heartbeat selama ini dianggap mati. Default: 60s.
+func NewHeartbeatFromEnv(repo repository.AppInstanceRepository) *Heartbeat {
+	interval := configs.GetEnvDuration("APP_HEARTBEAT_INTERVAL", 15*time.Second)
+	if interval <= 0 {
+		zlog.Info().Msg("App instance heartbeat disabled")
+		return nil
+	}
+	hostname, _ := os.Hostname()
+	return &Heartbeat{
+		repo:     repo,
+		instance: models.AppInstance{InstanceID: newInstanceID(), AppVersion: buildinfo.AppVersion(), Hostname: hostname},
+		interval: interval,
+		ttl:      max(TTLFromEnv(), 2*interval),
+	}
+}
+
+// TTL mengembalikan batas umur heartbeat instance hidup.
+func (h *Heartbeat) TTL() time.Duration {
+	return h.ttl
+}
+
+// AppVersion mengembalikan versi yang dilaporkan instance ini.
+func (h *Heartbeat) AppVersion() string {
+	return h.instance.AppVersion
+}
+
+// Start mengirim heartbeat segera lalu setiap interval sampai ctx dibatalkan, dan menghapus
+// instance yang heartbeat-nya kedaluwarsa. Dipanggil sebagai goroutine.
+func (h *Heartbeat) Start(ctx context.Context) {
+	zlog.Info().Str("instance_id", h.instance.InstanceID).Str("app_version", h.instance.AppVersion).
+		Dur("interval", h.interval).Msg("App instance heartbeat started")
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		h.beat(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Heartbeat) beat(ctx context.Context) {
+	instance := h.instance
+	if err := h.repo.UpsertAppInstance(ctx, &instance); err != nil {
+		zlog.Warn().Err(err).Msg("Failed to record app instance heartbeat")
+		return
+	}
+	if deleted, err := h.repo.DeleteStaleAppInstances(ctx, time.Now().Add(-h.ttl)); err != nil {
+		zlog.Warn().Err(err).Msg("Failed to delete stale app instances")
+	} else if deleted > 0 {
+		zlog.Info().Int("deleted", deleted).Msg("Deleted stale app instances")
+	}
+}
+
+// Stop menghapus instance ini dari app_instances agar runner migrasi tidak perlu menunggu
+// heartbeat-nya kedaluwarsa. Dipanggil saat shutdown.
+func (h *Heartbeat) Stop(ctx context.Context) {
+	if h == nil {
+		return
+	}
+	if err := h.repo.DeleteAppInstance(ctx, h.instance.InstanceID); err != nil {
+		zlog.Warn().Err(err).Msg("Failed to deregister app instance")
+	}
+}
+
+// newInstanceID membuat ID acak 16 byte (hex) untuk instance ini.
+func newInstanceID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
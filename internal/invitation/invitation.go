@@ -0,0 +1,184 @@
+// internal/invitation/invitation.go
+
+// Package invitation menjalankan onboarding lewat undangan: admin mengundang alamat email dengan
+// role yang sudah ditentukan, dan penerima membuat akunnya lewat tautan bertoken di email.
+package invitation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/notify"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// sendTimeout membatasi pengiriman satu email undangan.
+const sendTimeout = 30 * time.Second
+
+var (
+	// ErrResendTooSoon dikembalikan jika undangan baru saja dikirim (lihat INVITATION_RESEND_INTERVAL).
+	ErrResendTooSoon = errors.New("invitation was sent recently")
+	// ErrInvalidLink dikembalikan untuk tautan undangan yang tidak valid, kedaluwarsa, sudah
+	// dipakai, atau dicabut.
+	ErrInvalidLink = errors.New("invalid or expired invitation link")
+)
+
+// Service mengelola undangan onboarding.
+type Service struct {
+	users        repository.UserRepository
+	notifier     notify.Notifier
+	linkTemplate string
+	setupLink    string // Tautan mengatur password untuk akun yang dibuat admin
+	ttl          time.Duration
+	resendAfter  time.Duration
+}
+
+// NewServiceFromEnv membuat Service berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - INVITATION_URL: URL frontend untuk tautan undangan dengan placeholder {token}.
+//     Default: http://localhost:3000/accept-invite?token={token}.
+//   - INVITATION_TTL: Masa berlaku undangan. Default: 168h (7 hari).
+//   - INVITATION_RESEND_INTERVAL: Jeda minimum antar pengiriman ulang. Default: 1m.
+//   - ACCOUNT_SETUP_URL: URL frontend untuk mengatur password akun yang dibuat admin, dengan
+//     placeholder {token} (token untuk POST /auth/password/reset).
+//     Default: http://localhost:3000/reset-password?token={token}.
+func NewServiceFromEnv(users repository.UserRepository, notifier notify.Notifier) (*Service, error) {
+	if notifier == nil {
+		return nil, fmt.Errorf("invitations require a notifier (NOTIFY_PROVIDER)")
+	}
+	linkTemplate := configs.GetEnv("INVITATION_URL", "http://localhost:3000/accept-invite?token={token}")
+	if !strings.Contains(linkTemplate, "{token}") {
+		return nil, fmt.Errorf("INVITATION_URL must contain {token}")
+	}
+	setupLink := configs.GetEnv("ACCOUNT_SETUP_URL", "http://localhost:3000/reset-password?token={token}")
+	if !strings.Contains(setupLink, "{token}") {
+		return nil, fmt.Errorf("ACCOUNT_SETUP_URL must contain {token}")
+	}
+	s := &Service{
+		users:        users,
+		notifier:     notifier,
+		linkTemplate: linkTemplate,
+		setupLink:    setupLink,
+		ttl:          configs.GetEnvDuration("INVITATION_TTL", 7*24*time.Hour),
+		resendAfter:  configs.GetEnvDuration("INVITATION_RESEND_INTERVAL", time.Minute),
+	}
+	if s.ttl <= 0 {
+		return nil, fmt.Errorf("INVITATION_TTL must be positive")
+	}
+	if notifier.Provider() == "log" {
+		zlog.Warn().Msg("Invitation and account setup links are not delivered with NOTIFY_PROVIDER=log (set NOTIFY_LOG_BODY=true in development to see them in the log)")
+	}
+	return s, nil
+}
+
+// Invite membuat undangan untuk email dengan role roleID (menggantikan undangan terbuka
+// sebelumnya untuk email yang sama) dan mengirim tautannya. repository.ErrEmailTaken jika email
+// sudah dipakai user; pgx.ErrNoRows jika role tidak ada. Jika email gagal terkirim, undangan
+// tetap tersimpan dan error dikembalikan (admin bisa mengirim ulang).
+func (s *Service) Invite(ctx context.Context, email string, roleID, invitedBy int) (*models.Invitation, error) {
+	inv, err := s.users.CreateInvitation(ctx, email, roleID, invitedBy, time.Now().Add(s.ttl))
+	if err != nil {
+		return nil, err
+	}
+	return inv, s.send(ctx, inv)
+}
+
+// Resend mengirim ulang tautan undangan id dan memperpanjang masa berlakunya. pgx.ErrNoRows jika
+// undangan tidak ada, sudah diterima, atau dicabut; ErrResendTooSoon jika terlalu cepat.
+func (s *Service) Resend(ctx context.Context, id int) (*models.Invitation, error) {
+	inv, err := s.users.GetInvitationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status != models.InvitationPending && inv.Status != models.InvitationExpired {
+		return nil, pgx.ErrNoRows
+	}
+	now := time.Now()
+	if now.Sub(inv.SentAt) < s.resendAfter {
+		return inv, ErrResendTooSoon
+	}
+	expiresAt := now.Add(s.ttl)
+	if err := s.users.RenewInvitation(ctx, inv.ID, expiresAt); err != nil {
+		return nil, err
+	}
+	inv.SentAt, inv.ExpiresAt, inv.Status = now, expiresAt, models.InvitationPending
+	return inv, s.send(ctx, inv)
+}
+
+// Open mengembalikan undangan dari token tautan jika masih bisa diterima, atau ErrInvalidLink.
+func (s *Service) Open(ctx context.Context, token string) (*models.Invitation, error) {
+	invitationID, err := utils.ValidateInvitationToken(token)
+	if err != nil {
+		zlog.Ctx(ctx).Warn().Err(err).Msg("Invalid invitation token")
+		return nil, ErrInvalidLink
+	}
+	inv, err := s.users.GetInvitationByID(ctx, invitationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidLink
+	}
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status != models.InvitationPending {
+		return nil, ErrInvalidLink
+	}
+	return inv, nil
+}
+
+// SendAccountSetup mengirim email ke user yang akunnya dibuat admin, berisi tautan untuk
+// mengatur password sendiri. Tautan berlaku selama INVITATION_TTL dan hanya sekali (token reset
+// terikat ke versi token user).
+func (s *Service) SendAccountSetup(ctx context.Context, user *models.User) error {
+	token, err := utils.GeneratePurposeToken(utils.PurposePasswordReset, user.ID, user.TokenVersion, s.ttl)
+	if err != nil {
+		return err
+	}
+	link := strings.ReplaceAll(s.setupLink, "{token}", url.QueryEscape(token))
+	msg := notify.Message{
+		To:      user.Email,
+		Topic:   notify.TopicInvitation,
+		Subject: "Your attendance system account",
+		Body: fmt.Sprintf("An administrator created an account for you with the username %s. Open this link before %s to set your password:\n%s\nIf you weren't expecting this email, contact your administrator.",
+			user.Username, time.Now().Add(s.ttl).UTC().Format(time.RFC1123), link),
+		Data: map[string]any{"user_id": user.ID},
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	if err := s.notifier.Notify(sendCtx, msg); err != nil {
+		return fmt.Errorf("error sending account setup email via %s: %w", s.notifier.Provider(), err)
+	}
+	return nil
+}
+
+// send mengirim tautan undangan ke alamat yang diundang.
+func (s *Service) send(ctx context.Context, inv *models.Invitation) error {
+	token, err := utils.GenerateInvitationToken(inv.ID, inv.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	link := strings.ReplaceAll(s.linkTemplate, "{token}", url.QueryEscape(token))
+	msg := notify.Message{
+		To:      inv.Email,
+		Topic:   notify.TopicInvitation,
+		Subject: "You're invited to the attendance system",
+		Body: fmt.Sprintf("You have been invited to create an account with the %s role. Open this link before %s to choose your username and password:\n%s\nIf you weren't expecting this invitation, ignore this email.",
+			inv.RoleName, inv.ExpiresAt.UTC().Format(time.RFC1123), link),
+		Data: map[string]any{"invitation_id": inv.ID},
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	if err := s.notifier.Notify(sendCtx, msg); err != nil {
+		return fmt.Errorf("error sending invitation via %s: %w", s.notifier.Provider(), err)
+	}
+	return nil
+}
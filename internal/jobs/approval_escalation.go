@@ -0,0 +1,176 @@
+// internal/jobs/approval_escalation.go
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// escalationBatchSize membatasi item yang diproses per tipe dalam satu putaran.
+const escalationBatchSize = 200
+
+// maxManagerDepth membatasi penelusuran garis pelaporan (jaga-jaga data siklik).
+const maxManagerDepth = 20
+
+// ApprovalEscalation mengeskalasi item approval yang menunggu lebih lama dari SLA tipenya.
+// Setiap SLA yang terlewati menambah satu langkah: item dilimpahkan ke atasan satu level di
+// atas penyetuju sebelumnya, lalu ke admin/HR jika garis pelaporan habis; setelah di admin,
+// setiap SLA berikutnya mengirim pengingat. Langkah dicatat di approval_escalations (riwayat
+// di approval inbox) dan event ApprovalEscalated di-publish dalam transaksi yang sama,
+// sehingga notifikasinya ikut outbox.
+//
+// Tipe yang didukung: attendance_dispute (penyetuju awal = atasan langsung pemilik dispute).
+type ApprovalEscalation struct {
+	disputes    repository.DisputeRepository
+	escalations repository.EscalationRepository
+	users       repository.UserRepository
+	tx          repository.TxManager
+	events      events.Publisher
+	interval    time.Duration
+	slas        map[string]time.Duration // Per tipe approval; 0 = tanpa eskalasi
+}
+
+// NewApprovalEscalationFromEnv membuat job berdasarkan environment variables.
+// Mengembalikan nil jika job dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - APPROVAL_ESCALATION_INTERVAL: Jeda antar pengecekan. Default: 15m. 0 menonaktifkan job.
+//   - APPROVAL_SLA_ATTENDANCE_DISPUTE: Batas tunggu dispute per level sebelum dieskalasi. Default: 48h. 0 = tanpa eskalasi.
+func NewApprovalEscalationFromEnv(disputes repository.DisputeRepository, escalations repository.EscalationRepository, users repository.UserRepository, tx repository.TxManager, eventBus events.Publisher) *ApprovalEscalation {
+	interval := configs.GetEnvDuration("APPROVAL_ESCALATION_INTERVAL", 15*time.Minute)
+	if interval <= 0 {
+		zlog.Info().Msg("Approval escalation job disabled")
+		return nil
+	}
+	return &ApprovalEscalation{
+		disputes:    disputes,
+		escalations: escalations,
+		users:       users,
+		tx:          tx,
+		events:      eventBus,
+		interval:    interval,
+		slas: map[string]time.Duration{
+			models.ApprovalTypeDispute: configs.GetEnvDuration("APPROVAL_SLA_ATTENDANCE_DISPUTE", 48*time.Hour),
+		},
+	}
+}
+
+// Name mengembalikan nama job di scheduler.
+func (j *ApprovalEscalation) Name() string {
+	return "approval_escalation"
+}
+
+// Interval mengembalikan jeda antar run.
+func (j *ApprovalEscalation) Interval() time.Duration {
+	return j.interval
+}
+
+// RunOnce mengeskalasi item yang jatuh tempo dan mengembalikan jumlah langkah yang dicatat.
+// Item yang gagal diproses dicoba lagi di putaran berikutnya.
+func (j *ApprovalEscalation) RunOnce(ctx context.Context) (int, error) {
+	sla := j.slas[models.ApprovalTypeDispute]
+	if sla <= 0 {
+		return 0, nil
+	}
+	now := time.Now()
+	disputes, err := j.disputes.GetOpenDisputesBefore(ctx, now.Add(-sla), escalationBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	ids := make([]int, len(disputes))
+	for i, d := range disputes {
+		ids[i] = d.ID
+	}
+	history, err := j.escalations.GetEscalations(ctx, models.ApprovalTypeDispute, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	escalated := 0
+	for _, d := range disputes {
+		steps := history[d.ID]
+		// Langkah ke-n jatuh tempo setelah n kali SLA sejak pengajuan.
+		if now.Before(d.CreatedAt.Add(sla * time.Duration(len(steps)+1))) {
+			continue
+		}
+		step, err := j.nextStep(ctx, d.UserID, steps)
+		if err != nil {
+			zlog.Warn().Err(err).Int("dispute_id", d.ID).Msg("Failed to resolve escalation target")
+			continue
+		}
+		step.ItemType, step.ItemID = models.ApprovalTypeDispute, d.ID
+		recorded, err := j.record(ctx, step, d.UserID, map[string]any{"dispute_id": d.ID, "attendance_id": d.AttendanceID})
+		if err != nil {
+			zlog.Warn().Err(err).Int("dispute_id", d.ID).Msg("Failed to escalate dispute")
+			continue
+		}
+		if recorded {
+			escalated++
+		}
+	}
+	if escalated > 0 {
+		zlog.Info().Int("steps", escalated).Msg("Escalated overdue approvals")
+	}
+	return escalated, nil
+}
+
+// nextStep menentukan langkah berikutnya untuk item milik ownerID. Penyetuju level 0 adalah
+// atasan langsung owner; level n adalah atasan ke-n di atasnya, lalu admin (EscalatedTo nil).
+func (j *ApprovalEscalation) nextStep(ctx context.Context, ownerID int, steps []models.ApprovalEscalation) (*models.ApprovalEscalation, error) {
+	step := &models.ApprovalEscalation{Level: len(steps) + 1, Action: models.EscalationEscalated}
+	if len(steps) > 0 && steps[len(steps)-1].EscalatedTo == nil {
+		step.Action = models.EscalationReminded // Sudah di admin
+		return step, nil
+	}
+	chain, err := j.managerChain(ctx, ownerID, step.Level+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) > step.Level {
+		step.EscalatedTo = &chain[step.Level]
+	}
+	return step, nil
+}
+
+// managerChain mengembalikan hingga n atasan userID berurutan ke atas (atasan langsung dulu).
+func (j *ApprovalEscalation) managerChain(ctx context.Context, userID, n int) ([]int, error) {
+	chain := []int{}
+	seen := map[int]bool{userID: true}
+	for len(chain) < min(n, maxManagerDepth) {
+		user, err := j.users.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if user.ManagerID == nil || seen[*user.ManagerID] {
+			break
+		}
+		userID = *user.ManagerID
+		seen[userID] = true
+		chain = append(chain, userID)
+	}
+	return chain, nil
+}
+
+// record mencatat langkah dan mem-publish ApprovalEscalated dalam satu transaksi.
+func (j *ApprovalEscalation) record(ctx context.Context, step *models.ApprovalEscalation, ownerID int, data map[string]any) (bool, error) {
+	recorded := false
+	err := j.tx.RunInTx(ctx, func(ctx context.Context) error {
+		var err error
+		if recorded, err = j.escalations.RecordEscalation(ctx, step); err != nil || !recorded {
+			return err
+		}
+		data["item_type"], data["item_id"], data["level"], data["action"] = step.ItemType, step.ItemID, step.Level, step.Action
+		if step.EscalatedTo != nil {
+			data["escalated_to"] = *step.EscalatedTo
+		}
+		j.events.Publish(ctx, events.Event{Name: events.ApprovalEscalated, UserID: ownerID, Data: data})
+		return nil
+	})
+	return recorded, err
+}
@@ -0,0 +1,93 @@
+// internal/jobs/attendance_photos.go
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/photos"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// attendancePhotoBatchSize membatasi foto yang diproses/dihapus dalam satu putaran.
+const attendancePhotoBatchSize = 100
+
+// AttendancePhotoProcessing memproses foto check-in yang masih pending: membuang metadata
+// EXIF, membuat thumbnail, dan menyimpan hasilnya terenkripsi (lihat photos.Pipeline).
+type AttendancePhotoProcessing struct {
+	pipeline *photos.Pipeline
+	interval time.Duration
+}
+
+// NewAttendancePhotoProcessingFromEnv membuat job berdasarkan environment variables.
+// Mengembalikan nil jika penyimpanan foto nonaktif (pipeline nil) atau job dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - ATTENDANCE_PHOTO_PROCESS_INTERVAL: Jeda antar pemrosesan. Default: 1m. 0 menonaktifkan job.
+func NewAttendancePhotoProcessingFromEnv(pipeline *photos.Pipeline) *AttendancePhotoProcessing {
+	if pipeline == nil {
+		return nil
+	}
+	interval := configs.GetEnvDuration("ATTENDANCE_PHOTO_PROCESS_INTERVAL", time.Minute)
+	if interval <= 0 {
+		zlog.Info().Msg("Attendance photo processing job disabled")
+		return nil
+	}
+	return &AttendancePhotoProcessing{pipeline: pipeline, interval: interval}
+}
+
+// Name mengembalikan nama job di scheduler.
+func (j *AttendancePhotoProcessing) Name() string {
+	return "attendance_photo_processing"
+}
+
+// Interval mengembalikan jeda antar run.
+func (j *AttendancePhotoProcessing) Interval() time.Duration {
+	return j.interval
+}
+
+// RunOnce memproses satu batch foto pending dan mengembalikan jumlah foto yang selesai.
+func (j *AttendancePhotoProcessing) RunOnce(ctx context.Context) (int, error) {
+	return j.pipeline.Process(ctx, attendancePhotoBatchSize)
+}
+
+// AttendancePhotoRetention menghapus file foto check-in yang melewati masa retensi
+// (ATTENDANCE_PHOTO_RETENTION). Baris foto tetap ada dengan status purged sebagai jejak.
+type AttendancePhotoRetention struct {
+	pipeline *photos.Pipeline
+	interval time.Duration
+}
+
+// NewAttendancePhotoRetentionFromEnv membuat job berdasarkan environment variables.
+// Mengembalikan nil jika penyimpanan foto nonaktif (pipeline nil) atau job dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - ATTENDANCE_PHOTO_RETENTION_INTERVAL: Jeda antar pembersihan. Default: 1h. 0 menonaktifkan job.
+func NewAttendancePhotoRetentionFromEnv(pipeline *photos.Pipeline) *AttendancePhotoRetention {
+	if pipeline == nil {
+		return nil
+	}
+	interval := configs.GetEnvDuration("ATTENDANCE_PHOTO_RETENTION_INTERVAL", time.Hour)
+	if interval <= 0 {
+		zlog.Info().Msg("Attendance photo retention job disabled")
+		return nil
+	}
+	return &AttendancePhotoRetention{pipeline: pipeline, interval: interval}
+}
+
+// Name mengembalikan nama job di scheduler.
+func (j *AttendancePhotoRetention) Name() string {
+	return "attendance_photo_retention"
+}
+
+// Interval mengembalikan jeda antar run.
+func (j *AttendancePhotoRetention) Interval() time.Duration {
+	return j.interval
+}
+
+// RunOnce menghapus satu batch foto kedaluwarsa dan mengembalikan jumlahnya. Sisa batch
+// diproses di putaran berikutnya.
+func (j *AttendancePhotoRetention) RunOnce(ctx context.Context) (int, error) {
+	return j.pipeline.Purge(ctx, attendancePhotoBatchSize)
+}
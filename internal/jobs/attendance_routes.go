@@ -0,0 +1,54 @@
+// internal/jobs/attendance_routes.go
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// attendancePingBatchSize membatasi ping yang dihapus dalam satu putaran.
+const attendancePingBatchSize = 5000
+
+// AttendanceRouteRetention menghapus ping lokasi sesi field yang lebih tua dari
+// route.retention_days. Ringkasan rute sesi lama menjadi kosong; record absensinya tetap.
+type AttendanceRouteRetention struct {
+	attendances repository.AttendanceRepository
+	settings    *settings.Store
+	interval    time.Duration
+}
+
+// NewAttendanceRouteRetentionFromEnv membuat job berdasarkan environment variables.
+// Mengembalikan nil jika job dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - ATTENDANCE_ROUTE_RETENTION_INTERVAL: Jeda antar pembersihan. Default: 1h. 0 menonaktifkan job.
+func NewAttendanceRouteRetentionFromEnv(attendances repository.AttendanceRepository, settingsStore *settings.Store) *AttendanceRouteRetention {
+	interval := configs.GetEnvDuration("ATTENDANCE_ROUTE_RETENTION_INTERVAL", time.Hour)
+	if interval <= 0 {
+		zlog.Info().Msg("Attendance route retention job disabled")
+		return nil
+	}
+	return &AttendanceRouteRetention{attendances: attendances, settings: settingsStore, interval: interval}
+}
+
+// Name mengembalikan nama job di scheduler.
+func (j *AttendanceRouteRetention) Name() string {
+	return "attendance_route_retention"
+}
+
+// Interval mengembalikan jeda antar run.
+func (j *AttendanceRouteRetention) Interval() time.Duration {
+	return j.interval
+}
+
+// RunOnce menghapus satu batch ping kedaluwarsa dan mengembalikan jumlahnya. Sisa batch
+// diproses di putaran berikutnya.
+func (j *AttendanceRouteRetention) RunOnce(ctx context.Context) (int, error) {
+	before := time.Now().Add(-j.settings.RouteRetention(ctx))
+	return j.attendances.DeleteAttendancePingsBefore(ctx, before, attendancePingBatchSize)
+}
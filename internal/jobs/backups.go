@@ -0,0 +1,47 @@
+// internal/jobs/backups.go
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/backup"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// BackupRun membuat backup database yang diminta admin (status pending) dan menyimpannya di
+// storage. Satu run membuat paling banyak satu backup.
+type BackupRun struct {
+	service  *backup.Service
+	interval time.Duration
+}
+
+// NewBackupRunFromEnv membuat job berdasarkan environment variables.
+// Mengembalikan nil jika job dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - BACKUP_INTERVAL: Jeda antar pemeriksaan backup pending. Default: 30s. 0 menonaktifkan job.
+func NewBackupRunFromEnv(service *backup.Service) *BackupRun {
+	interval := configs.GetEnvDuration("BACKUP_INTERVAL", 30*time.Second)
+	if interval <= 0 {
+		zlog.Info().Msg("Backup job disabled")
+		return nil
+	}
+	return &BackupRun{service: service, interval: interval}
+}
+
+// Name mengembalikan nama job di scheduler.
+func (j *BackupRun) Name() string {
+	return "backups"
+}
+
+// Interval mengembalikan jeda antar run.
+func (j *BackupRun) Interval() time.Duration {
+	return j.interval
+}
+
+// RunOnce membuat satu backup pending (jika ada) dan mengembalikan jumlah backup yang selesai.
+func (j *BackupRun) RunOnce(ctx context.Context) (int, error) {
+	return j.service.Run(ctx)
+}
@@ -0,0 +1,70 @@
+// internal/jobs/contractor_expiry.go
+
+// Package jobs berisi pekerjaan latar belakang periodik yang berjalan di dalam proses API.
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// ContractorExpiry menonaktifkan contractor yang masa aksesnya (access_valid_until) sudah lewat.
+// Login dan middleware Protected() sudah menolak contractor kedaluwarsa; job ini menjaga
+// status is_active tetap konsisten untuk listing dan laporan. Query-nya idempoten, sehingga
+// aman dijalankan bersamaan oleh beberapa instance.
+type ContractorExpiry struct {
+	users    repository.UserRepository
+	settings *settings.Store
+	events   events.Publisher // nil = tanpa event UserDeactivated
+	interval time.Duration
+}
+
+// NewContractorExpiryFromEnv membuat job berdasarkan environment variables.
+// Mengembalikan nil jika job dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - CONTRACTOR_EXPIRY_INTERVAL: Jeda antar pengecekan. Default: 1h. 0 menonaktifkan job.
+func NewContractorExpiryFromEnv(users repository.UserRepository, settingsStore *settings.Store, eventBus events.Publisher) *ContractorExpiry {
+	interval := configs.GetEnvDuration("CONTRACTOR_EXPIRY_INTERVAL", time.Hour)
+	if interval <= 0 {
+		zlog.Info().Msg("Contractor expiry job disabled")
+		return nil
+	}
+	return &ContractorExpiry{users: users, settings: settingsStore, events: eventBus, interval: interval}
+}
+
+// Name mengembalikan nama job di scheduler.
+func (j *ContractorExpiry) Name() string {
+	return "contractor_expiry"
+}
+
+// Interval mengembalikan jeda antar run.
+func (j *ContractorExpiry) Interval() time.Duration {
+	return j.interval
+}
+
+// RunOnce menonaktifkan contractor yang masa aksesnya berakhir sebelum hari ini
+// (zona waktu default sistem), mem-publish UserDeactivated untuk masing-masing, dan
+// mengembalikan jumlah user yang dinonaktifkan.
+func (j *ContractorExpiry) RunOnce(ctx context.Context) (int, error) {
+	today := time.Now().In(j.settings.DefaultLocation(ctx))
+	ids, err := j.users.DeactivateExpiredContractors(ctx, today)
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) > 0 {
+		zlog.Info().Ints("user_ids", ids).Str("date", today.Format("2006-01-02")).Msg("Deactivated contractors with expired access")
+	}
+	if j.events != nil {
+		for _, id := range ids {
+			j.events.Publish(ctx, events.Event{Name: events.UserDeactivated, UserID: id, Data: map[string]any{"reason": "access_expired"}})
+		}
+	}
+	return len(ids), nil
+}
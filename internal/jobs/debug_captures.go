@@ -0,0 +1,51 @@
+// internal/jobs/debug_captures.go
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// debugCaptureBatchSize membatasi rekaman yang dihapus dalam satu putaran.
+const debugCaptureBatchSize = 5000
+
+// DebugCaptureRetention menghapus rekaman request/response debug yang melewati expires_at
+// (debug.capture_ttl_hours saat direkam).
+type DebugCaptureRetention struct {
+	captures repository.DebugCaptureRepository
+	interval time.Duration
+}
+
+// NewDebugCaptureRetentionFromEnv membuat job berdasarkan environment variables.
+// Mengembalikan nil jika job dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - DEBUG_CAPTURE_RETENTION_INTERVAL: Jeda antar pembersihan. Default: 1h. 0 menonaktifkan job.
+func NewDebugCaptureRetentionFromEnv(captures repository.DebugCaptureRepository) *DebugCaptureRetention {
+	interval := configs.GetEnvDuration("DEBUG_CAPTURE_RETENTION_INTERVAL", time.Hour)
+	if interval <= 0 {
+		zlog.Info().Msg("Debug capture retention job disabled")
+		return nil
+	}
+	return &DebugCaptureRetention{captures: captures, interval: interval}
+}
+
+// Name mengembalikan nama job di scheduler.
+func (j *DebugCaptureRetention) Name() string {
+	return "debug_capture_retention"
+}
+
+// Interval mengembalikan jeda antar run.
+func (j *DebugCaptureRetention) Interval() time.Duration {
+	return j.interval
+}
+
+// RunOnce menghapus satu batch rekaman kedaluwarsa dan mengembalikan jumlahnya. Sisa batch
+// diproses di putaran berikutnya.
+func (j *DebugCaptureRetention) RunOnce(ctx context.Context) (int, error) {
+	return j.captures.DeleteExpiredDebugCaptures(ctx, time.Now(), debugCaptureBatchSize)
+}
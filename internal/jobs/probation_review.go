@@ -0,0 +1,99 @@
+// internal/jobs/probation_review.go
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/notify"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// ProbationReview memberi tahu HR (lewat notify.Notifier) tentang karyawan yang masa
+// probation-nya akan/sudah berakhir, satu notifikasi per karyawan. User yang berhasil
+// dinotifikasi ditandai agar tidak dikirim ulang; yang gagal dicoba lagi di putaran berikutnya.
+type ProbationReview struct {
+	users    repository.UserRepository
+	settings *settings.Store
+	notifier notify.Notifier
+	interval time.Duration
+	leadDays int
+}
+
+// NewProbationReviewFromEnv membuat job berdasarkan environment variables.
+// Mengembalikan nil jika job dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - PROBATION_REVIEW_INTERVAL: Jeda antar pengecekan. Default: 1h. 0 menonaktifkan job.
+//   - PROBATION_REVIEW_LEAD_DAYS: Berapa hari sebelum probation_end HR diberi tahu. Default: 7.
+func NewProbationReviewFromEnv(users repository.UserRepository, settingsStore *settings.Store, notifier notify.Notifier) *ProbationReview {
+	interval := configs.GetEnvDuration("PROBATION_REVIEW_INTERVAL", time.Hour)
+	if interval <= 0 {
+		zlog.Info().Msg("Probation review job disabled")
+		return nil
+	}
+	return &ProbationReview{
+		users:    users,
+		settings: settingsStore,
+		notifier: notifier,
+		interval: interval,
+		leadDays: max(configs.GetEnvInt("PROBATION_REVIEW_LEAD_DAYS", 7), 0),
+	}
+}
+
+// Name mengembalikan nama job di scheduler.
+func (j *ProbationReview) Name() string {
+	return "probation_review"
+}
+
+// Interval mengembalikan jeda antar run.
+func (j *ProbationReview) Interval() time.Duration {
+	return j.interval
+}
+
+// RunOnce mengirim notifikasi untuk probation yang berakhir paling lambat hari ini + leadDays
+// (zona waktu default sistem) dan mengembalikan jumlah karyawan yang berhasil dinotifikasi.
+func (j *ProbationReview) RunOnce(ctx context.Context) (int, error) {
+	today := time.Now().In(j.settings.DefaultLocation(ctx))
+	users, err := j.users.GetPendingProbationReviews(ctx, today.AddDate(0, 0, j.leadDays))
+	if err != nil {
+		return 0, err
+	}
+
+	notified := make([]int, 0, len(users))
+	for _, u := range users {
+		name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+		if name == "" {
+			name = u.Username
+		}
+		msg := notify.Message{
+			Topic:   notify.TopicProbationEnding,
+			Subject: fmt.Sprintf("Probation ending: %s", name),
+			Body:    fmt.Sprintf("Probation of %s (%s) ends on %s. Please review their employment status.", name, u.Username, *u.ProbationEnd),
+			Data: map[string]any{
+				"user_id":       u.ID,
+				"username":      u.Username,
+				"hire_date":     u.HireDate,
+				"probation_end": *u.ProbationEnd,
+			},
+		}
+		if err := j.notifier.Notify(ctx, msg); err != nil {
+			zlog.Warn().Err(err).Int("user_id", u.ID).Msg("Failed to notify HR about probation ending")
+			continue
+		}
+		notified = append(notified, u.ID)
+	}
+
+	if err := j.users.MarkProbationNotified(ctx, notified); err != nil {
+		return 0, err
+	}
+	if len(notified) > 0 {
+		zlog.Info().Ints("user_ids", notified).Msg("Notified HR about ending probations")
+	}
+	return len(notified), nil
+}
@@ -0,0 +1,87 @@
+// internal/jobs/report_exports.go
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/reports"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// reportExportBatchSize membatasi ekspor yang dibuat/dihapus dalam satu putaran.
+const reportExportBatchSize = 20
+
+// ReportExportGeneration membuat file ekspor laporan yang diminta admin (status pending) dan
+// menyimpannya di storage.
+type ReportExportGeneration struct {
+	service  *reports.Service
+	interval time.Duration
+}
+
+// NewReportExportGenerationFromEnv membuat job berdasarkan environment variables.
+// Mengembalikan nil jika job dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - REPORT_EXPORT_INTERVAL: Jeda antar pembuatan ekspor. Default: 30s. 0 menonaktifkan job.
+func NewReportExportGenerationFromEnv(service *reports.Service) *ReportExportGeneration {
+	interval := configs.GetEnvDuration("REPORT_EXPORT_INTERVAL", 30*time.Second)
+	if interval <= 0 {
+		zlog.Info().Msg("Report export generation job disabled")
+		return nil
+	}
+	return &ReportExportGeneration{service: service, interval: interval}
+}
+
+// Name mengembalikan nama job di scheduler.
+func (j *ReportExportGeneration) Name() string {
+	return "report_exports"
+}
+
+// Interval mengembalikan jeda antar run.
+func (j *ReportExportGeneration) Interval() time.Duration {
+	return j.interval
+}
+
+// RunOnce membuat satu batch ekspor pending dan mengembalikan jumlah yang selesai.
+func (j *ReportExportGeneration) RunOnce(ctx context.Context) (int, error) {
+	return j.service.Generate(ctx, reportExportBatchSize)
+}
+
+// ReportExportCleanup menghapus file ekspor laporan yang melewati expires_at. Baris ekspor
+// tetap ada dengan status purged sehingga tautan unduhannya dijawab 410 Gone.
+type ReportExportCleanup struct {
+	service  *reports.Service
+	interval time.Duration
+}
+
+// NewReportExportCleanupFromEnv membuat job berdasarkan environment variables.
+// Mengembalikan nil jika job dinonaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - REPORT_EXPORT_CLEANUP_INTERVAL: Jeda antar pembersihan. Default: 1h. 0 menonaktifkan job.
+func NewReportExportCleanupFromEnv(service *reports.Service) *ReportExportCleanup {
+	interval := configs.GetEnvDuration("REPORT_EXPORT_CLEANUP_INTERVAL", time.Hour)
+	if interval <= 0 {
+		zlog.Info().Msg("Report export cleanup job disabled")
+		return nil
+	}
+	return &ReportExportCleanup{service: service, interval: interval}
+}
+
+// Name mengembalikan nama job di scheduler.
+func (j *ReportExportCleanup) Name() string {
+	return "report_export_cleanup"
+}
+
+// Interval mengembalikan jeda antar run.
+func (j *ReportExportCleanup) Interval() time.Duration {
+	return j.interval
+}
+
+// RunOnce menghapus satu batch ekspor kedaluwarsa dan mengembalikan jumlahnya. Sisa batch
+// diproses di putaran berikutnya.
+func (j *ReportExportCleanup) RunOnce(ctx context.Context) (int, error) {
+	return j.service.Cleanup(ctx, reportExportBatchSize)
+}
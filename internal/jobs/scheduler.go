@@ -0,0 +1,170 @@
+// internal/jobs/scheduler.go
+package jobs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/lock"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// ErrUnknownJob dikembalikan Trigger untuk job yang tidak terdaftar di instance ini.
+var ErrUnknownJob = errors.New("job is not registered on this instance")
+
+// Job adalah pekerjaan periodik yang dijalankan Scheduler.
+type Job interface {
+	Name() string
+	Interval() time.Duration
+	// RunOnce menjalankan satu putaran dan mengembalikan jumlah item yang diproses.
+	RunOnce(ctx context.Context) (int, error)
+}
+
+// Scheduler menjalankan job terdaftar sesuai interval masing-masing. Definisi, jadwal berikutnya,
+// dan status run terakhir disimpan di database (scheduled_jobs) dan dibagi semua instance;
+// eksekusi diserialkan dengan lock terdistribusi per job (lock key "job:<nama>"), sehingga dengan beberapa replika API setiap
+// job tetap berjalan sekali per interval.
+type Scheduler struct {
+	repo   repository.JobRepository
+	locker lock.Locker
+	poll   time.Duration
+
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+// NewSchedulerFromEnv membuat scheduler berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - SCHEDULER_POLL_INTERVAL: Jeda pengecekan job yang jatuh tempo. Default: 30s.
+func NewSchedulerFromEnv(repo repository.JobRepository, locker lock.Locker) *Scheduler {
+	return &Scheduler{
+		repo:   repo,
+		locker: locker,
+		poll:   max(configs.GetEnvDuration("SCHEDULER_POLL_INTERVAL", 30*time.Second), time.Second),
+		jobs:   map[string]Job{},
+	}
+}
+
+// Register mendaftarkan job. Dipanggil sebelum Start.
+func (s *Scheduler) Register(j Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[j.Name()] = j
+}
+
+func (s *Scheduler) job(name string) Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobs[name]
+}
+
+// Start menyimpan definisi job terdaftar lalu menjalankan job yang jatuh tempo setiap poll
+// interval, sampai ctx dibatalkan. Dipanggil sebagai goroutine.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defs := make([]models.JobDefinition, 0, len(s.jobs))
+	for name, j := range s.jobs {
+		defs = append(defs, models.JobDefinition{Name: name, Interval: j.Interval()})
+	}
+	s.mu.Unlock()
+	slices.SortFunc(defs, func(a, b models.JobDefinition) int { return strings.Compare(a.Name, b.Name) })
+
+	// Database bisa belum siap sesaat setelah startup: coba lagi setiap poll.
+	ticker := time.NewTicker(s.poll)
+	defer ticker.Stop()
+	for {
+		err := s.repo.RegisterJobs(ctx, defs)
+		if err == nil {
+			break
+		}
+		zlog.Error().Err(err).Msg("Failed to register scheduled jobs")
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+	zlog.Info().Dur("poll_interval", s.poll).Interface("jobs", defs).Msg("Job scheduler started")
+	for {
+		s.runDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue menjalankan (berurutan) job terdaftar yang sudah jatuh tempo.
+func (s *Scheduler) runDue(ctx context.Context) {
+	jobs, err := s.repo.GetJobs(ctx)
+	if err != nil {
+		zlog.Error().Err(err).Msg("Failed to get scheduled jobs")
+		return
+	}
+	now := time.Now()
+	for _, sj := range jobs {
+		if j := s.job(sj.Name); j != nil && !sj.NextRunAt.After(now) {
+			s.run(ctx, j, models.JobTriggerSchedule)
+		}
+	}
+}
+
+// run menjalankan j di bawah lock job. Run terjadwal dilewati jika job sudah dijalankan instance
+// lain; run manual selalu berjalan kecuali job sedang berjalan di instance lain.
+func (s *Scheduler) run(ctx context.Context, j Job, trigger string) {
+	logger := zlog.With().Str("job", j.Name()).Str("trigger", trigger).Logger()
+	locked, err := s.locker.WithLock(ctx, "job:"+j.Name(), func(ctx context.Context) error {
+		started, err := s.repo.StartJobRun(ctx, j.Name(), trigger, trigger == models.JobTriggerManual)
+		if err != nil || !started {
+			return err
+		}
+		begin := time.Now()
+		result, runErr := j.RunOnce(ctx)
+		var errMsg *string
+		if runErr != nil {
+			msg := runErr.Error()
+			errMsg = &msg
+			logger.Error().Err(runErr).Msg("Scheduled job failed")
+		} else {
+			logger.Debug().Int("result", result).Dur("duration", time.Since(begin)).Msg("Scheduled job finished")
+		}
+		return s.repo.FinishJobRun(context.WithoutCancel(ctx), j.Name(), result, errMsg, time.Since(begin))
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to run scheduled job")
+	} else if !locked {
+		logger.Debug().Msg("Scheduled job is running on another instance, skipped")
+	}
+}
+
+// Trigger menjalankan job name segera di background (di luar jadwal) dan memajukan jadwal
+// berikutnya satu interval. Mengembalikan ErrUnknownJob jika job tidak terdaftar di instance ini.
+func (s *Scheduler) Trigger(ctx context.Context, name string) error {
+	j := s.job(name)
+	if j == nil {
+		return ErrUnknownJob
+	}
+	go s.run(context.WithoutCancel(ctx), j, models.JobTriggerManual)
+	return nil
+}
+
+// Jobs mengembalikan semua job tersimpan, ditandai Registered jika aktif di instance ini.
+func (s *Scheduler) Jobs(ctx context.Context) ([]models.ScheduledJob, error) {
+	jobs, err := s.repo.GetJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		jobs[i].Registered = s.job(jobs[i].Name) != nil
+	}
+	return jobs, nil
+}
@@ -0,0 +1,119 @@
+// internal/jobs/shift_reminder.go
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/inbox"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/otp"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// ShiftReminder mengirim pengingat check-in (inbox in-app dan push notification, plus SMS bagi
+// user yang memilih pengingat SMS) ke user yang shift-nya akan mulai dalam rentang lead. Jadwal ditandai (push_reminders) sebelum dikirim,
+// sehingga tiap jadwal diingatkan paling banyak sekali meskipun job berjalan di beberapa instance.
+type ShiftReminder struct {
+	devices  repository.DeviceRepository
+	users    repository.UserRepository
+	inbox    *inbox.Dispatcher
+	sms      *otp.Service // nil = SMS tidak dikonfigurasi
+	interval time.Duration
+	lead     time.Duration
+}
+
+// NewShiftReminderFromEnv membuat job berdasarkan environment variables.
+// Mengembalikan nil jika job dinonaktifkan atau push notification maupun SMS tidak dikonfigurasi.
+//
+// Variabel Environment yang didukung:
+//   - SHIFT_REMINDER_INTERVAL: Jeda antar pengecekan. Default: 5m. 0 menonaktifkan job.
+//   - SHIFT_REMINDER_LEAD: Berapa lama sebelum shift mulai pengingat dikirim. Default: 30m.
+func NewShiftReminderFromEnv(devices repository.DeviceRepository, users repository.UserRepository, inboxDispatcher *inbox.Dispatcher, smsService *otp.Service) *ShiftReminder {
+	interval := configs.GetEnvDuration("SHIFT_REMINDER_INTERVAL", 5*time.Minute)
+	if interval <= 0 || (!inboxDispatcher.PushEnabled() && smsService == nil) {
+		zlog.Info().Msg("Shift reminder job disabled")
+		return nil
+	}
+	return &ShiftReminder{
+		devices:  devices,
+		users:    users,
+		inbox:    inboxDispatcher,
+		sms:      smsService,
+		interval: interval,
+		lead:     max(configs.GetEnvDuration("SHIFT_REMINDER_LEAD", 30*time.Minute), interval),
+	}
+}
+
+// Name mengembalikan nama job di scheduler.
+func (j *ShiftReminder) Name() string {
+	return "shift_reminder"
+}
+
+// Interval mengembalikan jeda antar run.
+func (j *ShiftReminder) Interval() time.Duration {
+	return j.interval
+}
+
+// RunOnce mengirim pengingat untuk shift yang mulai antara sekarang dan sekarang+lead
+// (zona waktu server, sama seperti check-in) dan mengembalikan jumlah pengingat yang dikirim.
+func (j *ShiftReminder) RunOnce(ctx context.Context) (int, error) {
+	now := time.Now()
+	until := now.Add(j.lead)
+	reminders, err := j.devices.GetPendingShiftReminders(ctx, now, until)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, r := range reminders {
+		start, err := time.ParseInLocation("2006-01-02 15:04:05", r.Date+" "+r.StartTime, time.Local)
+		if err != nil {
+			zlog.Warn().Err(err).Int("schedule_id", r.ScheduleID).Msg("Skipping shift reminder with unparsable start time")
+			continue
+		}
+		if start.Before(now) || start.After(until) {
+			continue
+		}
+		marked, err := j.devices.MarkShiftReminderSent(ctx, r.ScheduleID)
+		if err != nil {
+			return sent, err
+		}
+		if !marked {
+			continue // Sudah dikirim instance lain
+		}
+		body := fmt.Sprintf("Your %s shift starts at %s. Don't forget to check in.", r.ShiftName, start.Format("15:04"))
+		if r.SMS && j.sms != nil {
+			j.sendSMS(ctx, r.UserID, body)
+		}
+		err = j.inbox.Deliver(ctx, &models.Notification{
+			UserID: r.UserID, Type: models.NotificationShiftReminder,
+			Title: "Shift starts soon",
+			Body:  body,
+			Data:  map[string]string{"schedule_id": strconv.Itoa(r.ScheduleID), "date": r.Date},
+		})
+		if err != nil {
+			continue // Sudah dicatat Deliver
+		}
+		sent++
+	}
+	if sent > 0 {
+		zlog.Info().Int("sent", sent).Msg("Sent shift reminders")
+	}
+	return sent, nil
+}
+
+// sendSMS mengirim pengingat lewat SMS; kegagalannya hanya dicatat (inbox tetap dikirim).
+func (j *ShiftReminder) sendSMS(ctx context.Context, userID int, body string) {
+	user, err := j.users.GetUserByID(ctx, userID)
+	if err == nil {
+		err = j.sms.Notify(ctx, user, body)
+	}
+	if err != nil {
+		zlog.Error().Err(err).Int("user_id", userID).Msg("Failed to send shift reminder SMS")
+	}
+}
@@ -0,0 +1,107 @@
+// internal/lock/lock.go
+
+// Package lock menyediakan lock terdistribusi agar pekerjaan latar belakang (job terjadwal,
+// worker) tidak dijalankan ganda saat API berjalan dengan beberapa replika. Backend default
+// adalah advisory lock Postgres, sehingga tidak butuh infrastruktur tambahan.
+package lock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// namespace adalah kunci pertama advisory lock dua-argumen untuk semua lock paket ini
+// (kunci kedua = hashtext(key)), terpisah dari advisory lock transaksi di repository.
+const namespace = 4676
+
+// Locker menjalankan fungsi secara eksklusif per key di seluruh instance.
+type Locker interface {
+	// WithLock menjalankan fn sambil memegang lock key. Tidak menunggu: mengembalikan false
+	// tanpa menjalankan fn jika lock sedang dipegang pihak lain.
+	WithLock(ctx context.Context, key string, fn func(ctx context.Context) error) (bool, error)
+}
+
+// NewFromEnv membuat Locker berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - LOCK_BACKEND: 'postgres' (default, advisory lock pada database primary) atau 'local'
+//     (mutex dalam proses; hanya untuk deployment satu instance).
+func NewFromEnv(pool *pgxpool.Pool) (Locker, error) {
+	backend := strings.ToLower(configs.GetEnv("LOCK_BACKEND", "postgres"))
+	switch backend {
+	case "postgres":
+		return NewPostgres(pool), nil
+	case "local":
+		zlog.Warn().Msg("LOCK_BACKEND=local: background jobs are not coordinated across instances")
+		return NewLocal(), nil
+	default:
+		return nil, fmt.Errorf("unsupported LOCK_BACKEND '%s'", backend)
+	}
+}
+
+// postgresLocker memakai advisory lock sesi Postgres pada satu koneksi khusus.
+type postgresLocker struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgres membuat Locker berbasis advisory lock Postgres. Lock otomatis lepas jika koneksi
+// putus (mis. instance mati di tengah pekerjaan).
+func NewPostgres(pool *pgxpool.Pool) Locker {
+	return &postgresLocker{pool: pool}
+}
+
+func (l *postgresLocker) WithLock(ctx context.Context, key string, fn func(ctx context.Context) error) (bool, error) {
+	c, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error acquiring connection for lock %s: %w", key, err)
+	}
+	defer c.Release()
+	var locked bool
+	if err := c.QueryRow(ctx, `SELECT pg_try_advisory_lock($1, hashtext($2))`, namespace, key).Scan(&locked); err != nil {
+		return false, fmt.Errorf("error acquiring lock %s: %w", key, err)
+	}
+	if !locked {
+		return false, nil
+	}
+	defer func() {
+		// Tetap dilepas walaupun ctx sudah dibatalkan.
+		if _, err := c.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1, hashtext($2))`, namespace, key); err != nil {
+			zlog.Ctx(ctx).Error().Err(err).Str("lock", key).Msg("Error releasing lock")
+			c.Conn().Close(context.Background()) // Tutup koneksi agar lock tidak tertinggal di pool
+		}
+	}()
+	return true, fn(ctx)
+}
+
+// localLocker adalah lock dalam proses untuk deployment satu instance.
+type localLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+// NewLocal membuat Locker dalam proses (tidak terkoordinasi antar instance).
+func NewLocal() Locker {
+	return &localLocker{held: map[string]bool{}}
+}
+
+func (l *localLocker) WithLock(ctx context.Context, key string, fn func(ctx context.Context) error) (bool, error) {
+	l.mu.Lock()
+	if l.held[key] {
+		l.mu.Unlock()
+		return false, nil
+	}
+	l.held[key] = true
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		delete(l.held, key)
+		l.mu.Unlock()
+	}()
+	return true, fn(ctx)
+}
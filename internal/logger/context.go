@@ -0,0 +1,30 @@
+// internal/logger/context.go
+package logger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Logger per-request disimpan di context.Context (lihat zerolog.Logger.WithContext).
+// Middleware mengisi context request dengan child logger berisi request_id, user_id, dan role,
+// sehingga handler dan repository cukup memanggil FromContext(ctx) (atau zlog.Ctx(ctx))
+// agar semua baris log dari satu request otomatis bisa dikorelasikan.
+
+// FromContext mengembalikan logger yang terpasang di ctx.
+// Jika ctx tidak membawa logger, dikembalikan logger global.
+func FromContext(ctx context.Context) *zerolog.Logger {
+	if ctx == nil {
+		return &log.Logger
+	}
+	return zerolog.Ctx(ctx)
+}
+
+// WithFields mengembalikan context baru berisi child logger dari logger di ctx
+// yang ditambah field dari fn.
+func WithFields(ctx context.Context, fn func(zerolog.Context) zerolog.Context) context.Context {
+	l := fn(FromContext(ctx).With()).Logger()
+	return l.WithContext(ctx)
+}
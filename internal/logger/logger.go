@@ -9,9 +9,11 @@ import (
 	"strconv"       // Untuk konversi string (dari env vars) ke bool/int
 	"time"
 
-	"github.com/rs/zerolog"            // Core library Zerolog
-	"github.com/rs/zerolog/log"        // Akses ke logger global Zerolog
-	"gopkg.in/natefinch/lumberjack.v2" // Library untuk rotasi file log
+	"github.com/rakaarfi/attendance-system-be/configs"            // LogConfig (level per modul & sampling)
+	"github.com/rakaarfi/attendance-system-be/internal/buildinfo" // Versi aplikasi untuk field version
+	"github.com/rs/zerolog"                                       // Core library Zerolog
+	"github.com/rs/zerolog/log"                                   // Akses ke logger global Zerolog
+	"gopkg.in/natefinch/lumberjack.v2"                            // Library untuk rotasi file log
 )
 
 // SetupLogger mengkonfigurasi logger global Zerolog berdasarkan environment variables.
@@ -22,8 +24,13 @@ import (
 // yang harus ditutup menggunakan 'defer' di fungsi main untuk memastikan buffer ditulis.
 // Mengembalikan nil jika file logging tidak aktif atau gagal diinisialisasi.
 //
+// Level dan sampling diambil dari cfg (configs.LoadLogConfig); sisanya dibaca dari env.
+//
 // Variabel Environment yang didukung:
 //   - LOG_LEVEL: Tingkat log minimum (trace, debug, info, warn, error, fatal, panic). Default: info.
+//   - LOG_LEVEL_<MODUL>: Override level per modul (http, handler, repository), misal LOG_LEVEL_REPOSITORY=warn.
+//   - LOG_SAMPLE_EVERY / LOG_SAMPLE_BURST / LOG_SAMPLE_PERIOD / LOG_SAMPLE_MODULES: Sampling log info/debug
+//     untuk modul bervolume tinggi (default modul: http, repository). Lihat configs.LogConfig.
 //   - LOG_FORMAT: Format output konsol ('json' atau lainnya untuk human-readable). Default: human-readable.
 //   - LOG_FILE_ENABLED: Aktifkan logging ke file ('true' atau 'false'). Default: false.
 //   - LOG_FILE_PATH: Path lengkap ke file log. Default: ./logs/app.log.
@@ -31,9 +38,12 @@ import (
 //   - LOG_FILE_MAX_BACKUPS: Jumlah maksimum file log lama yang disimpan. Default: 5.
 //   - LOG_FILE_MAX_AGE_DAYS: Usia maksimum file log lama (hari) sebelum dihapus. Default: 30.
 //   - LOG_FILE_COMPRESS: Kompres file log lama ('true' atau 'false'). Default: false.
-func SetupLogger() io.Closer {
+//
+// Field log yang namanya menandakan kredensial (lihat IsSensitiveField) selalu di-redact
+// sebelum ditulis; tidak ada env var untuk mematikannya, juga di mode debug.
+func SetupLogger(cfg configs.LogConfig) io.Closer {
 	// --- Konfigurasi Tingkat Log Global ---
-	logLevelStr := os.Getenv("LOG_LEVEL")
+	logLevelStr := cfg.Level
 	logLevel, err := zerolog.ParseLevel(logLevelStr)
 	// Jika LOG_LEVEL tidak valid atau kosong, gunakan 'info' sebagai default.
 	if err != nil || logLevelStr == "" {
@@ -41,7 +51,9 @@ func SetupLogger() io.Closer {
 		// Gunakan fmt ke Stderr karena logger mungkin belum siap
 		fmt.Fprintf(os.Stderr, "[WARN] Invalid or missing LOG_LEVEL env var ('%s'), using default: %s\n", logLevelStr, logLevel.String())
 	}
-	zerolog.SetGlobalLevel(logLevel) // Terapkan level log secara global
+	// Level global = level paling verbose yang dibutuhkan (LOG_LEVEL atau override modul);
+	// logger global sendiri tetap memakai LOG_LEVEL (lihat .Level() di bawah).
+	zerolog.SetGlobalLevel(configureModules(cfg, logLevel))
 
 	// --- Konfigurasi Writer (Output Tujuan Log) ---
 	var writers []io.Writer // Slice untuk menampung semua tujuan output (konsol, file)
@@ -106,20 +118,27 @@ func SetupLogger() io.Closer {
 
 	// --- Gabungkan Semua Writer Menjadi Satu ---
 	// MultiLevelWriter memungkinkan log ditulis ke semua writer yang ada di slice 'writers'.
-	multiWriter := zerolog.MultiLevelWriter(writers...)
+	// Dibungkus redactingWriter agar field kredensial (password, hash, token, dst.) tidak pernah
+	// tertulis ke output mana pun, termasuk saat LOG_LEVEL=debug/trace.
+	multiWriter := NewRedactingWriter(zerolog.MultiLevelWriter(writers...))
 
 	// --- Atur Logger Global Zerolog ---
 	// Buat instance logger baru yang menulis ke multiWriter.
 	// .With() memulai context builder untuk field global.
 	// .Timestamp() menambahkan field timestamp ke semua log.
 	// .Caller() menambahkan field caller (nama file:baris) ke semua log.
-	log.Logger = zerolog.New(multiWriter).With().Timestamp().Caller().Logger()
+	// .Str("version") menambahkan versi aplikasi (buildinfo.AppVersion) agar log bisa dikaitkan dengan deployment.
+	log.Logger = zerolog.New(multiWriter).Level(logLevel).With().Timestamp().Caller().Str("version", buildinfo.AppVersion()).Logger()
+	// Fallback untuk zerolog.Ctx(ctx) jika context tidak membawa logger per-request
+	// (mis. context.Background() di goroutine background): gunakan logger global.
+	zerolog.DefaultContextLogger = &log.Logger
 
 	// Log pesan konfirmasi setelah logger utama siap.
-	log.Info().Msgf("Global logger initialized. Level: %s. Format: %s. File Logging: %t.",
-		zerolog.GlobalLevel().String(),
+	log.Info().Msgf("Global logger initialized. Level: %s. Format: %s. File Logging: %t. Sampling: every %d.",
+		logLevel.String(),
 		logFormat,                           // Atau tentukan format console secara eksplisit
-		logFileEnabled && fileCloser != nil) // Konfirmasi file logging benar-benar aktif
+		logFileEnabled && fileCloser != nil, // Konfirmasi file logging benar-benar aktif
+		max(cfg.SampleEvery, 1))
 
 	// Kembalikan fileCloser (bisa nil) agar bisa ditutup di main.
 	return fileCloser
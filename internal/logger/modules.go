@@ -0,0 +1,78 @@
+// internal/logger/modules.go
+package logger
+
+import (
+	"context"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rs/zerolog"
+)
+
+// Nama modul untuk override level (LOG_LEVEL_<MODUL>) dan sampling (LOG_SAMPLE_MODULES).
+const (
+	ModuleHTTP       = "http"       // Log akses request dari middleware logger.
+	ModuleHandler    = "handler"    // Log dari handler API.
+	ModuleRepository = "repository" // Log dari lapisan repository (query DB).
+	ModuleDatabase   = "database"   // Log dari pgx query tracer (query lambat).
+)
+
+// moduleSettings adalah override level dan sampler untuk satu modul.
+type moduleSettings struct {
+	level    zerolog.Level
+	hasLevel bool
+	sampler  zerolog.Sampler // nil = tanpa sampling
+}
+
+// modules diisi sekali oleh SetupLogger dan hanya dibaca setelahnya.
+var modules = map[string]moduleSettings{}
+
+// configureModules menyiapkan override per modul dari cfg dan mengembalikan level
+// paling rendah yang dibutuhkan (untuk zerolog.SetGlobalLevel), karena level global
+// membatasi semua logger termasuk yang di-override lebih verbose.
+func configureModules(cfg configs.LogConfig, base zerolog.Level) zerolog.Level {
+	minLevel := base
+	modules = map[string]moduleSettings{}
+	for module, level := range cfg.ModuleLevels {
+		modules[module] = moduleSettings{level: level, hasLevel: true}
+		if level < minLevel {
+			minLevel = level
+		}
+	}
+	if cfg.SamplingEnabled() {
+		for _, module := range cfg.SampledModules {
+			settings := modules[module]
+			settings.sampler = newInfoSampler(cfg) // Counter terpisah per modul
+			modules[module] = settings
+		}
+	}
+	return minLevel
+}
+
+// newInfoSampler membuat sampler yang hanya berlaku untuk event info/debug/trace;
+// warn, error, dan fatal selalu ditulis.
+func newInfoSampler(cfg configs.LogConfig) zerolog.Sampler {
+	var sampler zerolog.Sampler = &zerolog.BasicSampler{N: uint32(cfg.SampleEvery)}
+	if cfg.SampleBurst > 0 {
+		sampler = &zerolog.BurstSampler{
+			Burst:       uint32(cfg.SampleBurst),
+			Period:      cfg.SamplePeriod,
+			NextSampler: sampler,
+		}
+	}
+	return zerolog.LevelSampler{TraceSampler: sampler, DebugSampler: sampler, InfoSampler: sampler}
+}
+
+// Module mengembalikan logger dari ctx (lihat FromContext) untuk modul tertentu:
+// ditambah field "module", level override LOG_LEVEL_<MODUL>, dan sampling jika modul disampling.
+func Module(ctx context.Context, module string) *zerolog.Logger {
+	l := FromContext(ctx).With().Str("module", module).Logger()
+	if settings, ok := modules[module]; ok {
+		if settings.hasLevel {
+			l = l.Level(settings.level)
+		}
+		if settings.sampler != nil {
+			l = l.Sample(settings.sampler)
+		}
+	}
+	return &l
+}
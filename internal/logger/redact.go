@@ -0,0 +1,133 @@
+// internal/logger/redact.go
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// RedactedValue menggantikan nilai field sensitif di output log.
+const RedactedValue = "[REDACTED]"
+
+// sensitiveFieldMarkers adalah potongan nama field (lowercase, tanpa '_' dan '-') yang dianggap
+// material kredensial. Field yang namanya mengandung salah satu marker selalu di-redact,
+// apa pun level log-nya (termasuk debug/trace).
+var sensitiveFieldMarkers = []string{
+	"password",
+	"passwd",
+	"plaintext",
+	"hash",
+	"secret",
+	"token",
+	"authorization",
+	"cookie",
+	"apikey",
+	"privatekey",
+	"captcha",
+}
+
+// IsSensitiveField mengembalikan true jika nama field log dianggap berisi kredensial.
+// Pencocokan case-insensitive dan mengabaikan '_' / '-' (mis. "stored_hash", "AuthorizationHeader").
+func IsSensitiveField(name string) bool {
+	return containsSensitiveMarker([]byte(name))
+}
+
+// redactingWriter membungkus writer log dan men-scrub field sensitif dari setiap event JSON
+// sebelum diteruskan ke writer asli (konsol/file). Karena dipasang di level writer,
+// redaction berlaku untuk semua logger (global maupun per-request) tanpa bisa dilewati call site.
+type redactingWriter struct {
+	next zerolog.LevelWriter
+}
+
+// NewRedactingWriter membungkus w dengan redaction field sensitif.
+func NewRedactingWriter(w io.Writer) zerolog.LevelWriter {
+	lw, ok := w.(zerolog.LevelWriter)
+	if !ok {
+		lw = zerolog.LevelWriterAdapter{Writer: w}
+	}
+	return &redactingWriter{next: lw}
+}
+
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := w.next.Write(redactEvent(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *redactingWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if _, err := w.next.WriteLevel(level, redactEvent(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// redactEvent mengembalikan event dengan nilai field sensitif diganti RedactedValue.
+// Jalur cepat: event yang tidak mengandung marker sama sekali diteruskan apa adanya.
+// Event yang bukan JSON valid juga diteruskan apa adanya.
+func redactEvent(p []byte) []byte {
+	if !containsSensitiveMarker(p) {
+		return p
+	}
+	var event map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber() // Pertahankan angka apa adanya (tanpa konversi float64)
+	if err := decoder.Decode(&event); err != nil {
+		return p
+	}
+	if !redactMap(event) {
+		return p
+	}
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)                  // Jangan ubah '<', '>', '&' di pesan log
+	if err := encoder.Encode(event); err != nil { // Encode menambahkan '\n' di akhir
+		return p
+	}
+	return out.Bytes()
+}
+
+// redactMap men-scrub field sensitif secara rekursif (termasuk objek bersarang dari Dict()).
+// Mengembalikan true jika ada field yang diubah.
+func redactMap(m map[string]any) bool {
+	changed := false
+	for key, value := range m {
+		if IsSensitiveField(key) {
+			m[key] = RedactedValue
+			changed = true
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok && redactMap(nested) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+func containsSensitiveMarker(p []byte) bool {
+	normalized := normalizeFieldName(p)
+	for _, marker := range sensitiveFieldMarkers {
+		if bytes.Contains(normalized, []byte(marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeFieldName meng-lowercase b dan membuang '_' / '-' dalam satu alokasi.
+func normalizeFieldName(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for _, ch := range b {
+		switch {
+		case ch == '_' || ch == '-':
+			continue
+		case 'A' <= ch && ch <= 'Z':
+			ch += 'a' - 'A'
+		}
+		out = append(out, ch)
+	}
+	return out
+}
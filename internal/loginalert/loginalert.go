@@ -0,0 +1,170 @@
+// internal/loginalert/loginalert.go
+
+// Package loginalert memberi tahu user lewat email saat login berhasil dari perangkat atau
+// negara yang belum pernah dipakai sebelumnya. Email berisi tautan "bukan saya" yang
+// mencabut semua sesi dan mewajibkan reset password.
+package loginalert
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/geoip"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/notify"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// sendTimeout membatasi pengiriman email yang berjalan di luar siklus request.
+const sendTimeout = 30 * time.Second
+
+// Alerter mendeteksi login tidak biasa dan mengirim peringatan ke user.
+type Alerter struct {
+	audit        repository.AuditRepository
+	locator      geoip.Locator // nil = negara tidak dicek
+	notifier     notify.Notifier
+	linkTemplate string
+	tokenTTL     time.Duration
+}
+
+// NewAlerterFromEnv membuat Alerter berdasarkan environment variables.
+// Mengembalikan nil jika fitur dimatikan, atau jika notifier hanya mencatat ke log
+// (tautan "bukan saya" bisa dipakai mereset password, jadi tidak boleh masuk log aplikasi).
+//
+// Variabel Environment yang didukung:
+//   - LOGIN_ALERT_ENABLED: Aktifkan peringatan login. Default: true.
+//   - LOGIN_ALERT_URL: URL frontend untuk tautan "bukan saya" dengan placeholder {token}.
+//     Default: http://localhost:3000/login-alert?token={token}.
+//   - LOGIN_ALERT_TOKEN_TTL: Masa berlaku tautan. Default: 168h (7 hari).
+func NewAlerterFromEnv(audit repository.AuditRepository, locator geoip.Locator, notifier notify.Notifier) (*Alerter, error) {
+	if !strings.EqualFold(configs.GetEnv("LOGIN_ALERT_ENABLED", "true"), "true") {
+		return nil, nil
+	}
+	if notifier == nil || notifier.Provider() == "log" {
+		zlog.Warn().Msg("Login alerts disabled: NOTIFY_PROVIDER must deliver to users (e.g. smtp)")
+		return nil, nil
+	}
+	linkTemplate := configs.GetEnv("LOGIN_ALERT_URL", "http://localhost:3000/login-alert?token={token}")
+	if !strings.Contains(linkTemplate, "{token}") {
+		return nil, fmt.Errorf("LOGIN_ALERT_URL must contain {token}")
+	}
+	a := &Alerter{
+		audit:        audit,
+		locator:      locator,
+		notifier:     notifier,
+		linkTemplate: linkTemplate,
+		tokenTTL:     configs.GetEnvDuration("LOGIN_ALERT_TOKEN_TTL", 7*24*time.Hour),
+	}
+	event := zlog.Info().Str("notifier", notifier.Provider()).Dur("token_ttl", a.tokenTTL)
+	if locator != nil {
+		event = event.Str("geoip", locator.Provider())
+	}
+	event.Msg("Login alerts enabled")
+	return a, nil
+}
+
+// DeviceFingerprint meringkas user agent menjadi ID perangkat pendek untuk audit log.
+func DeviceFingerprint(userAgent string) string {
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Inspect membandingkan login berhasil user dengan riwayat loginnya dan, jika perangkat atau
+// negaranya baru, mengirim peringatan di background. Harus dipanggil sebelum login dicatat
+// ke audit log. Mengembalikan detail (device, country) untuk ditambahkan ke entri audit login.
+// Login pertama user tidak memicu peringatan. Kegagalan hanya di-log.
+func (a *Alerter) Inspect(ctx context.Context, user *models.User, ip, userAgent string) map[string]any {
+	logger := zlog.Ctx(ctx).With().Int("user_id", user.ID).Logger()
+	device := DeviceFingerprint(userAgent)
+	details := map[string]any{"device": device}
+
+	country := ""
+	if a.locator != nil {
+		var err error
+		if country, err = a.locator.Country(ctx, ip); err != nil {
+			logger.Warn().Err(err).Str("geoip", a.locator.Provider()).Msg("GeoIP lookup failed during login")
+		} else if country != "" {
+			details["country"] = country
+		}
+	}
+
+	match, err := a.audit.GetLoginHistoryMatch(ctx, user.ID, device, country)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to compare login with history; skipping login alert")
+		return details
+	}
+	newDevice := !match.KnownDevice
+	newCountry := country != "" && !match.KnownCountry
+	if !match.HasHistory || (!newDevice && !newCountry) {
+		return details
+	}
+	if user.Email == "" {
+		logger.Info().Msg("Unusual login detected but user has no email; alert not sent")
+		return details
+	}
+
+	token, err := utils.GeneratePurposeToken(utils.PurposeLoginAlert, user.ID, user.TokenVersion, a.tokenTTL)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to generate login alert token")
+		return details
+	}
+	msg := a.message(user, ip, userAgent, country, newDevice, newCountry, token)
+	logger.Info().Bool("new_device", newDevice).Bool("new_country", newCountry).Msg("Unusual login detected; sending alert")
+
+	// Kirim di background agar login tidak menunggu server email.
+	sendCtx := logger.WithContext(context.WithoutCancel(ctx))
+	go func() {
+		sendCtx, cancel := context.WithTimeout(sendCtx, sendTimeout)
+		defer cancel()
+		if err := a.notifier.Notify(sendCtx, msg); err != nil {
+			zerolog.Ctx(sendCtx).Error().Err(err).Str("notifier", a.notifier.Provider()).Msg("Failed to send login alert")
+		}
+	}()
+	return details
+}
+
+func (a *Alerter) message(user *models.User, ip, userAgent, country string, newDevice, newCountry bool, token string) notify.Message {
+	reason := "a new device"
+	switch {
+	case newDevice && newCountry:
+		reason = "a new device and country"
+	case newCountry:
+		reason = "a new country"
+	}
+	location := ip
+	if country != "" {
+		location = fmt.Sprintf("%s (%s)", ip, country)
+	}
+	link := strings.ReplaceAll(a.linkTemplate, "{token}", url.QueryEscape(token))
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n", user.FirstName)
+	fmt.Fprintf(&body, "Your account %s was signed in from %s.\n\n", user.Username, reason)
+	fmt.Fprintf(&body, "Time: %s\n", time.Now().UTC().Format(time.RFC1123))
+	fmt.Fprintf(&body, "Location: %s\n", location)
+	fmt.Fprintf(&body, "Device: %s\n\n", userAgent)
+	body.WriteString("If this was you, no action is needed.\n")
+	fmt.Fprintf(&body, "If this wasn't you, open the link below to sign out all sessions and reset your password:\n%s\n", link)
+
+	return notify.Message{
+		Topic:   notify.TopicUnusualLogin,
+		To:      user.Email,
+		Subject: "New sign-in to your account",
+		Body:    body.String(),
+		Data: map[string]any{
+			"user_id":     user.ID,
+			"new_device":  newDevice,
+			"new_country": newCountry,
+			"country":     country,
+		},
+	}
+}
@@ -0,0 +1,192 @@
+// internal/maintenance/maintenance.go
+
+// Package maintenance mengelola mode maintenance API: selama maintenance.mode = on semua route
+// non-admin dijawab 503 dengan Retry-After (lihat middleware.Maintenance), agar migrasi bisa
+// dijalankan dengan aman di jam kerja. Status disimpan di pengaturan sistem sehingga berlaku
+// untuk semua instance (setelah SETTINGS_CACHE_TTL). Jika maintenance.checkins = buffer,
+// check-in tetap diterima dan ditampung di memori instance yang menerimanya, lalu dicatat
+// berurutan dengan waktu aslinya begitu maintenance selesai.
+package maintenance
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/degraded"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Controller membaca status maintenance dari pengaturan dan menyimpan check-in yang ditampung.
+// Method baca dan antrean (Status, BufferingCheckIns, QueuePunch, SetFlusher) aman dipanggil
+// pada Controller nil (maintenance tidak pernah aktif).
+type Controller struct {
+	settings *settings.Store
+	interval time.Duration
+	capacity int
+
+	mu      sync.Mutex
+	punches []degraded.Punch
+	flush   degraded.FlushFunc
+	reject  degraded.RejectFunc
+}
+
+// NewControllerFromEnv membuat controller berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - MAINTENANCE_FLUSH_INTERVAL: Jeda pemeriksaan apakah maintenance sudah selesai untuk mencatat check-in yang ditampung. Default: 5s.
+//   - MAINTENANCE_CHECKIN_BUFFER_SIZE: Maksimum check-in yang ditampung per instance selama maintenance. Default: 1000.
+func NewControllerFromEnv(store *settings.Store) *Controller {
+	return &Controller{
+		settings: store,
+		interval: max(configs.GetEnvDuration("MAINTENANCE_FLUSH_INTERVAL", 5*time.Second), time.Second),
+		capacity: max(configs.GetEnvInt("MAINTENANCE_CHECKIN_BUFFER_SIZE", 1000), 1),
+	}
+}
+
+// SetFlusher mendaftarkan fungsi pencatat check-in yang ditampung dan penyimpan dead letter-nya.
+// Dipanggil sebelum Start.
+func (c *Controller) SetFlusher(flush degraded.FlushFunc, reject degraded.RejectFunc) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.flush, c.reject = flush, reject
+	c.mu.Unlock()
+}
+
+// Status mengembalikan status maintenance saat ini (dipenuhi middleware.MaintenanceGate).
+func (c *Controller) Status(ctx context.Context) models.MaintenanceStatus {
+	if c == nil {
+		return models.MaintenanceStatus{}
+	}
+	status := models.MaintenanceStatus{
+		Enabled:           c.settings.MaintenanceEnabled(ctx),
+		Message:           c.settings.String(ctx, settings.KeyMaintenanceMessage),
+		RetryAfterSeconds: int(c.settings.MaintenanceRetryAfter(ctx) / time.Second),
+		BufferCheckIns:    c.settings.MaintenanceBuffersCheckIns(ctx),
+		BufferCapacity:    c.capacity,
+	}
+	c.mu.Lock()
+	status.QueuedCheckIns = len(c.punches)
+	c.mu.Unlock()
+	return status
+}
+
+// BufferingCheckIns melaporkan apakah check-in saat ini harus ditampung (maintenance aktif
+// dengan maintenance.checkins = buffer).
+func (c *Controller) BufferingCheckIns(ctx context.Context) bool {
+	if c == nil {
+		return false
+	}
+	return c.settings.MaintenanceEnabled(ctx) && c.settings.MaintenanceBuffersCheckIns(ctx)
+}
+
+// Enable mengaktifkan maintenance dengan pesan, Retry-After, dan perlakuan check-in dari input
+// (field kosong memakai default). Mengembalikan *settings.ValidationError jika nilai tidak valid.
+func (c *Controller) Enable(ctx context.Context, input models.EnableMaintenanceInput, actorUserID int) error {
+	retryAfter := input.RetryAfterSeconds
+	if retryAfter == 0 {
+		d, _ := settings.Lookup(settings.KeyMaintenanceRetry)
+		retryAfter, _ = strconv.Atoi(d.Default)
+	}
+	checkIns := models.MaintenanceCheckInsReject
+	if input.BufferCheckIns {
+		checkIns = models.MaintenanceCheckInsBuffer
+	}
+	return c.settings.Update(ctx, map[string]any{
+		settings.KeyMaintenanceMode:     models.MaintenanceOn,
+		settings.KeyMaintenanceMessage:  input.Message,
+		settings.KeyMaintenanceRetry:    retryAfter,
+		settings.KeyMaintenanceCheckIns: checkIns,
+	}, actorUserID)
+}
+
+// Disable menonaktifkan maintenance. Check-in yang ditampung dicatat oleh Start pada
+// pemeriksaan berikutnya.
+func (c *Controller) Disable(ctx context.Context, actorUserID int) error {
+	return c.settings.Update(ctx, map[string]any{settings.KeyMaintenanceMode: models.MaintenanceOff}, actorUserID)
+}
+
+// QueuePunch menampung check-in selama maintenance.
+// Mengembalikan degraded.ErrBufferFull atau degraded.ErrAlreadyQueued jika tidak dapat ditampung.
+func (c *Controller) QueuePunch(p degraded.Punch) error {
+	if c == nil {
+		return degraded.ErrBufferFull // Tanpa controller tidak ada buffer
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.punches) >= c.capacity {
+		return degraded.ErrBufferFull
+	}
+	for _, queued := range c.punches {
+		if queued.UserID == p.UserID {
+			return degraded.ErrAlreadyQueued
+		}
+	}
+	c.punches = append(c.punches, p)
+	return nil
+}
+
+// Start memeriksa setiap interval sampai ctx dibatalkan apakah maintenance sudah selesai, lalu
+// mencatat check-in yang ditampung. Dipanggil sebagai goroutine.
+func (c *Controller) Start(ctx context.Context) {
+	zlog.Info().Dur("interval", c.interval).Int("buffer_capacity", c.capacity).Msg("Maintenance controller started")
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.check(ctx)
+	}
+}
+
+// check mencatat check-in yang ditampung jika maintenance sudah dinonaktifkan.
+func (c *Controller) check(ctx context.Context) {
+	c.mu.Lock()
+	pending := len(c.punches)
+	c.mu.Unlock()
+	if pending == 0 || c.settings.MaintenanceEnabled(ctx) {
+		return
+	}
+	flushed, err := c.flushPunches(ctx)
+	if err != nil {
+		zlog.Warn().Err(err).Int("flushed", flushed).Msg("Check-ins queued during maintenance not fully flushed, retrying on the next check")
+		return
+	}
+	zlog.Info().Int("flushed", flushed).Msg("Maintenance ended, queued check-ins recorded")
+}
+
+// flushPunches mencatat check-in yang ditampung berurutan sampai buffer kosong. Berhenti dengan
+// error (punch tetap di buffer) jika database tidak tersedia atau dead letter gagal disimpan;
+// punch yang gagal dicatat karena alasan lain disimpan sebagai dead letter (degraded.Replay).
+func (c *Controller) flushPunches(ctx context.Context) (int, error) {
+	flushed := 0
+	for {
+		c.mu.Lock()
+		if len(c.punches) == 0 || c.flush == nil {
+			c.mu.Unlock()
+			return flushed, nil
+		}
+		p, flush, reject := c.punches[0], c.flush, c.reject
+		c.mu.Unlock()
+
+		recorded, err := degraded.Replay(ctx, p, flush, reject, models.QueuedCheckInSourceMaintenance)
+		if err != nil {
+			return flushed, err
+		}
+		c.mu.Lock()
+		c.punches = c.punches[1:]
+		c.mu.Unlock()
+		if recorded {
+			flushed++
+		}
+	}
+}
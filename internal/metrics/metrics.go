@@ -0,0 +1,81 @@
+// internal/metrics/metrics.go
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// Counter aplikasi berbasis expvar (stdlib), dibaca lewat endpoint admin GET /admin/metrics.
+// Setiap grup metrik adalah satu expvar.Map agar snapshot mudah dikelompokkan.
+
+// DB berisi counter query database yang diisi oleh query tracer (internal/database).
+var DB = expvar.NewMap("db")
+
+// Nama counter di grup DB.
+const (
+	DBQueriesTotal     = "queries_total"       // Semua query yang dieksekusi.
+	DBQueryErrorsTotal = "query_errors_total"  // Query yang berakhir error.
+	DBSlowQueriesTotal = "slow_queries_total"  // Query yang melewati DB_SLOW_QUERY_THRESHOLD.
+	DBQueryTimeMsTotal = "query_time_ms_total" // Akumulasi durasi query (ms), untuk menghitung rata-rata.
+)
+
+// DBPools berisi satu map per connection pool database (primary, replica) yang dibaca langsung dari
+// statistik pgxpool: koneksi (total/idle/acquired/max), jumlah acquire, dan waktu tunggu acquire
+// (lihat database.PoolStats). Dipakai operator untuk menyesuaikan DB_POOL_MAX_CONNS per environment.
+var DBPools = expvar.NewMap("db_pools")
+
+// DBFailover berisi status failover pool primary jika DATABASE_URLS berisi beberapa DSN
+// (internal/database): active_target, failovers_total, failbacks_total, consecutive_check_failures.
+var DBFailover = expvar.NewMap("db_failover")
+
+// QueuedCheckIns berisi counter check-in yang ditampung saat mode degraded/maintenance lalu dikirim
+// ulang (internal/degraded.Replay).
+var QueuedCheckIns = expvar.NewMap("queued_check_ins")
+
+// Nama counter di grup QueuedCheckIns.
+const (
+	QueuedCheckInsRecordedTotal      = "recorded_total"           // Punch yang berhasil dicatat.
+	QueuedCheckInsDeadLetteredTotal  = "dead_lettered_total"      // Punch yang gagal dicatat dan disimpan sebagai dead letter.
+	QueuedCheckInsDeadLetterErrTotal = "dead_letter_errors_total" // Dead letter yang gagal disimpan (punch tetap di buffer).
+)
+
+// CircuitBreakers berisi satu map per circuit breaker integrasi keluar (internal/resilience):
+// state, consecutive_failures, calls_total, failures_total, rejected_total, retries_total, opened_total.
+var CircuitBreakers = expvar.NewMap("circuit_breakers")
+
+// Build berisi info build aplikasi (version, commit, build_time, go_version), diisi saat startup
+// lewat SetBuildInfo agar snapshot metrik bisa dikaitkan dengan deployment.
+var Build = expvar.NewMap("build")
+
+// SetBuildInfo mengisi grup Build.
+func SetBuildInfo(info models.BuildInfo) {
+	for key, value := range map[string]string{
+		"version": info.Version, "commit": info.Commit, "build_time": info.BuildTime, "go_version": info.GoVersion,
+	} {
+		v := new(expvar.String)
+		v.Set(value)
+		Build.Set(key, v)
+	}
+}
+
+// groups adalah daftar grup metrik yang diekspos oleh Snapshot.
+var groups = map[string]*expvar.Map{
+	"db":               DB,
+	"db_pools":         DBPools,
+	"db_failover":      DBFailover,
+	"queued_check_ins": QueuedCheckIns,
+	"circuit_breakers": CircuitBreakers,
+	"build":            Build,
+}
+
+// Snapshot mengembalikan nilai terkini semua grup metrik dalam bentuk yang siap di-encode JSON.
+func Snapshot() map[string]json.RawMessage {
+	out := make(map[string]json.RawMessage, len(groups))
+	for name, group := range groups {
+		out[name] = json.RawMessage(group.String())
+	}
+	return out
+}
@@ -2,25 +2,37 @@
 package middleware
 
 import (
-	"strings" // Digunakan untuk perbandingan string case-insensitive (EqualFold)
+	"context"       // Untuk membaca token_version user & hierarki role
+	"crypto/subtle" // Perbandingan fingerprint perangkat kiosk (constant time)
+	"errors"        // Untuk membedakan user yang sudah dihapus
+	"strings"       // Digunakan untuk perbandingan string case-insensitive (EqualFold)
+	"time"          // Untuk memeriksa masa akses user berbatas waktu (contractor)
 
-	"github.com/gofiber/fiber/v2"                              // Framework Fiber
-	"github.com/rakaarfi/attendance-system-be/internal/models" // Model untuk struktur Response
-	"github.com/rakaarfi/attendance-system-be/internal/utils"  // Utilitas untuk JWT (ExtractToken, ValidateJWT, JwtClaims)
-	zlog "github.com/rs/zerolog/log"                           // Logger global Zerolog
+	"github.com/gofiber/fiber/v2"                               // Framework Fiber
+	"github.com/jackc/pgx/v5"                                   // Sentinel perangkat kiosk tidak ditemukan
+	"github.com/rakaarfi/attendance-system-be/internal/models"  // Model untuk struktur Response
+	"github.com/rakaarfi/attendance-system-be/internal/session" // Sentinel user tidak ditemukan
+	"github.com/rakaarfi/attendance-system-be/internal/utils"   // Utilitas untuk JWT (ExtractToken, ValidateJWT, JwtClaims)
+	zlog "github.com/rs/zerolog/log"                            // Logger global Zerolog
 )
 
+// TokenVersionSource memberikan token_version user saat ini (lihat session.VersionCache).
+type TokenVersionSource interface {
+	Current(ctx context.Context, userID int) (int, error)
+}
+
 // Protected adalah middleware Fiber yang memastikan sebuah request memiliki token JWT yang valid.
 // Middleware ini harus dijalankan *sebelum* handler atau middleware lain yang memerlukan
 // informasi user yang terautentikasi.
-func Protected() fiber.Handler {
+// versions (opsional, nil = tidak diperiksa) menolak token yang sesinya sudah dicabut.
+func Protected(versions TokenVersionSource) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// --- 1. Ekstrak Token dari Header Authorization ---
 		// Mencari header "Authorization: Bearer <token>" dan mengambil bagian token-nya.
 		tokenString := utils.ExtractToken(c)
 		if tokenString == "" {
 			// Jika token tidak ditemukan, log peringatan dan kirim response 401 Unauthorized.
-			zlog.Warn().Str("path", c.Path()).Str("ip", c.IP()).Msg("Protected route access attempt without token")
+			zlog.Ctx(c.UserContext()).Warn().Str("path", c.Path()).Str("ip", c.IP()).Msg("Protected route access attempt without token")
 			return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
 				Success: false, Message: "Unauthorized: Missing token",
 			})
@@ -31,31 +43,170 @@ func Protected() fiber.Handler {
 		claims, err := utils.ValidateJWT(tokenString)
 		if err != nil {
 			// Jika token tidak valid (kadaluarsa, signature salah, format rusak), log error dan kirim 401.
-			zlog.Warn().Err(err).Str("path", c.Path()).Str("ip", c.IP()).Msg("Protected route access attempt with invalid token")
+			zlog.Ctx(c.UserContext()).Warn().Err(err).Str("path", c.Path()).Str("ip", c.IP()).Msg("Protected route access attempt with invalid token")
 			return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
 				Success: false, Message: "Unauthorized: Invalid token",
 			})
 		}
 
-		// --- 3. Simpan Claims ke Locals ---
+		// --- 3. Tolak User yang Masa Aksesnya Berakhir ---
+		// Contractor membawa claim access_until; token lama tetap ditolak setelah kontrak berakhir
+		// walaupun JWT-nya sendiri belum kedaluwarsa.
+		if claims.AccessExpired(time.Now()) {
+			zlog.Ctx(c.UserContext()).Warn().Int("user_id", claims.UserID).Str("path", c.Path()).Msg("Protected route access attempt after access period ended")
+			return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
+				Success: false, Message: "Unauthorized: Access period has ended",
+			})
+		}
+
+		// --- 4. Tolak Token yang Sesinya Sudah Dicabut ---
+		// token_version dinaikkan saat user menekan "bukan saya" atau me-reset password, dan saat role
+		// user berganti atau user dinonaktifkan (trigger database).
+		if versions != nil {
+			current, err := versions.Current(c.UserContext(), claims.UserID)
+			if err != nil && !errors.Is(err, session.ErrUserNotFound) {
+				zlog.Ctx(c.UserContext()).Error().Err(err).Int("user_id", claims.UserID).Msg("Failed to verify session token version")
+				return c.Status(fiber.StatusServiceUnavailable).JSON(models.Response{
+					Success: false, Message: "Unable to verify session, please retry",
+				})
+			}
+			if err != nil || current != claims.TokenVersion {
+				zlog.Ctx(c.UserContext()).Warn().Int("user_id", claims.UserID).Str("path", c.Path()).Msg("Protected route access attempt with revoked session")
+				return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
+					Success: false, Message: "Unauthorized: Session has been revoked",
+				})
+			}
+		}
+
+		// --- 5. Simpan Claims ke Locals ---
 		// Jika token valid, simpan data claims (*utils.JwtClaims) ke dalam context request Fiber (c.Locals).
 		// Kunci "user" digunakan secara konvensi. Handler/middleware selanjutnya bisa mengambil data ini.
 		c.Locals("user", claims) // Menyimpan pointer ke JwtClaims
+		// Tambahkan user_id dan role ke logger per-request agar log selanjutnya ikut membawanya.
+		attachUserLogFields(c, claims)
 
-		// --- 4. Lanjutkan ke Middleware/Handler Berikutnya ---
+		// --- 6. Lanjutkan ke Middleware/Handler Berikutnya ---
 		// Log level debug untuk menandakan autentikasi berhasil (hanya muncul jika LOG_LEVEL=debug).
-		zlog.Debug().Str("username", claims.Username).Int("user_id", claims.UserID).Str("role", claims.Role).Msg("JWT authenticated, proceeding")
+		zlog.Ctx(c.UserContext()).Debug().Str("username", claims.Username).Msg("JWT authenticated, proceeding")
 		return c.Next() // Lanjutkan ke proses selanjutnya dalam rantai middleware/handler.
 	}
 }
 
+// Header untuk token kiosk yang terikat perangkat (lihat DeviceBound).
+const (
+	HeaderDeviceFingerprint = "X-Device-Fingerprint" // Fingerprint perangkat kiosk, wajib untuk token terikat perangkat
+	HeaderRotatedToken      = "X-Rotated-Token"      // Token pengganti setelah setengah masa berlaku token lewat
+)
+
+// KioskDeviceSource membaca perangkat kiosk terdaftar (dipenuhi repository.KioskRepository).
+type KioskDeviceSource interface {
+	GetKioskDevice(ctx context.Context, id int) (*models.KioskDevice, error)
+	TouchKioskDevice(ctx context.Context, id int) error
+}
+
+// DeviceBound adalah middleware untuk token yang terikat perangkat kiosk (claim did, lihat
+// utils.GenerateDeviceJWT). Request harus membawa fingerprint perangkat yang sama di header
+// X-Device-Fingerprint, dan perangkatnya harus masih terdaftar & belum dicabut; token curian
+// tidak bisa dipakai dari perangkat lain, dan tablet yang hilang cukup dicabut. Setelah setengah
+// masa berlakunya lewat, token baru dengan masa berlaku yang sama dikirim di header
+// X-Rotated-Token sehingga kiosk tidak memerlukan refresh token. Token lain tidak diperiksa.
+// WAJIB dijalankan setelah Protected(); devices nil = token terikat perangkat selalu ditolak.
+func DeviceBound(devices KioskDeviceSource) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("user").(*utils.JwtClaims)
+		if !ok || claims.DeviceID == 0 {
+			return c.Next()
+		}
+		logger := zlog.Ctx(c.UserContext()).With().Int("user_id", claims.UserID).Int("kiosk_id", claims.DeviceID).Logger()
+
+		fingerprint := c.Get(HeaderDeviceFingerprint)
+		if fingerprint == "" || subtle.ConstantTimeCompare([]byte(utils.HashDeviceFingerprint(fingerprint)), []byte(claims.DeviceFingerprint)) != 1 {
+			logger.Warn().Str("path", c.Path()).Str("ip", c.IP()).Msg("Device-bound token used without the enrolled device fingerprint")
+			return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
+				Success: false, Message: "Unauthorized: Token is bound to another device",
+			})
+		}
+		if devices == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
+				Success: false, Message: "Unauthorized: Device has been revoked",
+			})
+		}
+		device, err := devices.GetKioskDevice(c.UserContext(), claims.DeviceID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			logger.Error().Err(err).Msg("Failed to verify kiosk device")
+			return c.Status(fiber.StatusServiceUnavailable).JSON(models.Response{
+				Success: false, Message: "Unable to verify device, please retry",
+			})
+		}
+		if err != nil || device.RevokedAt != nil || device.UserID != claims.UserID || device.FingerprintHash != claims.DeviceFingerprint {
+			logger.Warn().Str("path", c.Path()).Str("ip", c.IP()).Msg("Access attempt with token of a revoked or unknown kiosk device")
+			return c.Status(fiber.StatusUnauthorized).JSON(models.Response{
+				Success: false, Message: "Unauthorized: Device has been revoked",
+			})
+		}
+
+		// Rotasi: token baru berlaku selama masa berlaku token asli (iat -> exp).
+		if claims.IssuedAt != nil && claims.ExpiresAt != nil {
+			lifetime := claims.ExpiresAt.Sub(claims.IssuedAt.Time)
+			if lifetime > 0 && time.Until(claims.ExpiresAt.Time) < lifetime/2 {
+				var accessUntil *time.Time
+				if claims.AccessUntil != nil {
+					accessUntil = &claims.AccessUntil.Time
+				}
+				token, err := utils.GenerateDeviceJWT(claims.UserID, claims.Username, claims.Role, accessUntil, claims.TokenVersion,
+					claims.Scopes, claims.DeviceID, claims.DeviceFingerprint, time.Now().Add(lifetime))
+				if err != nil {
+					// Token lama masih berlaku; rotasi dicoba lagi pada request berikutnya.
+					logger.Error().Err(err).Msg("Failed to rotate kiosk token")
+				} else {
+					c.Set(HeaderRotatedToken, token)
+					if err := devices.TouchKioskDevice(c.UserContext(), device.ID); err != nil {
+						logger.Warn().Err(err).Msg("Failed to update kiosk device last seen")
+					}
+					logger.Debug().Msg("Kiosk token rotated")
+				}
+			}
+		}
+		return c.Next()
+	}
+}
+
+// RequireScope adalah middleware yang membatasi token terbatas (claim scp, lihat
+// utils.GenerateScopedJWT) ke route yang menerima salah satu scopes. Token sesi penuh selalu
+// lolos. WAJIB dijalankan setelah Protected().
+func RequireScope(scopes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("user").(*utils.JwtClaims)
+		if !ok {
+			zlog.Ctx(c.UserContext()).Error().Str("path", c.Path()).Msg("User claims not found in context during scope check. Ensure Protected middleware runs first.")
+			return c.Status(fiber.StatusForbidden).JSON(models.Response{
+				Success: false, Message: "Forbidden: Cannot determine token scope",
+			})
+		}
+		if !claims.AllowsScope(scopes...) {
+			zlog.Ctx(c.UserContext()).Warn().Int("user_id", claims.UserID).Strs("token_scopes", claims.Scopes).Strs("accepted_scopes", scopes).Str("path", c.Path()).Msg("Scope check failed: token scope not permitted")
+			return c.Status(fiber.StatusForbidden).JSON(models.Response{
+				Success: false, Message: "Forbidden: Token scope does not allow this action",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// RoleResolver memeriksa role beserta role yang diwarisinya (lihat rbac.Resolver).
+type RoleResolver interface {
+	Allows(ctx context.Context, role string, allowed ...string) (bool, error)
+}
+
 // Authorize adalah middleware Fiber yang memeriksa apakah user yang terautentikasi
 // memiliki salah satu role yang diizinkan untuk mengakses suatu route.
 // Middleware ini WAJIB dijalankan *setelah* middleware Protected() agar claims user sudah ada di c.Locals.
 //
 // Parameter:
+//   - roles: Resolver hierarki role (opsional, nil = hanya role user sendiri yang dicocokkan).
+//     Dengan resolver, role yang mewarisi salah satu allowedRoles juga diizinkan.
 //   - allowedRoles: Daftar string nama role yang diizinkan (varargs).
-func Authorize(allowedRoles ...string) fiber.Handler {
+func Authorize(roles RoleResolver, allowedRoles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// --- 1. Ambil Claims User dari Locals ---
 		// Mengambil data claims (*utils.JwtClaims) yang sebelumnya disimpan oleh middleware Protected().
@@ -65,7 +216,7 @@ func Authorize(allowedRoles ...string) fiber.Handler {
 			// Jika claims tidak ditemukan atau tipenya salah (seharusnya tidak terjadi jika Protected() jalan duluan),
 			// log error kritis dan kirim response 403 Forbidden.
 			// Status 500 mungkin juga bisa dipertimbangkan karena ini menandakan kesalahan konfigurasi middleware.
-			zlog.Error().Str("path", c.Path()).Str("ip", c.IP()).Msg("User claims not found in context during authorization. Ensure Protected middleware runs first.")
+			zlog.Ctx(c.UserContext()).Error().Str("path", c.Path()).Str("ip", c.IP()).Msg("User claims not found in context during authorization. Ensure Protected middleware runs first.")
 			return c.Status(fiber.StatusForbidden).JSON(models.Response{
 				Success: false, Message: "Forbidden: Cannot determine user role",
 			})
@@ -83,10 +234,22 @@ func Authorize(allowedRoles ...string) fiber.Handler {
 			}
 		}
 
+		// Role yang tidak cocok langsung masih bisa mewarisi salah satu role yang diizinkan.
+		if !isAllowed && roles != nil {
+			allowed, err := roles.Allows(c.UserContext(), claims.Role, allowedRoles...)
+			if err != nil {
+				zlog.Ctx(c.UserContext()).Error().Err(err).Str("user_role", claims.Role).Msg("Failed to resolve inherited roles")
+				return c.Status(fiber.StatusServiceUnavailable).JSON(models.Response{
+					Success: false, Message: "Unable to verify privileges, please retry",
+				})
+			}
+			isAllowed = allowed
+		}
+
 		// --- 3. Tolak Akses Jika Role Tidak Sesuai ---
 		if !isAllowed {
 			// Jika role user tidak ada dalam daftar yang diizinkan, log peringatan dan kirim 403 Forbidden.
-			zlog.Warn().Str("username", claims.Username).Int("user_id", claims.UserID).Str("user_role", claims.Role).Strs("required_roles", allowedRoles).Str("path", c.Path()).Msg("Authorization failed: User role not permitted")
+			zlog.Ctx(c.UserContext()).Warn().Str("username", claims.Username).Int("user_id", claims.UserID).Str("user_role", claims.Role).Strs("required_roles", allowedRoles).Str("path", c.Path()).Msg("Authorization failed: User role not permitted")
 			return c.Status(fiber.StatusForbidden).JSON(models.Response{
 				Success: false, Message: "Forbidden: Insufficient privileges",
 			})
@@ -94,7 +257,7 @@ func Authorize(allowedRoles ...string) fiber.Handler {
 
 		// --- 4. Izinkan Akses Jika Role Sesuai ---
 		// Jika user memiliki role yang diizinkan, log debug dan lanjutkan ke handler berikutnya.
-		zlog.Debug().Str("username", claims.Username).Str("role", claims.Role).Msg("Authorization successful, proceeding")
+		zlog.Ctx(c.UserContext()).Debug().Str("username", claims.Username).Str("role", claims.Role).Msg("Authorization successful, proceeding")
 		return c.Next()
 	}
 }
@@ -0,0 +1,62 @@
+// internal/middleware/captcha.go
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/captcha" // Verifier CAPTCHA (hCaptcha/Turnstile)
+	"github.com/rakaarfi/attendance-system-be/internal/models"  // Model untuk struktur Response
+	zlog "github.com/rs/zerolog/log"
+)
+
+// CaptchaTokenHeader adalah header tempat klien mengirim token CAPTCHA.
+// Sebagai alternatif, token juga bisa dikirim di body JSON dengan field "captcha_token".
+const CaptchaTokenHeader = "X-Captcha-Token"
+
+// Captcha adalah middleware yang mewajibkan token CAPTCHA valid sebelum request diteruskan.
+// Jika verifier bernilai nil (CAPTCHA tidak diaktifkan lewat env), middleware ini hanya
+// meneruskan request sehingga aman dipasang permanen di route publik seperti /auth/login.
+func Captcha(verifier captcha.Verifier) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if verifier == nil {
+			return c.Next()
+		}
+
+		// --- 1. Ambil Token dari Header atau Body ---
+		token := c.Get(CaptchaTokenHeader)
+		if token == "" {
+			var body struct {
+				CaptchaToken string `json:"captcha_token"`
+			}
+			// Error parsing diabaikan: handler akan mem-parsing ulang body dan melaporkan error-nya sendiri.
+			_ = c.BodyParser(&body)
+			token = body.CaptchaToken
+		}
+
+		// --- 2. Verifikasi ke Provider ---
+		ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+		defer cancel()
+
+		err := verifier.Verify(ctx, token, c.IP())
+		if err == nil {
+			return c.Next()
+		}
+
+		// --- 3. Tolak Request ---
+		if errors.Is(err, captcha.ErrMissingToken) || errors.Is(err, captcha.ErrVerificationFailed) {
+			zlog.Ctx(c.UserContext()).Warn().Err(err).Str("path", c.Path()).Str("ip", c.IP()).Str("provider", verifier.Provider()).Msg("CAPTCHA verification rejected request")
+			return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+				Success: false, Message: "CAPTCHA verification failed",
+			})
+		}
+
+		// Provider tidak bisa dihubungi: fail-closed agar CAPTCHA tidak bisa di-bypass.
+		zlog.Ctx(c.UserContext()).Error().Err(err).Str("path", c.Path()).Str("provider", verifier.Provider()).Msg("CAPTCHA provider unavailable")
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.Response{
+			Success: false, Message: "CAPTCHA verification is temporarily unavailable",
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// internal/middleware/capture.go
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// RequestCapturer merekam request/response lengkap untuk debugging (implementasi: internal/debugcapture).
+type RequestCapturer interface {
+	// ShouldCapture melaporkan apakah request ke route (pola yang didaftarkan) oleh userID
+	// (0 = tanpa login) perlu direkam.
+	ShouldCapture(ctx context.Context, method, route string, userID int) bool
+	// Capture menyalin request/response dari c (sudah di-redact) lalu menyimpannya di background.
+	// handlerErr adalah error yang dikembalikan handler (response-nya belum ditulis ErrorHandler).
+	Capture(c *fiber.Ctx, route string, userID int, latency time.Duration, handlerErr error)
+}
+
+// CaptureRequests merekam request/response route jika capturer mengaktifkannya untuk route itu
+// atau untuk user yang login. Dipasang paling awal di rantai route (lihat routeGroup.add) agar
+// response 401/403/429 ikut terekam; keputusan diambil setelah handler selesai karena user baru
+// diketahui setelah Protected(). Jika capturer nil, middleware ini hanya meneruskan request.
+func CaptureRequests(capturer RequestCapturer, route string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if capturer == nil {
+			return c.Next()
+		}
+		start := time.Now()
+		err := c.Next()
+
+		userID := 0
+		if claims, ok := c.Locals("user").(*utils.JwtClaims); ok {
+			userID = claims.UserID
+		}
+		if capturer.ShouldCapture(c.UserContext(), c.Method(), route, userID) {
+			capturer.Capture(c, route, userID, time.Since(start), err)
+		}
+		return err
+	}
+}
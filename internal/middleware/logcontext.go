@@ -0,0 +1,31 @@
+// internal/middleware/logcontext.go
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	applogger "github.com/rakaarfi/attendance-system-be/internal/logger"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+// RequestLogContext memasang child logger per-request ke c.UserContext().
+// Child logger membawa request_id, method, dan path; middleware Protected() menambahkan
+// user_id dan role setelah token tervalidasi. Handler dan repository yang menerima
+// c.UserContext() cukup log via zlog.Ctx(ctx) agar semua baris satu request berkorelasi.
+// Harus didaftarkan setelah middleware requestid.
+func RequestLogContext() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID, _ := c.Locals("requestid").(string)
+		c.SetUserContext(applogger.WithFields(c.UserContext(), func(lc zerolog.Context) zerolog.Context {
+			return lc.Str("request_id", requestID).Str("method", c.Method()).Str("path", c.Path())
+		}))
+		return c.Next()
+	}
+}
+
+// attachUserLogFields menambahkan identitas user terautentikasi ke logger per-request.
+func attachUserLogFields(c *fiber.Ctx, claims *utils.JwtClaims) {
+	c.SetUserContext(applogger.WithFields(c.UserContext(), func(lc zerolog.Context) zerolog.Context {
+		return lc.Int("user_id", claims.UserID).Str("role", claims.Role)
+	}))
+}
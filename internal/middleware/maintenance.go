@@ -0,0 +1,40 @@
+// internal/middleware/maintenance.go
+package middleware
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// MaintenanceGate melaporkan status mode maintenance (implementasi: internal/maintenance).
+type MaintenanceGate interface {
+	Status(ctx context.Context) models.MaintenanceStatus
+}
+
+// Maintenance menjawab 503 dengan header Retry-After selama mode maintenance aktif. Route
+// yang tetap dilayani selama maintenance (admin, login, health) tidak memasang middleware ini;
+// bufferable menandai route check-in yang diteruskan ke handler jika check-in sedang ditampung
+// (maintenance.checkins = buffer). Jika gate nil, middleware ini hanya meneruskan request.
+func Maintenance(gate MaintenanceGate, bufferable bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if gate == nil {
+			return c.Next()
+		}
+		status := gate.Status(c.UserContext())
+		if !status.Enabled || (bufferable && status.BufferCheckIns) {
+			return c.Next()
+		}
+		message := status.Message
+		if message == "" {
+			message = "The service is under maintenance, please try again later"
+		}
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(status.RetryAfterSeconds))
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.Response{
+			Success: false, Message: message,
+			Data: fiber.Map{"maintenance": true, "retry_after_seconds": status.RetryAfterSeconds},
+		})
+	}
+}
@@ -2,57 +2,79 @@
 package middleware
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/compress"  // Middleware untuk kompresi response (Gzip)
-	"github.com/gofiber/fiber/v2/middleware/cors"      // Middleware untuk Cross-Origin Resource Sharing
-	"github.com/gofiber/fiber/v2/middleware/limiter"   // Middleware untuk membatasi rate request
-	"github.com/gofiber/fiber/v2/middleware/recover"   // Middleware untuk menangkap panic
-	"github.com/gofiber/fiber/v2/middleware/requestid" // Middleware untuk menambahkan ID unik ke request
-	"github.com/rs/zerolog"                            // Digunakan oleh logger request
-	zlog "github.com/rs/zerolog/log"                   // Logger global Zerolog
+	"github.com/gofiber/fiber/v2/middleware/compress"                    // Middleware untuk kompresi response (Gzip)
+	"github.com/gofiber/fiber/v2/middleware/cors"                        // Middleware untuk Cross-Origin Resource Sharing
+	"github.com/rakaarfi/attendance-system-be/configs"                   // SecurityConfig untuk CORS & security headers
+	"github.com/rakaarfi/attendance-system-be/internal/errorreport"      // Pelaporan panic ke Sentry/Glitchtip
+	applogger "github.com/rakaarfi/attendance-system-be/internal/logger" // Logger per modul (level & sampling)
+	"github.com/rs/zerolog"                                              // Digunakan oleh logger request
+	zlog "github.com/rs/zerolog/log"                                     // Logger global Zerolog
 )
 
 // SetupGlobalMiddleware mendaftarkan middleware standar yang akan dijalankan
 // untuk sebagian besar atau semua request ke aplikasi Fiber.
-// Urutan pendaftaran middleware penting.
-func SetupGlobalMiddleware(app *fiber.App) {
+// Urutan pendaftaran middleware penting. panicReporter boleh nil (panic hanya dicatat ke log).
+func SetupGlobalMiddleware(app *fiber.App, securityCfg configs.SecurityConfig, panicReporter errorreport.Reporter) {
 	// --- 1. Recover Middleware (Paling Awal) ---
 	// Menangkap panic yang mungkin terjadi di handler atau middleware lain
-	// agar server tidak crash. Mengembalikan response 500 Internal Server Error.
+	// agar server tidak crash. Stack trace dicatat ke log (dan dikirim ke Sentry jika
+	// dikonfigurasi), lalu ErrorHandler mengembalikan 500 Internal Server Error.
 	// Harus didaftarkan sepagi mungkin.
-	app.Use(recover.New())
-	zlog.Info().Msg("Recover middleware registered")
+	app.Use(Recover(panicReporter))
+	zlog.Info().Bool("reporting", panicReporter != nil).Msg("Recover middleware registered")
 
 	// --- 2. Request ID Middleware ---
-	// Menambahkan header 'X-Request-ID' ke setiap request (jika belum ada)
-	// dan menyimpannya di c.Locals("requestid"). Berguna untuk tracing log.
-	app.Use(requestid.New())
-	zlog.Info().Msg("RequestID middleware registered")
+	// Menambahkan header 'X-Request-ID' ke setiap response dan menyimpannya di
+	// c.Locals("requestid"). Berguna untuk tracing log. X-Request-ID dari request
+	// hanya diteruskan jika datang dari proxy tepercaya (TRUSTED_PROXIES).
+	app.Use(RequestID(securityCfg.TrustedProxies))
+	zlog.Info().Int("trusted_proxies", len(securityCfg.TrustedProxies)).Msg("RequestID middleware registered")
+
+	// --- 2b. Request Log Context Middleware ---
+	// Memasang child logger berisi request_id ke c.UserContext() agar semua log
+	// dari handler/repository untuk request yang sama bisa dikorelasikan.
+	app.Use(RequestLogContext())
+	zlog.Info().Msg("Request log context middleware registered")
 
 	// --- 3. CORS Middleware ---
 	// Mengatur header Cross-Origin Resource Sharing. Penting agar frontend
 	// yang berjalan di domain berbeda bisa berkomunikasi dengan API ini.
-	app.Use(cors.New(cors.Config{
-		// AllowOrigins: "*", // Izinkan semua origin (HATI-HATI di production!)
-		// Ganti dengan daftar origin frontend Anda di production, pisahkan dengan koma.
-		AllowOrigins: "http://localhost:5173, http://127.0.0.1:5173, https://frontend-domain-anda.com, http://localhost:3001",
-		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",      // Metode HTTP yang diizinkan.
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization", // Header yang boleh dikirim oleh klien.
-		// AllowCredentials: true, // Set true jika perlu mengirim cookie lintas domain.
-	}))
-	zlog.Info().Msg("CORS middleware registered")
-
-	// --- 4. Rate Limiter Middleware ---
-	// Membatasi jumlah request dari IP address yang sama dalam periode waktu tertentu.
-	// Membantu mencegah serangan brute-force atau penyalahgunaan API.
-	app.Use(limiter.New(limiter.Config{
-		Max:        200,             // Maksimum 200 request...
-		Expiration: 1 * time.Minute, // ...dalam periode 1 menit per IP.
-		// KeyGenerator: func(c *fiber.Ctx) string { return c.Get("x-forwarded-for")}, // Gunakan jika di belakang reverse proxy/load balancer.
-		LimiterMiddleware: limiter.SlidingWindow{}, // Algoritma rate limiting (Sliding Window).
-	}))
+	// Daftar origin, credentials, dll. diambil dari SecurityConfig (profil APP_ENV + env var CORS_*).
+	corsConfig := cors.Config{
+		AllowOrigins:     strings.Join(securityCfg.CORSAllowOrigins, ","),
+		AllowMethods:     strings.Join(securityCfg.CORSAllowMethods, ","),
+		AllowHeaders:     strings.Join(securityCfg.CORSAllowHeaders, ","),
+		ExposeHeaders:    strings.Join(securityCfg.CORSExposeHeaders, ","),
+		AllowCredentials: securityCfg.CORSAllowCredentials,
+		MaxAge:           securityCfg.CORSMaxAgeSeconds,
+	}
+	if len(securityCfg.CORSAllowOrigins) == 0 {
+		// Tanpa origin yang dikonfigurasi, tolak semua request lintas origin
+		// (Fiber akan default ke "*" jika AllowOrigins dan AllowOriginsFunc sama-sama kosong).
+		corsConfig.AllowOriginsFunc = func(origin string) bool { return false }
+	}
+	app.Use(cors.New(corsConfig))
+	zlog.Info().Str("env", securityCfg.Environment).Strs("origins", securityCfg.CORSAllowOrigins).Bool("credentials", securityCfg.CORSAllowCredentials).Msg("CORS middleware registered")
+
+	// --- 3b. Security Headers Middleware ---
+	// Menambahkan header seperti X-Content-Type-Options, X-Frame-Options, Referrer-Policy,
+	// dan HSTS (jika diaktifkan untuk profil production).
+	app.Use(SecurityHeaders(securityCfg))
+	zlog.Info().Bool("hsts", securityCfg.HSTSEnabled).Msg("Security headers middleware registered")
+
+	// --- 4. Rate Limiter Middleware (Backstop Global) ---
+	// Batas kasar per IP/user untuk seluruh aplikasi (termasuk Swagger UI).
+	// Budget yang lebih spesifik per grup route (auth, user, admin, public) dipasang di routes.go
+	// menggunakan middleware RateLimit(). Semua nilai bisa diatur via env RATE_LIMIT_GLOBAL_*.
+	app.Use(RateLimit("global", RateLimitBudgetFromEnv("global", RateLimitBudget{
+		ReadMax:  200,             // Maksimum 200 request baca...
+		WriteMax: 200,             // ...dan 200 request tulis...
+		Window:   1 * time.Minute, // ...dalam periode 1 menit.
+	})))
 	zlog.Info().Msg("Rate limiter middleware registered")
 
 	// --- 5. Logger Request Middleware (Custom Zerolog) ---
@@ -67,48 +89,36 @@ func SetupGlobalMiddleware(app *fiber.App) {
 		latency := stop.Sub(start)              // Durasi pemrosesan request
 		statusCode := c.Response().StatusCode() // Status HTTP response
 
-		// Ambil Request ID yang ditambahkan oleh middleware requestid
-		requestIDInterface := c.Locals("requestid")
-		requestID := "" // Default string kosong
-		if requestIDInterface != nil {
-			// Lakukan type assertion yang aman
-			if idStr, ok := requestIDInterface.(string); ok {
-				requestID = idStr
-			}
-		}
+		// Logger per-request (sudah berisi request_id, method, path, dan user_id/role jika
+		// request terautentikasi) dipasang oleh RequestLogContext() dan Protected().
+		// Modul "http": bisa disampling (LOG_SAMPLE_*) dan di-override lewat LOG_LEVEL_HTTP.
+		reqLogger := applogger.Module(c.UserContext(), applogger.ModuleHTTP)
 
 		// Tentukan level log berdasarkan status code atau adanya error
 		var logEvent *zerolog.Event
 		if err != nil {
 			// Jika ada error dari handler (akan ditangani juga oleh ErrorHandler global),
 			// log sebagai warning/error di sini. ErrorHandler global akan memberikan response.
-			logEvent = zlog.Warn().Err(err) // Atau Error() tergantung tingkat keparahan
+			logEvent = reqLogger.Warn().Err(err) // Atau Error() tergantung tingkat keparahan
 		} else {
 			// Log request sukses
-			logEvent = zlog.Info() // Default Info
+			logEvent = reqLogger.Info() // Default Info
 			if statusCode >= 500 {
-				logEvent = zlog.Error() // Jika status 5xx, log sebagai Error
+				logEvent = reqLogger.Error() // Jika status 5xx, log sebagai Error
 			} else if statusCode >= 400 {
-				logEvent = zlog.Warn() // Jika status 4xx, log sebagai Warn
+				logEvent = reqLogger.Warn() // Jika status 4xx, log sebagai Warn
 			}
 		}
 
 		// Bangun field-field log
-		loggerWithFields := logEvent.
-			Str("method", c.Method()).                      // Metode HTTP (GET, POST, etc.)
-			Str("path", c.Path()).                          // Path request
-			Int("status", statusCode).                      // Status code response
-			Dur("latency", latency).                        // Durasi request
-			Str("ip", c.IP()).                              // IP address klien
-			Str("user_agent", c.Get(fiber.HeaderUserAgent)) // User agent klien
-
-		// Tambahkan request ID jika ada
-		if requestID != "" {
-			loggerWithFields = loggerWithFields.Str("request_id", requestID)
-		}
-
-		// Tulis log
-		loggerWithFields.Msg("Request handled")
+		// (method, path, request_id sudah ada di logger per-request)
+		logEvent.
+			Str("route", c.Route().Path).                    // Pola route yang cocok (mis. /api/v1/admin/users/:userId)
+			Int("status", statusCode).                       // Status code response
+			Dur("latency", latency).                         // Durasi request
+			Str("ip", c.IP()).                               // IP address klien
+			Str("user_agent", c.Get(fiber.HeaderUserAgent)). // User agent klien
+			Msg("Request handled")
 
 		// Kembalikan error (jika ada) agar bisa ditangani oleh ErrorHandler global
 		return err
@@ -123,7 +133,11 @@ func SetupGlobalMiddleware(app *fiber.App) {
 	}))
 	zlog.Info().Msg("Compress middleware registered")
 
+	// --- 7. Request ID di Response Error ---
+	// Menambahkan request_id ke body JSON response error yang ditulis langsung oleh handler.
+	// Didaftarkan setelah compress agar bekerja pada body yang belum dikompres.
+	app.Use(RequestIDInErrors())
+	zlog.Info().Msg("Request ID error body middleware registered")
+
 	// --- Middleware lain bisa ditambahkan di sini ---
-	// Contoh:
-	// app.Use(helmet.New()) // Middleware untuk menambahkan header keamanan (perlu library terpisah)
 }
@@ -0,0 +1,94 @@
+// internal/middleware/ratelimit.go
+package middleware
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"           // Implementasi rate limiter bawaan Fiber
+	"github.com/rakaarfi/attendance-system-be/configs"         // Helper pembaca env var
+	"github.com/rakaarfi/attendance-system-be/internal/models" // Model untuk struktur Response
+	"github.com/rakaarfi/attendance-system-be/internal/utils"  // JwtClaims untuk key per-user
+	zlog "github.com/rs/zerolog/log"
+)
+
+// RateLimitBudget mendefinisikan anggaran request untuk satu grup route.
+// Request baca (GET/HEAD/OPTIONS) dan tulis dihitung terpisah agar endpoint baca
+// bisa diberi budget lebih longgar daripada endpoint yang mengubah data.
+type RateLimitBudget struct {
+	ReadMax  int           // Maksimum request baca per Window.
+	WriteMax int           // Maksimum request tulis per Window.
+	Window   time.Duration // Panjang jendela waktu (sliding window).
+}
+
+// RateLimitBudgetFromEnv membaca budget untuk grup tertentu dari env var dengan prefix
+// RATE_LIMIT_<GROUP>_, menggunakan 'defaults' jika variabel tidak di-set.
+//
+// Contoh untuk grup "auth":
+//   - RATE_LIMIT_AUTH_READ_MAX
+//   - RATE_LIMIT_AUTH_WRITE_MAX
+//   - RATE_LIMIT_AUTH_WINDOW (format durasi Go, misal "1m")
+func RateLimitBudgetFromEnv(group string, defaults RateLimitBudget) RateLimitBudget {
+	prefix := "RATE_LIMIT_" + strings.ToUpper(group) + "_"
+	return RateLimitBudget{
+		ReadMax:  configs.GetEnvInt(prefix+"READ_MAX", defaults.ReadMax),
+		WriteMax: configs.GetEnvInt(prefix+"WRITE_MAX", defaults.WriteMax),
+		Window:   configs.GetEnvDuration(prefix+"WINDOW", defaults.Window),
+	}
+}
+
+// isReadMethod mengembalikan true untuk metode HTTP yang tidak mengubah data.
+func isReadMethod(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// rateLimitKey menentukan kunci counter: per user jika request sudah terautentikasi
+// (claims JWT ada di Locals), atau per IP untuk request anonim.
+// Dengan begitu user di belakang NAT yang sama tidak saling menghabiskan budget.
+func rateLimitKey(group, class string, c *fiber.Ctx) string {
+	if claims, ok := c.Locals("user").(*utils.JwtClaims); ok {
+		return fmt.Sprintf("%s:%s:user:%d", group, class, claims.UserID)
+	}
+	return fmt.Sprintf("%s:%s:ip:%s", group, class, c.IP())
+}
+
+// RateLimit membuat middleware rate limiting untuk satu grup route.
+// Pasang middleware ini *setelah* Protected() pada grup yang membutuhkan login
+// agar kunci per-user bisa digunakan; di grup publik kunci jatuh ke IP.
+func RateLimit(group string, budget RateLimitBudget) fiber.Handler {
+	newLimiter := func(class string, max int) fiber.Handler {
+		return limiter.New(limiter.Config{
+			Max:        max,
+			Expiration: budget.Window,
+			KeyGenerator: func(c *fiber.Ctx) string {
+				return rateLimitKey(group, class, c)
+			},
+			LimitReached: func(c *fiber.Ctx) error {
+				zlog.Ctx(c.UserContext()).Warn().Str("group", group).Str("class", class).Str("key", rateLimitKey(group, class, c)).Str("path", c.Path()).Msg("Rate limit exceeded")
+				// Limiter global berjalan sebelum RequestIDInErrors, jadi request_id diisi di sini.
+				return c.Status(fiber.StatusTooManyRequests).JSON(models.Response{
+					Success: false, Message: "Too many requests, please try again later", RequestID: requestIDOf(c),
+				})
+			},
+			LimiterMiddleware: limiter.SlidingWindow{},
+		})
+	}
+
+	readLimiter := newLimiter("read", budget.ReadMax)
+	writeLimiter := newLimiter("write", budget.WriteMax)
+
+	zlog.Info().Str("group", group).Int("read_max", budget.ReadMax).Int("write_max", budget.WriteMax).Dur("window", budget.Window).Msg("Rate limiter configured")
+
+	return func(c *fiber.Ctx) error {
+		if isReadMethod(c.Method()) {
+			return readLimiter(c)
+		}
+		return writeLimiter(c)
+	}
+}
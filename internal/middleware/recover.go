@@ -0,0 +1,55 @@
+// internal/middleware/recover.go
+package middleware
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/errorreport"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Recover menangkap panic dari middleware/handler berikutnya agar server tidak crash.
+// Panic dicatat ke logger per-request (request_id, user_id, route) lengkap dengan stack trace,
+// dikirim ke reporter jika dikonfigurasi (SENTRY_DSN), lalu diteruskan sebagai 500 ke
+// ErrorHandler sehingga klien tetap menerima response problem+json dengan request_id.
+// reporter nil = panic hanya dicatat ke log. Harus didaftarkan paling awal.
+func Recover(reporter errorreport.Reporter) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			// Skip frame fungsi defer ini dan runtime.gopanic agar frame teratas adalah lokasi panic.
+			frames := errorreport.Callers(2)
+			event := errorreport.Event{
+				Message:   fmt.Sprint(r),
+				Frames:    frames,
+				RequestID: requestIDOf(c),
+				Method:    c.Method(),
+				Route:     c.Route().Path,
+				Path:      c.Path(),
+				Time:      time.Now(),
+			}
+			if claims, ok := c.Locals("user").(*utils.JwtClaims); ok {
+				event.UserID = claims.UserID
+			}
+
+			// Logger per-request sudah membawa request_id, method, path, dan user_id/role (jika login).
+			zlog.Ctx(c.UserContext()).Error().
+				Str("panic", event.Message).
+				Str("route", event.Route).
+				Str("stack", string(debug.Stack())).
+				Msg("Panic recovered during request processing")
+			if reporter != nil {
+				reporter.Report(event)
+			}
+			err = fiber.ErrInternalServerError
+		}()
+		return c.Next()
+	}
+}
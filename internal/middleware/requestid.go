@@ -0,0 +1,110 @@
+// internal/middleware/requestid.go
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// requestIDLocal adalah key c.Locals untuk ID request (sama dengan middleware requestid Fiber,
+// sehingga pembaca yang sudah ada tetap bekerja).
+const requestIDLocal = "requestid"
+
+// requestIDPattern membatasi X-Request-ID dari proxy agar aman ditulis ke log dan header.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:+=/@-]{1,128}$`)
+
+// RequestID memberi setiap request ID di c.Locals("requestid") dan header response X-Request-ID.
+// X-Request-ID dari request hanya dipakai jika koneksi datang dari proxy tepercaya
+// (TRUSTED_PROXIES) dan formatnya valid, sehingga ID dari load balancer bisa dicari di log
+// kedua sisi; klien lain tidak bisa memilih ID yang tercatat di log. Selain itu dibuat UUID baru.
+func RequestID(trustedProxies []netip.Prefix) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(fiber.HeaderXRequestID)
+		if id == "" || !requestIDPattern.MatchString(id) || !fromTrustedProxy(c, trustedProxies) {
+			id = utils.UUIDv4()
+		}
+		c.Locals(requestIDLocal, id)
+		c.Set(fiber.HeaderXRequestID, id)
+		return c.Next()
+	}
+}
+
+// fromTrustedProxy melaporkan apakah koneksi langsung (bukan X-Forwarded-For) berasal dari proxy tepercaya.
+func fromTrustedProxy(c *fiber.Ctx, trustedProxies []netip.Prefix) bool {
+	if len(trustedProxies) == 0 {
+		return false
+	}
+	addr, ok := netip.AddrFromSlice(c.Context().RemoteIP())
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestIDOf mengembalikan ID request yang dipasang RequestID.
+func requestIDOf(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestIDLocal).(string)
+	return id
+}
+
+// RequestIDInErrors menambahkan field request_id ke body JSON response error (status >= 400)
+// yang belum memilikinya, agar user bisa mengutip ID yang langsung bisa dicari support di log.
+// Error yang dikembalikan handler diteruskan apa adanya; ErrorHandler sudah mengisi request_id.
+// Harus didaftarkan setelah middleware compress agar melihat body yang belum dikompres.
+func RequestIDInErrors() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+		resp := c.Response()
+		if resp.StatusCode() < fiber.StatusBadRequest || resp.IsBodyStream() ||
+			!strings.HasPrefix(string(resp.Header.ContentType()), fiber.MIMEApplicationJSON) {
+			return nil
+		}
+		id := requestIDOf(c)
+		if id == "" {
+			return nil
+		}
+		if body, ok := withRequestID(resp.Body(), id); ok {
+			resp.SetBody(body)
+		}
+		return nil
+	}
+}
+
+// withRequestID menambahkan "request_id" di akhir objek JSON body tanpa mengubah urutan field
+// lain. Mengembalikan false jika body bukan objek JSON atau sudah memiliki request_id.
+func withRequestID(body []byte, id string) ([]byte, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return nil, false
+	}
+	if _, exists := fields["request_id"]; exists {
+		return nil, false
+	}
+	encodedID, err := json.Marshal(id)
+	if err != nil {
+		return nil, false
+	}
+	trimmed := bytes.TrimRight(body, " \t\r\n")
+	out := make([]byte, 0, len(trimmed)+len(encodedID)+16)
+	out = append(out, trimmed[:len(trimmed)-1]...) // Tanpa '}' penutup
+	if len(fields) > 0 {
+		out = append(out, ',')
+	}
+	out = append(out, `"request_id":`...)
+	out = append(out, encodedID...)
+	return append(out, '}'), true
+}
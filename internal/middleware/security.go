@@ -0,0 +1,45 @@
+// internal/middleware/security.go
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/configs" // SecurityConfig (profil dev/prod)
+)
+
+// SecurityHeaders menambahkan header keamanan standar ke setiap response.
+// Nilai header diambil dari configs.SecurityConfig sehingga bisa berbeda per environment
+// (misal: HSTS hanya aktif di production yang berjalan di belakang TLS).
+func SecurityHeaders(cfg configs.SecurityConfig) fiber.Handler {
+	// Bangun nilai HSTS sekali saja saat inisialisasi.
+	hsts := ""
+	if cfg.HSTSEnabled && cfg.HSTSMaxAgeSeconds > 0 {
+		hsts = fmt.Sprintf("max-age=%d", cfg.HSTSMaxAgeSeconds)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+		c.Set("Cross-Origin-Opener-Policy", "same-origin")
+		c.Set("Permissions-Policy", "camera=(), microphone=(), geolocation=()")
+		if cfg.FrameOptions != "" {
+			c.Set(fiber.HeaderXFrameOptions, cfg.FrameOptions)
+		}
+		if cfg.ReferrerPolicy != "" {
+			c.Set(fiber.HeaderReferrerPolicy, cfg.ReferrerPolicy)
+		}
+		if hsts != "" {
+			c.Set(fiber.HeaderStrictTransportSecurity, hsts)
+		}
+		// CSP ketat hanya untuk response API. Swagger UI butuh script & style,
+		// jadi path /swagger dikecualikan.
+		if cfg.ContentSecurityPolicy != "" && !strings.HasPrefix(c.Path(), "/swagger") {
+			c.Set(fiber.HeaderContentSecurityPolicy, cfg.ContentSecurityPolicy)
+		}
+		return c.Next()
+	}
+}
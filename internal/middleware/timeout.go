@@ -0,0 +1,48 @@
+// internal/middleware/timeout.go
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Timeout membatasi waktu proses satu request dengan memasang deadline budget pada
+// c.UserContext(). Query pgx (dan Acquire koneksi pool) yang memakai context itu dibatalkan
+// saat deadline lewat, sehingga laporan yang berat tidak menahan koneksi pool tanpa batas.
+// Jika deadline lewat dan handler gagal (error atau status 5xx), response diganti dengan 504
+// lewat ErrorHandler (problem+json, error_code GATEWAY_TIMEOUT); response yang sudah berhasil
+// dibiarkan. budget <= 0 = tanpa batas.
+//
+// Context dibatalkan saat handler selesai, jadi route yang menulis body secara streaming
+// setelah handler kembali (SendStream, SetBodyStreamWriter) tidak boleh memakai middleware ini.
+func Timeout(budget time.Duration) fiber.Handler {
+	if budget <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), budget)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return err
+		}
+		if err == nil && c.Response().StatusCode() < fiber.StatusInternalServerError {
+			return nil // Selesai tepat di batas waktu
+		}
+		zlog.Ctx(c.UserContext()).Warn().Err(err).
+			Str("route", c.Route().Path).
+			Dur("timeout", budget).
+			Msg("Request exceeded its time limit, queries cancelled")
+		c.Response().ResetBody()
+		return fiber.NewError(fiber.StatusGatewayTimeout, fmt.Sprintf("The request did not complete within %s", budget))
+	}
+}
@@ -0,0 +1,139 @@
+// internal/middleware/upload.go
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/models" // Model untuk struktur Response
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Preset tipe MIME yang umum dipakai endpoint upload.
+var (
+	ImageMIMETypes    = []string{"image/jpeg", "image/png", "image/webp"}
+	CSVMIMETypes      = []string{"text/csv", "text/plain"} // http.DetectContentType mendeteksi CSV sebagai text/plain.
+	DocumentMIMETypes = []string{"application/pdf", "image/jpeg", "image/png"}
+)
+
+// UploadRule mendefinisikan aturan validasi untuk satu field file pada form multipart.
+type UploadRule struct {
+	Field        string   // Nama field form (misal: "photo", "file").
+	Required     bool     // Tolak request jika field tidak ada.
+	MaxBytes     int64    // Ukuran maksimum per file (0 = tanpa batas tambahan).
+	AllowedMIMEs []string // Tipe MIME yang diizinkan berdasarkan hasil sniffing konten.
+}
+
+// BodyLimit menolak request dengan body lebih besar dari maxBytes menggunakan
+// response 413 terstruktur. Dipakai untuk membatasi route tertentu lebih ketat
+// daripada batas global (fiber.Config.BodyLimit).
+func BodyLimit(maxBytes int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Request().Header.ContentLength() > maxBytes || len(c.Body()) > maxBytes {
+			zlog.Ctx(c.UserContext()).Warn().Str("path", c.Path()).Int("max_bytes", maxBytes).Int("content_length", c.Request().Header.ContentLength()).Msg("Request body too large")
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(models.Response{
+				Success: false, Message: fmt.Sprintf("Request body exceeds the maximum size of %d bytes", maxBytes),
+			})
+		}
+		return c.Next()
+	}
+}
+
+// RequireContentType memastikan request yang membawa body memiliki Content-Type
+// yang diizinkan (dibandingkan tanpa parameter seperti charset/boundary).
+// Request tanpa body (misal: check-in tanpa catatan) tetap diteruskan.
+func RequireContentType(allowed ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if len(c.Body()) == 0 || isReadMethod(c.Method()) {
+			return c.Next()
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+		if err == nil {
+			for _, a := range allowed {
+				if strings.EqualFold(mediaType, a) {
+					return c.Next()
+				}
+			}
+		}
+
+		zlog.Ctx(c.UserContext()).Warn().Str("path", c.Path()).Str("content_type", c.Get(fiber.HeaderContentType)).Strs("allowed", allowed).Msg("Unsupported request content type")
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(models.Response{
+			Success: false,
+			Message: fmt.Sprintf("Unsupported Content-Type, expected one of: %s", strings.Join(allowed, ", ")),
+		})
+	}
+}
+
+// ValidateUpload memvalidasi file pada form multipart sesuai daftar UploadRule:
+// ukuran per file (413) dan tipe konten hasil sniffing byte awal file (415).
+// Header Content-Type dari klien tidak dipercaya karena mudah dipalsukan.
+func ValidateUpload(rules ...UploadRule) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		form, err := c.MultipartForm()
+		if err != nil {
+			zlog.Ctx(c.UserContext()).Warn().Err(err).Str("path", c.Path()).Msg("Invalid multipart form for upload")
+			return c.Status(fiber.StatusUnsupportedMediaType).JSON(models.Response{
+				Success: false, Message: "Request must be multipart/form-data",
+			})
+		}
+
+		for _, rule := range rules {
+			files := form.File[rule.Field]
+			if len(files) == 0 {
+				if rule.Required {
+					return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+						Success: false, Message: fmt.Sprintf("File field '%s' is required", rule.Field),
+					})
+				}
+				continue
+			}
+
+			for _, fh := range files {
+				// --- 1. Cek Ukuran ---
+				if rule.MaxBytes > 0 && fh.Size > rule.MaxBytes {
+					zlog.Ctx(c.UserContext()).Warn().Str("field", rule.Field).Str("filename", fh.Filename).Int64("size", fh.Size).Int64("max_bytes", rule.MaxBytes).Msg("Uploaded file too large")
+					return c.Status(fiber.StatusRequestEntityTooLarge).JSON(models.Response{
+						Success: false, Message: fmt.Sprintf("File '%s' exceeds the maximum size of %d bytes", fh.Filename, rule.MaxBytes),
+					})
+				}
+
+				// --- 2. Sniffing MIME dari 512 byte pertama ---
+				f, err := fh.Open()
+				if err != nil {
+					zlog.Ctx(c.UserContext()).Error().Err(err).Str("field", rule.Field).Msg("Failed to open uploaded file for validation")
+					return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+						Success: false, Message: "Failed to read uploaded file",
+					})
+				}
+				head := make([]byte, 512)
+				n, _ := f.Read(head)
+				f.Close()
+
+				detected, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+				if len(rule.AllowedMIMEs) > 0 && !containsFold(rule.AllowedMIMEs, detected) {
+					zlog.Ctx(c.UserContext()).Warn().Str("field", rule.Field).Str("filename", fh.Filename).Str("detected_mime", detected).Strs("allowed", rule.AllowedMIMEs).Msg("Uploaded file type not allowed")
+					return c.Status(fiber.StatusUnsupportedMediaType).JSON(models.Response{
+						Success: false,
+						Message: fmt.Sprintf("File '%s' has unsupported type '%s'", fh.Filename, detected),
+					})
+				}
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// containsFold mengecek apakah 'value' ada di 'list' (case-insensitive).
+func containsFold(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
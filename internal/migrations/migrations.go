@@ -0,0 +1,367 @@
+// internal/migrations/migrations.go
+
+// Package migrations menjalankan file migrations/*.up.sql dengan pencatatan versi yang sama
+// dengan golang-migrate (tabel schema_migrations: satu baris version & dirty), sehingga runner
+// ini dan CLI migrate bisa dipakai bergantian. Sebelum migrasi dijalankan, Destructive
+// memeriksa perubahan yang merusak versi aplikasi lama yang masih berjalan (DROP, RENAME,
+// ganti tipe kolom, NOT NULL baru); cmd/migrate menolaknya selama instance versi lain masih
+// mengirim heartbeat (lihat internal/instances).
+package migrations
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// lockID adalah kunci advisory lock sesi yang menserialkan runner migrasi antar proses.
+const lockID = 4714
+
+// File adalah satu migrasi up.
+type File struct {
+	Version int64
+	Name    string // Nama file, mis. 000049_app_instances.up.sql
+	SQL     string
+}
+
+// Finding adalah satu statement destruktif di file migrasi.
+type Finding struct {
+	Version   int64  `json:"version"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Reason    string `json:"reason"`
+	Statement string `json:"statement"` // Dinormalisasi (spasi dirapatkan), dipotong jika panjang
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", f.File, f.Line, f.Reason, f.Statement)
+}
+
+// Load membaca semua file *.up.sql di dir, urut versi. Nama file harus diawali versi angka
+// (format golang-migrate, mis. 000049_app_instances.up.sql).
+func Load(dir string) ([]File, error) {
+	files, err := LoadFS(os.DirFS(dir))
+	if errors.Is(err, errNoMigrations) {
+		return nil, fmt.Errorf("no *.up.sql migrations found in %s", dir)
+	}
+	return files, err
+}
+
+// errNoMigrations dikembalikan LoadFS jika tidak ada file *.up.sql.
+var errNoMigrations = errors.New("no *.up.sql migrations found")
+
+// LoadFS sama dengan Load untuk file di root fsys, mis. migrasi yang disematkan dengan go:embed.
+func LoadFS(fsys fs.FS) ([]File, error) {
+	names, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errNoMigrations
+	}
+	files := make([]File, 0, len(names))
+	seen := map[int64]string{}
+	for _, name := range names {
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			prefix = strings.TrimSuffix(name, ".up.sql")
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s does not start with a numeric version", name)
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migrations %s and %s have the same version %d", other, name, version)
+		}
+		seen[version] = name
+		sql, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", name, err)
+		}
+		files = append(files, File{Version: version, Name: name, SQL: string(sql)})
+	}
+	slices.SortFunc(files, func(a, b File) int { return cmp.Compare(a.Version, b.Version) })
+	return files, nil
+}
+
+// Pending mengembalikan migrasi dengan versi di atas current.
+func Pending(files []File, current int64) []File {
+	var pending []File
+	for _, f := range files {
+		if f.Version > current {
+			pending = append(pending, f)
+		}
+	}
+	return pending
+}
+
+// Lock mengambil advisory lock runner migrasi pada conn (menunggu jika runner lain sedang
+// berjalan); fungsi yang dikembalikan melepasnya.
+func Lock(ctx context.Context, conn *pgx.Conn) (func(), error) {
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return nil, fmt.Errorf("error acquiring migration lock: %w", err)
+	}
+	return func() { _, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID) }, nil
+}
+
+// CurrentVersion membaca versi migrasi database, membuat tabel schema_migrations jika belum
+// ada. Versi 0 berarti belum ada migrasi.
+func CurrentVersion(ctx context.Context, conn *pgx.Conn) (version int64, dirty bool, err error) {
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`); err != nil {
+		return 0, false, fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+	return AppliedVersion(ctx, conn)
+}
+
+// Querier dipenuhi *pgx.Conn, *pgxpool.Pool, dan pgx.Tx.
+type Querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// AppliedVersion membaca versi migrasi database tanpa mengubah apa pun (dipakai juga oleh
+// diagnostik). Versi 0 berarti belum ada migrasi atau schema_migrations belum ada.
+func AppliedVersion(ctx context.Context, q Querier) (version int64, dirty bool, err error) {
+	err = q.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	var pgErr *pgconn.PgError
+	if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == "42P01") { // 42P01: undefined_table
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("error reading schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// Apply menjalankan satu migrasi seperti golang-migrate: versi dicatat dirty, file dijalankan
+// (boleh berisi banyak statement), lalu versi ditandai bersih. Jika file gagal, versi tetap
+// dirty dan harus diperbaiki manual (migrate force).
+func Apply(ctx context.Context, conn *pgx.Conn, f File) error {
+	if err := setVersion(ctx, conn, f.Version, true); err != nil {
+		return err
+	}
+	if _, err := conn.Exec(ctx, f.SQL); err != nil {
+		return fmt.Errorf("migration %s failed (schema version %d is now dirty): %w", f.Name, f.Version, err)
+	}
+	return setVersion(ctx, conn, f.Version, false)
+}
+
+func setVersion(ctx context.Context, conn *pgx.Conn, version int64, dirty bool) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error setting schema version: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+	if _, err := tx.Exec(ctx, `TRUNCATE schema_migrations`); err != nil {
+		return fmt.Errorf("error setting schema version: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)`, version, dirty); err != nil {
+		return fmt.Errorf("error setting schema version: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// Aturan statement destruktif, dicocokkan pada statement yang sudah dinormalisasi (huruf
+// besar, spasi dirapatkan, isi string & body $$ dibuang).
+var (
+	dropObject     = regexp.MustCompile(`^DROP (TABLE|VIEW|MATERIALIZED VIEW|TYPE|SCHEMA|SEQUENCE) `)
+	alterTable     = regexp.MustCompile(`^ALTER TABLE (IF EXISTS )?(ONLY )?\S+ (.*)$`)
+	dropColumn     = regexp.MustCompile(`^DROP (COLUMN )?`)
+	dropConstraint = regexp.MustCompile(`^DROP CONSTRAINT `)
+	renameAction   = regexp.MustCompile(`^RENAME (COLUMN |TO )?`)
+	renameOther    = regexp.MustCompile(`^RENAME CONSTRAINT `)
+	changeType     = regexp.MustCompile(`^ALTER (COLUMN )?\S+ (SET DATA )?TYPE `)
+	setNotNull     = regexp.MustCompile(`^ALTER (COLUMN )?\S+ SET NOT NULL`)
+	addColumn      = regexp.MustCompile(`^ADD (COLUMN )?`)
+	addConstraint  = regexp.MustCompile(`^ADD (CONSTRAINT|PRIMARY KEY|UNIQUE|CHECK|FOREIGN KEY|EXCLUDE) `)
+)
+
+// Destructive mengembalikan statement di f yang merusak versi aplikasi lama yang masih
+// berjalan: menghapus atau mengganti nama tabel/kolom/tipe, mengubah tipe kolom, mewajibkan
+// NOT NULL pada kolom lama, menambah kolom NOT NULL tanpa DEFAULT, atau TRUNCATE. Pemeriksaan
+// berbasis teks: body fungsi ($$ ... $$) dan SQL dinamis tidak diperiksa.
+func Destructive(f File) []Finding {
+	var findings []Finding
+	for _, stmt := range splitStatements(f.SQL) {
+		for _, reason := range destructiveReasons(stmt.text) {
+			findings = append(findings, Finding{Version: f.Version, File: f.Name, Line: stmt.line, Reason: reason, Statement: truncate(stmt.text, 160)})
+		}
+	}
+	return findings
+}
+
+func destructiveReasons(stmt string) []string {
+	if m := dropObject.FindStringSubmatch(stmt); m != nil {
+		return []string{"drops a " + strings.ToLower(m[1])}
+	}
+	if strings.HasPrefix(stmt, "TRUNCATE ") {
+		return []string{"deletes all rows of a table"}
+	}
+	if strings.HasPrefix(stmt, "ALTER TYPE ") && strings.Contains(stmt, " RENAME ") {
+		return []string{"renames a type or enum value"}
+	}
+	m := alterTable.FindStringSubmatch(stmt)
+	if m == nil {
+		return nil
+	}
+	var reasons []string
+	add := func(reason string) {
+		if !slices.Contains(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+	for _, action := range splitTopLevel(m[3]) {
+		switch {
+		case dropConstraint.MatchString(action): // Tidak mengubah kolom yang dibaca/ditulis versi lama
+		case dropColumn.MatchString(action):
+			add("drops a column")
+		case renameOther.MatchString(action): // Tidak mengubah kolom yang dibaca/ditulis versi lama
+		case renameAction.MatchString(action):
+			add("renames a table or column")
+		case changeType.MatchString(action):
+			add("changes a column type")
+		case setNotNull.MatchString(action):
+			add("makes an existing column NOT NULL")
+		case addConstraint.MatchString(action): // Tidak mengubah kolom yang dibaca/ditulis versi lama
+		case addColumn.MatchString(action):
+			if strings.Contains(action, " NOT NULL") && !strings.Contains(action, " DEFAULT ") && !strings.Contains(action, " GENERATED ") {
+				add("adds a NOT NULL column without DEFAULT")
+			}
+		}
+	}
+	return reasons
+}
+
+type statement struct {
+	text string
+	line int
+}
+
+// splitStatements memecah SQL per ';' di luar string, identifier ber-quote, komentar, dan body
+// dollar-quoted. Komentar dibuang; isi string & body $$ diganti placeholder agar kata kunci
+// di dalamnya tidak ikut diperiksa.
+func splitStatements(sql string) []statement {
+	var out []statement
+	var b strings.Builder
+	line, start := 1, 0
+	flush := func() {
+		text := strings.ToUpper(strings.Join(strings.Fields(b.String()), " "))
+		if text != "" {
+			out = append(out, statement{text: text, line: start})
+		}
+		b.Reset()
+		start = 0
+	}
+	mark := func() {
+		if start == 0 {
+			start = line
+		}
+	}
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\n':
+			line++
+			b.WriteByte(' ')
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+			i-- // '\n' diproses di iterasi berikutnya
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				end = len(sql) - i - 2
+			}
+			line += strings.Count(sql[i:i+2+end], "\n")
+			i += end + 3
+			b.WriteByte(' ')
+		case c == '\'' || c == '"':
+			mark()
+			end := strings.IndexByte(sql[i+1:], c)
+			if end < 0 {
+				end = len(sql) - i - 1
+			}
+			line += strings.Count(sql[i:i+1+end], "\n")
+			if c == '"' {
+				b.WriteString(sql[i : i+2+end]) // Identifier tetap utuh
+			} else {
+				b.WriteString("''")
+			}
+			i += end + 1
+		case c == '$':
+			tag := dollarTag(sql[i:])
+			if tag == "" {
+				b.WriteByte(c)
+				continue
+			}
+			mark()
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end < 0 {
+				end = len(sql) - i - len(tag)
+			}
+			line += strings.Count(sql[i:min(len(sql), i+2*len(tag)+end)], "\n")
+			b.WriteString("$$")
+			i += 2*len(tag) + end - 1
+		case c == ';':
+			flush()
+		default:
+			if c != ' ' && c != '\t' && c != '\r' {
+				mark()
+			}
+			b.WriteByte(c)
+		}
+	}
+	flush()
+	return out
+}
+
+// dollarTag mengembalikan tag pembuka dollar quote ($$ atau $nama$) di awal s.
+func dollarTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		if c == '$' {
+			return s[:j+1]
+		}
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || j > 1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+// splitTopLevel memecah daftar aksi ALTER TABLE per koma di luar tanda kurung.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -1,35 +1,183 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
 type Role struct {
-	ID   int    `json:"id"`
-	Name string `json:"name" validate:"required,min=3,max=50"`
+	ID       int    `json:"id"`
+	Name     string `json:"name" validate:"required,min=3,max=50"`
+	ParentID *int   `json:"parent_id,omitempty" validate:"omitempty,gt=0"` // Role yang hak aksesnya diwarisi (lihat internal/rbac)
+}
+
+// RoleParentInput dipakai PUT /admin/roles/{roleId}/parent. ParentID nil melepas pewarisan.
+type RoleParentInput struct {
+	ParentID *int `json:"parent_id" validate:"omitempty,gt=0"`
+}
+
+// EffectiveRole adalah role beserta rantai role yang diwarisinya, terdekat dulu.
+type EffectiveRole struct {
+	Role      Role     `json:"role"`
+	Inherited []string `json:"inherited"`       // Nama role yang diwarisi (parent, parent dari parent, ...)
+	Effective []string `json:"effective_roles"` // Role sendiri + Inherited; dipakai pemeriksaan akses
 }
 
 type User struct {
-	ID        int       `json:"id"`
-	Username  string    `json:"username" validate:"required,min=3,max=100"`
-	Password  string    `json:"-"`
-	Email     string    `json:"email" validate:"required,email"`
-	FirstName string    `json:"first_name,omitempty"`
-	LastName  string    `json:"last_name,omitempty"`
-	RoleID    int       `json:"role_id" validate:"required"`
-	Role      *Role     `json:"role,omitempty"`
-	CreatedAt time.Time `json:"created_at,omitzero"`
-	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	ID                    int        `json:"id"`
+	Username              string     `json:"username" validate:"required,min=3,max=100"`
+	Password              string     `json:"-"`
+	Email                 string     `json:"email" validate:"required,email"`
+	FirstName             string     `json:"first_name,omitempty"`
+	LastName              string     `json:"last_name,omitempty"`
+	Phone                 *string    `json:"phone,omitempty"`       // Disimpan terenkripsi (lihat internal/pii)
+	NationalID            *string    `json:"national_id,omitempty"` // Disimpan terenkripsi (lihat internal/pii)
+	RoleID                int        `json:"role_id" validate:"required"`
+	Role                  *Role      `json:"role,omitempty"`
+	UserType              string     `json:"user_type,omitempty"`               // employee / contractor
+	ValidFrom             *string    `json:"valid_from,omitempty"`              // Awal masa akses (YYYY-MM-DD), nil = tidak dibatasi
+	ValidUntil            *string    `json:"valid_until,omitempty"`             // Akhir masa akses, inklusif (YYYY-MM-DD); wajib untuk contractor
+	IsActive              bool       `json:"is_active"`                         // FALSE setelah dinonaktifkan (mis. kontrak berakhir)
+	HireDate              *string    `json:"hire_date,omitempty"`               // Tanggal mulai kerja (YYYY-MM-DD)
+	EmploymentStatus      string     `json:"employment_status,omitempty"`       // Lihat EmploymentStatus*
+	ProbationEnd          *string    `json:"probation_end,omitempty"`           // YYYY-MM-DD; wajib jika status probation
+	ManagerID             *int       `json:"manager_id,omitempty"`              // Atasan langsung (garis pelaporan)
+	TokenVersion          int        `json:"-"`                                 // Disematkan di JWT; dinaikkan untuk mencabut semua sesi
+	PasswordResetRequired bool       `json:"password_reset_required,omitempty"` // Login ditolak sampai password di-reset
+	MustChangePassword    bool       `json:"must_change_password,omitempty"`    // Password sementara dari admin; login pertama wajib menggantinya
+	PhoneVerifiedAt       *time.Time `json:"phone_verified_at,omitempty"`       // Nomor HP dikonfirmasi lewat OTP SMS; direset saat nomor berganti
+	SMSTwoFactor          bool       `json:"sms_two_factor"`                    // Login memerlukan kode OTP SMS
+	SMSReminders          bool       `json:"sms_reminders"`                     // Pengingat shift juga dikirim lewat SMS
+	RemoteDays            string     `json:"remote_days,omitempty"`             // Hari boleh check-in remote, mis. "mon,fri"
+	FieldWorkAllowed      bool       `json:"field_work_allowed,omitempty"`      // Boleh check-in dengan mode field
+	Version               int        `json:"version,omitempty"`                 // Optimistic locking (lihat If-Match)
+	CreatedAt             time.Time  `json:"created_at,omitzero"`
+	UpdatedAt             time.Time  `json:"updated_at,omitzero"`
+}
+
+// Tipe user (kolom users.user_type).
+const (
+	UserTypeEmployee   = "employee"
+	UserTypeContractor = "contractor"
+)
+
+// Status kepegawaian (kolom users.employment_status).
+const (
+	EmploymentStatusProbation  = "probation"
+	EmploymentStatusPermanent  = "permanent"
+	EmploymentStatusFixedTerm  = "fixed_term"
+	EmploymentStatusIntern     = "intern"
+	EmploymentStatusTerminated = "terminated"
+)
+
+// EmploymentStatuses adalah semua status kepegawaian yang valid (untuk validasi filter).
+var EmploymentStatuses = []string{
+	EmploymentStatusProbation, EmploymentStatusPermanent, EmploymentStatusFixedTerm,
+	EmploymentStatusIntern, EmploymentStatusTerminated,
+}
+
+// AccessWindow mengembalikan rentang waktu akses user [start, end) di zona waktu loc.
+// ValidUntil bersifat inklusif, sehingga end adalah awal hari setelahnya. Nil = tidak dibatasi.
+func (u *User) AccessWindow(loc *time.Location) (start, end *time.Time) {
+	parse := func(date *string) *time.Time {
+		if date == nil {
+			return nil
+		}
+		t, err := time.ParseInLocation("2006-01-02", *date, loc)
+		if err != nil {
+			return nil
+		}
+		return &t
+	}
+	start = parse(u.ValidFrom)
+	if end = parse(u.ValidUntil); end != nil {
+		next := end.AddDate(0, 0, 1)
+		end = &next
+	}
+	return start, end
+}
+
+// UpdateEmploymentInput mengatur data kepegawaian user (PUT /admin/users/:userId/employment).
+// Status probation wajib memiliki probation_end.
+type UpdateEmploymentInput struct {
+	HireDate         *string `json:"hire_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EmploymentStatus string  `json:"employment_status" validate:"required,oneof=probation permanent fixed_term intern terminated"`
+	ProbationEnd     *string `json:"probation_end,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// SetManagerInput mengatur atasan langsung user (PUT /admin/users/:userId/manager); null = tanpa atasan.
+type SetManagerInput struct {
+	ManagerID *int `json:"manager_id" validate:"omitempty,gt=0"`
+}
+
+// Status kehadiran hari ini pada org-chart.
+const (
+	PresencePresent    = "present"     // Sedang check-in (sesi terbuka)
+	PresenceCheckedOut = "checked_out" // Sudah check-in & check-out hari ini
+	PresenceAbsent     = "absent"      // Terjadwal hari ini tapi belum check-in
+	PresenceOff        = "off"         // Tidak terjadwal hari ini
+)
+
+// OrgChartNode adalah satu user pada pohon garis pelaporan beserta status kehadirannya hari ini.
+// Repository mengembalikan node datar (Reports kosong) terurut per depth; handler menyusun pohonnya.
+type OrgChartNode struct {
+	UserID    int             `json:"user_id"`
+	Username  string          `json:"username"`
+	FirstName string          `json:"first_name,omitempty"`
+	LastName  string          `json:"last_name,omitempty"`
+	ManagerID *int            `json:"manager_id,omitempty"`
+	Depth     int             `json:"depth"` // 0 = akar pohon
+	Presence  string          `json:"presence"`
+	Reports   []*OrgChartNode `json:"reports,omitempty"`
+}
+
+// UserExportFilter menyaring daftar user yang diekspor admin (nilai kosong/nil = semua).
+// Tag JSON dipakai saat filter disimpan sebagai parameter ekspor laporan (ReportExport).
+type UserExportFilter struct {
+	RoleID           *int   `json:"role_id,omitempty" validate:"omitempty,gt=0"`
+	UserType         string `json:"user_type,omitempty" validate:"omitempty,oneof=employee contractor"`
+	EmploymentStatus string `json:"employment_status,omitempty" validate:"omitempty,oneof=probation permanent fixed_term intern terminated"`
+	IsActive         *bool  `json:"is_active,omitempty"`
+}
+
+// UserExportRow adalah satu baris ekspor user: data user (termasuk role) dan aktivitas terakhirnya
+// (check-in/check-out terbaru; nil jika belum pernah absen).
+type UserExportRow struct {
+	User              User
+	LastActivityAt    *time.Time
+	PreviousUsernames []string // Username lama, terbaru dulu
+}
+
+// AttendanceReportFilter adalah filter tambahan laporan absensi admin (kosong = semua).
+type AttendanceReportFilter struct {
+	EmploymentStatus string
+	DisputedOnly     bool // Hanya record dengan dispute yang masih open
+}
+
+// UpdateUserAccessInput mengatur tipe user dan masa aksesnya (PUT /admin/users/:userId/access).
+// Contractor wajib memiliki valid_until; employee tidak boleh memiliki masa akses.
+type UpdateUserAccessInput struct {
+	UserType   string  `json:"user_type" validate:"required,oneof=employee contractor"`
+	ValidFrom  *string `json:"valid_from,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	ValidUntil *string `json:"valid_until,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// UpdateWorkModesInput mengatur kebijakan mode kerja user (PUT /admin/users/:userId/work-modes).
+// Field yang tidak dikirim tidak diubah.
+type UpdateWorkModesInput struct {
+	RemoteDays       *string `json:"remote_days,omitempty"` // Hari sun..sat dipisah koma; "" = tidak boleh remote
+	FieldWorkAllowed *bool   `json:"field_work_allowed,omitempty"`
 }
 
 // Input struct terpisah untuk registrasi dan login
 type RegisterUserInput struct {
-	Username  string `json:"username" validate:"required,min=3,max=100"`
-	Password  string `json:"password" validate:"required,min=6"`
-	Email     string `json:"email" validate:"required,email"`
-	FirstName string `json:"first_name,omitempty"`
-	LastName  string `json:"last_name,omitempty"`
-	RoleID    int    `json:"role_id" validate:"required,gt=0"`
+	Username  string  `json:"username" validate:"required,min=3,max=100"`
+	Password  string  `json:"password" validate:"required,min=6"`
+	Email     string  `json:"email" validate:"required,email"`
+	FirstName string  `json:"first_name,omitempty"`
+	LastName  string  `json:"last_name,omitempty"`
+	Phone     *string `json:"phone,omitempty" validate:"omitempty,e164"`
+	RoleID    int     `json:"role_id,omitempty" validate:"omitempty,gt=0"` // Kosong = role default registrasi
 }
 
 type LoginUserInput struct {
@@ -37,13 +185,55 @@ type LoginUserInput struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// DenyLoginInput adalah token dari tautan "bukan saya" pada email peringatan login.
+type DenyLoginInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ResetPasswordInput mengganti password memakai token reset (setelah sesi dicabut).
+type ResetPasswordInput struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=6"`
+}
+
 type Shift struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name" validate:"required,min=3,max=100"`
-	StartTime string    `json:"start_time" validate:"required"` // Format HH:MM:SS
-	EndTime   string    `json:"end_time" validate:"required"`   // Format HH:MM:SS
-	CreatedAt time.Time `json:"created_at,omitzero"`
-	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	ID         int       `json:"id"`
+	Name       string    `json:"name" validate:"required,min=3,max=100"`
+	StartTime  string    `json:"start_time" validate:"required"` // Format HH:MM:SS
+	EndTime    string    `json:"end_time" validate:"required"`   // Format HH:MM:SS
+	Version    int       `json:"version,omitempty"`              // Optimistic locking; saat update, 0 = tanpa precondition
+	OverrideID *int      `json:"override_id,omitempty"`          // Hanya di jadwal: override musiman yang mengubah jam shift
+	CreatedAt  time.Time `json:"created_at,omitzero"`
+	UpdatedAt  time.Time `json:"updated_at,omitzero"`
+}
+
+// ShiftOverride mengubah jam shift untuk jadwal dalam rentang tanggal (mis. jam kerja Ramadan)
+// tanpa mengedit shift atau jadwal satu per satu. Berlaku untuk semua shift (ShiftID nil,
+// jam digeser offset) atau satu shift (jam absolut StartTime/EndTime, atau offset).
+type ShiftOverride struct {
+	ID                 int       `json:"id"`
+	Name               string    `json:"name"`
+	ShiftID            *int      `json:"shift_id"`   // nil = semua shift
+	StartDate          string    `json:"start_date"` // YYYY-MM-DD, inklusif
+	EndDate            string    `json:"end_date"`   // YYYY-MM-DD, inklusif
+	StartTime          *string   `json:"start_time,omitempty"`
+	EndTime            *string   `json:"end_time,omitempty"`
+	StartOffsetMinutes int       `json:"start_offset_minutes"`
+	EndOffsetMinutes   int       `json:"end_offset_minutes"`
+	CreatedBy          *int      `json:"created_by,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// ShiftOverrideInput adalah body untuk membuat override shift.
+type ShiftOverrideInput struct {
+	Name               string  `json:"name" validate:"required,min=3,max=100"`
+	ShiftID            *int    `json:"shift_id" validate:"omitempty,gt=0"`
+	StartDate          string  `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate            string  `json:"end_date" validate:"required,datetime=2006-01-02"`
+	StartTime          *string `json:"start_time" validate:"omitempty,datetime=15:04:05,required_with=EndTime"` // Jam absolut, hanya dengan shift_id
+	EndTime            *string `json:"end_time" validate:"omitempty,datetime=15:04:05,required_with=StartTime"`
+	StartOffsetMinutes int     `json:"start_offset_minutes" validate:"min=-720,max=720"`
+	EndOffsetMinutes   int     `json:"end_offset_minutes" validate:"min=-720,max=720"`
 }
 
 type UserSchedule struct {
@@ -51,28 +241,214 @@ type UserSchedule struct {
 	UserID    int       `json:"user_id" validate:"required"`
 	ShiftID   int       `json:"shift_id" validate:"required"`
 	Date      string    `json:"date" validate:"required"` // Format YYYY-MM-DD
+	Version   int       `json:"version,omitempty"`        // Optimistic locking; saat update, 0 = tanpa precondition
 	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 	User      *User     `json:"user,omitempty"`
 	Shift     *Shift    `json:"shift,omitempty"`
+	// Hanya diisi dengan ?include=attendance: absensi pada tanggal jadwal (check-in pertama) dan statusnya.
+	Attendance       *Attendance `json:"attendance,omitempty"`
+	AttendanceStatus string      `json:"attendance_status,omitempty"` // AttendanceStatusPresent / AttendanceStatusMissing
 }
 
+// Status kepatuhan jadwal (UserSchedule.AttendanceStatus).
+const (
+	AttendanceStatusPresent = "present" // Ada check-in pada tanggal jadwal
+	AttendanceStatusMissing = "missing" // Belum/tidak ada check-in pada tanggal jadwal
+)
+
 type Attendance struct {
 	ID         int        `json:"id"`
 	UserID     int        `json:"user_id" validate:"required"`
+	ScheduleID *int       `json:"schedule_id,omitempty"` // Jadwal yang berlaku saat check-in; nil = tanpa jadwal
+	ProjectID  *int       `json:"project_id,omitempty"`  // Project/cost center yang dipilih saat check-in
 	CheckInAt  time.Time  `json:"check_in_at"`
 	CheckOutAt *time.Time `json:"check_out_at,omitempty"`
 	Notes      *string    `json:"notes,omitempty"`
+	Mode       string     `json:"mode"`           // AttendanceMode*
+	Tags       []string   `json:"tags,omitempty"` // Tag terstruktur (attendance_tags); hanya di riwayat, laporan & ekspor
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
 	User       *User      `json:"user,omitempty"`
+	// Hanya dengan ?include=schedule / ?include=shift: jadwal yang ditautkan dan shift efektifnya.
+	Schedule *UserSchedule `json:"schedule,omitempty"`
+	Shift    *Shift        `json:"shift,omitempty"`
+	// Pembagian jam kerja per kategori; hanya di laporan admin untuk sesi yang sudah check-out.
+	Hours *HoursBreakdown `json:"hours,omitempty"`
+	// Dispute karyawan yang masih open atas record ini; hanya di laporan admin.
+	OpenDisputeID *int `json:"open_dispute_id,omitempty"`
+}
+
+// Resource terkait yang bisa di-embed di listing lewat ?include= (dipisah koma). Setiap
+// endpoint mendokumentasikan nilai yang didukung dan embed bawaannya jika include tidak dikirim.
+const (
+	IncludeUser       = "user"       // Ringkasan user pemilik record
+	IncludeShift      = "shift"      // Shift efektif (termasuk override musiman)
+	IncludeSchedule   = "schedule"   // Jadwal yang ditautkan ke absensi
+	IncludeAttendance = "attendance" // Absensi pada tanggal jadwal
+)
+
+// Includes adalah himpunan nilai ?include= yang diminta.
+type Includes map[string]bool
+
+// Has melaporkan apakah resource name diminta.
+func (i Includes) Has(name string) bool { return i[name] }
+
+// Mode kerja sesi absensi (attendances.mode), dipilih saat check-in.
+const (
+	AttendanceModeOnsite = "onsite" // Default; selalu boleh
+	AttendanceModeRemote = "remote" // Hanya pada hari di User.RemoteDays
+	AttendanceModeField  = "field"  // Hanya jika User.FieldWorkAllowed
+)
+
+// HoursBreakdown adalah jam kerja satu sesi absensi per kategori tarif (jam desimal).
+// Setiap menit masuk tepat satu kategori: holiday > weekend > night > regular.
+type HoursBreakdown struct {
+	RegularHours float64 `json:"regular_hours"`
+	NightHours   float64 `json:"night_hours"`
+	WeekendHours float64 `json:"weekend_hours"`
+	HolidayHours float64 `json:"holiday_hours"`
+	TotalHours   float64 `json:"total_hours"`
 }
 
+// Jenis event pada ledger attendance_events.
+const (
+	AttendanceEventCheckIn      = "check_in"
+	AttendanceEventCheckOut     = "check_out"
+	AttendanceEventCorrection   = "correction"
+	AttendanceEventLegacyImport = "legacy_import"
+)
+
+// AttendanceEvent adalah satu entri append-only pada ledger absensi.
+// Setiap event menyimpan snapshot lengkap record setelah perubahan;
+// record Attendance saat ini sama dengan snapshot event terakhir.
+type AttendanceEvent struct {
+	ID           int64      `json:"id"`
+	AttendanceID int        `json:"attendance_id"`
+	UserID       int        `json:"user_id"`
+	EventType    string     `json:"event_type"`
+	CheckInAt    time.Time  `json:"check_in_at"`
+	CheckOutAt   *time.Time `json:"check_out_at,omitempty"`
+	Notes        *string    `json:"notes,omitempty"`
+	Reason       *string    `json:"reason,omitempty"`
+	ActorUserID  *int       `json:"actor_user_id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Aksi audit log (kolom audit_log.action, format "<kategori>.<aksi>").
+// Aksi schedule.* dicatat oleh trigger database pada tabel user_schedules; profile.username_changed
+// oleh trigger pada users.username.
+const (
+	AuditLoginSucceeded       = "auth.login_succeeded"
+	AuditLoginFailed          = "auth.login_failed"
+	AuditLoginRejected        = "auth.login_rejected"
+	AuditPasswordChanged      = "auth.password_changed"
+	AuditLoginDenied          = "auth.login_denied"            // User menekan tautan "bukan saya"; sesi dicabut
+	AuditPasswordReset        = "auth.password_reset"          // Password diganti lewat token reset
+	AuditScopedTokenIssued    = "auth.scoped_token_issued"     // Token terbatas (kiosk/laporan) diterbitkan
+	AuditKioskEnrolled        = "auth.kiosk_enrolled"          // Perangkat kiosk didaftarkan
+	AuditKioskRevoked         = "auth.kiosk_revoked"           // Perangkat kiosk dicabut (oleh user atau admin)
+	AuditSessionsRevoked      = "auth.sessions_revoked"        // Admin mencabut semua sesi user (akun disusupi)
+	AuditSMSPreferences       = "auth.sms_preferences_updated" // 2FA/pengingat SMS diaktifkan atau dimatikan
+	AuditPhoneVerified        = "profile.phone_verified"       // Nomor HP dikonfirmasi dengan kode OTP SMS
+	AuditProfileUpdated       = "profile.updated"
+	AuditAccessUpdated        = "profile.access_updated"
+	AuditEmploymentUpdated    = "profile.employment_updated"
+	AuditWorkModesUpdated     = "profile.work_modes_updated"     // Kebijakan remote/field user diubah admin
+	AuditEmailChangeRequested = "profile.email_change_requested" // Tautan konfirmasi dikirim ke email baru
+	AuditEmailChanged         = "profile.email_changed"          // Email baru dikonfirmasi dan diterapkan
+	AuditInvitationCreated    = "user.invitation_created"        // Admin mengundang email dengan role tertentu
+	AuditInvitationRevoked    = "user.invitation_revoked"        // Undangan dibatalkan admin
+	AuditInvitationAccepted   = "user.invitation_accepted"       // Akun dibuat dari tautan undangan
+	AuditUserCreated          = "user.created"                   // Akun dibuat langsung oleh admin
+	AuditUsernameChanged      = "profile.username_changed"       // Username lama disimpan di previous_usernames
+	AuditUsernameRequested    = "profile.username_change_requested"
+	AuditUsernameReviewed     = "profile.username_change_reviewed" // Disetujui/ditolak admin
+	AuditRoleChanged          = "profile.role_changed"
+	AuditScheduleCreated      = "schedule.created"
+	AuditScheduleUpdated      = "schedule.updated"
+	AuditScheduleDeleted      = "schedule.deleted"
+	AuditPayrollClosed        = "payroll.period_closed"       // Subjek = admin pelaku
+	AuditPayrollReopened      = "payroll.period_reopened"     // Subjek = admin pelaku
+	AuditAttendanceSigned     = "attendance.signed_off"       // Subjek = karyawan yang di-sign-off
+	AuditDisputeResolved      = "attendance.dispute_resolved" // Subjek = karyawan pemilik dispute
+	AuditDelegationCreated    = "approval.delegation_created" // Subjek = atasan pemberi delegasi
+	AuditDelegationRevoked    = "approval.delegation_revoked" // Subjek = atasan pemberi delegasi
+	AuditRouteViewed          = "attendance.route_viewed"     // Atasan melihat rute sesi field; subjek = karyawan
+
+	AuditVerificationLinkCreated = "employment.verification_link_created" // Subjek = karyawan yang diverifikasi
+	AuditVerificationLinkRevoked = "employment.verification_link_revoked" // Subjek = karyawan yang diverifikasi
+	AuditVerificationAccessed    = "employment.verification_accessed"     // Akses pihak ketiga (termasuk yang ditolak); subjek = karyawan
+)
+
+// AuditEntry adalah satu catatan audit log tentang user (subjek) UserID.
+// Details hanya berisi metadata (nama field yang berubah, IP, user agent), bukan nilai data pribadi.
+type AuditEntry struct {
+	ID          int64          `json:"id"`
+	UserID      int            `json:"user_id"`
+	ActorUserID *int           `json:"actor_user_id,omitempty"`
+	Action      string         `json:"action"`
+	Details     map[string]any `json:"details,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// Sumber entri feed aktivitas.
+const (
+	ActivitySourceAuditLog         = "audit_log"
+	ActivitySourceAttendanceEvents = "attendance_events"
+)
+
+// ActivityItem adalah satu entri feed aktivitas user: gabungan audit log dan ledger absensi,
+// terurut dari yang terbaru. Category adalah prefix Action (attendance, auth, profile, schedule).
+type ActivityItem struct {
+	Source      string         `json:"source"`    // Lihat ActivitySource*
+	SourceID    int64          `json:"source_id"` // ID baris di tabel sumber
+	Category    string         `json:"category"`
+	Action      string         `json:"action"`
+	OccurredAt  time.Time      `json:"occurred_at"`
+	ActorUserID *int           `json:"actor_user_id,omitempty"`
+	Details     map[string]any `json:"details,omitempty"`
+}
+
+// LoginHistoryMatch membandingkan login baru dengan riwayat login sukses user.
+type LoginHistoryMatch struct {
+	HasHistory   bool // Pernah login sukses sebelumnya
+	KnownDevice  bool // Perangkat (fingerprint user agent) pernah dipakai, atau belum ada riwayat perangkat
+	KnownCountry bool // Negara pernah dipakai, atau belum ada riwayat negara
+}
+
+// ActivityCursor menunjuk posisi entri terakhir yang sudah dibaca di feed aktivitas.
+// Banyak entri bisa berbagi waktu yang sama (mis. bulk delete jadwal dalam satu transaksi),
+// sehingga posisi memakai (OccurredAt, Source, SourceID), bukan waktu saja.
+type ActivityCursor struct {
+	OccurredAt time.Time
+	Source     string
+	SourceID   int64
+}
+
+// AttendanceCorrectionInput adalah koreksi admin atas record absensi.
+// Field yang tidak dikirim (nil) tidak diubah; alasan koreksi wajib diisi.
+type AttendanceCorrectionInput struct {
+	CheckInAt  *time.Time `json:"check_in_at,omitempty"`
+	CheckOutAt *time.Time `json:"check_out_at,omitempty"`
+	Notes      *string    `json:"notes,omitempty" validate:"omitempty,max=500"`
+	Reason     string     `json:"reason" validate:"required,min=5,max=500"`
+}
+
+// Catatan absensi dibatasi 500 karakter; tag dipilih dari pengaturan attendance.tags (maks. 5).
 type CheckInInput struct {
-	Notes *string `json:"notes,omitempty"`
+	Notes     *string  `json:"notes,omitempty" validate:"omitempty,max=500"`
+	Tags      []string `json:"tags,omitempty" validate:"omitempty,max=5,dive,required,max=50"` // Tag dari pengaturan attendance.tags
+	ProjectID *int     `json:"project_id,omitempty" validate:"omitempty,gt=0"`                 // Opsional: project aktif untuk sesi ini
+	Mode      string   `json:"mode,omitempty" validate:"omitempty,oneof=onsite remote field"`  // Kosong = onsite; remote/field mengikuti kebijakan user
+	// Selfie (JPEG/PNG, base64) untuk verifikasi wajah, dan disimpan sebagai foto check-in jika
+	// penyimpanan foto diaktifkan (ATTENDANCE_PHOTO_ENABLED).
+	Selfie *string `json:"selfie,omitempty" validate:"omitempty,base64,max=2800000"`
 }
 
 type CheckOutInput struct {
-	Notes *string `json:"notes,omitempty"`
+	Notes *string  `json:"notes,omitempty" validate:"omitempty,max=500"`
+	Tags  []string `json:"tags,omitempty" validate:"omitempty,max=5,dive,required,max=50"` // Ditambahkan ke tag sesi
 }
 
 // Response standar untuk API
@@ -80,24 +456,1540 @@ type Response struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	// RequestID diisi pada response error agar user bisa mengutip ID yang tercatat di log.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// MIMEApplicationProblemJSON adalah Content-Type body Problem (RFC 7807).
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem adalah body error RFC 7807 yang ditulis ErrorHandler global untuk error yang
+// dikembalikan handler/middleware (termasuk *fiber.Error dan panic). Response sukses dan
+// error yang ditulis langsung oleh handler tetap memakai Response.
+type Problem struct {
+	Type      string `json:"type"`               // Selalu "about:blank": arti error mengikuti status HTTP dan error_code
+	Title     string `json:"title"`              // Teks status HTTP, mis. "Not Found"
+	Status    int    `json:"status"`             // Status HTTP
+	Detail    string `json:"detail,omitempty"`   // Penjelasan untuk klien; kosong untuk error internal
+	Instance  string `json:"instance,omitempty"` // Path request yang gagal
+	ErrorCode string `json:"error_code"`         // Kode stabil untuk klien, mis. NOT_FOUND, VALIDATION_FAILED
+	RequestID string `json:"request_id,omitempty"`
 }
 
 type AdminUpdateUserInput struct {
-	Username  string `json:"username" validate:"required,min=3,max=100"`
-	Email     string `json:"email" validate:"required,email"`
-	FirstName string `json:"first_name,omitempty"`
-	LastName  string `json:"last_name,omitempty"`
-	RoleID    int    `json:"role_id" validate:"required,gt=0"` // Pastikan role ID > 0
+	Username   string  `json:"username" validate:"required,min=3,max=100"`
+	Email      string  `json:"email" validate:"required,email"`
+	FirstName  string  `json:"first_name,omitempty"`
+	LastName   string  `json:"last_name,omitempty"`
+	Phone      *string `json:"phone,omitempty" validate:"omitempty,e164"`
+	NationalID *string `json:"national_id,omitempty" validate:"omitempty,min=4,max=64"`
+	RoleID     int     `json:"role_id" validate:"required,gt=0"` // Pastikan role ID > 0
+	Version    int     `json:"version,omitempty"`                // Versi yang diharapkan (0 = tanpa precondition)
+}
+
+// AdminPatchUserInput adalah update parsial user oleh Admin (PATCH /admin/users/:userId).
+// Hanya field yang dikirim (non-nil) yang diubah. Phone/NationalID berisi "" untuk mengosongkan.
+type AdminPatchUserInput struct {
+	Username   *string `json:"username,omitempty" validate:"omitempty,min=3,max=100"`
+	Email      *string `json:"email,omitempty" validate:"omitempty,email"`
+	FirstName  *string `json:"first_name,omitempty"`
+	LastName   *string `json:"last_name,omitempty"`
+	Phone      *string `json:"phone,omitempty" validate:"omitempty,e164"`
+	NationalID *string `json:"national_id,omitempty" validate:"omitempty,min=4,max=64"`
+	RoleID     *int    `json:"role_id,omitempty" validate:"omitempty,gt=0"`
+	Version    int     `json:"version,omitempty"` // Versi yang diharapkan (0 = tanpa precondition)
+}
+
+// AdminRevokeSessionsInput adalah body opsional POST /admin/users/:userId/revoke-sessions.
+type AdminRevokeSessionsInput struct {
+	Reason     string `json:"reason,omitempty" validate:"max=500"` // Dicatat di audit log (dan dikirim ke user jika notify_user)
+	NotifyUser bool   `json:"notify_user,omitempty"`               // Beri tahu user lewat email & inbox
+}
+
+// ScheduleConflict adalah jadwal user yang jam shift-nya tumpang tindih dengan jadwal yang
+// akan dibuat/diubah (dikirim di payload 409). StartsAt/EndsAt adalah waktu lokal tanpa zona
+// (YYYY-MM-DDTHH:MM:SS); EndsAt jatuh di hari berikutnya untuk shift malam.
+type ScheduleConflict struct {
+	ScheduleID int    `json:"schedule_id"`
+	Date       string `json:"date"` // Format YYYY-MM-DD
+	ShiftID    int    `json:"shift_id"`
+	ShiftName  string `json:"shift_name"`
+	StartsAt   string `json:"starts_at"`
+	EndsAt     string `json:"ends_at"`
+}
+
+// ShiftHeadcount adalah jumlah user yang dijadwalkan pada satu shift di satu tanggal dan
+// berapa di antaranya yang benar-benar check-in.
+type ShiftHeadcount struct {
+	Date      string `json:"date"` // Format YYYY-MM-DD
+	ShiftID   int    `json:"shift_id"`
+	ShiftName string `json:"shift_name"`
+	Scheduled int    `json:"scheduled"`
+	Attended  int    `json:"attended"`
+}
+
+// StaffingSuggestion adalah saran jumlah orang untuk satu shift pada tanggal mendatang,
+// dari rata-rata kehadiran shift tersebut pada hari yang sama di minggu-minggu sebelumnya.
+type StaffingSuggestion struct {
+	Date               string  `json:"date"`    // Format YYYY-MM-DD
+	Weekday            string  `json:"weekday"` // Mis. Monday
+	ShiftID            int     `json:"shift_id"`
+	ShiftName          string  `json:"shift_name"`
+	SuggestedHeadcount int     `json:"suggested_headcount"` // Pembulatan ke atas average_attended
+	AverageAttended    float64 `json:"average_attended"`
+	Samples            int     `json:"samples"`             // Jumlah hari historis yang dirata-rata
+	ScheduledHeadcount int     `json:"scheduled_headcount"` // Jadwal yang sudah dibuat untuk tanggal tsb
+	Gap                int     `json:"gap"`                 // suggested - scheduled; positif = kurang orang
+	DayType            string  `json:"day_type"`            // Jenis hari menurut kalender kerja (lihat CalendarDay*)
+}
+
+// PatchScheduleInput adalah update parsial jadwal (PATCH /admin/schedules/:scheduleId).
+type PatchScheduleInput struct {
+	UserID  *int    `json:"user_id,omitempty" validate:"omitempty,gt=0"`
+	ShiftID *int    `json:"shift_id,omitempty" validate:"omitempty,gt=0"`
+	Date    *string `json:"date,omitempty"` // Format YYYY-MM-DD
+	Version int     `json:"version,omitempty"`
 }
 
 type UpdateProfileInput struct {
-	Username  string `json:"username" validate:"required,min=3,max=100"`
-	Email     string `json:"email" validate:"required,email"`
-	FirstName string `json:"first_name,omitempty"`
-	LastName  string `json:"last_name,omitempty"`
+	Username  string  `json:"username" validate:"required,min=3,max=100"`
+	Email     string  `json:"email" validate:"required,email"`
+	FirstName string  `json:"first_name,omitempty"`
+	LastName  string  `json:"last_name,omitempty"`
+	Phone     *string `json:"phone,omitempty" validate:"omitempty,e164"`
+}
+
+// EmailChangeRequest adalah permintaan ganti email yang menunggu konfirmasi lewat tautan yang
+// dikirim ke alamat baru. Email user baru berganti setelah dikonfirmasi sebelum ExpiresAt.
+type EmailChangeRequest struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	NewEmail    string     `json:"new_email"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	SentAt      time.Time  `json:"sent_at"` // Pengiriman tautan terakhir
+	CreatedAt   time.Time  `json:"created_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+}
+
+// RequestEmailChangeInput adalah body POST /user/email.
+type RequestEmailChangeInput struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+// Mode registrasi akun baru (pengaturan auth.registration_mode).
+const (
+	RegistrationOpen       = "open"        // POST /auth/register terbuka untuk publik
+	RegistrationInviteOnly = "invite_only" // Akun baru hanya lewat undangan admin
+)
+
+// Status undangan (dihitung dari accepted_at, revoked_at, dan expires_at).
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Invitation adalah undangan onboarding untuk satu alamat email dengan role yang sudah
+// ditentukan admin. Penerima menyelesaikan registrasi lewat tautan bertoken sebelum ExpiresAt.
+type Invitation struct {
+	ID             int        `json:"id"`
+	Email          string     `json:"email"`
+	RoleID         int        `json:"role_id"`
+	RoleName       string     `json:"role_name,omitempty"`
+	InvitedBy      *int       `json:"invited_by,omitempty"`
+	Status         string     `json:"status"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	SentAt         time.Time  `json:"sent_at"` // Pengiriman tautan terakhir
+	CreatedAt      time.Time  `json:"created_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	AcceptedUserID *int       `json:"accepted_user_id,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateInvitationInput adalah body POST /admin/invitations.
+type CreateInvitationInput struct {
+	Email  string `json:"email" validate:"required,email,max=255"`
+	RoleID int    `json:"role_id" validate:"required,gt=0"`
+}
+
+// InvitationTokenInput adalah token dari tautan undangan (POST /auth/invitations/preview).
+type InvitationTokenInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// AcceptInvitationInput adalah body POST /auth/invitations/accept. Email dan role diambil dari
+// undangan, bukan dari request.
+type AcceptInvitationInput struct {
+	Token     string  `json:"token" validate:"required"`
+	Username  string  `json:"username" validate:"required,min=3,max=100"`
+	Password  string  `json:"password" validate:"required,min=6"`
+	FirstName string  `json:"first_name,omitempty"`
+	LastName  string  `json:"last_name,omitempty"`
+	Phone     *string `json:"phone,omitempty" validate:"omitempty,e164"`
+}
+
+// AdminCreateUserInput adalah body POST /admin/users. Berbeda dengan registrasi publik, admin
+// boleh memberi role apa pun. Password boleh kosong jika send_invite: user mengatur password
+// sendiri lewat tautan di email.
+type AdminCreateUserInput struct {
+	Username              string  `json:"username" validate:"required,min=3,max=100"`
+	Password              string  `json:"password,omitempty" validate:"required_without=SendInvite,omitempty,min=6"`
+	Email                 string  `json:"email" validate:"required,email"`
+	FirstName             string  `json:"first_name,omitempty"`
+	LastName              string  `json:"last_name,omitempty"`
+	Phone                 *string `json:"phone,omitempty" validate:"omitempty,e164"`
+	RoleID                int     `json:"role_id" validate:"required,gt=0"`
+	SendInvite            bool    `json:"send_invite"`             // Kirim email berisi tautan untuk mengatur password
+	RequirePasswordChange bool    `json:"require_password_change"` // Password wajib diganti saat login pertama
+}
+
+// ConfirmEmailChangeInput adalah body POST /auth/email/confirm (token dari tautan email).
+type ConfirmEmailChangeInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// Tujuan kode OTP SMS (kolom phone_otps.purpose).
+const (
+	OTPPurposeVerifyPhone = "verify_phone" // Membuktikan nomor HP milik user
+	OTPPurposeLogin       = "login"        // Faktor kedua login (sms_two_factor)
+)
+
+// PhoneOTP adalah kode OTP SMS yang sudah dikirim; hanya hash kodenya yang disimpan.
+type PhoneOTP struct {
+	ID         int        `json:"-"`
+	UserID     int        `json:"-"`
+	Purpose    string     `json:"purpose"`
+	CodeHash   string     `json:"-"`
+	Attempts   int        `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedAt  time.Time  `json:"sent_at"`
+	ConsumedAt *time.Time `json:"-"`
+}
+
+// ConfirmPhoneInput adalah body POST /user/phone/verification/confirm.
+type ConfirmPhoneInput struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+
+// LoginOTPInput adalah body POST /auth/login/otp: challenge_token dari respons login dan kode SMS.
+type LoginOTPInput struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required,numeric,len=6"`
+}
+
+// UpdateSMSPreferencesInput adalah body PUT /user/sms-preferences (field kosong = tidak diubah).
+// Mengaktifkan salah satunya memerlukan nomor HP yang sudah diverifikasi.
+type UpdateSMSPreferencesInput struct {
+	TwoFactor *bool `json:"two_factor,omitempty"` // Login memerlukan kode OTP SMS
+	Reminders *bool `json:"reminders,omitempty"`  // Pengingat shift lewat SMS
+}
+
+// Kebijakan ganti username lewat profil sendiri (pengaturan profile.username_change_policy).
+// Admin tetap bisa mengganti username lewat endpoint admin.
+const (
+	UsernamePolicyAllow    = "allow"    // Username langsung berganti
+	UsernamePolicyDeny     = "deny"     // Username tidak boleh diganti user
+	UsernamePolicyApproval = "approval" // Username baru menunggu persetujuan admin
+)
+
+// Status permintaan ganti username.
+const (
+	UsernameChangePending  = "pending"
+	UsernameChangeApproved = "approved"
+	UsernameChangeRejected = "rejected"
+)
+
+// UsernameChangeRequest adalah permintaan ganti username yang menunggu persetujuan admin.
+type UsernameChangeRequest struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	Username    string     `json:"username,omitempty"` // Username saat ini (hanya di daftar admin)
+	NewUsername string     `json:"new_username"`
+	Status      string     `json:"status"`
+	ReviewedBy  *int       `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote  *string    `json:"review_note,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ReviewUsernameChangeInput adalah body opsional POST /admin/username-requests/:requestId/approve|reject.
+type ReviewUsernameChangeInput struct {
+	Note string `json:"note,omitempty" validate:"max=500"`
+}
+
+// PreviousUsername adalah username lama seorang user beserta waktu penggantiannya.
+type PreviousUsername struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// UsernameResolution adalah hasil pencarian user dari username saat ini atau username lama.
+type UsernameResolution struct {
+	User            *User      `json:"user"`
+	MatchedPrevious bool       `json:"matched_previous"`         // true jika cocok dengan username lama
+	PreviousUntil   *time.Time `json:"previous_until,omitempty"` // Kapan username lama itu diganti
+}
+
+// UserDataExport adalah arsip data pribadi user (GET /user/data-export).
+type UserDataExport struct {
+	ExportedAt  time.Time      `json:"exported_at"`
+	Profile     *User          `json:"profile"`
+	Schedules   []UserSchedule `json:"schedules"`
+	Attendances []Attendance   `json:"attendances"`
+}
+
+// BulkRoleAssignInput adalah input POST /admin/users/bulk/role.
+type BulkRoleAssignInput struct {
+	UserIDs []int `json:"user_ids" validate:"required,min=1,max=500,dive,gt=0"`
+	RoleID  int   `json:"role_id" validate:"required,gt=0"`
+}
+
+// Status hasil per item pada operasi bulk.
+const (
+	BulkStatusUpdated   = "updated"
+	BulkStatusUnchanged = "unchanged"
+	BulkStatusNotFound  = "not_found"
+	BulkStatusSkipped   = "skipped"
+)
+
+// BulkItemResult adalah hasil operasi bulk untuk satu ID.
+type BulkItemResult struct {
+	ID      int    `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// BulkDeleteSchedulesInput adalah input POST /admin/schedules/bulk-delete.
+// Gunakan salah satu mode: ScheduleIDs, ATAU filter StartDate+EndDate (opsional dibatasi UserIDs).
+type BulkDeleteSchedulesInput struct {
+	ScheduleIDs []int  `json:"schedule_ids,omitempty" validate:"omitempty,max=1000,dive,gt=0"`
+	UserIDs     []int  `json:"user_ids,omitempty" validate:"omitempty,max=1000,dive,gt=0"` // Kosong = semua user
+	StartDate   string `json:"start_date,omitempty"`                                       // Format YYYY-MM-DD
+	EndDate     string `json:"end_date,omitempty"`                                         // Format YYYY-MM-DD
+}
+
+// BulkDeleteSchedulesResult merangkum hasil bulk delete jadwal.
+// Jadwal yang sudah memiliki absensi pada tanggalnya tidak dihapus (kept).
+type BulkDeleteSchedulesResult struct {
+	DeletedCount int              `json:"deleted_count"`
+	KeptCount    int              `json:"kept_count"`
+	DeletedIDs   []int            `json:"deleted_ids"`
+	Kept         []BulkItemResult `json:"kept"`
+	NotFoundIDs  []int            `json:"not_found_ids,omitempty"`
 }
 
 type UpdatePasswordInput struct {
 	OldPassword string `json:"old_password" validate:"required,min=6"`
 	NewPassword string `json:"new_password" validate:"required,min=6"`
-}
\ No newline at end of file
+}
+
+// Setting adalah satu baris tabel settings (nilai mentah dalam bentuk teks).
+type Setting struct {
+	Key       string
+	Value     string
+	UpdatedBy *int
+	UpdatedAt time.Time
+}
+
+// SettingDetail adalah tampilan pengaturan sistem untuk API admin: nilai efektif
+// (sudah bertipe), default, dan metadata perubahan terakhir.
+type SettingDetail struct {
+	Key         string     `json:"key"`
+	Type        string     `json:"type"` // int | string | email | timezone | clock | weekdays | date_list | choice
+	Value       any        `json:"value"`
+	Default     any        `json:"default"`
+	IsDefault   bool       `json:"is_default"` // true jika belum pernah di-set (memakai default)
+	Description string     `json:"description"`
+	Options     []string   `json:"options,omitempty"` // Nilai yang diizinkan untuk tipe choice
+	UpdatedBy   *int       `json:"updated_by,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdateSettingsInput berisi key pengaturan dan nilai barunya, mis.
+// {"settings": {"attendance.grace_minutes": 10, "general.default_timezone": "Asia/Jakarta"}}.
+type UpdateSettingsInput struct {
+	Settings map[string]any `json:"settings" validate:"required,min=1"`
+}
+
+// Announcement adalah pengumuman admin untuk karyawan dengan jendela publikasi
+// [PublishAt, ExpiresAt) dan audience berdasarkan role.
+type Announcement struct {
+	ID              int        `json:"id"`
+	Title           string     `json:"title"`
+	Body            string     `json:"body"`
+	PublishAt       time.Time  `json:"publish_at"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"` // nil = tidak kedaluwarsa
+	AudienceRoleIDs []int      `json:"audience_role_ids"`    // Kosong = semua user
+	CreatedBy       *int       `json:"created_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// AnnouncementInput dipakai untuk membuat dan mengganti pengumuman.
+// Waktu dalam format RFC 3339; PublishAt kosong = terbit sekarang.
+type AnnouncementInput struct {
+	Title           string     `json:"title" validate:"required,min=3,max=200"`
+	Body            string     `json:"body" validate:"required,max=5000"`
+	PublishAt       *time.Time `json:"publish_at,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	AudienceRoleIDs []int      `json:"audience_role_ids,omitempty" validate:"omitempty,max=50,dive,gt=0"`
+}
+
+// Jenis entitas yang bisa dilampiri dokumen.
+const (
+	DocumentSubjectAttendance   = "attendance"
+	DocumentSubjectProfilePhoto = "profile_photo" // subject_id = user; foto acuan verifikasi wajah
+)
+
+// Status pemindaian malware dokumen.
+const (
+	DocumentScanClean   = "clean"
+	DocumentScanSkipped = "skipped" // Pemindai tidak dikonfigurasi
+)
+
+// Document adalah metadata dokumen pendukung (surat sakit, izin) yang diunggah user.
+// Isi file ada di storage dan diunduh lewat endpoint download.
+type Document struct {
+	ID          int       `json:"id"`
+	OwnerUserID int       `json:"owner_user_id"`
+	SubjectType string    `json:"subject_type"`
+	SubjectID   int       `json:"subject_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	SHA256      string    `json:"sha256"`
+	StorageKey  string    `json:"-"`
+	ScanStatus  string    `json:"scan_status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DocumentUploadInput meminta URL unggah langsung ke storage (backend S3) untuk file besar;
+// isi yang dijanjikan di sini ditandatangani sehingga storage menolak file yang berbeda.
+type DocumentUploadInput struct {
+	FileName    string `json:"file_name" validate:"required,max=255"`
+	ContentType string `json:"content_type" validate:"required,oneof=application/pdf image/jpeg image/png"`
+	SizeBytes   int64  `json:"size_bytes" validate:"required,gt=0"`
+	SHA256      string `json:"sha256" validate:"required,len=64,hexadecimal"` // Checksum isi file (hex)
+}
+
+// DocumentUpload adalah request yang harus dikirim klien langsung ke storage, plus token untuk
+// menyelesaikan unggahan (CompleteDocumentUploadInput) setelah file terkirim.
+type DocumentUpload struct {
+	UploadToken string            `json:"upload_token"`
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	Headers     map[string]string `json:"headers"` // Wajib dikirim apa adanya
+	ExpiresAt   time.Time         `json:"expires_at"`
+}
+
+// CompleteDocumentUploadInput menyelesaikan unggahan langsung: metadata dokumen dicatat setelah
+// file terverifikasi ada di storage.
+type CompleteDocumentUploadInput struct {
+	UploadToken string `json:"upload_token" validate:"required"`
+}
+
+// Status periode payroll.
+const (
+	PayrollPeriodOpen   = "open"
+	PayrollPeriodClosed = "closed"
+)
+
+// PayrollPeriodClosedCode adalah kode error (data.code) untuk perubahan absensi yang ditolak
+// karena tanggalnya berada di periode payroll yang sudah ditutup.
+const PayrollPeriodClosedCode = "PAYROLL_PERIOD_CLOSED"
+
+// PayrollPeriod adalah periode payroll [StartDate, EndDate] (inklusif, YYYY-MM-DD).
+// Selama status closed, absensi dengan tanggal check-in di dalam periode tidak bisa diubah.
+type PayrollPeriod struct {
+	ID           int        `json:"id"`
+	StartDate    string     `json:"start_date"`
+	EndDate      string     `json:"end_date"`
+	Status       string     `json:"status"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+	ClosedBy     *int       `json:"closed_by,omitempty"`
+	ReopenedAt   *time.Time `json:"reopened_at,omitempty"` // Reopen terakhir
+	ReopenedBy   *int       `json:"reopened_by,omitempty"`
+	ReopenReason *string    `json:"reopen_reason,omitempty"`
+	CreatedBy    *int       `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// PayrollPeriodInput dipakai untuk membuat periode payroll (tanggal YYYY-MM-DD).
+type PayrollPeriodInput struct {
+	StartDate string `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" validate:"required,datetime=2006-01-02"`
+}
+
+// ReopenPayrollPeriodInput adalah alasan membuka kembali periode payroll yang sudah ditutup.
+// Reopen adalah alur istimewa: admin wajib memasukkan ulang password-nya.
+type ReopenPayrollPeriodInput struct {
+	Reason   string `json:"reason" validate:"required,min=5,max=500"`
+	Password string `json:"password" validate:"required"`
+}
+
+// Project adalah project/cost center untuk menandai sesi absensi (time tracking tim billable).
+type Project struct {
+	ID         int       `json:"id"`
+	Code       string    `json:"code"`
+	Name       string    `json:"name"`
+	CostCenter *string   `json:"cost_center,omitempty"`
+	IsActive   bool      `json:"is_active"` // Project nonaktif tidak bisa dipilih saat check-in
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ProjectInput dipakai untuk membuat dan mengganti project. IsActive kosong = aktif.
+type ProjectInput struct {
+	Code       string  `json:"code" validate:"required,min=2,max=32"`
+	Name       string  `json:"name" validate:"required,min=3,max=100"`
+	CostCenter *string `json:"cost_center,omitempty" validate:"omitempty,max=64"`
+	IsActive   *bool   `json:"is_active,omitempty"`
+}
+
+// ProjectHours adalah rekap jam kerja sesi absensi yang sudah check-out per project.
+// Baris dengan ProjectID nil merangkum sesi tanpa tag project.
+type ProjectHours struct {
+	ProjectID  *int    `json:"project_id"`
+	Code       *string `json:"code"`
+	Name       *string `json:"name"`
+	CostCenter *string `json:"cost_center,omitempty"`
+	Sessions   int     `json:"sessions"`
+	Users      int     `json:"users"`
+	TotalHours float64 `json:"total_hours"`
+}
+
+// TagHours adalah rekap sesi absensi yang sudah check-out per tag (GET /admin/attendance/report/tags).
+// Satu sesi dengan beberapa tag dihitung di setiap tagnya.
+type TagHours struct {
+	Tag        string  `json:"tag"`
+	Sessions   int     `json:"sessions"`
+	Users      int     `json:"users"`
+	TotalHours float64 `json:"total_hours"`
+}
+
+// ModeHours adalah rekap sesi absensi yang sudah check-out per mode kerja
+// (GET /admin/attendance/report/modes).
+type ModeHours struct {
+	Mode       string  `json:"mode"`
+	Sessions   int     `json:"sessions"`
+	Users      int     `json:"users"`
+	TotalHours float64 `json:"total_hours"`
+}
+
+// AttendancePing adalah satu titik lokasi berkala selama sesi absensi mode field
+// (tabel attendance_pings, koordinat disimpan dalam mikroderajat).
+type AttendancePing struct {
+	AttendanceID int       `json:"attendance_id"`
+	RecordedAt   time.Time `json:"recorded_at"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	AccuracyM    *int      `json:"accuracy_m,omitempty"`
+}
+
+// AttendancePingInput adalah body POST /user/attendance/:attendanceId/ping.
+type AttendancePingInput struct {
+	Latitude   *float64   `json:"latitude" validate:"required,gte=-90,lte=90"`
+	Longitude  *float64   `json:"longitude" validate:"required,gte=-180,lte=180"`
+	AccuracyM  *int       `json:"accuracy_m,omitempty" validate:"omitempty,gte=0,lte=10000"`
+	RecordedAt *time.Time `json:"recorded_at,omitempty"` // Waktu GPS di perangkat (ping tertunda); default waktu server
+}
+
+// RoutePoint adalah satu titik pada ringkasan rute.
+type RoutePoint struct {
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// AttendanceRoute adalah ringkasan rute satu sesi field: jarak dihitung dari semua titik,
+// sedangkan Points sudah dijarangkan. Untuk atasan, koordinat dibulatkan ke Precision desimal.
+type AttendanceRoute struct {
+	AttendanceID int          `json:"attendance_id"`
+	UserID       int          `json:"user_id"`
+	CheckInAt    time.Time    `json:"check_in_at"`
+	CheckOutAt   *time.Time   `json:"check_out_at,omitempty"`
+	PingCount    int          `json:"ping_count"`
+	DistanceKm   float64      `json:"distance_km"`
+	FirstPingAt  *time.Time   `json:"first_ping_at,omitempty"`
+	LastPingAt   *time.Time   `json:"last_ping_at,omitempty"`
+	Precision    int          `json:"precision"` // Jumlah desimal koordinat pada Points
+	Points       []RoutePoint `json:"points"`
+}
+
+// AttendanceSegment adalah rentang waktu dalam satu sesi absensi yang dikerjakan untuk satu project.
+// Check-in membuka segmen pertama; switch project menutupnya dan membuka segmen baru.
+type AttendanceSegment struct {
+	ID           int64      `json:"id"`
+	AttendanceID int        `json:"attendance_id"`
+	ProjectID    *int       `json:"project_id"` // nil = tanpa project
+	StartedAt    time.Time  `json:"started_at"`
+	EndedAt      *time.Time `json:"ended_at,omitempty"` // nil = segmen sedang berjalan
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Status hasil verifikasi wajah saat check-in.
+const (
+	FaceMatchMatched       = "matched"        // Skor >= ambang
+	FaceMatchLowConfidence = "low_confidence" // Skor di bawah ambang
+	FaceMatchNoReference   = "no_reference"   // User belum punya foto profil; tidak ditandai
+	FaceMatchMissingSelfie = "missing_selfie" // Check-in tanpa selfie
+	FaceMatchError         = "error"          // Layanan verifikasi gagal/tidak tersedia
+)
+
+// Status tinjauan admin atas punch yang ditandai verifikasi wajah.
+const (
+	FaceReviewPending  = "pending"
+	FaceReviewApproved = "approved"
+	FaceReviewRejected = "rejected"
+)
+
+// FaceCheck adalah hasil verifikasi wajah satu check-in.
+type FaceCheck struct {
+	AttendanceID int        `json:"attendance_id"`
+	UserID       int        `json:"user_id"`
+	CheckInAt    time.Time  `json:"check_in_at"`
+	Provider     string     `json:"provider"`
+	Status       string     `json:"status"`                  // Lihat FaceMatch*
+	Score        *float64   `json:"score,omitempty"`         // 0..1; nil jika layanan tidak dipanggil/gagal
+	ReviewStatus *string    `json:"review_status,omitempty"` // Lihat FaceReview*; nil = tidak perlu ditinjau
+	ReviewedBy   *int       `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	User         *User      `json:"user,omitempty"`
+}
+
+// FaceReviewInput dipakai admin untuk menyelesaikan tinjauan punch yang ditandai.
+type FaceReviewInput struct {
+	Status string `json:"status" validate:"required,oneof=approved rejected"`
+}
+
+// Buffer asal check-in tertunda (QueuedCheckInFailure.Source).
+const (
+	QueuedCheckInSourceDegraded    = "degraded"    // Database tidak tersedia (internal/degraded)
+	QueuedCheckInSourceMaintenance = "maintenance" // Mode maintenance dengan maintenance.checkins = buffer
+)
+
+// QueuedCheckInFailure adalah check-in yang sudah dijawab 202 saat mode degraded/maintenance tetapi
+// gagal dicatat saat dikirim ulang (mis. jadwal dihapus, user sudah check-in lagi). Disimpan
+// sebagai dead letter sampai admin mengoreksi absensi dan menandainya selesai.
+type QueuedCheckInFailure struct {
+	ID             int        `json:"id"`
+	UserID         int        `json:"user_id"`
+	CheckInAt      time.Time  `json:"check_in_at"` // Waktu check-in asli
+	Notes          *string    `json:"notes,omitempty"`
+	Tags           []string   `json:"tags"`
+	ProjectID      *int       `json:"project_id,omitempty"`
+	Mode           string     `json:"mode,omitempty"`
+	Source         string     `json:"source"` // Lihat QueuedCheckInSource*
+	Error          string     `json:"error"`  // Alasan gagal dicatat
+	FailedAt       time.Time  `json:"failed_at"`
+	ResolvedBy     *int       `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	ResolutionNote *string    `json:"resolution_note,omitempty"`
+	User           *User      `json:"user,omitempty"`
+}
+
+// ResolveQueuedCheckInFailureInput dipakai admin untuk menutup dead letter check-in setelah
+// absensinya dikoreksi (atau diputuskan tidak perlu dicatat).
+type ResolveQueuedCheckInFailureInput struct {
+	Note string `json:"note" validate:"required,max=500"`
+}
+
+// Status foto check-in (lihat internal/photos).
+const (
+	AttendancePhotoPending   = "pending"   // Upload mentah menunggu diproses
+	AttendancePhotoProcessed = "processed" // Foto asli tanpa EXIF & thumbnail tersedia
+	AttendancePhotoFailed    = "failed"    // Bukan gambar JPEG/PNG yang valid; upload dibuang
+	AttendancePhotoPurged    = "purged"    // Dihapus oleh kebijakan retensi
+)
+
+// Varian file foto check-in yang bisa dibuka lewat URL bertanda tangan.
+const (
+	AttendancePhotoOriginal  = "original"
+	AttendancePhotoThumbnail = "thumbnail"
+)
+
+// AttendancePhoto adalah foto (selfie) satu check-in. Key storage tidak pernah dikirim ke klien;
+// file dibuka lewat URL bertanda tangan berumur pendek (AttendancePhotoLinks).
+type AttendancePhoto struct {
+	ID           int        `json:"id"`
+	AttendanceID int        `json:"attendance_id"`
+	UserID       int        `json:"user_id"`
+	Status       string     `json:"status"` // Lihat AttendancePhoto*
+	IncomingKey  *string    `json:"-"`
+	OriginalKey  *string    `json:"-"`
+	ThumbnailKey *string    `json:"-"`
+	Width        *int       `json:"width,omitempty"`
+	Height       *int       `json:"height,omitempty"`
+	Error        *string    `json:"error,omitempty"`
+	CapturedAt   time.Time  `json:"captured_at"`
+	ProcessedAt  *time.Time `json:"processed_at,omitempty"`
+	PurgedAt     *time.Time `json:"purged_at,omitempty"`
+}
+
+// AttendancePhotoLinks adalah foto check-in beserta URL bertanda tangan untuk membukanya (hanya
+// untuk foto berstatus processed). URL relatif terhadap host API.
+type AttendancePhotoLinks struct {
+	Photo        *AttendancePhoto `json:"photo"`
+	ThumbnailURL string           `json:"thumbnail_url,omitempty"`
+	OriginalURL  string           `json:"original_url,omitempty"`
+	ExpiresAt    *time.Time       `json:"expires_at,omitempty"`
+}
+
+// SwitchProjectInput dipakai untuk berpindah project di tengah sesi tanpa check-out.
+type SwitchProjectInput struct {
+	ProjectID int `json:"project_id" validate:"required,gt=0"`
+}
+
+// Pengecualian yang ditandai saat sign-off harian oleh atasan.
+const (
+	SignOffExceptionNoShow      = "no_show"     // Terjadwal tetapi tidak check-in
+	SignOffExceptionLate        = "late"        // Check-in melewati jam mulai shift + toleransi
+	SignOffExceptionUnscheduled = "unscheduled" // Check-in tanpa jadwal pada tanggal tersebut
+	SignOffExceptionCorrected   = "corrected"   // Absensi pernah dikoreksi admin
+)
+
+// AttendanceSignedOffCode adalah kode error (data.code) untuk perubahan absensi yang ditolak
+// karena tanggalnya sudah di-sign-off atasan.
+const AttendanceSignedOffCode = "ATTENDANCE_SIGNED_OFF"
+
+// AttendanceSignOff adalah verifikasi absensi satu karyawan pada satu tanggal kerja oleh atasannya.
+type AttendanceSignOff struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	WorkDate   string    `json:"work_date"` // YYYY-MM-DD
+	SignedBy   *int      `json:"signed_by"`
+	SignedAt   time.Time `json:"signed_at"`
+	Exceptions []string  `json:"exceptions"`
+	Note       *string   `json:"note,omitempty"`
+}
+
+// SignOffInput adalah input POST /manager/attendance/sign-off. UserIDs kosong = seluruh tim
+// (bawahan langsung & tidak langsung).
+type SignOffInput struct {
+	Date    string  `json:"date" validate:"required,datetime=2006-01-02"`
+	UserIDs []int   `json:"user_ids,omitempty" validate:"omitempty,max=500,dive,gt=0"`
+	Note    *string `json:"note,omitempty" validate:"omitempty,max=500"`
+	// Atasan yang diwakili lewat delegasi aktif (lihat ApprovalDelegation); kosong = tim sendiri.
+	OnBehalfOf *int `json:"on_behalf_of,omitempty" validate:"omitempty,gt=0"`
+}
+
+// SignOffResult adalah hasil sign-off untuk satu karyawan. Status memakai BulkStatus*:
+// updated (di-sign-off), unchanged (sudah di-sign-off), not_found (bukan anggota tim),
+// skipped (sesi absensi masih terbuka).
+type SignOffResult struct {
+	UserID     int      `json:"user_id"`
+	Status     string   `json:"status"`
+	Exceptions []string `json:"exceptions,omitempty"`
+	Message    string   `json:"message,omitempty"`
+}
+
+// UnsignedDay adalah tanggal kerja (ada jadwal atau absensi) seorang karyawan yang belum di-sign-off.
+type UnsignedDay struct {
+	UserID    int    `json:"user_id"`
+	Username  string `json:"username"`
+	ManagerID *int   `json:"manager_id"`
+	WorkDate  string `json:"work_date"` // YYYY-MM-DD
+}
+
+// Status dispute absensi.
+const (
+	DisputeOpen     = "open"
+	DisputeResolved = "resolved"
+	DisputeRejected = "rejected"
+)
+
+// AttendanceDispute adalah sanggahan karyawan atas salah satu record absensinya.
+type AttendanceDispute struct {
+	ID             int        `json:"id"`
+	AttendanceID   int        `json:"attendance_id"`
+	UserID         int        `json:"user_id"`
+	Comment        string     `json:"comment"`
+	Status         string     `json:"status"`
+	ResolutionNote *string    `json:"resolution_note,omitempty"`
+	ResolvedBy     *int       `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// DisputeInput adalah input POST /user/attendance/:attendanceId/dispute.
+type DisputeInput struct {
+	Comment string `json:"comment" validate:"required,min=5,max=1000"`
+}
+
+// ResolveDisputeInput menutup dispute dengan status resolved atau rejected beserta catatannya.
+type ResolveDisputeInput struct {
+	Status string `json:"status" validate:"required,oneof=resolved rejected"`
+	Note   string `json:"note" validate:"required,min=5,max=500"`
+}
+
+// Tipe item di approval inbox admin (GET /admin/approvals).
+const (
+	ApprovalTypeDispute = "attendance_dispute" // Dispute karyawan = permintaan koreksi absensi
+)
+
+// Keputusan bulk approval inbox.
+const (
+	ApprovalApprove = "approve"
+	ApprovalReject  = "reject"
+)
+
+// ApprovalItem adalah satu item menunggu keputusan di approval inbox, apa pun tipenya.
+type ApprovalItem struct {
+	Type        string    `json:"type"`
+	ID          int       `json:"id"` // ID pada tabel asal tipe tersebut
+	UserID      int       `json:"user_id"`
+	Summary     string    `json:"summary"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	Details     any       `json:"details"` // Objek asal, mis. AttendanceDispute
+	// Riwayat eskalasi karena melewati SLA, terlama dulu.
+	Escalations []ApprovalEscalation `json:"escalations"`
+}
+
+// Aksi langkah eskalasi approval.
+const (
+	EscalationEscalated = "escalated" // Dilimpahkan ke atasan level berikutnya atau admin
+	EscalationReminded  = "reminded"  // Sudah di admin; pengingat ulang tiap SLA
+)
+
+// ApprovalEscalation adalah satu langkah eskalasi item approval yang menunggu melewati SLA.
+type ApprovalEscalation struct {
+	ID          int       `json:"id"`
+	ItemType    string    `json:"item_type"`
+	ItemID      int       `json:"item_id"`
+	Level       int       `json:"level"` // 1 = eskalasi pertama
+	Action      string    `json:"action"`
+	EscalatedTo *int      `json:"escalated_to,omitempty"` // nil = admin/HR
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ApprovalRef menunjuk satu item approval inbox.
+type ApprovalRef struct {
+	Type string `json:"type" validate:"required"`
+	ID   int    `json:"id" validate:"required,gt=0"`
+}
+
+// BulkApprovalInput adalah input POST /admin/approvals/bulk.
+type BulkApprovalInput struct {
+	Action string        `json:"action" validate:"required,oneof=approve reject"`
+	Note   string        `json:"note" validate:"required,min=5,max=500"`
+	Items  []ApprovalRef `json:"items" validate:"required,min=1,max=200,dive"`
+}
+
+// ApprovalItemResult adalah hasil bulk approval untuk satu item.
+type ApprovalItemResult struct {
+	Type string `json:"type"`
+	BulkItemResult
+}
+
+// ApprovalDelegation adalah pelimpahan wewenang approval atasan (DelegatorID) ke user lain
+// (DelegateID) untuk rentang tanggal StartDate..EndDate (inklusif), mis. selama cuti.
+type ApprovalDelegation struct {
+	ID          int        `json:"id"`
+	DelegatorID int        `json:"delegator_id"`
+	DelegateID  int        `json:"delegate_id"`
+	StartDate   string     `json:"start_date"`
+	EndDate     string     `json:"end_date"`
+	Reason      *string    `json:"reason,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// DelegationInput adalah input POST /user/delegations.
+type DelegationInput struct {
+	DelegateID int     `json:"delegate_id" validate:"required,gt=0"`
+	StartDate  string  `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate    string  `json:"end_date" validate:"required,datetime=2006-01-02"`
+	Reason     *string `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+// Platform token perangkat push notification.
+const (
+	DevicePlatformFCM  = "fcm"  // Firebase Cloud Messaging (Android, web, atau iOS lewat FCM)
+	DevicePlatformAPNs = "apns" // Apple Push Notification service
+)
+
+// DeviceToken adalah token push notification perangkat milik user.
+type DeviceToken struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Platform  string    `json:"platform"`
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RegisterDeviceInput adalah input POST /user/devices.
+type RegisterDeviceInput struct {
+	Platform string `json:"platform" validate:"required,oneof=fcm apns"`
+	Token    string `json:"token" validate:"required,min=8,max=4096"`
+}
+
+// KioskDevice adalah perangkat kiosk/terminal absensi yang didaftarkan user. Token kiosk
+// (scope attendance:punch) hanya berlaku dari perangkat dengan fingerprint yang sama dan selama
+// perangkat belum dicabut.
+type KioskDevice struct {
+	ID              int        `json:"id"`
+	UserID          int        `json:"user_id"`
+	Name            string     `json:"name"`
+	FingerprintHash string     `json:"-"` // SHA-256 (hex) fingerprint perangkat
+	CreatedAt       time.Time  `json:"created_at"`
+	LastSeenAt      *time.Time `json:"last_seen_at,omitempty"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy       *int       `json:"revoked_by,omitempty"`
+}
+
+// EnrollKioskInput adalah input POST /user/kiosks. Fingerprint adalah pengenal stabil yang
+// dibuat aplikasi kiosk dan disimpan di perangkat (mis. kunci acak di secure storage); nilai
+// yang sama dikirim di header X-Device-Fingerprint pada setiap request.
+type EnrollKioskInput struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Fingerprint string `json:"fingerprint" validate:"required,min=16,max=512"`
+}
+
+// KioskTokenInput adalah input POST /user/kiosks/{id}/token.
+type KioskTokenInput struct {
+	Fingerprint string `json:"fingerprint" validate:"required,min=16,max=512"`
+}
+
+// KioskToken adalah token kiosk yang diterbitkan. Selama dipakai, token baru dikirim di header
+// X-Rotated-Token setelah setengah masa berlakunya lewat.
+type KioskToken struct {
+	Device    *KioskDevice `json:"device"`
+	Token     string       `json:"token"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// ShiftReminder adalah jadwal shift yang akan dimulai dan belum dikirimi pengingat (push/SMS).
+type ShiftReminder struct {
+	ScheduleID int    `json:"schedule_id"`
+	UserID     int    `json:"user_id"`
+	Date       string `json:"date"`       // YYYY-MM-DD
+	ShiftName  string `json:"shift_name"` // Nama shift
+	StartTime  string `json:"start_time"` // HH:MM:SS
+	SMS        bool   `json:"sms"`        // User memilih pengingat SMS dan nomor HP-nya terverifikasi
+}
+
+// Tipe notifikasi (Notification.Type, juga Data["type"] pada push notification).
+const (
+	NotificationScheduleChanged   = "schedule_changed"   // Jadwal dibuat/diubah/dihapus admin
+	NotificationShiftReminder     = "shift_reminder"     // Pengingat check-in sebelum shift mulai
+	NotificationDisputeOpened     = "dispute_opened"     // Ke atasan: bawahan menyanggah record absensi
+	NotificationDisputeResolved   = "dispute_resolved"   // Ke karyawan: keputusan atas dispute
+	NotificationApprovalEscalated = "approval_escalated" // Ke atasan level berikutnya: item melewati SLA
+	NotificationSessionsRevoked   = "sessions_revoked"   // Ke user: admin mengeluarkan semua sesinya
+	NotificationUsernameReviewed  = "username_reviewed"  // Ke user: permintaan ganti username disetujui/ditolak
+	NotificationCheckInFailed     = "check_in_failed"    // Ke user: check-in yang ditampung gagal dicatat
+)
+
+// Notification adalah item inbox notifikasi in-app milik user.
+type Notification struct {
+	ID        int               `json:"id"`
+	UserID    int               `json:"user_id"`
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data"`              // Referensi terkait, mis. schedule_id, dispute_id
+	ReadAt    *time.Time        `json:"read_at,omitempty"` // Kosong = belum dibaca
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// OutboxMessage adalah efek samping satu domain event untuk satu tujuan (destination),
+// menunggu dikirim oleh dispatcher outbox.
+type OutboxMessage struct {
+	ID          int64           `json:"id"`
+	Destination string          `json:"destination"` // Mis. notifications, webhook, stream
+	EventName   string          `json:"event_name"`
+	UserID      *int            `json:"user_id,omitempty"`
+	Payload     json.RawMessage `json:"payload"` // events.Event dalam JSON
+	Attempts    int             `json:"attempts"`
+	LastError   *string         `json:"last_error,omitempty"`
+	AvailableAt time.Time       `json:"available_at"` // Percobaan berikutnya
+	CreatedAt   time.Time       `json:"created_at"`
+	DeadAt      *time.Time      `json:"dead_at,omitempty"` // Terisi = dead letter
+}
+
+// HourlyRateInput mengatur tarif per jam user atau role (PUT .../hourly-rate); null = hapus tarif.
+type HourlyRateInput struct {
+	HourlyRate *float64 `json:"hourly_rate" validate:"omitempty,gte=0,lte=9999999999"`
+}
+
+// Pengelompokan laporan biaya tenaga kerja (query group_by).
+const (
+	LaborGroupDay     = "day"
+	LaborGroupRole    = "role"
+	LaborGroupManager = "manager" // Per tim: bawahan langsung satu atasan
+)
+
+// LaborCostRow adalah jam & biaya terjadwal vs aktual satu kelompok (tanggal, role, atau tim).
+// Biaya memakai tarif user, atau tarif role jika user tidak punya; jam user tanpa tarif
+// tidak masuk biaya dan dilaporkan di kolom unrated.
+type LaborCostRow struct {
+	Key                   *string `json:"key"`          // Tanggal (YYYY-MM-DD), nama role, atau username atasan; nil = tanpa atasan / total
+	ID                    *int    `json:"id,omitempty"` // role_id atau manager_id
+	Users                 int     `json:"users"`
+	ScheduledHours        float64 `json:"scheduled_hours"` // Dari durasi shift jadwal (shift malam dihitung lintas hari)
+	ActualHours           float64 `json:"actual_hours"`    // Dari sesi absensi yang sudah check-out
+	ScheduledCost         float64 `json:"scheduled_cost"`
+	ActualCost            float64 `json:"actual_cost"`
+	CostVariance          float64 `json:"cost_variance"` // actual_cost - scheduled_cost
+	UnratedScheduledHours float64 `json:"unrated_scheduled_hours"`
+	UnratedActualHours    float64 `json:"unrated_actual_hours"`
+}
+
+// LaborBudget membandingkan biaya periode dengan anggaran yang diberikan (query budget).
+type LaborBudget struct {
+	Amount             float64 `json:"amount"`
+	ScheduledRemaining float64 `json:"scheduled_remaining"` // amount - scheduled_cost
+	ActualRemaining    float64 `json:"actual_remaining"`    // amount - actual_cost
+	OverBudget         bool    `json:"over_budget"`         // Salah satu biaya melebihi anggaran
+}
+
+// LaborCostReport adalah estimasi biaya tenaga kerja terjadwal vs aktual dalam rentang tanggal.
+type LaborCostReport struct {
+	StartDate string         `json:"start_date"`
+	EndDate   string         `json:"end_date"`
+	GroupBy   string         `json:"group_by"`
+	Rows      []LaborCostRow `json:"rows"`
+	Totals    LaborCostRow   `json:"totals"`
+	Budget    *LaborBudget   `json:"budget,omitempty"`
+}
+
+// Granularitas heatmap okupansi (query granularity).
+const (
+	OccupancyGranularityHour = "hour"
+	OccupancyGranularityDay  = "day"
+)
+
+// OccupancyBucket adalah jumlah user berbeda yang sedang check-in pada satu slot waktu di satu
+// lokasi. Lokasi adalah mode kerja sesi (AttendanceMode*): onsite berarti kantor.
+type OccupancyBucket struct {
+	Start     time.Time `json:"start"`
+	Location  string    `json:"location"`
+	Headcount int       `json:"headcount"`
+}
+
+// OccupancyReport adalah heatmap okupansi (GET /admin/analytics/occupancy). Buckets berisi
+// setiap slot × lokasi dalam rentang, termasuk yang kosong; Peak per lokasi adalah slot
+// dengan headcount tertinggi (slot paling awal jika sama).
+type OccupancyReport struct {
+	StartDate   string                     `json:"start_date"`
+	EndDate     string                     `json:"end_date"`
+	Granularity string                     `json:"granularity"`
+	Timezone    string                     `json:"timezone"`
+	Buckets     []OccupancyBucket          `json:"buckets"`
+	Peak        map[string]OccupancyBucket `json:"peak"`
+}
+
+// Status run terakhir job terjadwal.
+const (
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// Pemicu run job terjadwal.
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual" // Dipicu admin lewat POST /admin/jobs/:name/run
+)
+
+// JobDefinition adalah job latar belakang yang didaftarkan ke scheduler.
+type JobDefinition struct {
+	Name     string
+	Interval time.Duration
+}
+
+// ScheduledJob adalah definisi job beserta status run terakhirnya, dibagi semua instance.
+type ScheduledJob struct {
+	Name            string     `json:"name"`
+	IntervalSeconds int        `json:"interval_seconds"`
+	NextRunAt       time.Time  `json:"next_run_at"`
+	LastStartedAt   *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt  *time.Time `json:"last_finished_at,omitempty"`
+	LastStatus      *string    `json:"last_status,omitempty"`  // Lihat JobStatus*
+	LastTrigger     *string    `json:"last_trigger,omitempty"` // Lihat JobTrigger*
+	LastResult      *int       `json:"last_result,omitempty"`  // Jumlah item yang diproses run terakhir
+	LastError       *string    `json:"last_error,omitempty"`
+	LastDurationMs  *int64     `json:"last_duration_ms,omitempty"`
+	RunCount        int        `json:"run_count"`
+	FailureCount    int        `json:"failure_count"`
+	Registered      bool       `json:"registered"` // Aktif (terdaftar) di instance yang menjawab request
+}
+
+// Jenis hari pada kalender kerja organisasi.
+const (
+	CalendarDayWorking = "working"  // Hari kerja penuh
+	CalendarDayHalf    = "half_day" // Hari kerja setengah hari
+	CalendarDayOff     = "off"      // Bukan hari kerja (mis. akhir pekan)
+	CalendarDayHoliday = "holiday"  // Tanggal libur
+)
+
+// CalendarDay adalah satu tanggal pada kalender kerja.
+type CalendarDay struct {
+	Date         string  `json:"date"`    // Format YYYY-MM-DD
+	Weekday      string  `json:"weekday"` // Mis. Monday
+	Type         string  `json:"type"`    // Lihat CalendarDay*
+	WorkFraction float64 `json:"work_fraction"`
+}
+
+// WorkingCalendarView adalah kalender kerja pada rentang tanggal beserta jumlah hari kerjanya.
+type WorkingCalendarView struct {
+	StartDate   string        `json:"start_date"`
+	EndDate     string        `json:"end_date"`
+	WorkingDays []string      `json:"working_days"` // Hari kerja dalam seminggu (sun..sat)
+	HalfDays    []string      `json:"half_days"`
+	TotalDays   float64       `json:"total_working_days"` // Jumlah work_fraction dalam rentang
+	Days        []CalendarDay `json:"days"`
+}
+
+// Hasil akses tautan verifikasi kepegawaian (kolom employment_verification_accesses.outcome).
+const (
+	VerificationAccessGranted = "granted"
+	VerificationAccessExpired = "expired"
+	VerificationAccessRevoked = "revoked"
+)
+
+// EmploymentVerificationLink adalah tautan publik bertanda tangan yang dibuat admin agar pihak
+// ketiga (bank, pemilik sewa) dapat mengonfirmasi status kepegawaian seorang karyawan.
+type EmploymentVerificationLink struct {
+	ID                int        `json:"id"`
+	UserID            int        `json:"user_id"`
+	Recipient         string     `json:"recipient"`
+	IncludeAttendance bool       `json:"include_attendance"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	CreatedBy         *int       `json:"created_by,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+	AccessCount       int        `json:"access_count"`
+	LastAccessedAt    *time.Time `json:"last_accessed_at,omitempty"`
+	Token             string     `json:"token,omitempty"` // Hanya dikembalikan saat tautan dibuat
+	Path              string     `json:"path,omitempty"`  // Path publik GET /api/v1/verify/{token}, hanya saat dibuat
+}
+
+// EmploymentVerificationLinkInput adalah input POST /admin/users/{userId}/verification-link.
+type EmploymentVerificationLinkInput struct {
+	Recipient         string `json:"recipient" validate:"required,max=200"`
+	ExpiresInHours    int    `json:"expires_in_hours,omitempty" validate:"omitempty,min=1,max=720"` // Default 72
+	IncludeAttendance bool   `json:"include_attendance"`                                            // Sertakan jumlah hari hadir 90 hari terakhir
+}
+
+// EmploymentVerificationAccess adalah satu akses (audit) ke tautan verifikasi kepegawaian.
+type EmploymentVerificationAccess struct {
+	ID         int64     `json:"id"`
+	LinkID     int       `json:"link_id"`
+	AccessedAt time.Time `json:"accessed_at"`
+	IP         string    `json:"ip"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	Outcome    string    `json:"outcome"` // Lihat VerificationAccess*
+}
+
+// EmploymentVerification adalah data terbatas yang ditampilkan ke pihak ketiga lewat tautan
+// verifikasi: tanpa kontak, identitas, maupun detail jam absensi.
+type EmploymentVerification struct {
+	EmployeeName     string                            `json:"employee_name"`
+	Employed         bool                              `json:"employed"` // Aktif dan belum terminated
+	EmploymentStatus string                            `json:"employment_status"`
+	Position         string                            `json:"position"` // Nama role
+	HireDate         *string                           `json:"hire_date,omitempty"`
+	Recipient        string                            `json:"recipient"`
+	VerifiedAt       time.Time                         `json:"verified_at"`
+	ValidUntil       time.Time                         `json:"valid_until"` // Masa berlaku tautan
+	Attendance       *EmploymentVerificationAttendance `json:"attendance,omitempty"`
+}
+
+// EmploymentVerificationAttendance adalah ringkasan kehadiran pada verifikasi kepegawaian.
+type EmploymentVerificationAttendance struct {
+	PeriodStart string `json:"period_start"` // Format YYYY-MM-DD
+	PeriodEnd   string `json:"period_end"`
+	DaysPresent int    `json:"days_present"` // Tanggal dengan minimal satu check-in
+}
+
+// Permission route di luar role tertentu (role:<A|B> untuk route yang dibatasi role).
+const (
+	PermissionPublic        = "public"        // Tanpa login
+	PermissionAuthenticated = "authenticated" // Login, semua role; data dibatasi handler
+)
+
+// RoutePermission adalah permission yang diwajibkan satu route API (GET /admin/permissions/routes).
+type RoutePermission struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Permission string   `json:"permission"`       // public, authenticated, atau role:<A|B>
+	Roles      []string `json:"roles,omitempty"`  // Role yang diizinkan (termasuk role yang mewarisinya)
+	Scopes     []string `json:"scopes,omitempty"` // Scope token terbatas yang diterima (token sesi penuh selalu diterima)
+}
+
+// Scope token terbatas (POST /user/tokens). Route GET menerima ScopeRead; route lain hanya
+// menerima scope yang dicantumkan eksplisit pada grupnya.
+//
+// ScopeRead menjangkau endpoint GET berbalasan JSON yang diizinkan role user (daftar, detail,
+// laporan & analitik). Token baca tidak menjangkau:
+//   - unduhan file & ekspor massal: /admin/documents/:id/download, /admin/users/export,
+//     /admin/reports/exports/:id/download, /admin/maintenance/backups/:id/download,
+//     /user/documents/:id/download, /user/data-export
+//   - rekaman debug: /admin/debug/captures dan /admin/debug/captures/:id
+//   - diagnostik: /admin/system/diagnostics
+//
+// Daftar lengkap scope per route ada di GET /admin/permissions/routes.
+const (
+	ScopeAttendancePunch = "attendance:punch" // Check-in, check-out & pindah project (kiosk)
+	ScopeRead            = "read"             // Endpoint GET JSON yang diizinkan role user (lihat di atas)
+)
+
+// Batas masa berlaku token terbatas.
+const (
+	ScopedTokenDefaultTTLHours = 720  // 30 hari
+	ScopedTokenMaxTTLHours     = 2160 // 90 hari
+)
+
+// ScopedTokenInput adalah permintaan token terbatas untuk klien/integrasi milik user yang login.
+type ScopedTokenInput struct {
+	Scopes   []string `json:"scopes" validate:"required,min=1,unique,dive,oneof=attendance:punch read"`
+	Label    string   `json:"label" validate:"omitempty,max=100"`            // Penanda klien di audit log (mis. "Kiosk Lobby")
+	TTLHours int      `json:"ttl_hours" validate:"omitempty,min=1,max=2160"` // Default 720 (30 hari)
+}
+
+// ScopedToken adalah token terbatas yang diterbitkan. Token tidak disimpan server; token dicabut
+// bersama semua sesi user (token_version).
+type ScopedToken struct {
+	Token     string    `json:"token"`
+	Scopes    []string  `json:"scopes"`
+	Label     string    `json:"label,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Jenis laporan yang bisa diekspor di background (POST /admin/reports/exports).
+const (
+	ReportTypeUsers = "users" // Daftar user, sama dengan GET /admin/users/export
+)
+
+// Status ekspor laporan.
+const (
+	ReportExportPending   = "pending"   // Menunggu dibuat job report_exports
+	ReportExportCompleted = "completed" // File tersedia sampai expires_at
+	ReportExportFailed    = "failed"    // Gagal dibuat; lihat error
+	ReportExportPurged    = "purged"    // File dihapus setelah masa retensi
+)
+
+// ReportExportMaxRetentionHours membatasi retensi file ekspor yang bisa diminta (30 hari).
+const ReportExportMaxRetentionHours = 720
+
+// ReportExportInput adalah permintaan ekspor laporan di background. Filter dipakai untuk
+// report_type users.
+type ReportExportInput struct {
+	ReportType     string           `json:"report_type" validate:"required,oneof=users"`
+	Format         string           `json:"format" validate:"required,oneof=csv xlsx"`
+	Filter         UserExportFilter `json:"filter"`
+	RetentionHours int              `json:"retention_hours" validate:"omitempty,min=1,max=720"` // Default REPORT_EXPORT_RETENTION
+}
+
+// ReportExport adalah satu file laporan yang dibuat di background. Key storage tidak pernah
+// dikirim ke klien; file diunduh lewat GET /admin/reports/exports/{id}/download sampai expires_at.
+type ReportExport struct {
+	ID               int             `json:"id"`
+	ReportType       string          `json:"report_type"`
+	Format           string          `json:"format"`
+	Params           json.RawMessage `json:"params"`
+	Status           string          `json:"status"` // Lihat ReportExport*
+	RequestedBy      *int            `json:"requested_by"`
+	RetentionSeconds int             `json:"retention_seconds"`
+	StorageKey       *string         `json:"-"`
+	FileName         *string         `json:"file_name,omitempty"`
+	ContentType      *string         `json:"content_type,omitempty"`
+	SizeBytes        *int64          `json:"size_bytes,omitempty"`
+	RowCount         *int            `json:"row_count,omitempty"`
+	Error            *string         `json:"error,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	CompletedAt      *time.Time      `json:"completed_at,omitempty"`
+	ExpiresAt        *time.Time      `json:"expires_at,omitempty"`
+	PurgedAt         *time.Time      `json:"purged_at,omitempty"`
+}
+
+// ReportSnapshotInput membekukan ringkasan absensi satu periode (POST /admin/reports/snapshots).
+// Periode diambil dari payroll_period_id, atau dari start_date & end_date jika tidak diisi.
+type ReportSnapshotInput struct {
+	PayrollPeriodID *int    `json:"payroll_period_id,omitempty" validate:"omitempty,gt=0"`
+	StartDate       string  `json:"start_date,omitempty" validate:"required_without=PayrollPeriodID,omitempty,datetime=2006-01-02"`
+	EndDate         string  `json:"end_date,omitempty" validate:"required_without=PayrollPeriodID,omitempty,datetime=2006-01-02"`
+	Label           *string `json:"label,omitempty" validate:"omitempty,max=100"`
+}
+
+// ReportSnapshotRow adalah ringkasan absensi satu user dalam snapshot: sesi yang sudah
+// check-out (berdasarkan tanggal check-in) beserta pembagian jamnya, dan sesi yang masih
+// terbuka saat snapshot dibuat (tidak dihitung ke jam).
+type ReportSnapshotRow struct {
+	UserID       int            `json:"user_id"`
+	Username     string         `json:"username"`
+	Sessions     int            `json:"sessions"`
+	DaysWorked   int            `json:"days_worked"`
+	OpenSessions int            `json:"open_sessions"`
+	Hours        HoursBreakdown `json:"hours"`
+}
+
+// ReportSnapshot adalah salinan beku ringkasan absensi satu periode. Checksum adalah SHA-256
+// dari Rows (JSON); ChecksumValid dihitung ulang setiap kali snapshot dibaca. Rows dan Totals
+// hanya ada pada detail snapshot.
+type ReportSnapshot struct {
+	ID              int                 `json:"id"`
+	Label           *string             `json:"label,omitempty"`
+	StartDate       string              `json:"start_date"`
+	EndDate         string              `json:"end_date"`
+	PayrollPeriodID *int                `json:"payroll_period_id,omitempty"`
+	RowCount        int                 `json:"row_count"`
+	Checksum        string              `json:"checksum"`
+	ChecksumValid   *bool               `json:"checksum_valid,omitempty"`
+	CreatedBy       *int                `json:"created_by,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	Totals          *HoursBreakdown     `json:"totals,omitempty"`
+	Rows            []ReportSnapshotRow `json:"rows,omitempty"`
+}
+
+// ReportExportResult adalah hasil pembuatan file ekspor yang dicatat saat status completed.
+type ReportExportResult struct {
+	StorageKey  string
+	FileName    string
+	ContentType string
+	SizeBytes   int64
+	RowCount    int
+}
+
+// Entitas sinkronisasi delta (GET /sync/{entity}); nilainya sama dengan sync_tombstones.entity.
+const (
+	SyncEntityUsers       = "users"
+	SyncEntityAttendances = "attendances"
+	SyncEntitySchedules   = "user_schedules"
+)
+
+// Batas sinkronisasi delta.
+const (
+	SyncDefaultLimit  = 500
+	SyncMaxLimit      = 1000
+	SyncSettleSeconds = 30 // Perubahan yang lebih baru dari ini baru dikembalikan pada panggilan berikutnya
+)
+
+// SyncCursor adalah watermark sinkronisasi delta: perubahan setelah (UpdatedSince, AfterID)
+// dalam urutan (updated_at, id). AfterID memisahkan record dengan updated_at yang sama.
+type SyncCursor struct {
+	UpdatedSince time.Time
+	AfterID      int
+}
+
+// SyncTombstone menandai record yang sudah dihapus.
+type SyncTombstone struct {
+	ID        int       `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// SyncBatch adalah satu halaman perubahan sejak watermark. Klien menerapkan Items (upsert)
+// dan Deleted, lalu memanggil ulang dengan next_updated_since & next_after_id; selama
+// has_more true masih ada perubahan yang belum diambil.
+type SyncBatch struct {
+	Items            any             `json:"items"` // []User, []Attendance, atau []UserSchedule sesuai entitas
+	Deleted          []SyncTombstone `json:"deleted"`
+	NextUpdatedSince time.Time       `json:"next_updated_since"`
+	NextAfterID      int             `json:"next_after_id"`
+	HasMore          bool            `json:"has_more"`
+}
+
+// DebugCapture adalah rekaman satu request/response API untuk diagnosis (lihat internal/debugcapture).
+// Header kredensial dan field sensitif di body sudah di-redact; body panjang dipotong. Body
+// hanya ada pada detail rekaman.
+type DebugCapture struct {
+	ID              int64             `json:"id"`
+	RequestID       *string           `json:"request_id,omitempty"`
+	UserID          *int              `json:"user_id,omitempty"`
+	Method          string            `json:"method"`
+	Route           string            `json:"route"` // Pola route, mis. /api/v1/user/schedules/:scheduleId
+	Path            string            `json:"path"`
+	Query           *string           `json:"query,omitempty"`
+	Status          int               `json:"status"`
+	LatencyMs       int               `json:"latency_ms"`
+	Error           *string           `json:"error,omitempty"` // Error dari handler (response ditulis ErrorHandler global)
+	IP              *string           `json:"ip,omitempty"`
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	RequestBody     *string           `json:"request_body,omitempty"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	ResponseBody    *string           `json:"response_body,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	ExpiresAt       time.Time         `json:"expires_at"`
+}
+
+// DebugCaptureFilter menyaring daftar rekaman (GET /admin/debug/captures).
+type DebugCaptureFilter struct {
+	UserID *int
+	Route  string // Pola route persis, mis. /api/v1/user/attendance/checkin
+	Method string
+}
+
+// Status backup database.
+const (
+	BackupPending   = "pending"   // Menunggu diambil job backups
+	BackupRunning   = "running"   // Sedang ditulis ke storage
+	BackupCompleted = "completed" // File tersedia; pulihkan dengan cmd/restore
+	BackupFailed    = "failed"    // Gagal dibuat; lihat error
+)
+
+// Backup adalah satu backup logis database (POST /admin/maintenance/backup): arsip tar.gz
+// berisi manifest.json dan isi tiap tabel dalam format COPY, dari satu snapshot transaksi.
+// Key storage tidak pernah dikirim ke klien; file diunduh lewat
+// GET /admin/maintenance/backups/{id}/download.
+type Backup struct {
+	ID            int        `json:"id"`
+	Status        string     `json:"status"` // Lihat Backup*
+	RequestedBy   *int       `json:"requested_by"`
+	SchemaVersion *int64     `json:"schema_version,omitempty"` // Versi migrasi database yang di-backup
+	StorageKey    *string    `json:"-"`
+	FileName      *string    `json:"file_name,omitempty"`
+	SizeBytes     *int64     `json:"size_bytes,omitempty"`
+	TableCount    *int       `json:"table_count,omitempty"`
+	RowCount      *int64     `json:"row_count,omitempty"`
+	Error         *string    `json:"error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// BackupResult adalah hasil backup yang dicatat saat status completed.
+type BackupResult struct {
+	StorageKey    string
+	FileName      string
+	SizeBytes     int64
+	SchemaVersion int64
+	TableCount    int
+	RowCount      int64
+}
+
+// Mode maintenance (pengaturan maintenance.mode dan maintenance.checkins).
+const (
+	MaintenanceOff            = "off"    // API melayani semua route seperti biasa
+	MaintenanceOn             = "on"     // Route non-admin dijawab 503 dengan Retry-After
+	MaintenanceCheckInsReject = "reject" // Check-in ikut ditolak selama maintenance
+	MaintenanceCheckInsBuffer = "buffer" // Check-in ditampung lalu dicatat setelah maintenance selesai
+)
+
+// MaintenanceStatus adalah status mode maintenance (GET /admin/maintenance, healthcheck).
+type MaintenanceStatus struct {
+	Enabled           bool   `json:"enabled"`
+	Message           string `json:"message,omitempty"` // Pesan untuk klien di response 503
+	RetryAfterSeconds int    `json:"retry_after_seconds"`
+	BufferCheckIns    bool   `json:"buffer_checkins"`
+	QueuedCheckIns    int    `json:"queued_checkins"` // Check-in yang ditampung di instance ini
+	BufferCapacity    int    `json:"buffer_capacity"`
+}
+
+// EnableMaintenanceInput adalah body POST /admin/maintenance/enable. Field yang tidak diisi
+// memakai default (tanpa pesan, Retry-After 300 detik, check-in ditolak).
+type EnableMaintenanceInput struct {
+	Message           string `json:"message" validate:"max=255"`
+	RetryAfterSeconds int    `json:"retry_after_seconds" validate:"omitempty,min=5,max=86400"`
+	BufferCheckIns    bool   `json:"buffer_checkins"`
+}
+
+// AppInstance adalah satu instance API yang melaporkan heartbeat (tabel app_instances).
+type AppInstance struct {
+	InstanceID string    `json:"instance_id"`
+	AppVersion string    `json:"app_version"`
+	Hostname   string    `json:"hostname"`
+	StartedAt  time.Time `json:"started_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// AppVersionSummary merangkum instance hidup per versi aplikasi.
+type AppVersionSummary struct {
+	AppVersion   string    `json:"app_version"`
+	Instances    int       `json:"instances"`
+	FirstStarted time.Time `json:"first_started_at"`
+	LastSeenAt   time.Time `json:"last_seen_at"`
+}
+
+// AppVersionsReport adalah response GET /admin/maintenance/app-versions.
+type AppVersionsReport struct {
+	CurrentVersion string              `json:"current_version"` // Versi instance yang menjawab request
+	Mixed          bool                `json:"mixed"`           // Lebih dari satu versi hidup: migrasi destruktif ditolak cmd/migrate
+	Versions       []AppVersionSummary `json:"versions"`
+	Instances      []AppInstance       `json:"instances"`
+}
+
+// Status hasil pemeriksaan diagnostik sistem (GET /admin/system/diagnostics).
+const (
+	DiagnosticOK      = "ok"
+	DiagnosticWarn    = "warn"
+	DiagnosticFail    = "fail"
+	DiagnosticSkipped = "skipped" // Tidak dikonfigurasi atau tidak berlaku (mis. database di sandbox)
+)
+
+// Asal nilai konfigurasi efektif.
+const (
+	ConfigSourceEnv     = "env"     // Di-set di environment
+	ConfigSourceDefault = "default" // Tidak di-set; memakai default aplikasi
+)
+
+// ConfigValue adalah nilai efektif satu environment variable.
+type ConfigValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`            // Nilai yang dipakai; rahasia disamarkan
+	Source string `json:"source"`           // Lihat ConfigSource*
+	Masked bool   `json:"masked,omitempty"` // Nilai disamarkan karena rahasia
+}
+
+// DiagnosticCheck adalah hasil satu pemeriksaan diagnostik (database, storage, SMTP, ...).
+type DiagnosticCheck struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"` // Lihat Diagnostic*
+	LatencyMs *int64 `json:"latency_ms,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+	Data      any    `json:"data,omitempty"` // MigrationDiagnostics, ClockDiagnostics, atau JobBacklog
+}
+
+// MigrationDiagnostics adalah status migrasi database dibanding file migrasi yang dibawa build ini.
+type MigrationDiagnostics struct {
+	AppliedVersion int64    `json:"applied_version"`
+	Dirty          bool     `json:"dirty"`                    // Migrasi terakhir gagal di tengah jalan
+	LatestVersion  *int64   `json:"latest_version,omitempty"` // Kosong jika direktori migrasi tidak ditemukan
+	Pending        []string `json:"pending"`                  // File migrasi yang belum dijalankan
+}
+
+// ClockDiagnostics membandingkan jam instance dengan server NTP.
+type ClockDiagnostics struct {
+	Server     string    `json:"server"`
+	OffsetMs   int64     `json:"offset_ms"` // Jam NTP dikurangi jam lokal; positif = jam lokal tertinggal
+	ServerTime time.Time `json:"server_time"`
+	LocalTime  time.Time `json:"local_time"`
+}
+
+// JobBacklog merangkum antrean job latar belakang (scheduled_jobs).
+type JobBacklog struct {
+	Jobs    int      `json:"jobs"`
+	Overdue []string `json:"overdue"` // Jadwal berikutnya sudah lewat lebih dari satu interval poll
+	Running []string `json:"running"`
+	Failing []string `json:"failing"` // Run terakhir gagal
+}
+
+// SystemDiagnostics adalah response GET /admin/system/diagnostics.
+type SystemDiagnostics struct {
+	Status        string            `json:"status"` // Terburuk dari semua pemeriksaan: ok, warn, atau fail
+	GeneratedAt   time.Time         `json:"generated_at"`
+	AppVersion    string            `json:"app_version"`
+	GoVersion     string            `json:"go_version"`
+	Hostname      string            `json:"hostname"`
+	StartedAt     time.Time         `json:"started_at"`
+	UptimeSeconds int64             `json:"uptime_seconds"`
+	SandboxMode   bool              `json:"sandbox_mode"`
+	Checks        []DiagnosticCheck `json:"checks"`
+	Config        []ConfigValue     `json:"config"` // Konfigurasi efektif, rahasia disamarkan
+}
+
+// BuildInfo adalah versi dan info build aplikasi (GET /version, lihat internal/buildinfo).
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`     // Commit git build ini
+	Modified  bool   `json:"modified,omitempty"`   // Dibangun dari working tree dengan perubahan belum di-commit
+	BuildTime string `json:"build_time,omitempty"` // RFC 3339
+	GoVersion string `json:"go_version"`
+}
@@ -0,0 +1,272 @@
+// internal/notify/notify.go
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/resilience"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Message adalah notifikasi untuk tim HR/admin (mis. masa probation berakhir) atau, jika To
+// diisi, untuk satu user (mis. peringatan login tidak biasa).
+// Topic dipakai penerima untuk routing, Data berisi detail terstruktur.
+type Message struct {
+	Topic   string         `json:"topic"`
+	To      string         `json:"to,omitempty"` // Email penerima; kosong = penerima default (HR)
+	Subject string         `json:"subject"`
+	Body    string         `json:"body"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// Topic notifikasi yang dikirim aplikasi.
+const (
+	TopicProbationEnding     = "hr.probation_ending"
+	TopicUnusualLogin        = "user.unusual_login"
+	TopicDisputeOpened       = "attendance.dispute_opened"         // Ke atasan langsung (atau HR jika tidak ada)
+	TopicDisputeResolved     = "attendance.dispute_resolved"       // Ke karyawan pemilik dispute
+	TopicApprovalEscalated   = "approval.escalated"                // Ke atasan berikutnya (atau HR) untuk item melewati SLA
+	TopicSessionsRevoked     = "user.sessions_revoked"             // Ke user: admin mengeluarkan semua sesinya
+	TopicEmailChangeConfirm  = "user.email_change_confirm"         // Ke alamat baru: tautan konfirmasi ganti email
+	TopicEmailChangeNotice   = "user.email_change_notice"          // Ke alamat lama: permintaan/hasil ganti email
+	TopicInvitation          = "user.invitation"                   // Ke alamat yang diundang: tautan registrasi
+	TopicQueuedCheckInFailed = "attendance.queued_check_in_failed" // Ke karyawan dan HR: check-in tertunda gagal dicatat
+)
+
+// Notifier adalah kontrak pengiriman notifikasi ke HR atau user.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+	Provider() string
+}
+
+// NewNotifierFromEnv membuat Notifier berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - NOTIFY_PROVIDER: 'log' (default, hanya mencatat topic & subject ke log aplikasi), 'webhook', atau 'smtp'.
+//   - NOTIFY_LOG_BODY: Untuk provider log, ikut mencatat body dan data (tautan token, alamat email).
+//     Hanya untuk development; ditolak jika APP_ENV=production. Default: false.
+//   - NOTIFY_WEBHOOK_URL: URL tujuan POST JSON Message (wajib untuk webhook), mis. webhook chat HR.
+//   - NOTIFY_WEBHOOK_TIMEOUT: Timeout request webhook. Default: 10s.
+//   - NOTIFY_SMTP_ADDR: Alamat server SMTP host:port (wajib untuk smtp). STARTTLS dipakai jika didukung.
+//   - NOTIFY_SMTP_USERNAME / NOTIFY_SMTP_PASSWORD: Kredensial SMTP (opsional).
+//   - NOTIFY_SMTP_FROM: Alamat pengirim (wajib untuk smtp).
+//   - NOTIFY_SMTP_HR_TO: Penerima untuk Message tanpa To (notifikasi HR).
+//   - NOTIFY_SMTP_TIMEOUT: Batas waktu satu pengiriman email. Default: 30s.
+//
+// Pengiriman webhook dan SMTP memakai retry & circuit breaker bersama (lihat resilience.PolicyFromEnv).
+func NewNotifierFromEnv() (Notifier, error) {
+	provider := strings.ToLower(configs.GetEnv("NOTIFY_PROVIDER", "log"))
+	switch provider {
+	case "log":
+		logBody := configs.GetEnvBool("NOTIFY_LOG_BODY", false)
+		if logBody {
+			if strings.EqualFold(configs.GetEnv("APP_ENV", configs.EnvDevelopment), configs.EnvProduction) {
+				return nil, fmt.Errorf("NOTIFY_LOG_BODY cannot be enabled when APP_ENV=production")
+			}
+			zlog.Warn().Msg("NOTIFY_LOG_BODY enabled: notification bodies, including sign-in and confirmation links, are written to the log")
+		}
+		return logNotifier{logBody: logBody}, nil
+	case "webhook":
+		url := configs.GetEnv("NOTIFY_WEBHOOK_URL", "")
+		if url == "" {
+			return nil, fmt.Errorf("NOTIFY_WEBHOOK_URL must be set when NOTIFY_PROVIDER=webhook")
+		}
+		timeout := configs.GetEnvDuration("NOTIFY_WEBHOOK_TIMEOUT", 10*time.Second)
+		zlog.Info().Str("provider", provider).Msg("HR notifications enabled")
+		return &webhookNotifier{
+			url: url, client: &http.Client{},
+			breaker: resilience.Get("notify.webhook"), policy: resilience.PolicyFromEnv(timeout),
+		}, nil
+	case "smtp":
+		n := &smtpNotifier{
+			addr:     configs.GetEnv("NOTIFY_SMTP_ADDR", ""),
+			username: configs.GetEnv("NOTIFY_SMTP_USERNAME", ""),
+			password: configs.GetEnv("NOTIFY_SMTP_PASSWORD", ""),
+			from:     configs.GetEnv("NOTIFY_SMTP_FROM", ""),
+			hrTo:     configs.GetEnv("NOTIFY_SMTP_HR_TO", ""),
+			breaker:  resilience.Get("notify.smtp"),
+			policy:   resilience.PolicyFromEnv(configs.GetEnvDuration("NOTIFY_SMTP_TIMEOUT", 30*time.Second)),
+		}
+		if n.addr == "" || n.from == "" {
+			return nil, fmt.Errorf("NOTIFY_SMTP_ADDR and NOTIFY_SMTP_FROM must be set when NOTIFY_PROVIDER=smtp")
+		}
+		zlog.Info().Str("provider", provider).Msg("Email notifications enabled")
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unsupported NOTIFY_PROVIDER '%s'", provider)
+	}
+}
+
+// logNotifier mencatat notifikasi ke log aplikasi (default tanpa integrasi eksternal).
+// Body dan Data tidak dicatat kecuali logBody: body bisa berisi tautan bertoken (konfirmasi
+// email, undangan, setup akun, "bukan saya") yang memberi akses ke akun siapa pun yang membaca log.
+type logNotifier struct {
+	logBody bool // NOTIFY_LOG_BODY (development saja)
+}
+
+func (logNotifier) Provider() string {
+	return "log"
+}
+
+func (n logNotifier) Notify(ctx context.Context, msg Message) error {
+	// Alamat penerima tidak dicatat (data pribadi).
+	event := zlog.Ctx(ctx).Info().Str("topic", msg.Topic).Bool("has_recipient", msg.To != "").Str("subject", msg.Subject)
+	if n.logBody {
+		event = event.Interface("data", msg.Data).Str("body", msg.Body)
+	}
+	event.Msg("Notification not delivered (NOTIFY_PROVIDER=log)")
+	return nil
+}
+
+// webhookNotifier mengirim Message sebagai JSON ke URL webhook.
+type webhookNotifier struct {
+	url     string
+	client  *http.Client
+	breaker *resilience.Breaker
+	policy  resilience.Policy // Timeout per percobaan ada di policy
+}
+
+func (n *webhookNotifier) Provider() string {
+	return "webhook"
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("error encoding notification: %w", err)
+	}
+	return resilience.Do(ctx, n.breaker, n.policy, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+		if err != nil {
+			return resilience.Permanent(fmt.Errorf("error building notification request: %w", err))
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := n.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("error calling notification webhook: %w", err)
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return resilience.HTTPStatusError(fmt.Errorf("notification webhook returned status %d", resp.StatusCode), resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// smtpNotifier mengirim Message sebagai email teks polos lewat SMTP.
+type smtpNotifier struct {
+	addr     string
+	username string
+	password string
+	from     string
+	hrTo     string
+	breaker  *resilience.Breaker
+	policy   resilience.Policy
+}
+
+func (n *smtpNotifier) Provider() string {
+	return "smtp"
+}
+
+func (n *smtpNotifier) Notify(ctx context.Context, msg Message) error {
+	to := msg.To
+	if to == "" {
+		to = n.hrTo
+	}
+	if to == "" {
+		return fmt.Errorf("no recipient for %s notification (set NOTIFY_SMTP_HR_TO)", msg.Topic)
+	}
+	// Buang CR/LF agar nilai dari data user tidak bisa menyisipkan header email.
+	header := strings.NewReplacer("\r", "", "\n", "")
+	var email bytes.Buffer
+	fmt.Fprintf(&email, "From: %s\r\n", header.Replace(n.from))
+	fmt.Fprintf(&email, "To: %s\r\n", header.Replace(to))
+	fmt.Fprintf(&email, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", header.Replace(msg.Subject)))
+	email.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	email.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if n.username != "" {
+		host, _, _ := net.SplitHostPort(n.addr)
+		auth = smtp.PlainAuth("", n.username, n.password, host)
+	}
+	err := resilience.Do(ctx, n.breaker, n.policy, func(ctx context.Context) error {
+		return sendMail(ctx, n.addr, auth, n.from, header.Replace(to), email.Bytes())
+	})
+	if err != nil {
+		return fmt.Errorf("error sending %s email: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// sendMail setara smtp.SendMail, tetapi koneksi dibuka dengan deadline dari ctx agar server
+// SMTP yang lambat tidak menahan pemanggil melewati timeout policy.
+func sendMail(ctx context.Context, addr string, auth smtp.Auth, from, to string, body []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	host, _, _ := net.SplitHostPort(addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return resilience.Permanent(err) // Kredensial salah: mencoba ulang tidak membantu
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return smtpError(err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return smtpError(err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return smtpError(err)
+	}
+	return client.Quit()
+}
+
+// smtpError menandai balasan SMTP 5xx (alamat ditolak, pesan ditolak) sebagai permanen.
+func smtpError(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		return resilience.Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogNotifierOmitsBodyByDefault(t *testing.T) {
+	t.Setenv("NOTIFY_PROVIDER", "log")
+	t.Setenv("NOTIFY_LOG_BODY", "")
+	notifier, err := NewNotifierFromEnv()
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	ctx := zerolog.New(&out).WithContext(context.Background())
+	err = notifier.Notify(ctx, Message{
+		Topic:   TopicEmailChangeConfirm,
+		To:      "new@example.com",
+		Subject: "Confirm your new email address",
+		Body:    "Open https://app.example.com/confirm-email?token=secret-token",
+		Data:    map[string]any{"user_id": 7},
+	})
+	require.NoError(t, err)
+
+	logged := out.String()
+	assert.Contains(t, logged, TopicEmailChangeConfirm)
+	assert.Contains(t, logged, "Confirm your new email address")
+	assert.NotContains(t, logged, "secret-token")
+	assert.NotContains(t, logged, "new@example.com")
+}
+
+func TestLogNotifierBodyOptIn(t *testing.T) {
+	t.Setenv("NOTIFY_PROVIDER", "log")
+	t.Setenv("NOTIFY_LOG_BODY", "true")
+
+	t.Run("development", func(t *testing.T) {
+		t.Setenv("APP_ENV", "development")
+		notifier, err := NewNotifierFromEnv()
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		ctx := zerolog.New(&out).WithContext(context.Background())
+		require.NoError(t, notifier.Notify(ctx, Message{Topic: TopicInvitation, Body: "token=abc"}))
+		assert.Contains(t, out.String(), "token=abc")
+	})
+
+	t.Run("production is refused", func(t *testing.T) {
+		t.Setenv("APP_ENV", "production")
+		_, err := NewNotifierFromEnv()
+		assert.ErrorContains(t, err, "NOTIFY_LOG_BODY")
+	})
+}
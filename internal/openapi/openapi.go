@@ -0,0 +1,225 @@
+// internal/openapi/openapi.go
+
+// Package openapi menyajikan spesifikasi API (GET /api/v1/openapi.json) dan, jika diaktifkan,
+// memvalidasi request masuk terhadap spesifikasi itu.
+//
+// Dasar spesifikasi adalah dokumen Swagger 2.0 yang digenerate swag dari anotasi godoc handler
+// (package docs). Saat startup dokumen itu dilengkapi dengan route yang benar-benar terdaftar di
+// router: permission setiap operasi (x-permission), dan operasi minimal (x-undocumented) untuk
+// route yang belum dianotasi. Selisih antara dokumen dan router (drift) dicatat ke log dan
+// dikembalikan di x-drift, sehingga dokumentasi yang tertinggal terlihat tanpa pipeline CI.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Mode validasi request (OPENAPI_VALIDATION).
+const (
+	ModeOff    = "off"    // Tidak memvalidasi request
+	ModeLog    = "log"    // Request yang tidak sesuai spesifikasi dicatat ke log, tetap diproses
+	ModeStrict = "strict" // Request yang tidak sesuai spesifikasi ditolak 400
+)
+
+// httpMethods adalah key operasi pada path item Swagger 2.0.
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// Spec adalah spesifikasi API yang disajikan dan dipakai validasi.
+type Spec struct {
+	mode     string
+	basePath string
+	raw      map[string]any                   // Dokumen apa adanya (ditambah hasil Complete) untuk disajikan
+	ops      map[string]map[string]*operation // path Swagger -> method (lowercase) -> operasi
+	defs     map[string]*schema               // definitions
+	served   []byte                           // JSON yang disajikan, dibuat oleh Complete
+}
+
+// Drift adalah selisih antara dokumen swag dan route yang terdaftar.
+type Drift struct {
+	Undocumented []string `json:"undocumented"` // Route terdaftar tanpa anotasi, mis. "GET /admin/jobs"
+	Stale        []string `json:"stale"`        // Operasi terdokumentasi tanpa route
+}
+
+type document struct {
+	BasePath    string                           `json:"basePath"`
+	Paths       map[string]map[string]*operation `json:"paths"`
+	Definitions map[string]*schema               `json:"definitions"`
+}
+
+type operation struct {
+	Consumes   []string    `json:"consumes"`
+	Parameters []parameter `json:"parameters"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"` // path, query, header, body, formData
+	Required bool    `json:"required"`
+	Type     string  `json:"type"`
+	Enum     []any   `json:"enum"`
+	Items    *schema `json:"items"`
+	Schema   *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Required   []string           `json:"required"`
+	Properties map[string]*schema `json:"properties"`
+	Items      *schema            `json:"items"`
+	AllOf      []*schema          `json:"allOf"`
+	Enum       []any              `json:"enum"`
+	MinLength  *int               `json:"minLength"`
+	MaxLength  *int               `json:"maxLength"`
+	Minimum    *float64           `json:"minimum"`
+	Maximum    *float64           `json:"maximum"`
+	// Objek tanpa properties (mis. map[string]any) menerima field apa pun.
+	AdditionalProperties any `json:"additionalProperties"`
+}
+
+// New mem-parse dokumen Swagger 2.0 (hasil swag ReadDoc) dengan mode validasi mode.
+func New(doc string, mode string) (*Spec, error) {
+	if !slices.Contains([]string{ModeOff, ModeLog, ModeStrict}, mode) {
+		return nil, fmt.Errorf("invalid OpenAPI validation mode %q, use: off, log, strict", mode)
+	}
+	var parsed document
+	if err := json.Unmarshal([]byte(doc), &parsed); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI document: %w", err)
+	}
+	var raw map[string]any
+	decoder := json.NewDecoder(strings.NewReader(doc))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI document: %w", err)
+	}
+	if parsed.Paths == nil {
+		parsed.Paths = map[string]map[string]*operation{}
+	}
+	return &Spec{mode: mode, basePath: parsed.BasePath, raw: raw, ops: parsed.Paths, defs: parsed.Definitions}, nil
+}
+
+// NewFromEnv membuat Spec dari dokumen swag berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - OPENAPI_VALIDATION: Validasi request terhadap spesifikasi: off, log (catat request yang
+//     tidak sesuai), atau strict (tolak 400). Default: off.
+func NewFromEnv(doc string) (*Spec, error) {
+	return New(doc, strings.ToLower(configs.GetEnv("OPENAPI_VALIDATION", ModeOff)))
+}
+
+// Mode mengembalikan mode validasi request.
+func (s *Spec) Mode() string {
+	return s.mode
+}
+
+// Complete melengkapi dokumen dengan route yang terdaftar (prefix router API apiPrefix, mis.
+// /api/v1): x-permission untuk setiap operasi dan operasi minimal untuk route tanpa anotasi.
+// Drift dicatat ke log dan dikembalikan. Harus dipanggil sekali setelah semua route terdaftar.
+func (s *Spec) Complete(apiPrefix string, routes []models.RoutePermission) Drift {
+	drift := Drift{Undocumented: []string{}, Stale: []string{}}
+	paths, _ := s.raw["paths"].(map[string]any)
+	if paths == nil {
+		paths = map[string]any{}
+		s.raw["paths"] = paths
+	}
+	routed := map[string]bool{}
+	for _, route := range routes {
+		path := swaggerPath(strings.TrimPrefix(route.Path, apiPrefix))
+		method := strings.ToLower(route.Method)
+		routed[method+" "+path] = true
+
+		item, _ := paths[path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[path] = item
+		}
+		op, _ := item[method].(map[string]any)
+		if op == nil {
+			op = undocumentedOperation(path)
+			item[method] = op
+			drift.Undocumented = append(drift.Undocumented, route.Method+" "+path)
+		}
+		op["x-permission"] = route.Permission
+	}
+	for path, item := range s.ops {
+		for _, method := range httpMethods {
+			if item[method] != nil && !routed[method+" "+path] {
+				drift.Stale = append(drift.Stale, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	slices.Sort(drift.Undocumented)
+	slices.Sort(drift.Stale)
+	s.raw["x-drift"] = drift
+
+	if len(drift.Undocumented) > 0 || len(drift.Stale) > 0 {
+		zlog.Warn().Int("undocumented", len(drift.Undocumented)).Strs("stale", drift.Stale).
+			Msg("OpenAPI document is out of date with the registered routes; regenerate it with swag init")
+	}
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s.raw); err != nil {
+		zlog.Error().Err(err).Msg("Failed to encode OpenAPI document")
+	}
+	s.served = out.Bytes()
+	return drift
+}
+
+// undocumentedOperation adalah operasi minimal untuk route tanpa anotasi: hanya parameter path.
+func undocumentedOperation(path string) map[string]any {
+	params := []any{}
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			params = append(params, map[string]any{
+				"name": strings.TrimSuffix(name, "}"), "in": "path", "required": true, "type": "string",
+			})
+		}
+	}
+	return map[string]any{
+		"summary":        "Undocumented route",
+		"parameters":     params,
+		"responses":      map[string]any{"default": map[string]any{"description": "Not documented"}},
+		"x-undocumented": true,
+	}
+}
+
+// swaggerPath mengubah pola route Fiber (/users/:userId) menjadi path Swagger (/users/{userId}).
+func swaggerPath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + strings.TrimSuffix(name, "?") + "}"
+		}
+	}
+	if joined := strings.Join(segments, "/"); joined != "" {
+		return joined
+	}
+	return "/"
+}
+
+// Handler menyajikan dokumen yang sudah dilengkapi Complete.
+//
+// @Summary OpenAPI specification
+// @Description Swagger 2.0 document of this API, generated from the handler annotations and completed at startup with every registered route: x-permission on each operation, minimal x-undocumented operations for routes without annotations, and x-drift listing undocumented routes and documented operations that no longer exist.
+// @Tags Public
+// @Produce json
+// @Success 200 {object} map[string]interface{} "OpenAPI (Swagger 2.0) document"
+// @Router /openapi.json [get]
+func (s *Spec) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return c.Send(s.served)
+	}
+}
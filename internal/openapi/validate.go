@@ -0,0 +1,235 @@
+// internal/openapi/validate.go
+
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rs/zerolog"
+)
+
+// maxValidationErrors membatasi jumlah pelanggaran yang dilaporkan per request.
+const maxValidationErrors = 20
+
+// Validator mengembalikan middleware yang memvalidasi parameter path/query dan body JSON
+// request terhadap operasi terdokumentasi untuk method dan route (pola route Fiber lengkap, mis.
+// /api/v1/admin/users/:userId). Mengembalikan nil jika validasi dimatikan atau route belum
+// terdokumentasi (route seperti itu muncul sebagai drift, bukan ditolak).
+func (s *Spec) Validator(method, route string) fiber.Handler {
+	if s == nil || s.mode == ModeOff {
+		return nil
+	}
+	path := swaggerPath(strings.TrimPrefix(route, s.basePath))
+	op := s.ops[path][strings.ToLower(method)]
+	if op == nil {
+		return nil
+	}
+	return func(c *fiber.Ctx) error {
+		violations := s.validateRequest(c, op)
+		if len(violations) == 0 {
+			return c.Next()
+		}
+		zerolog.Ctx(c.UserContext()).Warn().Str("route", route).Str("method", method).Strs("violations", violations).
+			Str("mode", s.mode).Msg("Request does not match the OpenAPI specification")
+		if s.mode != ModeStrict {
+			return c.Next()
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(models.Response{
+			Success: false, Message: "Request does not match the API specification", Data: violations,
+		})
+	}
+}
+
+func (s *Spec) validateRequest(c *fiber.Ctx, op *operation) []string {
+	var violations []string
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			violations = append(violations, checkParam(param, c.Params(param.Name), true)...)
+		case "query":
+			raw := c.Query(param.Name)
+			violations = append(violations, checkParam(param, raw, raw != "" || c.Context().QueryArgs().Has(param.Name))...)
+		case "body":
+			violations = append(violations, s.checkBody(c, param)...)
+		}
+	}
+	if len(violations) > maxValidationErrors {
+		violations = append(violations[:maxValidationErrors], fmt.Sprintf("... and %d more", len(violations)-maxValidationErrors))
+	}
+	return violations
+}
+
+// checkParam memvalidasi parameter path/query berupa string mentah.
+func checkParam(param parameter, raw string, present bool) []string {
+	name := param.In + " parameter " + param.Name
+	if !present {
+		if param.Required {
+			return []string{name + " is required"}
+		}
+		return nil
+	}
+	values := []string{raw}
+	itemType, enum := param.Type, param.Enum
+	if param.Type == "array" && param.Items != nil {
+		values = strings.Split(raw, ",")
+		itemType, enum = param.Items.Type, param.Items.Enum
+	}
+	var violations []string
+	for _, value := range values {
+		if !matchesScalarType(itemType, value) {
+			violations = append(violations, fmt.Sprintf("%s must be of type %s", name, itemType))
+			continue
+		}
+		if len(enum) > 0 && !slices.Contains(enumStrings(enum), value) {
+			violations = append(violations, fmt.Sprintf("%s must be one of %s", name, strings.Join(enumStrings(enum), ", ")))
+		}
+	}
+	return violations
+}
+
+func matchesScalarType(typ, raw string) bool {
+	switch typ {
+	case "integer":
+		_, err := strconv.ParseInt(raw, 10, 64)
+		return err == nil
+	case "number":
+		_, err := strconv.ParseFloat(raw, 64)
+		return err == nil
+	case "boolean":
+		_, err := strconv.ParseBool(raw)
+		return err == nil
+	}
+	return true
+}
+
+func enumStrings(enum []any) []string {
+	out := make([]string, len(enum))
+	for i, value := range enum {
+		out[i] = fmt.Sprint(value)
+	}
+	return out
+}
+
+// checkBody memvalidasi body JSON terhadap schema parameter body. Body non-JSON (multipart,
+// form) tidak divalidasi di sini.
+func (s *Spec) checkBody(c *fiber.Ctx, param parameter) []string {
+	body := c.Body()
+	if len(bytes.TrimSpace(body)) == 0 {
+		if param.Required {
+			return []string{"request body is required"}
+		}
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(string(c.Request().Header.ContentType()))
+	if mediaType != fiber.MIMEApplicationJSON && !strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return []string{"request body is not valid JSON"}
+	}
+	if param.Schema == nil {
+		return nil
+	}
+	return s.checkValue("body", param.Schema, value, 0)
+}
+
+// checkValue memvalidasi value (hasil decode JSON dengan UseNumber) terhadap sch.
+func (s *Spec) checkValue(at string, sch *schema, value any, depth int) []string {
+	if sch == nil || depth > 32 {
+		return nil
+	}
+	if sch.Ref != "" {
+		return s.checkValue(at, s.defs[strings.TrimPrefix(sch.Ref, "#/definitions/")], value, depth+1)
+	}
+	var violations []string
+	for _, part := range sch.AllOf {
+		violations = append(violations, s.checkValue(at, part, value, depth+1)...)
+	}
+	if value == nil {
+		// null diterima untuk field opsional/pointer; swag tidak menandai nullable.
+		return violations
+	}
+
+	switch sch.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return append(violations, at+" must be an object")
+		}
+		for _, name := range sch.Required {
+			if _, ok := object[name]; !ok {
+				violations = append(violations, fmt.Sprintf("%s.%s is required", at, name))
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			property, known := sch.Properties[name]
+			switch {
+			case known:
+				violations = append(violations, s.checkValue(at+"."+name, property, object[name], depth+1)...)
+			case len(sch.Properties) > 0 && sch.AdditionalProperties == nil:
+				violations = append(violations, fmt.Sprintf("%s.%s is not a documented field", at, name))
+			}
+		}
+	case "array":
+		list, ok := value.([]any)
+		if !ok {
+			return append(violations, at+" must be an array")
+		}
+		for i, item := range list {
+			violations = append(violations, s.checkValue(fmt.Sprintf("%s[%d]", at, i), sch.Items, item, depth+1)...)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return append(violations, at+" must be a string")
+		}
+		length := len([]rune(text))
+		if sch.MinLength != nil && length < *sch.MinLength {
+			violations = append(violations, fmt.Sprintf("%s must be at least %d characters", at, *sch.MinLength))
+		}
+		if sch.MaxLength != nil && length > *sch.MaxLength {
+			violations = append(violations, fmt.Sprintf("%s must be at most %d characters", at, *sch.MaxLength))
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return append(violations, fmt.Sprintf("%s must be of type %s", at, sch.Type))
+		}
+		if sch.Type == "integer" {
+			if _, err := number.Int64(); err != nil {
+				return append(violations, at+" must be of type integer")
+			}
+		}
+		f, _ := number.Float64()
+		if sch.Minimum != nil && f < *sch.Minimum {
+			violations = append(violations, fmt.Sprintf("%s must be at least %v", at, *sch.Minimum))
+		}
+		if sch.Maximum != nil && f > *sch.Maximum {
+			violations = append(violations, fmt.Sprintf("%s must be at most %v", at, *sch.Maximum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return append(violations, at+" must be a boolean")
+		}
+	}
+	if len(sch.Enum) > 0 && !slices.Contains(enumStrings(sch.Enum), fmt.Sprint(value)) {
+		violations = append(violations, fmt.Sprintf("%s must be one of %s", at, strings.Join(enumStrings(sch.Enum), ", ")))
+	}
+	return violations
+}
@@ -0,0 +1,166 @@
+// internal/otp/otp.go
+
+// Package otp mengirim dan memeriksa kode OTP SMS: verifikasi nomor HP user dan faktor kedua
+// login (2FA) bagi user yang mengaktifkannya.
+package otp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/sms"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// codeDigits adalah panjang kode OTP.
+const codeDigits = 6
+
+var (
+	// ErrNoPhone dikembalikan jika user belum mengisi nomor HP.
+	ErrNoPhone = errors.New("user has no phone number")
+	// ErrResendTooSoon dikembalikan jika kode baru saja dikirim (lihat OTP_RESEND_INTERVAL).
+	ErrResendTooSoon = errors.New("verification code was sent recently")
+	// ErrInvalidCode dikembalikan untuk kode yang salah, kedaluwarsa, sudah dipakai, terlalu
+	// banyak dicoba, atau terkirim ke nomor yang sudah diganti.
+	ErrInvalidCode = errors.New("invalid or expired verification code")
+)
+
+// Service mengirim dan memeriksa kode OTP SMS.
+type Service struct {
+	users       repository.UserRepository
+	sender      sms.Sender
+	ttl         time.Duration
+	resendAfter time.Duration
+	maxAttempts int
+}
+
+// NewServiceFromEnv membuat Service berdasarkan environment variables. Mengembalikan nil jika
+// SMS tidak dikonfigurasi (sender nil).
+//
+// Variabel Environment yang didukung:
+//   - OTP_TTL: Masa berlaku kode. Default: 5m.
+//   - OTP_RESEND_INTERVAL: Jeda minimum antar pengiriman kode untuk tujuan yang sama. Default: 1m.
+//   - OTP_MAX_ATTEMPTS: Jumlah percobaan kode salah sebelum kode hangus. Default: 5.
+func NewServiceFromEnv(users repository.UserRepository, sender sms.Sender) (*Service, error) {
+	if sender == nil {
+		return nil, nil
+	}
+	s := &Service{
+		users:       users,
+		sender:      sender,
+		ttl:         configs.GetEnvDuration("OTP_TTL", 5*time.Minute),
+		resendAfter: configs.GetEnvDuration("OTP_RESEND_INTERVAL", time.Minute),
+		maxAttempts: configs.GetEnvInt("OTP_MAX_ATTEMPTS", 5),
+	}
+	if s.ttl <= 0 || s.maxAttempts <= 0 {
+		return nil, fmt.Errorf("OTP_TTL and OTP_MAX_ATTEMPTS must be positive")
+	}
+	zlog.Info().Str("sms_provider", sender.Provider()).Dur("ttl", s.ttl).Msg("SMS one-time codes enabled")
+	return s, nil
+}
+
+// TTL mengembalikan masa berlaku kode.
+func (s *Service) TTL() time.Duration {
+	return s.ttl
+}
+
+// Send membuat kode baru untuk tujuan purpose (menggantikan kode terbuka sebelumnya) dan
+// mengirimkannya ke nomor HP user. ErrNoPhone jika user tidak punya nomor HP;
+// ErrResendTooSoon jika kode sebelumnya baru saja dikirim.
+func (s *Service) Send(ctx context.Context, user *models.User, purpose string) (*models.PhoneOTP, error) {
+	if user.Phone == nil || *user.Phone == "" {
+		return nil, ErrNoPhone
+	}
+	now := time.Now()
+	previous, err := s.users.GetOpenPhoneOTP(ctx, user.ID, purpose)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if previous != nil && now.Sub(previous.CreatedAt) < s.resendAfter {
+		return previous, ErrResendTooSoon
+	}
+
+	code, err := newCode()
+	if err != nil {
+		return nil, err
+	}
+	otp := &models.PhoneOTP{UserID: user.ID, Purpose: purpose, CodeHash: hashCode(user.ID, purpose, code), ExpiresAt: now.Add(s.ttl)}
+	if err := s.users.CreatePhoneOTP(ctx, otp, *user.Phone); err != nil {
+		return nil, err
+	}
+	body := fmt.Sprintf("Your attendance verification code is %s. It expires in %d minutes. Never share this code.", code, int(s.ttl.Minutes()))
+	if purpose == models.OTPPurposeLogin {
+		body = fmt.Sprintf("Your attendance login code is %s. It expires in %d minutes. If you didn't try to log in, change your password.", code, int(s.ttl.Minutes()))
+	}
+	if err := s.sender.Send(ctx, *user.Phone, body); err != nil {
+		return nil, fmt.Errorf("error sending %s code via %s: %w", purpose, s.sender.Provider(), err)
+	}
+	return otp, nil
+}
+
+// Verify memeriksa kode untuk tujuan purpose dan memakainya jika benar. Untuk verify_phone,
+// nomor HP user sekaligus ditandai terverifikasi. Kode hangus setelah OTP_MAX_ATTEMPTS
+// percobaan salah. ErrInvalidCode untuk semua kode yang tidak berlaku.
+func (s *Service) Verify(ctx context.Context, userID int, purpose, code string) error {
+	otp, err := s.users.GetOpenPhoneOTP(ctx, userID, purpose)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrInvalidCode
+	}
+	if err != nil {
+		return err
+	}
+	if !time.Now().Before(otp.ExpiresAt) || otp.Attempts >= s.maxAttempts {
+		return ErrInvalidCode
+	}
+	if subtle.ConstantTimeCompare([]byte(hashCode(userID, purpose, code)), []byte(otp.CodeHash)) != 1 {
+		if _, err := s.users.RecordPhoneOTPAttempt(ctx, otp.ID); err != nil {
+			return err
+		}
+		return ErrInvalidCode
+	}
+	err = s.users.ConsumePhoneOTP(ctx, otp.ID)
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, repository.ErrPhoneChanged) {
+		return ErrInvalidCode
+	}
+	return err
+}
+
+// Notify mengirim SMS teks bebas (mis. pengingat shift) ke nomor HP terverifikasi user.
+func (s *Service) Notify(ctx context.Context, user *models.User, body string) error {
+	if user.Phone == nil || *user.Phone == "" || user.PhoneVerifiedAt == nil {
+		return ErrNoPhone
+	}
+	return s.sender.Send(ctx, *user.Phone, body)
+}
+
+// newCode membuat kode numerik acak sepanjang codeDigits.
+func newCode() (string, error) {
+	limit := big.NewInt(1)
+	for range codeDigits {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", fmt.Errorf("error generating verification code: %w", err)
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n.Int64()), nil
+}
+
+// hashCode mengikat kode ke user & tujuannya. Ruang kode kecil, sehingga perlindungan utamanya
+// tetap batas percobaan dan masa berlaku; hash mencegah kode terbaca langsung dari database.
+func hashCode(userID int, purpose, code string) string {
+	sum := sha256.Sum256([]byte(strconv.Itoa(userID) + ":" + purpose + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,168 @@
+// internal/outbox/outbox.go
+
+// Package outbox mengirim efek samping domain event (email/inbox/push, webhook, streaming
+// broker) dengan pola transactional outbox. Subscriber Dispatcher.Enqueue menyimpan satu pesan
+// per tujuan ke tabel outbox_messages, dalam transaksi yang sama dengan perubahan domain jika
+// handler memakai repository.TxManager. Dispatcher kemudian mengirimnya di background dengan
+// retry ber-backoff; pesan yang terus gagal dipindah ke dead letter untuk ditinjau admin.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Handler mengirim satu pesan outbox ke tujuannya. Error berarti pesan dicoba lagi, sehingga
+// handler harus sinkron (tidak mengirim di goroutine) dan sebaiknya idempoten.
+type Handler func(ctx context.Context, msg models.OutboxMessage) error
+
+// EventHandler mengadaptasi events.Handler menjadi Handler dengan men-decode payload event.
+func EventHandler(h events.Handler) Handler {
+	return func(ctx context.Context, msg models.OutboxMessage) error {
+		var ev events.Event
+		if err := json.Unmarshal(msg.Payload, &ev); err != nil {
+			return fmt.Errorf("error decoding outbox event %d: %w", msg.ID, err)
+		}
+		return h(ctx, ev)
+	}
+}
+
+type destination struct {
+	handler Handler
+	names   []string // Kosong = semua event
+}
+
+// Dispatcher mendaftarkan tujuan efek samping, menyimpan pesannya ke outbox, dan mengirimnya.
+type Dispatcher struct {
+	repo         repository.OutboxRepository
+	destinations map[string]destination
+	interval     time.Duration
+	batchSize    int
+	maxAttempts  int
+	backoff      time.Duration
+	maxBackoff   time.Duration
+	retention    time.Duration
+}
+
+// NewDispatcherFromEnv membuat dispatcher berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - OUTBOX_DISPATCH_INTERVAL: Jeda antar pengecekan pesan jatuh tempo. Default: 2s.
+//   - OUTBOX_BATCH_SIZE: Maksimum pesan per batch. Default: 50.
+//   - OUTBOX_MAX_ATTEMPTS: Percobaan sebelum pesan menjadi dead letter. Default: 8.
+//   - OUTBOX_RETRY_BACKOFF: Jeda retry pertama, dilipatgandakan tiap percobaan. Default: 30s.
+//   - OUTBOX_MAX_BACKOFF: Batas atas jeda retry. Default: 1h.
+//   - OUTBOX_RETENTION: Lama pesan terkirim disimpan. Default: 168h. 0 = tidak dihapus.
+func NewDispatcherFromEnv(repo repository.OutboxRepository) *Dispatcher {
+	return &Dispatcher{
+		repo:         repo,
+		destinations: map[string]destination{},
+		interval:     max(configs.GetEnvDuration("OUTBOX_DISPATCH_INTERVAL", 2*time.Second), 100*time.Millisecond),
+		batchSize:    max(configs.GetEnvInt("OUTBOX_BATCH_SIZE", 50), 1),
+		maxAttempts:  max(configs.GetEnvInt("OUTBOX_MAX_ATTEMPTS", 8), 1),
+		backoff:      configs.GetEnvDuration("OUTBOX_RETRY_BACKOFF", 30*time.Second),
+		maxBackoff:   configs.GetEnvDuration("OUTBOX_MAX_BACKOFF", time.Hour),
+		retention:    configs.GetEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+	}
+}
+
+// Register mendaftarkan tujuan bernama name untuk event bernama names (kosong = semua event).
+// Harus dipanggil sebelum event pertama di-publish. Nama tujuan disimpan di outbox, sehingga
+// tidak boleh diganti selama masih ada pesan tertunda.
+func (d *Dispatcher) Register(name string, handler Handler, names ...string) {
+	d.destinations[name] = destination{handler: handler, names: names}
+}
+
+// Enqueue adalah subscriber events.Bus yang menyimpan satu pesan outbox per tujuan yang
+// berlangganan event tersebut. Dipanggil di dalam TxManager.RunInTx, pesan ikut transaksi
+// perubahan domain.
+func (d *Dispatcher) Enqueue(ctx context.Context, ev events.Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("error encoding event %s: %w", ev.Name, err)
+	}
+	var userID *int
+	if ev.UserID != 0 {
+		userID = &ev.UserID
+	}
+	var msgs []models.OutboxMessage
+	for name, dest := range d.destinations {
+		if len(dest.names) > 0 && !slices.Contains(dest.names, ev.Name) {
+			continue
+		}
+		msgs = append(msgs, models.OutboxMessage{Destination: name, EventName: ev.Name, UserID: userID, Payload: payload})
+	}
+	return d.repo.EnqueueOutboxMessages(ctx, msgs)
+}
+
+// Start menjalankan dispatcher setiap interval sampai ctx dibatalkan. Dipanggil sebagai goroutine.
+func (d *Dispatcher) Start(ctx context.Context) {
+	zlog.Info().Dur("interval", d.interval).Int("max_attempts", d.maxAttempts).Msg("Outbox dispatcher started")
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.RunOnce(ctx); err != nil {
+			zlog.Error().Err(err).Msg("Outbox dispatcher failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce mengirim pesan jatuh tempo batch demi batch sampai tidak ada lagi, lalu menghapus
+// pesan terkirim yang melewati retensi. Mengembalikan jumlah pesan yang terkirim.
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		delivered, failed, err := d.repo.ProcessDueOutboxMessages(ctx, d.batchSize, d.maxAttempts, d.retryDelay, d.deliver)
+		total += delivered
+		if err != nil {
+			return total, err
+		}
+		if delivered+failed < d.batchSize {
+			break
+		}
+	}
+	if d.retention > 0 {
+		if _, err := d.repo.DeleteDeliveredOutboxMessages(ctx, time.Now().Add(-d.retention)); err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// deliver memanggil handler tujuan pesan. Pesan untuk tujuan yang tidak lagi terdaftar
+// (mis. webhook dimatikan) gagal dan akhirnya masuk dead letter.
+func (d *Dispatcher) deliver(ctx context.Context, msg models.OutboxMessage) (err error) {
+	dest, ok := d.destinations[msg.Destination]
+	if !ok {
+		return fmt.Errorf("no handler registered for outbox destination %q", msg.Destination)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in outbox handler %s: %v", msg.Destination, r)
+		}
+	}()
+	return dest.handler(ctx, msg)
+}
+
+// retryDelay mengembalikan jeda sebelum percobaan berikutnya (backoff eksponensial).
+func (d *Dispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.backoff
+	for i := 1; i < attempts && delay < d.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.maxBackoff)
+}
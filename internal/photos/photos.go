@@ -0,0 +1,315 @@
+// internal/photos/photos.go
+
+// Package photos mengelola foto (selfie) check-in: upload mentah disimpan terenkripsi lalu
+// diproses di background (decode, buang metadata EXIF, buat thumbnail), hasilnya disimpan
+// terenkripsi dengan kunci PII, dan dihapus setelah masa retensi. Admin membuka foto lewat URL
+// bertanda tangan berumur pendek, bukan path file.
+package photos
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // Registrasi decoder PNG untuk image.Decode
+	"io"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/pii"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/storage"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// maxPixels membatasi ukuran gambar yang di-decode (jaga-jaga "decompression bomb").
+const maxPixels = 25_000_000
+
+// jpegQuality dipakai untuk foto asli dan thumbnail hasil proses.
+const jpegQuality = 90
+
+// ErrNotProcessed dikembalikan Open jika foto belum diproses, gagal, atau sudah dihapus.
+var ErrNotProcessed = errors.New("attendance photo is not available")
+
+// Pipeline menyimpan, memproses, dan menghapus foto check-in. Pipeline nil = fitur nonaktif.
+type Pipeline struct {
+	repo          repository.AttendancePhotoRepository
+	store         storage.Storage
+	protector     *pii.Protector
+	thumbnailSize int           // Sisi terpanjang thumbnail (px)
+	retention     time.Duration // Umur foto sebelum dihapus; 0 = disimpan selamanya
+	urlTTL        time.Duration // Masa berlaku URL bertanda tangan
+}
+
+// NewPipeline membuat Pipeline dengan pengaturan eksplisit.
+func NewPipeline(repo repository.AttendancePhotoRepository, store storage.Storage, protector *pii.Protector, thumbnailSize int, retention, urlTTL time.Duration) *Pipeline {
+	return &Pipeline{repo: repo, store: store, protector: protector, thumbnailSize: thumbnailSize, retention: retention, urlTTL: urlTTL}
+}
+
+// NewPipelineFromEnv membuat Pipeline berdasarkan environment variables.
+// Mengembalikan nil jika penyimpanan foto tidak diaktifkan.
+//
+// Variabel Environment yang didukung:
+//   - ATTENDANCE_PHOTO_ENABLED: Simpan selfie check-in sebagai foto absensi. Default: false.
+//   - ATTENDANCE_PHOTO_THUMBNAIL_SIZE: Sisi terpanjang thumbnail (px). Default: 320.
+//   - ATTENDANCE_PHOTO_RETENTION: Umur foto sebelum dihapus. Default: 2160h (90 hari). 0 = selamanya.
+//   - ATTENDANCE_PHOTO_URL_TTL: Masa berlaku URL foto untuk admin. Default: 5m.
+func NewPipelineFromEnv(repo repository.AttendancePhotoRepository, store storage.Storage, protector *pii.Protector) (*Pipeline, error) {
+	if !configs.GetEnvBool("ATTENDANCE_PHOTO_ENABLED", false) {
+		return nil, nil
+	}
+	thumbnailSize := configs.GetEnvInt("ATTENDANCE_PHOTO_THUMBNAIL_SIZE", 320)
+	if thumbnailSize <= 0 {
+		return nil, fmt.Errorf("ATTENDANCE_PHOTO_THUMBNAIL_SIZE must be positive")
+	}
+	urlTTL := configs.GetEnvDuration("ATTENDANCE_PHOTO_URL_TTL", 5*time.Minute)
+	if urlTTL <= 0 {
+		return nil, fmt.Errorf("ATTENDANCE_PHOTO_URL_TTL must be positive")
+	}
+	retention := configs.GetEnvDuration("ATTENDANCE_PHOTO_RETENTION", 90*24*time.Hour)
+	zlog.Info().Int("thumbnail_size", thumbnailSize).Dur("retention", retention).Msg("Attendance photo storage enabled")
+	return NewPipeline(repo, store, protector, thumbnailSize, retention, urlTTL), nil
+}
+
+// URLTTL mengembalikan masa berlaku URL bertanda tangan.
+func (p *Pipeline) URLTTL() time.Duration {
+	return p.urlTTL
+}
+
+// Ingest menyimpan upload mentah (terenkripsi) untuk record absensi dan mengantrekannya untuk
+// diproses. Isi file belum diperiksa di sini agar check-in tidak menunggu decode gambar.
+func (p *Pipeline) Ingest(ctx context.Context, attendanceID, userID int, raw []byte) (*models.AttendancePhoto, error) {
+	key, err := newPhotoKey("attendance-photos/incoming", time.Now(), ".enc")
+	if err != nil {
+		return nil, err
+	}
+	if err := p.putEncrypted(ctx, key, raw); err != nil {
+		return nil, err
+	}
+	photo := &models.AttendancePhoto{AttendanceID: attendanceID, UserID: userID, IncomingKey: &key}
+	if err := p.repo.CreateAttendancePhoto(ctx, photo); err != nil {
+		p.deleteQuietly(ctx, key)
+		return nil, err
+	}
+	return photo, nil
+}
+
+// Process memproses maksimal batch foto pending dan mengembalikan jumlah yang selesai (berhasil
+// maupun gagal). Upload yang bukan JPEG/PNG valid ditandai failed dan dibuang; error storage
+// atau database menghentikan putaran agar dicoba lagi nanti.
+func (p *Pipeline) Process(ctx context.Context, batch int) (int, error) {
+	pending, err := p.repo.GetPendingAttendancePhotos(ctx, batch)
+	if err != nil {
+		return 0, err
+	}
+	done := 0
+	for i := range pending {
+		if err := ctx.Err(); err != nil {
+			return done, err
+		}
+		if err := p.processOne(ctx, &pending[i]); err != nil {
+			return done, err
+		}
+		done++
+	}
+	return done, nil
+}
+
+func (p *Pipeline) processOne(ctx context.Context, photo *models.AttendancePhoto) error {
+	if photo.IncomingKey == nil {
+		return p.repo.MarkAttendancePhotoFailed(ctx, photo.ID, "upload is missing")
+	}
+	incoming := *photo.IncomingKey
+	raw, err := p.openDecrypted(ctx, incoming)
+	if errors.Is(err, storage.ErrNotFound) {
+		return p.repo.MarkAttendancePhotoFailed(ctx, photo.ID, "upload is missing")
+	}
+	if err != nil {
+		return err
+	}
+
+	original, thumbnail, bounds, err := p.render(raw)
+	if err != nil {
+		zlog.Warn().Err(err).Int("photo_id", photo.ID).Msg("Attendance photo rejected")
+		if err := p.repo.MarkAttendancePhotoFailed(ctx, photo.ID, err.Error()); err != nil {
+			return err
+		}
+		p.deleteQuietly(ctx, incoming)
+		return nil
+	}
+
+	now := time.Now()
+	originalKey, err := newPhotoKey("attendance-photos", now, ".orig")
+	if err != nil {
+		return err
+	}
+	thumbnailKey, err := newPhotoKey("attendance-photos", now, ".thumb")
+	if err != nil {
+		return err
+	}
+	if err := p.putEncrypted(ctx, originalKey, original); err != nil {
+		return err
+	}
+	if err := p.putEncrypted(ctx, thumbnailKey, thumbnail); err != nil {
+		p.deleteQuietly(ctx, originalKey)
+		return err
+	}
+	if err := p.repo.MarkAttendancePhotoProcessed(ctx, photo.ID, originalKey, thumbnailKey, bounds.Dx(), bounds.Dy()); err != nil {
+		p.deleteQuietly(ctx, originalKey)
+		p.deleteQuietly(ctx, thumbnailKey)
+		return err
+	}
+	p.deleteQuietly(ctx, incoming)
+	return nil
+}
+
+// render men-decode upload lalu meng-encode ulang sebagai JPEG (metadata EXIF, termasuk
+// lokasi GPS, tidak ikut tersalin) beserta thumbnail-nya.
+func (p *Pipeline) render(raw []byte) (original, thumbnail []byte, bounds image.Rectangle, err error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, nil, bounds, fmt.Errorf("unsupported image: %w", err)
+	}
+	if format != "jpeg" && format != "png" {
+		return nil, nil, bounds, fmt.Errorf("unsupported image format %q", format)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, nil, bounds, fmt.Errorf("image dimensions %dx%d out of range", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, nil, bounds, fmt.Errorf("invalid image: %w", err)
+	}
+	if original, err = encodeJPEG(img); err != nil {
+		return nil, nil, bounds, err
+	}
+	if thumbnail, err = encodeJPEG(downscale(img, p.thumbnailSize)); err != nil {
+		return nil, nil, bounds, err
+	}
+	return original, thumbnail, img.Bounds(), nil
+}
+
+// Purge menghapus file foto yang melewati masa retensi (maksimal batch foto) dan mengembalikan
+// jumlah foto yang dihapus.
+func (p *Pipeline) Purge(ctx context.Context, batch int) (int, error) {
+	if p.retention <= 0 {
+		return 0, nil
+	}
+	expired, err := p.repo.GetExpiredAttendancePhotos(ctx, time.Now().Add(-p.retention), batch)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, photo := range expired {
+		for _, key := range []*string{photo.IncomingKey, photo.OriginalKey, photo.ThumbnailKey} {
+			if key == nil {
+				continue
+			}
+			if err := p.store.Delete(ctx, *key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return purged, fmt.Errorf("error deleting attendance photo %d: %w", photo.ID, err)
+			}
+		}
+		if err := p.repo.MarkAttendancePhotoPurged(ctx, photo.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// Open mengembalikan isi JPEG (terdekripsi) satu varian foto yang sudah diproses.
+func (p *Pipeline) Open(ctx context.Context, photo *models.AttendancePhoto, variant string) ([]byte, error) {
+	if photo.Status != models.AttendancePhotoProcessed {
+		return nil, ErrNotProcessed
+	}
+	key := photo.ThumbnailKey
+	if variant == models.AttendancePhotoOriginal {
+		key = photo.OriginalKey
+	}
+	if key == nil {
+		return nil, ErrNotProcessed
+	}
+	return p.openDecrypted(ctx, *key)
+}
+
+func (p *Pipeline) putEncrypted(ctx context.Context, key string, data []byte) error {
+	sealed, err := p.protector.EncryptBytes(data)
+	if err != nil {
+		return err
+	}
+	return p.store.Put(ctx, key, bytes.NewReader(sealed), "application/octet-stream")
+}
+
+func (p *Pipeline) openDecrypted(ctx context.Context, key string) ([]byte, error) {
+	rc, err := p.store.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	sealed, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", key, err)
+	}
+	return p.protector.DecryptBytes(sealed)
+}
+
+// deleteQuietly menghapus file yang tidak lagi dirujuk; kegagalannya hanya dicatat.
+func (p *Pipeline) deleteQuietly(ctx context.Context, key string) {
+	if err := p.store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		zlog.Warn().Err(err).Str("key", key).Msg("Failed to delete attendance photo file")
+	}
+}
+
+// newPhotoKey membuat storage key acak di bawah prefix/YYYY/MM.
+func newPhotoKey(prefix string, now time.Time, suffix string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s/%s%s", prefix, now.UTC().Format("2006/01"), hex.EncodeToString(b), suffix), nil
+}
+
+func encodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, fmt.Errorf("error encoding jpeg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// downscale memperkecil img (rata-rata area) sehingga sisi terpanjangnya maksimal size px.
+// Gambar yang sudah cukup kecil dikembalikan apa adanya.
+func downscale(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+	tw, th := size, h*size/w
+	if h > w {
+		tw, th = w*size/h, size
+	}
+	tw, th = max(tw, 1), max(th, 1)
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+max((y+1)*h/th, y*h/th+1)
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+max((x+1)*w/tw, x*w/tw+1)
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a, n = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(bl / n >> 8), A: uint8(a / n >> 8)})
+		}
+	}
+	return dst
+}
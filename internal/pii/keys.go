@@ -0,0 +1,63 @@
+// internal/pii/keys.go
+package pii
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvKeyProvider membaca keyring dari environment variables.
+// Di deployment yang memakai KMS, env var ini biasanya diisi oleh secret manager
+// (misal: data key yang sudah di-unwrap oleh init container / sidecar).
+//
+// Variabel Environment yang didukung:
+//   - PII_ENCRYPTION_KEYS: daftar "<id>:<base64-32-byte>" dipisahkan koma (misal: "v1:...,v2:...").
+//   - PII_ACTIVE_KEY_ID: ID kunci untuk enkripsi data baru (default: ID pertama di daftar).
+//   - PII_INDEX_KEY: kunci base64 untuk HMAC blind index.
+type EnvKeyProvider struct{}
+
+// Keyring mengimplementasikan KeyProvider.
+func (EnvKeyProvider) Keyring() (Keyring, error) {
+	kr := Keyring{DataKeys: map[string][]byte{}}
+
+	raw := strings.TrimSpace(os.Getenv("PII_ENCRYPTION_KEYS"))
+	if raw == "" {
+		return kr, fmt.Errorf("%w: PII_ENCRYPTION_KEYS is not set", ErrNoActiveKey)
+	}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return kr, fmt.Errorf("pii: invalid PII_ENCRYPTION_KEYS entry, expected '<id>:<base64>'")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return kr, fmt.Errorf("pii: invalid base64 for key '%s': %w", id, err)
+		}
+		if kr.ActiveKeyID == "" {
+			kr.ActiveKeyID = id
+		}
+		kr.DataKeys[id] = key
+	}
+	if active := strings.TrimSpace(os.Getenv("PII_ACTIVE_KEY_ID")); active != "" {
+		kr.ActiveKeyID = active
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(os.Getenv("PII_INDEX_KEY")))
+	if err != nil {
+		return kr, fmt.Errorf("pii: invalid base64 for PII_INDEX_KEY: %w", err)
+	}
+	kr.IndexKey = indexKey
+
+	return kr, nil
+}
+
+// NewProtectorFromEnv adalah shortcut NewProtector(EnvKeyProvider{}).
+func NewProtectorFromEnv() (*Protector, error) {
+	return NewProtector(EnvKeyProvider{})
+}
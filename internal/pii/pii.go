@@ -0,0 +1,239 @@
+// internal/pii/pii.go
+package pii
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Paket pii menyediakan enkripsi tingkat aplikasi (AES-256-GCM) untuk kolom data
+// pribadi (email, nomor telepon, nomor identitas), beserta "blind index" (HMAC-SHA256)
+// agar kolom terenkripsi tetap bisa dicari dengan pencocokan persis (equality lookup)
+// dan dijaga keunikannya di database.
+//
+// Format ciphertext yang disimpan di database:
+//
+//	enc:<key-id>:<base64(nonce || ciphertext)>
+//
+// key-id ikut disimpan sehingga rotasi kunci bisa dilakukan tanpa migrasi massal:
+// data lama tetap bisa didekripsi dengan kunci lama, data baru memakai kunci aktif.
+// Nilai tanpa prefix "enc:" dianggap plaintext lama (sebelum enkripsi diaktifkan)
+// dan dikembalikan apa adanya.
+
+const ciphertextPrefix = "enc:"
+
+var (
+	ErrNoActiveKey      = errors.New("pii: active encryption key not configured")
+	ErrUnknownKeyID     = errors.New("pii: unknown encryption key id")
+	ErrMalformedCipher  = errors.New("pii: malformed ciphertext")
+	ErrInvalidKeyLength = errors.New("pii: encryption key must be 32 bytes (AES-256)")
+	ErrMissingIndexKey  = errors.New("pii: blind index key not configured")
+)
+
+// Keyring berisi kunci-kunci yang dipakai Protector.
+type Keyring struct {
+	ActiveKeyID string            // ID kunci yang dipakai untuk enkripsi data baru.
+	DataKeys    map[string][]byte // Semua kunci (aktif + lama) untuk dekripsi, per ID.
+	IndexKey    []byte            // Kunci HMAC untuk blind index (tidak dirotasi; rotasi butuh re-index).
+}
+
+// KeyProvider adalah sumber kunci. Implementasi default membaca dari env var
+// (lihat EnvKeyProvider); implementasi lain bisa mengambil/membuka (unwrap)
+// data key dari KMS atau secret manager saat startup.
+type KeyProvider interface {
+	Keyring() (Keyring, error)
+}
+
+// Protector mengenkripsi/dekripsi nilai PII dan menghitung blind index.
+// Aman dipakai bersamaan dari banyak goroutine.
+type Protector struct {
+	activeID string
+	aeads    map[string]cipher.AEAD
+	indexKey []byte
+}
+
+// NewProtector membuat Protector dari KeyProvider.
+func NewProtector(provider KeyProvider) (*Protector, error) {
+	kr, err := provider.Keyring()
+	if err != nil {
+		return nil, err
+	}
+	if kr.ActiveKeyID == "" {
+		return nil, ErrNoActiveKey
+	}
+	if _, ok := kr.DataKeys[kr.ActiveKeyID]; !ok {
+		return nil, fmt.Errorf("%w: active key '%s' not in keyring", ErrNoActiveKey, kr.ActiveKeyID)
+	}
+	if len(kr.IndexKey) == 0 {
+		return nil, ErrMissingIndexKey
+	}
+
+	p := &Protector{activeID: kr.ActiveKeyID, aeads: make(map[string]cipher.AEAD, len(kr.DataKeys)), indexKey: kr.IndexKey}
+	for id, key := range kr.DataKeys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("pii: key id '%s' must not contain ':'", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("%w (key id '%s')", ErrInvalidKeyLength, id)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("pii: error creating cipher for key '%s': %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("pii: error creating GCM for key '%s': %w", id, err)
+		}
+		p.aeads[id] = aead
+	}
+	return p, nil
+}
+
+// Encrypt mengenkripsi plaintext dengan kunci aktif. String kosong tidak dienkripsi.
+func (p *Protector) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	aead := p.aeads[p.activeID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("pii: error generating nonce: %w", err)
+	}
+	// key-id dijadikan additional data agar ciphertext tidak bisa "dipindah" ke key-id lain.
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(p.activeID))
+	return ciphertextPrefix + p.activeID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// EncryptBytes mengenkripsi data biner (mis. file foto) dengan kunci aktif. Formatnya
+// "enc:<key-id>:" diikuti nonce || ciphertext (tanpa base64), sehingga rotasi kunci berlaku
+// sama seperti untuk kolom teks.
+func (p *Protector) EncryptBytes(plaintext []byte) ([]byte, error) {
+	aead := p.aeads[p.activeID]
+	header := []byte(ciphertextPrefix + p.activeID + ":")
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("pii: error generating nonce: %w", err)
+	}
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, plaintext, []byte(p.activeID)), nil
+}
+
+// DecryptBytes mengembalikan plaintext dari hasil EncryptBytes.
+func (p *Protector) DecryptBytes(sealed []byte) ([]byte, error) {
+	rest, ok := bytes.CutPrefix(sealed, []byte(ciphertextPrefix))
+	if !ok {
+		return nil, ErrMalformedCipher
+	}
+	id, raw, ok := bytes.Cut(rest, []byte(":"))
+	if !ok {
+		return nil, ErrMalformedCipher
+	}
+	aead, ok := p.aeads[string(id)]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownKeyID, id)
+	}
+	if len(raw) < aead.NonceSize() {
+		return nil, ErrMalformedCipher
+	}
+	plain, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], id)
+	if err != nil {
+		return nil, fmt.Errorf("pii: error decrypting data: %w", err)
+	}
+	return plain, nil
+}
+
+// EncryptPtr adalah varian Encrypt untuk kolom nullable.
+func (p *Protector) EncryptPtr(plaintext *string) (*string, error) {
+	if plaintext == nil || *plaintext == "" {
+		return nil, nil
+	}
+	enc, err := p.Encrypt(*plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return &enc, nil
+}
+
+// Decrypt mengembalikan plaintext dari nilai tersimpan.
+// Nilai yang bukan ciphertext (data lama) dikembalikan apa adanya.
+func (p *Protector) Decrypt(stored string) (string, error) {
+	if !IsEncrypted(stored) {
+		return stored, nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(stored, ciphertextPrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", ErrMalformedCipher
+	}
+	aead, ok := p.aeads[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%w '%s'", ErrUnknownKeyID, parts[0])
+	}
+	raw, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil || len(raw) < aead.NonceSize() {
+		return "", ErrMalformedCipher
+	}
+	nonce, ct := raw[:aead.NonceSize()], raw[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ct, []byte(parts[0]))
+	if err != nil {
+		return "", fmt.Errorf("pii: error decrypting value: %w", err)
+	}
+	return string(plain), nil
+}
+
+// DecryptPtr adalah varian Decrypt untuk kolom nullable.
+func (p *Protector) DecryptPtr(stored *string) (*string, error) {
+	if stored == nil {
+		return nil, nil
+	}
+	plain, err := p.Decrypt(*stored)
+	if err != nil {
+		return nil, err
+	}
+	return &plain, nil
+}
+
+// NeedsReencrypt mengembalikan true jika nilai tersimpan masih plaintext
+// atau dienkripsi dengan kunci yang bukan kunci aktif.
+func (p *Protector) NeedsReencrypt(stored string) bool {
+	if stored == "" {
+		return false
+	}
+	return !strings.HasPrefix(stored, ciphertextPrefix+p.activeID+":")
+}
+
+// BlindIndex menghasilkan HMAC-SHA256 (hex) dari nilai yang sudah dinormalisasi
+// (trim + lowercase), untuk kolom *_hash yang dipakai pencarian dan constraint UNIQUE.
+// String kosong menghasilkan string kosong.
+func (p *Protector) BlindIndex(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if normalized == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, p.indexKey)
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// BlindIndexPtr adalah varian BlindIndex untuk kolom nullable.
+func (p *Protector) BlindIndexPtr(value *string) *string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil
+	}
+	h := p.BlindIndex(*value)
+	return &h
+}
+
+// IsEncrypted mengecek apakah nilai tersimpan berformat ciphertext pii.
+func IsEncrypted(stored string) bool {
+	return strings.HasPrefix(stored, ciphertextPrefix)
+}
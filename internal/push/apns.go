@@ -0,0 +1,147 @@
+// internal/push/apns.go
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+const (
+	apnsProductionURL = "https://api.push.apple.com"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com"
+	// APNs menolak provider token yang berumur lebih dari 1 jam dan membatasi frekuensi
+	// pembaruan, sehingga token dipakai ulang dan diperbarui tiap 50 menit.
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// apnsSender mengirim lewat APNs HTTP/2 API dengan provider token (kunci .p8, ES256).
+type apnsSender struct {
+	baseURL string
+	keyID   string
+	teamID  string
+	topic   string
+	key     *ecdsa.PrivateKey
+	client  *http.Client
+
+	mu       sync.Mutex
+	jwtToken string
+	issuedAt time.Time
+}
+
+func newAPNsSender(keyFile, keyID, teamID, topic string, sandbox bool) (*apnsSender, error) {
+	if keyID == "" || teamID == "" || topic == "" {
+		return nil, fmt.Errorf("APNS_KEY_ID, APNS_TEAM_ID and APNS_TOPIC must be set when APNS_KEY_FILE is set")
+	}
+	raw, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading APNS_KEY_FILE: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing APNs signing key: %w", err)
+	}
+	baseURL := apnsProductionURL
+	if sandbox {
+		baseURL = apnsSandboxURL
+	}
+	// Transport default net/http memakai HTTP/2 untuk koneksi TLS, sesuai syarat APNs.
+	return &apnsSender{
+		baseURL: baseURL, keyID: keyID, teamID: teamID, topic: topic, key: key,
+		client: &http.Client{},
+	}, nil
+}
+
+func (s *apnsSender) Platform() string {
+	return models.DevicePlatformAPNs
+}
+
+func (s *apnsSender) providerToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jwtToken != "" && time.Since(s.issuedAt) < apnsTokenTTL {
+		return s.jwtToken, nil
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": s.teamID, "iat": now.Unix()})
+	token.Header["kid"] = s.keyID
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("error signing APNs provider token: %w", err)
+	}
+	s.jwtToken, s.issuedAt = signed, now
+	return signed, nil
+}
+
+func (s *apnsSender) Send(ctx context.Context, token string, msg Message) error {
+	providerToken, err := s.providerToken()
+	if err != nil {
+		return err
+	}
+	body := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+			"sound": "default",
+		},
+	}
+	for k, v := range msg.Data {
+		if k != "aps" {
+			body[k] = v
+		}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error encoding APNs payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/3/device/"+url.PathEscape(token), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error building APNs request: %w", err)
+	}
+	req.Header.Set("authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", s.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("content-type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return &RetryableError{Err: fmt.Errorf("error calling APNs: %w", err)}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var apnsErr struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&apnsErr)
+	switch apnsErr.Reason {
+	case "BadDeviceToken", "Unregistered", "DeviceTokenNotForTopic":
+		return ErrInvalidToken
+	case "ExpiredProviderToken":
+		s.mu.Lock()
+		s.jwtToken = ""
+		s.mu.Unlock()
+	}
+	err = fmt.Errorf("APNs returned status %d: %s", resp.StatusCode, apnsErr.Reason)
+	switch {
+	case resp.StatusCode == http.StatusGone:
+		return ErrInvalidToken
+	case apnsErr.Reason == "ExpiredProviderToken",
+		resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode >= 500:
+		return &RetryableError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,196 @@
+// internal/push/push.go
+
+// Package push mengirim push notification ke perangkat mobile (FCM untuk Android/web,
+// APNs untuk iOS) berdasarkan token perangkat yang didaftarkan user.
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/resilience"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// ErrInvalidToken dikembalikan Sender jika provider menyatakan token tidak lagi terdaftar
+// (aplikasi di-uninstall, token kedaluwarsa). Token tersebut dihapus dari database.
+var ErrInvalidToken = errors.New("device token is no longer valid")
+
+// RetryableError menandai kegagalan sementara (rate limit, 5xx, jaringan) yang layak dicoba ulang.
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// Message adalah isi push notification. Data dikirim sebagai payload tambahan agar aplikasi
+// bisa membuka layar yang relevan (mis. type=schedule_changed, schedule_id=12; lihat models.Notification*).
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Sender mengirim satu Message ke satu token perangkat pada satu platform.
+type Sender interface {
+	Send(ctx context.Context, token string, msg Message) error
+	Platform() string
+}
+
+// Service mengirim Message ke semua perangkat milik user dengan retry dan pembersihan token invalid.
+type Service struct {
+	devices     repository.DeviceRepository
+	senders     map[string]Sender
+	breakers    map[string]*resilience.Breaker // Satu circuit breaker per platform
+	maxAttempts int
+	backoff     time.Duration
+	timeout     time.Duration
+}
+
+// NewServiceFromEnv membuat Service berdasarkan environment variables. Mengembalikan nil
+// (push dinonaktifkan) jika tidak ada provider yang dikonfigurasi.
+//
+// Variabel Environment yang didukung:
+//   - PUSH_FCM_CREDENTIALS_FILE: Path JSON service account Firebase. Mengaktifkan FCM.
+//   - APNS_KEY_FILE / APNS_KEY_ID / APNS_TEAM_ID / APNS_TOPIC: Kunci .p8 dan identitas aplikasi iOS. Mengaktifkan APNs.
+//   - APNS_SANDBOX: true untuk gateway development APNs. Default: false.
+//   - PUSH_MAX_ATTEMPTS: Jumlah percobaan per token untuk kegagalan sementara. Default: 3.
+//   - PUSH_RETRY_BACKOFF: Jeda awal antar percobaan (dilipatgandakan tiap percobaan). Default: 1s.
+//   - PUSH_TIMEOUT: Batas waktu pengiriman ke semua perangkat seorang user. Default: 30s.
+func NewServiceFromEnv(devices repository.DeviceRepository) (*Service, error) {
+	var senders []Sender
+	if path := configs.GetEnv("PUSH_FCM_CREDENTIALS_FILE", ""); path != "" {
+		s, err := newFCMSender(path)
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, s)
+	}
+	if keyFile := configs.GetEnv("APNS_KEY_FILE", ""); keyFile != "" {
+		s, err := newAPNsSender(keyFile,
+			configs.GetEnv("APNS_KEY_ID", ""),
+			configs.GetEnv("APNS_TEAM_ID", ""),
+			configs.GetEnv("APNS_TOPIC", ""),
+			configs.GetEnvBool("APNS_SANDBOX", false))
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, s)
+	}
+	if len(senders) == 0 {
+		zlog.Info().Msg("Push notifications disabled (no FCM or APNs credentials configured)")
+		return nil, nil
+	}
+	maxAttempts := configs.GetEnvInt("PUSH_MAX_ATTEMPTS", 3)
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	platforms := make([]string, 0, len(senders))
+	for _, s := range senders {
+		platforms = append(platforms, s.Platform())
+	}
+	zlog.Info().Strs("platforms", platforms).Msg("Push notifications enabled")
+	return NewService(devices, maxAttempts,
+		configs.GetEnvDuration("PUSH_RETRY_BACKOFF", time.Second),
+		configs.GetEnvDuration("PUSH_TIMEOUT", 30*time.Second),
+		senders...), nil
+}
+
+// NewService membuat Service dengan Sender yang diberikan (satu per platform).
+func NewService(devices repository.DeviceRepository, maxAttempts int, backoff, timeout time.Duration, senders ...Sender) *Service {
+	s := &Service{devices: devices, senders: map[string]Sender{}, breakers: map[string]*resilience.Breaker{}, maxAttempts: maxAttempts, backoff: backoff, timeout: timeout}
+	for _, sender := range senders {
+		s.senders[sender.Platform()] = sender
+		s.breakers[sender.Platform()] = resilience.Get("push." + sender.Platform())
+	}
+	return s
+}
+
+// SendToUser mengirim msg ke semua perangkat user dan mengembalikan jumlah perangkat yang
+// berhasil dikirimi. Token yang ditolak provider dihapus; kegagalan per perangkat hanya dicatat.
+func (s *Service) SendToUser(ctx context.Context, userID int, msg Message) (int, error) {
+	tokens, err := s.devices.GetDeviceTokensByUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	logger := zlog.Ctx(ctx)
+	sent := 0
+	var invalid []string
+	for _, d := range tokens {
+		sender, ok := s.senders[d.Platform]
+		if !ok {
+			continue // Platform tidak dikonfigurasi di instance ini
+		}
+		err := s.sendWithRetry(ctx, sender, d, msg)
+		switch {
+		case err == nil:
+			sent++
+		case errors.Is(err, ErrInvalidToken):
+			invalid = append(invalid, d.Token)
+		default:
+			logger.Warn().Err(err).Int("user_id", userID).Int("device_id", d.ID).Str("platform", d.Platform).Msg("Failed to send push notification")
+		}
+	}
+	if len(invalid) > 0 {
+		removed, err := s.devices.DeleteDeviceTokens(ctx, invalid)
+		if err != nil {
+			logger.Error().Err(err).Int("user_id", userID).Msg("Failed to remove invalid device tokens")
+		} else {
+			logger.Info().Int("user_id", userID).Int("removed", removed).Msg("Removed device tokens rejected by push provider")
+		}
+	}
+	return sent, nil
+}
+
+// sendTimeout adalah batas waktu satu percobaan kirim ke satu perangkat.
+const sendTimeout = 10 * time.Second
+
+// sendWithRetry mengirim ke satu perangkat lewat circuit breaker platform-nya. Hanya
+// RetryableError yang dicoba ulang dan dihitung sebagai kegagalan breaker; token invalid
+// dan penolakan lain berarti provider sehat.
+func (s *Service) sendWithRetry(ctx context.Context, sender Sender, d models.DeviceToken, msg Message) error {
+	policy := resilience.Policy{
+		Timeout:     sendTimeout,
+		MaxAttempts: s.maxAttempts,
+		BaseDelay:   s.backoff,
+		MaxDelay:    s.timeout,
+		Retryable: func(err error) bool {
+			var retryable *RetryableError
+			return errors.As(err, &retryable)
+		},
+	}
+	err := resilience.Do(ctx, s.breakers[sender.Platform()], policy, func(ctx context.Context) error {
+		return sender.Send(ctx, d.Token, msg)
+	})
+	if err != nil && !errors.Is(err, ErrInvalidToken) {
+		return fmt.Errorf("%s push failed: %w", sender.Platform(), err)
+	}
+	return err
+}
+
+// NotifyUser mengirim msg ke perangkat user di background agar request tidak menunggu
+// provider push. Aman dipanggil pada Service nil (push dinonaktifkan).
+func (s *Service) NotifyUser(ctx context.Context, userID int, msg Message) {
+	if s == nil {
+		return
+	}
+	sendCtx := context.WithoutCancel(ctx) // Tetap membawa logger request
+	go func() {
+		ctx, cancel := context.WithTimeout(sendCtx, s.timeout)
+		defer cancel()
+		if _, err := s.SendToUser(ctx, userID, msg); err != nil {
+			zlog.Ctx(ctx).Error().Err(err).Int("user_id", userID).Msg("Failed to send push notification")
+		}
+	}()
+}
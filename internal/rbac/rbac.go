@@ -0,0 +1,106 @@
+// internal/rbac/rbac.go
+
+// Package rbac menyelesaikan role efektif dari hierarki role (roles.parent_id). Role mewarisi
+// hak akses parent-nya secara berantai: dengan Admin -> Manager -> Supervisor, user ber-role
+// Admin juga lolos pemeriksaan role Manager dan Supervisor. Rantai per role di-cache singkat
+// agar middleware Authorize tidak membaca database di setiap request.
+package rbac
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/configs"
+)
+
+// HierarchyStore membaca rantai role yang diwarisi (dipenuhi repository.RoleRepository).
+type HierarchyStore interface {
+	GetInheritedRoles(ctx context.Context, roleName string) ([]string, error)
+}
+
+type cachedRoles struct {
+	effective []string
+	expiresAt time.Time
+}
+
+// Resolver menyimpan role efektif per nama role selama ttl. Perubahan hierarki dari instance
+// ini langsung berlaku (Invalidate); instance lain melihatnya paling lambat setelah ttl.
+type Resolver struct {
+	store   HierarchyStore
+	ttl     time.Duration
+	stale   func(err error) bool // Opsional; lihat ServeStaleWhen
+	mu      sync.Mutex
+	entries map[string]cachedRoles
+}
+
+// NewResolver membuat resolver dengan ttl tertentu (0 = selalu membaca database).
+func NewResolver(store HierarchyStore, ttl time.Duration) *Resolver {
+	return &Resolver{store: store, ttl: ttl, entries: make(map[string]cachedRoles)}
+}
+
+// NewResolverFromEnv membuat Resolver dengan ROLE_HIERARCHY_CACHE_TTL (default 60s).
+func NewResolverFromEnv(store HierarchyStore) *Resolver {
+	return NewResolver(store, configs.GetEnvDuration("ROLE_HIERARCHY_CACHE_TTL", time.Minute))
+}
+
+// EffectiveRoles mengembalikan role sendiri diikuti role yang diwarisinya (terdekat dulu).
+// Role yang tidak ada di database hanya menghasilkan dirinya sendiri.
+func (r *Resolver) EffectiveRoles(ctx context.Context, role string) ([]string, error) {
+	now := time.Now()
+	r.mu.Lock()
+	entry, ok := r.entries[role]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.effective, nil
+	}
+
+	inherited, err := r.store.GetInheritedRoles(ctx, role)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		if ok && r.stale != nil && r.stale(err) {
+			return entry.effective, nil
+		}
+		return nil, err
+	}
+	effective := append([]string{role}, inherited...)
+	if r.ttl > 0 {
+		r.mu.Lock()
+		r.entries[role] = cachedRoles{effective: effective, expiresAt: now.Add(r.ttl)}
+		r.mu.Unlock()
+	}
+	return effective, nil
+}
+
+// Allows melaporkan apakah role (langsung atau lewat pewarisan) termasuk salah satu allowed.
+// Nama role dibandingkan tanpa membedakan huruf besar/kecil.
+func (r *Resolver) Allows(ctx context.Context, role string, allowed ...string) (bool, error) {
+	effective, err := r.EffectiveRoles(ctx, role)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(effective, func(name string) bool {
+		return slices.ContainsFunc(allowed, func(a string) bool { return strings.EqualFold(name, a) })
+	}), nil
+}
+
+// ServeStaleWhen membuat EffectiveRoles mengembalikan rantai yang sudah kedaluwarsa di cache
+// (jika ada) saat pembacaan database gagal dengan error yang diterima fn, mis.
+// degraded.Controller.ReportError. Dipanggil sebelum server menerima request.
+func (r *Resolver) ServeStaleWhen(fn func(err error) bool) {
+	r.stale = fn
+}
+
+// Invalidate membuang seluruh cache (panggil setelah hierarki atau nama role berubah; satu
+// perubahan parent bisa memengaruhi semua role turunannya). Aman dipanggil pada Resolver nil.
+func (r *Resolver) Invalidate() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	clear(r.entries)
+	r.mu.Unlock()
+}
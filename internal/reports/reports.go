@@ -0,0 +1,221 @@
+// internal/reports/reports.go
+
+// Package reports membuat file laporan (ekspor CSV/XLSX) di background, menyimpannya di
+// storage, dan menghapusnya setelah masa retensi. Isi tiap jenis laporan ditulis oleh Renderer
+// yang didaftarkan pemilik datanya (mis. handler admin untuk laporan users).
+package reports
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/export"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/storage"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Renderer menulis seluruh isi satu laporan (termasuk header) ke w dan mengembalikan jumlah
+// baris data yang ditulis. Parameter laporan ada di report.Params.
+type Renderer func(ctx context.Context, report *models.ReportExport, w export.Writer) (int, error)
+
+// Service membuat dan membersihkan file ekspor laporan.
+type Service struct {
+	repo      repository.ReportExportRepository
+	store     storage.Storage
+	retention time.Duration // Retensi default jika permintaan tidak menentukannya
+
+	mu        sync.RWMutex
+	renderers map[string]Renderer
+}
+
+// NewService membuat Service dengan pengaturan eksplisit.
+func NewService(repo repository.ReportExportRepository, store storage.Storage, retention time.Duration) *Service {
+	return &Service{repo: repo, store: store, retention: retention, renderers: map[string]Renderer{}}
+}
+
+// NewServiceFromEnv membuat Service berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - REPORT_EXPORT_RETENTION: Umur default file ekspor sejak selesai dibuat. Default: 168h (7 hari).
+func NewServiceFromEnv(repo repository.ReportExportRepository, store storage.Storage) (*Service, error) {
+	retention := configs.GetEnvDuration("REPORT_EXPORT_RETENTION", 7*24*time.Hour)
+	if retention <= 0 || retention > models.ReportExportMaxRetentionHours*time.Hour {
+		return nil, fmt.Errorf("REPORT_EXPORT_RETENTION must be between 1s and %dh", models.ReportExportMaxRetentionHours)
+	}
+	return NewService(repo, store, retention), nil
+}
+
+// Register mendaftarkan Renderer untuk satu jenis laporan. Aman dipanggil setelah job berjalan;
+// laporan tanpa Renderer tetap pending sampai didaftarkan.
+func (s *Service) Register(reportType string, render Renderer) {
+	s.mu.Lock()
+	s.renderers[reportType] = render
+	s.mu.Unlock()
+}
+
+// DefaultRetention mengembalikan retensi file ekspor jika permintaan tidak menentukannya.
+func (s *Service) DefaultRetention() time.Duration {
+	return s.retention
+}
+
+func (s *Service) renderer(reportType string) Renderer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.renderers[reportType]
+}
+
+// Generate membuat maksimal batch ekspor pending dan mengembalikan jumlah yang selesai (berhasil
+// maupun gagal). Kegagalan menulis isi laporan ditandai failed; error storage atau database
+// menghentikan putaran agar dicoba lagi nanti.
+func (s *Service) Generate(ctx context.Context, batch int) (int, error) {
+	pending, err := s.repo.GetPendingReportExports(ctx, batch)
+	if err != nil {
+		return 0, err
+	}
+	done := 0
+	for i := range pending {
+		if err := ctx.Err(); err != nil {
+			return done, err
+		}
+		report := &pending[i]
+		render := s.renderer(report.ReportType)
+		if render == nil {
+			zlog.Warn().Int("report_export_id", report.ID).Str("report_type", report.ReportType).Msg("No renderer registered for report type")
+			continue
+		}
+		if err := s.generateOne(ctx, report, render); err != nil {
+			return done, err
+		}
+		done++
+	}
+	return done, nil
+}
+
+func (s *Service) generateOne(ctx context.Context, report *models.ReportExport, render Renderer) error {
+	contentType, ok := export.ContentType(report.Format)
+	if !ok {
+		return s.repo.MarkReportExportFailed(ctx, report.ID, fmt.Sprintf("unsupported format %q", report.Format))
+	}
+
+	// File ditulis ke disk dulu agar laporan besar tidak ditampung di memori.
+	tmp, err := os.CreateTemp("", "report-export-*")
+	if err != nil {
+		return fmt.Errorf("error creating temp file for report export %d: %w", report.ID, err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	rows, err := s.render(ctx, report, render, tmp)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		zlog.Warn().Err(err).Int("report_export_id", report.ID).Msg("Report export failed")
+		return s.repo.MarkReportExportFailed(ctx, report.ID, err.Error())
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("error sizing report export %d: %w", report.ID, err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error rewinding report export %d: %w", report.ID, err)
+	}
+
+	now := time.Now()
+	key, err := newReportKey(now, report.Format)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Put(ctx, key, tmp, contentType); err != nil {
+		return err
+	}
+	result := models.ReportExportResult{
+		StorageKey:  key,
+		FileName:    fmt.Sprintf("%s-%s.%s", report.ReportType, report.CreatedAt.UTC().Format("20060102-150405"), report.Format),
+		ContentType: contentType,
+		SizeBytes:   size,
+		RowCount:    rows,
+	}
+	if _, err := s.repo.MarkReportExportCompleted(ctx, report.ID, result); err != nil {
+		s.deleteQuietly(ctx, key)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Sudah diproses instance lain
+		}
+		return err
+	}
+	zlog.Info().Int("report_export_id", report.ID).Str("report_type", report.ReportType).Int("rows", rows).Int64("size_bytes", size).Msg("Report export completed")
+	return nil
+}
+
+// render menulis laporan ke w memakai writer sesuai format.
+func (s *Service) render(ctx context.Context, report *models.ReportExport, render Renderer, w io.Writer) (int, error) {
+	writer, err := export.NewWriter(report.Format, w, "Report")
+	if err != nil {
+		return 0, err
+	}
+	rows, err := render(ctx, report, writer)
+	if err != nil {
+		return rows, err
+	}
+	return rows, writer.Close()
+}
+
+// Cleanup menghapus file ekspor yang melewati expires_at (maksimal batch) dan mengembalikan
+// jumlah yang dihapus. Barisnya tetap ada dengan status purged; unduhan sesudahnya dijawab 410.
+func (s *Service) Cleanup(ctx context.Context, batch int) (int, error) {
+	expired, err := s.repo.GetExpiredReportExports(ctx, time.Now(), batch)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, report := range expired {
+		if report.StorageKey != nil {
+			if err := s.store.Delete(ctx, *report.StorageKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return purged, fmt.Errorf("error deleting report export %d: %w", report.ID, err)
+			}
+		}
+		if err := s.repo.MarkReportExportPurged(ctx, report.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// deleteQuietly menghapus file yang tidak lagi dirujuk; kegagalannya hanya dicatat.
+func (s *Service) deleteQuietly(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		zlog.Warn().Err(err).Str("key", key).Msg("Failed to delete report export file")
+	}
+}
+
+// newReportKey membuat storage key acak di bawah reports/YYYY/MM.
+func newReportKey(now time.Time, format string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("reports/%s/%s.%s", now.UTC().Format("2006/01"), hex.EncodeToString(b), format), nil
+}
+
+// Expired melaporkan apakah file ekspor sudah tidak bisa diunduh karena masa retensinya lewat
+// (termasuk yang sudah dihapus job cleanup).
+func Expired(report *models.ReportExport, now time.Time) bool {
+	if report.Status == models.ReportExportPurged {
+		return true
+	}
+	return report.Status == models.ReportExportCompleted && report.ExpiresAt != nil && !now.Before(*report.ExpiresAt)
+}
@@ -0,0 +1,93 @@
+// internal/reports/snapshot.go
+package reports
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/worktime"
+)
+
+// SummarizeSessions meringkas sesi absensi per user untuk snapshot laporan. sessions harus
+// membawa Attendance.User (username). Jam hanya dihitung dari sesi yang sudah check-out;
+// hari kerja adalah tanggal check-in berbeda pada zona waktu rules. Hasil urut username.
+func SummarizeSessions(sessions []models.Attendance, rules worktime.Rules) []models.ReportSnapshotRow {
+	loc := rules.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	byUser := map[int]*models.ReportSnapshotRow{}
+	days := map[int]map[string]bool{}
+	for _, s := range sessions {
+		row, ok := byUser[s.UserID]
+		if !ok {
+			row = &models.ReportSnapshotRow{UserID: s.UserID}
+			if s.User != nil {
+				row.Username = s.User.Username
+			}
+			byUser[s.UserID] = row
+			days[s.UserID] = map[string]bool{}
+		}
+		if s.CheckOutAt == nil {
+			row.OpenSessions++
+			continue
+		}
+		row.Sessions++
+		days[s.UserID][s.CheckInAt.In(loc).Format("2006-01-02")] = true
+		row.Hours = addHours(row.Hours, rules.Split(s.CheckInAt, *s.CheckOutAt))
+	}
+
+	rows := make([]models.ReportSnapshotRow, 0, len(byUser))
+	for userID, row := range byUser {
+		row.DaysWorked = len(days[userID])
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Username != rows[j].Username {
+			return rows[i].Username < rows[j].Username
+		}
+		return rows[i].UserID < rows[j].UserID
+	})
+	return rows
+}
+
+// SnapshotTotals menjumlahkan jam semua baris snapshot.
+func SnapshotTotals(rows []models.ReportSnapshotRow) models.HoursBreakdown {
+	var total models.HoursBreakdown
+	for _, row := range rows {
+		total = addHours(total, row.Hours)
+	}
+	return total
+}
+
+// SnapshotChecksum adalah SHA-256 (hex) dari JSON baris snapshot. Dipakai saat snapshot
+// dibuat dan dihitung ulang saat dibaca untuk mendeteksi perubahan isi.
+func SnapshotChecksum(rows []models.ReportSnapshotRow) (string, error) {
+	if rows == nil {
+		rows = []models.ReportSnapshotRow{}
+	}
+	raw, err := json.Marshal(rows)
+	if err != nil {
+		return "", fmt.Errorf("error encoding report snapshot rows: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// addHours menjumlahkan dua pembagian jam dan membulatkannya ke dua angka di belakang koma.
+func addHours(a, b models.HoursBreakdown) models.HoursBreakdown {
+	round := func(h float64) float64 { return math.Round(h*100) / 100 }
+	return models.HoursBreakdown{
+		RegularHours: round(a.RegularHours + b.RegularHours),
+		NightHours:   round(a.NightHours + b.NightHours),
+		WeekendHours: round(a.WeekendHours + b.WeekendHours),
+		HolidayHours: round(a.HolidayHours + b.HolidayHours),
+		TotalHours:   round(a.TotalHours + b.TotalHours),
+	}
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type announcementRepo struct {
+	db   Conn // Primary: tulis & baca konsisten
+	read Conn // Replica (atau Primary jika tidak ada) untuk listing
+}
+
+func NewAnnouncementRepository(pools Pools) AnnouncementRepository {
+	return &announcementRepo{db: pools.Primary, read: pools.reader()}
+}
+
+func (r *announcementRepo) CreateAnnouncement(ctx context.Context, a *models.Announcement) (int, error) {
+	query := `INSERT INTO announcements (title, body, publish_at, expires_at, audience_role_ids, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	var id int
+	err := r.db.QueryRow(ctx, query, a.Title, a.Body, a.PublishAt, a.ExpiresAt, shared.AudienceRoleIDs(a.AudienceRoleIDs), a.CreatedBy).Scan(&id)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error creating announcement")
+		return 0, fmt.Errorf("error creating announcement: %w", err)
+	}
+	return id, nil
+}
+
+func (r *announcementRepo) GetAnnouncementByID(ctx context.Context, id int) (*models.Announcement, error) {
+	query := `SELECT ` + shared.SelectList("an", shared.AnnouncementColumns) + ` FROM announcements an WHERE an.id = $1`
+	a := &models.Announcement{}
+	if err := shared.ScanAnnouncement(r.db.QueryRow(ctx, query, id), a); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("announcement_id", id).Msg("Error getting announcement by ID")
+		return nil, fmt.Errorf("error getting announcement by id %d: %w", id, err)
+	}
+	return a, nil
+}
+
+func (r *announcementRepo) GetAllAnnouncements(ctx context.Context, page, limit int) ([]models.Announcement, int, error) {
+	var total int
+	if err := r.read.QueryRow(ctx, `SELECT COUNT(*) FROM announcements`).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting announcements")
+		return nil, 0, fmt.Errorf("error counting announcements: %w", err)
+	}
+	if total == 0 {
+		return []models.Announcement{}, 0, nil
+	}
+
+	query := `SELECT ` + shared.SelectList("an", shared.AnnouncementColumns) + `
+              FROM announcements an
+              ORDER BY an.publish_at DESC, an.id DESC
+              LIMIT $1 OFFSET $2`
+	announcements, err := r.queryAnnouncements(ctx, query, limit, shared.PageOffset(page, limit))
+	return announcements, total, err
+}
+
+// GetActiveAnnouncementsForUser mengembalikan pengumuman yang sedang tayang pada waktu at
+// dan ditujukan ke role user (atau ke semua user).
+func (r *announcementRepo) GetActiveAnnouncementsForUser(ctx context.Context, userID int, at time.Time, page, limit int) ([]models.Announcement, int, error) {
+	where := `
+              WHERE an.publish_at <= $1
+                AND (an.expires_at IS NULL OR an.expires_at > $1)
+                AND (cardinality(an.audience_role_ids) = 0
+                     OR (SELECT u.role_id FROM users u WHERE u.id = $2) = ANY(an.audience_role_ids))`
+
+	var total int
+	if err := r.read.QueryRow(ctx, `SELECT COUNT(*) FROM announcements an`+where, at, userID).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error counting active announcements")
+		return nil, 0, fmt.Errorf("error counting active announcements: %w", err)
+	}
+	if total == 0 {
+		return []models.Announcement{}, 0, nil
+	}
+
+	query := `SELECT ` + shared.SelectList("an", shared.AnnouncementColumns) + `
+              FROM announcements an` + where + `
+              ORDER BY an.publish_at DESC, an.id DESC
+              LIMIT $3 OFFSET $4`
+	announcements, err := r.queryAnnouncements(ctx, query, at, userID, limit, shared.PageOffset(page, limit))
+	return announcements, total, err
+}
+
+func (r *announcementRepo) queryAnnouncements(ctx context.Context, query string, args ...any) ([]models.Announcement, error) {
+	rows, err := r.read.Query(ctx, query, args...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying announcements")
+		return nil, fmt.Errorf("error querying announcements: %w", err)
+	}
+	defer rows.Close()
+
+	announcements := []models.Announcement{}
+	for rows.Next() {
+		var a models.Announcement
+		if err := shared.ScanAnnouncement(rows, &a); err != nil {
+			shared.Logger(ctx).Error().Err(err).Msg("Error scanning announcement row")
+			return nil, fmt.Errorf("error scanning announcement row: %w", err)
+		}
+		announcements = append(announcements, a)
+	}
+	if err := rows.Err(); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error iterating announcement rows")
+		return nil, fmt.Errorf("error iterating announcement rows: %w", err)
+	}
+	return announcements, nil
+}
+
+func (r *announcementRepo) UpdateAnnouncement(ctx context.Context, a *models.Announcement) error {
+	query := `UPDATE announcements
+              SET title = $1, body = $2, publish_at = $3, expires_at = $4, audience_role_ids = $5, updated_at = CURRENT_TIMESTAMP
+              WHERE id = $6`
+	tag, err := r.db.Exec(ctx, query, a.Title, a.Body, a.PublishAt, a.ExpiresAt, shared.AudienceRoleIDs(a.AudienceRoleIDs), a.ID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("announcement_id", a.ID).Msg("Error updating announcement")
+		return fmt.Errorf("error updating announcement id %d: %w", a.ID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *announcementRepo) DeleteAnnouncement(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM announcements WHERE id = $1`, id)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("announcement_id", id).Msg("Error deleting announcement")
+		return fmt.Errorf("error deleting announcement id %d: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
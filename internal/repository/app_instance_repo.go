@@ -0,0 +1,79 @@
+// internal/repository/app_instance_repo.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type appInstanceRepo struct {
+	db Conn // Primary: heartbeat ditulis dan dibaca runner migrasi tanpa lag replika
+}
+
+func NewAppInstanceRepository(pools Pools) AppInstanceRepository {
+	return &appInstanceRepo{db: pools.Primary}
+}
+
+// UpsertAppInstance mencatat heartbeat instance; started_at hanya diisi saat pertama kali.
+func (r *appInstanceRepo) UpsertAppInstance(ctx context.Context, instance *models.AppInstance) error {
+	query := `INSERT INTO app_instances AS i (instance_id, app_version, hostname)
+              VALUES ($1, $2, $3)
+              ON CONFLICT (instance_id) DO UPDATE
+              SET app_version = EXCLUDED.app_version, hostname = EXCLUDED.hostname, last_seen_at = CURRENT_TIMESTAMP
+              RETURNING ` + shared.SelectList("i", shared.AppInstanceColumns)
+	if err := shared.ScanAppInstance(r.db.QueryRow(ctx, query, instance.InstanceID, instance.AppVersion, instance.Hostname), instance); err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("instance_id", instance.InstanceID).Msg("Error recording app instance heartbeat")
+		return fmt.Errorf("error recording heartbeat of instance %s: %w", instance.InstanceID, err)
+	}
+	return nil
+}
+
+// DeleteAppInstance menghapus instance yang berhenti dengan normal.
+func (r *appInstanceRepo) DeleteAppInstance(ctx context.Context, instanceID string) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM app_instances WHERE instance_id = $1`, instanceID); err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("instance_id", instanceID).Msg("Error deleting app instance")
+		return fmt.Errorf("error deleting instance %s: %w", instanceID, err)
+	}
+	return nil
+}
+
+// GetAppInstances mengembalikan instance yang heartbeat terakhirnya sejak seenSince.
+func (r *appInstanceRepo) GetAppInstances(ctx context.Context, seenSince time.Time) ([]models.AppInstance, error) {
+	query := `SELECT ` + shared.SelectList("i", shared.AppInstanceColumns) + `
+              FROM app_instances i
+              WHERE i.last_seen_at >= $1
+              ORDER BY i.app_version, i.started_at, i.instance_id`
+	rows, err := r.db.Query(ctx, query, seenSince)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying app instances")
+		return nil, fmt.Errorf("error getting app instances: %w", err)
+	}
+	defer rows.Close()
+	instances := []models.AppInstance{}
+	for rows.Next() {
+		var i models.AppInstance
+		if err := shared.ScanAppInstance(rows, &i); err != nil {
+			return nil, fmt.Errorf("error scanning app instance row: %w", err)
+		}
+		instances = append(instances, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating app instance rows: %w", err)
+	}
+	return instances, nil
+}
+
+// DeleteStaleAppInstances menghapus instance yang berhenti tanpa sempat menghapus dirinya
+// (crash, SIGKILL).
+func (r *appInstanceRepo) DeleteStaleAppInstances(ctx context.Context, seenBefore time.Time) (int, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM app_instances WHERE last_seen_at < $1`, seenBefore)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error deleting stale app instances")
+		return 0, fmt.Errorf("error deleting stale app instances: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,103 @@
+// internal/repository/attendance_failures.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// maxQueuedCheckInErrorLength membatasi panjang alasan gagal yang disimpan.
+const maxQueuedCheckInErrorLength = 1000
+
+// Dead letter check-in tertunda (queued_check_in_failures): punch yang sudah dijawab 202 saat
+// mode degraded/maintenance tetapi gagal dicatat saat dikirim ulang. Baris tanpa resolved_at
+// menunggu tindak lanjut admin.
+
+// RecordQueuedCheckInFailure menyimpan punch yang gagal dicatat (ikut transaksi RunInTx jika ada).
+// Mengisi ID dan FailedAt.
+func (r *attendanceRepo) RecordQueuedCheckInFailure(ctx context.Context, f *models.QueuedCheckInFailure) error {
+	if len(f.Error) > maxQueuedCheckInErrorLength {
+		f.Error = f.Error[:maxQueuedCheckInErrorLength]
+	}
+	if f.Tags == nil {
+		f.Tags = []string{}
+	}
+	query := `INSERT INTO queued_check_in_failures (user_id, check_in_at, notes, tags, project_id, mode, source, error)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+              RETURNING id, failed_at`
+	err := conn(ctx, r.db).QueryRow(ctx, query, f.UserID, f.CheckInAt, f.Notes, f.Tags, f.ProjectID, f.Mode, f.Source, f.Error).
+		Scan(&f.ID, &f.FailedAt)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", f.UserID).Time("check_in_at", f.CheckInAt).Msg("Error recording queued check-in failure")
+		return fmt.Errorf("error recording queued check-in failure for user id %d: %w", f.UserID, err)
+	}
+	return nil
+}
+
+// GetQueuedCheckInFailures mengembalikan dead letter check-in (terbaru lebih dulu) beserta user.
+// resolved nil = semua; false = yang belum ditindaklanjuti; true = yang sudah.
+func (r *attendanceRepo) GetQueuedCheckInFailures(ctx context.Context, resolved *bool, page, limit int) (failures []models.QueuedCheckInFailure, totalCount int, err error) {
+	filter := `$1::boolean IS NULL OR (f.resolved_at IS NOT NULL) = $1`
+	countQuery := `SELECT COUNT(*) FROM queued_check_in_failures f WHERE ` + filter
+	if err = r.read.QueryRow(ctx, countQuery, resolved).Scan(&totalCount); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting queued check-in failures")
+		err = fmt.Errorf("error counting queued check-in failures: %w", err)
+		return
+	}
+	failures = []models.QueuedCheckInFailure{}
+	if totalCount == 0 {
+		return
+	}
+
+	query := `
+        SELECT ` + shared.SelectList("f", shared.QueuedCheckInFailureColumns) + `,
+               ` + shared.SelectList("u", userSummaryColumns) + `
+        FROM queued_check_in_failures f
+        JOIN users u ON u.id = f.user_id
+        WHERE ` + filter + `
+        ORDER BY f.failed_at DESC, f.id DESC
+        LIMIT $2 OFFSET $3`
+	rows, err := r.read.Query(ctx, query, resolved, limit, max(page-1, 0)*limit)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying queued check-in failures")
+		err = fmt.Errorf("error getting queued check-in failures: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		f := models.QueuedCheckInFailure{User: &models.User{}}
+		if err = rows.Scan(append(shared.QueuedCheckInFailureDest(&f), shared.UserSummaryDest(f.User)...)...); err != nil {
+			err = fmt.Errorf("error scanning queued check-in failure row: %w", err)
+			return
+		}
+		if err = shared.DecryptUserPII(r.pii, f.User); err != nil {
+			return
+		}
+		failures = append(failures, f)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("error iterating queued check-in failure rows: %w", err)
+	}
+	return
+}
+
+// ResolveQueuedCheckInFailure menandai dead letter sudah ditindaklanjuti admin.
+// pgx.ErrNoRows jika tidak ada atau sudah ditandai sebelumnya.
+func (r *attendanceRepo) ResolveQueuedCheckInFailure(ctx context.Context, id, adminID int, note string) (*models.QueuedCheckInFailure, error) {
+	query := `UPDATE queued_check_in_failures
+              SET resolved_by = $2, resolved_at = $3, resolution_note = $4
+              WHERE id = $1 AND resolved_at IS NULL
+              RETURNING ` + shared.SelectList("queued_check_in_failures", shared.QueuedCheckInFailureColumns)
+	f := &models.QueuedCheckInFailure{}
+	if err := r.db.QueryRow(ctx, query, id, adminID, time.Now(), note).Scan(shared.QueuedCheckInFailureDest(f)...); err != nil {
+		shared.Logger(ctx).Warn().Err(err).Int("failure_id", id).Msg("Error resolving queued check-in failure")
+		return nil, fmt.Errorf("error resolving queued check-in failure id %d: %w", id, err)
+	}
+	shared.Logger(ctx).Info().Int("failure_id", id).Int("admin_id", adminID).Msg("Queued check-in failure resolved")
+	return f, nil
+}
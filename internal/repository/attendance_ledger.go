@@ -0,0 +1,40 @@
+// internal/repository/attendance_ledger.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Helper ledger absensi (attendance_events) yang dipakai attendanceRepo.
+// Alur setiap perubahan: kunci record -> catat event (snapshot lengkap) -> perbarui proyeksi,
+// semuanya di dalam satu transaksi. Ledger dijaga append-only oleh trigger database.
+
+// ErrInvalidAttendanceTimes dikembalikan jika koreksi membuat check_out_at < check_in_at.
+var ErrInvalidAttendanceTimes = errors.New("check_out_at cannot be before check_in_at")
+
+// lockAttendance mengambil record absensi dengan SELECT ... FOR UPDATE.
+// Mengembalikan pgx.ErrNoRows jika record tidak ada.
+func lockAttendance(ctx context.Context, tx pgx.Tx, attendanceID int) (*models.Attendance, error) {
+	query := `SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + ` FROM attendances a WHERE a.id = $1 FOR UPDATE`
+	att := &models.Attendance{}
+	if err := shared.ScanAttendance(tx.QueryRow(ctx, query, attendanceID), att); err != nil {
+		return nil, err
+	}
+	return att, nil
+}
+
+// projectAttendance menyalin snapshot terbaru ke baris proyeksi di tabel attendances.
+func projectAttendance(ctx context.Context, tx pgx.Tx, att *models.Attendance) error {
+	query := `UPDATE attendances SET check_in_at = $1, check_out_at = $2, notes = $3
+	          WHERE id = $4 RETURNING updated_at` // updated_at dihandle trigger
+	if err := tx.QueryRow(ctx, query, att.CheckInAt, att.CheckOutAt, att.Notes, att.ID).Scan(&att.UpdatedAt); err != nil {
+		return fmt.Errorf("error projecting attendance id %d: %w", att.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// internal/repository/attendance_photo_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type attendancePhotoRepo struct {
+	db Conn // Primary: antrean proses dibaca tepat setelah upload ditulis
+}
+
+func NewAttendancePhotoRepository(pools Pools) AttendancePhotoRepository {
+	return &attendancePhotoRepo{db: pools.Primary}
+}
+
+// CreateAttendancePhoto menyimpan upload mentah berstatus pending dan mengisi ID & captured_at.
+func (r *attendancePhotoRepo) CreateAttendancePhoto(ctx context.Context, photo *models.AttendancePhoto) error {
+	query := `INSERT INTO attendance_photos AS ap (attendance_id, user_id, status, incoming_key)
+              VALUES ($1, $2, $3, $4)
+              RETURNING ` + shared.SelectList("ap", shared.AttendancePhotoColumns)
+	err := shared.ScanAttendancePhoto(r.db.QueryRow(ctx, query, photo.AttendanceID, photo.UserID, models.AttendancePhotoPending, photo.IncomingKey), photo)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", photo.AttendanceID).Msg("Error creating attendance photo")
+		return fmt.Errorf("error creating photo for attendance %d: %w", photo.AttendanceID, err)
+	}
+	return nil
+}
+
+// GetAttendancePhoto mengembalikan foto satu record absensi, atau pgx.ErrNoRows.
+func (r *attendancePhotoRepo) GetAttendancePhoto(ctx context.Context, attendanceID int) (*models.AttendancePhoto, error) {
+	return r.getOne(ctx, "ap.attendance_id", attendanceID)
+}
+
+// GetAttendancePhotoByID mengembalikan foto berdasarkan ID, atau pgx.ErrNoRows.
+func (r *attendancePhotoRepo) GetAttendancePhotoByID(ctx context.Context, id int) (*models.AttendancePhoto, error) {
+	return r.getOne(ctx, "ap.id", id)
+}
+
+func (r *attendancePhotoRepo) getOne(ctx context.Context, column string, value int) (*models.AttendancePhoto, error) {
+	query := `SELECT ` + shared.SelectList("ap", shared.AttendancePhotoColumns) + ` FROM attendance_photos ap WHERE ` + column + ` = $1`
+	photo := &models.AttendancePhoto{}
+	if err := shared.ScanAttendancePhoto(r.db.QueryRow(ctx, query, value), photo); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Str("column", column).Int("value", value).Msg("Error getting attendance photo")
+		return nil, fmt.Errorf("error getting attendance photo by %s %d: %w", column, value, err)
+	}
+	return photo, nil
+}
+
+// GetPendingAttendancePhotos mengembalikan maksimal limit foto pending, terlama dulu.
+func (r *attendancePhotoRepo) GetPendingAttendancePhotos(ctx context.Context, limit int) ([]models.AttendancePhoto, error) {
+	query := `SELECT ` + shared.SelectList("ap", shared.AttendancePhotoColumns) + `
+              FROM attendance_photos ap
+              WHERE ap.status = $1
+              ORDER BY ap.captured_at, ap.id
+              LIMIT $2`
+	return r.list(ctx, "pending", query, models.AttendancePhotoPending, limit)
+}
+
+// GetExpiredAttendancePhotos mengembalikan maksimal limit foto yang belum purged dan diambil
+// sebelum capturedBefore, terlama dulu.
+func (r *attendancePhotoRepo) GetExpiredAttendancePhotos(ctx context.Context, capturedBefore time.Time, limit int) ([]models.AttendancePhoto, error) {
+	query := `SELECT ` + shared.SelectList("ap", shared.AttendancePhotoColumns) + `
+              FROM attendance_photos ap
+              WHERE ap.status <> $1 AND ap.captured_at < $2
+              ORDER BY ap.captured_at, ap.id
+              LIMIT $3`
+	return r.list(ctx, "expired", query, models.AttendancePhotoPurged, capturedBefore, limit)
+}
+
+func (r *attendancePhotoRepo) list(ctx context.Context, kind, query string, args ...any) ([]models.AttendancePhoto, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("kind", kind).Msg("Error querying attendance photos")
+		return nil, fmt.Errorf("error getting %s attendance photos: %w", kind, err)
+	}
+	defer rows.Close()
+	photos := []models.AttendancePhoto{}
+	for rows.Next() {
+		var p models.AttendancePhoto
+		if err := shared.ScanAttendancePhoto(rows, &p); err != nil {
+			return nil, fmt.Errorf("error scanning attendance photo row: %w", err)
+		}
+		photos = append(photos, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attendance photo rows: %w", err)
+	}
+	return photos, nil
+}
+
+// MarkAttendancePhotoProcessed mencatat hasil proses dan mengosongkan incoming_key.
+func (r *attendancePhotoRepo) MarkAttendancePhotoProcessed(ctx context.Context, id int, originalKey, thumbnailKey string, width, height int) error {
+	query := `UPDATE attendance_photos
+              SET status = $2, original_key = $3, thumbnail_key = $4, width = $5, height = $6,
+                  incoming_key = NULL, error = NULL, processed_at = CURRENT_TIMESTAMP
+              WHERE id = $1`
+	return r.update(ctx, id, "processed", query, id, models.AttendancePhotoProcessed, originalKey, thumbnailKey, width, height)
+}
+
+// MarkAttendancePhotoFailed mencatat kegagalan proses dan mengosongkan incoming_key.
+func (r *attendancePhotoRepo) MarkAttendancePhotoFailed(ctx context.Context, id int, reason string) error {
+	query := `UPDATE attendance_photos
+              SET status = $2, error = $3, incoming_key = NULL, processed_at = CURRENT_TIMESTAMP
+              WHERE id = $1`
+	return r.update(ctx, id, "failed", query, id, models.AttendancePhotoFailed, reason)
+}
+
+// MarkAttendancePhotoPurged mencatat bahwa file foto sudah dihapus dan mengosongkan semua key.
+func (r *attendancePhotoRepo) MarkAttendancePhotoPurged(ctx context.Context, id int) error {
+	query := `UPDATE attendance_photos
+              SET status = $2, incoming_key = NULL, original_key = NULL, thumbnail_key = NULL,
+                  purged_at = CURRENT_TIMESTAMP
+              WHERE id = $1`
+	return r.update(ctx, id, "purged", query, id, models.AttendancePhotoPurged)
+}
+
+func (r *attendancePhotoRepo) update(ctx context.Context, id int, status, query string, args ...any) error {
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("photo_id", id).Str("status", status).Msg("Error updating attendance photo")
+		return fmt.Errorf("error marking attendance photo %d %s: %w", id, status, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// internal/repository/attendance_pings.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Ping lokasi berkala sesi field (attendance_pings). Koordinat disimpan sebagai mikroderajat
+// (lat_e6/lon_e6) dan dikonversi kembali ke derajat saat dibaca.
+
+var (
+	// ErrNotFieldSession dikembalikan RecordAttendancePing jika sesi bukan mode field.
+	ErrNotFieldSession = errors.New("attendance session is not in field mode")
+	// ErrPingOutsideSession dikembalikan jika waktu rekam ping sebelum check-in.
+	ErrPingOutsideSession = errors.New("ping is outside the attendance session")
+	// ErrPingTooSoon dikembalikan jika ada ping lain dalam jeda minimum dari waktu rekam ping.
+	ErrPingTooSoon = errors.New("location ping sent too soon after the previous one")
+	// ErrRouteLimitReached dikembalikan jika sesi sudah mencapai shared.MaxPingsPerSession titik.
+	ErrRouteLimitReached = errors.New("attendance session has reached the maximum number of pings")
+)
+
+// RecordAttendancePing menyimpan ping lokasi untuk sesi terbuka milik userID. Record dikunci
+// agar dua ping bersamaan tidak melewati pemeriksaan jeda minimum. pgx.ErrNoRows jika sesi
+// tidak ada atau milik user lain; ErrNotCheckedIn jika sesi sudah check-out.
+func (r *attendanceRepo) RecordAttendancePing(ctx context.Context, userID int, ping *models.AttendancePing, minInterval time.Duration) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("error starting ping transaction for attendance id %d: %w", ping.AttendanceID, err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	var ownerID int
+	var checkInAt time.Time
+	var checkOutAt *time.Time
+	var mode string
+	query := `SELECT user_id, check_in_at, check_out_at, mode FROM attendances WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(ctx, query, ping.AttendanceID).Scan(&ownerID, &checkInAt, &checkOutAt, &mode); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		return fmt.Errorf("error locking attendance id %d for ping: %w", ping.AttendanceID, err)
+	}
+	switch {
+	case ownerID != userID:
+		return pgx.ErrNoRows
+	case checkOutAt != nil:
+		return ErrNotCheckedIn
+	case mode != models.AttendanceModeField:
+		return ErrNotFieldSession
+	case ping.RecordedAt.Before(checkInAt):
+		return ErrPingOutsideSession
+	}
+
+	var count int
+	var tooSoon bool
+	query = `SELECT COUNT(*),
+                    COALESCE(BOOL_OR(recorded_at > $2::timestamptz - $3::interval AND recorded_at < $2::timestamptz + $3::interval), FALSE)
+             FROM attendance_pings WHERE attendance_id = $1`
+	if err := tx.QueryRow(ctx, query, ping.AttendanceID, ping.RecordedAt, minInterval).Scan(&count, &tooSoon); err != nil {
+		return fmt.Errorf("error checking pings of attendance id %d: %w", ping.AttendanceID, err)
+	}
+	if tooSoon {
+		return ErrPingTooSoon
+	}
+	if count >= shared.MaxPingsPerSession {
+		return ErrRouteLimitReached
+	}
+
+	query = `INSERT INTO attendance_pings (attendance_id, recorded_at, lat_e6, lon_e6, accuracy_m) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.Exec(ctx, query, ping.AttendanceID, ping.RecordedAt, shared.ToMicrodegrees(ping.Latitude), shared.ToMicrodegrees(ping.Longitude), ping.AccuracyM); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", ping.AttendanceID).Msg("Error inserting attendance ping")
+		return fmt.Errorf("error inserting ping for attendance id %d: %w", ping.AttendanceID, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing ping for attendance id %d: %w", ping.AttendanceID, err)
+	}
+	return nil
+}
+
+// GetAttendancePings mengembalikan semua ping satu sesi absensi, urut waktu rekam.
+func (r *attendanceRepo) GetAttendancePings(ctx context.Context, attendanceID int) ([]models.AttendancePing, error) {
+	query := `SELECT recorded_at, lat_e6, lon_e6, accuracy_m FROM attendance_pings
+              WHERE attendance_id = $1 ORDER BY recorded_at`
+	rows, err := r.read.Query(ctx, query, attendanceID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", attendanceID).Msg("Error querying attendance pings")
+		return nil, fmt.Errorf("error getting pings for attendance id %d: %w", attendanceID, err)
+	}
+	defer rows.Close()
+
+	pings := []models.AttendancePing{}
+	for rows.Next() {
+		p := models.AttendancePing{AttendanceID: attendanceID}
+		var lat, lon int32
+		var accuracy *int16
+		if err := rows.Scan(&p.RecordedAt, &lat, &lon, &accuracy); err != nil {
+			return nil, fmt.Errorf("error scanning attendance ping row: %w", err)
+		}
+		p.Latitude, p.Longitude = float64(lat)/1e6, float64(lon)/1e6
+		if accuracy != nil {
+			m := int(*accuracy)
+			p.AccuracyM = &m
+		}
+		pings = append(pings, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attendance ping rows: %w", err)
+	}
+	return pings, nil
+}
+
+// DeleteAttendancePingsBefore menghapus maksimal limit ping yang direkam sebelum before dan
+// mengembalikan jumlahnya. Sisa batch dihapus di putaran berikutnya.
+func (r *attendanceRepo) DeleteAttendancePingsBefore(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `DELETE FROM attendance_pings
+              WHERE (attendance_id, recorded_at) IN (
+                  SELECT attendance_id, recorded_at FROM attendance_pings WHERE recorded_at < $1 LIMIT $2)`
+	tag, err := r.db.Exec(ctx, query, before, limit)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Time("before", before).Msg("Error deleting expired attendance pings")
+		return 0, fmt.Errorf("error deleting expired attendance pings: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
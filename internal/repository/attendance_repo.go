@@ -7,29 +7,88 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/rakaarfi/attendance-system-be/internal/models"
-	zlog "github.com/rs/zerolog/log"
+	"github.com/rakaarfi/attendance-system-be/internal/pii"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
 )
 
+// ErrAlreadyCheckedIn dikembalikan CreateCheckIn jika user masih punya sesi absensi terbuka.
+var ErrAlreadyCheckedIn = errors.New("user already checked in")
+
+// openSessionConstraint adalah unique index parsial (user_id WHERE check_out_at IS NULL).
+const openSessionConstraint = "uq_attendances_open_session"
+
 type attendanceRepo struct {
-	db *pgxpool.Pool
+	db   Conn           // Primary: tulis & baca konsisten
+	read Conn           // Replica (atau Primary jika tidak ada) untuk laporan/listing
+	pii  *pii.Protector // Dekripsi email user pada query yang JOIN ke tabel users
 }
 
-func NewAttendanceRepository(db *pgxpool.Pool) AttendanceRepository {
-	return &attendanceRepo{db: db}
+func NewAttendanceRepository(pools Pools, protector *pii.Protector) AttendanceRepository {
+	return &attendanceRepo{db: pools.Primary, read: pools.reader(), pii: protector}
 }
 
-// CreateCheckIn records a check-in event
-func (r *attendanceRepo) CreateCheckIn(ctx context.Context, userID int, checkInTime time.Time, notes *string) (int, error) {
-	query := `INSERT INTO attendances (user_id, check_in_at, notes) VALUES ($1, $2, $3) RETURNING id`
+// CreateCheckIn records a check-in event.
+// Ditolak dengan *PayrollPeriodClosedError jika tanggal check-in berada di periode payroll yang sudah ditutup,
+// atau *AttendanceSignedOffError jika tanggal tersebut sudah di-sign-off atasan.
+// Record attendances dan event check_in ditulis dalam satu transaksi.
+// scheduleID (opsional) adalah jadwal yang berlaku saat check-in dan disimpan sebagai tautan tetap.
+// projectID (opsional) menandai sesi dengan project; ErrProjectUnavailable jika project tidak ada atau nonaktif.
+// mode adalah mode kerja sesi (models.AttendanceMode*), sudah diperiksa terhadap kebijakan user.
+func (r *attendanceRepo) CreateCheckIn(ctx context.Context, userID int, checkInTime time.Time, notes *string, scheduleID, projectID *int, mode string) (int, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("error starting check-in transaction for user %d: %w", userID, err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	if err = checkPayrollPeriodsOpen(ctx, tx, checkInTime); err != nil {
+		return 0, err
+	}
+	if err = checkNotSignedOff(ctx, tx, userID, checkInTime); err != nil {
+		return 0, err
+	}
+
+	if projectID != nil {
+		if err = checkProjectActive(ctx, tx, *projectID); err != nil {
+			return 0, err
+		}
+	}
+
+	query := `INSERT INTO attendances (user_id, check_in_at, notes, schedule_id, project_id, mode) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
 	var attendanceID int
-	err := r.db.QueryRow(ctx, query, userID, checkInTime, notes).Scan(&attendanceID)
+	err = tx.QueryRow(ctx, query, userID, checkInTime, notes, scheduleID, projectID, mode).Scan(&attendanceID)
 	if err != nil {
-		zlog.Error().Err(err).Int("user_id", userID).Time("check_in_at", checkInTime).Msg("Error creating check-in for user")
+		// Unique index parsial uq_attendances_open_session: sudah ada sesi terbuka (check-in paralel)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == openSessionConstraint {
+			shared.Logger(ctx).Warn().Int("user_id", userID).Msg("Concurrent check-in rejected by open session constraint")
+			return 0, ErrAlreadyCheckedIn
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Time("check_in_at", checkInTime).Msg("Error creating check-in for user")
 		return 0, fmt.Errorf("error creating check-in for user %d: %w", userID, err)
 	}
-	zlog.Info().Int("attendance_id", attendanceID).Int("user_id", userID).Time("check_in_at", checkInTime).Msg("Check-in created successfully")
+
+	if _, err = openSegment(ctx, tx, attendanceID, projectID, checkInTime); err != nil {
+		return 0, err
+	}
+
+	actorID := userID
+	if err = shared.AppendAttendanceEvent(ctx, tx, &models.AttendanceEvent{
+		AttendanceID: attendanceID,
+		UserID:       userID,
+		EventType:    models.AttendanceEventCheckIn,
+		CheckInAt:    checkInTime,
+		Notes:        notes,
+		ActorUserID:  &actorID,
+	}); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error committing check-in for user %d: %w", userID, err)
+	}
+	shared.Logger(ctx).Info().Int("attendance_id", attendanceID).Int("user_id", userID).Time("check_in_at", checkInTime).Msg("Check-in created successfully")
 	return attendanceID, nil
 }
 
@@ -37,49 +96,96 @@ func (r *attendanceRepo) CreateCheckIn(ctx context.Context, userID int, checkInT
 // Useful for checking status (already checked in?) or finding record to checkout.
 func (r *attendanceRepo) GetLastAttendance(ctx context.Context, userID int) (*models.Attendance, error) {
 	query := `
-        SELECT id, user_id, check_in_at, check_out_at, notes, created_at, updated_at
-        FROM attendances
-        WHERE user_id = $1
-        ORDER BY check_in_at DESC
+        SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + `
+        FROM attendances a
+        WHERE a.user_id = $1
+        ORDER BY a.check_in_at DESC
         LIMIT 1`
 	att := &models.Attendance{}
-	err := r.db.QueryRow(ctx, query, userID).Scan(
-		&att.ID,
-		&att.UserID,
-		&att.CheckInAt,
-		&att.CheckOutAt, // Handles NULL automatically with *time.Time
-		&att.Notes,      // Handles NULL automatically with *string
-		&att.CreatedAt,
-		&att.UpdatedAt,
-	)
+	err := shared.ScanAttendance(r.db.QueryRow(ctx, query, userID), att)
 	if err != nil {
 		// Penting: ErrNoRows di sini berarti user belum pernah absensi sama sekali
 		if errors.Is(err, pgx.ErrNoRows) {
-			zlog.Warn().Int("user_id", userID).Msg("User has no attendance record")
+			shared.Logger(ctx).Warn().Int("user_id", userID).Msg("User has no attendance record")
 			return nil, pgx.ErrNoRows // Kembalikan error asli agar handler bisa bedakan
 		}
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Error getting last attendance for user")
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error getting last attendance for user")
 		return nil, fmt.Errorf("error getting last attendance for user %d: %w", userID, err)
 	}
 	return att, nil
 }
 
-// UpdateCheckOut records the check-out time for a specific attendance record
-func (r *attendanceRepo) UpdateCheckOut(ctx context.Context, attendanceID int, checkOutTime time.Time, notes *string) error {
-	// Update notes jika disediakan, jika tidak, biarkan notes yang ada
-	query := `UPDATE attendances SET check_out_at = $1, updated_at = CURRENT_TIMESTAMP, notes = COALESCE($2, notes)
-              WHERE id = $3 AND check_out_at IS NULL` // Pastikan hanya update yang belum checkout
+// GetAttendanceByID retrieves a single attendance record by its ID.
+func (r *attendanceRepo) GetAttendanceByID(ctx context.Context, attendanceID int) (*models.Attendance, error) {
+	query := `SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + ` FROM attendances a WHERE a.id = $1`
+	att := &models.Attendance{}
+	if err := shared.ScanAttendance(r.db.QueryRow(ctx, query, attendanceID), att); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", attendanceID).Msg("Error getting attendance by ID")
+		return nil, fmt.Errorf("error getting attendance by id %d: %w", attendanceID, err)
+	}
+	return att, nil
+}
 
-	tag, err := r.db.Exec(ctx, query, checkOutTime, notes, attendanceID)
+// UpdateCheckOut records the check-out time for a specific attendance record.
+// Perubahan dicatat sebagai event check_out di ledger, lalu proyeksi diperbarui dari snapshot event tersebut.
+// Ditolak dengan *PayrollPeriodClosedError jika tanggal check-in record berada di periode payroll yang sudah ditutup,
+// atau *AttendanceSignedOffError jika tanggal tersebut sudah di-sign-off atasan.
+func (r *attendanceRepo) UpdateCheckOut(ctx context.Context, attendanceID int, checkOutTime time.Time, notes *string) error {
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
-		zlog.Error().Err(err).Int("attendance_id", attendanceID).Msg("Error updating check-out for attendance ID")
+		return fmt.Errorf("error starting check-out transaction for attendance id %d: %w", attendanceID, err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	// Kunci record yang belum checkout agar dua request checkout tidak menulis event ganda
+	current, err := lockAttendance(ctx, tx, attendanceID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", attendanceID).Msg("Error updating check-out for attendance ID")
 		return fmt.Errorf("error updating check-out for attendance id %d: %w", attendanceID, err)
 	}
-	if tag.RowsAffected() == 0 {
+	if current == nil || current.CheckOutAt != nil {
 		// Ini bisa berarti ID tidak ditemukan ATAU sudah checkout sebelumnya
-		zlog.Warn().Int("attendance_id", attendanceID).Msg("Attendance record not found or already checked out")
+		shared.Logger(ctx).Warn().Int("attendance_id", attendanceID).Msg("Attendance record not found or already checked out")
 		return fmt.Errorf("attendance record %d not found or already checked out", attendanceID)
 	}
+	if err = checkPayrollPeriodsOpen(ctx, tx, current.CheckInAt); err != nil {
+		return err
+	}
+	if err = checkNotSignedOff(ctx, tx, current.UserID, current.CheckInAt); err != nil {
+		return err
+	}
+
+	// Update notes jika disediakan, jika tidak, biarkan notes yang ada
+	if notes != nil {
+		current.Notes = notes
+	}
+	current.CheckOutAt = &checkOutTime
+	if err = shared.CloseOpenSegment(ctx, tx, current.ID, checkOutTime); err != nil {
+		return err
+	}
+
+	actorID := current.UserID
+	if err = shared.AppendAttendanceEvent(ctx, tx, &models.AttendanceEvent{
+		AttendanceID: current.ID,
+		UserID:       current.UserID,
+		EventType:    models.AttendanceEventCheckOut,
+		CheckInAt:    current.CheckInAt,
+		CheckOutAt:   current.CheckOutAt,
+		Notes:        current.Notes,
+		ActorUserID:  &actorID,
+	}); err != nil {
+		return err
+	}
+	if err = projectAttendance(ctx, tx, current); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing check-out for attendance id %d: %w", attendanceID, err)
+	}
 	return nil
 }
 
@@ -88,9 +194,9 @@ func (r *attendanceRepo) GetAttendancesByUser(ctx context.Context, userID int, s
 	// --- 1. Count Total ---
 	// Gunakan >= startDate dan <= endDate karena handler akan set endDate ke akhir hari
 	countQuery := `SELECT COUNT(*) FROM attendances WHERE user_id = $1 AND check_in_at >= $2 AND check_in_at <= $3`
-	err = r.db.QueryRow(ctx, countQuery, userID, startDate, endDate).Scan(&totalCount)
+	err = r.read.QueryRow(ctx, countQuery, userID, startDate, endDate).Scan(&totalCount)
 	if err != nil {
-		zlog.Error().Err(err).Int("user_id", userID).Time("start", startDate).Time("end", endDate).Msg("Error counting user attendances")
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Time("start", startDate).Time("end", endDate).Msg("Error counting user attendances")
 		err = fmt.Errorf("error counting attendances for user %d: %w", userID, err)
 		return // Kembalikan error
 	}
@@ -107,15 +213,15 @@ func (r *attendanceRepo) GetAttendancesByUser(ctx context.Context, userID int, s
 
 	// --- 3. Query Data ---
 	query := `
-        SELECT id, user_id, check_in_at, check_out_at, notes, created_at, updated_at
-        FROM attendances
-        WHERE user_id = $1 AND check_in_at >= $2 AND check_in_at <= $3
-        ORDER BY check_in_at DESC -- Order by check_in paling baru
+        SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + `, ` + attendanceTagsColumn + `
+        FROM attendances a
+        WHERE a.user_id = $1 AND a.check_in_at >= $2 AND a.check_in_at <= $3
+        ORDER BY a.check_in_at DESC -- Order by check_in paling baru
         LIMIT $4 OFFSET $5`
 
-	rows, err := r.db.Query(ctx, query, userID, startDate, endDate, limit, offset)
+	rows, err := r.read.Query(ctx, query, userID, startDate, endDate, limit, offset)
 	if err != nil {
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Error querying paginated user attendances")
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error querying paginated user attendances")
 		err = fmt.Errorf("error getting paginated attendances for user %d: %w", userID, err)
 		return
 	}
@@ -125,24 +231,16 @@ func (r *attendanceRepo) GetAttendancesByUser(ctx context.Context, userID int, s
 	attendances = []models.Attendance{}
 	for rows.Next() {
 		var att models.Attendance
-		scanErr := rows.Scan(
-			&att.ID,
-			&att.UserID,
-			&att.CheckInAt,
-			&att.CheckOutAt, // Handles NULL
-			&att.Notes,      // Handles NULL
-			&att.CreatedAt,
-			&att.UpdatedAt,
-		)
+		scanErr := shared.ScanAttendance(rows, &att, &att.Tags)
 		if scanErr != nil {
-			zlog.Warn().Err(scanErr).Int("user_id", userID).Msg("Error scanning user attendance row (paginated)")
+			shared.Logger(ctx).Warn().Err(scanErr).Int("user_id", userID).Msg("Error scanning user attendance row (paginated)")
 			err = fmt.Errorf("error scanning attendance row: %w", scanErr)
 			return // Return error jika scan gagal
 		}
 		attendances = append(attendances, att)
 	}
 	if err = rows.Err(); err != nil {
-		zlog.Error().Err(err).Int("user_id", userID).Msg("Error iterating user attendance rows")
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error iterating user attendance rows")
 		err = fmt.Errorf("error iterating attendance rows: %w", err)
 		return
 	}
@@ -151,13 +249,15 @@ func (r *attendanceRepo) GetAttendancesByUser(ctx context.Context, userID int, s
 }
 
 // GetAllAttendances retrieves all attendance records within a date range (for Admin)
-// Includes user information
-func (r *attendanceRepo) GetAllAttendances(ctx context.Context, startDate, endDate time.Time, page, limit int) (attendances []models.Attendance, totalCount int, err error) {
-	// --- 1. Count Total (tanpa join) ---
-	countQuery := `SELECT COUNT(*) FROM attendances WHERE check_in_at >= $1 AND check_in_at <= $2`
-	err = r.db.QueryRow(ctx, countQuery, startDate, endDate).Scan(&totalCount)
+// Includes user information. filter.EmploymentStatus (optional) limits rows to users with that status.
+func (r *attendanceRepo) GetAllAttendances(ctx context.Context, startDate, endDate time.Time, filter models.AttendanceReportFilter, page, limit int) (attendances []models.Attendance, totalCount int, err error) {
+	// --- 1. Count Total (join users hanya untuk filter) ---
+	countQuery := `SELECT COUNT(*) FROM attendances a JOIN users u ON a.user_id = u.id
+                   WHERE a.check_in_at >= $1 AND a.check_in_at <= $2 AND ($3 = '' OR u.employment_status = $3)
+                     AND (NOT $4 OR EXISTS (SELECT 1 FROM attendance_disputes ad WHERE ad.attendance_id = a.id AND ad.status = $5))`
+	err = r.read.QueryRow(ctx, countQuery, startDate, endDate, filter.EmploymentStatus, filter.DisputedOnly, models.DisputeOpen).Scan(&totalCount)
 	if err != nil {
-		zlog.Error().Err(err).Time("start", startDate).Time("end", endDate).Msg("Error counting all attendances")
+		shared.Logger(ctx).Error().Err(err).Time("start", startDate).Time("end", endDate).Msg("Error counting all attendances")
 		err = fmt.Errorf("error counting all attendances: %w", err)
 		return
 	}
@@ -172,19 +272,22 @@ func (r *attendanceRepo) GetAllAttendances(ctx context.Context, startDate, endDa
 		offset = 0
 	}
 
-	// --- 3. Query Data (dengan join user) ---
+	// --- 3. Query Data (dengan join user & dispute open) ---
 	query := `
-        SELECT a.id, a.user_id, a.check_in_at, a.check_out_at, a.notes, a.created_at, a.updated_at,
-               u.id as userid, u.username, u.first_name, u.last_name, u.email
+        SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + `,
+               ` + shared.SelectList("u", userSummaryColumns) + `,
+               ad.id, ` + attendanceTagsColumn + `
         FROM attendances a
         JOIN users u ON a.user_id = u.id
-        WHERE a.check_in_at >= $1 AND a.check_in_at <= $2
+        LEFT JOIN attendance_disputes ad ON ad.attendance_id = a.id AND ad.status = $7
+        WHERE a.check_in_at >= $1 AND a.check_in_at <= $2 AND ($3 = '' OR u.employment_status = $3)
+          AND (NOT $6 OR ad.id IS NOT NULL)
         ORDER BY a.check_in_at DESC, u.username ASC -- Order by check_in, lalu username
-        LIMIT $3 OFFSET $4`
+        LIMIT $4 OFFSET $5`
 
-	rows, err := r.db.Query(ctx, query, startDate, endDate, limit, offset)
+	rows, err := r.read.Query(ctx, query, startDate, endDate, filter.EmploymentStatus, limit, offset, filter.DisputedOnly, models.DisputeOpen)
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error querying paginated all attendances report")
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying paginated all attendances report")
 		err = fmt.Errorf("error getting paginated all attendances report: %w", err)
 		return
 	}
@@ -195,23 +298,196 @@ func (r *attendanceRepo) GetAllAttendances(ctx context.Context, startDate, endDa
 	for rows.Next() {
 		var att models.Attendance
 		att.User = &models.User{} // !!! Penting: Inisialisasi User sebelum scan !!!
-		scanErr := rows.Scan(
-			&att.ID, &att.UserID, &att.CheckInAt, &att.CheckOutAt, &att.Notes,
-			&att.CreatedAt, &att.UpdatedAt,
-			&att.User.ID, &att.User.Username, &att.User.FirstName, &att.User.LastName, &att.User.Email,
-		)
+		scanErr := shared.ScanAttendance(rows, &att, append(shared.UserSummaryDest(att.User), &att.OpenDisputeID, &att.Tags)...)
 		if scanErr != nil {
-			zlog.Warn().Err(scanErr).Msg("Error scanning attendance report row (paginated)")
+			shared.Logger(ctx).Warn().Err(scanErr).Msg("Error scanning attendance report row (paginated)")
 			err = fmt.Errorf("error scanning attendance report row: %w", scanErr)
 			return
 		}
+		if err = shared.DecryptUserPII(r.pii, att.User); err != nil {
+			return
+		}
 		attendances = append(attendances, att)
 	}
 	if err = rows.Err(); err != nil {
-		zlog.Error().Err(err).Msg("Error iterating attendance report rows")
+		shared.Logger(ctx).Error().Err(err).Msg("Error iterating attendance report rows")
 		err = fmt.Errorf("error iterating attendance report rows: %w", err)
 		return
 	}
 
 	return // attendances, totalCount, nil error
 }
+
+// ExportAttendancesByUser retrieves every attendance record of a user (no date filter, no pagination).
+// Used by the personal data export (GET /user/data-export).
+func (r *attendanceRepo) ExportAttendancesByUser(ctx context.Context, userID int) ([]models.Attendance, error) {
+	query := `
+        SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + `, ` + attendanceTagsColumn + `
+        FROM attendances a
+        WHERE a.user_id = $1
+        ORDER BY a.check_in_at ASC`
+
+	rows, err := r.read.Query(ctx, query, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error querying attendances for export")
+		return nil, fmt.Errorf("error exporting attendances for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	attendances := []models.Attendance{}
+	for rows.Next() {
+		var att models.Attendance
+		if err := shared.ScanAttendance(rows, &att, &att.Tags); err != nil {
+			return nil, fmt.Errorf("error scanning exported attendance row: %w", err)
+		}
+		attendances = append(attendances, att)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating exported attendance rows: %w", err)
+	}
+	return attendances, nil
+}
+
+// CorrectAttendance menerapkan koreksi admin pada record absensi tanpa menimpa riwayat:
+// snapshot baru dicatat sebagai event "correction" (beserta alasan & admin pelaku),
+// lalu proyeksi attendances diperbarui dari snapshot tersebut.
+// Ditolak dengan *PayrollPeriodClosedError jika tanggal check-in lama atau baru berada di periode payroll yang sudah ditutup,
+// atau *AttendanceSignedOffError jika salah satu tanggal tersebut sudah di-sign-off atasan.
+func (r *attendanceRepo) CorrectAttendance(ctx context.Context, attendanceID int, input *models.AttendanceCorrectionInput, actorUserID int) (*models.Attendance, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("error starting correction transaction for attendance id %d: %w", attendanceID, err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	current, err := lockAttendance(ctx, tx, attendanceID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("error loading attendance id %d for correction: %w", attendanceID, err)
+	}
+	// Tanggal lama dan baru harus sama-sama berada di periode payroll yang masih open
+	previousCheckIn := current.CheckInAt
+
+	// Terapkan hanya field yang dikirim
+	if input.CheckInAt != nil {
+		current.CheckInAt = *input.CheckInAt
+	}
+	if input.CheckOutAt != nil {
+		current.CheckOutAt = input.CheckOutAt
+	}
+	if input.Notes != nil {
+		current.Notes = input.Notes
+	}
+	if current.CheckOutAt != nil && current.CheckOutAt.Before(current.CheckInAt) {
+		return nil, ErrInvalidAttendanceTimes
+	}
+	if err = checkPayrollPeriodsOpen(ctx, tx, previousCheckIn, current.CheckInAt); err != nil {
+		return nil, err
+	}
+	if err = checkNotSignedOff(ctx, tx, current.UserID, previousCheckIn, current.CheckInAt); err != nil {
+		return nil, err
+	}
+
+	reason := input.Reason
+	if err = shared.AppendAttendanceEvent(ctx, tx, &models.AttendanceEvent{
+		AttendanceID: current.ID,
+		UserID:       current.UserID,
+		EventType:    models.AttendanceEventCorrection,
+		CheckInAt:    current.CheckInAt,
+		CheckOutAt:   current.CheckOutAt,
+		Notes:        current.Notes,
+		Reason:       &reason,
+		ActorUserID:  &actorUserID,
+	}); err != nil {
+		return nil, err
+	}
+	if err = projectAttendance(ctx, tx, current); err != nil {
+		return nil, err
+	}
+	if err = syncSegmentBounds(ctx, tx, current); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing correction for attendance id %d: %w", attendanceID, err)
+	}
+	shared.Logger(ctx).Info().Int("attendance_id", attendanceID).Int("actor_user_id", actorUserID).Msg("Attendance corrected via ledger")
+	return current, nil
+}
+
+// GetAttendanceEvents mengembalikan seluruh event ledger untuk satu record absensi (urut kronologis).
+// Mengembalikan pgx.ErrNoRows jika record absensi tidak ditemukan.
+func (r *attendanceRepo) GetAttendanceEvents(ctx context.Context, attendanceID int) ([]models.AttendanceEvent, error) {
+	var exists bool
+	if err := r.read.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM attendances WHERE id = $1)`, attendanceID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking attendance id %d: %w", attendanceID, err)
+	}
+	if !exists {
+		return nil, pgx.ErrNoRows
+	}
+
+	query := `
+        SELECT ` + shared.SelectList("e", shared.AttendanceEventColumns) + `
+        FROM attendance_events e
+        WHERE e.attendance_id = $1
+        ORDER BY e.id ASC`
+	rows, err := r.read.Query(ctx, query, attendanceID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", attendanceID).Msg("Error querying attendance events")
+		return nil, fmt.Errorf("error getting events for attendance id %d: %w", attendanceID, err)
+	}
+	defer rows.Close()
+
+	events := []models.AttendanceEvent{}
+	for rows.Next() {
+		var ev models.AttendanceEvent
+		if err := shared.ScanAttendanceEvent(rows, &ev); err != nil {
+			return nil, fmt.Errorf("error scanning attendance event row: %w", err)
+		}
+		events = append(events, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attendance event rows: %w", err)
+	}
+	return events, nil
+}
+
+// GetProjectHours merekap jam kerja sesi yang sudah check-out per project berdasarkan segmen
+// project (termasuk perpindahan project di tengah sesi), untuk sesi dengan check-in dalam
+// [startDate, endDate]. userID (opsional) membatasi ke satu user.
+// Segmen tanpa project dirangkum di baris dengan ProjectID nil (paling akhir).
+func (r *attendanceRepo) GetProjectHours(ctx context.Context, startDate, endDate time.Time, userID *int) ([]models.ProjectHours, error) {
+	query := `
+        SELECT p.id, p.code, p.name, p.cost_center,
+               COUNT(DISTINCT a.id), COUNT(DISTINCT a.user_id),
+               ROUND((SUM(EXTRACT(EPOCH FROM (sg.ended_at - sg.started_at))) / 3600)::numeric, 2)::float8
+        FROM attendance_segments sg
+        JOIN attendances a ON sg.attendance_id = a.id
+        LEFT JOIN projects p ON sg.project_id = p.id
+        WHERE a.check_out_at IS NOT NULL AND sg.ended_at IS NOT NULL
+          AND a.check_in_at >= $1 AND a.check_in_at <= $2
+          AND ($3::int IS NULL OR a.user_id = $3)
+        GROUP BY p.id
+        ORDER BY p.code NULLS LAST`
+	rows, err := r.read.Query(ctx, query, startDate, endDate, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Time("start", startDate).Time("end", endDate).Msg("Error querying project hours")
+		return nil, fmt.Errorf("error getting project hours: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []models.ProjectHours{}
+	for rows.Next() {
+		var ph models.ProjectHours
+		if err := rows.Scan(&ph.ProjectID, &ph.Code, &ph.Name, &ph.CostCenter, &ph.Sessions, &ph.Users, &ph.TotalHours); err != nil {
+			return nil, fmt.Errorf("error scanning project hours row: %w", err)
+		}
+		totals = append(totals, ph)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating project hours rows: %w", err)
+	}
+	return totals, nil
+}
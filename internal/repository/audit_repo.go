@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type auditRepo struct {
+	db   Conn // Primary: tulis
+	read Conn // Replica (atau Primary jika tidak ada) untuk feed
+}
+
+func NewAuditRepository(pools Pools) AuditRepository {
+	return &auditRepo{db: pools.Primary, read: pools.reader()}
+}
+
+func (r *auditRepo) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	details := entry.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+	query := `INSERT INTO audit_log (user_id, actor_user_id, action, details)
+              VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	if err := r.db.QueryRow(ctx, query, entry.UserID, entry.ActorUserID, entry.Action, details).Scan(&entry.ID, &entry.CreatedAt); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", entry.UserID).Str("action", entry.Action).Msg("Error creating audit entry")
+		return fmt.Errorf("error creating audit entry: %w", err)
+	}
+	return nil
+}
+
+// GetUserActivity menggabungkan audit log dan ledger attendance_events milik user menjadi satu
+// feed terurut dari yang terbaru. after (opsional) adalah kursor entri terakhir halaman sebelumnya.
+func (r *auditRepo) GetUserActivity(ctx context.Context, userID int, after *models.ActivityCursor, limit int) ([]models.ActivityItem, error) {
+	query := `
+        SELECT source, source_id, category, action, occurred_at, actor_user_id, details FROM (
+            SELECT $5::text AS source, al.id AS source_id, split_part(al.action, '.', 1) AS category,
+                   al.action, al.created_at AS occurred_at, al.actor_user_id, al.details
+            FROM audit_log al
+            WHERE al.user_id = $1 AND ($2::timestamptz IS NULL OR al.created_at <= $2)
+            UNION ALL
+            SELECT $6::text, e.id, 'attendance', 'attendance.' || e.event_type, e.created_at, e.actor_user_id,
+                   jsonb_strip_nulls(jsonb_build_object(
+                       'attendance_id', e.attendance_id, 'check_in_at', e.check_in_at,
+                       'check_out_at', e.check_out_at, 'reason', e.reason))
+            FROM attendance_events e
+            WHERE e.user_id = $1 AND ($2::timestamptz IS NULL OR e.created_at <= $2)
+        ) feed
+        WHERE $2::timestamptz IS NULL OR (occurred_at, source, source_id) < ($2, $3, $4)
+        ORDER BY occurred_at DESC, source DESC, source_id DESC
+        LIMIT $7`
+
+	var afterAt *time.Time
+	var afterSource string
+	var afterID int64
+	if after != nil {
+		afterAt, afterSource, afterID = &after.OccurredAt, after.Source, after.SourceID
+	}
+	rows, err := r.read.Query(ctx, query, userID, afterAt, afterSource, afterID,
+		models.ActivitySourceAuditLog, models.ActivitySourceAttendanceEvents, limit)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error querying user activity")
+		return nil, fmt.Errorf("error getting activity for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	items := []models.ActivityItem{}
+	for rows.Next() {
+		var item models.ActivityItem
+		if err := rows.Scan(&item.Source, &item.SourceID, &item.Category, &item.Action, &item.OccurredAt, &item.ActorUserID, &item.Details); err != nil {
+			return nil, fmt.Errorf("error scanning activity row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating activity rows: %w", err)
+	}
+	return items, nil
+}
+
+// GetLoginHistoryMatch mencocokkan perangkat & negara login dengan login sukses sebelumnya.
+// Riwayat yang belum pernah mencatat device/country (login sebelum fitur aktif) dianggap cocok,
+// agar pengaktifan fitur tidak memicu peringatan untuk semua user.
+func (r *auditRepo) GetLoginHistoryMatch(ctx context.Context, userID int, device, country string) (*models.LoginHistoryMatch, error) {
+	query := `SELECT COUNT(*) > 0,
+                     COALESCE(bool_or(al.details->>'device' = $3), FALSE) OR NOT COALESCE(bool_or(al.details ? 'device'), FALSE),
+                     COALESCE(bool_or($4 <> '' AND al.details->>'country' = $4), FALSE) OR NOT COALESCE(bool_or(al.details ? 'country'), FALSE)
+              FROM audit_log al
+              WHERE al.user_id = $1 AND al.action = $2`
+	match := &models.LoginHistoryMatch{}
+	err := r.db.QueryRow(ctx, query, userID, models.AuditLoginSucceeded, device, country).
+		Scan(&match.HasHistory, &match.KnownDevice, &match.KnownCountry)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error matching login history")
+		return nil, fmt.Errorf("error matching login history for user %d: %w", userID, err)
+	}
+	return match, nil
+}
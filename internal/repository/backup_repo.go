@@ -0,0 +1,150 @@
+// internal/repository/backup_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// ErrBackupInProgress dikembalikan CreateBackup jika masih ada backup pending/running.
+var ErrBackupInProgress = errors.New("another backup is already pending or running")
+
+type backupRepo struct {
+	db Conn // Primary: status dibaca ulang tepat setelah job memperbaruinya
+}
+
+func NewBackupRepository(pools Pools) BackupRepository {
+	return &backupRepo{db: pools.Primary}
+}
+
+// CreateBackup menyimpan permintaan backup berstatus pending dan mengisi ID & created_at.
+func (r *backupRepo) CreateBackup(ctx context.Context, backup *models.Backup) error {
+	query := `INSERT INTO backups AS b (status, requested_by)
+              VALUES ($1, $2)
+              RETURNING ` + shared.SelectList("b", shared.BackupColumns)
+	if err := shared.ScanBackup(r.db.QueryRow(ctx, query, models.BackupPending, backup.RequestedBy), backup); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == shared.ActiveBackupConstraint {
+			return ErrBackupInProgress
+		}
+		shared.Logger(ctx).Error().Err(err).Msg("Error creating backup")
+		return fmt.Errorf("error creating backup: %w", err)
+	}
+	return nil
+}
+
+// GetBackupByID mengembalikan satu backup, atau pgx.ErrNoRows.
+func (r *backupRepo) GetBackupByID(ctx context.Context, id int) (*models.Backup, error) {
+	query := `SELECT ` + shared.SelectList("b", shared.BackupColumns) + ` FROM backups b WHERE b.id = $1`
+	backup := &models.Backup{}
+	if err := shared.ScanBackup(r.db.QueryRow(ctx, query, id), backup); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("backup_id", id).Msg("Error getting backup")
+		return nil, fmt.Errorf("error getting backup %d: %w", id, err)
+	}
+	return backup, nil
+}
+
+// GetBackups mengembalikan semua backup, terbaru dulu.
+func (r *backupRepo) GetBackups(ctx context.Context, page, limit int) ([]models.Backup, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM backups`).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting backups")
+		return nil, 0, fmt.Errorf("error counting backups: %w", err)
+	}
+	if total == 0 {
+		return []models.Backup{}, 0, nil
+	}
+	query := `SELECT ` + shared.SelectList("b", shared.BackupColumns) + `
+              FROM backups b
+              ORDER BY b.created_at DESC, b.id DESC
+              LIMIT $1 OFFSET $2`
+	rows, err := r.db.Query(ctx, query, limit, shared.PageOffset(page, limit))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying backups")
+		return nil, 0, fmt.Errorf("error getting backups: %w", err)
+	}
+	defer rows.Close()
+	backups := []models.Backup{}
+	for rows.Next() {
+		var b models.Backup
+		if err := shared.ScanBackup(rows, &b); err != nil {
+			return nil, 0, fmt.Errorf("error scanning backup row: %w", err)
+		}
+		backups = append(backups, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating backup rows: %w", err)
+	}
+	return backups, total, nil
+}
+
+// ClaimPendingBackup mengubah backup pending terlama menjadi running (SKIP LOCKED agar aman
+// dipanggil beberapa instance) dan mengembalikannya.
+func (r *backupRepo) ClaimPendingBackup(ctx context.Context) (*models.Backup, error) {
+	query := `UPDATE backups AS b
+              SET status = $2, started_at = CURRENT_TIMESTAMP
+              WHERE b.id = (SELECT id FROM backups WHERE status = $1 ORDER BY created_at, id LIMIT 1 FOR UPDATE SKIP LOCKED)
+              RETURNING ` + shared.SelectList("b", shared.BackupColumns)
+	backup := &models.Backup{}
+	if err := shared.ScanBackup(r.db.QueryRow(ctx, query, models.BackupPending, models.BackupRunning), backup); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Msg("Error claiming pending backup")
+		return nil, fmt.Errorf("error claiming pending backup: %w", err)
+	}
+	return backup, nil
+}
+
+// MarkBackupCompleted mencatat file hasil backup. pgx.ErrNoRows jika backup tidak lagi running.
+func (r *backupRepo) MarkBackupCompleted(ctx context.Context, id int, result models.BackupResult) error {
+	query := `UPDATE backups
+              SET status = $2, storage_key = $3, file_name = $4, size_bytes = $5, schema_version = $6,
+                  table_count = $7, row_count = $8, error = NULL, completed_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND status = $9`
+	return r.update(ctx, id, "completed", query, id, models.BackupCompleted, result.StorageKey, result.FileName, result.SizeBytes,
+		result.SchemaVersion, result.TableCount, result.RowCount, models.BackupRunning)
+}
+
+// MarkBackupFailed mencatat kegagalan backup yang belum selesai.
+func (r *backupRepo) MarkBackupFailed(ctx context.Context, id int, reason string) error {
+	query := `UPDATE backups
+              SET status = $2, error = $3, completed_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND status IN ($4, $5)`
+	return r.update(ctx, id, "failed", query, id, models.BackupFailed, reason, models.BackupPending, models.BackupRunning)
+}
+
+// FailStaleBackups menandai failed backup running yang dimulai sebelum startedBefore, mis.
+// karena proses berhenti di tengah backup, agar backup baru bisa diminta lagi.
+func (r *backupRepo) FailStaleBackups(ctx context.Context, startedBefore time.Time, reason string) (int, error) {
+	query := `UPDATE backups
+              SET status = $1, error = $2, completed_at = CURRENT_TIMESTAMP
+              WHERE status = $3 AND started_at < $4`
+	tag, err := r.db.Exec(ctx, query, models.BackupFailed, reason, models.BackupRunning, startedBefore)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error failing stale backups")
+		return 0, fmt.Errorf("error failing stale backups: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *backupRepo) update(ctx context.Context, id int, status, query string, args ...any) error {
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("backup_id", id).Str("status", status).Msg("Error updating backup")
+		return fmt.Errorf("error marking backup %d %s: %w", id, status, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// internal/repository/columns.go
+package repository
+
+// Daftar kolom SELECT yang teksnya khusus PostgreSQL (cast DATE ke text, ARRAY, NUMERIC). Urutan
+// kolomnya sama dengan destinasi Scan di registry shared (internal/repository/internal/shared),
+// yang juga memuat daftar kolom lain yang sama untuk semua driver beserta alias tabelnya.
+//
+// Teks query yang disusun dari registry bersifat konstan per method, sehingga cache
+// prepared statement bawaan pgx (QueryExecModeCacheStatement) tetap efektif.
+
+// --- users ---
+
+// Kolom DATE masa akses di-cast ke text (YYYY-MM-DD) agar bisa di-scan langsung ke *string.
+// Destinasi: shared.UserDest.
+var userColumns = []string{
+	"id", "username", "password", "email", "phone", "national_id",
+	"first_name", "last_name", "role_id", "user_type", "access_valid_from::text", "access_valid_until::text",
+	"is_active", "hire_date::text", "employment_status", "probation_end::text", "manager_id",
+	"token_version", "password_reset_required", "must_change_password", "phone_verified_at", "sms_two_factor", "sms_reminders",
+	"remote_days", "field_work_allowed", "version", "created_at", "updated_at",
+}
+
+// userSummaryColumns adalah data user ringkas untuk JOIN di laporan/listing.
+// Tipe user, akhir masa akses & status aktif ikut disertakan agar listing jadwal menampilkan akhir kontrak;
+// status kepegawaian memberi konteks baris laporan absensi.
+// Email masih terenkripsi; panggil shared.DecryptUserPII setelah scan. Destinasi: shared.UserSummaryDest.
+var userSummaryColumns = []string{
+	"id", "username", "email", "first_name", "last_name", "user_type", "access_valid_until::text", "is_active",
+	"employment_status",
+}
+
+// --- attendances ---
+
+// attendanceTagsColumn mengumpulkan tag record (attendance_tags) sebagai text[] untuk
+// Attendance.Tags; dipakai sebagai kolom extra setelah shared.AttendanceColumns (alias a).
+const attendanceTagsColumn = `ARRAY(SELECT atg.tag FROM attendance_tags atg WHERE atg.attendance_id = a.id ORDER BY atg.tag)`
+
+// --- attendance_face_checks ---
+
+// score (NUMERIC) di-cast ke float8 agar bisa di-scan ke *float64. Destinasi: shared.FaceCheckDest.
+var faceCheckColumns = []string{"attendance_id", "provider", "status", "score::float8", "review_status", "reviewed_by", "reviewed_at", "created_at"}
+
+// --- payroll_periods ---
+
+// start_date/end_date (DATE) di-cast ke text (YYYY-MM-DD).
+var payrollPeriodColumns = []string{
+	"id", "start_date::text", "end_date::text", "status", "closed_at", "closed_by",
+	"reopened_at", "reopened_by", "reopen_reason", "created_by", "created_at", "updated_at",
+}
+
+// --- attendance_signoffs ---
+
+// work_date (DATE) di-cast ke text (YYYY-MM-DD).
+var attendanceSignOffColumns = []string{"id", "user_id", "work_date::text", "signed_by", "signed_at", "exceptions", "note"}
+
+// --- approval_delegations ---
+
+var approvalDelegationColumns = []string{
+	"id", "delegator_id", "delegate_id", "start_date::text", "end_date::text", "reason", "created_at", "revoked_at",
+}
+
+// --- report_snapshots ---
+
+// Kolom rows (isi snapshot) hanya dibaca pada detail snapshot, sebagai kolom tambahan.
+var reportSnapshotColumns = []string{
+	"id", "label", "start_date::text", "end_date::text", "payroll_period_id", "row_count", "checksum", "created_by", "created_at",
+}
@@ -0,0 +1,13 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/pgtest"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/repotest"
+)
+
+// TestRepositoryContract menjalankan suite kontrak repository terhadap PostgreSQL (lihat pgtest).
+func TestRepositoryContract(t *testing.T) {
+	repotest.RunRepositoryContract(t, func(t *testing.T) repotest.Repos { return pgtest.New(t).Repos })
+}
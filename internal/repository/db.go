@@ -0,0 +1,21 @@
+// internal/repository/db.go
+package repository
+
+import "github.com/jackc/pgx/v5/pgxpool"
+
+// Pools adalah connection pool yang dipakai repository.
+// Tulis, transaksi, dan baca yang harus konsisten dengan tulis (by ID, login, check-in)
+// selalu ke Primary. Query baca berat (laporan, listing, ekspor) diarahkan ke Replica
+// jika dikonfigurasi; replica bisa sedikit tertinggal (replication lag).
+type Pools struct {
+	Primary *pgxpool.Pool
+	Replica *pgxpool.Pool // Opsional; nil = semua query ke Primary.
+}
+
+// reader mengembalikan pool untuk query baca read-only.
+func (p Pools) reader() *pgxpool.Pool {
+	if p.Replica != nil {
+		return p.Replica
+	}
+	return p.Primary
+}
@@ -0,0 +1,128 @@
+// internal/repository/debug_capture_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type debugCaptureRepo struct {
+	db Conn // Primary: rekaman dibaca admin sesaat setelah request yang direkam
+}
+
+func NewDebugCaptureRepository(pools Pools) DebugCaptureRepository {
+	return &debugCaptureRepo{db: pools.Primary}
+}
+
+// CreateDebugCapture menyimpan satu rekaman request/response dan mengisi ID & created_at.
+func (r *debugCaptureRepo) CreateDebugCapture(ctx context.Context, capture *models.DebugCapture) error {
+	query := `INSERT INTO debug_captures (request_id, user_id, method, route, path, query, status, latency_ms, error, ip,
+                  request_headers, request_body, response_headers, response_body, expires_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+              RETURNING id, created_at`
+	err := r.db.QueryRow(ctx, query, capture.RequestID, capture.UserID, capture.Method, capture.Route, capture.Path, capture.Query,
+		capture.Status, capture.LatencyMs, capture.Error, capture.IP, headersOrEmpty(capture.RequestHeaders), capture.RequestBody,
+		headersOrEmpty(capture.ResponseHeaders), capture.ResponseBody, capture.ExpiresAt).Scan(&capture.ID, &capture.CreatedAt)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("route", capture.Route).Msg("Error creating debug capture")
+		return fmt.Errorf("error creating debug capture: %w", err)
+	}
+	return nil
+}
+
+// GetDebugCaptureByID mengembalikan satu rekaman beserta header & body-nya, atau pgx.ErrNoRows
+// (termasuk rekaman yang sudah kedaluwarsa tetapi belum dihapus job retensi).
+func (r *debugCaptureRepo) GetDebugCaptureByID(ctx context.Context, id int64) (*models.DebugCapture, error) {
+	query := `SELECT ` + shared.SelectList("dc", shared.DebugCaptureColumns) + `, dc.request_headers, dc.request_body, dc.response_headers, dc.response_body
+              FROM debug_captures dc
+              WHERE dc.id = $1 AND dc.expires_at > NOW()`
+	capture := &models.DebugCapture{}
+	err := shared.ScanDebugCapture(r.db.QueryRow(ctx, query, id), capture,
+		&capture.RequestHeaders, &capture.RequestBody, &capture.ResponseHeaders, &capture.ResponseBody)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int64("debug_capture_id", id).Msg("Error getting debug capture")
+		return nil, fmt.Errorf("error getting debug capture %d: %w", id, err)
+	}
+	return capture, nil
+}
+
+// GetDebugCaptures mengembalikan rekaman yang belum kedaluwarsa (tanpa header & body), terbaru dulu.
+func (r *debugCaptureRepo) GetDebugCaptures(ctx context.Context, filter models.DebugCaptureFilter, page, limit int) ([]models.DebugCapture, int, error) {
+	conditions := []string{"dc.expires_at > NOW()"}
+	var args []any
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("dc.user_id = $%d", len(args)))
+	}
+	if filter.Route != "" {
+		args = append(args, filter.Route)
+		conditions = append(conditions, fmt.Sprintf("dc.route = $%d", len(args)))
+	}
+	if filter.Method != "" {
+		args = append(args, filter.Method)
+		conditions = append(conditions, fmt.Sprintf("dc.method = $%d", len(args)))
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM debug_captures dc`+where, args...).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting debug captures")
+		return nil, 0, fmt.Errorf("error counting debug captures: %w", err)
+	}
+	if total == 0 {
+		return []models.DebugCapture{}, 0, nil
+	}
+
+	query := `SELECT ` + shared.SelectList("dc", shared.DebugCaptureColumns) + ` FROM debug_captures dc` + where +
+		fmt.Sprintf(` ORDER BY dc.created_at DESC, dc.id DESC LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(ctx, query, append(args, limit, shared.PageOffset(page, limit))...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying debug captures")
+		return nil, 0, fmt.Errorf("error getting debug captures: %w", err)
+	}
+	defer rows.Close()
+
+	captures := []models.DebugCapture{}
+	for rows.Next() {
+		var capture models.DebugCapture
+		if err := shared.ScanDebugCapture(rows, &capture); err != nil {
+			return nil, 0, fmt.Errorf("error scanning debug capture row: %w", err)
+		}
+		captures = append(captures, capture)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating debug capture rows: %w", err)
+	}
+	return captures, total, nil
+}
+
+// DeleteExpiredDebugCaptures menghapus maksimal limit rekaman yang expires_at-nya sebelum before
+// dan mengembalikan jumlahnya. Sisa batch dihapus di putaran berikutnya.
+func (r *debugCaptureRepo) DeleteExpiredDebugCaptures(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `DELETE FROM debug_captures
+              WHERE id IN (SELECT id FROM debug_captures WHERE expires_at < $1 ORDER BY expires_at LIMIT $2)`
+	tag, err := r.db.Exec(ctx, query, before, limit)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Time("before", before).Msg("Error deleting expired debug captures")
+		return 0, fmt.Errorf("error deleting expired debug captures: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// headersOrEmpty menyimpan header nil sebagai objek JSON kosong (kolom NOT NULL).
+func headersOrEmpty(headers map[string]string) map[string]string {
+	if headers == nil {
+		return map[string]string{}
+	}
+	return headers
+}
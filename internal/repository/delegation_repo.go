@@ -0,0 +1,110 @@
+// internal/repository/delegation_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// ErrDelegationRevoked dikembalikan saat mencabut delegasi yang sudah dicabut.
+var ErrDelegationRevoked = errors.New("delegation is already revoked")
+
+type delegationRepo struct {
+	db Conn // Primary: pengecekan wewenang harus melihat delegasi terbaru
+}
+
+func NewDelegationRepository(pools Pools) DelegationRepository {
+	return &delegationRepo{db: pools.Primary}
+}
+
+// CreateDelegation melimpahkan wewenang approval delegatorID ke input.DelegateID.
+// Mengembalikan pgx.ErrNoRows jika delegate tidak ada atau tidak aktif.
+func (r *delegationRepo) CreateDelegation(ctx context.Context, delegatorID int, input models.DelegationInput) (*models.ApprovalDelegation, error) {
+	query := `INSERT INTO approval_delegations AS dg (delegator_id, delegate_id, start_date, end_date, reason)
+              SELECT $1, u.id, $3::date, $4::date, $5 FROM users u WHERE u.id = $2 AND u.is_active
+              RETURNING ` + shared.SelectList("dg", approvalDelegationColumns)
+	d := &models.ApprovalDelegation{}
+	err := shared.ScanApprovalDelegation(r.db.QueryRow(ctx, query, delegatorID, input.DelegateID, input.StartDate, input.EndDate, input.Reason), d)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("delegator_id", delegatorID).Int("delegate_id", input.DelegateID).Msg("Error creating approval delegation")
+		return nil, fmt.Errorf("error creating delegation from user %d: %w", delegatorID, err)
+	}
+	shared.Logger(ctx).Info().Int("delegation_id", d.ID).Int("delegator_id", delegatorID).Int("delegate_id", d.DelegateID).Msg("Approval delegation created")
+	return d, nil
+}
+
+// GetDelegationsByUser mengembalikan delegasi yang diberikan maupun diterima user, terbaru dulu.
+func (r *delegationRepo) GetDelegationsByUser(ctx context.Context, userID int) ([]models.ApprovalDelegation, error) {
+	query := `SELECT ` + shared.SelectList("dg", approvalDelegationColumns) + `
+              FROM approval_delegations dg
+              WHERE dg.delegator_id = $1 OR dg.delegate_id = $1
+              ORDER BY dg.created_at DESC, dg.id DESC`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error querying approval delegations")
+		return nil, fmt.Errorf("error getting delegations of user %d: %w", userID, err)
+	}
+	defer rows.Close()
+	delegations := []models.ApprovalDelegation{}
+	for rows.Next() {
+		var d models.ApprovalDelegation
+		if err := shared.ScanApprovalDelegation(rows, &d); err != nil {
+			return nil, fmt.Errorf("error scanning delegation row: %w", err)
+		}
+		delegations = append(delegations, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating delegation rows: %w", err)
+	}
+	return delegations, nil
+}
+
+// RevokeDelegation mencabut delegasi milik delegatorID. Mengembalikan pgx.ErrNoRows jika
+// delegasi tidak ada atau bukan miliknya, ErrDelegationRevoked jika sudah dicabut.
+func (r *delegationRepo) RevokeDelegation(ctx context.Context, id, delegatorID int) (*models.ApprovalDelegation, error) {
+	query := `UPDATE approval_delegations dg SET revoked_at = CURRENT_TIMESTAMP
+              WHERE dg.id = $1 AND dg.delegator_id = $2 AND dg.revoked_at IS NULL
+              RETURNING ` + shared.SelectList("dg", approvalDelegationColumns)
+	d := &models.ApprovalDelegation{}
+	err := shared.ScanApprovalDelegation(r.db.QueryRow(ctx, query, id, delegatorID), d)
+	if err == nil {
+		shared.Logger(ctx).Info().Int("delegation_id", id).Int("delegator_id", delegatorID).Msg("Approval delegation revoked")
+		return d, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		shared.Logger(ctx).Error().Err(err).Int("delegation_id", id).Msg("Error revoking approval delegation")
+		return nil, fmt.Errorf("error revoking delegation %d: %w", id, err)
+	}
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM approval_delegations WHERE id = $1 AND delegator_id = $2)`, id, delegatorID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking delegation id %d: %w", id, err)
+	}
+	if !exists {
+		return nil, pgx.ErrNoRows
+	}
+	return nil, ErrDelegationRevoked
+}
+
+// HasActiveDelegation melaporkan apakah delegateID memegang delegasi dari delegatorID yang
+// belum dicabut dan rentang tanggalnya mencakup day.
+func (r *delegationRepo) HasActiveDelegation(ctx context.Context, delegatorID, delegateID int, day time.Time) (bool, error) {
+	query := `SELECT EXISTS (
+                  SELECT 1 FROM approval_delegations
+                  WHERE delegator_id = $1 AND delegate_id = $2 AND revoked_at IS NULL
+                    AND $3::date BETWEEN start_date AND end_date)`
+	var active bool
+	if err := r.db.QueryRow(ctx, query, delegatorID, delegateID, day.Format(shared.DateLayout)).Scan(&active); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("delegator_id", delegatorID).Int("delegate_id", delegateID).Msg("Error checking approval delegation")
+		return false, fmt.Errorf("error checking delegation from user %d to user %d: %w", delegatorID, delegateID, err)
+	}
+	return active, nil
+}
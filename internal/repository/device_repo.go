@@ -0,0 +1,134 @@
+// internal/repository/device_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type deviceRepo struct {
+	db   Conn // Primary: tulis & baca konsisten
+	read Conn // Replica (atau Primary jika tidak ada) untuk job pengingat
+}
+
+func NewDeviceRepository(pools Pools) DeviceRepository {
+	return &deviceRepo{db: pools.Primary, read: pools.reader()}
+}
+
+// RegisterDeviceToken menyimpan token perangkat untuk userID. Token yang sudah terdaftar
+// (termasuk milik user lain, mis. perangkat berganti akun) dipindahkan ke userID.
+func (r *deviceRepo) RegisterDeviceToken(ctx context.Context, userID int, platform, token string) (*models.DeviceToken, error) {
+	query := `INSERT INTO device_tokens AS dt (user_id, platform, token) VALUES ($1, $2, $3)
+              ON CONFLICT (token) DO UPDATE SET user_id = EXCLUDED.user_id, platform = EXCLUDED.platform, updated_at = CURRENT_TIMESTAMP
+              RETURNING ` + shared.SelectList("dt", shared.DeviceTokenColumns)
+	d := &models.DeviceToken{}
+	if err := shared.ScanDeviceToken(r.db.QueryRow(ctx, query, userID, platform, token), d); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Str("platform", platform).Msg("Error registering device token")
+		return nil, fmt.Errorf("error registering device token for user %d: %w", userID, err)
+	}
+	return d, nil
+}
+
+// DeleteDeviceToken menghapus token milik userID. Mengembalikan pgx.ErrNoRows jika token
+// tidak terdaftar untuk user tersebut.
+func (r *deviceRepo) DeleteDeviceToken(ctx context.Context, userID int, token string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM device_tokens WHERE user_id = $1 AND token = $2`, userID, token)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error deleting device token")
+		return fmt.Errorf("error deleting device token of user %d: %w", userID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetDeviceTokensByUser mengembalikan token perangkat milik user, terbaru dulu.
+func (r *deviceRepo) GetDeviceTokensByUser(ctx context.Context, userID int) ([]models.DeviceToken, error) {
+	query := `SELECT ` + shared.SelectList("dt", shared.DeviceTokenColumns) + `
+              FROM device_tokens dt WHERE dt.user_id = $1 ORDER BY dt.updated_at DESC`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting device tokens of user %d: %w", userID, err)
+	}
+	defer rows.Close()
+	tokens := []models.DeviceToken{}
+	for rows.Next() {
+		var d models.DeviceToken
+		if err := shared.ScanDeviceToken(rows, &d); err != nil {
+			return nil, fmt.Errorf("error scanning device token row: %w", err)
+		}
+		tokens = append(tokens, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating device token rows: %w", err)
+	}
+	return tokens, nil
+}
+
+// DeleteDeviceTokens menghapus token yang ditolak provider push (sudah tidak terdaftar).
+func (r *deviceRepo) DeleteDeviceTokens(ctx context.Context, tokens []string) (int, error) {
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+	tag, err := r.db.Exec(ctx, `DELETE FROM device_tokens WHERE token = ANY($1)`, tokens)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting invalid device tokens: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// GetPendingShiftReminders mengembalikan jadwal user aktif ber-token perangkat atau berlangganan
+// pengingat SMS pada tanggal [fromDate, toDate] yang belum dikirimi pengingat. Jam mulai disaring oleh pemanggil.
+func (r *deviceRepo) GetPendingShiftReminders(ctx context.Context, fromDate, toDate time.Time) ([]models.ShiftReminder, error) {
+	query := `
+        SELECT us.id, us.user_id, us.date::text, s.name, s.start_time::text,
+               u.sms_reminders AND u.phone_verified_at IS NOT NULL
+        FROM user_schedules us
+        CROSS JOIN LATERAL schedule_shift(us.shift_id, us.date) s
+        JOIN users u ON u.id = us.user_id
+        WHERE us.date BETWEEN $1::date AND $2::date
+          AND u.is_active
+          AND (EXISTS (SELECT 1 FROM device_tokens dt WHERE dt.user_id = us.user_id)
+               OR (u.sms_reminders AND u.phone_verified_at IS NOT NULL))
+          AND NOT EXISTS (SELECT 1 FROM push_reminders pr WHERE pr.schedule_id = us.id)
+        ORDER BY us.date, s.start_time`
+	rows, err := r.read.Query(ctx, query, fromDate.Format(shared.DateLayout), toDate.Format(shared.DateLayout))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying pending shift reminders")
+		return nil, fmt.Errorf("error getting pending shift reminders: %w", err)
+	}
+	defer rows.Close()
+	reminders := []models.ShiftReminder{}
+	for rows.Next() {
+		var sr models.ShiftReminder
+		if err := rows.Scan(&sr.ScheduleID, &sr.UserID, &sr.Date, &sr.ShiftName, &sr.StartTime, &sr.SMS); err != nil {
+			return nil, fmt.Errorf("error scanning shift reminder row: %w", err)
+		}
+		reminders = append(reminders, sr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shift reminder rows: %w", err)
+	}
+	return reminders, nil
+}
+
+// MarkShiftReminderSent mencatat pengingat jadwal. Mengembalikan false jika sudah dicatat
+// sebelumnya (mis. oleh instance lain), sehingga pengingat tidak dikirim dua kali.
+func (r *deviceRepo) MarkShiftReminderSent(ctx context.Context, scheduleID int) (bool, error) {
+	var inserted bool
+	err := r.db.QueryRow(ctx, `INSERT INTO push_reminders (schedule_id) VALUES ($1) ON CONFLICT DO NOTHING RETURNING true`, scheduleID).Scan(&inserted)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error marking shift reminder for schedule %d: %w", scheduleID, err)
+	}
+	return inserted, nil
+}
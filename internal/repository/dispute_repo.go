@@ -0,0 +1,166 @@
+// internal/repository/dispute_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// ErrDisputeAlreadyOpen dikembalikan jika record absensi sudah punya dispute berstatus open.
+var ErrDisputeAlreadyOpen = errors.New("attendance record already has an open dispute")
+
+// ErrDisputeNotOpen dikembalikan saat menyelesaikan dispute yang sudah resolved/rejected.
+var ErrDisputeNotOpen = errors.New("dispute is no longer open")
+
+// openDisputeConstraint adalah unique index parsial (attendance_id WHERE status = 'open').
+const openDisputeConstraint = "uq_attendance_disputes_open"
+
+type disputeRepo struct {
+	db   Conn // Primary: tulis & baca konsisten
+	read Conn // Replica (atau Primary jika tidak ada) untuk listing
+}
+
+func NewDisputeRepository(pools Pools) DisputeRepository {
+	return &disputeRepo{db: pools.Primary, read: pools.reader()}
+}
+
+// CreateDispute membuat dispute open atas record absensi milik userID. Mengembalikan
+// pgx.ErrNoRows jika record tidak ada atau bukan milik user, ErrDisputeAlreadyOpen jika
+// record sudah punya dispute open.
+func (r *disputeRepo) CreateDispute(ctx context.Context, attendanceID, userID int, comment string) (*models.AttendanceDispute, error) {
+	query := `INSERT INTO attendance_disputes AS ad (attendance_id, user_id, comment)
+              SELECT a.id, a.user_id, $3 FROM attendances a WHERE a.id = $1 AND a.user_id = $2
+              RETURNING ` + shared.SelectList("ad", shared.AttendanceDisputeColumns)
+	d := &models.AttendanceDispute{}
+	if err := shared.ScanAttendanceDispute(conn(ctx, r.db).QueryRow(ctx, query, attendanceID, userID, comment), d); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == openDisputeConstraint {
+			return nil, ErrDisputeAlreadyOpen
+		}
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", attendanceID).Int("user_id", userID).Msg("Error creating attendance dispute")
+		return nil, fmt.Errorf("error creating dispute for attendance id %d: %w", attendanceID, err)
+	}
+	shared.Logger(ctx).Info().Int("dispute_id", d.ID).Int("attendance_id", attendanceID).Int("user_id", userID).Msg("Attendance dispute created")
+	return d, nil
+}
+
+func (r *disputeRepo) GetDisputeByID(ctx context.Context, id int) (*models.AttendanceDispute, error) {
+	query := `SELECT ` + shared.SelectList("ad", shared.AttendanceDisputeColumns) + ` FROM attendance_disputes ad WHERE ad.id = $1`
+	d := &models.AttendanceDispute{}
+	if err := shared.ScanAttendanceDispute(r.db.QueryRow(ctx, query, id), d); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("dispute_id", id).Msg("Error getting dispute by ID")
+		return nil, fmt.Errorf("error getting dispute by id %d: %w", id, err)
+	}
+	return d, nil
+}
+
+// GetDisputesByUser mengembalikan semua dispute milik user, terbaru dulu.
+func (r *disputeRepo) GetDisputesByUser(ctx context.Context, userID int) ([]models.AttendanceDispute, error) {
+	query := `SELECT ` + shared.SelectList("ad", shared.AttendanceDisputeColumns) + `
+              FROM attendance_disputes ad WHERE ad.user_id = $1 ORDER BY ad.created_at DESC, ad.id DESC`
+	rows, err := r.read.Query(ctx, query, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error querying user disputes")
+		return nil, fmt.Errorf("error getting disputes of user %d: %w", userID, err)
+	}
+	return collectDisputes(rows)
+}
+
+// GetAllDisputes mengembalikan dispute (status kosong = semua status), terlama dulu agar
+// antrean penyelesaian diproses berurutan.
+func (r *disputeRepo) GetAllDisputes(ctx context.Context, status string, page, limit int) ([]models.AttendanceDispute, int, error) {
+	var total int
+	if err := r.read.QueryRow(ctx, `SELECT COUNT(*) FROM attendance_disputes WHERE ($1 = '' OR status = $1)`, status).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting disputes")
+		return nil, 0, fmt.Errorf("error counting disputes: %w", err)
+	}
+	if total == 0 {
+		return []models.AttendanceDispute{}, 0, nil
+	}
+
+	query := `SELECT ` + shared.SelectList("ad", shared.AttendanceDisputeColumns) + `
+              FROM attendance_disputes ad
+              WHERE ($1 = '' OR ad.status = $1)
+              ORDER BY ad.created_at ASC, ad.id ASC
+              LIMIT $2 OFFSET $3`
+	rows, err := r.read.Query(ctx, query, status, limit, shared.PageOffset(page, limit))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying disputes")
+		return nil, 0, fmt.Errorf("error querying disputes: %w", err)
+	}
+	disputes, err := collectDisputes(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return disputes, total, nil
+}
+
+// GetOpenDisputesBefore mengembalikan dispute open yang dibuat sebelum before, terlama dulu
+// (maksimal limit), untuk job eskalasi SLA.
+func (r *disputeRepo) GetOpenDisputesBefore(ctx context.Context, before time.Time, limit int) ([]models.AttendanceDispute, error) {
+	query := `SELECT ` + shared.SelectList("ad", shared.AttendanceDisputeColumns) + `
+              FROM attendance_disputes ad
+              WHERE ad.status = $1 AND ad.created_at < $2
+              ORDER BY ad.created_at ASC, ad.id ASC
+              LIMIT $3`
+	rows, err := r.db.Query(ctx, query, models.DisputeOpen, before, limit)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying stale disputes")
+		return nil, fmt.Errorf("error querying open disputes before %s: %w", before.Format(time.RFC3339), err)
+	}
+	return collectDisputes(rows)
+}
+
+func collectDisputes(rows pgx.Rows) ([]models.AttendanceDispute, error) {
+	defer rows.Close()
+	disputes := []models.AttendanceDispute{}
+	for rows.Next() {
+		var d models.AttendanceDispute
+		if err := shared.ScanAttendanceDispute(rows, &d); err != nil {
+			return nil, fmt.Errorf("error scanning dispute row: %w", err)
+		}
+		disputes = append(disputes, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating dispute rows: %w", err)
+	}
+	return disputes, nil
+}
+
+// ResolveDispute menutup dispute open dengan status resolved/rejected dan catatan penyelesaian.
+// Mengembalikan pgx.ErrNoRows jika dispute tidak ada, atau ErrDisputeNotOpen jika sudah ditutup.
+func (r *disputeRepo) ResolveDispute(ctx context.Context, id int, actorUserID int, status, note string) (*models.AttendanceDispute, error) {
+	query := `UPDATE attendance_disputes ad SET status = $2, resolution_note = $3, resolved_by = $4, resolved_at = CURRENT_TIMESTAMP
+              WHERE ad.id = $1 AND ad.status = $5
+              RETURNING ` + shared.SelectList("ad", shared.AttendanceDisputeColumns)
+	d := &models.AttendanceDispute{}
+	err := shared.ScanAttendanceDispute(conn(ctx, r.db).QueryRow(ctx, query, id, status, note, actorUserID, models.DisputeOpen), d)
+	if err == nil {
+		shared.Logger(ctx).Info().Int("dispute_id", id).Str("status", status).Int("actor_id", actorUserID).Msg("Attendance dispute resolved")
+		return d, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		shared.Logger(ctx).Error().Err(err).Int("dispute_id", id).Msg("Error resolving dispute")
+		return nil, fmt.Errorf("error resolving dispute %d: %w", id, err)
+	}
+	var exists bool
+	if err := conn(ctx, r.db).QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM attendance_disputes WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking dispute id %d: %w", id, err)
+	}
+	if !exists {
+		return nil, pgx.ErrNoRows
+	}
+	return nil, ErrDisputeNotOpen
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// ErrDocumentExists dikembalikan CreateDocument jika storage key sudah tercatat (mis. token
+// unggahan langsung dipakai dua kali).
+var ErrDocumentExists = errors.New("document is already registered")
+
+const documentStorageKeyConstraint = "documents_storage_key_key"
+
+type documentRepo struct {
+	db Conn // Primary: dokumen dibaca tepat setelah diunggah
+}
+
+func NewDocumentRepository(pools Pools) DocumentRepository {
+	return &documentRepo{db: pools.Primary}
+}
+
+func (r *documentRepo) CreateDocument(ctx context.Context, doc *models.Document) (int, error) {
+	query := `INSERT INTO documents (owner_user_id, subject_type, subject_id, file_name, content_type, size_bytes, sha256, storage_key, scan_status)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+              RETURNING id, created_at`
+	err := r.db.QueryRow(ctx, query,
+		doc.OwnerUserID, doc.SubjectType, doc.SubjectID, doc.FileName, doc.ContentType,
+		doc.SizeBytes, doc.SHA256, doc.StorageKey, doc.ScanStatus,
+	).Scan(&doc.ID, &doc.CreatedAt)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == documentStorageKeyConstraint {
+			return 0, ErrDocumentExists
+		}
+		shared.Logger(ctx).Error().Err(err).Int("owner_user_id", doc.OwnerUserID).Msg("Error creating document")
+		return 0, fmt.Errorf("error creating document: %w", err)
+	}
+	return doc.ID, nil
+}
+
+func (r *documentRepo) GetDocumentByID(ctx context.Context, id int) (*models.Document, error) {
+	query := `SELECT ` + shared.SelectList("d", shared.DocumentColumns) + ` FROM documents d WHERE d.id = $1`
+	doc := &models.Document{}
+	if err := shared.ScanDocument(r.db.QueryRow(ctx, query, id), doc); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("document_id", id).Msg("Error getting document by ID")
+		return nil, fmt.Errorf("error getting document by id %d: %w", id, err)
+	}
+	return doc, nil
+}
+
+func (r *documentRepo) GetDocumentsBySubject(ctx context.Context, subjectType string, subjectID int) ([]models.Document, error) {
+	query := `SELECT ` + shared.SelectList("d", shared.DocumentColumns) + `
+              FROM documents d
+              WHERE d.subject_type = $1 AND d.subject_id = $2
+              ORDER BY d.id ASC`
+	rows, err := r.db.Query(ctx, query, subjectType, subjectID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("subject_type", subjectType).Int("subject_id", subjectID).Msg("Error querying documents")
+		return nil, fmt.Errorf("error getting documents for %s %d: %w", subjectType, subjectID, err)
+	}
+	defer rows.Close()
+
+	docs := []models.Document{}
+	for rows.Next() {
+		var doc models.Document
+		if err := shared.ScanDocument(rows, &doc); err != nil {
+			shared.Logger(ctx).Error().Err(err).Msg("Error scanning document row")
+			return nil, fmt.Errorf("error scanning document row: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating document rows: %w", err)
+	}
+	return docs, nil
+}
+
+func (r *documentRepo) DeleteDocument(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM documents WHERE id = $1`, id)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("document_id", id).Msg("Error deleting document")
+		return fmt.Errorf("error deleting document id %d: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// internal/repository/escalation_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type escalationRepo struct {
+	db   Conn // Primary: tulis & pengecekan level job
+	read Conn // Replica (atau Primary jika tidak ada) untuk approval inbox
+}
+
+func NewEscalationRepository(pools Pools) EscalationRepository {
+	return &escalationRepo{db: pools.Primary, read: pools.reader()}
+}
+
+// RecordEscalation mencatat satu langkah eskalasi dan mengisi ID & CreatedAt. Mengembalikan
+// false jika level tersebut sudah dicatat (mis. oleh instance lain). Ikut transaksi RunInTx.
+func (r *escalationRepo) RecordEscalation(ctx context.Context, e *models.ApprovalEscalation) (bool, error) {
+	query := `INSERT INTO approval_escalations (item_type, item_id, level, action, escalated_to)
+              VALUES ($1, $2, $3, $4, $5)
+              ON CONFLICT (item_type, item_id, level) DO NOTHING
+              RETURNING id, created_at`
+	err := conn(ctx, r.db).QueryRow(ctx, query, e.ItemType, e.ItemID, e.Level, e.Action, e.EscalatedTo).Scan(&e.ID, &e.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("item_type", e.ItemType).Int("item_id", e.ItemID).Msg("Error recording approval escalation")
+		return false, fmt.Errorf("error recording escalation of %s %d: %w", e.ItemType, e.ItemID, err)
+	}
+	return true, nil
+}
+
+// GetEscalations mengembalikan riwayat eskalasi item-item itemIDs bertipe itemType, per item
+// terlama dulu. Item tanpa eskalasi tidak ada di map.
+func (r *escalationRepo) GetEscalations(ctx context.Context, itemType string, itemIDs []int) (map[int][]models.ApprovalEscalation, error) {
+	history := map[int][]models.ApprovalEscalation{}
+	if len(itemIDs) == 0 {
+		return history, nil
+	}
+	query := `SELECT ` + shared.SelectList("ae", shared.ApprovalEscalationColumns) + `
+              FROM approval_escalations ae
+              WHERE ae.item_type = $1 AND ae.item_id = ANY($2)
+              ORDER BY ae.item_id, ae.level`
+	rows, err := r.read.Query(ctx, query, itemType, itemIDs)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("item_type", itemType).Msg("Error querying approval escalations")
+		return nil, fmt.Errorf("error getting escalations of %s items: %w", itemType, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e models.ApprovalEscalation
+		if err := shared.ScanApprovalEscalation(rows, &e); err != nil {
+			return nil, fmt.Errorf("error scanning escalation row: %w", err)
+		}
+		history[e.ItemID] = append(history[e.ItemID], e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalation rows: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,78 @@
+// internal/repository/includes.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Pemuat batch untuk resource yang di-embed lewat ?include= (lihat handlers/includes.go):
+// satu query per jenis resource untuk seluruh halaman listing, bukan satu query per baris.
+
+// GetUserSummariesByIDs mengembalikan ringkasan user (userSummaryColumns, email sudah
+// didekripsi) untuk ids. User yang tidak ada dilewati.
+func (r *userRepo) GetUserSummariesByIDs(ctx context.Context, ids []int) ([]models.User, error) {
+	users := []models.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+	query := `SELECT ` + shared.SelectList("u", userSummaryColumns) + ` FROM users u WHERE u.id = ANY($1)`
+	rows, err := r.read.Query(ctx, query, ids)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("count", len(ids)).Msg("Error querying user summaries by IDs")
+		return nil, fmt.Errorf("error getting user summaries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(shared.UserSummaryDest(&user)...); err != nil {
+			return nil, fmt.Errorf("error scanning user summary: %w", err)
+		}
+		if err := shared.DecryptUserPII(r.pii, &user); err != nil {
+			shared.Logger(ctx).Error().Err(err).Int("user_id", user.ID).Msg("Error decrypting user PII (summary)")
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user summaries: %w", err)
+	}
+	return users, nil
+}
+
+// GetSchedulesByIDs mengembalikan jadwal untuk ids beserta ringkasan shift efektifnya
+// (termasuk override musiman). Jadwal yang tidak ada dilewati.
+func (r *scheduleRepo) GetSchedulesByIDs(ctx context.Context, ids []int) ([]models.UserSchedule, error) {
+	schedules := []models.UserSchedule{}
+	if len(ids) == 0 {
+		return schedules, nil
+	}
+	query := `
+        SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+               ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `
+        FROM user_schedules us
+        CROSS JOIN LATERAL schedule_shift(us.shift_id, us.date) s
+        WHERE us.id = ANY($1)`
+	rows, err := r.read.Query(ctx, query, ids)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("count", len(ids)).Msg("Error querying schedules by IDs")
+		return nil, fmt.Errorf("error getting schedules by ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		schedule := models.UserSchedule{Shift: &models.Shift{}}
+		if err := shared.ScanSchedule(rows, &schedule, shared.ShiftSummaryDest(schedule.Shift)...); err != nil {
+			return nil, fmt.Errorf("error scanning schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schedules: %w", err)
+	}
+	return schedules, nil
+}
@@ -0,0 +1,426 @@
+// internal/repository/internal/shared/columns.go
+package shared
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// Registry kolom SELECT dan destinasi Scan per entitas.
+// Daftar kolom (XxxColumns) dan fungsi destinasi/scan (XxxDest/ScanXxx) didefinisikan
+// berdampingan dengan urutan yang sama, sehingga query dan Scan tidak bisa lagi berbeda
+// urutan/kelengkapan kolom antar method. Daftar kolom yang teksnya berbeda per dialek (cast
+// DATE ke text, array, NUMERIC) ada di columns.go masing-masing driver dengan urutan yang sama
+// dengan destinasinya di sini. Query memakai alias tabel tetap:
+// users u, roles r, shifts s, user_schedules us, attendances a, attendance_events e,
+// announcements an, documents d, payroll_periods pp, projects p, attendance_segments sg,
+// attendance_signoffs so, attendance_disputes ad, device_tokens dt,
+// notifications n, outbox_messages o, approval_delegations dg, approval_escalations ae,
+// shift_overrides sov, attendance_face_checks fc, employment_verification_links evl,
+// employment_verification_accesses eva, kiosk_devices kd, attendance_photos ap, report_exports re,
+// email_change_requests ec, username_change_requests ucr, previous_usernames pu, phone_otps po,
+// user_invitations ui, attendance_tags atg, report_snapshots rs, sync_tombstones st.
+//
+// Teks query yang disusun dari registry bersifat konstan per method, sehingga cache
+// prepared statement bawaan pgx (QueryExecModeCacheStatement) tetap efektif.
+
+// RowScanner dipenuhi oleh pgx.Row maupun pgx.Rows.
+type RowScanner interface {
+	Scan(dest ...any) error
+}
+
+// SelectList menyusun "alias.kolom1, alias.kolom2, ..." untuk klausa SELECT.
+func SelectList(alias string, columns []string) string {
+	prefixed := make([]string, len(columns))
+	for i, col := range columns {
+		prefixed[i] = alias + "." + col
+	}
+	return strings.Join(prefixed, ", ")
+}
+
+// --- users ---
+
+// UserDest adalah destinasi Scan untuk kolom userColumns driver.
+func UserDest(u *models.User) []any {
+	return []any{
+		&u.ID, &u.Username, &u.Password, &u.Email, &u.Phone, &u.NationalID,
+		&u.FirstName, &u.LastName, &u.RoleID, &u.UserType, &u.ValidFrom, &u.ValidUntil,
+		&u.IsActive, &u.HireDate, &u.EmploymentStatus, &u.ProbationEnd, &u.ManagerID,
+		&u.TokenVersion, &u.PasswordResetRequired, &u.MustChangePassword, &u.PhoneVerifiedAt, &u.SMSTwoFactor, &u.SMSReminders,
+		&u.RemoteDays, &u.FieldWorkAllowed, &u.Version, &u.CreatedAt, &u.UpdatedAt,
+	}
+}
+
+// UserSummaryDest adalah destinasi Scan untuk kolom userSummaryColumns driver.
+func UserSummaryDest(u *models.User) []any {
+	return []any{&u.ID, &u.Username, &u.Email, &u.FirstName, &u.LastName, &u.UserType, &u.ValidUntil, &u.IsActive, &u.EmploymentStatus}
+}
+
+// ScanUser memindai kolom userColumns (diikuti kolom JOIN di extra) ke u.
+func ScanUser(row RowScanner, u *models.User, extra ...any) error {
+	return row.Scan(append(UserDest(u), extra...)...)
+}
+
+// --- roles ---
+
+var RoleColumns = []string{"id", "name", "parent_id"}
+
+func RoleDest(r *models.Role) []any {
+	return []any{&r.ID, &r.Name, &r.ParentID}
+}
+
+func ScanRole(row RowScanner, r *models.Role) error {
+	return row.Scan(RoleDest(r)...)
+}
+
+// --- shifts ---
+
+// start_time/end_time bertipe TIME dibaca sebagai string (format HH:MM:SS).
+var ShiftColumns = []string{"id", "name", "start_time", "end_time", "version", "created_at", "updated_at"}
+
+func ScanShift(row RowScanner, s *models.Shift) error {
+	return row.Scan(&s.ID, &s.Name, &s.StartTime, &s.EndTime, &s.Version, &s.CreatedAt, &s.UpdatedAt)
+}
+
+// ShiftSummaryColumns adalah data shift ringkas untuk JOIN di jadwal, dibaca dari
+// schedule_shift(shift_id, date) sehingga jam sudah memperhitungkan override musiman.
+var ShiftSummaryColumns = []string{"id", "name", "start_time", "end_time", "override_id"}
+
+func ShiftSummaryDest(s *models.Shift) []any {
+	return []any{&s.ID, &s.Name, &s.StartTime, &s.EndTime, &s.OverrideID}
+}
+
+// --- shift_overrides ---
+
+var ShiftOverrideColumns = []string{"id", "name", "shift_id", "start_date", "end_date", "start_time", "end_time",
+	"start_offset_minutes", "end_offset_minutes", "created_by", "created_at"}
+
+// ScanShiftOverride memindai kolom ShiftOverrideColumns; tanggal diformat ke YYYY-MM-DD.
+func ScanShiftOverride(row RowScanner, o *models.ShiftOverride) error {
+	var start, end time.Time
+	if err := row.Scan(&o.ID, &o.Name, &o.ShiftID, &start, &end, &o.StartTime, &o.EndTime,
+		&o.StartOffsetMinutes, &o.EndOffsetMinutes, &o.CreatedBy, &o.CreatedAt); err != nil {
+		return err
+	}
+	o.StartDate, o.EndDate = start.Format(DateLayout), end.Format(DateLayout)
+	return nil
+}
+
+// --- user_schedules ---
+
+var ScheduleColumns = []string{"id", "user_id", "shift_id", "date", "version", "created_at", "updated_at"}
+
+// ScanSchedule memindai kolom ScheduleColumns (diikuti kolom JOIN di extra) ke s.
+// Kolom date (DATE) diformat ke YYYY-MM-DD.
+func ScanSchedule(row RowScanner, s *models.UserSchedule, extra ...any) error {
+	var date time.Time
+	dest := append([]any{&s.ID, &s.UserID, &s.ShiftID, &date, &s.Version, &s.CreatedAt, &s.UpdatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	s.Date = date.Format(DateLayout)
+	return nil
+}
+
+// --- attendances ---
+
+var AttendanceColumns = []string{"id", "user_id", "schedule_id", "project_id", "check_in_at", "check_out_at", "notes", "created_at", "updated_at", "mode"}
+
+// ScanAttendance memindai kolom AttendanceColumns (diikuti kolom JOIN di extra) ke a.
+// schedule_id, project_id, check_out_at dan notes boleh NULL (*int / *time.Time / *string).
+func ScanAttendance(row RowScanner, a *models.Attendance, extra ...any) error {
+	dest := append([]any{&a.ID, &a.UserID, &a.ScheduleID, &a.ProjectID, &a.CheckInAt, &a.CheckOutAt, &a.Notes, &a.CreatedAt, &a.UpdatedAt, &a.Mode}, extra...)
+	return row.Scan(dest...)
+}
+
+// --- attendance_face_checks ---
+
+// FaceCheckDest adalah destinasi Scan untuk kolom faceCheckColumns driver.
+func FaceCheckDest(fc *models.FaceCheck) []any {
+	return []any{&fc.AttendanceID, &fc.Provider, &fc.Status, &fc.Score, &fc.ReviewStatus, &fc.ReviewedBy, &fc.ReviewedAt, &fc.CreatedAt}
+}
+
+// --- queued_check_in_failures ---
+
+var QueuedCheckInFailureColumns = []string{
+	"id", "user_id", "check_in_at", "notes", "tags", "project_id", "mode", "source", "error",
+	"failed_at", "resolved_by", "resolved_at", "resolution_note",
+}
+
+func QueuedCheckInFailureDest(f *models.QueuedCheckInFailure) []any {
+	return []any{
+		&f.ID, &f.UserID, &f.CheckInAt, &f.Notes, &f.Tags, &f.ProjectID, &f.Mode, &f.Source, &f.Error,
+		&f.FailedAt, &f.ResolvedBy, &f.ResolvedAt, &f.ResolutionNote,
+	}
+}
+
+// --- attendance_events ---
+
+var AttendanceEventColumns = []string{
+	"id", "attendance_id", "user_id", "event_type", "check_in_at", "check_out_at",
+	"notes", "reason", "actor_user_id", "created_at",
+}
+
+func ScanAttendanceEvent(row RowScanner, ev *models.AttendanceEvent) error {
+	return row.Scan(
+		&ev.ID, &ev.AttendanceID, &ev.UserID, &ev.EventType, &ev.CheckInAt, &ev.CheckOutAt,
+		&ev.Notes, &ev.Reason, &ev.ActorUserID, &ev.CreatedAt,
+	)
+}
+
+// --- announcements ---
+
+var AnnouncementColumns = []string{
+	"id", "title", "body", "publish_at", "expires_at", "audience_role_ids",
+	"created_by", "created_at", "updated_at",
+}
+
+func ScanAnnouncement(row RowScanner, a *models.Announcement) error {
+	return row.Scan(
+		&a.ID, &a.Title, &a.Body, &a.PublishAt, &a.ExpiresAt, &a.AudienceRoleIDs,
+		&a.CreatedBy, &a.CreatedAt, &a.UpdatedAt,
+	)
+}
+
+// --- documents ---
+
+var DocumentColumns = []string{
+	"id", "owner_user_id", "subject_type", "subject_id", "file_name", "content_type",
+	"size_bytes", "sha256", "storage_key", "scan_status", "created_at",
+}
+
+func ScanDocument(row RowScanner, d *models.Document) error {
+	return row.Scan(
+		&d.ID, &d.OwnerUserID, &d.SubjectType, &d.SubjectID, &d.FileName, &d.ContentType,
+		&d.SizeBytes, &d.SHA256, &d.StorageKey, &d.ScanStatus, &d.CreatedAt,
+	)
+}
+
+// --- payroll_periods ---
+
+// ScanPayrollPeriod memindai kolom payrollPeriodColumns driver.
+func ScanPayrollPeriod(row RowScanner, p *models.PayrollPeriod) error {
+	return row.Scan(
+		&p.ID, &p.StartDate, &p.EndDate, &p.Status, &p.ClosedAt, &p.ClosedBy,
+		&p.ReopenedAt, &p.ReopenedBy, &p.ReopenReason, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt,
+	)
+}
+
+// --- projects ---
+
+var ProjectColumns = []string{"id", "code", "name", "cost_center", "is_active", "created_at", "updated_at"}
+
+func ScanProject(row RowScanner, p *models.Project) error {
+	return row.Scan(&p.ID, &p.Code, &p.Name, &p.CostCenter, &p.IsActive, &p.CreatedAt, &p.UpdatedAt)
+}
+
+// --- attendance_segments ---
+
+var AttendanceSegmentColumns = []string{"id", "attendance_id", "project_id", "started_at", "ended_at", "created_at"}
+
+func ScanAttendanceSegment(row RowScanner, sg *models.AttendanceSegment) error {
+	return row.Scan(&sg.ID, &sg.AttendanceID, &sg.ProjectID, &sg.StartedAt, &sg.EndedAt, &sg.CreatedAt)
+}
+
+// --- attendance_signoffs ---
+
+// ScanAttendanceSignOff memindai kolom attendanceSignOffColumns driver.
+func ScanAttendanceSignOff(row RowScanner, so *models.AttendanceSignOff) error {
+	return row.Scan(&so.ID, &so.UserID, &so.WorkDate, &so.SignedBy, &so.SignedAt, &so.Exceptions, &so.Note)
+}
+
+// --- attendance_disputes ---
+
+var AttendanceDisputeColumns = []string{
+	"id", "attendance_id", "user_id", "comment", "status", "resolution_note",
+	"resolved_by", "resolved_at", "created_at", "updated_at",
+}
+
+func ScanAttendanceDispute(row RowScanner, d *models.AttendanceDispute) error {
+	return row.Scan(
+		&d.ID, &d.AttendanceID, &d.UserID, &d.Comment, &d.Status, &d.ResolutionNote,
+		&d.ResolvedBy, &d.ResolvedAt, &d.CreatedAt, &d.UpdatedAt,
+	)
+}
+
+// --- device_tokens ---
+
+var DeviceTokenColumns = []string{"id", "user_id", "platform", "token", "created_at", "updated_at"}
+
+func ScanDeviceToken(row RowScanner, d *models.DeviceToken) error {
+	return row.Scan(&d.ID, &d.UserID, &d.Platform, &d.Token, &d.CreatedAt, &d.UpdatedAt)
+}
+
+// --- notifications ---
+
+var NotificationColumns = []string{"id", "user_id", "type", "title", "body", "data", "read_at", "created_at"}
+
+func ScanNotification(row RowScanner, n *models.Notification) error {
+	return row.Scan(&n.ID, &n.UserID, &n.Type, &n.Title, &n.Body, &n.Data, &n.ReadAt, &n.CreatedAt)
+}
+
+// --- outbox_messages ---
+
+var OutboxMessageColumns = []string{
+	"id", "destination", "event_name", "user_id", "payload", "attempts", "last_error",
+	"available_at", "created_at", "dead_at",
+}
+
+func ScanOutboxMessage(row RowScanner, msg *models.OutboxMessage) error {
+	return row.Scan(
+		&msg.ID, &msg.Destination, &msg.EventName, &msg.UserID, &msg.Payload, &msg.Attempts, &msg.LastError,
+		&msg.AvailableAt, &msg.CreatedAt, &msg.DeadAt,
+	)
+}
+
+// --- approval_delegations ---
+
+// ScanApprovalDelegation memindai kolom approvalDelegationColumns driver.
+func ScanApprovalDelegation(row RowScanner, d *models.ApprovalDelegation) error {
+	return row.Scan(&d.ID, &d.DelegatorID, &d.DelegateID, &d.StartDate, &d.EndDate, &d.Reason, &d.CreatedAt, &d.RevokedAt)
+}
+
+// --- approval_escalations ---
+
+var ApprovalEscalationColumns = []string{"id", "item_type", "item_id", "level", "action", "escalated_to", "created_at"}
+
+func ScanApprovalEscalation(row RowScanner, e *models.ApprovalEscalation) error {
+	return row.Scan(&e.ID, &e.ItemType, &e.ItemID, &e.Level, &e.Action, &e.EscalatedTo, &e.CreatedAt)
+}
+
+// --- scheduled_jobs ---
+
+var ScheduledJobColumns = []string{
+	"name", "interval_seconds", "next_run_at", "last_started_at", "last_finished_at", "last_status",
+	"last_trigger", "last_result", "last_error", "last_duration_ms", "run_count", "failure_count",
+}
+
+func ScanScheduledJob(row RowScanner, j *models.ScheduledJob) error {
+	return row.Scan(&j.Name, &j.IntervalSeconds, &j.NextRunAt, &j.LastStartedAt, &j.LastFinishedAt, &j.LastStatus,
+		&j.LastTrigger, &j.LastResult, &j.LastError, &j.LastDurationMs, &j.RunCount, &j.FailureCount)
+}
+
+// --- employment_verification_links / employment_verification_accesses ---
+
+var VerificationLinkColumns = []string{
+	"id", "user_id", "recipient", "include_attendance", "expires_at", "created_by", "created_at",
+	"revoked_at", "access_count", "last_accessed_at",
+}
+
+func ScanVerificationLink(row RowScanner, l *models.EmploymentVerificationLink) error {
+	return row.Scan(&l.ID, &l.UserID, &l.Recipient, &l.IncludeAttendance, &l.ExpiresAt, &l.CreatedBy, &l.CreatedAt,
+		&l.RevokedAt, &l.AccessCount, &l.LastAccessedAt)
+}
+
+var VerificationAccessColumns = []string{"id", "link_id", "accessed_at", "ip", "user_agent", "outcome"}
+
+func ScanVerificationAccess(row RowScanner, a *models.EmploymentVerificationAccess) error {
+	return row.Scan(&a.ID, &a.LinkID, &a.AccessedAt, &a.IP, &a.UserAgent, &a.Outcome)
+}
+
+var KioskDeviceColumns = []string{"id", "user_id", "name", "fingerprint_hash", "created_at", "last_seen_at", "revoked_at", "revoked_by"}
+
+func ScanKioskDevice(row RowScanner, d *models.KioskDevice) error {
+	return row.Scan(&d.ID, &d.UserID, &d.Name, &d.FingerprintHash, &d.CreatedAt, &d.LastSeenAt, &d.RevokedAt, &d.RevokedBy)
+}
+
+var AttendancePhotoColumns = []string{
+	"id", "attendance_id", "user_id", "status", "incoming_key", "original_key", "thumbnail_key",
+	"width", "height", "error", "captured_at", "processed_at", "purged_at",
+}
+
+func ScanAttendancePhoto(row RowScanner, p *models.AttendancePhoto) error {
+	return row.Scan(&p.ID, &p.AttendanceID, &p.UserID, &p.Status, &p.IncomingKey, &p.OriginalKey, &p.ThumbnailKey,
+		&p.Width, &p.Height, &p.Error, &p.CapturedAt, &p.ProcessedAt, &p.PurgedAt)
+}
+
+var ReportExportColumns = []string{
+	"id", "report_type", "format", "params", "status", "requested_by", "retention_seconds", "storage_key",
+	"file_name", "content_type", "size_bytes", "row_count", "error", "created_at", "completed_at", "expires_at", "purged_at",
+}
+
+func ScanReportExport(row RowScanner, r *models.ReportExport) error {
+	return row.Scan(&r.ID, &r.ReportType, &r.Format, &r.Params, &r.Status, &r.RequestedBy, &r.RetentionSeconds, &r.StorageKey,
+		&r.FileName, &r.ContentType, &r.SizeBytes, &r.RowCount, &r.Error, &r.CreatedAt, &r.CompletedAt, &r.ExpiresAt, &r.PurgedAt)
+}
+
+// ScanReportSnapshot memindai kolom reportSnapshotColumns driver.
+func ScanReportSnapshot(row RowScanner, s *models.ReportSnapshot, extra ...any) error {
+	return row.Scan(append([]any{&s.ID, &s.Label, &s.StartDate, &s.EndDate, &s.PayrollPeriodID, &s.RowCount, &s.Checksum,
+		&s.CreatedBy, &s.CreatedAt}, extra...)...)
+}
+
+// Header dan body rekaman debug hanya dibaca pada detail rekaman, sebagai kolom tambahan.
+var DebugCaptureColumns = []string{
+	"id", "request_id", "user_id", "method", "route", "path", "query", "status", "latency_ms", "error", "ip", "created_at", "expires_at",
+}
+
+func ScanDebugCapture(row RowScanner, d *models.DebugCapture, extra ...any) error {
+	return row.Scan(append([]any{&d.ID, &d.RequestID, &d.UserID, &d.Method, &d.Route, &d.Path, &d.Query, &d.Status,
+		&d.LatencyMs, &d.Error, &d.IP, &d.CreatedAt, &d.ExpiresAt}, extra...)...)
+}
+
+var EmailChangeColumns = []string{"id", "user_id", "new_email", "expires_at", "sent_at", "created_at", "confirmed_at"}
+
+func ScanEmailChange(row RowScanner, ec *models.EmailChangeRequest) error {
+	return row.Scan(&ec.ID, &ec.UserID, &ec.NewEmail, &ec.ExpiresAt, &ec.SentAt, &ec.CreatedAt, &ec.ConfirmedAt)
+}
+
+var UsernameChangeColumns = []string{"id", "user_id", "new_username", "status", "reviewed_by", "reviewed_at", "review_note", "created_at"}
+
+func ScanUsernameChange(row RowScanner, uc *models.UsernameChangeRequest, extra ...any) error {
+	dest := append([]any{&uc.ID, &uc.UserID, &uc.NewUsername, &uc.Status, &uc.ReviewedBy, &uc.ReviewedAt, &uc.ReviewNote, &uc.CreatedAt}, extra...)
+	return row.Scan(dest...)
+}
+
+var PreviousUsernameColumns = []string{"id", "user_id", "username", "changed_at"}
+
+func ScanPreviousUsername(row RowScanner, pu *models.PreviousUsername) error {
+	return row.Scan(&pu.ID, &pu.UserID, &pu.Username, &pu.ChangedAt)
+}
+
+var PhoneOTPColumns = []string{"id", "user_id", "purpose", "code_hash", "attempts", "expires_at", "created_at", "consumed_at"}
+
+func ScanPhoneOTP(row RowScanner, otp *models.PhoneOTP) error {
+	return row.Scan(&otp.ID, &otp.UserID, &otp.Purpose, &otp.CodeHash, &otp.Attempts, &otp.ExpiresAt, &otp.CreatedAt, &otp.ConsumedAt)
+}
+
+var InvitationColumns = []string{"id", "email", "role_id", "invited_by", "expires_at", "sent_at", "created_at", "accepted_at", "accepted_user_id", "revoked_at"}
+
+// ScanInvitation men-scan undangan (email masih terenkripsi) dan mengisi Status-nya.
+func ScanInvitation(row RowScanner, inv *models.Invitation, extra ...any) error {
+	dest := append([]any{&inv.ID, &inv.Email, &inv.RoleID, &inv.InvitedBy, &inv.ExpiresAt, &inv.SentAt, &inv.CreatedAt,
+		&inv.AcceptedAt, &inv.AcceptedUserID, &inv.RevokedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	switch {
+	case inv.AcceptedAt != nil:
+		inv.Status = models.InvitationAccepted
+	case inv.RevokedAt != nil:
+		inv.Status = models.InvitationRevoked
+	case !time.Now().Before(inv.ExpiresAt):
+		inv.Status = models.InvitationExpired
+	default:
+		inv.Status = models.InvitationPending
+	}
+	return nil
+}
+
+var BackupColumns = []string{
+	"id", "status", "requested_by", "schema_version", "storage_key", "file_name", "size_bytes", "table_count", "row_count",
+	"error", "created_at", "started_at", "completed_at",
+}
+
+func ScanBackup(row RowScanner, b *models.Backup) error {
+	return row.Scan(&b.ID, &b.Status, &b.RequestedBy, &b.SchemaVersion, &b.StorageKey, &b.FileName, &b.SizeBytes, &b.TableCount,
+		&b.RowCount, &b.Error, &b.CreatedAt, &b.StartedAt, &b.CompletedAt)
+}
+
+var AppInstanceColumns = []string{"instance_id", "app_version", "hostname", "started_at", "last_seen_at"}
+
+func ScanAppInstance(row RowScanner, i *models.AppInstance) error {
+	return row.Scan(&i.InstanceID, &i.AppVersion, &i.Hostname, &i.StartedAt, &i.LastSeenAt)
+}
@@ -0,0 +1,15 @@
+// internal/repository/internal/shared/logging.go
+package shared
+
+import (
+	"context"
+
+	applogger "github.com/rakaarfi/attendance-system-be/internal/logger"
+	"github.com/rs/zerolog"
+)
+
+// Logger mengembalikan logger per-request dari ctx untuk modul "repository",
+// sehingga level (LOG_LEVEL_REPOSITORY) dan sampling log sukses bisa diatur terpisah.
+func Logger(ctx context.Context) *zerolog.Logger {
+	return applogger.Module(ctx, applogger.ModuleRepository)
+}
@@ -0,0 +1,42 @@
+// internal/repository/internal/shared/patch.go
+package shared
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetBuilder menyusun klausa SET dinamis untuk update parsial (PATCH).
+// Hanya nama kolom konstanta dari repository yang boleh dimasukkan (bukan input user);
+// nilai selalu dikirim sebagai parameter query ($n).
+type SetBuilder struct {
+	sets []string
+	args []any
+}
+
+// Add menambahkan "column = $n" dengan nilai value.
+func (b *SetBuilder) Add(column string, value any) {
+	b.args = append(b.args, value)
+	b.sets = append(b.sets, fmt.Sprintf("%s = $%d", column, len(b.args)))
+}
+
+// Empty mengembalikan true jika tidak ada kolom yang diubah.
+func (b *SetBuilder) Empty() bool {
+	return len(b.sets) == 0
+}
+
+// Arg menambahkan parameter non-SET (mis. untuk WHERE) dan mengembalikan placeholder-nya.
+func (b *SetBuilder) Arg(value any) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// Clause mengembalikan daftar assignment yang digabung koma.
+func (b *SetBuilder) Clause() string {
+	return strings.Join(b.sets, ", ")
+}
+
+// Args mengembalikan nilai parameter query sesuai urutan placeholder.
+func (b *SetBuilder) Args() []any {
+	return b.args
+}
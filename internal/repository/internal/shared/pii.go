@@ -0,0 +1,61 @@
+// internal/repository/internal/shared/pii.go
+package shared
+
+import (
+	"fmt"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/pii"
+)
+
+// Helper enkripsi/dekripsi kolom PII user yang dipakai bersama oleh repository.
+// Kolom terenkripsi: email, phone, national_id. Pencarian & constraint UNIQUE
+// memakai kolom blind index (email_hash, phone_hash, national_id_hash).
+
+// EncryptedUserPII menampung nilai siap-simpan untuk kolom PII user.
+type EncryptedUserPII struct {
+	Email          string
+	EmailHash      string
+	Phone          *string
+	PhoneHash      *string
+	NationalID     *string
+	NationalIDHash *string
+}
+
+// EncryptUserPII mengenkripsi nilai PII dan menghitung blind index-nya.
+func EncryptUserPII(p *pii.Protector, email string, phone, nationalID *string) (out EncryptedUserPII, err error) {
+	if out.Email, err = p.Encrypt(email); err != nil {
+		return out, fmt.Errorf("error encrypting email: %w", err)
+	}
+	out.EmailHash = p.BlindIndex(email)
+
+	if out.Phone, err = p.EncryptPtr(phone); err != nil {
+		return out, fmt.Errorf("error encrypting phone: %w", err)
+	}
+	out.PhoneHash = p.BlindIndexPtr(phone)
+
+	if out.NationalID, err = p.EncryptPtr(nationalID); err != nil {
+		return out, fmt.Errorf("error encrypting national id: %w", err)
+	}
+	out.NationalIDHash = p.BlindIndexPtr(nationalID)
+
+	return out, nil
+}
+
+// DecryptUserPII mendekripsi kolom PII pada struct User hasil scan (in-place).
+func DecryptUserPII(p *pii.Protector, user *models.User) error {
+	if user == nil {
+		return nil
+	}
+	var err error
+	if user.Email, err = p.Decrypt(user.Email); err != nil {
+		return fmt.Errorf("error decrypting email for user %d: %w", user.ID, err)
+	}
+	if user.Phone, err = p.DecryptPtr(user.Phone); err != nil {
+		return fmt.Errorf("error decrypting phone for user %d: %w", user.ID, err)
+	}
+	if user.NationalID, err = p.DecryptPtr(user.NationalID); err != nil {
+		return fmt.Errorf("error decrypting national id for user %d: %w", user.ID, err)
+	}
+	return nil
+}
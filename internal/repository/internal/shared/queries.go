@@ -0,0 +1,63 @@
+// internal/repository/internal/shared/queries.go
+package shared
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// Query di dalam transaksi yang teksnya sama untuk semua driver: ledger absensi dan segmen project.
+
+// AppendAttendanceEvent menambahkan satu event ke ledger.
+func AppendAttendanceEvent(ctx context.Context, tx pgx.Tx, ev *models.AttendanceEvent) error {
+	query := `INSERT INTO attendance_events (attendance_id, user_id, event_type, check_in_at, check_out_at, notes, reason, actor_user_id)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`
+	err := tx.QueryRow(ctx, query,
+		ev.AttendanceID, ev.UserID, ev.EventType, ev.CheckInAt, ev.CheckOutAt, ev.Notes, ev.Reason, ev.ActorUserID,
+	).Scan(&ev.ID, &ev.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error appending %s event for attendance id %d: %w", ev.EventType, ev.AttendanceID, err)
+	}
+	return nil
+}
+
+// CloseOpenSegment menutup segmen berjalan (jika ada) pada endedAt.
+func CloseOpenSegment(ctx context.Context, tx pgx.Tx, attendanceID int, endedAt time.Time) error {
+	query := `UPDATE attendance_segments SET ended_at = $2 WHERE attendance_id = $1 AND ended_at IS NULL`
+	if _, err := tx.Exec(ctx, query, attendanceID, endedAt); err != nil {
+		return fmt.Errorf("error closing segment for attendance id %d: %w", attendanceID, err)
+	}
+	return nil
+}
+
+// RowsQuerier dipenuhi oleh koneksi repository (pool) maupun pgx.Tx.
+type RowsQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// QuerySegments mengembalikan segmen satu sesi absensi, urut waktu mulai.
+func QuerySegments(ctx context.Context, q RowsQuerier, attendanceID int) ([]models.AttendanceSegment, error) {
+	query := `SELECT ` + SelectList("sg", AttendanceSegmentColumns) + `
+	          FROM attendance_segments sg WHERE sg.attendance_id = $1 ORDER BY sg.started_at, sg.id`
+	rows, err := q.Query(ctx, query, attendanceID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting segments for attendance id %d: %w", attendanceID, err)
+	}
+	defer rows.Close()
+	segments := []models.AttendanceSegment{}
+	for rows.Next() {
+		var sg models.AttendanceSegment
+		if err := ScanAttendanceSegment(rows, &sg); err != nil {
+			return nil, fmt.Errorf("error scanning attendance segment row: %w", err)
+		}
+		segments = append(segments, sg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attendance segment rows: %w", err)
+	}
+	return segments, nil
+}
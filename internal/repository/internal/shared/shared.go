@@ -0,0 +1,68 @@
+// internal/repository/internal/shared/shared.go
+
+// Package shared berisi bagian repository yang tidak bergantung dialek SQL dan dipakai bersama
+// oleh repository PostgreSQL (internal/repository) dan SQLite (internal/repository/sqlite):
+// registry kolom & destinasi Scan, enkripsi kolom PII, builder klausa SET, logger modul, serta
+// konstanta domain (nama constraint, batas panjang) yang harus sama di kedua driver.
+package shared
+
+import (
+	"math"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// DateLayout adalah format kolom tanggal (YYYY-MM-DD).
+const DateLayout = "2006-01-02"
+
+// PageOffset menghitung OFFSET untuk query paginated (page dimulai dari 1).
+func PageOffset(page, limit int) int {
+	if page < 1 {
+		return 0
+	}
+	return (page - 1) * limit
+}
+
+// ExpectedVersion mengubah versi dari input menjadi argumen query; 0 berarti tanpa precondition.
+func ExpectedVersion(version int) *int {
+	if version <= 0 {
+		return nil
+	}
+	return &version
+}
+
+// Nama constraint yang dikenali dari error database (sama di skema kedua driver).
+const (
+	ActiveBackupConstraint   = "idx_backups_active"           // Unique index parsial: satu backup pending/running
+	PayrollOverlapConstraint = "excl_payroll_periods_overlap" // Periode payroll tidak boleh tumpang tindih
+)
+
+// MaxOutboxErrorLength membatasi panjang last_error outbox yang disimpan.
+const MaxOutboxErrorLength = 1000
+
+// MaxPingsPerSession membatasi jumlah titik satu sesi (24 jam dengan jeda minimum 30 detik).
+const MaxPingsPerSession = 2880
+
+// ToMicrodegrees mengonversi derajat ke mikroderajat (kolom lat_e6/lon_e6).
+func ToMicrodegrees(deg float64) int32 {
+	return int32(math.Round(deg * 1e6))
+}
+
+// AnonymizedPassword bukan format hash yang valid (bcrypt/Argon2id),
+// sehingga CheckPasswordHash selalu gagal dan user anonim tidak bisa login.
+const AnonymizedPassword = "!"
+
+// AudienceRoleIDs memastikan array tidak NULL di database (kosong = semua user).
+func AudienceRoleIDs(ids []int) []int {
+	if ids == nil {
+		return []int{}
+	}
+	return ids
+}
+
+// SyncTables adalah tabel yang boleh dibaca delta sync (nama tabel masuk ke teks query).
+var SyncTables = map[string]bool{
+	models.SyncEntityUsers:       true,
+	models.SyncEntityAttendances: true,
+	models.SyncEntitySchedules:   true,
+}
@@ -0,0 +1,87 @@
+// internal/repository/job_repo.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type jobRepo struct {
+	db Conn // Primary: status job harus konsisten antar instance
+}
+
+func NewJobRepository(pools Pools) JobRepository {
+	return &jobRepo{db: pools.Primary}
+}
+
+// RegisterJobs menyimpan definisi job (insert atau perbarui interval). Job baru jatuh tempo
+// seketika; jadwal job yang sudah ada tidak diubah.
+func (r *jobRepo) RegisterJobs(ctx context.Context, defs []models.JobDefinition) error {
+	for _, def := range defs {
+		_, err := r.db.Exec(ctx, `INSERT INTO scheduled_jobs (name, interval_seconds) VALUES ($1, $2)
+              ON CONFLICT (name) DO UPDATE SET interval_seconds = EXCLUDED.interval_seconds`,
+			def.Name, max(int(def.Interval/time.Second), 1))
+		if err != nil {
+			shared.Logger(ctx).Error().Err(err).Str("job", def.Name).Msg("Error registering scheduled job")
+			return fmt.Errorf("error registering job %s: %w", def.Name, err)
+		}
+	}
+	return nil
+}
+
+// GetJobs mengembalikan semua job terjadwal, urut nama.
+func (r *jobRepo) GetJobs(ctx context.Context) ([]models.ScheduledJob, error) {
+	query := `SELECT ` + shared.SelectList("sj", shared.ScheduledJobColumns) + ` FROM scheduled_jobs sj ORDER BY sj.name`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying scheduled jobs")
+		return nil, fmt.Errorf("error getting scheduled jobs: %w", err)
+	}
+	defer rows.Close()
+	jobs := []models.ScheduledJob{}
+	for rows.Next() {
+		var j models.ScheduledJob
+		if err := shared.ScanScheduledJob(rows, &j); err != nil {
+			return nil, fmt.Errorf("error scanning scheduled job: %w", err)
+		}
+		jobs = append(jobs, j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheduled jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// StartJobRun menandai job berjalan dan memajukan next_run_at satu interval. Tanpa force, hanya
+// berhasil jika job sudah jatuh tempo (bisa jadi baru dijalankan instance lain). Dipanggil
+// sambil memegang lock job (lihat jobs.Scheduler).
+func (r *jobRepo) StartJobRun(ctx context.Context, name, trigger string, force bool) (bool, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE scheduled_jobs
+              SET last_status = 'running', last_trigger = $2, last_started_at = CURRENT_TIMESTAMP,
+                  next_run_at = CURRENT_TIMESTAMP + interval_seconds * INTERVAL '1 second'
+              WHERE name = $1 AND ($3 OR next_run_at <= CURRENT_TIMESTAMP)`, name, trigger, force)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("job", name).Msg("Error starting scheduled job run")
+		return false, fmt.Errorf("error starting run of job %s: %w", name, err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// FinishJobRun mencatat hasil run: jumlah item yang diproses, atau pesan error jika gagal.
+func (r *jobRepo) FinishJobRun(ctx context.Context, name string, result int, runErr *string, duration time.Duration) error {
+	_, err := r.db.Exec(ctx, `UPDATE scheduled_jobs
+              SET last_status = CASE WHEN $3::text IS NULL THEN 'succeeded' ELSE 'failed' END,
+                  last_result = CASE WHEN $3::text IS NULL THEN $2::int END, last_error = $3,
+                  last_finished_at = CURRENT_TIMESTAMP, last_duration_ms = $4,
+                  run_count = run_count + 1, failure_count = failure_count + CASE WHEN $3::text IS NULL THEN 0 ELSE 1 END
+              WHERE name = $1`, name, result, runErr, duration.Milliseconds())
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("job", name).Msg("Error finishing scheduled job run")
+		return fmt.Errorf("error finishing run of job %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// internal/repository/kiosk_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// ErrKioskAlreadyEnrolled dikembalikan saat fingerprint perangkat sudah terdaftar aktif untuk user.
+var ErrKioskAlreadyEnrolled = errors.New("device is already enrolled as a kiosk")
+
+const activeKioskFingerprintConstraint = "uq_kiosk_devices_active_fingerprint"
+
+type kioskRepo struct {
+	db Conn // Primary: pencabutan perangkat harus langsung berlaku
+}
+
+func NewKioskRepository(pools Pools) KioskRepository {
+	return &kioskRepo{db: pools.Primary}
+}
+
+// CreateKioskDevice mendaftarkan perangkat kiosk dan mengisi ID & created_at.
+func (r *kioskRepo) CreateKioskDevice(ctx context.Context, device *models.KioskDevice) error {
+	query := `INSERT INTO kiosk_devices AS kd (user_id, name, fingerprint_hash)
+              VALUES ($1, $2, $3)
+              RETURNING ` + shared.SelectList("kd", shared.KioskDeviceColumns)
+	err := shared.ScanKioskDevice(r.db.QueryRow(ctx, query, device.UserID, device.Name, device.FingerprintHash), device)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == activeKioskFingerprintConstraint {
+			return ErrKioskAlreadyEnrolled
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", device.UserID).Msg("Error enrolling kiosk device")
+		return fmt.Errorf("error enrolling kiosk device for user %d: %w", device.UserID, err)
+	}
+	shared.Logger(ctx).Info().Int("kiosk_id", device.ID).Int("user_id", device.UserID).Msg("Kiosk device enrolled")
+	return nil
+}
+
+// GetKioskDevice mengembalikan perangkat berdasarkan ID (termasuk yang dicabut), atau pgx.ErrNoRows.
+func (r *kioskRepo) GetKioskDevice(ctx context.Context, id int) (*models.KioskDevice, error) {
+	query := `SELECT ` + shared.SelectList("kd", shared.KioskDeviceColumns) + ` FROM kiosk_devices kd WHERE kd.id = $1`
+	device := &models.KioskDevice{}
+	if err := shared.ScanKioskDevice(r.db.QueryRow(ctx, query, id), device); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("kiosk_id", id).Msg("Error getting kiosk device")
+		return nil, fmt.Errorf("error getting kiosk device %d: %w", id, err)
+	}
+	return device, nil
+}
+
+// GetKioskDevicesByUser mengembalikan semua perangkat kiosk milik user, terbaru dulu.
+func (r *kioskRepo) GetKioskDevicesByUser(ctx context.Context, userID int) ([]models.KioskDevice, error) {
+	query := `SELECT ` + shared.SelectList("kd", shared.KioskDeviceColumns) + `
+              FROM kiosk_devices kd
+              WHERE kd.user_id = $1
+              ORDER BY kd.created_at DESC, kd.id DESC`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error querying kiosk devices")
+		return nil, fmt.Errorf("error getting kiosk devices of user %d: %w", userID, err)
+	}
+	defer rows.Close()
+	devices := []models.KioskDevice{}
+	for rows.Next() {
+		var d models.KioskDevice
+		if err := shared.ScanKioskDevice(rows, &d); err != nil {
+			return nil, fmt.Errorf("error scanning kiosk device row: %w", err)
+		}
+		devices = append(devices, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating kiosk device rows: %w", err)
+	}
+	return devices, nil
+}
+
+// RevokeKioskDevice mencabut perangkat sehingga token kiosk-nya langsung ditolak. ownerID
+// membatasi ke perangkat milik user tersebut (nil = perangkat siapa pun, untuk admin).
+// Mengembalikan pgx.ErrNoRows jika perangkat tidak ada, bukan milik ownerID, atau sudah dicabut.
+func (r *kioskRepo) RevokeKioskDevice(ctx context.Context, id int, ownerID *int, revokedBy int) (*models.KioskDevice, error) {
+	query := `UPDATE kiosk_devices kd SET revoked_at = CURRENT_TIMESTAMP, revoked_by = $3
+              WHERE kd.id = $1 AND ($2::int IS NULL OR kd.user_id = $2) AND kd.revoked_at IS NULL
+              RETURNING ` + shared.SelectList("kd", shared.KioskDeviceColumns)
+	device := &models.KioskDevice{}
+	if err := shared.ScanKioskDevice(r.db.QueryRow(ctx, query, id, ownerID, revokedBy), device); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("kiosk_id", id).Msg("Error revoking kiosk device")
+		return nil, fmt.Errorf("error revoking kiosk device %d: %w", id, err)
+	}
+	shared.Logger(ctx).Info().Int("kiosk_id", id).Int("revoked_by", revokedBy).Msg("Kiosk device revoked")
+	return device, nil
+}
+
+// TouchKioskDevice memperbarui last_seen_at perangkat.
+func (r *kioskRepo) TouchKioskDevice(ctx context.Context, id int) error {
+	if _, err := r.db.Exec(ctx, `UPDATE kiosk_devices SET last_seen_at = CURRENT_TIMESTAMP WHERE id = $1`, id); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("kiosk_id", id).Msg("Error updating kiosk device last seen")
+		return fmt.Errorf("error updating last seen of kiosk device %d: %w", id, err)
+	}
+	return nil
+}
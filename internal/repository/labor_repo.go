@@ -0,0 +1,126 @@
+// internal/repository/labor_repo.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// laborGroupings memetakan group_by ke ekspresi kunci & ID kelompok atas CTE rated di
+// GetLaborCost. Hanya nilai di map ini yang masuk ke query.
+var laborGroupings = map[string]struct{ key, id string }{
+	models.LaborGroupDay:     {key: "day::text", id: "NULL::int"},
+	models.LaborGroupRole:    {key: "role_name", id: "role_id"},
+	models.LaborGroupManager: {key: "manager_name", id: "manager_id"},
+}
+
+type laborRepo struct {
+	db   Conn // Primary: perubahan tarif
+	read Conn // Replica (atau Primary jika tidak ada) untuk laporan
+}
+
+func NewLaborRepository(pools Pools) LaborRepository {
+	return &laborRepo{db: pools.Primary, read: pools.reader()}
+}
+
+// SetUserHourlyRate mengatur tarif per jam user (nil = ikut tarif role).
+// Mengembalikan pgx.ErrNoRows jika user tidak ada.
+func (r *laborRepo) SetUserHourlyRate(ctx context.Context, userID int, rate *float64) error {
+	tag, err := r.db.Exec(ctx, `UPDATE users SET hourly_rate = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, rate, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error updating user hourly rate")
+		return fmt.Errorf("error updating hourly rate of user %d: %w", userID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// SetRoleHourlyRate mengatur tarif per jam default role (nil = tanpa tarif).
+// Mengembalikan pgx.ErrNoRows jika role tidak ada.
+func (r *laborRepo) SetRoleHourlyRate(ctx context.Context, roleID int, rate *float64) error {
+	tag, err := r.db.Exec(ctx, `UPDATE roles SET hourly_rate = $1 WHERE id = $2`, rate, roleID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("role_id", roleID).Msg("Error updating role hourly rate")
+		return fmt.Errorf("error updating hourly rate of role %d: %w", roleID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetLaborCost merekap jam & biaya terjadwal (user_schedules × durasi shift, shift malam
+// dihitung lintas hari) dan aktual (sesi absensi yang sudah check-out, berdasarkan tanggal
+// check-in) dalam [startDate, endDate], dikelompokkan menurut groupBy (lihat models.LaborGroup*).
+// Baris total seluruh periode dikembalikan terpisah (selalu ada, nol jika tidak ada data).
+func (r *laborRepo) GetLaborCost(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]models.LaborCostRow, *models.LaborCostRow, error) {
+	grouping, ok := laborGroupings[groupBy]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported labor cost grouping %q", groupBy)
+	}
+	query := fmt.Sprintf(`
+        WITH work AS (
+            SELECT us.user_id, us.date AS day,
+                   EXTRACT(EPOCH FROM (s.end_time - s.start_time
+                       + CASE WHEN s.end_time <= s.start_time THEN INTERVAL '1 day' ELSE INTERVAL '0' END))::numeric / 3600 AS scheduled,
+                   0::numeric AS actual
+            FROM user_schedules us
+            CROSS JOIN LATERAL schedule_shift(us.shift_id, us.date) s
+            WHERE us.date BETWEEN $1::date AND $2::date
+            UNION ALL
+            SELECT a.user_id, a.check_in_at::date, 0, EXTRACT(EPOCH FROM (a.check_out_at - a.check_in_at))::numeric / 3600
+            FROM attendances a
+            WHERE a.check_out_at IS NOT NULL AND a.check_in_at::date BETWEEN $1::date AND $2::date
+        ), rated AS (
+            SELECT w.user_id, w.day, w.scheduled, w.actual,
+                   COALESCE(u.hourly_rate, r.hourly_rate) AS rate,
+                   r.id AS role_id, r.name AS role_name, u.manager_id, m.username AS manager_name
+            FROM work w
+            JOIN users u ON u.id = w.user_id
+            JOIN roles r ON r.id = u.role_id
+            LEFT JOIN users m ON m.id = u.manager_id
+        )
+        SELECT GROUPING(%[1]s) = 1, %[1]s, MIN(%[2]s), COUNT(DISTINCT user_id),
+               ROUND(COALESCE(SUM(scheduled), 0), 2)::float8, ROUND(COALESCE(SUM(actual), 0), 2)::float8,
+               ROUND(COALESCE(SUM(scheduled * rate), 0), 2)::float8, ROUND(COALESCE(SUM(actual * rate), 0), 2)::float8,
+               ROUND(COALESCE(SUM(scheduled) FILTER (WHERE rate IS NULL), 0), 2)::float8,
+               ROUND(COALESCE(SUM(actual) FILTER (WHERE rate IS NULL), 0), 2)::float8
+        FROM rated
+        GROUP BY GROUPING SETS ((%[1]s), ())
+        ORDER BY GROUPING(%[1]s), %[1]s NULLS LAST`, grouping.key, grouping.id)
+	rows, err := r.read.Query(ctx, query, startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("group_by", groupBy).Msg("Error querying labor cost")
+		return nil, nil, fmt.Errorf("error getting labor cost: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []models.LaborCostRow{}
+	totals := &models.LaborCostRow{}
+	for rows.Next() {
+		var row models.LaborCostRow
+		var isTotal bool
+		if err := rows.Scan(&isTotal, &row.Key, &row.ID, &row.Users, &row.ScheduledHours, &row.ActualHours,
+			&row.ScheduledCost, &row.ActualCost, &row.UnratedScheduledHours, &row.UnratedActualHours); err != nil {
+			return nil, nil, fmt.Errorf("error scanning labor cost row: %w", err)
+		}
+		row.CostVariance = math.Round((row.ActualCost-row.ScheduledCost)*100) / 100
+		if isTotal {
+			*totals = row
+			continue
+		}
+		groups = append(groups, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating labor cost rows: %w", err)
+	}
+	return groups, totals, nil
+}
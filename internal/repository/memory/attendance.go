@@ -0,0 +1,792 @@
+// internal/repository/memory/attendance.go
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// Tag absensi disimpan langsung di Attendance.Tags (terurut) dan hanya disalin pada method yang
+// di implementasi PostgreSQL membaca attendanceTagsColumn.
+
+type attendanceRepo struct{ *store }
+
+// maxPingsPerSession sama dengan batas di repository.RecordAttendancePing.
+const maxPingsPerSession = 2880
+
+// attendanceCopy menyalin record absensi; withTags mengisi Tags.
+func (s *store) attendanceCopy(a *models.Attendance, withTags bool) *models.Attendance {
+	out := *a
+	out.User, out.Schedule, out.Shift, out.Hours, out.OpenDisputeID = nil, nil, nil, nil, nil
+	out.ScheduleID, out.ProjectID, out.CheckOutAt, out.Notes = clonePtr(a.ScheduleID), clonePtr(a.ProjectID), clonePtr(a.CheckOutAt), clonePtr(a.Notes)
+	out.Tags = nil
+	if withTags {
+		out.Tags = slices.Clone(a.Tags)
+		if out.Tags == nil {
+			out.Tags = []string{}
+		}
+	}
+	return &out
+}
+
+// deleteAttendance menghapus record absensi beserta data ON DELETE CASCADE dan tombstone sync.
+func (s *store) deleteAttendance(id int) {
+	delete(s.attendances, id)
+	delete(s.faceChecks, id)
+	s.attendanceEvents = slices.DeleteFunc(s.attendanceEvents, func(e models.AttendanceEvent) bool { return e.AttendanceID == id })
+	s.segments = slices.DeleteFunc(s.segments, func(sg *models.AttendanceSegment) bool { return sg.AttendanceID == id })
+	s.pings = slices.DeleteFunc(s.pings, func(p models.AttendancePing) bool { return p.AttendanceID == id })
+	deleteWhere(s.disputes, func(d *models.AttendanceDispute) bool { return d.AttendanceID == id })
+	deleteWhere(s.photos, func(p *models.AttendancePhoto) bool { return p.AttendanceID == id })
+	s.recordTombstone(models.SyncEntityAttendances, id)
+}
+
+// checkPayrollPeriodsOpen meniru repository.checkPayrollPeriodsOpen.
+func (s *store) checkPayrollPeriodsOpen(times ...time.Time) error {
+	periods := sortedByKey(s.payrollPeriods)
+	slices.SortStableFunc(periods, func(a, b *models.PayrollPeriod) int { return strings.Compare(a.StartDate, b.StartDate) })
+	for _, p := range periods {
+		if p.Status != models.PayrollPeriodClosed {
+			continue
+		}
+		for _, t := range times {
+			if date := t.Format(dateLayout); date >= p.StartDate && date <= p.EndDate {
+				return &repository.PayrollPeriodClosedError{Period: *p}
+			}
+		}
+	}
+	return nil
+}
+
+// checkNotSignedOff meniru repository.checkNotSignedOff.
+func (s *store) checkNotSignedOff(userID int, times ...time.Time) error {
+	var found *models.AttendanceSignOff
+	for _, so := range s.signOffs {
+		if so.UserID != userID {
+			continue
+		}
+		for _, t := range times {
+			if so.WorkDate == t.Format(dateLayout) && (found == nil || so.WorkDate < found.WorkDate) {
+				found = so
+			}
+		}
+	}
+	if found != nil {
+		return &repository.AttendanceSignedOffError{SignOff: *found}
+	}
+	return nil
+}
+
+// checkProjectActive meniru repository.checkProjectActive.
+func (s *store) checkProjectActive(projectID int) error {
+	if p := s.projects[projectID]; p == nil || !p.IsActive {
+		return repository.ErrProjectUnavailable
+	}
+	return nil
+}
+
+// appendAttendanceEvent menambahkan event ke ledger (ID & created_at diisi).
+func (s *store) appendAttendanceEvent(ev models.AttendanceEvent) {
+	ev.ID, ev.CreatedAt = int64(s.nextID("attendance_events")), time.Now()
+	ev.CheckOutAt, ev.Notes, ev.Reason, ev.ActorUserID = clonePtr(ev.CheckOutAt), clonePtr(ev.Notes), clonePtr(ev.Reason), clonePtr(ev.ActorUserID)
+	s.attendanceEvents = append(s.attendanceEvents, ev)
+}
+
+// openSegment membuka segmen project baru mulai startedAt.
+func (s *store) openSegment(attendanceID int, projectID *int, startedAt time.Time) *models.AttendanceSegment {
+	sg := &models.AttendanceSegment{
+		ID: int64(s.nextID("attendance_segments")), AttendanceID: attendanceID, ProjectID: clonePtr(projectID),
+		StartedAt: startedAt, CreatedAt: time.Now(),
+	}
+	s.segments = append(s.segments, sg)
+	return sg
+}
+
+// closeOpenSegment menutup segmen berjalan (jika ada) pada endedAt.
+func (s *store) closeOpenSegment(attendanceID int, endedAt time.Time) {
+	for _, sg := range s.segments {
+		if sg.AttendanceID == attendanceID && sg.EndedAt == nil {
+			sg.EndedAt = ptr(endedAt)
+		}
+	}
+}
+
+// attendanceSegments mengembalikan segmen satu sesi, urut waktu mulai.
+func (s *store) attendanceSegments(attendanceID int) []*models.AttendanceSegment {
+	var out []*models.AttendanceSegment
+	for _, sg := range s.segments {
+		if sg.AttendanceID == attendanceID {
+			out = append(out, sg)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b *models.AttendanceSegment) int { return a.StartedAt.Compare(b.StartedAt) })
+	return out
+}
+
+// syncSegmentBounds meniru repository.syncSegmentBounds setelah koreksi jam check-in/out.
+func (s *store) syncSegmentBounds(att *models.Attendance) {
+	segments := s.attendanceSegments(att.ID)
+	if len(segments) == 0 {
+		sg := s.openSegment(att.ID, att.ProjectID, att.CheckInAt)
+		sg.EndedAt = clonePtr(att.CheckOutAt)
+		return
+	}
+	var kept, dropped []*models.AttendanceSegment
+	for _, sg := range segments {
+		endsBeforeIn := sg.EndedAt != nil && !sg.EndedAt.After(att.CheckInAt)
+		startsAfterOut := att.CheckOutAt != nil && !sg.StartedAt.Before(*att.CheckOutAt)
+		if endsBeforeIn || startsAfterOut {
+			dropped = append(dropped, sg)
+			continue
+		}
+		kept = append(kept, sg)
+	}
+	if len(kept) == 0 {
+		kept, dropped = dropped[:1], dropped[1:]
+		kept[0].EndedAt = clonePtr(att.CheckOutAt)
+	}
+	kept[0].StartedAt = att.CheckInAt
+	if att.CheckOutAt != nil {
+		kept[len(kept)-1].EndedAt = clonePtr(att.CheckOutAt)
+	}
+	s.segments = slices.DeleteFunc(s.segments, func(sg *models.AttendanceSegment) bool { return slices.Contains(dropped, sg) })
+}
+
+func (r *attendanceRepo) CreateCheckIn(ctx context.Context, userID int, checkInTime time.Time, notes *string, scheduleID, projectID *int, mode string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkPayrollPeriodsOpen(checkInTime); err != nil {
+		return 0, err
+	}
+	if err := r.checkNotSignedOff(userID, checkInTime); err != nil {
+		return 0, err
+	}
+	if projectID != nil {
+		if err := r.checkProjectActive(*projectID); err != nil {
+			return 0, err
+		}
+	}
+	for _, a := range r.attendances {
+		if a.UserID == userID && a.CheckOutAt == nil {
+			return 0, repository.ErrAlreadyCheckedIn
+		}
+	}
+	if r.users[userID] == nil {
+		return 0, fmt.Errorf("error creating check-in for user %d: %w", userID, pgx.ErrNoRows)
+	}
+	now := time.Now()
+	att := &models.Attendance{
+		ID: r.nextID("attendances"), UserID: userID, ScheduleID: clonePtr(scheduleID), ProjectID: clonePtr(projectID),
+		CheckInAt: checkInTime, Notes: clonePtr(notes), Mode: mode, CreatedAt: now, UpdatedAt: now,
+	}
+	r.attendances[att.ID] = att
+	r.openSegment(att.ID, projectID, checkInTime)
+	r.appendAttendanceEvent(models.AttendanceEvent{
+		AttendanceID: att.ID, UserID: userID, EventType: models.AttendanceEventCheckIn,
+		CheckInAt: checkInTime, Notes: notes, ActorUserID: &userID,
+	})
+	return att.ID, nil
+}
+
+func (r *attendanceRepo) GetLastAttendance(ctx context.Context, userID int) (*models.Attendance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var last *models.Attendance
+	for _, a := range r.attendances {
+		if a.UserID == userID && (last == nil || a.CheckInAt.After(last.CheckInAt)) {
+			last = a
+		}
+	}
+	if last == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return r.attendanceCopy(last, false), nil
+}
+
+func (r *attendanceRepo) GetAttendanceByID(ctx context.Context, attendanceID int) (*models.Attendance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a := r.attendances[attendanceID]
+	if a == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return r.attendanceCopy(a, false), nil
+}
+
+func (r *attendanceRepo) UpdateCheckOut(ctx context.Context, attendanceID int, checkOutTime time.Time, notes *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.attendances[attendanceID]
+	if current == nil || current.CheckOutAt != nil {
+		return fmt.Errorf("attendance record %d not found or already checked out", attendanceID)
+	}
+	if err := r.checkPayrollPeriodsOpen(current.CheckInAt); err != nil {
+		return err
+	}
+	if err := r.checkNotSignedOff(current.UserID, current.CheckInAt); err != nil {
+		return err
+	}
+	if notes != nil {
+		current.Notes = clonePtr(notes)
+	}
+	current.CheckOutAt = ptr(checkOutTime)
+	current.UpdatedAt = time.Now()
+	r.closeOpenSegment(current.ID, checkOutTime)
+	r.appendAttendanceEvent(models.AttendanceEvent{
+		AttendanceID: current.ID, UserID: current.UserID, EventType: models.AttendanceEventCheckOut,
+		CheckInAt: current.CheckInAt, CheckOutAt: current.CheckOutAt, Notes: current.Notes, ActorUserID: &current.UserID,
+	})
+	return nil
+}
+
+// attendancesBetween mengembalikan absensi dengan check-in dalam [startDate, endDate] yang
+// memenuhi fn, terbaru dulu.
+func (s *store) attendancesBetween(startDate, endDate time.Time, fn func(a *models.Attendance) bool) []*models.Attendance {
+	var out []*models.Attendance
+	for _, a := range sortedByKey(s.attendances) {
+		if !a.CheckInAt.Before(startDate) && !a.CheckInAt.After(endDate) && fn(a) {
+			out = append(out, a)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b *models.Attendance) int { return b.CheckInAt.Compare(a.CheckInAt) })
+	return out
+}
+
+func (r *attendanceRepo) GetAttendancesByUser(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) ([]models.Attendance, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := r.attendancesBetween(startDate, endDate, func(a *models.Attendance) bool { return a.UserID == userID })
+	attendances := []models.Attendance{}
+	for _, a := range paginate(all, page, limit) {
+		attendances = append(attendances, *r.attendanceCopy(a, true))
+	}
+	return attendances, len(all), nil
+}
+
+// openDispute mengembalikan ID dispute open atas record absensi, atau nil.
+func (s *store) openDispute(attendanceID int) *int {
+	for _, d := range s.disputes {
+		if d.AttendanceID == attendanceID && d.Status == models.DisputeOpen {
+			return ptr(d.ID)
+		}
+	}
+	return nil
+}
+
+func (r *attendanceRepo) GetAllAttendances(ctx context.Context, startDate, endDate time.Time, filter models.AttendanceReportFilter, page, limit int) ([]models.Attendance, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := r.attendancesBetween(startDate, endDate, func(a *models.Attendance) bool {
+		u := r.users[a.UserID]
+		return u != nil && (filter.EmploymentStatus == "" || u.EmploymentStatus == filter.EmploymentStatus) &&
+			(!filter.DisputedOnly || r.openDispute(a.ID) != nil)
+	})
+	slices.SortStableFunc(all, func(a, b *models.Attendance) int {
+		if c := b.CheckInAt.Compare(a.CheckInAt); c != 0 {
+			return c
+		}
+		return strings.Compare(r.users[a.UserID].Username, r.users[b.UserID].Username)
+	})
+	attendances := []models.Attendance{}
+	for _, a := range paginate(all, page, limit) {
+		out := r.attendanceCopy(a, true)
+		out.User = r.userSummary(r.users[a.UserID])
+		out.OpenDisputeID = r.openDispute(a.ID)
+		attendances = append(attendances, *out)
+	}
+	return attendances, len(all), nil
+}
+
+func (r *attendanceRepo) ExportAttendancesByUser(ctx context.Context, userID int) ([]models.Attendance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := r.attendancesBetween(time.Time{}, time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), func(a *models.Attendance) bool { return a.UserID == userID })
+	slices.Reverse(all)
+	attendances := []models.Attendance{}
+	for _, a := range all {
+		attendances = append(attendances, *r.attendanceCopy(a, true))
+	}
+	return attendances, nil
+}
+
+func (r *attendanceRepo) CorrectAttendance(ctx context.Context, attendanceID int, input *models.AttendanceCorrectionInput, actorUserID int) (*models.Attendance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.attendances[attendanceID]
+	if row == nil {
+		return nil, pgx.ErrNoRows
+	}
+	current := r.attendanceCopy(row, false)
+	previousCheckIn := current.CheckInAt
+	if input.CheckInAt != nil {
+		current.CheckInAt = *input.CheckInAt
+	}
+	if input.CheckOutAt != nil {
+		current.CheckOutAt = clonePtr(input.CheckOutAt)
+	}
+	if input.Notes != nil {
+		current.Notes = clonePtr(input.Notes)
+	}
+	if current.CheckOutAt != nil && current.CheckOutAt.Before(current.CheckInAt) {
+		return nil, repository.ErrInvalidAttendanceTimes
+	}
+	if err := r.checkPayrollPeriodsOpen(previousCheckIn, current.CheckInAt); err != nil {
+		return nil, err
+	}
+	if err := r.checkNotSignedOff(current.UserID, previousCheckIn, current.CheckInAt); err != nil {
+		return nil, err
+	}
+	reason := input.Reason
+	r.appendAttendanceEvent(models.AttendanceEvent{
+		AttendanceID: current.ID, UserID: current.UserID, EventType: models.AttendanceEventCorrection,
+		CheckInAt: current.CheckInAt, CheckOutAt: current.CheckOutAt, Notes: current.Notes, Reason: &reason, ActorUserID: &actorUserID,
+	})
+	current.UpdatedAt = time.Now()
+	row.CheckInAt, row.CheckOutAt, row.Notes, row.UpdatedAt = current.CheckInAt, clonePtr(current.CheckOutAt), clonePtr(current.Notes), current.UpdatedAt
+	r.syncSegmentBounds(row)
+	return current, nil
+}
+
+func (r *attendanceRepo) GetAttendanceEvents(ctx context.Context, attendanceID int) ([]models.AttendanceEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.attendances[attendanceID] == nil {
+		return nil, pgx.ErrNoRows
+	}
+	events := []models.AttendanceEvent{}
+	for _, ev := range r.attendanceEvents {
+		if ev.AttendanceID == attendanceID {
+			events = append(events, ev)
+		}
+	}
+	return events, nil
+}
+
+// hoursTotal mengakumulasi sesi/segmen ke satu baris rekap jam.
+type hoursTotal struct {
+	sessions map[int]bool
+	users    map[int]bool
+	seconds  float64
+}
+
+func (h *hoursTotal) add(attendanceID, userID int, d time.Duration) {
+	if h.sessions == nil {
+		h.sessions, h.users = map[int]bool{}, map[int]bool{}
+	}
+	h.sessions[attendanceID], h.users[userID] = true, true
+	h.seconds += d.Seconds()
+}
+
+func (h *hoursTotal) hours() float64 { return roundHours(h.seconds / 3600) }
+
+// closedSessions mengembalikan sesi yang sudah check-out dengan check-in dalam [startDate, endDate].
+func (s *store) closedSessions(startDate, endDate time.Time, userID *int) []*models.Attendance {
+	return s.attendancesBetween(startDate, endDate, func(a *models.Attendance) bool {
+		return a.CheckOutAt != nil && (userID == nil || a.UserID == *userID)
+	})
+}
+
+func (r *attendanceRepo) GetProjectHours(ctx context.Context, startDate, endDate time.Time, userID *int) ([]models.ProjectHours, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byProject := map[int]*hoursTotal{} // 0 = tanpa project
+	for _, a := range r.closedSessions(startDate, endDate, userID) {
+		for _, sg := range r.attendanceSegments(a.ID) {
+			if sg.EndedAt == nil {
+				continue
+			}
+			key := 0
+			if sg.ProjectID != nil {
+				key = *sg.ProjectID
+			}
+			if byProject[key] == nil {
+				byProject[key] = &hoursTotal{}
+			}
+			byProject[key].add(a.ID, a.UserID, sg.EndedAt.Sub(sg.StartedAt))
+		}
+	}
+	totals := []models.ProjectHours{}
+	for id, h := range byProject {
+		ph := models.ProjectHours{Sessions: len(h.sessions), Users: len(h.users), TotalHours: h.hours()}
+		if p := r.projects[id]; p != nil {
+			ph.ProjectID, ph.Code, ph.Name, ph.CostCenter = ptr(p.ID), ptr(p.Code), ptr(p.Name), clonePtr(p.CostCenter)
+		}
+		totals = append(totals, ph)
+	}
+	slices.SortFunc(totals, func(a, b models.ProjectHours) int {
+		switch {
+		case a.Code == nil:
+			return 1
+		case b.Code == nil:
+			return -1
+		}
+		return strings.Compare(*a.Code, *b.Code)
+	})
+	return totals, nil
+}
+
+func (r *attendanceRepo) SwitchProject(ctx context.Context, userID int, at time.Time, projectID int) (*models.AttendanceSegment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var current *models.Attendance
+	for _, a := range r.attendances {
+		if a.UserID == userID && a.CheckOutAt == nil {
+			current = a
+		}
+	}
+	if current == nil {
+		return nil, repository.ErrNotCheckedIn
+	}
+	if err := r.checkPayrollPeriodsOpen(current.CheckInAt); err != nil {
+		return nil, err
+	}
+	if err := r.checkNotSignedOff(userID, current.CheckInAt); err != nil {
+		return nil, err
+	}
+	if err := r.checkProjectActive(projectID); err != nil {
+		return nil, err
+	}
+	for _, sg := range r.attendanceSegments(current.ID) {
+		if sg.EndedAt == nil && sg.ProjectID != nil && *sg.ProjectID == projectID {
+			return nil, repository.ErrAlreadyOnProject
+		}
+	}
+	if at.Before(current.CheckInAt) {
+		at = current.CheckInAt
+	}
+	r.closeOpenSegment(current.ID, at)
+	return clonePtr(r.openSegment(current.ID, &projectID, at)), nil
+}
+
+func (r *attendanceRepo) GetAttendanceSegments(ctx context.Context, attendanceID int) ([]models.AttendanceSegment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.attendances[attendanceID] == nil {
+		return nil, pgx.ErrNoRows
+	}
+	segments := []models.AttendanceSegment{}
+	for _, sg := range r.attendanceSegments(attendanceID) {
+		segments = append(segments, *sg)
+	}
+	return segments, nil
+}
+
+// --- Verifikasi wajah ---
+
+func (r *attendanceRepo) RecordFaceCheck(ctx context.Context, fc *models.FaceCheck) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.attendances[fc.AttendanceID] == nil || r.faceChecks[fc.AttendanceID] != nil {
+		return fmt.Errorf("error recording face check for attendance id %d: %w", fc.AttendanceID, pgx.ErrNoRows)
+	}
+	fc.CreatedAt = time.Now()
+	r.faceChecks[fc.AttendanceID] = &models.FaceCheck{
+		AttendanceID: fc.AttendanceID, Provider: fc.Provider, Status: fc.Status, Score: clonePtr(fc.Score),
+		ReviewStatus: clonePtr(fc.ReviewStatus), CreatedAt: fc.CreatedAt,
+	}
+	return nil
+}
+
+// faceCheckCopy menyalin hasil verifikasi wajah beserta user & jam check-in absensinya.
+func (s *store) faceCheckCopy(fc *models.FaceCheck) models.FaceCheck {
+	out := *fc
+	out.Score, out.ReviewStatus, out.ReviewedBy, out.ReviewedAt = clonePtr(fc.Score), clonePtr(fc.ReviewStatus), clonePtr(fc.ReviewedBy), clonePtr(fc.ReviewedAt)
+	if a := s.attendances[fc.AttendanceID]; a != nil {
+		out.UserID, out.CheckInAt = a.UserID, a.CheckInAt
+	}
+	return out
+}
+
+func (r *attendanceRepo) GetFaceChecks(ctx context.Context, reviewStatus string, page, limit int) ([]models.FaceCheck, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var all []*models.FaceCheck
+	for _, fc := range sortedByKey(r.faceChecks) {
+		if reviewStatus == "" || (fc.ReviewStatus != nil && *fc.ReviewStatus == reviewStatus) {
+			all = append(all, fc)
+		}
+	}
+	slices.Reverse(all)
+	slices.SortStableFunc(all, func(a, b *models.FaceCheck) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	checks := []models.FaceCheck{}
+	for _, fc := range paginate(all, page, limit) {
+		out := r.faceCheckCopy(fc)
+		out.User = r.userSummary(r.users[out.UserID])
+		checks = append(checks, out)
+	}
+	return checks, len(all), nil
+}
+
+func (r *attendanceRepo) ReviewFaceCheck(ctx context.Context, attendanceID int, status string, reviewerID int) (*models.FaceCheck, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fc := r.faceChecks[attendanceID]
+	if fc == nil || fc.ReviewStatus == nil || *fc.ReviewStatus != models.FaceReviewPending {
+		return nil, fmt.Errorf("error reviewing face check for attendance id %d: %w", attendanceID, pgx.ErrNoRows)
+	}
+	fc.ReviewStatus, fc.ReviewedBy, fc.ReviewedAt = ptr(status), ptr(reviewerID), ptr(time.Now())
+	out := r.faceCheckCopy(fc)
+	return &out, nil
+}
+
+// --- Dead letter check-in tertunda ---
+
+func (r *attendanceRepo) RecordQueuedCheckInFailure(ctx context.Context, f *models.QueuedCheckInFailure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.users[f.UserID] == nil {
+		return fmt.Errorf("error recording queued check-in failure for user id %d: %w", f.UserID, &pgconn.PgError{Code: "23503", ConstraintName: "queued_check_in_failures_user_id_fkey"})
+	}
+	if f.Tags == nil {
+		f.Tags = []string{}
+	}
+	f.ID, f.FailedAt = r.nextID("queued_check_in_failures"), time.Now()
+	row := *f
+	row.Notes, row.Tags, row.ProjectID, row.User = clonePtr(f.Notes), slices.Clone(f.Tags), clonePtr(f.ProjectID), nil
+	r.checkInFailures[row.ID] = &row
+	return nil
+}
+
+// checkInFailureCopy menyalin dead letter check-in tanpa berbagi pointer dengan store.
+func checkInFailureCopy(f *models.QueuedCheckInFailure) models.QueuedCheckInFailure {
+	out := *f
+	out.Notes, out.Tags, out.ProjectID = clonePtr(f.Notes), slices.Clone(f.Tags), clonePtr(f.ProjectID)
+	out.ResolvedBy, out.ResolvedAt, out.ResolutionNote = clonePtr(f.ResolvedBy), clonePtr(f.ResolvedAt), clonePtr(f.ResolutionNote)
+	return out
+}
+
+func (r *attendanceRepo) GetQueuedCheckInFailures(ctx context.Context, resolved *bool, page, limit int) ([]models.QueuedCheckInFailure, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var all []*models.QueuedCheckInFailure
+	for _, f := range sortedByKey(r.checkInFailures) {
+		if resolved == nil || (f.ResolvedAt != nil) == *resolved {
+			all = append(all, f)
+		}
+	}
+	slices.Reverse(all)
+	slices.SortStableFunc(all, func(a, b *models.QueuedCheckInFailure) int { return b.FailedAt.Compare(a.FailedAt) })
+	failures := []models.QueuedCheckInFailure{}
+	for _, f := range paginate(all, page, limit) {
+		out := checkInFailureCopy(f)
+		out.User = r.userSummary(r.users[out.UserID])
+		failures = append(failures, out)
+	}
+	return failures, len(all), nil
+}
+
+func (r *attendanceRepo) ResolveQueuedCheckInFailure(ctx context.Context, id, adminID int, note string) (*models.QueuedCheckInFailure, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := r.checkInFailures[id]
+	if f == nil || f.ResolvedAt != nil {
+		return nil, fmt.Errorf("error resolving queued check-in failure id %d: %w", id, pgx.ErrNoRows)
+	}
+	f.ResolvedBy, f.ResolvedAt, f.ResolutionNote = ptr(adminID), ptr(time.Now()), ptr(note)
+	out := checkInFailureCopy(f)
+	return &out, nil
+}
+
+// --- Tag & mode kerja ---
+
+func (r *attendanceRepo) AddAttendanceTags(ctx context.Context, attendanceID int, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a := r.attendances[attendanceID]
+	if a == nil {
+		return fmt.Errorf("error adding tags to attendance id %d: %w", attendanceID, pgx.ErrNoRows)
+	}
+	for _, tag := range tags {
+		if !slices.Contains(a.Tags, tag) {
+			a.Tags = append(a.Tags, tag)
+		}
+	}
+	slices.Sort(a.Tags)
+	return nil
+}
+
+// hoursBy merekap sesi yang sudah check-out per kunci (tag atau mode), urut kunci.
+func (s *store) hoursBy(startDate, endDate time.Time, userID *int, keys func(a *models.Attendance) []string) ([]string, map[string]*hoursTotal) {
+	totals := map[string]*hoursTotal{}
+	for _, a := range s.closedSessions(startDate, endDate, userID) {
+		for _, k := range keys(a) {
+			if totals[k] == nil {
+				totals[k] = &hoursTotal{}
+			}
+			totals[k].add(a.ID, a.UserID, a.CheckOutAt.Sub(a.CheckInAt))
+		}
+	}
+	names := make([]string, 0, len(totals))
+	for k := range totals {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	return names, totals
+}
+
+func (r *attendanceRepo) GetTagHours(ctx context.Context, startDate, endDate time.Time, userID *int) ([]models.TagHours, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tags, totals := r.hoursBy(startDate, endDate, userID, func(a *models.Attendance) []string { return a.Tags })
+	out := []models.TagHours{}
+	for _, tag := range tags {
+		h := totals[tag]
+		out = append(out, models.TagHours{Tag: tag, Sessions: len(h.sessions), Users: len(h.users), TotalHours: h.hours()})
+	}
+	return out, nil
+}
+
+func (r *attendanceRepo) GetModeHours(ctx context.Context, startDate, endDate time.Time, userID *int) ([]models.ModeHours, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	modes, totals := r.hoursBy(startDate, endDate, userID, func(a *models.Attendance) []string { return []string{a.Mode} })
+	out := []models.ModeHours{}
+	for _, mode := range modes {
+		h := totals[mode]
+		out = append(out, models.ModeHours{Mode: mode, Sessions: len(h.sessions), Users: len(h.users), TotalHours: h.hours()})
+	}
+	return out, nil
+}
+
+// --- Ping lokasi ---
+
+func (r *attendanceRepo) RecordAttendancePing(ctx context.Context, userID int, ping *models.AttendancePing, minInterval time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a := r.attendances[ping.AttendanceID]
+	switch {
+	case a == nil || a.UserID != userID:
+		return pgx.ErrNoRows
+	case a.CheckOutAt != nil:
+		return repository.ErrNotCheckedIn
+	case a.Mode != models.AttendanceModeField:
+		return repository.ErrNotFieldSession
+	case ping.RecordedAt.Before(a.CheckInAt):
+		return repository.ErrPingOutsideSession
+	}
+	count := 0
+	for _, p := range r.pings {
+		if p.AttendanceID != ping.AttendanceID {
+			continue
+		}
+		count++
+		if gap := p.RecordedAt.Sub(ping.RecordedAt); gap > -minInterval && gap < minInterval {
+			return repository.ErrPingTooSoon
+		}
+	}
+	if count >= maxPingsPerSession {
+		return repository.ErrRouteLimitReached
+	}
+	// Koordinat disimpan dengan presisi mikroderajat seperti kolom lat_e6/lon_e6.
+	r.pings = append(r.pings, models.AttendancePing{
+		AttendanceID: ping.AttendanceID, RecordedAt: ping.RecordedAt,
+		Latitude: math.Round(ping.Latitude*1e6) / 1e6, Longitude: math.Round(ping.Longitude*1e6) / 1e6,
+		AccuracyM: clonePtr(ping.AccuracyM),
+	})
+	return nil
+}
+
+func (r *attendanceRepo) GetAttendancePings(ctx context.Context, attendanceID int) ([]models.AttendancePing, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pings := []models.AttendancePing{}
+	for _, p := range r.pings {
+		if p.AttendanceID == attendanceID {
+			p.AccuracyM = clonePtr(p.AccuracyM)
+			pings = append(pings, p)
+		}
+	}
+	slices.SortStableFunc(pings, func(a, b models.AttendancePing) int { return a.RecordedAt.Compare(b.RecordedAt) })
+	return pings, nil
+}
+
+func (r *attendanceRepo) DeleteAttendancePingsBefore(ctx context.Context, before time.Time, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deleted := 0
+	r.pings = slices.DeleteFunc(r.pings, func(p models.AttendancePing) bool {
+		if deleted < limit && p.RecordedAt.Before(before) {
+			deleted++
+			return true
+		}
+		return false
+	})
+	return deleted, nil
+}
+
+// --- Okupansi ---
+
+func (r *attendanceRepo) GetOccupancy(ctx context.Context, startDate, endDate time.Time, granularity, timezone string, mode *string) ([]models.OccupancyBucket, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("error getting occupancy: %w", err)
+	}
+	// Slot dibentuk dari jam dinding di timezone (seperti generate_series atas timestamp lokal).
+	first := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, loc)
+	last := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, loc)
+	var step func(time.Time) time.Time
+	switch granularity {
+	case models.OccupancyGranularityHour:
+		step = func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc) }
+		last = time.Date(last.Year(), last.Month(), last.Day(), 23, 0, 0, 0, loc)
+	case models.OccupancyGranularityDay:
+		step = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	default:
+		return nil, fmt.Errorf("unsupported occupancy granularity %q", granularity)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	locations := []string{models.AttendanceModeOnsite, models.AttendanceModeRemote, models.AttendanceModeField}
+	if mode != nil {
+		locations = slices.DeleteFunc(locations, func(m string) bool { return m != *mode })
+	}
+	slices.Sort(locations)
+	now := time.Now()
+	buckets := []models.OccupancyBucket{}
+	for slot := first; !slot.After(last); slot = step(slot) {
+		slotEnd := step(slot)
+		for _, m := range locations {
+			users := map[int]bool{}
+			for _, a := range r.attendances {
+				end := now
+				if a.CheckOutAt != nil {
+					end = *a.CheckOutAt
+				}
+				if a.Mode == m && a.CheckInAt.Before(slotEnd) && end.After(slot) {
+					users[a.UserID] = true
+				}
+			}
+			buckets = append(buckets, models.OccupancyBucket{Start: slot, Location: m, Headcount: len(users)})
+		}
+	}
+	return buckets, nil
+}
+
+func (r *attendanceRepo) GetAttendanceChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var updated []change
+	for _, a := range r.attendances {
+		updated = append(updated, change{id: a.ID, changedAt: a.UpdatedAt})
+	}
+	ids, batch := r.syncChanges(models.SyncEntityAttendances, updated, cursor, limit)
+	attendances := []models.Attendance{}
+	for _, id := range ids {
+		attendances = append(attendances, *r.attendanceCopy(r.attendances[id], false))
+	}
+	batch.Items = attendances
+	return batch, nil
+}
@@ -0,0 +1,121 @@
+// internal/repository/memory/audit.go
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+type auditRepo struct{ *store }
+
+// jsonDetails meniru penyimpanan kolom JSONB: nilai dibaca kembali sebagai hasil decode JSON
+// (angka float64, waktu string RFC 3339), dan map nil menjadi objek kosong.
+func jsonDetails(details map[string]any) map[string]any {
+	out := map[string]any{}
+	if raw, err := json.Marshal(details); err == nil {
+		_ = json.Unmarshal(raw, &out)
+	}
+	return out
+}
+
+// addAudit mencatat entri audit_log (dipakai juga untuk meniru trigger database).
+func (s *store) addAudit(userID int, actor *int, action string, details map[string]any) models.AuditEntry {
+	entry := models.AuditEntry{
+		ID: int64(s.nextID("audit_log")), UserID: userID, ActorUserID: clonePtr(actor),
+		Action: action, Details: jsonDetails(details), CreatedAt: time.Now(),
+	}
+	s.audit = append(s.audit, entry)
+	return entry
+}
+
+func (r *auditRepo) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := r.addAudit(entry.UserID, entry.ActorUserID, entry.Action, entry.Details)
+	entry.ID, entry.CreatedAt = saved.ID, saved.CreatedAt
+	return nil
+}
+
+func (r *auditRepo) GetUserActivity(ctx context.Context, userID int, after *models.ActivityCursor, limit int) ([]models.ActivityItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var feed []models.ActivityItem
+	for _, e := range r.audit {
+		if e.UserID == userID {
+			category, _, _ := strings.Cut(e.Action, ".")
+			feed = append(feed, models.ActivityItem{
+				Source: models.ActivitySourceAuditLog, SourceID: e.ID, Category: category, Action: e.Action,
+				OccurredAt: e.CreatedAt, ActorUserID: clonePtr(e.ActorUserID), Details: jsonDetails(e.Details),
+			})
+		}
+	}
+	for _, e := range r.attendanceEvents {
+		if e.UserID != userID {
+			continue
+		}
+		details := map[string]any{"attendance_id": e.AttendanceID, "check_in_at": e.CheckInAt}
+		if e.CheckOutAt != nil {
+			details["check_out_at"] = *e.CheckOutAt
+		}
+		if e.Reason != nil {
+			details["reason"] = *e.Reason
+		}
+		feed = append(feed, models.ActivityItem{
+			Source: models.ActivitySourceAttendanceEvents, SourceID: e.ID, Category: "attendance", Action: "attendance." + e.EventType,
+			OccurredAt: e.CreatedAt, ActorUserID: clonePtr(e.ActorUserID), Details: jsonDetails(details),
+		})
+	}
+	// Urutan (occurred_at, source, source_id) DESC; kursor menunjuk entri terakhir halaman sebelumnya.
+	compare := func(a models.ActivityItem, at time.Time, source string, id int64) int {
+		if c := a.OccurredAt.Compare(at); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Source, source); c != 0 {
+			return c
+		}
+		return int(a.SourceID - id)
+	}
+	if after != nil {
+		feed = slices.DeleteFunc(feed, func(item models.ActivityItem) bool {
+			return compare(item, after.OccurredAt, after.Source, after.SourceID) >= 0
+		})
+	}
+	slices.SortFunc(feed, func(a, b models.ActivityItem) int { return -compare(a, b.OccurredAt, b.Source, b.SourceID) })
+	if len(feed) > limit {
+		feed = feed[:limit]
+	}
+	if feed == nil {
+		feed = []models.ActivityItem{}
+	}
+	return feed, nil
+}
+
+func (r *auditRepo) GetLoginHistoryMatch(ctx context.Context, userID int, device, country string) (*models.LoginHistoryMatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	match := &models.LoginHistoryMatch{}
+	var hasDevice, hasCountry bool
+	for _, e := range r.audit {
+		if e.UserID != userID || e.Action != models.AuditLoginSucceeded {
+			continue
+		}
+		match.HasHistory = true
+		if d, ok := e.Details["device"]; ok {
+			hasDevice = true
+			match.KnownDevice = match.KnownDevice || d == device
+		}
+		if c, ok := e.Details["country"]; ok {
+			hasCountry = true
+			match.KnownCountry = match.KnownCountry || (country != "" && c == country)
+		}
+	}
+	// Riwayat tanpa data perangkat/negara (login sebelum fitur aktif) dianggap cocok.
+	match.KnownDevice = match.KnownDevice || !hasDevice
+	match.KnownCountry = match.KnownCountry || !hasCountry
+	return match, nil
+}
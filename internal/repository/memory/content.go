@@ -0,0 +1,185 @@
+// internal/repository/memory/content.go
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// --- Pengaturan sistem ---
+
+type settingsRepo struct{ *store }
+
+func (r *settingsRepo) GetAllSettings(ctx context.Context) ([]models.Setting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	settings := []models.Setting{}
+	for _, s := range sortedByKey(r.settings) {
+		out := *s
+		out.UpdatedBy = clonePtr(s.UpdatedBy)
+		settings = append(settings, out)
+	}
+	return settings, nil
+}
+
+func (r *settingsRepo) UpsertSettings(ctx context.Context, values map[string]string, actorUserID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for key, value := range values {
+		r.settings[key] = &models.Setting{Key: key, Value: value, UpdatedBy: ptr(actorUserID), UpdatedAt: now}
+	}
+	return nil
+}
+
+// --- Pengumuman ---
+
+type announcementRepo struct{ *store }
+
+func announcementCopy(a *models.Announcement) models.Announcement {
+	out := *a
+	out.ExpiresAt, out.CreatedBy = clonePtr(a.ExpiresAt), clonePtr(a.CreatedBy)
+	out.AudienceRoleIDs = slices.Clone(a.AudienceRoleIDs)
+	if out.AudienceRoleIDs == nil {
+		out.AudienceRoleIDs = []int{}
+	}
+	return out
+}
+
+// announcementsWhere mengembalikan pengumuman yang memenuhi fn, urut publish_at & id DESC.
+func (s *store) announcementsWhere(fn func(a *models.Announcement) bool) []*models.Announcement {
+	var out []*models.Announcement
+	for _, a := range sortedByKey(s.announcements) {
+		if fn(a) {
+			out = append(out, a)
+		}
+	}
+	slices.Reverse(out)
+	slices.SortStableFunc(out, func(a, b *models.Announcement) int { return b.PublishAt.Compare(a.PublishAt) })
+	return out
+}
+
+func (r *announcementRepo) CreateAnnouncement(ctx context.Context, announcement *models.Announcement) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	a := announcementCopy(announcement)
+	a.ID, a.CreatedAt, a.UpdatedAt = r.nextID("announcements"), now, now
+	r.announcements[a.ID] = &a
+	return a.ID, nil
+}
+
+func (r *announcementRepo) GetAnnouncementByID(ctx context.Context, id int) (*models.Announcement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a := r.announcements[id]
+	if a == nil {
+		return nil, pgx.ErrNoRows
+	}
+	out := announcementCopy(a)
+	return &out, nil
+}
+
+func (r *announcementRepo) pageOf(all []*models.Announcement, page, limit int) ([]models.Announcement, int, error) {
+	announcements := []models.Announcement{}
+	for _, a := range paginate(all, page, limit) {
+		announcements = append(announcements, announcementCopy(a))
+	}
+	return announcements, len(all), nil
+}
+
+func (r *announcementRepo) GetAllAnnouncements(ctx context.Context, page, limit int) ([]models.Announcement, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pageOf(r.announcementsWhere(func(*models.Announcement) bool { return true }), page, limit)
+}
+
+func (r *announcementRepo) GetActiveAnnouncementsForUser(ctx context.Context, userID int, at time.Time, page, limit int) ([]models.Announcement, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	roleID := 0
+	if u := r.users[userID]; u != nil {
+		roleID = u.RoleID
+	}
+	return r.pageOf(r.announcementsWhere(func(a *models.Announcement) bool {
+		return !a.PublishAt.After(at) && (a.ExpiresAt == nil || a.ExpiresAt.After(at)) &&
+			(len(a.AudienceRoleIDs) == 0 || slices.Contains(a.AudienceRoleIDs, roleID))
+	}), page, limit)
+}
+
+func (r *announcementRepo) UpdateAnnouncement(ctx context.Context, announcement *models.Announcement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.announcements[announcement.ID]
+	if current == nil {
+		return pgx.ErrNoRows
+	}
+	a := announcementCopy(announcement)
+	a.CreatedBy, a.CreatedAt, a.UpdatedAt = current.CreatedBy, current.CreatedAt, time.Now()
+	r.announcements[a.ID] = &a
+	return nil
+}
+
+func (r *announcementRepo) DeleteAnnouncement(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.announcements[id] == nil {
+		return pgx.ErrNoRows
+	}
+	delete(r.announcements, id)
+	return nil
+}
+
+// --- Dokumen ---
+
+type documentRepo struct{ *store }
+
+func (r *documentRepo) CreateDocument(ctx context.Context, doc *models.Document) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.documents {
+		if d.StorageKey == doc.StorageKey {
+			return 0, repository.ErrDocumentExists
+		}
+	}
+	doc.ID, doc.CreatedAt = r.nextID("documents"), time.Now()
+	r.documents[doc.ID] = clonePtr(doc)
+	return doc.ID, nil
+}
+
+func (r *documentRepo) GetDocumentByID(ctx context.Context, id int) (*models.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.documents[id]
+	if d == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return clonePtr(d), nil
+}
+
+func (r *documentRepo) GetDocumentsBySubject(ctx context.Context, subjectType string, subjectID int) ([]models.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	docs := []models.Document{}
+	for _, d := range sortedByKey(r.documents) {
+		if d.SubjectType == subjectType && d.SubjectID == subjectID {
+			docs = append(docs, *d)
+		}
+	}
+	return docs, nil
+}
+
+func (r *documentRepo) DeleteDocument(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.documents[id] == nil {
+		return pgx.ErrNoRows
+	}
+	delete(r.documents, id)
+	return nil
+}
@@ -0,0 +1,228 @@
+// internal/repository/memory/memory.go
+
+// Package memory adalah implementasi repository di memori untuk SANDBOX_MODE: API bisa
+// dijalankan tanpa PostgreSQL dengan data contoh deterministik (lihat seed.go), misalnya
+// untuk pengembangan frontend.
+//
+// Perilaku mengikuti implementasi PostgreSQL di package repository sejauh yang terlihat dari
+// API: error yang sama (pgx.ErrNoRows, repository.ErrVersionConflict, dst.), urutan hasil,
+// dan efek trigger database (version & updated_at, token_version, riwayat username, audit
+// jadwal, tombstone sync). Batasan yang disengaja:
+//   - Data hilang saat proses berhenti.
+//   - RunInTx tidak me-rollback perubahan jika fn gagal.
+//   - Data pribadi tidak dienkripsi.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// dateLayout adalah format kolom DATE.
+const dateLayout = "2006-01-02"
+
+// store menyimpan semua "tabel". Satu mutex untuk seluruh store: method publik mengunci,
+// helper (tanpa lock) mengasumsikan lock sudah dipegang pemanggil.
+type store struct {
+	mu  sync.Mutex
+	ids map[string]int64 // Sequence per tabel
+
+	roles              map[int]*roleRow
+	users              map[int]*userRow
+	previousUsernames  []models.PreviousUsername
+	emailChanges       map[int]*models.EmailChangeRequest
+	usernameChanges    map[int]*models.UsernameChangeRequest
+	phoneOTPs          map[int]*phoneOTPRow
+	invitations        map[int]*models.Invitation
+	shifts             map[int]*models.Shift
+	shiftOverrides     map[int]*models.ShiftOverride
+	schedules          map[int]*models.UserSchedule
+	attendances        map[int]*models.Attendance
+	attendanceEvents   []models.AttendanceEvent
+	segments           []*models.AttendanceSegment
+	faceChecks         map[int]*models.FaceCheck
+	checkInFailures    map[int]*models.QueuedCheckInFailure
+	pings              []models.AttendancePing
+	tombstones         map[string]map[int]time.Time // entity -> id -> deleted_at
+	settings           map[string]*models.Setting
+	announcements      map[int]*models.Announcement
+	audit              []models.AuditEntry
+	documents          map[int]*models.Document
+	payrollPeriods     map[int]*models.PayrollPeriod
+	projects           map[int]*models.Project
+	signOffs           map[int]*models.AttendanceSignOff
+	disputes           map[int]*models.AttendanceDispute
+	deviceTokens       map[int]*models.DeviceToken
+	remindersSent      map[int]bool // schedule_id
+	delegations        map[int]*models.ApprovalDelegation
+	escalations        []models.ApprovalEscalation
+	notifications      map[int]*models.Notification
+	outbox             map[int64]*outboxRow
+	jobs               map[string]*models.ScheduledJob
+	verificationLinks  map[int]*models.EmploymentVerificationLink
+	verificationAccess []models.EmploymentVerificationAccess
+	kiosks             map[int]*models.KioskDevice
+	photos             map[int]*models.AttendancePhoto
+	reportExports      map[int]*models.ReportExport
+	reportSnapshots    map[int]*models.ReportSnapshot
+	debugCaptures      map[int64]*models.DebugCapture
+	backups            map[int]*models.Backup
+	appInstances       map[string]*models.AppInstance
+}
+
+func newStore() *store {
+	return &store{
+		ids:               map[string]int64{},
+		roles:             map[int]*roleRow{},
+		users:             map[int]*userRow{},
+		emailChanges:      map[int]*models.EmailChangeRequest{},
+		usernameChanges:   map[int]*models.UsernameChangeRequest{},
+		phoneOTPs:         map[int]*phoneOTPRow{},
+		invitations:       map[int]*models.Invitation{},
+		shifts:            map[int]*models.Shift{},
+		shiftOverrides:    map[int]*models.ShiftOverride{},
+		schedules:         map[int]*models.UserSchedule{},
+		attendances:       map[int]*models.Attendance{},
+		faceChecks:        map[int]*models.FaceCheck{},
+		checkInFailures:   map[int]*models.QueuedCheckInFailure{},
+		tombstones:        map[string]map[int]time.Time{},
+		settings:          map[string]*models.Setting{},
+		announcements:     map[int]*models.Announcement{},
+		documents:         map[int]*models.Document{},
+		payrollPeriods:    map[int]*models.PayrollPeriod{},
+		projects:          map[int]*models.Project{},
+		signOffs:          map[int]*models.AttendanceSignOff{},
+		disputes:          map[int]*models.AttendanceDispute{},
+		deviceTokens:      map[int]*models.DeviceToken{},
+		remindersSent:     map[int]bool{},
+		delegations:       map[int]*models.ApprovalDelegation{},
+		notifications:     map[int]*models.Notification{},
+		outbox:            map[int64]*outboxRow{},
+		jobs:              map[string]*models.ScheduledJob{},
+		verificationLinks: map[int]*models.EmploymentVerificationLink{},
+		kiosks:            map[int]*models.KioskDevice{},
+		photos:            map[int]*models.AttendancePhoto{},
+		reportExports:     map[int]*models.ReportExport{},
+		reportSnapshots:   map[int]*models.ReportSnapshot{},
+		debugCaptures:     map[int64]*models.DebugCapture{},
+		backups:           map[int]*models.Backup{},
+		appInstances:      map[string]*models.AppInstance{},
+	}
+}
+
+// nextID meniru SERIAL/BIGSERIAL: ID berikutnya untuk tabel.
+func (s *store) nextID(table string) int {
+	s.ids[table]++
+	return int(s.ids[table])
+}
+
+// NewRepositories membuat semua repository di atas satu store baru yang sudah berisi data
+// contoh (lihat seed).
+func NewRepositories() repository.Repositories {
+	s := newStore()
+	s.seed(time.Now())
+	return repository.Repositories{
+		Users:            &userRepo{s},
+		Roles:            &roleRepo{s},
+		Shifts:           &shiftRepo{s},
+		Schedules:        &scheduleRepo{s},
+		Attendance:       &attendanceRepo{s},
+		Settings:         &settingsRepo{s},
+		Announcements:    &announcementRepo{s},
+		Documents:        &documentRepo{s},
+		Audit:            &auditRepo{s},
+		Payroll:          &payrollRepo{s},
+		Projects:         &projectRepo{s},
+		SignOffs:         &signOffRepo{s},
+		Disputes:         &disputeRepo{s},
+		Devices:          &deviceRepo{s},
+		Delegations:      &delegationRepo{s},
+		Escalations:      &escalationRepo{s},
+		Jobs:             &jobRepo{s},
+		Labor:            &laborRepo{s},
+		Notifications:    &notificationRepo{s},
+		Outbox:           &outboxRepo{s},
+		Verification:     &verificationRepo{s},
+		Kiosks:           &kioskRepo{s},
+		AttendancePhotos: &attendancePhotoRepo{s},
+		ReportExports:    &reportExportRepo{s},
+		DebugCaptures:    &debugCaptureRepo{s},
+		Backups:          &backupRepo{s},
+		AppInstances:     &appInstanceRepo{s},
+		Tx:               txManager{},
+	}
+}
+
+// txManager menjalankan fn langsung: store tidak mendukung rollback.
+type txManager struct{}
+
+func (txManager) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// --- Helper umum ---
+
+// sortedByKey mengembalikan nilai map terurut berdasarkan key (urutan id ASC).
+func sortedByKey[K cmp.Ordered, V any](m map[K]V) []V {
+	keys := slices.Sorted(maps.Keys(m))
+	out := make([]V, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, m[k])
+	}
+	return out
+}
+
+// paginate meniru LIMIT/OFFSET; page dimulai dari 1.
+func paginate[T any](items []T, page, limit int) []T {
+	offset := 0
+	if page > 1 {
+		offset = (page - 1) * limit
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return slices.Clone(items[offset:end])
+}
+
+// dateOf mengembalikan tanggal lokal t (seperti cast timestamptz::date).
+func dateOf(t time.Time) string {
+	return t.Local().Format(dateLayout)
+}
+
+// inDateRange mengecek start <= date <= end (tanggal berformat YYYY-MM-DD).
+func inDateRange(date string, start, end time.Time) bool {
+	return date >= start.Format(dateLayout) && date <= end.Format(dateLayout)
+}
+
+// midnight mengembalikan awal hari lokal t.
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// clonePtr menyalin nilai yang ditunjuk agar data store tidak ikut berubah lewat pointer.
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// roundHours membulatkan jam ke dua desimal seperti ROUND(..., 2).
+func roundHours(h float64) float64 {
+	return float64(int64(h*100+0.5)) / 100
+}
@@ -0,0 +1,596 @@
+// internal/repository/memory/ops.go
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// --- Token perangkat & pengingat shift ---
+
+type deviceRepo struct{ *store }
+
+func (r *deviceRepo) RegisterDeviceToken(ctx context.Context, userID int, platform, token string) (*models.DeviceToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.users[userID] == nil {
+		return nil, fmt.Errorf("error registering device token for user %d: user not found", userID)
+	}
+	now := time.Now()
+	for _, d := range r.deviceTokens {
+		if d.Token == token {
+			d.UserID, d.Platform, d.UpdatedAt = userID, platform, now
+			return clonePtr(d), nil
+		}
+	}
+	d := &models.DeviceToken{ID: r.nextID("device_tokens"), UserID: userID, Platform: platform, Token: token, CreatedAt: now, UpdatedAt: now}
+	r.deviceTokens[d.ID] = d
+	return clonePtr(d), nil
+}
+
+func (r *deviceRepo) DeleteDeviceToken(ctx context.Context, userID int, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if deleteWhere(r.deviceTokens, func(d *models.DeviceToken) bool { return d.UserID == userID && d.Token == token }) == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *deviceRepo) GetDeviceTokensByUser(ctx context.Context, userID int) ([]models.DeviceToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tokens := []models.DeviceToken{}
+	for _, d := range sortedByKey(r.deviceTokens) {
+		if d.UserID == userID {
+			tokens = append(tokens, *d)
+		}
+	}
+	slices.SortStableFunc(tokens, func(a, b models.DeviceToken) int { return b.UpdatedAt.Compare(a.UpdatedAt) })
+	return tokens, nil
+}
+
+func (r *deviceRepo) DeleteDeviceTokens(ctx context.Context, tokens []string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return deleteWhere(r.deviceTokens, func(d *models.DeviceToken) bool { return slices.Contains(tokens, d.Token) }), nil
+}
+
+func (r *deviceRepo) GetPendingShiftReminders(ctx context.Context, fromDate, toDate time.Time) ([]models.ShiftReminder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reminders := []models.ShiftReminder{}
+	for _, sc := range sortedByKey(r.schedules) {
+		u := r.users[sc.UserID]
+		if u == nil || !u.IsActive || !inDateRange(sc.Date, fromDate, toDate) || r.remindersSent[sc.ID] {
+			continue
+		}
+		sms := u.SMSReminders && u.PhoneVerifiedAt != nil
+		hasDevice := false
+		for _, d := range r.deviceTokens {
+			hasDevice = hasDevice || d.UserID == u.ID
+		}
+		shift := r.effectiveShift(sc.ShiftID, sc.Date)
+		if shift == nil || (!hasDevice && !sms) {
+			continue
+		}
+		reminders = append(reminders, models.ShiftReminder{
+			ScheduleID: sc.ID, UserID: u.ID, Date: sc.Date, ShiftName: shift.Name, StartTime: shift.StartTime, SMS: sms,
+		})
+	}
+	slices.SortStableFunc(reminders, func(a, b models.ShiftReminder) int {
+		return cmp.Or(strings.Compare(a.Date, b.Date), strings.Compare(a.StartTime, b.StartTime))
+	})
+	return reminders, nil
+}
+
+func (r *deviceRepo) MarkShiftReminderSent(ctx context.Context, scheduleID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.schedules[scheduleID] == nil {
+		return false, fmt.Errorf("error marking shift reminder for schedule %d: schedule not found", scheduleID)
+	}
+	if r.remindersSent[scheduleID] {
+		return false, nil
+	}
+	r.remindersSent[scheduleID] = true
+	return true, nil
+}
+
+// --- Delegasi approval ---
+
+type delegationRepo struct{ *store }
+
+func delegationCopy(d *models.ApprovalDelegation) *models.ApprovalDelegation {
+	out := *d
+	out.Reason, out.RevokedAt = clonePtr(d.Reason), clonePtr(d.RevokedAt)
+	return &out
+}
+
+func (r *delegationRepo) CreateDelegation(ctx context.Context, delegatorID int, input models.DelegationInput) (*models.ApprovalDelegation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u := r.users[input.DelegateID]; u == nil || !u.IsActive {
+		return nil, pgx.ErrNoRows
+	}
+	d := &models.ApprovalDelegation{
+		ID: r.nextID("approval_delegations"), DelegatorID: delegatorID, DelegateID: input.DelegateID,
+		StartDate: input.StartDate, EndDate: input.EndDate, Reason: clonePtr(input.Reason), CreatedAt: time.Now(),
+	}
+	r.delegations[d.ID] = d
+	return delegationCopy(d), nil
+}
+
+func (r *delegationRepo) GetDelegationsByUser(ctx context.Context, userID int) ([]models.ApprovalDelegation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delegations := []models.ApprovalDelegation{}
+	for _, d := range sortedByKey(r.delegations) {
+		if d.DelegatorID == userID || d.DelegateID == userID {
+			delegations = append(delegations, *delegationCopy(d))
+		}
+	}
+	slices.SortStableFunc(delegations, func(a, b models.ApprovalDelegation) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	slices.Reverse(delegations)
+	return delegations, nil
+}
+
+func (r *delegationRepo) RevokeDelegation(ctx context.Context, id, delegatorID int) (*models.ApprovalDelegation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.delegations[id]
+	if d == nil || d.DelegatorID != delegatorID {
+		return nil, pgx.ErrNoRows
+	}
+	if d.RevokedAt != nil {
+		return nil, repository.ErrDelegationRevoked
+	}
+	d.RevokedAt = ptr(time.Now())
+	return delegationCopy(d), nil
+}
+
+func (r *delegationRepo) HasActiveDelegation(ctx context.Context, delegatorID, delegateID int, day time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	date := day.Format(dateLayout)
+	for _, d := range r.delegations {
+		if d.DelegatorID == delegatorID && d.DelegateID == delegateID && d.RevokedAt == nil && date >= d.StartDate && date <= d.EndDate {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// --- Eskalasi approval ---
+
+type escalationRepo struct{ *store }
+
+func (r *escalationRepo) RecordEscalation(ctx context.Context, e *models.ApprovalEscalation) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.escalations {
+		if existing.ItemType == e.ItemType && existing.ItemID == e.ItemID && existing.Level == e.Level {
+			return false, nil
+		}
+	}
+	e.ID, e.CreatedAt = r.nextID("approval_escalations"), time.Now()
+	r.escalations = append(r.escalations, *e)
+	r.escalations[len(r.escalations)-1].EscalatedTo = clonePtr(e.EscalatedTo)
+	return true, nil
+}
+
+func (r *escalationRepo) GetEscalations(ctx context.Context, itemType string, itemIDs []int) (map[int][]models.ApprovalEscalation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	history := map[int][]models.ApprovalEscalation{}
+	for _, e := range r.escalations {
+		if e.ItemType == itemType && slices.Contains(itemIDs, e.ItemID) {
+			e.EscalatedTo = clonePtr(e.EscalatedTo)
+			history[e.ItemID] = append(history[e.ItemID], e)
+		}
+	}
+	for _, steps := range history {
+		slices.SortFunc(steps, func(a, b models.ApprovalEscalation) int { return a.Level - b.Level })
+	}
+	return history, nil
+}
+
+// --- Inbox notifikasi ---
+
+type notificationRepo struct{ *store }
+
+func notificationCopy(n *models.Notification) models.Notification {
+	out := *n
+	out.ReadAt = clonePtr(n.ReadAt)
+	out.Data = make(map[string]string, len(n.Data))
+	for k, v := range n.Data {
+		out.Data[k] = v
+	}
+	return out
+}
+
+func (r *notificationRepo) CreateNotification(ctx context.Context, n *models.Notification) (int, error) {
+	if n.Data == nil {
+		n.Data = map[string]string{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.users[n.UserID] == nil {
+		return 0, fmt.Errorf("error creating notification for user %d: user not found", n.UserID)
+	}
+	n.ID, n.CreatedAt = r.nextID("notifications"), time.Now()
+	stored := notificationCopy(n)
+	stored.ReadAt = nil
+	r.notifications[n.ID] = &stored
+	return n.ID, nil
+}
+
+func (r *notificationRepo) GetNotificationsByUser(ctx context.Context, userID int, unreadOnly bool, page, limit int) ([]models.Notification, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := []models.Notification{}
+	for _, n := range sortedByKey(r.notifications) {
+		if n.UserID == userID && (!unreadOnly || n.ReadAt == nil) {
+			all = append(all, notificationCopy(n))
+		}
+	}
+	slices.SortStableFunc(all, func(a, b models.Notification) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	slices.Reverse(all)
+	return paginate(all, page, limit), len(all), nil
+}
+
+func (r *notificationRepo) CountUnreadNotifications(ctx context.Context, userID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, n := range r.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *notificationRepo) MarkNotificationRead(ctx context.Context, userID, id int) (*models.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.notifications[id]
+	if n == nil || n.UserID != userID {
+		return nil, pgx.ErrNoRows
+	}
+	if n.ReadAt == nil {
+		n.ReadAt = ptr(time.Now())
+	}
+	out := notificationCopy(n)
+	return &out, nil
+}
+
+func (r *notificationRepo) MarkAllNotificationsRead(ctx context.Context, userID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now, count := time.Now(), 0
+	for _, n := range r.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			n.ReadAt = ptr(now)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// --- Outbox ---
+
+// maxOutboxErrorLength sama dengan batas last_error di repository.outboxRepo.
+const maxOutboxErrorLength = 1000
+
+// outboxRow adalah baris outbox_messages termasuk kolom yang tidak diekspos model.
+type outboxRow struct {
+	models.OutboxMessage
+	deliveredAt *time.Time
+	claimed     bool // Sedang dikirim (pengganti FOR UPDATE SKIP LOCKED)
+}
+
+type outboxRepo struct{ *store }
+
+func outboxCopy(row *outboxRow) models.OutboxMessage {
+	msg := row.OutboxMessage
+	msg.UserID, msg.LastError, msg.DeadAt = clonePtr(row.UserID), clonePtr(row.LastError), clonePtr(row.DeadAt)
+	msg.Payload = slices.Clone(row.Payload)
+	return msg
+}
+
+func (r *outboxRepo) EnqueueOutboxMessages(ctx context.Context, msgs []models.OutboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, msg := range msgs {
+		row := &outboxRow{OutboxMessage: models.OutboxMessage{
+			ID: int64(r.nextID("outbox_messages")), Destination: msg.Destination, EventName: msg.EventName,
+			UserID: clonePtr(msg.UserID), Payload: slices.Clone(msg.Payload), AvailableAt: now, CreatedAt: now,
+		}}
+		r.outbox[row.ID] = row
+	}
+	return nil
+}
+
+// ProcessDueOutboxMessages mengklaim pesan jatuh tempo lalu memanggil deliver tanpa memegang
+// lock store, karena deliver biasanya menulis ke repository lain (mis. notifikasi).
+func (r *outboxRepo) ProcessDueOutboxMessages(ctx context.Context, limit, maxAttempts int, backoff func(attempts int) time.Duration, deliver func(ctx context.Context, msg models.OutboxMessage) error) (int, int, error) {
+	r.mu.Lock()
+	now := time.Now()
+	var due []models.OutboxMessage
+	for _, row := range sortedByKey(r.outbox) {
+		if len(due) < limit && row.deliveredAt == nil && row.DeadAt == nil && !row.claimed && !row.AvailableAt.After(now) {
+			row.claimed = true
+			due = append(due, outboxCopy(row))
+		}
+	}
+	r.mu.Unlock()
+
+	delivered, failed := 0, 0
+	for _, msg := range due {
+		deliverErr := deliver(ctx, msg)
+		r.mu.Lock()
+		row := r.outbox[msg.ID]
+		if row == nil {
+			r.mu.Unlock()
+			continue
+		}
+		row.claimed = false
+		row.Attempts++
+		if deliverErr == nil {
+			row.LastError, row.deliveredAt = nil, ptr(time.Now())
+			delivered++
+		} else {
+			failed++
+			reason := deliverErr.Error()
+			if len(reason) > maxOutboxErrorLength {
+				reason = reason[:maxOutboxErrorLength]
+			}
+			row.LastError = ptr(reason)
+			if row.Attempts >= maxAttempts {
+				row.DeadAt = ptr(time.Now())
+			} else {
+				row.AvailableAt = time.Now().Add(backoff(row.Attempts))
+			}
+		}
+		r.mu.Unlock()
+	}
+	return delivered, failed, nil
+}
+
+func (r *outboxRepo) GetDeadOutboxMessages(ctx context.Context, page, limit int) ([]models.OutboxMessage, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := []models.OutboxMessage{}
+	for _, row := range sortedByKey(r.outbox) {
+		if row.DeadAt != nil {
+			all = append(all, outboxCopy(row))
+		}
+	}
+	slices.Reverse(all)
+	slices.SortStableFunc(all, func(a, b models.OutboxMessage) int { return b.DeadAt.Compare(*a.DeadAt) })
+	return paginate(all, page, limit), len(all), nil
+}
+
+func (r *outboxRepo) RetryOutboxMessage(ctx context.Context, id int64) (*models.OutboxMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.outbox[id]
+	if row == nil {
+		return nil, pgx.ErrNoRows
+	}
+	if row.DeadAt == nil {
+		return nil, repository.ErrOutboxMessageNotDead
+	}
+	row.Attempts, row.DeadAt, row.AvailableAt = 0, nil, time.Now()
+	msg := outboxCopy(row)
+	return &msg, nil
+}
+
+func (r *outboxRepo) DeleteDeliveredOutboxMessages(ctx context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return deleteWhere(r.outbox, func(row *outboxRow) bool { return row.deliveredAt != nil && row.deliveredAt.Before(before) }), nil
+}
+
+// --- Job terjadwal ---
+
+type jobRepo struct{ *store }
+
+func (r *jobRepo) RegisterJobs(ctx context.Context, defs []models.JobDefinition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, def := range defs {
+		interval := max(int(def.Interval/time.Second), 1)
+		if job := r.jobs[def.Name]; job != nil {
+			job.IntervalSeconds = interval
+			continue
+		}
+		r.jobs[def.Name] = &models.ScheduledJob{Name: def.Name, IntervalSeconds: interval, NextRunAt: time.Now()}
+	}
+	return nil
+}
+
+func (r *jobRepo) GetJobs(ctx context.Context) ([]models.ScheduledJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	jobs := []models.ScheduledJob{}
+	for _, job := range sortedByKey(r.jobs) {
+		out := *job
+		out.LastStartedAt, out.LastFinishedAt, out.LastStatus, out.LastTrigger = clonePtr(job.LastStartedAt), clonePtr(job.LastFinishedAt), clonePtr(job.LastStatus), clonePtr(job.LastTrigger)
+		out.LastResult, out.LastError, out.LastDurationMs = clonePtr(job.LastResult), clonePtr(job.LastError), clonePtr(job.LastDurationMs)
+		jobs = append(jobs, out)
+	}
+	return jobs, nil
+}
+
+func (r *jobRepo) StartJobRun(ctx context.Context, name, trigger string, force bool) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, now := r.jobs[name], time.Now()
+	if job == nil || (!force && job.NextRunAt.After(now)) {
+		return false, nil
+	}
+	job.LastStatus, job.LastTrigger, job.LastStartedAt = ptr(models.JobStatusRunning), ptr(trigger), ptr(now)
+	job.NextRunAt = now.Add(time.Duration(job.IntervalSeconds) * time.Second)
+	return true, nil
+}
+
+func (r *jobRepo) FinishJobRun(ctx context.Context, name string, result int, runErr *string, duration time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[name]
+	if job == nil {
+		return nil
+	}
+	job.LastStatus, job.LastResult, job.LastError = ptr(models.JobStatusSucceeded), ptr(result), nil
+	if runErr != nil {
+		job.LastStatus, job.LastResult, job.LastError = ptr(models.JobStatusFailed), nil, clonePtr(runErr)
+		job.FailureCount++
+	}
+	job.LastFinishedAt, job.LastDurationMs = ptr(time.Now()), ptr(duration.Milliseconds())
+	job.RunCount++
+	return nil
+}
+
+// --- Biaya tenaga kerja ---
+
+type laborRepo struct{ *store }
+
+func (r *laborRepo) SetUserHourlyRate(ctx context.Context, userID int, rate *float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := r.users[userID]
+	if u == nil {
+		return pgx.ErrNoRows
+	}
+	r.updateUser(u, func(u *userRow) { u.hourlyRate = clonePtr(rate) })
+	return nil
+}
+
+func (r *laborRepo) SetRoleHourlyRate(ctx context.Context, roleID int, rate *float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	role := r.roles[roleID]
+	if role == nil {
+		return pgx.ErrNoRows
+	}
+	role.hourlyRate = clonePtr(rate)
+	return nil
+}
+
+// laborGroup mengakumulasi satu kelompok laporan biaya tenaga kerja.
+type laborGroup struct {
+	row   models.LaborCostRow
+	users map[int]bool
+}
+
+func (g *laborGroup) add(userID int, scheduled, actual float64, rate *float64) {
+	g.users[userID] = true
+	g.row.ScheduledHours += scheduled
+	g.row.ActualHours += actual
+	if rate == nil {
+		g.row.UnratedScheduledHours += scheduled
+		g.row.UnratedActualHours += actual
+		return
+	}
+	g.row.ScheduledCost += scheduled * *rate
+	g.row.ActualCost += actual * *rate
+}
+
+func (g *laborGroup) result() models.LaborCostRow {
+	row := g.row
+	row.Users = len(g.users)
+	row.ScheduledHours, row.ActualHours = roundHours(row.ScheduledHours), roundHours(row.ActualHours)
+	row.ScheduledCost, row.ActualCost = roundHours(row.ScheduledCost), roundHours(row.ActualCost)
+	row.UnratedScheduledHours, row.UnratedActualHours = roundHours(row.UnratedScheduledHours), roundHours(row.UnratedActualHours)
+	row.CostVariance = roundHours(row.ActualCost - row.ScheduledCost)
+	return row
+}
+
+func (r *laborRepo) GetLaborCost(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]models.LaborCostRow, *models.LaborCostRow, error) {
+	switch groupBy {
+	case models.LaborGroupDay, models.LaborGroupRole, models.LaborGroupManager:
+	default:
+		return nil, nil, fmt.Errorf("unsupported labor cost grouping %q", groupBy)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	groups := map[string]*laborGroup{}
+	total := &laborGroup{users: map[int]bool{}}
+	record := func(userID int, day string, scheduled, actual float64) {
+		u := r.users[userID]
+		if u == nil || r.roles[u.RoleID] == nil {
+			return
+		}
+		role := r.roles[u.RoleID]
+		rate := u.hourlyRate
+		if rate == nil {
+			rate = role.hourlyRate
+		}
+		var key *string
+		var id *int
+		switch groupBy {
+		case models.LaborGroupDay:
+			key = ptr(day)
+		case models.LaborGroupRole:
+			key, id = ptr(role.Name), ptr(role.ID)
+		case models.LaborGroupManager:
+			if u.ManagerID != nil {
+				id = ptr(*u.ManagerID)
+				if m := r.users[*u.ManagerID]; m != nil {
+					key = ptr(m.Username)
+				}
+			}
+		}
+		mapKey := "\x00" // Kunci NULL
+		if key != nil {
+			mapKey = "=" + *key
+		}
+		g := groups[mapKey]
+		if g == nil {
+			g = &laborGroup{row: models.LaborCostRow{Key: key}, users: map[int]bool{}}
+			groups[mapKey] = g
+		}
+		if id != nil && (g.row.ID == nil || *id < *g.row.ID) {
+			g.row.ID = id
+		}
+		g.add(userID, scheduled, actual, rate)
+		total.add(userID, scheduled, actual, rate)
+	}
+	for _, sc := range sortedByKey(r.schedules) {
+		if shift := r.effectiveShift(sc.ShiftID, sc.Date); shift != nil && inDateRange(sc.Date, startDate, endDate) {
+			start, end := shiftWindow(sc.Date, shift)
+			record(sc.UserID, sc.Date, end.Sub(start).Hours(), 0)
+		}
+	}
+	for _, a := range sortedByKey(r.attendances) {
+		if day := dateOf(a.CheckInAt); a.CheckOutAt != nil && inDateRange(day, startDate, endDate) {
+			record(a.UserID, day, 0, a.CheckOutAt.Sub(a.CheckInAt).Hours())
+		}
+	}
+	rows := []models.LaborCostRow{}
+	for _, g := range groups {
+		rows = append(rows, g.result())
+	}
+	slices.SortFunc(rows, func(a, b models.LaborCostRow) int {
+		switch {
+		case a.Key == nil:
+			return 1
+		case b.Key == nil:
+			return -1
+		}
+		return strings.Compare(*a.Key, *b.Key)
+	})
+	totals := total.result()
+	return rows, &totals, nil
+}
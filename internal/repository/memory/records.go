@@ -0,0 +1,748 @@
+// internal/repository/memory/records.go
+package memory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// newestFirst mengurutkan items menurut created DESC lalu id DESC; items diasumsikan urut id ASC.
+func newestFirst[T any](items []T, created func(T) time.Time) {
+	slices.Reverse(items)
+	slices.SortStableFunc(items, func(a, b T) int { return created(b).Compare(created(a)) })
+}
+
+// --- Verifikasi kepegawaian ---
+
+type verificationRepo struct{ *store }
+
+func verificationLinkCopy(l *models.EmploymentVerificationLink) models.EmploymentVerificationLink {
+	out := *l
+	out.CreatedBy, out.RevokedAt, out.LastAccessedAt = clonePtr(l.CreatedBy), clonePtr(l.RevokedAt), clonePtr(l.LastAccessedAt)
+	out.Token, out.Path = "", ""
+	return out
+}
+
+func (r *verificationRepo) CreateVerificationLink(ctx context.Context, link *models.EmploymentVerificationLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.users[link.UserID] == nil {
+		return pgx.ErrNoRows
+	}
+	stored := &models.EmploymentVerificationLink{
+		ID: r.nextID("employment_verification_links"), UserID: link.UserID, Recipient: link.Recipient,
+		IncludeAttendance: link.IncludeAttendance, ExpiresAt: link.ExpiresAt, CreatedBy: clonePtr(link.CreatedBy), CreatedAt: time.Now(),
+	}
+	r.verificationLinks[stored.ID] = stored
+	*link = verificationLinkCopy(stored)
+	return nil
+}
+
+func (r *verificationRepo) GetVerificationLink(ctx context.Context, id int) (*models.EmploymentVerificationLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l := r.verificationLinks[id]
+	if l == nil {
+		return nil, pgx.ErrNoRows
+	}
+	out := verificationLinkCopy(l)
+	return &out, nil
+}
+
+func (r *verificationRepo) GetVerificationLinksByUser(ctx context.Context, userID int) ([]models.EmploymentVerificationLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	links := []models.EmploymentVerificationLink{}
+	for _, l := range sortedByKey(r.verificationLinks) {
+		if l.UserID == userID {
+			links = append(links, verificationLinkCopy(l))
+		}
+	}
+	newestFirst(links, func(l models.EmploymentVerificationLink) time.Time { return l.CreatedAt })
+	return links, nil
+}
+
+func (r *verificationRepo) RevokeVerificationLink(ctx context.Context, id int) (*models.EmploymentVerificationLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l := r.verificationLinks[id]
+	if l == nil || l.RevokedAt != nil {
+		return nil, pgx.ErrNoRows
+	}
+	l.RevokedAt = ptr(time.Now())
+	out := verificationLinkCopy(l)
+	return &out, nil
+}
+
+func (r *verificationRepo) RecordVerificationAccess(ctx context.Context, access *models.EmploymentVerificationAccess) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l := r.verificationLinks[access.LinkID]
+	if l == nil {
+		return fmt.Errorf("error recording access to verification link %d: link not found", access.LinkID)
+	}
+	access.ID, access.AccessedAt = int64(r.nextID("employment_verification_accesses")), time.Now()
+	if access.Outcome == models.VerificationAccessGranted {
+		l.AccessCount++
+		l.LastAccessedAt = ptr(access.AccessedAt)
+	}
+	stored := *access
+	stored.UserAgent = clonePtr(access.UserAgent)
+	r.verificationAccess = append(r.verificationAccess, stored)
+	return nil
+}
+
+func (r *verificationRepo) GetVerificationAccesses(ctx context.Context, linkID int) ([]models.EmploymentVerificationAccess, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	accesses := []models.EmploymentVerificationAccess{}
+	for _, a := range r.verificationAccess {
+		if a.LinkID == linkID {
+			a.UserAgent = clonePtr(a.UserAgent)
+			accesses = append(accesses, a)
+		}
+	}
+	newestFirst(accesses, func(a models.EmploymentVerificationAccess) time.Time { return a.AccessedAt })
+	return accesses, nil
+}
+
+func (r *verificationRepo) GetEmploymentVerification(ctx context.Context, userID int, attendanceSince *time.Time) (*models.EmploymentVerification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := r.users[userID]
+	if u == nil || r.roles[u.RoleID] == nil {
+		return nil, pgx.ErrNoRows
+	}
+	v := &models.EmploymentVerification{
+		EmployeeName:     strings.TrimSpace(strings.Join(slices.DeleteFunc([]string{u.FirstName, u.LastName}, func(s string) bool { return s == "" }), " ")),
+		Employed:         u.IsActive && u.EmploymentStatus != models.EmploymentStatusTerminated,
+		EmploymentStatus: u.EmploymentStatus,
+		Position:         r.roles[u.RoleID].Name,
+		HireDate:         clonePtr(u.HireDate),
+	}
+	if v.EmployeeName == "" {
+		v.EmployeeName = u.Username // User tanpa nama lengkap
+	}
+	if attendanceSince != nil {
+		since := attendanceSince.Format(dateLayout)
+		days := map[string]bool{}
+		for _, a := range r.attendances {
+			if a.UserID == userID && dateOf(a.CheckInAt) >= since {
+				days[dateOf(a.CheckInAt)] = true
+			}
+		}
+		v.Attendance = &models.EmploymentVerificationAttendance{
+			PeriodStart: since, PeriodEnd: time.Now().Format(dateLayout), DaysPresent: len(days),
+		}
+	}
+	return v, nil
+}
+
+// --- Perangkat kiosk ---
+
+type kioskRepo struct{ *store }
+
+func kioskCopy(k *models.KioskDevice) *models.KioskDevice {
+	out := *k
+	out.LastSeenAt, out.RevokedAt, out.RevokedBy = clonePtr(k.LastSeenAt), clonePtr(k.RevokedAt), clonePtr(k.RevokedBy)
+	return &out
+}
+
+func (r *kioskRepo) CreateKioskDevice(ctx context.Context, device *models.KioskDevice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.users[device.UserID] == nil {
+		return fmt.Errorf("error enrolling kiosk device for user %d: user not found", device.UserID)
+	}
+	for _, k := range r.kiosks {
+		if k.UserID == device.UserID && k.FingerprintHash == device.FingerprintHash && k.RevokedAt == nil {
+			return repository.ErrKioskAlreadyEnrolled
+		}
+	}
+	k := &models.KioskDevice{ID: r.nextID("kiosk_devices"), UserID: device.UserID, Name: device.Name, FingerprintHash: device.FingerprintHash, CreatedAt: time.Now()}
+	r.kiosks[k.ID] = k
+	*device = *kioskCopy(k)
+	return nil
+}
+
+func (r *kioskRepo) GetKioskDevice(ctx context.Context, id int) (*models.KioskDevice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k := r.kiosks[id]
+	if k == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return kioskCopy(k), nil
+}
+
+func (r *kioskRepo) GetKioskDevicesByUser(ctx context.Context, userID int) ([]models.KioskDevice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	devices := []models.KioskDevice{}
+	for _, k := range sortedByKey(r.kiosks) {
+		if k.UserID == userID {
+			devices = append(devices, *kioskCopy(k))
+		}
+	}
+	newestFirst(devices, func(k models.KioskDevice) time.Time { return k.CreatedAt })
+	return devices, nil
+}
+
+func (r *kioskRepo) RevokeKioskDevice(ctx context.Context, id int, ownerID *int, revokedBy int) (*models.KioskDevice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k := r.kiosks[id]
+	if k == nil || (ownerID != nil && k.UserID != *ownerID) || k.RevokedAt != nil {
+		return nil, pgx.ErrNoRows
+	}
+	k.RevokedAt, k.RevokedBy = ptr(time.Now()), ptr(revokedBy)
+	return kioskCopy(k), nil
+}
+
+func (r *kioskRepo) TouchKioskDevice(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if k := r.kiosks[id]; k != nil {
+		k.LastSeenAt = ptr(time.Now())
+	}
+	return nil
+}
+
+// --- Foto check-in ---
+
+type attendancePhotoRepo struct{ *store }
+
+func photoCopy(p *models.AttendancePhoto) models.AttendancePhoto {
+	out := *p
+	out.IncomingKey, out.OriginalKey, out.ThumbnailKey = clonePtr(p.IncomingKey), clonePtr(p.OriginalKey), clonePtr(p.ThumbnailKey)
+	out.Width, out.Height, out.Error = clonePtr(p.Width), clonePtr(p.Height), clonePtr(p.Error)
+	out.ProcessedAt, out.PurgedAt = clonePtr(p.ProcessedAt), clonePtr(p.PurgedAt)
+	return out
+}
+
+// oldestPhotos mengembalikan maksimal limit foto yang memenuhi fn, urut captured_at lalu id.
+func (s *store) oldestPhotos(limit int, fn func(p *models.AttendancePhoto) bool) []models.AttendancePhoto {
+	photos := []models.AttendancePhoto{}
+	for _, p := range sortedByKey(s.photos) {
+		if fn(p) {
+			photos = append(photos, photoCopy(p))
+		}
+	}
+	slices.SortStableFunc(photos, func(a, b models.AttendancePhoto) int { return a.CapturedAt.Compare(b.CapturedAt) })
+	return photos[:min(limit, len(photos))]
+}
+
+func (r *attendancePhotoRepo) CreateAttendancePhoto(ctx context.Context, photo *models.AttendancePhoto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.attendances[photo.AttendanceID] == nil {
+		return fmt.Errorf("error creating photo of attendance %d: attendance not found", photo.AttendanceID)
+	}
+	for _, p := range r.photos {
+		if p.AttendanceID == photo.AttendanceID {
+			return fmt.Errorf("error creating photo of attendance %d: photo already exists", photo.AttendanceID)
+		}
+	}
+	p := &models.AttendancePhoto{
+		ID: r.nextID("attendance_photos"), AttendanceID: photo.AttendanceID, UserID: photo.UserID,
+		Status: models.AttendancePhotoPending, IncomingKey: clonePtr(photo.IncomingKey), CapturedAt: time.Now(),
+	}
+	r.photos[p.ID] = p
+	*photo = photoCopy(p)
+	return nil
+}
+
+func (r *attendancePhotoRepo) GetAttendancePhoto(ctx context.Context, attendanceID int) (*models.AttendancePhoto, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.photos {
+		if p.AttendanceID == attendanceID {
+			out := photoCopy(p)
+			return &out, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (r *attendancePhotoRepo) GetAttendancePhotoByID(ctx context.Context, id int) (*models.AttendancePhoto, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.photos[id]
+	if p == nil {
+		return nil, pgx.ErrNoRows
+	}
+	out := photoCopy(p)
+	return &out, nil
+}
+
+func (r *attendancePhotoRepo) GetPendingAttendancePhotos(ctx context.Context, limit int) ([]models.AttendancePhoto, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.oldestPhotos(limit, func(p *models.AttendancePhoto) bool { return p.Status == models.AttendancePhotoPending }), nil
+}
+
+func (r *attendancePhotoRepo) GetExpiredAttendancePhotos(ctx context.Context, capturedBefore time.Time, limit int) ([]models.AttendancePhoto, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.oldestPhotos(limit, func(p *models.AttendancePhoto) bool {
+		return p.Status != models.AttendancePhotoPurged && p.CapturedAt.Before(capturedBefore)
+	}), nil
+}
+
+// updatePhoto menjalankan fn atas foto id; pgx.ErrNoRows jika tidak ada.
+func (r *attendancePhotoRepo) updatePhoto(id int, fn func(p *models.AttendancePhoto)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.photos[id]
+	if p == nil {
+		return pgx.ErrNoRows
+	}
+	fn(p)
+	return nil
+}
+
+func (r *attendancePhotoRepo) MarkAttendancePhotoProcessed(ctx context.Context, id int, originalKey, thumbnailKey string, width, height int) error {
+	return r.updatePhoto(id, func(p *models.AttendancePhoto) {
+		p.Status, p.OriginalKey, p.ThumbnailKey, p.Width, p.Height = models.AttendancePhotoProcessed, ptr(originalKey), ptr(thumbnailKey), ptr(width), ptr(height)
+		p.IncomingKey, p.Error, p.ProcessedAt = nil, nil, ptr(time.Now())
+	})
+}
+
+func (r *attendancePhotoRepo) MarkAttendancePhotoFailed(ctx context.Context, id int, reason string) error {
+	return r.updatePhoto(id, func(p *models.AttendancePhoto) {
+		p.Status, p.Error, p.IncomingKey, p.ProcessedAt = models.AttendancePhotoFailed, ptr(reason), nil, ptr(time.Now())
+	})
+}
+
+func (r *attendancePhotoRepo) MarkAttendancePhotoPurged(ctx context.Context, id int) error {
+	return r.updatePhoto(id, func(p *models.AttendancePhoto) {
+		p.Status, p.IncomingKey, p.OriginalKey, p.ThumbnailKey, p.PurgedAt = models.AttendancePhotoPurged, nil, nil, nil, ptr(time.Now())
+	})
+}
+
+// --- Ekspor & snapshot laporan ---
+
+type reportExportRepo struct{ *store }
+
+func reportExportCopy(re *models.ReportExport) models.ReportExport {
+	out := *re
+	out.Params, out.RequestedBy, out.StorageKey = slices.Clone(re.Params), clonePtr(re.RequestedBy), clonePtr(re.StorageKey)
+	out.FileName, out.ContentType, out.SizeBytes, out.RowCount = clonePtr(re.FileName), clonePtr(re.ContentType), clonePtr(re.SizeBytes), clonePtr(re.RowCount)
+	out.Error, out.CompletedAt, out.ExpiresAt, out.PurgedAt = clonePtr(re.Error), clonePtr(re.CompletedAt), clonePtr(re.ExpiresAt), clonePtr(re.PurgedAt)
+	return out
+}
+
+func (s *store) reportExportsWhere(fn func(re *models.ReportExport) bool) []models.ReportExport {
+	reports := []models.ReportExport{}
+	for _, re := range sortedByKey(s.reportExports) {
+		if fn(re) {
+			reports = append(reports, reportExportCopy(re))
+		}
+	}
+	return reports
+}
+
+func (r *reportExportRepo) CreateReportExport(ctx context.Context, report *models.ReportExport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	params := report.Params
+	if len(params) == 0 {
+		params = []byte("{}")
+	}
+	re := &models.ReportExport{
+		ID: r.nextID("report_exports"), ReportType: report.ReportType, Format: report.Format, Params: slices.Clone(params),
+		Status: models.ReportExportPending, RequestedBy: clonePtr(report.RequestedBy), RetentionSeconds: report.RetentionSeconds, CreatedAt: time.Now(),
+	}
+	r.reportExports[re.ID] = re
+	*report = reportExportCopy(re)
+	return nil
+}
+
+func (r *reportExportRepo) GetReportExportByID(ctx context.Context, id int) (*models.ReportExport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	re := r.reportExports[id]
+	if re == nil {
+		return nil, pgx.ErrNoRows
+	}
+	out := reportExportCopy(re)
+	return &out, nil
+}
+
+func (r *reportExportRepo) GetReportExports(ctx context.Context, page, limit int) ([]models.ReportExport, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := r.reportExportsWhere(func(*models.ReportExport) bool { return true })
+	newestFirst(all, func(re models.ReportExport) time.Time { return re.CreatedAt })
+	return paginate(all, page, limit), len(all), nil
+}
+
+func (r *reportExportRepo) GetPendingReportExports(ctx context.Context, limit int) ([]models.ReportExport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reports := r.reportExportsWhere(func(re *models.ReportExport) bool { return re.Status == models.ReportExportPending })
+	slices.SortStableFunc(reports, func(a, b models.ReportExport) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return reports[:min(limit, len(reports))], nil
+}
+
+func (r *reportExportRepo) MarkReportExportCompleted(ctx context.Context, id int, result models.ReportExportResult) (*models.ReportExport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	re := r.reportExports[id]
+	if re == nil || re.Status != models.ReportExportPending {
+		return nil, pgx.ErrNoRows
+	}
+	now := time.Now()
+	re.Status, re.StorageKey, re.FileName, re.ContentType = models.ReportExportCompleted, ptr(result.StorageKey), ptr(result.FileName), ptr(result.ContentType)
+	re.SizeBytes, re.RowCount, re.Error, re.CompletedAt = ptr(result.SizeBytes), ptr(result.RowCount), nil, ptr(now)
+	re.ExpiresAt = ptr(now.Add(time.Duration(re.RetentionSeconds) * time.Second))
+	out := reportExportCopy(re)
+	return &out, nil
+}
+
+func (r *reportExportRepo) MarkReportExportFailed(ctx context.Context, id int, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	re := r.reportExports[id]
+	if re == nil || re.Status != models.ReportExportPending {
+		return pgx.ErrNoRows
+	}
+	re.Status, re.Error, re.CompletedAt = models.ReportExportFailed, ptr(reason), ptr(time.Now())
+	return nil
+}
+
+func (r *reportExportRepo) GetExpiredReportExports(ctx context.Context, before time.Time, limit int) ([]models.ReportExport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reports := r.reportExportsWhere(func(re *models.ReportExport) bool {
+		return re.Status == models.ReportExportCompleted && re.ExpiresAt != nil && re.ExpiresAt.Before(before)
+	})
+	slices.SortStableFunc(reports, func(a, b models.ReportExport) int { return a.ExpiresAt.Compare(*b.ExpiresAt) })
+	return reports[:min(limit, len(reports))], nil
+}
+
+func (r *reportExportRepo) MarkReportExportPurged(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	re := r.reportExports[id]
+	if re == nil {
+		return pgx.ErrNoRows
+	}
+	re.Status, re.StorageKey, re.PurgedAt = models.ReportExportPurged, nil, ptr(time.Now())
+	return nil
+}
+
+func (r *reportExportRepo) GetSnapshotSessions(ctx context.Context, startDate, endDate time.Time) ([]models.Attendance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sessions := []models.Attendance{}
+	for _, a := range sortedByKey(r.attendances) {
+		u := r.users[a.UserID]
+		if u == nil || !inDateRange(dateOf(a.CheckInAt), startDate, endDate) {
+			continue
+		}
+		sessions = append(sessions, models.Attendance{
+			ID: a.ID, UserID: a.UserID, User: &models.User{ID: u.ID, Username: u.Username},
+			CheckInAt: a.CheckInAt, CheckOutAt: clonePtr(a.CheckOutAt),
+		})
+	}
+	slices.SortStableFunc(sessions, func(a, b models.Attendance) int {
+		if a.UserID != b.UserID {
+			return a.UserID - b.UserID
+		}
+		return a.CheckInAt.Compare(b.CheckInAt)
+	})
+	return sessions, nil
+}
+
+// snapshotCopy menyalin snapshot; baris disalin lewat JSON seperti kolom rows (JSONB).
+func snapshotCopy(s *models.ReportSnapshot, withRows bool) (*models.ReportSnapshot, error) {
+	out := *s
+	out.Label, out.PayrollPeriodID, out.CreatedBy = clonePtr(s.Label), clonePtr(s.PayrollPeriodID), clonePtr(s.CreatedBy)
+	out.ChecksumValid, out.Totals, out.Rows = nil, nil, nil
+	if withRows {
+		raw, err := json.Marshal(s.Rows)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding report snapshot rows: %w", err)
+		}
+		if err := json.Unmarshal(raw, &out.Rows); err != nil {
+			return nil, fmt.Errorf("error decoding rows of report snapshot %d: %w", s.ID, err)
+		}
+	}
+	return &out, nil
+}
+
+func (r *reportExportRepo) CreateReportSnapshot(ctx context.Context, snapshot *models.ReportSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if snapshot.PayrollPeriodID != nil && r.payrollPeriods[*snapshot.PayrollPeriodID] == nil {
+		return pgx.ErrNoRows
+	}
+	stored, err := snapshotCopy(snapshot, true)
+	if err != nil {
+		return err
+	}
+	stored.ID, stored.RowCount, stored.CreatedAt = r.nextID("report_snapshots"), len(snapshot.Rows), time.Now()
+	r.reportSnapshots[stored.ID] = stored
+	snapshot.ID, snapshot.RowCount, snapshot.CreatedAt = stored.ID, stored.RowCount, stored.CreatedAt
+	return nil
+}
+
+func (r *reportExportRepo) GetReportSnapshotByID(ctx context.Context, id int) (*models.ReportSnapshot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.reportSnapshots[id]
+	if s == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return snapshotCopy(s, true)
+}
+
+func (r *reportExportRepo) GetReportSnapshots(ctx context.Context, page, limit int) ([]models.ReportSnapshot, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := []models.ReportSnapshot{}
+	for _, s := range sortedByKey(r.reportSnapshots) {
+		out, _ := snapshotCopy(s, false)
+		all = append(all, *out)
+	}
+	newestFirst(all, func(s models.ReportSnapshot) time.Time { return s.CreatedAt })
+	return paginate(all, page, limit), len(all), nil
+}
+
+// --- Rekaman debug ---
+
+type debugCaptureRepo struct{ *store }
+
+func debugCaptureCopy(d *models.DebugCapture, withBody bool) models.DebugCapture {
+	out := *d
+	out.RequestID, out.UserID, out.Query, out.Error, out.IP = clonePtr(d.RequestID), clonePtr(d.UserID), clonePtr(d.Query), clonePtr(d.Error), clonePtr(d.IP)
+	out.RequestHeaders, out.RequestBody, out.ResponseHeaders, out.ResponseBody = nil, nil, nil, nil
+	if withBody {
+		out.RequestHeaders, out.ResponseHeaders = maps.Clone(d.RequestHeaders), maps.Clone(d.ResponseHeaders)
+		out.RequestBody, out.ResponseBody = clonePtr(d.RequestBody), clonePtr(d.ResponseBody)
+	}
+	return out
+}
+
+func (r *debugCaptureRepo) CreateDebugCapture(ctx context.Context, capture *models.DebugCapture) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	capture.ID, capture.CreatedAt = int64(r.nextID("debug_captures")), time.Now()
+	stored := debugCaptureCopy(capture, true)
+	if stored.RequestHeaders == nil {
+		stored.RequestHeaders = map[string]string{}
+	}
+	if stored.ResponseHeaders == nil {
+		stored.ResponseHeaders = map[string]string{}
+	}
+	r.debugCaptures[stored.ID] = &stored
+	return nil
+}
+
+func (r *debugCaptureRepo) GetDebugCaptureByID(ctx context.Context, id int64) (*models.DebugCapture, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.debugCaptures[id]
+	if d == nil || !d.ExpiresAt.After(time.Now()) {
+		return nil, pgx.ErrNoRows
+	}
+	out := debugCaptureCopy(d, true)
+	return &out, nil
+}
+
+func (r *debugCaptureRepo) GetDebugCaptures(ctx context.Context, filter models.DebugCaptureFilter, page, limit int) ([]models.DebugCapture, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now, all := time.Now(), []models.DebugCapture{}
+	for _, d := range sortedByKey(r.debugCaptures) {
+		switch {
+		case !d.ExpiresAt.After(now),
+			filter.UserID != nil && (d.UserID == nil || *d.UserID != *filter.UserID),
+			filter.Route != "" && d.Route != filter.Route,
+			filter.Method != "" && d.Method != filter.Method:
+			continue
+		}
+		all = append(all, debugCaptureCopy(d, false))
+	}
+	newestFirst(all, func(d models.DebugCapture) time.Time { return d.CreatedAt })
+	return paginate(all, page, limit), len(all), nil
+}
+
+func (r *debugCaptureRepo) DeleteExpiredDebugCaptures(ctx context.Context, before time.Time, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var expired []*models.DebugCapture
+	for _, d := range sortedByKey(r.debugCaptures) {
+		if d.ExpiresAt.Before(before) {
+			expired = append(expired, d)
+		}
+	}
+	slices.SortStableFunc(expired, func(a, b *models.DebugCapture) int { return a.ExpiresAt.Compare(b.ExpiresAt) })
+	expired = expired[:min(limit, len(expired))]
+	for _, d := range expired {
+		delete(r.debugCaptures, d.ID)
+	}
+	return len(expired), nil
+}
+
+// --- Backup ---
+
+// backupRepo hanya menyimpan status backup; isi backup butuh PostgreSQL sehingga di
+// SANDBOX_MODE permintaan backup ditolak handler sebelum sampai ke sini.
+type backupRepo struct{ *store }
+
+func backupCopy(b *models.Backup) models.Backup {
+	out := *b
+	out.RequestedBy, out.SchemaVersion, out.StorageKey, out.FileName = clonePtr(b.RequestedBy), clonePtr(b.SchemaVersion), clonePtr(b.StorageKey), clonePtr(b.FileName)
+	out.SizeBytes, out.TableCount, out.RowCount, out.Error = clonePtr(b.SizeBytes), clonePtr(b.TableCount), clonePtr(b.RowCount), clonePtr(b.Error)
+	out.StartedAt, out.CompletedAt = clonePtr(b.StartedAt), clonePtr(b.CompletedAt)
+	return out
+}
+
+func (r *backupRepo) CreateBackup(ctx context.Context, backup *models.Backup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.backups {
+		if b.Status == models.BackupPending || b.Status == models.BackupRunning {
+			return repository.ErrBackupInProgress
+		}
+	}
+	b := &models.Backup{ID: r.nextID("backups"), Status: models.BackupPending, RequestedBy: clonePtr(backup.RequestedBy), CreatedAt: time.Now()}
+	r.backups[b.ID] = b
+	*backup = backupCopy(b)
+	return nil
+}
+
+func (r *backupRepo) GetBackupByID(ctx context.Context, id int) (*models.Backup, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := r.backups[id]
+	if b == nil {
+		return nil, pgx.ErrNoRows
+	}
+	out := backupCopy(b)
+	return &out, nil
+}
+
+func (r *backupRepo) GetBackups(ctx context.Context, page, limit int) ([]models.Backup, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := []models.Backup{}
+	for _, b := range sortedByKey(r.backups) {
+		all = append(all, backupCopy(b))
+	}
+	newestFirst(all, func(b models.Backup) time.Time { return b.CreatedAt })
+	return paginate(all, page, limit), len(all), nil
+}
+
+func (r *backupRepo) ClaimPendingBackup(ctx context.Context) (*models.Backup, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range sortedByKey(r.backups) {
+		if b.Status == models.BackupPending {
+			b.Status, b.StartedAt = models.BackupRunning, ptr(time.Now())
+			out := backupCopy(b)
+			return &out, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (r *backupRepo) MarkBackupCompleted(ctx context.Context, id int, result models.BackupResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := r.backups[id]
+	if b == nil || b.Status != models.BackupRunning {
+		return pgx.ErrNoRows
+	}
+	b.Status, b.StorageKey, b.FileName, b.SizeBytes = models.BackupCompleted, ptr(result.StorageKey), ptr(result.FileName), ptr(result.SizeBytes)
+	b.SchemaVersion, b.TableCount, b.RowCount = ptr(result.SchemaVersion), ptr(result.TableCount), ptr(result.RowCount)
+	b.Error, b.CompletedAt = nil, ptr(time.Now())
+	return nil
+}
+
+func (r *backupRepo) MarkBackupFailed(ctx context.Context, id int, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := r.backups[id]
+	if b == nil || (b.Status != models.BackupPending && b.Status != models.BackupRunning) {
+		return pgx.ErrNoRows
+	}
+	b.Status, b.Error, b.CompletedAt = models.BackupFailed, ptr(reason), ptr(time.Now())
+	return nil
+}
+
+func (r *backupRepo) FailStaleBackups(ctx context.Context, startedBefore time.Time, reason string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	failed := 0
+	for _, b := range r.backups {
+		if b.Status == models.BackupRunning && b.StartedAt != nil && b.StartedAt.Before(startedBefore) {
+			b.Status, b.Error, b.CompletedAt = models.BackupFailed, ptr(reason), ptr(time.Now())
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+type appInstanceRepo struct{ *store }
+
+func (r *appInstanceRepo) UpsertAppInstance(ctx context.Context, instance *models.AppInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	i := r.appInstances[instance.InstanceID]
+	if i == nil {
+		i = &models.AppInstance{InstanceID: instance.InstanceID, StartedAt: now}
+		r.appInstances[i.InstanceID] = i
+	}
+	i.AppVersion, i.Hostname, i.LastSeenAt = instance.AppVersion, instance.Hostname, now
+	*instance = *i
+	return nil
+}
+
+func (r *appInstanceRepo) DeleteAppInstance(ctx context.Context, instanceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.appInstances, instanceID)
+	return nil
+}
+
+func (r *appInstanceRepo) GetAppInstances(ctx context.Context, seenSince time.Time) ([]models.AppInstance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	instances := []models.AppInstance{}
+	for _, i := range r.appInstances {
+		if !i.LastSeenAt.Before(seenSince) {
+			instances = append(instances, *i)
+		}
+	}
+	slices.SortFunc(instances, func(a, b models.AppInstance) int {
+		return cmp.Or(cmp.Compare(a.AppVersion, b.AppVersion), a.StartedAt.Compare(b.StartedAt), cmp.Compare(a.InstanceID, b.InstanceID))
+	})
+	return instances, nil
+}
+
+func (r *appInstanceRepo) DeleteStaleAppInstances(ctx context.Context, seenBefore time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deleted := 0
+	for id, i := range r.appInstances {
+		if i.LastSeenAt.Before(seenBefore) {
+			delete(r.appInstances, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,358 @@
+// internal/repository/memory/roles_shifts.go
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// roleRow adalah baris tabel roles termasuk tarif per jam default.
+type roleRow struct {
+	models.Role
+	hourlyRate *float64
+}
+
+type roleRepo struct{ *store }
+
+func (r *roleRepo) roleNameTaken(name string, exceptID int) bool {
+	for _, role := range r.roles {
+		if role.Name == name && role.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// roleCycle meniru trigger check_role_parent_cycle: parentID tidak boleh roleID sendiri atau
+// turunan roleID.
+func (s *store) roleCycle(roleID int, parentID *int) bool {
+	seen := map[int]bool{}
+	for current := parentID; current != nil && !seen[*current]; {
+		if *current == roleID {
+			return true
+		}
+		seen[*current] = true
+		parent := s.roles[*current]
+		if parent == nil {
+			break
+		}
+		current = parent.ParentID
+	}
+	return false
+}
+
+func (r *roleRepo) CreateRole(ctx context.Context, role *models.Role) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if role.ParentID != nil && r.roles[*role.ParentID] == nil {
+		return 0, repository.ErrParentRoleNotFound
+	}
+	if r.roleNameTaken(role.Name, 0) {
+		return 0, fmt.Errorf("role name '%s' already exists", role.Name)
+	}
+	row := &roleRow{Role: models.Role{ID: r.nextID("roles"), Name: role.Name, ParentID: clonePtr(role.ParentID)}}
+	r.roles[row.ID] = row
+	return row.ID, nil
+}
+
+func (r *roleRepo) GetRoleByID(ctx context.Context, id int) (*models.Role, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.roles[id]
+	if row == nil {
+		return nil, pgx.ErrNoRows
+	}
+	role := row.Role
+	return &role, nil
+}
+
+func (r *roleRepo) GetAllRoles(ctx context.Context) ([]models.Role, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	roles := []models.Role{}
+	for _, row := range sortedByKey(r.roles) {
+		roles = append(roles, row.Role)
+	}
+	slices.SortStableFunc(roles, func(a, b models.Role) int { return strings.Compare(a.Name, b.Name) })
+	return roles, nil
+}
+
+func (r *roleRepo) UpdateRole(ctx context.Context, role *models.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.roleNameTaken(role.Name, role.ID) {
+		return fmt.Errorf("role name '%s' already exists", role.Name)
+	}
+	row := r.roles[role.ID]
+	if row == nil {
+		return pgx.ErrNoRows
+	}
+	if row.Name != role.Name {
+		// Trigger bump_token_versions_role_rename: klaim role di JWT berisi nama role.
+		for _, u := range r.users {
+			if u.RoleID == role.ID {
+				r.updateUser(u, func(u *userRow) { u.TokenVersion++ })
+			}
+		}
+	}
+	row.Name = role.Name
+	return nil
+}
+
+func (r *roleRepo) DeleteRole(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users, children := 0, 0
+	for _, u := range r.users {
+		if u.RoleID == id {
+			users++
+		}
+	}
+	if users > 0 {
+		return fmt.Errorf("cannot delete role: %d user(s) still assigned to this role", users)
+	}
+	for _, role := range r.roles {
+		if role.ParentID != nil && *role.ParentID == id {
+			children++
+		}
+	}
+	if children > 0 {
+		return fmt.Errorf("cannot delete role: %d role(s) still inherit from this role", children)
+	}
+	if r.roles[id] == nil {
+		return pgx.ErrNoRows
+	}
+	delete(r.roles, id)
+	return nil
+}
+
+func (r *roleRepo) SetRoleParent(ctx context.Context, roleID int, parentID *int) (*models.Role, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.roles[roleID]
+	if row == nil {
+		return nil, pgx.ErrNoRows
+	}
+	if parentID != nil && *parentID == roleID {
+		return nil, repository.ErrRoleCycle
+	}
+	if parentID != nil && r.roles[*parentID] == nil {
+		return nil, repository.ErrParentRoleNotFound
+	}
+	if r.roleCycle(roleID, parentID) {
+		return nil, repository.ErrRoleCycle
+	}
+	row.ParentID = clonePtr(parentID)
+	role := row.Role
+	return &role, nil
+}
+
+func (r *roleRepo) GetInheritedRoles(ctx context.Context, roleName string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var current *roleRow
+	for _, role := range r.roles {
+		if role.Name == roleName {
+			current = role
+		}
+	}
+	if current == nil {
+		return nil, pgx.ErrNoRows
+	}
+	inherited := []string{}
+	for depth := 0; current.ParentID != nil && depth < 32; depth++ {
+		if current = r.roles[*current.ParentID]; current == nil {
+			break
+		}
+		inherited = append(inherited, current.Name)
+	}
+	return inherited, nil
+}
+
+// --- Shift ---
+
+type shiftRepo struct{ *store }
+
+func validShiftTimes(shift *models.Shift) bool {
+	_, errStart := time.Parse("15:04:05", shift.StartTime)
+	_, errEnd := time.Parse("15:04:05", shift.EndTime)
+	return errStart == nil && errEnd == nil
+}
+
+func (r *shiftRepo) CreateShift(ctx context.Context, shift *models.Shift) (int, error) {
+	if !validShiftTimes(shift) {
+		return 0, fmt.Errorf("invalid time format, use HH:MM:SS")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	row := &models.Shift{ID: r.nextID("shifts"), Name: shift.Name, StartTime: shift.StartTime, EndTime: shift.EndTime, Version: 1, CreatedAt: now, UpdatedAt: now}
+	r.shifts[row.ID] = row
+	return row.ID, nil
+}
+
+func (r *shiftRepo) GetShiftByID(ctx context.Context, id int) (*models.Shift, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	shift := r.shifts[id]
+	if shift == nil {
+		return nil, fmt.Errorf("error getting shift by id %d: %w", id, pgx.ErrNoRows)
+	}
+	return clonePtr(shift), nil
+}
+
+func (r *shiftRepo) GetAllShifts(ctx context.Context) ([]models.Shift, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	shifts := []models.Shift{}
+	for _, shift := range sortedByKey(r.shifts) {
+		shifts = append(shifts, *shift)
+	}
+	slices.SortStableFunc(shifts, func(a, b models.Shift) int { return strings.Compare(a.Name, b.Name) })
+	return shifts, nil
+}
+
+func (r *shiftRepo) UpdateShift(ctx context.Context, shift *models.Shift) error {
+	if !validShiftTimes(shift) {
+		return fmt.Errorf("invalid time format, use HH:MM:SS")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.shifts[shift.ID]
+	if row == nil {
+		return pgx.ErrNoRows
+	}
+	if shift.Version > 0 && row.Version != shift.Version {
+		return repository.ErrVersionConflict
+	}
+	row.Name, row.StartTime, row.EndTime = shift.Name, shift.StartTime, shift.EndTime
+	row.Version++
+	row.UpdatedAt = time.Now()
+	shift.Version = row.Version
+	return nil
+}
+
+func (r *shiftRepo) DeleteShift(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sc := range r.schedules {
+		if sc.ShiftID == id {
+			return fmt.Errorf("cannot delete shift: it is still referenced by user schedules")
+		}
+	}
+	if r.shifts[id] == nil {
+		return pgx.ErrNoRows
+	}
+	delete(r.shifts, id)
+	deleteWhere(r.shiftOverrides, func(o *models.ShiftOverride) bool { return o.ShiftID != nil && *o.ShiftID == id })
+	return nil
+}
+
+func (r *shiftRepo) CreateShiftOverride(ctx context.Context, o *models.ShiftOverride) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if o.ShiftID != nil && r.shifts[*o.ShiftID] == nil {
+		return 0, pgx.ErrNoRows
+	}
+	o.ID, o.CreatedAt = r.nextID("shift_overrides"), time.Now()
+	r.shiftOverrides[o.ID] = clonePtr(o)
+	return o.ID, nil
+}
+
+func (r *shiftRepo) GetShiftOverrides(ctx context.Context, activeFrom *time.Time) ([]models.ShiftOverride, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	overrides := []models.ShiftOverride{}
+	for _, o := range sortedByKey(r.shiftOverrides) {
+		if activeFrom == nil || o.EndDate >= activeFrom.Format(dateLayout) {
+			overrides = append(overrides, *o)
+		}
+	}
+	slices.Reverse(overrides)
+	slices.SortStableFunc(overrides, func(a, b models.ShiftOverride) int { return strings.Compare(b.StartDate, a.StartDate) })
+	return overrides, nil
+}
+
+func (r *shiftRepo) DeleteShiftOverride(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.shiftOverrides[id] == nil {
+		return pgx.ErrNoRows
+	}
+	delete(r.shiftOverrides, id)
+	return nil
+}
+
+// --- Shift efektif (fungsi SQL schedule_shift) ---
+
+// effectiveShift mengembalikan ringkasan shift (id, nama, jam, override) yang berlaku pada date:
+// override khusus shift didahulukan dari override global, lalu yang terbaru. Jam override
+// mutlak menggantikan jam shift; jika tidak, jam shift digeser offset menit (modulo 24 jam).
+func (s *store) effectiveShift(shiftID int, date string) *models.Shift {
+	shift := s.shifts[shiftID]
+	if shift == nil {
+		return nil
+	}
+	var best *models.ShiftOverride
+	for _, o := range s.shiftOverrides {
+		if (o.ShiftID != nil && *o.ShiftID != shiftID) || date < o.StartDate || date > o.EndDate {
+			continue
+		}
+		switch {
+		case best == nil:
+			best = o
+		case (o.ShiftID != nil) != (best.ShiftID != nil):
+			if o.ShiftID != nil {
+				best = o
+			}
+		case o.ID > best.ID:
+			best = o
+		}
+	}
+	out := &models.Shift{ID: shift.ID, Name: shift.Name, StartTime: shift.StartTime, EndTime: shift.EndTime}
+	if best != nil {
+		out.StartTime = overrideTime(best.StartTime, shift.StartTime, best.StartOffsetMinutes)
+		out.EndTime = overrideTime(best.EndTime, shift.EndTime, best.EndOffsetMinutes)
+		out.OverrideID = ptr(best.ID)
+	}
+	return out
+}
+
+func overrideTime(absolute *string, base string, offsetMinutes int) string {
+	if absolute != nil {
+		return *absolute
+	}
+	seconds := (clockSeconds(base) + offsetMinutes*60) % 86400
+	if seconds < 0 {
+		seconds += 86400
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// clockSeconds mengubah jam HH:MM:SS menjadi detik sejak tengah malam.
+func clockSeconds(clock string) int {
+	t, err := time.Parse("15:04:05", clock)
+	if err != nil {
+		return 0
+	}
+	return t.Hour()*3600 + t.Minute()*60 + t.Second()
+}
+
+// shiftWindow mengembalikan rentang [mulai, selesai) shift pada date dalam waktu lokal;
+// jam selesai <= jam mulai berarti selesai keesokan harinya.
+func shiftWindow(date string, shift *models.Shift) (time.Time, time.Time) {
+	day, _ := time.ParseInLocation(dateLayout, date, time.Local)
+	start := day.Add(time.Duration(clockSeconds(shift.StartTime)) * time.Second)
+	end := day.Add(time.Duration(clockSeconds(shift.EndTime)) * time.Second)
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end
+}
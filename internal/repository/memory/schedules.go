@@ -0,0 +1,448 @@
+// internal/repository/memory/schedules.go
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+type scheduleRepo struct{ *store }
+
+// scheduleCopy menyalin jadwal; withShift mengisi shift efektif seperti schedule_shift().
+func (s *store) scheduleCopy(sc *models.UserSchedule, withShift bool) models.UserSchedule {
+	out := *sc
+	out.User, out.Shift, out.Attendance, out.AttendanceStatus = nil, nil, nil, ""
+	if withShift {
+		out.Shift = s.effectiveShift(sc.ShiftID, sc.Date)
+	}
+	return out
+}
+
+// scheduleAttendance mengembalikan absensi jadwal: yang ditautkan ke jadwal lebih dulu, lalu
+// check-in pertama user pada tanggal jadwal tanpa tautan jadwal.
+func (s *store) scheduleAttendance(sc *models.UserSchedule) *models.Attendance {
+	var best *models.Attendance
+	for _, a := range s.attendances {
+		linked := a.ScheduleID != nil && *a.ScheduleID == sc.ID
+		sameDay := a.ScheduleID == nil && a.UserID == sc.UserID && dateOf(a.CheckInAt) == sc.Date
+		if !linked && !sameDay {
+			continue
+		}
+		bestLinked := best != nil && best.ScheduleID != nil
+		if best == nil || (linked && !bestLinked) || (linked == bestLinked && a.CheckInAt.Before(best.CheckInAt)) {
+			best = a
+		}
+	}
+	return best
+}
+
+// checkScheduleOverlap meniru repository.checkScheduleOverlap: jadwal lain user (tanggal ±1)
+// yang jam shift efektifnya beririsan dengan kandidat.
+func (s *store) checkScheduleOverlap(userID, shiftID int, date string, excludeID int) error {
+	candidate := s.effectiveShift(shiftID, date)
+	if candidate == nil {
+		return nil
+	}
+	candStart, candEnd := shiftWindow(date, candidate)
+	day, _ := time.Parse(dateLayout, date)
+	from, to := day.AddDate(0, 0, -1), day.AddDate(0, 0, 1)
+
+	type overlap struct {
+		startsAt time.Time
+		conflict models.ScheduleConflict
+	}
+	var overlaps []overlap
+	for _, sc := range sortedByKey(s.schedules) {
+		if sc.UserID != userID || sc.ID == excludeID || !inDateRange(sc.Date, from, to) {
+			continue
+		}
+		shift := s.effectiveShift(sc.ShiftID, sc.Date)
+		if shift == nil {
+			continue
+		}
+		start, end := shiftWindow(sc.Date, shift)
+		if start.Before(candEnd) && end.After(candStart) {
+			overlaps = append(overlaps, overlap{start, models.ScheduleConflict{
+				ScheduleID: sc.ID, Date: sc.Date, ShiftID: shift.ID, ShiftName: shift.Name,
+				StartsAt: start.Format("2006-01-02T15:04:05"), EndsAt: end.Format("2006-01-02T15:04:05"),
+			}})
+		}
+	}
+	if len(overlaps) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(overlaps, func(a, b overlap) int { return a.startsAt.Compare(b.startsAt) })
+	conflicts := make([]models.ScheduleConflict, 0, len(overlaps))
+	for _, o := range overlaps {
+		conflicts = append(conflicts, o.conflict)
+	}
+	return &repository.ScheduleConflictError{Conflicts: conflicts}
+}
+
+// scheduleTaken mengecek unique (user_id, date) selain exceptID.
+func (s *store) scheduleTaken(userID int, date string, exceptID int) bool {
+	for _, sc := range s.schedules {
+		if sc.ID != exceptID && sc.UserID == userID && sc.Date == date {
+			return true
+		}
+	}
+	return false
+}
+
+// auditSchedule meniru trigger audit_user_schedule_change; entri dicatat atas nama user
+// pemilik jadwal selama user tersebut masih ada.
+func (s *store) auditSchedule(userID int, action string, details map[string]any) {
+	if s.users[userID] != nil {
+		s.addAudit(userID, nil, action, details)
+	}
+}
+
+// saveSchedule menerapkan perubahan user/shift/tanggal ke jadwal beserta efek trigger-nya.
+func (s *store) saveSchedule(sc *models.UserSchedule, userID, shiftID int, date string) {
+	old := *sc
+	sc.UserID, sc.ShiftID, sc.Date = userID, shiftID, date
+	sc.Version++
+	sc.UpdatedAt = time.Now()
+	if old.UserID != userID {
+		s.auditSchedule(old.UserID, models.AuditScheduleDeleted, map[string]any{"schedule_id": sc.ID, "shift_id": old.ShiftID, "date": old.Date})
+		s.auditSchedule(userID, models.AuditScheduleCreated, map[string]any{"schedule_id": sc.ID, "shift_id": shiftID, "date": date})
+	} else if old.ShiftID != shiftID || old.Date != date {
+		s.auditSchedule(userID, models.AuditScheduleUpdated, map[string]any{
+			"schedule_id": sc.ID, "shift_id": shiftID, "date": date, "previous_shift_id": old.ShiftID, "previous_date": old.Date,
+		})
+	}
+}
+
+// deleteSchedule menghapus jadwal beserta audit dan tombstone sync-nya.
+func (s *store) deleteSchedule(sc *models.UserSchedule) {
+	delete(s.schedules, sc.ID)
+	delete(s.remindersSent, sc.ID)
+	for _, a := range s.attendances {
+		if a.ScheduleID != nil && *a.ScheduleID == sc.ID {
+			a.ScheduleID = nil
+		}
+	}
+	s.auditSchedule(sc.UserID, models.AuditScheduleDeleted, map[string]any{"schedule_id": sc.ID, "shift_id": sc.ShiftID, "date": sc.Date})
+	s.recordTombstone(models.SyncEntitySchedules, sc.ID)
+}
+
+func (r *scheduleRepo) CreateSchedule(ctx context.Context, schedule *models.UserSchedule) (int, error) {
+	if _, err := time.Parse(dateLayout, schedule.Date); err != nil {
+		return 0, fmt.Errorf("invalid date format for schedule, use YYYY-MM-DD: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkScheduleOverlap(schedule.UserID, schedule.ShiftID, schedule.Date, 0); err != nil {
+		return 0, err
+	}
+	if r.scheduleTaken(schedule.UserID, schedule.Date, 0) {
+		return 0, fmt.Errorf("user %d already has a schedule on %s", schedule.UserID, schedule.Date)
+	}
+	if r.users[schedule.UserID] == nil || r.shifts[schedule.ShiftID] == nil {
+		return 0, fmt.Errorf("invalid user_id (%d) or shift_id (%d)", schedule.UserID, schedule.ShiftID)
+	}
+	now := time.Now()
+	sc := &models.UserSchedule{
+		ID: r.nextID("user_schedules"), UserID: schedule.UserID, ShiftID: schedule.ShiftID, Date: schedule.Date,
+		Version: 1, CreatedAt: now, UpdatedAt: now,
+	}
+	r.schedules[sc.ID] = sc
+	r.auditSchedule(sc.UserID, models.AuditScheduleCreated, map[string]any{"schedule_id": sc.ID, "shift_id": sc.ShiftID, "date": sc.Date})
+	return sc.ID, nil
+}
+
+func (r *scheduleRepo) GetScheduleByUserAndDate(ctx context.Context, userID int, date time.Time) (*models.UserSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sc := range r.schedules {
+		if sc.UserID == userID && sc.Date == date.Format(dateLayout) {
+			out := r.scheduleCopy(sc, true)
+			return &out, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *scheduleRepo) GetScheduleByID(ctx context.Context, id int) (*models.UserSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sc := r.schedules[id]
+	if sc == nil {
+		return nil, pgx.ErrNoRows
+	}
+	out := r.scheduleCopy(sc, true)
+	return &out, nil
+}
+
+// userSchedules mengembalikan jadwal user dalam rentang tanggal, urut tanggal.
+func (s *store) userSchedules(userID int, startDate, endDate time.Time) []*models.UserSchedule {
+	var out []*models.UserSchedule
+	for _, sc := range s.schedules {
+		if sc.UserID == userID && inDateRange(sc.Date, startDate, endDate) {
+			out = append(out, sc)
+		}
+	}
+	slices.SortFunc(out, func(a, b *models.UserSchedule) int { return strings.Compare(a.Date, b.Date) })
+	return out
+}
+
+func (r *scheduleRepo) GetSchedulesByUser(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) ([]models.UserSchedule, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := r.userSchedules(userID, startDate, endDate)
+	schedules := []models.UserSchedule{}
+	for _, sc := range paginate(all, page, limit) {
+		schedules = append(schedules, r.scheduleCopy(sc, true))
+	}
+	return schedules, len(all), nil
+}
+
+func (r *scheduleRepo) GetSchedulesByUserWithAttendance(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) ([]models.UserSchedule, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := r.userSchedules(userID, startDate, endDate)
+	schedules := []models.UserSchedule{}
+	for _, sc := range paginate(all, page, limit) {
+		out := r.scheduleCopy(sc, true)
+		out.AttendanceStatus = models.AttendanceStatusMissing
+		if a := r.scheduleAttendance(sc); a != nil {
+			out.Attendance = r.attendanceCopy(a, false)
+			out.AttendanceStatus = models.AttendanceStatusPresent
+		}
+		schedules = append(schedules, out)
+	}
+	return schedules, len(all), nil
+}
+
+func (r *scheduleRepo) GetSchedulesByDateRangeForAllUsers(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]models.UserSchedule, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var all []*models.UserSchedule
+	for _, sc := range sortedByKey(r.schedules) {
+		if inDateRange(sc.Date, startDate, endDate) {
+			all = append(all, sc)
+		}
+	}
+	slices.SortStableFunc(all, func(a, b *models.UserSchedule) int {
+		if c := strings.Compare(a.Date, b.Date); c != 0 {
+			return c
+		}
+		return strings.Compare(r.users[a.UserID].Username, r.users[b.UserID].Username)
+	})
+	schedules := []models.UserSchedule{}
+	for _, sc := range paginate(all, page, limit) {
+		out := r.scheduleCopy(sc, true)
+		out.User = r.userSummary(r.users[sc.UserID])
+		schedules = append(schedules, out)
+	}
+	return schedules, len(all), nil
+}
+
+func (r *scheduleRepo) DeleteSchedule(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sc := r.schedules[id]
+	if sc == nil {
+		return pgx.ErrNoRows
+	}
+	r.deleteSchedule(sc)
+	return nil
+}
+
+func (r *scheduleRepo) UpdateSchedule(ctx context.Context, schedule *models.UserSchedule) error {
+	if _, err := time.Parse(dateLayout, schedule.Date); err != nil {
+		return fmt.Errorf("invalid date format for schedule update, use YYYY-MM-DD: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkScheduleOverlap(schedule.UserID, schedule.ShiftID, schedule.Date, schedule.ID); err != nil {
+		return err
+	}
+	sc := r.schedules[schedule.ID]
+	if sc == nil {
+		return pgx.ErrNoRows
+	}
+	if schedule.Version > 0 && sc.Version != schedule.Version {
+		return repository.ErrVersionConflict
+	}
+	if r.scheduleTaken(schedule.UserID, schedule.Date, sc.ID) {
+		return fmt.Errorf("user %d already has a schedule on %s", schedule.UserID, schedule.Date)
+	}
+	if r.users[schedule.UserID] == nil || r.shifts[schedule.ShiftID] == nil {
+		return fmt.Errorf("invalid user_id (%d) or shift_id (%d)", schedule.UserID, schedule.ShiftID)
+	}
+	r.saveSchedule(sc, schedule.UserID, schedule.ShiftID, schedule.Date)
+	schedule.Version = sc.Version
+	return nil
+}
+
+func (r *scheduleRepo) PatchSchedule(ctx context.Context, id int, input *models.PatchScheduleInput) (int, error) {
+	if input.Date != nil {
+		if _, err := time.Parse(dateLayout, *input.Date); err != nil {
+			return 0, fmt.Errorf("invalid date format for schedule update, use YYYY-MM-DD: %w", err)
+		}
+	}
+	if input.UserID == nil && input.ShiftID == nil && input.Date == nil {
+		return 0, repository.ErrNoFieldsToUpdate
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sc := r.schedules[id]
+	if sc == nil {
+		return 0, pgx.ErrNoRows
+	}
+	userID, shiftID, date := sc.UserID, sc.ShiftID, sc.Date
+	if input.UserID != nil {
+		userID = *input.UserID
+	}
+	if input.ShiftID != nil {
+		shiftID = *input.ShiftID
+	}
+	if input.Date != nil {
+		date = *input.Date
+	}
+	if err := r.checkScheduleOverlap(userID, shiftID, date, id); err != nil {
+		return 0, err
+	}
+	if input.Version > 0 && sc.Version != input.Version {
+		return 0, repository.ErrVersionConflict
+	}
+	if r.scheduleTaken(userID, date, id) {
+		return 0, fmt.Errorf("user already has a schedule on that date")
+	}
+	if r.users[userID] == nil || r.shifts[shiftID] == nil {
+		return 0, fmt.Errorf("invalid user_id or shift_id")
+	}
+	r.saveSchedule(sc, userID, shiftID, date)
+	return sc.Version, nil
+}
+
+func (r *scheduleRepo) BulkDeleteSchedules(ctx context.Context, ids []int, userIDs []int, startDate, endDate *time.Time) (*models.BulkDeleteSchedulesResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var targets []*models.UserSchedule
+	for _, sc := range sortedByKey(r.schedules) {
+		var match bool
+		if len(ids) > 0 {
+			match = slices.Contains(ids, sc.ID)
+		} else if startDate != nil && endDate != nil {
+			match = inDateRange(sc.Date, *startDate, *endDate) && (len(userIDs) == 0 || slices.Contains(userIDs, sc.UserID))
+		}
+		if match {
+			targets = append(targets, sc)
+		}
+	}
+
+	result := &models.BulkDeleteSchedulesResult{DeletedIDs: []int{}, Kept: []models.BulkItemResult{}}
+	for _, sc := range targets {
+		hasAttendance := slices.ContainsFunc(sortedByKey(r.attendances), func(a *models.Attendance) bool {
+			return a.UserID == sc.UserID && dateOf(a.CheckInAt) == sc.Date
+		})
+		if hasAttendance {
+			result.Kept = append(result.Kept, models.BulkItemResult{
+				ID: sc.ID, Status: models.BulkStatusSkipped, Message: "attendance already recorded for this schedule",
+			})
+			continue
+		}
+		r.deleteSchedule(sc)
+		result.DeletedIDs = append(result.DeletedIDs, sc.ID)
+	}
+	for _, id := range ids {
+		found := slices.ContainsFunc(targets, func(sc *models.UserSchedule) bool { return sc.ID == id })
+		if !found && !slices.Contains(result.NotFoundIDs, id) {
+			result.NotFoundIDs = append(result.NotFoundIDs, id)
+		}
+	}
+	result.DeletedCount, result.KeptCount = len(result.DeletedIDs), len(result.Kept)
+	return result, nil
+}
+
+func (r *scheduleRepo) ExportSchedulesByUser(ctx context.Context, userID int) ([]models.UserSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schedules := []models.UserSchedule{}
+	for _, sc := range r.userSchedules(userID, time.Time{}, time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		schedules = append(schedules, r.scheduleCopy(sc, true))
+	}
+	return schedules, nil
+}
+
+func (r *scheduleRepo) GetShiftHeadcounts(ctx context.Context, startDate, endDate time.Time) ([]models.ShiftHeadcount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	type key struct {
+		date    string
+		shiftID int
+	}
+	byKey := map[key]*models.ShiftHeadcount{}
+	startTimes := map[key]string{}
+	for _, sc := range r.schedules {
+		shift := r.effectiveShift(sc.ShiftID, sc.Date)
+		if shift == nil || !inDateRange(sc.Date, startDate, endDate) {
+			continue
+		}
+		k := key{sc.Date, shift.ID}
+		hc := byKey[k]
+		if hc == nil {
+			hc = &models.ShiftHeadcount{Date: sc.Date, ShiftID: shift.ID, ShiftName: shift.Name}
+			byKey[k], startTimes[k] = hc, shift.StartTime
+		}
+		hc.Scheduled++
+		if r.scheduleAttendance(sc) != nil {
+			hc.Attended++
+		}
+	}
+	keys := make([]key, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b key) int {
+		if c := strings.Compare(a.date, b.date); c != 0 {
+			return c
+		}
+		if c := strings.Compare(startTimes[a], startTimes[b]); c != 0 {
+			return c
+		}
+		return a.shiftID - b.shiftID
+	})
+	counts := []models.ShiftHeadcount{}
+	for _, k := range keys {
+		counts = append(counts, *byKey[k])
+	}
+	return counts, nil
+}
+
+func (r *scheduleRepo) GetScheduleChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var updated []change
+	for _, sc := range r.schedules {
+		updated = append(updated, change{id: sc.ID, changedAt: sc.UpdatedAt})
+	}
+	ids, batch := r.syncChanges(models.SyncEntitySchedules, updated, cursor, limit)
+	schedules := []models.UserSchedule{}
+	for _, id := range ids {
+		schedules = append(schedules, r.scheduleCopy(r.schedules[id], false))
+	}
+	batch.Items = schedules
+	return batch, nil
+}
+
+func (r *scheduleRepo) GetSchedulesByIDs(ctx context.Context, ids []int) ([]models.UserSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schedules := []models.UserSchedule{}
+	for _, sc := range sortedByKey(r.schedules) {
+		if slices.Contains(ids, sc.ID) {
+			schedules = append(schedules, r.scheduleCopy(sc, true))
+		}
+	}
+	return schedules, nil
+}
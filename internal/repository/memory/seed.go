@@ -0,0 +1,87 @@
+// internal/repository/memory/seed.go
+package memory
+
+import (
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// SeedPassword adalah password semua user contoh (admin, manager, budi, sari).
+const SeedPassword = "sandbox123"
+
+// seed mengisi store dengan data contoh yang sama setiap start relatif terhadap minggu now:
+// role Admin & Employee (ID 1 & 2 seperti migrasi awal), tiga shift, satu admin, satu manager
+// dengan dua bawahan, jadwal Senin–Jumat minggu berjalan, absensi hari kerja yang sudah lewat
+// (sari terlambat setiap hari), dan satu project.
+func (s *store) seed(now time.Time) {
+	created := now.Add(-30 * 24 * time.Hour)
+	for _, name := range []string{"Admin", "Employee"} {
+		id := s.nextID("roles")
+		s.roles[id] = &roleRow{Role: models.Role{ID: id, Name: name}}
+	}
+
+	shiftIDs := map[string]int{}
+	for _, shift := range []struct{ name, start, end string }{
+		{"Pagi", "08:00:00", "16:00:00"},
+		{"Siang", "14:00:00", "22:00:00"},
+		{"Malam", "22:00:00", "06:00:00"},
+	} {
+		id := s.nextID("shifts")
+		s.shifts[id] = &models.Shift{ID: id, Name: shift.name, StartTime: shift.start, EndTime: shift.end, Version: 1, CreatedAt: created, UpdatedAt: created}
+		shiftIDs[shift.name] = id
+	}
+
+	// Hash dibuat sekali: argon2 sengaja lambat.
+	hashed, err := utils.HashPassword(SeedPassword)
+	if err != nil {
+		panic("memory: hashing seed password: " + err.Error())
+	}
+	addUser := func(username, first, last string, roleID int, managerID *int) int {
+		id := s.nextID("users")
+		s.users[id] = &userRow{User: models.User{
+			ID: id, Username: username, Password: hashed, Email: username + "@sandbox.local", FirstName: first, LastName: last,
+			RoleID: roleID, UserType: models.UserTypeEmployee, IsActive: true, EmploymentStatus: models.EmploymentStatusPermanent,
+			HireDate: ptr(created.Format(dateLayout)), ManagerID: managerID, Version: 1, CreatedAt: created, UpdatedAt: created,
+		}}
+		return id
+	}
+	addUser("admin", "Admin", "Sandbox", 1, nil)
+	manager := addUser("manager", "Maya", "Putri", 2, nil)
+	budi := addUser("budi", "Budi", "Santoso", 2, ptr(manager))
+	sari := addUser("sari", "Sari", "Wulandari", 2, ptr(manager))
+
+	projectID := s.nextID("projects")
+	s.projects[projectID] = &models.Project{ID: projectID, Code: "HQ", Name: "Head Office", IsActive: true, CreatedAt: created, UpdatedAt: created}
+
+	today := midnight(now)
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	roster := []struct {
+		userID            int
+		shift             string
+		checkIn, checkOut time.Duration // Sejak tengah malam
+	}{
+		{manager, "Pagi", 7*time.Hour + 50*time.Minute, 16*time.Hour + 10*time.Minute},
+		{budi, "Pagi", 7*time.Hour + 55*time.Minute, 16*time.Hour + 5*time.Minute},
+		{sari, "Siang", 14*time.Hour + 20*time.Minute, 22 * time.Hour},
+	}
+	for day := monday; day.Before(monday.AddDate(0, 0, 5)); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		for _, r := range roster {
+			scheduleID := s.nextID("user_schedules")
+			s.schedules[scheduleID] = &models.UserSchedule{
+				ID: scheduleID, UserID: r.userID, ShiftID: shiftIDs[r.shift], Date: date, Version: 1, CreatedAt: created, UpdatedAt: created,
+			}
+			if !day.Before(today) {
+				continue
+			}
+			checkIn, checkOut := day.Add(r.checkIn), day.Add(r.checkOut)
+			attendanceID := s.nextID("attendances")
+			s.attendances[attendanceID] = &models.Attendance{
+				ID: attendanceID, UserID: r.userID, ScheduleID: ptr(scheduleID), ProjectID: ptr(projectID),
+				CheckInAt: checkIn, CheckOutAt: ptr(checkOut), Mode: models.AttendanceModeOnsite, CreatedAt: checkIn, UpdatedAt: checkOut,
+			}
+		}
+	}
+}
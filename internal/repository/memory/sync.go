@@ -0,0 +1,61 @@
+// internal/repository/memory/sync.go
+package memory
+
+import (
+	"slices"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// change adalah satu baris aliran perubahan sync: record yang berubah atau tombstone.
+type change struct {
+	id        int
+	changedAt time.Time
+	deleted   bool
+}
+
+// recordTombstone meniru trigger record_sync_tombstone (AFTER DELETE).
+func (s *store) recordTombstone(entity string, id int) {
+	if s.tombstones[entity] == nil {
+		s.tombstones[entity] = map[int]time.Time{}
+	}
+	s.tombstones[entity][id] = time.Now()
+}
+
+// syncChanges meniru repository.syncChanges: updated (id & updated_at record yang masih ada)
+// digabung dengan tombstone entity, diurutkan keyset (waktu perubahan, id), hanya yang sudah
+// lewat SyncSettleSeconds. Mengembalikan id record yang berubah dan batch tanpa Items.
+func (s *store) syncChanges(entity string, updated []change, cursor models.SyncCursor, limit int) ([]int, *models.SyncBatch) {
+	settled := time.Now().Add(-time.Duration(models.SyncSettleSeconds) * time.Second)
+	all := slices.Clone(updated)
+	for id, deletedAt := range s.tombstones[entity] {
+		all = append(all, change{id: id, changedAt: deletedAt, deleted: true})
+	}
+	all = slices.DeleteFunc(all, func(c change) bool {
+		after := c.changedAt.After(cursor.UpdatedSince) || (c.changedAt.Equal(cursor.UpdatedSince) && c.id > cursor.AfterID)
+		return !after || c.changedAt.After(settled)
+	})
+	slices.SortFunc(all, func(a, b change) int {
+		if c := a.changedAt.Compare(b.changedAt); c != 0 {
+			return c
+		}
+		return a.id - b.id
+	})
+
+	batch := &models.SyncBatch{Deleted: []models.SyncTombstone{}, NextUpdatedSince: cursor.UpdatedSince, NextAfterID: cursor.AfterID}
+	ids := []int{}
+	for _, c := range all {
+		if len(ids)+len(batch.Deleted) == limit {
+			batch.HasMore = true
+			break
+		}
+		if c.deleted {
+			batch.Deleted = append(batch.Deleted, models.SyncTombstone{ID: c.id, DeletedAt: c.changedAt})
+		} else {
+			ids = append(ids, c.id)
+		}
+		batch.NextUpdatedSince, batch.NextAfterID = c.changedAt, c.id
+	}
+	return ids, batch
+}
@@ -0,0 +1,508 @@
+// internal/repository/memory/user_account.go
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// Alur akun milik userRepo: sesi, ganti email/username, OTP SMS, dan undangan.
+
+// phoneOTPRow menyimpan nomor HP (ternormalisasi) saat kode dikirim, seperti phone_hash.
+type phoneOTPRow struct {
+	models.PhoneOTP
+	phone string
+}
+
+// --- Sesi (user_sessions.go) ---
+
+func (r *userRepo) GetTokenVersion(ctx context.Context, id int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil {
+		return 0, pgx.ErrNoRows
+	}
+	return row.TokenVersion, nil
+}
+
+func (r *userRepo) RevokeSessions(ctx context.Context, id int, expectedVersion int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil || row.TokenVersion != expectedVersion {
+		return 0, pgx.ErrNoRows
+	}
+	r.updateUser(row, func(u *userRow) {
+		u.TokenVersion++
+		u.PasswordResetRequired = true
+	})
+	return row.TokenVersion, nil
+}
+
+func (r *userRepo) ForceRevokeSessions(ctx context.Context, id int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil {
+		return 0, pgx.ErrNoRows
+	}
+	r.updateUser(row, func(u *userRow) { u.TokenVersion++ })
+	return row.TokenVersion, nil
+}
+
+func (r *userRepo) ResetPassword(ctx context.Context, id int, hashedPassword string, expectedVersion int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil || row.TokenVersion != expectedVersion {
+		return pgx.ErrNoRows
+	}
+	r.updateUser(row, func(u *userRow) {
+		u.Password = hashedPassword
+		u.PasswordResetRequired, u.MustChangePassword = false, false
+		u.TokenVersion++
+	})
+	return nil
+}
+
+func (r *userRepo) RequirePasswordChange(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil {
+		return pgx.ErrNoRows
+	}
+	r.updateUser(row, func(u *userRow) { u.MustChangePassword = true })
+	return nil
+}
+
+func (r *userRepo) UpdateWorkModes(ctx context.Context, id int, remoteDays *string, fieldWorkAllowed *bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil {
+		return pgx.ErrNoRows
+	}
+	r.updateUser(row, func(u *userRow) {
+		if remoteDays != nil {
+			u.RemoteDays = *remoteDays
+		}
+		if fieldWorkAllowed != nil {
+			u.FieldWorkAllowed = *fieldWorkAllowed
+		}
+	})
+	return nil
+}
+
+// --- Ganti email (user_email_change.go) ---
+
+func (r *userRepo) CreateEmailChangeRequest(ctx context.Context, userID int, newEmail string, expiresAt time.Time) (*models.EmailChangeRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.emailTaken(newEmail, userID) {
+		return nil, repository.ErrEmailTaken
+	}
+	if r.users[userID] == nil {
+		return nil, pgx.ErrNoRows
+	}
+	deleteWhere(r.emailChanges, func(e *models.EmailChangeRequest) bool { return e.UserID == userID && e.ConfirmedAt == nil })
+	now := time.Now()
+	ec := &models.EmailChangeRequest{
+		ID: r.nextID("email_change_requests"), UserID: userID, NewEmail: newEmail, ExpiresAt: expiresAt, SentAt: now, CreatedAt: now,
+	}
+	r.emailChanges[ec.ID] = ec
+	return clonePtr(ec), nil
+}
+
+func (r *userRepo) GetPendingEmailChange(ctx context.Context, userID int) (*models.EmailChangeRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ec := range r.emailChanges {
+		if ec.UserID == userID && ec.ConfirmedAt == nil {
+			return clonePtr(ec), nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (r *userRepo) GetEmailChangeByID(ctx context.Context, id int) (*models.EmailChangeRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ec := r.emailChanges[id]
+	if ec == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return clonePtr(ec), nil
+}
+
+func (r *userRepo) RenewEmailChangeRequest(ctx context.Context, id int, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ec := r.emailChanges[id]
+	if ec == nil || ec.ConfirmedAt != nil {
+		return pgx.ErrNoRows
+	}
+	ec.SentAt, ec.ExpiresAt = time.Now(), expiresAt
+	return nil
+}
+
+func (r *userRepo) CancelEmailChange(ctx context.Context, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found := false
+	deleteWhere(r.emailChanges, func(e *models.EmailChangeRequest) bool {
+		open := e.UserID == userID && e.ConfirmedAt == nil
+		found = found || open
+		return open
+	})
+	if !found {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *userRepo) ConfirmEmailChange(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ec := r.emailChanges[id]
+	if ec == nil || ec.ConfirmedAt != nil || !ec.ExpiresAt.After(time.Now()) {
+		return pgx.ErrNoRows
+	}
+	if r.emailTaken(ec.NewEmail, ec.UserID) {
+		return repository.ErrEmailTaken
+	}
+	ec.ConfirmedAt = ptr(time.Now())
+	if row := r.users[ec.UserID]; row != nil {
+		r.updateUser(row, func(u *userRow) { u.Email = ec.NewEmail })
+	}
+	return nil
+}
+
+// --- Ganti username (user_username.go) ---
+
+// usernameChangeCopy menyalin permintaan beserta username user saat ini.
+func (s *store) usernameChangeCopy(uc *models.UsernameChangeRequest) *models.UsernameChangeRequest {
+	c := *uc
+	if u := s.users[uc.UserID]; u != nil {
+		c.Username = u.Username
+	}
+	return &c
+}
+
+func (r *userRepo) CreateUsernameChangeRequest(ctx context.Context, userID int, newUsername string) (*models.UsernameChangeRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Username == newUsername && u.ID != userID {
+			return nil, repository.ErrUsernameTaken
+		}
+	}
+	if r.users[userID] == nil {
+		return nil, pgx.ErrNoRows
+	}
+	deleteWhere(r.usernameChanges, func(uc *models.UsernameChangeRequest) bool {
+		return uc.UserID == userID && uc.Status == models.UsernameChangePending
+	})
+	uc := &models.UsernameChangeRequest{
+		ID: r.nextID("username_change_requests"), UserID: userID, NewUsername: newUsername,
+		Status: models.UsernameChangePending, CreatedAt: time.Now(),
+	}
+	r.usernameChanges[uc.ID] = uc
+	return r.usernameChangeCopy(uc), nil
+}
+
+func (r *userRepo) GetPendingUsernameChange(ctx context.Context, userID int) (*models.UsernameChangeRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, uc := range r.usernameChanges {
+		if uc.UserID == userID && uc.Status == models.UsernameChangePending {
+			return r.usernameChangeCopy(uc), nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (r *userRepo) GetUsernameChangeRequests(ctx context.Context, status string, page, limit int) ([]models.UsernameChangeRequest, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	requests := []models.UsernameChangeRequest{}
+	for _, uc := range sortedByKey(r.usernameChanges) {
+		if status == "" || uc.Status == status {
+			requests = append(requests, *r.usernameChangeCopy(uc))
+		}
+	}
+	slices.SortStableFunc(requests, func(a, b models.UsernameChangeRequest) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return paginate(requests, page, limit), len(requests), nil
+}
+
+func (r *userRepo) ReviewUsernameChangeRequest(ctx context.Context, id, reviewerID int, approve bool, note *string) (*models.UsernameChangeRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	uc := r.usernameChanges[id]
+	if uc == nil {
+		return nil, pgx.ErrNoRows
+	}
+	if uc.Status != models.UsernameChangePending {
+		return r.usernameChangeCopy(uc), repository.ErrUsernameChangeReviewed
+	}
+	status := models.UsernameChangeRejected
+	if approve {
+		status = models.UsernameChangeApproved
+		row := r.users[uc.UserID]
+		if row == nil || row.anonymizedAt != nil {
+			return nil, pgx.ErrNoRows
+		}
+		for _, u := range r.users {
+			if u.Username == uc.NewUsername && u.ID != uc.UserID {
+				return nil, repository.ErrUsernameTaken
+			}
+		}
+		r.updateUser(row, func(u *userRow) { u.Username = uc.NewUsername })
+	}
+	uc.Status, uc.ReviewedBy, uc.ReviewedAt, uc.ReviewNote = status, ptr(reviewerID), ptr(time.Now()), clonePtr(note)
+	return r.usernameChangeCopy(uc), nil
+}
+
+// previousUsernamesOf mengembalikan riwayat username user, terbaru dulu.
+func (s *store) previousUsernamesOf(userID int) []models.PreviousUsername {
+	history := []models.PreviousUsername{}
+	for _, p := range s.previousUsernames {
+		if p.UserID == userID {
+			history = append(history, p)
+		}
+	}
+	slices.SortFunc(history, func(a, b models.PreviousUsername) int {
+		if c := b.ChangedAt.Compare(a.ChangedAt); c != 0 {
+			return c
+		}
+		return b.ID - a.ID
+	})
+	return history
+}
+
+func (r *userRepo) GetPreviousUsernames(ctx context.Context, userID int) ([]models.PreviousUsername, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.previousUsernamesOf(userID), nil
+}
+
+func (r *userRepo) ResolveUsername(ctx context.Context, username string) (*models.UsernameResolution, error) {
+	if user, err := r.GetUserByUsername(ctx, username); err == nil {
+		return &models.UsernameResolution{User: user}, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var latest *models.PreviousUsername
+	for i, p := range r.previousUsernames {
+		if p.Username == username && (latest == nil || p.ChangedAt.After(latest.ChangedAt) ||
+			(p.ChangedAt.Equal(latest.ChangedAt) && p.ID > latest.ID)) {
+			latest = &r.previousUsernames[i]
+		}
+	}
+	if latest == nil || r.users[latest.UserID] == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return &models.UsernameResolution{
+		User: r.userCopy(r.users[latest.UserID], false), MatchedPrevious: true, PreviousUntil: ptr(latest.ChangedAt),
+	}, nil
+}
+
+// --- OTP SMS (user_phone.go) ---
+
+func (r *userRepo) CreatePhoneOTP(ctx context.Context, otp *models.PhoneOTP, phone string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.users[otp.UserID] == nil {
+		return pgx.ErrNoRows
+	}
+	deleteWhere(r.phoneOTPs, func(o *phoneOTPRow) bool {
+		return o.UserID == otp.UserID && o.Purpose == otp.Purpose && o.ConsumedAt == nil
+	})
+	otp.ID, otp.CreatedAt = r.nextID("phone_otps"), time.Now()
+	row := &phoneOTPRow{PhoneOTP: *otp, phone: normalizePII(&phone)}
+	row.Attempts, row.ConsumedAt = 0, nil
+	r.phoneOTPs[otp.ID] = row
+	return nil
+}
+
+func (r *userRepo) GetOpenPhoneOTP(ctx context.Context, userID int, purpose string) (*models.PhoneOTP, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, o := range r.phoneOTPs {
+		if o.UserID == userID && o.Purpose == purpose && o.ConsumedAt == nil {
+			otp := o.PhoneOTP
+			return &otp, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (r *userRepo) RecordPhoneOTPAttempt(ctx context.Context, id int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o := r.phoneOTPs[id]
+	if o == nil {
+		return 0, pgx.ErrNoRows
+	}
+	o.Attempts++
+	return o.Attempts, nil
+}
+
+func (r *userRepo) ConsumePhoneOTP(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o := r.phoneOTPs[id]
+	if o == nil || o.ConsumedAt != nil || !o.ExpiresAt.After(time.Now()) {
+		return pgx.ErrNoRows
+	}
+	row := r.users[o.UserID]
+	if row == nil || normalizePII(row.Phone) == "" || normalizePII(row.Phone) != o.phone {
+		return repository.ErrPhoneChanged
+	}
+	o.ConsumedAt = ptr(time.Now())
+	if o.Purpose == models.OTPPurposeVerifyPhone && row.PhoneVerifiedAt == nil {
+		r.updateUser(row, func(u *userRow) { u.PhoneVerifiedAt = ptr(time.Now()) })
+	}
+	return nil
+}
+
+func (r *userRepo) UpdateSMSPreferences(ctx context.Context, userID int, input *models.UpdateSMSPreferencesInput) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[userID]
+	if row == nil {
+		return nil, pgx.ErrNoRows
+	}
+	enabling := (input.TwoFactor != nil && *input.TwoFactor) || (input.Reminders != nil && *input.Reminders)
+	if enabling && row.PhoneVerifiedAt == nil {
+		return nil, repository.ErrPhoneNotVerified
+	}
+	r.updateUser(row, func(u *userRow) {
+		if input.TwoFactor != nil {
+			u.SMSTwoFactor = *input.TwoFactor
+		}
+		if input.Reminders != nil {
+			u.SMSReminders = *input.Reminders
+		}
+	})
+	return r.userCopy(row, false), nil
+}
+
+// --- Undangan (user_invitation.go) ---
+
+// invitationCopy menyalin undangan dengan status dan nama role terhitung.
+func (s *store) invitationCopy(inv *models.Invitation) models.Invitation {
+	c := *inv
+	switch {
+	case c.AcceptedAt != nil:
+		c.Status = models.InvitationAccepted
+	case c.RevokedAt != nil:
+		c.Status = models.InvitationRevoked
+	case !c.ExpiresAt.After(time.Now()):
+		c.Status = models.InvitationExpired
+	default:
+		c.Status = models.InvitationPending
+	}
+	if role := s.roles[c.RoleID]; role != nil {
+		c.RoleName = role.Name
+	}
+	return c
+}
+
+func (r *userRepo) CreateInvitation(ctx context.Context, email string, roleID, invitedBy int, expiresAt time.Time) (*models.Invitation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.emailTaken(email, 0) {
+		return nil, repository.ErrEmailTaken
+	}
+	if r.roles[roleID] == nil {
+		return nil, pgx.ErrNoRows
+	}
+	now := time.Now()
+	for _, inv := range r.invitations {
+		if strings.EqualFold(strings.TrimSpace(inv.Email), strings.TrimSpace(email)) && inv.AcceptedAt == nil && inv.RevokedAt == nil {
+			inv.RevokedAt = ptr(now)
+		}
+	}
+	inv := &models.Invitation{
+		ID: r.nextID("user_invitations"), Email: email, RoleID: roleID, InvitedBy: ptr(invitedBy),
+		ExpiresAt: expiresAt, SentAt: now, CreatedAt: now,
+	}
+	r.invitations[inv.ID] = inv
+	c := r.invitationCopy(inv)
+	return &c, nil
+}
+
+func (r *userRepo) GetInvitationByID(ctx context.Context, id int) (*models.Invitation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	inv := r.invitations[id]
+	if inv == nil {
+		return nil, pgx.ErrNoRows
+	}
+	c := r.invitationCopy(inv)
+	return &c, nil
+}
+
+func (r *userRepo) GetInvitations(ctx context.Context, status string, page, limit int) ([]models.Invitation, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	invitations := []models.Invitation{}
+	for _, inv := range sortedByKey(r.invitations) {
+		if c := r.invitationCopy(inv); status == "" || c.Status == status {
+			invitations = append(invitations, c)
+		}
+	}
+	slices.Reverse(invitations)
+	slices.SortStableFunc(invitations, func(a, b models.Invitation) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return paginate(invitations, page, limit), len(invitations), nil
+}
+
+// openInvitation mengembalikan undangan yang belum diterima & belum dicabut, atau nil.
+func (s *store) openInvitation(id int) *models.Invitation {
+	inv := s.invitations[id]
+	if inv == nil || inv.AcceptedAt != nil || inv.RevokedAt != nil {
+		return nil
+	}
+	return inv
+}
+
+func (r *userRepo) RenewInvitation(ctx context.Context, id int, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	inv := r.openInvitation(id)
+	if inv == nil {
+		return pgx.ErrNoRows
+	}
+	inv.SentAt, inv.ExpiresAt = time.Now(), expiresAt
+	return nil
+}
+
+func (r *userRepo) RevokeInvitation(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	inv := r.openInvitation(id)
+	if inv == nil {
+		return pgx.ErrNoRows
+	}
+	inv.RevokedAt = ptr(time.Now())
+	return nil
+}
+
+func (r *userRepo) AcceptInvitation(ctx context.Context, id, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	inv := r.openInvitation(id)
+	if inv == nil || !inv.ExpiresAt.After(time.Now()) {
+		return pgx.ErrNoRows
+	}
+	inv.AcceptedAt, inv.AcceptedUserID = ptr(time.Now()), ptr(userID)
+	return nil
+}
@@ -0,0 +1,669 @@
+// internal/repository/memory/users.go
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// userRow adalah baris tabel users termasuk kolom yang tidak ada di models.User.
+type userRow struct {
+	models.User
+	anonymizedAt        *time.Time
+	probationNotifiedAt *time.Time
+	hourlyRate          *float64
+}
+
+type userRepo struct{ *store }
+
+// normalizePII meniru normalisasi blind index (pii.BlindIndex): huruf kecil tanpa spasi tepi.
+func normalizePII(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(*value))
+}
+
+// userCopy menyalin baris user; withRole mengisi Role seperti JOIN roles.
+func (s *store) userCopy(row *userRow, withRole bool) *models.User {
+	u := row.User
+	u.Role = nil
+	if withRole {
+		if role := s.roles[row.RoleID]; role != nil {
+			r := role.Role
+			u.Role = &r
+		}
+	}
+	return &u
+}
+
+// uniqueViolation mengembalikan nama field unik yang sudah dipakai user lain selain id
+// (format pesan "%s already exists" di repository), atau "".
+func (s *store) uniqueViolation(id int, username, email string, nationalID *string) string {
+	for _, other := range s.users {
+		if other.ID == id {
+			continue
+		}
+		switch {
+		case other.Username == username:
+			return "username"
+		case normalizePII(&other.Email) == normalizePII(&email):
+			return "email"
+		case nationalID != nil && normalizePII(nationalID) != "" && normalizePII(other.NationalID) == normalizePII(nationalID):
+			return "national id"
+		}
+	}
+	return ""
+}
+
+// emailTaken mengecek apakah email dipakai user selain exceptID.
+func (s *store) emailTaken(email string, exceptID int) bool {
+	for _, u := range s.users {
+		if u.ID != exceptID && normalizePII(&u.Email) == normalizePII(&email) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateUser menerapkan fn ke baris user lalu meniru trigger UPDATE tabel users: version &
+// updated_at, reset verifikasi HP, token_version saat hak akses berubah, dan riwayat username.
+func (s *store) updateUser(row *userRow, fn func(u *userRow)) {
+	old := *row
+	fn(row)
+	now := time.Now()
+	row.Version = old.Version + 1
+	row.UpdatedAt = now
+	if normalizePII(row.Phone) != normalizePII(old.Phone) {
+		row.PhoneVerifiedAt = nil
+		row.SMSTwoFactor = false
+		row.SMSReminders = false
+	}
+	if row.TokenVersion == old.TokenVersion && (row.RoleID != old.RoleID ||
+		(old.IsActive && !row.IsActive) || (old.anonymizedAt == nil && row.anonymizedAt != nil)) {
+		row.TokenVersion++
+	}
+	if row.Username != old.Username && row.anonymizedAt == nil {
+		historyID := s.nextID("previous_usernames")
+		s.previousUsernames = append(s.previousUsernames, models.PreviousUsername{
+			ID: historyID, UserID: row.ID, Username: old.Username, ChangedAt: now,
+		})
+		s.addAudit(row.ID, nil, models.AuditUsernameChanged, map[string]any{"previous_username_id": historyID})
+	}
+}
+
+func (r *userRepo) CreateUser(ctx context.Context, input *models.RegisterUserInput, hashedPassword string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if field := r.uniqueViolation(0, input.Username, input.Email, nil); field != "" {
+		return 0, fmt.Errorf("username already taken: %w", &pgconn.PgError{Code: "23505", ConstraintName: "users_" + field + "_key"})
+	}
+	if r.roles[input.RoleID] == nil {
+		return 0, fmt.Errorf("error creating user: %w", &pgconn.PgError{Code: "23503", ConstraintName: "users_role_id_fkey"})
+	}
+	now := time.Now()
+	row := &userRow{User: models.User{
+		ID:               r.nextID("users"),
+		Username:         input.Username,
+		Password:         hashedPassword,
+		Email:            input.Email,
+		FirstName:        input.FirstName,
+		LastName:         input.LastName,
+		Phone:            clonePtr(input.Phone),
+		RoleID:           input.RoleID,
+		UserType:         models.UserTypeEmployee,
+		IsActive:         true,
+		EmploymentStatus: models.EmploymentStatusPermanent,
+		Version:          1,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}}
+	r.users[row.ID] = row
+	return row.ID, nil
+}
+
+func (r *userRepo) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, row := range r.users {
+		if row.Username == username {
+			return r.userCopy(row, true), nil
+		}
+	}
+	return nil, fmt.Errorf("error getting user by username %s: %w", username, pgx.ErrNoRows)
+}
+
+func (r *userRepo) GetUserByID(ctx context.Context, id int) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil {
+		return nil, fmt.Errorf("error getting user by id %d: %w", id, pgx.ErrNoRows)
+	}
+	return r.userCopy(row, false), nil
+}
+
+func (r *userRepo) DeleteUserByID(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.users[id] == nil {
+		return pgx.ErrNoRows
+	}
+	r.deleteUser(id)
+	return nil
+}
+
+// deleteUser menghapus user beserta data yang ON DELETE CASCADE; manager_id bawahan di-NULL-kan.
+func (s *store) deleteUser(id int) {
+	delete(s.users, id)
+	s.recordTombstone(models.SyncEntityUsers, id)
+	for _, u := range s.users {
+		if u.ManagerID != nil && *u.ManagerID == id {
+			s.updateUser(u, func(u *userRow) { u.ManagerID = nil })
+		}
+	}
+	for _, sc := range s.schedules {
+		if sc.UserID == id {
+			delete(s.schedules, sc.ID)
+			s.recordTombstone(models.SyncEntitySchedules, sc.ID)
+		}
+	}
+	for _, a := range s.attendances {
+		if a.UserID == id {
+			s.deleteAttendance(a.ID)
+		}
+	}
+	s.previousUsernames = slices.DeleteFunc(s.previousUsernames, func(p models.PreviousUsername) bool { return p.UserID == id })
+	s.audit = slices.DeleteFunc(s.audit, func(e models.AuditEntry) bool { return e.UserID == id })
+	deleteWhere(s.emailChanges, func(e *models.EmailChangeRequest) bool { return e.UserID == id })
+	deleteWhere(s.usernameChanges, func(uc *models.UsernameChangeRequest) bool { return uc.UserID == id })
+	deleteWhere(s.phoneOTPs, func(o *phoneOTPRow) bool { return o.UserID == id })
+	deleteWhere(s.deviceTokens, func(t *models.DeviceToken) bool { return t.UserID == id })
+	deleteWhere(s.notifications, func(n *models.Notification) bool { return n.UserID == id })
+	deleteWhere(s.kiosks, func(k *models.KioskDevice) bool { return k.UserID == id })
+	deleteWhere(s.verificationLinks, func(l *models.EmploymentVerificationLink) bool { return l.UserID == id })
+	deleteWhere(s.delegations, func(d *models.ApprovalDelegation) bool { return d.DelegatorID == id || d.DelegateID == id })
+}
+
+// deleteWhere menghapus entri map yang memenuhi fn.
+func deleteWhere[K comparable, V any](m map[K]V, fn func(V) bool) int {
+	deleted := 0
+	for k, v := range m {
+		if fn(v) {
+			delete(m, k)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func (r *userRepo) GetAllUsers(ctx context.Context, page, limit int) ([]models.User, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users := []models.User{}
+	for _, row := range sortedByKey(r.users) {
+		users = append(users, *r.userCopy(row, true))
+	}
+	return paginate(users, page, limit), len(users), nil
+}
+
+// missedUpdate meniru resolveMissedUpdate untuk users.
+func (s *store) missedUserUpdate(id int) error {
+	if s.users[id] == nil {
+		return pgx.ErrNoRows
+	}
+	return repository.ErrVersionConflict
+}
+
+func (r *userRepo) UpdateUserByID(ctx context.Context, id int, input *models.AdminUpdateUserInput) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil || (input.Version > 0 && row.Version != input.Version) {
+		return r.missedUserUpdate(id)
+	}
+	if field := r.uniqueViolation(id, input.Username, input.Email, input.NationalID); field != "" {
+		return fmt.Errorf("%s already exists", field)
+	}
+	if r.roles[input.RoleID] == nil {
+		return fmt.Errorf("error updating user: %w", &pgconn.PgError{Code: "23503", ConstraintName: "users_role_id_fkey"})
+	}
+	r.updateUser(row, func(u *userRow) {
+		u.Username, u.Email, u.FirstName, u.LastName = input.Username, input.Email, input.FirstName, input.LastName
+		u.Phone, u.NationalID, u.RoleID = clonePtr(input.Phone), clonePtr(input.NationalID), input.RoleID
+	})
+	input.Version = row.Version
+	return nil
+}
+
+func (r *userRepo) PatchUserByID(ctx context.Context, id int, input *models.AdminPatchUserInput) (int, error) {
+	if input.Username == nil && input.Email == nil && input.FirstName == nil && input.LastName == nil &&
+		input.Phone == nil && input.NationalID == nil && input.RoleID == nil {
+		return 0, repository.ErrNoFieldsToUpdate
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil || (input.Version > 0 && row.Version != input.Version) {
+		return 0, r.missedUserUpdate(id)
+	}
+	next := row.User
+	if input.Username != nil {
+		next.Username = *input.Username
+	}
+	if input.Email != nil {
+		next.Email = *input.Email
+	}
+	if input.NationalID != nil {
+		next.NationalID = nil
+		if *input.NationalID != "" {
+			next.NationalID = clonePtr(input.NationalID)
+		}
+	}
+	if field := r.uniqueViolation(id, next.Username, next.Email, next.NationalID); field != "" {
+		return 0, fmt.Errorf("%s already exists", field)
+	}
+	if input.RoleID != nil && r.roles[*input.RoleID] == nil {
+		return 0, fmt.Errorf("invalid role_id")
+	}
+	r.updateUser(row, func(u *userRow) {
+		u.Username, u.Email, u.NationalID = next.Username, next.Email, next.NationalID
+		if input.FirstName != nil {
+			u.FirstName = *input.FirstName
+		}
+		if input.LastName != nil {
+			u.LastName = *input.LastName
+		}
+		if input.Phone != nil {
+			u.Phone = nil
+			if *input.Phone != "" {
+				u.Phone = clonePtr(input.Phone)
+			}
+		}
+		if input.RoleID != nil {
+			u.RoleID = *input.RoleID
+		}
+	})
+	return row.Version, nil
+}
+
+func (r *userRepo) BulkUpdateUserRole(ctx context.Context, userIDs []int, roleID int) ([]models.BulkItemResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.roles[roleID] == nil {
+		return nil, fmt.Errorf("invalid role_id")
+	}
+	results := make([]models.BulkItemResult, 0, len(userIDs))
+	seen := make(map[int]bool, len(userIDs))
+	for _, id := range userIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		row := r.users[id]
+		switch {
+		case row == nil:
+			results = append(results, models.BulkItemResult{ID: id, Status: models.BulkStatusNotFound})
+		case row.RoleID == roleID:
+			results = append(results, models.BulkItemResult{ID: id, Status: models.BulkStatusUnchanged})
+		default:
+			r.updateUser(row, func(u *userRow) { u.RoleID = roleID })
+			results = append(results, models.BulkItemResult{ID: id, Status: models.BulkStatusUpdated})
+		}
+	}
+	return results, nil
+}
+
+func (r *userRepo) UpdateUserPassword(ctx context.Context, id int, hashedPassword string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil {
+		return pgx.ErrNoRows
+	}
+	r.updateUser(row, func(u *userRow) { u.Password = hashedPassword })
+	return nil
+}
+
+func (r *userRepo) UpdateUserProfile(ctx context.Context, id int, input *models.UpdateProfileInput) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil {
+		return pgx.ErrNoRows
+	}
+	if field := r.uniqueViolation(id, input.Username, input.Email, nil); field != "" {
+		return fmt.Errorf("%s already exists", field)
+	}
+	r.updateUser(row, func(u *userRow) {
+		u.Username, u.Email, u.FirstName, u.LastName = input.Username, input.Email, input.FirstName, input.LastName
+		u.Phone = clonePtr(input.Phone)
+	})
+	return nil
+}
+
+func (r *userRepo) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if normalizePII(&email) != "" {
+		for _, row := range r.users {
+			if normalizePII(&row.Email) == normalizePII(&email) {
+				return r.userCopy(row, false), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("error getting user by email: %w", pgx.ErrNoRows)
+}
+
+// EncryptLegacyPII tidak melakukan apa-apa: store memori tidak mengenkripsi PII.
+func (r *userRepo) EncryptLegacyPII(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (r *userRepo) AnonymizeUser(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil {
+		return pgx.ErrNoRows
+	}
+	if row.anonymizedAt != nil {
+		return repository.ErrUserAlreadyAnonymized
+	}
+	pseudonym := fmt.Sprintf("anonymized-%d", id)
+	r.updateUser(row, func(u *userRow) {
+		u.Username, u.Password, u.Email = pseudonym, "!", pseudonym+"@anonymized.invalid"
+		u.Phone, u.NationalID = nil, nil
+		u.FirstName, u.LastName = "Anonymized", ""
+		u.anonymizedAt = ptr(time.Now())
+	})
+	for _, a := range r.attendances {
+		if a.UserID == id && a.Notes != nil {
+			a.Notes = nil
+			a.UpdatedAt = time.Now()
+		}
+	}
+	for i := range r.attendanceEvents {
+		if r.attendanceEvents[i].UserID == id {
+			r.attendanceEvents[i].Notes = nil
+		}
+	}
+	for i := range r.audit {
+		if r.audit[i].UserID == id {
+			for _, key := range []string{"ip", "user_agent", "device", "country"} {
+				delete(r.audit[i].Details, key)
+			}
+		}
+	}
+	deleteWhere(r.emailChanges, func(e *models.EmailChangeRequest) bool { return e.UserID == id })
+	r.previousUsernames = slices.DeleteFunc(r.previousUsernames, func(p models.PreviousUsername) bool { return p.UserID == id })
+	deleteWhere(r.usernameChanges, func(uc *models.UsernameChangeRequest) bool { return uc.UserID == id })
+	deleteWhere(r.invitations, func(inv *models.Invitation) bool { return inv.AcceptedUserID != nil && *inv.AcceptedUserID == id })
+	deleteWhere(r.phoneOTPs, func(o *phoneOTPRow) bool { return o.UserID == id })
+	return nil
+}
+
+func (r *userRepo) UpdateUserAccess(ctx context.Context, id int, input *models.UpdateUserAccessInput, today time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil {
+		return pgx.ErrNoRows
+	}
+	r.updateUser(row, func(u *userRow) {
+		u.UserType, u.ValidFrom, u.ValidUntil = input.UserType, clonePtr(input.ValidFrom), clonePtr(input.ValidUntil)
+		u.IsActive = input.ValidUntil == nil || *input.ValidUntil >= today.Format(dateLayout)
+	})
+	return nil
+}
+
+func (r *userRepo) DeactivateExpiredContractors(ctx context.Context, today time.Time) ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := []int{}
+	for _, row := range sortedByKey(r.users) {
+		if row.UserType == models.UserTypeContractor && row.IsActive && row.ValidUntil != nil && *row.ValidUntil < today.Format(dateLayout) {
+			r.updateUser(row, func(u *userRow) { u.IsActive = false })
+			ids = append(ids, row.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *userRepo) UpdateUserEmployment(ctx context.Context, id int, input *models.UpdateEmploymentInput) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.users[id]
+	if row == nil {
+		return pgx.ErrNoRows
+	}
+	r.updateUser(row, func(u *userRow) {
+		if normalizePII(u.ProbationEnd) != normalizePII(input.ProbationEnd) {
+			u.probationNotifiedAt = nil
+		}
+		u.HireDate, u.EmploymentStatus, u.ProbationEnd = clonePtr(input.HireDate), input.EmploymentStatus, clonePtr(input.ProbationEnd)
+	})
+	return nil
+}
+
+func (r *userRepo) GetPendingProbationReviews(ctx context.Context, endingBy time.Time) ([]models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users := []models.User{}
+	for _, row := range sortedByKey(r.users) {
+		if row.EmploymentStatus == models.EmploymentStatusProbation && row.ProbationEnd != nil &&
+			*row.ProbationEnd <= endingBy.Format(dateLayout) && row.probationNotifiedAt == nil {
+			users = append(users, *r.userCopy(row, false))
+		}
+	}
+	slices.SortStableFunc(users, func(a, b models.User) int { return strings.Compare(*a.ProbationEnd, *b.ProbationEnd) })
+	return users, nil
+}
+
+func (r *userRepo) MarkProbationNotified(ctx context.Context, ids []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		if row := r.users[id]; row != nil {
+			r.updateUser(row, func(u *userRow) { u.probationNotifiedAt = ptr(time.Now()) })
+		}
+	}
+	return nil
+}
+
+// --- Garis pelaporan (org_chart.go) ---
+
+// managerChain mengembalikan id pada rantai atasan mulai dari id sendiri (berhenti saat siklus).
+func (s *store) managerChain(id int) []int {
+	var chain []int
+	for current := s.users[id]; current != nil && !slices.Contains(chain, current.ID); {
+		chain = append(chain, current.ID)
+		if current.ManagerID == nil {
+			break
+		}
+		current = s.users[*current.ManagerID]
+	}
+	return chain
+}
+
+func (r *userRepo) SetUserManager(ctx context.Context, userID int, managerID *int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if managerID != nil {
+		chain := r.managerChain(*managerID)
+		if len(chain) == 0 {
+			return pgx.ErrNoRows
+		}
+		if slices.Contains(chain, userID) {
+			return repository.ErrManagerCycle
+		}
+	}
+	row := r.users[userID]
+	if row == nil {
+		return pgx.ErrNoRows
+	}
+	r.updateUser(row, func(u *userRow) { u.ManagerID = clonePtr(managerID) })
+	return nil
+}
+
+func (r *userRepo) IsInReportingLine(ctx context.Context, managerID, userID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return managerID != userID && r.inReportingLine(managerID, userID), nil
+}
+
+// inReportingLine mengecek apakah managerID ada di rantai atasan userID (tanpa userID sendiri).
+func (s *store) inReportingLine(managerID, userID int) bool {
+	chain := s.managerChain(userID)
+	return len(chain) > 1 && slices.Contains(chain[1:], managerID)
+}
+
+func (r *userRepo) GetReportingTree(ctx context.Context, rootID *int, dayStart time.Time) ([]models.OrgChartNode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	type entry struct {
+		row   *userRow
+		depth int
+		path  []int
+	}
+	var queue []entry
+	for _, u := range sortedByKey(r.users) {
+		if u.IsActive && ((rootID == nil && u.ManagerID == nil) || (rootID != nil && u.ID == *rootID)) {
+			queue = append(queue, entry{u, 0, []int{u.ID}})
+		}
+	}
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	nodes := []models.OrgChartNode{}
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		nodes = append(nodes, models.OrgChartNode{
+			UserID: e.row.ID, Username: e.row.Username, FirstName: e.row.FirstName, LastName: e.row.LastName,
+			ManagerID: clonePtr(e.row.ManagerID), Depth: e.depth, Presence: r.presence(e.row.ID, dayStart, dayEnd),
+		})
+		for _, c := range sortedByKey(r.users) {
+			if c.IsActive && c.ManagerID != nil && *c.ManagerID == e.row.ID && !slices.Contains(e.path, c.ID) {
+				queue = append(queue, entry{c, e.depth + 1, append(slices.Clone(e.path), c.ID)})
+			}
+		}
+	}
+	slices.SortStableFunc(nodes, func(a, b models.OrgChartNode) int {
+		if a.Depth != b.Depth {
+			return a.Depth - b.Depth
+		}
+		return strings.Compare(a.Username, b.Username)
+	})
+	return nodes, nil
+}
+
+// presence menghitung status kehadiran user pada [dayStart, dayEnd) seperti GetReportingTree.
+func (s *store) presence(userID int, dayStart, dayEnd time.Time) string {
+	checkedIn := false
+	for _, a := range s.attendances {
+		if a.UserID != userID {
+			continue
+		}
+		if a.CheckOutAt == nil {
+			return models.PresencePresent
+		}
+		if !a.CheckInAt.Before(dayStart) && a.CheckInAt.Before(dayEnd) {
+			checkedIn = true
+		}
+	}
+	if checkedIn {
+		return models.PresenceCheckedOut
+	}
+	for _, sc := range s.schedules {
+		if sc.UserID == userID && sc.Date == dayStart.Format(dateLayout) {
+			return models.PresenceAbsent
+		}
+	}
+	return models.PresenceOff
+}
+
+// --- Ekspor, sync & include ---
+
+func (r *userRepo) ExportUsers(ctx context.Context, filter models.UserExportFilter, fn func(*models.UserExportRow) error) error {
+	// Baris disusun dulu agar fn dipanggil tanpa memegang lock.
+	r.mu.Lock()
+	var rows []models.UserExportRow
+	for _, u := range sortedByKey(r.users) {
+		if (filter.RoleID != nil && u.RoleID != *filter.RoleID) || (filter.UserType != "" && u.UserType != filter.UserType) ||
+			(filter.EmploymentStatus != "" && u.EmploymentStatus != filter.EmploymentStatus) ||
+			(filter.IsActive != nil && u.IsActive != *filter.IsActive) {
+			continue
+		}
+		row := models.UserExportRow{User: *r.userCopy(u, true), PreviousUsernames: []string{}}
+		for _, a := range r.attendances {
+			if a.UserID != u.ID {
+				continue
+			}
+			last := a.CheckInAt
+			if a.CheckOutAt != nil && a.CheckOutAt.After(last) {
+				last = *a.CheckOutAt
+			}
+			if row.LastActivityAt == nil || last.After(*row.LastActivityAt) {
+				row.LastActivityAt = ptr(last)
+			}
+		}
+		for _, p := range r.previousUsernamesOf(u.ID) {
+			row.PreviousUsernames = append(row.PreviousUsernames, p.Username)
+		}
+		rows = append(rows, row)
+	}
+	r.mu.Unlock()
+
+	for i := range rows {
+		if err := fn(&rows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *userRepo) GetUserChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	updated := make([]change, 0, len(r.users))
+	for _, u := range r.users {
+		updated = append(updated, change{id: u.ID, changedAt: u.UpdatedAt})
+	}
+	ids, batch := r.syncChanges(models.SyncEntityUsers, updated, cursor, limit)
+	users := []models.User{}
+	for _, id := range ids {
+		users = append(users, *r.userCopy(r.users[id], false))
+	}
+	batch.Items = users
+	return batch, nil
+}
+
+func (r *userRepo) GetUserSummariesByIDs(ctx context.Context, ids []int) ([]models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users := []models.User{}
+	for _, u := range sortedByKey(r.users) {
+		if slices.Contains(ids, u.ID) {
+			users = append(users, *r.userSummary(u))
+		}
+	}
+	return users, nil
+}
+
+// userSummary meniru kolom userSummaryColumns (ringkasan user untuk embed).
+func (s *store) userSummary(u *userRow) *models.User {
+	if u == nil {
+		return nil
+	}
+	return &models.User{
+		ID: u.ID, Username: u.Username, Email: u.Email, FirstName: u.FirstName, LastName: u.LastName,
+		UserType: u.UserType, ValidUntil: clonePtr(u.ValidUntil), IsActive: u.IsActive, EmploymentStatus: u.EmploymentStatus,
+	}
+}
@@ -0,0 +1,429 @@
+// internal/repository/memory/workflow.go
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// --- Periode payroll ---
+
+type payrollRepo struct{ *store }
+
+func payrollCopy(p *models.PayrollPeriod) *models.PayrollPeriod {
+	out := *p
+	out.ClosedAt, out.ClosedBy, out.ReopenedAt, out.ReopenedBy = clonePtr(p.ClosedAt), clonePtr(p.ClosedBy), clonePtr(p.ReopenedAt), clonePtr(p.ReopenedBy)
+	out.ReopenReason, out.CreatedBy = clonePtr(p.ReopenReason), clonePtr(p.CreatedBy)
+	return &out
+}
+
+func (r *payrollRepo) CreatePayrollPeriod(ctx context.Context, period *models.PayrollPeriod) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.payrollPeriods {
+		if period.StartDate <= p.EndDate && period.EndDate >= p.StartDate {
+			return 0, repository.ErrPayrollPeriodOverlap
+		}
+	}
+	now := time.Now()
+	p := &models.PayrollPeriod{
+		ID: r.nextID("payroll_periods"), StartDate: period.StartDate, EndDate: period.EndDate, Status: models.PayrollPeriodOpen,
+		CreatedBy: clonePtr(period.CreatedBy), CreatedAt: now, UpdatedAt: now,
+	}
+	r.payrollPeriods[p.ID] = p
+	return p.ID, nil
+}
+
+func (r *payrollRepo) GetPayrollPeriodByID(ctx context.Context, id int) (*models.PayrollPeriod, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.payrollPeriods[id]
+	if p == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return payrollCopy(p), nil
+}
+
+func (r *payrollRepo) GetAllPayrollPeriods(ctx context.Context, page, limit int) ([]models.PayrollPeriod, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := sortedByKey(r.payrollPeriods)
+	slices.SortStableFunc(all, func(a, b *models.PayrollPeriod) int { return strings.Compare(b.StartDate, a.StartDate) })
+	periods := []models.PayrollPeriod{}
+	for _, p := range paginate(all, page, limit) {
+		periods = append(periods, *payrollCopy(p))
+	}
+	return periods, len(all), nil
+}
+
+// transition mengubah status periode dari from; ErrPayrollPeriodStatus jika status tidak cocok.
+func (r *payrollRepo) transition(id int, from string, fn func(p *models.PayrollPeriod)) (*models.PayrollPeriod, error) {
+	p := r.payrollPeriods[id]
+	if p == nil {
+		return nil, pgx.ErrNoRows
+	}
+	if p.Status != from {
+		return nil, repository.ErrPayrollPeriodStatus
+	}
+	fn(p)
+	p.UpdatedAt = time.Now()
+	return payrollCopy(p), nil
+}
+
+func (r *payrollRepo) ClosePayrollPeriod(ctx context.Context, id int, actorUserID int) (*models.PayrollPeriod, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.transition(id, models.PayrollPeriodOpen, func(p *models.PayrollPeriod) {
+		p.Status, p.ClosedAt, p.ClosedBy = models.PayrollPeriodClosed, ptr(time.Now()), ptr(actorUserID)
+	})
+}
+
+func (r *payrollRepo) ReopenPayrollPeriod(ctx context.Context, id int, actorUserID int, reason string) (*models.PayrollPeriod, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.transition(id, models.PayrollPeriodClosed, func(p *models.PayrollPeriod) {
+		p.Status, p.ReopenedAt, p.ReopenedBy, p.ReopenReason = models.PayrollPeriodOpen, ptr(time.Now()), ptr(actorUserID), ptr(reason)
+	})
+}
+
+// --- Project ---
+
+type projectRepo struct{ *store }
+
+func (r *projectRepo) codeTaken(code string, exceptID int) bool {
+	for _, p := range r.projects {
+		if p.ID != exceptID && p.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *projectRepo) CreateProject(ctx context.Context, project *models.Project) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.codeTaken(project.Code, 0) {
+		return 0, repository.ErrProjectCodeTaken
+	}
+	now := time.Now()
+	p := &models.Project{
+		ID: r.nextID("projects"), Code: project.Code, Name: project.Name, CostCenter: clonePtr(project.CostCenter),
+		IsActive: project.IsActive, CreatedAt: now, UpdatedAt: now,
+	}
+	r.projects[p.ID] = p
+	return p.ID, nil
+}
+
+func (r *projectRepo) GetProjectByID(ctx context.Context, id int) (*models.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.projects[id]
+	if p == nil {
+		return nil, pgx.ErrNoRows
+	}
+	out := *p
+	out.CostCenter = clonePtr(p.CostCenter)
+	return &out, nil
+}
+
+func (r *projectRepo) GetAllProjects(ctx context.Context, activeOnly bool) ([]models.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	projects := []models.Project{}
+	for _, p := range sortedByKey(r.projects) {
+		if p.IsActive || !activeOnly {
+			out := *p
+			out.CostCenter = clonePtr(p.CostCenter)
+			projects = append(projects, out)
+		}
+	}
+	slices.SortStableFunc(projects, func(a, b models.Project) int { return strings.Compare(a.Code, b.Code) })
+	return projects, nil
+}
+
+func (r *projectRepo) UpdateProject(ctx context.Context, project *models.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.codeTaken(project.Code, project.ID) {
+		return repository.ErrProjectCodeTaken
+	}
+	p := r.projects[project.ID]
+	if p == nil {
+		return pgx.ErrNoRows
+	}
+	p.Code, p.Name, p.CostCenter, p.IsActive, p.UpdatedAt = project.Code, project.Name, clonePtr(project.CostCenter), project.IsActive, time.Now()
+	return nil
+}
+
+func (r *projectRepo) DeleteProject(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.attendances {
+		if a.ProjectID != nil && *a.ProjectID == id {
+			return repository.ErrProjectInUse
+		}
+	}
+	for _, sg := range r.segments {
+		if sg.ProjectID != nil && *sg.ProjectID == id {
+			return repository.ErrProjectInUse
+		}
+	}
+	if r.projects[id] == nil {
+		return pgx.ErrNoRows
+	}
+	delete(r.projects, id)
+	return nil
+}
+
+// --- Sign-off harian ---
+
+type signOffRepo struct{ *store }
+
+// team meniru teamQuery: id user aktif di bawah managerID (langsung & tidak langsung), urut id.
+func (s *store) team(managerID int) []int {
+	var ids []int
+	var walk func(parent int, path []int)
+	walk = func(parent int, path []int) {
+		for _, u := range sortedByKey(s.users) {
+			if u.ManagerID != nil && *u.ManagerID == parent && u.IsActive && !slices.Contains(path, u.ID) {
+				if !slices.Contains(ids, u.ID) {
+					ids = append(ids, u.ID)
+				}
+				walk(u.ID, append(slices.Clone(path), u.ID))
+			}
+		}
+	}
+	if s.users[managerID] != nil {
+		walk(managerID, []int{managerID})
+	}
+	ids = slices.DeleteFunc(ids, func(id int) bool { return id == managerID })
+	slices.Sort(ids)
+	return ids
+}
+
+func (r *signOffRepo) SignOffDay(ctx context.Context, managerID, signerID int, day time.Time, userIDs []int, grace time.Duration, note *string) ([]models.SignOffResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	team := r.team(managerID)
+	results := []models.SignOffResult{}
+	targets := team
+	if len(userIDs) > 0 {
+		targets = nil
+		for _, id := range userIDs {
+			switch {
+			case slices.Contains(targets, id):
+				// Duplikat di input
+			case slices.Contains(team, id):
+				targets = append(targets, id)
+			default:
+				results = append(results, models.SignOffResult{UserID: id, Status: models.BulkStatusNotFound, Message: "User is not in your team"})
+			}
+		}
+		slices.Sort(targets)
+	}
+	for _, userID := range targets {
+		results = append(results, r.signOffUser(signerID, userID, day, grace, note))
+	}
+	return results, nil
+}
+
+// signOffUser meniru repository.signOffUser untuk satu anggota tim.
+func (s *store) signOffUser(signerID, userID int, day time.Time, grace time.Duration, note *string) models.SignOffResult {
+	workDate := day.Format(dateLayout)
+	scheduled := false
+	for _, so := range s.signOffs {
+		if so.UserID == userID && so.WorkDate == workDate {
+			return models.SignOffResult{UserID: userID, Status: models.BulkStatusUnchanged, Message: "Already signed off"}
+		}
+	}
+	for _, sc := range s.schedules {
+		scheduled = scheduled || (sc.UserID == userID && sc.Date == workDate)
+	}
+
+	exceptions := []string{}
+	flag := func(exception string) {
+		if !slices.Contains(exceptions, exception) {
+			exceptions = append(exceptions, exception)
+		}
+	}
+	sessions := s.attendancesBetween(day, day.AddDate(0, 0, 1).Add(-time.Nanosecond), func(a *models.Attendance) bool { return a.UserID == userID })
+	slices.Reverse(sessions)
+	open := false
+	for _, a := range sessions {
+		open = open || a.CheckOutAt == nil
+		var linked *models.UserSchedule
+		if a.ScheduleID != nil {
+			linked = s.schedules[*a.ScheduleID]
+		}
+		if linked == nil {
+			flag(models.SignOffExceptionUnscheduled)
+		} else if shift := s.effectiveShift(linked.ShiftID, linked.Date); shift == nil {
+			flag(models.SignOffExceptionUnscheduled)
+		} else if start, err := time.ParseInLocation(dateLayout+" 15:04:05", linked.Date+" "+shift.StartTime, day.Location()); err == nil && a.CheckInAt.After(start.Add(grace)) {
+			flag(models.SignOffExceptionLate)
+		}
+		corrected := slices.ContainsFunc(s.attendanceEvents, func(e models.AttendanceEvent) bool {
+			return e.AttendanceID == a.ID && e.EventType == models.AttendanceEventCorrection
+		})
+		if corrected {
+			flag(models.SignOffExceptionCorrected)
+		}
+	}
+	if open {
+		return models.SignOffResult{UserID: userID, Status: models.BulkStatusSkipped, Message: "Attendance session is still open"}
+	}
+	if len(sessions) == 0 && scheduled {
+		flag(models.SignOffExceptionNoShow)
+	}
+	so := &models.AttendanceSignOff{
+		ID: s.nextID("attendance_signoffs"), UserID: userID, WorkDate: workDate, SignedBy: ptr(signerID),
+		SignedAt: time.Now(), Exceptions: slices.Clone(exceptions), Note: clonePtr(note),
+	}
+	s.signOffs[so.ID] = so
+	return models.SignOffResult{UserID: userID, Status: models.BulkStatusUpdated, Exceptions: exceptions}
+}
+
+func (r *signOffRepo) GetUnsignedDays(ctx context.Context, managerID *int, startDate, endDate time.Time) ([]models.UnsignedDay, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var team []int
+	if managerID != nil {
+		team = r.team(*managerID)
+	}
+	type workDay struct {
+		userID int
+		date   string
+	}
+	seen := map[workDay]bool{}
+	for _, sc := range r.schedules {
+		if inDateRange(sc.Date, startDate, endDate) {
+			seen[workDay{sc.UserID, sc.Date}] = true
+		}
+	}
+	for _, a := range r.attendances {
+		if date := dateOf(a.CheckInAt); inDateRange(date, startDate, endDate) {
+			seen[workDay{a.UserID, date}] = true
+		}
+	}
+	days := []models.UnsignedDay{}
+	for w := range seen {
+		u := r.users[w.userID]
+		if u == nil || !u.IsActive || (managerID != nil && !slices.Contains(team, u.ID)) {
+			continue
+		}
+		signed := false
+		for _, so := range r.signOffs {
+			signed = signed || (so.UserID == w.userID && so.WorkDate == w.date)
+		}
+		if !signed {
+			days = append(days, models.UnsignedDay{UserID: u.ID, Username: u.Username, ManagerID: clonePtr(u.ManagerID), WorkDate: w.date})
+		}
+	}
+	slices.SortFunc(days, func(a, b models.UnsignedDay) int {
+		if c := strings.Compare(a.WorkDate, b.WorkDate); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Username, b.Username)
+	})
+	return days, nil
+}
+
+// --- Dispute absensi ---
+
+type disputeRepo struct{ *store }
+
+func disputeCopy(d *models.AttendanceDispute) *models.AttendanceDispute {
+	out := *d
+	out.ResolutionNote, out.ResolvedBy, out.ResolvedAt = clonePtr(d.ResolutionNote), clonePtr(d.ResolvedBy), clonePtr(d.ResolvedAt)
+	return &out
+}
+
+// disputesWhere mengembalikan dispute yang memenuhi fn, urut created_at & id (newestFirst = DESC).
+func (s *store) disputesWhere(newestFirst bool, fn func(d *models.AttendanceDispute) bool) []models.AttendanceDispute {
+	disputes := []models.AttendanceDispute{}
+	for _, d := range sortedByKey(s.disputes) {
+		if fn(d) {
+			disputes = append(disputes, *disputeCopy(d))
+		}
+	}
+	slices.SortStableFunc(disputes, func(a, b models.AttendanceDispute) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	if newestFirst {
+		slices.Reverse(disputes)
+	}
+	return disputes
+}
+
+func (r *disputeRepo) CreateDispute(ctx context.Context, attendanceID, userID int, comment string) (*models.AttendanceDispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a := r.attendances[attendanceID]
+	if a == nil || a.UserID != userID {
+		return nil, pgx.ErrNoRows
+	}
+	if r.openDispute(attendanceID) != nil {
+		return nil, repository.ErrDisputeAlreadyOpen
+	}
+	now := time.Now()
+	d := &models.AttendanceDispute{
+		ID: r.nextID("attendance_disputes"), AttendanceID: attendanceID, UserID: userID, Comment: comment,
+		Status: models.DisputeOpen, CreatedAt: now, UpdatedAt: now,
+	}
+	r.disputes[d.ID] = d
+	return disputeCopy(d), nil
+}
+
+func (r *disputeRepo) GetDisputeByID(ctx context.Context, id int) (*models.AttendanceDispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.disputes[id]
+	if d == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return disputeCopy(d), nil
+}
+
+func (r *disputeRepo) GetDisputesByUser(ctx context.Context, userID int) ([]models.AttendanceDispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.disputesWhere(true, func(d *models.AttendanceDispute) bool { return d.UserID == userID }), nil
+}
+
+func (r *disputeRepo) GetAllDisputes(ctx context.Context, status string, page, limit int) ([]models.AttendanceDispute, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := r.disputesWhere(false, func(d *models.AttendanceDispute) bool { return status == "" || d.Status == status })
+	return paginate(all, page, limit), len(all), nil
+}
+
+func (r *disputeRepo) ResolveDispute(ctx context.Context, id int, actorUserID int, status, note string) (*models.AttendanceDispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.disputes[id]
+	if d == nil {
+		return nil, pgx.ErrNoRows
+	}
+	if d.Status != models.DisputeOpen {
+		return nil, repository.ErrDisputeNotOpen
+	}
+	now := time.Now()
+	d.Status, d.ResolutionNote, d.ResolvedBy, d.ResolvedAt, d.UpdatedAt = status, ptr(note), ptr(actorUserID), ptr(now), now
+	return disputeCopy(d), nil
+}
+
+func (r *disputeRepo) GetOpenDisputesBefore(ctx context.Context, before time.Time, limit int) ([]models.AttendanceDispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	disputes := r.disputesWhere(false, func(d *models.AttendanceDispute) bool {
+		return d.Status == models.DisputeOpen && d.CreatedAt.Before(before)
+	})
+	if len(disputes) > limit {
+		disputes = disputes[:limit]
+	}
+	return disputes, nil
+}
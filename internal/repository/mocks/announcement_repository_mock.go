@@ -0,0 +1,385 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockAnnouncementRepository is an autogenerated mock type for the AnnouncementRepository type
+type MockAnnouncementRepository struct {
+	mock.Mock
+}
+
+type MockAnnouncementRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAnnouncementRepository) EXPECT() *MockAnnouncementRepository_Expecter {
+	return &MockAnnouncementRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateAnnouncement provides a mock function with given fields: ctx, announcement
+func (_m *MockAnnouncementRepository) CreateAnnouncement(ctx context.Context, announcement *models.Announcement) (int, error) {
+	ret := _m.Called(ctx, announcement)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAnnouncement")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Announcement) (int, error)); ok {
+		return rf(ctx, announcement)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Announcement) int); ok {
+		r0 = rf(ctx, announcement)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Announcement) error); ok {
+		r1 = rf(ctx, announcement)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAnnouncementRepository_CreateAnnouncement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAnnouncement'
+type MockAnnouncementRepository_CreateAnnouncement_Call struct {
+	*mock.Call
+}
+
+// CreateAnnouncement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - announcement *models.Announcement
+func (_e *MockAnnouncementRepository_Expecter) CreateAnnouncement(ctx interface{}, announcement interface{}) *MockAnnouncementRepository_CreateAnnouncement_Call {
+	return &MockAnnouncementRepository_CreateAnnouncement_Call{Call: _e.mock.On("CreateAnnouncement", ctx, announcement)}
+}
+
+func (_c *MockAnnouncementRepository_CreateAnnouncement_Call) Run(run func(ctx context.Context, announcement *models.Announcement)) *MockAnnouncementRepository_CreateAnnouncement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Announcement))
+	})
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_CreateAnnouncement_Call) Return(_a0 int, _a1 error) *MockAnnouncementRepository_CreateAnnouncement_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_CreateAnnouncement_Call) RunAndReturn(run func(context.Context, *models.Announcement) (int, error)) *MockAnnouncementRepository_CreateAnnouncement_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAnnouncement provides a mock function with given fields: ctx, id
+func (_m *MockAnnouncementRepository) DeleteAnnouncement(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAnnouncement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAnnouncementRepository_DeleteAnnouncement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAnnouncement'
+type MockAnnouncementRepository_DeleteAnnouncement_Call struct {
+	*mock.Call
+}
+
+// DeleteAnnouncement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockAnnouncementRepository_Expecter) DeleteAnnouncement(ctx interface{}, id interface{}) *MockAnnouncementRepository_DeleteAnnouncement_Call {
+	return &MockAnnouncementRepository_DeleteAnnouncement_Call{Call: _e.mock.On("DeleteAnnouncement", ctx, id)}
+}
+
+func (_c *MockAnnouncementRepository_DeleteAnnouncement_Call) Run(run func(ctx context.Context, id int)) *MockAnnouncementRepository_DeleteAnnouncement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_DeleteAnnouncement_Call) Return(_a0 error) *MockAnnouncementRepository_DeleteAnnouncement_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_DeleteAnnouncement_Call) RunAndReturn(run func(context.Context, int) error) *MockAnnouncementRepository_DeleteAnnouncement_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetActiveAnnouncementsForUser provides a mock function with given fields: ctx, userID, at, page, limit
+func (_m *MockAnnouncementRepository) GetActiveAnnouncementsForUser(ctx context.Context, userID int, at time.Time, page int, limit int) ([]models.Announcement, int, error) {
+	ret := _m.Called(ctx, userID, at, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActiveAnnouncementsForUser")
+	}
+
+	var r0 []models.Announcement
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, int, int) ([]models.Announcement, int, error)); ok {
+		return rf(ctx, userID, at, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, int, int) []models.Announcement); ok {
+		r0 = rf(ctx, userID, at, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Announcement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, int, int) int); ok {
+		r1 = rf(ctx, userID, at, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, time.Time, int, int) error); ok {
+		r2 = rf(ctx, userID, at, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockAnnouncementRepository_GetActiveAnnouncementsForUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetActiveAnnouncementsForUser'
+type MockAnnouncementRepository_GetActiveAnnouncementsForUser_Call struct {
+	*mock.Call
+}
+
+// GetActiveAnnouncementsForUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - at time.Time
+//   - page int
+//   - limit int
+func (_e *MockAnnouncementRepository_Expecter) GetActiveAnnouncementsForUser(ctx interface{}, userID interface{}, at interface{}, page interface{}, limit interface{}) *MockAnnouncementRepository_GetActiveAnnouncementsForUser_Call {
+	return &MockAnnouncementRepository_GetActiveAnnouncementsForUser_Call{Call: _e.mock.On("GetActiveAnnouncementsForUser", ctx, userID, at, page, limit)}
+}
+
+func (_c *MockAnnouncementRepository_GetActiveAnnouncementsForUser_Call) Run(run func(ctx context.Context, userID int, at time.Time, page int, limit int)) *MockAnnouncementRepository_GetActiveAnnouncementsForUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(int), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_GetActiveAnnouncementsForUser_Call) Return(_a0 []models.Announcement, _a1 int, _a2 error) *MockAnnouncementRepository_GetActiveAnnouncementsForUser_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_GetActiveAnnouncementsForUser_Call) RunAndReturn(run func(context.Context, int, time.Time, int, int) ([]models.Announcement, int, error)) *MockAnnouncementRepository_GetActiveAnnouncementsForUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllAnnouncements provides a mock function with given fields: ctx, page, limit
+func (_m *MockAnnouncementRepository) GetAllAnnouncements(ctx context.Context, page int, limit int) ([]models.Announcement, int, error) {
+	ret := _m.Called(ctx, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllAnnouncements")
+	}
+
+	var r0 []models.Announcement
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.Announcement, int, error)); ok {
+		return rf(ctx, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.Announcement); ok {
+		r0 = rf(ctx, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Announcement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = rf(ctx, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockAnnouncementRepository_GetAllAnnouncements_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllAnnouncements'
+type MockAnnouncementRepository_GetAllAnnouncements_Call struct {
+	*mock.Call
+}
+
+// GetAllAnnouncements is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - limit int
+func (_e *MockAnnouncementRepository_Expecter) GetAllAnnouncements(ctx interface{}, page interface{}, limit interface{}) *MockAnnouncementRepository_GetAllAnnouncements_Call {
+	return &MockAnnouncementRepository_GetAllAnnouncements_Call{Call: _e.mock.On("GetAllAnnouncements", ctx, page, limit)}
+}
+
+func (_c *MockAnnouncementRepository_GetAllAnnouncements_Call) Run(run func(ctx context.Context, page int, limit int)) *MockAnnouncementRepository_GetAllAnnouncements_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_GetAllAnnouncements_Call) Return(_a0 []models.Announcement, _a1 int, _a2 error) *MockAnnouncementRepository_GetAllAnnouncements_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_GetAllAnnouncements_Call) RunAndReturn(run func(context.Context, int, int) ([]models.Announcement, int, error)) *MockAnnouncementRepository_GetAllAnnouncements_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAnnouncementByID provides a mock function with given fields: ctx, id
+func (_m *MockAnnouncementRepository) GetAnnouncementByID(ctx context.Context, id int) (*models.Announcement, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAnnouncementByID")
+	}
+
+	var r0 *models.Announcement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Announcement, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Announcement); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Announcement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAnnouncementRepository_GetAnnouncementByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAnnouncementByID'
+type MockAnnouncementRepository_GetAnnouncementByID_Call struct {
+	*mock.Call
+}
+
+// GetAnnouncementByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockAnnouncementRepository_Expecter) GetAnnouncementByID(ctx interface{}, id interface{}) *MockAnnouncementRepository_GetAnnouncementByID_Call {
+	return &MockAnnouncementRepository_GetAnnouncementByID_Call{Call: _e.mock.On("GetAnnouncementByID", ctx, id)}
+}
+
+func (_c *MockAnnouncementRepository_GetAnnouncementByID_Call) Run(run func(ctx context.Context, id int)) *MockAnnouncementRepository_GetAnnouncementByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_GetAnnouncementByID_Call) Return(_a0 *models.Announcement, _a1 error) *MockAnnouncementRepository_GetAnnouncementByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_GetAnnouncementByID_Call) RunAndReturn(run func(context.Context, int) (*models.Announcement, error)) *MockAnnouncementRepository_GetAnnouncementByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAnnouncement provides a mock function with given fields: ctx, announcement
+func (_m *MockAnnouncementRepository) UpdateAnnouncement(ctx context.Context, announcement *models.Announcement) error {
+	ret := _m.Called(ctx, announcement)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAnnouncement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Announcement) error); ok {
+		r0 = rf(ctx, announcement)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAnnouncementRepository_UpdateAnnouncement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAnnouncement'
+type MockAnnouncementRepository_UpdateAnnouncement_Call struct {
+	*mock.Call
+}
+
+// UpdateAnnouncement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - announcement *models.Announcement
+func (_e *MockAnnouncementRepository_Expecter) UpdateAnnouncement(ctx interface{}, announcement interface{}) *MockAnnouncementRepository_UpdateAnnouncement_Call {
+	return &MockAnnouncementRepository_UpdateAnnouncement_Call{Call: _e.mock.On("UpdateAnnouncement", ctx, announcement)}
+}
+
+func (_c *MockAnnouncementRepository_UpdateAnnouncement_Call) Run(run func(ctx context.Context, announcement *models.Announcement)) *MockAnnouncementRepository_UpdateAnnouncement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Announcement))
+	})
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_UpdateAnnouncement_Call) Return(_a0 error) *MockAnnouncementRepository_UpdateAnnouncement_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAnnouncementRepository_UpdateAnnouncement_Call) RunAndReturn(run func(context.Context, *models.Announcement) error) *MockAnnouncementRepository_UpdateAnnouncement_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAnnouncementRepository creates a new instance of MockAnnouncementRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAnnouncementRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAnnouncementRepository {
+	mock := &MockAnnouncementRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
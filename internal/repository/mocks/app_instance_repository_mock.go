@@ -0,0 +1,249 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockAppInstanceRepository is an autogenerated mock type for the AppInstanceRepository type
+type MockAppInstanceRepository struct {
+	mock.Mock
+}
+
+type MockAppInstanceRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAppInstanceRepository) EXPECT() *MockAppInstanceRepository_Expecter {
+	return &MockAppInstanceRepository_Expecter{mock: &_m.Mock}
+}
+
+// DeleteAppInstance provides a mock function with given fields: ctx, instanceID
+func (_m *MockAppInstanceRepository) DeleteAppInstance(ctx context.Context, instanceID string) error {
+	ret := _m.Called(ctx, instanceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAppInstance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, instanceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAppInstanceRepository_DeleteAppInstance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAppInstance'
+type MockAppInstanceRepository_DeleteAppInstance_Call struct {
+	*mock.Call
+}
+
+// DeleteAppInstance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - instanceID string
+func (_e *MockAppInstanceRepository_Expecter) DeleteAppInstance(ctx interface{}, instanceID interface{}) *MockAppInstanceRepository_DeleteAppInstance_Call {
+	return &MockAppInstanceRepository_DeleteAppInstance_Call{Call: _e.mock.On("DeleteAppInstance", ctx, instanceID)}
+}
+
+func (_c *MockAppInstanceRepository_DeleteAppInstance_Call) Run(run func(ctx context.Context, instanceID string)) *MockAppInstanceRepository_DeleteAppInstance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockAppInstanceRepository_DeleteAppInstance_Call) Return(_a0 error) *MockAppInstanceRepository_DeleteAppInstance_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAppInstanceRepository_DeleteAppInstance_Call) RunAndReturn(run func(context.Context, string) error) *MockAppInstanceRepository_DeleteAppInstance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteStaleAppInstances provides a mock function with given fields: ctx, seenBefore
+func (_m *MockAppInstanceRepository) DeleteStaleAppInstances(ctx context.Context, seenBefore time.Time) (int, error) {
+	ret := _m.Called(ctx, seenBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteStaleAppInstances")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, seenBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, seenBefore)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, seenBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAppInstanceRepository_DeleteStaleAppInstances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteStaleAppInstances'
+type MockAppInstanceRepository_DeleteStaleAppInstances_Call struct {
+	*mock.Call
+}
+
+// DeleteStaleAppInstances is a helper method to define mock.On call
+//   - ctx context.Context
+//   - seenBefore time.Time
+func (_e *MockAppInstanceRepository_Expecter) DeleteStaleAppInstances(ctx interface{}, seenBefore interface{}) *MockAppInstanceRepository_DeleteStaleAppInstances_Call {
+	return &MockAppInstanceRepository_DeleteStaleAppInstances_Call{Call: _e.mock.On("DeleteStaleAppInstances", ctx, seenBefore)}
+}
+
+func (_c *MockAppInstanceRepository_DeleteStaleAppInstances_Call) Run(run func(ctx context.Context, seenBefore time.Time)) *MockAppInstanceRepository_DeleteStaleAppInstances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockAppInstanceRepository_DeleteStaleAppInstances_Call) Return(_a0 int, _a1 error) *MockAppInstanceRepository_DeleteStaleAppInstances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAppInstanceRepository_DeleteStaleAppInstances_Call) RunAndReturn(run func(context.Context, time.Time) (int, error)) *MockAppInstanceRepository_DeleteStaleAppInstances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAppInstances provides a mock function with given fields: ctx, seenSince
+func (_m *MockAppInstanceRepository) GetAppInstances(ctx context.Context, seenSince time.Time) ([]models.AppInstance, error) {
+	ret := _m.Called(ctx, seenSince)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAppInstances")
+	}
+
+	var r0 []models.AppInstance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]models.AppInstance, error)); ok {
+		return rf(ctx, seenSince)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []models.AppInstance); ok {
+		r0 = rf(ctx, seenSince)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AppInstance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, seenSince)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAppInstanceRepository_GetAppInstances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAppInstances'
+type MockAppInstanceRepository_GetAppInstances_Call struct {
+	*mock.Call
+}
+
+// GetAppInstances is a helper method to define mock.On call
+//   - ctx context.Context
+//   - seenSince time.Time
+func (_e *MockAppInstanceRepository_Expecter) GetAppInstances(ctx interface{}, seenSince interface{}) *MockAppInstanceRepository_GetAppInstances_Call {
+	return &MockAppInstanceRepository_GetAppInstances_Call{Call: _e.mock.On("GetAppInstances", ctx, seenSince)}
+}
+
+func (_c *MockAppInstanceRepository_GetAppInstances_Call) Run(run func(ctx context.Context, seenSince time.Time)) *MockAppInstanceRepository_GetAppInstances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockAppInstanceRepository_GetAppInstances_Call) Return(_a0 []models.AppInstance, _a1 error) *MockAppInstanceRepository_GetAppInstances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAppInstanceRepository_GetAppInstances_Call) RunAndReturn(run func(context.Context, time.Time) ([]models.AppInstance, error)) *MockAppInstanceRepository_GetAppInstances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertAppInstance provides a mock function with given fields: ctx, instance
+func (_m *MockAppInstanceRepository) UpsertAppInstance(ctx context.Context, instance *models.AppInstance) error {
+	ret := _m.Called(ctx, instance)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertAppInstance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AppInstance) error); ok {
+		r0 = rf(ctx, instance)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAppInstanceRepository_UpsertAppInstance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertAppInstance'
+type MockAppInstanceRepository_UpsertAppInstance_Call struct {
+	*mock.Call
+}
+
+// UpsertAppInstance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - instance *models.AppInstance
+func (_e *MockAppInstanceRepository_Expecter) UpsertAppInstance(ctx interface{}, instance interface{}) *MockAppInstanceRepository_UpsertAppInstance_Call {
+	return &MockAppInstanceRepository_UpsertAppInstance_Call{Call: _e.mock.On("UpsertAppInstance", ctx, instance)}
+}
+
+func (_c *MockAppInstanceRepository_UpsertAppInstance_Call) Run(run func(ctx context.Context, instance *models.AppInstance)) *MockAppInstanceRepository_UpsertAppInstance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.AppInstance))
+	})
+	return _c
+}
+
+func (_c *MockAppInstanceRepository_UpsertAppInstance_Call) Return(_a0 error) *MockAppInstanceRepository_UpsertAppInstance_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAppInstanceRepository_UpsertAppInstance_Call) RunAndReturn(run func(context.Context, *models.AppInstance) error) *MockAppInstanceRepository_UpsertAppInstance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAppInstanceRepository creates a new instance of MockAppInstanceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAppInstanceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAppInstanceRepository {
+	mock := &MockAppInstanceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
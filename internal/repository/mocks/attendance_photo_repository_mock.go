@@ -0,0 +1,469 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockAttendancePhotoRepository is an autogenerated mock type for the AttendancePhotoRepository type
+type MockAttendancePhotoRepository struct {
+	mock.Mock
+}
+
+type MockAttendancePhotoRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAttendancePhotoRepository) EXPECT() *MockAttendancePhotoRepository_Expecter {
+	return &MockAttendancePhotoRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateAttendancePhoto provides a mock function with given fields: ctx, photo
+func (_m *MockAttendancePhotoRepository) CreateAttendancePhoto(ctx context.Context, photo *models.AttendancePhoto) error {
+	ret := _m.Called(ctx, photo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAttendancePhoto")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AttendancePhoto) error); ok {
+		r0 = rf(ctx, photo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAttendancePhotoRepository_CreateAttendancePhoto_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAttendancePhoto'
+type MockAttendancePhotoRepository_CreateAttendancePhoto_Call struct {
+	*mock.Call
+}
+
+// CreateAttendancePhoto is a helper method to define mock.On call
+//   - ctx context.Context
+//   - photo *models.AttendancePhoto
+func (_e *MockAttendancePhotoRepository_Expecter) CreateAttendancePhoto(ctx interface{}, photo interface{}) *MockAttendancePhotoRepository_CreateAttendancePhoto_Call {
+	return &MockAttendancePhotoRepository_CreateAttendancePhoto_Call{Call: _e.mock.On("CreateAttendancePhoto", ctx, photo)}
+}
+
+func (_c *MockAttendancePhotoRepository_CreateAttendancePhoto_Call) Run(run func(ctx context.Context, photo *models.AttendancePhoto)) *MockAttendancePhotoRepository_CreateAttendancePhoto_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.AttendancePhoto))
+	})
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_CreateAttendancePhoto_Call) Return(_a0 error) *MockAttendancePhotoRepository_CreateAttendancePhoto_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_CreateAttendancePhoto_Call) RunAndReturn(run func(context.Context, *models.AttendancePhoto) error) *MockAttendancePhotoRepository_CreateAttendancePhoto_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAttendancePhoto provides a mock function with given fields: ctx, attendanceID
+func (_m *MockAttendancePhotoRepository) GetAttendancePhoto(ctx context.Context, attendanceID int) (*models.AttendancePhoto, error) {
+	ret := _m.Called(ctx, attendanceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttendancePhoto")
+	}
+
+	var r0 *models.AttendancePhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.AttendancePhoto, error)); ok {
+		return rf(ctx, attendanceID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.AttendancePhoto); ok {
+		r0 = rf(ctx, attendanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AttendancePhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, attendanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendancePhotoRepository_GetAttendancePhoto_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttendancePhoto'
+type MockAttendancePhotoRepository_GetAttendancePhoto_Call struct {
+	*mock.Call
+}
+
+// GetAttendancePhoto is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attendanceID int
+func (_e *MockAttendancePhotoRepository_Expecter) GetAttendancePhoto(ctx interface{}, attendanceID interface{}) *MockAttendancePhotoRepository_GetAttendancePhoto_Call {
+	return &MockAttendancePhotoRepository_GetAttendancePhoto_Call{Call: _e.mock.On("GetAttendancePhoto", ctx, attendanceID)}
+}
+
+func (_c *MockAttendancePhotoRepository_GetAttendancePhoto_Call) Run(run func(ctx context.Context, attendanceID int)) *MockAttendancePhotoRepository_GetAttendancePhoto_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_GetAttendancePhoto_Call) Return(_a0 *models.AttendancePhoto, _a1 error) *MockAttendancePhotoRepository_GetAttendancePhoto_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_GetAttendancePhoto_Call) RunAndReturn(run func(context.Context, int) (*models.AttendancePhoto, error)) *MockAttendancePhotoRepository_GetAttendancePhoto_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAttendancePhotoByID provides a mock function with given fields: ctx, id
+func (_m *MockAttendancePhotoRepository) GetAttendancePhotoByID(ctx context.Context, id int) (*models.AttendancePhoto, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttendancePhotoByID")
+	}
+
+	var r0 *models.AttendancePhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.AttendancePhoto, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.AttendancePhoto); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AttendancePhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendancePhotoRepository_GetAttendancePhotoByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttendancePhotoByID'
+type MockAttendancePhotoRepository_GetAttendancePhotoByID_Call struct {
+	*mock.Call
+}
+
+// GetAttendancePhotoByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockAttendancePhotoRepository_Expecter) GetAttendancePhotoByID(ctx interface{}, id interface{}) *MockAttendancePhotoRepository_GetAttendancePhotoByID_Call {
+	return &MockAttendancePhotoRepository_GetAttendancePhotoByID_Call{Call: _e.mock.On("GetAttendancePhotoByID", ctx, id)}
+}
+
+func (_c *MockAttendancePhotoRepository_GetAttendancePhotoByID_Call) Run(run func(ctx context.Context, id int)) *MockAttendancePhotoRepository_GetAttendancePhotoByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_GetAttendancePhotoByID_Call) Return(_a0 *models.AttendancePhoto, _a1 error) *MockAttendancePhotoRepository_GetAttendancePhotoByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_GetAttendancePhotoByID_Call) RunAndReturn(run func(context.Context, int) (*models.AttendancePhoto, error)) *MockAttendancePhotoRepository_GetAttendancePhotoByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetExpiredAttendancePhotos provides a mock function with given fields: ctx, capturedBefore, limit
+func (_m *MockAttendancePhotoRepository) GetExpiredAttendancePhotos(ctx context.Context, capturedBefore time.Time, limit int) ([]models.AttendancePhoto, error) {
+	ret := _m.Called(ctx, capturedBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExpiredAttendancePhotos")
+	}
+
+	var r0 []models.AttendancePhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.AttendancePhoto, error)); ok {
+		return rf(ctx, capturedBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.AttendancePhoto); ok {
+		r0 = rf(ctx, capturedBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AttendancePhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, capturedBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendancePhotoRepository_GetExpiredAttendancePhotos_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetExpiredAttendancePhotos'
+type MockAttendancePhotoRepository_GetExpiredAttendancePhotos_Call struct {
+	*mock.Call
+}
+
+// GetExpiredAttendancePhotos is a helper method to define mock.On call
+//   - ctx context.Context
+//   - capturedBefore time.Time
+//   - limit int
+func (_e *MockAttendancePhotoRepository_Expecter) GetExpiredAttendancePhotos(ctx interface{}, capturedBefore interface{}, limit interface{}) *MockAttendancePhotoRepository_GetExpiredAttendancePhotos_Call {
+	return &MockAttendancePhotoRepository_GetExpiredAttendancePhotos_Call{Call: _e.mock.On("GetExpiredAttendancePhotos", ctx, capturedBefore, limit)}
+}
+
+func (_c *MockAttendancePhotoRepository_GetExpiredAttendancePhotos_Call) Run(run func(ctx context.Context, capturedBefore time.Time, limit int)) *MockAttendancePhotoRepository_GetExpiredAttendancePhotos_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_GetExpiredAttendancePhotos_Call) Return(_a0 []models.AttendancePhoto, _a1 error) *MockAttendancePhotoRepository_GetExpiredAttendancePhotos_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_GetExpiredAttendancePhotos_Call) RunAndReturn(run func(context.Context, time.Time, int) ([]models.AttendancePhoto, error)) *MockAttendancePhotoRepository_GetExpiredAttendancePhotos_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPendingAttendancePhotos provides a mock function with given fields: ctx, limit
+func (_m *MockAttendancePhotoRepository) GetPendingAttendancePhotos(ctx context.Context, limit int) ([]models.AttendancePhoto, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingAttendancePhotos")
+	}
+
+	var r0 []models.AttendancePhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.AttendancePhoto, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.AttendancePhoto); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AttendancePhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendancePhotoRepository_GetPendingAttendancePhotos_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingAttendancePhotos'
+type MockAttendancePhotoRepository_GetPendingAttendancePhotos_Call struct {
+	*mock.Call
+}
+
+// GetPendingAttendancePhotos is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+func (_e *MockAttendancePhotoRepository_Expecter) GetPendingAttendancePhotos(ctx interface{}, limit interface{}) *MockAttendancePhotoRepository_GetPendingAttendancePhotos_Call {
+	return &MockAttendancePhotoRepository_GetPendingAttendancePhotos_Call{Call: _e.mock.On("GetPendingAttendancePhotos", ctx, limit)}
+}
+
+func (_c *MockAttendancePhotoRepository_GetPendingAttendancePhotos_Call) Run(run func(ctx context.Context, limit int)) *MockAttendancePhotoRepository_GetPendingAttendancePhotos_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_GetPendingAttendancePhotos_Call) Return(_a0 []models.AttendancePhoto, _a1 error) *MockAttendancePhotoRepository_GetPendingAttendancePhotos_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_GetPendingAttendancePhotos_Call) RunAndReturn(run func(context.Context, int) ([]models.AttendancePhoto, error)) *MockAttendancePhotoRepository_GetPendingAttendancePhotos_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkAttendancePhotoFailed provides a mock function with given fields: ctx, id, reason
+func (_m *MockAttendancePhotoRepository) MarkAttendancePhotoFailed(ctx context.Context, id int, reason string) error {
+	ret := _m.Called(ctx, id, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkAttendancePhotoFailed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, id, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAttendancePhotoRepository_MarkAttendancePhotoFailed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkAttendancePhotoFailed'
+type MockAttendancePhotoRepository_MarkAttendancePhotoFailed_Call struct {
+	*mock.Call
+}
+
+// MarkAttendancePhotoFailed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - reason string
+func (_e *MockAttendancePhotoRepository_Expecter) MarkAttendancePhotoFailed(ctx interface{}, id interface{}, reason interface{}) *MockAttendancePhotoRepository_MarkAttendancePhotoFailed_Call {
+	return &MockAttendancePhotoRepository_MarkAttendancePhotoFailed_Call{Call: _e.mock.On("MarkAttendancePhotoFailed", ctx, id, reason)}
+}
+
+func (_c *MockAttendancePhotoRepository_MarkAttendancePhotoFailed_Call) Run(run func(ctx context.Context, id int, reason string)) *MockAttendancePhotoRepository_MarkAttendancePhotoFailed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_MarkAttendancePhotoFailed_Call) Return(_a0 error) *MockAttendancePhotoRepository_MarkAttendancePhotoFailed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_MarkAttendancePhotoFailed_Call) RunAndReturn(run func(context.Context, int, string) error) *MockAttendancePhotoRepository_MarkAttendancePhotoFailed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkAttendancePhotoProcessed provides a mock function with given fields: ctx, id, originalKey, thumbnailKey, width, height
+func (_m *MockAttendancePhotoRepository) MarkAttendancePhotoProcessed(ctx context.Context, id int, originalKey string, thumbnailKey string, width int, height int) error {
+	ret := _m.Called(ctx, id, originalKey, thumbnailKey, width, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkAttendancePhotoProcessed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, int, int) error); ok {
+		r0 = rf(ctx, id, originalKey, thumbnailKey, width, height)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAttendancePhotoRepository_MarkAttendancePhotoProcessed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkAttendancePhotoProcessed'
+type MockAttendancePhotoRepository_MarkAttendancePhotoProcessed_Call struct {
+	*mock.Call
+}
+
+// MarkAttendancePhotoProcessed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - originalKey string
+//   - thumbnailKey string
+//   - width int
+//   - height int
+func (_e *MockAttendancePhotoRepository_Expecter) MarkAttendancePhotoProcessed(ctx interface{}, id interface{}, originalKey interface{}, thumbnailKey interface{}, width interface{}, height interface{}) *MockAttendancePhotoRepository_MarkAttendancePhotoProcessed_Call {
+	return &MockAttendancePhotoRepository_MarkAttendancePhotoProcessed_Call{Call: _e.mock.On("MarkAttendancePhotoProcessed", ctx, id, originalKey, thumbnailKey, width, height)}
+}
+
+func (_c *MockAttendancePhotoRepository_MarkAttendancePhotoProcessed_Call) Run(run func(ctx context.Context, id int, originalKey string, thumbnailKey string, width int, height int)) *MockAttendancePhotoRepository_MarkAttendancePhotoProcessed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string), args[4].(int), args[5].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_MarkAttendancePhotoProcessed_Call) Return(_a0 error) *MockAttendancePhotoRepository_MarkAttendancePhotoProcessed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_MarkAttendancePhotoProcessed_Call) RunAndReturn(run func(context.Context, int, string, string, int, int) error) *MockAttendancePhotoRepository_MarkAttendancePhotoProcessed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkAttendancePhotoPurged provides a mock function with given fields: ctx, id
+func (_m *MockAttendancePhotoRepository) MarkAttendancePhotoPurged(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkAttendancePhotoPurged")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAttendancePhotoRepository_MarkAttendancePhotoPurged_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkAttendancePhotoPurged'
+type MockAttendancePhotoRepository_MarkAttendancePhotoPurged_Call struct {
+	*mock.Call
+}
+
+// MarkAttendancePhotoPurged is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockAttendancePhotoRepository_Expecter) MarkAttendancePhotoPurged(ctx interface{}, id interface{}) *MockAttendancePhotoRepository_MarkAttendancePhotoPurged_Call {
+	return &MockAttendancePhotoRepository_MarkAttendancePhotoPurged_Call{Call: _e.mock.On("MarkAttendancePhotoPurged", ctx, id)}
+}
+
+func (_c *MockAttendancePhotoRepository_MarkAttendancePhotoPurged_Call) Run(run func(ctx context.Context, id int)) *MockAttendancePhotoRepository_MarkAttendancePhotoPurged_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_MarkAttendancePhotoPurged_Call) Return(_a0 error) *MockAttendancePhotoRepository_MarkAttendancePhotoPurged_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAttendancePhotoRepository_MarkAttendancePhotoPurged_Call) RunAndReturn(run func(context.Context, int) error) *MockAttendancePhotoRepository_MarkAttendancePhotoPurged_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAttendancePhotoRepository creates a new instance of MockAttendancePhotoRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAttendancePhotoRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAttendancePhotoRepository {
+	mock := &MockAttendancePhotoRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
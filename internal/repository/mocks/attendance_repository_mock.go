@@ -1,54 +1,1579 @@
+// Code generated by mockery. DO NOT EDIT.
+
 package mocks
 
 import (
-	"context"
-	"time"
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
 
-	"github.com/stretchr/testify/mock"
-	"github.com/rakaarfi/attendance-system-be/internal/models"
+	time "time"
 )
 
-// MockAttendanceRepository mocks the AttendanceRepository interface.
+// MockAttendanceRepository is an autogenerated mock type for the AttendanceRepository type
 type MockAttendanceRepository struct {
 	mock.Mock
 }
 
-func (m *MockAttendanceRepository) CreateCheckIn(ctx context.Context, userID int, checkInTime time.Time, notes *string) (int, error) {
-	// Use mock.Anything for time.Time if precise matching is hard/unnecessary
-	args := m.Called(ctx, userID, mock.AnythingOfType("time.Time"), notes)
-	return args.Int(0), args.Error(1)
+type MockAttendanceRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAttendanceRepository) EXPECT() *MockAttendanceRepository_Expecter {
+	return &MockAttendanceRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddAttendanceTags provides a mock function with given fields: ctx, attendanceID, tags
+func (_m *MockAttendanceRepository) AddAttendanceTags(ctx context.Context, attendanceID int, tags []string) error {
+	ret := _m.Called(ctx, attendanceID, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAttendanceTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string) error); ok {
+		r0 = rf(ctx, attendanceID, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAttendanceRepository_AddAttendanceTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddAttendanceTags'
+type MockAttendanceRepository_AddAttendanceTags_Call struct {
+	*mock.Call
+}
+
+// AddAttendanceTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attendanceID int
+//   - tags []string
+func (_e *MockAttendanceRepository_Expecter) AddAttendanceTags(ctx interface{}, attendanceID interface{}, tags interface{}) *MockAttendanceRepository_AddAttendanceTags_Call {
+	return &MockAttendanceRepository_AddAttendanceTags_Call{Call: _e.mock.On("AddAttendanceTags", ctx, attendanceID, tags)}
+}
+
+func (_c *MockAttendanceRepository_AddAttendanceTags_Call) Run(run func(ctx context.Context, attendanceID int, tags []string)) *MockAttendanceRepository_AddAttendanceTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_AddAttendanceTags_Call) Return(_a0 error) *MockAttendanceRepository_AddAttendanceTags_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_AddAttendanceTags_Call) RunAndReturn(run func(context.Context, int, []string) error) *MockAttendanceRepository_AddAttendanceTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CorrectAttendance provides a mock function with given fields: ctx, attendanceID, input, actorUserID
+func (_m *MockAttendanceRepository) CorrectAttendance(ctx context.Context, attendanceID int, input *models.AttendanceCorrectionInput, actorUserID int) (*models.Attendance, error) {
+	ret := _m.Called(ctx, attendanceID, input, actorUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CorrectAttendance")
+	}
+
+	var r0 *models.Attendance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.AttendanceCorrectionInput, int) (*models.Attendance, error)); ok {
+		return rf(ctx, attendanceID, input, actorUserID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.AttendanceCorrectionInput, int) *models.Attendance); ok {
+		r0 = rf(ctx, attendanceID, input, actorUserID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Attendance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.AttendanceCorrectionInput, int) error); ok {
+		r1 = rf(ctx, attendanceID, input, actorUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_CorrectAttendance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CorrectAttendance'
+type MockAttendanceRepository_CorrectAttendance_Call struct {
+	*mock.Call
+}
+
+// CorrectAttendance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attendanceID int
+//   - input *models.AttendanceCorrectionInput
+//   - actorUserID int
+func (_e *MockAttendanceRepository_Expecter) CorrectAttendance(ctx interface{}, attendanceID interface{}, input interface{}, actorUserID interface{}) *MockAttendanceRepository_CorrectAttendance_Call {
+	return &MockAttendanceRepository_CorrectAttendance_Call{Call: _e.mock.On("CorrectAttendance", ctx, attendanceID, input, actorUserID)}
+}
+
+func (_c *MockAttendanceRepository_CorrectAttendance_Call) Run(run func(ctx context.Context, attendanceID int, input *models.AttendanceCorrectionInput, actorUserID int)) *MockAttendanceRepository_CorrectAttendance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.AttendanceCorrectionInput), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_CorrectAttendance_Call) Return(_a0 *models.Attendance, _a1 error) *MockAttendanceRepository_CorrectAttendance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_CorrectAttendance_Call) RunAndReturn(run func(context.Context, int, *models.AttendanceCorrectionInput, int) (*models.Attendance, error)) *MockAttendanceRepository_CorrectAttendance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateCheckIn provides a mock function with given fields: ctx, userID, checkInTime, notes, scheduleID, projectID, mode
+func (_m *MockAttendanceRepository) CreateCheckIn(ctx context.Context, userID int, checkInTime time.Time, notes *string, scheduleID *int, projectID *int, mode string) (int, error) {
+	ret := _m.Called(ctx, userID, checkInTime, notes, scheduleID, projectID, mode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCheckIn")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, *string, *int, *int, string) (int, error)); ok {
+		return rf(ctx, userID, checkInTime, notes, scheduleID, projectID, mode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, *string, *int, *int, string) int); ok {
+		r0 = rf(ctx, userID, checkInTime, notes, scheduleID, projectID, mode)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, *string, *int, *int, string) error); ok {
+		r1 = rf(ctx, userID, checkInTime, notes, scheduleID, projectID, mode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_CreateCheckIn_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCheckIn'
+type MockAttendanceRepository_CreateCheckIn_Call struct {
+	*mock.Call
+}
+
+// CreateCheckIn is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - checkInTime time.Time
+//   - notes *string
+//   - scheduleID *int
+//   - projectID *int
+//   - mode string
+func (_e *MockAttendanceRepository_Expecter) CreateCheckIn(ctx interface{}, userID interface{}, checkInTime interface{}, notes interface{}, scheduleID interface{}, projectID interface{}, mode interface{}) *MockAttendanceRepository_CreateCheckIn_Call {
+	return &MockAttendanceRepository_CreateCheckIn_Call{Call: _e.mock.On("CreateCheckIn", ctx, userID, checkInTime, notes, scheduleID, projectID, mode)}
+}
+
+func (_c *MockAttendanceRepository_CreateCheckIn_Call) Run(run func(ctx context.Context, userID int, checkInTime time.Time, notes *string, scheduleID *int, projectID *int, mode string)) *MockAttendanceRepository_CreateCheckIn_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(*string), args[4].(*int), args[5].(*int), args[6].(string))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_CreateCheckIn_Call) Return(_a0 int, _a1 error) *MockAttendanceRepository_CreateCheckIn_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_CreateCheckIn_Call) RunAndReturn(run func(context.Context, int, time.Time, *string, *int, *int, string) (int, error)) *MockAttendanceRepository_CreateCheckIn_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAttendancePingsBefore provides a mock function with given fields: ctx, before, limit
+func (_m *MockAttendanceRepository) DeleteAttendancePingsBefore(ctx context.Context, before time.Time, limit int) (int, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAttendancePingsBefore")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) (int, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) int); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_DeleteAttendancePingsBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAttendancePingsBefore'
+type MockAttendanceRepository_DeleteAttendancePingsBefore_Call struct {
+	*mock.Call
+}
+
+// DeleteAttendancePingsBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+//   - limit int
+func (_e *MockAttendanceRepository_Expecter) DeleteAttendancePingsBefore(ctx interface{}, before interface{}, limit interface{}) *MockAttendanceRepository_DeleteAttendancePingsBefore_Call {
+	return &MockAttendanceRepository_DeleteAttendancePingsBefore_Call{Call: _e.mock.On("DeleteAttendancePingsBefore", ctx, before, limit)}
+}
+
+func (_c *MockAttendanceRepository_DeleteAttendancePingsBefore_Call) Run(run func(ctx context.Context, before time.Time, limit int)) *MockAttendanceRepository_DeleteAttendancePingsBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_DeleteAttendancePingsBefore_Call) Return(_a0 int, _a1 error) *MockAttendanceRepository_DeleteAttendancePingsBefore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_DeleteAttendancePingsBefore_Call) RunAndReturn(run func(context.Context, time.Time, int) (int, error)) *MockAttendanceRepository_DeleteAttendancePingsBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportAttendancesByUser provides a mock function with given fields: ctx, userID
+func (_m *MockAttendanceRepository) ExportAttendancesByUser(ctx context.Context, userID int) ([]models.Attendance, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportAttendancesByUser")
+	}
+
+	var r0 []models.Attendance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Attendance, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Attendance); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Attendance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_ExportAttendancesByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportAttendancesByUser'
+type MockAttendanceRepository_ExportAttendancesByUser_Call struct {
+	*mock.Call
+}
+
+// ExportAttendancesByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockAttendanceRepository_Expecter) ExportAttendancesByUser(ctx interface{}, userID interface{}) *MockAttendanceRepository_ExportAttendancesByUser_Call {
+	return &MockAttendanceRepository_ExportAttendancesByUser_Call{Call: _e.mock.On("ExportAttendancesByUser", ctx, userID)}
+}
+
+func (_c *MockAttendanceRepository_ExportAttendancesByUser_Call) Run(run func(ctx context.Context, userID int)) *MockAttendanceRepository_ExportAttendancesByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_ExportAttendancesByUser_Call) Return(_a0 []models.Attendance, _a1 error) *MockAttendanceRepository_ExportAttendancesByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_ExportAttendancesByUser_Call) RunAndReturn(run func(context.Context, int) ([]models.Attendance, error)) *MockAttendanceRepository_ExportAttendancesByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllAttendances provides a mock function with given fields: ctx, startDate, endDate, filter, page, limit
+func (_m *MockAttendanceRepository) GetAllAttendances(ctx context.Context, startDate time.Time, endDate time.Time, filter models.AttendanceReportFilter, page int, limit int) ([]models.Attendance, int, error) {
+	ret := _m.Called(ctx, startDate, endDate, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllAttendances")
+	}
+
+	var r0 []models.Attendance
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, models.AttendanceReportFilter, int, int) ([]models.Attendance, int, error)); ok {
+		return rf(ctx, startDate, endDate, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, models.AttendanceReportFilter, int, int) []models.Attendance); ok {
+		r0 = rf(ctx, startDate, endDate, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Attendance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, models.AttendanceReportFilter, int, int) int); ok {
+		r1 = rf(ctx, startDate, endDate, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, time.Time, time.Time, models.AttendanceReportFilter, int, int) error); ok {
+		r2 = rf(ctx, startDate, endDate, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockAttendanceRepository_GetAllAttendances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllAttendances'
+type MockAttendanceRepository_GetAllAttendances_Call struct {
+	*mock.Call
+}
+
+// GetAllAttendances is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startDate time.Time
+//   - endDate time.Time
+//   - filter models.AttendanceReportFilter
+//   - page int
+//   - limit int
+func (_e *MockAttendanceRepository_Expecter) GetAllAttendances(ctx interface{}, startDate interface{}, endDate interface{}, filter interface{}, page interface{}, limit interface{}) *MockAttendanceRepository_GetAllAttendances_Call {
+	return &MockAttendanceRepository_GetAllAttendances_Call{Call: _e.mock.On("GetAllAttendances", ctx, startDate, endDate, filter, page, limit)}
+}
+
+func (_c *MockAttendanceRepository_GetAllAttendances_Call) Run(run func(ctx context.Context, startDate time.Time, endDate time.Time, filter models.AttendanceReportFilter, page int, limit int)) *MockAttendanceRepository_GetAllAttendances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time), args[3].(models.AttendanceReportFilter), args[4].(int), args[5].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAllAttendances_Call) Return(_a0 []models.Attendance, _a1 int, _a2 error) *MockAttendanceRepository_GetAllAttendances_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAllAttendances_Call) RunAndReturn(run func(context.Context, time.Time, time.Time, models.AttendanceReportFilter, int, int) ([]models.Attendance, int, error)) *MockAttendanceRepository_GetAllAttendances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAttendanceByID provides a mock function with given fields: ctx, attendanceID
+func (_m *MockAttendanceRepository) GetAttendanceByID(ctx context.Context, attendanceID int) (*models.Attendance, error) {
+	ret := _m.Called(ctx, attendanceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttendanceByID")
+	}
+
+	var r0 *models.Attendance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Attendance, error)); ok {
+		return rf(ctx, attendanceID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Attendance); ok {
+		r0 = rf(ctx, attendanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Attendance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, attendanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_GetAttendanceByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttendanceByID'
+type MockAttendanceRepository_GetAttendanceByID_Call struct {
+	*mock.Call
+}
+
+// GetAttendanceByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attendanceID int
+func (_e *MockAttendanceRepository_Expecter) GetAttendanceByID(ctx interface{}, attendanceID interface{}) *MockAttendanceRepository_GetAttendanceByID_Call {
+	return &MockAttendanceRepository_GetAttendanceByID_Call{Call: _e.mock.On("GetAttendanceByID", ctx, attendanceID)}
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceByID_Call) Run(run func(ctx context.Context, attendanceID int)) *MockAttendanceRepository_GetAttendanceByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceByID_Call) Return(_a0 *models.Attendance, _a1 error) *MockAttendanceRepository_GetAttendanceByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceByID_Call) RunAndReturn(run func(context.Context, int) (*models.Attendance, error)) *MockAttendanceRepository_GetAttendanceByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAttendanceChanges provides a mock function with given fields: ctx, cursor, limit
+func (_m *MockAttendanceRepository) GetAttendanceChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+	ret := _m.Called(ctx, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttendanceChanges")
+	}
+
+	var r0 *models.SyncBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.SyncCursor, int) (*models.SyncBatch, error)); ok {
+		return rf(ctx, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.SyncCursor, int) *models.SyncBatch); ok {
+		r0 = rf(ctx, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SyncBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.SyncCursor, int) error); ok {
+		r1 = rf(ctx, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_GetAttendanceChanges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttendanceChanges'
+type MockAttendanceRepository_GetAttendanceChanges_Call struct {
+	*mock.Call
+}
+
+// GetAttendanceChanges is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cursor models.SyncCursor
+//   - limit int
+func (_e *MockAttendanceRepository_Expecter) GetAttendanceChanges(ctx interface{}, cursor interface{}, limit interface{}) *MockAttendanceRepository_GetAttendanceChanges_Call {
+	return &MockAttendanceRepository_GetAttendanceChanges_Call{Call: _e.mock.On("GetAttendanceChanges", ctx, cursor, limit)}
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceChanges_Call) Run(run func(ctx context.Context, cursor models.SyncCursor, limit int)) *MockAttendanceRepository_GetAttendanceChanges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.SyncCursor), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceChanges_Call) Return(_a0 *models.SyncBatch, _a1 error) *MockAttendanceRepository_GetAttendanceChanges_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceChanges_Call) RunAndReturn(run func(context.Context, models.SyncCursor, int) (*models.SyncBatch, error)) *MockAttendanceRepository_GetAttendanceChanges_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAttendanceEvents provides a mock function with given fields: ctx, attendanceID
+func (_m *MockAttendanceRepository) GetAttendanceEvents(ctx context.Context, attendanceID int) ([]models.AttendanceEvent, error) {
+	ret := _m.Called(ctx, attendanceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttendanceEvents")
+	}
+
+	var r0 []models.AttendanceEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.AttendanceEvent, error)); ok {
+		return rf(ctx, attendanceID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.AttendanceEvent); ok {
+		r0 = rf(ctx, attendanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AttendanceEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, attendanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_GetAttendanceEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttendanceEvents'
+type MockAttendanceRepository_GetAttendanceEvents_Call struct {
+	*mock.Call
+}
+
+// GetAttendanceEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attendanceID int
+func (_e *MockAttendanceRepository_Expecter) GetAttendanceEvents(ctx interface{}, attendanceID interface{}) *MockAttendanceRepository_GetAttendanceEvents_Call {
+	return &MockAttendanceRepository_GetAttendanceEvents_Call{Call: _e.mock.On("GetAttendanceEvents", ctx, attendanceID)}
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceEvents_Call) Run(run func(ctx context.Context, attendanceID int)) *MockAttendanceRepository_GetAttendanceEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceEvents_Call) Return(_a0 []models.AttendanceEvent, _a1 error) *MockAttendanceRepository_GetAttendanceEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceEvents_Call) RunAndReturn(run func(context.Context, int) ([]models.AttendanceEvent, error)) *MockAttendanceRepository_GetAttendanceEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAttendancePings provides a mock function with given fields: ctx, attendanceID
+func (_m *MockAttendanceRepository) GetAttendancePings(ctx context.Context, attendanceID int) ([]models.AttendancePing, error) {
+	ret := _m.Called(ctx, attendanceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttendancePings")
+	}
+
+	var r0 []models.AttendancePing
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.AttendancePing, error)); ok {
+		return rf(ctx, attendanceID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.AttendancePing); ok {
+		r0 = rf(ctx, attendanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AttendancePing)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, attendanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_GetAttendancePings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttendancePings'
+type MockAttendanceRepository_GetAttendancePings_Call struct {
+	*mock.Call
+}
+
+// GetAttendancePings is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attendanceID int
+func (_e *MockAttendanceRepository_Expecter) GetAttendancePings(ctx interface{}, attendanceID interface{}) *MockAttendanceRepository_GetAttendancePings_Call {
+	return &MockAttendanceRepository_GetAttendancePings_Call{Call: _e.mock.On("GetAttendancePings", ctx, attendanceID)}
+}
+
+func (_c *MockAttendanceRepository_GetAttendancePings_Call) Run(run func(ctx context.Context, attendanceID int)) *MockAttendanceRepository_GetAttendancePings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendancePings_Call) Return(_a0 []models.AttendancePing, _a1 error) *MockAttendanceRepository_GetAttendancePings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendancePings_Call) RunAndReturn(run func(context.Context, int) ([]models.AttendancePing, error)) *MockAttendanceRepository_GetAttendancePings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAttendanceSegments provides a mock function with given fields: ctx, attendanceID
+func (_m *MockAttendanceRepository) GetAttendanceSegments(ctx context.Context, attendanceID int) ([]models.AttendanceSegment, error) {
+	ret := _m.Called(ctx, attendanceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttendanceSegments")
+	}
+
+	var r0 []models.AttendanceSegment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.AttendanceSegment, error)); ok {
+		return rf(ctx, attendanceID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.AttendanceSegment); ok {
+		r0 = rf(ctx, attendanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AttendanceSegment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, attendanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_GetAttendanceSegments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttendanceSegments'
+type MockAttendanceRepository_GetAttendanceSegments_Call struct {
+	*mock.Call
+}
+
+// GetAttendanceSegments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attendanceID int
+func (_e *MockAttendanceRepository_Expecter) GetAttendanceSegments(ctx interface{}, attendanceID interface{}) *MockAttendanceRepository_GetAttendanceSegments_Call {
+	return &MockAttendanceRepository_GetAttendanceSegments_Call{Call: _e.mock.On("GetAttendanceSegments", ctx, attendanceID)}
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceSegments_Call) Run(run func(ctx context.Context, attendanceID int)) *MockAttendanceRepository_GetAttendanceSegments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceSegments_Call) Return(_a0 []models.AttendanceSegment, _a1 error) *MockAttendanceRepository_GetAttendanceSegments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendanceSegments_Call) RunAndReturn(run func(context.Context, int) ([]models.AttendanceSegment, error)) *MockAttendanceRepository_GetAttendanceSegments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAttendancesByUser provides a mock function with given fields: ctx, userID, startDate, endDate, page, limit
+func (_m *MockAttendanceRepository) GetAttendancesByUser(ctx context.Context, userID int, startDate time.Time, endDate time.Time, page int, limit int) ([]models.Attendance, int, error) {
+	ret := _m.Called(ctx, userID, startDate, endDate, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttendancesByUser")
+	}
+
+	var r0 []models.Attendance
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time, int, int) ([]models.Attendance, int, error)); ok {
+		return rf(ctx, userID, startDate, endDate, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time, int, int) []models.Attendance); ok {
+		r0 = rf(ctx, userID, startDate, endDate, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Attendance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, time.Time, int, int) int); ok {
+		r1 = rf(ctx, userID, startDate, endDate, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, time.Time, time.Time, int, int) error); ok {
+		r2 = rf(ctx, userID, startDate, endDate, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockAttendanceRepository_GetAttendancesByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttendancesByUser'
+type MockAttendanceRepository_GetAttendancesByUser_Call struct {
+	*mock.Call
+}
+
+// GetAttendancesByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - startDate time.Time
+//   - endDate time.Time
+//   - page int
+//   - limit int
+func (_e *MockAttendanceRepository_Expecter) GetAttendancesByUser(ctx interface{}, userID interface{}, startDate interface{}, endDate interface{}, page interface{}, limit interface{}) *MockAttendanceRepository_GetAttendancesByUser_Call {
+	return &MockAttendanceRepository_GetAttendancesByUser_Call{Call: _e.mock.On("GetAttendancesByUser", ctx, userID, startDate, endDate, page, limit)}
+}
+
+func (_c *MockAttendanceRepository_GetAttendancesByUser_Call) Run(run func(ctx context.Context, userID int, startDate time.Time, endDate time.Time, page int, limit int)) *MockAttendanceRepository_GetAttendancesByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(time.Time), args[4].(int), args[5].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendancesByUser_Call) Return(_a0 []models.Attendance, _a1 int, _a2 error) *MockAttendanceRepository_GetAttendancesByUser_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetAttendancesByUser_Call) RunAndReturn(run func(context.Context, int, time.Time, time.Time, int, int) ([]models.Attendance, int, error)) *MockAttendanceRepository_GetAttendancesByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFaceChecks provides a mock function with given fields: ctx, reviewStatus, page, limit
+func (_m *MockAttendanceRepository) GetFaceChecks(ctx context.Context, reviewStatus string, page int, limit int) ([]models.FaceCheck, int, error) {
+	ret := _m.Called(ctx, reviewStatus, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFaceChecks")
+	}
+
+	var r0 []models.FaceCheck
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]models.FaceCheck, int, error)); ok {
+		return rf(ctx, reviewStatus, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []models.FaceCheck); ok {
+		r0 = rf(ctx, reviewStatus, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.FaceCheck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
+		r1 = rf(ctx, reviewStatus, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = rf(ctx, reviewStatus, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockAttendanceRepository_GetFaceChecks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFaceChecks'
+type MockAttendanceRepository_GetFaceChecks_Call struct {
+	*mock.Call
+}
+
+// GetFaceChecks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - reviewStatus string
+//   - page int
+//   - limit int
+func (_e *MockAttendanceRepository_Expecter) GetFaceChecks(ctx interface{}, reviewStatus interface{}, page interface{}, limit interface{}) *MockAttendanceRepository_GetFaceChecks_Call {
+	return &MockAttendanceRepository_GetFaceChecks_Call{Call: _e.mock.On("GetFaceChecks", ctx, reviewStatus, page, limit)}
+}
+
+func (_c *MockAttendanceRepository_GetFaceChecks_Call) Run(run func(ctx context.Context, reviewStatus string, page int, limit int)) *MockAttendanceRepository_GetFaceChecks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetFaceChecks_Call) Return(_a0 []models.FaceCheck, _a1 int, _a2 error) *MockAttendanceRepository_GetFaceChecks_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetFaceChecks_Call) RunAndReturn(run func(context.Context, string, int, int) ([]models.FaceCheck, int, error)) *MockAttendanceRepository_GetFaceChecks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLastAttendance provides a mock function with given fields: ctx, userID
+func (_m *MockAttendanceRepository) GetLastAttendance(ctx context.Context, userID int) (*models.Attendance, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastAttendance")
+	}
+
+	var r0 *models.Attendance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Attendance, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Attendance); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Attendance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_GetLastAttendance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastAttendance'
+type MockAttendanceRepository_GetLastAttendance_Call struct {
+	*mock.Call
+}
+
+// GetLastAttendance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockAttendanceRepository_Expecter) GetLastAttendance(ctx interface{}, userID interface{}) *MockAttendanceRepository_GetLastAttendance_Call {
+	return &MockAttendanceRepository_GetLastAttendance_Call{Call: _e.mock.On("GetLastAttendance", ctx, userID)}
+}
+
+func (_c *MockAttendanceRepository_GetLastAttendance_Call) Run(run func(ctx context.Context, userID int)) *MockAttendanceRepository_GetLastAttendance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetLastAttendance_Call) Return(_a0 *models.Attendance, _a1 error) *MockAttendanceRepository_GetLastAttendance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetLastAttendance_Call) RunAndReturn(run func(context.Context, int) (*models.Attendance, error)) *MockAttendanceRepository_GetLastAttendance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetModeHours provides a mock function with given fields: ctx, startDate, endDate, userID
+func (_m *MockAttendanceRepository) GetModeHours(ctx context.Context, startDate time.Time, endDate time.Time, userID *int) ([]models.ModeHours, error) {
+	ret := _m.Called(ctx, startDate, endDate, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetModeHours")
+	}
+
+	var r0 []models.ModeHours
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, *int) ([]models.ModeHours, error)); ok {
+		return rf(ctx, startDate, endDate, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, *int) []models.ModeHours); ok {
+		r0 = rf(ctx, startDate, endDate, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ModeHours)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, *int) error); ok {
+		r1 = rf(ctx, startDate, endDate, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_GetModeHours_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetModeHours'
+type MockAttendanceRepository_GetModeHours_Call struct {
+	*mock.Call
+}
+
+// GetModeHours is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startDate time.Time
+//   - endDate time.Time
+//   - userID *int
+func (_e *MockAttendanceRepository_Expecter) GetModeHours(ctx interface{}, startDate interface{}, endDate interface{}, userID interface{}) *MockAttendanceRepository_GetModeHours_Call {
+	return &MockAttendanceRepository_GetModeHours_Call{Call: _e.mock.On("GetModeHours", ctx, startDate, endDate, userID)}
+}
+
+func (_c *MockAttendanceRepository_GetModeHours_Call) Run(run func(ctx context.Context, startDate time.Time, endDate time.Time, userID *int)) *MockAttendanceRepository_GetModeHours_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time), args[3].(*int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetModeHours_Call) Return(_a0 []models.ModeHours, _a1 error) *MockAttendanceRepository_GetModeHours_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetModeHours_Call) RunAndReturn(run func(context.Context, time.Time, time.Time, *int) ([]models.ModeHours, error)) *MockAttendanceRepository_GetModeHours_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOccupancy provides a mock function with given fields: ctx, startDate, endDate, granularity, timezone, mode
+func (_m *MockAttendanceRepository) GetOccupancy(ctx context.Context, startDate time.Time, endDate time.Time, granularity string, timezone string, mode *string) ([]models.OccupancyBucket, error) {
+	ret := _m.Called(ctx, startDate, endDate, granularity, timezone, mode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOccupancy")
+	}
+
+	var r0 []models.OccupancyBucket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, string, string, *string) ([]models.OccupancyBucket, error)); ok {
+		return rf(ctx, startDate, endDate, granularity, timezone, mode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, string, string, *string) []models.OccupancyBucket); ok {
+		r0 = rf(ctx, startDate, endDate, granularity, timezone, mode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OccupancyBucket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, string, string, *string) error); ok {
+		r1 = rf(ctx, startDate, endDate, granularity, timezone, mode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_GetOccupancy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOccupancy'
+type MockAttendanceRepository_GetOccupancy_Call struct {
+	*mock.Call
 }
 
-func (m *MockAttendanceRepository) GetLastAttendance(ctx context.Context, userID int) (*models.Attendance, error) {
-	args := m.Called(ctx, userID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+// GetOccupancy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startDate time.Time
+//   - endDate time.Time
+//   - granularity string
+//   - timezone string
+//   - mode *string
+func (_e *MockAttendanceRepository_Expecter) GetOccupancy(ctx interface{}, startDate interface{}, endDate interface{}, granularity interface{}, timezone interface{}, mode interface{}) *MockAttendanceRepository_GetOccupancy_Call {
+	return &MockAttendanceRepository_GetOccupancy_Call{Call: _e.mock.On("GetOccupancy", ctx, startDate, endDate, granularity, timezone, mode)}
+}
+
+func (_c *MockAttendanceRepository_GetOccupancy_Call) Run(run func(ctx context.Context, startDate time.Time, endDate time.Time, granularity string, timezone string, mode *string)) *MockAttendanceRepository_GetOccupancy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time), args[3].(string), args[4].(string), args[5].(*string))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetOccupancy_Call) Return(_a0 []models.OccupancyBucket, _a1 error) *MockAttendanceRepository_GetOccupancy_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetOccupancy_Call) RunAndReturn(run func(context.Context, time.Time, time.Time, string, string, *string) ([]models.OccupancyBucket, error)) *MockAttendanceRepository_GetOccupancy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProjectHours provides a mock function with given fields: ctx, startDate, endDate, userID
+func (_m *MockAttendanceRepository) GetProjectHours(ctx context.Context, startDate time.Time, endDate time.Time, userID *int) ([]models.ProjectHours, error) {
+	ret := _m.Called(ctx, startDate, endDate, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProjectHours")
+	}
+
+	var r0 []models.ProjectHours
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, *int) ([]models.ProjectHours, error)); ok {
+		return rf(ctx, startDate, endDate, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, *int) []models.ProjectHours); ok {
+		r0 = rf(ctx, startDate, endDate, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ProjectHours)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, *int) error); ok {
+		r1 = rf(ctx, startDate, endDate, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_GetProjectHours_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProjectHours'
+type MockAttendanceRepository_GetProjectHours_Call struct {
+	*mock.Call
+}
+
+// GetProjectHours is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startDate time.Time
+//   - endDate time.Time
+//   - userID *int
+func (_e *MockAttendanceRepository_Expecter) GetProjectHours(ctx interface{}, startDate interface{}, endDate interface{}, userID interface{}) *MockAttendanceRepository_GetProjectHours_Call {
+	return &MockAttendanceRepository_GetProjectHours_Call{Call: _e.mock.On("GetProjectHours", ctx, startDate, endDate, userID)}
+}
+
+func (_c *MockAttendanceRepository_GetProjectHours_Call) Run(run func(ctx context.Context, startDate time.Time, endDate time.Time, userID *int)) *MockAttendanceRepository_GetProjectHours_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time), args[3].(*int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetProjectHours_Call) Return(_a0 []models.ProjectHours, _a1 error) *MockAttendanceRepository_GetProjectHours_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetProjectHours_Call) RunAndReturn(run func(context.Context, time.Time, time.Time, *int) ([]models.ProjectHours, error)) *MockAttendanceRepository_GetProjectHours_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetQueuedCheckInFailures provides a mock function with given fields: ctx, resolved, page, limit
+func (_m *MockAttendanceRepository) GetQueuedCheckInFailures(ctx context.Context, resolved *bool, page int, limit int) ([]models.QueuedCheckInFailure, int, error) {
+	ret := _m.Called(ctx, resolved, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQueuedCheckInFailures")
+	}
+
+	var r0 []models.QueuedCheckInFailure
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *bool, int, int) ([]models.QueuedCheckInFailure, int, error)); ok {
+		return rf(ctx, resolved, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *bool, int, int) []models.QueuedCheckInFailure); ok {
+		r0 = rf(ctx, resolved, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.QueuedCheckInFailure)
+		}
 	}
-	return args.Get(0).(*models.Attendance), args.Error(1)
+
+	if rf, ok := ret.Get(1).(func(context.Context, *bool, int, int) int); ok {
+		r1 = rf(ctx, resolved, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *bool, int, int) error); ok {
+		r2 = rf(ctx, resolved, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockAttendanceRepository_GetQueuedCheckInFailures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetQueuedCheckInFailures'
+type MockAttendanceRepository_GetQueuedCheckInFailures_Call struct {
+	*mock.Call
+}
+
+// GetQueuedCheckInFailures is a helper method to define mock.On call
+//   - ctx context.Context
+//   - resolved *bool
+//   - page int
+//   - limit int
+func (_e *MockAttendanceRepository_Expecter) GetQueuedCheckInFailures(ctx interface{}, resolved interface{}, page interface{}, limit interface{}) *MockAttendanceRepository_GetQueuedCheckInFailures_Call {
+	return &MockAttendanceRepository_GetQueuedCheckInFailures_Call{Call: _e.mock.On("GetQueuedCheckInFailures", ctx, resolved, page, limit)}
+}
+
+func (_c *MockAttendanceRepository_GetQueuedCheckInFailures_Call) Run(run func(ctx context.Context, resolved *bool, page int, limit int)) *MockAttendanceRepository_GetQueuedCheckInFailures_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*bool), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetQueuedCheckInFailures_Call) Return(_a0 []models.QueuedCheckInFailure, _a1 int, _a2 error) *MockAttendanceRepository_GetQueuedCheckInFailures_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
 }
 
-func (m *MockAttendanceRepository) UpdateCheckOut(ctx context.Context, attendanceID int, checkOutTime time.Time, notes *string) error {
-	// Use mock.Anything for time.Time
-	args := m.Called(ctx, attendanceID, mock.AnythingOfType("time.Time"), notes)
-	return args.Error(0)
+func (_c *MockAttendanceRepository_GetQueuedCheckInFailures_Call) RunAndReturn(run func(context.Context, *bool, int, int) ([]models.QueuedCheckInFailure, int, error)) *MockAttendanceRepository_GetQueuedCheckInFailures_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
-func (m *MockAttendanceRepository) GetAttendancesByUser(ctx context.Context, userID int, startDate, endDate time.Time) ([]models.Attendance, error) {
-	// Use mock.Anything for time boundaries if needed
-	args := m.Called(ctx, userID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"))
-	ret := args.Get(0)
-	if ret == nil {
-		return nil, args.Error(1)
+// GetTagHours provides a mock function with given fields: ctx, startDate, endDate, userID
+func (_m *MockAttendanceRepository) GetTagHours(ctx context.Context, startDate time.Time, endDate time.Time, userID *int) ([]models.TagHours, error) {
+	ret := _m.Called(ctx, startDate, endDate, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTagHours")
+	}
+
+	var r0 []models.TagHours
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, *int) ([]models.TagHours, error)); ok {
+		return rf(ctx, startDate, endDate, userID)
 	}
-	return ret.([]models.Attendance), args.Error(1)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, *int) []models.TagHours); ok {
+		r0 = rf(ctx, startDate, endDate, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TagHours)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, *int) error); ok {
+		r1 = rf(ctx, startDate, endDate, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_GetTagHours_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTagHours'
+type MockAttendanceRepository_GetTagHours_Call struct {
+	*mock.Call
+}
+
+// GetTagHours is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startDate time.Time
+//   - endDate time.Time
+//   - userID *int
+func (_e *MockAttendanceRepository_Expecter) GetTagHours(ctx interface{}, startDate interface{}, endDate interface{}, userID interface{}) *MockAttendanceRepository_GetTagHours_Call {
+	return &MockAttendanceRepository_GetTagHours_Call{Call: _e.mock.On("GetTagHours", ctx, startDate, endDate, userID)}
 }
 
-func (m *MockAttendanceRepository) GetAllAttendances(ctx context.Context, startDate, endDate time.Time) ([]models.Attendance, error) {
-	// Use mock.Anything for time boundaries
-	args := m.Called(ctx, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"))
-	ret := args.Get(0)
-	if ret == nil {
-		return nil, args.Error(1)
+func (_c *MockAttendanceRepository_GetTagHours_Call) Run(run func(ctx context.Context, startDate time.Time, endDate time.Time, userID *int)) *MockAttendanceRepository_GetTagHours_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time), args[3].(*int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetTagHours_Call) Return(_a0 []models.TagHours, _a1 error) *MockAttendanceRepository_GetTagHours_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_GetTagHours_Call) RunAndReturn(run func(context.Context, time.Time, time.Time, *int) ([]models.TagHours, error)) *MockAttendanceRepository_GetTagHours_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordAttendancePing provides a mock function with given fields: ctx, userID, ping, minInterval
+func (_m *MockAttendanceRepository) RecordAttendancePing(ctx context.Context, userID int, ping *models.AttendancePing, minInterval time.Duration) error {
+	ret := _m.Called(ctx, userID, ping, minInterval)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordAttendancePing")
 	}
-	return ret.([]models.Attendance), args.Error(1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.AttendancePing, time.Duration) error); ok {
+		r0 = rf(ctx, userID, ping, minInterval)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAttendanceRepository_RecordAttendancePing_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordAttendancePing'
+type MockAttendanceRepository_RecordAttendancePing_Call struct {
+	*mock.Call
+}
+
+// RecordAttendancePing is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - ping *models.AttendancePing
+//   - minInterval time.Duration
+func (_e *MockAttendanceRepository_Expecter) RecordAttendancePing(ctx interface{}, userID interface{}, ping interface{}, minInterval interface{}) *MockAttendanceRepository_RecordAttendancePing_Call {
+	return &MockAttendanceRepository_RecordAttendancePing_Call{Call: _e.mock.On("RecordAttendancePing", ctx, userID, ping, minInterval)}
+}
+
+func (_c *MockAttendanceRepository_RecordAttendancePing_Call) Run(run func(ctx context.Context, userID int, ping *models.AttendancePing, minInterval time.Duration)) *MockAttendanceRepository_RecordAttendancePing_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.AttendancePing), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_RecordAttendancePing_Call) Return(_a0 error) *MockAttendanceRepository_RecordAttendancePing_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_RecordAttendancePing_Call) RunAndReturn(run func(context.Context, int, *models.AttendancePing, time.Duration) error) *MockAttendanceRepository_RecordAttendancePing_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordFaceCheck provides a mock function with given fields: ctx, fc
+func (_m *MockAttendanceRepository) RecordFaceCheck(ctx context.Context, fc *models.FaceCheck) error {
+	ret := _m.Called(ctx, fc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordFaceCheck")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FaceCheck) error); ok {
+		r0 = rf(ctx, fc)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAttendanceRepository_RecordFaceCheck_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordFaceCheck'
+type MockAttendanceRepository_RecordFaceCheck_Call struct {
+	*mock.Call
+}
+
+// RecordFaceCheck is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fc *models.FaceCheck
+func (_e *MockAttendanceRepository_Expecter) RecordFaceCheck(ctx interface{}, fc interface{}) *MockAttendanceRepository_RecordFaceCheck_Call {
+	return &MockAttendanceRepository_RecordFaceCheck_Call{Call: _e.mock.On("RecordFaceCheck", ctx, fc)}
+}
+
+func (_c *MockAttendanceRepository_RecordFaceCheck_Call) Run(run func(ctx context.Context, fc *models.FaceCheck)) *MockAttendanceRepository_RecordFaceCheck_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.FaceCheck))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_RecordFaceCheck_Call) Return(_a0 error) *MockAttendanceRepository_RecordFaceCheck_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_RecordFaceCheck_Call) RunAndReturn(run func(context.Context, *models.FaceCheck) error) *MockAttendanceRepository_RecordFaceCheck_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordQueuedCheckInFailure provides a mock function with given fields: ctx, f
+func (_m *MockAttendanceRepository) RecordQueuedCheckInFailure(ctx context.Context, f *models.QueuedCheckInFailure) error {
+	ret := _m.Called(ctx, f)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordQueuedCheckInFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.QueuedCheckInFailure) error); ok {
+		r0 = rf(ctx, f)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAttendanceRepository_RecordQueuedCheckInFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordQueuedCheckInFailure'
+type MockAttendanceRepository_RecordQueuedCheckInFailure_Call struct {
+	*mock.Call
+}
+
+// RecordQueuedCheckInFailure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - f *models.QueuedCheckInFailure
+func (_e *MockAttendanceRepository_Expecter) RecordQueuedCheckInFailure(ctx interface{}, f interface{}) *MockAttendanceRepository_RecordQueuedCheckInFailure_Call {
+	return &MockAttendanceRepository_RecordQueuedCheckInFailure_Call{Call: _e.mock.On("RecordQueuedCheckInFailure", ctx, f)}
+}
+
+func (_c *MockAttendanceRepository_RecordQueuedCheckInFailure_Call) Run(run func(ctx context.Context, f *models.QueuedCheckInFailure)) *MockAttendanceRepository_RecordQueuedCheckInFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.QueuedCheckInFailure))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_RecordQueuedCheckInFailure_Call) Return(_a0 error) *MockAttendanceRepository_RecordQueuedCheckInFailure_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_RecordQueuedCheckInFailure_Call) RunAndReturn(run func(context.Context, *models.QueuedCheckInFailure) error) *MockAttendanceRepository_RecordQueuedCheckInFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResolveQueuedCheckInFailure provides a mock function with given fields: ctx, id, adminID, note
+func (_m *MockAttendanceRepository) ResolveQueuedCheckInFailure(ctx context.Context, id int, adminID int, note string) (*models.QueuedCheckInFailure, error) {
+	ret := _m.Called(ctx, id, adminID, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveQueuedCheckInFailure")
+	}
+
+	var r0 *models.QueuedCheckInFailure
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) (*models.QueuedCheckInFailure, error)); ok {
+		return rf(ctx, id, adminID, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) *models.QueuedCheckInFailure); ok {
+		r0 = rf(ctx, id, adminID, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.QueuedCheckInFailure)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = rf(ctx, id, adminID, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_ResolveQueuedCheckInFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveQueuedCheckInFailure'
+type MockAttendanceRepository_ResolveQueuedCheckInFailure_Call struct {
+	*mock.Call
+}
+
+// ResolveQueuedCheckInFailure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - adminID int
+//   - note string
+func (_e *MockAttendanceRepository_Expecter) ResolveQueuedCheckInFailure(ctx interface{}, id interface{}, adminID interface{}, note interface{}) *MockAttendanceRepository_ResolveQueuedCheckInFailure_Call {
+	return &MockAttendanceRepository_ResolveQueuedCheckInFailure_Call{Call: _e.mock.On("ResolveQueuedCheckInFailure", ctx, id, adminID, note)}
+}
+
+func (_c *MockAttendanceRepository_ResolveQueuedCheckInFailure_Call) Run(run func(ctx context.Context, id int, adminID int, note string)) *MockAttendanceRepository_ResolveQueuedCheckInFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_ResolveQueuedCheckInFailure_Call) Return(_a0 *models.QueuedCheckInFailure, _a1 error) *MockAttendanceRepository_ResolveQueuedCheckInFailure_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_ResolveQueuedCheckInFailure_Call) RunAndReturn(run func(context.Context, int, int, string) (*models.QueuedCheckInFailure, error)) *MockAttendanceRepository_ResolveQueuedCheckInFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReviewFaceCheck provides a mock function with given fields: ctx, attendanceID, status, reviewerID
+func (_m *MockAttendanceRepository) ReviewFaceCheck(ctx context.Context, attendanceID int, status string, reviewerID int) (*models.FaceCheck, error) {
+	ret := _m.Called(ctx, attendanceID, status, reviewerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReviewFaceCheck")
+	}
+
+	var r0 *models.FaceCheck
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, int) (*models.FaceCheck, error)); ok {
+		return rf(ctx, attendanceID, status, reviewerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, int) *models.FaceCheck); ok {
+		r0 = rf(ctx, attendanceID, status, reviewerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FaceCheck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, int) error); ok {
+		r1 = rf(ctx, attendanceID, status, reviewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_ReviewFaceCheck_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReviewFaceCheck'
+type MockAttendanceRepository_ReviewFaceCheck_Call struct {
+	*mock.Call
+}
+
+// ReviewFaceCheck is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attendanceID int
+//   - status string
+//   - reviewerID int
+func (_e *MockAttendanceRepository_Expecter) ReviewFaceCheck(ctx interface{}, attendanceID interface{}, status interface{}, reviewerID interface{}) *MockAttendanceRepository_ReviewFaceCheck_Call {
+	return &MockAttendanceRepository_ReviewFaceCheck_Call{Call: _e.mock.On("ReviewFaceCheck", ctx, attendanceID, status, reviewerID)}
+}
+
+func (_c *MockAttendanceRepository_ReviewFaceCheck_Call) Run(run func(ctx context.Context, attendanceID int, status string, reviewerID int)) *MockAttendanceRepository_ReviewFaceCheck_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_ReviewFaceCheck_Call) Return(_a0 *models.FaceCheck, _a1 error) *MockAttendanceRepository_ReviewFaceCheck_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_ReviewFaceCheck_Call) RunAndReturn(run func(context.Context, int, string, int) (*models.FaceCheck, error)) *MockAttendanceRepository_ReviewFaceCheck_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SwitchProject provides a mock function with given fields: ctx, userID, at, projectID
+func (_m *MockAttendanceRepository) SwitchProject(ctx context.Context, userID int, at time.Time, projectID int) (*models.AttendanceSegment, error) {
+	ret := _m.Called(ctx, userID, at, projectID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SwitchProject")
+	}
+
+	var r0 *models.AttendanceSegment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, int) (*models.AttendanceSegment, error)); ok {
+		return rf(ctx, userID, at, projectID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, int) *models.AttendanceSegment); ok {
+		r0 = rf(ctx, userID, at, projectID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AttendanceSegment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, int) error); ok {
+		r1 = rf(ctx, userID, at, projectID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAttendanceRepository_SwitchProject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SwitchProject'
+type MockAttendanceRepository_SwitchProject_Call struct {
+	*mock.Call
+}
+
+// SwitchProject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - at time.Time
+//   - projectID int
+func (_e *MockAttendanceRepository_Expecter) SwitchProject(ctx interface{}, userID interface{}, at interface{}, projectID interface{}) *MockAttendanceRepository_SwitchProject_Call {
+	return &MockAttendanceRepository_SwitchProject_Call{Call: _e.mock.On("SwitchProject", ctx, userID, at, projectID)}
+}
+
+func (_c *MockAttendanceRepository_SwitchProject_Call) Run(run func(ctx context.Context, userID int, at time.Time, projectID int)) *MockAttendanceRepository_SwitchProject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_SwitchProject_Call) Return(_a0 *models.AttendanceSegment, _a1 error) *MockAttendanceRepository_SwitchProject_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_SwitchProject_Call) RunAndReturn(run func(context.Context, int, time.Time, int) (*models.AttendanceSegment, error)) *MockAttendanceRepository_SwitchProject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCheckOut provides a mock function with given fields: ctx, attendanceID, checkOutTime, notes
+func (_m *MockAttendanceRepository) UpdateCheckOut(ctx context.Context, attendanceID int, checkOutTime time.Time, notes *string) error {
+	ret := _m.Called(ctx, attendanceID, checkOutTime, notes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCheckOut")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, *string) error); ok {
+		r0 = rf(ctx, attendanceID, checkOutTime, notes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAttendanceRepository_UpdateCheckOut_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCheckOut'
+type MockAttendanceRepository_UpdateCheckOut_Call struct {
+	*mock.Call
+}
+
+// UpdateCheckOut is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attendanceID int
+//   - checkOutTime time.Time
+//   - notes *string
+func (_e *MockAttendanceRepository_Expecter) UpdateCheckOut(ctx interface{}, attendanceID interface{}, checkOutTime interface{}, notes interface{}) *MockAttendanceRepository_UpdateCheckOut_Call {
+	return &MockAttendanceRepository_UpdateCheckOut_Call{Call: _e.mock.On("UpdateCheckOut", ctx, attendanceID, checkOutTime, notes)}
+}
+
+func (_c *MockAttendanceRepository_UpdateCheckOut_Call) Run(run func(ctx context.Context, attendanceID int, checkOutTime time.Time, notes *string)) *MockAttendanceRepository_UpdateCheckOut_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(*string))
+	})
+	return _c
+}
+
+func (_c *MockAttendanceRepository_UpdateCheckOut_Call) Return(_a0 error) *MockAttendanceRepository_UpdateCheckOut_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAttendanceRepository_UpdateCheckOut_Call) RunAndReturn(run func(context.Context, int, time.Time, *string) error) *MockAttendanceRepository_UpdateCheckOut_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAttendanceRepository creates a new instance of MockAttendanceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAttendanceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAttendanceRepository {
+	mock := &MockAttendanceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
 }
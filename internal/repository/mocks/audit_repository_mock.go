@@ -0,0 +1,206 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAuditRepository is an autogenerated mock type for the AuditRepository type
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+type MockAuditRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuditRepository) EXPECT() *MockAuditRepository_Expecter {
+	return &MockAuditRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateAuditEntry provides a mock function with given fields: ctx, entry
+func (_m *MockAuditRepository) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAuditEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AuditEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAuditRepository_CreateAuditEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAuditEntry'
+type MockAuditRepository_CreateAuditEntry_Call struct {
+	*mock.Call
+}
+
+// CreateAuditEntry is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entry *models.AuditEntry
+func (_e *MockAuditRepository_Expecter) CreateAuditEntry(ctx interface{}, entry interface{}) *MockAuditRepository_CreateAuditEntry_Call {
+	return &MockAuditRepository_CreateAuditEntry_Call{Call: _e.mock.On("CreateAuditEntry", ctx, entry)}
+}
+
+func (_c *MockAuditRepository_CreateAuditEntry_Call) Run(run func(ctx context.Context, entry *models.AuditEntry)) *MockAuditRepository_CreateAuditEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.AuditEntry))
+	})
+	return _c
+}
+
+func (_c *MockAuditRepository_CreateAuditEntry_Call) Return(_a0 error) *MockAuditRepository_CreateAuditEntry_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAuditRepository_CreateAuditEntry_Call) RunAndReturn(run func(context.Context, *models.AuditEntry) error) *MockAuditRepository_CreateAuditEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLoginHistoryMatch provides a mock function with given fields: ctx, userID, device, country
+func (_m *MockAuditRepository) GetLoginHistoryMatch(ctx context.Context, userID int, device string, country string) (*models.LoginHistoryMatch, error) {
+	ret := _m.Called(ctx, userID, device, country)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginHistoryMatch")
+	}
+
+	var r0 *models.LoginHistoryMatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) (*models.LoginHistoryMatch, error)); ok {
+		return rf(ctx, userID, device, country)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) *models.LoginHistoryMatch); ok {
+		r0 = rf(ctx, userID, device, country)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.LoginHistoryMatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string) error); ok {
+		r1 = rf(ctx, userID, device, country)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAuditRepository_GetLoginHistoryMatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginHistoryMatch'
+type MockAuditRepository_GetLoginHistoryMatch_Call struct {
+	*mock.Call
+}
+
+// GetLoginHistoryMatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - device string
+//   - country string
+func (_e *MockAuditRepository_Expecter) GetLoginHistoryMatch(ctx interface{}, userID interface{}, device interface{}, country interface{}) *MockAuditRepository_GetLoginHistoryMatch_Call {
+	return &MockAuditRepository_GetLoginHistoryMatch_Call{Call: _e.mock.On("GetLoginHistoryMatch", ctx, userID, device, country)}
+}
+
+func (_c *MockAuditRepository_GetLoginHistoryMatch_Call) Run(run func(ctx context.Context, userID int, device string, country string)) *MockAuditRepository_GetLoginHistoryMatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockAuditRepository_GetLoginHistoryMatch_Call) Return(_a0 *models.LoginHistoryMatch, _a1 error) *MockAuditRepository_GetLoginHistoryMatch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAuditRepository_GetLoginHistoryMatch_Call) RunAndReturn(run func(context.Context, int, string, string) (*models.LoginHistoryMatch, error)) *MockAuditRepository_GetLoginHistoryMatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserActivity provides a mock function with given fields: ctx, userID, after, limit
+func (_m *MockAuditRepository) GetUserActivity(ctx context.Context, userID int, after *models.ActivityCursor, limit int) ([]models.ActivityItem, error) {
+	ret := _m.Called(ctx, userID, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserActivity")
+	}
+
+	var r0 []models.ActivityItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.ActivityCursor, int) ([]models.ActivityItem, error)); ok {
+		return rf(ctx, userID, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.ActivityCursor, int) []models.ActivityItem); ok {
+		r0 = rf(ctx, userID, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ActivityItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.ActivityCursor, int) error); ok {
+		r1 = rf(ctx, userID, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAuditRepository_GetUserActivity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserActivity'
+type MockAuditRepository_GetUserActivity_Call struct {
+	*mock.Call
+}
+
+// GetUserActivity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - after *models.ActivityCursor
+//   - limit int
+func (_e *MockAuditRepository_Expecter) GetUserActivity(ctx interface{}, userID interface{}, after interface{}, limit interface{}) *MockAuditRepository_GetUserActivity_Call {
+	return &MockAuditRepository_GetUserActivity_Call{Call: _e.mock.On("GetUserActivity", ctx, userID, after, limit)}
+}
+
+func (_c *MockAuditRepository_GetUserActivity_Call) Run(run func(ctx context.Context, userID int, after *models.ActivityCursor, limit int)) *MockAuditRepository_GetUserActivity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.ActivityCursor), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockAuditRepository_GetUserActivity_Call) Return(_a0 []models.ActivityItem, _a1 error) *MockAuditRepository_GetUserActivity_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAuditRepository_GetUserActivity_Call) RunAndReturn(run func(context.Context, int, *models.ActivityCursor, int) ([]models.ActivityItem, error)) *MockAuditRepository_GetUserActivity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAuditRepository creates a new instance of MockAuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuditRepository {
+	mock := &MockAuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
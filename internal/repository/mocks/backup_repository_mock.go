@@ -0,0 +1,424 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockBackupRepository is an autogenerated mock type for the BackupRepository type
+type MockBackupRepository struct {
+	mock.Mock
+}
+
+type MockBackupRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockBackupRepository) EXPECT() *MockBackupRepository_Expecter {
+	return &MockBackupRepository_Expecter{mock: &_m.Mock}
+}
+
+// ClaimPendingBackup provides a mock function with given fields: ctx
+func (_m *MockBackupRepository) ClaimPendingBackup(ctx context.Context) (*models.Backup, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimPendingBackup")
+	}
+
+	var r0 *models.Backup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*models.Backup, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *models.Backup); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Backup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBackupRepository_ClaimPendingBackup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimPendingBackup'
+type MockBackupRepository_ClaimPendingBackup_Call struct {
+	*mock.Call
+}
+
+// ClaimPendingBackup is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockBackupRepository_Expecter) ClaimPendingBackup(ctx interface{}) *MockBackupRepository_ClaimPendingBackup_Call {
+	return &MockBackupRepository_ClaimPendingBackup_Call{Call: _e.mock.On("ClaimPendingBackup", ctx)}
+}
+
+func (_c *MockBackupRepository_ClaimPendingBackup_Call) Run(run func(ctx context.Context)) *MockBackupRepository_ClaimPendingBackup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockBackupRepository_ClaimPendingBackup_Call) Return(_a0 *models.Backup, _a1 error) *MockBackupRepository_ClaimPendingBackup_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBackupRepository_ClaimPendingBackup_Call) RunAndReturn(run func(context.Context) (*models.Backup, error)) *MockBackupRepository_ClaimPendingBackup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateBackup provides a mock function with given fields: ctx, backup
+func (_m *MockBackupRepository) CreateBackup(ctx context.Context, backup *models.Backup) error {
+	ret := _m.Called(ctx, backup)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBackup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Backup) error); ok {
+		r0 = rf(ctx, backup)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockBackupRepository_CreateBackup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateBackup'
+type MockBackupRepository_CreateBackup_Call struct {
+	*mock.Call
+}
+
+// CreateBackup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - backup *models.Backup
+func (_e *MockBackupRepository_Expecter) CreateBackup(ctx interface{}, backup interface{}) *MockBackupRepository_CreateBackup_Call {
+	return &MockBackupRepository_CreateBackup_Call{Call: _e.mock.On("CreateBackup", ctx, backup)}
+}
+
+func (_c *MockBackupRepository_CreateBackup_Call) Run(run func(ctx context.Context, backup *models.Backup)) *MockBackupRepository_CreateBackup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Backup))
+	})
+	return _c
+}
+
+func (_c *MockBackupRepository_CreateBackup_Call) Return(_a0 error) *MockBackupRepository_CreateBackup_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBackupRepository_CreateBackup_Call) RunAndReturn(run func(context.Context, *models.Backup) error) *MockBackupRepository_CreateBackup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FailStaleBackups provides a mock function with given fields: ctx, startedBefore, reason
+func (_m *MockBackupRepository) FailStaleBackups(ctx context.Context, startedBefore time.Time, reason string) (int, error) {
+	ret := _m.Called(ctx, startedBefore, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailStaleBackups")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) (int, error)); ok {
+		return rf(ctx, startedBefore, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) int); ok {
+		r0 = rf(ctx, startedBefore, reason)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, string) error); ok {
+		r1 = rf(ctx, startedBefore, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBackupRepository_FailStaleBackups_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FailStaleBackups'
+type MockBackupRepository_FailStaleBackups_Call struct {
+	*mock.Call
+}
+
+// FailStaleBackups is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startedBefore time.Time
+//   - reason string
+func (_e *MockBackupRepository_Expecter) FailStaleBackups(ctx interface{}, startedBefore interface{}, reason interface{}) *MockBackupRepository_FailStaleBackups_Call {
+	return &MockBackupRepository_FailStaleBackups_Call{Call: _e.mock.On("FailStaleBackups", ctx, startedBefore, reason)}
+}
+
+func (_c *MockBackupRepository_FailStaleBackups_Call) Run(run func(ctx context.Context, startedBefore time.Time, reason string)) *MockBackupRepository_FailStaleBackups_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockBackupRepository_FailStaleBackups_Call) Return(_a0 int, _a1 error) *MockBackupRepository_FailStaleBackups_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBackupRepository_FailStaleBackups_Call) RunAndReturn(run func(context.Context, time.Time, string) (int, error)) *MockBackupRepository_FailStaleBackups_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBackupByID provides a mock function with given fields: ctx, id
+func (_m *MockBackupRepository) GetBackupByID(ctx context.Context, id int) (*models.Backup, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBackupByID")
+	}
+
+	var r0 *models.Backup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Backup, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Backup); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Backup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBackupRepository_GetBackupByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBackupByID'
+type MockBackupRepository_GetBackupByID_Call struct {
+	*mock.Call
+}
+
+// GetBackupByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockBackupRepository_Expecter) GetBackupByID(ctx interface{}, id interface{}) *MockBackupRepository_GetBackupByID_Call {
+	return &MockBackupRepository_GetBackupByID_Call{Call: _e.mock.On("GetBackupByID", ctx, id)}
+}
+
+func (_c *MockBackupRepository_GetBackupByID_Call) Run(run func(ctx context.Context, id int)) *MockBackupRepository_GetBackupByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockBackupRepository_GetBackupByID_Call) Return(_a0 *models.Backup, _a1 error) *MockBackupRepository_GetBackupByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBackupRepository_GetBackupByID_Call) RunAndReturn(run func(context.Context, int) (*models.Backup, error)) *MockBackupRepository_GetBackupByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBackups provides a mock function with given fields: ctx, page, limit
+func (_m *MockBackupRepository) GetBackups(ctx context.Context, page int, limit int) ([]models.Backup, int, error) {
+	ret := _m.Called(ctx, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBackups")
+	}
+
+	var r0 []models.Backup
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.Backup, int, error)); ok {
+		return rf(ctx, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.Backup); ok {
+		r0 = rf(ctx, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Backup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = rf(ctx, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockBackupRepository_GetBackups_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBackups'
+type MockBackupRepository_GetBackups_Call struct {
+	*mock.Call
+}
+
+// GetBackups is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - limit int
+func (_e *MockBackupRepository_Expecter) GetBackups(ctx interface{}, page interface{}, limit interface{}) *MockBackupRepository_GetBackups_Call {
+	return &MockBackupRepository_GetBackups_Call{Call: _e.mock.On("GetBackups", ctx, page, limit)}
+}
+
+func (_c *MockBackupRepository_GetBackups_Call) Run(run func(ctx context.Context, page int, limit int)) *MockBackupRepository_GetBackups_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockBackupRepository_GetBackups_Call) Return(_a0 []models.Backup, _a1 int, _a2 error) *MockBackupRepository_GetBackups_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockBackupRepository_GetBackups_Call) RunAndReturn(run func(context.Context, int, int) ([]models.Backup, int, error)) *MockBackupRepository_GetBackups_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkBackupCompleted provides a mock function with given fields: ctx, id, result
+func (_m *MockBackupRepository) MarkBackupCompleted(ctx context.Context, id int, result models.BackupResult) error {
+	ret := _m.Called(ctx, id, result)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkBackupCompleted")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.BackupResult) error); ok {
+		r0 = rf(ctx, id, result)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockBackupRepository_MarkBackupCompleted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkBackupCompleted'
+type MockBackupRepository_MarkBackupCompleted_Call struct {
+	*mock.Call
+}
+
+// MarkBackupCompleted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - result models.BackupResult
+func (_e *MockBackupRepository_Expecter) MarkBackupCompleted(ctx interface{}, id interface{}, result interface{}) *MockBackupRepository_MarkBackupCompleted_Call {
+	return &MockBackupRepository_MarkBackupCompleted_Call{Call: _e.mock.On("MarkBackupCompleted", ctx, id, result)}
+}
+
+func (_c *MockBackupRepository_MarkBackupCompleted_Call) Run(run func(ctx context.Context, id int, result models.BackupResult)) *MockBackupRepository_MarkBackupCompleted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.BackupResult))
+	})
+	return _c
+}
+
+func (_c *MockBackupRepository_MarkBackupCompleted_Call) Return(_a0 error) *MockBackupRepository_MarkBackupCompleted_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBackupRepository_MarkBackupCompleted_Call) RunAndReturn(run func(context.Context, int, models.BackupResult) error) *MockBackupRepository_MarkBackupCompleted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkBackupFailed provides a mock function with given fields: ctx, id, reason
+func (_m *MockBackupRepository) MarkBackupFailed(ctx context.Context, id int, reason string) error {
+	ret := _m.Called(ctx, id, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkBackupFailed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, id, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockBackupRepository_MarkBackupFailed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkBackupFailed'
+type MockBackupRepository_MarkBackupFailed_Call struct {
+	*mock.Call
+}
+
+// MarkBackupFailed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - reason string
+func (_e *MockBackupRepository_Expecter) MarkBackupFailed(ctx interface{}, id interface{}, reason interface{}) *MockBackupRepository_MarkBackupFailed_Call {
+	return &MockBackupRepository_MarkBackupFailed_Call{Call: _e.mock.On("MarkBackupFailed", ctx, id, reason)}
+}
+
+func (_c *MockBackupRepository_MarkBackupFailed_Call) Run(run func(ctx context.Context, id int, reason string)) *MockBackupRepository_MarkBackupFailed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockBackupRepository_MarkBackupFailed_Call) Return(_a0 error) *MockBackupRepository_MarkBackupFailed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBackupRepository_MarkBackupFailed_Call) RunAndReturn(run func(context.Context, int, string) error) *MockBackupRepository_MarkBackupFailed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockBackupRepository creates a new instance of MockBackupRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockBackupRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockBackupRepository {
+	mock := &MockBackupRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,271 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockDebugCaptureRepository is an autogenerated mock type for the DebugCaptureRepository type
+type MockDebugCaptureRepository struct {
+	mock.Mock
+}
+
+type MockDebugCaptureRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockDebugCaptureRepository) EXPECT() *MockDebugCaptureRepository_Expecter {
+	return &MockDebugCaptureRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateDebugCapture provides a mock function with given fields: ctx, capture
+func (_m *MockDebugCaptureRepository) CreateDebugCapture(ctx context.Context, capture *models.DebugCapture) error {
+	ret := _m.Called(ctx, capture)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDebugCapture")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.DebugCapture) error); ok {
+		r0 = rf(ctx, capture)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDebugCaptureRepository_CreateDebugCapture_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDebugCapture'
+type MockDebugCaptureRepository_CreateDebugCapture_Call struct {
+	*mock.Call
+}
+
+// CreateDebugCapture is a helper method to define mock.On call
+//   - ctx context.Context
+//   - capture *models.DebugCapture
+func (_e *MockDebugCaptureRepository_Expecter) CreateDebugCapture(ctx interface{}, capture interface{}) *MockDebugCaptureRepository_CreateDebugCapture_Call {
+	return &MockDebugCaptureRepository_CreateDebugCapture_Call{Call: _e.mock.On("CreateDebugCapture", ctx, capture)}
+}
+
+func (_c *MockDebugCaptureRepository_CreateDebugCapture_Call) Run(run func(ctx context.Context, capture *models.DebugCapture)) *MockDebugCaptureRepository_CreateDebugCapture_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.DebugCapture))
+	})
+	return _c
+}
+
+func (_c *MockDebugCaptureRepository_CreateDebugCapture_Call) Return(_a0 error) *MockDebugCaptureRepository_CreateDebugCapture_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDebugCaptureRepository_CreateDebugCapture_Call) RunAndReturn(run func(context.Context, *models.DebugCapture) error) *MockDebugCaptureRepository_CreateDebugCapture_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteExpiredDebugCaptures provides a mock function with given fields: ctx, before, limit
+func (_m *MockDebugCaptureRepository) DeleteExpiredDebugCaptures(ctx context.Context, before time.Time, limit int) (int, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredDebugCaptures")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) (int, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) int); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDebugCaptureRepository_DeleteExpiredDebugCaptures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredDebugCaptures'
+type MockDebugCaptureRepository_DeleteExpiredDebugCaptures_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredDebugCaptures is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+//   - limit int
+func (_e *MockDebugCaptureRepository_Expecter) DeleteExpiredDebugCaptures(ctx interface{}, before interface{}, limit interface{}) *MockDebugCaptureRepository_DeleteExpiredDebugCaptures_Call {
+	return &MockDebugCaptureRepository_DeleteExpiredDebugCaptures_Call{Call: _e.mock.On("DeleteExpiredDebugCaptures", ctx, before, limit)}
+}
+
+func (_c *MockDebugCaptureRepository_DeleteExpiredDebugCaptures_Call) Run(run func(ctx context.Context, before time.Time, limit int)) *MockDebugCaptureRepository_DeleteExpiredDebugCaptures_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockDebugCaptureRepository_DeleteExpiredDebugCaptures_Call) Return(_a0 int, _a1 error) *MockDebugCaptureRepository_DeleteExpiredDebugCaptures_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDebugCaptureRepository_DeleteExpiredDebugCaptures_Call) RunAndReturn(run func(context.Context, time.Time, int) (int, error)) *MockDebugCaptureRepository_DeleteExpiredDebugCaptures_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDebugCaptureByID provides a mock function with given fields: ctx, id
+func (_m *MockDebugCaptureRepository) GetDebugCaptureByID(ctx context.Context, id int64) (*models.DebugCapture, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDebugCaptureByID")
+	}
+
+	var r0 *models.DebugCapture
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*models.DebugCapture, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.DebugCapture); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DebugCapture)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDebugCaptureRepository_GetDebugCaptureByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDebugCaptureByID'
+type MockDebugCaptureRepository_GetDebugCaptureByID_Call struct {
+	*mock.Call
+}
+
+// GetDebugCaptureByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockDebugCaptureRepository_Expecter) GetDebugCaptureByID(ctx interface{}, id interface{}) *MockDebugCaptureRepository_GetDebugCaptureByID_Call {
+	return &MockDebugCaptureRepository_GetDebugCaptureByID_Call{Call: _e.mock.On("GetDebugCaptureByID", ctx, id)}
+}
+
+func (_c *MockDebugCaptureRepository_GetDebugCaptureByID_Call) Run(run func(ctx context.Context, id int64)) *MockDebugCaptureRepository_GetDebugCaptureByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockDebugCaptureRepository_GetDebugCaptureByID_Call) Return(_a0 *models.DebugCapture, _a1 error) *MockDebugCaptureRepository_GetDebugCaptureByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDebugCaptureRepository_GetDebugCaptureByID_Call) RunAndReturn(run func(context.Context, int64) (*models.DebugCapture, error)) *MockDebugCaptureRepository_GetDebugCaptureByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDebugCaptures provides a mock function with given fields: ctx, filter, page, limit
+func (_m *MockDebugCaptureRepository) GetDebugCaptures(ctx context.Context, filter models.DebugCaptureFilter, page int, limit int) ([]models.DebugCapture, int, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDebugCaptures")
+	}
+
+	var r0 []models.DebugCapture
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.DebugCaptureFilter, int, int) ([]models.DebugCapture, int, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.DebugCaptureFilter, int, int) []models.DebugCapture); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.DebugCapture)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.DebugCaptureFilter, int, int) int); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, models.DebugCaptureFilter, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockDebugCaptureRepository_GetDebugCaptures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDebugCaptures'
+type MockDebugCaptureRepository_GetDebugCaptures_Call struct {
+	*mock.Call
+}
+
+// GetDebugCaptures is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.DebugCaptureFilter
+//   - page int
+//   - limit int
+func (_e *MockDebugCaptureRepository_Expecter) GetDebugCaptures(ctx interface{}, filter interface{}, page interface{}, limit interface{}) *MockDebugCaptureRepository_GetDebugCaptures_Call {
+	return &MockDebugCaptureRepository_GetDebugCaptures_Call{Call: _e.mock.On("GetDebugCaptures", ctx, filter, page, limit)}
+}
+
+func (_c *MockDebugCaptureRepository_GetDebugCaptures_Call) Run(run func(ctx context.Context, filter models.DebugCaptureFilter, page int, limit int)) *MockDebugCaptureRepository_GetDebugCaptures_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.DebugCaptureFilter), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockDebugCaptureRepository_GetDebugCaptures_Call) Return(_a0 []models.DebugCapture, _a1 int, _a2 error) *MockDebugCaptureRepository_GetDebugCaptures_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockDebugCaptureRepository_GetDebugCaptures_Call) RunAndReturn(run func(context.Context, models.DebugCaptureFilter, int, int) ([]models.DebugCapture, int, error)) *MockDebugCaptureRepository_GetDebugCaptures_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockDebugCaptureRepository creates a new instance of MockDebugCaptureRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDebugCaptureRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDebugCaptureRepository {
+	mock := &MockDebugCaptureRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,277 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockDelegationRepository is an autogenerated mock type for the DelegationRepository type
+type MockDelegationRepository struct {
+	mock.Mock
+}
+
+type MockDelegationRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockDelegationRepository) EXPECT() *MockDelegationRepository_Expecter {
+	return &MockDelegationRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateDelegation provides a mock function with given fields: ctx, delegatorID, input
+func (_m *MockDelegationRepository) CreateDelegation(ctx context.Context, delegatorID int, input models.DelegationInput) (*models.ApprovalDelegation, error) {
+	ret := _m.Called(ctx, delegatorID, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDelegation")
+	}
+
+	var r0 *models.ApprovalDelegation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.DelegationInput) (*models.ApprovalDelegation, error)); ok {
+		return rf(ctx, delegatorID, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.DelegationInput) *models.ApprovalDelegation); ok {
+		r0 = rf(ctx, delegatorID, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ApprovalDelegation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.DelegationInput) error); ok {
+		r1 = rf(ctx, delegatorID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDelegationRepository_CreateDelegation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDelegation'
+type MockDelegationRepository_CreateDelegation_Call struct {
+	*mock.Call
+}
+
+// CreateDelegation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - delegatorID int
+//   - input models.DelegationInput
+func (_e *MockDelegationRepository_Expecter) CreateDelegation(ctx interface{}, delegatorID interface{}, input interface{}) *MockDelegationRepository_CreateDelegation_Call {
+	return &MockDelegationRepository_CreateDelegation_Call{Call: _e.mock.On("CreateDelegation", ctx, delegatorID, input)}
+}
+
+func (_c *MockDelegationRepository_CreateDelegation_Call) Run(run func(ctx context.Context, delegatorID int, input models.DelegationInput)) *MockDelegationRepository_CreateDelegation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.DelegationInput))
+	})
+	return _c
+}
+
+func (_c *MockDelegationRepository_CreateDelegation_Call) Return(_a0 *models.ApprovalDelegation, _a1 error) *MockDelegationRepository_CreateDelegation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDelegationRepository_CreateDelegation_Call) RunAndReturn(run func(context.Context, int, models.DelegationInput) (*models.ApprovalDelegation, error)) *MockDelegationRepository_CreateDelegation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDelegationsByUser provides a mock function with given fields: ctx, userID
+func (_m *MockDelegationRepository) GetDelegationsByUser(ctx context.Context, userID int) ([]models.ApprovalDelegation, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDelegationsByUser")
+	}
+
+	var r0 []models.ApprovalDelegation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.ApprovalDelegation, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.ApprovalDelegation); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ApprovalDelegation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDelegationRepository_GetDelegationsByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDelegationsByUser'
+type MockDelegationRepository_GetDelegationsByUser_Call struct {
+	*mock.Call
+}
+
+// GetDelegationsByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockDelegationRepository_Expecter) GetDelegationsByUser(ctx interface{}, userID interface{}) *MockDelegationRepository_GetDelegationsByUser_Call {
+	return &MockDelegationRepository_GetDelegationsByUser_Call{Call: _e.mock.On("GetDelegationsByUser", ctx, userID)}
+}
+
+func (_c *MockDelegationRepository_GetDelegationsByUser_Call) Run(run func(ctx context.Context, userID int)) *MockDelegationRepository_GetDelegationsByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockDelegationRepository_GetDelegationsByUser_Call) Return(_a0 []models.ApprovalDelegation, _a1 error) *MockDelegationRepository_GetDelegationsByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDelegationRepository_GetDelegationsByUser_Call) RunAndReturn(run func(context.Context, int) ([]models.ApprovalDelegation, error)) *MockDelegationRepository_GetDelegationsByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HasActiveDelegation provides a mock function with given fields: ctx, delegatorID, delegateID, day
+func (_m *MockDelegationRepository) HasActiveDelegation(ctx context.Context, delegatorID int, delegateID int, day time.Time) (bool, error) {
+	ret := _m.Called(ctx, delegatorID, delegateID, day)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasActiveDelegation")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Time) (bool, error)); ok {
+		return rf(ctx, delegatorID, delegateID, day)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Time) bool); ok {
+		r0 = rf(ctx, delegatorID, delegateID, day)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, time.Time) error); ok {
+		r1 = rf(ctx, delegatorID, delegateID, day)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDelegationRepository_HasActiveDelegation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HasActiveDelegation'
+type MockDelegationRepository_HasActiveDelegation_Call struct {
+	*mock.Call
+}
+
+// HasActiveDelegation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - delegatorID int
+//   - delegateID int
+//   - day time.Time
+func (_e *MockDelegationRepository_Expecter) HasActiveDelegation(ctx interface{}, delegatorID interface{}, delegateID interface{}, day interface{}) *MockDelegationRepository_HasActiveDelegation_Call {
+	return &MockDelegationRepository_HasActiveDelegation_Call{Call: _e.mock.On("HasActiveDelegation", ctx, delegatorID, delegateID, day)}
+}
+
+func (_c *MockDelegationRepository_HasActiveDelegation_Call) Run(run func(ctx context.Context, delegatorID int, delegateID int, day time.Time)) *MockDelegationRepository_HasActiveDelegation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockDelegationRepository_HasActiveDelegation_Call) Return(_a0 bool, _a1 error) *MockDelegationRepository_HasActiveDelegation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDelegationRepository_HasActiveDelegation_Call) RunAndReturn(run func(context.Context, int, int, time.Time) (bool, error)) *MockDelegationRepository_HasActiveDelegation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeDelegation provides a mock function with given fields: ctx, id, delegatorID
+func (_m *MockDelegationRepository) RevokeDelegation(ctx context.Context, id int, delegatorID int) (*models.ApprovalDelegation, error) {
+	ret := _m.Called(ctx, id, delegatorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeDelegation")
+	}
+
+	var r0 *models.ApprovalDelegation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.ApprovalDelegation, error)); ok {
+		return rf(ctx, id, delegatorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.ApprovalDelegation); ok {
+		r0 = rf(ctx, id, delegatorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ApprovalDelegation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, id, delegatorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDelegationRepository_RevokeDelegation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeDelegation'
+type MockDelegationRepository_RevokeDelegation_Call struct {
+	*mock.Call
+}
+
+// RevokeDelegation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - delegatorID int
+func (_e *MockDelegationRepository_Expecter) RevokeDelegation(ctx interface{}, id interface{}, delegatorID interface{}) *MockDelegationRepository_RevokeDelegation_Call {
+	return &MockDelegationRepository_RevokeDelegation_Call{Call: _e.mock.On("RevokeDelegation", ctx, id, delegatorID)}
+}
+
+func (_c *MockDelegationRepository_RevokeDelegation_Call) Run(run func(ctx context.Context, id int, delegatorID int)) *MockDelegationRepository_RevokeDelegation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockDelegationRepository_RevokeDelegation_Call) Return(_a0 *models.ApprovalDelegation, _a1 error) *MockDelegationRepository_RevokeDelegation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDelegationRepository_RevokeDelegation_Call) RunAndReturn(run func(context.Context, int, int) (*models.ApprovalDelegation, error)) *MockDelegationRepository_RevokeDelegation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockDelegationRepository creates a new instance of MockDelegationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDelegationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDelegationRepository {
+	mock := &MockDelegationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,381 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockDeviceRepository is an autogenerated mock type for the DeviceRepository type
+type MockDeviceRepository struct {
+	mock.Mock
+}
+
+type MockDeviceRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockDeviceRepository) EXPECT() *MockDeviceRepository_Expecter {
+	return &MockDeviceRepository_Expecter{mock: &_m.Mock}
+}
+
+// DeleteDeviceToken provides a mock function with given fields: ctx, userID, token
+func (_m *MockDeviceRepository) DeleteDeviceToken(ctx context.Context, userID int, token string) error {
+	ret := _m.Called(ctx, userID, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDeviceToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDeviceRepository_DeleteDeviceToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDeviceToken'
+type MockDeviceRepository_DeleteDeviceToken_Call struct {
+	*mock.Call
+}
+
+// DeleteDeviceToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - token string
+func (_e *MockDeviceRepository_Expecter) DeleteDeviceToken(ctx interface{}, userID interface{}, token interface{}) *MockDeviceRepository_DeleteDeviceToken_Call {
+	return &MockDeviceRepository_DeleteDeviceToken_Call{Call: _e.mock.On("DeleteDeviceToken", ctx, userID, token)}
+}
+
+func (_c *MockDeviceRepository_DeleteDeviceToken_Call) Run(run func(ctx context.Context, userID int, token string)) *MockDeviceRepository_DeleteDeviceToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_DeleteDeviceToken_Call) Return(_a0 error) *MockDeviceRepository_DeleteDeviceToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDeviceRepository_DeleteDeviceToken_Call) RunAndReturn(run func(context.Context, int, string) error) *MockDeviceRepository_DeleteDeviceToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteDeviceTokens provides a mock function with given fields: ctx, tokens
+func (_m *MockDeviceRepository) DeleteDeviceTokens(ctx context.Context, tokens []string) (int, error) {
+	ret := _m.Called(ctx, tokens)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDeviceTokens")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (int, error)); ok {
+		return rf(ctx, tokens)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) int); ok {
+		r0 = rf(ctx, tokens)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, tokens)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDeviceRepository_DeleteDeviceTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDeviceTokens'
+type MockDeviceRepository_DeleteDeviceTokens_Call struct {
+	*mock.Call
+}
+
+// DeleteDeviceTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokens []string
+func (_e *MockDeviceRepository_Expecter) DeleteDeviceTokens(ctx interface{}, tokens interface{}) *MockDeviceRepository_DeleteDeviceTokens_Call {
+	return &MockDeviceRepository_DeleteDeviceTokens_Call{Call: _e.mock.On("DeleteDeviceTokens", ctx, tokens)}
+}
+
+func (_c *MockDeviceRepository_DeleteDeviceTokens_Call) Run(run func(ctx context.Context, tokens []string)) *MockDeviceRepository_DeleteDeviceTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_DeleteDeviceTokens_Call) Return(_a0 int, _a1 error) *MockDeviceRepository_DeleteDeviceTokens_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDeviceRepository_DeleteDeviceTokens_Call) RunAndReturn(run func(context.Context, []string) (int, error)) *MockDeviceRepository_DeleteDeviceTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeviceTokensByUser provides a mock function with given fields: ctx, userID
+func (_m *MockDeviceRepository) GetDeviceTokensByUser(ctx context.Context, userID int) ([]models.DeviceToken, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeviceTokensByUser")
+	}
+
+	var r0 []models.DeviceToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.DeviceToken, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.DeviceToken); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.DeviceToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDeviceRepository_GetDeviceTokensByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeviceTokensByUser'
+type MockDeviceRepository_GetDeviceTokensByUser_Call struct {
+	*mock.Call
+}
+
+// GetDeviceTokensByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockDeviceRepository_Expecter) GetDeviceTokensByUser(ctx interface{}, userID interface{}) *MockDeviceRepository_GetDeviceTokensByUser_Call {
+	return &MockDeviceRepository_GetDeviceTokensByUser_Call{Call: _e.mock.On("GetDeviceTokensByUser", ctx, userID)}
+}
+
+func (_c *MockDeviceRepository_GetDeviceTokensByUser_Call) Run(run func(ctx context.Context, userID int)) *MockDeviceRepository_GetDeviceTokensByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_GetDeviceTokensByUser_Call) Return(_a0 []models.DeviceToken, _a1 error) *MockDeviceRepository_GetDeviceTokensByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDeviceRepository_GetDeviceTokensByUser_Call) RunAndReturn(run func(context.Context, int) ([]models.DeviceToken, error)) *MockDeviceRepository_GetDeviceTokensByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPendingShiftReminders provides a mock function with given fields: ctx, fromDate, toDate
+func (_m *MockDeviceRepository) GetPendingShiftReminders(ctx context.Context, fromDate time.Time, toDate time.Time) ([]models.ShiftReminder, error) {
+	ret := _m.Called(ctx, fromDate, toDate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingShiftReminders")
+	}
+
+	var r0 []models.ShiftReminder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]models.ShiftReminder, error)); ok {
+		return rf(ctx, fromDate, toDate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []models.ShiftReminder); ok {
+		r0 = rf(ctx, fromDate, toDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ShiftReminder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, fromDate, toDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDeviceRepository_GetPendingShiftReminders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingShiftReminders'
+type MockDeviceRepository_GetPendingShiftReminders_Call struct {
+	*mock.Call
+}
+
+// GetPendingShiftReminders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fromDate time.Time
+//   - toDate time.Time
+func (_e *MockDeviceRepository_Expecter) GetPendingShiftReminders(ctx interface{}, fromDate interface{}, toDate interface{}) *MockDeviceRepository_GetPendingShiftReminders_Call {
+	return &MockDeviceRepository_GetPendingShiftReminders_Call{Call: _e.mock.On("GetPendingShiftReminders", ctx, fromDate, toDate)}
+}
+
+func (_c *MockDeviceRepository_GetPendingShiftReminders_Call) Run(run func(ctx context.Context, fromDate time.Time, toDate time.Time)) *MockDeviceRepository_GetPendingShiftReminders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_GetPendingShiftReminders_Call) Return(_a0 []models.ShiftReminder, _a1 error) *MockDeviceRepository_GetPendingShiftReminders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDeviceRepository_GetPendingShiftReminders_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) ([]models.ShiftReminder, error)) *MockDeviceRepository_GetPendingShiftReminders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkShiftReminderSent provides a mock function with given fields: ctx, scheduleID
+func (_m *MockDeviceRepository) MarkShiftReminderSent(ctx context.Context, scheduleID int) (bool, error) {
+	ret := _m.Called(ctx, scheduleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkShiftReminderSent")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (bool, error)); ok {
+		return rf(ctx, scheduleID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, scheduleID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, scheduleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDeviceRepository_MarkShiftReminderSent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkShiftReminderSent'
+type MockDeviceRepository_MarkShiftReminderSent_Call struct {
+	*mock.Call
+}
+
+// MarkShiftReminderSent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - scheduleID int
+func (_e *MockDeviceRepository_Expecter) MarkShiftReminderSent(ctx interface{}, scheduleID interface{}) *MockDeviceRepository_MarkShiftReminderSent_Call {
+	return &MockDeviceRepository_MarkShiftReminderSent_Call{Call: _e.mock.On("MarkShiftReminderSent", ctx, scheduleID)}
+}
+
+func (_c *MockDeviceRepository_MarkShiftReminderSent_Call) Run(run func(ctx context.Context, scheduleID int)) *MockDeviceRepository_MarkShiftReminderSent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_MarkShiftReminderSent_Call) Return(_a0 bool, _a1 error) *MockDeviceRepository_MarkShiftReminderSent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDeviceRepository_MarkShiftReminderSent_Call) RunAndReturn(run func(context.Context, int) (bool, error)) *MockDeviceRepository_MarkShiftReminderSent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegisterDeviceToken provides a mock function with given fields: ctx, userID, platform, token
+func (_m *MockDeviceRepository) RegisterDeviceToken(ctx context.Context, userID int, platform string, token string) (*models.DeviceToken, error) {
+	ret := _m.Called(ctx, userID, platform, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterDeviceToken")
+	}
+
+	var r0 *models.DeviceToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) (*models.DeviceToken, error)); ok {
+		return rf(ctx, userID, platform, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) *models.DeviceToken); ok {
+		r0 = rf(ctx, userID, platform, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DeviceToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string) error); ok {
+		r1 = rf(ctx, userID, platform, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDeviceRepository_RegisterDeviceToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegisterDeviceToken'
+type MockDeviceRepository_RegisterDeviceToken_Call struct {
+	*mock.Call
+}
+
+// RegisterDeviceToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - platform string
+//   - token string
+func (_e *MockDeviceRepository_Expecter) RegisterDeviceToken(ctx interface{}, userID interface{}, platform interface{}, token interface{}) *MockDeviceRepository_RegisterDeviceToken_Call {
+	return &MockDeviceRepository_RegisterDeviceToken_Call{Call: _e.mock.On("RegisterDeviceToken", ctx, userID, platform, token)}
+}
+
+func (_c *MockDeviceRepository_RegisterDeviceToken_Call) Run(run func(ctx context.Context, userID int, platform string, token string)) *MockDeviceRepository_RegisterDeviceToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_RegisterDeviceToken_Call) Return(_a0 *models.DeviceToken, _a1 error) *MockDeviceRepository_RegisterDeviceToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDeviceRepository_RegisterDeviceToken_Call) RunAndReturn(run func(context.Context, int, string, string) (*models.DeviceToken, error)) *MockDeviceRepository_RegisterDeviceToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockDeviceRepository creates a new instance of MockDeviceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDeviceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDeviceRepository {
+	mock := &MockDeviceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,408 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockDisputeRepository is an autogenerated mock type for the DisputeRepository type
+type MockDisputeRepository struct {
+	mock.Mock
+}
+
+type MockDisputeRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockDisputeRepository) EXPECT() *MockDisputeRepository_Expecter {
+	return &MockDisputeRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateDispute provides a mock function with given fields: ctx, attendanceID, userID, comment
+func (_m *MockDisputeRepository) CreateDispute(ctx context.Context, attendanceID int, userID int, comment string) (*models.AttendanceDispute, error) {
+	ret := _m.Called(ctx, attendanceID, userID, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDispute")
+	}
+
+	var r0 *models.AttendanceDispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) (*models.AttendanceDispute, error)); ok {
+		return rf(ctx, attendanceID, userID, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) *models.AttendanceDispute); ok {
+		r0 = rf(ctx, attendanceID, userID, comment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AttendanceDispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = rf(ctx, attendanceID, userID, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDisputeRepository_CreateDispute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDispute'
+type MockDisputeRepository_CreateDispute_Call struct {
+	*mock.Call
+}
+
+// CreateDispute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attendanceID int
+//   - userID int
+//   - comment string
+func (_e *MockDisputeRepository_Expecter) CreateDispute(ctx interface{}, attendanceID interface{}, userID interface{}, comment interface{}) *MockDisputeRepository_CreateDispute_Call {
+	return &MockDisputeRepository_CreateDispute_Call{Call: _e.mock.On("CreateDispute", ctx, attendanceID, userID, comment)}
+}
+
+func (_c *MockDisputeRepository_CreateDispute_Call) Run(run func(ctx context.Context, attendanceID int, userID int, comment string)) *MockDisputeRepository_CreateDispute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockDisputeRepository_CreateDispute_Call) Return(_a0 *models.AttendanceDispute, _a1 error) *MockDisputeRepository_CreateDispute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDisputeRepository_CreateDispute_Call) RunAndReturn(run func(context.Context, int, int, string) (*models.AttendanceDispute, error)) *MockDisputeRepository_CreateDispute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllDisputes provides a mock function with given fields: ctx, status, page, limit
+func (_m *MockDisputeRepository) GetAllDisputes(ctx context.Context, status string, page int, limit int) ([]models.AttendanceDispute, int, error) {
+	ret := _m.Called(ctx, status, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllDisputes")
+	}
+
+	var r0 []models.AttendanceDispute
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]models.AttendanceDispute, int, error)); ok {
+		return rf(ctx, status, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []models.AttendanceDispute); ok {
+		r0 = rf(ctx, status, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AttendanceDispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
+		r1 = rf(ctx, status, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = rf(ctx, status, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockDisputeRepository_GetAllDisputes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllDisputes'
+type MockDisputeRepository_GetAllDisputes_Call struct {
+	*mock.Call
+}
+
+// GetAllDisputes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - status string
+//   - page int
+//   - limit int
+func (_e *MockDisputeRepository_Expecter) GetAllDisputes(ctx interface{}, status interface{}, page interface{}, limit interface{}) *MockDisputeRepository_GetAllDisputes_Call {
+	return &MockDisputeRepository_GetAllDisputes_Call{Call: _e.mock.On("GetAllDisputes", ctx, status, page, limit)}
+}
+
+func (_c *MockDisputeRepository_GetAllDisputes_Call) Run(run func(ctx context.Context, status string, page int, limit int)) *MockDisputeRepository_GetAllDisputes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockDisputeRepository_GetAllDisputes_Call) Return(_a0 []models.AttendanceDispute, _a1 int, _a2 error) *MockDisputeRepository_GetAllDisputes_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockDisputeRepository_GetAllDisputes_Call) RunAndReturn(run func(context.Context, string, int, int) ([]models.AttendanceDispute, int, error)) *MockDisputeRepository_GetAllDisputes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDisputeByID provides a mock function with given fields: ctx, id
+func (_m *MockDisputeRepository) GetDisputeByID(ctx context.Context, id int) (*models.AttendanceDispute, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDisputeByID")
+	}
+
+	var r0 *models.AttendanceDispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.AttendanceDispute, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.AttendanceDispute); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AttendanceDispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDisputeRepository_GetDisputeByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDisputeByID'
+type MockDisputeRepository_GetDisputeByID_Call struct {
+	*mock.Call
+}
+
+// GetDisputeByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockDisputeRepository_Expecter) GetDisputeByID(ctx interface{}, id interface{}) *MockDisputeRepository_GetDisputeByID_Call {
+	return &MockDisputeRepository_GetDisputeByID_Call{Call: _e.mock.On("GetDisputeByID", ctx, id)}
+}
+
+func (_c *MockDisputeRepository_GetDisputeByID_Call) Run(run func(ctx context.Context, id int)) *MockDisputeRepository_GetDisputeByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockDisputeRepository_GetDisputeByID_Call) Return(_a0 *models.AttendanceDispute, _a1 error) *MockDisputeRepository_GetDisputeByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDisputeRepository_GetDisputeByID_Call) RunAndReturn(run func(context.Context, int) (*models.AttendanceDispute, error)) *MockDisputeRepository_GetDisputeByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDisputesByUser provides a mock function with given fields: ctx, userID
+func (_m *MockDisputeRepository) GetDisputesByUser(ctx context.Context, userID int) ([]models.AttendanceDispute, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDisputesByUser")
+	}
+
+	var r0 []models.AttendanceDispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.AttendanceDispute, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.AttendanceDispute); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AttendanceDispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDisputeRepository_GetDisputesByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDisputesByUser'
+type MockDisputeRepository_GetDisputesByUser_Call struct {
+	*mock.Call
+}
+
+// GetDisputesByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockDisputeRepository_Expecter) GetDisputesByUser(ctx interface{}, userID interface{}) *MockDisputeRepository_GetDisputesByUser_Call {
+	return &MockDisputeRepository_GetDisputesByUser_Call{Call: _e.mock.On("GetDisputesByUser", ctx, userID)}
+}
+
+func (_c *MockDisputeRepository_GetDisputesByUser_Call) Run(run func(ctx context.Context, userID int)) *MockDisputeRepository_GetDisputesByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockDisputeRepository_GetDisputesByUser_Call) Return(_a0 []models.AttendanceDispute, _a1 error) *MockDisputeRepository_GetDisputesByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDisputeRepository_GetDisputesByUser_Call) RunAndReturn(run func(context.Context, int) ([]models.AttendanceDispute, error)) *MockDisputeRepository_GetDisputesByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOpenDisputesBefore provides a mock function with given fields: ctx, before, limit
+func (_m *MockDisputeRepository) GetOpenDisputesBefore(ctx context.Context, before time.Time, limit int) ([]models.AttendanceDispute, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOpenDisputesBefore")
+	}
+
+	var r0 []models.AttendanceDispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.AttendanceDispute, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.AttendanceDispute); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AttendanceDispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDisputeRepository_GetOpenDisputesBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOpenDisputesBefore'
+type MockDisputeRepository_GetOpenDisputesBefore_Call struct {
+	*mock.Call
+}
+
+// GetOpenDisputesBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+//   - limit int
+func (_e *MockDisputeRepository_Expecter) GetOpenDisputesBefore(ctx interface{}, before interface{}, limit interface{}) *MockDisputeRepository_GetOpenDisputesBefore_Call {
+	return &MockDisputeRepository_GetOpenDisputesBefore_Call{Call: _e.mock.On("GetOpenDisputesBefore", ctx, before, limit)}
+}
+
+func (_c *MockDisputeRepository_GetOpenDisputesBefore_Call) Run(run func(ctx context.Context, before time.Time, limit int)) *MockDisputeRepository_GetOpenDisputesBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockDisputeRepository_GetOpenDisputesBefore_Call) Return(_a0 []models.AttendanceDispute, _a1 error) *MockDisputeRepository_GetOpenDisputesBefore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDisputeRepository_GetOpenDisputesBefore_Call) RunAndReturn(run func(context.Context, time.Time, int) ([]models.AttendanceDispute, error)) *MockDisputeRepository_GetOpenDisputesBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResolveDispute provides a mock function with given fields: ctx, id, actorUserID, status, note
+func (_m *MockDisputeRepository) ResolveDispute(ctx context.Context, id int, actorUserID int, status string, note string) (*models.AttendanceDispute, error) {
+	ret := _m.Called(ctx, id, actorUserID, status, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveDispute")
+	}
+
+	var r0 *models.AttendanceDispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, string) (*models.AttendanceDispute, error)); ok {
+		return rf(ctx, id, actorUserID, status, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, string) *models.AttendanceDispute); ok {
+		r0 = rf(ctx, id, actorUserID, status, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AttendanceDispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string, string) error); ok {
+		r1 = rf(ctx, id, actorUserID, status, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDisputeRepository_ResolveDispute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveDispute'
+type MockDisputeRepository_ResolveDispute_Call struct {
+	*mock.Call
+}
+
+// ResolveDispute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - actorUserID int
+//   - status string
+//   - note string
+func (_e *MockDisputeRepository_Expecter) ResolveDispute(ctx interface{}, id interface{}, actorUserID interface{}, status interface{}, note interface{}) *MockDisputeRepository_ResolveDispute_Call {
+	return &MockDisputeRepository_ResolveDispute_Call{Call: _e.mock.On("ResolveDispute", ctx, id, actorUserID, status, note)}
+}
+
+func (_c *MockDisputeRepository_ResolveDispute_Call) Run(run func(ctx context.Context, id int, actorUserID int, status string, note string)) *MockDisputeRepository_ResolveDispute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockDisputeRepository_ResolveDispute_Call) Return(_a0 *models.AttendanceDispute, _a1 error) *MockDisputeRepository_ResolveDispute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDisputeRepository_ResolveDispute_Call) RunAndReturn(run func(context.Context, int, int, string, string) (*models.AttendanceDispute, error)) *MockDisputeRepository_ResolveDispute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockDisputeRepository creates a new instance of MockDisputeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDisputeRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDisputeRepository {
+	mock := &MockDisputeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,260 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockDocumentRepository is an autogenerated mock type for the DocumentRepository type
+type MockDocumentRepository struct {
+	mock.Mock
+}
+
+type MockDocumentRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockDocumentRepository) EXPECT() *MockDocumentRepository_Expecter {
+	return &MockDocumentRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateDocument provides a mock function with given fields: ctx, doc
+func (_m *MockDocumentRepository) CreateDocument(ctx context.Context, doc *models.Document) (int, error) {
+	ret := _m.Called(ctx, doc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDocument")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Document) (int, error)); ok {
+		return rf(ctx, doc)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Document) int); ok {
+		r0 = rf(ctx, doc)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Document) error); ok {
+		r1 = rf(ctx, doc)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDocumentRepository_CreateDocument_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDocument'
+type MockDocumentRepository_CreateDocument_Call struct {
+	*mock.Call
+}
+
+// CreateDocument is a helper method to define mock.On call
+//   - ctx context.Context
+//   - doc *models.Document
+func (_e *MockDocumentRepository_Expecter) CreateDocument(ctx interface{}, doc interface{}) *MockDocumentRepository_CreateDocument_Call {
+	return &MockDocumentRepository_CreateDocument_Call{Call: _e.mock.On("CreateDocument", ctx, doc)}
+}
+
+func (_c *MockDocumentRepository_CreateDocument_Call) Run(run func(ctx context.Context, doc *models.Document)) *MockDocumentRepository_CreateDocument_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Document))
+	})
+	return _c
+}
+
+func (_c *MockDocumentRepository_CreateDocument_Call) Return(_a0 int, _a1 error) *MockDocumentRepository_CreateDocument_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDocumentRepository_CreateDocument_Call) RunAndReturn(run func(context.Context, *models.Document) (int, error)) *MockDocumentRepository_CreateDocument_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteDocument provides a mock function with given fields: ctx, id
+func (_m *MockDocumentRepository) DeleteDocument(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDocument")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDocumentRepository_DeleteDocument_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDocument'
+type MockDocumentRepository_DeleteDocument_Call struct {
+	*mock.Call
+}
+
+// DeleteDocument is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockDocumentRepository_Expecter) DeleteDocument(ctx interface{}, id interface{}) *MockDocumentRepository_DeleteDocument_Call {
+	return &MockDocumentRepository_DeleteDocument_Call{Call: _e.mock.On("DeleteDocument", ctx, id)}
+}
+
+func (_c *MockDocumentRepository_DeleteDocument_Call) Run(run func(ctx context.Context, id int)) *MockDocumentRepository_DeleteDocument_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockDocumentRepository_DeleteDocument_Call) Return(_a0 error) *MockDocumentRepository_DeleteDocument_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDocumentRepository_DeleteDocument_Call) RunAndReturn(run func(context.Context, int) error) *MockDocumentRepository_DeleteDocument_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDocumentByID provides a mock function with given fields: ctx, id
+func (_m *MockDocumentRepository) GetDocumentByID(ctx context.Context, id int) (*models.Document, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDocumentByID")
+	}
+
+	var r0 *models.Document
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Document, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Document); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Document)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDocumentRepository_GetDocumentByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDocumentByID'
+type MockDocumentRepository_GetDocumentByID_Call struct {
+	*mock.Call
+}
+
+// GetDocumentByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockDocumentRepository_Expecter) GetDocumentByID(ctx interface{}, id interface{}) *MockDocumentRepository_GetDocumentByID_Call {
+	return &MockDocumentRepository_GetDocumentByID_Call{Call: _e.mock.On("GetDocumentByID", ctx, id)}
+}
+
+func (_c *MockDocumentRepository_GetDocumentByID_Call) Run(run func(ctx context.Context, id int)) *MockDocumentRepository_GetDocumentByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockDocumentRepository_GetDocumentByID_Call) Return(_a0 *models.Document, _a1 error) *MockDocumentRepository_GetDocumentByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDocumentRepository_GetDocumentByID_Call) RunAndReturn(run func(context.Context, int) (*models.Document, error)) *MockDocumentRepository_GetDocumentByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDocumentsBySubject provides a mock function with given fields: ctx, subjectType, subjectID
+func (_m *MockDocumentRepository) GetDocumentsBySubject(ctx context.Context, subjectType string, subjectID int) ([]models.Document, error) {
+	ret := _m.Called(ctx, subjectType, subjectID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDocumentsBySubject")
+	}
+
+	var r0 []models.Document
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]models.Document, error)); ok {
+		return rf(ctx, subjectType, subjectID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []models.Document); ok {
+		r0 = rf(ctx, subjectType, subjectID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Document)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, subjectType, subjectID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDocumentRepository_GetDocumentsBySubject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDocumentsBySubject'
+type MockDocumentRepository_GetDocumentsBySubject_Call struct {
+	*mock.Call
+}
+
+// GetDocumentsBySubject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subjectType string
+//   - subjectID int
+func (_e *MockDocumentRepository_Expecter) GetDocumentsBySubject(ctx interface{}, subjectType interface{}, subjectID interface{}) *MockDocumentRepository_GetDocumentsBySubject_Call {
+	return &MockDocumentRepository_GetDocumentsBySubject_Call{Call: _e.mock.On("GetDocumentsBySubject", ctx, subjectType, subjectID)}
+}
+
+func (_c *MockDocumentRepository_GetDocumentsBySubject_Call) Run(run func(ctx context.Context, subjectType string, subjectID int)) *MockDocumentRepository_GetDocumentsBySubject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockDocumentRepository_GetDocumentsBySubject_Call) Return(_a0 []models.Document, _a1 error) *MockDocumentRepository_GetDocumentsBySubject_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDocumentRepository_GetDocumentsBySubject_Call) RunAndReturn(run func(context.Context, string, int) ([]models.Document, error)) *MockDocumentRepository_GetDocumentsBySubject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockDocumentRepository creates a new instance of MockDocumentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDocumentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDocumentRepository {
+	mock := &MockDocumentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
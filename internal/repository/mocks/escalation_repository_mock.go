@@ -0,0 +1,154 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockEscalationRepository is an autogenerated mock type for the EscalationRepository type
+type MockEscalationRepository struct {
+	mock.Mock
+}
+
+type MockEscalationRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockEscalationRepository) EXPECT() *MockEscalationRepository_Expecter {
+	return &MockEscalationRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetEscalations provides a mock function with given fields: ctx, itemType, itemIDs
+func (_m *MockEscalationRepository) GetEscalations(ctx context.Context, itemType string, itemIDs []int) (map[int][]models.ApprovalEscalation, error) {
+	ret := _m.Called(ctx, itemType, itemIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEscalations")
+	}
+
+	var r0 map[int][]models.ApprovalEscalation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []int) (map[int][]models.ApprovalEscalation, error)); ok {
+		return rf(ctx, itemType, itemIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []int) map[int][]models.ApprovalEscalation); ok {
+		r0 = rf(ctx, itemType, itemIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int][]models.ApprovalEscalation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []int) error); ok {
+		r1 = rf(ctx, itemType, itemIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEscalationRepository_GetEscalations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEscalations'
+type MockEscalationRepository_GetEscalations_Call struct {
+	*mock.Call
+}
+
+// GetEscalations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - itemType string
+//   - itemIDs []int
+func (_e *MockEscalationRepository_Expecter) GetEscalations(ctx interface{}, itemType interface{}, itemIDs interface{}) *MockEscalationRepository_GetEscalations_Call {
+	return &MockEscalationRepository_GetEscalations_Call{Call: _e.mock.On("GetEscalations", ctx, itemType, itemIDs)}
+}
+
+func (_c *MockEscalationRepository_GetEscalations_Call) Run(run func(ctx context.Context, itemType string, itemIDs []int)) *MockEscalationRepository_GetEscalations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]int))
+	})
+	return _c
+}
+
+func (_c *MockEscalationRepository_GetEscalations_Call) Return(_a0 map[int][]models.ApprovalEscalation, _a1 error) *MockEscalationRepository_GetEscalations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEscalationRepository_GetEscalations_Call) RunAndReturn(run func(context.Context, string, []int) (map[int][]models.ApprovalEscalation, error)) *MockEscalationRepository_GetEscalations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordEscalation provides a mock function with given fields: ctx, e
+func (_m *MockEscalationRepository) RecordEscalation(ctx context.Context, e *models.ApprovalEscalation) (bool, error) {
+	ret := _m.Called(ctx, e)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordEscalation")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ApprovalEscalation) (bool, error)); ok {
+		return rf(ctx, e)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ApprovalEscalation) bool); ok {
+		r0 = rf(ctx, e)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.ApprovalEscalation) error); ok {
+		r1 = rf(ctx, e)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEscalationRepository_RecordEscalation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordEscalation'
+type MockEscalationRepository_RecordEscalation_Call struct {
+	*mock.Call
+}
+
+// RecordEscalation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - e *models.ApprovalEscalation
+func (_e *MockEscalationRepository_Expecter) RecordEscalation(ctx interface{}, e interface{}) *MockEscalationRepository_RecordEscalation_Call {
+	return &MockEscalationRepository_RecordEscalation_Call{Call: _e.mock.On("RecordEscalation", ctx, e)}
+}
+
+func (_c *MockEscalationRepository_RecordEscalation_Call) Run(run func(ctx context.Context, e *models.ApprovalEscalation)) *MockEscalationRepository_RecordEscalation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.ApprovalEscalation))
+	})
+	return _c
+}
+
+func (_c *MockEscalationRepository_RecordEscalation_Call) Return(_a0 bool, _a1 error) *MockEscalationRepository_RecordEscalation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEscalationRepository_RecordEscalation_Call) RunAndReturn(run func(context.Context, *models.ApprovalEscalation) (bool, error)) *MockEscalationRepository_RecordEscalation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockEscalationRepository creates a new instance of MockEscalationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockEscalationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockEscalationRepository {
+	mock := &MockEscalationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
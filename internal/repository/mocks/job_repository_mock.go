@@ -0,0 +1,253 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockJobRepository is an autogenerated mock type for the JobRepository type
+type MockJobRepository struct {
+	mock.Mock
+}
+
+type MockJobRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockJobRepository) EXPECT() *MockJobRepository_Expecter {
+	return &MockJobRepository_Expecter{mock: &_m.Mock}
+}
+
+// FinishJobRun provides a mock function with given fields: ctx, name, result, runErr, duration
+func (_m *MockJobRepository) FinishJobRun(ctx context.Context, name string, result int, runErr *string, duration time.Duration) error {
+	ret := _m.Called(ctx, name, result, runErr, duration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishJobRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, *string, time.Duration) error); ok {
+		r0 = rf(ctx, name, result, runErr, duration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockJobRepository_FinishJobRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FinishJobRun'
+type MockJobRepository_FinishJobRun_Call struct {
+	*mock.Call
+}
+
+// FinishJobRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - result int
+//   - runErr *string
+//   - duration time.Duration
+func (_e *MockJobRepository_Expecter) FinishJobRun(ctx interface{}, name interface{}, result interface{}, runErr interface{}, duration interface{}) *MockJobRepository_FinishJobRun_Call {
+	return &MockJobRepository_FinishJobRun_Call{Call: _e.mock.On("FinishJobRun", ctx, name, result, runErr, duration)}
+}
+
+func (_c *MockJobRepository_FinishJobRun_Call) Run(run func(ctx context.Context, name string, result int, runErr *string, duration time.Duration)) *MockJobRepository_FinishJobRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(*string), args[4].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_FinishJobRun_Call) Return(_a0 error) *MockJobRepository_FinishJobRun_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockJobRepository_FinishJobRun_Call) RunAndReturn(run func(context.Context, string, int, *string, time.Duration) error) *MockJobRepository_FinishJobRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJobs provides a mock function with given fields: ctx
+func (_m *MockJobRepository) GetJobs(ctx context.Context) ([]models.ScheduledJob, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobs")
+	}
+
+	var r0 []models.ScheduledJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.ScheduledJob, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.ScheduledJob); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ScheduledJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockJobRepository_GetJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobs'
+type MockJobRepository_GetJobs_Call struct {
+	*mock.Call
+}
+
+// GetJobs is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockJobRepository_Expecter) GetJobs(ctx interface{}) *MockJobRepository_GetJobs_Call {
+	return &MockJobRepository_GetJobs_Call{Call: _e.mock.On("GetJobs", ctx)}
+}
+
+func (_c *MockJobRepository_GetJobs_Call) Run(run func(ctx context.Context)) *MockJobRepository_GetJobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_GetJobs_Call) Return(_a0 []models.ScheduledJob, _a1 error) *MockJobRepository_GetJobs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockJobRepository_GetJobs_Call) RunAndReturn(run func(context.Context) ([]models.ScheduledJob, error)) *MockJobRepository_GetJobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegisterJobs provides a mock function with given fields: ctx, defs
+func (_m *MockJobRepository) RegisterJobs(ctx context.Context, defs []models.JobDefinition) error {
+	ret := _m.Called(ctx, defs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterJobs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.JobDefinition) error); ok {
+		r0 = rf(ctx, defs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockJobRepository_RegisterJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegisterJobs'
+type MockJobRepository_RegisterJobs_Call struct {
+	*mock.Call
+}
+
+// RegisterJobs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - defs []models.JobDefinition
+func (_e *MockJobRepository_Expecter) RegisterJobs(ctx interface{}, defs interface{}) *MockJobRepository_RegisterJobs_Call {
+	return &MockJobRepository_RegisterJobs_Call{Call: _e.mock.On("RegisterJobs", ctx, defs)}
+}
+
+func (_c *MockJobRepository_RegisterJobs_Call) Run(run func(ctx context.Context, defs []models.JobDefinition)) *MockJobRepository_RegisterJobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.JobDefinition))
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_RegisterJobs_Call) Return(_a0 error) *MockJobRepository_RegisterJobs_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockJobRepository_RegisterJobs_Call) RunAndReturn(run func(context.Context, []models.JobDefinition) error) *MockJobRepository_RegisterJobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartJobRun provides a mock function with given fields: ctx, name, trigger, force
+func (_m *MockJobRepository) StartJobRun(ctx context.Context, name string, trigger string, force bool) (bool, error) {
+	ret := _m.Called(ctx, name, trigger, force)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartJobRun")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) (bool, error)); ok {
+		return rf(ctx, name, trigger, force)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) bool); ok {
+		r0 = rf(ctx, name, trigger, force)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool) error); ok {
+		r1 = rf(ctx, name, trigger, force)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockJobRepository_StartJobRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartJobRun'
+type MockJobRepository_StartJobRun_Call struct {
+	*mock.Call
+}
+
+// StartJobRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - trigger string
+//   - force bool
+func (_e *MockJobRepository_Expecter) StartJobRun(ctx interface{}, name interface{}, trigger interface{}, force interface{}) *MockJobRepository_StartJobRun_Call {
+	return &MockJobRepository_StartJobRun_Call{Call: _e.mock.On("StartJobRun", ctx, name, trigger, force)}
+}
+
+func (_c *MockJobRepository_StartJobRun_Call) Run(run func(ctx context.Context, name string, trigger string, force bool)) *MockJobRepository_StartJobRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_StartJobRun_Call) Return(_a0 bool, _a1 error) *MockJobRepository_StartJobRun_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockJobRepository_StartJobRun_Call) RunAndReturn(run func(context.Context, string, string, bool) (bool, error)) *MockJobRepository_StartJobRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockJobRepository creates a new instance of MockJobRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockJobRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockJobRepository {
+	mock := &MockJobRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,310 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockKioskRepository is an autogenerated mock type for the KioskRepository type
+type MockKioskRepository struct {
+	mock.Mock
+}
+
+type MockKioskRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockKioskRepository) EXPECT() *MockKioskRepository_Expecter {
+	return &MockKioskRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateKioskDevice provides a mock function with given fields: ctx, device
+func (_m *MockKioskRepository) CreateKioskDevice(ctx context.Context, device *models.KioskDevice) error {
+	ret := _m.Called(ctx, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateKioskDevice")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.KioskDevice) error); ok {
+		r0 = rf(ctx, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockKioskRepository_CreateKioskDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateKioskDevice'
+type MockKioskRepository_CreateKioskDevice_Call struct {
+	*mock.Call
+}
+
+// CreateKioskDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - device *models.KioskDevice
+func (_e *MockKioskRepository_Expecter) CreateKioskDevice(ctx interface{}, device interface{}) *MockKioskRepository_CreateKioskDevice_Call {
+	return &MockKioskRepository_CreateKioskDevice_Call{Call: _e.mock.On("CreateKioskDevice", ctx, device)}
+}
+
+func (_c *MockKioskRepository_CreateKioskDevice_Call) Run(run func(ctx context.Context, device *models.KioskDevice)) *MockKioskRepository_CreateKioskDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.KioskDevice))
+	})
+	return _c
+}
+
+func (_c *MockKioskRepository_CreateKioskDevice_Call) Return(_a0 error) *MockKioskRepository_CreateKioskDevice_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockKioskRepository_CreateKioskDevice_Call) RunAndReturn(run func(context.Context, *models.KioskDevice) error) *MockKioskRepository_CreateKioskDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetKioskDevice provides a mock function with given fields: ctx, id
+func (_m *MockKioskRepository) GetKioskDevice(ctx context.Context, id int) (*models.KioskDevice, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetKioskDevice")
+	}
+
+	var r0 *models.KioskDevice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.KioskDevice, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.KioskDevice); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.KioskDevice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockKioskRepository_GetKioskDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetKioskDevice'
+type MockKioskRepository_GetKioskDevice_Call struct {
+	*mock.Call
+}
+
+// GetKioskDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockKioskRepository_Expecter) GetKioskDevice(ctx interface{}, id interface{}) *MockKioskRepository_GetKioskDevice_Call {
+	return &MockKioskRepository_GetKioskDevice_Call{Call: _e.mock.On("GetKioskDevice", ctx, id)}
+}
+
+func (_c *MockKioskRepository_GetKioskDevice_Call) Run(run func(ctx context.Context, id int)) *MockKioskRepository_GetKioskDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockKioskRepository_GetKioskDevice_Call) Return(_a0 *models.KioskDevice, _a1 error) *MockKioskRepository_GetKioskDevice_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockKioskRepository_GetKioskDevice_Call) RunAndReturn(run func(context.Context, int) (*models.KioskDevice, error)) *MockKioskRepository_GetKioskDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetKioskDevicesByUser provides a mock function with given fields: ctx, userID
+func (_m *MockKioskRepository) GetKioskDevicesByUser(ctx context.Context, userID int) ([]models.KioskDevice, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetKioskDevicesByUser")
+	}
+
+	var r0 []models.KioskDevice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.KioskDevice, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.KioskDevice); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.KioskDevice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockKioskRepository_GetKioskDevicesByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetKioskDevicesByUser'
+type MockKioskRepository_GetKioskDevicesByUser_Call struct {
+	*mock.Call
+}
+
+// GetKioskDevicesByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockKioskRepository_Expecter) GetKioskDevicesByUser(ctx interface{}, userID interface{}) *MockKioskRepository_GetKioskDevicesByUser_Call {
+	return &MockKioskRepository_GetKioskDevicesByUser_Call{Call: _e.mock.On("GetKioskDevicesByUser", ctx, userID)}
+}
+
+func (_c *MockKioskRepository_GetKioskDevicesByUser_Call) Run(run func(ctx context.Context, userID int)) *MockKioskRepository_GetKioskDevicesByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockKioskRepository_GetKioskDevicesByUser_Call) Return(_a0 []models.KioskDevice, _a1 error) *MockKioskRepository_GetKioskDevicesByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockKioskRepository_GetKioskDevicesByUser_Call) RunAndReturn(run func(context.Context, int) ([]models.KioskDevice, error)) *MockKioskRepository_GetKioskDevicesByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeKioskDevice provides a mock function with given fields: ctx, id, ownerID, revokedBy
+func (_m *MockKioskRepository) RevokeKioskDevice(ctx context.Context, id int, ownerID *int, revokedBy int) (*models.KioskDevice, error) {
+	ret := _m.Called(ctx, id, ownerID, revokedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeKioskDevice")
+	}
+
+	var r0 *models.KioskDevice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *int, int) (*models.KioskDevice, error)); ok {
+		return rf(ctx, id, ownerID, revokedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *int, int) *models.KioskDevice); ok {
+		r0 = rf(ctx, id, ownerID, revokedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.KioskDevice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *int, int) error); ok {
+		r1 = rf(ctx, id, ownerID, revokedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockKioskRepository_RevokeKioskDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeKioskDevice'
+type MockKioskRepository_RevokeKioskDevice_Call struct {
+	*mock.Call
+}
+
+// RevokeKioskDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - ownerID *int
+//   - revokedBy int
+func (_e *MockKioskRepository_Expecter) RevokeKioskDevice(ctx interface{}, id interface{}, ownerID interface{}, revokedBy interface{}) *MockKioskRepository_RevokeKioskDevice_Call {
+	return &MockKioskRepository_RevokeKioskDevice_Call{Call: _e.mock.On("RevokeKioskDevice", ctx, id, ownerID, revokedBy)}
+}
+
+func (_c *MockKioskRepository_RevokeKioskDevice_Call) Run(run func(ctx context.Context, id int, ownerID *int, revokedBy int)) *MockKioskRepository_RevokeKioskDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockKioskRepository_RevokeKioskDevice_Call) Return(_a0 *models.KioskDevice, _a1 error) *MockKioskRepository_RevokeKioskDevice_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockKioskRepository_RevokeKioskDevice_Call) RunAndReturn(run func(context.Context, int, *int, int) (*models.KioskDevice, error)) *MockKioskRepository_RevokeKioskDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TouchKioskDevice provides a mock function with given fields: ctx, id
+func (_m *MockKioskRepository) TouchKioskDevice(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchKioskDevice")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockKioskRepository_TouchKioskDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TouchKioskDevice'
+type MockKioskRepository_TouchKioskDevice_Call struct {
+	*mock.Call
+}
+
+// TouchKioskDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockKioskRepository_Expecter) TouchKioskDevice(ctx interface{}, id interface{}) *MockKioskRepository_TouchKioskDevice_Call {
+	return &MockKioskRepository_TouchKioskDevice_Call{Call: _e.mock.On("TouchKioskDevice", ctx, id)}
+}
+
+func (_c *MockKioskRepository_TouchKioskDevice_Call) Run(run func(ctx context.Context, id int)) *MockKioskRepository_TouchKioskDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockKioskRepository_TouchKioskDevice_Call) Return(_a0 error) *MockKioskRepository_TouchKioskDevice_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockKioskRepository_TouchKioskDevice_Call) RunAndReturn(run func(context.Context, int) error) *MockKioskRepository_TouchKioskDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockKioskRepository creates a new instance of MockKioskRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockKioskRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockKioskRepository {
+	mock := &MockKioskRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
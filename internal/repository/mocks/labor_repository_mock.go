@@ -0,0 +1,205 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockLaborRepository is an autogenerated mock type for the LaborRepository type
+type MockLaborRepository struct {
+	mock.Mock
+}
+
+type MockLaborRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLaborRepository) EXPECT() *MockLaborRepository_Expecter {
+	return &MockLaborRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetLaborCost provides a mock function with given fields: ctx, startDate, endDate, groupBy
+func (_m *MockLaborRepository) GetLaborCost(ctx context.Context, startDate time.Time, endDate time.Time, groupBy string) ([]models.LaborCostRow, *models.LaborCostRow, error) {
+	ret := _m.Called(ctx, startDate, endDate, groupBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLaborCost")
+	}
+
+	var r0 []models.LaborCostRow
+	var r1 *models.LaborCostRow
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, string) ([]models.LaborCostRow, *models.LaborCostRow, error)); ok {
+		return rf(ctx, startDate, endDate, groupBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, string) []models.LaborCostRow); ok {
+		r0 = rf(ctx, startDate, endDate, groupBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LaborCostRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, string) *models.LaborCostRow); ok {
+		r1 = rf(ctx, startDate, endDate, groupBy)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*models.LaborCostRow)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, time.Time, time.Time, string) error); ok {
+		r2 = rf(ctx, startDate, endDate, groupBy)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockLaborRepository_GetLaborCost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLaborCost'
+type MockLaborRepository_GetLaborCost_Call struct {
+	*mock.Call
+}
+
+// GetLaborCost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startDate time.Time
+//   - endDate time.Time
+//   - groupBy string
+func (_e *MockLaborRepository_Expecter) GetLaborCost(ctx interface{}, startDate interface{}, endDate interface{}, groupBy interface{}) *MockLaborRepository_GetLaborCost_Call {
+	return &MockLaborRepository_GetLaborCost_Call{Call: _e.mock.On("GetLaborCost", ctx, startDate, endDate, groupBy)}
+}
+
+func (_c *MockLaborRepository_GetLaborCost_Call) Run(run func(ctx context.Context, startDate time.Time, endDate time.Time, groupBy string)) *MockLaborRepository_GetLaborCost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockLaborRepository_GetLaborCost_Call) Return(_a0 []models.LaborCostRow, _a1 *models.LaborCostRow, _a2 error) *MockLaborRepository_GetLaborCost_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockLaborRepository_GetLaborCost_Call) RunAndReturn(run func(context.Context, time.Time, time.Time, string) ([]models.LaborCostRow, *models.LaborCostRow, error)) *MockLaborRepository_GetLaborCost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetRoleHourlyRate provides a mock function with given fields: ctx, roleID, rate
+func (_m *MockLaborRepository) SetRoleHourlyRate(ctx context.Context, roleID int, rate *float64) error {
+	ret := _m.Called(ctx, roleID, rate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRoleHourlyRate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *float64) error); ok {
+		r0 = rf(ctx, roleID, rate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLaborRepository_SetRoleHourlyRate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRoleHourlyRate'
+type MockLaborRepository_SetRoleHourlyRate_Call struct {
+	*mock.Call
+}
+
+// SetRoleHourlyRate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - roleID int
+//   - rate *float64
+func (_e *MockLaborRepository_Expecter) SetRoleHourlyRate(ctx interface{}, roleID interface{}, rate interface{}) *MockLaborRepository_SetRoleHourlyRate_Call {
+	return &MockLaborRepository_SetRoleHourlyRate_Call{Call: _e.mock.On("SetRoleHourlyRate", ctx, roleID, rate)}
+}
+
+func (_c *MockLaborRepository_SetRoleHourlyRate_Call) Run(run func(ctx context.Context, roleID int, rate *float64)) *MockLaborRepository_SetRoleHourlyRate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*float64))
+	})
+	return _c
+}
+
+func (_c *MockLaborRepository_SetRoleHourlyRate_Call) Return(_a0 error) *MockLaborRepository_SetRoleHourlyRate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLaborRepository_SetRoleHourlyRate_Call) RunAndReturn(run func(context.Context, int, *float64) error) *MockLaborRepository_SetRoleHourlyRate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserHourlyRate provides a mock function with given fields: ctx, userID, rate
+func (_m *MockLaborRepository) SetUserHourlyRate(ctx context.Context, userID int, rate *float64) error {
+	ret := _m.Called(ctx, userID, rate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserHourlyRate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *float64) error); ok {
+		r0 = rf(ctx, userID, rate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLaborRepository_SetUserHourlyRate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserHourlyRate'
+type MockLaborRepository_SetUserHourlyRate_Call struct {
+	*mock.Call
+}
+
+// SetUserHourlyRate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - rate *float64
+func (_e *MockLaborRepository_Expecter) SetUserHourlyRate(ctx interface{}, userID interface{}, rate interface{}) *MockLaborRepository_SetUserHourlyRate_Call {
+	return &MockLaborRepository_SetUserHourlyRate_Call{Call: _e.mock.On("SetUserHourlyRate", ctx, userID, rate)}
+}
+
+func (_c *MockLaborRepository_SetUserHourlyRate_Call) Run(run func(ctx context.Context, userID int, rate *float64)) *MockLaborRepository_SetUserHourlyRate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*float64))
+	})
+	return _c
+}
+
+func (_c *MockLaborRepository_SetUserHourlyRate_Call) Return(_a0 error) *MockLaborRepository_SetUserHourlyRate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLaborRepository_SetUserHourlyRate_Call) RunAndReturn(run func(context.Context, int, *float64) error) *MockLaborRepository_SetUserHourlyRate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockLaborRepository creates a new instance of MockLaborRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLaborRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLaborRepository {
+	mock := &MockLaborRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
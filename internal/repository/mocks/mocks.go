@@ -0,0 +1,40 @@
+// internal/repository/mocks/mocks.go
+
+// Package mocks berisi mock testify untuk semua interface di internal/repository.
+// File *_mock.go digenerate mockery v2 (konfigurasi di .mockery.yaml) dan tidak diedit manual;
+// regenerasi dengan `go generate ./internal/repository/...`. Assertion di bawah membuat build
+// gagal jika mock tertinggal dari interface.
+package mocks
+
+import "github.com/rakaarfi/attendance-system-be/internal/repository"
+
+var (
+	_ repository.UserRepository            = (*MockUserRepository)(nil)
+	_ repository.RoleRepository            = (*MockRoleRepository)(nil)
+	_ repository.ShiftRepository           = (*MockShiftRepository)(nil)
+	_ repository.ScheduleRepository        = (*MockScheduleRepository)(nil)
+	_ repository.AttendanceRepository      = (*MockAttendanceRepository)(nil)
+	_ repository.SettingsRepository        = (*MockSettingsRepository)(nil)
+	_ repository.AnnouncementRepository    = (*MockAnnouncementRepository)(nil)
+	_ repository.DocumentRepository        = (*MockDocumentRepository)(nil)
+	_ repository.AuditRepository           = (*MockAuditRepository)(nil)
+	_ repository.PayrollRepository         = (*MockPayrollRepository)(nil)
+	_ repository.ProjectRepository         = (*MockProjectRepository)(nil)
+	_ repository.SignOffRepository         = (*MockSignOffRepository)(nil)
+	_ repository.DisputeRepository         = (*MockDisputeRepository)(nil)
+	_ repository.DeviceRepository          = (*MockDeviceRepository)(nil)
+	_ repository.NotificationRepository    = (*MockNotificationRepository)(nil)
+	_ repository.OutboxRepository          = (*MockOutboxRepository)(nil)
+	_ repository.DelegationRepository      = (*MockDelegationRepository)(nil)
+	_ repository.EscalationRepository      = (*MockEscalationRepository)(nil)
+	_ repository.LaborRepository           = (*MockLaborRepository)(nil)
+	_ repository.JobRepository             = (*MockJobRepository)(nil)
+	_ repository.VerificationRepository    = (*MockVerificationRepository)(nil)
+	_ repository.KioskRepository           = (*MockKioskRepository)(nil)
+	_ repository.AttendancePhotoRepository = (*MockAttendancePhotoRepository)(nil)
+	_ repository.ReportExportRepository    = (*MockReportExportRepository)(nil)
+	_ repository.DebugCaptureRepository    = (*MockDebugCaptureRepository)(nil)
+	_ repository.BackupRepository          = (*MockBackupRepository)(nil)
+	_ repository.AppInstanceRepository     = (*MockAppInstanceRepository)(nil)
+	_ repository.TxManager                 = (*MockTxManager)(nil)
+)
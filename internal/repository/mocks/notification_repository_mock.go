@@ -0,0 +1,337 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockNotificationRepository is an autogenerated mock type for the NotificationRepository type
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+type MockNotificationRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockNotificationRepository) EXPECT() *MockNotificationRepository_Expecter {
+	return &MockNotificationRepository_Expecter{mock: &_m.Mock}
+}
+
+// CountUnreadNotifications provides a mock function with given fields: ctx, userID
+func (_m *MockNotificationRepository) CountUnreadNotifications(ctx context.Context, userID int) (int, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountUnreadNotifications")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockNotificationRepository_CountUnreadNotifications_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountUnreadNotifications'
+type MockNotificationRepository_CountUnreadNotifications_Call struct {
+	*mock.Call
+}
+
+// CountUnreadNotifications is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockNotificationRepository_Expecter) CountUnreadNotifications(ctx interface{}, userID interface{}) *MockNotificationRepository_CountUnreadNotifications_Call {
+	return &MockNotificationRepository_CountUnreadNotifications_Call{Call: _e.mock.On("CountUnreadNotifications", ctx, userID)}
+}
+
+func (_c *MockNotificationRepository_CountUnreadNotifications_Call) Run(run func(ctx context.Context, userID int)) *MockNotificationRepository_CountUnreadNotifications_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_CountUnreadNotifications_Call) Return(_a0 int, _a1 error) *MockNotificationRepository_CountUnreadNotifications_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockNotificationRepository_CountUnreadNotifications_Call) RunAndReturn(run func(context.Context, int) (int, error)) *MockNotificationRepository_CountUnreadNotifications_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateNotification provides a mock function with given fields: ctx, n
+func (_m *MockNotificationRepository) CreateNotification(ctx context.Context, n *models.Notification) (int, error) {
+	ret := _m.Called(ctx, n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNotification")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Notification) (int, error)); ok {
+		return rf(ctx, n)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Notification) int); ok {
+		r0 = rf(ctx, n)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Notification) error); ok {
+		r1 = rf(ctx, n)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockNotificationRepository_CreateNotification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateNotification'
+type MockNotificationRepository_CreateNotification_Call struct {
+	*mock.Call
+}
+
+// CreateNotification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - n *models.Notification
+func (_e *MockNotificationRepository_Expecter) CreateNotification(ctx interface{}, n interface{}) *MockNotificationRepository_CreateNotification_Call {
+	return &MockNotificationRepository_CreateNotification_Call{Call: _e.mock.On("CreateNotification", ctx, n)}
+}
+
+func (_c *MockNotificationRepository_CreateNotification_Call) Run(run func(ctx context.Context, n *models.Notification)) *MockNotificationRepository_CreateNotification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Notification))
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_CreateNotification_Call) Return(_a0 int, _a1 error) *MockNotificationRepository_CreateNotification_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockNotificationRepository_CreateNotification_Call) RunAndReturn(run func(context.Context, *models.Notification) (int, error)) *MockNotificationRepository_CreateNotification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNotificationsByUser provides a mock function with given fields: ctx, userID, unreadOnly, page, limit
+func (_m *MockNotificationRepository) GetNotificationsByUser(ctx context.Context, userID int, unreadOnly bool, page int, limit int) ([]models.Notification, int, error) {
+	ret := _m.Called(ctx, userID, unreadOnly, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotificationsByUser")
+	}
+
+	var r0 []models.Notification
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool, int, int) ([]models.Notification, int, error)); ok {
+		return rf(ctx, userID, unreadOnly, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool, int, int) []models.Notification); ok {
+		r0 = rf(ctx, userID, unreadOnly, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, bool, int, int) int); ok {
+		r1 = rf(ctx, userID, unreadOnly, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, bool, int, int) error); ok {
+		r2 = rf(ctx, userID, unreadOnly, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockNotificationRepository_GetNotificationsByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationsByUser'
+type MockNotificationRepository_GetNotificationsByUser_Call struct {
+	*mock.Call
+}
+
+// GetNotificationsByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - unreadOnly bool
+//   - page int
+//   - limit int
+func (_e *MockNotificationRepository_Expecter) GetNotificationsByUser(ctx interface{}, userID interface{}, unreadOnly interface{}, page interface{}, limit interface{}) *MockNotificationRepository_GetNotificationsByUser_Call {
+	return &MockNotificationRepository_GetNotificationsByUser_Call{Call: _e.mock.On("GetNotificationsByUser", ctx, userID, unreadOnly, page, limit)}
+}
+
+func (_c *MockNotificationRepository_GetNotificationsByUser_Call) Run(run func(ctx context.Context, userID int, unreadOnly bool, page int, limit int)) *MockNotificationRepository_GetNotificationsByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(bool), args[3].(int), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_GetNotificationsByUser_Call) Return(_a0 []models.Notification, _a1 int, _a2 error) *MockNotificationRepository_GetNotificationsByUser_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockNotificationRepository_GetNotificationsByUser_Call) RunAndReturn(run func(context.Context, int, bool, int, int) ([]models.Notification, int, error)) *MockNotificationRepository_GetNotificationsByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkAllNotificationsRead provides a mock function with given fields: ctx, userID
+func (_m *MockNotificationRepository) MarkAllNotificationsRead(ctx context.Context, userID int) (int, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkAllNotificationsRead")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockNotificationRepository_MarkAllNotificationsRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkAllNotificationsRead'
+type MockNotificationRepository_MarkAllNotificationsRead_Call struct {
+	*mock.Call
+}
+
+// MarkAllNotificationsRead is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockNotificationRepository_Expecter) MarkAllNotificationsRead(ctx interface{}, userID interface{}) *MockNotificationRepository_MarkAllNotificationsRead_Call {
+	return &MockNotificationRepository_MarkAllNotificationsRead_Call{Call: _e.mock.On("MarkAllNotificationsRead", ctx, userID)}
+}
+
+func (_c *MockNotificationRepository_MarkAllNotificationsRead_Call) Run(run func(ctx context.Context, userID int)) *MockNotificationRepository_MarkAllNotificationsRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_MarkAllNotificationsRead_Call) Return(_a0 int, _a1 error) *MockNotificationRepository_MarkAllNotificationsRead_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockNotificationRepository_MarkAllNotificationsRead_Call) RunAndReturn(run func(context.Context, int) (int, error)) *MockNotificationRepository_MarkAllNotificationsRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkNotificationRead provides a mock function with given fields: ctx, userID, id
+func (_m *MockNotificationRepository) MarkNotificationRead(ctx context.Context, userID int, id int) (*models.Notification, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkNotificationRead")
+	}
+
+	var r0 *models.Notification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.Notification, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.Notification); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockNotificationRepository_MarkNotificationRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkNotificationRead'
+type MockNotificationRepository_MarkNotificationRead_Call struct {
+	*mock.Call
+}
+
+// MarkNotificationRead is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - id int
+func (_e *MockNotificationRepository_Expecter) MarkNotificationRead(ctx interface{}, userID interface{}, id interface{}) *MockNotificationRepository_MarkNotificationRead_Call {
+	return &MockNotificationRepository_MarkNotificationRead_Call{Call: _e.mock.On("MarkNotificationRead", ctx, userID, id)}
+}
+
+func (_c *MockNotificationRepository_MarkNotificationRead_Call) Run(run func(ctx context.Context, userID int, id int)) *MockNotificationRepository_MarkNotificationRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_MarkNotificationRead_Call) Return(_a0 *models.Notification, _a1 error) *MockNotificationRepository_MarkNotificationRead_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockNotificationRepository_MarkNotificationRead_Call) RunAndReturn(run func(context.Context, int, int) (*models.Notification, error)) *MockNotificationRepository_MarkNotificationRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockNotificationRepository creates a new instance of MockNotificationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockNotificationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockNotificationRepository {
+	mock := &MockNotificationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
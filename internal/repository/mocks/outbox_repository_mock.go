@@ -0,0 +1,336 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockOutboxRepository is an autogenerated mock type for the OutboxRepository type
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+type MockOutboxRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOutboxRepository) EXPECT() *MockOutboxRepository_Expecter {
+	return &MockOutboxRepository_Expecter{mock: &_m.Mock}
+}
+
+// DeleteDeliveredOutboxMessages provides a mock function with given fields: ctx, before
+func (_m *MockOutboxRepository) DeleteDeliveredOutboxMessages(ctx context.Context, before time.Time) (int, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDeliveredOutboxMessages")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOutboxRepository_DeleteDeliveredOutboxMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDeliveredOutboxMessages'
+type MockOutboxRepository_DeleteDeliveredOutboxMessages_Call struct {
+	*mock.Call
+}
+
+// DeleteDeliveredOutboxMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *MockOutboxRepository_Expecter) DeleteDeliveredOutboxMessages(ctx interface{}, before interface{}) *MockOutboxRepository_DeleteDeliveredOutboxMessages_Call {
+	return &MockOutboxRepository_DeleteDeliveredOutboxMessages_Call{Call: _e.mock.On("DeleteDeliveredOutboxMessages", ctx, before)}
+}
+
+func (_c *MockOutboxRepository_DeleteDeliveredOutboxMessages_Call) Run(run func(ctx context.Context, before time.Time)) *MockOutboxRepository_DeleteDeliveredOutboxMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_DeleteDeliveredOutboxMessages_Call) Return(_a0 int, _a1 error) *MockOutboxRepository_DeleteDeliveredOutboxMessages_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOutboxRepository_DeleteDeliveredOutboxMessages_Call) RunAndReturn(run func(context.Context, time.Time) (int, error)) *MockOutboxRepository_DeleteDeliveredOutboxMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnqueueOutboxMessages provides a mock function with given fields: ctx, msgs
+func (_m *MockOutboxRepository) EnqueueOutboxMessages(ctx context.Context, msgs []models.OutboxMessage) error {
+	ret := _m.Called(ctx, msgs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnqueueOutboxMessages")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.OutboxMessage) error); ok {
+		r0 = rf(ctx, msgs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOutboxRepository_EnqueueOutboxMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnqueueOutboxMessages'
+type MockOutboxRepository_EnqueueOutboxMessages_Call struct {
+	*mock.Call
+}
+
+// EnqueueOutboxMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msgs []models.OutboxMessage
+func (_e *MockOutboxRepository_Expecter) EnqueueOutboxMessages(ctx interface{}, msgs interface{}) *MockOutboxRepository_EnqueueOutboxMessages_Call {
+	return &MockOutboxRepository_EnqueueOutboxMessages_Call{Call: _e.mock.On("EnqueueOutboxMessages", ctx, msgs)}
+}
+
+func (_c *MockOutboxRepository_EnqueueOutboxMessages_Call) Run(run func(ctx context.Context, msgs []models.OutboxMessage)) *MockOutboxRepository_EnqueueOutboxMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.OutboxMessage))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_EnqueueOutboxMessages_Call) Return(_a0 error) *MockOutboxRepository_EnqueueOutboxMessages_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOutboxRepository_EnqueueOutboxMessages_Call) RunAndReturn(run func(context.Context, []models.OutboxMessage) error) *MockOutboxRepository_EnqueueOutboxMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeadOutboxMessages provides a mock function with given fields: ctx, page, limit
+func (_m *MockOutboxRepository) GetDeadOutboxMessages(ctx context.Context, page int, limit int) ([]models.OutboxMessage, int, error) {
+	ret := _m.Called(ctx, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadOutboxMessages")
+	}
+
+	var r0 []models.OutboxMessage
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.OutboxMessage, int, error)); ok {
+		return rf(ctx, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.OutboxMessage); ok {
+		r0 = rf(ctx, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = rf(ctx, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockOutboxRepository_GetDeadOutboxMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeadOutboxMessages'
+type MockOutboxRepository_GetDeadOutboxMessages_Call struct {
+	*mock.Call
+}
+
+// GetDeadOutboxMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - limit int
+func (_e *MockOutboxRepository_Expecter) GetDeadOutboxMessages(ctx interface{}, page interface{}, limit interface{}) *MockOutboxRepository_GetDeadOutboxMessages_Call {
+	return &MockOutboxRepository_GetDeadOutboxMessages_Call{Call: _e.mock.On("GetDeadOutboxMessages", ctx, page, limit)}
+}
+
+func (_c *MockOutboxRepository_GetDeadOutboxMessages_Call) Run(run func(ctx context.Context, page int, limit int)) *MockOutboxRepository_GetDeadOutboxMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_GetDeadOutboxMessages_Call) Return(_a0 []models.OutboxMessage, _a1 int, _a2 error) *MockOutboxRepository_GetDeadOutboxMessages_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockOutboxRepository_GetDeadOutboxMessages_Call) RunAndReturn(run func(context.Context, int, int) ([]models.OutboxMessage, int, error)) *MockOutboxRepository_GetDeadOutboxMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ProcessDueOutboxMessages provides a mock function with given fields: ctx, limit, maxAttempts, backoff, deliver
+func (_m *MockOutboxRepository) ProcessDueOutboxMessages(ctx context.Context, limit int, maxAttempts int, backoff func(int) time.Duration, deliver func(context.Context, models.OutboxMessage) error) (int, int, error) {
+	ret := _m.Called(ctx, limit, maxAttempts, backoff, deliver)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProcessDueOutboxMessages")
+	}
+
+	var r0 int
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, func(int) time.Duration, func(context.Context, models.OutboxMessage) error) (int, int, error)); ok {
+		return rf(ctx, limit, maxAttempts, backoff, deliver)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, func(int) time.Duration, func(context.Context, models.OutboxMessage) error) int); ok {
+		r0 = rf(ctx, limit, maxAttempts, backoff, deliver)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, func(int) time.Duration, func(context.Context, models.OutboxMessage) error) int); ok {
+		r1 = rf(ctx, limit, maxAttempts, backoff, deliver)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, func(int) time.Duration, func(context.Context, models.OutboxMessage) error) error); ok {
+		r2 = rf(ctx, limit, maxAttempts, backoff, deliver)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockOutboxRepository_ProcessDueOutboxMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProcessDueOutboxMessages'
+type MockOutboxRepository_ProcessDueOutboxMessages_Call struct {
+	*mock.Call
+}
+
+// ProcessDueOutboxMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - maxAttempts int
+//   - backoff func(int) time.Duration
+//   - deliver func(context.Context , models.OutboxMessage) error
+func (_e *MockOutboxRepository_Expecter) ProcessDueOutboxMessages(ctx interface{}, limit interface{}, maxAttempts interface{}, backoff interface{}, deliver interface{}) *MockOutboxRepository_ProcessDueOutboxMessages_Call {
+	return &MockOutboxRepository_ProcessDueOutboxMessages_Call{Call: _e.mock.On("ProcessDueOutboxMessages", ctx, limit, maxAttempts, backoff, deliver)}
+}
+
+func (_c *MockOutboxRepository_ProcessDueOutboxMessages_Call) Run(run func(ctx context.Context, limit int, maxAttempts int, backoff func(int) time.Duration, deliver func(context.Context, models.OutboxMessage) error)) *MockOutboxRepository_ProcessDueOutboxMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(func(int) time.Duration), args[4].(func(context.Context, models.OutboxMessage) error))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_ProcessDueOutboxMessages_Call) Return(_a0 int, _a1 int, _a2 error) *MockOutboxRepository_ProcessDueOutboxMessages_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockOutboxRepository_ProcessDueOutboxMessages_Call) RunAndReturn(run func(context.Context, int, int, func(int) time.Duration, func(context.Context, models.OutboxMessage) error) (int, int, error)) *MockOutboxRepository_ProcessDueOutboxMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RetryOutboxMessage provides a mock function with given fields: ctx, id
+func (_m *MockOutboxRepository) RetryOutboxMessage(ctx context.Context, id int64) (*models.OutboxMessage, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetryOutboxMessage")
+	}
+
+	var r0 *models.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*models.OutboxMessage, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.OutboxMessage); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOutboxRepository_RetryOutboxMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RetryOutboxMessage'
+type MockOutboxRepository_RetryOutboxMessage_Call struct {
+	*mock.Call
+}
+
+// RetryOutboxMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockOutboxRepository_Expecter) RetryOutboxMessage(ctx interface{}, id interface{}) *MockOutboxRepository_RetryOutboxMessage_Call {
+	return &MockOutboxRepository_RetryOutboxMessage_Call{Call: _e.mock.On("RetryOutboxMessage", ctx, id)}
+}
+
+func (_c *MockOutboxRepository_RetryOutboxMessage_Call) Run(run func(ctx context.Context, id int64)) *MockOutboxRepository_RetryOutboxMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_RetryOutboxMessage_Call) Return(_a0 *models.OutboxMessage, _a1 error) *MockOutboxRepository_RetryOutboxMessage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOutboxRepository_RetryOutboxMessage_Call) RunAndReturn(run func(context.Context, int64) (*models.OutboxMessage, error)) *MockOutboxRepository_RetryOutboxMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockOutboxRepository creates a new instance of MockOutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOutboxRepository {
+	mock := &MockOutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
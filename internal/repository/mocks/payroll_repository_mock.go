@@ -0,0 +1,341 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockPayrollRepository is an autogenerated mock type for the PayrollRepository type
+type MockPayrollRepository struct {
+	mock.Mock
+}
+
+type MockPayrollRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPayrollRepository) EXPECT() *MockPayrollRepository_Expecter {
+	return &MockPayrollRepository_Expecter{mock: &_m.Mock}
+}
+
+// ClosePayrollPeriod provides a mock function with given fields: ctx, id, actorUserID
+func (_m *MockPayrollRepository) ClosePayrollPeriod(ctx context.Context, id int, actorUserID int) (*models.PayrollPeriod, error) {
+	ret := _m.Called(ctx, id, actorUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClosePayrollPeriod")
+	}
+
+	var r0 *models.PayrollPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.PayrollPeriod, error)); ok {
+		return rf(ctx, id, actorUserID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.PayrollPeriod); ok {
+		r0 = rf(ctx, id, actorUserID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PayrollPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, id, actorUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPayrollRepository_ClosePayrollPeriod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClosePayrollPeriod'
+type MockPayrollRepository_ClosePayrollPeriod_Call struct {
+	*mock.Call
+}
+
+// ClosePayrollPeriod is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - actorUserID int
+func (_e *MockPayrollRepository_Expecter) ClosePayrollPeriod(ctx interface{}, id interface{}, actorUserID interface{}) *MockPayrollRepository_ClosePayrollPeriod_Call {
+	return &MockPayrollRepository_ClosePayrollPeriod_Call{Call: _e.mock.On("ClosePayrollPeriod", ctx, id, actorUserID)}
+}
+
+func (_c *MockPayrollRepository_ClosePayrollPeriod_Call) Run(run func(ctx context.Context, id int, actorUserID int)) *MockPayrollRepository_ClosePayrollPeriod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockPayrollRepository_ClosePayrollPeriod_Call) Return(_a0 *models.PayrollPeriod, _a1 error) *MockPayrollRepository_ClosePayrollPeriod_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPayrollRepository_ClosePayrollPeriod_Call) RunAndReturn(run func(context.Context, int, int) (*models.PayrollPeriod, error)) *MockPayrollRepository_ClosePayrollPeriod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreatePayrollPeriod provides a mock function with given fields: ctx, period
+func (_m *MockPayrollRepository) CreatePayrollPeriod(ctx context.Context, period *models.PayrollPeriod) (int, error) {
+	ret := _m.Called(ctx, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePayrollPeriod")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PayrollPeriod) (int, error)); ok {
+		return rf(ctx, period)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PayrollPeriod) int); ok {
+		r0 = rf(ctx, period)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.PayrollPeriod) error); ok {
+		r1 = rf(ctx, period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPayrollRepository_CreatePayrollPeriod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePayrollPeriod'
+type MockPayrollRepository_CreatePayrollPeriod_Call struct {
+	*mock.Call
+}
+
+// CreatePayrollPeriod is a helper method to define mock.On call
+//   - ctx context.Context
+//   - period *models.PayrollPeriod
+func (_e *MockPayrollRepository_Expecter) CreatePayrollPeriod(ctx interface{}, period interface{}) *MockPayrollRepository_CreatePayrollPeriod_Call {
+	return &MockPayrollRepository_CreatePayrollPeriod_Call{Call: _e.mock.On("CreatePayrollPeriod", ctx, period)}
+}
+
+func (_c *MockPayrollRepository_CreatePayrollPeriod_Call) Run(run func(ctx context.Context, period *models.PayrollPeriod)) *MockPayrollRepository_CreatePayrollPeriod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.PayrollPeriod))
+	})
+	return _c
+}
+
+func (_c *MockPayrollRepository_CreatePayrollPeriod_Call) Return(_a0 int, _a1 error) *MockPayrollRepository_CreatePayrollPeriod_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPayrollRepository_CreatePayrollPeriod_Call) RunAndReturn(run func(context.Context, *models.PayrollPeriod) (int, error)) *MockPayrollRepository_CreatePayrollPeriod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllPayrollPeriods provides a mock function with given fields: ctx, page, limit
+func (_m *MockPayrollRepository) GetAllPayrollPeriods(ctx context.Context, page int, limit int) ([]models.PayrollPeriod, int, error) {
+	ret := _m.Called(ctx, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllPayrollPeriods")
+	}
+
+	var r0 []models.PayrollPeriod
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.PayrollPeriod, int, error)); ok {
+		return rf(ctx, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.PayrollPeriod); ok {
+		r0 = rf(ctx, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PayrollPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = rf(ctx, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockPayrollRepository_GetAllPayrollPeriods_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllPayrollPeriods'
+type MockPayrollRepository_GetAllPayrollPeriods_Call struct {
+	*mock.Call
+}
+
+// GetAllPayrollPeriods is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - limit int
+func (_e *MockPayrollRepository_Expecter) GetAllPayrollPeriods(ctx interface{}, page interface{}, limit interface{}) *MockPayrollRepository_GetAllPayrollPeriods_Call {
+	return &MockPayrollRepository_GetAllPayrollPeriods_Call{Call: _e.mock.On("GetAllPayrollPeriods", ctx, page, limit)}
+}
+
+func (_c *MockPayrollRepository_GetAllPayrollPeriods_Call) Run(run func(ctx context.Context, page int, limit int)) *MockPayrollRepository_GetAllPayrollPeriods_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockPayrollRepository_GetAllPayrollPeriods_Call) Return(_a0 []models.PayrollPeriod, _a1 int, _a2 error) *MockPayrollRepository_GetAllPayrollPeriods_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockPayrollRepository_GetAllPayrollPeriods_Call) RunAndReturn(run func(context.Context, int, int) ([]models.PayrollPeriod, int, error)) *MockPayrollRepository_GetAllPayrollPeriods_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPayrollPeriodByID provides a mock function with given fields: ctx, id
+func (_m *MockPayrollRepository) GetPayrollPeriodByID(ctx context.Context, id int) (*models.PayrollPeriod, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayrollPeriodByID")
+	}
+
+	var r0 *models.PayrollPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.PayrollPeriod, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.PayrollPeriod); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PayrollPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPayrollRepository_GetPayrollPeriodByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPayrollPeriodByID'
+type MockPayrollRepository_GetPayrollPeriodByID_Call struct {
+	*mock.Call
+}
+
+// GetPayrollPeriodByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockPayrollRepository_Expecter) GetPayrollPeriodByID(ctx interface{}, id interface{}) *MockPayrollRepository_GetPayrollPeriodByID_Call {
+	return &MockPayrollRepository_GetPayrollPeriodByID_Call{Call: _e.mock.On("GetPayrollPeriodByID", ctx, id)}
+}
+
+func (_c *MockPayrollRepository_GetPayrollPeriodByID_Call) Run(run func(ctx context.Context, id int)) *MockPayrollRepository_GetPayrollPeriodByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockPayrollRepository_GetPayrollPeriodByID_Call) Return(_a0 *models.PayrollPeriod, _a1 error) *MockPayrollRepository_GetPayrollPeriodByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPayrollRepository_GetPayrollPeriodByID_Call) RunAndReturn(run func(context.Context, int) (*models.PayrollPeriod, error)) *MockPayrollRepository_GetPayrollPeriodByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReopenPayrollPeriod provides a mock function with given fields: ctx, id, actorUserID, reason
+func (_m *MockPayrollRepository) ReopenPayrollPeriod(ctx context.Context, id int, actorUserID int, reason string) (*models.PayrollPeriod, error) {
+	ret := _m.Called(ctx, id, actorUserID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReopenPayrollPeriod")
+	}
+
+	var r0 *models.PayrollPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) (*models.PayrollPeriod, error)); ok {
+		return rf(ctx, id, actorUserID, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) *models.PayrollPeriod); ok {
+		r0 = rf(ctx, id, actorUserID, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PayrollPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = rf(ctx, id, actorUserID, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPayrollRepository_ReopenPayrollPeriod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReopenPayrollPeriod'
+type MockPayrollRepository_ReopenPayrollPeriod_Call struct {
+	*mock.Call
+}
+
+// ReopenPayrollPeriod is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - actorUserID int
+//   - reason string
+func (_e *MockPayrollRepository_Expecter) ReopenPayrollPeriod(ctx interface{}, id interface{}, actorUserID interface{}, reason interface{}) *MockPayrollRepository_ReopenPayrollPeriod_Call {
+	return &MockPayrollRepository_ReopenPayrollPeriod_Call{Call: _e.mock.On("ReopenPayrollPeriod", ctx, id, actorUserID, reason)}
+}
+
+func (_c *MockPayrollRepository_ReopenPayrollPeriod_Call) Run(run func(ctx context.Context, id int, actorUserID int, reason string)) *MockPayrollRepository_ReopenPayrollPeriod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockPayrollRepository_ReopenPayrollPeriod_Call) Return(_a0 *models.PayrollPeriod, _a1 error) *MockPayrollRepository_ReopenPayrollPeriod_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPayrollRepository_ReopenPayrollPeriod_Call) RunAndReturn(run func(context.Context, int, int, string) (*models.PayrollPeriod, error)) *MockPayrollRepository_ReopenPayrollPeriod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockPayrollRepository creates a new instance of MockPayrollRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPayrollRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPayrollRepository {
+	mock := &MockPayrollRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
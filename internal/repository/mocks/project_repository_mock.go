@@ -0,0 +1,306 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockProjectRepository is an autogenerated mock type for the ProjectRepository type
+type MockProjectRepository struct {
+	mock.Mock
+}
+
+type MockProjectRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockProjectRepository) EXPECT() *MockProjectRepository_Expecter {
+	return &MockProjectRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateProject provides a mock function with given fields: ctx, project
+func (_m *MockProjectRepository) CreateProject(ctx context.Context, project *models.Project) (int, error) {
+	ret := _m.Called(ctx, project)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateProject")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Project) (int, error)); ok {
+		return rf(ctx, project)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Project) int); ok {
+		r0 = rf(ctx, project)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Project) error); ok {
+		r1 = rf(ctx, project)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockProjectRepository_CreateProject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateProject'
+type MockProjectRepository_CreateProject_Call struct {
+	*mock.Call
+}
+
+// CreateProject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - project *models.Project
+func (_e *MockProjectRepository_Expecter) CreateProject(ctx interface{}, project interface{}) *MockProjectRepository_CreateProject_Call {
+	return &MockProjectRepository_CreateProject_Call{Call: _e.mock.On("CreateProject", ctx, project)}
+}
+
+func (_c *MockProjectRepository_CreateProject_Call) Run(run func(ctx context.Context, project *models.Project)) *MockProjectRepository_CreateProject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Project))
+	})
+	return _c
+}
+
+func (_c *MockProjectRepository_CreateProject_Call) Return(_a0 int, _a1 error) *MockProjectRepository_CreateProject_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockProjectRepository_CreateProject_Call) RunAndReturn(run func(context.Context, *models.Project) (int, error)) *MockProjectRepository_CreateProject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteProject provides a mock function with given fields: ctx, id
+func (_m *MockProjectRepository) DeleteProject(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProject")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockProjectRepository_DeleteProject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteProject'
+type MockProjectRepository_DeleteProject_Call struct {
+	*mock.Call
+}
+
+// DeleteProject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockProjectRepository_Expecter) DeleteProject(ctx interface{}, id interface{}) *MockProjectRepository_DeleteProject_Call {
+	return &MockProjectRepository_DeleteProject_Call{Call: _e.mock.On("DeleteProject", ctx, id)}
+}
+
+func (_c *MockProjectRepository_DeleteProject_Call) Run(run func(ctx context.Context, id int)) *MockProjectRepository_DeleteProject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockProjectRepository_DeleteProject_Call) Return(_a0 error) *MockProjectRepository_DeleteProject_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockProjectRepository_DeleteProject_Call) RunAndReturn(run func(context.Context, int) error) *MockProjectRepository_DeleteProject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllProjects provides a mock function with given fields: ctx, activeOnly
+func (_m *MockProjectRepository) GetAllProjects(ctx context.Context, activeOnly bool) ([]models.Project, error) {
+	ret := _m.Called(ctx, activeOnly)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllProjects")
+	}
+
+	var r0 []models.Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool) ([]models.Project, error)); ok {
+		return rf(ctx, activeOnly)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bool) []models.Project); ok {
+		r0 = rf(ctx, activeOnly)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = rf(ctx, activeOnly)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockProjectRepository_GetAllProjects_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllProjects'
+type MockProjectRepository_GetAllProjects_Call struct {
+	*mock.Call
+}
+
+// GetAllProjects is a helper method to define mock.On call
+//   - ctx context.Context
+//   - activeOnly bool
+func (_e *MockProjectRepository_Expecter) GetAllProjects(ctx interface{}, activeOnly interface{}) *MockProjectRepository_GetAllProjects_Call {
+	return &MockProjectRepository_GetAllProjects_Call{Call: _e.mock.On("GetAllProjects", ctx, activeOnly)}
+}
+
+func (_c *MockProjectRepository_GetAllProjects_Call) Run(run func(ctx context.Context, activeOnly bool)) *MockProjectRepository_GetAllProjects_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(bool))
+	})
+	return _c
+}
+
+func (_c *MockProjectRepository_GetAllProjects_Call) Return(_a0 []models.Project, _a1 error) *MockProjectRepository_GetAllProjects_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockProjectRepository_GetAllProjects_Call) RunAndReturn(run func(context.Context, bool) ([]models.Project, error)) *MockProjectRepository_GetAllProjects_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProjectByID provides a mock function with given fields: ctx, id
+func (_m *MockProjectRepository) GetProjectByID(ctx context.Context, id int) (*models.Project, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProjectByID")
+	}
+
+	var r0 *models.Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Project, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Project); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockProjectRepository_GetProjectByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProjectByID'
+type MockProjectRepository_GetProjectByID_Call struct {
+	*mock.Call
+}
+
+// GetProjectByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockProjectRepository_Expecter) GetProjectByID(ctx interface{}, id interface{}) *MockProjectRepository_GetProjectByID_Call {
+	return &MockProjectRepository_GetProjectByID_Call{Call: _e.mock.On("GetProjectByID", ctx, id)}
+}
+
+func (_c *MockProjectRepository_GetProjectByID_Call) Run(run func(ctx context.Context, id int)) *MockProjectRepository_GetProjectByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockProjectRepository_GetProjectByID_Call) Return(_a0 *models.Project, _a1 error) *MockProjectRepository_GetProjectByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockProjectRepository_GetProjectByID_Call) RunAndReturn(run func(context.Context, int) (*models.Project, error)) *MockProjectRepository_GetProjectByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateProject provides a mock function with given fields: ctx, project
+func (_m *MockProjectRepository) UpdateProject(ctx context.Context, project *models.Project) error {
+	ret := _m.Called(ctx, project)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProject")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Project) error); ok {
+		r0 = rf(ctx, project)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockProjectRepository_UpdateProject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateProject'
+type MockProjectRepository_UpdateProject_Call struct {
+	*mock.Call
+}
+
+// UpdateProject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - project *models.Project
+func (_e *MockProjectRepository_Expecter) UpdateProject(ctx interface{}, project interface{}) *MockProjectRepository_UpdateProject_Call {
+	return &MockProjectRepository_UpdateProject_Call{Call: _e.mock.On("UpdateProject", ctx, project)}
+}
+
+func (_c *MockProjectRepository_UpdateProject_Call) Run(run func(ctx context.Context, project *models.Project)) *MockProjectRepository_UpdateProject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Project))
+	})
+	return _c
+}
+
+func (_c *MockProjectRepository_UpdateProject_Call) Return(_a0 error) *MockProjectRepository_UpdateProject_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockProjectRepository_UpdateProject_Call) RunAndReturn(run func(context.Context, *models.Project) error) *MockProjectRepository_UpdateProject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockProjectRepository creates a new instance of MockProjectRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockProjectRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockProjectRepository {
+	mock := &MockProjectRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
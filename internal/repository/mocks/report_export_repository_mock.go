@@ -0,0 +1,719 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockReportExportRepository is an autogenerated mock type for the ReportExportRepository type
+type MockReportExportRepository struct {
+	mock.Mock
+}
+
+type MockReportExportRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockReportExportRepository) EXPECT() *MockReportExportRepository_Expecter {
+	return &MockReportExportRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateReportExport provides a mock function with given fields: ctx, report
+func (_m *MockReportExportRepository) CreateReportExport(ctx context.Context, report *models.ReportExport) error {
+	ret := _m.Called(ctx, report)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReportExport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ReportExport) error); ok {
+		r0 = rf(ctx, report)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockReportExportRepository_CreateReportExport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateReportExport'
+type MockReportExportRepository_CreateReportExport_Call struct {
+	*mock.Call
+}
+
+// CreateReportExport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - report *models.ReportExport
+func (_e *MockReportExportRepository_Expecter) CreateReportExport(ctx interface{}, report interface{}) *MockReportExportRepository_CreateReportExport_Call {
+	return &MockReportExportRepository_CreateReportExport_Call{Call: _e.mock.On("CreateReportExport", ctx, report)}
+}
+
+func (_c *MockReportExportRepository_CreateReportExport_Call) Run(run func(ctx context.Context, report *models.ReportExport)) *MockReportExportRepository_CreateReportExport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.ReportExport))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_CreateReportExport_Call) Return(_a0 error) *MockReportExportRepository_CreateReportExport_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReportExportRepository_CreateReportExport_Call) RunAndReturn(run func(context.Context, *models.ReportExport) error) *MockReportExportRepository_CreateReportExport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateReportSnapshot provides a mock function with given fields: ctx, snapshot
+func (_m *MockReportExportRepository) CreateReportSnapshot(ctx context.Context, snapshot *models.ReportSnapshot) error {
+	ret := _m.Called(ctx, snapshot)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReportSnapshot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ReportSnapshot) error); ok {
+		r0 = rf(ctx, snapshot)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockReportExportRepository_CreateReportSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateReportSnapshot'
+type MockReportExportRepository_CreateReportSnapshot_Call struct {
+	*mock.Call
+}
+
+// CreateReportSnapshot is a helper method to define mock.On call
+//   - ctx context.Context
+//   - snapshot *models.ReportSnapshot
+func (_e *MockReportExportRepository_Expecter) CreateReportSnapshot(ctx interface{}, snapshot interface{}) *MockReportExportRepository_CreateReportSnapshot_Call {
+	return &MockReportExportRepository_CreateReportSnapshot_Call{Call: _e.mock.On("CreateReportSnapshot", ctx, snapshot)}
+}
+
+func (_c *MockReportExportRepository_CreateReportSnapshot_Call) Run(run func(ctx context.Context, snapshot *models.ReportSnapshot)) *MockReportExportRepository_CreateReportSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.ReportSnapshot))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_CreateReportSnapshot_Call) Return(_a0 error) *MockReportExportRepository_CreateReportSnapshot_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReportExportRepository_CreateReportSnapshot_Call) RunAndReturn(run func(context.Context, *models.ReportSnapshot) error) *MockReportExportRepository_CreateReportSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetExpiredReportExports provides a mock function with given fields: ctx, before, limit
+func (_m *MockReportExportRepository) GetExpiredReportExports(ctx context.Context, before time.Time, limit int) ([]models.ReportExport, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExpiredReportExports")
+	}
+
+	var r0 []models.ReportExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.ReportExport, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.ReportExport); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ReportExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockReportExportRepository_GetExpiredReportExports_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetExpiredReportExports'
+type MockReportExportRepository_GetExpiredReportExports_Call struct {
+	*mock.Call
+}
+
+// GetExpiredReportExports is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+//   - limit int
+func (_e *MockReportExportRepository_Expecter) GetExpiredReportExports(ctx interface{}, before interface{}, limit interface{}) *MockReportExportRepository_GetExpiredReportExports_Call {
+	return &MockReportExportRepository_GetExpiredReportExports_Call{Call: _e.mock.On("GetExpiredReportExports", ctx, before, limit)}
+}
+
+func (_c *MockReportExportRepository_GetExpiredReportExports_Call) Run(run func(ctx context.Context, before time.Time, limit int)) *MockReportExportRepository_GetExpiredReportExports_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetExpiredReportExports_Call) Return(_a0 []models.ReportExport, _a1 error) *MockReportExportRepository_GetExpiredReportExports_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetExpiredReportExports_Call) RunAndReturn(run func(context.Context, time.Time, int) ([]models.ReportExport, error)) *MockReportExportRepository_GetExpiredReportExports_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPendingReportExports provides a mock function with given fields: ctx, limit
+func (_m *MockReportExportRepository) GetPendingReportExports(ctx context.Context, limit int) ([]models.ReportExport, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingReportExports")
+	}
+
+	var r0 []models.ReportExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.ReportExport, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.ReportExport); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ReportExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockReportExportRepository_GetPendingReportExports_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingReportExports'
+type MockReportExportRepository_GetPendingReportExports_Call struct {
+	*mock.Call
+}
+
+// GetPendingReportExports is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+func (_e *MockReportExportRepository_Expecter) GetPendingReportExports(ctx interface{}, limit interface{}) *MockReportExportRepository_GetPendingReportExports_Call {
+	return &MockReportExportRepository_GetPendingReportExports_Call{Call: _e.mock.On("GetPendingReportExports", ctx, limit)}
+}
+
+func (_c *MockReportExportRepository_GetPendingReportExports_Call) Run(run func(ctx context.Context, limit int)) *MockReportExportRepository_GetPendingReportExports_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetPendingReportExports_Call) Return(_a0 []models.ReportExport, _a1 error) *MockReportExportRepository_GetPendingReportExports_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetPendingReportExports_Call) RunAndReturn(run func(context.Context, int) ([]models.ReportExport, error)) *MockReportExportRepository_GetPendingReportExports_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetReportExportByID provides a mock function with given fields: ctx, id
+func (_m *MockReportExportRepository) GetReportExportByID(ctx context.Context, id int) (*models.ReportExport, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReportExportByID")
+	}
+
+	var r0 *models.ReportExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.ReportExport, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.ReportExport); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReportExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockReportExportRepository_GetReportExportByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReportExportByID'
+type MockReportExportRepository_GetReportExportByID_Call struct {
+	*mock.Call
+}
+
+// GetReportExportByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockReportExportRepository_Expecter) GetReportExportByID(ctx interface{}, id interface{}) *MockReportExportRepository_GetReportExportByID_Call {
+	return &MockReportExportRepository_GetReportExportByID_Call{Call: _e.mock.On("GetReportExportByID", ctx, id)}
+}
+
+func (_c *MockReportExportRepository_GetReportExportByID_Call) Run(run func(ctx context.Context, id int)) *MockReportExportRepository_GetReportExportByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetReportExportByID_Call) Return(_a0 *models.ReportExport, _a1 error) *MockReportExportRepository_GetReportExportByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetReportExportByID_Call) RunAndReturn(run func(context.Context, int) (*models.ReportExport, error)) *MockReportExportRepository_GetReportExportByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetReportExports provides a mock function with given fields: ctx, page, limit
+func (_m *MockReportExportRepository) GetReportExports(ctx context.Context, page int, limit int) ([]models.ReportExport, int, error) {
+	ret := _m.Called(ctx, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReportExports")
+	}
+
+	var r0 []models.ReportExport
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.ReportExport, int, error)); ok {
+		return rf(ctx, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.ReportExport); ok {
+		r0 = rf(ctx, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ReportExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = rf(ctx, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockReportExportRepository_GetReportExports_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReportExports'
+type MockReportExportRepository_GetReportExports_Call struct {
+	*mock.Call
+}
+
+// GetReportExports is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - limit int
+func (_e *MockReportExportRepository_Expecter) GetReportExports(ctx interface{}, page interface{}, limit interface{}) *MockReportExportRepository_GetReportExports_Call {
+	return &MockReportExportRepository_GetReportExports_Call{Call: _e.mock.On("GetReportExports", ctx, page, limit)}
+}
+
+func (_c *MockReportExportRepository_GetReportExports_Call) Run(run func(ctx context.Context, page int, limit int)) *MockReportExportRepository_GetReportExports_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetReportExports_Call) Return(_a0 []models.ReportExport, _a1 int, _a2 error) *MockReportExportRepository_GetReportExports_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetReportExports_Call) RunAndReturn(run func(context.Context, int, int) ([]models.ReportExport, int, error)) *MockReportExportRepository_GetReportExports_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetReportSnapshotByID provides a mock function with given fields: ctx, id
+func (_m *MockReportExportRepository) GetReportSnapshotByID(ctx context.Context, id int) (*models.ReportSnapshot, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReportSnapshotByID")
+	}
+
+	var r0 *models.ReportSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.ReportSnapshot, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.ReportSnapshot); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReportSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockReportExportRepository_GetReportSnapshotByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReportSnapshotByID'
+type MockReportExportRepository_GetReportSnapshotByID_Call struct {
+	*mock.Call
+}
+
+// GetReportSnapshotByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockReportExportRepository_Expecter) GetReportSnapshotByID(ctx interface{}, id interface{}) *MockReportExportRepository_GetReportSnapshotByID_Call {
+	return &MockReportExportRepository_GetReportSnapshotByID_Call{Call: _e.mock.On("GetReportSnapshotByID", ctx, id)}
+}
+
+func (_c *MockReportExportRepository_GetReportSnapshotByID_Call) Run(run func(ctx context.Context, id int)) *MockReportExportRepository_GetReportSnapshotByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetReportSnapshotByID_Call) Return(_a0 *models.ReportSnapshot, _a1 error) *MockReportExportRepository_GetReportSnapshotByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetReportSnapshotByID_Call) RunAndReturn(run func(context.Context, int) (*models.ReportSnapshot, error)) *MockReportExportRepository_GetReportSnapshotByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetReportSnapshots provides a mock function with given fields: ctx, page, limit
+func (_m *MockReportExportRepository) GetReportSnapshots(ctx context.Context, page int, limit int) ([]models.ReportSnapshot, int, error) {
+	ret := _m.Called(ctx, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReportSnapshots")
+	}
+
+	var r0 []models.ReportSnapshot
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.ReportSnapshot, int, error)); ok {
+		return rf(ctx, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.ReportSnapshot); ok {
+		r0 = rf(ctx, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ReportSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = rf(ctx, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockReportExportRepository_GetReportSnapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReportSnapshots'
+type MockReportExportRepository_GetReportSnapshots_Call struct {
+	*mock.Call
+}
+
+// GetReportSnapshots is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - limit int
+func (_e *MockReportExportRepository_Expecter) GetReportSnapshots(ctx interface{}, page interface{}, limit interface{}) *MockReportExportRepository_GetReportSnapshots_Call {
+	return &MockReportExportRepository_GetReportSnapshots_Call{Call: _e.mock.On("GetReportSnapshots", ctx, page, limit)}
+}
+
+func (_c *MockReportExportRepository_GetReportSnapshots_Call) Run(run func(ctx context.Context, page int, limit int)) *MockReportExportRepository_GetReportSnapshots_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetReportSnapshots_Call) Return(_a0 []models.ReportSnapshot, _a1 int, _a2 error) *MockReportExportRepository_GetReportSnapshots_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetReportSnapshots_Call) RunAndReturn(run func(context.Context, int, int) ([]models.ReportSnapshot, int, error)) *MockReportExportRepository_GetReportSnapshots_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSnapshotSessions provides a mock function with given fields: ctx, startDate, endDate
+func (_m *MockReportExportRepository) GetSnapshotSessions(ctx context.Context, startDate time.Time, endDate time.Time) ([]models.Attendance, error) {
+	ret := _m.Called(ctx, startDate, endDate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSnapshotSessions")
+	}
+
+	var r0 []models.Attendance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]models.Attendance, error)); ok {
+		return rf(ctx, startDate, endDate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []models.Attendance); ok {
+		r0 = rf(ctx, startDate, endDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Attendance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, startDate, endDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockReportExportRepository_GetSnapshotSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSnapshotSessions'
+type MockReportExportRepository_GetSnapshotSessions_Call struct {
+	*mock.Call
+}
+
+// GetSnapshotSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startDate time.Time
+//   - endDate time.Time
+func (_e *MockReportExportRepository_Expecter) GetSnapshotSessions(ctx interface{}, startDate interface{}, endDate interface{}) *MockReportExportRepository_GetSnapshotSessions_Call {
+	return &MockReportExportRepository_GetSnapshotSessions_Call{Call: _e.mock.On("GetSnapshotSessions", ctx, startDate, endDate)}
+}
+
+func (_c *MockReportExportRepository_GetSnapshotSessions_Call) Run(run func(ctx context.Context, startDate time.Time, endDate time.Time)) *MockReportExportRepository_GetSnapshotSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetSnapshotSessions_Call) Return(_a0 []models.Attendance, _a1 error) *MockReportExportRepository_GetSnapshotSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockReportExportRepository_GetSnapshotSessions_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) ([]models.Attendance, error)) *MockReportExportRepository_GetSnapshotSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkReportExportCompleted provides a mock function with given fields: ctx, id, result
+func (_m *MockReportExportRepository) MarkReportExportCompleted(ctx context.Context, id int, result models.ReportExportResult) (*models.ReportExport, error) {
+	ret := _m.Called(ctx, id, result)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkReportExportCompleted")
+	}
+
+	var r0 *models.ReportExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.ReportExportResult) (*models.ReportExport, error)); ok {
+		return rf(ctx, id, result)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.ReportExportResult) *models.ReportExport); ok {
+		r0 = rf(ctx, id, result)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReportExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.ReportExportResult) error); ok {
+		r1 = rf(ctx, id, result)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockReportExportRepository_MarkReportExportCompleted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkReportExportCompleted'
+type MockReportExportRepository_MarkReportExportCompleted_Call struct {
+	*mock.Call
+}
+
+// MarkReportExportCompleted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - result models.ReportExportResult
+func (_e *MockReportExportRepository_Expecter) MarkReportExportCompleted(ctx interface{}, id interface{}, result interface{}) *MockReportExportRepository_MarkReportExportCompleted_Call {
+	return &MockReportExportRepository_MarkReportExportCompleted_Call{Call: _e.mock.On("MarkReportExportCompleted", ctx, id, result)}
+}
+
+func (_c *MockReportExportRepository_MarkReportExportCompleted_Call) Run(run func(ctx context.Context, id int, result models.ReportExportResult)) *MockReportExportRepository_MarkReportExportCompleted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.ReportExportResult))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_MarkReportExportCompleted_Call) Return(_a0 *models.ReportExport, _a1 error) *MockReportExportRepository_MarkReportExportCompleted_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockReportExportRepository_MarkReportExportCompleted_Call) RunAndReturn(run func(context.Context, int, models.ReportExportResult) (*models.ReportExport, error)) *MockReportExportRepository_MarkReportExportCompleted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkReportExportFailed provides a mock function with given fields: ctx, id, reason
+func (_m *MockReportExportRepository) MarkReportExportFailed(ctx context.Context, id int, reason string) error {
+	ret := _m.Called(ctx, id, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkReportExportFailed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, id, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockReportExportRepository_MarkReportExportFailed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkReportExportFailed'
+type MockReportExportRepository_MarkReportExportFailed_Call struct {
+	*mock.Call
+}
+
+// MarkReportExportFailed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - reason string
+func (_e *MockReportExportRepository_Expecter) MarkReportExportFailed(ctx interface{}, id interface{}, reason interface{}) *MockReportExportRepository_MarkReportExportFailed_Call {
+	return &MockReportExportRepository_MarkReportExportFailed_Call{Call: _e.mock.On("MarkReportExportFailed", ctx, id, reason)}
+}
+
+func (_c *MockReportExportRepository_MarkReportExportFailed_Call) Run(run func(ctx context.Context, id int, reason string)) *MockReportExportRepository_MarkReportExportFailed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_MarkReportExportFailed_Call) Return(_a0 error) *MockReportExportRepository_MarkReportExportFailed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReportExportRepository_MarkReportExportFailed_Call) RunAndReturn(run func(context.Context, int, string) error) *MockReportExportRepository_MarkReportExportFailed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkReportExportPurged provides a mock function with given fields: ctx, id
+func (_m *MockReportExportRepository) MarkReportExportPurged(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkReportExportPurged")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockReportExportRepository_MarkReportExportPurged_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkReportExportPurged'
+type MockReportExportRepository_MarkReportExportPurged_Call struct {
+	*mock.Call
+}
+
+// MarkReportExportPurged is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockReportExportRepository_Expecter) MarkReportExportPurged(ctx interface{}, id interface{}) *MockReportExportRepository_MarkReportExportPurged_Call {
+	return &MockReportExportRepository_MarkReportExportPurged_Call{Call: _e.mock.On("MarkReportExportPurged", ctx, id)}
+}
+
+func (_c *MockReportExportRepository_MarkReportExportPurged_Call) Run(run func(ctx context.Context, id int)) *MockReportExportRepository_MarkReportExportPurged_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockReportExportRepository_MarkReportExportPurged_Call) Return(_a0 error) *MockReportExportRepository_MarkReportExportPurged_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReportExportRepository_MarkReportExportPurged_Call) RunAndReturn(run func(context.Context, int) error) *MockReportExportRepository_MarkReportExportPurged_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockReportExportRepository creates a new instance of MockReportExportRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockReportExportRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockReportExportRepository {
+	mock := &MockReportExportRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,424 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRoleRepository is an autogenerated mock type for the RoleRepository type
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+type MockRoleRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRoleRepository) EXPECT() *MockRoleRepository_Expecter {
+	return &MockRoleRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateRole provides a mock function with given fields: ctx, role
+func (_m *MockRoleRepository) CreateRole(ctx context.Context, role *models.Role) (int, error) {
+	ret := _m.Called(ctx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRole")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Role) (int, error)); ok {
+		return rf(ctx, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Role) int); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Role) error); ok {
+		r1 = rf(ctx, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRoleRepository_CreateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRole'
+type MockRoleRepository_CreateRole_Call struct {
+	*mock.Call
+}
+
+// CreateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - role *models.Role
+func (_e *MockRoleRepository_Expecter) CreateRole(ctx interface{}, role interface{}) *MockRoleRepository_CreateRole_Call {
+	return &MockRoleRepository_CreateRole_Call{Call: _e.mock.On("CreateRole", ctx, role)}
+}
+
+func (_c *MockRoleRepository_CreateRole_Call) Run(run func(ctx context.Context, role *models.Role)) *MockRoleRepository_CreateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Role))
+	})
+	return _c
+}
+
+func (_c *MockRoleRepository_CreateRole_Call) Return(_a0 int, _a1 error) *MockRoleRepository_CreateRole_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRoleRepository_CreateRole_Call) RunAndReturn(run func(context.Context, *models.Role) (int, error)) *MockRoleRepository_CreateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRole provides a mock function with given fields: ctx, id
+func (_m *MockRoleRepository) DeleteRole(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRoleRepository_DeleteRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRole'
+type MockRoleRepository_DeleteRole_Call struct {
+	*mock.Call
+}
+
+// DeleteRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockRoleRepository_Expecter) DeleteRole(ctx interface{}, id interface{}) *MockRoleRepository_DeleteRole_Call {
+	return &MockRoleRepository_DeleteRole_Call{Call: _e.mock.On("DeleteRole", ctx, id)}
+}
+
+func (_c *MockRoleRepository_DeleteRole_Call) Run(run func(ctx context.Context, id int)) *MockRoleRepository_DeleteRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockRoleRepository_DeleteRole_Call) Return(_a0 error) *MockRoleRepository_DeleteRole_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRoleRepository_DeleteRole_Call) RunAndReturn(run func(context.Context, int) error) *MockRoleRepository_DeleteRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllRoles provides a mock function with given fields: ctx
+func (_m *MockRoleRepository) GetAllRoles(ctx context.Context) ([]models.Role, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllRoles")
+	}
+
+	var r0 []models.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Role, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Role); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRoleRepository_GetAllRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllRoles'
+type MockRoleRepository_GetAllRoles_Call struct {
+	*mock.Call
+}
+
+// GetAllRoles is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockRoleRepository_Expecter) GetAllRoles(ctx interface{}) *MockRoleRepository_GetAllRoles_Call {
+	return &MockRoleRepository_GetAllRoles_Call{Call: _e.mock.On("GetAllRoles", ctx)}
+}
+
+func (_c *MockRoleRepository_GetAllRoles_Call) Run(run func(ctx context.Context)) *MockRoleRepository_GetAllRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockRoleRepository_GetAllRoles_Call) Return(_a0 []models.Role, _a1 error) *MockRoleRepository_GetAllRoles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRoleRepository_GetAllRoles_Call) RunAndReturn(run func(context.Context) ([]models.Role, error)) *MockRoleRepository_GetAllRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetInheritedRoles provides a mock function with given fields: ctx, roleName
+func (_m *MockRoleRepository) GetInheritedRoles(ctx context.Context, roleName string) ([]string, error) {
+	ret := _m.Called(ctx, roleName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInheritedRoles")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return rf(ctx, roleName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(ctx, roleName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, roleName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRoleRepository_GetInheritedRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInheritedRoles'
+type MockRoleRepository_GetInheritedRoles_Call struct {
+	*mock.Call
+}
+
+// GetInheritedRoles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - roleName string
+func (_e *MockRoleRepository_Expecter) GetInheritedRoles(ctx interface{}, roleName interface{}) *MockRoleRepository_GetInheritedRoles_Call {
+	return &MockRoleRepository_GetInheritedRoles_Call{Call: _e.mock.On("GetInheritedRoles", ctx, roleName)}
+}
+
+func (_c *MockRoleRepository_GetInheritedRoles_Call) Run(run func(ctx context.Context, roleName string)) *MockRoleRepository_GetInheritedRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockRoleRepository_GetInheritedRoles_Call) Return(_a0 []string, _a1 error) *MockRoleRepository_GetInheritedRoles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRoleRepository_GetInheritedRoles_Call) RunAndReturn(run func(context.Context, string) ([]string, error)) *MockRoleRepository_GetInheritedRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRoleByID provides a mock function with given fields: ctx, id
+func (_m *MockRoleRepository) GetRoleByID(ctx context.Context, id int) (*models.Role, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoleByID")
+	}
+
+	var r0 *models.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Role, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Role); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRoleRepository_GetRoleByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRoleByID'
+type MockRoleRepository_GetRoleByID_Call struct {
+	*mock.Call
+}
+
+// GetRoleByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockRoleRepository_Expecter) GetRoleByID(ctx interface{}, id interface{}) *MockRoleRepository_GetRoleByID_Call {
+	return &MockRoleRepository_GetRoleByID_Call{Call: _e.mock.On("GetRoleByID", ctx, id)}
+}
+
+func (_c *MockRoleRepository_GetRoleByID_Call) Run(run func(ctx context.Context, id int)) *MockRoleRepository_GetRoleByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockRoleRepository_GetRoleByID_Call) Return(_a0 *models.Role, _a1 error) *MockRoleRepository_GetRoleByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRoleRepository_GetRoleByID_Call) RunAndReturn(run func(context.Context, int) (*models.Role, error)) *MockRoleRepository_GetRoleByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetRoleParent provides a mock function with given fields: ctx, roleID, parentID
+func (_m *MockRoleRepository) SetRoleParent(ctx context.Context, roleID int, parentID *int) (*models.Role, error) {
+	ret := _m.Called(ctx, roleID, parentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRoleParent")
+	}
+
+	var r0 *models.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *int) (*models.Role, error)); ok {
+		return rf(ctx, roleID, parentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *int) *models.Role); ok {
+		r0 = rf(ctx, roleID, parentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *int) error); ok {
+		r1 = rf(ctx, roleID, parentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRoleRepository_SetRoleParent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRoleParent'
+type MockRoleRepository_SetRoleParent_Call struct {
+	*mock.Call
+}
+
+// SetRoleParent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - roleID int
+//   - parentID *int
+func (_e *MockRoleRepository_Expecter) SetRoleParent(ctx interface{}, roleID interface{}, parentID interface{}) *MockRoleRepository_SetRoleParent_Call {
+	return &MockRoleRepository_SetRoleParent_Call{Call: _e.mock.On("SetRoleParent", ctx, roleID, parentID)}
+}
+
+func (_c *MockRoleRepository_SetRoleParent_Call) Run(run func(ctx context.Context, roleID int, parentID *int)) *MockRoleRepository_SetRoleParent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*int))
+	})
+	return _c
+}
+
+func (_c *MockRoleRepository_SetRoleParent_Call) Return(_a0 *models.Role, _a1 error) *MockRoleRepository_SetRoleParent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRoleRepository_SetRoleParent_Call) RunAndReturn(run func(context.Context, int, *int) (*models.Role, error)) *MockRoleRepository_SetRoleParent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRole provides a mock function with given fields: ctx, role
+func (_m *MockRoleRepository) UpdateRole(ctx context.Context, role *models.Role) error {
+	ret := _m.Called(ctx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Role) error); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRoleRepository_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
+type MockRoleRepository_UpdateRole_Call struct {
+	*mock.Call
+}
+
+// UpdateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - role *models.Role
+func (_e *MockRoleRepository_Expecter) UpdateRole(ctx interface{}, role interface{}) *MockRoleRepository_UpdateRole_Call {
+	return &MockRoleRepository_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, role)}
+}
+
+func (_c *MockRoleRepository_UpdateRole_Call) Run(run func(ctx context.Context, role *models.Role)) *MockRoleRepository_UpdateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Role))
+	})
+	return _c
+}
+
+func (_c *MockRoleRepository_UpdateRole_Call) Return(_a0 error) *MockRoleRepository_UpdateRole_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRoleRepository_UpdateRole_Call) RunAndReturn(run func(context.Context, *models.Role) error) *MockRoleRepository_UpdateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRoleRepository creates a new instance of MockRoleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRoleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRoleRepository {
+	mock := &MockRoleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,36 +1,876 @@
+// Code generated by mockery. DO NOT EDIT.
+
 package mocks
 
 import (
-	"context"
-	"time"
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
 
-	"github.com/stretchr/testify/mock"
-	"github.com/rakaarfi/attendance-system-be/internal/models"
+	time "time"
 )
 
-// MockScheduleRepository mocks the ScheduleRepository interface.
+// MockScheduleRepository is an autogenerated mock type for the ScheduleRepository type
 type MockScheduleRepository struct {
 	mock.Mock
 }
 
-func (m *MockScheduleRepository) CreateSchedule(ctx context.Context, schedule *models.UserSchedule) (int, error) {
-	args := m.Called(ctx, schedule)
-	return args.Int(0), args.Error(1)
+type MockScheduleRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockScheduleRepository) EXPECT() *MockScheduleRepository_Expecter {
+	return &MockScheduleRepository_Expecter{mock: &_m.Mock}
+}
+
+// BulkDeleteSchedules provides a mock function with given fields: ctx, ids, userIDs, startDate, endDate
+func (_m *MockScheduleRepository) BulkDeleteSchedules(ctx context.Context, ids []int, userIDs []int, startDate *time.Time, endDate *time.Time) (*models.BulkDeleteSchedulesResult, error) {
+	ret := _m.Called(ctx, ids, userIDs, startDate, endDate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkDeleteSchedules")
+	}
+
+	var r0 *models.BulkDeleteSchedulesResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int, []int, *time.Time, *time.Time) (*models.BulkDeleteSchedulesResult, error)); ok {
+		return rf(ctx, ids, userIDs, startDate, endDate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int, []int, *time.Time, *time.Time) *models.BulkDeleteSchedulesResult); ok {
+		r0 = rf(ctx, ids, userIDs, startDate, endDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.BulkDeleteSchedulesResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int, []int, *time.Time, *time.Time) error); ok {
+		r1 = rf(ctx, ids, userIDs, startDate, endDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockScheduleRepository_BulkDeleteSchedules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkDeleteSchedules'
+type MockScheduleRepository_BulkDeleteSchedules_Call struct {
+	*mock.Call
+}
+
+// BulkDeleteSchedules is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int
+//   - userIDs []int
+//   - startDate *time.Time
+//   - endDate *time.Time
+func (_e *MockScheduleRepository_Expecter) BulkDeleteSchedules(ctx interface{}, ids interface{}, userIDs interface{}, startDate interface{}, endDate interface{}) *MockScheduleRepository_BulkDeleteSchedules_Call {
+	return &MockScheduleRepository_BulkDeleteSchedules_Call{Call: _e.mock.On("BulkDeleteSchedules", ctx, ids, userIDs, startDate, endDate)}
+}
+
+func (_c *MockScheduleRepository_BulkDeleteSchedules_Call) Run(run func(ctx context.Context, ids []int, userIDs []int, startDate *time.Time, endDate *time.Time)) *MockScheduleRepository_BulkDeleteSchedules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int), args[2].([]int), args[3].(*time.Time), args[4].(*time.Time))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_BulkDeleteSchedules_Call) Return(_a0 *models.BulkDeleteSchedulesResult, _a1 error) *MockScheduleRepository_BulkDeleteSchedules_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockScheduleRepository_BulkDeleteSchedules_Call) RunAndReturn(run func(context.Context, []int, []int, *time.Time, *time.Time) (*models.BulkDeleteSchedulesResult, error)) *MockScheduleRepository_BulkDeleteSchedules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateSchedule provides a mock function with given fields: ctx, schedule
+func (_m *MockScheduleRepository) CreateSchedule(ctx context.Context, schedule *models.UserSchedule) (int, error) {
+	ret := _m.Called(ctx, schedule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSchedule")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.UserSchedule) (int, error)); ok {
+		return rf(ctx, schedule)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.UserSchedule) int); ok {
+		r0 = rf(ctx, schedule)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.UserSchedule) error); ok {
+		r1 = rf(ctx, schedule)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockScheduleRepository_CreateSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSchedule'
+type MockScheduleRepository_CreateSchedule_Call struct {
+	*mock.Call
+}
+
+// CreateSchedule is a helper method to define mock.On call
+//   - ctx context.Context
+//   - schedule *models.UserSchedule
+func (_e *MockScheduleRepository_Expecter) CreateSchedule(ctx interface{}, schedule interface{}) *MockScheduleRepository_CreateSchedule_Call {
+	return &MockScheduleRepository_CreateSchedule_Call{Call: _e.mock.On("CreateSchedule", ctx, schedule)}
+}
+
+func (_c *MockScheduleRepository_CreateSchedule_Call) Run(run func(ctx context.Context, schedule *models.UserSchedule)) *MockScheduleRepository_CreateSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.UserSchedule))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_CreateSchedule_Call) Return(_a0 int, _a1 error) *MockScheduleRepository_CreateSchedule_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockScheduleRepository_CreateSchedule_Call) RunAndReturn(run func(context.Context, *models.UserSchedule) (int, error)) *MockScheduleRepository_CreateSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteSchedule provides a mock function with given fields: ctx, id
+func (_m *MockScheduleRepository) DeleteSchedule(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSchedule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockScheduleRepository_DeleteSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSchedule'
+type MockScheduleRepository_DeleteSchedule_Call struct {
+	*mock.Call
+}
+
+// DeleteSchedule is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockScheduleRepository_Expecter) DeleteSchedule(ctx interface{}, id interface{}) *MockScheduleRepository_DeleteSchedule_Call {
+	return &MockScheduleRepository_DeleteSchedule_Call{Call: _e.mock.On("DeleteSchedule", ctx, id)}
+}
+
+func (_c *MockScheduleRepository_DeleteSchedule_Call) Run(run func(ctx context.Context, id int)) *MockScheduleRepository_DeleteSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_DeleteSchedule_Call) Return(_a0 error) *MockScheduleRepository_DeleteSchedule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockScheduleRepository_DeleteSchedule_Call) RunAndReturn(run func(context.Context, int) error) *MockScheduleRepository_DeleteSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportSchedulesByUser provides a mock function with given fields: ctx, userID
+func (_m *MockScheduleRepository) ExportSchedulesByUser(ctx context.Context, userID int) ([]models.UserSchedule, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportSchedulesByUser")
+	}
+
+	var r0 []models.UserSchedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.UserSchedule, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.UserSchedule); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UserSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockScheduleRepository_ExportSchedulesByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportSchedulesByUser'
+type MockScheduleRepository_ExportSchedulesByUser_Call struct {
+	*mock.Call
+}
+
+// ExportSchedulesByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockScheduleRepository_Expecter) ExportSchedulesByUser(ctx interface{}, userID interface{}) *MockScheduleRepository_ExportSchedulesByUser_Call {
+	return &MockScheduleRepository_ExportSchedulesByUser_Call{Call: _e.mock.On("ExportSchedulesByUser", ctx, userID)}
+}
+
+func (_c *MockScheduleRepository_ExportSchedulesByUser_Call) Run(run func(ctx context.Context, userID int)) *MockScheduleRepository_ExportSchedulesByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_ExportSchedulesByUser_Call) Return(_a0 []models.UserSchedule, _a1 error) *MockScheduleRepository_ExportSchedulesByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockScheduleRepository_ExportSchedulesByUser_Call) RunAndReturn(run func(context.Context, int) ([]models.UserSchedule, error)) *MockScheduleRepository_ExportSchedulesByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetScheduleByID provides a mock function with given fields: ctx, id
+func (_m *MockScheduleRepository) GetScheduleByID(ctx context.Context, id int) (*models.UserSchedule, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetScheduleByID")
+	}
+
+	var r0 *models.UserSchedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.UserSchedule, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.UserSchedule); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockScheduleRepository_GetScheduleByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetScheduleByID'
+type MockScheduleRepository_GetScheduleByID_Call struct {
+	*mock.Call
+}
+
+// GetScheduleByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockScheduleRepository_Expecter) GetScheduleByID(ctx interface{}, id interface{}) *MockScheduleRepository_GetScheduleByID_Call {
+	return &MockScheduleRepository_GetScheduleByID_Call{Call: _e.mock.On("GetScheduleByID", ctx, id)}
+}
+
+func (_c *MockScheduleRepository_GetScheduleByID_Call) Run(run func(ctx context.Context, id int)) *MockScheduleRepository_GetScheduleByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetScheduleByID_Call) Return(_a0 *models.UserSchedule, _a1 error) *MockScheduleRepository_GetScheduleByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetScheduleByID_Call) RunAndReturn(run func(context.Context, int) (*models.UserSchedule, error)) *MockScheduleRepository_GetScheduleByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetScheduleByUserAndDate provides a mock function with given fields: ctx, userID, date
+func (_m *MockScheduleRepository) GetScheduleByUserAndDate(ctx context.Context, userID int, date time.Time) (*models.UserSchedule, error) {
+	ret := _m.Called(ctx, userID, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetScheduleByUserAndDate")
+	}
+
+	var r0 *models.UserSchedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) (*models.UserSchedule, error)); ok {
+		return rf(ctx, userID, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) *models.UserSchedule); ok {
+		r0 = rf(ctx, userID, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = rf(ctx, userID, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockScheduleRepository_GetScheduleByUserAndDate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetScheduleByUserAndDate'
+type MockScheduleRepository_GetScheduleByUserAndDate_Call struct {
+	*mock.Call
+}
+
+// GetScheduleByUserAndDate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - date time.Time
+func (_e *MockScheduleRepository_Expecter) GetScheduleByUserAndDate(ctx interface{}, userID interface{}, date interface{}) *MockScheduleRepository_GetScheduleByUserAndDate_Call {
+	return &MockScheduleRepository_GetScheduleByUserAndDate_Call{Call: _e.mock.On("GetScheduleByUserAndDate", ctx, userID, date)}
+}
+
+func (_c *MockScheduleRepository_GetScheduleByUserAndDate_Call) Run(run func(ctx context.Context, userID int, date time.Time)) *MockScheduleRepository_GetScheduleByUserAndDate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetScheduleByUserAndDate_Call) Return(_a0 *models.UserSchedule, _a1 error) *MockScheduleRepository_GetScheduleByUserAndDate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetScheduleByUserAndDate_Call) RunAndReturn(run func(context.Context, int, time.Time) (*models.UserSchedule, error)) *MockScheduleRepository_GetScheduleByUserAndDate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetScheduleChanges provides a mock function with given fields: ctx, cursor, limit
+func (_m *MockScheduleRepository) GetScheduleChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+	ret := _m.Called(ctx, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetScheduleChanges")
+	}
+
+	var r0 *models.SyncBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.SyncCursor, int) (*models.SyncBatch, error)); ok {
+		return rf(ctx, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.SyncCursor, int) *models.SyncBatch); ok {
+		r0 = rf(ctx, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SyncBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.SyncCursor, int) error); ok {
+		r1 = rf(ctx, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockScheduleRepository_GetScheduleChanges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetScheduleChanges'
+type MockScheduleRepository_GetScheduleChanges_Call struct {
+	*mock.Call
+}
+
+// GetScheduleChanges is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cursor models.SyncCursor
+//   - limit int
+func (_e *MockScheduleRepository_Expecter) GetScheduleChanges(ctx interface{}, cursor interface{}, limit interface{}) *MockScheduleRepository_GetScheduleChanges_Call {
+	return &MockScheduleRepository_GetScheduleChanges_Call{Call: _e.mock.On("GetScheduleChanges", ctx, cursor, limit)}
+}
+
+func (_c *MockScheduleRepository_GetScheduleChanges_Call) Run(run func(ctx context.Context, cursor models.SyncCursor, limit int)) *MockScheduleRepository_GetScheduleChanges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.SyncCursor), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetScheduleChanges_Call) Return(_a0 *models.SyncBatch, _a1 error) *MockScheduleRepository_GetScheduleChanges_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetScheduleChanges_Call) RunAndReturn(run func(context.Context, models.SyncCursor, int) (*models.SyncBatch, error)) *MockScheduleRepository_GetScheduleChanges_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSchedulesByDateRangeForAllUsers provides a mock function with given fields: ctx, startDate, endDate, page, limit
+func (_m *MockScheduleRepository) GetSchedulesByDateRangeForAllUsers(ctx context.Context, startDate time.Time, endDate time.Time, page int, limit int) ([]models.UserSchedule, int, error) {
+	ret := _m.Called(ctx, startDate, endDate, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSchedulesByDateRangeForAllUsers")
+	}
+
+	var r0 []models.UserSchedule
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int, int) ([]models.UserSchedule, int, error)); ok {
+		return rf(ctx, startDate, endDate, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int, int) []models.UserSchedule); ok {
+		r0 = rf(ctx, startDate, endDate, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UserSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, int, int) int); ok {
+		r1 = rf(ctx, startDate, endDate, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, time.Time, time.Time, int, int) error); ok {
+		r2 = rf(ctx, startDate, endDate, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockScheduleRepository_GetSchedulesByDateRangeForAllUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSchedulesByDateRangeForAllUsers'
+type MockScheduleRepository_GetSchedulesByDateRangeForAllUsers_Call struct {
+	*mock.Call
+}
+
+// GetSchedulesByDateRangeForAllUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startDate time.Time
+//   - endDate time.Time
+//   - page int
+//   - limit int
+func (_e *MockScheduleRepository_Expecter) GetSchedulesByDateRangeForAllUsers(ctx interface{}, startDate interface{}, endDate interface{}, page interface{}, limit interface{}) *MockScheduleRepository_GetSchedulesByDateRangeForAllUsers_Call {
+	return &MockScheduleRepository_GetSchedulesByDateRangeForAllUsers_Call{Call: _e.mock.On("GetSchedulesByDateRangeForAllUsers", ctx, startDate, endDate, page, limit)}
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByDateRangeForAllUsers_Call) Run(run func(ctx context.Context, startDate time.Time, endDate time.Time, page int, limit int)) *MockScheduleRepository_GetSchedulesByDateRangeForAllUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time), args[3].(int), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByDateRangeForAllUsers_Call) Return(_a0 []models.UserSchedule, _a1 int, _a2 error) *MockScheduleRepository_GetSchedulesByDateRangeForAllUsers_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByDateRangeForAllUsers_Call) RunAndReturn(run func(context.Context, time.Time, time.Time, int, int) ([]models.UserSchedule, int, error)) *MockScheduleRepository_GetSchedulesByDateRangeForAllUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSchedulesByIDs provides a mock function with given fields: ctx, ids
+func (_m *MockScheduleRepository) GetSchedulesByIDs(ctx context.Context, ids []int) ([]models.UserSchedule, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSchedulesByIDs")
+	}
+
+	var r0 []models.UserSchedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) ([]models.UserSchedule, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) []models.UserSchedule); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UserSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockScheduleRepository_GetSchedulesByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSchedulesByIDs'
+type MockScheduleRepository_GetSchedulesByIDs_Call struct {
+	*mock.Call
+}
+
+// GetSchedulesByIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int
+func (_e *MockScheduleRepository_Expecter) GetSchedulesByIDs(ctx interface{}, ids interface{}) *MockScheduleRepository_GetSchedulesByIDs_Call {
+	return &MockScheduleRepository_GetSchedulesByIDs_Call{Call: _e.mock.On("GetSchedulesByIDs", ctx, ids)}
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByIDs_Call) Run(run func(ctx context.Context, ids []int)) *MockScheduleRepository_GetSchedulesByIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByIDs_Call) Return(_a0 []models.UserSchedule, _a1 error) *MockScheduleRepository_GetSchedulesByIDs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByIDs_Call) RunAndReturn(run func(context.Context, []int) ([]models.UserSchedule, error)) *MockScheduleRepository_GetSchedulesByIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSchedulesByUser provides a mock function with given fields: ctx, userID, startDate, endDate, page, limit
+func (_m *MockScheduleRepository) GetSchedulesByUser(ctx context.Context, userID int, startDate time.Time, endDate time.Time, page int, limit int) ([]models.UserSchedule, int, error) {
+	ret := _m.Called(ctx, userID, startDate, endDate, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSchedulesByUser")
+	}
+
+	var r0 []models.UserSchedule
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time, int, int) ([]models.UserSchedule, int, error)); ok {
+		return rf(ctx, userID, startDate, endDate, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time, int, int) []models.UserSchedule); ok {
+		r0 = rf(ctx, userID, startDate, endDate, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UserSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, time.Time, int, int) int); ok {
+		r1 = rf(ctx, userID, startDate, endDate, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, time.Time, time.Time, int, int) error); ok {
+		r2 = rf(ctx, userID, startDate, endDate, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockScheduleRepository_GetSchedulesByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSchedulesByUser'
+type MockScheduleRepository_GetSchedulesByUser_Call struct {
+	*mock.Call
+}
+
+// GetSchedulesByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - startDate time.Time
+//   - endDate time.Time
+//   - page int
+//   - limit int
+func (_e *MockScheduleRepository_Expecter) GetSchedulesByUser(ctx interface{}, userID interface{}, startDate interface{}, endDate interface{}, page interface{}, limit interface{}) *MockScheduleRepository_GetSchedulesByUser_Call {
+	return &MockScheduleRepository_GetSchedulesByUser_Call{Call: _e.mock.On("GetSchedulesByUser", ctx, userID, startDate, endDate, page, limit)}
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByUser_Call) Run(run func(ctx context.Context, userID int, startDate time.Time, endDate time.Time, page int, limit int)) *MockScheduleRepository_GetSchedulesByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(time.Time), args[4].(int), args[5].(int))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByUser_Call) Return(_a0 []models.UserSchedule, _a1 int, _a2 error) *MockScheduleRepository_GetSchedulesByUser_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByUser_Call) RunAndReturn(run func(context.Context, int, time.Time, time.Time, int, int) ([]models.UserSchedule, int, error)) *MockScheduleRepository_GetSchedulesByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSchedulesByUserWithAttendance provides a mock function with given fields: ctx, userID, startDate, endDate, page, limit
+func (_m *MockScheduleRepository) GetSchedulesByUserWithAttendance(ctx context.Context, userID int, startDate time.Time, endDate time.Time, page int, limit int) ([]models.UserSchedule, int, error) {
+	ret := _m.Called(ctx, userID, startDate, endDate, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSchedulesByUserWithAttendance")
+	}
+
+	var r0 []models.UserSchedule
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time, int, int) ([]models.UserSchedule, int, error)); ok {
+		return rf(ctx, userID, startDate, endDate, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time, int, int) []models.UserSchedule); ok {
+		r0 = rf(ctx, userID, startDate, endDate, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UserSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, time.Time, int, int) int); ok {
+		r1 = rf(ctx, userID, startDate, endDate, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, time.Time, time.Time, int, int) error); ok {
+		r2 = rf(ctx, userID, startDate, endDate, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockScheduleRepository_GetSchedulesByUserWithAttendance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSchedulesByUserWithAttendance'
+type MockScheduleRepository_GetSchedulesByUserWithAttendance_Call struct {
+	*mock.Call
+}
+
+// GetSchedulesByUserWithAttendance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - startDate time.Time
+//   - endDate time.Time
+//   - page int
+//   - limit int
+func (_e *MockScheduleRepository_Expecter) GetSchedulesByUserWithAttendance(ctx interface{}, userID interface{}, startDate interface{}, endDate interface{}, page interface{}, limit interface{}) *MockScheduleRepository_GetSchedulesByUserWithAttendance_Call {
+	return &MockScheduleRepository_GetSchedulesByUserWithAttendance_Call{Call: _e.mock.On("GetSchedulesByUserWithAttendance", ctx, userID, startDate, endDate, page, limit)}
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByUserWithAttendance_Call) Run(run func(ctx context.Context, userID int, startDate time.Time, endDate time.Time, page int, limit int)) *MockScheduleRepository_GetSchedulesByUserWithAttendance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(time.Time), args[4].(int), args[5].(int))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByUserWithAttendance_Call) Return(_a0 []models.UserSchedule, _a1 int, _a2 error) *MockScheduleRepository_GetSchedulesByUserWithAttendance_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetSchedulesByUserWithAttendance_Call) RunAndReturn(run func(context.Context, int, time.Time, time.Time, int, int) ([]models.UserSchedule, int, error)) *MockScheduleRepository_GetSchedulesByUserWithAttendance_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
-func (m *MockScheduleRepository) GetScheduleByUserAndDate(ctx context.Context, userID int, date time.Time) (*models.UserSchedule, error) {
-	args := m.Called(ctx, userID, date)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+// GetShiftHeadcounts provides a mock function with given fields: ctx, startDate, endDate
+func (_m *MockScheduleRepository) GetShiftHeadcounts(ctx context.Context, startDate time.Time, endDate time.Time) ([]models.ShiftHeadcount, error) {
+	ret := _m.Called(ctx, startDate, endDate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShiftHeadcounts")
+	}
+
+	var r0 []models.ShiftHeadcount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]models.ShiftHeadcount, error)); ok {
+		return rf(ctx, startDate, endDate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []models.ShiftHeadcount); ok {
+		r0 = rf(ctx, startDate, endDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ShiftHeadcount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, startDate, endDate)
+	} else {
+		r1 = ret.Error(1)
 	}
-	return args.Get(0).(*models.UserSchedule), args.Error(1)
+
+	return r0, r1
+}
+
+// MockScheduleRepository_GetShiftHeadcounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetShiftHeadcounts'
+type MockScheduleRepository_GetShiftHeadcounts_Call struct {
+	*mock.Call
+}
+
+// GetShiftHeadcounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startDate time.Time
+//   - endDate time.Time
+func (_e *MockScheduleRepository_Expecter) GetShiftHeadcounts(ctx interface{}, startDate interface{}, endDate interface{}) *MockScheduleRepository_GetShiftHeadcounts_Call {
+	return &MockScheduleRepository_GetShiftHeadcounts_Call{Call: _e.mock.On("GetShiftHeadcounts", ctx, startDate, endDate)}
+}
+
+func (_c *MockScheduleRepository_GetShiftHeadcounts_Call) Run(run func(ctx context.Context, startDate time.Time, endDate time.Time)) *MockScheduleRepository_GetShiftHeadcounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
 }
 
-func (m *MockScheduleRepository) GetSchedulesByUser(ctx context.Context, userID int, startDate, endDate time.Time) ([]models.UserSchedule, error) {
-	args := m.Called(ctx, userID, startDate, endDate)
-    ret := args.Get(0)
-    if ret == nil {
-        return nil, args.Error(1)
-    }
-	return ret.([]models.UserSchedule), args.Error(1)
-}
\ No newline at end of file
+func (_c *MockScheduleRepository_GetShiftHeadcounts_Call) Return(_a0 []models.ShiftHeadcount, _a1 error) *MockScheduleRepository_GetShiftHeadcounts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockScheduleRepository_GetShiftHeadcounts_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) ([]models.ShiftHeadcount, error)) *MockScheduleRepository_GetShiftHeadcounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PatchSchedule provides a mock function with given fields: ctx, id, input
+func (_m *MockScheduleRepository) PatchSchedule(ctx context.Context, id int, input *models.PatchScheduleInput) (int, error) {
+	ret := _m.Called(ctx, id, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchSchedule")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.PatchScheduleInput) (int, error)); ok {
+		return rf(ctx, id, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.PatchScheduleInput) int); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.PatchScheduleInput) error); ok {
+		r1 = rf(ctx, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockScheduleRepository_PatchSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PatchSchedule'
+type MockScheduleRepository_PatchSchedule_Call struct {
+	*mock.Call
+}
+
+// PatchSchedule is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - input *models.PatchScheduleInput
+func (_e *MockScheduleRepository_Expecter) PatchSchedule(ctx interface{}, id interface{}, input interface{}) *MockScheduleRepository_PatchSchedule_Call {
+	return &MockScheduleRepository_PatchSchedule_Call{Call: _e.mock.On("PatchSchedule", ctx, id, input)}
+}
+
+func (_c *MockScheduleRepository_PatchSchedule_Call) Run(run func(ctx context.Context, id int, input *models.PatchScheduleInput)) *MockScheduleRepository_PatchSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.PatchScheduleInput))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_PatchSchedule_Call) Return(_a0 int, _a1 error) *MockScheduleRepository_PatchSchedule_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockScheduleRepository_PatchSchedule_Call) RunAndReturn(run func(context.Context, int, *models.PatchScheduleInput) (int, error)) *MockScheduleRepository_PatchSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateSchedule provides a mock function with given fields: ctx, schedule
+func (_m *MockScheduleRepository) UpdateSchedule(ctx context.Context, schedule *models.UserSchedule) error {
+	ret := _m.Called(ctx, schedule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSchedule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.UserSchedule) error); ok {
+		r0 = rf(ctx, schedule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockScheduleRepository_UpdateSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSchedule'
+type MockScheduleRepository_UpdateSchedule_Call struct {
+	*mock.Call
+}
+
+// UpdateSchedule is a helper method to define mock.On call
+//   - ctx context.Context
+//   - schedule *models.UserSchedule
+func (_e *MockScheduleRepository_Expecter) UpdateSchedule(ctx interface{}, schedule interface{}) *MockScheduleRepository_UpdateSchedule_Call {
+	return &MockScheduleRepository_UpdateSchedule_Call{Call: _e.mock.On("UpdateSchedule", ctx, schedule)}
+}
+
+func (_c *MockScheduleRepository_UpdateSchedule_Call) Run(run func(ctx context.Context, schedule *models.UserSchedule)) *MockScheduleRepository_UpdateSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.UserSchedule))
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_UpdateSchedule_Call) Return(_a0 error) *MockScheduleRepository_UpdateSchedule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockScheduleRepository_UpdateSchedule_Call) RunAndReturn(run func(context.Context, *models.UserSchedule) error) *MockScheduleRepository_UpdateSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockScheduleRepository creates a new instance of MockScheduleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockScheduleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockScheduleRepository {
+	mock := &MockScheduleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,143 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockSettingsRepository is an autogenerated mock type for the SettingsRepository type
+type MockSettingsRepository struct {
+	mock.Mock
+}
+
+type MockSettingsRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSettingsRepository) EXPECT() *MockSettingsRepository_Expecter {
+	return &MockSettingsRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetAllSettings provides a mock function with given fields: ctx
+func (_m *MockSettingsRepository) GetAllSettings(ctx context.Context) ([]models.Setting, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllSettings")
+	}
+
+	var r0 []models.Setting
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Setting, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Setting); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Setting)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSettingsRepository_GetAllSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllSettings'
+type MockSettingsRepository_GetAllSettings_Call struct {
+	*mock.Call
+}
+
+// GetAllSettings is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockSettingsRepository_Expecter) GetAllSettings(ctx interface{}) *MockSettingsRepository_GetAllSettings_Call {
+	return &MockSettingsRepository_GetAllSettings_Call{Call: _e.mock.On("GetAllSettings", ctx)}
+}
+
+func (_c *MockSettingsRepository_GetAllSettings_Call) Run(run func(ctx context.Context)) *MockSettingsRepository_GetAllSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockSettingsRepository_GetAllSettings_Call) Return(_a0 []models.Setting, _a1 error) *MockSettingsRepository_GetAllSettings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSettingsRepository_GetAllSettings_Call) RunAndReturn(run func(context.Context) ([]models.Setting, error)) *MockSettingsRepository_GetAllSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertSettings provides a mock function with given fields: ctx, values, actorUserID
+func (_m *MockSettingsRepository) UpsertSettings(ctx context.Context, values map[string]string, actorUserID int) error {
+	ret := _m.Called(ctx, values, actorUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertSettings")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]string, int) error); ok {
+		r0 = rf(ctx, values, actorUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSettingsRepository_UpsertSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertSettings'
+type MockSettingsRepository_UpsertSettings_Call struct {
+	*mock.Call
+}
+
+// UpsertSettings is a helper method to define mock.On call
+//   - ctx context.Context
+//   - values map[string]string
+//   - actorUserID int
+func (_e *MockSettingsRepository_Expecter) UpsertSettings(ctx interface{}, values interface{}, actorUserID interface{}) *MockSettingsRepository_UpsertSettings_Call {
+	return &MockSettingsRepository_UpsertSettings_Call{Call: _e.mock.On("UpsertSettings", ctx, values, actorUserID)}
+}
+
+func (_c *MockSettingsRepository_UpsertSettings_Call) Run(run func(ctx context.Context, values map[string]string, actorUserID int)) *MockSettingsRepository_UpsertSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(map[string]string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockSettingsRepository_UpsertSettings_Call) Return(_a0 error) *MockSettingsRepository_UpsertSettings_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSettingsRepository_UpsertSettings_Call) RunAndReturn(run func(context.Context, map[string]string, int) error) *MockSettingsRepository_UpsertSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSettingsRepository creates a new instance of MockSettingsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSettingsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSettingsRepository {
+	mock := &MockSettingsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
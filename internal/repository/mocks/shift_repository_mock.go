@@ -1,47 +1,470 @@
+// Code generated by mockery. DO NOT EDIT.
+
 package mocks
 
 import (
-	"context"
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
 
-	"github.com/stretchr/testify/mock"
-	"github.com/rakaarfi/attendance-system-be/internal/models"
+	time "time"
 )
 
-// MockShiftRepository mocks the ShiftRepository interface.
+// MockShiftRepository is an autogenerated mock type for the ShiftRepository type
 type MockShiftRepository struct {
 	mock.Mock
 }
 
-func (m *MockShiftRepository) CreateShift(ctx context.Context, shift *models.Shift) (int, error) {
-	args := m.Called(ctx, shift)
-	return args.Int(0), args.Error(1)
+type MockShiftRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockShiftRepository) EXPECT() *MockShiftRepository_Expecter {
+	return &MockShiftRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateShift provides a mock function with given fields: ctx, shift
+func (_m *MockShiftRepository) CreateShift(ctx context.Context, shift *models.Shift) (int, error) {
+	ret := _m.Called(ctx, shift)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateShift")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Shift) (int, error)); ok {
+		return rf(ctx, shift)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Shift) int); ok {
+		r0 = rf(ctx, shift)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Shift) error); ok {
+		r1 = rf(ctx, shift)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockShiftRepository_CreateShift_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateShift'
+type MockShiftRepository_CreateShift_Call struct {
+	*mock.Call
+}
+
+// CreateShift is a helper method to define mock.On call
+//   - ctx context.Context
+//   - shift *models.Shift
+func (_e *MockShiftRepository_Expecter) CreateShift(ctx interface{}, shift interface{}) *MockShiftRepository_CreateShift_Call {
+	return &MockShiftRepository_CreateShift_Call{Call: _e.mock.On("CreateShift", ctx, shift)}
+}
+
+func (_c *MockShiftRepository_CreateShift_Call) Run(run func(ctx context.Context, shift *models.Shift)) *MockShiftRepository_CreateShift_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Shift))
+	})
+	return _c
+}
+
+func (_c *MockShiftRepository_CreateShift_Call) Return(_a0 int, _a1 error) *MockShiftRepository_CreateShift_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockShiftRepository_CreateShift_Call) RunAndReturn(run func(context.Context, *models.Shift) (int, error)) *MockShiftRepository_CreateShift_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateShiftOverride provides a mock function with given fields: ctx, o
+func (_m *MockShiftRepository) CreateShiftOverride(ctx context.Context, o *models.ShiftOverride) (int, error) {
+	ret := _m.Called(ctx, o)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateShiftOverride")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ShiftOverride) (int, error)); ok {
+		return rf(ctx, o)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ShiftOverride) int); ok {
+		r0 = rf(ctx, o)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.ShiftOverride) error); ok {
+		r1 = rf(ctx, o)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockShiftRepository_CreateShiftOverride_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateShiftOverride'
+type MockShiftRepository_CreateShiftOverride_Call struct {
+	*mock.Call
+}
+
+// CreateShiftOverride is a helper method to define mock.On call
+//   - ctx context.Context
+//   - o *models.ShiftOverride
+func (_e *MockShiftRepository_Expecter) CreateShiftOverride(ctx interface{}, o interface{}) *MockShiftRepository_CreateShiftOverride_Call {
+	return &MockShiftRepository_CreateShiftOverride_Call{Call: _e.mock.On("CreateShiftOverride", ctx, o)}
 }
 
-func (m *MockShiftRepository) GetShiftByID(ctx context.Context, id int) (*models.Shift, error) {
-	args := m.Called(ctx, id)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+func (_c *MockShiftRepository_CreateShiftOverride_Call) Run(run func(ctx context.Context, o *models.ShiftOverride)) *MockShiftRepository_CreateShiftOverride_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.ShiftOverride))
+	})
+	return _c
+}
+
+func (_c *MockShiftRepository_CreateShiftOverride_Call) Return(_a0 int, _a1 error) *MockShiftRepository_CreateShiftOverride_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockShiftRepository_CreateShiftOverride_Call) RunAndReturn(run func(context.Context, *models.ShiftOverride) (int, error)) *MockShiftRepository_CreateShiftOverride_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteShift provides a mock function with given fields: ctx, id
+func (_m *MockShiftRepository) DeleteShift(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteShift")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockShiftRepository_DeleteShift_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteShift'
+type MockShiftRepository_DeleteShift_Call struct {
+	*mock.Call
+}
+
+// DeleteShift is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockShiftRepository_Expecter) DeleteShift(ctx interface{}, id interface{}) *MockShiftRepository_DeleteShift_Call {
+	return &MockShiftRepository_DeleteShift_Call{Call: _e.mock.On("DeleteShift", ctx, id)}
+}
+
+func (_c *MockShiftRepository_DeleteShift_Call) Run(run func(ctx context.Context, id int)) *MockShiftRepository_DeleteShift_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockShiftRepository_DeleteShift_Call) Return(_a0 error) *MockShiftRepository_DeleteShift_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockShiftRepository_DeleteShift_Call) RunAndReturn(run func(context.Context, int) error) *MockShiftRepository_DeleteShift_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteShiftOverride provides a mock function with given fields: ctx, id
+func (_m *MockShiftRepository) DeleteShiftOverride(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteShiftOverride")
 	}
-	return args.Get(0).(*models.Shift), args.Error(1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockShiftRepository_DeleteShiftOverride_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteShiftOverride'
+type MockShiftRepository_DeleteShiftOverride_Call struct {
+	*mock.Call
 }
 
-func (m *MockShiftRepository) GetAllShifts(ctx context.Context) ([]models.Shift, error) {
-	args := m.Called(ctx)
-    // Handle potentially nil slice return
-    ret := args.Get(0)
-    if ret == nil {
-        // Return nil slice explicitly if needed, otherwise let testify handle based on setup
-        return nil, args.Error(1)
-    }
-	return ret.([]models.Shift), args.Error(1)
+// DeleteShiftOverride is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockShiftRepository_Expecter) DeleteShiftOverride(ctx interface{}, id interface{}) *MockShiftRepository_DeleteShiftOverride_Call {
+	return &MockShiftRepository_DeleteShiftOverride_Call{Call: _e.mock.On("DeleteShiftOverride", ctx, id)}
 }
 
-func (m *MockShiftRepository) UpdateShift(ctx context.Context, shift *models.Shift) error {
-	args := m.Called(ctx, shift)
-	return args.Error(0)
+func (_c *MockShiftRepository_DeleteShiftOverride_Call) Run(run func(ctx context.Context, id int)) *MockShiftRepository_DeleteShiftOverride_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
 }
 
-func (m *MockShiftRepository) DeleteShift(ctx context.Context, id int) error {
-	args := m.Called(ctx, id)
-	return args.Error(0)
-}
\ No newline at end of file
+func (_c *MockShiftRepository_DeleteShiftOverride_Call) Return(_a0 error) *MockShiftRepository_DeleteShiftOverride_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockShiftRepository_DeleteShiftOverride_Call) RunAndReturn(run func(context.Context, int) error) *MockShiftRepository_DeleteShiftOverride_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllShifts provides a mock function with given fields: ctx
+func (_m *MockShiftRepository) GetAllShifts(ctx context.Context) ([]models.Shift, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllShifts")
+	}
+
+	var r0 []models.Shift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Shift, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Shift); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Shift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockShiftRepository_GetAllShifts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllShifts'
+type MockShiftRepository_GetAllShifts_Call struct {
+	*mock.Call
+}
+
+// GetAllShifts is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockShiftRepository_Expecter) GetAllShifts(ctx interface{}) *MockShiftRepository_GetAllShifts_Call {
+	return &MockShiftRepository_GetAllShifts_Call{Call: _e.mock.On("GetAllShifts", ctx)}
+}
+
+func (_c *MockShiftRepository_GetAllShifts_Call) Run(run func(ctx context.Context)) *MockShiftRepository_GetAllShifts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockShiftRepository_GetAllShifts_Call) Return(_a0 []models.Shift, _a1 error) *MockShiftRepository_GetAllShifts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockShiftRepository_GetAllShifts_Call) RunAndReturn(run func(context.Context) ([]models.Shift, error)) *MockShiftRepository_GetAllShifts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetShiftByID provides a mock function with given fields: ctx, id
+func (_m *MockShiftRepository) GetShiftByID(ctx context.Context, id int) (*models.Shift, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShiftByID")
+	}
+
+	var r0 *models.Shift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Shift, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Shift); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Shift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockShiftRepository_GetShiftByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetShiftByID'
+type MockShiftRepository_GetShiftByID_Call struct {
+	*mock.Call
+}
+
+// GetShiftByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockShiftRepository_Expecter) GetShiftByID(ctx interface{}, id interface{}) *MockShiftRepository_GetShiftByID_Call {
+	return &MockShiftRepository_GetShiftByID_Call{Call: _e.mock.On("GetShiftByID", ctx, id)}
+}
+
+func (_c *MockShiftRepository_GetShiftByID_Call) Run(run func(ctx context.Context, id int)) *MockShiftRepository_GetShiftByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockShiftRepository_GetShiftByID_Call) Return(_a0 *models.Shift, _a1 error) *MockShiftRepository_GetShiftByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockShiftRepository_GetShiftByID_Call) RunAndReturn(run func(context.Context, int) (*models.Shift, error)) *MockShiftRepository_GetShiftByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetShiftOverrides provides a mock function with given fields: ctx, activeFrom
+func (_m *MockShiftRepository) GetShiftOverrides(ctx context.Context, activeFrom *time.Time) ([]models.ShiftOverride, error) {
+	ret := _m.Called(ctx, activeFrom)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShiftOverrides")
+	}
+
+	var r0 []models.ShiftOverride
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time) ([]models.ShiftOverride, error)); ok {
+		return rf(ctx, activeFrom)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time) []models.ShiftOverride); ok {
+		r0 = rf(ctx, activeFrom)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ShiftOverride)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *time.Time) error); ok {
+		r1 = rf(ctx, activeFrom)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockShiftRepository_GetShiftOverrides_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetShiftOverrides'
+type MockShiftRepository_GetShiftOverrides_Call struct {
+	*mock.Call
+}
+
+// GetShiftOverrides is a helper method to define mock.On call
+//   - ctx context.Context
+//   - activeFrom *time.Time
+func (_e *MockShiftRepository_Expecter) GetShiftOverrides(ctx interface{}, activeFrom interface{}) *MockShiftRepository_GetShiftOverrides_Call {
+	return &MockShiftRepository_GetShiftOverrides_Call{Call: _e.mock.On("GetShiftOverrides", ctx, activeFrom)}
+}
+
+func (_c *MockShiftRepository_GetShiftOverrides_Call) Run(run func(ctx context.Context, activeFrom *time.Time)) *MockShiftRepository_GetShiftOverrides_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*time.Time))
+	})
+	return _c
+}
+
+func (_c *MockShiftRepository_GetShiftOverrides_Call) Return(_a0 []models.ShiftOverride, _a1 error) *MockShiftRepository_GetShiftOverrides_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockShiftRepository_GetShiftOverrides_Call) RunAndReturn(run func(context.Context, *time.Time) ([]models.ShiftOverride, error)) *MockShiftRepository_GetShiftOverrides_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateShift provides a mock function with given fields: ctx, shift
+func (_m *MockShiftRepository) UpdateShift(ctx context.Context, shift *models.Shift) error {
+	ret := _m.Called(ctx, shift)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateShift")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Shift) error); ok {
+		r0 = rf(ctx, shift)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockShiftRepository_UpdateShift_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateShift'
+type MockShiftRepository_UpdateShift_Call struct {
+	*mock.Call
+}
+
+// UpdateShift is a helper method to define mock.On call
+//   - ctx context.Context
+//   - shift *models.Shift
+func (_e *MockShiftRepository_Expecter) UpdateShift(ctx interface{}, shift interface{}) *MockShiftRepository_UpdateShift_Call {
+	return &MockShiftRepository_UpdateShift_Call{Call: _e.mock.On("UpdateShift", ctx, shift)}
+}
+
+func (_c *MockShiftRepository_UpdateShift_Call) Run(run func(ctx context.Context, shift *models.Shift)) *MockShiftRepository_UpdateShift_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Shift))
+	})
+	return _c
+}
+
+func (_c *MockShiftRepository_UpdateShift_Call) Return(_a0 error) *MockShiftRepository_UpdateShift_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockShiftRepository_UpdateShift_Call) RunAndReturn(run func(context.Context, *models.Shift) error) *MockShiftRepository_UpdateShift_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockShiftRepository creates a new instance of MockShiftRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockShiftRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockShiftRepository {
+	mock := &MockShiftRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,164 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockSignOffRepository is an autogenerated mock type for the SignOffRepository type
+type MockSignOffRepository struct {
+	mock.Mock
+}
+
+type MockSignOffRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSignOffRepository) EXPECT() *MockSignOffRepository_Expecter {
+	return &MockSignOffRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetUnsignedDays provides a mock function with given fields: ctx, managerID, startDate, endDate
+func (_m *MockSignOffRepository) GetUnsignedDays(ctx context.Context, managerID *int, startDate time.Time, endDate time.Time) ([]models.UnsignedDay, error) {
+	ret := _m.Called(ctx, managerID, startDate, endDate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUnsignedDays")
+	}
+
+	var r0 []models.UnsignedDay
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *int, time.Time, time.Time) ([]models.UnsignedDay, error)); ok {
+		return rf(ctx, managerID, startDate, endDate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *int, time.Time, time.Time) []models.UnsignedDay); ok {
+		r0 = rf(ctx, managerID, startDate, endDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UnsignedDay)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *int, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, managerID, startDate, endDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSignOffRepository_GetUnsignedDays_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUnsignedDays'
+type MockSignOffRepository_GetUnsignedDays_Call struct {
+	*mock.Call
+}
+
+// GetUnsignedDays is a helper method to define mock.On call
+//   - ctx context.Context
+//   - managerID *int
+//   - startDate time.Time
+//   - endDate time.Time
+func (_e *MockSignOffRepository_Expecter) GetUnsignedDays(ctx interface{}, managerID interface{}, startDate interface{}, endDate interface{}) *MockSignOffRepository_GetUnsignedDays_Call {
+	return &MockSignOffRepository_GetUnsignedDays_Call{Call: _e.mock.On("GetUnsignedDays", ctx, managerID, startDate, endDate)}
+}
+
+func (_c *MockSignOffRepository_GetUnsignedDays_Call) Run(run func(ctx context.Context, managerID *int, startDate time.Time, endDate time.Time)) *MockSignOffRepository_GetUnsignedDays_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*int), args[2].(time.Time), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSignOffRepository_GetUnsignedDays_Call) Return(_a0 []models.UnsignedDay, _a1 error) *MockSignOffRepository_GetUnsignedDays_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSignOffRepository_GetUnsignedDays_Call) RunAndReturn(run func(context.Context, *int, time.Time, time.Time) ([]models.UnsignedDay, error)) *MockSignOffRepository_GetUnsignedDays_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SignOffDay provides a mock function with given fields: ctx, managerID, signerID, day, userIDs, grace, note
+func (_m *MockSignOffRepository) SignOffDay(ctx context.Context, managerID int, signerID int, day time.Time, userIDs []int, grace time.Duration, note *string) ([]models.SignOffResult, error) {
+	ret := _m.Called(ctx, managerID, signerID, day, userIDs, grace, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SignOffDay")
+	}
+
+	var r0 []models.SignOffResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Time, []int, time.Duration, *string) ([]models.SignOffResult, error)); ok {
+		return rf(ctx, managerID, signerID, day, userIDs, grace, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Time, []int, time.Duration, *string) []models.SignOffResult); ok {
+		r0 = rf(ctx, managerID, signerID, day, userIDs, grace, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.SignOffResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, time.Time, []int, time.Duration, *string) error); ok {
+		r1 = rf(ctx, managerID, signerID, day, userIDs, grace, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSignOffRepository_SignOffDay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SignOffDay'
+type MockSignOffRepository_SignOffDay_Call struct {
+	*mock.Call
+}
+
+// SignOffDay is a helper method to define mock.On call
+//   - ctx context.Context
+//   - managerID int
+//   - signerID int
+//   - day time.Time
+//   - userIDs []int
+//   - grace time.Duration
+//   - note *string
+func (_e *MockSignOffRepository_Expecter) SignOffDay(ctx interface{}, managerID interface{}, signerID interface{}, day interface{}, userIDs interface{}, grace interface{}, note interface{}) *MockSignOffRepository_SignOffDay_Call {
+	return &MockSignOffRepository_SignOffDay_Call{Call: _e.mock.On("SignOffDay", ctx, managerID, signerID, day, userIDs, grace, note)}
+}
+
+func (_c *MockSignOffRepository_SignOffDay_Call) Run(run func(ctx context.Context, managerID int, signerID int, day time.Time, userIDs []int, grace time.Duration, note *string)) *MockSignOffRepository_SignOffDay_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(time.Time), args[4].([]int), args[5].(time.Duration), args[6].(*string))
+	})
+	return _c
+}
+
+func (_c *MockSignOffRepository_SignOffDay_Call) Return(_a0 []models.SignOffResult, _a1 error) *MockSignOffRepository_SignOffDay_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSignOffRepository_SignOffDay_Call) RunAndReturn(run func(context.Context, int, int, time.Time, []int, time.Duration, *string) ([]models.SignOffResult, error)) *MockSignOffRepository_SignOffDay_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSignOffRepository creates a new instance of MockSignOffRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSignOffRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSignOffRepository {
+	mock := &MockSignOffRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
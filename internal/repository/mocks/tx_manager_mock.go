@@ -0,0 +1,83 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockTxManager is an autogenerated mock type for the TxManager type
+type MockTxManager struct {
+	mock.Mock
+}
+
+type MockTxManager_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTxManager) EXPECT() *MockTxManager_Expecter {
+	return &MockTxManager_Expecter{mock: &_m.Mock}
+}
+
+// RunInTx provides a mock function with given fields: ctx, fn
+func (_m *MockTxManager) RunInTx(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunInTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTxManager_RunInTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunInTx'
+type MockTxManager_RunInTx_Call struct {
+	*mock.Call
+}
+
+// RunInTx is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(context.Context) error
+func (_e *MockTxManager_Expecter) RunInTx(ctx interface{}, fn interface{}) *MockTxManager_RunInTx_Call {
+	return &MockTxManager_RunInTx_Call{Call: _e.mock.On("RunInTx", ctx, fn)}
+}
+
+func (_c *MockTxManager_RunInTx_Call) Run(run func(ctx context.Context, fn func(context.Context) error)) *MockTxManager_RunInTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(func(context.Context) error))
+	})
+	return _c
+}
+
+func (_c *MockTxManager_RunInTx_Call) Return(_a0 error) *MockTxManager_RunInTx_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTxManager_RunInTx_Call) RunAndReturn(run func(context.Context, func(context.Context) error) error) *MockTxManager_RunInTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTxManager creates a new instance of MockTxManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTxManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTxManager {
+	mock := &MockTxManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
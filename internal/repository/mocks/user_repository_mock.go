@@ -1,55 +1,2957 @@
-// internal/repository/mocks/user_repository_mock.go
+// Code generated by mockery. DO NOT EDIT.
+
 package mocks
 
 import (
-	"context"
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
 
-	"github.com/rakaarfi/attendance-system-be/internal/models"
-	"github.com/stretchr/testify/mock"
+	time "time"
 )
 
-// MockUserRepository adalah mock untuk UserRepository
+// MockUserRepository is an autogenerated mock type for the UserRepository type
 type MockUserRepository struct {
 	mock.Mock
 }
 
-// Implementasikan SEMUA method dari interface UserRepository
+type MockUserRepository_Expecter struct {
+	mock *mock.Mock
+}
 
-func (m *MockUserRepository) CreateUser(ctx context.Context, user *models.RegisterUserInput, hashedPassword string) (int, error) {
-	// Beritahu testify method ini dipanggil dengan argumen apa saja
-	args := m.Called(ctx, user, hashedPassword)
-	// Kembalikan apa yang sudah di-set di expectation (.Return(...))
-	return args.Int(0), args.Error(1)
+func (_m *MockUserRepository) EXPECT() *MockUserRepository_Expecter {
+	return &MockUserRepository_Expecter{mock: &_m.Mock}
 }
 
-func (m *MockUserRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
-	args := m.Called(ctx, username)
-	// Handle nil return jika user tidak ditemukan
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+// AcceptInvitation provides a mock function with given fields: ctx, id, userID
+func (_m *MockUserRepository) AcceptInvitation(ctx context.Context, id int, userID int) error {
+	ret := _m.Called(ctx, id, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptInvitation")
 	}
-	return args.Get(0).(*models.User), args.Error(1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_AcceptInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AcceptInvitation'
+type MockUserRepository_AcceptInvitation_Call struct {
+	*mock.Call
+}
+
+// AcceptInvitation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - userID int
+func (_e *MockUserRepository_Expecter) AcceptInvitation(ctx interface{}, id interface{}, userID interface{}) *MockUserRepository_AcceptInvitation_Call {
+	return &MockUserRepository_AcceptInvitation_Call{Call: _e.mock.On("AcceptInvitation", ctx, id, userID)}
+}
+
+func (_c *MockUserRepository_AcceptInvitation_Call) Run(run func(ctx context.Context, id int, userID int)) *MockUserRepository_AcceptInvitation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_AcceptInvitation_Call) Return(_a0 error) *MockUserRepository_AcceptInvitation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_AcceptInvitation_Call) RunAndReturn(run func(context.Context, int, int) error) *MockUserRepository_AcceptInvitation_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
-func (m *MockUserRepository) GetUserByID(ctx context.Context, id int) (*models.User, error) {
-	args := m.Called(ctx, id)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+// AnonymizeUser provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) AnonymizeUser(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AnonymizeUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
 	}
-	return args.Get(0).(*models.User), args.Error(1)
+
+	return r0
 }
 
-// --- Mock untuk RoleRepository ---
-type MockRoleRepository struct {
-	mock.Mock
+// MockUserRepository_AnonymizeUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AnonymizeUser'
+type MockUserRepository_AnonymizeUser_Call struct {
+	*mock.Call
+}
+
+// AnonymizeUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) AnonymizeUser(ctx interface{}, id interface{}) *MockUserRepository_AnonymizeUser_Call {
+	return &MockUserRepository_AnonymizeUser_Call{Call: _e.mock.On("AnonymizeUser", ctx, id)}
+}
+
+func (_c *MockUserRepository_AnonymizeUser_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_AnonymizeUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_AnonymizeUser_Call) Return(_a0 error) *MockUserRepository_AnonymizeUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_AnonymizeUser_Call) RunAndReturn(run func(context.Context, int) error) *MockUserRepository_AnonymizeUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BulkUpdateUserRole provides a mock function with given fields: ctx, userIDs, roleID
+func (_m *MockUserRepository) BulkUpdateUserRole(ctx context.Context, userIDs []int, roleID int) ([]models.BulkItemResult, error) {
+	ret := _m.Called(ctx, userIDs, roleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkUpdateUserRole")
+	}
+
+	var r0 []models.BulkItemResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int, int) ([]models.BulkItemResult, error)); ok {
+		return rf(ctx, userIDs, roleID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int, int) []models.BulkItemResult); ok {
+		r0 = rf(ctx, userIDs, roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.BulkItemResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int, int) error); ok {
+		r1 = rf(ctx, userIDs, roleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_BulkUpdateUserRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkUpdateUserRole'
+type MockUserRepository_BulkUpdateUserRole_Call struct {
+	*mock.Call
+}
+
+// BulkUpdateUserRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userIDs []int
+//   - roleID int
+func (_e *MockUserRepository_Expecter) BulkUpdateUserRole(ctx interface{}, userIDs interface{}, roleID interface{}) *MockUserRepository_BulkUpdateUserRole_Call {
+	return &MockUserRepository_BulkUpdateUserRole_Call{Call: _e.mock.On("BulkUpdateUserRole", ctx, userIDs, roleID)}
+}
+
+func (_c *MockUserRepository_BulkUpdateUserRole_Call) Run(run func(ctx context.Context, userIDs []int, roleID int)) *MockUserRepository_BulkUpdateUserRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_BulkUpdateUserRole_Call) Return(_a0 []models.BulkItemResult, _a1 error) *MockUserRepository_BulkUpdateUserRole_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_BulkUpdateUserRole_Call) RunAndReturn(run func(context.Context, []int, int) ([]models.BulkItemResult, error)) *MockUserRepository_BulkUpdateUserRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CancelEmailChange provides a mock function with given fields: ctx, userID
+func (_m *MockUserRepository) CancelEmailChange(ctx context.Context, userID int) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelEmailChange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_CancelEmailChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelEmailChange'
+type MockUserRepository_CancelEmailChange_Call struct {
+	*mock.Call
+}
+
+// CancelEmailChange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockUserRepository_Expecter) CancelEmailChange(ctx interface{}, userID interface{}) *MockUserRepository_CancelEmailChange_Call {
+	return &MockUserRepository_CancelEmailChange_Call{Call: _e.mock.On("CancelEmailChange", ctx, userID)}
+}
+
+func (_c *MockUserRepository_CancelEmailChange_Call) Run(run func(ctx context.Context, userID int)) *MockUserRepository_CancelEmailChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_CancelEmailChange_Call) Return(_a0 error) *MockUserRepository_CancelEmailChange_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_CancelEmailChange_Call) RunAndReturn(run func(context.Context, int) error) *MockUserRepository_CancelEmailChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConfirmEmailChange provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) ConfirmEmailChange(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmEmailChange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_ConfirmEmailChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmEmailChange'
+type MockUserRepository_ConfirmEmailChange_Call struct {
+	*mock.Call
+}
+
+// ConfirmEmailChange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) ConfirmEmailChange(ctx interface{}, id interface{}) *MockUserRepository_ConfirmEmailChange_Call {
+	return &MockUserRepository_ConfirmEmailChange_Call{Call: _e.mock.On("ConfirmEmailChange", ctx, id)}
+}
+
+func (_c *MockUserRepository_ConfirmEmailChange_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_ConfirmEmailChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ConfirmEmailChange_Call) Return(_a0 error) *MockUserRepository_ConfirmEmailChange_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_ConfirmEmailChange_Call) RunAndReturn(run func(context.Context, int) error) *MockUserRepository_ConfirmEmailChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConsumePhoneOTP provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) ConsumePhoneOTP(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumePhoneOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_ConsumePhoneOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConsumePhoneOTP'
+type MockUserRepository_ConsumePhoneOTP_Call struct {
+	*mock.Call
+}
+
+// ConsumePhoneOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) ConsumePhoneOTP(ctx interface{}, id interface{}) *MockUserRepository_ConsumePhoneOTP_Call {
+	return &MockUserRepository_ConsumePhoneOTP_Call{Call: _e.mock.On("ConsumePhoneOTP", ctx, id)}
+}
+
+func (_c *MockUserRepository_ConsumePhoneOTP_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_ConsumePhoneOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ConsumePhoneOTP_Call) Return(_a0 error) *MockUserRepository_ConsumePhoneOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_ConsumePhoneOTP_Call) RunAndReturn(run func(context.Context, int) error) *MockUserRepository_ConsumePhoneOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateEmailChangeRequest provides a mock function with given fields: ctx, userID, newEmail, expiresAt
+func (_m *MockUserRepository) CreateEmailChangeRequest(ctx context.Context, userID int, newEmail string, expiresAt time.Time) (*models.EmailChangeRequest, error) {
+	ret := _m.Called(ctx, userID, newEmail, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEmailChangeRequest")
+	}
+
+	var r0 *models.EmailChangeRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Time) (*models.EmailChangeRequest, error)); ok {
+		return rf(ctx, userID, newEmail, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Time) *models.EmailChangeRequest); ok {
+		r0 = rf(ctx, userID, newEmail, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailChangeRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, newEmail, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_CreateEmailChangeRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateEmailChangeRequest'
+type MockUserRepository_CreateEmailChangeRequest_Call struct {
+	*mock.Call
+}
+
+// CreateEmailChangeRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - newEmail string
+//   - expiresAt time.Time
+func (_e *MockUserRepository_Expecter) CreateEmailChangeRequest(ctx interface{}, userID interface{}, newEmail interface{}, expiresAt interface{}) *MockUserRepository_CreateEmailChangeRequest_Call {
+	return &MockUserRepository_CreateEmailChangeRequest_Call{Call: _e.mock.On("CreateEmailChangeRequest", ctx, userID, newEmail, expiresAt)}
+}
+
+func (_c *MockUserRepository_CreateEmailChangeRequest_Call) Run(run func(ctx context.Context, userID int, newEmail string, expiresAt time.Time)) *MockUserRepository_CreateEmailChangeRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_CreateEmailChangeRequest_Call) Return(_a0 *models.EmailChangeRequest, _a1 error) *MockUserRepository_CreateEmailChangeRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_CreateEmailChangeRequest_Call) RunAndReturn(run func(context.Context, int, string, time.Time) (*models.EmailChangeRequest, error)) *MockUserRepository_CreateEmailChangeRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateInvitation provides a mock function with given fields: ctx, email, roleID, invitedBy, expiresAt
+func (_m *MockUserRepository) CreateInvitation(ctx context.Context, email string, roleID int, invitedBy int, expiresAt time.Time) (*models.Invitation, error) {
+	ret := _m.Called(ctx, email, roleID, invitedBy, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateInvitation")
+	}
+
+	var r0 *models.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, time.Time) (*models.Invitation, error)); ok {
+		return rf(ctx, email, roleID, invitedBy, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, time.Time) *models.Invitation); ok {
+		r0 = rf(ctx, email, roleID, invitedBy, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int, time.Time) error); ok {
+		r1 = rf(ctx, email, roleID, invitedBy, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_CreateInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateInvitation'
+type MockUserRepository_CreateInvitation_Call struct {
+	*mock.Call
+}
+
+// CreateInvitation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - roleID int
+//   - invitedBy int
+//   - expiresAt time.Time
+func (_e *MockUserRepository_Expecter) CreateInvitation(ctx interface{}, email interface{}, roleID interface{}, invitedBy interface{}, expiresAt interface{}) *MockUserRepository_CreateInvitation_Call {
+	return &MockUserRepository_CreateInvitation_Call{Call: _e.mock.On("CreateInvitation", ctx, email, roleID, invitedBy, expiresAt)}
+}
+
+func (_c *MockUserRepository_CreateInvitation_Call) Run(run func(ctx context.Context, email string, roleID int, invitedBy int, expiresAt time.Time)) *MockUserRepository_CreateInvitation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(int), args[4].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_CreateInvitation_Call) Return(_a0 *models.Invitation, _a1 error) *MockUserRepository_CreateInvitation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_CreateInvitation_Call) RunAndReturn(run func(context.Context, string, int, int, time.Time) (*models.Invitation, error)) *MockUserRepository_CreateInvitation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreatePhoneOTP provides a mock function with given fields: ctx, otp, phone
+func (_m *MockUserRepository) CreatePhoneOTP(ctx context.Context, otp *models.PhoneOTP, phone string) error {
+	ret := _m.Called(ctx, otp, phone)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePhoneOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PhoneOTP, string) error); ok {
+		r0 = rf(ctx, otp, phone)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_CreatePhoneOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePhoneOTP'
+type MockUserRepository_CreatePhoneOTP_Call struct {
+	*mock.Call
+}
+
+// CreatePhoneOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - otp *models.PhoneOTP
+//   - phone string
+func (_e *MockUserRepository_Expecter) CreatePhoneOTP(ctx interface{}, otp interface{}, phone interface{}) *MockUserRepository_CreatePhoneOTP_Call {
+	return &MockUserRepository_CreatePhoneOTP_Call{Call: _e.mock.On("CreatePhoneOTP", ctx, otp, phone)}
+}
+
+func (_c *MockUserRepository_CreatePhoneOTP_Call) Run(run func(ctx context.Context, otp *models.PhoneOTP, phone string)) *MockUserRepository_CreatePhoneOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.PhoneOTP), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_CreatePhoneOTP_Call) Return(_a0 error) *MockUserRepository_CreatePhoneOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_CreatePhoneOTP_Call) RunAndReturn(run func(context.Context, *models.PhoneOTP, string) error) *MockUserRepository_CreatePhoneOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateUser provides a mock function with given fields: ctx, user, hashedPassword
+func (_m *MockUserRepository) CreateUser(ctx context.Context, user *models.RegisterUserInput, hashedPassword string) (int, error) {
+	ret := _m.Called(ctx, user, hashedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.RegisterUserInput, string) (int, error)); ok {
+		return rf(ctx, user, hashedPassword)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.RegisterUserInput, string) int); ok {
+		r0 = rf(ctx, user, hashedPassword)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.RegisterUserInput, string) error); ok {
+		r1 = rf(ctx, user, hashedPassword)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
+type MockUserRepository_CreateUser_Call struct {
+	*mock.Call
+}
+
+// CreateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *models.RegisterUserInput
+//   - hashedPassword string
+func (_e *MockUserRepository_Expecter) CreateUser(ctx interface{}, user interface{}, hashedPassword interface{}) *MockUserRepository_CreateUser_Call {
+	return &MockUserRepository_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, user, hashedPassword)}
+}
+
+func (_c *MockUserRepository_CreateUser_Call) Run(run func(ctx context.Context, user *models.RegisterUserInput, hashedPassword string)) *MockUserRepository_CreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.RegisterUserInput), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_CreateUser_Call) Return(_a0 int, _a1 error) *MockUserRepository_CreateUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_CreateUser_Call) RunAndReturn(run func(context.Context, *models.RegisterUserInput, string) (int, error)) *MockUserRepository_CreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateUsernameChangeRequest provides a mock function with given fields: ctx, userID, newUsername
+func (_m *MockUserRepository) CreateUsernameChangeRequest(ctx context.Context, userID int, newUsername string) (*models.UsernameChangeRequest, error) {
+	ret := _m.Called(ctx, userID, newUsername)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUsernameChangeRequest")
+	}
+
+	var r0 *models.UsernameChangeRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (*models.UsernameChangeRequest, error)); ok {
+		return rf(ctx, userID, newUsername)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *models.UsernameChangeRequest); ok {
+		r0 = rf(ctx, userID, newUsername)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UsernameChangeRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, newUsername)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_CreateUsernameChangeRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUsernameChangeRequest'
+type MockUserRepository_CreateUsernameChangeRequest_Call struct {
+	*mock.Call
+}
+
+// CreateUsernameChangeRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - newUsername string
+func (_e *MockUserRepository_Expecter) CreateUsernameChangeRequest(ctx interface{}, userID interface{}, newUsername interface{}) *MockUserRepository_CreateUsernameChangeRequest_Call {
+	return &MockUserRepository_CreateUsernameChangeRequest_Call{Call: _e.mock.On("CreateUsernameChangeRequest", ctx, userID, newUsername)}
 }
 
-func (m *MockRoleRepository) GetRoleByID(ctx context.Context, id int) (*models.Role, error) {
-	args := m.Called(ctx, id)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+func (_c *MockUserRepository_CreateUsernameChangeRequest_Call) Run(run func(ctx context.Context, userID int, newUsername string)) *MockUserRepository_CreateUsernameChangeRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_CreateUsernameChangeRequest_Call) Return(_a0 *models.UsernameChangeRequest, _a1 error) *MockUserRepository_CreateUsernameChangeRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_CreateUsernameChangeRequest_Call) RunAndReturn(run func(context.Context, int, string) (*models.UsernameChangeRequest, error)) *MockUserRepository_CreateUsernameChangeRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeactivateExpiredContractors provides a mock function with given fields: ctx, today
+func (_m *MockUserRepository) DeactivateExpiredContractors(ctx context.Context, today time.Time) ([]int, error) {
+	ret := _m.Called(ctx, today)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeactivateExpiredContractors")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]int, error)); ok {
+		return rf(ctx, today)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []int); ok {
+		r0 = rf(ctx, today)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, today)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_DeactivateExpiredContractors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeactivateExpiredContractors'
+type MockUserRepository_DeactivateExpiredContractors_Call struct {
+	*mock.Call
+}
+
+// DeactivateExpiredContractors is a helper method to define mock.On call
+//   - ctx context.Context
+//   - today time.Time
+func (_e *MockUserRepository_Expecter) DeactivateExpiredContractors(ctx interface{}, today interface{}) *MockUserRepository_DeactivateExpiredContractors_Call {
+	return &MockUserRepository_DeactivateExpiredContractors_Call{Call: _e.mock.On("DeactivateExpiredContractors", ctx, today)}
+}
+
+func (_c *MockUserRepository_DeactivateExpiredContractors_Call) Run(run func(ctx context.Context, today time.Time)) *MockUserRepository_DeactivateExpiredContractors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_DeactivateExpiredContractors_Call) Return(_a0 []int, _a1 error) *MockUserRepository_DeactivateExpiredContractors_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_DeactivateExpiredContractors_Call) RunAndReturn(run func(context.Context, time.Time) ([]int, error)) *MockUserRepository_DeactivateExpiredContractors_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUserByID provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) DeleteUserByID(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserByID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_DeleteUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUserByID'
+type MockUserRepository_DeleteUserByID_Call struct {
+	*mock.Call
+}
+
+// DeleteUserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) DeleteUserByID(ctx interface{}, id interface{}) *MockUserRepository_DeleteUserByID_Call {
+	return &MockUserRepository_DeleteUserByID_Call{Call: _e.mock.On("DeleteUserByID", ctx, id)}
+}
+
+func (_c *MockUserRepository_DeleteUserByID_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_DeleteUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_DeleteUserByID_Call) Return(_a0 error) *MockUserRepository_DeleteUserByID_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_DeleteUserByID_Call) RunAndReturn(run func(context.Context, int) error) *MockUserRepository_DeleteUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EncryptLegacyPII provides a mock function with given fields: ctx
+func (_m *MockUserRepository) EncryptLegacyPII(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EncryptLegacyPII")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
 	}
-	return args.Get(0).(*models.Role), args.Error(1)
+
+	return r0, r1
+}
+
+// MockUserRepository_EncryptLegacyPII_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EncryptLegacyPII'
+type MockUserRepository_EncryptLegacyPII_Call struct {
+	*mock.Call
+}
+
+// EncryptLegacyPII is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockUserRepository_Expecter) EncryptLegacyPII(ctx interface{}) *MockUserRepository_EncryptLegacyPII_Call {
+	return &MockUserRepository_EncryptLegacyPII_Call{Call: _e.mock.On("EncryptLegacyPII", ctx)}
+}
+
+func (_c *MockUserRepository_EncryptLegacyPII_Call) Run(run func(ctx context.Context)) *MockUserRepository_EncryptLegacyPII_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
 }
 
-// Buat mock serupa untuk ShiftRepository, ScheduleRepository, AttendanceRepository...
+func (_c *MockUserRepository_EncryptLegacyPII_Call) Return(_a0 int, _a1 error) *MockUserRepository_EncryptLegacyPII_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_EncryptLegacyPII_Call) RunAndReturn(run func(context.Context) (int, error)) *MockUserRepository_EncryptLegacyPII_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportUsers provides a mock function with given fields: ctx, filter, fn
+func (_m *MockUserRepository) ExportUsers(ctx context.Context, filter models.UserExportFilter, fn func(*models.UserExportRow) error) error {
+	ret := _m.Called(ctx, filter, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUsers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.UserExportFilter, func(*models.UserExportRow) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_ExportUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportUsers'
+type MockUserRepository_ExportUsers_Call struct {
+	*mock.Call
+}
+
+// ExportUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.UserExportFilter
+//   - fn func(*models.UserExportRow) error
+func (_e *MockUserRepository_Expecter) ExportUsers(ctx interface{}, filter interface{}, fn interface{}) *MockUserRepository_ExportUsers_Call {
+	return &MockUserRepository_ExportUsers_Call{Call: _e.mock.On("ExportUsers", ctx, filter, fn)}
+}
+
+func (_c *MockUserRepository_ExportUsers_Call) Run(run func(ctx context.Context, filter models.UserExportFilter, fn func(*models.UserExportRow) error)) *MockUserRepository_ExportUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.UserExportFilter), args[2].(func(*models.UserExportRow) error))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ExportUsers_Call) Return(_a0 error) *MockUserRepository_ExportUsers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_ExportUsers_Call) RunAndReturn(run func(context.Context, models.UserExportFilter, func(*models.UserExportRow) error) error) *MockUserRepository_ExportUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ForceRevokeSessions provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) ForceRevokeSessions(ctx context.Context, id int) (int, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForceRevokeSessions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_ForceRevokeSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForceRevokeSessions'
+type MockUserRepository_ForceRevokeSessions_Call struct {
+	*mock.Call
+}
+
+// ForceRevokeSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) ForceRevokeSessions(ctx interface{}, id interface{}) *MockUserRepository_ForceRevokeSessions_Call {
+	return &MockUserRepository_ForceRevokeSessions_Call{Call: _e.mock.On("ForceRevokeSessions", ctx, id)}
+}
+
+func (_c *MockUserRepository_ForceRevokeSessions_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_ForceRevokeSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ForceRevokeSessions_Call) Return(_a0 int, _a1 error) *MockUserRepository_ForceRevokeSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_ForceRevokeSessions_Call) RunAndReturn(run func(context.Context, int) (int, error)) *MockUserRepository_ForceRevokeSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllUsers provides a mock function with given fields: ctx, page, limit
+func (_m *MockUserRepository) GetAllUsers(ctx context.Context, page int, limit int) ([]models.User, int, error) {
+	ret := _m.Called(ctx, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllUsers")
+	}
+
+	var r0 []models.User
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.User, int, error)); ok {
+		return rf(ctx, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.User); ok {
+		r0 = rf(ctx, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = rf(ctx, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockUserRepository_GetAllUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllUsers'
+type MockUserRepository_GetAllUsers_Call struct {
+	*mock.Call
+}
+
+// GetAllUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - limit int
+func (_e *MockUserRepository_Expecter) GetAllUsers(ctx interface{}, page interface{}, limit interface{}) *MockUserRepository_GetAllUsers_Call {
+	return &MockUserRepository_GetAllUsers_Call{Call: _e.mock.On("GetAllUsers", ctx, page, limit)}
+}
+
+func (_c *MockUserRepository_GetAllUsers_Call) Run(run func(ctx context.Context, page int, limit int)) *MockUserRepository_GetAllUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetAllUsers_Call) Return(_a0 []models.User, _a1 int, _a2 error) *MockUserRepository_GetAllUsers_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockUserRepository_GetAllUsers_Call) RunAndReturn(run func(context.Context, int, int) ([]models.User, int, error)) *MockUserRepository_GetAllUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetEmailChangeByID provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) GetEmailChangeByID(ctx context.Context, id int) (*models.EmailChangeRequest, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEmailChangeByID")
+	}
+
+	var r0 *models.EmailChangeRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.EmailChangeRequest, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.EmailChangeRequest); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailChangeRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetEmailChangeByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEmailChangeByID'
+type MockUserRepository_GetEmailChangeByID_Call struct {
+	*mock.Call
+}
+
+// GetEmailChangeByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) GetEmailChangeByID(ctx interface{}, id interface{}) *MockUserRepository_GetEmailChangeByID_Call {
+	return &MockUserRepository_GetEmailChangeByID_Call{Call: _e.mock.On("GetEmailChangeByID", ctx, id)}
+}
+
+func (_c *MockUserRepository_GetEmailChangeByID_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_GetEmailChangeByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetEmailChangeByID_Call) Return(_a0 *models.EmailChangeRequest, _a1 error) *MockUserRepository_GetEmailChangeByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetEmailChangeByID_Call) RunAndReturn(run func(context.Context, int) (*models.EmailChangeRequest, error)) *MockUserRepository_GetEmailChangeByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetInvitationByID provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) GetInvitationByID(ctx context.Context, id int) (*models.Invitation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInvitationByID")
+	}
+
+	var r0 *models.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Invitation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Invitation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetInvitationByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInvitationByID'
+type MockUserRepository_GetInvitationByID_Call struct {
+	*mock.Call
+}
+
+// GetInvitationByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) GetInvitationByID(ctx interface{}, id interface{}) *MockUserRepository_GetInvitationByID_Call {
+	return &MockUserRepository_GetInvitationByID_Call{Call: _e.mock.On("GetInvitationByID", ctx, id)}
+}
+
+func (_c *MockUserRepository_GetInvitationByID_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_GetInvitationByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetInvitationByID_Call) Return(_a0 *models.Invitation, _a1 error) *MockUserRepository_GetInvitationByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetInvitationByID_Call) RunAndReturn(run func(context.Context, int) (*models.Invitation, error)) *MockUserRepository_GetInvitationByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetInvitations provides a mock function with given fields: ctx, status, page, limit
+func (_m *MockUserRepository) GetInvitations(ctx context.Context, status string, page int, limit int) ([]models.Invitation, int, error) {
+	ret := _m.Called(ctx, status, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInvitations")
+	}
+
+	var r0 []models.Invitation
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]models.Invitation, int, error)); ok {
+		return rf(ctx, status, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []models.Invitation); ok {
+		r0 = rf(ctx, status, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
+		r1 = rf(ctx, status, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = rf(ctx, status, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockUserRepository_GetInvitations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInvitations'
+type MockUserRepository_GetInvitations_Call struct {
+	*mock.Call
+}
+
+// GetInvitations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - status string
+//   - page int
+//   - limit int
+func (_e *MockUserRepository_Expecter) GetInvitations(ctx interface{}, status interface{}, page interface{}, limit interface{}) *MockUserRepository_GetInvitations_Call {
+	return &MockUserRepository_GetInvitations_Call{Call: _e.mock.On("GetInvitations", ctx, status, page, limit)}
+}
+
+func (_c *MockUserRepository_GetInvitations_Call) Run(run func(ctx context.Context, status string, page int, limit int)) *MockUserRepository_GetInvitations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetInvitations_Call) Return(_a0 []models.Invitation, _a1 int, _a2 error) *MockUserRepository_GetInvitations_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockUserRepository_GetInvitations_Call) RunAndReturn(run func(context.Context, string, int, int) ([]models.Invitation, int, error)) *MockUserRepository_GetInvitations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOpenPhoneOTP provides a mock function with given fields: ctx, userID, purpose
+func (_m *MockUserRepository) GetOpenPhoneOTP(ctx context.Context, userID int, purpose string) (*models.PhoneOTP, error) {
+	ret := _m.Called(ctx, userID, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOpenPhoneOTP")
+	}
+
+	var r0 *models.PhoneOTP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (*models.PhoneOTP, error)); ok {
+		return rf(ctx, userID, purpose)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *models.PhoneOTP); ok {
+		r0 = rf(ctx, userID, purpose)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PhoneOTP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, purpose)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetOpenPhoneOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOpenPhoneOTP'
+type MockUserRepository_GetOpenPhoneOTP_Call struct {
+	*mock.Call
+}
+
+// GetOpenPhoneOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - purpose string
+func (_e *MockUserRepository_Expecter) GetOpenPhoneOTP(ctx interface{}, userID interface{}, purpose interface{}) *MockUserRepository_GetOpenPhoneOTP_Call {
+	return &MockUserRepository_GetOpenPhoneOTP_Call{Call: _e.mock.On("GetOpenPhoneOTP", ctx, userID, purpose)}
+}
+
+func (_c *MockUserRepository_GetOpenPhoneOTP_Call) Run(run func(ctx context.Context, userID int, purpose string)) *MockUserRepository_GetOpenPhoneOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetOpenPhoneOTP_Call) Return(_a0 *models.PhoneOTP, _a1 error) *MockUserRepository_GetOpenPhoneOTP_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetOpenPhoneOTP_Call) RunAndReturn(run func(context.Context, int, string) (*models.PhoneOTP, error)) *MockUserRepository_GetOpenPhoneOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPendingEmailChange provides a mock function with given fields: ctx, userID
+func (_m *MockUserRepository) GetPendingEmailChange(ctx context.Context, userID int) (*models.EmailChangeRequest, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingEmailChange")
+	}
+
+	var r0 *models.EmailChangeRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.EmailChangeRequest, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.EmailChangeRequest); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailChangeRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetPendingEmailChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingEmailChange'
+type MockUserRepository_GetPendingEmailChange_Call struct {
+	*mock.Call
+}
+
+// GetPendingEmailChange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockUserRepository_Expecter) GetPendingEmailChange(ctx interface{}, userID interface{}) *MockUserRepository_GetPendingEmailChange_Call {
+	return &MockUserRepository_GetPendingEmailChange_Call{Call: _e.mock.On("GetPendingEmailChange", ctx, userID)}
+}
+
+func (_c *MockUserRepository_GetPendingEmailChange_Call) Run(run func(ctx context.Context, userID int)) *MockUserRepository_GetPendingEmailChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetPendingEmailChange_Call) Return(_a0 *models.EmailChangeRequest, _a1 error) *MockUserRepository_GetPendingEmailChange_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetPendingEmailChange_Call) RunAndReturn(run func(context.Context, int) (*models.EmailChangeRequest, error)) *MockUserRepository_GetPendingEmailChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPendingProbationReviews provides a mock function with given fields: ctx, endingBy
+func (_m *MockUserRepository) GetPendingProbationReviews(ctx context.Context, endingBy time.Time) ([]models.User, error) {
+	ret := _m.Called(ctx, endingBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingProbationReviews")
+	}
+
+	var r0 []models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]models.User, error)); ok {
+		return rf(ctx, endingBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []models.User); ok {
+		r0 = rf(ctx, endingBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, endingBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetPendingProbationReviews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingProbationReviews'
+type MockUserRepository_GetPendingProbationReviews_Call struct {
+	*mock.Call
+}
+
+// GetPendingProbationReviews is a helper method to define mock.On call
+//   - ctx context.Context
+//   - endingBy time.Time
+func (_e *MockUserRepository_Expecter) GetPendingProbationReviews(ctx interface{}, endingBy interface{}) *MockUserRepository_GetPendingProbationReviews_Call {
+	return &MockUserRepository_GetPendingProbationReviews_Call{Call: _e.mock.On("GetPendingProbationReviews", ctx, endingBy)}
+}
+
+func (_c *MockUserRepository_GetPendingProbationReviews_Call) Run(run func(ctx context.Context, endingBy time.Time)) *MockUserRepository_GetPendingProbationReviews_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetPendingProbationReviews_Call) Return(_a0 []models.User, _a1 error) *MockUserRepository_GetPendingProbationReviews_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetPendingProbationReviews_Call) RunAndReturn(run func(context.Context, time.Time) ([]models.User, error)) *MockUserRepository_GetPendingProbationReviews_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPendingUsernameChange provides a mock function with given fields: ctx, userID
+func (_m *MockUserRepository) GetPendingUsernameChange(ctx context.Context, userID int) (*models.UsernameChangeRequest, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingUsernameChange")
+	}
+
+	var r0 *models.UsernameChangeRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.UsernameChangeRequest, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.UsernameChangeRequest); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UsernameChangeRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetPendingUsernameChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingUsernameChange'
+type MockUserRepository_GetPendingUsernameChange_Call struct {
+	*mock.Call
+}
+
+// GetPendingUsernameChange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockUserRepository_Expecter) GetPendingUsernameChange(ctx interface{}, userID interface{}) *MockUserRepository_GetPendingUsernameChange_Call {
+	return &MockUserRepository_GetPendingUsernameChange_Call{Call: _e.mock.On("GetPendingUsernameChange", ctx, userID)}
+}
+
+func (_c *MockUserRepository_GetPendingUsernameChange_Call) Run(run func(ctx context.Context, userID int)) *MockUserRepository_GetPendingUsernameChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetPendingUsernameChange_Call) Return(_a0 *models.UsernameChangeRequest, _a1 error) *MockUserRepository_GetPendingUsernameChange_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetPendingUsernameChange_Call) RunAndReturn(run func(context.Context, int) (*models.UsernameChangeRequest, error)) *MockUserRepository_GetPendingUsernameChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPreviousUsernames provides a mock function with given fields: ctx, userID
+func (_m *MockUserRepository) GetPreviousUsernames(ctx context.Context, userID int) ([]models.PreviousUsername, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPreviousUsernames")
+	}
+
+	var r0 []models.PreviousUsername
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.PreviousUsername, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.PreviousUsername); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PreviousUsername)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetPreviousUsernames_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPreviousUsernames'
+type MockUserRepository_GetPreviousUsernames_Call struct {
+	*mock.Call
+}
+
+// GetPreviousUsernames is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockUserRepository_Expecter) GetPreviousUsernames(ctx interface{}, userID interface{}) *MockUserRepository_GetPreviousUsernames_Call {
+	return &MockUserRepository_GetPreviousUsernames_Call{Call: _e.mock.On("GetPreviousUsernames", ctx, userID)}
+}
+
+func (_c *MockUserRepository_GetPreviousUsernames_Call) Run(run func(ctx context.Context, userID int)) *MockUserRepository_GetPreviousUsernames_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetPreviousUsernames_Call) Return(_a0 []models.PreviousUsername, _a1 error) *MockUserRepository_GetPreviousUsernames_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetPreviousUsernames_Call) RunAndReturn(run func(context.Context, int) ([]models.PreviousUsername, error)) *MockUserRepository_GetPreviousUsernames_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetReportingTree provides a mock function with given fields: ctx, rootID, dayStart
+func (_m *MockUserRepository) GetReportingTree(ctx context.Context, rootID *int, dayStart time.Time) ([]models.OrgChartNode, error) {
+	ret := _m.Called(ctx, rootID, dayStart)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReportingTree")
+	}
+
+	var r0 []models.OrgChartNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *int, time.Time) ([]models.OrgChartNode, error)); ok {
+		return rf(ctx, rootID, dayStart)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *int, time.Time) []models.OrgChartNode); ok {
+		r0 = rf(ctx, rootID, dayStart)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OrgChartNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *int, time.Time) error); ok {
+		r1 = rf(ctx, rootID, dayStart)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetReportingTree_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReportingTree'
+type MockUserRepository_GetReportingTree_Call struct {
+	*mock.Call
+}
+
+// GetReportingTree is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rootID *int
+//   - dayStart time.Time
+func (_e *MockUserRepository_Expecter) GetReportingTree(ctx interface{}, rootID interface{}, dayStart interface{}) *MockUserRepository_GetReportingTree_Call {
+	return &MockUserRepository_GetReportingTree_Call{Call: _e.mock.On("GetReportingTree", ctx, rootID, dayStart)}
+}
+
+func (_c *MockUserRepository_GetReportingTree_Call) Run(run func(ctx context.Context, rootID *int, dayStart time.Time)) *MockUserRepository_GetReportingTree_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*int), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetReportingTree_Call) Return(_a0 []models.OrgChartNode, _a1 error) *MockUserRepository_GetReportingTree_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetReportingTree_Call) RunAndReturn(run func(context.Context, *int, time.Time) ([]models.OrgChartNode, error)) *MockUserRepository_GetReportingTree_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTokenVersion provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) GetTokenVersion(ctx context.Context, id int) (int, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTokenVersion")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetTokenVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTokenVersion'
+type MockUserRepository_GetTokenVersion_Call struct {
+	*mock.Call
+}
+
+// GetTokenVersion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) GetTokenVersion(ctx interface{}, id interface{}) *MockUserRepository_GetTokenVersion_Call {
+	return &MockUserRepository_GetTokenVersion_Call{Call: _e.mock.On("GetTokenVersion", ctx, id)}
+}
+
+func (_c *MockUserRepository_GetTokenVersion_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_GetTokenVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetTokenVersion_Call) Return(_a0 int, _a1 error) *MockUserRepository_GetTokenVersion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetTokenVersion_Call) RunAndReturn(run func(context.Context, int) (int, error)) *MockUserRepository_GetTokenVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByEmail provides a mock function with given fields: ctx, email
+func (_m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByEmail")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
+type MockUserRepository_GetUserByEmail_Call struct {
+	*mock.Call
+}
+
+// GetUserByEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *MockUserRepository_Expecter) GetUserByEmail(ctx interface{}, email interface{}) *MockUserRepository_GetUserByEmail_Call {
+	return &MockUserRepository_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", ctx, email)}
+}
+
+func (_c *MockUserRepository_GetUserByEmail_Call) Run(run func(ctx context.Context, email string)) *MockUserRepository_GetUserByEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserByEmail_Call) Return(_a0 *models.User, _a1 error) *MockUserRepository_GetUserByEmail_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserByEmail_Call) RunAndReturn(run func(context.Context, string) (*models.User, error)) *MockUserRepository_GetUserByEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) GetUserByID(ctx context.Context, id int) (*models.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByID'
+type MockUserRepository_GetUserByID_Call struct {
+	*mock.Call
+}
+
+// GetUserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) GetUserByID(ctx interface{}, id interface{}) *MockUserRepository_GetUserByID_Call {
+	return &MockUserRepository_GetUserByID_Call{Call: _e.mock.On("GetUserByID", ctx, id)}
+}
+
+func (_c *MockUserRepository_GetUserByID_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_GetUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserByID_Call) Return(_a0 *models.User, _a1 error) *MockUserRepository_GetUserByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserByID_Call) RunAndReturn(run func(context.Context, int) (*models.User, error)) *MockUserRepository_GetUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByUsername provides a mock function with given fields: ctx, username
+func (_m *MockUserRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByUsername")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetUserByUsername_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByUsername'
+type MockUserRepository_GetUserByUsername_Call struct {
+	*mock.Call
+}
+
+// GetUserByUsername is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+func (_e *MockUserRepository_Expecter) GetUserByUsername(ctx interface{}, username interface{}) *MockUserRepository_GetUserByUsername_Call {
+	return &MockUserRepository_GetUserByUsername_Call{Call: _e.mock.On("GetUserByUsername", ctx, username)}
+}
+
+func (_c *MockUserRepository_GetUserByUsername_Call) Run(run func(ctx context.Context, username string)) *MockUserRepository_GetUserByUsername_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserByUsername_Call) Return(_a0 *models.User, _a1 error) *MockUserRepository_GetUserByUsername_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserByUsername_Call) RunAndReturn(run func(context.Context, string) (*models.User, error)) *MockUserRepository_GetUserByUsername_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserChanges provides a mock function with given fields: ctx, cursor, limit
+func (_m *MockUserRepository) GetUserChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+	ret := _m.Called(ctx, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserChanges")
+	}
+
+	var r0 *models.SyncBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.SyncCursor, int) (*models.SyncBatch, error)); ok {
+		return rf(ctx, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.SyncCursor, int) *models.SyncBatch); ok {
+		r0 = rf(ctx, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SyncBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.SyncCursor, int) error); ok {
+		r1 = rf(ctx, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetUserChanges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserChanges'
+type MockUserRepository_GetUserChanges_Call struct {
+	*mock.Call
+}
+
+// GetUserChanges is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cursor models.SyncCursor
+//   - limit int
+func (_e *MockUserRepository_Expecter) GetUserChanges(ctx interface{}, cursor interface{}, limit interface{}) *MockUserRepository_GetUserChanges_Call {
+	return &MockUserRepository_GetUserChanges_Call{Call: _e.mock.On("GetUserChanges", ctx, cursor, limit)}
+}
+
+func (_c *MockUserRepository_GetUserChanges_Call) Run(run func(ctx context.Context, cursor models.SyncCursor, limit int)) *MockUserRepository_GetUserChanges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.SyncCursor), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserChanges_Call) Return(_a0 *models.SyncBatch, _a1 error) *MockUserRepository_GetUserChanges_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserChanges_Call) RunAndReturn(run func(context.Context, models.SyncCursor, int) (*models.SyncBatch, error)) *MockUserRepository_GetUserChanges_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserSummariesByIDs provides a mock function with given fields: ctx, ids
+func (_m *MockUserRepository) GetUserSummariesByIDs(ctx context.Context, ids []int) ([]models.User, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserSummariesByIDs")
+	}
+
+	var r0 []models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) ([]models.User, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) []models.User); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetUserSummariesByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserSummariesByIDs'
+type MockUserRepository_GetUserSummariesByIDs_Call struct {
+	*mock.Call
+}
+
+// GetUserSummariesByIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int
+func (_e *MockUserRepository_Expecter) GetUserSummariesByIDs(ctx interface{}, ids interface{}) *MockUserRepository_GetUserSummariesByIDs_Call {
+	return &MockUserRepository_GetUserSummariesByIDs_Call{Call: _e.mock.On("GetUserSummariesByIDs", ctx, ids)}
+}
+
+func (_c *MockUserRepository_GetUserSummariesByIDs_Call) Run(run func(ctx context.Context, ids []int)) *MockUserRepository_GetUserSummariesByIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserSummariesByIDs_Call) Return(_a0 []models.User, _a1 error) *MockUserRepository_GetUserSummariesByIDs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserSummariesByIDs_Call) RunAndReturn(run func(context.Context, []int) ([]models.User, error)) *MockUserRepository_GetUserSummariesByIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUsernameChangeRequests provides a mock function with given fields: ctx, status, page, limit
+func (_m *MockUserRepository) GetUsernameChangeRequests(ctx context.Context, status string, page int, limit int) ([]models.UsernameChangeRequest, int, error) {
+	ret := _m.Called(ctx, status, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsernameChangeRequests")
+	}
+
+	var r0 []models.UsernameChangeRequest
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]models.UsernameChangeRequest, int, error)); ok {
+		return rf(ctx, status, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []models.UsernameChangeRequest); ok {
+		r0 = rf(ctx, status, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UsernameChangeRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
+		r1 = rf(ctx, status, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = rf(ctx, status, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockUserRepository_GetUsernameChangeRequests_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUsernameChangeRequests'
+type MockUserRepository_GetUsernameChangeRequests_Call struct {
+	*mock.Call
+}
+
+// GetUsernameChangeRequests is a helper method to define mock.On call
+//   - ctx context.Context
+//   - status string
+//   - page int
+//   - limit int
+func (_e *MockUserRepository_Expecter) GetUsernameChangeRequests(ctx interface{}, status interface{}, page interface{}, limit interface{}) *MockUserRepository_GetUsernameChangeRequests_Call {
+	return &MockUserRepository_GetUsernameChangeRequests_Call{Call: _e.mock.On("GetUsernameChangeRequests", ctx, status, page, limit)}
+}
+
+func (_c *MockUserRepository_GetUsernameChangeRequests_Call) Run(run func(ctx context.Context, status string, page int, limit int)) *MockUserRepository_GetUsernameChangeRequests_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetUsernameChangeRequests_Call) Return(_a0 []models.UsernameChangeRequest, _a1 int, _a2 error) *MockUserRepository_GetUsernameChangeRequests_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockUserRepository_GetUsernameChangeRequests_Call) RunAndReturn(run func(context.Context, string, int, int) ([]models.UsernameChangeRequest, int, error)) *MockUserRepository_GetUsernameChangeRequests_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsInReportingLine provides a mock function with given fields: ctx, managerID, userID
+func (_m *MockUserRepository) IsInReportingLine(ctx context.Context, managerID int, userID int) (bool, error) {
+	ret := _m.Called(ctx, managerID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsInReportingLine")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (bool, error)); ok {
+		return rf(ctx, managerID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) bool); ok {
+		r0 = rf(ctx, managerID, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, managerID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_IsInReportingLine_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsInReportingLine'
+type MockUserRepository_IsInReportingLine_Call struct {
+	*mock.Call
+}
+
+// IsInReportingLine is a helper method to define mock.On call
+//   - ctx context.Context
+//   - managerID int
+//   - userID int
+func (_e *MockUserRepository_Expecter) IsInReportingLine(ctx interface{}, managerID interface{}, userID interface{}) *MockUserRepository_IsInReportingLine_Call {
+	return &MockUserRepository_IsInReportingLine_Call{Call: _e.mock.On("IsInReportingLine", ctx, managerID, userID)}
+}
+
+func (_c *MockUserRepository_IsInReportingLine_Call) Run(run func(ctx context.Context, managerID int, userID int)) *MockUserRepository_IsInReportingLine_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_IsInReportingLine_Call) Return(_a0 bool, _a1 error) *MockUserRepository_IsInReportingLine_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_IsInReportingLine_Call) RunAndReturn(run func(context.Context, int, int) (bool, error)) *MockUserRepository_IsInReportingLine_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkProbationNotified provides a mock function with given fields: ctx, ids
+func (_m *MockUserRepository) MarkProbationNotified(ctx context.Context, ids []int) error {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkProbationNotified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) error); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_MarkProbationNotified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkProbationNotified'
+type MockUserRepository_MarkProbationNotified_Call struct {
+	*mock.Call
+}
+
+// MarkProbationNotified is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int
+func (_e *MockUserRepository_Expecter) MarkProbationNotified(ctx interface{}, ids interface{}) *MockUserRepository_MarkProbationNotified_Call {
+	return &MockUserRepository_MarkProbationNotified_Call{Call: _e.mock.On("MarkProbationNotified", ctx, ids)}
+}
+
+func (_c *MockUserRepository_MarkProbationNotified_Call) Run(run func(ctx context.Context, ids []int)) *MockUserRepository_MarkProbationNotified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_MarkProbationNotified_Call) Return(_a0 error) *MockUserRepository_MarkProbationNotified_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_MarkProbationNotified_Call) RunAndReturn(run func(context.Context, []int) error) *MockUserRepository_MarkProbationNotified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PatchUserByID provides a mock function with given fields: ctx, id, input
+func (_m *MockUserRepository) PatchUserByID(ctx context.Context, id int, input *models.AdminPatchUserInput) (int, error) {
+	ret := _m.Called(ctx, id, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchUserByID")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.AdminPatchUserInput) (int, error)); ok {
+		return rf(ctx, id, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.AdminPatchUserInput) int); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.AdminPatchUserInput) error); ok {
+		r1 = rf(ctx, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_PatchUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PatchUserByID'
+type MockUserRepository_PatchUserByID_Call struct {
+	*mock.Call
+}
+
+// PatchUserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - input *models.AdminPatchUserInput
+func (_e *MockUserRepository_Expecter) PatchUserByID(ctx interface{}, id interface{}, input interface{}) *MockUserRepository_PatchUserByID_Call {
+	return &MockUserRepository_PatchUserByID_Call{Call: _e.mock.On("PatchUserByID", ctx, id, input)}
+}
+
+func (_c *MockUserRepository_PatchUserByID_Call) Run(run func(ctx context.Context, id int, input *models.AdminPatchUserInput)) *MockUserRepository_PatchUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.AdminPatchUserInput))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_PatchUserByID_Call) Return(_a0 int, _a1 error) *MockUserRepository_PatchUserByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_PatchUserByID_Call) RunAndReturn(run func(context.Context, int, *models.AdminPatchUserInput) (int, error)) *MockUserRepository_PatchUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordPhoneOTPAttempt provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) RecordPhoneOTPAttempt(ctx context.Context, id int) (int, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordPhoneOTPAttempt")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_RecordPhoneOTPAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordPhoneOTPAttempt'
+type MockUserRepository_RecordPhoneOTPAttempt_Call struct {
+	*mock.Call
+}
+
+// RecordPhoneOTPAttempt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) RecordPhoneOTPAttempt(ctx interface{}, id interface{}) *MockUserRepository_RecordPhoneOTPAttempt_Call {
+	return &MockUserRepository_RecordPhoneOTPAttempt_Call{Call: _e.mock.On("RecordPhoneOTPAttempt", ctx, id)}
+}
+
+func (_c *MockUserRepository_RecordPhoneOTPAttempt_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_RecordPhoneOTPAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_RecordPhoneOTPAttempt_Call) Return(_a0 int, _a1 error) *MockUserRepository_RecordPhoneOTPAttempt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_RecordPhoneOTPAttempt_Call) RunAndReturn(run func(context.Context, int) (int, error)) *MockUserRepository_RecordPhoneOTPAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RenewEmailChangeRequest provides a mock function with given fields: ctx, id, expiresAt
+func (_m *MockUserRepository) RenewEmailChangeRequest(ctx context.Context, id int, expiresAt time.Time) error {
+	ret := _m.Called(ctx, id, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewEmailChangeRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) error); ok {
+		r0 = rf(ctx, id, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_RenewEmailChangeRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenewEmailChangeRequest'
+type MockUserRepository_RenewEmailChangeRequest_Call struct {
+	*mock.Call
+}
+
+// RenewEmailChangeRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - expiresAt time.Time
+func (_e *MockUserRepository_Expecter) RenewEmailChangeRequest(ctx interface{}, id interface{}, expiresAt interface{}) *MockUserRepository_RenewEmailChangeRequest_Call {
+	return &MockUserRepository_RenewEmailChangeRequest_Call{Call: _e.mock.On("RenewEmailChangeRequest", ctx, id, expiresAt)}
+}
+
+func (_c *MockUserRepository_RenewEmailChangeRequest_Call) Run(run func(ctx context.Context, id int, expiresAt time.Time)) *MockUserRepository_RenewEmailChangeRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_RenewEmailChangeRequest_Call) Return(_a0 error) *MockUserRepository_RenewEmailChangeRequest_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_RenewEmailChangeRequest_Call) RunAndReturn(run func(context.Context, int, time.Time) error) *MockUserRepository_RenewEmailChangeRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RenewInvitation provides a mock function with given fields: ctx, id, expiresAt
+func (_m *MockUserRepository) RenewInvitation(ctx context.Context, id int, expiresAt time.Time) error {
+	ret := _m.Called(ctx, id, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) error); ok {
+		r0 = rf(ctx, id, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_RenewInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenewInvitation'
+type MockUserRepository_RenewInvitation_Call struct {
+	*mock.Call
+}
+
+// RenewInvitation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - expiresAt time.Time
+func (_e *MockUserRepository_Expecter) RenewInvitation(ctx interface{}, id interface{}, expiresAt interface{}) *MockUserRepository_RenewInvitation_Call {
+	return &MockUserRepository_RenewInvitation_Call{Call: _e.mock.On("RenewInvitation", ctx, id, expiresAt)}
+}
+
+func (_c *MockUserRepository_RenewInvitation_Call) Run(run func(ctx context.Context, id int, expiresAt time.Time)) *MockUserRepository_RenewInvitation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_RenewInvitation_Call) Return(_a0 error) *MockUserRepository_RenewInvitation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_RenewInvitation_Call) RunAndReturn(run func(context.Context, int, time.Time) error) *MockUserRepository_RenewInvitation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequirePasswordChange provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) RequirePasswordChange(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequirePasswordChange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_RequirePasswordChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequirePasswordChange'
+type MockUserRepository_RequirePasswordChange_Call struct {
+	*mock.Call
+}
+
+// RequirePasswordChange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) RequirePasswordChange(ctx interface{}, id interface{}) *MockUserRepository_RequirePasswordChange_Call {
+	return &MockUserRepository_RequirePasswordChange_Call{Call: _e.mock.On("RequirePasswordChange", ctx, id)}
+}
+
+func (_c *MockUserRepository_RequirePasswordChange_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_RequirePasswordChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_RequirePasswordChange_Call) Return(_a0 error) *MockUserRepository_RequirePasswordChange_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_RequirePasswordChange_Call) RunAndReturn(run func(context.Context, int) error) *MockUserRepository_RequirePasswordChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function with given fields: ctx, id, hashedPassword, expectedVersion
+func (_m *MockUserRepository) ResetPassword(ctx context.Context, id int, hashedPassword string, expectedVersion int) error {
+	ret := _m.Called(ctx, id, hashedPassword, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, int) error); ok {
+		r0 = rf(ctx, id, hashedPassword, expectedVersion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockUserRepository_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - hashedPassword string
+//   - expectedVersion int
+func (_e *MockUserRepository_Expecter) ResetPassword(ctx interface{}, id interface{}, hashedPassword interface{}, expectedVersion interface{}) *MockUserRepository_ResetPassword_Call {
+	return &MockUserRepository_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, id, hashedPassword, expectedVersion)}
+}
+
+func (_c *MockUserRepository_ResetPassword_Call) Run(run func(ctx context.Context, id int, hashedPassword string, expectedVersion int)) *MockUserRepository_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ResetPassword_Call) Return(_a0 error) *MockUserRepository_ResetPassword_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_ResetPassword_Call) RunAndReturn(run func(context.Context, int, string, int) error) *MockUserRepository_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResolveUsername provides a mock function with given fields: ctx, username
+func (_m *MockUserRepository) ResolveUsername(ctx context.Context, username string) (*models.UsernameResolution, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveUsername")
+	}
+
+	var r0 *models.UsernameResolution
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.UsernameResolution, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.UsernameResolution); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UsernameResolution)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_ResolveUsername_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveUsername'
+type MockUserRepository_ResolveUsername_Call struct {
+	*mock.Call
+}
+
+// ResolveUsername is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+func (_e *MockUserRepository_Expecter) ResolveUsername(ctx interface{}, username interface{}) *MockUserRepository_ResolveUsername_Call {
+	return &MockUserRepository_ResolveUsername_Call{Call: _e.mock.On("ResolveUsername", ctx, username)}
+}
+
+func (_c *MockUserRepository_ResolveUsername_Call) Run(run func(ctx context.Context, username string)) *MockUserRepository_ResolveUsername_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ResolveUsername_Call) Return(_a0 *models.UsernameResolution, _a1 error) *MockUserRepository_ResolveUsername_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_ResolveUsername_Call) RunAndReturn(run func(context.Context, string) (*models.UsernameResolution, error)) *MockUserRepository_ResolveUsername_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReviewUsernameChangeRequest provides a mock function with given fields: ctx, id, reviewerID, approve, note
+func (_m *MockUserRepository) ReviewUsernameChangeRequest(ctx context.Context, id int, reviewerID int, approve bool, note *string) (*models.UsernameChangeRequest, error) {
+	ret := _m.Called(ctx, id, reviewerID, approve, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReviewUsernameChangeRequest")
+	}
+
+	var r0 *models.UsernameChangeRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool, *string) (*models.UsernameChangeRequest, error)); ok {
+		return rf(ctx, id, reviewerID, approve, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool, *string) *models.UsernameChangeRequest); ok {
+		r0 = rf(ctx, id, reviewerID, approve, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UsernameChangeRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, bool, *string) error); ok {
+		r1 = rf(ctx, id, reviewerID, approve, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_ReviewUsernameChangeRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReviewUsernameChangeRequest'
+type MockUserRepository_ReviewUsernameChangeRequest_Call struct {
+	*mock.Call
+}
+
+// ReviewUsernameChangeRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - reviewerID int
+//   - approve bool
+//   - note *string
+func (_e *MockUserRepository_Expecter) ReviewUsernameChangeRequest(ctx interface{}, id interface{}, reviewerID interface{}, approve interface{}, note interface{}) *MockUserRepository_ReviewUsernameChangeRequest_Call {
+	return &MockUserRepository_ReviewUsernameChangeRequest_Call{Call: _e.mock.On("ReviewUsernameChangeRequest", ctx, id, reviewerID, approve, note)}
+}
+
+func (_c *MockUserRepository_ReviewUsernameChangeRequest_Call) Run(run func(ctx context.Context, id int, reviewerID int, approve bool, note *string)) *MockUserRepository_ReviewUsernameChangeRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(bool), args[4].(*string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ReviewUsernameChangeRequest_Call) Return(_a0 *models.UsernameChangeRequest, _a1 error) *MockUserRepository_ReviewUsernameChangeRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_ReviewUsernameChangeRequest_Call) RunAndReturn(run func(context.Context, int, int, bool, *string) (*models.UsernameChangeRequest, error)) *MockUserRepository_ReviewUsernameChangeRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeInvitation provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) RevokeInvitation(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_RevokeInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeInvitation'
+type MockUserRepository_RevokeInvitation_Call struct {
+	*mock.Call
+}
+
+// RevokeInvitation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockUserRepository_Expecter) RevokeInvitation(ctx interface{}, id interface{}) *MockUserRepository_RevokeInvitation_Call {
+	return &MockUserRepository_RevokeInvitation_Call{Call: _e.mock.On("RevokeInvitation", ctx, id)}
+}
+
+func (_c *MockUserRepository_RevokeInvitation_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_RevokeInvitation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_RevokeInvitation_Call) Return(_a0 error) *MockUserRepository_RevokeInvitation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_RevokeInvitation_Call) RunAndReturn(run func(context.Context, int) error) *MockUserRepository_RevokeInvitation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSessions provides a mock function with given fields: ctx, id, expectedVersion
+func (_m *MockUserRepository) RevokeSessions(ctx context.Context, id int, expectedVersion int) (int, error) {
+	ret := _m.Called(ctx, id, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSessions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int, error)); ok {
+		return rf(ctx, id, expectedVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int); ok {
+		r0 = rf(ctx, id, expectedVersion)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, id, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_RevokeSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSessions'
+type MockUserRepository_RevokeSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - expectedVersion int
+func (_e *MockUserRepository_Expecter) RevokeSessions(ctx interface{}, id interface{}, expectedVersion interface{}) *MockUserRepository_RevokeSessions_Call {
+	return &MockUserRepository_RevokeSessions_Call{Call: _e.mock.On("RevokeSessions", ctx, id, expectedVersion)}
+}
+
+func (_c *MockUserRepository_RevokeSessions_Call) Run(run func(ctx context.Context, id int, expectedVersion int)) *MockUserRepository_RevokeSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_RevokeSessions_Call) Return(_a0 int, _a1 error) *MockUserRepository_RevokeSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_RevokeSessions_Call) RunAndReturn(run func(context.Context, int, int) (int, error)) *MockUserRepository_RevokeSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserManager provides a mock function with given fields: ctx, userID, managerID
+func (_m *MockUserRepository) SetUserManager(ctx context.Context, userID int, managerID *int) error {
+	ret := _m.Called(ctx, userID, managerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserManager")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *int) error); ok {
+		r0 = rf(ctx, userID, managerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_SetUserManager_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserManager'
+type MockUserRepository_SetUserManager_Call struct {
+	*mock.Call
+}
+
+// SetUserManager is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - managerID *int
+func (_e *MockUserRepository_Expecter) SetUserManager(ctx interface{}, userID interface{}, managerID interface{}) *MockUserRepository_SetUserManager_Call {
+	return &MockUserRepository_SetUserManager_Call{Call: _e.mock.On("SetUserManager", ctx, userID, managerID)}
+}
+
+func (_c *MockUserRepository_SetUserManager_Call) Run(run func(ctx context.Context, userID int, managerID *int)) *MockUserRepository_SetUserManager_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_SetUserManager_Call) Return(_a0 error) *MockUserRepository_SetUserManager_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_SetUserManager_Call) RunAndReturn(run func(context.Context, int, *int) error) *MockUserRepository_SetUserManager_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateSMSPreferences provides a mock function with given fields: ctx, userID, input
+func (_m *MockUserRepository) UpdateSMSPreferences(ctx context.Context, userID int, input *models.UpdateSMSPreferencesInput) (*models.User, error) {
+	ret := _m.Called(ctx, userID, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSMSPreferences")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.UpdateSMSPreferencesInput) (*models.User, error)); ok {
+		return rf(ctx, userID, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.UpdateSMSPreferencesInput) *models.User); ok {
+		r0 = rf(ctx, userID, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.UpdateSMSPreferencesInput) error); ok {
+		r1 = rf(ctx, userID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_UpdateSMSPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSMSPreferences'
+type MockUserRepository_UpdateSMSPreferences_Call struct {
+	*mock.Call
+}
+
+// UpdateSMSPreferences is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - input *models.UpdateSMSPreferencesInput
+func (_e *MockUserRepository_Expecter) UpdateSMSPreferences(ctx interface{}, userID interface{}, input interface{}) *MockUserRepository_UpdateSMSPreferences_Call {
+	return &MockUserRepository_UpdateSMSPreferences_Call{Call: _e.mock.On("UpdateSMSPreferences", ctx, userID, input)}
+}
+
+func (_c *MockUserRepository_UpdateSMSPreferences_Call) Run(run func(ctx context.Context, userID int, input *models.UpdateSMSPreferencesInput)) *MockUserRepository_UpdateSMSPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.UpdateSMSPreferencesInput))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateSMSPreferences_Call) Return(_a0 *models.User, _a1 error) *MockUserRepository_UpdateSMSPreferences_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateSMSPreferences_Call) RunAndReturn(run func(context.Context, int, *models.UpdateSMSPreferencesInput) (*models.User, error)) *MockUserRepository_UpdateSMSPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserAccess provides a mock function with given fields: ctx, id, input, today
+func (_m *MockUserRepository) UpdateUserAccess(ctx context.Context, id int, input *models.UpdateUserAccessInput, today time.Time) error {
+	ret := _m.Called(ctx, id, input, today)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserAccess")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.UpdateUserAccessInput, time.Time) error); ok {
+		r0 = rf(ctx, id, input, today)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_UpdateUserAccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserAccess'
+type MockUserRepository_UpdateUserAccess_Call struct {
+	*mock.Call
+}
+
+// UpdateUserAccess is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - input *models.UpdateUserAccessInput
+//   - today time.Time
+func (_e *MockUserRepository_Expecter) UpdateUserAccess(ctx interface{}, id interface{}, input interface{}, today interface{}) *MockUserRepository_UpdateUserAccess_Call {
+	return &MockUserRepository_UpdateUserAccess_Call{Call: _e.mock.On("UpdateUserAccess", ctx, id, input, today)}
+}
+
+func (_c *MockUserRepository_UpdateUserAccess_Call) Run(run func(ctx context.Context, id int, input *models.UpdateUserAccessInput, today time.Time)) *MockUserRepository_UpdateUserAccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.UpdateUserAccessInput), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserAccess_Call) Return(_a0 error) *MockUserRepository_UpdateUserAccess_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserAccess_Call) RunAndReturn(run func(context.Context, int, *models.UpdateUserAccessInput, time.Time) error) *MockUserRepository_UpdateUserAccess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserByID provides a mock function with given fields: ctx, id, input
+func (_m *MockUserRepository) UpdateUserByID(ctx context.Context, id int, input *models.AdminUpdateUserInput) error {
+	ret := _m.Called(ctx, id, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserByID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.AdminUpdateUserInput) error); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_UpdateUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserByID'
+type MockUserRepository_UpdateUserByID_Call struct {
+	*mock.Call
+}
+
+// UpdateUserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - input *models.AdminUpdateUserInput
+func (_e *MockUserRepository_Expecter) UpdateUserByID(ctx interface{}, id interface{}, input interface{}) *MockUserRepository_UpdateUserByID_Call {
+	return &MockUserRepository_UpdateUserByID_Call{Call: _e.mock.On("UpdateUserByID", ctx, id, input)}
+}
+
+func (_c *MockUserRepository_UpdateUserByID_Call) Run(run func(ctx context.Context, id int, input *models.AdminUpdateUserInput)) *MockUserRepository_UpdateUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.AdminUpdateUserInput))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserByID_Call) Return(_a0 error) *MockUserRepository_UpdateUserByID_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserByID_Call) RunAndReturn(run func(context.Context, int, *models.AdminUpdateUserInput) error) *MockUserRepository_UpdateUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserEmployment provides a mock function with given fields: ctx, id, input
+func (_m *MockUserRepository) UpdateUserEmployment(ctx context.Context, id int, input *models.UpdateEmploymentInput) error {
+	ret := _m.Called(ctx, id, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserEmployment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.UpdateEmploymentInput) error); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_UpdateUserEmployment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserEmployment'
+type MockUserRepository_UpdateUserEmployment_Call struct {
+	*mock.Call
+}
+
+// UpdateUserEmployment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - input *models.UpdateEmploymentInput
+func (_e *MockUserRepository_Expecter) UpdateUserEmployment(ctx interface{}, id interface{}, input interface{}) *MockUserRepository_UpdateUserEmployment_Call {
+	return &MockUserRepository_UpdateUserEmployment_Call{Call: _e.mock.On("UpdateUserEmployment", ctx, id, input)}
+}
+
+func (_c *MockUserRepository_UpdateUserEmployment_Call) Run(run func(ctx context.Context, id int, input *models.UpdateEmploymentInput)) *MockUserRepository_UpdateUserEmployment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.UpdateEmploymentInput))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserEmployment_Call) Return(_a0 error) *MockUserRepository_UpdateUserEmployment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserEmployment_Call) RunAndReturn(run func(context.Context, int, *models.UpdateEmploymentInput) error) *MockUserRepository_UpdateUserEmployment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserPassword provides a mock function with given fields: ctx, id, hashedPassword
+func (_m *MockUserRepository) UpdateUserPassword(ctx context.Context, id int, hashedPassword string) error {
+	ret := _m.Called(ctx, id, hashedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, id, hashedPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_UpdateUserPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserPassword'
+type MockUserRepository_UpdateUserPassword_Call struct {
+	*mock.Call
+}
+
+// UpdateUserPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - hashedPassword string
+func (_e *MockUserRepository_Expecter) UpdateUserPassword(ctx interface{}, id interface{}, hashedPassword interface{}) *MockUserRepository_UpdateUserPassword_Call {
+	return &MockUserRepository_UpdateUserPassword_Call{Call: _e.mock.On("UpdateUserPassword", ctx, id, hashedPassword)}
+}
+
+func (_c *MockUserRepository_UpdateUserPassword_Call) Run(run func(ctx context.Context, id int, hashedPassword string)) *MockUserRepository_UpdateUserPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserPassword_Call) Return(_a0 error) *MockUserRepository_UpdateUserPassword_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserPassword_Call) RunAndReturn(run func(context.Context, int, string) error) *MockUserRepository_UpdateUserPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserProfile provides a mock function with given fields: ctx, id, input
+func (_m *MockUserRepository) UpdateUserProfile(ctx context.Context, id int, input *models.UpdateProfileInput) error {
+	ret := _m.Called(ctx, id, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserProfile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.UpdateProfileInput) error); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_UpdateUserProfile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserProfile'
+type MockUserRepository_UpdateUserProfile_Call struct {
+	*mock.Call
+}
+
+// UpdateUserProfile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - input *models.UpdateProfileInput
+func (_e *MockUserRepository_Expecter) UpdateUserProfile(ctx interface{}, id interface{}, input interface{}) *MockUserRepository_UpdateUserProfile_Call {
+	return &MockUserRepository_UpdateUserProfile_Call{Call: _e.mock.On("UpdateUserProfile", ctx, id, input)}
+}
+
+func (_c *MockUserRepository_UpdateUserProfile_Call) Run(run func(ctx context.Context, id int, input *models.UpdateProfileInput)) *MockUserRepository_UpdateUserProfile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.UpdateProfileInput))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserProfile_Call) Return(_a0 error) *MockUserRepository_UpdateUserProfile_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserProfile_Call) RunAndReturn(run func(context.Context, int, *models.UpdateProfileInput) error) *MockUserRepository_UpdateUserProfile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateWorkModes provides a mock function with given fields: ctx, id, remoteDays, fieldWorkAllowed
+func (_m *MockUserRepository) UpdateWorkModes(ctx context.Context, id int, remoteDays *string, fieldWorkAllowed *bool) error {
+	ret := _m.Called(ctx, id, remoteDays, fieldWorkAllowed)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWorkModes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *string, *bool) error); ok {
+		r0 = rf(ctx, id, remoteDays, fieldWorkAllowed)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_UpdateWorkModes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateWorkModes'
+type MockUserRepository_UpdateWorkModes_Call struct {
+	*mock.Call
+}
+
+// UpdateWorkModes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - remoteDays *string
+//   - fieldWorkAllowed *bool
+func (_e *MockUserRepository_Expecter) UpdateWorkModes(ctx interface{}, id interface{}, remoteDays interface{}, fieldWorkAllowed interface{}) *MockUserRepository_UpdateWorkModes_Call {
+	return &MockUserRepository_UpdateWorkModes_Call{Call: _e.mock.On("UpdateWorkModes", ctx, id, remoteDays, fieldWorkAllowed)}
+}
+
+func (_c *MockUserRepository_UpdateWorkModes_Call) Run(run func(ctx context.Context, id int, remoteDays *string, fieldWorkAllowed *bool)) *MockUserRepository_UpdateWorkModes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*string), args[3].(*bool))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateWorkModes_Call) Return(_a0 error) *MockUserRepository_UpdateWorkModes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateWorkModes_Call) RunAndReturn(run func(context.Context, int, *string, *bool) error) *MockUserRepository_UpdateWorkModes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockUserRepository creates a new instance of MockUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserRepository {
+	mock := &MockUserRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
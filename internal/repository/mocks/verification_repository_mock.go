@@ -0,0 +1,429 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/rakaarfi/attendance-system-be/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockVerificationRepository is an autogenerated mock type for the VerificationRepository type
+type MockVerificationRepository struct {
+	mock.Mock
+}
+
+type MockVerificationRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockVerificationRepository) EXPECT() *MockVerificationRepository_Expecter {
+	return &MockVerificationRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateVerificationLink provides a mock function with given fields: ctx, link
+func (_m *MockVerificationRepository) CreateVerificationLink(ctx context.Context, link *models.EmploymentVerificationLink) error {
+	ret := _m.Called(ctx, link)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateVerificationLink")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.EmploymentVerificationLink) error); ok {
+		r0 = rf(ctx, link)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockVerificationRepository_CreateVerificationLink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateVerificationLink'
+type MockVerificationRepository_CreateVerificationLink_Call struct {
+	*mock.Call
+}
+
+// CreateVerificationLink is a helper method to define mock.On call
+//   - ctx context.Context
+//   - link *models.EmploymentVerificationLink
+func (_e *MockVerificationRepository_Expecter) CreateVerificationLink(ctx interface{}, link interface{}) *MockVerificationRepository_CreateVerificationLink_Call {
+	return &MockVerificationRepository_CreateVerificationLink_Call{Call: _e.mock.On("CreateVerificationLink", ctx, link)}
+}
+
+func (_c *MockVerificationRepository_CreateVerificationLink_Call) Run(run func(ctx context.Context, link *models.EmploymentVerificationLink)) *MockVerificationRepository_CreateVerificationLink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.EmploymentVerificationLink))
+	})
+	return _c
+}
+
+func (_c *MockVerificationRepository_CreateVerificationLink_Call) Return(_a0 error) *MockVerificationRepository_CreateVerificationLink_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockVerificationRepository_CreateVerificationLink_Call) RunAndReturn(run func(context.Context, *models.EmploymentVerificationLink) error) *MockVerificationRepository_CreateVerificationLink_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetEmploymentVerification provides a mock function with given fields: ctx, userID, attendanceSince
+func (_m *MockVerificationRepository) GetEmploymentVerification(ctx context.Context, userID int, attendanceSince *time.Time) (*models.EmploymentVerification, error) {
+	ret := _m.Called(ctx, userID, attendanceSince)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEmploymentVerification")
+	}
+
+	var r0 *models.EmploymentVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *time.Time) (*models.EmploymentVerification, error)); ok {
+		return rf(ctx, userID, attendanceSince)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *time.Time) *models.EmploymentVerification); ok {
+		r0 = rf(ctx, userID, attendanceSince)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmploymentVerification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *time.Time) error); ok {
+		r1 = rf(ctx, userID, attendanceSince)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockVerificationRepository_GetEmploymentVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEmploymentVerification'
+type MockVerificationRepository_GetEmploymentVerification_Call struct {
+	*mock.Call
+}
+
+// GetEmploymentVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - attendanceSince *time.Time
+func (_e *MockVerificationRepository_Expecter) GetEmploymentVerification(ctx interface{}, userID interface{}, attendanceSince interface{}) *MockVerificationRepository_GetEmploymentVerification_Call {
+	return &MockVerificationRepository_GetEmploymentVerification_Call{Call: _e.mock.On("GetEmploymentVerification", ctx, userID, attendanceSince)}
+}
+
+func (_c *MockVerificationRepository_GetEmploymentVerification_Call) Run(run func(ctx context.Context, userID int, attendanceSince *time.Time)) *MockVerificationRepository_GetEmploymentVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*time.Time))
+	})
+	return _c
+}
+
+func (_c *MockVerificationRepository_GetEmploymentVerification_Call) Return(_a0 *models.EmploymentVerification, _a1 error) *MockVerificationRepository_GetEmploymentVerification_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockVerificationRepository_GetEmploymentVerification_Call) RunAndReturn(run func(context.Context, int, *time.Time) (*models.EmploymentVerification, error)) *MockVerificationRepository_GetEmploymentVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetVerificationAccesses provides a mock function with given fields: ctx, linkID
+func (_m *MockVerificationRepository) GetVerificationAccesses(ctx context.Context, linkID int) ([]models.EmploymentVerificationAccess, error) {
+	ret := _m.Called(ctx, linkID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVerificationAccesses")
+	}
+
+	var r0 []models.EmploymentVerificationAccess
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.EmploymentVerificationAccess, error)); ok {
+		return rf(ctx, linkID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.EmploymentVerificationAccess); ok {
+		r0 = rf(ctx, linkID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.EmploymentVerificationAccess)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, linkID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockVerificationRepository_GetVerificationAccesses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVerificationAccesses'
+type MockVerificationRepository_GetVerificationAccesses_Call struct {
+	*mock.Call
+}
+
+// GetVerificationAccesses is a helper method to define mock.On call
+//   - ctx context.Context
+//   - linkID int
+func (_e *MockVerificationRepository_Expecter) GetVerificationAccesses(ctx interface{}, linkID interface{}) *MockVerificationRepository_GetVerificationAccesses_Call {
+	return &MockVerificationRepository_GetVerificationAccesses_Call{Call: _e.mock.On("GetVerificationAccesses", ctx, linkID)}
+}
+
+func (_c *MockVerificationRepository_GetVerificationAccesses_Call) Run(run func(ctx context.Context, linkID int)) *MockVerificationRepository_GetVerificationAccesses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockVerificationRepository_GetVerificationAccesses_Call) Return(_a0 []models.EmploymentVerificationAccess, _a1 error) *MockVerificationRepository_GetVerificationAccesses_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockVerificationRepository_GetVerificationAccesses_Call) RunAndReturn(run func(context.Context, int) ([]models.EmploymentVerificationAccess, error)) *MockVerificationRepository_GetVerificationAccesses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetVerificationLink provides a mock function with given fields: ctx, id
+func (_m *MockVerificationRepository) GetVerificationLink(ctx context.Context, id int) (*models.EmploymentVerificationLink, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVerificationLink")
+	}
+
+	var r0 *models.EmploymentVerificationLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.EmploymentVerificationLink, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.EmploymentVerificationLink); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmploymentVerificationLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockVerificationRepository_GetVerificationLink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVerificationLink'
+type MockVerificationRepository_GetVerificationLink_Call struct {
+	*mock.Call
+}
+
+// GetVerificationLink is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockVerificationRepository_Expecter) GetVerificationLink(ctx interface{}, id interface{}) *MockVerificationRepository_GetVerificationLink_Call {
+	return &MockVerificationRepository_GetVerificationLink_Call{Call: _e.mock.On("GetVerificationLink", ctx, id)}
+}
+
+func (_c *MockVerificationRepository_GetVerificationLink_Call) Run(run func(ctx context.Context, id int)) *MockVerificationRepository_GetVerificationLink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockVerificationRepository_GetVerificationLink_Call) Return(_a0 *models.EmploymentVerificationLink, _a1 error) *MockVerificationRepository_GetVerificationLink_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockVerificationRepository_GetVerificationLink_Call) RunAndReturn(run func(context.Context, int) (*models.EmploymentVerificationLink, error)) *MockVerificationRepository_GetVerificationLink_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetVerificationLinksByUser provides a mock function with given fields: ctx, userID
+func (_m *MockVerificationRepository) GetVerificationLinksByUser(ctx context.Context, userID int) ([]models.EmploymentVerificationLink, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVerificationLinksByUser")
+	}
+
+	var r0 []models.EmploymentVerificationLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.EmploymentVerificationLink, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.EmploymentVerificationLink); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.EmploymentVerificationLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockVerificationRepository_GetVerificationLinksByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVerificationLinksByUser'
+type MockVerificationRepository_GetVerificationLinksByUser_Call struct {
+	*mock.Call
+}
+
+// GetVerificationLinksByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockVerificationRepository_Expecter) GetVerificationLinksByUser(ctx interface{}, userID interface{}) *MockVerificationRepository_GetVerificationLinksByUser_Call {
+	return &MockVerificationRepository_GetVerificationLinksByUser_Call{Call: _e.mock.On("GetVerificationLinksByUser", ctx, userID)}
+}
+
+func (_c *MockVerificationRepository_GetVerificationLinksByUser_Call) Run(run func(ctx context.Context, userID int)) *MockVerificationRepository_GetVerificationLinksByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockVerificationRepository_GetVerificationLinksByUser_Call) Return(_a0 []models.EmploymentVerificationLink, _a1 error) *MockVerificationRepository_GetVerificationLinksByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockVerificationRepository_GetVerificationLinksByUser_Call) RunAndReturn(run func(context.Context, int) ([]models.EmploymentVerificationLink, error)) *MockVerificationRepository_GetVerificationLinksByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordVerificationAccess provides a mock function with given fields: ctx, access
+func (_m *MockVerificationRepository) RecordVerificationAccess(ctx context.Context, access *models.EmploymentVerificationAccess) error {
+	ret := _m.Called(ctx, access)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordVerificationAccess")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.EmploymentVerificationAccess) error); ok {
+		r0 = rf(ctx, access)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockVerificationRepository_RecordVerificationAccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordVerificationAccess'
+type MockVerificationRepository_RecordVerificationAccess_Call struct {
+	*mock.Call
+}
+
+// RecordVerificationAccess is a helper method to define mock.On call
+//   - ctx context.Context
+//   - access *models.EmploymentVerificationAccess
+func (_e *MockVerificationRepository_Expecter) RecordVerificationAccess(ctx interface{}, access interface{}) *MockVerificationRepository_RecordVerificationAccess_Call {
+	return &MockVerificationRepository_RecordVerificationAccess_Call{Call: _e.mock.On("RecordVerificationAccess", ctx, access)}
+}
+
+func (_c *MockVerificationRepository_RecordVerificationAccess_Call) Run(run func(ctx context.Context, access *models.EmploymentVerificationAccess)) *MockVerificationRepository_RecordVerificationAccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.EmploymentVerificationAccess))
+	})
+	return _c
+}
+
+func (_c *MockVerificationRepository_RecordVerificationAccess_Call) Return(_a0 error) *MockVerificationRepository_RecordVerificationAccess_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockVerificationRepository_RecordVerificationAccess_Call) RunAndReturn(run func(context.Context, *models.EmploymentVerificationAccess) error) *MockVerificationRepository_RecordVerificationAccess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeVerificationLink provides a mock function with given fields: ctx, id
+func (_m *MockVerificationRepository) RevokeVerificationLink(ctx context.Context, id int) (*models.EmploymentVerificationLink, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeVerificationLink")
+	}
+
+	var r0 *models.EmploymentVerificationLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.EmploymentVerificationLink, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.EmploymentVerificationLink); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmploymentVerificationLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockVerificationRepository_RevokeVerificationLink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeVerificationLink'
+type MockVerificationRepository_RevokeVerificationLink_Call struct {
+	*mock.Call
+}
+
+// RevokeVerificationLink is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockVerificationRepository_Expecter) RevokeVerificationLink(ctx interface{}, id interface{}) *MockVerificationRepository_RevokeVerificationLink_Call {
+	return &MockVerificationRepository_RevokeVerificationLink_Call{Call: _e.mock.On("RevokeVerificationLink", ctx, id)}
+}
+
+func (_c *MockVerificationRepository_RevokeVerificationLink_Call) Run(run func(ctx context.Context, id int)) *MockVerificationRepository_RevokeVerificationLink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockVerificationRepository_RevokeVerificationLink_Call) Return(_a0 *models.EmploymentVerificationLink, _a1 error) *MockVerificationRepository_RevokeVerificationLink_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockVerificationRepository_RevokeVerificationLink_Call) RunAndReturn(run func(context.Context, int) (*models.EmploymentVerificationLink, error)) *MockVerificationRepository_RevokeVerificationLink_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockVerificationRepository creates a new instance of MockVerificationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockVerificationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockVerificationRepository {
+	mock := &MockVerificationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,107 @@
+// internal/repository/notification_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type notificationRepo struct {
+	db Conn // Primary: status baca harus langsung konsisten setelah mark-read
+}
+
+func NewNotificationRepository(pools Pools) NotificationRepository {
+	return &notificationRepo{db: pools.Primary}
+}
+
+// CreateNotification menambahkan notifikasi ke inbox n.UserID dan mengisi ID serta CreatedAt.
+func (r *notificationRepo) CreateNotification(ctx context.Context, n *models.Notification) (int, error) {
+	if n.Data == nil {
+		n.Data = map[string]string{}
+	}
+	query := `INSERT INTO notifications (user_id, type, title, body, data) VALUES ($1, $2, $3, $4, $5)
+              RETURNING id, created_at`
+	if err := r.db.QueryRow(ctx, query, n.UserID, n.Type, n.Title, n.Body, n.Data).Scan(&n.ID, &n.CreatedAt); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", n.UserID).Str("type", n.Type).Msg("Error creating notification")
+		return 0, fmt.Errorf("error creating notification for user %d: %w", n.UserID, err)
+	}
+	return n.ID, nil
+}
+
+// GetNotificationsByUser mengembalikan inbox user (terbaru dulu); unreadOnly hanya yang belum dibaca.
+func (r *notificationRepo) GetNotificationsByUser(ctx context.Context, userID int, unreadOnly bool, page, limit int) ([]models.Notification, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)`
+	if err := r.db.QueryRow(ctx, countQuery, userID, unreadOnly).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error counting notifications")
+		return nil, 0, fmt.Errorf("error counting notifications of user %d: %w", userID, err)
+	}
+	if total == 0 {
+		return []models.Notification{}, 0, nil
+	}
+
+	query := `SELECT ` + shared.SelectList("n", shared.NotificationColumns) + `
+              FROM notifications n
+              WHERE n.user_id = $1 AND (NOT $2 OR n.read_at IS NULL)
+              ORDER BY n.created_at DESC, n.id DESC
+              LIMIT $3 OFFSET $4`
+	rows, err := r.db.Query(ctx, query, userID, unreadOnly, limit, shared.PageOffset(page, limit))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error querying notifications")
+		return nil, 0, fmt.Errorf("error querying notifications of user %d: %w", userID, err)
+	}
+	defer rows.Close()
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		if err := shared.ScanNotification(rows, &n); err != nil {
+			return nil, 0, fmt.Errorf("error scanning notification row: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating notification rows: %w", err)
+	}
+	return notifications, total, nil
+}
+
+// CountUnreadNotifications mengembalikan jumlah notifikasi user yang belum dibaca.
+func (r *notificationRepo) CountUnreadNotifications(ctx context.Context, userID int) (int, error) {
+	var count int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting unread notifications of user %d: %w", userID, err)
+	}
+	return count, nil
+}
+
+// MarkNotificationRead menandai satu notifikasi milik user sebagai dibaca (idempoten: waktu
+// baca pertama dipertahankan). Mengembalikan pgx.ErrNoRows jika bukan milik user.
+func (r *notificationRepo) MarkNotificationRead(ctx context.Context, userID, id int) (*models.Notification, error) {
+	query := `UPDATE notifications n SET read_at = COALESCE(n.read_at, CURRENT_TIMESTAMP)
+              WHERE n.id = $1 AND n.user_id = $2
+              RETURNING ` + shared.SelectList("n", shared.NotificationColumns)
+	n := &models.Notification{}
+	if err := shared.ScanNotification(r.db.QueryRow(ctx, query, id, userID), n); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("notification_id", id).Msg("Error marking notification read")
+		return nil, fmt.Errorf("error marking notification %d read: %w", id, err)
+	}
+	return n, nil
+}
+
+// MarkAllNotificationsRead menandai semua notifikasi user yang belum dibaca dan mengembalikan jumlahnya.
+func (r *notificationRepo) MarkAllNotificationsRead(ctx context.Context, userID int) (int, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error marking all notifications read")
+		return 0, fmt.Errorf("error marking notifications of user %d read: %w", userID, err)
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,139 @@
+// internal/repository/org_chart.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Query garis pelaporan (users.manager_id) untuk userRepo.
+// Pohon ditelusuri dengan recursive CTE; kolom path mencegah loop seandainya data lama
+// mengandung siklus, walaupun SetUserManager menolak siklus baru.
+
+// ErrManagerCycle dikembalikan jika atasan yang dipilih adalah user itu sendiri atau bawahannya.
+var ErrManagerCycle = errors.New("manager assignment would create a reporting cycle")
+
+// orgChartLockKey adalah kunci advisory lock untuk menyerialkan perubahan manager_id,
+// sehingga dua perubahan bersamaan tidak bisa membentuk siklus.
+const orgChartLockKey = 4648
+
+// SetUserManager mengatur atasan langsung user (nil = tanpa atasan).
+// Mengembalikan pgx.ErrNoRows jika user atau manager tidak ada, ErrManagerCycle jika membentuk siklus.
+func (r *userRepo) SetUserManager(ctx context.Context, userID int, managerID *int) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("error starting manager update transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, orgChartLockKey); err != nil {
+		return fmt.Errorf("error locking org chart: %w", err)
+	}
+
+	if managerID != nil {
+		// Siklus terjadi jika userID ada di rantai atasan managerID (termasuk managerID == userID).
+		var cycle, managerExists bool
+		query := `WITH RECURSIVE chain AS (
+                      SELECT id, manager_id FROM users WHERE id = $2
+                      UNION
+                      SELECT u.id, u.manager_id FROM users u JOIN chain c ON u.id = c.manager_id
+                  )
+                  SELECT EXISTS (SELECT 1 FROM chain WHERE id = $1), EXISTS (SELECT 1 FROM chain WHERE id = $2)`
+		if err := tx.QueryRow(ctx, query, userID, *managerID).Scan(&cycle, &managerExists); err != nil {
+			return fmt.Errorf("error checking reporting chain: %w", err)
+		}
+		if !managerExists {
+			return pgx.ErrNoRows
+		}
+		if cycle {
+			return ErrManagerCycle
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE users SET manager_id = $1 WHERE id = $2`, managerID, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error updating user manager")
+		return fmt.Errorf("error updating user manager: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing manager update: %w", err)
+	}
+	shared.Logger(ctx).Info().Int("user_id", userID).Interface("manager_id", managerID).Msg("User manager updated")
+	return nil
+}
+
+// IsInReportingLine melaporkan apakah userID adalah bawahan langsung atau tidak langsung
+// managerID (rantai users.manager_id). User bukan bawahan dirinya sendiri.
+func (r *userRepo) IsInReportingLine(ctx context.Context, managerID, userID int) (bool, error) {
+	query := `WITH RECURSIVE chain AS (
+                  SELECT manager_id FROM users WHERE id = $2
+                  UNION
+                  SELECT u.manager_id FROM users u JOIN chain c ON u.id = c.manager_id
+              )
+              SELECT $1 <> $2 AND EXISTS (SELECT 1 FROM chain WHERE manager_id = $1)`
+	var ok bool
+	if err := r.db.QueryRow(ctx, query, managerID, userID).Scan(&ok); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("manager_id", managerID).Int("user_id", userID).Msg("Error checking reporting line")
+		return false, fmt.Errorf("error checking reporting line: %w", err)
+	}
+	return ok, nil
+}
+
+// GetReportingTree mengembalikan user aktif pada pohon pelaporan beserta status kehadiran
+// pada hari day (rentang [dayStart, dayStart+24h)). rootID nil = seluruh organisasi (akar adalah
+// user tanpa atasan); selain itu pohon di bawah rootID termasuk rootID sendiri.
+// Node dikembalikan datar, terurut depth lalu username.
+func (r *userRepo) GetReportingTree(ctx context.Context, rootID *int, dayStart time.Time) ([]models.OrgChartNode, error) {
+	query := `
+        WITH RECURSIVE tree AS (
+            SELECT u.id, u.manager_id, 0 AS depth, ARRAY[u.id] AS path
+            FROM users u
+            WHERE u.is_active AND (($1::int IS NULL AND u.manager_id IS NULL) OR u.id = $1::int)
+            UNION ALL
+            SELECT c.id, c.manager_id, t.depth + 1, t.path || c.id
+            FROM users c
+            JOIN tree t ON c.manager_id = t.id
+            WHERE c.is_active AND NOT c.id = ANY(t.path)
+        )
+        SELECT u.id, u.username, COALESCE(u.first_name, ''), COALESCE(u.last_name, ''), t.manager_id, t.depth,
+               CASE
+                   WHEN EXISTS (SELECT 1 FROM attendances a WHERE a.user_id = u.id AND a.check_out_at IS NULL) THEN $5
+                   WHEN EXISTS (SELECT 1 FROM attendances a WHERE a.user_id = u.id AND a.check_in_at >= $2 AND a.check_in_at < $3) THEN $6
+                   WHEN EXISTS (SELECT 1 FROM user_schedules us WHERE us.user_id = u.id AND us.date = $4::date) THEN $7
+                   ELSE $8
+               END AS presence
+        FROM tree t
+        JOIN users u ON u.id = t.id
+        ORDER BY t.depth ASC, u.username ASC`
+
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	rows, err := r.read.Query(ctx, query, rootID, dayStart, dayEnd, dayStart.Format(shared.DateLayout),
+		models.PresencePresent, models.PresenceCheckedOut, models.PresenceAbsent, models.PresenceOff)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying reporting tree")
+		return nil, fmt.Errorf("error getting reporting tree: %w", err)
+	}
+	defer rows.Close()
+
+	nodes := []models.OrgChartNode{}
+	for rows.Next() {
+		var n models.OrgChartNode
+		if err := rows.Scan(&n.UserID, &n.Username, &n.FirstName, &n.LastName, &n.ManagerID, &n.Depth, &n.Presence); err != nil {
+			return nil, fmt.Errorf("error scanning reporting tree row: %w", err)
+		}
+		nodes = append(nodes, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reporting tree rows: %w", err)
+	}
+	return nodes, nil
+}
@@ -0,0 +1,183 @@
+// internal/repository/outbox_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// ErrOutboxMessageNotDead dikembalikan saat retry diminta untuk pesan yang bukan dead letter.
+var ErrOutboxMessageNotDead = errors.New("outbox message is not in the dead letter queue")
+
+type outboxRepo struct {
+	db Conn // Primary: outbox ditulis dalam transaksi domain & dikunci dispatcher
+}
+
+func NewOutboxRepository(pools Pools) OutboxRepository {
+	return &outboxRepo{db: pools.Primary}
+}
+
+// EnqueueOutboxMessages menyimpan pesan outbox. Di dalam TxManager.RunInTx pesan ikut
+// transaksi perubahan domain; gagal menyimpan berarti seluruh transaksi gagal.
+func (r *outboxRepo) EnqueueOutboxMessages(ctx context.Context, msgs []models.OutboxMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("error starting outbox transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	query := `INSERT INTO outbox_messages (destination, event_name, user_id, payload) VALUES ($1, $2, $3, $4)`
+	for _, msg := range msgs {
+		if _, err := tx.Exec(ctx, query, msg.Destination, msg.EventName, msg.UserID, msg.Payload); err != nil {
+			shared.Logger(ctx).Error().Err(err).Str("destination", msg.Destination).Str("event", msg.EventName).Msg("Error enqueueing outbox message")
+			return fmt.Errorf("error enqueueing outbox message %s for %s: %w", msg.EventName, msg.Destination, err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing outbox messages: %w", err)
+	}
+	return nil
+}
+
+// ProcessDueOutboxMessages mengunci hingga limit pesan yang jatuh tempo (urut id) dengan
+// FOR UPDATE SKIP LOCKED, sehingga beberapa instance dispatcher tidak mengirim pesan yang
+// sama, lalu memanggil deliver per pesan. Pesan yang berhasil ditandai delivered. Yang gagal
+// dijadwalkan ulang setelah backoff(attempts), atau menjadi dead letter jika percobaannya
+// mencapai maxAttempts. Mengembalikan jumlah pesan yang terkirim dan yang gagal.
+func (r *outboxRepo) ProcessDueOutboxMessages(ctx context.Context, limit, maxAttempts int, backoff func(attempts int) time.Duration, deliver func(ctx context.Context, msg models.OutboxMessage) error) (int, int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error starting outbox transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	query := `SELECT ` + shared.SelectList("o", shared.OutboxMessageColumns) + `
+              FROM outbox_messages o
+              WHERE o.delivered_at IS NULL AND o.dead_at IS NULL AND o.available_at <= CURRENT_TIMESTAMP
+              ORDER BY o.id
+              LIMIT $1
+              FOR UPDATE SKIP LOCKED`
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying due outbox messages")
+		return 0, 0, fmt.Errorf("error querying due outbox messages: %w", err)
+	}
+	due, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.OutboxMessage, error) {
+		var msg models.OutboxMessage
+		err := shared.ScanOutboxMessage(row, &msg)
+		return msg, err
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("error scanning outbox message rows: %w", err)
+	}
+
+	delivered, failed := 0, 0
+	for _, msg := range due {
+		deliverErr := deliver(ctx, msg)
+		if deliverErr == nil {
+			if _, err := tx.Exec(ctx, `UPDATE outbox_messages SET attempts = attempts + 1, last_error = NULL, delivered_at = CURRENT_TIMESTAMP WHERE id = $1`, msg.ID); err != nil {
+				return 0, 0, fmt.Errorf("error marking outbox message %d delivered: %w", msg.ID, err)
+			}
+			delivered++
+			continue
+		}
+
+		failed++
+		reason := deliverErr.Error()
+		if len(reason) > shared.MaxOutboxErrorLength {
+			reason = reason[:shared.MaxOutboxErrorLength]
+		}
+		attempts := msg.Attempts + 1
+		logger := shared.Logger(ctx).Warn().Err(deliverErr).Int64("outbox_id", msg.ID).Str("destination", msg.Destination).Str("event", msg.EventName).Int("attempts", attempts)
+		if attempts >= maxAttempts {
+			_, err = tx.Exec(ctx, `UPDATE outbox_messages SET attempts = $2, last_error = $3, dead_at = CURRENT_TIMESTAMP WHERE id = $1`, msg.ID, attempts, reason)
+			logger.Msg("Outbox message moved to dead letter")
+		} else {
+			_, err = tx.Exec(ctx, `UPDATE outbox_messages SET attempts = $2, last_error = $3, available_at = CURRENT_TIMESTAMP + $4::interval WHERE id = $1`,
+				msg.ID, attempts, reason, backoff(attempts))
+			logger.Msg("Outbox message delivery failed; will retry")
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("error recording outbox message %d failure: %w", msg.ID, err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, fmt.Errorf("error committing outbox transaction: %w", err)
+	}
+	return delivered, failed, nil
+}
+
+// GetDeadOutboxMessages mengembalikan dead letter, terbaru dulu (paginated).
+func (r *outboxRepo) GetDeadOutboxMessages(ctx context.Context, page, limit int) ([]models.OutboxMessage, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM outbox_messages WHERE dead_at IS NOT NULL`).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting dead outbox messages")
+		return nil, 0, fmt.Errorf("error counting dead outbox messages: %w", err)
+	}
+	if total == 0 {
+		return []models.OutboxMessage{}, 0, nil
+	}
+	query := `SELECT ` + shared.SelectList("o", shared.OutboxMessageColumns) + `
+              FROM outbox_messages o
+              WHERE o.dead_at IS NOT NULL
+              ORDER BY o.dead_at DESC, o.id DESC
+              LIMIT $1 OFFSET $2`
+	rows, err := r.db.Query(ctx, query, limit, shared.PageOffset(page, limit))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying dead outbox messages")
+		return nil, 0, fmt.Errorf("error querying dead outbox messages: %w", err)
+	}
+	msgs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.OutboxMessage, error) {
+		var msg models.OutboxMessage
+		err := shared.ScanOutboxMessage(row, &msg)
+		return msg, err
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("error scanning dead outbox message rows: %w", err)
+	}
+	return msgs, total, nil
+}
+
+// RetryOutboxMessage mengembalikan dead letter ke antrean dengan percobaan direset.
+// Mengembalikan pgx.ErrNoRows jika pesan tidak ada, atau ErrOutboxMessageNotDead.
+func (r *outboxRepo) RetryOutboxMessage(ctx context.Context, id int64) (*models.OutboxMessage, error) {
+	query := `UPDATE outbox_messages o SET attempts = 0, dead_at = NULL, available_at = CURRENT_TIMESTAMP
+              WHERE o.id = $1 AND o.dead_at IS NOT NULL
+              RETURNING ` + shared.SelectList("o", shared.OutboxMessageColumns)
+	msg := &models.OutboxMessage{}
+	err := shared.ScanOutboxMessage(r.db.QueryRow(ctx, query, id), msg)
+	if err == nil {
+		return msg, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		shared.Logger(ctx).Error().Err(err).Int64("outbox_id", id).Msg("Error retrying outbox message")
+		return nil, fmt.Errorf("error retrying outbox message %d: %w", id, err)
+	}
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM outbox_messages WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking outbox message %d: %w", id, err)
+	}
+	if exists {
+		return nil, ErrOutboxMessageNotDead
+	}
+	return nil, pgx.ErrNoRows
+}
+
+// DeleteDeliveredOutboxMessages menghapus pesan terkirim sebelum cutoff (housekeeping).
+func (r *outboxRepo) DeleteDeliveredOutboxMessages(ctx context.Context, before time.Time) (int, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM outbox_messages WHERE delivered_at IS NOT NULL AND delivered_at < $1`, before)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error deleting delivered outbox messages")
+		return 0, fmt.Errorf("error deleting delivered outbox messages: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
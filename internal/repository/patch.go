@@ -0,0 +1,8 @@
+// internal/repository/patch.go
+package repository
+
+import "errors"
+
+// ErrNoFieldsToUpdate dikembalikan repository Patch* jika input tidak berisi field apa pun.
+// Klausa SET dinamisnya disusun dengan shared.SetBuilder.
+var ErrNoFieldsToUpdate = errors.New("no fields to update")
@@ -0,0 +1,187 @@
+// internal/repository/payroll_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Periode payroll dan kunci edit absensi. Absensi masuk ke periode berdasarkan tanggal
+// check-in (zona waktu server, sama seperti pencarian jadwal saat check-in). Perubahan absensi
+// mengambil FOR SHARE pada periode yang mencakup tanggalnya, sedangkan close/reopen mengambil
+// row lock, sehingga penutupan periode menunggu perubahan absensi yang sedang berjalan.
+
+// ErrPayrollPeriodOverlap dikembalikan jika periode baru tumpang tindih dengan periode lain.
+var ErrPayrollPeriodOverlap = errors.New("payroll period overlaps an existing period")
+
+// ErrPayrollPeriodStatus dikembalikan jika periode sudah berstatus tujuan (close periode yang
+// sudah closed, atau reopen periode yang masih open).
+var ErrPayrollPeriodStatus = errors.New("payroll period is already in the requested status")
+
+// PayrollPeriodClosedError dikembalikan oleh mutasi absensi yang tanggalnya berada
+// di periode payroll yang sudah ditutup.
+type PayrollPeriodClosedError struct {
+	Period models.PayrollPeriod
+}
+
+func (e *PayrollPeriodClosedError) Error() string {
+	return fmt.Sprintf("payroll period %d (%s to %s) is closed", e.Period.ID, e.Period.StartDate, e.Period.EndDate)
+}
+
+type payrollRepo struct {
+	db   Conn // Primary: tulis & baca konsisten
+	read Conn // Replica (atau Primary jika tidak ada) untuk listing
+}
+
+func NewPayrollRepository(pools Pools) PayrollRepository {
+	return &payrollRepo{db: pools.Primary, read: pools.reader()}
+}
+
+// CreatePayrollPeriod membuat periode berstatus open. Mengembalikan ErrPayrollPeriodOverlap
+// jika rentang tanggalnya tumpang tindih dengan periode lain.
+func (r *payrollRepo) CreatePayrollPeriod(ctx context.Context, period *models.PayrollPeriod) (int, error) {
+	query := `INSERT INTO payroll_periods (start_date, end_date, created_by) VALUES ($1::date, $2::date, $3) RETURNING id`
+	var id int
+	if err := r.db.QueryRow(ctx, query, period.StartDate, period.EndDate, period.CreatedBy).Scan(&id); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.ConstraintName == shared.PayrollOverlapConstraint {
+			return 0, ErrPayrollPeriodOverlap
+		}
+		shared.Logger(ctx).Error().Err(err).Str("start_date", period.StartDate).Str("end_date", period.EndDate).Msg("Error creating payroll period")
+		return 0, fmt.Errorf("error creating payroll period: %w", err)
+	}
+	return id, nil
+}
+
+func (r *payrollRepo) GetPayrollPeriodByID(ctx context.Context, id int) (*models.PayrollPeriod, error) {
+	query := `SELECT ` + shared.SelectList("pp", payrollPeriodColumns) + ` FROM payroll_periods pp WHERE pp.id = $1`
+	p := &models.PayrollPeriod{}
+	if err := shared.ScanPayrollPeriod(r.db.QueryRow(ctx, query, id), p); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("payroll_period_id", id).Msg("Error getting payroll period by ID")
+		return nil, fmt.Errorf("error getting payroll period by id %d: %w", id, err)
+	}
+	return p, nil
+}
+
+// GetAllPayrollPeriods mengembalikan periode payroll, terbaru dulu.
+func (r *payrollRepo) GetAllPayrollPeriods(ctx context.Context, page, limit int) ([]models.PayrollPeriod, int, error) {
+	var total int
+	if err := r.read.QueryRow(ctx, `SELECT COUNT(*) FROM payroll_periods`).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting payroll periods")
+		return nil, 0, fmt.Errorf("error counting payroll periods: %w", err)
+	}
+	periods := []models.PayrollPeriod{}
+	if total == 0 {
+		return periods, 0, nil
+	}
+
+	query := `SELECT ` + shared.SelectList("pp", payrollPeriodColumns) + `
+              FROM payroll_periods pp
+              ORDER BY pp.start_date DESC
+              LIMIT $1 OFFSET $2`
+	rows, err := r.read.Query(ctx, query, limit, shared.PageOffset(page, limit))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying payroll periods")
+		return nil, 0, fmt.Errorf("error querying payroll periods: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p models.PayrollPeriod
+		if err := shared.ScanPayrollPeriod(rows, &p); err != nil {
+			return nil, 0, fmt.Errorf("error scanning payroll period row: %w", err)
+		}
+		periods = append(periods, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating payroll period rows: %w", err)
+	}
+	return periods, total, nil
+}
+
+// ClosePayrollPeriod menutup periode. Mengembalikan pgx.ErrNoRows jika periode tidak ada,
+// atau ErrPayrollPeriodStatus jika sudah closed.
+func (r *payrollRepo) ClosePayrollPeriod(ctx context.Context, id int, actorUserID int) (*models.PayrollPeriod, error) {
+	query := `UPDATE payroll_periods pp SET status = $2, closed_at = CURRENT_TIMESTAMP, closed_by = $3
+              WHERE pp.id = $1 AND pp.status = $4
+              RETURNING ` + shared.SelectList("pp", payrollPeriodColumns)
+	return r.transition(ctx, id, query, models.PayrollPeriodClosed, actorUserID, models.PayrollPeriodOpen)
+}
+
+// ReopenPayrollPeriod membuka kembali periode yang sudah ditutup dan mencatat pelaku & alasannya.
+// Mengembalikan pgx.ErrNoRows jika periode tidak ada, atau ErrPayrollPeriodStatus jika masih open.
+func (r *payrollRepo) ReopenPayrollPeriod(ctx context.Context, id int, actorUserID int, reason string) (*models.PayrollPeriod, error) {
+	query := `UPDATE payroll_periods pp SET status = $2, reopened_at = CURRENT_TIMESTAMP, reopened_by = $3, reopen_reason = $5
+              WHERE pp.id = $1 AND pp.status = $4
+              RETURNING ` + shared.SelectList("pp", payrollPeriodColumns)
+	return r.transition(ctx, id, query, models.PayrollPeriodOpen, actorUserID, models.PayrollPeriodClosed, reason)
+}
+
+// transition menjalankan UPDATE status bersyarat dan membedakan periode tidak ada dari status yang tidak cocok.
+func (r *payrollRepo) transition(ctx context.Context, id int, query string, args ...any) (*models.PayrollPeriod, error) {
+	p := &models.PayrollPeriod{}
+	err := shared.ScanPayrollPeriod(r.db.QueryRow(ctx, query, append([]any{id}, args...)...), p)
+	if err == nil {
+		shared.Logger(ctx).Info().Int("payroll_period_id", id).Str("status", p.Status).Msg("Payroll period status changed")
+		return p, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		shared.Logger(ctx).Error().Err(err).Int("payroll_period_id", id).Msg("Error changing payroll period status")
+		return nil, fmt.Errorf("error changing status of payroll period %d: %w", id, err)
+	}
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM payroll_periods WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking payroll period id %d: %w", id, err)
+	}
+	if !exists {
+		return nil, pgx.ErrNoRows
+	}
+	return nil, ErrPayrollPeriodStatus
+}
+
+// checkPayrollPeriodsOpen mengembalikan *PayrollPeriodClosedError jika salah satu waktu
+// (berdasarkan tanggalnya) berada di periode payroll yang sudah ditutup. Periode yang
+// mencakup tanggal tersebut dikunci FOR SHARE sampai transaksi selesai.
+func checkPayrollPeriodsOpen(ctx context.Context, tx pgx.Tx, times ...time.Time) error {
+	dates := make([]string, len(times))
+	for i, t := range times {
+		dates[i] = t.Format(shared.DateLayout)
+	}
+	query := `SELECT ` + shared.SelectList("pp", payrollPeriodColumns) + `
+              FROM payroll_periods pp
+              WHERE EXISTS (SELECT 1 FROM unnest($1::text[]) d WHERE d::date BETWEEN pp.start_date AND pp.end_date)
+              ORDER BY pp.start_date
+              FOR SHARE`
+	rows, err := tx.Query(ctx, query, dates)
+	if err != nil {
+		return fmt.Errorf("error checking payroll periods: %w", err)
+	}
+	defer rows.Close()
+
+	var closed *models.PayrollPeriod
+	for rows.Next() {
+		var p models.PayrollPeriod
+		if err := shared.ScanPayrollPeriod(rows, &p); err != nil {
+			return fmt.Errorf("error scanning payroll period row: %w", err)
+		}
+		if closed == nil && p.Status == models.PayrollPeriodClosed {
+			closed = &p
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating payroll period rows: %w", err)
+	}
+	if closed != nil {
+		shared.Logger(ctx).Warn().Int("payroll_period_id", closed.ID).Strs("dates", dates).Msg("Attendance change rejected: payroll period closed")
+		return &PayrollPeriodClosedError{Period: *closed}
+	}
+	return nil
+}
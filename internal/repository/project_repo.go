@@ -0,0 +1,140 @@
+// internal/repository/project_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// ErrProjectCodeTaken dikembalikan jika kode project sudah dipakai project lain.
+var ErrProjectCodeTaken = errors.New("project code already exists")
+
+// ErrProjectInUse dikembalikan DeleteProject jika project sudah dipakai absensi.
+var ErrProjectInUse = errors.New("project is referenced by attendance records; deactivate it instead")
+
+// ErrProjectUnavailable dikembalikan saat check-in dengan project yang tidak ada atau nonaktif.
+var ErrProjectUnavailable = errors.New("project not found or inactive")
+
+type projectRepo struct {
+	db   Conn // Primary: tulis & baca konsisten
+	read Conn // Replica (atau Primary jika tidak ada) untuk listing
+}
+
+func NewProjectRepository(pools Pools) ProjectRepository {
+	return &projectRepo{db: pools.Primary, read: pools.reader()}
+}
+
+// projectWriteError menerjemahkan unique violation kode project ke ErrProjectCodeTaken.
+func projectWriteError(err error) error {
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+		return ErrProjectCodeTaken
+	}
+	return nil
+}
+
+func (r *projectRepo) CreateProject(ctx context.Context, p *models.Project) (int, error) {
+	query := `INSERT INTO projects (code, name, cost_center, is_active) VALUES ($1, $2, $3, $4) RETURNING id`
+	var id int
+	if err := r.db.QueryRow(ctx, query, p.Code, p.Name, p.CostCenter, p.IsActive).Scan(&id); err != nil {
+		if mapped := projectWriteError(err); mapped != nil {
+			return 0, mapped
+		}
+		shared.Logger(ctx).Error().Err(err).Str("code", p.Code).Msg("Error creating project")
+		return 0, fmt.Errorf("error creating project: %w", err)
+	}
+	return id, nil
+}
+
+func (r *projectRepo) GetProjectByID(ctx context.Context, id int) (*models.Project, error) {
+	query := `SELECT ` + shared.SelectList("p", shared.ProjectColumns) + ` FROM projects p WHERE p.id = $1`
+	p := &models.Project{}
+	if err := shared.ScanProject(r.db.QueryRow(ctx, query, id), p); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("project_id", id).Msg("Error getting project by ID")
+		return nil, fmt.Errorf("error getting project by id %d: %w", id, err)
+	}
+	return p, nil
+}
+
+// GetAllProjects mengembalikan project urut kode; activeOnly untuk daftar pilihan check-in.
+func (r *projectRepo) GetAllProjects(ctx context.Context, activeOnly bool) ([]models.Project, error) {
+	query := `SELECT ` + shared.SelectList("p", shared.ProjectColumns) + `
+              FROM projects p
+              WHERE p.is_active OR NOT $1
+              ORDER BY p.code`
+	rows, err := r.read.Query(ctx, query, activeOnly)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying projects")
+		return nil, fmt.Errorf("error querying projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []models.Project{}
+	for rows.Next() {
+		var p models.Project
+		if err := shared.ScanProject(rows, &p); err != nil {
+			return nil, fmt.Errorf("error scanning project row: %w", err)
+		}
+		projects = append(projects, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating project rows: %w", err)
+	}
+	return projects, nil
+}
+
+// UpdateProject mengganti data project by ID. Mengembalikan pgx.ErrNoRows jika tidak ada.
+func (r *projectRepo) UpdateProject(ctx context.Context, p *models.Project) error {
+	query := `UPDATE projects SET code = $1, name = $2, cost_center = $3, is_active = $4 WHERE id = $5`
+	tag, err := r.db.Exec(ctx, query, p.Code, p.Name, p.CostCenter, p.IsActive, p.ID)
+	if err != nil {
+		if mapped := projectWriteError(err); mapped != nil {
+			return mapped
+		}
+		shared.Logger(ctx).Error().Err(err).Int("project_id", p.ID).Msg("Error updating project")
+		return fmt.Errorf("error updating project id %d: %w", p.ID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteProject menghapus project yang belum pernah dipakai absensi.
+func (r *projectRepo) DeleteProject(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM projects WHERE id = $1`, id)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			shared.Logger(ctx).Warn().Int("project_id", id).Msg("Cannot delete project: it is still referenced by attendance records")
+			return ErrProjectInUse
+		}
+		shared.Logger(ctx).Error().Err(err).Int("project_id", id).Msg("Error deleting project")
+		return fmt.Errorf("error deleting project id %d: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// checkProjectActive memastikan project ada dan aktif. Baris project dikunci FOR SHARE agar
+// tidak dinonaktifkan/dihapus sebelum transaksi check-in selesai.
+func checkProjectActive(ctx context.Context, tx pgx.Tx, projectID int) error {
+	var active bool
+	err := tx.QueryRow(ctx, `SELECT is_active FROM projects WHERE id = $1 FOR SHARE`, projectID).Scan(&active)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !active) {
+		return ErrProjectUnavailable
+	}
+	if err != nil {
+		return fmt.Errorf("error checking project id %d: %w", projectID, err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// internal/repository/report_export_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type reportExportRepo struct {
+	db Conn // Primary: status dibaca ulang tepat setelah job memperbaruinya
+}
+
+func NewReportExportRepository(pools Pools) ReportExportRepository {
+	return &reportExportRepo{db: pools.Primary}
+}
+
+// CreateReportExport menyimpan permintaan ekspor berstatus pending dan mengisi ID & created_at.
+func (r *reportExportRepo) CreateReportExport(ctx context.Context, report *models.ReportExport) error {
+	query := `INSERT INTO report_exports AS re (report_type, format, params, status, requested_by, retention_seconds)
+              VALUES ($1, $2, $3, $4, $5, $6)
+              RETURNING ` + shared.SelectList("re", shared.ReportExportColumns)
+	params := report.Params
+	if len(params) == 0 {
+		params = []byte("{}")
+	}
+	err := shared.ScanReportExport(r.db.QueryRow(ctx, query, report.ReportType, report.Format, params, models.ReportExportPending,
+		report.RequestedBy, report.RetentionSeconds), report)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("report_type", report.ReportType).Msg("Error creating report export")
+		return fmt.Errorf("error creating %s report export: %w", report.ReportType, err)
+	}
+	return nil
+}
+
+// GetReportExportByID mengembalikan satu ekspor, atau pgx.ErrNoRows.
+func (r *reportExportRepo) GetReportExportByID(ctx context.Context, id int) (*models.ReportExport, error) {
+	query := `SELECT ` + shared.SelectList("re", shared.ReportExportColumns) + ` FROM report_exports re WHERE re.id = $1`
+	report := &models.ReportExport{}
+	if err := shared.ScanReportExport(r.db.QueryRow(ctx, query, id), report); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("report_export_id", id).Msg("Error getting report export")
+		return nil, fmt.Errorf("error getting report export %d: %w", id, err)
+	}
+	return report, nil
+}
+
+// GetReportExports mengembalikan semua ekspor, terbaru dulu.
+func (r *reportExportRepo) GetReportExports(ctx context.Context, page, limit int) ([]models.ReportExport, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM report_exports`).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting report exports")
+		return nil, 0, fmt.Errorf("error counting report exports: %w", err)
+	}
+	if total == 0 {
+		return []models.ReportExport{}, 0, nil
+	}
+	query := `SELECT ` + shared.SelectList("re", shared.ReportExportColumns) + `
+              FROM report_exports re
+              ORDER BY re.created_at DESC, re.id DESC
+              LIMIT $1 OFFSET $2`
+	reports, err := r.list(ctx, "paged", query, limit, shared.PageOffset(page, limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+// GetPendingReportExports mengembalikan maksimal limit ekspor pending, terlama dulu.
+func (r *reportExportRepo) GetPendingReportExports(ctx context.Context, limit int) ([]models.ReportExport, error) {
+	query := `SELECT ` + shared.SelectList("re", shared.ReportExportColumns) + `
+              FROM report_exports re
+              WHERE re.status = $1
+              ORDER BY re.created_at, re.id
+              LIMIT $2`
+	return r.list(ctx, "pending", query, models.ReportExportPending, limit)
+}
+
+// GetExpiredReportExports mengembalikan maksimal limit ekspor completed yang expires_at-nya
+// sebelum before, terlama dulu.
+func (r *reportExportRepo) GetExpiredReportExports(ctx context.Context, before time.Time, limit int) ([]models.ReportExport, error) {
+	query := `SELECT ` + shared.SelectList("re", shared.ReportExportColumns) + `
+              FROM report_exports re
+              WHERE re.status = $1 AND re.expires_at < $2
+              ORDER BY re.expires_at, re.id
+              LIMIT $3`
+	return r.list(ctx, "expired", query, models.ReportExportCompleted, before, limit)
+}
+
+func (r *reportExportRepo) list(ctx context.Context, kind, query string, args ...any) ([]models.ReportExport, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("kind", kind).Msg("Error querying report exports")
+		return nil, fmt.Errorf("error getting %s report exports: %w", kind, err)
+	}
+	defer rows.Close()
+	reports := []models.ReportExport{}
+	for rows.Next() {
+		var re models.ReportExport
+		if err := shared.ScanReportExport(rows, &re); err != nil {
+			return nil, fmt.Errorf("error scanning report export row: %w", err)
+		}
+		reports = append(reports, re)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating report export rows: %w", err)
+	}
+	return reports, nil
+}
+
+// MarkReportExportCompleted mencatat file hasil ekspor; expires_at dihitung dari retensi yang
+// diminta. pgx.ErrNoRows jika ekspor tidak lagi pending.
+func (r *reportExportRepo) MarkReportExportCompleted(ctx context.Context, id int, result models.ReportExportResult) (*models.ReportExport, error) {
+	query := `UPDATE report_exports AS re
+              SET status = $2, storage_key = $3, file_name = $4, content_type = $5, size_bytes = $6, row_count = $7,
+                  error = NULL, completed_at = CURRENT_TIMESTAMP,
+                  expires_at = CURRENT_TIMESTAMP + make_interval(secs => re.retention_seconds)
+              WHERE re.id = $1 AND re.status = $8
+              RETURNING ` + shared.SelectList("re", shared.ReportExportColumns)
+	report := &models.ReportExport{}
+	err := shared.ScanReportExport(r.db.QueryRow(ctx, query, id, models.ReportExportCompleted, result.StorageKey, result.FileName,
+		result.ContentType, result.SizeBytes, result.RowCount, models.ReportExportPending), report)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("report_export_id", id).Msg("Error completing report export")
+		return nil, fmt.Errorf("error marking report export %d completed: %w", id, err)
+	}
+	return report, nil
+}
+
+// MarkReportExportFailed mencatat kegagalan pembuatan ekspor pending.
+func (r *reportExportRepo) MarkReportExportFailed(ctx context.Context, id int, reason string) error {
+	query := `UPDATE report_exports
+              SET status = $2, error = $3, completed_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND status = $4`
+	return r.update(ctx, id, "failed", query, id, models.ReportExportFailed, reason, models.ReportExportPending)
+}
+
+// MarkReportExportPurged mencatat bahwa file ekspor sudah dihapus dan mengosongkan storage_key.
+func (r *reportExportRepo) MarkReportExportPurged(ctx context.Context, id int) error {
+	query := `UPDATE report_exports
+              SET status = $2, storage_key = NULL, purged_at = CURRENT_TIMESTAMP
+              WHERE id = $1`
+	return r.update(ctx, id, "purged", query, id, models.ReportExportPurged)
+}
+
+func (r *reportExportRepo) update(ctx context.Context, id int, status, query string, args ...any) error {
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("report_export_id", id).Str("status", status).Msg("Error updating report export")
+		return fmt.Errorf("error marking report export %d %s: %w", id, status, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// internal/repository/repositories.go
+package repository
+
+import "github.com/rakaarfi/attendance-system-be/internal/pii"
+
+// Repositories mengelompokkan semua repository aplikasi agar implementasinya bisa diganti
+// sekaligus (PostgreSQL lewat NewRepositories, atau store memori untuk SANDBOX_MODE).
+type Repositories struct {
+	Users            UserRepository
+	Roles            RoleRepository
+	Shifts           ShiftRepository
+	Schedules        ScheduleRepository
+	Attendance       AttendanceRepository
+	Settings         SettingsRepository
+	Announcements    AnnouncementRepository
+	Documents        DocumentRepository
+	Audit            AuditRepository
+	Payroll          PayrollRepository
+	Projects         ProjectRepository
+	SignOffs         SignOffRepository
+	Disputes         DisputeRepository
+	Devices          DeviceRepository
+	Delegations      DelegationRepository
+	Escalations      EscalationRepository
+	Jobs             JobRepository
+	Labor            LaborRepository
+	Notifications    NotificationRepository
+	Outbox           OutboxRepository
+	Verification     VerificationRepository
+	Kiosks           KioskRepository
+	AttendancePhotos AttendancePhotoRepository
+	ReportExports    ReportExportRepository
+	DebugCaptures    DebugCaptureRepository
+	Backups          BackupRepository
+	AppInstances     AppInstanceRepository
+	Tx               TxManager
+}
+
+// NewRepositories membuat semua repository PostgreSQL di atas pools. Repository yang menyentuh
+// data pribadi user menerima protector untuk enkripsi kolom PII.
+func NewRepositories(pools Pools, protector *pii.Protector) Repositories {
+	return Repositories{
+		Users:            NewUserRepository(pools, protector),
+		Roles:            NewRoleRepository(pools),
+		Shifts:           NewShiftRepository(pools),
+		Schedules:        NewScheduleRepository(pools, protector),
+		Attendance:       NewAttendanceRepository(pools, protector),
+		Settings:         NewSettingsRepository(pools),
+		Announcements:    NewAnnouncementRepository(pools),
+		Documents:        NewDocumentRepository(pools),
+		Audit:            NewAuditRepository(pools),
+		Payroll:          NewPayrollRepository(pools),
+		Projects:         NewProjectRepository(pools),
+		SignOffs:         NewSignOffRepository(pools),
+		Disputes:         NewDisputeRepository(pools),
+		Devices:          NewDeviceRepository(pools),
+		Delegations:      NewDelegationRepository(pools),
+		Escalations:      NewEscalationRepository(pools),
+		Jobs:             NewJobRepository(pools),
+		Labor:            NewLaborRepository(pools),
+		Notifications:    NewNotificationRepository(pools),
+		Outbox:           NewOutboxRepository(pools),
+		Verification:     NewVerificationRepository(pools),
+		Kiosks:           NewKioskRepository(pools),
+		AttendancePhotos: NewAttendancePhotoRepository(pools),
+		ReportExports:    NewReportExportRepository(pools),
+		DebugCaptures:    NewDebugCaptureRepository(pools),
+		Backups:          NewBackupRepository(pools),
+		AppInstances:     NewAppInstanceRepository(pools),
+		Tx:               NewTxManager(pools),
+	}
+}
+
+// NewRepositoriesWithConn membuat semua repository dengan query PostgreSQL di atas satu koneksi db
+// untuk tulis dan baca. Dipakai driver lain yang meniru API pgx dan hanya menimpa method yang
+// dialek SQL-nya berbeda (lihat internal/repository/sqlite).
+func NewRepositoriesWithConn(db Conn, protector *pii.Protector) Repositories {
+	return Repositories{
+		Users:            &userRepo{db: db, read: db, pii: protector},
+		Roles:            &roleRepo{db: db, read: db},
+		Shifts:           &shiftRepo{db: db, read: db},
+		Schedules:        &scheduleRepo{db: db, read: db, pii: protector},
+		Attendance:       &attendanceRepo{db: db, read: db, pii: protector},
+		Settings:         &settingsRepo{db: db},
+		Announcements:    &announcementRepo{db: db, read: db},
+		Documents:        &documentRepo{db: db},
+		Audit:            &auditRepo{db: db, read: db},
+		Payroll:          &payrollRepo{db: db, read: db},
+		Projects:         &projectRepo{db: db, read: db},
+		SignOffs:         &signOffRepo{db: db, read: db},
+		Disputes:         &disputeRepo{db: db, read: db},
+		Devices:          &deviceRepo{db: db, read: db},
+		Delegations:      &delegationRepo{db: db},
+		Escalations:      &escalationRepo{db: db, read: db},
+		Jobs:             &jobRepo{db: db},
+		Labor:            &laborRepo{db: db, read: db},
+		Notifications:    &notificationRepo{db: db},
+		Outbox:           &outboxRepo{db: db},
+		Verification:     &verificationRepo{db: db},
+		Kiosks:           &kioskRepo{db: db},
+		AttendancePhotos: &attendancePhotoRepo{db: db},
+		ReportExports:    &reportExportRepo{db: db},
+		DebugCaptures:    &debugCaptureRepo{db: db},
+		Backups:          &backupRepo{db: db},
+		AppInstances:     &appInstanceRepo{db: db},
+		Tx:               &txManager{db: db},
+	}
+}
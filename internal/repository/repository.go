@@ -1,6 +1,10 @@
 // internal/repository/repository.go
 package repository
 
+// Mock di internal/repository/mocks digenerate mockery v2 dari interface di file ini dan tx.go
+// (lihat .mockery.yaml); output-nya di-commit.
+//go:generate sh -c "cd ../.. && mockery"
+
 import (
 	"context"
 	"time"
@@ -16,49 +20,327 @@ import (
 
 // UserRepository: Kontrak untuk operasi data User.
 type UserRepository interface {
-	CreateUser(ctx context.Context, user *models.RegisterUserInput, hashedPassword string) (int, error) // Buat user baru.
-	GetUserByUsername(ctx context.Context, username string) (*models.User, error)                       // Cari user by username (termasuk role).
-	GetUserByID(ctx context.Context, id int) (*models.User, error)                                      // Cari user by ID (termasuk role).
-	DeleteUserByID(ctx context.Context, id int) error                                                   // Hapus user by ID.
-	GetAllUsers(ctx context.Context, page, limit int) ([]models.User, int, error)                       // Dapatkan semua user (paginated, termasuk role).
-	UpdateUserByID(ctx context.Context, id int, input *models.AdminUpdateUserInput) error               // Update user by ID (oleh Admin).
-	UpdateUserPassword(ctx context.Context, id int, hashedPassword string) error                        // Update password user by ID (dengan hash).
-	UpdateUserProfile(ctx context.Context, id int, input *models.UpdateProfileInput) error              // Update profil user by ID (oleh user sendiri).
+	CreateUser(ctx context.Context, user *models.RegisterUserInput, hashedPassword string) (int, error)                                     // Buat user baru.
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)                                                           // Cari user by username (termasuk role).
+	GetUserByID(ctx context.Context, id int) (*models.User, error)                                                                          // Cari user by ID (termasuk role).
+	DeleteUserByID(ctx context.Context, id int) error                                                                                       // Hapus user by ID.
+	GetAllUsers(ctx context.Context, page, limit int) ([]models.User, int, error)                                                           // Dapatkan semua user (paginated, termasuk role).
+	UpdateUserByID(ctx context.Context, id int, input *models.AdminUpdateUserInput) error                                                   // Update user by ID (oleh Admin).
+	PatchUserByID(ctx context.Context, id int, input *models.AdminPatchUserInput) (int, error)                                              // Update parsial user by ID (oleh Admin), mengembalikan versi baru.
+	BulkUpdateUserRole(ctx context.Context, userIDs []int, roleID int) ([]models.BulkItemResult, error)                                     // Ganti role banyak user dalam satu transaksi (hasil per user).
+	UpdateUserPassword(ctx context.Context, id int, hashedPassword string) error                                                            // Update password user by ID (dengan hash).
+	UpdateUserProfile(ctx context.Context, id int, input *models.UpdateProfileInput) error                                                  // Update profil user by ID (oleh user sendiri).
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)                                                                 // Cari user by email (via blind index email_hash).
+	EncryptLegacyPII(ctx context.Context) (int, error)                                                                                      // Enkripsi ulang PII plaintext/kunci lama (backfill saat startup).
+	AnonymizeUser(ctx context.Context, id int) error                                                                                        // Pseudonimkan data pribadi user (irreversible).
+	UpdateUserAccess(ctx context.Context, id int, input *models.UpdateUserAccessInput, today time.Time) error                               // Ganti tipe user & masa akses (status aktif dihitung ulang).
+	DeactivateExpiredContractors(ctx context.Context, today time.Time) ([]int, error)                                                       // Nonaktifkan kontraktor yang masa aksesnya lewat.
+	UpdateUserEmployment(ctx context.Context, id int, input *models.UpdateEmploymentInput) error                                            // Update data kepegawaian (hire date, status, akhir probation).
+	GetPendingProbationReviews(ctx context.Context, endingBy time.Time) ([]models.User, error)                                              // User probation yang berakhir s.d. tanggal tertentu & belum dinotifikasi.
+	MarkProbationNotified(ctx context.Context, ids []int) error                                                                             // Tandai HR sudah dinotifikasi untuk probation user.
+	SetUserManager(ctx context.Context, userID int, managerID *int) error                                                                   // Atur atasan langsung (menolak siklus pelaporan).
+	IsInReportingLine(ctx context.Context, managerID, userID int) (bool, error)                                                             // Apakah userID bawahan langsung/tidak langsung managerID.
+	GetReportingTree(ctx context.Context, rootID *int, dayStart time.Time) ([]models.OrgChartNode, error)                                   // Pohon pelaporan (recursive CTE) + status kehadiran hari itu.
+	ExportUsers(ctx context.Context, filter models.UserExportFilter, fn func(*models.UserExportRow) error) error                            // Alirkan semua user terfilter (role & aktivitas terakhir) untuk ekspor.
+	GetTokenVersion(ctx context.Context, id int) (int, error)                                                                               // Versi token sesi user saat ini.
+	RevokeSessions(ctx context.Context, id int, expectedVersion int) (int, error)                                                           // Cabut semua sesi & wajibkan reset password (jika versi cocok).
+	ForceRevokeSessions(ctx context.Context, id int) (int, error)                                                                           // Cabut semua sesi tanpa syarat versi (oleh admin).
+	CreateEmailChangeRequest(ctx context.Context, userID int, newEmail string, expiresAt time.Time) (*models.EmailChangeRequest, error)     // Permintaan ganti email baru (menggantikan yang terbuka).
+	GetPendingEmailChange(ctx context.Context, userID int) (*models.EmailChangeRequest, error)                                              // Permintaan ganti email terbuka milik user.
+	GetEmailChangeByID(ctx context.Context, id int) (*models.EmailChangeRequest, error)                                                     // Satu permintaan ganti email.
+	RenewEmailChangeRequest(ctx context.Context, id int, expiresAt time.Time) error                                                         // Catat kirim ulang tautan & perpanjang masa berlaku.
+	CancelEmailChange(ctx context.Context, userID int) error                                                                                // Batalkan permintaan ganti email terbuka.
+	ConfirmEmailChange(ctx context.Context, id int) error                                                                                   // Terapkan email baru setelah dikonfirmasi.
+	CreateUsernameChangeRequest(ctx context.Context, userID int, newUsername string) (*models.UsernameChangeRequest, error)                 // Permintaan ganti username baru (menggantikan yang pending).
+	GetPendingUsernameChange(ctx context.Context, userID int) (*models.UsernameChangeRequest, error)                                        // Permintaan ganti username pending milik user.
+	GetUsernameChangeRequests(ctx context.Context, status string, page, limit int) ([]models.UsernameChangeRequest, int, error)             // Antrian persetujuan admin.
+	ReviewUsernameChangeRequest(ctx context.Context, id, reviewerID int, approve bool, note *string) (*models.UsernameChangeRequest, error) // Setujui (terapkan) atau tolak.
+	GetPreviousUsernames(ctx context.Context, userID int) ([]models.PreviousUsername, error)                                                // Riwayat username lama, terbaru dulu.
+	ResolveUsername(ctx context.Context, username string) (*models.UsernameResolution, error)                                               // Cari user dari username saat ini atau lama.
+	CreatePhoneOTP(ctx context.Context, otp *models.PhoneOTP, phone string) error                                                           // Simpan kode OTP SMS (menggantikan yang terbuka).
+	GetOpenPhoneOTP(ctx context.Context, userID int, purpose string) (*models.PhoneOTP, error)                                              // Kode OTP terbuka milik user.
+	RecordPhoneOTPAttempt(ctx context.Context, id int) (int, error)                                                                         // Catat percobaan kode yang salah.
+	ConsumePhoneOTP(ctx context.Context, id int) error                                                                                      // Pakai kode (verify_phone: tandai nomor terverifikasi).
+	UpdateSMSPreferences(ctx context.Context, userID int, input *models.UpdateSMSPreferencesInput) (*models.User, error)                    // 2FA & pengingat SMS.
+	CreateInvitation(ctx context.Context, email string, roleID, invitedBy int, expiresAt time.Time) (*models.Invitation, error)             // Undangan onboarding (menggantikan undangan terbuka email yang sama).
+	GetInvitationByID(ctx context.Context, id int) (*models.Invitation, error)                                                              // Satu undangan beserta nama role.
+	GetInvitations(ctx context.Context, status string, page, limit int) ([]models.Invitation, int, error)                                   // Daftar undangan, terbaru dulu.
+	RenewInvitation(ctx context.Context, id int, expiresAt time.Time) error                                                                 // Kirim ulang: perpanjang masa berlaku.
+	RevokeInvitation(ctx context.Context, id int) error                                                                                     // Cabut undangan yang belum diterima.
+	AcceptInvitation(ctx context.Context, id, userID int) error                                                                             // Tandai diterima (satu transaksi dengan CreateUser).
+	ResetPassword(ctx context.Context, id int, hashedPassword string, expectedVersion int) error                                            // Ganti password via token reset (jika versi cocok).
+	RequirePasswordChange(ctx context.Context, id int) error                                                                                // Wajib ganti password sementara saat login berikutnya.
+	UpdateWorkModes(ctx context.Context, id int, remoteDays *string, fieldWorkAllowed *bool) error                                          // Kebijakan remote/field user (nil = tidak diubah).
+	GetUserChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error)                                     // User berubah/dihapus setelah watermark (sync delta).
+	GetUserSummariesByIDs(ctx context.Context, ids []int) ([]models.User, error)                                                            // Ringkasan user untuk ?include=user.
 }
 
 // ShiftRepository: Kontrak untuk operasi data Shift (definisi jam kerja).
 type ShiftRepository interface {
-	CreateShift(ctx context.Context, shift *models.Shift) (int, error) // Buat shift baru.
-	GetShiftByID(ctx context.Context, id int) (*models.Shift, error)   // Cari shift by ID.
-	GetAllShifts(ctx context.Context) ([]models.Shift, error)          // Dapatkan semua shift.
-	UpdateShift(ctx context.Context, shift *models.Shift) error        // Update shift by ID.
-	DeleteShift(ctx context.Context, id int) error                     // Hapus shift by ID (cek dependensi).
+	CreateShift(ctx context.Context, shift *models.Shift) (int, error)                            // Buat shift baru.
+	GetShiftByID(ctx context.Context, id int) (*models.Shift, error)                              // Cari shift by ID.
+	GetAllShifts(ctx context.Context) ([]models.Shift, error)                                     // Dapatkan semua shift.
+	UpdateShift(ctx context.Context, shift *models.Shift) error                                   // Update shift by ID.
+	DeleteShift(ctx context.Context, id int) error                                                // Hapus shift by ID (cek dependensi).
+	CreateShiftOverride(ctx context.Context, o *models.ShiftOverride) (int, error)                // Buat override jam shift musiman (ErrNoRows jika shift tidak ada).
+	GetShiftOverrides(ctx context.Context, activeFrom *time.Time) ([]models.ShiftOverride, error) // Daftar override; activeFrom = hanya yang belum berakhir.
+	DeleteShiftOverride(ctx context.Context, id int) error                                        // Hapus override by ID.
 }
 
 // ScheduleRepository: Kontrak untuk operasi data UserSchedule (penjadwalan).
 type ScheduleRepository interface {
-	CreateSchedule(ctx context.Context, schedule *models.UserSchedule) (int, error)                                                            // Buat jadwal baru.
-	GetScheduleByUserAndDate(ctx context.Context, userID int, date time.Time) (*models.UserSchedule, error)                                    // Cari jadwal user pada tanggal tertentu.
-	GetSchedulesByUser(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) ([]models.UserSchedule, int, error)     // Dapatkan jadwal user (paginated).
-	GetSchedulesByDateRangeForAllUsers(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]models.UserSchedule, int, error) // Dapatkan semua jadwal (paginated).
-	DeleteSchedule(ctx context.Context, id int) error                                                                                          // Hapus jadwal by ID.
-	UpdateSchedule(ctx context.Context, schedule *models.UserSchedule) error                                                                   // Update jadwal by ID.
+	CreateSchedule(ctx context.Context, schedule *models.UserSchedule) (int, error)                                                                      // Buat jadwal baru.
+	GetScheduleByUserAndDate(ctx context.Context, userID int, date time.Time) (*models.UserSchedule, error)                                              // Cari jadwal user pada tanggal tertentu.
+	GetScheduleByID(ctx context.Context, id int) (*models.UserSchedule, error)                                                                           // Cari jadwal by ID (beserta shift).
+	GetSchedulesByUser(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) ([]models.UserSchedule, int, error)               // Dapatkan jadwal user (paginated).
+	GetSchedulesByUserWithAttendance(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) ([]models.UserSchedule, int, error) // Jadwal user + absensi pada tanggalnya (paginated).
+	GetSchedulesByDateRangeForAllUsers(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]models.UserSchedule, int, error)           // Dapatkan semua jadwal (paginated).
+	DeleteSchedule(ctx context.Context, id int) error                                                                                                    // Hapus jadwal by ID.
+	UpdateSchedule(ctx context.Context, schedule *models.UserSchedule) error                                                                             // Update jadwal by ID.
+	PatchSchedule(ctx context.Context, id int, input *models.PatchScheduleInput) (int, error)                                                            // Update parsial jadwal by ID, mengembalikan versi baru.
+	BulkDeleteSchedules(ctx context.Context, ids []int, userIDs []int, startDate, endDate *time.Time) (*models.BulkDeleteSchedulesResult, error)         // Hapus banyak jadwal (by ID atau filter) dalam satu transaksi.
+	ExportSchedulesByUser(ctx context.Context, userID int) ([]models.UserSchedule, error)                                                                // Semua jadwal user tanpa pagination (ekspor data).
+	GetShiftHeadcounts(ctx context.Context, startDate, endDate time.Time) ([]models.ShiftHeadcount, error)                                               // Jumlah terjadwal & hadir per tanggal & shift.
+	GetScheduleChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error)                                              // Jadwal berubah/dihapus setelah watermark (sync delta).
+	GetSchedulesByIDs(ctx context.Context, ids []int) ([]models.UserSchedule, error)                                                                     // Jadwal + ringkasan shift untuk ?include=schedule.
 }
 
 // AttendanceRepository: Kontrak untuk operasi data Attendance (log absensi).
+// Semua perubahan dicatat sebagai event append-only di attendance_events;
+// tabel attendances adalah proyeksi dari event terakhir.
 type AttendanceRepository interface {
-	CreateCheckIn(ctx context.Context, userID int, checkInTime time.Time, notes *string) (int, error)                                      // Catat check-in.
-	GetLastAttendance(ctx context.Context, userID int) (*models.Attendance, error)                                                         // Dapatkan absensi terakhir user.
-	UpdateCheckOut(ctx context.Context, attendanceID int, checkOutTime time.Time, notes *string) error                                     // Catat check-out pada absensi ID tertentu.
-	GetAttendancesByUser(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) ([]models.Attendance, int, error) // Dapatkan absensi user (paginated).
-	GetAllAttendances(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]models.Attendance, int, error)                // Dapatkan semua absensi (paginated, termasuk user).
+	CreateCheckIn(ctx context.Context, userID int, checkInTime time.Time, notes *string, scheduleID, projectID *int, mode string) (int, error)                    // Catat check-in.
+	GetLastAttendance(ctx context.Context, userID int) (*models.Attendance, error)                                                                                // Dapatkan absensi terakhir user.
+	UpdateCheckOut(ctx context.Context, attendanceID int, checkOutTime time.Time, notes *string) error                                                            // Catat check-out pada absensi ID tertentu.
+	GetAttendancesByUser(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) ([]models.Attendance, int, error)                        // Dapatkan absensi user (paginated).
+	GetAllAttendances(ctx context.Context, startDate, endDate time.Time, filter models.AttendanceReportFilter, page, limit int) ([]models.Attendance, int, error) // Dapatkan semua absensi (paginated, termasuk user).
+	ExportAttendancesByUser(ctx context.Context, userID int) ([]models.Attendance, error)                                                                         // Semua absensi user tanpa pagination (ekspor data).
+	CorrectAttendance(ctx context.Context, attendanceID int, input *models.AttendanceCorrectionInput, actorUserID int) (*models.Attendance, error)                // Koreksi admin (dicatat di ledger).
+	GetAttendanceEvents(ctx context.Context, attendanceID int) ([]models.AttendanceEvent, error)                                                                  // Riwayat event ledger satu record absensi.
+	GetAttendanceByID(ctx context.Context, attendanceID int) (*models.Attendance, error)                                                                          // Cari absensi by ID.
+	GetProjectHours(ctx context.Context, startDate, endDate time.Time, userID *int) ([]models.ProjectHours, error)                                                // Rekap jam sesi selesai per project (opsional satu user).
+	SwitchProject(ctx context.Context, userID int, at time.Time, projectID int) (*models.AttendanceSegment, error)                                                // Pindah project di sesi terbuka (segmen baru tanpa check-out).
+	GetAttendanceSegments(ctx context.Context, attendanceID int) ([]models.AttendanceSegment, error)                                                              // Segmen project satu record absensi.
+	RecordFaceCheck(ctx context.Context, fc *models.FaceCheck) error                                                                                              // Simpan hasil verifikasi wajah check-in.
+	GetFaceChecks(ctx context.Context, reviewStatus string, page, limit int) ([]models.FaceCheck, int, error)                                                     // Hasil verifikasi wajah (paginated, opsional per status tinjauan).
+	ReviewFaceCheck(ctx context.Context, attendanceID int, status string, reviewerID int) (*models.FaceCheck, error)                                              // Selesaikan tinjauan punch yang ditandai (ErrNoRows jika tidak pending).
+	AddAttendanceTags(ctx context.Context, attendanceID int, tags []string) error                                                                                 // Tambah tag terstruktur (ikut transaksi check-in/out).
+	GetTagHours(ctx context.Context, startDate, endDate time.Time, userID *int) ([]models.TagHours, error)                                                        // Rekap jam sesi selesai per tag (opsional satu user).
+	GetModeHours(ctx context.Context, startDate, endDate time.Time, userID *int) ([]models.ModeHours, error)                                                      // Rekap jam sesi selesai per mode kerja (opsional satu user).
+	RecordAttendancePing(ctx context.Context, userID int, ping *models.AttendancePing, minInterval time.Duration) error                                           // Simpan ping lokasi sesi field terbuka milik user.
+	GetAttendancePings(ctx context.Context, attendanceID int) ([]models.AttendancePing, error)                                                                    // Semua ping lokasi satu sesi, urut waktu rekam.
+	DeleteAttendancePingsBefore(ctx context.Context, before time.Time, limit int) (int, error)                                                                    // Hapus satu batch ping yang melewati masa retensi.
+	GetOccupancy(ctx context.Context, startDate, endDate time.Time, granularity, timezone string, mode *string) ([]models.OccupancyBucket, error)                 // Heatmap user check-in per slot jam/hari & lokasi (mode kerja).
+	GetAttendanceChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error)                                                     // Absensi berubah/dihapus setelah watermark (sync delta).
+	RecordQueuedCheckInFailure(ctx context.Context, f *models.QueuedCheckInFailure) error                                                                         // Simpan check-in tertunda yang gagal dicatat (dead letter).
+	GetQueuedCheckInFailures(ctx context.Context, resolved *bool, page, limit int) ([]models.QueuedCheckInFailure, int, error)                                    // Dead letter check-in (paginated, opsional per status tindak lanjut).
+	ResolveQueuedCheckInFailure(ctx context.Context, id, adminID int, note string) (*models.QueuedCheckInFailure, error)                                          // Tandai dead letter sudah ditindaklanjuti (ErrNoRows jika tidak terbuka).
 }
 
 // RoleRepository: Kontrak untuk operasi data Role.
 type RoleRepository interface {
-	CreateRole(ctx context.Context, role *models.Role) (int, error) // Buat role baru.
-	GetRoleByID(ctx context.Context, id int) (*models.Role, error)  // Cari role by ID.
-	GetAllRoles(ctx context.Context) ([]models.Role, error)         // Dapatkan semua role.
-	UpdateRole(ctx context.Context, role *models.Role) error        // Update role by ID.
-	DeleteRole(ctx context.Context, id int) error                   // Hapus role by ID (cek dependensi user).
+	CreateRole(ctx context.Context, role *models.Role) (int, error)                     // Buat role baru.
+	GetRoleByID(ctx context.Context, id int) (*models.Role, error)                      // Cari role by ID.
+	GetAllRoles(ctx context.Context) ([]models.Role, error)                             // Dapatkan semua role.
+	UpdateRole(ctx context.Context, role *models.Role) error                            // Update role by ID.
+	DeleteRole(ctx context.Context, id int) error                                       // Hapus role by ID (cek dependensi user & role turunan).
+	SetRoleParent(ctx context.Context, roleID int, parentID *int) (*models.Role, error) // Atur role yang diwarisi (tolak siklus).
+	GetInheritedRoles(ctx context.Context, roleName string) ([]string, error)           // Rantai role yang diwarisi, terdekat dulu.
+}
+
+// SettingsRepository: Kontrak untuk pengaturan sistem (tabel settings, lihat internal/settings).
+type SettingsRepository interface {
+	GetAllSettings(ctx context.Context) ([]models.Setting, error)                        // Semua pengaturan yang pernah di-set.
+	UpsertSettings(ctx context.Context, values map[string]string, actorUserID int) error // Simpan banyak pengaturan dalam satu transaksi.
+}
+
+// AnnouncementRepository: Kontrak untuk pengumuman admin ke karyawan.
+type AnnouncementRepository interface {
+	CreateAnnouncement(ctx context.Context, announcement *models.Announcement) (int, error)                                           // Buat pengumuman baru.
+	GetAnnouncementByID(ctx context.Context, id int) (*models.Announcement, error)                                                    // Cari pengumuman by ID.
+	GetAllAnnouncements(ctx context.Context, page, limit int) ([]models.Announcement, int, error)                                     // Semua pengumuman (paginated, untuk admin).
+	GetActiveAnnouncementsForUser(ctx context.Context, userID int, at time.Time, page, limit int) ([]models.Announcement, int, error) // Pengumuman yang sedang tayang untuk role user (paginated).
+	UpdateAnnouncement(ctx context.Context, announcement *models.Announcement) error                                                  // Ganti isi pengumuman by ID.
+	DeleteAnnouncement(ctx context.Context, id int) error                                                                             // Hapus pengumuman by ID.
+}
+
+// AuditRepository: Kontrak untuk audit log aktivitas user dan feed aktivitas admin.
+type AuditRepository interface {
+	CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error                                                    // Catat entri audit (ID & created_at diisi ke entry).
+	GetUserActivity(ctx context.Context, userID int, after *models.ActivityCursor, limit int) ([]models.ActivityItem, error) // Feed aktivitas user (audit log + ledger absensi), terbaru dulu.
+	GetLoginHistoryMatch(ctx context.Context, userID int, device, country string) (*models.LoginHistoryMatch, error)         // Apakah perangkat/negara login pernah dipakai sebelumnya.
+}
+
+// DocumentRepository: Kontrak untuk metadata dokumen pendukung (isi file ada di storage).
+type DocumentRepository interface {
+	CreateDocument(ctx context.Context, doc *models.Document) (int, error)                                   // Simpan metadata dokumen (ID & created_at diisi ke doc).
+	GetDocumentByID(ctx context.Context, id int) (*models.Document, error)                                   // Cari dokumen by ID.
+	GetDocumentsBySubject(ctx context.Context, subjectType string, subjectID int) ([]models.Document, error) // Dokumen yang melampiri satu entitas.
+	DeleteDocument(ctx context.Context, id int) error                                                        // Hapus metadata dokumen by ID.
+}
+
+// PayrollRepository: Kontrak untuk periode payroll (penutupan periode mengunci edit absensi).
+type PayrollRepository interface {
+	CreatePayrollPeriod(ctx context.Context, period *models.PayrollPeriod) (int, error)                             // Buat periode (status open, tidak boleh tumpang tindih).
+	GetPayrollPeriodByID(ctx context.Context, id int) (*models.PayrollPeriod, error)                                // Cari periode by ID.
+	GetAllPayrollPeriods(ctx context.Context, page, limit int) ([]models.PayrollPeriod, int, error)                 // Semua periode (paginated, terbaru dulu).
+	ClosePayrollPeriod(ctx context.Context, id int, actorUserID int) (*models.PayrollPeriod, error)                 // Tutup periode (absensi di dalamnya terkunci).
+	ReopenPayrollPeriod(ctx context.Context, id int, actorUserID int, reason string) (*models.PayrollPeriod, error) // Buka kembali periode yang ditutup (wajib alasan).
+}
+
+// ProjectRepository: Kontrak untuk project/cost center yang dipakai menandai sesi absensi.
+type ProjectRepository interface {
+	CreateProject(ctx context.Context, project *models.Project) (int, error)       // Buat project baru (kode unik).
+	GetProjectByID(ctx context.Context, id int) (*models.Project, error)           // Cari project by ID.
+	GetAllProjects(ctx context.Context, activeOnly bool) ([]models.Project, error) // Semua project (atau hanya yang aktif).
+	UpdateProject(ctx context.Context, project *models.Project) error              // Ganti data project by ID.
+	DeleteProject(ctx context.Context, id int) error                               // Hapus project yang belum dipakai absensi.
+}
+
+// SignOffRepository: Kontrak untuk sign-off harian absensi tim oleh atasan (mengunci absensi tanggal tersebut).
+type SignOffRepository interface {
+	SignOffDay(ctx context.Context, managerID, signerID int, day time.Time, userIDs []int, grace time.Duration, note *string) ([]models.SignOffResult, error) // Sign-off anggota tim managerID pada satu tanggal oleh signerID (hasil per user).
+	GetUnsignedDays(ctx context.Context, managerID *int, startDate, endDate time.Time) ([]models.UnsignedDay, error)                                          // Tanggal kerja yang belum di-sign-off (tim atau seluruh organisasi).
+}
+
+// DisputeRepository: Kontrak untuk dispute (sanggahan) karyawan atas record absensinya.
+type DisputeRepository interface {
+	CreateDispute(ctx context.Context, attendanceID, userID int, comment string) (*models.AttendanceDispute, error)      // Buat dispute open atas absensi milik user.
+	GetDisputeByID(ctx context.Context, id int) (*models.AttendanceDispute, error)                                       // Cari dispute by ID.
+	GetDisputesByUser(ctx context.Context, userID int) ([]models.AttendanceDispute, error)                               // Dispute milik user (terbaru dulu).
+	GetAllDisputes(ctx context.Context, status string, page, limit int) ([]models.AttendanceDispute, int, error)         // Semua dispute, bisa difilter status (paginated, terlama dulu).
+	ResolveDispute(ctx context.Context, id int, actorUserID int, status, note string) (*models.AttendanceDispute, error) // Tutup dispute open (resolved/rejected).
+	GetOpenDisputesBefore(ctx context.Context, before time.Time, limit int) ([]models.AttendanceDispute, error)          // Dispute open yang dibuat sebelum before (terlama dulu).
+}
+
+// DeviceRepository: Kontrak untuk token perangkat push notification dan catatan pengingat shift.
+type DeviceRepository interface {
+	RegisterDeviceToken(ctx context.Context, userID int, platform, token string) (*models.DeviceToken, error) // Simpan token (token yang sama dipindahkan ke user ini).
+	DeleteDeviceToken(ctx context.Context, userID int, token string) error                                    // Hapus token milik user.
+	GetDeviceTokensByUser(ctx context.Context, userID int) ([]models.DeviceToken, error)                      // Token perangkat milik user.
+	DeleteDeviceTokens(ctx context.Context, tokens []string) (int, error)                                     // Hapus token yang ditolak provider push.
+	GetPendingShiftReminders(ctx context.Context, fromDate, toDate time.Time) ([]models.ShiftReminder, error) // Jadwal ber-token perangkat / pengingat SMS yang belum diingatkan.
+	MarkShiftReminderSent(ctx context.Context, scheduleID int) (bool, error)                                  // Catat pengingat (false = sudah dicatat sebelumnya).
+}
+
+// NotificationRepository: Kontrak untuk inbox notifikasi in-app per user.
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, n *models.Notification) (int, error)                                                  // Tambah notifikasi ke inbox user.
+	GetNotificationsByUser(ctx context.Context, userID int, unreadOnly bool, page, limit int) ([]models.Notification, int, error) // Inbox user, terbaru dulu (paginated).
+	CountUnreadNotifications(ctx context.Context, userID int) (int, error)                                                        // Jumlah belum dibaca.
+	MarkNotificationRead(ctx context.Context, userID, id int) (*models.Notification, error)                                       // Tandai satu notifikasi dibaca.
+	MarkAllNotificationsRead(ctx context.Context, userID int) (int, error)                                                        // Tandai semua dibaca, mengembalikan jumlahnya.
+}
+
+// OutboxRepository: Kontrak untuk outbox efek samping (notifikasi, webhook, streaming).
+type OutboxRepository interface {
+	EnqueueOutboxMessages(ctx context.Context, msgs []models.OutboxMessage) error                                                                                                                        // Simpan pesan (ikut transaksi RunInTx).
+	ProcessDueOutboxMessages(ctx context.Context, limit, maxAttempts int, backoff func(attempts int) time.Duration, deliver func(ctx context.Context, msg models.OutboxMessage) error) (int, int, error) // Kirim pesan jatuh tempo; retry/dead letter yang gagal.
+	GetDeadOutboxMessages(ctx context.Context, page, limit int) ([]models.OutboxMessage, int, error)                                                                                                     // Dead letter, terbaru dulu (paginated).
+	RetryOutboxMessage(ctx context.Context, id int64) (*models.OutboxMessage, error)                                                                                                                     // Kembalikan dead letter ke antrean.
+	DeleteDeliveredOutboxMessages(ctx context.Context, before time.Time) (int, error)                                                                                                                    // Hapus pesan terkirim sebelum cutoff.
+}
+
+// DelegationRepository: Kontrak untuk delegasi wewenang approval atasan selama rentang tanggal.
+type DelegationRepository interface {
+	CreateDelegation(ctx context.Context, delegatorID int, input models.DelegationInput) (*models.ApprovalDelegation, error) // Limpahkan wewenang ke user aktif lain.
+	GetDelegationsByUser(ctx context.Context, userID int) ([]models.ApprovalDelegation, error)                               // Delegasi yang diberikan/diterima user (terbaru dulu).
+	RevokeDelegation(ctx context.Context, id, delegatorID int) (*models.ApprovalDelegation, error)                           // Cabut delegasi milik delegator.
+	HasActiveDelegation(ctx context.Context, delegatorID, delegateID int, day time.Time) (bool, error)                       // Delegasi aktif pada tanggal day?
+}
+
+// EscalationRepository: Kontrak untuk riwayat eskalasi item approval yang melewati SLA.
+type EscalationRepository interface {
+	RecordEscalation(ctx context.Context, e *models.ApprovalEscalation) (bool, error)                                // Catat langkah eskalasi (false = level sudah tercatat).
+	GetEscalations(ctx context.Context, itemType string, itemIDs []int) (map[int][]models.ApprovalEscalation, error) // Riwayat per item, terlama dulu.
+}
+
+// LaborRepository: Kontrak untuk tarif per jam dan estimasi biaya tenaga kerja.
+type LaborRepository interface {
+	SetUserHourlyRate(ctx context.Context, userID int, rate *float64) error                                                              // Tarif user (nil = ikut role).
+	SetRoleHourlyRate(ctx context.Context, roleID int, rate *float64) error                                                              // Tarif default role (nil = tanpa tarif).
+	GetLaborCost(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]models.LaborCostRow, *models.LaborCostRow, error) // Jam & biaya terjadwal vs aktual per kelompok, plus total.
+}
+
+// JobRepository: Kontrak untuk definisi dan status run job terjadwal.
+type JobRepository interface {
+	RegisterJobs(ctx context.Context, defs []models.JobDefinition) error                                     // Upsert definisi job (interval).
+	GetJobs(ctx context.Context) ([]models.ScheduledJob, error)                                              // Semua job beserta run terakhir.
+	StartJobRun(ctx context.Context, name, trigger string, force bool) (bool, error)                         // Tandai berjalan & majukan jadwal (false = belum jatuh tempo).
+	FinishJobRun(ctx context.Context, name string, result int, runErr *string, duration time.Duration) error // Catat hasil run.
+}
+
+// VerificationRepository: Kontrak untuk tautan publik verifikasi kepegawaian dan audit aksesnya.
+type VerificationRepository interface {
+	CreateVerificationLink(ctx context.Context, link *models.EmploymentVerificationLink) error                                     // Simpan tautan (ErrNoRows = user tidak ada).
+	GetVerificationLink(ctx context.Context, id int) (*models.EmploymentVerificationLink, error)                                   // Tautan berdasarkan ID.
+	GetVerificationLinksByUser(ctx context.Context, userID int) ([]models.EmploymentVerificationLink, error)                       // Tautan milik user (terbaru dulu).
+	RevokeVerificationLink(ctx context.Context, id int) (*models.EmploymentVerificationLink, error)                                // Cabut tautan (ErrNoRows = tidak ada/sudah dicabut).
+	RecordVerificationAccess(ctx context.Context, access *models.EmploymentVerificationAccess) error                               // Catat akses (granted menambah access_count).
+	GetVerificationAccesses(ctx context.Context, linkID int) ([]models.EmploymentVerificationAccess, error)                        // Riwayat akses tautan (terbaru dulu).
+	GetEmploymentVerification(ctx context.Context, userID int, attendanceSince *time.Time) (*models.EmploymentVerification, error) // Data verifikasi terbatas (+ hari hadir sejak tanggal).
+}
+
+// KioskRepository: Kontrak untuk perangkat kiosk/terminal absensi (token terikat perangkat).
+type KioskRepository interface {
+	CreateKioskDevice(ctx context.Context, device *models.KioskDevice) error                                 // Daftarkan perangkat (ErrKioskAlreadyEnrolled = fingerprint sudah aktif).
+	GetKioskDevice(ctx context.Context, id int) (*models.KioskDevice, error)                                 // Perangkat berdasarkan ID (termasuk yang dicabut).
+	GetKioskDevicesByUser(ctx context.Context, userID int) ([]models.KioskDevice, error)                     // Perangkat milik user (terbaru dulu).
+	RevokeKioskDevice(ctx context.Context, id int, ownerID *int, revokedBy int) (*models.KioskDevice, error) // Cabut perangkat (ownerID nil = admin; ErrNoRows = tidak ada/sudah dicabut).
+	TouchKioskDevice(ctx context.Context, id int) error                                                      // Perbarui last_seen_at.
+}
+
+// AttendancePhotoRepository: Kontrak untuk foto check-in dan siklus hidupnya (proses & retensi).
+type AttendancePhotoRepository interface {
+	CreateAttendancePhoto(ctx context.Context, photo *models.AttendancePhoto) error                                        // Simpan upload mentah berstatus pending.
+	GetAttendancePhoto(ctx context.Context, attendanceID int) (*models.AttendancePhoto, error)                             // Foto satu record absensi.
+	GetAttendancePhotoByID(ctx context.Context, id int) (*models.AttendancePhoto, error)                                   // Foto berdasarkan ID.
+	GetPendingAttendancePhotos(ctx context.Context, limit int) ([]models.AttendancePhoto, error)                           // Antrean pending (terlama dulu).
+	MarkAttendancePhotoProcessed(ctx context.Context, id int, originalKey, thumbnailKey string, width, height int) error   // Selesai diproses (incoming_key dikosongkan).
+	MarkAttendancePhotoFailed(ctx context.Context, id int, reason string) error                                            // Gagal diproses (incoming_key dikosongkan).
+	GetExpiredAttendancePhotos(ctx context.Context, capturedBefore time.Time, limit int) ([]models.AttendancePhoto, error) // Foto belum purged yang diambil sebelum batas retensi.
+	MarkAttendancePhotoPurged(ctx context.Context, id int) error                                                           // File sudah dihapus (semua key dikosongkan).
+}
+
+// ReportExportRepository: Kontrak untuk file laporan yang dibuat di background dan retensinya.
+type ReportExportRepository interface {
+	CreateReportExport(ctx context.Context, report *models.ReportExport) error                                             // Simpan permintaan berstatus pending (mengisi ID & created_at).
+	GetReportExportByID(ctx context.Context, id int) (*models.ReportExport, error)                                         // pgx.ErrNoRows jika tidak ada.
+	GetReportExports(ctx context.Context, page, limit int) ([]models.ReportExport, int, error)                             // Semua ekspor, terbaru dulu (paginated).
+	GetPendingReportExports(ctx context.Context, limit int) ([]models.ReportExport, error)                                 // Ekspor pending, terlama dulu.
+	MarkReportExportCompleted(ctx context.Context, id int, result models.ReportExportResult) (*models.ReportExport, error) // Selesai; expires_at = sekarang + retensi.
+	MarkReportExportFailed(ctx context.Context, id int, reason string) error                                               // Gagal dibuat.
+	GetExpiredReportExports(ctx context.Context, before time.Time, limit int) ([]models.ReportExport, error)               // Ekspor completed dengan expires_at sebelum before.
+	MarkReportExportPurged(ctx context.Context, id int) error                                                              // File sudah dihapus (storage_key dikosongkan).
+	GetSnapshotSessions(ctx context.Context, startDate, endDate time.Time) ([]models.Attendance, error)                    // Sesi dengan tanggal check-in dalam periode (beserta username).
+	CreateReportSnapshot(ctx context.Context, snapshot *models.ReportSnapshot) error                                       // Simpan snapshot beku (mengisi ID & created_at); pgx.ErrNoRows jika periode payroll tidak ada.
+	GetReportSnapshotByID(ctx context.Context, id int) (*models.ReportSnapshot, error)                                     // Snapshot beserta barisnya; pgx.ErrNoRows jika tidak ada.
+	GetReportSnapshots(ctx context.Context, page, limit int) ([]models.ReportSnapshot, int, error)                         // Semua snapshot tanpa baris, terbaru dulu (paginated).
+}
+
+// BackupRepository: Kontrak untuk status backup database (isi backup dibuat package backup).
+type BackupRepository interface {
+	CreateBackup(ctx context.Context, backup *models.Backup) error                             // Simpan permintaan berstatus pending (mengisi ID & created_at); ErrBackupInProgress jika masih ada yang antre/berjalan.
+	GetBackupByID(ctx context.Context, id int) (*models.Backup, error)                         // pgx.ErrNoRows jika tidak ada.
+	GetBackups(ctx context.Context, page, limit int) ([]models.Backup, int, error)             // Semua backup, terbaru dulu (paginated).
+	ClaimPendingBackup(ctx context.Context) (*models.Backup, error)                            // Ubah backup pending terlama menjadi running; pgx.ErrNoRows jika tidak ada.
+	MarkBackupCompleted(ctx context.Context, id int, result models.BackupResult) error         // Selesai (hanya dari running).
+	MarkBackupFailed(ctx context.Context, id int, reason string) error                         // Gagal (dari pending atau running).
+	FailStaleBackups(ctx context.Context, startedBefore time.Time, reason string) (int, error) // Tandai failed backup running yang dimulai sebelum batas (proses terhenti).
+}
+
+// AppInstanceRepository: Kontrak untuk heartbeat instance API dan versi aplikasinya (lihat internal/instances).
+type AppInstanceRepository interface {
+	UpsertAppInstance(ctx context.Context, instance *models.AppInstance) error              // Catat heartbeat (last_seen_at = sekarang); mengisi started_at & last_seen_at.
+	DeleteAppInstance(ctx context.Context, instanceID string) error                         // Hapus instance (saat berhenti).
+	GetAppInstances(ctx context.Context, seenSince time.Time) ([]models.AppInstance, error) // Instance dengan heartbeat sejak batas, urut versi lalu started_at.
+	DeleteStaleAppInstances(ctx context.Context, seenBefore time.Time) (int, error)         // Hapus instance yang heartbeat terakhirnya sebelum batas.
+}
+
+// DebugCaptureRepository: Kontrak untuk rekaman request/response debug (lihat internal/debugcapture).
+type DebugCaptureRepository interface {
+	CreateDebugCapture(ctx context.Context, capture *models.DebugCapture) error                                                  // Simpan rekaman (mengisi ID & created_at).
+	GetDebugCaptureByID(ctx context.Context, id int64) (*models.DebugCapture, error)                                             // Rekaman beserta header & body; pgx.ErrNoRows jika tidak ada/kedaluwarsa.
+	GetDebugCaptures(ctx context.Context, filter models.DebugCaptureFilter, page, limit int) ([]models.DebugCapture, int, error) // Rekaman belum kedaluwarsa tanpa body, terbaru dulu (paginated).
+	DeleteExpiredDebugCaptures(ctx context.Context, before time.Time, limit int) (int, error)                                    // Hapus satu batch rekaman kedaluwarsa.
 }
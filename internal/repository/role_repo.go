@@ -7,56 +7,82 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rakaarfi/attendance-system-be/internal/models"
-	zlog "github.com/rs/zerolog/log"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
 )
 
+var (
+	// ErrRoleCycle dikembalikan saat parent role yang dipilih (langsung atau lewat rantainya)
+	// mewarisi role itu sendiri.
+	ErrRoleCycle = errors.New("role inheritance would create a cycle")
+	// ErrParentRoleNotFound dikembalikan saat parent role tidak ada.
+	ErrParentRoleNotFound = errors.New("parent role not found")
+)
+
+// roleHierarchyError memetakan pelanggaran hierarki role (trigger siklus & FK parent_id).
+func roleHierarchyError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+	switch {
+	case pgErr.ConstraintName == "roles_parent_cycle_check" || pgErr.ConstraintName == "roles_parent_self_check":
+		return ErrRoleCycle
+	case pgErr.Code == "23503" && pgErr.ConstraintName == "roles_parent_id_fkey":
+		return ErrParentRoleNotFound
+	}
+	return nil
+}
+
 type roleRepo struct {
-	db *pgxpool.Pool
+	db   Conn // Primary: tulis & baca konsisten
+	read Conn // Replica (atau Primary jika tidak ada) untuk laporan/listing
 }
 
-func NewRoleRepository(db *pgxpool.Pool) RoleRepository {
-	return &roleRepo{db: db}
+func NewRoleRepository(pools Pools) RoleRepository {
+	return &roleRepo{db: pools.Primary, read: pools.reader()}
 }
 
 func (r *roleRepo) GetRoleByID(ctx context.Context, id int) (*models.Role, error) {
-	query := `SELECT id, name FROM roles WHERE id = $1`
+	query := `SELECT ` + shared.SelectList("r", shared.RoleColumns) + ` FROM roles r WHERE r.id = $1`
 	role := &models.Role{}
-	err := r.db.QueryRow(ctx, query, id).Scan(&role.ID, &role.Name)
+	err := shared.ScanRole(r.db.QueryRow(ctx, query, id), role)
 	if err != nil {
 		// Handle pgx.ErrNoRows
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, pgx.ErrNoRows // Kembalikan error asli
 		}
-		zlog.Error().Err(err).Int("role_id", id).Msg("Error getting role by ID")
+		shared.Logger(ctx).Error().Err(err).Int("role_id", id).Msg("Error getting role by ID")
 		return nil, fmt.Errorf("error getting role by id %d: %w", id, err)
 	}
 	return role, nil
 }
 
 func (r *roleRepo) CreateRole(ctx context.Context, role *models.Role) (int, error) {
-	query := `INSERT INTO roles (name) VALUES ($1) RETURNING id`
+	query := `INSERT INTO roles (name, parent_id) VALUES ($1, $2) RETURNING id`
 	var roleID int
-	err := r.db.QueryRow(ctx, query, role.Name).Scan(&roleID)
+	err := r.db.QueryRow(ctx, query, role.Name, role.ParentID).Scan(&roleID)
 	if err != nil {
+		if hierarchyErr := roleHierarchyError(err); hierarchyErr != nil {
+			return 0, hierarchyErr
+		}
 		// Handle unique constraint violation (name)
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
-			zlog.Warn().Err(err).Str("role_name", role.Name).Msg("Role name already exists")
+			shared.Logger(ctx).Warn().Err(err).Str("role_name", role.Name).Msg("Role name already exists")
 			return 0, fmt.Errorf("role name '%s' already exists", role.Name)
 		}
 		// Error umum
-		zlog.Error().Err(err).Str("role_name", role.Name).Msg("Error creating role")
+		shared.Logger(ctx).Error().Err(err).Str("role_name", role.Name).Msg("Error creating role")
 		return 0, fmt.Errorf("error creating role: %w", err)
 	}
 	return roleID, nil
 }
 
 func (r *roleRepo) GetAllRoles(ctx context.Context) ([]models.Role, error) {
-	query := `SELECT id, name FROM roles ORDER BY name`
-	rows, err := r.db.Query(ctx, query)
+	query := `SELECT ` + shared.SelectList("r", shared.RoleColumns) + ` FROM roles r ORDER BY r.name`
+	rows, err := r.read.Query(ctx, query)
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error getting all roles")
+		shared.Logger(ctx).Error().Err(err).Msg("Error getting all roles")
 		return nil, fmt.Errorf("error getting all roles: %w", err)
 	}
 	defer rows.Close()
@@ -64,15 +90,15 @@ func (r *roleRepo) GetAllRoles(ctx context.Context) ([]models.Role, error) {
 	roles := []models.Role{}
 	for rows.Next() {
 		var role models.Role
-		if err := rows.Scan(&role.ID, &role.Name); err != nil {
-			zlog.Warn().Err(err).Msg("Error scanning role row")
+		if err := shared.ScanRole(rows, &role); err != nil {
+			shared.Logger(ctx).Warn().Err(err).Msg("Error scanning role row")
 			continue // Lanjutkan ke baris berikutnya
 		}
 		roles = append(roles, role)
 	}
 
 	if err = rows.Err(); err != nil {
-		zlog.Error().Err(err).Msg("Error iterating role rows")
+		shared.Logger(ctx).Error().Err(err).Msg("Error iterating role rows")
 		return nil, fmt.Errorf("error iterating role rows: %w", err)
 	}
 	return roles, nil
@@ -84,11 +110,11 @@ func (r *roleRepo) UpdateRole(ctx context.Context, role *models.Role) error {
 	if err != nil {
 		// Handle unique constraint violation (name)
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
-			zlog.Warn().Err(err).Str("role_name", role.Name).Int("role_id", role.ID).Msg("Role name already exists on update")
+			shared.Logger(ctx).Warn().Err(err).Str("role_name", role.Name).Int("role_id", role.ID).Msg("Role name already exists on update")
 			return fmt.Errorf("role name '%s' already exists", role.Name)
 		}
 		// Error umum
-		zlog.Error().Err(err).Int("role_id", role.ID).Msg("Error updating role")
+		shared.Logger(ctx).Error().Err(err).Int("role_id", role.ID).Msg("Error updating role")
 		return fmt.Errorf("error updating role %d: %w", role.ID, err)
 	}
 	if tag.RowsAffected() == 0 {
@@ -103,21 +129,32 @@ func (r *roleRepo) DeleteRole(ctx context.Context, id int) error {
 	var userCount int
 	err := r.db.QueryRow(ctx, countQuery, id).Scan(&userCount)
 	if err != nil {
-		zlog.Error().Err(err).Int("role_id", id).Msg("Error checking users for role before deletion")
+		shared.Logger(ctx).Error().Err(err).Int("role_id", id).Msg("Error checking users for role before deletion")
 		return fmt.Errorf("error checking users for role %d: %w", id, err)
 	}
 
 	if userCount > 0 {
-		zlog.Warn().Int("role_id", id).Int("user_count", userCount).Msg("Attempted to delete role that is still in use")
+		shared.Logger(ctx).Warn().Int("role_id", id).Int("user_count", userCount).Msg("Attempted to delete role that is still in use")
 		return fmt.Errorf("cannot delete role: %d user(s) still assigned to this role", userCount)
 	}
 
+	// Role yang diwarisi role lain juga tidak boleh dihapus (FK parent_id RESTRICT)
+	var childCount int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM roles WHERE parent_id = $1`, id).Scan(&childCount); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("role_id", id).Msg("Error checking child roles before deletion")
+		return fmt.Errorf("error checking child roles of role %d: %w", id, err)
+	}
+	if childCount > 0 {
+		shared.Logger(ctx).Warn().Int("role_id", id).Int("child_count", childCount).Msg("Attempted to delete role that is inherited by other roles")
+		return fmt.Errorf("cannot delete role: %d role(s) still inherit from this role", childCount)
+	}
+
 	// Jika tidak ada user, lanjutkan penghapusan
 	deleteQuery := `DELETE FROM roles WHERE id = $1`
 	tag, err := r.db.Exec(ctx, deleteQuery, id)
 	if err != nil {
 		// Error saat delete (seharusnya jarang terjadi jika pengecekan user berhasil)
-		zlog.Error().Err(err).Int("role_id", id).Msg("Error deleting role")
+		shared.Logger(ctx).Error().Err(err).Int("role_id", id).Msg("Error deleting role")
 		return fmt.Errorf("error deleting role %d: %w", id, err)
 	}
 	if tag.RowsAffected() == 0 {
@@ -125,3 +162,50 @@ func (r *roleRepo) DeleteRole(ctx context.Context, id int) error {
 	}
 	return nil
 }
+
+// SetRoleParent mengatur role yang diwarisi roleID (parentID nil = tanpa pewarisan).
+// Mengembalikan pgx.ErrNoRows jika role tidak ada, ErrParentRoleNotFound, atau ErrRoleCycle
+// (dicek trigger database, sehingga aman terhadap perubahan bersamaan).
+func (r *roleRepo) SetRoleParent(ctx context.Context, roleID int, parentID *int) (*models.Role, error) {
+	query := `UPDATE roles r SET parent_id = $2 WHERE r.id = $1 RETURNING ` + shared.SelectList("r", shared.RoleColumns)
+	role := &models.Role{}
+	err := shared.ScanRole(r.db.QueryRow(ctx, query, roleID, parentID), role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		if hierarchyErr := roleHierarchyError(err); hierarchyErr != nil {
+			shared.Logger(ctx).Warn().Err(err).Int("role_id", roleID).Msg("Rejected role parent change")
+			return nil, hierarchyErr
+		}
+		shared.Logger(ctx).Error().Err(err).Int("role_id", roleID).Msg("Error setting role parent")
+		return nil, fmt.Errorf("error setting parent of role %d: %w", roleID, err)
+	}
+	shared.Logger(ctx).Info().Int("role_id", roleID).Interface("parent_id", parentID).Msg("Role parent updated")
+	return role, nil
+}
+
+// GetInheritedRoles mengembalikan nama role yang diwarisi role bernama roleName lewat rantai
+// parent, terdekat dulu (tanpa roleName sendiri). Mengembalikan pgx.ErrNoRows jika role tidak ada.
+func (r *roleRepo) GetInheritedRoles(ctx context.Context, roleName string) ([]string, error) {
+	query := `WITH RECURSIVE chain AS (
+                  SELECT r.id, r.name, r.parent_id, 0 AS depth FROM roles r WHERE r.name = $1
+                  UNION
+                  SELECT p.id, p.name, p.parent_id, c.depth + 1 FROM roles p JOIN chain c ON p.id = c.parent_id
+                  WHERE c.depth < 32 -- Pengaman; siklus sudah ditolak trigger
+              )
+              SELECT name FROM chain ORDER BY depth`
+	rows, err := r.db.Query(ctx, query, roleName)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("role_name", roleName).Msg("Error querying inherited roles")
+		return nil, fmt.Errorf("error getting inherited roles of %s: %w", roleName, err)
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("error scanning inherited roles of %s: %w", roleName, err)
+	}
+	if len(names) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return names[1:], nil
+}
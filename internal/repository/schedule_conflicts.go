@@ -0,0 +1,92 @@
+// internal/repository/schedule_conflicts.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Validasi tumpang tindih jadwal berdasarkan jam shift, bukan hanya tanggal.
+// Unique (user_id, date) tidak mencegah shift malam (mis. 22:00-06:00) pada satu tanggal
+// bertabrakan dengan shift pagi keesokan harinya. Rentang jadwal adalah
+// [date + start_time, date + end_time), dengan end_time <= start_time berarti selesai
+// keesokan harinya, sehingga cukup memeriksa jadwal user pada tanggal -1..+1. Jam shift
+// diambil dari schedule_shift, jadi override musiman pada tanggal jadwal ikut diperhitungkan.
+
+// scheduleLockKey adalah kunci advisory lock (bersama user_id) untuk menyerialkan
+// perubahan jadwal per user, agar dua request bersamaan tidak sama-sama lolos pengecekan.
+const scheduleLockKey = 4653
+
+// ScheduleConflictError dikembalikan jika jadwal tumpang tindih dengan jadwal user yang lain.
+type ScheduleConflictError struct {
+	Conflicts []models.ScheduleConflict
+}
+
+func (e *ScheduleConflictError) Error() string {
+	if len(e.Conflicts) == 1 {
+		c := e.Conflicts[0]
+		return fmt.Sprintf("schedule overlaps with schedule %d (%s, %s %s-%s)", c.ScheduleID, c.Date, c.ShiftName, c.StartsAt, c.EndsAt)
+	}
+	return fmt.Sprintf("schedule overlaps with %d existing schedules", len(e.Conflicts))
+}
+
+// lockUserSchedules mengambil advisory lock transaksi untuk jadwal milik userID.
+func lockUserSchedules(ctx context.Context, tx pgx.Tx, userID int) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1, $2)`, scheduleLockKey, userID); err != nil {
+		return fmt.Errorf("error locking schedules of user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// checkScheduleOverlap mengembalikan *ScheduleConflictError jika shiftID pada date tumpang
+// tindih dengan jadwal lain milik userID (excludeID = jadwal yang sedang diubah, 0 saat membuat).
+// Shift yang tidak ada dilewati; insert/update akan gagal di foreign key.
+func checkScheduleOverlap(ctx context.Context, tx pgx.Tx, userID, shiftID int, date time.Time, excludeID int) error {
+	query := `
+        WITH candidate AS (
+            SELECT $3::date + s.start_time AS starts_at,
+                   $3::date + s.end_time + CASE WHEN s.end_time <= s.start_time THEN INTERVAL '1 day' ELSE INTERVAL '0' END AS ends_at
+            FROM schedule_shift($2, $3::date) s
+        ), existing AS (
+            SELECT us.id, us.date, s.id AS shift_id, s.name,
+                   us.date + s.start_time AS starts_at,
+                   us.date + s.end_time + CASE WHEN s.end_time <= s.start_time THEN INTERVAL '1 day' ELSE INTERVAL '0' END AS ends_at
+            FROM user_schedules us
+            CROSS JOIN LATERAL schedule_shift(us.shift_id, us.date) s
+            WHERE us.user_id = $1 AND us.id <> $4
+              AND us.date BETWEEN $3::date - 1 AND $3::date + 1
+        )
+        SELECT e.id, e.date::text, e.shift_id, e.name,
+               to_char(e.starts_at, 'YYYY-MM-DD"T"HH24:MI:SS'), to_char(e.ends_at, 'YYYY-MM-DD"T"HH24:MI:SS')
+        FROM existing e, candidate c
+        WHERE e.starts_at < c.ends_at AND e.ends_at > c.starts_at
+        ORDER BY e.starts_at`
+
+	rows, err := tx.Query(ctx, query, userID, shiftID, date, excludeID)
+	if err != nil {
+		return fmt.Errorf("error checking schedule overlap for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var conflicts []models.ScheduleConflict
+	for rows.Next() {
+		var c models.ScheduleConflict
+		if err := rows.Scan(&c.ScheduleID, &c.Date, &c.ShiftID, &c.ShiftName, &c.StartsAt, &c.EndsAt); err != nil {
+			return fmt.Errorf("error scanning overlapping schedule: %w", err)
+		}
+		conflicts = append(conflicts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating overlapping schedules: %w", err)
+	}
+	if len(conflicts) > 0 {
+		shared.Logger(ctx).Warn().Int("user_id", userID).Int("shift_id", shiftID).Str("date", date.Format(shared.DateLayout)).Int("conflicts", len(conflicts)).Msg("Schedule overlaps with existing schedules")
+		return &ScheduleConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
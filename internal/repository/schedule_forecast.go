@@ -0,0 +1,48 @@
+// internal/repository/schedule_forecast.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// GetShiftHeadcounts menghitung, per tanggal dan shift dalam [startDate, endDate], jumlah user
+// yang dijadwalkan dan yang check-in untuk jadwal tersebut (lewat attendances.schedule_id, atau
+// tanggal check-in untuk absensi tanpa tautan jadwal). Absensi tanpa jadwal tidak dihitung
+// karena tidak punya shift. Dipakai untuk saran jumlah orang per shift.
+func (r *scheduleRepo) GetShiftHeadcounts(ctx context.Context, startDate, endDate time.Time) ([]models.ShiftHeadcount, error) {
+	query := `
+        SELECT us.date::text, s.id, s.name, COUNT(*),
+               COUNT(*) FILTER (WHERE EXISTS (
+                   SELECT 1 FROM attendances att
+                   WHERE att.schedule_id = us.id
+                      OR (att.schedule_id IS NULL AND att.user_id = us.user_id AND att.check_in_at::date = us.date)))
+        FROM user_schedules us
+        CROSS JOIN LATERAL schedule_shift(us.shift_id, us.date) s
+        WHERE us.date BETWEEN $1::date AND $2::date
+        GROUP BY us.date, s.id, s.name, s.start_time
+        ORDER BY us.date, s.start_time, s.id`
+	rows, err := r.read.Query(ctx, query, startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Time("start", startDate).Time("end", endDate).Msg("Error querying shift headcounts")
+		return nil, fmt.Errorf("error getting shift headcounts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.ShiftHeadcount{}
+	for rows.Next() {
+		var hc models.ShiftHeadcount
+		if err := rows.Scan(&hc.Date, &hc.ShiftID, &hc.ShiftName, &hc.Scheduled, &hc.Attended); err != nil {
+			return nil, fmt.Errorf("error scanning shift headcount row: %w", err)
+		}
+		counts = append(counts, hc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shift headcount rows: %w", err)
+	}
+	return counts, nil
+}
@@ -2,99 +2,120 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn" // Untuk cek error code
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rakaarfi/attendance-system-be/internal/models"
-	zlog "github.com/rs/zerolog/log"
+	"github.com/rakaarfi/attendance-system-be/internal/pii"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
 )
 
 type scheduleRepo struct {
-	db *pgxpool.Pool
+	db   Conn           // Primary: tulis & baca konsisten
+	read Conn           // Replica (atau Primary jika tidak ada) untuk laporan/listing
+	pii  *pii.Protector // Dekripsi email user pada query yang JOIN ke tabel users
 }
 
-func NewScheduleRepository(db *pgxpool.Pool) ScheduleRepository {
-	return &scheduleRepo{db: db}
+func NewScheduleRepository(pools Pools, protector *pii.Protector) ScheduleRepository {
+	return &scheduleRepo{db: pools.Primary, read: pools.reader(), pii: protector}
 }
 
-const dateLayout = "2006-01-02" // YYYY-MM-DD
-
-// CreateSchedule assigns a shift to a user on a specific date
+// CreateSchedule assigns a shift to a user on a specific date.
+// Mengembalikan *ScheduleConflictError jika jam shift tumpang tindih dengan jadwal user yang lain.
 func (r *scheduleRepo) CreateSchedule(ctx context.Context, schedule *models.UserSchedule) (int, error) {
-	zlog.Info().Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Str("date", schedule.Date).Msg("Creating schedule for user and date")
+	shared.Logger(ctx).Info().Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Str("date", schedule.Date).Msg("Creating schedule for user and date")
 
 	query := `INSERT INTO user_schedules (user_id, shift_id, date) VALUES ($1, $2, $3) RETURNING id`
 	var scheduleID int
 
 	// Parse tanggal dari string ke time.Time untuk validasi dan insert
-	scheduleDate, err := time.Parse(dateLayout, schedule.Date)
+	scheduleDate, err := time.Parse(shared.DateLayout, schedule.Date)
 	if err != nil {
-		zlog.Warn().Err(err).Str("date", schedule.Date).Msg("Invalid date format for schedule, use YYYY-MM-DD")
+		shared.Logger(ctx).Warn().Err(err).Str("date", schedule.Date).Msg("Invalid date format for schedule, use YYYY-MM-DD")
 		return 0, fmt.Errorf("invalid date format for schedule, use YYYY-MM-DD: %w", err)
 	}
 
-	err = r.db.QueryRow(ctx, query, schedule.UserID, schedule.ShiftID, scheduleDate).Scan(&scheduleID)
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("error starting schedule transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	if err := lockUserSchedules(ctx, tx, schedule.UserID); err != nil {
+		return 0, err
+	}
+	if err := checkScheduleOverlap(ctx, tx, schedule.UserID, schedule.ShiftID, scheduleDate, 0); err != nil {
+		return 0, err
+	}
+
+	err = tx.QueryRow(ctx, query, schedule.UserID, schedule.ShiftID, scheduleDate).Scan(&scheduleID)
 	if err != nil {
 		// Cek unique constraint violation (user_id, date)
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
-			zlog.Warn().Err(err).Int("user_id", schedule.UserID).Str("date", schedule.Date).Msg("User already has a schedule on this date")
+			shared.Logger(ctx).Warn().Err(err).Int("user_id", schedule.UserID).Str("date", schedule.Date).Msg("User already has a schedule on this date")
 			return 0, fmt.Errorf("user %d already has a schedule on %s", schedule.UserID, schedule.Date)
 		}
 		// Cek foreign key constraint violation (misal user_id atau shift_id tidak ada)
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
-			zlog.Warn().Err(err).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Msg("Invalid user_id or shift_id")
+			shared.Logger(ctx).Warn().Err(err).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Msg("Invalid user_id or shift_id")
 			return 0, fmt.Errorf("invalid user_id (%d) or shift_id (%d)", schedule.UserID, schedule.ShiftID)
 		}
-		zlog.Error().Err(err).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Str("date", schedule.Date).Msg("Error creating schedule")
+		shared.Logger(ctx).Error().Err(err).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Str("date", schedule.Date).Msg("Error creating schedule")
 		return 0, fmt.Errorf("error creating schedule: %w", err)
 	}
-	zlog.Info().Int("schedule_id", scheduleID).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Str("date", schedule.Date).Msg("Schedule created successfully")
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error committing schedule: %w", err)
+	}
+	shared.Logger(ctx).Info().Int("schedule_id", scheduleID).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Str("date", schedule.Date).Msg("Schedule created successfully")
 	return scheduleID, nil
 }
 
 // GetScheduleByUserAndDate retrieves a specific schedule
 func (r *scheduleRepo) GetScheduleByUserAndDate(ctx context.Context, userID int, date time.Time) (*models.UserSchedule, error) {
-	zlog.Info().Int("user_id", userID).Str("date", date.Format(dateLayout)).Msg("Retrieving schedule for user and date")
+	shared.Logger(ctx).Info().Int("user_id", userID).Str("date", date.Format(shared.DateLayout)).Msg("Retrieving schedule for user and date")
 
 	query := `
-        SELECT us.id, us.user_id, us.shift_id, us.date, us.created_at,
-               s.id as shiftid, s.name as shiftname, s.start_time, s.end_time
+        SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+               ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `
         FROM user_schedules us
-        JOIN shifts s ON us.shift_id = s.id
+        CROSS JOIN LATERAL schedule_shift(us.shift_id, us.date) s
         WHERE us.user_id = $1 AND us.date = $2`
 
 	schedule := &models.UserSchedule{Shift: &models.Shift{}}
-	var scheduleDate time.Time
-	var startTime, endTime string
-
-	err := r.db.QueryRow(ctx, query, userID, date).Scan(
-		&schedule.ID,
-		&schedule.UserID,
-		&schedule.ShiftID,
-		&scheduleDate,
-		&schedule.CreatedAt,
-		&schedule.Shift.ID,
-		&schedule.Shift.Name,
-		&startTime,
-		&endTime,
-	)
+	err := shared.ScanSchedule(r.db.QueryRow(ctx, query, userID, date), schedule, shared.ShiftSummaryDest(schedule.Shift)...)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			zlog.Warn().Int("user_id", userID).Str("date", date.Format(dateLayout)).Msg("No schedule found for user and date")
+			shared.Logger(ctx).Warn().Int("user_id", userID).Str("date", date.Format(shared.DateLayout)).Msg("No schedule found for user and date")
 			return nil, nil
 		}
-		zlog.Error().Err(err).Int("user_id", userID).Str("date", date.Format(dateLayout)).Msg("Error getting schedule")
-		return nil, fmt.Errorf("error getting schedule for user %d on %s: %w", userID, date.Format(dateLayout), err)
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Str("date", date.Format(shared.DateLayout)).Msg("Error getting schedule")
+		return nil, fmt.Errorf("error getting schedule for user %d on %s: %w", userID, date.Format(shared.DateLayout), err)
 	}
 
-	schedule.Date = scheduleDate.Format(dateLayout)
-	schedule.Shift.StartTime = startTime
-	schedule.Shift.EndTime = endTime
+	shared.Logger(ctx).Info().Int("user_id", userID).Str("date", schedule.Date).Msg("Schedule retrieved successfully")
+	return schedule, nil
+}
+
+// GetScheduleByID mengembalikan jadwal beserta ringkasan shift-nya, atau pgx.ErrNoRows.
+func (r *scheduleRepo) GetScheduleByID(ctx context.Context, id int) (*models.UserSchedule, error) {
+	query := `
+        SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+               ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `
+        FROM user_schedules us
+        CROSS JOIN LATERAL schedule_shift(us.shift_id, us.date) s
+        WHERE us.id = $1`
 
-	zlog.Info().Int("user_id", userID).Str("date", scheduleDate.Format(dateLayout)).Msg("Schedule retrieved successfully")
+	schedule := &models.UserSchedule{Shift: &models.Shift{}}
+	if err := shared.ScanSchedule(conn(ctx, r.db).QueryRow(ctx, query, id), schedule, shared.ShiftSummaryDest(schedule.Shift)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("schedule_id", id).Msg("Error getting schedule by ID")
+		return nil, fmt.Errorf("error getting schedule by id %d: %w", id, err)
+	}
 	return schedule, nil
 }
 
@@ -102,7 +123,7 @@ func (r *scheduleRepo) GetScheduleByUserAndDate(ctx context.Context, userID int,
 func (r *scheduleRepo) GetSchedulesByUser(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) (schedules []models.UserSchedule, totalCount int, err error) {
 	// 1. Count Total
 	countQuery := `SELECT COUNT(*) FROM user_schedules WHERE user_id = $1 AND date >= $2 AND date <= $3`
-	err = r.db.QueryRow(ctx, countQuery, userID, startDate, endDate).Scan(&totalCount)
+	err = r.read.QueryRow(ctx, countQuery, userID, startDate, endDate).Scan(&totalCount)
 	if err != nil {
 		err = fmt.Errorf("error counting schedules for user %d: %w", userID, err)
 		return
@@ -120,15 +141,15 @@ func (r *scheduleRepo) GetSchedulesByUser(ctx context.Context, userID int, start
 
 	// 3. Query Data with JOIN, Filters, ORDER BY, LIMIT, OFFSET
 	query := `
-        SELECT us.id, us.user_id, us.shift_id, us.date, us.created_at,
-               s.id as shiftid, s.name as shiftname, s.start_time, s.end_time
+        SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+               ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `
         FROM user_schedules us
-        JOIN shifts s ON us.shift_id = s.id
+        CROSS JOIN LATERAL schedule_shift(us.shift_id, us.date) s
         WHERE us.user_id = $1 AND us.date >= $2 AND us.date <= $3
         ORDER BY us.date ASC -- ORDER BY penting
         LIMIT $4 OFFSET $5`
 
-	rows, err := r.db.Query(ctx, query, userID, startDate, endDate, limit, offset)
+	rows, err := r.read.Query(ctx, query, userID, startDate, endDate, limit, offset)
 	if err != nil {
 		err = fmt.Errorf("error getting paginated schedules for user %d: %w", userID, err)
 		return
@@ -140,29 +161,13 @@ func (r *scheduleRepo) GetSchedulesByUser(ctx context.Context, userID int, start
 	for rows.Next() {
 		var schedule models.UserSchedule
 		schedule.Shift = &models.Shift{} // Init nested struct
-		var scheduleDate time.Time
-		var startTime, endTime string
-
-		scanErr := rows.Scan(
-			&schedule.ID,
-			&schedule.UserID,
-			&schedule.ShiftID,
-			&scheduleDate,
-			&schedule.CreatedAt,
-			&schedule.Shift.ID,
-			&schedule.Shift.Name,
-			&startTime,
-			&endTime,
-		)
+		scanErr := shared.ScanSchedule(rows, &schedule, shared.ShiftSummaryDest(schedule.Shift)...)
 		if scanErr != nil {
-			zlog.Warn().Err(scanErr).Int("user_id", userID).Msg("Error scanning user schedule row (paginated)")
+			shared.Logger(ctx).Warn().Err(scanErr).Int("user_id", userID).Msg("Error scanning user schedule row (paginated)")
 			// Mungkin return error di sini
 			err = fmt.Errorf("error scanning schedule row: %w", scanErr)
 			return
 		}
-		schedule.Date = scheduleDate.Format(dateLayout)
-		schedule.Shift.StartTime = startTime
-		schedule.Shift.EndTime = endTime
 		schedules = append(schedules, schedule)
 	}
 
@@ -173,12 +178,82 @@ func (r *scheduleRepo) GetSchedulesByUser(ctx context.Context, userID int, start
 	return // schedules, totalCount, nil error implicitly returned
 }
 
+// GetSchedulesByUserWithAttendance sama seperti GetSchedulesByUser, tetapi setiap jadwal dilengkapi
+// absensinya (lewat LEFT JOIN) dan AttendanceStatus present/missing. Absensi dicocokkan lewat
+// attendances.schedule_id; absensi tanpa tautan jadwal dicocokkan dengan tanggal check-in.
+func (r *scheduleRepo) GetSchedulesByUserWithAttendance(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) (schedules []models.UserSchedule, totalCount int, err error) {
+	countQuery := `SELECT COUNT(*) FROM user_schedules WHERE user_id = $1 AND date >= $2 AND date <= $3`
+	if err = r.read.QueryRow(ctx, countQuery, userID, startDate, endDate).Scan(&totalCount); err != nil {
+		err = fmt.Errorf("error counting schedules for user %d: %w", userID, err)
+		return
+	}
+	schedules = []models.UserSchedule{}
+	if totalCount == 0 {
+		return
+	}
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := `
+        SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+               ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `,
+               ` + shared.SelectList("a", shared.AttendanceColumns) + `
+        FROM user_schedules us
+        CROSS JOIN LATERAL schedule_shift(us.shift_id, us.date) s
+        LEFT JOIN LATERAL (
+            SELECT * FROM attendances att
+            WHERE att.schedule_id = us.id
+               OR (att.schedule_id IS NULL AND att.user_id = us.user_id AND att.check_in_at::date = us.date)
+            ORDER BY att.schedule_id IS NULL, att.check_in_at ASC
+            LIMIT 1
+        ) a ON TRUE
+        WHERE us.user_id = $1 AND us.date >= $2 AND us.date <= $3
+        ORDER BY us.date ASC
+        LIMIT $4 OFFSET $5`
+
+	rows, err := r.read.Query(ctx, query, userID, startDate, endDate, limit, offset)
+	if err != nil {
+		err = fmt.Errorf("error getting schedules with attendance for user %d: %w", userID, err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schedule models.UserSchedule
+		schedule.Shift = &models.Shift{}
+		// Kolom absensi NULL jika tidak ada check-in pada tanggal jadwal.
+		var attID, attUserID *int
+		var checkInAt, createdAt, updatedAt *time.Time
+		var mode *string
+		att := &models.Attendance{}
+		dest := append(shared.ShiftSummaryDest(schedule.Shift), &attID, &attUserID, &att.ScheduleID, &att.ProjectID, &checkInAt, &att.CheckOutAt, &att.Notes, &createdAt, &updatedAt, &mode)
+		if scanErr := shared.ScanSchedule(rows, &schedule, dest...); scanErr != nil {
+			err = fmt.Errorf("error scanning schedule with attendance row: %w", scanErr)
+			return
+		}
+		schedule.AttendanceStatus = models.AttendanceStatusMissing
+		if attID != nil {
+			att.ID, att.UserID, att.CheckInAt, att.CreatedAt, att.UpdatedAt, att.Mode = *attID, *attUserID, *checkInAt, *createdAt, *updatedAt, *mode
+			schedule.Attendance = att
+			schedule.AttendanceStatus = models.AttendanceStatusPresent
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("error iterating schedule with attendance rows: %w", err)
+		return
+	}
+	return
+}
+
 // Tambahkan fungsi lain jika perlu (misal: GetSchedulesByDateRangeForAllUsers, UpdateSchedule, DeleteSchedule)
 
 func (r *scheduleRepo) GetSchedulesByDateRangeForAllUsers(ctx context.Context, startDate, endDate time.Time, page, limit int) (schedules []models.UserSchedule, totalCount int, err error) {
 	// 1. Count Total
 	countQuery := `SELECT COUNT(*) FROM user_schedules WHERE date >= $1 AND date <= $2`
-	err = r.db.QueryRow(ctx, countQuery, startDate, endDate).Scan(&totalCount)
+	err = r.read.QueryRow(ctx, countQuery, startDate, endDate).Scan(&totalCount)
 	if err != nil {
 		err = fmt.Errorf("error counting all schedules: %w", err)
 		return
@@ -196,17 +271,17 @@ func (r *scheduleRepo) GetSchedulesByDateRangeForAllUsers(ctx context.Context, s
 
 	// 3. Query Data
 	query := `
-		SELECT us.id, us.user_id, us.shift_id, us.date, us.created_at,
-		       s.id as shiftid, s.name as shiftname, s.start_time, s.end_time,
-               u.id as userid, u.username, u.email, u.first_name, u.last_name -- Tambahkan info user jika perlu di response ini
+		SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+		       ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `,
+		       ` + shared.SelectList("u", userSummaryColumns) + `
 		FROM user_schedules us
-		JOIN shifts s ON us.shift_id = s.id
+		CROSS JOIN LATERAL schedule_shift(us.shift_id, us.date) s
         JOIN users u ON us.user_id = u.id -- JOIN users
 		WHERE us.date >= $1 AND us.date <= $2
 		ORDER BY us.date ASC, u.username ASC -- ORDER BY penting
         LIMIT $3 OFFSET $4`
 
-	rows, err := r.db.Query(ctx, query, startDate, endDate, limit, offset)
+	rows, err := r.read.Query(ctx, query, startDate, endDate, limit, offset)
 	if err != nil {
 		err = fmt.Errorf("error getting paginated all schedules: %w", err)
 		return
@@ -219,32 +294,15 @@ func (r *scheduleRepo) GetSchedulesByDateRangeForAllUsers(ctx context.Context, s
 		var schedule models.UserSchedule
 		schedule.Shift = &models.Shift{} // Init nested struct
 		schedule.User = &models.User{}
-		var scheduleDate time.Time
-		var startTime, endTime string
-		scanErr := rows.Scan(
-			&schedule.ID, 
-			&schedule.UserID, 
-			&schedule.ShiftID, 
-			&scheduleDate, 
-			&schedule.CreatedAt,
-			&schedule.Shift.ID, 
-			&schedule.Shift.Name, 
-			&startTime, 
-			&endTime,
-			&schedule.User.ID, 
-			&schedule.User.Username, // Scan field user
-			&schedule.User.Email,
-			&schedule.User.FirstName,
-			&schedule.User.LastName,
-		)
+		scanErr := shared.ScanSchedule(rows, &schedule, append(shared.ShiftSummaryDest(schedule.Shift), shared.UserSummaryDest(schedule.User)...)...)
 		if scanErr != nil {
-			zlog.Warn().Err(scanErr).Msg("Error scanning all schedules row (paginated)")
+			shared.Logger(ctx).Warn().Err(scanErr).Msg("Error scanning all schedules row (paginated)")
 			err = fmt.Errorf("error scanning schedule row: %w", scanErr)
 			return
 		}
-		schedule.Date = scheduleDate.Format(dateLayout)
-		schedule.Shift.StartTime = startTime
-		schedule.Shift.EndTime = endTime
+		if err = shared.DecryptUserPII(r.pii, schedule.User); err != nil {
+			return
+		}
 		schedules = append(schedules, schedule)
 	}
 	if err = rows.Err(); err != nil {
@@ -256,9 +314,9 @@ func (r *scheduleRepo) GetSchedulesByDateRangeForAllUsers(ctx context.Context, s
 
 func (r *scheduleRepo) DeleteSchedule(ctx context.Context, id int) error {
 	query := "DELETE FROM user_schedules WHERE id = $1"
-	tag, err := r.db.Exec(ctx, query, id)
+	tag, err := conn(ctx, r.db).Exec(ctx, query, id)
 	if err != nil {
-		zlog.Error().Err(err).Int("schedule_id", id).Msg("Error deleting schedule")
+		shared.Logger(ctx).Error().Err(err).Int("schedule_id", id).Msg("Error deleting schedule")
 		return fmt.Errorf("error deleting schedule %d: %w", id, err)
 	}
 	if tag.RowsAffected() == 0 {
@@ -267,38 +325,257 @@ func (r *scheduleRepo) DeleteSchedule(ctx context.Context, id int) error {
 	return nil
 }
 
+// UpdateSchedule mengganti jadwal by ID.
+// Mengembalikan *ScheduleConflictError jika jam shift tumpang tindih dengan jadwal user yang lain.
 func (r *scheduleRepo) UpdateSchedule(ctx context.Context, schedule *models.UserSchedule) error {
 	// --- Validasi tanggal sebelum query (jika formatnya string) ---
-	scheduleDate, err := time.Parse(dateLayout, schedule.Date)
+	scheduleDate, err := time.Parse(shared.DateLayout, schedule.Date)
 	if err != nil {
 		// Kembalikan error format tanggal yang jelas
 		return fmt.Errorf("invalid date format for schedule update, use YYYY-MM-DD: %w", err)
 	}
 	// --- Akhir Validasi Tanggal ---
 
-	query := `UPDATE user_schedules SET user_id = $1, shift_id = $2, date = $3 WHERE id = $4`
-	// --- TAMBAHKAN tag ---
-	tag, err := r.db.Exec(ctx, query, schedule.UserID, schedule.ShiftID, scheduleDate, schedule.ID) // Gunakan scheduleDate
-	// --- AKHIR TAMBAHKAN ---
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("error starting schedule transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	if err := lockUserSchedules(ctx, tx, schedule.UserID); err != nil {
+		return err
+	}
+	if err := checkScheduleOverlap(ctx, tx, schedule.UserID, schedule.ShiftID, scheduleDate, schedule.ID); err != nil {
+		return err
+	}
+
+	// Optimistic locking: jika schedule.Version > 0, hanya update bila versi di DB sama
+	query := `UPDATE user_schedules SET user_id = $1, shift_id = $2, date = $3
+              WHERE id = $4 AND ($5::int IS NULL OR version = $5)
+              RETURNING version` // version dinaikkan trigger
+	err = tx.QueryRow(ctx, query, schedule.UserID, schedule.ShiftID, scheduleDate, schedule.ID, shared.ExpectedVersion(schedule.Version)).Scan(&schedule.Version) // Gunakan scheduleDate
 	if err != nil {
+		// Tidak ada baris terupdate: schedule tidak ditemukan atau versi usang
+		if errors.Is(err, pgx.ErrNoRows) {
+			return resolveMissedUpdate(ctx, r.db, "user_schedules", schedule.ID)
+		}
 		// Handle unique constraint (user_id, date)
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
-			zlog.Warn().Err(err).Int("schedule_id", schedule.ID).Int("user_id", schedule.UserID).Str("date", schedule.Date).Msg("Unique constraint violation on schedule update")
+			shared.Logger(ctx).Warn().Err(err).Int("schedule_id", schedule.ID).Int("user_id", schedule.UserID).Str("date", schedule.Date).Msg("Unique constraint violation on schedule update")
 			return fmt.Errorf("user %d already has a schedule on %s", schedule.UserID, schedule.Date)
 		}
 		// Handle foreign key constraint (user_id atau shift_id tidak valid)
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
-			zlog.Warn().Err(err).Int("schedule_id", schedule.ID).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Msg("Foreign key violation on schedule update")
+			shared.Logger(ctx).Warn().Err(err).Int("schedule_id", schedule.ID).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Msg("Foreign key violation on schedule update")
 			return fmt.Errorf("invalid user_id (%d) or shift_id (%d)", schedule.UserID, schedule.ShiftID)
 		}
 		// Error umum
-		zlog.Error().Err(err).Int("schedule_id", schedule.ID).Msg("Error updating schedule")
+		shared.Logger(ctx).Error().Err(err).Int("schedule_id", schedule.ID).Msg("Error updating schedule")
 		return fmt.Errorf("error updating schedule %d: %w", schedule.ID, err)
 	}
-	// --- TAMBAHKAN CEK RowsAffected ---
-	if tag.RowsAffected() == 0 {
-		return pgx.ErrNoRows // Schedule tidak ditemukan
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing schedule update: %w", err)
 	}
-	// --- AKHIR TAMBAHAN ---
 	return nil
 }
+
+// ExportSchedulesByUser mengambil seluruh jadwal milik user (tanpa filter tanggal & pagination),
+// dipakai untuk ekspor data pribadi (GET /user/data-export).
+func (r *scheduleRepo) ExportSchedulesByUser(ctx context.Context, userID int) ([]models.UserSchedule, error) {
+	query := `
+        SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+               ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `
+        FROM user_schedules us
+        CROSS JOIN LATERAL schedule_shift(us.shift_id, us.date) s
+        WHERE us.user_id = $1
+        ORDER BY us.date ASC`
+
+	rows, err := r.read.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting schedules for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	schedules := []models.UserSchedule{}
+	for rows.Next() {
+		var schedule models.UserSchedule
+		schedule.Shift = &models.Shift{}
+		if err := shared.ScanSchedule(rows, &schedule, shared.ShiftSummaryDest(schedule.Shift)...); err != nil {
+			return nil, fmt.Errorf("error scanning exported schedule row: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating exported schedule rows: %w", err)
+	}
+	return schedules, nil
+}
+
+// PatchSchedule memperbarui hanya field jadwal yang dikirim (non-nil).
+// Mengembalikan versi baru record, atau *ScheduleConflictError jika hasilnya tumpang tindih
+// dengan jadwal user yang lain.
+func (r *scheduleRepo) PatchSchedule(ctx context.Context, id int, input *models.PatchScheduleInput) (int, error) {
+	var b shared.SetBuilder
+	var patchDate time.Time
+	if input.UserID != nil {
+		b.Add("user_id", *input.UserID)
+	}
+	if input.ShiftID != nil {
+		b.Add("shift_id", *input.ShiftID)
+	}
+	if input.Date != nil {
+		scheduleDate, err := time.Parse(shared.DateLayout, *input.Date)
+		if err != nil {
+			return 0, fmt.Errorf("invalid date format for schedule update, use YYYY-MM-DD: %w", err)
+		}
+		b.Add("date", scheduleDate)
+		patchDate = scheduleDate
+	}
+	if b.Empty() {
+		return 0, ErrNoFieldsToUpdate
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("error starting schedule transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	// Gabungkan field yang dikirim dengan nilai saat ini untuk pengecekan tumpang tindih.
+	var userID, shiftID int
+	var date time.Time
+	err = tx.QueryRow(ctx, `SELECT user_id, shift_id, date FROM user_schedules WHERE id = $1`, id).Scan(&userID, &shiftID, &date)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("error loading schedule %d: %w", id, err)
+	}
+	// Jadwal yang tidak ada dilaporkan oleh UPDATE di bawah (pgx.ErrNoRows).
+	if err == nil {
+		if input.UserID != nil {
+			userID = *input.UserID
+		}
+		if input.ShiftID != nil {
+			shiftID = *input.ShiftID
+		}
+		if input.Date != nil {
+			date = patchDate
+		}
+		if err := lockUserSchedules(ctx, tx, userID); err != nil {
+			return 0, err
+		}
+		if err := checkScheduleOverlap(ctx, tx, userID, shiftID, date, id); err != nil {
+			return 0, err
+		}
+	}
+
+	idArg := b.Arg(id)
+	versionArg := b.Arg(shared.ExpectedVersion(input.Version))
+	query := fmt.Sprintf(`UPDATE user_schedules SET %s WHERE id = %s AND (%s::int IS NULL OR version = %s) RETURNING version`,
+		b.Clause(), idArg, versionArg, versionArg) // version dinaikkan trigger
+
+	var version int
+	err = tx.QueryRow(ctx, query, b.Args()...).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, resolveMissedUpdate(ctx, r.db, "user_schedules", id)
+		}
+		// Handle unique constraint (user_id, date)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			shared.Logger(ctx).Warn().Err(err).Int("schedule_id", id).Msg("Unique constraint violation on schedule patch")
+			return 0, fmt.Errorf("user already has a schedule on that date")
+		}
+		// Handle foreign key constraint (user_id atau shift_id tidak valid)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			shared.Logger(ctx).Warn().Err(err).Int("schedule_id", id).Msg("Foreign key violation on schedule patch")
+			return 0, fmt.Errorf("invalid user_id or shift_id")
+		}
+		shared.Logger(ctx).Error().Err(err).Int("schedule_id", id).Msg("Error patching schedule")
+		return 0, fmt.Errorf("error patching schedule %d: %w", id, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error committing schedule patch: %w", err)
+	}
+	return version, nil
+}
+
+// BulkDeleteSchedules menghapus banyak jadwal dalam satu transaksi.
+// Mode ID: ids berisi schedule ID (ID yang tidak ada dilaporkan di NotFoundIDs).
+// Mode filter: ids kosong, jadwal dipilih berdasarkan rentang tanggal [startDate, endDate]
+// dan (opsional) userIDs. Jadwal yang sudah memiliki absensi pada tanggalnya tetap disimpan
+// agar riwayat kerja tidak kehilangan konteks jadwalnya.
+func (r *scheduleRepo) BulkDeleteSchedules(ctx context.Context, ids []int, userIDs []int, startDate, endDate *time.Time) (*models.BulkDeleteSchedulesResult, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("error starting bulk schedule delete transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	// --- 1. Kumpulkan & kunci kandidat ---
+	selectQuery := `
+        SELECT us.id,
+               EXISTS (SELECT 1 FROM attendances a
+                       WHERE a.user_id = us.user_id AND a.check_in_at::date = us.date) AS has_attendance
+        FROM user_schedules us
+        WHERE %s
+        ORDER BY us.id
+        FOR UPDATE OF us`
+	var rows pgx.Rows
+	if len(ids) > 0 {
+		rows, err = tx.Query(ctx, fmt.Sprintf(selectQuery, `us.id = ANY($1)`), ids)
+	} else {
+		var userFilter []int // nil -> NULL -> semua user
+		if len(userIDs) > 0 {
+			userFilter = userIDs
+		}
+		rows, err = tx.Query(ctx, fmt.Sprintf(selectQuery, `us.date >= $1 AND us.date <= $2 AND ($3::int[] IS NULL OR us.user_id = ANY($3))`),
+			startDate, endDate, userFilter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error selecting schedules for bulk delete: %w", err)
+	}
+
+	result := &models.BulkDeleteSchedulesResult{DeletedIDs: []int{}, Kept: []models.BulkItemResult{}}
+	found := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		var hasAttendance bool
+		if err := rows.Scan(&id, &hasAttendance); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning schedule for bulk delete: %w", err)
+		}
+		found[id] = true
+		if hasAttendance {
+			result.Kept = append(result.Kept, models.BulkItemResult{
+				ID: id, Status: models.BulkStatusSkipped, Message: "attendance already recorded for this schedule",
+			})
+			continue
+		}
+		result.DeletedIDs = append(result.DeletedIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schedules for bulk delete: %w", err)
+	}
+
+	// --- 2. Hapus yang boleh dihapus ---
+	if len(result.DeletedIDs) > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM user_schedules WHERE id = ANY($1)`, result.DeletedIDs); err != nil {
+			shared.Logger(ctx).Error().Err(err).Int("count", len(result.DeletedIDs)).Msg("Error bulk deleting schedules")
+			return nil, fmt.Errorf("error bulk deleting schedules: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing bulk schedule delete: %w", err)
+	}
+
+	// --- 3. Ringkasan ---
+	for _, id := range ids {
+		if !found[id] {
+			result.NotFoundIDs = append(result.NotFoundIDs, id)
+			found[id] = true // Hindari duplikat
+		}
+	}
+	result.DeletedCount = len(result.DeletedIDs)
+	result.KeptCount = len(result.Kept)
+	shared.Logger(ctx).Info().Int("deleted", result.DeletedCount).Int("kept", result.KeptCount).Msg("Bulk schedule delete committed")
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type settingsRepo struct {
+	db Conn // Selalu Primary: nilai pengaturan harus langsung konsisten setelah diubah
+}
+
+func NewSettingsRepository(pools Pools) SettingsRepository {
+	return &settingsRepo{db: pools.Primary}
+}
+
+func (r *settingsRepo) GetAllSettings(ctx context.Context) ([]models.Setting, error) {
+	query := `SELECT key, value, updated_by, updated_at FROM settings ORDER BY key`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error getting settings")
+		return nil, fmt.Errorf("error getting settings: %w", err)
+	}
+	defer rows.Close()
+
+	settings := []models.Setting{}
+	for rows.Next() {
+		var s models.Setting
+		if err := rows.Scan(&s.Key, &s.Value, &s.UpdatedBy, &s.UpdatedAt); err != nil {
+			shared.Logger(ctx).Error().Err(err).Msg("Error scanning setting row")
+			return nil, fmt.Errorf("error scanning setting row: %w", err)
+		}
+		settings = append(settings, s)
+	}
+	if err := rows.Err(); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error iterating setting rows")
+		return nil, fmt.Errorf("error iterating setting rows: %w", err)
+	}
+	return settings, nil
+}
+
+func (r *settingsRepo) UpsertSettings(ctx context.Context, values map[string]string, actorUserID int) error {
+	query := `
+		INSERT INTO settings (key, value, updated_by, updated_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+		ON CONFLICT (key) DO UPDATE
+		SET value = EXCLUDED.value, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`
+
+	// Semua key disimpan dalam satu transaksi: perubahan diterapkan utuh atau tidak sama sekali.
+	batch := &pgx.Batch{}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		batch.Queue(query, key, values[key], actorUserID)
+	}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("actor_user_id", actorUserID).Msg("Error saving settings")
+		return fmt.Errorf("error saving settings: %w", err)
+	}
+	return nil
+}
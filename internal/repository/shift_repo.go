@@ -2,22 +2,23 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time" // Digunakan untuk parsing/formatting jika perlu, meskipun DB type TIME
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn" // Untuk cek error code
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rakaarfi/attendance-system-be/internal/models"
-	zlog "github.com/rs/zerolog/log"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
 )
 
 type shiftRepo struct {
-	db *pgxpool.Pool
+	db   Conn // Primary: tulis & baca konsisten
+	read Conn // Replica (atau Primary jika tidak ada) untuk laporan/listing
 }
 
-func NewShiftRepository(db *pgxpool.Pool) ShiftRepository {
-	return &shiftRepo{db: db}
+func NewShiftRepository(pools Pools) ShiftRepository {
+	return &shiftRepo{db: pools.Primary, read: pools.reader()}
 }
 
 // CreateShift adds a new shift definition
@@ -29,52 +30,40 @@ func (r *shiftRepo) CreateShift(ctx context.Context, shift *models.Shift) (int,
 	_, errStart := time.Parse("15:04:05", shift.StartTime)
 	_, errEnd := time.Parse("15:04:05", shift.EndTime)
 	if errStart != nil || errEnd != nil {
-		zlog.Warn().Err(errStart).Err(errEnd).Msg("Invalid time format, use HH:MM:SS")
+		shared.Logger(ctx).Warn().Err(errStart).Err(errEnd).Msg("Invalid time format, use HH:MM:SS")
 		return 0, fmt.Errorf("invalid time format, use HH:MM:SS")
 	}
 
 	err := r.db.QueryRow(ctx, query, shift.Name, shift.StartTime, shift.EndTime).Scan(&shiftID)
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error creating shift")
+		shared.Logger(ctx).Error().Err(err).Msg("Error creating shift")
 		return 0, fmt.Errorf("error creating shift: %w", err)
 	}
-	zlog.Info().Int("shift_id", shiftID).Msg("Shift created successfully")
+	shared.Logger(ctx).Info().Int("shift_id", shiftID).Msg("Shift created successfully")
 	return shiftID, nil
 }
 
 // GetShiftByID retrieves a shift by its ID
 func (r *shiftRepo) GetShiftByID(ctx context.Context, id int) (*models.Shift, error) {
-	query := `SELECT id, name, start_time, end_time, created_at, updated_at FROM shifts WHERE id = $1`
+	query := `SELECT ` + shared.SelectList("s", shared.ShiftColumns) + ` FROM shifts s WHERE s.id = $1`
 	shift := &models.Shift{}
-	var startTime, endTime string // Baca sebagai string dari DB (tipe TIME)
-
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&shift.ID,
-		&shift.Name,
-		&startTime,
-		&endTime,
-		&shift.CreatedAt,
-		&shift.UpdatedAt,
-	)
+	err := shared.ScanShift(r.db.QueryRow(ctx, query, id), shift)
 	if err != nil {
 		// Handle pgx.ErrNoRows
-		zlog.Warn().Err(err).Int("shift_id", id).Msg("Error getting shift by id")
+		shared.Logger(ctx).Warn().Err(err).Int("shift_id", id).Msg("Error getting shift by id")
 		return nil, fmt.Errorf("error getting shift by id %d: %w", id, err)
 	}
-	// Assign string times ke struct
-	shift.StartTime = startTime
-	shift.EndTime = endTime
 
-	zlog.Info().Int("shift_id", id).Msg("Shift retrieved successfully")
+	shared.Logger(ctx).Info().Int("shift_id", id).Msg("Shift retrieved successfully")
 	return shift, nil
 }
 
 // GetAllShifts retrieves all shift definitions
 func (r *shiftRepo) GetAllShifts(ctx context.Context) ([]models.Shift, error) {
-	query := `SELECT id, name, start_time, end_time, created_at, updated_at FROM shifts ORDER BY name`
-	rows, err := r.db.Query(ctx, query)
+	query := `SELECT ` + shared.SelectList("s", shared.ShiftColumns) + ` FROM shifts s ORDER BY s.name`
+	rows, err := r.read.Query(ctx, query)
 	if err != nil {
-		zlog.Error().Err(err).Msg("Error getting all shifts")
+		shared.Logger(ctx).Error().Err(err).Msg("Error getting all shifts")
 		return nil, fmt.Errorf("error getting all shifts: %w", err)
 	}
 	defer rows.Close()
@@ -82,54 +71,48 @@ func (r *shiftRepo) GetAllShifts(ctx context.Context) ([]models.Shift, error) {
 	shifts := []models.Shift{}
 	for rows.Next() {
 		var shift models.Shift
-		var startTime, endTime string
-		if err := rows.Scan(
-			&shift.ID,
-			&shift.Name,
-			&startTime,
-			&endTime,
-			&shift.CreatedAt,
-			&shift.UpdatedAt); err != nil {
-			zlog.Warn().Err(err).Msg("Error scanning shift row") // Log error but continue processing other rows
+		if err := shared.ScanShift(rows, &shift); err != nil {
+			shared.Logger(ctx).Warn().Err(err).Msg("Error scanning shift row") // Log error but continue processing other rows
 			continue
 		}
-		shift.StartTime = startTime
-		shift.EndTime = endTime
 		shifts = append(shifts, shift)
 	}
 
 	if err = rows.Err(); err != nil {
-		zlog.Error().Err(err).Msg("Error iterating shift rows")
+		shared.Logger(ctx).Error().Err(err).Msg("Error iterating shift rows")
 		return nil, fmt.Errorf("error iterating shift rows: %w", err)
 	}
 
-	zlog.Info().Int("record_count", len(shifts)).Msg("Shifts retrieved successfully")
+	shared.Logger(ctx).Info().Int("record_count", len(shifts)).Msg("Shifts retrieved successfully")
 	return shifts, nil
 }
 
-// UpdateShift modifies an existing shift
+// UpdateShift modifies an existing shift.
+// Jika shift.Version > 0, update hanya dilakukan bila versi di DB sama (optimistic locking);
+// selisih versi menghasilkan ErrVersionConflict. Versi baru ditulis kembali ke shift.Version.
 func (r *shiftRepo) UpdateShift(ctx context.Context, shift *models.Shift) error {
 	query := `UPDATE shifts SET name = $1, start_time = $2, end_time = $3, updated_at = CURRENT_TIMESTAMP
-              WHERE id = $4`
+              WHERE id = $4 AND ($5::int IS NULL OR version = $5)
+              RETURNING version` // version dinaikkan trigger
 
 	// Validasi format waktu
 	_, errStart := time.Parse("15:04:05", shift.StartTime)
 	_, errEnd := time.Parse("15:04:05", shift.EndTime)
 	if errStart != nil || errEnd != nil {
-		zlog.Warn().Err(errStart).Err(errEnd).Msg("Invalid time format, use HH:MM:SS")
+		shared.Logger(ctx).Warn().Err(errStart).Err(errEnd).Msg("Invalid time format, use HH:MM:SS")
 		return fmt.Errorf("invalid time format, use HH:MM:SS")
 	}
 
-	tag, err := r.db.Exec(ctx, query, shift.Name, shift.StartTime, shift.EndTime, shift.ID)
+	err := r.db.QueryRow(ctx, query, shift.Name, shift.StartTime, shift.EndTime, shift.ID, shared.ExpectedVersion(shift.Version)).Scan(&shift.Version)
 	if err != nil {
-		zlog.Error().Err(err).Int("shift_id", shift.ID).Msg("Error updating shift")
+		if errors.Is(err, pgx.ErrNoRows) {
+			shared.Logger(ctx).Info().Int("shift_id", shift.ID).Msg("No rows updated")
+			return resolveMissedUpdate(ctx, r.db, "shifts", shift.ID) // ErrNoRows atau ErrVersionConflict
+		}
+		shared.Logger(ctx).Error().Err(err).Int("shift_id", shift.ID).Msg("Error updating shift")
 		return fmt.Errorf("error updating shift id %d: %w", shift.ID, err)
 	}
-	if tag.RowsAffected() == 0 {
-		zlog.Info().Int("shift_id", shift.ID).Msg("No rows updated")
-		return pgx.ErrNoRows // Kembalikan error standar jika tidak ada row yang terupdate
-	}
-	zlog.Info().Int("shift_id", shift.ID).Msg("Shift updated successfully")
+	shared.Logger(ctx).Info().Int("shift_id", shift.ID).Int("version", shift.Version).Msg("Shift updated successfully")
 	return nil
 }
 
@@ -140,16 +123,85 @@ func (r *shiftRepo) DeleteShift(ctx context.Context, id int) error {
 	if err != nil {
 		// Cek foreign key constraint violation (code 23503)
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
-			zlog.Warn().Err(err).Int("shift_id", id).Msg("Cannot delete shift: it is still referenced by user schedules")
+			shared.Logger(ctx).Warn().Err(err).Int("shift_id", id).Msg("Cannot delete shift: it is still referenced by user schedules")
 			return fmt.Errorf("cannot delete shift: it is still referenced by user schedules")
 		}
-		zlog.Error().Err(err).Int("shift_id", id).Msg("Error deleting shift")
+		shared.Logger(ctx).Error().Err(err).Int("shift_id", id).Msg("Error deleting shift")
 		return fmt.Errorf("error deleting shift id %d: %w", id, err)
 	}
 	if tag.RowsAffected() == 0 {
-		zlog.Info().Int("shift_id", id).Msg("No shift deleted")
+		shared.Logger(ctx).Info().Int("shift_id", id).Msg("No shift deleted")
 		return pgx.ErrNoRows // Kembalikan error standar jika tidak ada row yang terhapus
 	}
-	zlog.Info().Int("shift_id", id).Msg("Shift deleted successfully")
+	shared.Logger(ctx).Info().Int("shift_id", id).Msg("Shift deleted successfully")
+	return nil
+}
+
+// CreateShiftOverride menyimpan override shift musiman. Mengembalikan pgx.ErrNoRows jika
+// o.ShiftID merujuk shift yang tidak ada. ID dan CreatedAt ditulis kembali ke o.
+func (r *shiftRepo) CreateShiftOverride(ctx context.Context, o *models.ShiftOverride) (int, error) {
+	query := `INSERT INTO shift_overrides (name, shift_id, start_date, end_date, start_time, end_time,
+                                           start_offset_minutes, end_offset_minutes, created_by)
+              VALUES ($1, $2, $3::date, $4::date, $5::time, $6::time, $7, $8, $9)
+              RETURNING id, created_at`
+	err := r.db.QueryRow(ctx, query, o.Name, o.ShiftID, o.StartDate, o.EndDate, o.StartTime, o.EndTime,
+		o.StartOffsetMinutes, o.EndOffsetMinutes, o.CreatedBy).Scan(&o.ID, &o.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" && pgErr.ConstraintName == "shift_overrides_shift_id_fkey" {
+			shared.Logger(ctx).Warn().Err(err).Interface("shift_id", o.ShiftID).Msg("Shift override references unknown shift")
+			return 0, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Msg("Error creating shift override")
+		return 0, fmt.Errorf("error creating shift override: %w", err)
+	}
+	shared.Logger(ctx).Info().Int("override_id", o.ID).Str("start_date", o.StartDate).Str("end_date", o.EndDate).Msg("Shift override created successfully")
+	return o.ID, nil
+}
+
+// GetShiftOverrides mengembalikan override shift, terbaru (tanggal mulai terakhir) lebih dulu.
+// activeFrom (opsional) menyaring override yang belum berakhir pada tanggal tersebut.
+func (r *shiftRepo) GetShiftOverrides(ctx context.Context, activeFrom *time.Time) ([]models.ShiftOverride, error) {
+	query := `SELECT ` + shared.SelectList("sov", shared.ShiftOverrideColumns) + `
+              FROM shift_overrides sov
+              WHERE $1::date IS NULL OR sov.end_date >= $1::date
+              ORDER BY sov.start_date DESC, sov.id DESC`
+	var from *string
+	if activeFrom != nil {
+		s := activeFrom.Format(shared.DateLayout)
+		from = &s
+	}
+	rows, err := r.read.Query(ctx, query, from)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error getting shift overrides")
+		return nil, fmt.Errorf("error getting shift overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := []models.ShiftOverride{}
+	for rows.Next() {
+		var o models.ShiftOverride
+		if err := shared.ScanShiftOverride(rows, &o); err != nil {
+			return nil, fmt.Errorf("error scanning shift override row: %w", err)
+		}
+		overrides = append(overrides, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shift override rows: %w", err)
+	}
+	return overrides, nil
+}
+
+// DeleteShiftOverride menghapus override; jadwal dalam rentangnya kembali memakai jam shift asli.
+func (r *shiftRepo) DeleteShiftOverride(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM shift_overrides WHERE id = $1`, id)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("override_id", id).Msg("Error deleting shift override")
+		return fmt.Errorf("error deleting shift override id %d: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	shared.Logger(ctx).Info().Int("override_id", id).Msg("Shift override deleted successfully")
 	return nil
 }
@@ -0,0 +1,86 @@
+// internal/repository/sqlite/announcement_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type announcementRepo struct {
+	repository.AnnouncementRepository
+	db *database
+}
+
+// GetActiveAnnouncementsForUser mengembalikan pengumuman yang sedang tayang pada waktu at
+// dan ditujukan ke role user (atau ke semua user).
+func (r *announcementRepo) GetActiveAnnouncementsForUser(ctx context.Context, userID int, at time.Time, page, limit int) ([]models.Announcement, int, error) {
+	where := `
+              WHERE an.publish_at <= $1
+                AND (an.expires_at IS NULL OR an.expires_at > $1)
+                AND (json_array_length(an.audience_role_ids) = 0
+                     OR (SELECT u.role_id FROM users u WHERE u.id = $2) IN (SELECT value FROM json_each(an.audience_role_ids)))`
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM announcements an`+where, at, userID).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error counting active announcements")
+		return nil, 0, fmt.Errorf("error counting active announcements: %w", err)
+	}
+	if total == 0 {
+		return []models.Announcement{}, 0, nil
+	}
+
+	query := `SELECT ` + shared.SelectList("an", shared.AnnouncementColumns) + `
+              FROM announcements an` + where + `
+              ORDER BY an.publish_at DESC, an.id DESC
+              LIMIT $3 OFFSET $4`
+	announcements, err := r.queryAnnouncements(ctx, query, at, userID, limit, shared.PageOffset(page, limit))
+	return announcements, total, err
+}
+
+func (r *announcementRepo) queryAnnouncements(ctx context.Context, query string, args ...any) ([]models.Announcement, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying announcements")
+		return nil, fmt.Errorf("error querying announcements: %w", err)
+	}
+	defer rows.Close()
+
+	announcements := []models.Announcement{}
+	for rows.Next() {
+		var a models.Announcement
+		if err := shared.ScanAnnouncement(rows, &a); err != nil {
+			shared.Logger(ctx).Error().Err(err).Msg("Error scanning announcement row")
+			return nil, fmt.Errorf("error scanning announcement row: %w", err)
+		}
+		announcements = append(announcements, a)
+	}
+	if err := rows.Err(); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error iterating announcement rows")
+		return nil, fmt.Errorf("error iterating announcement rows: %w", err)
+	}
+	return announcements, nil
+}
+
+func (r *announcementRepo) UpdateAnnouncement(ctx context.Context, a *models.Announcement) error {
+	query := `UPDATE announcements
+              SET title = $1, body = $2, publish_at = $3, expires_at = $4, audience_role_ids = $5, updated_at = NOW()
+              WHERE id = $6`
+	tag, err := r.db.Exec(ctx, query, a.Title, a.Body, a.PublishAt, a.ExpiresAt, shared.AudienceRoleIDs(a.AudienceRoleIDs), a.ID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("announcement_id", a.ID).Msg("Error updating announcement")
+		return fmt.Errorf("error updating announcement id %d: %w", a.ID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
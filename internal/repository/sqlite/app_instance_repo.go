@@ -0,0 +1,33 @@
+// internal/repository/sqlite/app_instance_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type appInstanceRepo struct {
+	repository.AppInstanceRepository
+	db *database
+}
+
+// UpsertAppInstance mencatat heartbeat instance; started_at hanya diisi saat pertama kali.
+func (r *appInstanceRepo) UpsertAppInstance(ctx context.Context, instance *models.AppInstance) error {
+	query := `INSERT INTO app_instances AS i (instance_id, app_version, hostname)
+              VALUES ($1, $2, $3)
+              ON CONFLICT (instance_id) DO UPDATE
+              SET app_version = EXCLUDED.app_version, hostname = EXCLUDED.hostname, last_seen_at = NOW()
+              RETURNING ` + returningList(shared.AppInstanceColumns)
+	if err := shared.ScanAppInstance(r.db.QueryRow(ctx, query, instance.InstanceID, instance.AppVersion, instance.Hostname), instance); err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("instance_id", instance.InstanceID).Msg("Error recording app instance heartbeat")
+		return fmt.Errorf("error recording heartbeat of instance %s: %w", instance.InstanceID, err)
+	}
+	return nil
+}
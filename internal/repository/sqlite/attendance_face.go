@@ -0,0 +1,87 @@
+// internal/repository/sqlite/attendance_face.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Hasil verifikasi wajah check-in (attendance_face_checks), satu baris per record absensi.
+// Baris dengan review_status 'pending' adalah punch yang menunggu tinjauan admin.
+
+// GetFaceChecks mengembalikan hasil verifikasi wajah (terbaru lebih dulu) beserta user dan jam
+// check-in; reviewStatus kosong = semua status.
+func (r *attendanceRepo) GetFaceChecks(ctx context.Context, reviewStatus string, page, limit int) (checks []models.FaceCheck, totalCount int, err error) {
+	countQuery := `SELECT COUNT(*) FROM attendance_face_checks fc WHERE $1 = '' OR fc.review_status = $1`
+	if err = r.db.QueryRow(ctx, countQuery, reviewStatus).Scan(&totalCount); err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("review_status", reviewStatus).Msg("Error counting face checks")
+		err = fmt.Errorf("error counting face checks: %w", err)
+		return
+	}
+	checks = []models.FaceCheck{}
+	if totalCount == 0 {
+		return
+	}
+
+	query := `
+        SELECT ` + shared.SelectList("fc", faceCheckColumns) + `, a.user_id, a.check_in_at,
+               ` + shared.SelectList("u", userSummaryColumns) + `
+        FROM attendance_face_checks fc
+        JOIN attendances a ON a.id = fc.attendance_id
+        JOIN users u ON u.id = a.user_id
+        WHERE $1 = '' OR fc.review_status = $1
+        ORDER BY fc.created_at DESC, fc.attendance_id DESC
+        LIMIT $2 OFFSET $3`
+	rows, err := r.db.Query(ctx, query, reviewStatus, limit, max(page-1, 0)*limit)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying face checks")
+		err = fmt.Errorf("error getting face checks: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		fc := models.FaceCheck{User: &models.User{}}
+		dest := append(shared.FaceCheckDest(&fc), &fc.UserID, &fc.CheckInAt)
+		if err = rows.Scan(append(dest, shared.UserSummaryDest(fc.User)...)...); err != nil {
+			err = fmt.Errorf("error scanning face check row: %w", err)
+			return
+		}
+		if err = shared.DecryptUserPII(r.pii, fc.User); err != nil {
+			return
+		}
+		checks = append(checks, fc)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("error iterating face check rows: %w", err)
+	}
+	return
+}
+
+// ReviewFaceCheck menyelesaikan tinjauan punch yang ditandai (status approved/rejected).
+// Mengembalikan pgx.ErrNoRows jika record tidak ada atau tidak sedang menunggu tinjauan.
+func (r *attendanceRepo) ReviewFaceCheck(ctx context.Context, attendanceID int, status string, reviewerID int) (*models.FaceCheck, error) {
+	// RETURNING SQLite tidak bisa merujuk tabel lain, sehingga data absensi diambil lewat subquery.
+	query := `UPDATE attendance_face_checks AS fc
+              SET review_status = $2, reviewed_by = $3, reviewed_at = $4
+              WHERE fc.attendance_id = $1 AND fc.review_status = $5
+              RETURNING ` + returningList(faceCheckColumns) + `,
+                        (SELECT a.user_id FROM attendances a WHERE a.id = attendance_id),
+                        (SELECT a.check_in_at FROM attendances a WHERE a.id = attendance_id)`
+	fc := &models.FaceCheck{}
+	err := r.db.QueryRow(ctx, query, attendanceID, status, reviewerID, time.Now(), models.FaceReviewPending).
+		Scan(append(shared.FaceCheckDest(fc), &fc.UserID, &fc.CheckInAt)...)
+	if err != nil {
+		shared.Logger(ctx).Warn().Err(err).Int("attendance_id", attendanceID).Msg("Error reviewing face check")
+		return nil, fmt.Errorf("error reviewing face check for attendance id %d: %w", attendanceID, err)
+	}
+	shared.Logger(ctx).Info().Int("attendance_id", attendanceID).Str("review_status", status).Int("reviewer_id", reviewerID).Msg("Face check reviewed")
+	return fc, nil
+}
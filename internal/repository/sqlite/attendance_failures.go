@@ -0,0 +1,82 @@
+// internal/repository/sqlite/attendance_failures.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Dead letter check-in tertunda (queued_check_in_failures): punch yang sudah dijawab 202 saat
+// mode degraded/maintenance tetapi gagal dicatat saat dikirim ulang. Baris tanpa resolved_at
+// menunggu tindak lanjut admin.
+
+// GetQueuedCheckInFailures mengembalikan dead letter check-in (terbaru lebih dulu) beserta user.
+// resolved nil = semua; false = yang belum ditindaklanjuti; true = yang sudah.
+func (r *attendanceRepo) GetQueuedCheckInFailures(ctx context.Context, resolved *bool, page, limit int) (failures []models.QueuedCheckInFailure, totalCount int, err error) {
+	filter := `$1 IS NULL OR (f.resolved_at IS NOT NULL) = $1`
+	countQuery := `SELECT COUNT(*) FROM queued_check_in_failures f WHERE ` + filter
+	if err = r.db.QueryRow(ctx, countQuery, resolved).Scan(&totalCount); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting queued check-in failures")
+		err = fmt.Errorf("error counting queued check-in failures: %w", err)
+		return
+	}
+	failures = []models.QueuedCheckInFailure{}
+	if totalCount == 0 {
+		return
+	}
+
+	query := `
+        SELECT ` + shared.SelectList("f", shared.QueuedCheckInFailureColumns) + `,
+               ` + shared.SelectList("u", userSummaryColumns) + `
+        FROM queued_check_in_failures f
+        JOIN users u ON u.id = f.user_id
+        WHERE ` + filter + `
+        ORDER BY f.failed_at DESC, f.id DESC
+        LIMIT $2 OFFSET $3`
+	rows, err := r.db.Query(ctx, query, resolved, limit, max(page-1, 0)*limit)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying queued check-in failures")
+		err = fmt.Errorf("error getting queued check-in failures: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		f := models.QueuedCheckInFailure{User: &models.User{}}
+		if err = rows.Scan(append(shared.QueuedCheckInFailureDest(&f), shared.UserSummaryDest(f.User)...)...); err != nil {
+			err = fmt.Errorf("error scanning queued check-in failure row: %w", err)
+			return
+		}
+		if err = shared.DecryptUserPII(r.pii, f.User); err != nil {
+			return
+		}
+		failures = append(failures, f)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("error iterating queued check-in failure rows: %w", err)
+	}
+	return
+}
+
+// ResolveQueuedCheckInFailure menandai dead letter sudah ditindaklanjuti admin.
+// pgx.ErrNoRows jika tidak ada atau sudah ditandai sebelumnya.
+func (r *attendanceRepo) ResolveQueuedCheckInFailure(ctx context.Context, id, adminID int, note string) (*models.QueuedCheckInFailure, error) {
+	query := `UPDATE queued_check_in_failures
+              SET resolved_by = $2, resolved_at = $3, resolution_note = $4
+              WHERE id = $1 AND resolved_at IS NULL
+              RETURNING ` + returningList(shared.QueuedCheckInFailureColumns)
+	f := &models.QueuedCheckInFailure{}
+	if err := r.db.QueryRow(ctx, query, id, adminID, time.Now(), note).Scan(shared.QueuedCheckInFailureDest(f)...); err != nil {
+		shared.Logger(ctx).Warn().Err(err).Int("failure_id", id).Msg("Error resolving queued check-in failure")
+		return nil, fmt.Errorf("error resolving queued check-in failure id %d: %w", id, err)
+	}
+	shared.Logger(ctx).Info().Int("failure_id", id).Int("admin_id", adminID).Msg("Queued check-in failure resolved")
+	return f, nil
+}
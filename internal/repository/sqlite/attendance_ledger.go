@@ -0,0 +1,40 @@
+// internal/repository/sqlite/attendance_ledger.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Helper ledger absensi (attendance_events) yang dipakai attendanceRepo.
+// Alur setiap perubahan: baca record -> catat event (snapshot lengkap) -> perbarui proyeksi,
+// semuanya di dalam satu transaksi tulis (SQLite menyerialkan penulis, sehingga record tidak
+// berubah di antara pembacaan dan pembaruan). Ledger dijaga append-only oleh trigger database.
+
+// lockAttendance mengambil record absensi di dalam transaksi tulis tx.
+// Mengembalikan pgx.ErrNoRows jika record tidak ada.
+func lockAttendance(ctx context.Context, tx pgx.Tx, attendanceID int) (*models.Attendance, error) {
+	query := `SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + ` FROM attendances a WHERE a.id = $1`
+	att := &models.Attendance{}
+	if err := shared.ScanAttendance(tx.QueryRow(ctx, query, attendanceID), att); err != nil {
+		return nil, err
+	}
+	return att, nil
+}
+
+// projectAttendance menyalin snapshot terbaru ke baris proyeksi di tabel attendances.
+func projectAttendance(ctx context.Context, tx pgx.Tx, att *models.Attendance) error {
+	query := `UPDATE attendances SET check_in_at = $1, check_out_at = $2, notes = $3, updated_at = NOW()
+	          WHERE id = $4 RETURNING updated_at`
+	if err := tx.QueryRow(ctx, query, att.CheckInAt, att.CheckOutAt, att.Notes, att.ID).Scan(&att.UpdatedAt); err != nil {
+		return fmt.Errorf("error projecting attendance id %d: %w", att.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// internal/repository/sqlite/attendance_modes.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Mode kerja absensi (attendances.mode) dan kebijakan per user (users.remote_days &
+// users.field_work_allowed). Pemeriksaan kebijakan saat check-in dilakukan di handler.
+
+// GetModeHours merekap sesi yang sudah check-out per mode kerja untuk sesi dengan check-in
+// dalam [startDate, endDate]. userID (opsional) membatasi ke satu user.
+func (r *attendanceRepo) GetModeHours(ctx context.Context, startDate, endDate time.Time, userID *int) ([]models.ModeHours, error) {
+	query := `
+        SELECT a.mode, COUNT(*), COUNT(DISTINCT a.user_id),
+               ROUND(SUM(` + hoursBetween("a.check_in_at", "a.check_out_at") + `), 2)
+        FROM attendances a
+        WHERE a.check_out_at IS NOT NULL
+          AND a.check_in_at >= $1 AND a.check_in_at <= $2
+          AND ($3 IS NULL OR a.user_id = $3)
+        GROUP BY a.mode
+        ORDER BY a.mode`
+	rows, err := r.db.Query(ctx, query, startDate, endDate, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Time("start", startDate).Time("end", endDate).Msg("Error querying mode hours")
+		return nil, fmt.Errorf("error getting mode hours: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []models.ModeHours{}
+	for rows.Next() {
+		var mh models.ModeHours
+		if err := rows.Scan(&mh.Mode, &mh.Sessions, &mh.Users, &mh.TotalHours); err != nil {
+			return nil, fmt.Errorf("error scanning mode hours row: %w", err)
+		}
+		totals = append(totals, mh)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating mode hours rows: %w", err)
+	}
+	return totals, nil
+}
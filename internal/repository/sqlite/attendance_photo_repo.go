@@ -0,0 +1,71 @@
+// internal/repository/sqlite/attendance_photo_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type attendancePhotoRepo struct {
+	repository.AttendancePhotoRepository
+	db *database
+}
+
+// CreateAttendancePhoto menyimpan upload mentah berstatus pending dan mengisi ID & captured_at.
+func (r *attendancePhotoRepo) CreateAttendancePhoto(ctx context.Context, photo *models.AttendancePhoto) error {
+	query := `INSERT INTO attendance_photos AS ap (attendance_id, user_id, status, incoming_key)
+              VALUES ($1, $2, $3, $4)
+              RETURNING ` + returningList(shared.AttendancePhotoColumns)
+	err := shared.ScanAttendancePhoto(r.db.QueryRow(ctx, query, photo.AttendanceID, photo.UserID, models.AttendancePhotoPending, photo.IncomingKey), photo)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", photo.AttendanceID).Msg("Error creating attendance photo")
+		return fmt.Errorf("error creating photo for attendance %d: %w", photo.AttendanceID, err)
+	}
+	return nil
+}
+
+// MarkAttendancePhotoProcessed mencatat hasil proses dan mengosongkan incoming_key.
+func (r *attendancePhotoRepo) MarkAttendancePhotoProcessed(ctx context.Context, id int, originalKey, thumbnailKey string, width, height int) error {
+	query := `UPDATE attendance_photos
+              SET status = $2, original_key = $3, thumbnail_key = $4, width = $5, height = $6,
+                  incoming_key = NULL, error = NULL, processed_at = NOW()
+              WHERE id = $1`
+	return r.update(ctx, id, "processed", query, id, models.AttendancePhotoProcessed, originalKey, thumbnailKey, width, height)
+}
+
+// MarkAttendancePhotoFailed mencatat kegagalan proses dan mengosongkan incoming_key.
+func (r *attendancePhotoRepo) MarkAttendancePhotoFailed(ctx context.Context, id int, reason string) error {
+	query := `UPDATE attendance_photos
+              SET status = $2, error = $3, incoming_key = NULL, processed_at = NOW()
+              WHERE id = $1`
+	return r.update(ctx, id, "failed", query, id, models.AttendancePhotoFailed, reason)
+}
+
+// MarkAttendancePhotoPurged mencatat bahwa file foto sudah dihapus dan mengosongkan semua key.
+func (r *attendancePhotoRepo) MarkAttendancePhotoPurged(ctx context.Context, id int) error {
+	query := `UPDATE attendance_photos
+              SET status = $2, incoming_key = NULL, original_key = NULL, thumbnail_key = NULL,
+                  purged_at = NOW()
+              WHERE id = $1`
+	return r.update(ctx, id, "purged", query, id, models.AttendancePhotoPurged)
+}
+
+func (r *attendancePhotoRepo) update(ctx context.Context, id int, status, query string, args ...any) error {
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("photo_id", id).Str("status", status).Msg("Error updating attendance photo")
+		return fmt.Errorf("error marking attendance photo %d %s: %w", id, status, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// internal/repository/sqlite/attendance_pings.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Ping lokasi berkala sesi field (attendance_pings). Koordinat disimpan sebagai mikroderajat
+// (lat_e6/lon_e6) dan dikonversi kembali ke derajat saat dibaca.
+
+// RecordAttendancePing menyimpan ping lokasi untuk sesi terbuka milik userID. Pemeriksaan dan
+// insert berada dalam satu transaksi tulis, sehingga dua ping bersamaan tidak melewati
+// pemeriksaan jeda minimum. pgx.ErrNoRows jika sesi
+// tidak ada atau milik user lain; repository.ErrNotCheckedIn jika sesi sudah check-out.
+func (r *attendanceRepo) RecordAttendancePing(ctx context.Context, userID int, ping *models.AttendancePing, minInterval time.Duration) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting ping transaction for attendance id %d: %w", ping.AttendanceID, err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	var ownerID int
+	var checkInAt time.Time
+	var checkOutAt *time.Time
+	var mode string
+	query := `SELECT user_id, check_in_at, check_out_at, mode FROM attendances WHERE id = $1`
+	if err := tx.QueryRow(ctx, query, ping.AttendanceID).Scan(&ownerID, &checkInAt, &checkOutAt, &mode); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		return fmt.Errorf("error loading attendance id %d for ping: %w", ping.AttendanceID, err)
+	}
+	switch {
+	case ownerID != userID:
+		return pgx.ErrNoRows
+	case checkOutAt != nil:
+		return repository.ErrNotCheckedIn
+	case mode != models.AttendanceModeField:
+		return repository.ErrNotFieldSession
+	case ping.RecordedAt.Before(checkInAt):
+		return repository.ErrPingOutsideSession
+	}
+
+	var count int
+	var tooSoon bool
+	query = `SELECT COUNT(*),
+                    COALESCE(MAX(recorded_at > $2 AND recorded_at < $3), FALSE)
+             FROM attendance_pings WHERE attendance_id = $1`
+	err = tx.QueryRow(ctx, query, ping.AttendanceID, ping.RecordedAt.Add(-minInterval), ping.RecordedAt.Add(minInterval)).Scan(&count, &tooSoon)
+	if err != nil {
+		return fmt.Errorf("error checking pings of attendance id %d: %w", ping.AttendanceID, err)
+	}
+	if tooSoon {
+		return repository.ErrPingTooSoon
+	}
+	if count >= shared.MaxPingsPerSession {
+		return repository.ErrRouteLimitReached
+	}
+
+	query = `INSERT INTO attendance_pings (attendance_id, recorded_at, lat_e6, lon_e6, accuracy_m) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.Exec(ctx, query, ping.AttendanceID, ping.RecordedAt, shared.ToMicrodegrees(ping.Latitude), shared.ToMicrodegrees(ping.Longitude), ping.AccuracyM); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", ping.AttendanceID).Msg("Error inserting attendance ping")
+		return fmt.Errorf("error inserting ping for attendance id %d: %w", ping.AttendanceID, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing ping for attendance id %d: %w", ping.AttendanceID, err)
+	}
+	return nil
+}
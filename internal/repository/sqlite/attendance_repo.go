@@ -0,0 +1,418 @@
+// internal/repository/sqlite/attendance_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/pii"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// openSessionConstraint adalah unique index parsial uq_attendances_open_session (user_id WHERE
+// check_out_at IS NULL). SQLite melaporkan pelanggarannya dengan daftar kolom, bukan nama index.
+const openSessionConstraint = "attendances.user_id"
+
+type attendanceRepo struct {
+	repository.AttendanceRepository
+	db  *database
+	pii *pii.Protector // Dekripsi email user pada query yang JOIN ke tabel users
+}
+
+// CreateCheckIn records a check-in event.
+// Ditolak dengan *repository.PayrollPeriodClosedError jika tanggal check-in berada di periode payroll yang sudah ditutup,
+// atau *repository.AttendanceSignedOffError jika tanggal tersebut sudah di-sign-off atasan.
+// Record attendances dan event check_in ditulis dalam satu transaksi.
+// scheduleID (opsional) adalah jadwal yang berlaku saat check-in dan disimpan sebagai tautan tetap.
+// projectID (opsional) menandai sesi dengan project; repository.ErrProjectUnavailable jika project tidak ada atau nonaktif.
+// mode adalah mode kerja sesi (models.AttendanceMode*), sudah diperiksa terhadap kebijakan user.
+func (r *attendanceRepo) CreateCheckIn(ctx context.Context, userID int, checkInTime time.Time, notes *string, scheduleID, projectID *int, mode string) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error starting check-in transaction for user %d: %w", userID, err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	if err = checkPayrollPeriodsOpen(ctx, tx, checkInTime); err != nil {
+		return 0, err
+	}
+	if err = checkNotSignedOff(ctx, tx, userID, checkInTime); err != nil {
+		return 0, err
+	}
+
+	if projectID != nil {
+		if err = checkProjectActive(ctx, tx, *projectID); err != nil {
+			return 0, err
+		}
+	}
+
+	query := `INSERT INTO attendances (user_id, check_in_at, notes, schedule_id, project_id, mode) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	var attendanceID int
+	err = tx.QueryRow(ctx, query, userID, checkInTime, notes, scheduleID, projectID, mode).Scan(&attendanceID)
+	if err != nil {
+		// Unique index parsial uq_attendances_open_session: sudah ada sesi terbuka (check-in paralel)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == openSessionConstraint {
+			shared.Logger(ctx).Warn().Int("user_id", userID).Msg("Concurrent check-in rejected by open session constraint")
+			return 0, repository.ErrAlreadyCheckedIn
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Time("check_in_at", checkInTime).Msg("Error creating check-in for user")
+		return 0, fmt.Errorf("error creating check-in for user %d: %w", userID, err)
+	}
+
+	if _, err = openSegment(ctx, tx, attendanceID, projectID, checkInTime); err != nil {
+		return 0, err
+	}
+
+	actorID := userID
+	if err = shared.AppendAttendanceEvent(ctx, tx, &models.AttendanceEvent{
+		AttendanceID: attendanceID,
+		UserID:       userID,
+		EventType:    models.AttendanceEventCheckIn,
+		CheckInAt:    checkInTime,
+		Notes:        notes,
+		ActorUserID:  &actorID,
+	}); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error committing check-in for user %d: %w", userID, err)
+	}
+	shared.Logger(ctx).Info().Int("attendance_id", attendanceID).Int("user_id", userID).Time("check_in_at", checkInTime).Msg("Check-in created successfully")
+	return attendanceID, nil
+}
+
+// UpdateCheckOut records the check-out time for a specific attendance record.
+// Perubahan dicatat sebagai event check_out di ledger, lalu proyeksi diperbarui dari snapshot event tersebut.
+// Ditolak dengan *repository.PayrollPeriodClosedError jika tanggal check-in record berada di periode payroll yang sudah ditutup,
+// atau *repository.AttendanceSignedOffError jika tanggal tersebut sudah di-sign-off atasan.
+func (r *attendanceRepo) UpdateCheckOut(ctx context.Context, attendanceID int, checkOutTime time.Time, notes *string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting check-out transaction for attendance id %d: %w", attendanceID, err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	// Baca record di dalam transaksi tulis agar dua request checkout tidak menulis event ganda
+	current, err := lockAttendance(ctx, tx, attendanceID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", attendanceID).Msg("Error updating check-out for attendance ID")
+		return fmt.Errorf("error updating check-out for attendance id %d: %w", attendanceID, err)
+	}
+	if current == nil || current.CheckOutAt != nil {
+		// Ini bisa berarti ID tidak ditemukan ATAU sudah checkout sebelumnya
+		shared.Logger(ctx).Warn().Int("attendance_id", attendanceID).Msg("Attendance record not found or already checked out")
+		return fmt.Errorf("attendance record %d not found or already checked out", attendanceID)
+	}
+	if err = checkPayrollPeriodsOpen(ctx, tx, current.CheckInAt); err != nil {
+		return err
+	}
+	if err = checkNotSignedOff(ctx, tx, current.UserID, current.CheckInAt); err != nil {
+		return err
+	}
+
+	// Update notes jika disediakan, jika tidak, biarkan notes yang ada
+	if notes != nil {
+		current.Notes = notes
+	}
+	current.CheckOutAt = &checkOutTime
+	if err = shared.CloseOpenSegment(ctx, tx, current.ID, checkOutTime); err != nil {
+		return err
+	}
+
+	actorID := current.UserID
+	if err = shared.AppendAttendanceEvent(ctx, tx, &models.AttendanceEvent{
+		AttendanceID: current.ID,
+		UserID:       current.UserID,
+		EventType:    models.AttendanceEventCheckOut,
+		CheckInAt:    current.CheckInAt,
+		CheckOutAt:   current.CheckOutAt,
+		Notes:        current.Notes,
+		ActorUserID:  &actorID,
+	}); err != nil {
+		return err
+	}
+	if err = projectAttendance(ctx, tx, current); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing check-out for attendance id %d: %w", attendanceID, err)
+	}
+	return nil
+}
+
+// GetAttendancesByUser retrieves attendance records for a user within a date range
+func (r *attendanceRepo) GetAttendancesByUser(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) (attendances []models.Attendance, totalCount int, err error) {
+	// --- 1. Count Total ---
+	// Gunakan >= startDate dan <= endDate karena handler akan set endDate ke akhir hari
+	countQuery := `SELECT COUNT(*) FROM attendances WHERE user_id = $1 AND check_in_at >= $2 AND check_in_at <= $3`
+	err = r.db.QueryRow(ctx, countQuery, userID, startDate, endDate).Scan(&totalCount)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Time("start", startDate).Time("end", endDate).Msg("Error counting user attendances")
+		err = fmt.Errorf("error counting attendances for user %d: %w", userID, err)
+		return // Kembalikan error
+	}
+	if totalCount == 0 {
+		attendances = []models.Attendance{} // Return slice kosong
+		return
+	}
+
+	// --- 2. Calculate Offset ---
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+
+	// --- 3. Query Data ---
+	query := `
+        SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + `, ` + attendanceTagsColumn + `
+        FROM attendances a
+        WHERE a.user_id = $1 AND a.check_in_at >= $2 AND a.check_in_at <= $3
+        ORDER BY a.check_in_at DESC -- Order by check_in paling baru
+        LIMIT $4 OFFSET $5`
+
+	rows, err := r.db.Query(ctx, query, userID, startDate, endDate, limit, offset)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error querying paginated user attendances")
+		err = fmt.Errorf("error getting paginated attendances for user %d: %w", userID, err)
+		return
+	}
+	defer rows.Close()
+
+	// --- 4. Scan Results ---
+	attendances = []models.Attendance{}
+	for rows.Next() {
+		var att models.Attendance
+		scanErr := shared.ScanAttendance(rows, &att, &att.Tags)
+		if scanErr != nil {
+			shared.Logger(ctx).Warn().Err(scanErr).Int("user_id", userID).Msg("Error scanning user attendance row (paginated)")
+			err = fmt.Errorf("error scanning attendance row: %w", scanErr)
+			return // Return error jika scan gagal
+		}
+		attendances = append(attendances, att)
+	}
+	if err = rows.Err(); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error iterating user attendance rows")
+		err = fmt.Errorf("error iterating attendance rows: %w", err)
+		return
+	}
+
+	return // attendances, totalCount, nil error
+}
+
+// GetAllAttendances retrieves all attendance records within a date range (for Admin)
+// Includes user information. filter.EmploymentStatus (optional) limits rows to users with that status.
+func (r *attendanceRepo) GetAllAttendances(ctx context.Context, startDate, endDate time.Time, filter models.AttendanceReportFilter, page, limit int) (attendances []models.Attendance, totalCount int, err error) {
+	// --- 1. Count Total (join users hanya untuk filter) ---
+	countQuery := `SELECT COUNT(*) FROM attendances a JOIN users u ON a.user_id = u.id
+                   WHERE a.check_in_at >= $1 AND a.check_in_at <= $2 AND ($3 = '' OR u.employment_status = $3)
+                     AND (NOT $4 OR EXISTS (SELECT 1 FROM attendance_disputes ad WHERE ad.attendance_id = a.id AND ad.status = $5))`
+	err = r.db.QueryRow(ctx, countQuery, startDate, endDate, filter.EmploymentStatus, filter.DisputedOnly, models.DisputeOpen).Scan(&totalCount)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Time("start", startDate).Time("end", endDate).Msg("Error counting all attendances")
+		err = fmt.Errorf("error counting all attendances: %w", err)
+		return
+	}
+	if totalCount == 0 {
+		attendances = []models.Attendance{}
+		return
+	}
+
+	// --- 2. Calculate Offset ---
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+
+	// --- 3. Query Data (dengan join user & dispute open) ---
+	query := `
+        SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + `,
+               ` + shared.SelectList("u", userSummaryColumns) + `,
+               ad.id, ` + attendanceTagsColumn + `
+        FROM attendances a
+        JOIN users u ON a.user_id = u.id
+        LEFT JOIN attendance_disputes ad ON ad.attendance_id = a.id AND ad.status = $7
+        WHERE a.check_in_at >= $1 AND a.check_in_at <= $2 AND ($3 = '' OR u.employment_status = $3)
+          AND (NOT $6 OR ad.id IS NOT NULL)
+        ORDER BY a.check_in_at DESC, u.username ASC -- Order by check_in, lalu username
+        LIMIT $4 OFFSET $5`
+
+	rows, err := r.db.Query(ctx, query, startDate, endDate, filter.EmploymentStatus, limit, offset, filter.DisputedOnly, models.DisputeOpen)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying paginated all attendances report")
+		err = fmt.Errorf("error getting paginated all attendances report: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	// --- 4. Scan Results ---
+	attendances = []models.Attendance{}
+	for rows.Next() {
+		var att models.Attendance
+		att.User = &models.User{} // !!! Penting: Inisialisasi User sebelum scan !!!
+		scanErr := shared.ScanAttendance(rows, &att, append(shared.UserSummaryDest(att.User), &att.OpenDisputeID, &att.Tags)...)
+		if scanErr != nil {
+			shared.Logger(ctx).Warn().Err(scanErr).Msg("Error scanning attendance report row (paginated)")
+			err = fmt.Errorf("error scanning attendance report row: %w", scanErr)
+			return
+		}
+		if err = shared.DecryptUserPII(r.pii, att.User); err != nil {
+			return
+		}
+		attendances = append(attendances, att)
+	}
+	if err = rows.Err(); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error iterating attendance report rows")
+		err = fmt.Errorf("error iterating attendance report rows: %w", err)
+		return
+	}
+
+	return // attendances, totalCount, nil error
+}
+
+// ExportAttendancesByUser retrieves every attendance record of a user (no date filter, no pagination).
+// Used by the personal data export (GET /user/data-export).
+func (r *attendanceRepo) ExportAttendancesByUser(ctx context.Context, userID int) ([]models.Attendance, error) {
+	query := `
+        SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + `, ` + attendanceTagsColumn + `
+        FROM attendances a
+        WHERE a.user_id = $1
+        ORDER BY a.check_in_at ASC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error querying attendances for export")
+		return nil, fmt.Errorf("error exporting attendances for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	attendances := []models.Attendance{}
+	for rows.Next() {
+		var att models.Attendance
+		if err := shared.ScanAttendance(rows, &att, &att.Tags); err != nil {
+			return nil, fmt.Errorf("error scanning exported attendance row: %w", err)
+		}
+		attendances = append(attendances, att)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating exported attendance rows: %w", err)
+	}
+	return attendances, nil
+}
+
+// CorrectAttendance menerapkan koreksi admin pada record absensi tanpa menimpa riwayat:
+// snapshot baru dicatat sebagai event "correction" (beserta alasan & admin pelaku),
+// lalu proyeksi attendances diperbarui dari snapshot tersebut.
+// Ditolak dengan *repository.PayrollPeriodClosedError jika tanggal check-in lama atau baru berada di periode payroll yang sudah ditutup,
+// atau *repository.AttendanceSignedOffError jika salah satu tanggal tersebut sudah di-sign-off atasan.
+func (r *attendanceRepo) CorrectAttendance(ctx context.Context, attendanceID int, input *models.AttendanceCorrectionInput, actorUserID int) (*models.Attendance, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting correction transaction for attendance id %d: %w", attendanceID, err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	current, err := lockAttendance(ctx, tx, attendanceID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("error loading attendance id %d for correction: %w", attendanceID, err)
+	}
+	// Tanggal lama dan baru harus sama-sama berada di periode payroll yang masih open
+	previousCheckIn := current.CheckInAt
+
+	// Terapkan hanya field yang dikirim
+	if input.CheckInAt != nil {
+		current.CheckInAt = *input.CheckInAt
+	}
+	if input.CheckOutAt != nil {
+		current.CheckOutAt = input.CheckOutAt
+	}
+	if input.Notes != nil {
+		current.Notes = input.Notes
+	}
+	if current.CheckOutAt != nil && current.CheckOutAt.Before(current.CheckInAt) {
+		return nil, repository.ErrInvalidAttendanceTimes
+	}
+	if err = checkPayrollPeriodsOpen(ctx, tx, previousCheckIn, current.CheckInAt); err != nil {
+		return nil, err
+	}
+	if err = checkNotSignedOff(ctx, tx, current.UserID, previousCheckIn, current.CheckInAt); err != nil {
+		return nil, err
+	}
+
+	reason := input.Reason
+	if err = shared.AppendAttendanceEvent(ctx, tx, &models.AttendanceEvent{
+		AttendanceID: current.ID,
+		UserID:       current.UserID,
+		EventType:    models.AttendanceEventCorrection,
+		CheckInAt:    current.CheckInAt,
+		CheckOutAt:   current.CheckOutAt,
+		Notes:        current.Notes,
+		Reason:       &reason,
+		ActorUserID:  &actorUserID,
+	}); err != nil {
+		return nil, err
+	}
+	if err = projectAttendance(ctx, tx, current); err != nil {
+		return nil, err
+	}
+	if err = syncSegmentBounds(ctx, tx, current); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing correction for attendance id %d: %w", attendanceID, err)
+	}
+	shared.Logger(ctx).Info().Int("attendance_id", attendanceID).Int("actor_user_id", actorUserID).Msg("Attendance corrected via ledger")
+	return current, nil
+}
+
+// GetProjectHours merekap jam kerja sesi yang sudah check-out per project berdasarkan segmen
+// project (termasuk perpindahan project di tengah sesi), untuk sesi dengan check-in dalam
+// [startDate, endDate]. userID (opsional) membatasi ke satu user.
+// Segmen tanpa project dirangkum di baris dengan ProjectID nil (paling akhir).
+func (r *attendanceRepo) GetProjectHours(ctx context.Context, startDate, endDate time.Time, userID *int) ([]models.ProjectHours, error) {
+	query := `
+        SELECT p.id, p.code, p.name, p.cost_center,
+               COUNT(DISTINCT a.id), COUNT(DISTINCT a.user_id),
+               ROUND(SUM(` + hoursBetween("sg.started_at", "sg.ended_at") + `), 2)
+        FROM attendance_segments sg
+        JOIN attendances a ON sg.attendance_id = a.id
+        LEFT JOIN projects p ON sg.project_id = p.id
+        WHERE a.check_out_at IS NOT NULL AND sg.ended_at IS NOT NULL
+          AND a.check_in_at >= $1 AND a.check_in_at <= $2
+          AND ($3 IS NULL OR a.user_id = $3)
+        GROUP BY p.id
+        ORDER BY p.code NULLS LAST`
+	rows, err := r.db.Query(ctx, query, startDate, endDate, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Time("start", startDate).Time("end", endDate).Msg("Error querying project hours")
+		return nil, fmt.Errorf("error getting project hours: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []models.ProjectHours{}
+	for rows.Next() {
+		var ph models.ProjectHours
+		if err := rows.Scan(&ph.ProjectID, &ph.Code, &ph.Name, &ph.CostCenter, &ph.Sessions, &ph.Users, &ph.TotalHours); err != nil {
+			return nil, fmt.Errorf("error scanning project hours row: %w", err)
+		}
+		totals = append(totals, ph)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating project hours rows: %w", err)
+	}
+	return totals, nil
+}
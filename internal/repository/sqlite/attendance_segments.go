@@ -0,0 +1,136 @@
+// internal/repository/sqlite/attendance_segments.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Segmen project dalam sesi absensi (attendance_segments). Setiap sesi punya minimal satu
+// segmen: check-in membuka segmen pertama, switch project menutup segmen berjalan dan
+// membuka yang baru, check-out menutup segmen terakhir. Koreksi admin atas jam check-in/out
+// menyesuaikan batas segmen (lihat syncSegmentBounds). Semua helper berjalan di dalam
+// transaksi tulis yang sama dengan perubahan record attendances induknya.
+
+// openSegment membuka segmen baru mulai startedAt.
+func openSegment(ctx context.Context, tx pgx.Tx, attendanceID int, projectID *int, startedAt time.Time) (*models.AttendanceSegment, error) {
+	query := `INSERT INTO attendance_segments AS sg (attendance_id, project_id, started_at) VALUES ($1, $2, $3)
+	          RETURNING ` + returningList(shared.AttendanceSegmentColumns)
+	sg := &models.AttendanceSegment{}
+	if err := shared.ScanAttendanceSegment(tx.QueryRow(ctx, query, attendanceID, projectID, startedAt), sg); err != nil {
+		return nil, fmt.Errorf("error opening segment for attendance id %d: %w", attendanceID, err)
+	}
+	return sg, nil
+}
+
+// syncSegmentBounds menyesuaikan segmen dengan jam check-in/out hasil koreksi: segmen yang
+// seluruhnya di luar sesi dihapus, segmen pertama dimulai saat check-in dan segmen terakhir
+// berakhir saat check-out. Minimal satu segmen selalu dipertahankan.
+func syncSegmentBounds(ctx context.Context, tx pgx.Tx, att *models.Attendance) error {
+	segments, err := shared.QuerySegments(ctx, tx, att.ID)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		sg, err := openSegment(ctx, tx, att.ID, att.ProjectID, att.CheckInAt)
+		if err != nil || att.CheckOutAt == nil {
+			return err
+		}
+		return shared.CloseOpenSegment(ctx, tx, sg.AttendanceID, *att.CheckOutAt)
+	}
+
+	var kept, dropped []models.AttendanceSegment
+	for _, sg := range segments {
+		endsBeforeIn := sg.EndedAt != nil && !sg.EndedAt.After(att.CheckInAt)
+		startsAfterOut := att.CheckOutAt != nil && !sg.StartedAt.Before(*att.CheckOutAt)
+		if endsBeforeIn || startsAfterOut {
+			dropped = append(dropped, sg)
+			continue
+		}
+		kept = append(kept, sg)
+	}
+	if len(kept) == 0 {
+		kept, dropped = dropped[:1], dropped[1:]
+		kept[0].EndedAt = att.CheckOutAt
+	}
+	kept[0].StartedAt = att.CheckInAt
+	if att.CheckOutAt != nil {
+		kept[len(kept)-1].EndedAt = att.CheckOutAt
+	}
+
+	for _, sg := range dropped {
+		if _, err := tx.Exec(ctx, `DELETE FROM attendance_segments WHERE id = $1`, sg.ID); err != nil {
+			return fmt.Errorf("error deleting segment %d: %w", sg.ID, err)
+		}
+	}
+	for _, sg := range kept {
+		if _, err := tx.Exec(ctx, `UPDATE attendance_segments SET started_at = $2, ended_at = $3 WHERE id = $1`, sg.ID, sg.StartedAt, sg.EndedAt); err != nil {
+			return fmt.Errorf("error adjusting segment %d: %w", sg.ID, err)
+		}
+	}
+	return nil
+}
+
+// SwitchProject menutup segmen berjalan pada sesi terbuka user dan membuka segmen baru untuk
+// projectID mulai at, tanpa check-out. Mengembalikan repository.ErrNotCheckedIn, repository.ErrAlreadyOnProject,
+// repository.ErrProjectUnavailable, *repository.PayrollPeriodClosedError, atau *repository.AttendanceSignedOffError.
+func (r *attendanceRepo) SwitchProject(ctx context.Context, userID int, at time.Time, projectID int) (*models.AttendanceSegment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting project switch transaction for user %d: %w", userID, err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	query := `SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + ` FROM attendances a WHERE a.user_id = $1 AND a.check_out_at IS NULL`
+	current := &models.Attendance{}
+	if err := shared.ScanAttendance(tx.QueryRow(ctx, query, userID), current); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotCheckedIn
+		}
+		return nil, fmt.Errorf("error loading open attendance of user %d: %w", userID, err)
+	}
+	if err = checkPayrollPeriodsOpen(ctx, tx, current.CheckInAt); err != nil {
+		return nil, err
+	}
+	if err = checkNotSignedOff(ctx, tx, userID, current.CheckInAt); err != nil {
+		return nil, err
+	}
+	if err = checkProjectActive(ctx, tx, projectID); err != nil {
+		return nil, err
+	}
+
+	var currentProject *int
+	err = tx.QueryRow(ctx, `SELECT project_id FROM attendance_segments WHERE attendance_id = $1 AND ended_at IS NULL`, current.ID).Scan(&currentProject)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("error loading open segment for attendance id %d: %w", current.ID, err)
+	}
+	if err == nil && currentProject != nil && *currentProject == projectID {
+		return nil, repository.ErrAlreadyOnProject
+	}
+	if at.Before(current.CheckInAt) {
+		at = current.CheckInAt
+	}
+
+	if err = shared.CloseOpenSegment(ctx, tx, current.ID, at); err != nil {
+		return nil, err
+	}
+	segment, err := openSegment(ctx, tx, current.ID, &projectID, at)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing project switch for user %d: %w", userID, err)
+	}
+	shared.Logger(ctx).Info().Int("attendance_id", current.ID).Int("user_id", userID).Int("project_id", projectID).Msg("Switched project mid-session")
+	return segment, nil
+}
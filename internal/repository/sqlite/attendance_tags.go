@@ -0,0 +1,67 @@
+// internal/repository/sqlite/attendance_tags.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Tag absensi terstruktur (attendance_tags), satu baris per pasangan record & tag. Validasi tag
+// terhadap pengaturan attendance.tags dilakukan di handler.
+
+// AddAttendanceTags menambahkan tag ke record absensi (tag yang sudah ada diabaikan). Ikut
+// transaksi check-in/check-out jika ada.
+func (r *attendanceRepo) AddAttendanceTags(ctx context.Context, attendanceID int, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	query := `INSERT INTO attendance_tags (attendance_id, tag)
+              SELECT $1, value FROM json_each($2) WHERE true -- WHERE wajib sebelum ON CONFLICT pada INSERT ... SELECT
+              ON CONFLICT (attendance_id, tag) DO NOTHING`
+	if _, err := r.db.Exec(ctx, query, attendanceID, tags); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", attendanceID).Strs("tags", tags).Msg("Error adding attendance tags")
+		return fmt.Errorf("error adding tags to attendance id %d: %w", attendanceID, err)
+	}
+	return nil
+}
+
+// GetTagHours merekap sesi yang sudah check-out per tag untuk sesi dengan check-in dalam
+// [startDate, endDate]. userID (opsional) membatasi ke satu user. Sesi tanpa tag tidak ikut.
+func (r *attendanceRepo) GetTagHours(ctx context.Context, startDate, endDate time.Time, userID *int) ([]models.TagHours, error) {
+	query := `
+        SELECT atg.tag, COUNT(*), COUNT(DISTINCT a.user_id),
+               ROUND(SUM(` + hoursBetween("a.check_in_at", "a.check_out_at") + `), 2)
+        FROM attendance_tags atg
+        JOIN attendances a ON atg.attendance_id = a.id
+        WHERE a.check_out_at IS NOT NULL
+          AND a.check_in_at >= $1 AND a.check_in_at <= $2
+          AND ($3 IS NULL OR a.user_id = $3)
+        GROUP BY atg.tag
+        ORDER BY atg.tag`
+	rows, err := r.db.Query(ctx, query, startDate, endDate, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Time("start", startDate).Time("end", endDate).Msg("Error querying tag hours")
+		return nil, fmt.Errorf("error getting tag hours: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []models.TagHours{}
+	for rows.Next() {
+		var th models.TagHours
+		if err := rows.Scan(&th.Tag, &th.Sessions, &th.Users, &th.TotalHours); err != nil {
+			return nil, fmt.Errorf("error scanning tag hours row: %w", err)
+		}
+		totals = append(totals, th)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tag hours rows: %w", err)
+	}
+	return totals, nil
+}
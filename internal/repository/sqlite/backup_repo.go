@@ -0,0 +1,100 @@
+// internal/repository/sqlite/backup_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type backupRepo struct {
+	repository.BackupRepository
+	db *database
+}
+
+// CreateBackup menyimpan permintaan backup berstatus pending dan mengisi ID & created_at.
+func (r *backupRepo) CreateBackup(ctx context.Context, backup *models.Backup) error {
+	query := `INSERT INTO backups AS b (status, requested_by)
+              VALUES ($1, $2)
+              RETURNING ` + returningList(shared.BackupColumns)
+	if err := shared.ScanBackup(r.db.QueryRow(ctx, query, models.BackupPending, backup.RequestedBy), backup); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == shared.ActiveBackupConstraint {
+			return repository.ErrBackupInProgress
+		}
+		shared.Logger(ctx).Error().Err(err).Msg("Error creating backup")
+		return fmt.Errorf("error creating backup: %w", err)
+	}
+	return nil
+}
+
+// ClaimPendingBackup mengubah backup pending terlama menjadi running dan mengembalikannya.
+// Pemilihan dan update terjadi dalam satu statement, sehingga satu backup hanya diklaim sekali.
+func (r *backupRepo) ClaimPendingBackup(ctx context.Context) (*models.Backup, error) {
+	query := `UPDATE backups AS b
+              SET status = $2, started_at = NOW()
+              WHERE b.id = (SELECT id FROM backups WHERE status = $1 ORDER BY created_at, id LIMIT 1)
+              RETURNING ` + returningList(shared.BackupColumns)
+	backup := &models.Backup{}
+	if err := shared.ScanBackup(r.db.QueryRow(ctx, query, models.BackupPending, models.BackupRunning), backup); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Msg("Error claiming pending backup")
+		return nil, fmt.Errorf("error claiming pending backup: %w", err)
+	}
+	return backup, nil
+}
+
+// MarkBackupCompleted mencatat file hasil backup. pgx.ErrNoRows jika backup tidak lagi running.
+func (r *backupRepo) MarkBackupCompleted(ctx context.Context, id int, result models.BackupResult) error {
+	query := `UPDATE backups
+              SET status = $2, storage_key = $3, file_name = $4, size_bytes = $5, schema_version = $6,
+                  table_count = $7, row_count = $8, error = NULL, completed_at = NOW()
+              WHERE id = $1 AND status = $9`
+	return r.update(ctx, id, "completed", query, id, models.BackupCompleted, result.StorageKey, result.FileName, result.SizeBytes,
+		result.SchemaVersion, result.TableCount, result.RowCount, models.BackupRunning)
+}
+
+// MarkBackupFailed mencatat kegagalan backup yang belum selesai.
+func (r *backupRepo) MarkBackupFailed(ctx context.Context, id int, reason string) error {
+	query := `UPDATE backups
+              SET status = $2, error = $3, completed_at = NOW()
+              WHERE id = $1 AND status IN ($4, $5)`
+	return r.update(ctx, id, "failed", query, id, models.BackupFailed, reason, models.BackupPending, models.BackupRunning)
+}
+
+// FailStaleBackups menandai failed backup running yang dimulai sebelum startedBefore, mis.
+// karena proses berhenti di tengah backup, agar backup baru bisa diminta lagi.
+func (r *backupRepo) FailStaleBackups(ctx context.Context, startedBefore time.Time, reason string) (int, error) {
+	query := `UPDATE backups
+              SET status = $1, error = $2, completed_at = NOW()
+              WHERE status = $3 AND started_at < $4`
+	tag, err := r.db.Exec(ctx, query, models.BackupFailed, reason, models.BackupRunning, startedBefore)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error failing stale backups")
+		return 0, fmt.Errorf("error failing stale backups: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *backupRepo) update(ctx context.Context, id int, status, query string, args ...any) error {
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("backup_id", id).Str("status", status).Msg("Error updating backup")
+		return fmt.Errorf("error marking backup %d %s: %w", id, status, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// internal/repository/sqlite/columns.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"strings"
+)
+
+// Daftar kolom SELECT yang teksnya berbeda dari repository PostgreSQL. Urutannya sama dengan
+// destinasi Scan di registry shared (internal/repository/internal/shared). Kolom tanggal dan jam
+// sudah disimpan sebagai teks (YYYY-MM-DD / HH:MM:SS) sehingga tidak perlu cast, dan kolom
+// array/JSONB di-decode dari teks JSON saat Scan (lihat toDest).
+
+// returningList menyusun daftar kolom untuk klausa RETURNING. SQLite tidak mengizinkan alias
+// tabel di RETURNING, sehingga nama kolom ditulis tanpa prefix.
+func returningList(columns []string) string {
+	return strings.Join(columns, ", ")
+}
+
+// --- users ---
+
+// Kolom tanggal masa akses disimpan sebagai teks (YYYY-MM-DD) dan di-scan langsung ke *string.
+var userColumns = []string{
+	"id", "username", "password", "email", "phone", "national_id",
+	"first_name", "last_name", "role_id", "user_type", "access_valid_from", "access_valid_until",
+	"is_active", "hire_date", "employment_status", "probation_end", "manager_id",
+	"token_version", "password_reset_required", "must_change_password", "phone_verified_at", "sms_two_factor", "sms_reminders",
+	"remote_days", "field_work_allowed", "version", "created_at", "updated_at",
+}
+
+// userSummaryColumns adalah data user ringkas untuk JOIN di laporan/listing.
+// Tipe user, akhir masa akses & status aktif ikut disertakan agar listing jadwal menampilkan akhir kontrak;
+// status kepegawaian memberi konteks baris laporan absensi.
+// Email masih terenkripsi; panggil shared.DecryptUserPII setelah scan.
+var userSummaryColumns = []string{
+	"id", "username", "email", "first_name", "last_name", "user_type", "access_valid_until", "is_active",
+	"employment_status",
+}
+
+// --- attendances ---
+
+// attendanceTagsColumn mengumpulkan tag record (attendance_tags) sebagai array JSON untuk
+// Attendance.Tags; dipakai sebagai kolom extra setelah shared.AttendanceColumns (alias a).
+const attendanceTagsColumn = `(SELECT json_group_array(tag) FROM (SELECT atg.tag FROM attendance_tags atg WHERE atg.attendance_id = a.id ORDER BY atg.tag))`
+
+// --- attendance_face_checks ---
+
+var faceCheckColumns = []string{"attendance_id", "provider", "status", "score", "review_status", "reviewed_by", "reviewed_at", "created_at"}
+
+// --- payroll_periods ---
+
+var payrollPeriodColumns = []string{
+	"id", "start_date", "end_date", "status", "closed_at", "closed_by",
+	"reopened_at", "reopened_by", "reopen_reason", "created_by", "created_at", "updated_at",
+}
+
+// --- attendance_signoffs ---
+
+var attendanceSignOffColumns = []string{"id", "user_id", "work_date", "signed_by", "signed_at", "exceptions", "note"}
+
+// --- approval_delegations ---
+
+var approvalDelegationColumns = []string{
+	"id", "delegator_id", "delegate_id", "start_date", "end_date", "reason", "created_at", "revoked_at",
+}
+
+// --- report_snapshots ---
+
+// Kolom rows (isi snapshot) hanya dibaca pada detail snapshot, sebagai kolom tambahan.
+var reportSnapshotColumns = []string{
+	"id", "label", "start_date", "end_date", "payroll_period_id", "row_count", "checksum", "created_by", "created_at",
+}
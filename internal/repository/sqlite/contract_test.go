@@ -0,0 +1,15 @@
+//go:build sqlite
+
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/repotest"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/sqlitetest"
+)
+
+// TestRepositoryContract menjalankan suite kontrak repository terhadap SQLite (lihat sqlitetest).
+func TestRepositoryContract(t *testing.T) {
+	repotest.RunRepositoryContract(t, func(t *testing.T) repotest.Repos { return sqlitetest.New(t).Repos })
+}
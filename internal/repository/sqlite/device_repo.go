@@ -0,0 +1,81 @@
+// internal/repository/sqlite/device_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type deviceRepo struct {
+	repository.DeviceRepository
+	db *database
+}
+
+// RegisterDeviceToken menyimpan token perangkat untuk userID. Token yang sudah terdaftar
+// (termasuk milik user lain, mis. perangkat berganti akun) dipindahkan ke userID.
+func (r *deviceRepo) RegisterDeviceToken(ctx context.Context, userID int, platform, token string) (*models.DeviceToken, error) {
+	query := `INSERT INTO device_tokens AS dt (user_id, platform, token) VALUES ($1, $2, $3)
+              ON CONFLICT (token) DO UPDATE SET user_id = EXCLUDED.user_id, platform = EXCLUDED.platform, updated_at = NOW()
+              RETURNING ` + returningList(shared.DeviceTokenColumns)
+	d := &models.DeviceToken{}
+	if err := shared.ScanDeviceToken(r.db.QueryRow(ctx, query, userID, platform, token), d); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Str("platform", platform).Msg("Error registering device token")
+		return nil, fmt.Errorf("error registering device token for user %d: %w", userID, err)
+	}
+	return d, nil
+}
+
+// DeleteDeviceTokens menghapus token yang ditolak provider push (sudah tidak terdaftar).
+func (r *deviceRepo) DeleteDeviceTokens(ctx context.Context, tokens []string) (int, error) {
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+	tag, err := r.db.Exec(ctx, `DELETE FROM device_tokens WHERE token IN (SELECT value FROM json_each($1))`, tokens)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting invalid device tokens: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// GetPendingShiftReminders mengembalikan jadwal user aktif ber-token perangkat atau berlangganan
+// pengingat SMS pada tanggal [fromDate, toDate] yang belum dikirimi pengingat. Jam mulai disaring oleh pemanggil.
+func (r *deviceRepo) GetPendingShiftReminders(ctx context.Context, fromDate, toDate time.Time) ([]models.ShiftReminder, error) {
+	query := `
+        SELECT us.id, us.user_id, us.date, s.name, s.start_time,
+               u.sms_reminders AND u.phone_verified_at IS NOT NULL
+        FROM user_schedules us
+        JOIN schedule_shifts s ON s.schedule_id = us.id
+        JOIN users u ON u.id = us.user_id
+        WHERE us.date BETWEEN $1 AND $2
+          AND u.is_active
+          AND (EXISTS (SELECT 1 FROM device_tokens dt WHERE dt.user_id = us.user_id)
+               OR (u.sms_reminders AND u.phone_verified_at IS NOT NULL))
+          AND NOT EXISTS (SELECT 1 FROM push_reminders pr WHERE pr.schedule_id = us.id)
+        ORDER BY us.date, s.start_time`
+	rows, err := r.db.Query(ctx, query, fromDate.Format(shared.DateLayout), toDate.Format(shared.DateLayout))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying pending shift reminders")
+		return nil, fmt.Errorf("error getting pending shift reminders: %w", err)
+	}
+	defer rows.Close()
+	reminders := []models.ShiftReminder{}
+	for rows.Next() {
+		var sr models.ShiftReminder
+		if err := rows.Scan(&sr.ScheduleID, &sr.UserID, &sr.Date, &sr.ShiftName, &sr.StartTime, &sr.SMS); err != nil {
+			return nil, fmt.Errorf("error scanning shift reminder row: %w", err)
+		}
+		reminders = append(reminders, sr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shift reminder rows: %w", err)
+	}
+	return reminders, nil
+}
@@ -0,0 +1,19 @@
+// internal/repository/sqlite/disabled.go
+
+//go:build !sqlite
+
+// Package sqlite berisi implementasi repository untuk DB_DRIVER=sqlite. Implementasinya hanya
+// ikut di-build dengan tag sqlite; tanpa tag itu database.NewSQLiteDB sudah gagal lebih dulu.
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/rakaarfi/attendance-system-be/internal/pii"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// NewRepositories tidak tersedia tanpa tag build sqlite dan selalu panic.
+func NewRepositories(*sql.DB, *pii.Protector) repository.Repositories {
+	panic("sqlite repositories require a binary built with the sqlite build tag (go build -tags sqlite)")
+}
@@ -0,0 +1,74 @@
+// internal/repository/sqlite/dispute_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// openDisputeConstraint adalah unique index parsial uq_attendance_disputes_open (attendance_id
+// WHERE status = 'open'), sebagaimana dilaporkan SQLite (daftar kolom, bukan nama index).
+const openDisputeConstraint = "attendance_disputes.attendance_id"
+
+type disputeRepo struct {
+	repository.DisputeRepository
+	db *database
+}
+
+// CreateDispute membuat dispute open atas record absensi milik userID. Mengembalikan
+// pgx.ErrNoRows jika record tidak ada atau bukan milik user, repository.ErrDisputeAlreadyOpen jika
+// record sudah punya dispute open.
+func (r *disputeRepo) CreateDispute(ctx context.Context, attendanceID, userID int, comment string) (*models.AttendanceDispute, error) {
+	query := `INSERT INTO attendance_disputes AS ad (attendance_id, user_id, comment)
+              SELECT a.id, a.user_id, $3 FROM attendances a WHERE a.id = $1 AND a.user_id = $2
+              RETURNING ` + returningList(shared.AttendanceDisputeColumns)
+	d := &models.AttendanceDispute{}
+	if err := shared.ScanAttendanceDispute(r.db.QueryRow(ctx, query, attendanceID, userID, comment), d); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == openDisputeConstraint {
+			return nil, repository.ErrDisputeAlreadyOpen
+		}
+		shared.Logger(ctx).Error().Err(err).Int("attendance_id", attendanceID).Int("user_id", userID).Msg("Error creating attendance dispute")
+		return nil, fmt.Errorf("error creating dispute for attendance id %d: %w", attendanceID, err)
+	}
+	shared.Logger(ctx).Info().Int("dispute_id", d.ID).Int("attendance_id", attendanceID).Int("user_id", userID).Msg("Attendance dispute created")
+	return d, nil
+}
+
+// ResolveDispute menutup dispute open dengan status resolved/rejected dan catatan penyelesaian.
+// Mengembalikan pgx.ErrNoRows jika dispute tidak ada, atau repository.ErrDisputeNotOpen jika sudah ditutup.
+func (r *disputeRepo) ResolveDispute(ctx context.Context, id int, actorUserID int, status, note string) (*models.AttendanceDispute, error) {
+	query := `UPDATE attendance_disputes AS ad SET status = $2, resolution_note = $3, resolved_by = $4, resolved_at = NOW()
+              WHERE ad.id = $1 AND ad.status = $5
+              RETURNING ` + returningList(shared.AttendanceDisputeColumns)
+	d := &models.AttendanceDispute{}
+	err := shared.ScanAttendanceDispute(r.db.QueryRow(ctx, query, id, status, note, actorUserID, models.DisputeOpen), d)
+	if err == nil {
+		shared.Logger(ctx).Info().Int("dispute_id", id).Str("status", status).Int("actor_id", actorUserID).Msg("Attendance dispute resolved")
+		return d, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		shared.Logger(ctx).Error().Err(err).Int("dispute_id", id).Msg("Error resolving dispute")
+		return nil, fmt.Errorf("error resolving dispute %d: %w", id, err)
+	}
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM attendance_disputes WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking dispute id %d: %w", id, err)
+	}
+	if !exists {
+		return nil, pgx.ErrNoRows
+	}
+	return nil, repository.ErrDisputeNotOpen
+}
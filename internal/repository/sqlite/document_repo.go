@@ -0,0 +1,41 @@
+// internal/repository/sqlite/document_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// documentStorageKeyConstraint adalah UNIQUE storage_key sebagaimana dilaporkan SQLite.
+const documentStorageKeyConstraint = "documents.storage_key"
+
+type documentRepo struct {
+	repository.DocumentRepository
+	db *database
+}
+
+func (r *documentRepo) CreateDocument(ctx context.Context, doc *models.Document) (int, error) {
+	query := `INSERT INTO documents (owner_user_id, subject_type, subject_id, file_name, content_type, size_bytes, sha256, storage_key, scan_status)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+              RETURNING id, created_at`
+	err := r.db.QueryRow(ctx, query,
+		doc.OwnerUserID, doc.SubjectType, doc.SubjectID, doc.FileName, doc.ContentType,
+		doc.SizeBytes, doc.SHA256, doc.StorageKey, doc.ScanStatus,
+	).Scan(&doc.ID, &doc.CreatedAt)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == documentStorageKeyConstraint {
+			return 0, repository.ErrDocumentExists
+		}
+		shared.Logger(ctx).Error().Err(err).Int("owner_user_id", doc.OwnerUserID).Msg("Error creating document")
+		return 0, fmt.Errorf("error creating document: %w", err)
+	}
+	return doc.ID, nil
+}
@@ -0,0 +1,49 @@
+// internal/repository/sqlite/escalation_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type escalationRepo struct {
+	repository.EscalationRepository
+	db *database
+}
+
+// GetEscalations mengembalikan riwayat eskalasi item-item itemIDs bertipe itemType, per item
+// terlama dulu. Item tanpa eskalasi tidak ada di map.
+func (r *escalationRepo) GetEscalations(ctx context.Context, itemType string, itemIDs []int) (map[int][]models.ApprovalEscalation, error) {
+	history := map[int][]models.ApprovalEscalation{}
+	if len(itemIDs) == 0 {
+		return history, nil
+	}
+	query := `SELECT ` + shared.SelectList("ae", shared.ApprovalEscalationColumns) + `
+              FROM approval_escalations ae
+              WHERE ae.item_type = $1 AND ae.item_id IN (SELECT value FROM json_each($2))
+              ORDER BY ae.item_id, ae.level`
+	rows, err := r.db.Query(ctx, query, itemType, itemIDs)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("item_type", itemType).Msg("Error querying approval escalations")
+		return nil, fmt.Errorf("error getting escalations of %s items: %w", itemType, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e models.ApprovalEscalation
+		if err := shared.ScanApprovalEscalation(rows, &e); err != nil {
+			return nil, fmt.Errorf("error scanning escalation row: %w", err)
+		}
+		history[e.ItemID] = append(history[e.ItemID], e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalation rows: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,49 @@
+// internal/repository/sqlite/job_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type jobRepo struct {
+	repository.JobRepository
+	db *database
+}
+
+// StartJobRun menandai job berjalan dan memajukan next_run_at satu interval. Tanpa force, hanya
+// berhasil jika job sudah jatuh tempo (bisa jadi baru dijalankan instance lain). Dipanggil
+// sambil memegang lock job (lihat jobs.Scheduler).
+func (r *jobRepo) StartJobRun(ctx context.Context, name, trigger string, force bool) (bool, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE scheduled_jobs
+              SET last_status = 'running', last_trigger = $2, last_started_at = NOW(),
+                  next_run_at = `+nowPlusSeconds("interval_seconds")+`
+              WHERE name = $1 AND ($3 OR next_run_at <= NOW())`, name, trigger, force)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("job", name).Msg("Error starting scheduled job run")
+		return false, fmt.Errorf("error starting run of job %s: %w", name, err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// FinishJobRun mencatat hasil run: jumlah item yang diproses, atau pesan error jika gagal.
+func (r *jobRepo) FinishJobRun(ctx context.Context, name string, result int, runErr *string, duration time.Duration) error {
+	_, err := r.db.Exec(ctx, `UPDATE scheduled_jobs
+              SET last_status = CASE WHEN $3 IS NULL THEN 'succeeded' ELSE 'failed' END,
+                  last_result = CASE WHEN $3 IS NULL THEN $2 END, last_error = $3,
+                  last_finished_at = NOW(), last_duration_ms = $4,
+                  run_count = run_count + 1, failure_count = failure_count + CASE WHEN $3 IS NULL THEN 0 ELSE 1 END
+              WHERE name = $1`, name, result, runErr, duration.Milliseconds())
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("job", name).Msg("Error finishing scheduled job run")
+		return fmt.Errorf("error finishing run of job %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// internal/repository/sqlite/kiosk_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// activeKioskFingerprintConstraint adalah unique index parsial uq_kiosk_devices_active_fingerprint
+// sebagaimana dilaporkan SQLite (daftar kolom, bukan nama index).
+const activeKioskFingerprintConstraint = "kiosk_devices.user_id, kiosk_devices.fingerprint_hash"
+
+type kioskRepo struct {
+	repository.KioskRepository
+	db *database
+}
+
+// CreateKioskDevice mendaftarkan perangkat kiosk dan mengisi ID & created_at.
+func (r *kioskRepo) CreateKioskDevice(ctx context.Context, device *models.KioskDevice) error {
+	query := `INSERT INTO kiosk_devices AS kd (user_id, name, fingerprint_hash)
+              VALUES ($1, $2, $3)
+              RETURNING ` + returningList(shared.KioskDeviceColumns)
+	err := shared.ScanKioskDevice(r.db.QueryRow(ctx, query, device.UserID, device.Name, device.FingerprintHash), device)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == activeKioskFingerprintConstraint {
+			return repository.ErrKioskAlreadyEnrolled
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", device.UserID).Msg("Error enrolling kiosk device")
+		return fmt.Errorf("error enrolling kiosk device for user %d: %w", device.UserID, err)
+	}
+	shared.Logger(ctx).Info().Int("kiosk_id", device.ID).Int("user_id", device.UserID).Msg("Kiosk device enrolled")
+	return nil
+}
+
+// RevokeKioskDevice mencabut perangkat sehingga token kiosk-nya langsung ditolak. ownerID
+// membatasi ke perangkat milik user tersebut (nil = perangkat siapa pun, untuk admin).
+// Mengembalikan pgx.ErrNoRows jika perangkat tidak ada, bukan milik ownerID, atau sudah dicabut.
+func (r *kioskRepo) RevokeKioskDevice(ctx context.Context, id int, ownerID *int, revokedBy int) (*models.KioskDevice, error) {
+	query := `UPDATE kiosk_devices AS kd SET revoked_at = NOW(), revoked_by = $3
+              WHERE kd.id = $1 AND ($2 IS NULL OR kd.user_id = $2) AND kd.revoked_at IS NULL
+              RETURNING ` + returningList(shared.KioskDeviceColumns)
+	device := &models.KioskDevice{}
+	if err := shared.ScanKioskDevice(r.db.QueryRow(ctx, query, id, ownerID, revokedBy), device); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("kiosk_id", id).Msg("Error revoking kiosk device")
+		return nil, fmt.Errorf("error revoking kiosk device %d: %w", id, err)
+	}
+	shared.Logger(ctx).Info().Int("kiosk_id", id).Int("revoked_by", revokedBy).Msg("Kiosk device revoked")
+	return device, nil
+}
+
+// TouchKioskDevice memperbarui last_seen_at perangkat.
+func (r *kioskRepo) TouchKioskDevice(ctx context.Context, id int) error {
+	if _, err := r.db.Exec(ctx, `UPDATE kiosk_devices SET last_seen_at = NOW() WHERE id = $1`, id); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("kiosk_id", id).Msg("Error updating kiosk device last seen")
+		return fmt.Errorf("error updating last seen of kiosk device %d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// internal/repository/sqlite/labor_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// laborGroupings memetakan group_by ke ekspresi kunci & ID kelompok atas CTE rated di
+// GetLaborCost. Hanya nilai di map ini yang masuk ke query.
+var laborGroupings = map[string]struct{ key, id string }{
+	models.LaborGroupDay:     {key: "day", id: "NULL"},
+	models.LaborGroupRole:    {key: "role_name", id: "role_id"},
+	models.LaborGroupManager: {key: "manager_name", id: "manager_id"},
+}
+
+// laborAggregates adalah kolom agregat GetLaborCost, dipakai baris kelompok maupun baris total.
+const laborAggregates = `COUNT(DISTINCT user_id),
+               ROUND(COALESCE(SUM(scheduled), 0), 2), ROUND(COALESCE(SUM(actual), 0), 2),
+               ROUND(COALESCE(SUM(scheduled * rate), 0), 2), ROUND(COALESCE(SUM(actual * rate), 0), 2),
+               ROUND(COALESCE(SUM(scheduled) FILTER (WHERE rate IS NULL), 0), 2),
+               ROUND(COALESCE(SUM(actual) FILTER (WHERE rate IS NULL), 0), 2)`
+
+type laborRepo struct {
+	repository.LaborRepository
+	db *database
+}
+
+// SetUserHourlyRate mengatur tarif per jam user (nil = ikut tarif role).
+// Mengembalikan pgx.ErrNoRows jika user tidak ada.
+func (r *laborRepo) SetUserHourlyRate(ctx context.Context, userID int, rate *float64) error {
+	tag, err := r.db.Exec(ctx, `UPDATE users SET hourly_rate = $1, updated_at = NOW() WHERE id = $2`, rate, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error updating user hourly rate")
+		return fmt.Errorf("error updating hourly rate of user %d: %w", userID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetLaborCost merekap jam & biaya terjadwal (user_schedules × durasi shift, shift malam
+// dihitung lintas hari) dan aktual (sesi absensi yang sudah check-out, berdasarkan tanggal
+// check-in) dalam [startDate, endDate], dikelompokkan menurut groupBy (lihat models.LaborGroup*).
+// Baris total seluruh periode dikembalikan terpisah (selalu ada, nol jika tidak ada data).
+// SQLite tidak mendukung GROUPING SETS, sehingga baris total disusun dengan UNION ALL.
+func (r *laborRepo) GetLaborCost(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]models.LaborCostRow, *models.LaborCostRow, error) {
+	grouping, ok := laborGroupings[groupBy]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported labor cost grouping %q", groupBy)
+	}
+	query := fmt.Sprintf(`
+        WITH work AS (
+            SELECT us.user_id, us.date AS day,
+                   (julianday('2000-01-01 ' || s.end_time) - julianday('2000-01-01 ' || s.start_time)
+                       + CASE WHEN s.end_time <= s.start_time THEN 1 ELSE 0 END) * 24 AS scheduled,
+                   0 AS actual
+            FROM user_schedules us
+            JOIN schedule_shifts s ON s.schedule_id = us.id
+            WHERE us.date BETWEEN $1 AND $2
+            UNION ALL
+            SELECT a.user_id, date(a.check_in_at, 'localtime'), 0, `+hoursBetween("a.check_in_at", "a.check_out_at")+`
+            FROM attendances a
+            WHERE a.check_out_at IS NOT NULL AND date(a.check_in_at, 'localtime') BETWEEN $1 AND $2
+        ), rated AS (
+            SELECT w.user_id, w.day, w.scheduled, w.actual,
+                   COALESCE(u.hourly_rate, r.hourly_rate) AS rate,
+                   r.id AS role_id, r.name AS role_name, u.manager_id, m.username AS manager_name
+            FROM work w
+            JOIN users u ON u.id = w.user_id
+            JOIN roles r ON r.id = u.role_id
+            LEFT JOIN users m ON m.id = u.manager_id
+        )
+        SELECT * FROM (
+            SELECT FALSE AS is_total, %[1]s AS group_key, MIN(%[2]s), %[3]s FROM rated GROUP BY %[1]s
+            UNION ALL
+            SELECT TRUE, NULL, NULL, %[3]s FROM rated
+        )
+        ORDER BY is_total, group_key NULLS LAST`, grouping.key, grouping.id, laborAggregates)
+	rows, err := r.db.Query(ctx, query, startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("group_by", groupBy).Msg("Error querying labor cost")
+		return nil, nil, fmt.Errorf("error getting labor cost: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []models.LaborCostRow{}
+	totals := &models.LaborCostRow{}
+	for rows.Next() {
+		var row models.LaborCostRow
+		var isTotal bool
+		if err := rows.Scan(&isTotal, &row.Key, &row.ID, &row.Users, &row.ScheduledHours, &row.ActualHours,
+			&row.ScheduledCost, &row.ActualCost, &row.UnratedScheduledHours, &row.UnratedActualHours); err != nil {
+			return nil, nil, fmt.Errorf("error scanning labor cost row: %w", err)
+		}
+		row.CostVariance = math.Round((row.ActualCost-row.ScheduledCost)*100) / 100
+		if isTotal {
+			*totals = row
+			continue
+		}
+		groups = append(groups, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating labor cost rows: %w", err)
+	}
+	return groups, totals, nil
+}
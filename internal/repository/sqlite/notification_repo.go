@@ -0,0 +1,48 @@
+// internal/repository/sqlite/notification_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type notificationRepo struct {
+	repository.NotificationRepository
+	db *database
+}
+
+// MarkNotificationRead menandai satu notifikasi milik user sebagai dibaca (idempoten: waktu
+// baca pertama dipertahankan). Mengembalikan pgx.ErrNoRows jika bukan milik user.
+func (r *notificationRepo) MarkNotificationRead(ctx context.Context, userID, id int) (*models.Notification, error) {
+	query := `UPDATE notifications AS n SET read_at = COALESCE(n.read_at, NOW())
+              WHERE n.id = $1 AND n.user_id = $2
+              RETURNING ` + returningList(shared.NotificationColumns)
+	n := &models.Notification{}
+	if err := shared.ScanNotification(r.db.QueryRow(ctx, query, id, userID), n); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("notification_id", id).Msg("Error marking notification read")
+		return nil, fmt.Errorf("error marking notification %d read: %w", id, err)
+	}
+	return n, nil
+}
+
+// MarkAllNotificationsRead menandai semua notifikasi user yang belum dibaca dan mengembalikan jumlahnya.
+func (r *notificationRepo) MarkAllNotificationsRead(ctx context.Context, userID int) (int, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error marking all notifications read")
+		return 0, fmt.Errorf("error marking notifications of user %d read: %w", userID, err)
+	}
+	return int(tag.RowsAffected()), nil
+}
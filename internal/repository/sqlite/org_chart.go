@@ -0,0 +1,115 @@
+// internal/repository/sqlite/org_chart.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Query garis pelaporan (users.manager_id) untuk userRepo.
+// Pohon ditelusuri dengan recursive CTE; kolom path (id dipisah koma) mencegah loop seandainya
+// data lama mengandung siklus, walaupun SetUserManager menolak siklus baru. Transaksi tulis
+// SQLite saling menunggu, sehingga dua perubahan bersamaan tidak bisa membentuk siklus.
+
+// SetUserManager mengatur atasan langsung user (nil = tanpa atasan).
+// Mengembalikan pgx.ErrNoRows jika user atau manager tidak ada, repository.ErrManagerCycle jika membentuk siklus.
+func (r *userRepo) SetUserManager(ctx context.Context, userID int, managerID *int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting manager update transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if managerID != nil {
+		// Siklus terjadi jika userID ada di rantai atasan managerID (termasuk managerID == userID).
+		var cycle, managerExists bool
+		query := `WITH RECURSIVE chain AS (
+                      SELECT id, manager_id FROM users WHERE id = $2
+                      UNION
+                      SELECT u.id, u.manager_id FROM users u JOIN chain c ON u.id = c.manager_id
+                  )
+                  SELECT EXISTS (SELECT 1 FROM chain WHERE id = $1), EXISTS (SELECT 1 FROM chain WHERE id = $2)`
+		if err := tx.QueryRow(ctx, query, userID, *managerID).Scan(&cycle, &managerExists); err != nil {
+			return fmt.Errorf("error checking reporting chain: %w", err)
+		}
+		if !managerExists {
+			return pgx.ErrNoRows
+		}
+		if cycle {
+			return repository.ErrManagerCycle
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE users SET manager_id = $1 WHERE id = $2`, managerID, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error updating user manager")
+		return fmt.Errorf("error updating user manager: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing manager update: %w", err)
+	}
+	shared.Logger(ctx).Info().Int("user_id", userID).Interface("manager_id", managerID).Msg("User manager updated")
+	return nil
+}
+
+// GetReportingTree mengembalikan user aktif pada pohon pelaporan beserta status kehadiran
+// pada hari day (rentang [dayStart, dayStart+24h)). rootID nil = seluruh organisasi (akar adalah
+// user tanpa atasan); selain itu pohon di bawah rootID termasuk rootID sendiri.
+// Node dikembalikan datar, terurut depth lalu username.
+func (r *userRepo) GetReportingTree(ctx context.Context, rootID *int, dayStart time.Time) ([]models.OrgChartNode, error) {
+	query := `
+        WITH RECURSIVE tree AS (
+            SELECT u.id, u.manager_id, 0 AS depth, ',' || u.id || ',' AS path
+            FROM users u
+            WHERE u.is_active AND (($1 IS NULL AND u.manager_id IS NULL) OR u.id = $1)
+            UNION ALL
+            SELECT c.id, c.manager_id, t.depth + 1, t.path || c.id || ','
+            FROM users c
+            JOIN tree t ON c.manager_id = t.id
+            WHERE c.is_active AND instr(t.path, ',' || c.id || ',') = 0
+        )
+        SELECT u.id, u.username, COALESCE(u.first_name, ''), COALESCE(u.last_name, ''), t.manager_id, t.depth,
+               CASE
+                   WHEN EXISTS (SELECT 1 FROM attendances a WHERE a.user_id = u.id AND a.check_out_at IS NULL) THEN $5
+                   WHEN EXISTS (SELECT 1 FROM attendances a WHERE a.user_id = u.id AND a.check_in_at >= $2 AND a.check_in_at < $3) THEN $6
+                   WHEN EXISTS (SELECT 1 FROM user_schedules us WHERE us.user_id = u.id AND us.date = $4) THEN $7
+                   ELSE $8
+               END AS presence
+        FROM tree t
+        JOIN users u ON u.id = t.id
+        ORDER BY t.depth ASC, u.username ASC`
+
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	rows, err := r.db.Query(ctx, query, rootID, dayStart, dayEnd, dayStart.Format(shared.DateLayout),
+		models.PresencePresent, models.PresenceCheckedOut, models.PresenceAbsent, models.PresenceOff)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying reporting tree")
+		return nil, fmt.Errorf("error getting reporting tree: %w", err)
+	}
+	defer rows.Close()
+
+	nodes := []models.OrgChartNode{}
+	for rows.Next() {
+		var n models.OrgChartNode
+		if err := rows.Scan(&n.UserID, &n.Username, &n.FirstName, &n.LastName, &n.ManagerID, &n.Depth, &n.Presence); err != nil {
+			return nil, fmt.Errorf("error scanning reporting tree row: %w", err)
+		}
+		nodes = append(nodes, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reporting tree rows: %w", err)
+	}
+	return nodes, nil
+}
@@ -0,0 +1,106 @@
+// internal/repository/sqlite/outbox_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type outboxRepo struct {
+	repository.OutboxRepository
+	db *database
+}
+
+// ProcessDueOutboxMessages mengambil hingga limit pesan yang jatuh tempo (urut id), lalu
+// memanggil deliver per pesan. Berbeda dengan PostgreSQL, pesan tidak dikunci selama dikirim:
+// database SQLite hanya dipakai satu instance, dan menahan transaksi tulis selama deliver
+// akan membuat semua penulis lain menunggu jaringan. Pesan yang berhasil ditandai delivered.
+// Yang gagal dijadwalkan ulang setelah backoff(attempts), atau menjadi dead letter jika
+// percobaannya mencapai maxAttempts. Mengembalikan jumlah pesan yang terkirim dan yang gagal.
+func (r *outboxRepo) ProcessDueOutboxMessages(ctx context.Context, limit, maxAttempts int, backoff func(attempts int) time.Duration, deliver func(ctx context.Context, msg models.OutboxMessage) error) (int, int, error) {
+	query := `SELECT ` + shared.SelectList("o", shared.OutboxMessageColumns) + `
+              FROM outbox_messages o
+              WHERE o.delivered_at IS NULL AND o.dead_at IS NULL AND o.available_at <= NOW()
+              ORDER BY o.id
+              LIMIT $1`
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying due outbox messages")
+		return 0, 0, fmt.Errorf("error querying due outbox messages: %w", err)
+	}
+	due, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.OutboxMessage, error) {
+		var msg models.OutboxMessage
+		err := shared.ScanOutboxMessage(row, &msg)
+		return msg, err
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("error scanning outbox message rows: %w", err)
+	}
+
+	delivered, failed := 0, 0
+	for _, msg := range due {
+		deliverErr := deliver(ctx, msg)
+		if deliverErr == nil {
+			if _, err := r.db.Exec(ctx, `UPDATE outbox_messages SET attempts = attempts + 1, last_error = NULL, delivered_at = NOW() WHERE id = $1`, msg.ID); err != nil {
+				return 0, 0, fmt.Errorf("error marking outbox message %d delivered: %w", msg.ID, err)
+			}
+			delivered++
+			continue
+		}
+
+		failed++
+		reason := deliverErr.Error()
+		if len(reason) > shared.MaxOutboxErrorLength {
+			reason = reason[:shared.MaxOutboxErrorLength]
+		}
+		attempts := msg.Attempts + 1
+		logger := shared.Logger(ctx).Warn().Err(deliverErr).Int64("outbox_id", msg.ID).Str("destination", msg.Destination).Str("event", msg.EventName).Int("attempts", attempts)
+		if attempts >= maxAttempts {
+			_, err = r.db.Exec(ctx, `UPDATE outbox_messages SET attempts = $2, last_error = $3, dead_at = NOW() WHERE id = $1`, msg.ID, attempts, reason)
+			logger.Msg("Outbox message moved to dead letter")
+		} else {
+			_, err = r.db.Exec(ctx, `UPDATE outbox_messages SET attempts = $2, last_error = $3, available_at = $4 WHERE id = $1`,
+				msg.ID, attempts, reason, time.Now().Add(backoff(attempts)))
+			logger.Msg("Outbox message delivery failed; will retry")
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("error recording outbox message %d failure: %w", msg.ID, err)
+		}
+	}
+	return delivered, failed, nil
+}
+
+// RetryOutboxMessage mengembalikan dead letter ke antrean dengan percobaan direset.
+// Mengembalikan pgx.ErrNoRows jika pesan tidak ada, atau repository.ErrOutboxMessageNotDead.
+func (r *outboxRepo) RetryOutboxMessage(ctx context.Context, id int64) (*models.OutboxMessage, error) {
+	query := `UPDATE outbox_messages AS o SET attempts = 0, dead_at = NULL, available_at = NOW()
+              WHERE o.id = $1 AND o.dead_at IS NOT NULL
+              RETURNING ` + returningList(shared.OutboxMessageColumns)
+	msg := &models.OutboxMessage{}
+	err := shared.ScanOutboxMessage(r.db.QueryRow(ctx, query, id), msg)
+	if err == nil {
+		return msg, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		shared.Logger(ctx).Error().Err(err).Int64("outbox_id", id).Msg("Error retrying outbox message")
+		return nil, fmt.Errorf("error retrying outbox message %d: %w", id, err)
+	}
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM outbox_messages WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking outbox message %d: %w", id, err)
+	}
+	if exists {
+		return nil, repository.ErrOutboxMessageNotDead
+	}
+	return nil, pgx.ErrNoRows
+}
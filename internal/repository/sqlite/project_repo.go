@@ -0,0 +1,91 @@
+// internal/repository/sqlite/project_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type projectRepo struct {
+	repository.ProjectRepository
+	db *database
+}
+
+// projectWriteError menerjemahkan unique violation kode project ke repository.ErrProjectCodeTaken.
+func projectWriteError(err error) error {
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+		return repository.ErrProjectCodeTaken
+	}
+	return nil
+}
+
+func (r *projectRepo) CreateProject(ctx context.Context, p *models.Project) (int, error) {
+	query := `INSERT INTO projects (code, name, cost_center, is_active) VALUES ($1, $2, $3, $4) RETURNING id`
+	var id int
+	if err := r.db.QueryRow(ctx, query, p.Code, p.Name, p.CostCenter, p.IsActive).Scan(&id); err != nil {
+		if mapped := projectWriteError(err); mapped != nil {
+			return 0, mapped
+		}
+		shared.Logger(ctx).Error().Err(err).Str("code", p.Code).Msg("Error creating project")
+		return 0, fmt.Errorf("error creating project: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateProject mengganti data project by ID. Mengembalikan pgx.ErrNoRows jika tidak ada.
+func (r *projectRepo) UpdateProject(ctx context.Context, p *models.Project) error {
+	query := `UPDATE projects SET code = $1, name = $2, cost_center = $3, is_active = $4 WHERE id = $5`
+	tag, err := r.db.Exec(ctx, query, p.Code, p.Name, p.CostCenter, p.IsActive, p.ID)
+	if err != nil {
+		if mapped := projectWriteError(err); mapped != nil {
+			return mapped
+		}
+		shared.Logger(ctx).Error().Err(err).Int("project_id", p.ID).Msg("Error updating project")
+		return fmt.Errorf("error updating project id %d: %w", p.ID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteProject menghapus project yang belum pernah dipakai absensi.
+func (r *projectRepo) DeleteProject(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM projects WHERE id = $1`, id)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			shared.Logger(ctx).Warn().Int("project_id", id).Msg("Cannot delete project: it is still referenced by attendance records")
+			return repository.ErrProjectInUse
+		}
+		shared.Logger(ctx).Error().Err(err).Int("project_id", id).Msg("Error deleting project")
+		return fmt.Errorf("error deleting project id %d: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// checkProjectActive memastikan project ada dan aktif. Transaksi check-in adalah transaksi
+// tulis, sehingga project tidak bisa dinonaktifkan/dihapus sebelum transaksi itu selesai.
+func checkProjectActive(ctx context.Context, tx pgx.Tx, projectID int) error {
+	var active bool
+	err := tx.QueryRow(ctx, `SELECT is_active FROM projects WHERE id = $1`, projectID).Scan(&active)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !active) {
+		return repository.ErrProjectUnavailable
+	}
+	if err != nil {
+		return fmt.Errorf("error checking project id %d: %w", projectID, err)
+	}
+	return nil
+}
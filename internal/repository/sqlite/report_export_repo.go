@@ -0,0 +1,89 @@
+// internal/repository/sqlite/report_export_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type reportExportRepo struct {
+	repository.ReportExportRepository
+	db *database
+}
+
+// CreateReportExport menyimpan permintaan ekspor berstatus pending dan mengisi ID & created_at.
+func (r *reportExportRepo) CreateReportExport(ctx context.Context, report *models.ReportExport) error {
+	query := `INSERT INTO report_exports AS re (report_type, format, params, status, requested_by, retention_seconds)
+              VALUES ($1, $2, $3, $4, $5, $6)
+              RETURNING ` + returningList(shared.ReportExportColumns)
+	params := report.Params
+	if len(params) == 0 {
+		params = []byte("{}")
+	}
+	err := shared.ScanReportExport(r.db.QueryRow(ctx, query, report.ReportType, report.Format, params, models.ReportExportPending,
+		report.RequestedBy, report.RetentionSeconds), report)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("report_type", report.ReportType).Msg("Error creating report export")
+		return fmt.Errorf("error creating %s report export: %w", report.ReportType, err)
+	}
+	return nil
+}
+
+// MarkReportExportCompleted mencatat file hasil ekspor; expires_at dihitung dari retensi yang
+// diminta. pgx.ErrNoRows jika ekspor tidak lagi pending.
+func (r *reportExportRepo) MarkReportExportCompleted(ctx context.Context, id int, result models.ReportExportResult) (*models.ReportExport, error) {
+	query := `UPDATE report_exports AS re
+              SET status = $2, storage_key = $3, file_name = $4, content_type = $5, size_bytes = $6, row_count = $7,
+                  error = NULL, completed_at = NOW(),
+                  expires_at = ` + nowPlusSeconds("re.retention_seconds") + `
+              WHERE re.id = $1 AND re.status = $8
+              RETURNING ` + returningList(shared.ReportExportColumns)
+	report := &models.ReportExport{}
+	err := shared.ScanReportExport(r.db.QueryRow(ctx, query, id, models.ReportExportCompleted, result.StorageKey, result.FileName,
+		result.ContentType, result.SizeBytes, result.RowCount, models.ReportExportPending), report)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("report_export_id", id).Msg("Error completing report export")
+		return nil, fmt.Errorf("error marking report export %d completed: %w", id, err)
+	}
+	return report, nil
+}
+
+// MarkReportExportFailed mencatat kegagalan pembuatan ekspor pending.
+func (r *reportExportRepo) MarkReportExportFailed(ctx context.Context, id int, reason string) error {
+	query := `UPDATE report_exports
+              SET status = $2, error = $3, completed_at = NOW()
+              WHERE id = $1 AND status = $4`
+	return r.update(ctx, id, "failed", query, id, models.ReportExportFailed, reason, models.ReportExportPending)
+}
+
+// MarkReportExportPurged mencatat bahwa file ekspor sudah dihapus dan mengosongkan storage_key.
+func (r *reportExportRepo) MarkReportExportPurged(ctx context.Context, id int) error {
+	query := `UPDATE report_exports
+              SET status = $2, storage_key = NULL, purged_at = NOW()
+              WHERE id = $1`
+	return r.update(ctx, id, "purged", query, id, models.ReportExportPurged)
+}
+
+func (r *reportExportRepo) update(ctx context.Context, id int, status, query string, args ...any) error {
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("report_export_id", id).Str("status", status).Msg("Error updating report export")
+		return fmt.Errorf("error marking report export %d %s: %w", id, status, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
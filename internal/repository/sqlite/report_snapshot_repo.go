@@ -0,0 +1,127 @@
+// internal/repository/sqlite/report_snapshot_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Snapshot laporan (report_snapshots): ringkasan absensi satu periode yang dibekukan untuk
+// payroll. Tabelnya immutable (trigger report_snapshots_immutable). Tanggal sesi diambil dari
+// date(check_in_at, 'localtime'), setara check_in_at::date di PostgreSQL.
+
+// GetSnapshotSessions mengembalikan semua sesi absensi dengan tanggal check-in dalam
+// [startDate, endDate] beserta username pemiliknya (Attendance.User), urut user lalu check-in.
+func (r *reportExportRepo) GetSnapshotSessions(ctx context.Context, startDate, endDate time.Time) ([]models.Attendance, error) {
+	query := `SELECT a.id, a.user_id, u.username, a.check_in_at, a.check_out_at
+              FROM attendances a
+              JOIN users u ON u.id = a.user_id
+              WHERE date(a.check_in_at, 'localtime') BETWEEN $1 AND $2
+              ORDER BY a.user_id, a.check_in_at, a.id`
+	rows, err := r.db.Query(ctx, query, startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Time("start", startDate).Time("end", endDate).Msg("Error querying snapshot sessions")
+		return nil, fmt.Errorf("error getting snapshot sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.Attendance{}
+	for rows.Next() {
+		a := models.Attendance{User: &models.User{}}
+		if err := rows.Scan(&a.ID, &a.UserID, &a.User.Username, &a.CheckInAt, &a.CheckOutAt); err != nil {
+			return nil, fmt.Errorf("error scanning snapshot session row: %w", err)
+		}
+		a.User.ID = a.UserID
+		sessions = append(sessions, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating snapshot session rows: %w", err)
+	}
+	return sessions, nil
+}
+
+// CreateReportSnapshot menyimpan snapshot beserta baris-barisnya dan mengisi ID & created_at.
+// pgx.ErrNoRows jika payroll_period_id tidak ada.
+func (r *reportExportRepo) CreateReportSnapshot(ctx context.Context, snapshot *models.ReportSnapshot) error {
+	rows, err := json.Marshal(snapshot.Rows)
+	if err != nil {
+		return fmt.Errorf("error encoding report snapshot rows: %w", err)
+	}
+	query := `INSERT INTO report_snapshots AS rs (label, start_date, end_date, payroll_period_id, rows, row_count, checksum, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+              RETURNING ` + returningList(reportSnapshotColumns)
+	err = shared.ScanReportSnapshot(r.db.QueryRow(ctx, query, snapshot.Label, snapshot.StartDate, snapshot.EndDate, snapshot.PayrollPeriodID,
+		string(rows), len(snapshot.Rows), snapshot.Checksum, snapshot.CreatedBy), snapshot)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Str("start_date", snapshot.StartDate).Msg("Error creating report snapshot")
+		return fmt.Errorf("error creating report snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetReportSnapshotByID mengembalikan satu snapshot beserta baris-barisnya, atau pgx.ErrNoRows.
+func (r *reportExportRepo) GetReportSnapshotByID(ctx context.Context, id int) (*models.ReportSnapshot, error) {
+	query := `SELECT ` + shared.SelectList("rs", reportSnapshotColumns) + `, rs.rows FROM report_snapshots rs WHERE rs.id = $1`
+	snapshot := &models.ReportSnapshot{}
+	var rows []byte
+	if err := shared.ScanReportSnapshot(r.db.QueryRow(ctx, query, id), snapshot, &rows); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("report_snapshot_id", id).Msg("Error getting report snapshot")
+		return nil, fmt.Errorf("error getting report snapshot %d: %w", id, err)
+	}
+	if err := json.Unmarshal(rows, &snapshot.Rows); err != nil {
+		return nil, fmt.Errorf("error decoding rows of report snapshot %d: %w", id, err)
+	}
+	return snapshot, nil
+}
+
+// GetReportSnapshots mengembalikan snapshot tanpa baris-barisnya, terbaru dulu.
+func (r *reportExportRepo) GetReportSnapshots(ctx context.Context, page, limit int) ([]models.ReportSnapshot, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM report_snapshots`).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting report snapshots")
+		return nil, 0, fmt.Errorf("error counting report snapshots: %w", err)
+	}
+	if total == 0 {
+		return []models.ReportSnapshot{}, 0, nil
+	}
+	query := `SELECT ` + shared.SelectList("rs", reportSnapshotColumns) + `
+              FROM report_snapshots rs
+              ORDER BY rs.created_at DESC, rs.id DESC
+              LIMIT $1 OFFSET $2`
+	rows, err := r.db.Query(ctx, query, limit, shared.PageOffset(page, limit))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying report snapshots")
+		return nil, 0, fmt.Errorf("error querying report snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []models.ReportSnapshot{}
+	for rows.Next() {
+		var s models.ReportSnapshot
+		if err := shared.ScanReportSnapshot(rows, &s); err != nil {
+			return nil, 0, fmt.Errorf("error scanning report snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating report snapshots: %w", err)
+	}
+	return snapshots, total, nil
+}
@@ -0,0 +1,142 @@
+// internal/repository/sqlite/role_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// roleHierarchyError memetakan pelanggaran hierarki role (CHECK parent sendiri & FK parent_id).
+// SQLite tidak menyebut nama FK yang dilanggar; satu-satunya FK di roles adalah parent_id.
+func roleHierarchyError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+	switch {
+	case pgErr.ConstraintName == "roles_parent_self_check":
+		return repository.ErrRoleCycle
+	case pgErr.Code == "23503":
+		return repository.ErrParentRoleNotFound
+	}
+	return nil
+}
+
+type roleRepo struct {
+	repository.RoleRepository
+	db *database
+}
+
+func (r *roleRepo) CreateRole(ctx context.Context, role *models.Role) (int, error) {
+	query := `INSERT INTO roles (name, parent_id) VALUES ($1, $2) RETURNING id`
+	var roleID int
+	err := r.db.QueryRow(ctx, query, role.Name, role.ParentID).Scan(&roleID)
+	if err != nil {
+		if hierarchyErr := roleHierarchyError(err); hierarchyErr != nil {
+			return 0, hierarchyErr
+		}
+		// Handle unique constraint violation (name)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			shared.Logger(ctx).Warn().Err(err).Str("role_name", role.Name).Msg("Role name already exists")
+			return 0, fmt.Errorf("role name '%s' already exists", role.Name)
+		}
+		// Error umum
+		shared.Logger(ctx).Error().Err(err).Str("role_name", role.Name).Msg("Error creating role")
+		return 0, fmt.Errorf("error creating role: %w", err)
+	}
+	return roleID, nil
+}
+
+// SetRoleParent mengatur role yang diwarisi roleID (parentID nil = tanpa pewarisan).
+// Mengembalikan pgx.ErrNoRows jika role tidak ada, repository.ErrParentRoleNotFound, atau repository.ErrRoleCycle.
+// Siklus dicek dalam transaksi yang sama dengan perubahannya (body trigger SQLite tidak mendukung
+// recursive CTE); transaksi tulis SQLite saling menunggu, sehingga aman terhadap perubahan bersamaan.
+func (r *roleRepo) SetRoleParent(ctx context.Context, roleID int, parentID *int) (*models.Role, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting role parent transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	if parentID != nil {
+		// Siklus terjadi jika roleID ada di rantai parent parentID (termasuk parentID == roleID).
+		var cycle bool
+		query := `WITH RECURSIVE chain AS (
+                      SELECT id, parent_id FROM roles WHERE id = $2
+                      UNION
+                      SELECT p.id, p.parent_id FROM roles p JOIN chain c ON p.id = c.parent_id
+                  )
+                  SELECT EXISTS (SELECT 1 FROM chain WHERE id = $1)`
+		if err := tx.QueryRow(ctx, query, roleID, *parentID).Scan(&cycle); err != nil {
+			return nil, fmt.Errorf("error checking role inheritance chain: %w", err)
+		}
+		if cycle {
+			shared.Logger(ctx).Warn().Int("role_id", roleID).Int("parent_id", *parentID).Msg("Rejected role parent change")
+			return nil, repository.ErrRoleCycle
+		}
+	}
+
+	query := `UPDATE roles SET parent_id = $2 WHERE id = $1 RETURNING ` + returningList(shared.RoleColumns)
+	role := &models.Role{}
+	err = shared.ScanRole(tx.QueryRow(ctx, query, roleID, parentID), role)
+	if err == nil {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		if hierarchyErr := roleHierarchyError(err); hierarchyErr != nil {
+			shared.Logger(ctx).Warn().Err(err).Int("role_id", roleID).Msg("Rejected role parent change")
+			return nil, hierarchyErr
+		}
+		shared.Logger(ctx).Error().Err(err).Int("role_id", roleID).Msg("Error setting role parent")
+		return nil, fmt.Errorf("error setting parent of role %d: %w", roleID, err)
+	}
+	shared.Logger(ctx).Info().Int("role_id", roleID).Interface("parent_id", parentID).Msg("Role parent updated")
+	return role, nil
+}
+
+// GetInheritedRoles mengembalikan nama role yang diwarisi role bernama roleName lewat rantai
+// parent, terdekat dulu (tanpa roleName sendiri). Mengembalikan pgx.ErrNoRows jika role tidak ada.
+func (r *roleRepo) GetInheritedRoles(ctx context.Context, roleName string) ([]string, error) {
+	query := `WITH RECURSIVE chain AS (
+                  SELECT r.id, r.name, r.parent_id, 0 AS depth FROM roles r WHERE r.name = $1
+                  UNION
+                  SELECT p.id, p.name, p.parent_id, c.depth + 1 FROM roles p JOIN chain c ON p.id = c.parent_id
+                  WHERE c.depth < 32 -- Pengaman; siklus sudah ditolak SetRoleParent
+              )
+              SELECT name FROM chain ORDER BY depth`
+	rows, err := r.db.Query(ctx, query, roleName)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("role_name", roleName).Msg("Error querying inherited roles")
+		return nil, fmt.Errorf("error getting inherited roles of %s: %w", roleName, err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("error scanning inherited roles of %s: %w", roleName, err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning inherited roles of %s: %w", roleName, err)
+	}
+	if len(names) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return names[1:], nil
+}
@@ -0,0 +1,86 @@
+// internal/repository/sqlite/schedule_conflicts.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Validasi tumpang tindih jadwal berdasarkan jam shift, bukan hanya tanggal.
+// Unique (user_id, date) tidak mencegah shift malam (mis. 22:00-06:00) pada satu tanggal
+// bertabrakan dengan shift pagi keesokan harinya. Rentang jadwal adalah
+// [date + start_time, date + end_time), dengan end_time <= start_time berarti selesai
+// keesokan harinya, sehingga cukup memeriksa jadwal user pada tanggal -1..+1. Jam shift
+// diambil dari view schedule_shifts, jadi override musiman pada tanggal jadwal ikut diperhitungkan.
+//
+// Pengecekan dan perubahan jadwal berjalan dalam satu transaksi tulis; SQLite menyerialkan
+// transaksi tulis, sehingga dua request bersamaan tidak bisa sama-sama lolos pengecekan.
+
+// checkScheduleOverlap mengembalikan *repository.ScheduleConflictError jika shiftID pada date tumpang
+// tindih dengan jadwal lain milik userID (excludeID = jadwal yang sedang diubah, 0 saat membuat).
+// Shift yang tidak ada dilewati; insert/update akan gagal di foreign key.
+func checkScheduleOverlap(ctx context.Context, tx pgx.Tx, userID, shiftID int, date time.Time, excludeID int) error {
+	// candidate memilih override dengan aturan yang sama seperti view schedule_shifts.
+	query := `
+        WITH candidate_shift AS (
+            SELECT COALESCE(o.start_time, time(s.start_time, COALESCE(o.start_offset_minutes, 0) || ' minutes')) AS start_time,
+                   COALESCE(o.end_time, time(s.end_time, COALESCE(o.end_offset_minutes, 0) || ' minutes')) AS end_time
+            FROM shifts s
+            LEFT JOIN shift_overrides o ON o.id = (
+                SELECT so.id FROM shift_overrides so
+                WHERE (so.shift_id = s.id OR so.shift_id IS NULL) AND $3 BETWEEN so.start_date AND so.end_date
+                ORDER BY so.shift_id IS NULL, so.id DESC
+                LIMIT 1
+            )
+            WHERE s.id = $2
+        ), candidate AS (
+            SELECT datetime($3 || ' ' || s.start_time) AS starts_at,
+                   datetime($3 || ' ' || s.end_time, CASE WHEN s.end_time <= s.start_time THEN '+1 day' ELSE '+0 days' END) AS ends_at
+            FROM candidate_shift s
+        ), existing AS (
+            SELECT us.id, us.date, s.id AS shift_id, s.name,
+                   datetime(us.date || ' ' || s.start_time) AS starts_at,
+                   datetime(us.date || ' ' || s.end_time, CASE WHEN s.end_time <= s.start_time THEN '+1 day' ELSE '+0 days' END) AS ends_at
+            FROM user_schedules us
+            JOIN schedule_shifts s ON s.schedule_id = us.id
+            WHERE us.user_id = $1 AND us.id <> $4
+              AND us.date BETWEEN date($3, '-1 day') AND date($3, '+1 day')
+        )
+        SELECT e.id, e.date, e.shift_id, e.name,
+               strftime('%Y-%m-%dT%H:%M:%S', e.starts_at), strftime('%Y-%m-%dT%H:%M:%S', e.ends_at)
+        FROM existing e, candidate c
+        WHERE e.starts_at < c.ends_at AND e.ends_at > c.starts_at
+        ORDER BY e.starts_at`
+
+	rows, err := tx.Query(ctx, query, userID, shiftID, date.Format(shared.DateLayout), excludeID)
+	if err != nil {
+		return fmt.Errorf("error checking schedule overlap for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var conflicts []models.ScheduleConflict
+	for rows.Next() {
+		var c models.ScheduleConflict
+		if err := rows.Scan(&c.ScheduleID, &c.Date, &c.ShiftID, &c.ShiftName, &c.StartsAt, &c.EndsAt); err != nil {
+			return fmt.Errorf("error scanning overlapping schedule: %w", err)
+		}
+		conflicts = append(conflicts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating overlapping schedules: %w", err)
+	}
+	if len(conflicts) > 0 {
+		shared.Logger(ctx).Warn().Int("user_id", userID).Int("shift_id", shiftID).Str("date", date.Format(shared.DateLayout)).Int("conflicts", len(conflicts)).Msg("Schedule overlaps with existing schedules")
+		return &repository.ScheduleConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
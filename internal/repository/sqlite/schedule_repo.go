@@ -0,0 +1,559 @@
+// internal/repository/sqlite/schedule_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn" // Untuk cek error code
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/pii"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type scheduleRepo struct {
+	repository.ScheduleRepository
+	db  *database
+	pii *pii.Protector // Dekripsi email user pada query yang JOIN ke tabel users
+}
+
+// CreateSchedule assigns a shift to a user on a specific date.
+// Mengembalikan *repository.ScheduleConflictError jika jam shift tumpang tindih dengan jadwal user yang lain.
+func (r *scheduleRepo) CreateSchedule(ctx context.Context, schedule *models.UserSchedule) (int, error) {
+	shared.Logger(ctx).Info().Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Str("date", schedule.Date).Msg("Creating schedule for user and date")
+
+	query := `INSERT INTO user_schedules (user_id, shift_id, date) VALUES ($1, $2, $3) RETURNING id`
+	var scheduleID int
+
+	// Parse tanggal dari string ke time.Time untuk validasi dan insert
+	scheduleDate, err := time.Parse(shared.DateLayout, schedule.Date)
+	if err != nil {
+		shared.Logger(ctx).Warn().Err(err).Str("date", schedule.Date).Msg("Invalid date format for schedule, use YYYY-MM-DD")
+		return 0, fmt.Errorf("invalid date format for schedule, use YYYY-MM-DD: %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error starting schedule transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	if err := checkScheduleOverlap(ctx, tx, schedule.UserID, schedule.ShiftID, scheduleDate, 0); err != nil {
+		return 0, err
+	}
+
+	err = tx.QueryRow(ctx, query, schedule.UserID, schedule.ShiftID, schedule.Date).Scan(&scheduleID)
+	if err != nil {
+		// Cek unique constraint violation (user_id, date)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			shared.Logger(ctx).Warn().Err(err).Int("user_id", schedule.UserID).Str("date", schedule.Date).Msg("User already has a schedule on this date")
+			return 0, fmt.Errorf("user %d already has a schedule on %s", schedule.UserID, schedule.Date)
+		}
+		// Cek foreign key constraint violation (misal user_id atau shift_id tidak ada)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			shared.Logger(ctx).Warn().Err(err).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Msg("Invalid user_id or shift_id")
+			return 0, fmt.Errorf("invalid user_id (%d) or shift_id (%d)", schedule.UserID, schedule.ShiftID)
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Str("date", schedule.Date).Msg("Error creating schedule")
+		return 0, fmt.Errorf("error creating schedule: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error committing schedule: %w", err)
+	}
+	shared.Logger(ctx).Info().Int("schedule_id", scheduleID).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Str("date", schedule.Date).Msg("Schedule created successfully")
+	return scheduleID, nil
+}
+
+// GetScheduleByUserAndDate retrieves a specific schedule
+func (r *scheduleRepo) GetScheduleByUserAndDate(ctx context.Context, userID int, date time.Time) (*models.UserSchedule, error) {
+	shared.Logger(ctx).Info().Int("user_id", userID).Str("date", date.Format(shared.DateLayout)).Msg("Retrieving schedule for user and date")
+
+	query := `
+        SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+               ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `
+        FROM user_schedules us
+        JOIN schedule_shifts s ON s.schedule_id = us.id
+        WHERE us.user_id = $1 AND us.date = $2`
+
+	schedule := &models.UserSchedule{Shift: &models.Shift{}}
+	err := shared.ScanSchedule(r.db.QueryRow(ctx, query, userID, date.Format(shared.DateLayout)), schedule, shared.ShiftSummaryDest(schedule.Shift)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			shared.Logger(ctx).Warn().Int("user_id", userID).Str("date", date.Format(shared.DateLayout)).Msg("No schedule found for user and date")
+			return nil, nil
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Str("date", date.Format(shared.DateLayout)).Msg("Error getting schedule")
+		return nil, fmt.Errorf("error getting schedule for user %d on %s: %w", userID, date.Format(shared.DateLayout), err)
+	}
+
+	shared.Logger(ctx).Info().Int("user_id", userID).Str("date", schedule.Date).Msg("Schedule retrieved successfully")
+	return schedule, nil
+}
+
+// GetScheduleByID mengembalikan jadwal beserta ringkasan shift-nya, atau pgx.ErrNoRows.
+func (r *scheduleRepo) GetScheduleByID(ctx context.Context, id int) (*models.UserSchedule, error) {
+	query := `
+        SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+               ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `
+        FROM user_schedules us
+        JOIN schedule_shifts s ON s.schedule_id = us.id
+        WHERE us.id = $1`
+
+	schedule := &models.UserSchedule{Shift: &models.Shift{}}
+	if err := shared.ScanSchedule(r.db.QueryRow(ctx, query, id), schedule, shared.ShiftSummaryDest(schedule.Shift)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("schedule_id", id).Msg("Error getting schedule by ID")
+		return nil, fmt.Errorf("error getting schedule by id %d: %w", id, err)
+	}
+	return schedule, nil
+}
+
+// GetSchedulesByUser retrieves schedules for a user within a date range
+func (r *scheduleRepo) GetSchedulesByUser(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) (schedules []models.UserSchedule, totalCount int, err error) {
+	// 1. Count Total
+	countQuery := `SELECT COUNT(*) FROM user_schedules WHERE user_id = $1 AND date >= $2 AND date <= $3`
+	err = r.db.QueryRow(ctx, countQuery, userID, startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout)).Scan(&totalCount)
+	if err != nil {
+		err = fmt.Errorf("error counting schedules for user %d: %w", userID, err)
+		return
+	}
+	if totalCount == 0 {
+		schedules = []models.UserSchedule{}
+		return
+	}
+
+	// 2. Calculate Offset
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+
+	// 3. Query Data with JOIN, Filters, ORDER BY, LIMIT, OFFSET
+	query := `
+        SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+               ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `
+        FROM user_schedules us
+        JOIN schedule_shifts s ON s.schedule_id = us.id
+        WHERE us.user_id = $1 AND us.date >= $2 AND us.date <= $3
+        ORDER BY us.date ASC -- ORDER BY penting
+        LIMIT $4 OFFSET $5`
+
+	rows, err := r.db.Query(ctx, query, userID, startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout), limit, offset)
+	if err != nil {
+		err = fmt.Errorf("error getting paginated schedules for user %d: %w", userID, err)
+		return
+	}
+	defer rows.Close()
+
+	// 4. Scan Results
+	schedules = []models.UserSchedule{}
+	for rows.Next() {
+		var schedule models.UserSchedule
+		schedule.Shift = &models.Shift{} // Init nested struct
+		scanErr := shared.ScanSchedule(rows, &schedule, shared.ShiftSummaryDest(schedule.Shift)...)
+		if scanErr != nil {
+			shared.Logger(ctx).Warn().Err(scanErr).Int("user_id", userID).Msg("Error scanning user schedule row (paginated)")
+			// Mungkin return error di sini
+			err = fmt.Errorf("error scanning schedule row: %w", scanErr)
+			return
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("error iterating schedule rows: %w", err)
+		return
+	}
+	return // schedules, totalCount, nil error implicitly returned
+}
+
+// GetSchedulesByUserWithAttendance sama seperti GetSchedulesByUser, tetapi setiap jadwal dilengkapi
+// absensinya (lewat LEFT JOIN) dan AttendanceStatus present/missing. Absensi dicocokkan lewat
+// attendances.schedule_id; absensi tanpa tautan jadwal dicocokkan dengan tanggal check-in.
+func (r *scheduleRepo) GetSchedulesByUserWithAttendance(ctx context.Context, userID int, startDate, endDate time.Time, page, limit int) (schedules []models.UserSchedule, totalCount int, err error) {
+	countQuery := `SELECT COUNT(*) FROM user_schedules WHERE user_id = $1 AND date >= $2 AND date <= $3`
+	if err = r.db.QueryRow(ctx, countQuery, userID, startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout)).Scan(&totalCount); err != nil {
+		err = fmt.Errorf("error counting schedules for user %d: %w", userID, err)
+		return
+	}
+	schedules = []models.UserSchedule{}
+	if totalCount == 0 {
+		return
+	}
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := `
+        SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+               ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `,
+               ` + shared.SelectList("a", shared.AttendanceColumns) + `
+        FROM user_schedules us
+        JOIN schedule_shifts s ON s.schedule_id = us.id
+        LEFT JOIN attendances a ON a.id = (
+            SELECT att.id FROM attendances att
+            WHERE att.schedule_id = us.id
+               OR (att.schedule_id IS NULL AND att.user_id = us.user_id AND date(att.check_in_at, 'localtime') = us.date)
+            ORDER BY att.schedule_id IS NULL, att.check_in_at ASC
+            LIMIT 1
+        )
+        WHERE us.user_id = $1 AND us.date >= $2 AND us.date <= $3
+        ORDER BY us.date ASC
+        LIMIT $4 OFFSET $5`
+
+	rows, err := r.db.Query(ctx, query, userID, startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout), limit, offset)
+	if err != nil {
+		err = fmt.Errorf("error getting schedules with attendance for user %d: %w", userID, err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schedule models.UserSchedule
+		schedule.Shift = &models.Shift{}
+		// Kolom absensi NULL jika tidak ada check-in pada tanggal jadwal.
+		var attID, attUserID *int
+		var checkInAt, createdAt, updatedAt *time.Time
+		var mode *string
+		att := &models.Attendance{}
+		dest := append(shared.ShiftSummaryDest(schedule.Shift), &attID, &attUserID, &att.ScheduleID, &att.ProjectID, &checkInAt, &att.CheckOutAt, &att.Notes, &createdAt, &updatedAt, &mode)
+		if scanErr := shared.ScanSchedule(rows, &schedule, dest...); scanErr != nil {
+			err = fmt.Errorf("error scanning schedule with attendance row: %w", scanErr)
+			return
+		}
+		schedule.AttendanceStatus = models.AttendanceStatusMissing
+		if attID != nil {
+			att.ID, att.UserID, att.CheckInAt, att.CreatedAt, att.UpdatedAt, att.Mode = *attID, *attUserID, *checkInAt, *createdAt, *updatedAt, *mode
+			schedule.Attendance = att
+			schedule.AttendanceStatus = models.AttendanceStatusPresent
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("error iterating schedule with attendance rows: %w", err)
+		return
+	}
+	return
+}
+
+// Tambahkan fungsi lain jika perlu (misal: GetSchedulesByDateRangeForAllUsers, UpdateSchedule, DeleteSchedule)
+
+func (r *scheduleRepo) GetSchedulesByDateRangeForAllUsers(ctx context.Context, startDate, endDate time.Time, page, limit int) (schedules []models.UserSchedule, totalCount int, err error) {
+	// 1. Count Total
+	countQuery := `SELECT COUNT(*) FROM user_schedules WHERE date >= $1 AND date <= $2`
+	err = r.db.QueryRow(ctx, countQuery, startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout)).Scan(&totalCount)
+	if err != nil {
+		err = fmt.Errorf("error counting all schedules: %w", err)
+		return
+	}
+	if totalCount == 0 {
+		schedules = []models.UserSchedule{}
+		return
+	}
+
+	// 2. Calculate Offset
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+
+	// 3. Query Data
+	query := `
+		SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+		       ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `,
+		       ` + shared.SelectList("u", userSummaryColumns) + `
+		FROM user_schedules us
+		JOIN schedule_shifts s ON s.schedule_id = us.id
+        JOIN users u ON us.user_id = u.id -- JOIN users
+		WHERE us.date >= $1 AND us.date <= $2
+		ORDER BY us.date ASC, u.username ASC -- ORDER BY penting
+        LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(ctx, query, startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout), limit, offset)
+	if err != nil {
+		err = fmt.Errorf("error getting paginated all schedules: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	// 4. Scan Results (termasuk field user jika ditambahkan di query)
+	schedules = []models.UserSchedule{}
+	for rows.Next() {
+		var schedule models.UserSchedule
+		schedule.Shift = &models.Shift{} // Init nested struct
+		schedule.User = &models.User{}
+		scanErr := shared.ScanSchedule(rows, &schedule, append(shared.ShiftSummaryDest(schedule.Shift), shared.UserSummaryDest(schedule.User)...)...)
+		if scanErr != nil {
+			shared.Logger(ctx).Warn().Err(scanErr).Msg("Error scanning all schedules row (paginated)")
+			err = fmt.Errorf("error scanning schedule row: %w", scanErr)
+			return
+		}
+		if err = shared.DecryptUserPII(r.pii, schedule.User); err != nil {
+			return
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("error iterating all schedule rows: %w", err)
+		return
+	}
+	return
+}
+
+// UpdateSchedule mengganti jadwal by ID.
+// Mengembalikan *repository.ScheduleConflictError jika jam shift tumpang tindih dengan jadwal user yang lain.
+func (r *scheduleRepo) UpdateSchedule(ctx context.Context, schedule *models.UserSchedule) error {
+	// --- Validasi tanggal sebelum query (jika formatnya string) ---
+	scheduleDate, err := time.Parse(shared.DateLayout, schedule.Date)
+	if err != nil {
+		// Kembalikan error format tanggal yang jelas
+		return fmt.Errorf("invalid date format for schedule update, use YYYY-MM-DD: %w", err)
+	}
+	// --- Akhir Validasi Tanggal ---
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting schedule transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	if err := checkScheduleOverlap(ctx, tx, schedule.UserID, schedule.ShiftID, scheduleDate, schedule.ID); err != nil {
+		return err
+	}
+
+	// Optimistic locking: jika schedule.Version > 0, hanya update bila versi di DB sama
+	query := `UPDATE user_schedules SET user_id = $1, shift_id = $2, date = $3, updated_at = NOW(), version = version + 1
+              WHERE id = $4 AND ($5 IS NULL OR version = $5)
+              RETURNING version`
+	err = tx.QueryRow(ctx, query, schedule.UserID, schedule.ShiftID, schedule.Date, schedule.ID, shared.ExpectedVersion(schedule.Version)).Scan(&schedule.Version)
+	if err != nil {
+		// Tidak ada baris terupdate: schedule tidak ditemukan atau versi usang
+		if errors.Is(err, pgx.ErrNoRows) {
+			return resolveMissedUpdate(ctx, r.db, "user_schedules", schedule.ID)
+		}
+		// Handle unique constraint (user_id, date)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			shared.Logger(ctx).Warn().Err(err).Int("schedule_id", schedule.ID).Int("user_id", schedule.UserID).Str("date", schedule.Date).Msg("Unique constraint violation on schedule update")
+			return fmt.Errorf("user %d already has a schedule on %s", schedule.UserID, schedule.Date)
+		}
+		// Handle foreign key constraint (user_id atau shift_id tidak valid)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			shared.Logger(ctx).Warn().Err(err).Int("schedule_id", schedule.ID).Int("user_id", schedule.UserID).Int("shift_id", schedule.ShiftID).Msg("Foreign key violation on schedule update")
+			return fmt.Errorf("invalid user_id (%d) or shift_id (%d)", schedule.UserID, schedule.ShiftID)
+		}
+		// Error umum
+		shared.Logger(ctx).Error().Err(err).Int("schedule_id", schedule.ID).Msg("Error updating schedule")
+		return fmt.Errorf("error updating schedule %d: %w", schedule.ID, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing schedule update: %w", err)
+	}
+	return nil
+}
+
+// ExportSchedulesByUser mengambil seluruh jadwal milik user (tanpa filter tanggal & pagination),
+// dipakai untuk ekspor data pribadi (GET /user/data-export).
+func (r *scheduleRepo) ExportSchedulesByUser(ctx context.Context, userID int) ([]models.UserSchedule, error) {
+	query := `
+        SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + `,
+               ` + shared.SelectList("s", shared.ShiftSummaryColumns) + `
+        FROM user_schedules us
+        JOIN schedule_shifts s ON s.schedule_id = us.id
+        WHERE us.user_id = $1
+        ORDER BY us.date ASC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting schedules for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	schedules := []models.UserSchedule{}
+	for rows.Next() {
+		var schedule models.UserSchedule
+		schedule.Shift = &models.Shift{}
+		if err := shared.ScanSchedule(rows, &schedule, shared.ShiftSummaryDest(schedule.Shift)...); err != nil {
+			return nil, fmt.Errorf("error scanning exported schedule row: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating exported schedule rows: %w", err)
+	}
+	return schedules, nil
+}
+
+// PatchSchedule memperbarui hanya field jadwal yang dikirim (non-nil).
+// Mengembalikan versi baru record, atau *repository.ScheduleConflictError jika hasilnya tumpang tindih
+// dengan jadwal user yang lain.
+func (r *scheduleRepo) PatchSchedule(ctx context.Context, id int, input *models.PatchScheduleInput) (int, error) {
+	var b shared.SetBuilder
+	var patchDate time.Time
+	if input.UserID != nil {
+		b.Add("user_id", *input.UserID)
+	}
+	if input.ShiftID != nil {
+		b.Add("shift_id", *input.ShiftID)
+	}
+	if input.Date != nil {
+		scheduleDate, err := time.Parse(shared.DateLayout, *input.Date)
+		if err != nil {
+			return 0, fmt.Errorf("invalid date format for schedule update, use YYYY-MM-DD: %w", err)
+		}
+		b.Add("date", *input.Date)
+		patchDate = scheduleDate
+	}
+	if b.Empty() {
+		return 0, repository.ErrNoFieldsToUpdate
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error starting schedule transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	// Gabungkan field yang dikirim dengan nilai saat ini untuk pengecekan tumpang tindih.
+	var userID, shiftID int
+	var date time.Time
+	err = tx.QueryRow(ctx, `SELECT user_id, shift_id, date FROM user_schedules WHERE id = $1`, id).Scan(&userID, &shiftID, &date)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("error loading schedule %d: %w", id, err)
+	}
+	// Jadwal yang tidak ada dilaporkan oleh UPDATE di bawah (pgx.ErrNoRows).
+	if err == nil {
+		if input.UserID != nil {
+			userID = *input.UserID
+		}
+		if input.ShiftID != nil {
+			shiftID = *input.ShiftID
+		}
+		if input.Date != nil {
+			date = patchDate
+		}
+		if err := checkScheduleOverlap(ctx, tx, userID, shiftID, date, id); err != nil {
+			return 0, err
+		}
+	}
+
+	idArg := b.Arg(id)
+	versionArg := b.Arg(shared.ExpectedVersion(input.Version))
+	query := fmt.Sprintf(`UPDATE user_schedules SET %s, updated_at = NOW(), version = version + 1 WHERE id = %s AND (%s IS NULL OR version = %s) RETURNING version`,
+		b.Clause(), idArg, versionArg, versionArg)
+
+	var version int
+	err = tx.QueryRow(ctx, query, b.Args()...).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, resolveMissedUpdate(ctx, r.db, "user_schedules", id)
+		}
+		// Handle unique constraint (user_id, date)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			shared.Logger(ctx).Warn().Err(err).Int("schedule_id", id).Msg("Unique constraint violation on schedule patch")
+			return 0, fmt.Errorf("user already has a schedule on that date")
+		}
+		// Handle foreign key constraint (user_id atau shift_id tidak valid)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			shared.Logger(ctx).Warn().Err(err).Int("schedule_id", id).Msg("Foreign key violation on schedule patch")
+			return 0, fmt.Errorf("invalid user_id or shift_id")
+		}
+		shared.Logger(ctx).Error().Err(err).Int("schedule_id", id).Msg("Error patching schedule")
+		return 0, fmt.Errorf("error patching schedule %d: %w", id, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error committing schedule patch: %w", err)
+	}
+	return version, nil
+}
+
+// BulkDeleteSchedules menghapus banyak jadwal dalam satu transaksi.
+// Mode ID: ids berisi schedule ID (ID yang tidak ada dilaporkan di NotFoundIDs).
+// Mode filter: ids kosong, jadwal dipilih berdasarkan rentang tanggal [startDate, endDate]
+// dan (opsional) userIDs. Jadwal yang sudah memiliki absensi pada tanggalnya tetap disimpan
+// agar riwayat kerja tidak kehilangan konteks jadwalnya.
+func (r *scheduleRepo) BulkDeleteSchedules(ctx context.Context, ids []int, userIDs []int, startDate, endDate *time.Time) (*models.BulkDeleteSchedulesResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting bulk schedule delete transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	// --- 1. Kumpulkan kandidat ---
+	selectQuery := `
+        SELECT us.id,
+               EXISTS (SELECT 1 FROM attendances a
+                       WHERE a.user_id = us.user_id AND date(a.check_in_at, 'localtime') = us.date) AS has_attendance
+        FROM user_schedules us
+        WHERE %s
+        ORDER BY us.id`
+	var rows pgx.Rows
+	if len(ids) > 0 {
+		rows, err = tx.Query(ctx, fmt.Sprintf(selectQuery, `us.id IN (SELECT value FROM json_each($1))`), ids)
+	} else {
+		var userFilter any // nil -> NULL -> semua user
+		if len(userIDs) > 0 {
+			userFilter = userIDs
+		}
+		rows, err = tx.Query(ctx, fmt.Sprintf(selectQuery, `us.date >= $1 AND us.date <= $2 AND ($3 IS NULL OR us.user_id IN (SELECT value FROM json_each($3)))`),
+			startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout), userFilter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error selecting schedules for bulk delete: %w", err)
+	}
+
+	result := &models.BulkDeleteSchedulesResult{DeletedIDs: []int{}, Kept: []models.BulkItemResult{}}
+	found := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		var hasAttendance bool
+		if err := rows.Scan(&id, &hasAttendance); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning schedule for bulk delete: %w", err)
+		}
+		found[id] = true
+		if hasAttendance {
+			result.Kept = append(result.Kept, models.BulkItemResult{
+				ID: id, Status: models.BulkStatusSkipped, Message: "attendance already recorded for this schedule",
+			})
+			continue
+		}
+		result.DeletedIDs = append(result.DeletedIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schedules for bulk delete: %w", err)
+	}
+
+	// --- 2. Hapus yang boleh dihapus ---
+	if len(result.DeletedIDs) > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM user_schedules WHERE id IN (SELECT value FROM json_each($1))`, result.DeletedIDs); err != nil {
+			shared.Logger(ctx).Error().Err(err).Int("count", len(result.DeletedIDs)).Msg("Error bulk deleting schedules")
+			return nil, fmt.Errorf("error bulk deleting schedules: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing bulk schedule delete: %w", err)
+	}
+
+	// --- 3. Ringkasan ---
+	for _, id := range ids {
+		if !found[id] {
+			result.NotFoundIDs = append(result.NotFoundIDs, id)
+			found[id] = true // Hindari duplikat
+		}
+	}
+	result.DeletedCount = len(result.DeletedIDs)
+	result.KeptCount = len(result.Kept)
+	shared.Logger(ctx).Info().Int("deleted", result.DeletedCount).Int("kept", result.KeptCount).Msg("Bulk schedule delete committed")
+	return result, nil
+}
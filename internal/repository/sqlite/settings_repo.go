@@ -0,0 +1,52 @@
+// internal/repository/sqlite/settings_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type settingsRepo struct {
+	repository.SettingsRepository
+	db *database
+}
+
+func (r *settingsRepo) UpsertSettings(ctx context.Context, values map[string]string, actorUserID int) error {
+	query := `
+		INSERT INTO settings (key, value, updated_by, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (key) DO UPDATE
+		SET value = EXCLUDED.value, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`
+
+	// Semua key disimpan dalam satu transaksi: perubahan diterapkan utuh atau tidak sama sekali.
+	err := r.upsertSettings(ctx, query, values, actorUserID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("actor_user_id", actorUserID).Msg("Error saving settings")
+		return fmt.Errorf("error saving settings: %w", err)
+	}
+	return nil
+}
+
+// upsertSettings menjalankan query untuk setiap key (terurut) dalam satu transaksi.
+func (r *settingsRepo) upsertSettings(ctx context.Context, query string, values map[string]string, actorUserID int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if _, err := tx.Exec(ctx, query, key, values[key], actorUserID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,107 @@
+// internal/repository/sqlite/shift_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time" // Digunakan untuk parsing/formatting jika perlu, meskipun DB type TIME
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn" // Untuk cek error code
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type shiftRepo struct {
+	repository.ShiftRepository
+	db *database
+}
+
+// UpdateShift modifies an existing shift.
+// Jika shift.Version > 0, update hanya dilakukan bila versi di DB sama (optimistic locking);
+// selisih versi menghasilkan repository.ErrVersionConflict. Versi baru ditulis kembali ke shift.Version.
+func (r *shiftRepo) UpdateShift(ctx context.Context, shift *models.Shift) error {
+	query := `UPDATE shifts SET name = $1, start_time = $2, end_time = $3, updated_at = NOW(), version = version + 1
+              WHERE id = $4 AND ($5 IS NULL OR version = $5)
+              RETURNING version`
+
+	// Validasi format waktu
+	_, errStart := time.Parse("15:04:05", shift.StartTime)
+	_, errEnd := time.Parse("15:04:05", shift.EndTime)
+	if errStart != nil || errEnd != nil {
+		shared.Logger(ctx).Warn().Err(errStart).Err(errEnd).Msg("Invalid time format, use HH:MM:SS")
+		return fmt.Errorf("invalid time format, use HH:MM:SS")
+	}
+
+	err := r.db.QueryRow(ctx, query, shift.Name, shift.StartTime, shift.EndTime, shift.ID, shared.ExpectedVersion(shift.Version)).Scan(&shift.Version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			shared.Logger(ctx).Info().Int("shift_id", shift.ID).Msg("No rows updated")
+			return resolveMissedUpdate(ctx, r.db, "shifts", shift.ID) // repository.ErrNoRows atau repository.ErrVersionConflict
+		}
+		shared.Logger(ctx).Error().Err(err).Int("shift_id", shift.ID).Msg("Error updating shift")
+		return fmt.Errorf("error updating shift id %d: %w", shift.ID, err)
+	}
+	shared.Logger(ctx).Info().Int("shift_id", shift.ID).Int("version", shift.Version).Msg("Shift updated successfully")
+	return nil
+}
+
+// CreateShiftOverride menyimpan override shift musiman. Mengembalikan pgx.ErrNoRows jika
+// o.ShiftID merujuk shift yang tidak ada. ID dan CreatedAt ditulis kembali ke o.
+func (r *shiftRepo) CreateShiftOverride(ctx context.Context, o *models.ShiftOverride) (int, error) {
+	query := `INSERT INTO shift_overrides (name, shift_id, start_date, end_date, start_time, end_time,
+                                           start_offset_minutes, end_offset_minutes, created_by)
+              VALUES ($1, $2, $3, $4, time($5), time($6), $7, $8, $9)
+              RETURNING id, created_at`
+	err := r.db.QueryRow(ctx, query, o.Name, o.ShiftID, o.StartDate, o.EndDate, o.StartTime, o.EndTime,
+		o.StartOffsetMinutes, o.EndOffsetMinutes, o.CreatedBy).Scan(&o.ID, &o.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // FK lain (created_by) tidak bisa dilanggar pemanggil
+			shared.Logger(ctx).Warn().Err(err).Interface("shift_id", o.ShiftID).Msg("Shift override references unknown shift")
+			return 0, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Msg("Error creating shift override")
+		return 0, fmt.Errorf("error creating shift override: %w", err)
+	}
+	shared.Logger(ctx).Info().Int("override_id", o.ID).Str("start_date", o.StartDate).Str("end_date", o.EndDate).Msg("Shift override created successfully")
+	return o.ID, nil
+}
+
+// GetShiftOverrides mengembalikan override shift, terbaru (tanggal mulai terakhir) lebih dulu.
+// activeFrom (opsional) menyaring override yang belum berakhir pada tanggal tersebut.
+func (r *shiftRepo) GetShiftOverrides(ctx context.Context, activeFrom *time.Time) ([]models.ShiftOverride, error) {
+	query := `SELECT ` + shared.SelectList("sov", shared.ShiftOverrideColumns) + `
+              FROM shift_overrides sov
+              WHERE $1 IS NULL OR sov.end_date >= $1
+              ORDER BY sov.start_date DESC, sov.id DESC`
+	var from *string
+	if activeFrom != nil {
+		s := activeFrom.Format(shared.DateLayout)
+		from = &s
+	}
+	rows, err := r.db.Query(ctx, query, from)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error getting shift overrides")
+		return nil, fmt.Errorf("error getting shift overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := []models.ShiftOverride{}
+	for rows.Next() {
+		var o models.ShiftOverride
+		if err := shared.ScanShiftOverride(rows, &o); err != nil {
+			return nil, fmt.Errorf("error scanning shift override row: %w", err)
+		}
+		overrides = append(overrides, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shift override rows: %w", err)
+	}
+	return overrides, nil
+}
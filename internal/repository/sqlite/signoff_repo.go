@@ -0,0 +1,243 @@
+// internal/repository/sqlite/signoff_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Sign-off harian absensi oleh atasan. Atasan hanya bisa men-sign-off anggota timnya
+// (bawahan langsung & tidak langsung menurut users.manager_id). Tanggal kerja mengikuti tanggal
+// check-in (zona waktu server), sama seperti periode payroll. Setelah di-sign-off, mutasi absensi
+// pada tanggal tersebut ditolak dengan *repository.AttendanceSignedOffError.
+//
+// Sign-off dan mutasi absensi masing-masing berjalan dalam transaksi tulis; SQLite menyerialkan
+// transaksi tulis, sehingga sign-off menunggu mutasi absensi yang sedang berjalan (dan sebaliknya).
+
+type signOffRepo struct {
+	repository.SignOffRepository
+	db *database
+}
+
+// teamQuery mengembalikan id user aktif di bawah $1 (tidak termasuk $1 sendiri).
+const teamQuery = `
+        WITH RECURSIVE tree AS (
+            SELECT u.id, ',' || u.id || ',' AS path FROM users u WHERE u.id = $1
+            UNION ALL
+            SELECT c.id, t.path || c.id || ','
+            FROM users c
+            JOIN tree t ON c.manager_id = t.id
+            WHERE c.is_active AND instr(t.path, ',' || c.id || ',') = 0
+        )
+        SELECT id FROM tree WHERE id <> $1 ORDER BY id`
+
+// SignOffDay men-sign-off absensi anggota tim managerID pada tanggal day (awal hari, zona waktu server).
+// signerID dicatat sebagai signed_by: managerID sendiri, atau pemegang delegasinya.
+// userIDs kosong = seluruh tim. Hasil per user: updated, unchanged (sudah di-sign-off), not_found
+// (bukan anggota tim), atau skipped (sesi absensi pada tanggal tersebut masih terbuka).
+// grace adalah toleransi keterlambatan untuk pengecualian late.
+func (r *signOffRepo) SignOffDay(ctx context.Context, managerID, signerID int, day time.Time, userIDs []int, grace time.Duration, note *string) ([]models.SignOffResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting sign-off transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	rows, err := tx.Query(ctx, teamQuery, managerID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading team of user %d: %w", managerID, err)
+	}
+	team, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("error scanning team of user %d: %w", managerID, err)
+	}
+
+	results := []models.SignOffResult{}
+	targets := team
+	if len(userIDs) > 0 {
+		targets = nil
+		for _, id := range userIDs {
+			switch {
+			case slices.Contains(targets, id):
+				// Duplikat di input
+			case slices.Contains(team, id):
+				targets = append(targets, id)
+			default:
+				results = append(results, models.SignOffResult{UserID: id, Status: models.BulkStatusNotFound, Message: "User is not in your team"})
+			}
+		}
+		slices.Sort(targets)
+	}
+
+	workDate := day.Format(shared.DateLayout)
+	for _, userID := range targets {
+		result, err := signOffUser(ctx, tx, signerID, userID, day, grace, note)
+		if err != nil {
+			shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Str("work_date", workDate).Msg("Error signing off attendance")
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing sign-off: %w", err)
+	}
+	shared.Logger(ctx).Info().Int("manager_id", managerID).Int("signer_id", signerID).Str("work_date", workDate).Int("users", len(targets)).Msg("Attendance signed off")
+	return results, nil
+}
+
+// signOffUser men-sign-off satu user dalam transaksi SignOffDay.
+func signOffUser(ctx context.Context, tx pgx.Tx, signerID, userID int, day time.Time, grace time.Duration, note *string) (*models.SignOffResult, error) {
+	workDate := day.Format(shared.DateLayout)
+
+	var signed, scheduled bool
+	query := `SELECT EXISTS (SELECT 1 FROM attendance_signoffs WHERE user_id = $1 AND work_date = $2),
+                     EXISTS (SELECT 1 FROM user_schedules WHERE user_id = $1 AND date = $2)`
+	if err := tx.QueryRow(ctx, query, userID, workDate).Scan(&signed, &scheduled); err != nil {
+		return nil, fmt.Errorf("error checking sign-off of user %d: %w", userID, err)
+	}
+	if signed {
+		return &models.SignOffResult{UserID: userID, Status: models.BulkStatusUnchanged, Message: "Already signed off"}, nil
+	}
+
+	// Absensi hari itu beserta jam mulai shift jadwal yang ditautkan dan tanda koreksi.
+	query = `SELECT a.check_out_at, us.date, s.start_time, a.check_in_at,
+                    EXISTS (SELECT 1 FROM attendance_events e WHERE e.attendance_id = a.id AND e.event_type = $4)
+             FROM attendances a
+             LEFT JOIN user_schedules us ON us.id = a.schedule_id
+             LEFT JOIN schedule_shifts s ON s.schedule_id = us.id
+             WHERE a.user_id = $1 AND a.check_in_at >= $2 AND a.check_in_at < $3
+             ORDER BY a.check_in_at`
+	rows, err := tx.Query(ctx, query, userID, day, day.AddDate(0, 0, 1), models.AttendanceEventCorrection)
+	if err != nil {
+		return nil, fmt.Errorf("error loading attendance of user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	exceptions := []string{}
+	flag := func(exception string) {
+		if !slices.Contains(exceptions, exception) {
+			exceptions = append(exceptions, exception)
+		}
+	}
+	sessions, open := 0, false
+	for rows.Next() {
+		var (
+			checkInAt             time.Time
+			checkOutAt            *time.Time
+			schedDate, shiftStart *string
+			corrected             bool
+		)
+		if err := rows.Scan(&checkOutAt, &schedDate, &shiftStart, &checkInAt, &corrected); err != nil {
+			return nil, fmt.Errorf("error scanning attendance row: %w", err)
+		}
+		sessions++
+		open = open || checkOutAt == nil
+		if schedDate == nil || shiftStart == nil {
+			flag(models.SignOffExceptionUnscheduled)
+		} else if start, err := time.ParseInLocation(shared.DateLayout+" 15:04:05", *schedDate+" "+*shiftStart, day.Location()); err == nil && checkInAt.After(start.Add(grace)) {
+			flag(models.SignOffExceptionLate)
+		}
+		if corrected {
+			flag(models.SignOffExceptionCorrected)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attendance rows: %w", err)
+	}
+	rows.Close()
+
+	if open {
+		return &models.SignOffResult{UserID: userID, Status: models.BulkStatusSkipped, Message: "Attendance session is still open"}, nil
+	}
+	if sessions == 0 && scheduled {
+		flag(models.SignOffExceptionNoShow)
+	}
+
+	query = `INSERT INTO attendance_signoffs (user_id, work_date, signed_by, exceptions, note) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.Exec(ctx, query, userID, workDate, signerID, exceptions, note); err != nil {
+		return nil, fmt.Errorf("error inserting sign-off of user %d: %w", userID, err)
+	}
+	return &models.SignOffResult{UserID: userID, Status: models.BulkStatusUpdated, Exceptions: exceptions}, nil
+}
+
+// GetUnsignedDays mengembalikan tanggal kerja (ada jadwal atau check-in) dalam [startDate, endDate]
+// yang belum di-sign-off. managerID nil = seluruh organisasi; selain itu hanya anggota timnya.
+// Diurutkan berdasarkan tanggal lalu username.
+func (r *signOffRepo) GetUnsignedDays(ctx context.Context, managerID *int, startDate, endDate time.Time) ([]models.UnsignedDay, error) {
+	query := `
+        WITH RECURSIVE tree AS (
+            SELECT u.id, ',' || u.id || ',' AS path FROM users u WHERE u.id = $1
+            UNION ALL
+            SELECT c.id, t.path || c.id || ','
+            FROM users c
+            JOIN tree t ON c.manager_id = t.id
+            WHERE c.is_active AND instr(t.path, ',' || c.id || ',') = 0
+        ),
+        work_days AS (
+            SELECT us.user_id, us.date AS work_date FROM user_schedules us WHERE us.date BETWEEN $2 AND $3
+            UNION
+            SELECT a.user_id, date(a.check_in_at, 'localtime') FROM attendances a WHERE date(a.check_in_at, 'localtime') BETWEEN $2 AND $3
+        )
+        SELECT u.id, u.username, u.manager_id, w.work_date
+        FROM work_days w
+        JOIN users u ON u.id = w.user_id
+        WHERE u.is_active
+          AND ($1 IS NULL OR (u.id <> $1 AND u.id IN (SELECT id FROM tree)))
+          AND NOT EXISTS (SELECT 1 FROM attendance_signoffs so WHERE so.user_id = w.user_id AND so.work_date = w.work_date)
+        ORDER BY w.work_date, u.username`
+	rows, err := r.db.Query(ctx, query, managerID, startDate.Format(shared.DateLayout), endDate.Format(shared.DateLayout))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Interface("manager_id", managerID).Msg("Error querying unsigned days")
+		return nil, fmt.Errorf("error getting unsigned days: %w", err)
+	}
+	defer rows.Close()
+
+	days := []models.UnsignedDay{}
+	for rows.Next() {
+		var d models.UnsignedDay
+		if err := rows.Scan(&d.UserID, &d.Username, &d.ManagerID, &d.WorkDate); err != nil {
+			return nil, fmt.Errorf("error scanning unsigned day row: %w", err)
+		}
+		days = append(days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unsigned day rows: %w", err)
+	}
+	return days, nil
+}
+
+// checkNotSignedOff mengembalikan *repository.AttendanceSignedOffError jika salah satu tanggal (berdasarkan
+// waktu yang diberikan) sudah di-sign-off untuk userID.
+func checkNotSignedOff(ctx context.Context, tx pgx.Tx, userID int, times ...time.Time) error {
+	dates := make([]string, len(times))
+	for i, t := range times {
+		dates[i] = t.Format(shared.DateLayout)
+	}
+	query := `SELECT ` + shared.SelectList("so", attendanceSignOffColumns) + `
+              FROM attendance_signoffs so
+              WHERE so.user_id = $1 AND so.work_date IN (SELECT value FROM json_each($2))
+              ORDER BY so.work_date
+              LIMIT 1`
+	so := models.AttendanceSignOff{}
+	err := shared.ScanAttendanceSignOff(tx.QueryRow(ctx, query, userID, dates), &so)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error checking attendance sign-off: %w", err)
+	}
+	shared.Logger(ctx).Warn().Int("user_id", userID).Str("work_date", so.WorkDate).Msg("Attendance change rejected: day signed off")
+	return &repository.AttendanceSignedOffError{SignOff: so}
+}
@@ -0,0 +1,591 @@
+// internal/repository/sqlite/sqlite.go
+
+//go:build sqlite
+
+// Package sqlite mengimplementasikan semua interface repository di atas SQLite (DB_DRIVER=sqlite)
+// untuk instalasi kecil satu instance tanpa server PostgreSQL. Hanya di-build dengan tag sqlite
+// (go build -tags sqlite) agar driver SQLite tidak ikut ke binary PostgreSQL.
+//
+// Skema ada di migrations/sqlite dan diterapkan database.NewSQLiteDB. Setiap repository di sini
+// meng-embed repository PostgreSQL (repository.NewRepositoriesWithConn) yang berjalan di atas
+// adapter database, sehingga method yang teks SQL-nya sama untuk kedua dialek tidak ditulis ulang;
+// package ini hanya menimpa method yang SQL-nya berbeda. Helper yang tidak bergantung dialek
+// (registry kolom, enkripsi PII, builder SET, logger) ada di internal/repository/internal/shared.
+// Penyesuaian dialeknya:
+//   - Timestamp disimpan sebagai teks UTC berpresisi tetap, sehingga perbandingan teks sama
+//     dengan perbandingan waktu; argumen time.Time dikonversi otomatis (lihat toArg).
+//   - Tanggal (DATE) disimpan sebagai teks YYYY-MM-DD, sehingga argumen yang dibandingkan
+//     dengan kolom tanggal dikirim sebagai t.Format(shared.DateLayout), bukan time.Time. Tanggal
+//     lokal sebuah timestamp diambil dengan date(kolom, 'localtime') (pengganti ::date).
+//   - Array & JSONB disimpan sebagai teks JSON, dan parameter array dibaca dengan json_each.
+//   - Error constraint SQLite dipetakan ke *pgconn.PgError dengan kode SQLSTATE yang sama
+//     (23505, 23503, 23514, 23P01, ...) dan sql.ErrNoRows ke pgx.ErrNoRows, sehingga handler
+//     memperlakukan kedua backend dengan cara yang sama.
+//   - Fungsi NOW() didaftarkan ke driver dan mengembalikan waktu saat ini dalam format yang sama.
+//
+// SQLite hanya mengizinkan satu penulis. Karena itu semua query repository ikut transaksi
+// TxManager.RunInTx jika ctx membawanya (PostgreSQL hanya untuk tulis), agar pembacaan di
+// tengah transaksi tidak menunggu lock yang dipegang transaksi itu sendiri.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/pii"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+	sqlite "modernc.org/sqlite"
+)
+
+// timeLayout adalah format timestamp tersimpan: UTC dengan nanodetik berlebar tetap.
+const timeLayout = "2006-01-02 15:04:05.000000000"
+
+func init() {
+	// NOW() seperti PostgreSQL, dengan format timestamp tersimpan.
+	sqlite.MustRegisterScalarFunction("now", 0, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+		return time.Now().UTC().Format(timeLayout), nil
+	})
+}
+
+// NewRepositories membuat semua repository SQLite di atas db. Repository yang menyentuh data
+// pribadi user menerima protector untuk enkripsi kolom PII. Method yang tidak ditimpa di package
+// ini diteruskan ke repository PostgreSQL (base) yang memakai adapter yang sama; repository yang
+// tidak punya query khusus SQLite (DebugCaptures) langsung memakai base.
+func NewRepositories(sqlDB *sql.DB, protector *pii.Protector) repository.Repositories {
+	db := &database{sql: sqlDB}
+	base := repository.NewRepositoriesWithConn(db, protector)
+	return repository.Repositories{
+		Users:            &userRepo{UserRepository: base.Users, db: db, pii: protector},
+		Roles:            &roleRepo{RoleRepository: base.Roles, db: db},
+		Shifts:           &shiftRepo{ShiftRepository: base.Shifts, db: db},
+		Schedules:        &scheduleRepo{ScheduleRepository: base.Schedules, db: db, pii: protector},
+		Attendance:       &attendanceRepo{AttendanceRepository: base.Attendance, db: db, pii: protector},
+		Settings:         &settingsRepo{SettingsRepository: base.Settings, db: db},
+		Announcements:    &announcementRepo{AnnouncementRepository: base.Announcements, db: db},
+		Documents:        &documentRepo{DocumentRepository: base.Documents, db: db},
+		Audit:            &auditRepo{AuditRepository: base.Audit, db: db},
+		Payroll:          &payrollRepo{PayrollRepository: base.Payroll, db: db},
+		Projects:         &projectRepo{ProjectRepository: base.Projects, db: db},
+		SignOffs:         &signOffRepo{SignOffRepository: base.SignOffs, db: db},
+		Disputes:         &disputeRepo{DisputeRepository: base.Disputes, db: db},
+		Devices:          &deviceRepo{DeviceRepository: base.Devices, db: db},
+		Delegations:      &delegationRepo{DelegationRepository: base.Delegations, db: db},
+		Escalations:      &escalationRepo{EscalationRepository: base.Escalations, db: db},
+		Jobs:             &jobRepo{JobRepository: base.Jobs, db: db},
+		Labor:            &laborRepo{LaborRepository: base.Labor, db: db},
+		Notifications:    &notificationRepo{NotificationRepository: base.Notifications, db: db},
+		Outbox:           &outboxRepo{OutboxRepository: base.Outbox, db: db},
+		Verification:     &verificationRepo{VerificationRepository: base.Verification, db: db},
+		Kiosks:           &kioskRepo{KioskRepository: base.Kiosks, db: db},
+		AttendancePhotos: &attendancePhotoRepo{AttendancePhotoRepository: base.AttendancePhotos, db: db},
+		ReportExports:    &reportExportRepo{ReportExportRepository: base.ReportExports, db: db},
+		DebugCaptures:    base.DebugCaptures,
+		Backups:          &backupRepo{BackupRepository: base.Backups, db: db},
+		AppInstances:     &appInstanceRepo{AppInstanceRepository: base.AppInstances, db: db},
+		Tx:               &txManager{db: db},
+	}
+}
+
+// --- Koneksi & transaksi ---
+
+// database membungkus *sql.DB dengan API pgx (repository.Conn: Exec/Query/QueryRow/Begin dengan
+// ctx) beserta konversi argumen, destinasi Scan, dan error, sehingga repository PostgreSQL bisa
+// dipakai langsung untuk query yang dialeknya sama.
+type database struct {
+	sql *sql.DB
+}
+
+var _ repository.Conn = (*database)(nil)
+
+// querier dipenuhi oleh *sql.DB maupun *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type ctxTxKey struct{}
+
+// txState adalah transaksi yang dibawa ctx dari RunInTx; savepoints menghitung Begin bertingkat.
+type txState struct {
+	tx         *sql.Tx
+	savepoints int
+}
+
+// conn mengembalikan transaksi dari ctx jika ada, atau pool.
+func (d *database) conn(ctx context.Context) querier {
+	if st, ok := ctx.Value(ctxTxKey{}).(*txState); ok {
+		return st.tx
+	}
+	return d.sql
+}
+
+func (d *database) Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	return execOn(ctx, d.conn(ctx), query, args)
+}
+
+func (d *database) Query(ctx context.Context, query string, args ...any) (pgx.Rows, error) {
+	return queryOn(ctx, d.conn(ctx), query, args)
+}
+
+func (d *database) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	return &row{row: d.conn(ctx).QueryRowContext(ctx, query, toArgs(args)...)}
+}
+
+// Begin memulai transaksi milik method repository. Di dalam RunInTx transaksi ini menjadi
+// savepoint dari transaksi luar, sehingga commit-nya baru permanen saat transaksi luar di-commit.
+func (d *database) Begin(ctx context.Context) (pgx.Tx, error) {
+	if st, ok := ctx.Value(ctxTxKey{}).(*txState); ok {
+		return beginSavepoint(ctx, st)
+	}
+	sqlTx, err := d.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &transaction{q: sqlTx, sqlTx: sqlTx, state: &txState{tx: sqlTx}}, nil
+}
+
+func beginSavepoint(ctx context.Context, st *txState) (*transaction, error) {
+	st.savepoints++
+	name := fmt.Sprintf("sp_%d", st.savepoints)
+	if _, err := st.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		st.savepoints--
+		return nil, mapError(err)
+	}
+	return &transaction{q: st.tx, state: st, savepoint: name}, nil
+}
+
+// transaction adalah transaksi (atau savepoint) yang memenuhi pgx.Tx. Fitur khusus protokol
+// PostgreSQL (COPY, batch, large object, prepared statement) tidak didukung.
+type transaction struct {
+	q         querier
+	sqlTx     *sql.Tx  // Transaksi sendiri; nil jika savepoint
+	state     *txState // Transaksi luar (atau transaksi ini sendiri) untuk savepoint bertingkat
+	savepoint string
+	done      bool
+}
+
+var errUnsupported = errors.New("sqlite: operation not supported")
+
+func (t *transaction) Begin(ctx context.Context) (pgx.Tx, error) {
+	return beginSavepoint(ctx, t.state)
+}
+
+func (t *transaction) Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	return execOn(ctx, t.q, query, args)
+}
+
+func (t *transaction) Query(ctx context.Context, query string, args ...any) (pgx.Rows, error) {
+	return queryOn(ctx, t.q, query, args)
+}
+
+func (t *transaction) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	return &row{row: t.q.QueryRowContext(ctx, query, toArgs(args)...)}
+}
+
+func (t *transaction) Commit(ctx context.Context) error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	if t.sqlTx != nil {
+		return mapError(t.sqlTx.Commit())
+	}
+	defer func() { t.state.savepoints-- }()
+	_, err := t.q.ExecContext(ctx, "RELEASE "+t.savepoint)
+	return mapError(err)
+}
+
+// Rollback membatalkan transaksi; no-op jika sudah di-commit atau di-rollback.
+func (t *transaction) Rollback(ctx context.Context) error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	if t.sqlTx != nil {
+		return t.sqlTx.Rollback()
+	}
+	defer func() { t.state.savepoints-- }()
+	if _, err := t.q.ExecContext(ctx, "ROLLBACK TO "+t.savepoint); err != nil {
+		return err
+	}
+	_, err := t.q.ExecContext(ctx, "RELEASE "+t.savepoint)
+	return err
+}
+
+func (t *transaction) CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error) {
+	return 0, errUnsupported
+}
+
+func (t *transaction) SendBatch(context.Context, *pgx.Batch) pgx.BatchResults { return nil }
+
+func (t *transaction) LargeObjects() pgx.LargeObjects { return pgx.LargeObjects{} }
+
+func (t *transaction) Prepare(context.Context, string, string) (*pgconn.StatementDescription, error) {
+	return nil, errUnsupported
+}
+
+func (t *transaction) Conn() *pgx.Conn { return nil }
+
+// txManager mengimplementasikan repository.TxManager.
+type txManager struct {
+	db *database
+}
+
+func (m *txManager) RunInTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(ctxTxKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+	sqlTx, err := m.db.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return mapError(err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = sqlTx.Rollback()
+			panic(p)
+		}
+	}()
+	if err := fn(context.WithValue(ctx, ctxTxKey{}, &txState{tx: sqlTx})); err != nil {
+		_ = sqlTx.Rollback()
+		return err
+	}
+	return mapError(sqlTx.Commit())
+}
+
+func execOn(ctx context.Context, q querier, query string, args []any) (pgconn.CommandTag, error) {
+	res, err := q.ExecContext(ctx, query, toArgs(args)...)
+	if err != nil {
+		return pgconn.CommandTag{}, mapError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pgconn.CommandTag{}, mapError(err)
+	}
+	// RowsAffected pgconn.CommandTag dibaca dari angka di akhir tag.
+	return pgconn.NewCommandTag(fmt.Sprintf("EXEC %d", n)), nil
+}
+
+func queryOn(ctx context.Context, q querier, query string, args []any) (pgx.Rows, error) {
+	r, err := q.QueryContext(ctx, query, toArgs(args)...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &rows{rows: r}, nil
+}
+
+// row memenuhi pgx.Row: error query muncul saat Scan.
+type row struct {
+	row *sql.Row
+}
+
+func (r *row) Scan(dest ...any) error {
+	return mapError(r.row.Scan(toDests(dest)...))
+}
+
+// rows memenuhi pgx.Rows (cukup untuk Next/Scan dan pgx.CollectRows). Metadata kolom dan
+// nilai mentah protokol PostgreSQL tidak tersedia.
+type rows struct {
+	rows *sql.Rows
+}
+
+func (r *rows) Next() bool { return r.rows.Next() }
+
+func (r *rows) Scan(dest ...any) error { return mapError(r.rows.Scan(toDests(dest)...)) }
+
+func (r *rows) Err() error { return mapError(r.rows.Err()) }
+
+func (r *rows) Close() { _ = r.rows.Close() }
+
+func (r *rows) CommandTag() pgconn.CommandTag { return pgconn.CommandTag{} }
+
+func (r *rows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+
+func (r *rows) Values() ([]any, error) { return nil, errUnsupported }
+
+func (r *rows) RawValues() [][]byte { return nil }
+
+func (r *rows) Conn() *pgx.Conn { return nil }
+
+// --- Konversi argumen & destinasi Scan ---
+
+// toArgs mengonversi argumen query ke nilai yang disimpan SQLite: waktu ke teks UTC
+// (timeLayout), slice/map/json.RawMessage ke teks JSON.
+func toArgs(args []any) []any {
+	out := make([]any, len(args))
+	for i, arg := range args {
+		out[i] = toArg(arg)
+	}
+	return out
+}
+
+func toArg(arg any) any {
+	switch v := arg.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		return formatTime(v)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return formatTime(*v)
+	case json.RawMessage:
+		if v == nil {
+			return nil
+		}
+		return string(v)
+	case []byte, driver.Valuer:
+		return arg
+	}
+	rv := reflect.ValueOf(arg)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		if rv.IsNil() {
+			if rv.Kind() == reflect.Map {
+				return "{}"
+			}
+			return "[]"
+		}
+		b, err := json.Marshal(arg)
+		if err != nil {
+			return arg // Biarkan driver yang menolak
+		}
+		return string(b)
+	}
+	return arg
+}
+
+// formatTime memformat t sebagai timestamp tersimpan.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// hoursBetween menyusun ekspresi SQL selisih dua timestamp dalam jam, pengganti
+// EXTRACT(EPOCH FROM (to - from)) / 3600 di PostgreSQL.
+func hoursBetween(from, to string) string {
+	return "((julianday(" + to + ") - julianday(" + from + ")) * 24)"
+}
+
+// nowPlusSeconds menyusun ekspresi SQL waktu sekarang ditambah expr detik dalam format timestamp
+// tersimpan, pengganti NOW() + expr * INTERVAL '1 second' di PostgreSQL.
+func nowPlusSeconds(expr string) string {
+	return "strftime('%Y-%m-%d %H:%M:%f000000', 'now', (" + expr + ") || ' seconds')"
+}
+
+// toDests membungkus destinasi Scan yang butuh konversi dari nilai SQLite.
+func toDests(dest []any) []any {
+	out := make([]any, len(dest))
+	for i, d := range dest {
+		out[i] = toDest(d)
+	}
+	return out
+}
+
+func toDest(dest any) any {
+	switch d := dest.(type) {
+	case *time.Time:
+		return &timeDest{dst: d}
+	case **time.Time:
+		return &nullTimeDest{dst: d}
+	case *json.RawMessage:
+		return &rawJSONDest{dst: d}
+	case sql.Scanner:
+		return dest
+	}
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		switch elem := rv.Elem(); elem.Kind() {
+		case reflect.Slice:
+			if elem.Type().Elem().Kind() != reflect.Uint8 {
+				return &jsonDest{dst: dest}
+			}
+		case reflect.Map:
+			return &jsonDest{dst: dest}
+		}
+	}
+	return dest
+}
+
+// parseTime membaca timestamp dari nilai SQLite (time.Time untuk kolom TIMESTAMP, teks untuk
+// hasil ekspresi). Waktu dikembalikan dalam zona lokal seperti pgx.
+func parseTime(src any) (time.Time, error) {
+	switch v := src.(type) {
+	case time.Time:
+		return v.Local(), nil
+	case string:
+		return parseTimeString(v)
+	case []byte:
+		return parseTimeString(string(v))
+	}
+	return time.Time{}, fmt.Errorf("cannot scan %T into time.Time", src)
+}
+
+var timeFormats = []string{timeLayout, "2006-01-02 15:04:05.999999999", time.RFC3339Nano, "2006-01-02T15:04:05.999999999", shared.DateLayout}
+
+func parseTimeString(s string) (time.Time, error) {
+	for _, layout := range timeFormats {
+		t, err := time.ParseInLocation(layout, s, time.UTC)
+		if err != nil {
+			continue
+		}
+		if layout == shared.DateLayout {
+			return t, nil // Tanggal tetap tengah malam UTC, seperti DATE dari pgx
+		}
+		return t.Local(), nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as timestamp", s)
+}
+
+type timeDest struct{ dst *time.Time }
+
+func (d *timeDest) Scan(src any) error {
+	if src == nil {
+		return errors.New("cannot scan NULL into time.Time")
+	}
+	t, err := parseTime(src)
+	if err != nil {
+		return err
+	}
+	*d.dst = t
+	return nil
+}
+
+type nullTimeDest struct{ dst **time.Time }
+
+func (d *nullTimeDest) Scan(src any) error {
+	if src == nil {
+		*d.dst = nil
+		return nil
+	}
+	t, err := parseTime(src)
+	if err != nil {
+		return err
+	}
+	*d.dst = &t
+	return nil
+}
+
+// jsonDest men-decode kolom teks JSON ke slice/map; NULL menjadi nilai kosong.
+type jsonDest struct{ dst any }
+
+func (d *jsonDest) Scan(src any) error {
+	elem := reflect.ValueOf(d.dst).Elem()
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		elem.Set(reflect.Zero(elem.Type()))
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("cannot scan %T into %s", src, elem.Type())
+	}
+	// Nilai baru agar elemen lama (mis. map yang dipakai ulang) tidak tercampur.
+	fresh := reflect.New(elem.Type())
+	if err := json.Unmarshal(b, fresh.Interface()); err != nil {
+		return fmt.Errorf("error decoding JSON column into %s: %w", elem.Type(), err)
+	}
+	elem.Set(fresh.Elem())
+	return nil
+}
+
+type rawJSONDest struct{ dst *json.RawMessage }
+
+func (d *rawJSONDest) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d.dst = nil
+	case string:
+		*d.dst = json.RawMessage(v)
+	case []byte:
+		*d.dst = append(json.RawMessage(nil), v...)
+	default:
+		return fmt.Errorf("cannot scan %T into json.RawMessage", src)
+	}
+	return nil
+}
+
+// --- Error ---
+
+// Kode extended SQLite untuk constraint (https://sqlite.org/rescode.html).
+const (
+	sqliteConstraintCheck      = 275
+	sqliteConstraintForeignKey = 787
+	sqliteConstraintNotNull    = 1299
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintTrigger    = 1811
+	sqliteConstraintUnique     = 2067
+)
+
+var errCodeSuffix = regexp.MustCompile(` \(\d+\)$`)
+
+// mapError menerjemahkan error SQLite ke bentuk yang dipakai repository PostgreSQL:
+// sql.ErrNoRows menjadi pgx.ErrNoRows dan pelanggaran constraint menjadi *pgconn.PgError.
+// ConstraintName berisi nama constraint/index dari pesan SQLite, atau tabel.kolom untuk
+// constraint UNIQUE berbasis kolom (mis. "users.username").
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return pgx.ErrNoRows
+	}
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+	msg := strings.TrimPrefix(errCodeSuffix.ReplaceAllString(sqliteErr.Error(), ""), "constraint failed: ")
+	pgErr := &pgconn.PgError{Severity: "ERROR", Message: msg}
+	switch sqliteErr.Code() {
+	case sqliteConstraintUnique, sqliteConstraintPrimaryKey:
+		pgErr.Code = "23505"
+		pgErr.ConstraintName = constraintName(msg, "UNIQUE constraint failed: ")
+	case sqliteConstraintForeignKey:
+		pgErr.Code = "23503"
+	case sqliteConstraintCheck:
+		pgErr.Code = "23514"
+		pgErr.ConstraintName = constraintName(msg, "CHECK constraint failed: ")
+	case sqliteConstraintNotNull:
+		pgErr.Code = "23502"
+		pgErr.ConstraintName = constraintName(msg, "NOT NULL constraint failed: ")
+	case sqliteConstraintTrigger:
+		// RAISE(ABORT, '<nama>') dari trigger; nama excl_* meniru EXCLUDE constraint.
+		// Foreign key ON DELETE RESTRICT juga dilaporkan SQLite dengan kode ini.
+		pgErr.ConstraintName = msg
+		switch {
+		case msg == "FOREIGN KEY constraint failed":
+			pgErr.Code = "23503"
+			pgErr.ConstraintName = ""
+		case strings.HasPrefix(msg, "excl_"):
+			pgErr.Code = "23P01"
+		case strings.HasSuffix(msg, "_check"):
+			pgErr.Code = "23514"
+		default:
+			pgErr.Code = "P0001"
+		}
+	default:
+		return err
+	}
+	return pgErr
+}
+
+// constraintName mengambil nama constraint dari pesan SQLite setelah prefix.
+func constraintName(msg, prefix string) string {
+	name := strings.TrimPrefix(msg, prefix)
+	name = strings.TrimPrefix(name, "index '")
+	return strings.TrimSuffix(name, "'")
+}
@@ -0,0 +1,163 @@
+// internal/repository/sqlite/sync_changes.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Sinkronisasi delta untuk sistem eksternal: perubahan (updated_at) dan penghapusan
+// (sync_tombstones) satu tabel dibaca sebagai satu aliran dengan urutan keyset
+// (waktu perubahan, id). Timestamp tersimpan berlebar tetap, sehingga perbandingan keyset
+// pada teks sama dengan perbandingan waktu.
+
+// syncChanges membaca maksimal limit perubahan entity setelah cursor yang sudah lewat
+// SyncSettleSeconds (transaksi yang lebih lama dari itu bisa commit dengan updated_at di
+// belakang watermark). Mengembalikan id record yang berubah (urut keyset) dan batch berisi
+// tombstone, watermark berikutnya, dan HasMore; Items diisi pemanggil.
+func syncChanges(ctx context.Context, db *database, entity string, cursor models.SyncCursor, limit int) ([]int, *models.SyncBatch, error) {
+	if !shared.SyncTables[entity] {
+		return nil, nil, fmt.Errorf("unsupported sync entity %q", entity)
+	}
+	query := `SELECT c.id, c.changed_at, c.deleted FROM (
+                  SELECT t.id, t.updated_at AS changed_at, FALSE AS deleted FROM ` + entity + ` t
+                  UNION ALL
+                  SELECT st.entity_id, st.deleted_at, TRUE FROM sync_tombstones st WHERE st.entity = $1
+              ) c
+              WHERE (c.changed_at, c.id) > ($2, $3) AND c.changed_at <= $4
+              ORDER BY c.changed_at, c.id
+              LIMIT $5`
+	settle := time.Duration(models.SyncSettleSeconds) * time.Second
+	rows, err := db.Query(ctx, query, entity, cursor.UpdatedSince, cursor.AfterID, time.Now().Add(-settle), limit+1)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("entity", entity).Time("updated_since", cursor.UpdatedSince).Msg("Error querying sync changes")
+		return nil, nil, fmt.Errorf("error getting %s changes: %w", entity, err)
+	}
+	defer rows.Close()
+
+	batch := &models.SyncBatch{Deleted: []models.SyncTombstone{}, NextUpdatedSince: cursor.UpdatedSince, NextAfterID: cursor.AfterID}
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		var changedAt time.Time
+		var deleted bool
+		if err := rows.Scan(&id, &changedAt, &deleted); err != nil {
+			return nil, nil, fmt.Errorf("error scanning %s change row: %w", entity, err)
+		}
+		if len(ids)+len(batch.Deleted) == limit {
+			batch.HasMore = true
+			break
+		}
+		if deleted {
+			batch.Deleted = append(batch.Deleted, models.SyncTombstone{ID: id, DeletedAt: changedAt})
+		} else {
+			ids = append(ids, id)
+		}
+		batch.NextUpdatedSince, batch.NextAfterID = changedAt, id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating %s change rows: %w", entity, err)
+	}
+	return ids, batch, nil
+}
+
+// GetUserChanges mengembalikan user yang berubah/dihapus setelah cursor (Items: []models.User).
+func (r *userRepo) GetUserChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+	ids, batch, err := syncChanges(ctx, r.db, models.SyncEntityUsers, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	users := []models.User{}
+	if len(ids) > 0 {
+		query := `SELECT ` + shared.SelectList("u", userColumns) + ` FROM users u WHERE u.id IN (SELECT value FROM json_each($1)) ORDER BY u.updated_at, u.id`
+		rows, err := r.db.Query(ctx, query, ids)
+		if err != nil {
+			shared.Logger(ctx).Error().Err(err).Msg("Error querying changed users")
+			return nil, fmt.Errorf("error getting changed users: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var user models.User
+			if err := shared.ScanUser(rows, &user); err != nil {
+				return nil, fmt.Errorf("error scanning changed user: %w", err)
+			}
+			if err := shared.DecryptUserPII(r.pii, &user); err != nil {
+				shared.Logger(ctx).Error().Err(err).Int("user_id", user.ID).Msg("Error decrypting user PII (sync)")
+				return nil, err
+			}
+			users = append(users, user)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating changed users: %w", err)
+		}
+	}
+	batch.Items = users
+	return batch, nil
+}
+
+// GetAttendanceChanges mengembalikan absensi yang berubah/dihapus setelah cursor (Items: []models.Attendance).
+func (r *attendanceRepo) GetAttendanceChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+	ids, batch, err := syncChanges(ctx, r.db, models.SyncEntityAttendances, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	attendances := []models.Attendance{}
+	if len(ids) > 0 {
+		query := `SELECT ` + shared.SelectList("a", shared.AttendanceColumns) + ` FROM attendances a WHERE a.id IN (SELECT value FROM json_each($1)) ORDER BY a.updated_at, a.id`
+		rows, err := r.db.Query(ctx, query, ids)
+		if err != nil {
+			shared.Logger(ctx).Error().Err(err).Msg("Error querying changed attendances")
+			return nil, fmt.Errorf("error getting changed attendances: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var a models.Attendance
+			if err := shared.ScanAttendance(rows, &a); err != nil {
+				return nil, fmt.Errorf("error scanning changed attendance: %w", err)
+			}
+			attendances = append(attendances, a)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating changed attendances: %w", err)
+		}
+	}
+	batch.Items = attendances
+	return batch, nil
+}
+
+// GetScheduleChanges mengembalikan jadwal yang berubah/dihapus setelah cursor (Items: []models.UserSchedule).
+func (r *scheduleRepo) GetScheduleChanges(ctx context.Context, cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+	ids, batch, err := syncChanges(ctx, r.db, models.SyncEntitySchedules, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	schedules := []models.UserSchedule{}
+	if len(ids) > 0 {
+		query := `SELECT ` + shared.SelectList("us", shared.ScheduleColumns) + ` FROM user_schedules us WHERE us.id IN (SELECT value FROM json_each($1)) ORDER BY us.updated_at, us.id`
+		rows, err := r.db.Query(ctx, query, ids)
+		if err != nil {
+			shared.Logger(ctx).Error().Err(err).Msg("Error querying changed schedules")
+			return nil, fmt.Errorf("error getting changed schedules: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var s models.UserSchedule
+			if err := shared.ScanSchedule(rows, &s); err != nil {
+				return nil, fmt.Errorf("error scanning changed schedule: %w", err)
+			}
+			schedules = append(schedules, s)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating changed schedules: %w", err)
+		}
+	}
+	batch.Items = schedules
+	return batch, nil
+}
@@ -0,0 +1,100 @@
+// internal/repository/sqlite/user_email_change.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Ganti email oleh user sendiri: email_change_requests menyimpan alamat baru (terenkripsi)
+// sampai user mengonfirmasinya lewat tautan di email; users.email baru berubah saat konfirmasi.
+
+// CreateEmailChangeRequest membuat permintaan ganti email dan menggantikan permintaan terbuka
+// sebelumnya milik user. repository.ErrEmailTaken jika email sudah dipakai user lain.
+func (r *userRepo) CreateEmailChangeRequest(ctx context.Context, userID int, newEmail string, expiresAt time.Time) (*models.EmailChangeRequest, error) {
+	encrypted, err := r.pii.Encrypt(newEmail)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting email: %w", err)
+	}
+	hash := r.pii.BlindIndex(newEmail)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting email change transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	var taken bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE email_hash = $1 AND id <> $2)`, hash, userID).Scan(&taken); err != nil {
+		return nil, fmt.Errorf("error checking email availability: %w", err)
+	}
+	if taken {
+		return nil, repository.ErrEmailTaken
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM email_change_requests WHERE user_id = $1 AND confirmed_at IS NULL`, userID); err != nil {
+		return nil, fmt.Errorf("error replacing email change request: %w", err)
+	}
+	query := `INSERT INTO email_change_requests AS ec (user_id, new_email, new_email_hash, expires_at)
+              VALUES ($1, $2, $3, $4)
+              RETURNING ` + returningList(shared.EmailChangeColumns)
+	ec := &models.EmailChangeRequest{}
+	if err := shared.ScanEmailChange(tx.QueryRow(ctx, query, userID, encrypted, hash, expiresAt), ec); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error creating email change request")
+		return nil, fmt.Errorf("error creating email change request: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing email change request: %w", err)
+	}
+	ec.NewEmail = newEmail
+	return ec, nil
+}
+
+// ConfirmEmailChange menerapkan permintaan id: users.email diganti dengan email baru dan
+// permintaan ditandai confirmed, dalam satu transaksi. pgx.ErrNoRows jika permintaan sudah
+// dikonfirmasi, dibatalkan, atau kedaluwarsa; repository.ErrEmailTaken jika email sudah dipakai user lain.
+func (r *userRepo) ConfirmEmailChange(ctx context.Context, id int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting email change transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	var userID int
+	var newEmail, newEmailHash string
+	err = tx.QueryRow(ctx, `UPDATE email_change_requests SET confirmed_at = NOW()
+              WHERE id = $1 AND confirmed_at IS NULL AND expires_at > NOW()
+              RETURNING user_id, new_email, new_email_hash`, id).Scan(&userID, &newEmail, &newEmailHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		return fmt.Errorf("error confirming email change request %d: %w", id, err)
+	}
+	// new_email sudah terenkripsi dengan format yang sama seperti users.email.
+	if _, err := tx.Exec(ctx, `UPDATE users SET email = $1, email_hash = $2 WHERE id = $3`, newEmail, newEmailHash, userID); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return repository.ErrEmailTaken
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error applying email change")
+		return fmt.Errorf("error applying email change for user %d: %w", userID, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing email change: %w", err)
+	}
+	shared.Logger(ctx).Info().Int("user_id", userID).Int("email_change_id", id).Msg("User email changed after confirmation")
+	return nil
+}
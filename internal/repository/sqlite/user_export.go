@@ -0,0 +1,82 @@
+// internal/repository/sqlite/user_export.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// ExportUsers mengalirkan seluruh user yang cocok dengan filter (beserta role, aktivitas terakhir
+// & username lama) ke fn satu per satu, terurut by ID. Baris tidak ditampung di memori sehingga
+// aman untuk jumlah user besar; iterasi berhenti pada error pertama (termasuk error dari fn).
+func (r *userRepo) ExportUsers(ctx context.Context, filter models.UserExportFilter, fn func(*models.UserExportRow) error) error {
+	var conds []string
+	var args []any
+	addCond := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, strings.ReplaceAll(cond, "?", "$"+strconv.Itoa(len(args))))
+	}
+	if filter.RoleID != nil {
+		addCond("u.role_id = ?", *filter.RoleID)
+	}
+	if filter.UserType != "" {
+		addCond("u.user_type = ?", filter.UserType)
+	}
+	if filter.EmploymentStatus != "" {
+		addCond("u.employment_status = ?", filter.EmploymentStatus)
+	}
+	if filter.IsActive != nil {
+		addCond("u.is_active = ?", *filter.IsActive)
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+
+	// max() multi-argumen SQLite bernilai NULL jika salah satu argumennya NULL, sehingga
+	// check-out kosong diganti check-in.
+	query := `SELECT ` + shared.SelectList("u", userColumns) + `, ` + shared.SelectList("r", shared.RoleColumns) + `,
+                     (SELECT MAX(max(a.check_in_at, COALESCE(a.check_out_at, a.check_in_at)))
+                      FROM attendances a WHERE a.user_id = u.id) AS last_activity_at,
+                     (SELECT json_group_array(username) FROM (
+                          SELECT pu.username FROM previous_usernames pu WHERE pu.user_id = u.id
+                          ORDER BY pu.changed_at DESC, pu.id DESC
+                      )) AS usernames
+              FROM users u
+              LEFT JOIN roles r ON u.role_id = r.id
+              ` + where + `
+              ORDER BY u.id ASC`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying users for export")
+		return fmt.Errorf("error exporting users: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.UserExportRow
+		row.User.Role = &models.Role{}
+		if err := shared.ScanUser(rows, &row.User, append(shared.RoleDest(row.User.Role), &row.LastActivityAt, &row.PreviousUsernames)...); err != nil {
+			return fmt.Errorf("error scanning user export row: %w", err)
+		}
+		if err := shared.DecryptUserPII(r.pii, &row.User); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating user export rows: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// internal/repository/sqlite/user_invitation.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Undangan onboarding: user_invitations menyimpan email yang diundang (terenkripsi) dan role-nya
+// sampai penerima menyelesaikan registrasi lewat tautan undangan.
+
+// CreateInvitation membuat undangan untuk email dengan role roleID dan menggantikan (mencabut)
+// undangan terbuka sebelumnya untuk email yang sama. repository.ErrEmailTaken jika email sudah dipakai
+// user; pgx.ErrNoRows jika role tidak ada.
+func (r *userRepo) CreateInvitation(ctx context.Context, email string, roleID, invitedBy int, expiresAt time.Time) (*models.Invitation, error) {
+	encrypted, err := r.pii.Encrypt(email)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting email: %w", err)
+	}
+	hash := r.pii.BlindIndex(email)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting invitation transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	var taken bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE email_hash = $1)`, hash).Scan(&taken); err != nil {
+		return nil, fmt.Errorf("error checking email availability: %w", err)
+	}
+	if taken {
+		return nil, repository.ErrEmailTaken
+	}
+	if _, err := tx.Exec(ctx, `UPDATE user_invitations SET revoked_at = NOW() WHERE email_hash = $1 AND accepted_at IS NULL AND revoked_at IS NULL`, hash); err != nil {
+		return nil, fmt.Errorf("error replacing invitation: %w", err)
+	}
+	query := `INSERT INTO user_invitations AS ui (email, email_hash, role_id, invited_by, expires_at)
+              VALUES ($1, $2, $3, $4, $5)
+              RETURNING ` + returningList(shared.InvitationColumns)
+	inv := &models.Invitation{}
+	if err := shared.ScanInvitation(tx.QueryRow(ctx, query, encrypted, hash, roleID, invitedBy, expiresAt), inv); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("role_id", roleID).Msg("Error creating invitation")
+		return nil, fmt.Errorf("error creating invitation: %w", err)
+	}
+	if err := tx.QueryRow(ctx, `SELECT name FROM roles WHERE id = $1`, roleID).Scan(&inv.RoleName); err != nil {
+		return nil, fmt.Errorf("error getting invitation role: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing invitation: %w", err)
+	}
+	inv.Email = email
+	shared.Logger(ctx).Info().Int("invitation_id", inv.ID).Int("role_id", roleID).Msg("Invitation created")
+	return inv, nil
+}
@@ -0,0 +1,93 @@
+// internal/repository/sqlite/user_phone.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Verifikasi nomor HP & OTP SMS: phone_otps menyimpan hash kode yang terikat ke nomor saat
+// dikirim (phone_hash); users.phone_verified_at direset trigger database saat nomor berganti.
+
+// ConsumePhoneOTP menandai kode sudah dipakai. Untuk tujuan verify_phone, nomor HP user sekaligus
+// ditandai terverifikasi (dalam satu transaksi), dengan syarat nomornya masih sama seperti saat
+// kode dikirim (repository.ErrPhoneChanged). pgx.ErrNoRows jika kode sudah dipakai atau kedaluwarsa.
+func (r *userRepo) ConsumePhoneOTP(ctx context.Context, id int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting phone otp transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	var userID int
+	var purpose, phoneHash string
+	err = tx.QueryRow(ctx, `UPDATE phone_otps SET consumed_at = NOW()
+              WHERE id = $1 AND consumed_at IS NULL AND expires_at > NOW()
+              RETURNING user_id, purpose, phone_hash`, id).Scan(&userID, &purpose, &phoneHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		return fmt.Errorf("error consuming phone otp %d: %w", id, err)
+	}
+	// Kode login juga hanya berlaku untuk nomor yang sama (nomor berganti = 2FA dimatikan trigger).
+	var matched bool
+	if purpose == models.OTPPurposeVerifyPhone {
+		var tag pgconn.CommandTag
+		tag, err = tx.Exec(ctx, `UPDATE users SET phone_verified_at = COALESCE(phone_verified_at, NOW()) WHERE id = $1 AND phone_hash = $2`, userID, phoneHash)
+		matched = err == nil && tag.RowsAffected() > 0
+	} else {
+		// SQLite tidak melaporkan jumlah baris SELECT lewat Exec.
+		err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND phone_hash = $2)`, userID, phoneHash).Scan(&matched)
+	}
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error marking phone verified")
+		return fmt.Errorf("error verifying phone of user %d: %w", userID, err)
+	}
+	if !matched {
+		return repository.ErrPhoneChanged
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing phone otp %d: %w", id, err)
+	}
+	return nil
+}
+
+// UpdateSMSPreferences mengubah 2FA & pengingat SMS user (nil = tidak diubah) dan mengembalikan
+// user terbaru. repository.ErrPhoneNotVerified jika salah satunya diaktifkan tanpa nomor HP terverifikasi.
+func (r *userRepo) UpdateSMSPreferences(ctx context.Context, userID int, input *models.UpdateSMSPreferencesInput) (*models.User, error) {
+	enabling := (input.TwoFactor != nil && *input.TwoFactor) || (input.Reminders != nil && *input.Reminders)
+	query := `UPDATE users AS u SET sms_two_factor = COALESCE($2, u.sms_two_factor), sms_reminders = COALESCE($3, u.sms_reminders),
+                     version = u.version + 1, updated_at = NOW()
+              WHERE u.id = $1 AND (NOT $4 OR u.phone_verified_at IS NOT NULL)
+              RETURNING ` + returningList(userColumns)
+	user := &models.User{}
+	if err := shared.ScanUser(r.db.QueryRow(ctx, query, userID, input.TwoFactor, input.Reminders, enabling), user); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error updating sms preferences")
+			return nil, fmt.Errorf("error updating sms preferences: %w", err)
+		}
+		var exists bool
+		if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, userID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("error checking user %d: %w", userID, err)
+		}
+		if !exists {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, repository.ErrPhoneNotVerified
+	}
+	if err := shared.DecryptUserPII(r.pii, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,541 @@
+// internal/repository/sqlite/user_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/pii"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type userRepo struct {
+	repository.UserRepository
+	db  *database
+	pii *pii.Protector // Enkripsi kolom email/phone/national_id
+}
+
+func (r *userRepo) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	query := `SELECT ` + shared.SelectList("u", userColumns) + `, ` + shared.SelectList("r", shared.RoleColumns) + `
+	          FROM users u
+	          JOIN roles r ON u.role_id = r.id
+	          WHERE u.username = $1`
+	user := &models.User{Role: &models.Role{}} // Inisialisasi Role
+	err := shared.ScanUser(r.db.QueryRow(ctx, query, username), user, shared.RoleDest(user.Role)...)
+	if err != nil {
+		// Handle pgx.ErrNoRows jika user tidak ditemukan
+		shared.Logger(ctx).Error().Err(err).Str("username", username).Msg("Error getting user by username")
+		return nil, fmt.Errorf("error getting user by username %s: %w", username, err)
+	}
+	if err := shared.DecryptUserPII(r.pii, user); err != nil {
+		shared.Logger(ctx).Error().Err(err).Str("username", username).Msg("Error decrypting user PII")
+		return nil, err
+	}
+	shared.Logger(ctx).Info().Str("username", username).Msg("User retrieved successfully")
+	return user, nil
+}
+
+func (r *userRepo) GetUserByID(ctx context.Context, id int) (*models.User, error) {
+	query := `SELECT ` + shared.SelectList("u", userColumns) + ` FROM users u WHERE u.id = $1`
+	user := &models.User{}
+	err := shared.ScanUser(r.db.QueryRow(ctx, query, id), user) // Ikut transaksi RunInTx agar melihat perubahan terbaru
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error getting user by id")
+		return nil, fmt.Errorf("error getting user by id %d: %w", id, err)
+	}
+	if err := shared.DecryptUserPII(r.pii, user); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error decrypting user PII")
+		return nil, err
+	}
+	shared.Logger(ctx).Info().Int("user_id", id).Msg("User retrieved successfully")
+	return user, nil
+}
+
+// GetAllUsers retrieves a paginated list of users with role information.
+func (r *userRepo) GetAllUsers(ctx context.Context, page, limit int) (users []models.User, totalCount int, err error) {
+	// --- 1. Hitung Total User (Tanpa Pagination) ---
+	countQuery := `SELECT COUNT(*) FROM users`
+	err = r.db.QueryRow(ctx, countQuery).Scan(&totalCount)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting total users")
+		err = fmt.Errorf("error counting total users: %w", err)
+		return // Kembalikan error
+	}
+
+	// Jika tidak ada user, kembalikan slice kosong dan total 0
+	if totalCount == 0 {
+		users = []models.User{} // Pastikan mengembalikan slice kosong, bukan nil
+		return                  // Kembalikan users kosong, totalCount 0, err nil
+	}
+
+	// --- 2. Hitung Offset ---
+	offset := (page - 1) * limit
+	if offset < 0 { // Jaga-jaga jika page < 1 (seharusnya divalidasi di handler)
+		offset = 0
+	}
+
+	// --- 3. Query Pengguna dengan Pagination dan Role ---
+	query := `SELECT ` + shared.SelectList("u", userColumns) + `, ` + shared.SelectList("r", shared.RoleColumns) + `
+              FROM users u
+              LEFT JOIN roles r ON u.role_id = r.id
+              ORDER BY u.id ASC -- Atau u.username, ORDER BY penting untuk pagination stabil
+              LIMIT $1 OFFSET $2` // Tambahkan LIMIT dan OFFSET
+
+	rows, err := r.db.Query(ctx, query, limit, offset) // Pass limit dan offset sebagai parameter
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying paginated users with roles")
+		err = fmt.Errorf("error getting paginated users with roles: %w", err)
+		return // Kembalikan error (totalCount mungkin sudah ada, tapi users belum)
+	}
+	defer rows.Close()
+
+	// --- 4. Scan Hasil ---
+	users = []models.User{} // Inisialisasi slice
+	for rows.Next() {
+		var user models.User
+		user.Role = &models.Role{} // Inisialisasi pointer Role
+		scanErr := shared.ScanUser(rows, &user, shared.RoleDest(user.Role)...)
+		if scanErr != nil {
+			shared.Logger(ctx).Warn().Err(scanErr).Msg("Error scanning user row with role (paginated)")
+			// Mungkin lanjutkan saja, atau hentikan dan kembalikan error?
+			// Jika ada error scan, mungkin lebih baik hentikan.
+			err = fmt.Errorf("error scanning user row: %w", scanErr)
+			return // Kembalikan users yang sudah terkumpul sejauh ini & error
+		}
+		if err = shared.DecryptUserPII(r.pii, &user); err != nil {
+			shared.Logger(ctx).Error().Err(err).Int("user_id", user.ID).Msg("Error decrypting user PII (paginated)")
+			return
+		}
+		users = append(users, user)
+	}
+
+	// Cek error setelah loop selesai
+	if err = rows.Err(); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error iterating paginated user rows with roles")
+		err = fmt.Errorf("error iterating paginated user rows: %w", err)
+		return // Kembalikan users yang sudah terkumpul & error
+	}
+
+	// Jika sampai sini tanpa error, kembalikan users, totalCount, dan err nil
+	return users, totalCount, nil
+}
+
+func (r *userRepo) UpdateUserByID(ctx context.Context, id int, input *models.AdminUpdateUserInput) error {
+	enc, err := shared.EncryptUserPII(r.pii, input.Email, input.Phone, input.NationalID)
+	if err != nil {
+		return err
+	}
+
+	// Optimistic locking: jika input.Version > 0, hanya update bila versi di DB sama
+	query := `UPDATE users SET username = $1, email = $2, email_hash = $3, phone = $4, phone_hash = $5,
+                     national_id = $6, national_id_hash = $7, first_name = $8, last_name = $9, role_id = $10,
+                     version = version + 1
+              WHERE id = $11 AND ($12 IS NULL OR version = $12)
+              RETURNING version` // updated_at dihandle trigger
+
+	err = r.db.QueryRow(ctx, query, input.Username, enc.Email, enc.EmailHash, enc.Phone, enc.PhoneHash,
+		enc.NationalID, enc.NationalIDHash, input.FirstName, input.LastName, input.RoleID, id, shared.ExpectedVersion(input.Version)).Scan(&input.Version)
+	if err != nil {
+		// Tidak ada baris terupdate: user tidak ditemukan atau versi usang
+		if errors.Is(err, pgx.ErrNoRows) {
+			return resolveMissedUpdate(ctx, r.db, "users", id)
+		}
+		// Handle unique constraint (username/email exists)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			// Cek constraint name jika perlu untuk bedakan username/email
+			fieldName := "username or email"
+			if strings.Contains(pgErr.ConstraintName, "email") {
+				fieldName = "email"
+			}
+			if strings.Contains(pgErr.ConstraintName, "username") {
+				fieldName = "username"
+			}
+			if strings.Contains(pgErr.ConstraintName, "national_id") {
+				fieldName = "national id"
+			}
+
+			shared.Logger(ctx).Warn().Err(err).Int("user_id", id).Str("field", fieldName).Msg("Unique constraint violation on user update")
+			return fmt.Errorf("%s already exists", fieldName) // Error spesifik
+		}
+		// Error umum
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error updating user")
+		return fmt.Errorf("error updating user: %w", err)
+	}
+	return nil
+}
+
+// GetUserByEmail mencari user berdasarkan email melalui blind index (email_hash),
+// karena kolom email sendiri tersimpan terenkripsi dan tidak bisa di-query langsung.
+func (r *userRepo) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `SELECT ` + shared.SelectList("u", userColumns) + ` FROM users u WHERE u.email_hash = $1`
+	user := &models.User{}
+	err := shared.ScanUser(r.db.QueryRow(ctx, query, r.pii.BlindIndex(email)), user)
+	if err != nil {
+		// Jangan log email (PII); cukup pesan umum.
+		shared.Logger(ctx).Debug().Err(err).Msg("Error getting user by email")
+		return nil, fmt.Errorf("error getting user by email: %w", err)
+	}
+	if err := shared.DecryptUserPII(r.pii, user); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", user.ID).Msg("Error decrypting user PII")
+		return nil, err
+	}
+	return user, nil
+}
+
+// AnonymizeUser mempseudonimkan data pribadi user secara permanen (hak penghapusan GDPR).
+// Username/email diganti nilai turunan ID, nama/phone/national_id dikosongkan, password
+// dibuat tidak bisa dipakai, dan catatan bebas pada absensi dihapus. Baris user, jadwal,
+// dan jam check-in/check-out tetap ada agar agregat kehadiran untuk payroll tidak hilang.
+func (r *userRepo) AnonymizeUser(ctx context.Context, id int) error {
+	pseudonym := fmt.Sprintf("anonymized-%d", id)
+	enc, err := shared.EncryptUserPII(r.pii, pseudonym+"@anonymized.invalid", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting anonymize transaction for user %d: %w", id, err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	// --- 1. Pastikan user ada & belum dianonimkan ---
+	var anonymizedAt *time.Time
+	err = tx.QueryRow(ctx, `SELECT anonymized_at FROM users WHERE id = $1`, id).Scan(&anonymizedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		return fmt.Errorf("error locking user %d for anonymization: %w", id, err)
+	}
+	if anonymizedAt != nil {
+		return repository.ErrUserAlreadyAnonymized
+	}
+
+	// --- 2. Ganti data pribadi dengan pseudonim ---
+	query := `UPDATE users SET username = $1, password = $2, email = $3, email_hash = $4,
+                     phone = NULL, phone_hash = NULL, national_id = NULL, national_id_hash = NULL,
+                     first_name = 'Anonymized', last_name = '', anonymized_at = NOW()
+              WHERE id = $5`
+	if _, err = tx.Exec(ctx, query, pseudonym, shared.AnonymizedPassword, enc.Email, enc.EmailHash, id); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error anonymizing user")
+		return fmt.Errorf("error anonymizing user %d: %w", id, err)
+	}
+
+	// --- 3. Hapus catatan bebas absensi & ledger-nya (bisa berisi data pribadi) ---
+	if _, err = tx.Exec(ctx, `UPDATE attendances SET notes = NULL WHERE user_id = $1 AND notes IS NOT NULL`, id); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error clearing attendance notes during anonymization")
+		return fmt.Errorf("error clearing attendance notes for user %d: %w", id, err)
+	}
+	// Ledger absensi append-only, tetapi trigger-nya mengizinkan redaksi notes menjadi NULL.
+	if _, err = tx.Exec(ctx, `UPDATE attendance_events SET notes = NULL WHERE user_id = $1 AND notes IS NOT NULL`, id); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error redacting attendance event notes during anonymization")
+		return fmt.Errorf("error redacting attendance event notes for user %d: %w", id, err)
+	}
+	// Audit log menyimpan IP, user agent, perangkat & negara login; aksi dan waktunya tetap dipertahankan.
+	if _, err = tx.Exec(ctx, `UPDATE audit_log SET details = json_remove(details, '$.ip', '$.user_agent', '$.device', '$.country')
+	                        WHERE user_id = $1 AND (json_type(details, '$.ip') IS NOT NULL OR json_type(details, '$.user_agent') IS NOT NULL
+	                              OR json_type(details, '$.device') IS NOT NULL OR json_type(details, '$.country') IS NOT NULL)`, id); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error redacting audit log during anonymization")
+		return fmt.Errorf("error redacting audit log for user %d: %w", id, err)
+	}
+	// Permintaan ganti email menyimpan alamat email (terenkripsi).
+	if _, err = tx.Exec(ctx, `DELETE FROM email_change_requests WHERE user_id = $1`, id); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error deleting email change requests during anonymization")
+		return fmt.Errorf("error deleting email change requests for user %d: %w", id, err)
+	}
+	// Riwayat & permintaan ganti username menyimpan username asli user.
+	if _, err = tx.Exec(ctx, `DELETE FROM previous_usernames WHERE user_id = $1`, id); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error deleting previous usernames during anonymization")
+		return fmt.Errorf("error deleting previous usernames for user %d: %w", id, err)
+	}
+	if _, err = tx.Exec(ctx, `DELETE FROM username_change_requests WHERE user_id = $1`, id); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error deleting username change requests during anonymization")
+		return fmt.Errorf("error deleting username change requests for user %d: %w", id, err)
+	}
+	// Undangan yang diterima user menyimpan alamat email-nya (terenkripsi).
+	if _, err = tx.Exec(ctx, `DELETE FROM user_invitations WHERE accepted_user_id = $1`, id); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error deleting invitations during anonymization")
+		return fmt.Errorf("error deleting invitations for user %d: %w", id, err)
+	}
+	// Kode OTP SMS menyimpan hash nomor HP.
+	if _, err = tx.Exec(ctx, `DELETE FROM phone_otps WHERE user_id = $1`, id); err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error deleting phone codes during anonymization")
+		return fmt.Errorf("error deleting phone codes for user %d: %w", id, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing anonymization for user %d: %w", id, err)
+	}
+	shared.Logger(ctx).Info().Int("user_id", id).Msg("User anonymized successfully")
+	return nil
+}
+
+// PatchUserByID memperbarui hanya field yang dikirim (non-nil) pada input.
+// Email/phone/national_id dienkripsi ulang beserta blind index-nya; string kosong pada
+// phone/national_id mengosongkan kolom. Mengembalikan versi baru record.
+func (r *userRepo) PatchUserByID(ctx context.Context, id int, input *models.AdminPatchUserInput) (int, error) {
+	var b shared.SetBuilder
+	if input.Username != nil {
+		b.Add("username", *input.Username)
+	}
+	if input.Email != nil {
+		enc, err := r.pii.Encrypt(*input.Email)
+		if err != nil {
+			return 0, fmt.Errorf("error encrypting email: %w", err)
+		}
+		b.Add("email", enc)
+		b.Add("email_hash", r.pii.BlindIndex(*input.Email))
+	}
+	if input.FirstName != nil {
+		b.Add("first_name", *input.FirstName)
+	}
+	if input.LastName != nil {
+		b.Add("last_name", *input.LastName)
+	}
+	if input.Phone != nil {
+		phone := input.Phone
+		if *phone == "" {
+			phone = nil
+		}
+		enc, err := r.pii.EncryptPtr(phone)
+		if err != nil {
+			return 0, fmt.Errorf("error encrypting phone: %w", err)
+		}
+		b.Add("phone", enc)
+		b.Add("phone_hash", r.pii.BlindIndexPtr(phone))
+	}
+	if input.NationalID != nil {
+		nationalID := input.NationalID
+		if *nationalID == "" {
+			nationalID = nil
+		}
+		enc, err := r.pii.EncryptPtr(nationalID)
+		if err != nil {
+			return 0, fmt.Errorf("error encrypting national id: %w", err)
+		}
+		b.Add("national_id", enc)
+		b.Add("national_id_hash", r.pii.BlindIndexPtr(nationalID))
+	}
+	if input.RoleID != nil {
+		b.Add("role_id", *input.RoleID)
+	}
+	if b.Empty() {
+		return 0, repository.ErrNoFieldsToUpdate
+	}
+
+	idArg := b.Arg(id)
+	versionArg := b.Arg(shared.ExpectedVersion(input.Version))
+	query := fmt.Sprintf(`UPDATE users SET %s, version = version + 1 WHERE id = %s AND (%s IS NULL OR version = %s) RETURNING version`,
+		b.Clause(), idArg, versionArg, versionArg) // updated_at dihandle trigger
+
+	var version int
+	err := r.db.QueryRow(ctx, query, b.Args()...).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, resolveMissedUpdate(ctx, r.db, "users", id)
+		}
+		// Handle unique constraint (username/email/national_id exists)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			fieldName := "username or email"
+			if strings.Contains(pgErr.ConstraintName, "email") {
+				fieldName = "email"
+			}
+			if strings.Contains(pgErr.ConstraintName, "username") {
+				fieldName = "username"
+			}
+			if strings.Contains(pgErr.ConstraintName, "national_id") {
+				fieldName = "national id"
+			}
+			shared.Logger(ctx).Warn().Err(err).Int("user_id", id).Str("field", fieldName).Msg("Unique constraint violation on user patch")
+			return 0, fmt.Errorf("%s already exists", fieldName)
+		}
+		// Foreign key role_id
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return 0, fmt.Errorf("invalid role_id")
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error patching user")
+		return 0, fmt.Errorf("error patching user: %w", err)
+	}
+	return version, nil
+}
+
+// BulkUpdateUserRole mengganti role beberapa user sekaligus dalam satu transaksi.
+// ID duplikat diabaikan; hasil dikembalikan per user sesuai urutan input
+// (updated / unchanged jika role sudah sama / not_found).
+func (r *userRepo) BulkUpdateUserRole(ctx context.Context, userIDs []int, roleID int) ([]models.BulkItemResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting bulk role transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	// --- 1. Catat role saat ini user yang ada ---
+	rows, err := tx.Query(ctx, `SELECT id, role_id FROM users WHERE id IN (SELECT value FROM json_each($1))`, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error locking users for bulk role update: %w", err)
+	}
+	currentRoles := make(map[int]int, len(userIDs))
+	for rows.Next() {
+		var id, currentRole int
+		if err := rows.Scan(&id, &currentRole); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning user for bulk role update: %w", err)
+		}
+		currentRoles[id] = currentRole
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users for bulk role update: %w", err)
+	}
+
+	// --- 2. Update hanya user yang role-nya berbeda ---
+	if _, err := tx.Exec(ctx, `UPDATE users SET role_id = $1 WHERE id IN (SELECT value FROM json_each($2)) AND role_id <> $1`, roleID, userIDs); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return nil, fmt.Errorf("invalid role_id")
+		}
+		shared.Logger(ctx).Error().Err(err).Int("role_id", roleID).Msg("Error bulk updating user roles")
+		return nil, fmt.Errorf("error bulk updating user roles: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing bulk role update: %w", err)
+	}
+
+	// --- 3. Susun hasil per user ---
+	results := make([]models.BulkItemResult, 0, len(userIDs))
+	seen := make(map[int]bool, len(userIDs))
+	for _, id := range userIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		currentRole, ok := currentRoles[id]
+		switch {
+		case !ok:
+			results = append(results, models.BulkItemResult{ID: id, Status: models.BulkStatusNotFound})
+		case currentRole == roleID:
+			results = append(results, models.BulkItemResult{ID: id, Status: models.BulkStatusUnchanged})
+		default:
+			results = append(results, models.BulkItemResult{ID: id, Status: models.BulkStatusUpdated})
+		}
+	}
+	shared.Logger(ctx).Info().Int("role_id", roleID).Int("requested", len(userIDs)).Msg("Bulk user role update committed")
+	return results, nil
+}
+
+// UpdateUserAccess mengganti tipe user dan masa aksesnya. Status aktif dihitung ulang
+// terhadap tanggal today, sehingga memperpanjang kontrak sekaligus mengaktifkan kembali user.
+func (r *userRepo) UpdateUserAccess(ctx context.Context, id int, input *models.UpdateUserAccessInput, today time.Time) error {
+	query := `UPDATE users SET user_type = $1, access_valid_from = $2, access_valid_until = $3,
+                     is_active = ($3 IS NULL OR $3 >= $4)
+              WHERE id = $5` // updated_at & version dihandle trigger
+	tag, err := r.db.Exec(ctx, query, input.UserType, input.ValidFrom, input.ValidUntil, today.Format(shared.DateLayout), id)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error updating user access")
+		return fmt.Errorf("error updating user access: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	shared.Logger(ctx).Info().Int("user_id", id).Str("user_type", input.UserType).Msg("User access updated")
+	return nil
+}
+
+// DeactivateExpiredContractors menonaktifkan kontraktor aktif yang masa aksesnya berakhir
+// sebelum tanggal today dan mengembalikan ID user yang dinonaktifkan.
+func (r *userRepo) DeactivateExpiredContractors(ctx context.Context, today time.Time) ([]int, error) {
+	query := `UPDATE users SET is_active = FALSE
+              WHERE user_type = $1 AND is_active AND access_valid_until < $2
+              RETURNING id`
+	rows, err := r.db.Query(ctx, query, models.UserTypeContractor, today.Format(shared.DateLayout))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error deactivating expired contractors")
+		return nil, fmt.Errorf("error deactivating expired contractors: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning deactivated contractor id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating deactivated contractors: %w", err)
+	}
+	return ids, nil
+}
+
+// UpdateUserEmployment mengganti data kepegawaian user. Penanda notifikasi probation
+// direset jika tanggal akhir probation berubah, sehingga HR diberi tahu lagi untuk tanggal baru.
+func (r *userRepo) UpdateUserEmployment(ctx context.Context, id int, input *models.UpdateEmploymentInput) error {
+	query := `UPDATE users SET hire_date = $1, employment_status = $2, probation_end = $3,
+                     probation_notified_at = CASE WHEN probation_end IS NOT $3 THEN NULL ELSE probation_notified_at END
+              WHERE id = $4` // updated_at & version dihandle trigger
+	tag, err := r.db.Exec(ctx, query, input.HireDate, input.EmploymentStatus, input.ProbationEnd, id)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error updating user employment")
+		return fmt.Errorf("error updating user employment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	shared.Logger(ctx).Info().Int("user_id", id).Str("employment_status", input.EmploymentStatus).Msg("User employment updated")
+	return nil
+}
+
+// GetPendingProbationReviews mengambil user berstatus probation yang masa probation-nya
+// berakhir paling lambat endingBy dan belum dinotifikasikan ke HR.
+func (r *userRepo) GetPendingProbationReviews(ctx context.Context, endingBy time.Time) ([]models.User, error) {
+	query := `SELECT ` + shared.SelectList("u", userColumns) + `
+              FROM users u
+              WHERE u.employment_status = $1 AND u.probation_end <= $2 AND u.probation_notified_at IS NULL
+              ORDER BY u.probation_end ASC, u.id ASC`
+	rows, err := r.db.Query(ctx, query, models.EmploymentStatusProbation, endingBy.Format(shared.DateLayout))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying pending probation reviews")
+		return nil, fmt.Errorf("error getting pending probation reviews: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := shared.ScanUser(rows, &user); err != nil {
+			return nil, fmt.Errorf("error scanning probation review row: %w", err)
+		}
+		if err := shared.DecryptUserPII(r.pii, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating probation review rows: %w", err)
+	}
+	return users, nil
+}
+
+// MarkProbationNotified mencatat bahwa HR sudah diberi tahu tentang akhir probation user.
+func (r *userRepo) MarkProbationNotified(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := r.db.Exec(ctx, `UPDATE users SET probation_notified_at = NOW() WHERE id IN (SELECT value FROM json_each($1))`, ids); err != nil {
+		shared.Logger(ctx).Error().Err(err).Ints("user_ids", ids).Msg("Error marking probation notified")
+		return fmt.Errorf("error marking probation notified: %w", err)
+	}
+	return nil
+}
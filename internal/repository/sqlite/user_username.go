@@ -0,0 +1,135 @@
+// internal/repository/sqlite/user_username.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Riwayat & persetujuan username: previous_usernames diisi trigger database setiap username
+// berganti; username_change_requests menampung permintaan yang menunggu persetujuan admin.
+
+// CreateUsernameChangeRequest membuat permintaan ganti username dan menggantikan permintaan
+// pending sebelumnya milik user. repository.ErrUsernameTaken jika username sudah dipakai user lain.
+func (r *userRepo) CreateUsernameChangeRequest(ctx context.Context, userID int, newUsername string) (*models.UsernameChangeRequest, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting username change transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	var taken bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE username = $1 AND id <> $2)`, newUsername, userID).Scan(&taken); err != nil {
+		return nil, fmt.Errorf("error checking username availability: %w", err)
+	}
+	if taken {
+		return nil, repository.ErrUsernameTaken
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM username_change_requests WHERE user_id = $1 AND status = $2`, userID, models.UsernameChangePending); err != nil {
+		return nil, fmt.Errorf("error replacing username change request: %w", err)
+	}
+	query := `INSERT INTO username_change_requests AS ucr (user_id, new_username)
+              VALUES ($1, $2)
+              RETURNING ` + returningList(shared.UsernameChangeColumns)
+	uc := &models.UsernameChangeRequest{}
+	if err := shared.ScanUsernameChange(tx.QueryRow(ctx, query, userID, newUsername), uc); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error creating username change request")
+		return nil, fmt.Errorf("error creating username change request: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing username change request: %w", err)
+	}
+	return uc, nil
+}
+
+// ReviewUsernameChangeRequest menyetujui (username user langsung diganti) atau menolak permintaan
+// id, dalam satu transaksi. pgx.ErrNoRows jika permintaan tidak ada; repository.ErrUsernameChangeReviewed
+// jika sudah ditinjau; repository.ErrUsernameTaken jika username sudah dipakai user lain sejak diminta.
+func (r *userRepo) ReviewUsernameChangeRequest(ctx context.Context, id, reviewerID int, approve bool, note *string) (*models.UsernameChangeRequest, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting username review transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	uc := &models.UsernameChangeRequest{}
+	query := `SELECT ` + shared.SelectList("ucr", shared.UsernameChangeColumns) + ` FROM username_change_requests ucr WHERE ucr.id = $1`
+	if err := shared.ScanUsernameChange(tx.QueryRow(ctx, query, id), uc); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("error locking username change request %d: %w", id, err)
+	}
+	if uc.Status != models.UsernameChangePending {
+		return uc, repository.ErrUsernameChangeReviewed
+	}
+
+	status := models.UsernameChangeRejected
+	if approve {
+		status = models.UsernameChangeApproved
+		// Trigger record_username_change menyimpan username lama & mencatat audit log.
+		tag, err := tx.Exec(ctx, `UPDATE users SET username = $1 WHERE id = $2 AND anonymized_at IS NULL`, uc.NewUsername, uc.UserID)
+		if err != nil {
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+				return nil, repository.ErrUsernameTaken
+			}
+			shared.Logger(ctx).Error().Err(err).Int("username_change_id", id).Msg("Error applying username change")
+			return nil, fmt.Errorf("error applying username change %d: %w", id, err)
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, pgx.ErrNoRows
+		}
+	}
+	query = `UPDATE username_change_requests AS ucr SET status = $2, reviewed_by = $3, reviewed_at = NOW(), review_note = $4
+              WHERE ucr.id = $1
+              RETURNING ` + returningList(shared.UsernameChangeColumns)
+	if err := shared.ScanUsernameChange(tx.QueryRow(ctx, query, id, status, reviewerID, note), uc); err != nil {
+		return nil, fmt.Errorf("error reviewing username change request %d: %w", id, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing username review %d: %w", id, err)
+	}
+	shared.Logger(ctx).Info().Int("username_change_id", id).Int("user_id", uc.UserID).Str("status", status).Msg("Username change request reviewed")
+	return uc, nil
+}
+
+// ResolveUsername mencari user dari username saat ini; jika tidak ada, dari username lama
+// (pemakai terakhir username itu). pgx.ErrNoRows jika username tidak pernah dipakai.
+func (r *userRepo) ResolveUsername(ctx context.Context, username string) (*models.UsernameResolution, error) {
+	user, err := r.GetUserByUsername(ctx, username)
+	if err == nil {
+		return &models.UsernameResolution{User: user}, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	var pu models.PreviousUsername
+	query := `SELECT ` + shared.SelectList("pu", shared.PreviousUsernameColumns) + ` FROM previous_usernames pu
+              WHERE pu.username = $1
+              ORDER BY pu.changed_at DESC, pu.id DESC
+              LIMIT 1`
+	if err := shared.ScanPreviousUsername(r.db.QueryRow(ctx, query, username), &pu); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Msg("Error resolving previous username")
+		return nil, fmt.Errorf("error resolving previous username: %w", err)
+	}
+	if user, err = r.GetUserByID(ctx, pu.UserID); err != nil {
+		return nil, err
+	}
+	return &models.UsernameResolution{User: user, MatchedPrevious: true, PreviousUntil: &pu.ChangedAt}, nil
+}
@@ -0,0 +1,133 @@
+// internal/repository/sqlite/verification_repo.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type verificationRepo struct {
+	repository.VerificationRepository
+	db *database
+}
+
+// CreateVerificationLink menyimpan tautan verifikasi kepegawaian dan mengisi ID & created_at.
+// Mengembalikan pgx.ErrNoRows jika user tidak ada.
+func (r *verificationRepo) CreateVerificationLink(ctx context.Context, link *models.EmploymentVerificationLink) error {
+	query := `INSERT INTO employment_verification_links AS evl (user_id, recipient, include_attendance, expires_at, created_by)
+              SELECT u.id, $2, $3, $4, $5 FROM users u WHERE u.id = $1
+              RETURNING ` + returningList(shared.VerificationLinkColumns)
+	err := shared.ScanVerificationLink(r.db.QueryRow(ctx, query, link.UserID, link.Recipient, link.IncludeAttendance, link.ExpiresAt, link.CreatedBy), link)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", link.UserID).Msg("Error creating employment verification link")
+		return fmt.Errorf("error creating verification link for user %d: %w", link.UserID, err)
+	}
+	shared.Logger(ctx).Info().Int("link_id", link.ID).Int("user_id", link.UserID).Time("expires_at", link.ExpiresAt).Msg("Employment verification link created")
+	return nil
+}
+
+// RevokeVerificationLink mencabut tautan. Mengembalikan pgx.ErrNoRows jika tautan tidak ada
+// atau sudah dicabut.
+func (r *verificationRepo) RevokeVerificationLink(ctx context.Context, id int) (*models.EmploymentVerificationLink, error) {
+	query := `UPDATE employment_verification_links AS evl SET revoked_at = NOW()
+              WHERE evl.id = $1 AND evl.revoked_at IS NULL
+              RETURNING ` + returningList(shared.VerificationLinkColumns)
+	link := &models.EmploymentVerificationLink{}
+	if err := shared.ScanVerificationLink(r.db.QueryRow(ctx, query, id), link); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("link_id", id).Msg("Error revoking employment verification link")
+		return nil, fmt.Errorf("error revoking verification link %d: %w", id, err)
+	}
+	shared.Logger(ctx).Info().Int("link_id", id).Msg("Employment verification link revoked")
+	return link, nil
+}
+
+// RecordVerificationAccess mencatat satu akses ke tautan. SQLite tidak mendukung INSERT/UPDATE di
+// dalam CTE, jadi insert akses dan penambahan access_count (hanya untuk akses granted) dijalankan
+// berurutan dalam satu transaksi.
+func (r *verificationRepo) RecordVerificationAccess(ctx context.Context, access *models.EmploymentVerificationAccess) error {
+	err := r.recordVerificationAccess(ctx, access)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("link_id", access.LinkID).Str("outcome", access.Outcome).Msg("Error recording employment verification access")
+		return fmt.Errorf("error recording access to verification link %d: %w", access.LinkID, err)
+	}
+	return nil
+}
+
+// recordVerificationAccess menjalankan insert akses dan update tautan dalam satu transaksi.
+func (r *verificationRepo) recordVerificationAccess(ctx context.Context, access *models.EmploymentVerificationAccess) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	query := `INSERT INTO employment_verification_accesses (link_id, ip, user_agent, outcome)
+              VALUES ($1, $2, $3, $4)
+              RETURNING id, accessed_at`
+	if err := tx.QueryRow(ctx, query, access.LinkID, access.IP, access.UserAgent, access.Outcome).Scan(&access.ID, &access.AccessedAt); err != nil {
+		return err
+	}
+	if access.Outcome == models.VerificationAccessGranted {
+		query = `UPDATE employment_verification_links
+                 SET access_count = access_count + 1, last_accessed_at = $2
+                 WHERE id = $1`
+		if _, err := tx.Exec(ctx, query, access.LinkID, access.AccessedAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// GetEmploymentVerification menyusun data verifikasi terbatas untuk user. Jika attendanceSince
+// tidak nil, jumlah tanggal dengan check-in sejak tanggal itu (sampai hari ini) disertakan.
+// Mengembalikan pgx.ErrNoRows jika user tidak ada.
+func (r *verificationRepo) GetEmploymentVerification(ctx context.Context, userID int, attendanceSince *time.Time) (*models.EmploymentVerification, error) {
+	query := `SELECT TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), u.username,
+                     u.is_active AND u.employment_status <> 'terminated', u.employment_status,
+                     r.name, u.hire_date,
+                     (SELECT COUNT(DISTINCT date(a.check_in_at, 'localtime')) FROM attendances a
+                      WHERE a.user_id = u.id AND $2 IS NOT NULL AND date(a.check_in_at, 'localtime') >= $2)
+              FROM users u JOIN roles r ON r.id = u.role_id
+              WHERE u.id = $1`
+	var since *string
+	if attendanceSince != nil {
+		s := attendanceSince.Format(shared.DateLayout)
+		since = &s
+	}
+	v := &models.EmploymentVerification{}
+	var username string
+	var daysPresent int
+	err := r.db.QueryRow(ctx, query, userID, since).Scan(&v.EmployeeName, &username, &v.Employed, &v.EmploymentStatus, &v.Position, &v.HireDate, &daysPresent)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error getting employment verification data")
+		return nil, fmt.Errorf("error getting employment verification of user %d: %w", userID, err)
+	}
+	if v.EmployeeName == "" {
+		v.EmployeeName = username // User tanpa nama lengkap
+	}
+	if since != nil {
+		v.Attendance = &models.EmploymentVerificationAttendance{
+			PeriodStart: *since, PeriodEnd: time.Now().Format(shared.DateLayout), DaysPresent: daysPresent,
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,35 @@
+// internal/repository/sqlite/version.go
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+)
+
+// Helper optimistic locking untuk tabel yang memiliki kolom version
+// (shifts, user_schedules, users). Trigger AFTER UPDATE menaikkan version, tetapi RETURNING
+// tidak melihat perubahan trigger; UPDATE yang mengembalikan versi baru menulis
+// `version = version + 1` sendiri (trigger mempertahankan nilai yang di-set eksplisit).
+// Query update memakai kondisi `($n IS NULL OR version = $n)`, sehingga
+// precondition hanya berlaku jika klien mengirim versi yang diharapkan.
+
+// resolveMissedUpdate dipanggil ketika UPDATE bersyarat version tidak mengenai baris mana pun:
+// mengembalikan pgx.ErrNoRows jika record tidak ada, atau repository.ErrVersionConflict jika versinya berbeda.
+// table hanya boleh berisi nama tabel konstanta dari repository (bukan input user).
+func resolveMissedUpdate(ctx context.Context, db *database, table string, id int) error {
+	var exists bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1)`, table)
+	if err := db.QueryRow(ctx, query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("error checking %s id %d after update: %w", table, id, err)
+	}
+	if !exists {
+		return pgx.ErrNoRows
+	}
+	return repository.ErrVersionConflict
+}
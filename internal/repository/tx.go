@@ -0,0 +1,63 @@
+// internal/repository/tx.go
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// TxManager menjalankan beberapa operasi repository dalam satu transaksi database.
+// Dipakai agar perubahan domain dan pesan outbox (lihat OutboxRepository) ter-commit
+// bersama: jika proses mati setelah commit, efek sampingnya tetap tercatat untuk dikirim.
+type TxManager interface {
+	// RunInTx menjalankan fn dengan context yang membawa transaksi. Repository yang menulis
+	// lewat context tersebut ikut transaksi ini; error dari fn (atau commit) me-rollback semuanya.
+	// Pemanggilan bertingkat memakai transaksi terluar.
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type ctxTxKey struct{}
+
+type txManager struct {
+	db Conn
+}
+
+func NewTxManager(pools Pools) TxManager {
+	return &txManager{db: pools.Primary}
+}
+
+func (m *txManager) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(ctxTxKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+	return pgx.BeginFunc(ctx, m.db, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, ctxTxKey{}, tx))
+	})
+}
+
+// Conn adalah koneksi yang dipakai repository: dipenuhi oleh *pgxpool.Pool, pgx.Tx, maupun
+// koneksi driver lain yang meniru API pgx (lihat NewRepositoriesWithConn).
+type Conn interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn mengembalikan transaksi dari TxManager.RunInTx jika ctx membawanya, atau pool.
+// Hanya untuk pool Primary: query ke replica tidak boleh ikut transaksi tulis.
+func conn(ctx context.Context, pool Conn) Conn {
+	if tx, ok := ctx.Value(ctxTxKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
+
+// beginTx memulai transaksi milik method repository. Di dalam RunInTx transaksi ini
+// menjadi savepoint dari transaksi luar, sehingga commit-nya baru permanen saat
+// transaksi luar di-commit.
+func beginTx(ctx context.Context, pool Conn) (pgx.Tx, error) {
+	return conn(ctx, pool).Begin(ctx)
+}
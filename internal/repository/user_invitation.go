@@ -0,0 +1,182 @@
+// internal/repository/user_invitation.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Undangan onboarding: user_invitations menyimpan email yang diundang (terenkripsi) dan role-nya
+// sampai penerima menyelesaikan registrasi lewat tautan undangan.
+
+// invitationStatusFilter menyaring undangan berdasarkan status ($1 kosong = semua), sesuai
+// perhitungan Status di shared.ScanInvitation.
+const invitationStatusFilter = `($1 = ''
+    OR ($1 = 'accepted' AND ui.accepted_at IS NOT NULL)
+    OR ($1 = 'revoked' AND ui.accepted_at IS NULL AND ui.revoked_at IS NOT NULL)
+    OR ($1 = 'expired' AND ui.accepted_at IS NULL AND ui.revoked_at IS NULL AND ui.expires_at <= NOW())
+    OR ($1 = 'pending' AND ui.accepted_at IS NULL AND ui.revoked_at IS NULL AND ui.expires_at > NOW()))`
+
+// CreateInvitation membuat undangan untuk email dengan role roleID dan menggantikan (mencabut)
+// undangan terbuka sebelumnya untuk email yang sama. ErrEmailTaken jika email sudah dipakai
+// user; pgx.ErrNoRows jika role tidak ada.
+func (r *userRepo) CreateInvitation(ctx context.Context, email string, roleID, invitedBy int, expiresAt time.Time) (*models.Invitation, error) {
+	encrypted, err := r.pii.Encrypt(email)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting email: %w", err)
+	}
+	hash := r.pii.BlindIndex(email)
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("error starting invitation transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	var taken bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE email_hash = $1)`, hash).Scan(&taken); err != nil {
+		return nil, fmt.Errorf("error checking email availability: %w", err)
+	}
+	if taken {
+		return nil, ErrEmailTaken
+	}
+	if _, err := tx.Exec(ctx, `UPDATE user_invitations SET revoked_at = NOW() WHERE email_hash = $1 AND accepted_at IS NULL AND revoked_at IS NULL`, hash); err != nil {
+		return nil, fmt.Errorf("error replacing invitation: %w", err)
+	}
+	query := `INSERT INTO user_invitations AS ui (email, email_hash, role_id, invited_by, expires_at)
+              VALUES ($1, $2, $3, $4, $5)
+              RETURNING ` + shared.SelectList("ui", shared.InvitationColumns)
+	inv := &models.Invitation{}
+	if err := shared.ScanInvitation(tx.QueryRow(ctx, query, encrypted, hash, roleID, invitedBy, expiresAt), inv); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("role_id", roleID).Msg("Error creating invitation")
+		return nil, fmt.Errorf("error creating invitation: %w", err)
+	}
+	if err := tx.QueryRow(ctx, `SELECT name FROM roles WHERE id = $1`, roleID).Scan(&inv.RoleName); err != nil {
+		return nil, fmt.Errorf("error getting invitation role: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing invitation: %w", err)
+	}
+	inv.Email = email
+	shared.Logger(ctx).Info().Int("invitation_id", inv.ID).Int("role_id", roleID).Msg("Invitation created")
+	return inv, nil
+}
+
+// GetInvitationByID mengembalikan satu undangan beserta nama role-nya, atau pgx.ErrNoRows.
+func (r *userRepo) GetInvitationByID(ctx context.Context, id int) (*models.Invitation, error) {
+	query := `SELECT ` + shared.SelectList("ui", shared.InvitationColumns) + `, r.name
+              FROM user_invitations ui
+              JOIN roles r ON r.id = ui.role_id
+              WHERE ui.id = $1`
+	inv := &models.Invitation{}
+	if err := shared.ScanInvitation(conn(ctx, r.db).QueryRow(ctx, query, id), inv, &inv.RoleName); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("invitation_id", id).Msg("Error getting invitation")
+		return nil, fmt.Errorf("error getting invitation %d: %w", id, err)
+	}
+	var err error
+	if inv.Email, err = r.pii.Decrypt(inv.Email); err != nil {
+		return nil, fmt.Errorf("error decrypting invitation %d: %w", id, err)
+	}
+	return inv, nil
+}
+
+// GetInvitations mengembalikan undangan (status kosong = semua) beserta nama role-nya, terbaru
+// dulu, dan jumlah totalnya.
+func (r *userRepo) GetInvitations(ctx context.Context, status string, page, limit int) ([]models.Invitation, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM user_invitations ui WHERE `+invitationStatusFilter, status).Scan(&total); err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error counting invitations")
+		return nil, 0, fmt.Errorf("error counting invitations: %w", err)
+	}
+	if total == 0 {
+		return []models.Invitation{}, 0, nil
+	}
+	query := `SELECT ` + shared.SelectList("ui", shared.InvitationColumns) + `, r.name
+              FROM user_invitations ui
+              JOIN roles r ON r.id = ui.role_id
+              WHERE ` + invitationStatusFilter + `
+              ORDER BY ui.created_at DESC, ui.id DESC
+              LIMIT $2 OFFSET $3`
+	rows, err := r.db.Query(ctx, query, status, limit, shared.PageOffset(page, limit))
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Msg("Error querying invitations")
+		return nil, 0, fmt.Errorf("error querying invitations: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []models.Invitation{}
+	for rows.Next() {
+		var inv models.Invitation
+		if err := shared.ScanInvitation(rows, &inv, &inv.RoleName); err != nil {
+			return nil, 0, fmt.Errorf("error scanning invitation: %w", err)
+		}
+		if inv.Email, err = r.pii.Decrypt(inv.Email); err != nil {
+			return nil, 0, fmt.Errorf("error decrypting invitation %d: %w", inv.ID, err)
+		}
+		invitations = append(invitations, inv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating invitations: %w", err)
+	}
+	return invitations, total, nil
+}
+
+// RenewInvitation mencatat pengiriman ulang tautan undangan: sent_at diperbarui dan masa berlaku
+// diperpanjang sampai expiresAt (juga untuk undangan yang sudah kedaluwarsa). pgx.ErrNoRows jika
+// undangan tidak ada, sudah diterima, atau dicabut.
+func (r *userRepo) RenewInvitation(ctx context.Context, id int, expiresAt time.Time) error {
+	tag, err := r.db.Exec(ctx, `UPDATE user_invitations SET sent_at = NOW(), expires_at = $2
+              WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL`, id, expiresAt)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("invitation_id", id).Msg("Error renewing invitation")
+		return fmt.Errorf("error renewing invitation %d: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// RevokeInvitation mencabut undangan yang belum diterima; tautannya tidak berlaku lagi.
+// pgx.ErrNoRows jika undangan tidak ada, sudah diterima, atau sudah dicabut.
+func (r *userRepo) RevokeInvitation(ctx context.Context, id int) error {
+	tag, err := conn(ctx, r.db).Exec(ctx, `UPDATE user_invitations SET revoked_at = NOW()
+              WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL`, id)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("invitation_id", id).Msg("Error revoking invitation")
+		return fmt.Errorf("error revoking invitation %d: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// AcceptInvitation menandai undangan diterima oleh userID. Dipanggil dalam transaksi yang sama
+// dengan CreateUser (TxManager.RunInTx), sehingga satu undangan hanya bisa menghasilkan satu
+// akun. pgx.ErrNoRows jika undangan sudah diterima, dicabut, atau kedaluwarsa.
+func (r *userRepo) AcceptInvitation(ctx context.Context, id, userID int) error {
+	tag, err := conn(ctx, r.db).Exec(ctx, `UPDATE user_invitations SET accepted_at = NOW(), accepted_user_id = $2
+              WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()`, id, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("invitation_id", id).Msg("Error accepting invitation")
+		return fmt.Errorf("error accepting invitation %d: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
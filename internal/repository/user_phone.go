@@ -0,0 +1,149 @@
+// internal/repository/user_phone.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+var (
+	// ErrPhoneNotVerified dikembalikan jika fitur SMS diaktifkan sebelum nomor HP diverifikasi.
+	ErrPhoneNotVerified = errors.New("phone number is not verified")
+	// ErrPhoneChanged dikembalikan jika nomor HP berganti setelah kode verifikasi dikirim.
+	ErrPhoneChanged = errors.New("phone number changed after the code was sent")
+)
+
+// Verifikasi nomor HP & OTP SMS: phone_otps menyimpan hash kode yang terikat ke nomor saat
+// dikirim (phone_hash); users.phone_verified_at direset trigger database saat nomor berganti.
+
+// CreatePhoneOTP menyimpan kode OTP untuk nomor phone dan menggantikan kode terbuka sebelumnya
+// dengan tujuan yang sama. otp.ID dan otp.CreatedAt diisi.
+func (r *userRepo) CreatePhoneOTP(ctx context.Context, otp *models.PhoneOTP, phone string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("error starting phone otp transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	if _, err := tx.Exec(ctx, `DELETE FROM phone_otps WHERE user_id = $1 AND purpose = $2 AND consumed_at IS NULL`, otp.UserID, otp.Purpose); err != nil {
+		return fmt.Errorf("error replacing phone otp: %w", err)
+	}
+	query := `INSERT INTO phone_otps (user_id, purpose, code_hash, phone_hash, expires_at)
+              VALUES ($1, $2, $3, $4, $5)
+              RETURNING id, created_at`
+	err = tx.QueryRow(ctx, query, otp.UserID, otp.Purpose, otp.CodeHash, r.pii.BlindIndex(phone), otp.ExpiresAt).Scan(&otp.ID, &otp.CreatedAt)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", otp.UserID).Str("purpose", otp.Purpose).Msg("Error creating phone otp")
+		return fmt.Errorf("error creating phone otp: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing phone otp: %w", err)
+	}
+	return nil
+}
+
+// GetOpenPhoneOTP mengembalikan kode OTP terbuka (belum dipakai, termasuk yang kedaluwarsa)
+// milik user untuk tujuan purpose, atau pgx.ErrNoRows.
+func (r *userRepo) GetOpenPhoneOTP(ctx context.Context, userID int, purpose string) (*models.PhoneOTP, error) {
+	query := `SELECT ` + shared.SelectList("po", shared.PhoneOTPColumns) + ` FROM phone_otps po
+              WHERE po.user_id = $1 AND po.purpose = $2 AND po.consumed_at IS NULL`
+	otp := &models.PhoneOTP{}
+	if err := shared.ScanPhoneOTP(r.db.QueryRow(ctx, query, userID, purpose), otp); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Str("purpose", purpose).Msg("Error getting phone otp")
+		return nil, fmt.Errorf("error getting phone otp: %w", err)
+	}
+	return otp, nil
+}
+
+// RecordPhoneOTPAttempt menaikkan jumlah percobaan kode yang salah dan mengembalikan nilai barunya.
+func (r *userRepo) RecordPhoneOTPAttempt(ctx context.Context, id int) (int, error) {
+	var attempts int
+	err := r.db.QueryRow(ctx, `UPDATE phone_otps SET attempts = attempts + 1 WHERE id = $1 RETURNING attempts`, id).Scan(&attempts)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, pgx.ErrNoRows
+		}
+		return 0, fmt.Errorf("error recording phone otp attempt %d: %w", id, err)
+	}
+	return attempts, nil
+}
+
+// ConsumePhoneOTP menandai kode sudah dipakai. Untuk tujuan verify_phone, nomor HP user sekaligus
+// ditandai terverifikasi (dalam satu transaksi), dengan syarat nomornya masih sama seperti saat
+// kode dikirim (ErrPhoneChanged). pgx.ErrNoRows jika kode sudah dipakai atau kedaluwarsa.
+func (r *userRepo) ConsumePhoneOTP(ctx context.Context, id int) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("error starting phone otp transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op jika sudah di-commit
+
+	var userID int
+	var purpose, phoneHash string
+	err = tx.QueryRow(ctx, `UPDATE phone_otps SET consumed_at = NOW()
+              WHERE id = $1 AND consumed_at IS NULL AND expires_at > NOW()
+              RETURNING user_id, purpose, phone_hash`, id).Scan(&userID, &purpose, &phoneHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		return fmt.Errorf("error consuming phone otp %d: %w", id, err)
+	}
+	// Kode login juga hanya berlaku untuk nomor yang sama (nomor berganti = 2FA dimatikan trigger).
+	query := `UPDATE users SET phone_verified_at = COALESCE(phone_verified_at, NOW()) WHERE id = $1 AND phone_hash = $2`
+	if purpose != models.OTPPurposeVerifyPhone {
+		query = `SELECT 1 FROM users WHERE id = $1 AND phone_hash = $2`
+	}
+	tag, err := tx.Exec(ctx, query, userID, phoneHash)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error marking phone verified")
+		return fmt.Errorf("error verifying phone of user %d: %w", userID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPhoneChanged
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing phone otp %d: %w", id, err)
+	}
+	return nil
+}
+
+// UpdateSMSPreferences mengubah 2FA & pengingat SMS user (nil = tidak diubah) dan mengembalikan
+// user terbaru. ErrPhoneNotVerified jika salah satunya diaktifkan tanpa nomor HP terverifikasi.
+func (r *userRepo) UpdateSMSPreferences(ctx context.Context, userID int, input *models.UpdateSMSPreferencesInput) (*models.User, error) {
+	enabling := (input.TwoFactor != nil && *input.TwoFactor) || (input.Reminders != nil && *input.Reminders)
+	query := `UPDATE users AS u SET sms_two_factor = COALESCE($2, u.sms_two_factor), sms_reminders = COALESCE($3, u.sms_reminders)
+              WHERE u.id = $1 AND (NOT $4::boolean OR u.phone_verified_at IS NOT NULL)
+              RETURNING ` + shared.SelectList("u", userColumns)
+	user := &models.User{}
+	if err := shared.ScanUser(r.db.QueryRow(ctx, query, userID, input.TwoFactor, input.Reminders, enabling), user); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error updating sms preferences")
+			return nil, fmt.Errorf("error updating sms preferences: %w", err)
+		}
+		var exists bool
+		if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, userID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("error checking user %d: %w", userID, err)
+		}
+		if !exists {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, ErrPhoneNotVerified
+	}
+	if err := shared.DecryptUserPII(r.pii, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
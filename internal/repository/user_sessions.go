@@ -0,0 +1,93 @@
+// internal/repository/user_sessions.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+// Pencabutan sesi & reset password memakai users.token_version: setiap JWT membawa versi saat
+// diterbitkan, dan token dengan versi lama ditolak middleware Protected().
+
+func (r *userRepo) GetTokenVersion(ctx context.Context, id int) (int, error) {
+	var version int
+	if err := r.db.QueryRow(ctx, `SELECT token_version FROM users WHERE id = $1`, id).Scan(&version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error getting token version")
+		return 0, fmt.Errorf("error getting token version for user %d: %w", id, err)
+	}
+	return version, nil
+}
+
+// RevokeSessions mencabut semua sesi user dan mewajibkan reset password, hanya jika versi
+// token saat ini masih expectedVersion (tautan yang sama tidak bisa dipakai dua kali).
+// Mengembalikan versi baru; pgx.ErrNoRows jika user tidak ada atau versi sudah berubah.
+func (r *userRepo) RevokeSessions(ctx context.Context, id int, expectedVersion int) (int, error) {
+	query := `UPDATE users SET token_version = token_version + 1, password_reset_required = TRUE
+              WHERE id = $1 AND token_version = $2
+              RETURNING token_version`
+	var version int
+	if err := r.db.QueryRow(ctx, query, id, expectedVersion).Scan(&version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error revoking sessions")
+		return 0, fmt.Errorf("error revoking sessions for user %d: %w", id, err)
+	}
+	shared.Logger(ctx).Info().Int("user_id", id).Int("token_version", version).Msg("User sessions revoked")
+	return version, nil
+}
+
+// ForceRevokeSessions mencabut semua sesi user tanpa syarat versi (dipakai admin untuk akun yang
+// disusupi). Mengembalikan versi baru; pgx.ErrNoRows jika user tidak ada.
+func (r *userRepo) ForceRevokeSessions(ctx context.Context, id int) (int, error) {
+	var version int
+	err := r.db.QueryRow(ctx, `UPDATE users SET token_version = token_version + 1 WHERE id = $1 RETURNING token_version`, id).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error force revoking sessions")
+		return 0, fmt.Errorf("error revoking sessions for user %d: %w", id, err)
+	}
+	shared.Logger(ctx).Info().Int("user_id", id).Int("token_version", version).Msg("User sessions revoked by admin")
+	return version, nil
+}
+
+// ResetPassword mengganti password lewat token reset: menghapus kewajiban reset/ganti password
+// dan menaikkan versi token (token reset & sesi lama tidak berlaku lagi). pgx.ErrNoRows jika
+// versi sudah berubah.
+func (r *userRepo) ResetPassword(ctx context.Context, id int, hashedPassword string, expectedVersion int) error {
+	query := `UPDATE users SET password = $1, password_reset_required = FALSE, must_change_password = FALSE,
+                  token_version = token_version + 1
+              WHERE id = $2 AND token_version = $3`
+	tag, err := r.db.Exec(ctx, query, hashedPassword, id, expectedVersion)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error resetting password")
+		return fmt.Errorf("error resetting password for user %d: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// RequirePasswordChange mewajibkan user mengganti password saat login berikutnya (password
+// sementara dari admin). Ikut transaksi RunInTx. pgx.ErrNoRows jika user tidak ada.
+func (r *userRepo) RequirePasswordChange(ctx context.Context, id int) error {
+	tag, err := conn(ctx, r.db).Exec(ctx, `UPDATE users SET must_change_password = TRUE WHERE id = $1`, id)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", id).Msg("Error requiring password change")
+		return fmt.Errorf("error requiring password change for user %d: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+// internal/repository/verification_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository/internal/shared"
+)
+
+type verificationRepo struct {
+	db Conn // Primary: pencabutan tautan harus langsung berlaku
+}
+
+func NewVerificationRepository(pools Pools) VerificationRepository {
+	return &verificationRepo{db: pools.Primary}
+}
+
+// CreateVerificationLink menyimpan tautan verifikasi kepegawaian dan mengisi ID & created_at.
+// Mengembalikan pgx.ErrNoRows jika user tidak ada.
+func (r *verificationRepo) CreateVerificationLink(ctx context.Context, link *models.EmploymentVerificationLink) error {
+	query := `INSERT INTO employment_verification_links AS evl (user_id, recipient, include_attendance, expires_at, created_by)
+              SELECT u.id, $2, $3, $4, $5 FROM users u WHERE u.id = $1
+              RETURNING ` + shared.SelectList("evl", shared.VerificationLinkColumns)
+	err := shared.ScanVerificationLink(r.db.QueryRow(ctx, query, link.UserID, link.Recipient, link.IncludeAttendance, link.ExpiresAt, link.CreatedBy), link)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", link.UserID).Msg("Error creating employment verification link")
+		return fmt.Errorf("error creating verification link for user %d: %w", link.UserID, err)
+	}
+	shared.Logger(ctx).Info().Int("link_id", link.ID).Int("user_id", link.UserID).Time("expires_at", link.ExpiresAt).Msg("Employment verification link created")
+	return nil
+}
+
+// GetVerificationLink mengembalikan tautan berdasarkan ID, atau pgx.ErrNoRows.
+func (r *verificationRepo) GetVerificationLink(ctx context.Context, id int) (*models.EmploymentVerificationLink, error) {
+	query := `SELECT ` + shared.SelectList("evl", shared.VerificationLinkColumns) + ` FROM employment_verification_links evl WHERE evl.id = $1`
+	link := &models.EmploymentVerificationLink{}
+	if err := shared.ScanVerificationLink(r.db.QueryRow(ctx, query, id), link); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("link_id", id).Msg("Error getting employment verification link")
+		return nil, fmt.Errorf("error getting verification link %d: %w", id, err)
+	}
+	return link, nil
+}
+
+// GetVerificationLinksByUser mengembalikan semua tautan milik user, terbaru dulu.
+func (r *verificationRepo) GetVerificationLinksByUser(ctx context.Context, userID int) ([]models.EmploymentVerificationLink, error) {
+	query := `SELECT ` + shared.SelectList("evl", shared.VerificationLinkColumns) + `
+              FROM employment_verification_links evl
+              WHERE evl.user_id = $1
+              ORDER BY evl.created_at DESC, evl.id DESC`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error querying employment verification links")
+		return nil, fmt.Errorf("error getting verification links of user %d: %w", userID, err)
+	}
+	defer rows.Close()
+	links := []models.EmploymentVerificationLink{}
+	for rows.Next() {
+		var l models.EmploymentVerificationLink
+		if err := shared.ScanVerificationLink(rows, &l); err != nil {
+			return nil, fmt.Errorf("error scanning verification link row: %w", err)
+		}
+		links = append(links, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating verification link rows: %w", err)
+	}
+	return links, nil
+}
+
+// RevokeVerificationLink mencabut tautan. Mengembalikan pgx.ErrNoRows jika tautan tidak ada
+// atau sudah dicabut.
+func (r *verificationRepo) RevokeVerificationLink(ctx context.Context, id int) (*models.EmploymentVerificationLink, error) {
+	query := `UPDATE employment_verification_links evl SET revoked_at = CURRENT_TIMESTAMP
+              WHERE evl.id = $1 AND evl.revoked_at IS NULL
+              RETURNING ` + shared.SelectList("evl", shared.VerificationLinkColumns)
+	link := &models.EmploymentVerificationLink{}
+	if err := shared.ScanVerificationLink(r.db.QueryRow(ctx, query, id), link); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("link_id", id).Msg("Error revoking employment verification link")
+		return nil, fmt.Errorf("error revoking verification link %d: %w", id, err)
+	}
+	shared.Logger(ctx).Info().Int("link_id", id).Msg("Employment verification link revoked")
+	return link, nil
+}
+
+// RecordVerificationAccess mencatat satu akses ke tautan. Akses yang dilayani (granted) juga
+// menambah access_count dan last_accessed_at tautan dalam statement yang sama.
+func (r *verificationRepo) RecordVerificationAccess(ctx context.Context, access *models.EmploymentVerificationAccess) error {
+	query := `WITH ins AS (
+                  INSERT INTO employment_verification_accesses (link_id, ip, user_agent, outcome)
+                  VALUES ($1, $2, $3, $4)
+                  RETURNING id, accessed_at
+              ), upd AS (
+                  UPDATE employment_verification_links
+                  SET access_count = access_count + 1, last_accessed_at = (SELECT accessed_at FROM ins)
+                  WHERE id = $1 AND $4 = 'granted'
+              )
+              SELECT id, accessed_at FROM ins`
+	err := r.db.QueryRow(ctx, query, access.LinkID, access.IP, access.UserAgent, access.Outcome).Scan(&access.ID, &access.AccessedAt)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("link_id", access.LinkID).Str("outcome", access.Outcome).Msg("Error recording employment verification access")
+		return fmt.Errorf("error recording access to verification link %d: %w", access.LinkID, err)
+	}
+	return nil
+}
+
+// GetVerificationAccesses mengembalikan riwayat akses tautan, terbaru dulu.
+func (r *verificationRepo) GetVerificationAccesses(ctx context.Context, linkID int) ([]models.EmploymentVerificationAccess, error) {
+	query := `SELECT ` + shared.SelectList("eva", shared.VerificationAccessColumns) + `
+              FROM employment_verification_accesses eva
+              WHERE eva.link_id = $1
+              ORDER BY eva.accessed_at DESC, eva.id DESC`
+	rows, err := r.db.Query(ctx, query, linkID)
+	if err != nil {
+		shared.Logger(ctx).Error().Err(err).Int("link_id", linkID).Msg("Error querying employment verification accesses")
+		return nil, fmt.Errorf("error getting accesses of verification link %d: %w", linkID, err)
+	}
+	defer rows.Close()
+	accesses := []models.EmploymentVerificationAccess{}
+	for rows.Next() {
+		var a models.EmploymentVerificationAccess
+		if err := shared.ScanVerificationAccess(rows, &a); err != nil {
+			return nil, fmt.Errorf("error scanning verification access row: %w", err)
+		}
+		accesses = append(accesses, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating verification access rows: %w", err)
+	}
+	return accesses, nil
+}
+
+// GetEmploymentVerification menyusun data verifikasi terbatas untuk user. Jika attendanceSince
+// tidak nil, jumlah tanggal dengan check-in sejak tanggal itu (sampai hari ini) disertakan.
+// Mengembalikan pgx.ErrNoRows jika user tidak ada.
+func (r *verificationRepo) GetEmploymentVerification(ctx context.Context, userID int, attendanceSince *time.Time) (*models.EmploymentVerification, error) {
+	query := `SELECT TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), u.username,
+                     u.is_active AND u.employment_status <> 'terminated', u.employment_status,
+                     r.name, u.hire_date::text,
+                     (SELECT COUNT(DISTINCT a.check_in_at::date) FROM attendances a
+                      WHERE a.user_id = u.id AND $2::date IS NOT NULL AND a.check_in_at >= $2::date)
+              FROM users u JOIN roles r ON r.id = u.role_id
+              WHERE u.id = $1`
+	var since *string
+	if attendanceSince != nil {
+		s := attendanceSince.Format(shared.DateLayout)
+		since = &s
+	}
+	v := &models.EmploymentVerification{}
+	var username string
+	var daysPresent int
+	err := r.db.QueryRow(ctx, query, userID, since).Scan(&v.EmployeeName, &username, &v.Employed, &v.EmploymentStatus, &v.Position, &v.HireDate, &daysPresent)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		shared.Logger(ctx).Error().Err(err).Int("user_id", userID).Msg("Error getting employment verification data")
+		return nil, fmt.Errorf("error getting employment verification of user %d: %w", userID, err)
+	}
+	if v.EmployeeName == "" {
+		v.EmployeeName = username // User tanpa nama lengkap
+	}
+	if since != nil {
+		v.Attendance = &models.EmploymentVerificationAttendance{
+			PeriodStart: *since, PeriodEnd: time.Now().Format(shared.DateLayout), DaysPresent: daysPresent,
+		}
+	}
+	return v, nil
+}
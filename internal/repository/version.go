@@ -0,0 +1,34 @@
+// internal/repository/version.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Helper optimistic locking untuk tabel yang memiliki kolom version
+// (shifts, user_schedules, users). Kolom version dinaikkan oleh trigger database.
+// Query update memakai kondisi `($n::int IS NULL OR version = $n)`, sehingga
+// precondition hanya berlaku jika klien mengirim versi yang diharapkan.
+
+// ErrVersionConflict dikembalikan jika versi yang diharapkan klien sudah usang
+// (record telah diubah oleh request lain).
+var ErrVersionConflict = errors.New("record was modified by another request")
+
+// resolveMissedUpdate dipanggil ketika UPDATE bersyarat version tidak mengenai baris mana pun:
+// mengembalikan pgx.ErrNoRows jika record tidak ada, atau ErrVersionConflict jika versinya berbeda.
+// table hanya boleh berisi nama tabel konstanta dari repository (bukan input user).
+func resolveMissedUpdate(ctx context.Context, db Conn, table string, id int) error {
+	var exists bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1)`, table)
+	if err := db.QueryRow(ctx, query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("error checking %s id %d after update: %w", table, id, err)
+	}
+	if !exists {
+		return pgx.ErrNoRows
+	}
+	return ErrVersionConflict
+}
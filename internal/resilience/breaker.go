@@ -0,0 +1,190 @@
+// internal/resilience/breaker.go
+package resilience
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/metrics"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// ErrCircuitOpen dikembalikan tanpa memanggil target selama breaker terbuka.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Status breaker.
+const (
+	StateClosed   = "closed"    // Normal, semua panggilan diteruskan
+	StateOpen     = "open"      // Target dianggap mati, panggilan ditolak sampai OpenTimeout lewat
+	StateHalfOpen = "half_open" // Satu panggilan percobaan diteruskan untuk menguji pemulihan
+)
+
+// Breaker adalah circuit breaker untuk satu integrasi. Setelah FailureThreshold kegagalan
+// sementara berturut-turut breaker terbuka; setelah OpenTimeout satu panggilan percobaan
+// diteruskan, dan hasilnya menutup atau membuka kembali breaker.
+type Breaker struct {
+	name        string
+	threshold   int
+	openTimeout time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool // Panggilan percobaan half-open sedang berjalan
+
+	stats        *expvar.Map
+	stateVar     *expvar.String
+	failuresVar  *expvar.Int
+	callsTotal   *expvar.Int
+	failedTotal  *expvar.Int
+	rejectTotal  *expvar.Int
+	retriesTotal *expvar.Int
+	openedTotal  *expvar.Int
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Breaker{}
+)
+
+// Get mengembalikan breaker bernama name (mis. "notify.webhook"), membuatnya dari
+// environment variables pada pemanggilan pertama. Nama yang sama berbagi satu breaker.
+//
+// Variabel Environment yang didukung:
+//   - OUTBOUND_BREAKER_FAILURE_THRESHOLD: Kegagalan berturut-turut sebelum breaker terbuka. Default: 5. 0 menonaktifkan breaker.
+//   - OUTBOUND_BREAKER_OPEN_TIMEOUT: Lama breaker terbuka sebelum panggilan percobaan. Default: 30s.
+func Get(name string) *Breaker {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if b, ok := registry[name]; ok {
+		return b
+	}
+	b := newBreaker(name,
+		configs.GetEnvInt("OUTBOUND_BREAKER_FAILURE_THRESHOLD", 5),
+		configs.GetEnvDuration("OUTBOUND_BREAKER_OPEN_TIMEOUT", 30*time.Second))
+	registry[name] = b
+	return b
+}
+
+func newBreaker(name string, threshold int, openTimeout time.Duration) *Breaker {
+	b := &Breaker{
+		name: name, threshold: threshold, openTimeout: max(openTimeout, time.Second), state: StateClosed,
+		stats:        new(expvar.Map).Init(),
+		stateVar:     new(expvar.String),
+		failuresVar:  new(expvar.Int),
+		callsTotal:   new(expvar.Int),
+		failedTotal:  new(expvar.Int),
+		rejectTotal:  new(expvar.Int),
+		retriesTotal: new(expvar.Int),
+		openedTotal:  new(expvar.Int),
+	}
+	b.stateVar.Set(StateClosed)
+	b.stats.Set("state", b.stateVar)
+	b.stats.Set("consecutive_failures", b.failuresVar)
+	b.stats.Set("calls_total", b.callsTotal)
+	b.stats.Set("failures_total", b.failedTotal)
+	b.stats.Set("rejected_total", b.rejectTotal)
+	b.stats.Set("retries_total", b.retriesTotal)
+	b.stats.Set("opened_total", b.openedTotal)
+	metrics.CircuitBreakers.Set(name, b.stats)
+	return b
+}
+
+// State mengembalikan status breaker saat ini (lihat State*).
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// allow memutuskan apakah panggilan boleh diteruskan; probe=true untuk panggilan percobaan half-open.
+func (b *Breaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.threshold <= 0 {
+		return false, nil
+	}
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return false, ErrCircuitOpen
+		}
+		b.setState(StateHalfOpen)
+		fallthrough
+	case StateHalfOpen:
+		if b.probing {
+			return false, ErrCircuitOpen
+		}
+		b.probing = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// record mencatat hasil panggilan; failed=true untuk kegagalan sementara.
+func (b *Breaker) record(probe, failed bool, cause error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		b.probing = false
+	}
+	if !failed {
+		if b.state != StateClosed {
+			zlog.Info().Str("breaker", b.name).Msg("Circuit breaker closed, integration recovered")
+		}
+		b.failures = 0
+		b.failuresVar.Set(0)
+		b.setState(StateClosed)
+		return
+	}
+	b.failures++
+	b.failuresVar.Set(int64(b.failures))
+	b.failedTotal.Add(1)
+	if b.threshold > 0 && (probe || (b.state == StateClosed && b.failures >= b.threshold)) {
+		b.openedAt = time.Now()
+		b.openedTotal.Add(1)
+		b.setState(StateOpen)
+		zlog.Warn().Err(cause).Str("breaker", b.name).Int("consecutive_failures", b.failures).Dur("open_for", b.openTimeout).Msg("Circuit breaker opened")
+	}
+}
+
+func (b *Breaker) setState(state string) {
+	b.state = state
+	b.stateVar.Set(state)
+}
+
+func (b *Breaker) retried() {
+	if b != nil {
+		b.retriesTotal.Add(1)
+	}
+}
+
+// call menjalankan satu percobaan fn lewat breaker (tanpa breaker jika b nil).
+func (b *Breaker) call(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	if b == nil {
+		return attempt(ctx, p, fn)
+	}
+	probe, err := b.allow()
+	if err != nil {
+		b.rejectTotal.Add(1)
+		return err
+	}
+	b.callsTotal.Add(1)
+	err = attempt(ctx, p, fn)
+	// Pembatalan oleh pemanggil bukan kesalahan target.
+	if err != nil && ctx.Err() != nil {
+		b.mu.Lock()
+		if probe {
+			b.probing = false
+		}
+		b.mu.Unlock()
+		return err
+	}
+	b.record(probe, err != nil && p.retryable(err), err)
+	return err
+}
@@ -0,0 +1,132 @@
+// internal/resilience/resilience.go
+
+// Package resilience membungkus panggilan ke sistem eksternal (SMTP, webhook, push, GeoIP)
+// dengan timeout per percobaan, retry dengan exponential backoff + jitter, dan circuit breaker
+// per integrasi. Breaker yang terbuka menolak panggilan seketika (ErrCircuitOpen) sehingga satu
+// target yang lambat atau mati tidak menahan worker/request; statusnya diekspos lewat
+// GET /admin/metrics (grup circuit_breakers).
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2" // Jitter untuk backoff retry.
+	"net/http"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+)
+
+// Policy mengatur timeout dan retry satu panggilan keluar.
+type Policy struct {
+	Timeout     time.Duration // Batas waktu per percobaan; 0 = hanya mengikuti ctx
+	MaxAttempts int           // Jumlah percobaan maksimum (minimal 1)
+	BaseDelay   time.Duration // Jeda sebelum percobaan kedua, dilipatgandakan tiap percobaan
+	MaxDelay    time.Duration // Batas atas jeda
+	// Retryable menentukan apakah error layak dicoba ulang dan dihitung sebagai kegagalan
+	// breaker. nil = semua error kecuali yang dibungkus Permanent.
+	Retryable func(error) bool
+}
+
+// PolicyFromEnv membuat Policy bersama untuk integrasi keluar dengan timeout per percobaan
+// dari konfigurasi integrasi masing-masing.
+//
+// Variabel Environment yang didukung:
+//   - OUTBOUND_RETRY_MAX_ATTEMPTS: Jumlah percobaan untuk kegagalan sementara. Default: 3.
+//   - OUTBOUND_RETRY_BASE_DELAY: Jeda awal antar percobaan (dilipatgandakan, dengan jitter). Default: 200ms.
+//   - OUTBOUND_RETRY_MAX_DELAY: Batas atas jeda antar percobaan. Default: 5s.
+func PolicyFromEnv(timeout time.Duration) Policy {
+	return Policy{
+		Timeout:     timeout,
+		MaxAttempts: max(configs.GetEnvInt("OUTBOUND_RETRY_MAX_ATTEMPTS", 3), 1),
+		BaseDelay:   configs.GetEnvDuration("OUTBOUND_RETRY_BASE_DELAY", 200*time.Millisecond),
+		MaxDelay:    configs.GetEnvDuration("OUTBOUND_RETRY_MAX_DELAY", 5*time.Second),
+	}
+}
+
+// permanentError menandai error yang tidak layak dicoba ulang (mis. HTTP 4xx): target
+// merespons, jadi tidak dihitung sebagai kegagalan breaker.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent membungkus err agar Do tidak mencobanya ulang. Do mengembalikan err aslinya.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+func (p Policy) retryable(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return true
+}
+
+// Do menjalankan fn lewat breaker b dengan policy p. Kegagalan sementara dicoba ulang sampai
+// MaxAttempts selama breaker mengizinkan; error permanen dan ErrCircuitOpen dikembalikan langsung.
+// b boleh nil (tanpa circuit breaker).
+func Do(ctx context.Context, b *Breaker, p Policy, fn func(ctx context.Context) error) error {
+	delay := p.BaseDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = b.call(ctx, p, fn)
+		if err == nil || !p.retryable(err) || attempt >= p.MaxAttempts {
+			break
+		}
+		b.retried()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(withJitter(delay)):
+		}
+		delay = min(delay*2, max(p.MaxDelay, p.BaseDelay))
+	}
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return permanent.err
+	}
+	return err
+}
+
+// attempt menjalankan satu percobaan dengan timeout policy.
+func attempt(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	if p.Timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// withJitter mengembalikan durasi acak antara d/2 dan d agar retry banyak worker tidak serempak.
+func withJitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + rand.N(half)
+}
+
+// HTTPStatusError mengklasifikasikan respons HTTP non-2xx: 429 dan 5xx adalah kegagalan
+// sementara (dicoba ulang, dihitung breaker), status lain permanen.
+func HTTPStatusError(err error, status int) error {
+	if status == http.StatusTooManyRequests || status >= 500 {
+		return err
+	}
+	return Permanent(err)
+}
@@ -0,0 +1,114 @@
+// internal/session/versions.go
+
+// Package session memeriksa apakah token sesi (JWT) masih berlaku terhadap users.token_version.
+// Menaikkan token_version (mis. lewat tautan "bukan saya", reset password, ganti role, atau
+// penonaktifan user) mencabut semua token yang sudah terbit; versi di-cache singkat agar tidak
+// setiap request membaca database.
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/configs"
+)
+
+// ErrUserNotFound dikembalikan Current jika user sudah tidak ada.
+var ErrUserNotFound = errors.New("session user not found")
+
+// maxCachedUsers membatasi ukuran cache; entri kedaluwarsa dibuang saat batas tercapai.
+const maxCachedUsers = 10000
+
+// VersionStore membaca users.token_version (dipenuhi repository.UserRepository).
+type VersionStore interface {
+	GetTokenVersion(ctx context.Context, userID int) (int, error)
+}
+
+type cachedVersion struct {
+	version   int
+	expiresAt time.Time
+}
+
+// VersionCache menyimpan token_version per user selama ttl. Pencabutan dari instance ini
+// langsung berlaku (Invalidate); instance lain melihatnya paling lambat setelah ttl.
+type VersionCache struct {
+	store   VersionStore
+	ttl     time.Duration
+	stale   func(err error) bool // Opsional; lihat ServeStaleWhen
+	mu      sync.Mutex
+	entries map[int]cachedVersion
+}
+
+// NewVersionCache membuat cache dengan ttl tertentu (0 = selalu membaca database).
+func NewVersionCache(store VersionStore, ttl time.Duration) *VersionCache {
+	return &VersionCache{store: store, ttl: ttl, entries: make(map[int]cachedVersion)}
+}
+
+// NewVersionCacheFromEnv membuat VersionCache dengan SESSION_VERSION_CACHE_TTL (default 30s).
+func NewVersionCacheFromEnv(store VersionStore) *VersionCache {
+	return NewVersionCache(store, configs.GetEnvDuration("SESSION_VERSION_CACHE_TTL", 30*time.Second))
+}
+
+// Current mengembalikan token_version user saat ini.
+func (c *VersionCache) Current(ctx context.Context, userID int) (int, error) {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[userID]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.version, nil
+	}
+
+	version, err := c.store.GetTokenVersion(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrUserNotFound
+		}
+		if ok && c.stale != nil && c.stale(err) {
+			return entry.version, nil
+		}
+		return 0, err
+	}
+	if c.ttl > 0 {
+		c.mu.Lock()
+		if len(c.entries) >= maxCachedUsers {
+			for id, e := range c.entries {
+				if !now.Before(e.expiresAt) {
+					delete(c.entries, id)
+				}
+			}
+			if len(c.entries) >= maxCachedUsers {
+				clear(c.entries)
+			}
+		}
+		c.entries[userID] = cachedVersion{version: version, expiresAt: now.Add(c.ttl)}
+		c.mu.Unlock()
+	}
+	return version, nil
+}
+
+// ServeStaleWhen membuat Current mengembalikan versi yang sudah kedaluwarsa di cache (jika ada)
+// saat pembacaan database gagal dengan error yang diterima fn, mis. degraded.Controller.ReportError
+// ketika database tidak tersedia. Pencabutan sesi yang terjadi di instance lain selama itu baru
+// berlaku setelah database pulih. Dipanggil sebelum server menerima request.
+func (c *VersionCache) ServeStaleWhen(fn func(err error) bool) {
+	c.stale = fn
+}
+
+// Invalidate membuang versi user dari cache (panggil setelah token_version berubah).
+func (c *VersionCache) Invalidate(userID int) {
+	c.mu.Lock()
+	delete(c.entries, userID)
+	c.mu.Unlock()
+}
+
+// InvalidateAll mengosongkan cache, mis. setelah perubahan yang menaikkan token_version banyak
+// user sekaligus (ganti nama role).
+func (c *VersionCache) InvalidateAll() {
+	c.mu.Lock()
+	clear(c.entries)
+	c.mu.Unlock()
+}
@@ -0,0 +1,383 @@
+// internal/settings/definitions.go
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// Key pengaturan yang dikenal. Key lain ditolak oleh Store.Update.
+const (
+	KeyGraceMinutes         = "attendance.grace_minutes"          // Toleransi keterlambatan setelah jam mulai shift.
+	KeyCheckInWindowMinutes = "attendance.checkin_window_minutes" // Seberapa awal check-in boleh dilakukan sebelum jam mulai shift.
+	KeyDefaultTimezone      = "general.default_timezone"          // Zona waktu IANA untuk tanggal kerja & laporan.
+	KeyReportSenderEmail    = "reports.sender_email"              // Alamat pengirim email laporan (kosong = nonaktif).
+	KeyNightStart           = "payroll.night_start"               // Awal jam malam (HH:MM) untuk kategori jam malam.
+	KeyNightEnd             = "payroll.night_end"                 // Akhir jam malam (HH:MM); boleh lebih kecil dari awal (melewati tengah malam).
+	KeyWeekendDays          = "payroll.weekend_days"              // Hari akhir pekan, mis. "sat,sun".
+	KeyHolidays             = "payroll.holidays"                  // Kalender hari libur: daftar tanggal YYYY-MM-DD dipisah koma.
+	KeyWorkingDays          = "calendar.working_days"             // Hari kerja dalam seminggu, mis. "mon,tue,wed,thu,fri".
+	KeyHalfDays             = "calendar.half_days"                // Hari kerja setengah hari, mis. "sat".
+	KeyUsernameChangePolicy = "profile.username_change_policy"    // Ganti username lewat profil: allow, deny, atau approval.
+	KeyRegistrationMode     = "auth.registration_mode"            // Registrasi akun baru: open (publik) atau invite_only.
+	KeyRegistrationRole     = "auth.registration_default_role"    // Role untuk registrasi publik tanpa role_id.
+	KeyRegistrationRoles    = "auth.registration_roles"           // Role lain yang boleh dipilih sendiri saat registrasi publik.
+	KeyAttendanceTags       = "attendance.tags"                   // Tag yang boleh dipasang pada absensi, mis. "client_visit,sick,wfh".
+	KeyRoutePingInterval    = "route.ping_interval_seconds"       // Jeda minimum antar ping lokasi sesi field.
+	KeyRouteRetentionDays   = "route.retention_days"              // Berapa lama ping lokasi disimpan.
+	KeyRoutePrecision       = "route.manager_precision"           // Desimal koordinat rute yang terlihat oleh atasan.
+	KeyDebugCaptureRoutes   = "debug.capture_routes"              // Route yang request/response-nya direkam untuk debugging.
+	KeyDebugCaptureUsers    = "debug.capture_users"               // ID user yang semua request/response-nya direkam.
+	KeyDebugCaptureTTL      = "debug.capture_ttl_hours"           // Berapa lama rekaman debug disimpan.
+	KeyMaintenanceMode      = "maintenance.mode"                  // Mode maintenance: off atau on (route non-admin dijawab 503).
+	KeyMaintenanceMessage   = "maintenance.message"               // Pesan untuk klien selama maintenance.
+	KeyMaintenanceRetry     = "maintenance.retry_after_seconds"   // Nilai header Retry-After selama maintenance.
+	KeyMaintenanceCheckIns  = "maintenance.checkins"              // Check-in selama maintenance: reject atau buffer.
+)
+
+// Tipe nilai pengaturan.
+const (
+	TypeInt      = "int"
+	TypeString   = "string"
+	TypeEmail    = "email"
+	TypeTimezone = "timezone"
+	TypeClock    = "clock"     // Jam HH:MM
+	TypeWeekdays = "weekdays"  // Daftar hari (sun..sat) dipisah koma
+	TypeDateList = "date_list" // Daftar tanggal YYYY-MM-DD dipisah koma
+	TypeChoice   = "choice"    // Salah satu dari Options
+	TypeNameList = "name_list" // Daftar nama (mis. role) dipisah koma, dibandingkan tanpa membedakan huruf besar/kecil
+
+	TypeRouteList = "route_list" // Daftar pola route ("[method] /path", boleh diakhiri *) dipisah koma
+	TypeIDList    = "id_list"    // Daftar ID (bilangan bulat positif) dipisah koma
+)
+
+// maxDateListLength membatasi panjang kalender libur (sekitar 400 tanggal).
+const maxDateListLength = 4400
+
+// maxRouteListLength membatasi panjang daftar pola route.
+const maxRouteListLength = 2000
+
+// routeMethods adalah method HTTP yang boleh mengawali pola TypeRouteList.
+var routeMethods = []string{"get", "post", "put", "patch", "delete"}
+
+// weekdayNames adalah singkatan hari untuk TypeWeekdays, urut sesuai time.Weekday.
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ErrUnknownKey dikembalikan jika key tidak terdaftar di definitions.
+var ErrUnknownKey = errors.New("unknown setting key")
+
+// Definition mendeskripsikan satu key: tipe, default, dan batas nilai.
+type Definition struct {
+	Key         string
+	Type        string
+	Default     string // Dalam bentuk teks (format penyimpanan)
+	Description string
+	Min, Max    int      // Hanya untuk TypeInt
+	Options     []string // Hanya untuk TypeChoice
+}
+
+var definitions = []Definition{
+	{
+		Key: KeyGraceMinutes, Type: TypeInt, Default: "5", Min: 0, Max: 240,
+		Description: "Minutes after shift start before a check-in counts as late.",
+	},
+	{
+		Key: KeyCheckInWindowMinutes, Type: TypeInt, Default: "60", Min: 0, Max: 720,
+		Description: "Minutes before shift start from which check-in is accepted.",
+	},
+	{
+		Key: KeyDefaultTimezone, Type: TypeTimezone, Default: "UTC",
+		Description: "IANA time zone used for work dates and reports (e.g. Asia/Jakarta).",
+	},
+	{
+		Key: KeyReportSenderEmail, Type: TypeEmail, Default: "",
+		Description: "Sender address for report emails; empty disables report emails.",
+	},
+	{
+		Key: KeyNightStart, Type: TypeClock, Default: "22:00",
+		Description: "Start of night hours (HH:MM) for the night-shift differential; equal to the end disables night hours.",
+	},
+	{
+		Key: KeyNightEnd, Type: TypeClock, Default: "06:00",
+		Description: "End of night hours (HH:MM); may be earlier than the start to span midnight.",
+	},
+	{
+		Key: KeyWeekendDays, Type: TypeWeekdays, Default: "sat,sun",
+		Description: "Comma-separated weekend days (sun, mon, tue, wed, thu, fri, sat) paid as weekend hours.",
+	},
+	{
+		Key: KeyHolidays, Type: TypeDateList, Default: "",
+		Description: "Holiday calendar: comma-separated dates (YYYY-MM-DD) paid as holiday hours.",
+	},
+	{
+		Key: KeyWorkingDays, Type: TypeWeekdays, Default: "mon,tue,wed,thu,fri",
+		Description: "Comma-separated working days of the week (sun, mon, tue, wed, thu, fri, sat), e.g. sun,mon,tue,wed,thu. Holidays are never working days.",
+	},
+	{
+		Key: KeyHalfDays, Type: TypeWeekdays, Default: "",
+		Description: "Comma-separated days of the week that are half working days, e.g. sat. A half day counts as a working day even if missing from the working days.",
+	},
+	{
+		Key: KeyUsernameChangePolicy, Type: TypeChoice, Default: models.UsernamePolicyAllow,
+		Options:     []string{models.UsernamePolicyAllow, models.UsernamePolicyDeny, models.UsernamePolicyApproval},
+		Description: "Whether users may change their own username: allow (immediately), deny, or approval (an admin must approve the new username). Admins can always rename users.",
+	},
+	{
+		Key: KeyRegistrationMode, Type: TypeChoice, Default: models.RegistrationOpen,
+		Options:     []string{models.RegistrationOpen, models.RegistrationInviteOnly},
+		Description: "Who can create accounts: open (anyone through POST /auth/register) or invite_only (only people invited by an administrator, with the role chosen in the invitation).",
+	},
+	{
+		Key: KeyRegistrationRole, Type: TypeString, Default: "Employee",
+		Description: "Role given to users who register themselves without choosing a role_id; empty makes role_id required. Always self-assignable.",
+	},
+	{
+		Key: KeyRegistrationRoles, Type: TypeNameList, Default: "",
+		Description: "Comma-separated names of other roles users may choose when registering themselves (e.g. contractor); any other role_id is rejected, so admin roles can only be granted by an administrator.",
+	},
+	{
+		Key: KeyAttendanceTags, Type: TypeNameList, Default: "client_visit,sick,wfh",
+		Description: "Comma-separated tags employees may attach to a check-in or check-out (e.g. wfh, client_visit, sick); reports aggregate worked hours per tag. Empty disables tags.",
+	},
+	{
+		Key: KeyRoutePingInterval, Type: TypeInt, Default: "60", Min: 30, Max: 3600,
+		Description: "Minimum seconds between two location pings of a field session; faster pings are rejected.",
+	},
+	{
+		Key: KeyRouteRetentionDays, Type: TypeInt, Default: "30", Min: 1, Max: 365,
+		Description: "Days location pings of field sessions are kept before they are deleted.",
+	},
+	{
+		Key: KeyRoutePrecision, Type: TypeInt, Default: "3", Min: 2, Max: 6,
+		Description: "Decimal places of route coordinates shown to managers (3 is about 100 m); employees always see their own route in full.",
+	},
+	{
+		Key: KeyDebugCaptureRoutes, Type: TypeRouteList, Default: "",
+		Description: "Comma-separated API routes whose requests and responses are recorded (redacted) for debugging, as registered: an optional method and the route pattern, e.g. \"POST /api/v1/user/attendance/checkin\" or \"/api/v1/user/*\" (trailing * matches a prefix). Empty disables route capture.",
+	},
+	{
+		Key: KeyDebugCaptureUsers, Type: TypeIDList, Default: "",
+		Description: "Comma-separated user IDs whose API requests and responses are all recorded (redacted) for debugging, e.g. 12,45. Empty disables user capture.",
+	},
+	{
+		Key: KeyDebugCaptureTTL, Type: TypeInt, Default: "24", Min: 1, Max: 168,
+		Description: "Hours a recorded request/response is kept before it is deleted.",
+	},
+	{
+		Key: KeyMaintenanceMode, Type: TypeChoice, Default: models.MaintenanceOff,
+		Options:     []string{models.MaintenanceOff, models.MaintenanceOn},
+		Description: "Maintenance mode: on answers every non-admin route with 503 and a Retry-After header (admin routes, login and /health keep working), e.g. for migrations during working hours. Usually set through POST /admin/maintenance/enable and /disable; other instances follow within SETTINGS_CACHE_TTL.",
+	},
+	{
+		Key: KeyMaintenanceMessage, Type: TypeString, Default: "",
+		Description: "Message returned to clients in the 503 response during maintenance. Empty uses a generic message.",
+	},
+	{
+		Key: KeyMaintenanceRetry, Type: TypeInt, Default: "300", Min: 5, Max: 86400,
+		Description: "Seconds sent in the Retry-After header during maintenance.",
+	},
+	{
+		Key: KeyMaintenanceCheckIns, Type: TypeChoice, Default: models.MaintenanceCheckInsReject,
+		Options:     []string{models.MaintenanceCheckInsReject, models.MaintenanceCheckInsBuffer},
+		Description: "Check-ins during maintenance: reject (503 like other routes) or buffer (accepted with 202 and recorded with their original time once maintenance ends; kept in the memory of the instance that received them).",
+	},
+}
+
+// Definitions mengembalikan salinan semua definisi pengaturan (urut sesuai registrasi).
+func Definitions() []Definition {
+	return append([]Definition(nil), definitions...)
+}
+
+// Lookup mencari definisi berdasarkan key.
+func Lookup(key string) (Definition, bool) {
+	for _, d := range definitions {
+		if d.Key == key {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Normalize memvalidasi nilai dari request JSON dan mengembalikan bentuk teks kanoniknya.
+// Angka JSON (float64) maupun string angka diterima untuk TypeInt.
+func (d Definition) Normalize(raw any) (string, error) {
+	switch d.Type {
+	case TypeInt:
+		n, err := toInt(raw)
+		if err != nil {
+			return "", err
+		}
+		if n < d.Min || n > d.Max {
+			return "", fmt.Errorf("must be between %d and %d", d.Min, d.Max)
+		}
+		return strconv.Itoa(n), nil
+	case TypeString, TypeEmail, TypeTimezone:
+		s, ok := raw.(string)
+		if !ok {
+			return "", fmt.Errorf("must be a string")
+		}
+		s = strings.TrimSpace(s)
+		if err := d.validateString(s); err != nil {
+			return "", err
+		}
+		return s, nil
+	case TypeClock:
+		s, ok := raw.(string)
+		if !ok {
+			return "", fmt.Errorf("must be a string")
+		}
+		t, err := time.Parse("15:04", strings.TrimSpace(s))
+		if err != nil {
+			return "", fmt.Errorf("must be a time in HH:MM format")
+		}
+		return t.Format("15:04"), nil
+	case TypeWeekdays, TypeDateList, TypeNameList, TypeRouteList, TypeIDList:
+		s, ok := raw.(string)
+		if !ok {
+			return "", fmt.Errorf("must be a string")
+		}
+		return d.normalizeList(s)
+	case TypeChoice:
+		s, ok := raw.(string)
+		if !ok {
+			return "", fmt.Errorf("must be a string")
+		}
+		s = strings.ToLower(strings.TrimSpace(s))
+		if !slices.Contains(d.Options, s) {
+			return "", fmt.Errorf("must be one of: %s", strings.Join(d.Options, ", "))
+		}
+		return s, nil
+	}
+	return "", fmt.Errorf("unsupported setting type %q", d.Type)
+}
+
+func (d Definition) validateString(s string) error {
+	switch d.Type {
+	case TypeEmail:
+		if s == "" {
+			return nil
+		}
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+			return fmt.Errorf("must be a valid email address")
+		}
+	case TypeTimezone:
+		if s == "" {
+			return fmt.Errorf("must not be empty")
+		}
+		if _, err := time.LoadLocation(s); err != nil {
+			return fmt.Errorf("must be a valid IANA time zone")
+		}
+	}
+	if len(s) > 255 {
+		return fmt.Errorf("must be at most 255 characters")
+	}
+	return nil
+}
+
+// normalizeList memvalidasi daftar dipisah koma (hari, tanggal, nama, pola route, atau ID) dan
+// mengembalikannya dalam bentuk kanonik: huruf kecil, tanpa spasi & duplikat, terurut.
+func (d Definition) normalizeList(s string) (string, error) {
+	seen := make(map[string]bool)
+	var items []string
+	for _, part := range strings.Split(s, ",") {
+		item := strings.ToLower(strings.TrimSpace(part))
+		if d.Type == TypeRouteList {
+			item = strings.Join(strings.Fields(item), " ") // "POST   /x" -> "post /x"
+		}
+		if item == "" || seen[item] {
+			continue
+		}
+		switch d.Type {
+		case TypeWeekdays:
+			if !slices.Contains(weekdayNames, item) {
+				return "", fmt.Errorf("invalid day %q, use: %s", item, strings.Join(weekdayNames, ", "))
+			}
+		case TypeDateList:
+			if _, err := time.Parse("2006-01-02", item); err != nil {
+				return "", fmt.Errorf("invalid date %q, use YYYY-MM-DD", item)
+			}
+		case TypeNameList:
+			if len(item) > 50 {
+				return "", fmt.Errorf("name %q must be at most 50 characters", item)
+			}
+		case TypeRouteList:
+			method, path, hasMethod := strings.Cut(item, " ")
+			if !hasMethod {
+				path = method
+			} else if !slices.Contains(routeMethods, method) {
+				return "", fmt.Errorf("invalid method in %q, use: %s", item, strings.Join(routeMethods, ", "))
+			}
+			if !strings.HasPrefix(path, "/") || strings.Contains(path, " ") || strings.Contains(strings.TrimSuffix(path, "*"), "*") {
+				return "", fmt.Errorf("invalid route %q, use e.g. \"post /api/v1/user/attendance/checkin\" or \"/api/v1/user/*\"", item)
+			}
+		case TypeIDList:
+			if n, err := strconv.Atoi(item); err != nil || n <= 0 || strconv.Itoa(n) != item {
+				return "", fmt.Errorf("invalid id %q, use positive whole numbers", item)
+			}
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+	switch d.Type {
+	case TypeWeekdays:
+		slices.SortFunc(items, func(a, b string) int {
+			return slices.Index(weekdayNames, a) - slices.Index(weekdayNames, b)
+		})
+	case TypeIDList:
+		slices.SortFunc(items, func(a, b string) int {
+			x, _ := strconv.Atoi(a)
+			y, _ := strconv.Atoi(b)
+			return x - y
+		})
+	default:
+		slices.Sort(items)
+	}
+	out := strings.Join(items, ",")
+	if d.Type == TypeDateList && len(out) > maxDateListLength {
+		return "", fmt.Errorf("must be at most %d characters", maxDateListLength)
+	}
+	if (d.Type == TypeNameList || d.Type == TypeIDList) && len(out) > 255 {
+		return "", fmt.Errorf("must be at most 255 characters")
+	}
+	if d.Type == TypeRouteList && len(out) > maxRouteListLength {
+		return "", fmt.Errorf("must be at most %d characters", maxRouteListLength)
+	}
+	return out, nil
+}
+
+// typed mengubah nilai teks tersimpan ke tipe JSON untuk response API.
+func (d Definition) typed(value string) any {
+	if d.Type == TypeInt {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return value
+}
+
+func toInt(raw any) (int, error) {
+	switch v := raw.(type) {
+	case int: // Dari pemanggil Go (mis. maintenance.Controller), bukan JSON
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) || v > math.MaxInt32 || v < math.MinInt32 {
+			return 0, fmt.Errorf("must be a whole number")
+		}
+		return int(v), nil
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("must be a whole number")
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("must be a whole number")
+}
@@ -0,0 +1,343 @@
+// internal/settings/store.go
+
+// Package settings menyediakan pengaturan sistem yang bisa diubah saat runtime
+// (GET/PUT /admin/settings) tanpa restart aplikasi.
+//
+// Nilai disimpan di tabel settings dan di-cache in-process. Cache diinvalidasi langsung
+// setelah Update pada instance yang sama; instance lain memuat ulang setelah TTL
+// (SETTINGS_CACHE_TTL, default 30s).
+package settings
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/worktime"
+	"github.com/rs/zerolog/log"
+)
+
+// ValidationError berisi pesan validasi per key untuk Store.Update.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + ": " + e.Fields[k]
+	}
+	return "invalid settings: " + strings.Join(parts, "; ")
+}
+
+// Store adalah akses ber-cache ke pengaturan sistem.
+type Store struct {
+	repo repository.SettingsRepository
+	ttl  time.Duration
+
+	mu       sync.RWMutex
+	values   map[string]models.Setting // nil = belum dimuat / sudah diinvalidasi
+	loadedAt time.Time
+}
+
+// NewStore membuat Store dengan TTL cache dari SETTINGS_CACHE_TTL (default 30s).
+func NewStore(repo repository.SettingsRepository) *Store {
+	return &Store{repo: repo, ttl: configs.GetEnvDuration("SETTINGS_CACHE_TTL", 30*time.Second)}
+}
+
+// Invalidate mengosongkan cache; pembacaan berikutnya memuat ulang dari database.
+func (s *Store) Invalidate() {
+	s.mu.Lock()
+	s.values = nil
+	s.mu.Unlock()
+}
+
+// load mengembalikan nilai tersimpan, memuat ulang dari database jika cache kosong/kedaluwarsa.
+// Jika database gagal dan cache lama masih ada, cache lama dipakai.
+func (s *Store) load(ctx context.Context) (map[string]models.Setting, error) {
+	s.mu.RLock()
+	values, fresh := s.values, s.values != nil && time.Since(s.loadedAt) < s.ttl
+	s.mu.RUnlock()
+	if fresh {
+		return values, nil
+	}
+
+	rows, err := s.repo.GetAllSettings(ctx)
+	if err != nil {
+		if values != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("Failed to reload settings, using cached values")
+			return values, nil
+		}
+		return nil, err
+	}
+	loaded := make(map[string]models.Setting, len(rows))
+	for _, row := range rows {
+		loaded[row.Key] = row
+	}
+	s.mu.Lock()
+	s.values, s.loadedAt = loaded, time.Now()
+	s.mu.Unlock()
+	return loaded, nil
+}
+
+// All mengembalikan semua pengaturan terdaftar beserta nilai efektifnya.
+func (s *Store) All(ctx context.Context) ([]models.SettingDetail, error) {
+	values, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	details := make([]models.SettingDetail, 0, len(definitions))
+	for _, d := range definitions {
+		detail := models.SettingDetail{
+			Key: d.Key, Type: d.Type, Value: d.typed(d.Default), Default: d.typed(d.Default),
+			IsDefault: true, Description: d.Description, Options: d.Options,
+		}
+		if stored, ok := values[d.Key]; ok {
+			updatedAt := stored.UpdatedAt
+			detail.Value, detail.IsDefault = d.typed(stored.Value), false
+			detail.UpdatedBy, detail.UpdatedAt = stored.UpdatedBy, &updatedAt
+		}
+		details = append(details, detail)
+	}
+	return details, nil
+}
+
+// Update memvalidasi lalu menyimpan semua nilai dalam satu transaksi, kemudian
+// menginvalidasi cache. Tidak ada yang disimpan jika satu key pun tidak valid.
+func (s *Store) Update(ctx context.Context, input map[string]any, actorUserID int) error {
+	normalized := make(map[string]string, len(input))
+	invalid := map[string]string{}
+	for key, raw := range input {
+		d, ok := Lookup(key)
+		if !ok {
+			invalid[key] = ErrUnknownKey.Error()
+			continue
+		}
+		value, err := d.Normalize(raw)
+		if err != nil {
+			invalid[key] = err.Error()
+			continue
+		}
+		normalized[key] = value
+	}
+	if len(invalid) > 0 {
+		return &ValidationError{Fields: invalid}
+	}
+	if err := s.repo.UpsertSettings(ctx, normalized, actorUserID); err != nil {
+		return err
+	}
+	s.Invalidate()
+	return nil
+}
+
+// raw mengembalikan nilai teks efektif sebuah key (tersimpan atau default).
+// Jika pengaturan tidak bisa dimuat, default dipakai agar fitur tetap berjalan.
+func (s *Store) raw(ctx context.Context, key string) string {
+	d, ok := Lookup(key)
+	if !ok {
+		panic(fmt.Sprintf("settings: unregistered key %q", key))
+	}
+	values, err := s.load(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("key", key).Msg("Failed to load settings, using default value")
+		return d.Default
+	}
+	if stored, ok := values[key]; ok {
+		return stored.Value
+	}
+	return d.Default
+}
+
+// Int mengembalikan nilai key bertipe int.
+func (s *Store) Int(ctx context.Context, key string) int {
+	n, err := strconv.Atoi(s.raw(ctx, key))
+	if err != nil {
+		d, _ := Lookup(key)
+		n, _ = strconv.Atoi(d.Default)
+	}
+	return n
+}
+
+// String mengembalikan nilai key bertipe string/email/timezone/choice.
+func (s *Store) String(ctx context.Context, key string) string {
+	return s.raw(ctx, key)
+}
+
+// GracePeriod adalah toleransi keterlambatan setelah jam mulai shift.
+func (s *Store) GracePeriod(ctx context.Context) time.Duration {
+	return time.Duration(s.Int(ctx, KeyGraceMinutes)) * time.Minute
+}
+
+// CheckInWindow adalah rentang waktu sebelum jam mulai shift di mana check-in diterima.
+func (s *Store) CheckInWindow(ctx context.Context) time.Duration {
+	return time.Duration(s.Int(ctx, KeyCheckInWindowMinutes)) * time.Minute
+}
+
+// DefaultLocation mengembalikan zona waktu default; UTC jika nilai tersimpan tidak bisa dimuat.
+func (s *Store) DefaultLocation(ctx context.Context) *time.Location {
+	loc, err := time.LoadLocation(s.String(ctx, KeyDefaultTimezone))
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ReportSenderEmail mengembalikan alamat pengirim email laporan (kosong = nonaktif).
+func (s *Store) ReportSenderEmail(ctx context.Context) string {
+	return s.String(ctx, KeyReportSenderEmail)
+}
+
+// UsernameChangePolicy mengembalikan kebijakan ganti username lewat profil (models.UsernamePolicyX).
+func (s *Store) UsernameChangePolicy(ctx context.Context) string {
+	return s.String(ctx, KeyUsernameChangePolicy)
+}
+
+// RegistrationMode mengembalikan mode registrasi akun baru (models.RegistrationX).
+func (s *Store) RegistrationMode(ctx context.Context) string {
+	return s.String(ctx, KeyRegistrationMode)
+}
+
+// SelfAssignableRole melaporkan apakah role roleName boleh dipilih sendiri saat registrasi
+// publik: role default registrasi atau salah satu dari auth.registration_roles.
+func (s *Store) SelfAssignableRole(ctx context.Context, roleName string) bool {
+	if strings.EqualFold(roleName, s.String(ctx, KeyRegistrationRole)) {
+		return true
+	}
+	for _, name := range strings.Split(s.String(ctx, KeyRegistrationRoles), ",") {
+		if name != "" && strings.EqualFold(name, roleName) {
+			return true
+		}
+	}
+	return false
+}
+
+// AttendanceTags mengembalikan tag absensi yang boleh dipakai (huruf kecil, terurut).
+func (s *Store) AttendanceTags(ctx context.Context) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(s.String(ctx, KeyAttendanceTags), ",") {
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// RoutePingInterval adalah jeda minimum antar ping lokasi satu sesi field.
+func (s *Store) RoutePingInterval(ctx context.Context) time.Duration {
+	return time.Duration(s.Int(ctx, KeyRoutePingInterval)) * time.Second
+}
+
+// RouteRetention adalah masa simpan ping lokasi sesi field.
+func (s *Store) RouteRetention(ctx context.Context) time.Duration {
+	return time.Duration(s.Int(ctx, KeyRouteRetentionDays)) * 24 * time.Hour
+}
+
+// DebugCaptureRoutes mengembalikan pola route yang direkam untuk debugging (huruf kecil,
+// "[method] /path" dengan * opsional di akhir path).
+func (s *Store) DebugCaptureRoutes(ctx context.Context) []string {
+	routes := []string{}
+	for _, route := range strings.Split(s.String(ctx, KeyDebugCaptureRoutes), ",") {
+		if route != "" {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// DebugCaptureUsers mengembalikan ID user yang semua request-nya direkam untuk debugging.
+func (s *Store) DebugCaptureUsers(ctx context.Context) map[int]bool {
+	set := make(map[int]bool)
+	for _, id := range strings.Split(s.String(ctx, KeyDebugCaptureUsers), ",") {
+		if n, err := strconv.Atoi(id); err == nil {
+			set[n] = true
+		}
+	}
+	return set
+}
+
+// DebugCaptureTTL adalah masa simpan rekaman request/response debug.
+func (s *Store) DebugCaptureTTL(ctx context.Context) time.Duration {
+	return time.Duration(s.Int(ctx, KeyDebugCaptureTTL)) * time.Hour
+}
+
+// MaintenanceEnabled melaporkan apakah mode maintenance aktif.
+func (s *Store) MaintenanceEnabled(ctx context.Context) bool {
+	return s.String(ctx, KeyMaintenanceMode) == models.MaintenanceOn
+}
+
+// MaintenanceRetryAfter adalah nilai header Retry-After selama maintenance.
+func (s *Store) MaintenanceRetryAfter(ctx context.Context) time.Duration {
+	return time.Duration(s.Int(ctx, KeyMaintenanceRetry)) * time.Second
+}
+
+// MaintenanceBuffersCheckIns melaporkan apakah check-in ditampung (bukan ditolak) selama maintenance.
+func (s *Store) MaintenanceBuffersCheckIns(ctx context.Context) bool {
+	return s.String(ctx, KeyMaintenanceCheckIns) == models.MaintenanceCheckInsBuffer
+}
+
+// HourRules mengembalikan aturan kategori jam kerja (jam malam, akhir pekan, kalender libur)
+// pada zona waktu default.
+func (s *Store) HourRules(ctx context.Context) worktime.Rules {
+	return worktime.Rules{
+		Location:   s.DefaultLocation(ctx),
+		NightStart: s.clockMinutes(ctx, KeyNightStart),
+		NightEnd:   s.clockMinutes(ctx, KeyNightEnd),
+		Weekend:    s.weekdays(ctx, KeyWeekendDays),
+		Holidays:   s.holidays(ctx),
+	}
+}
+
+// WorkingCalendar mengembalikan kalender kerja organisasi (hari kerja, setengah hari, dan
+// kalender libur yang sama dengan HourRules).
+func (s *Store) WorkingCalendar(ctx context.Context) worktime.Calendar {
+	return worktime.Calendar{
+		WorkingDays: s.weekdays(ctx, KeyWorkingDays),
+		HalfDays:    s.weekdays(ctx, KeyHalfDays),
+		Holidays:    s.holidays(ctx),
+	}
+}
+
+// weekdays mengembalikan nilai key bertipe weekdays sebagai set.
+func (s *Store) weekdays(ctx context.Context, key string) map[time.Weekday]bool {
+	set := make(map[time.Weekday]bool)
+	for _, day := range strings.Split(s.String(ctx, key), ",") {
+		if i := slices.Index(weekdayNames, day); i >= 0 {
+			set[time.Weekday(i)] = true
+		}
+	}
+	return set
+}
+
+// holidays mengembalikan kalender libur sebagai set tanggal YYYY-MM-DD.
+func (s *Store) holidays(ctx context.Context) map[string]bool {
+	set := make(map[string]bool)
+	for _, date := range strings.Split(s.String(ctx, KeyHolidays), ",") {
+		if date != "" {
+			set[date] = true
+		}
+	}
+	return set
+}
+
+// clockMinutes mengembalikan nilai key bertipe clock dalam menit sejak 00:00.
+func (s *Store) clockMinutes(ctx context.Context, key string) int {
+	t, err := time.Parse("15:04", s.raw(ctx, key))
+	if err != nil {
+		d, _ := Lookup(key)
+		t, _ = time.Parse("15:04", d.Default)
+	}
+	return t.Hour()*60 + t.Minute()
+}
@@ -0,0 +1,196 @@
+// internal/sms/sms.go
+
+// Package sms mengirim SMS (kode OTP, pengingat shift) lewat provider eksternal (Twilio,
+// Vonage) untuk karyawan yang tidak memiliki email kantor.
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/resilience"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Sender mengirim satu SMS teks ke nomor E.164 (mis. +6281234567890).
+type Sender interface {
+	Send(ctx context.Context, to, body string) error
+	Provider() string
+}
+
+// NewSenderFromEnv membuat Sender berdasarkan environment variables. Mengembalikan nil jika
+// SMS tidak dikonfigurasi (verifikasi nomor HP, 2FA dan pengingat SMS dinonaktifkan).
+//
+// Variabel Environment yang didukung:
+//   - SMS_PROVIDER: kosong/'none' (default, nonaktif), 'log' (hanya mencatat ke log, untuk development),
+//     'twilio', atau 'vonage'.
+//   - SMS_FROM: Nomor/sender ID pengirim (wajib untuk twilio & vonage).
+//   - SMS_TWILIO_ACCOUNT_SID / SMS_TWILIO_AUTH_TOKEN: Kredensial Twilio.
+//   - SMS_VONAGE_API_KEY / SMS_VONAGE_API_SECRET: Kredensial Vonage (Nexmo).
+//   - SMS_TIMEOUT: Batas waktu satu percobaan pengiriman. Default: 10s.
+//
+// Pengiriman memakai retry & circuit breaker bersama (lihat resilience.PolicyFromEnv).
+func NewSenderFromEnv() (Sender, error) {
+	provider := strings.ToLower(configs.GetEnv("SMS_PROVIDER", ""))
+	from := configs.GetEnv("SMS_FROM", "")
+	policy := resilience.PolicyFromEnv(configs.GetEnvDuration("SMS_TIMEOUT", 10*time.Second))
+	switch provider {
+	case "", "none":
+		zlog.Info().Msg("SMS disabled")
+		return nil, nil
+	case "log":
+		zlog.Warn().Msg("SMS messages are only written to the log (SMS_PROVIDER=log)")
+		return logSender{}, nil
+	case "twilio":
+		s := &twilioSender{
+			accountSID: configs.GetEnv("SMS_TWILIO_ACCOUNT_SID", ""),
+			authToken:  configs.GetEnv("SMS_TWILIO_AUTH_TOKEN", ""),
+			from:       from,
+			baseURL:    "https://api.twilio.com",
+			client:     &http.Client{},
+			breaker:    resilience.Get("sms.twilio"),
+			policy:     policy,
+		}
+		if s.accountSID == "" || s.authToken == "" || s.from == "" {
+			return nil, fmt.Errorf("SMS_TWILIO_ACCOUNT_SID, SMS_TWILIO_AUTH_TOKEN and SMS_FROM must be set when SMS_PROVIDER=twilio")
+		}
+		zlog.Info().Str("provider", provider).Msg("SMS enabled")
+		return s, nil
+	case "vonage":
+		s := &vonageSender{
+			apiKey:    configs.GetEnv("SMS_VONAGE_API_KEY", ""),
+			apiSecret: configs.GetEnv("SMS_VONAGE_API_SECRET", ""),
+			from:      from,
+			baseURL:   "https://rest.nexmo.com",
+			client:    &http.Client{},
+			breaker:   resilience.Get("sms.vonage"),
+			policy:    policy,
+		}
+		if s.apiKey == "" || s.apiSecret == "" || s.from == "" {
+			return nil, fmt.Errorf("SMS_VONAGE_API_KEY, SMS_VONAGE_API_SECRET and SMS_FROM must be set when SMS_PROVIDER=vonage")
+		}
+		zlog.Info().Str("provider", provider).Msg("SMS enabled")
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unsupported SMS_PROVIDER '%s'", provider)
+	}
+}
+
+// logSender mencatat SMS ke log aplikasi (development). Nomor tujuan tidak dicatat (data pribadi).
+type logSender struct{}
+
+func (logSender) Provider() string {
+	return "log"
+}
+
+func (logSender) Send(ctx context.Context, to, body string) error {
+	zlog.Ctx(ctx).Info().Bool("has_recipient", to != "").Msg("SMS: " + body)
+	return nil
+}
+
+// twilioSender mengirim SMS lewat Twilio Programmable Messaging API.
+type twilioSender struct {
+	accountSID string
+	authToken  string
+	from       string
+	baseURL    string
+	client     *http.Client
+	breaker    *resilience.Breaker
+	policy     resilience.Policy
+}
+
+func (s *twilioSender) Provider() string {
+	return "twilio"
+}
+
+func (s *twilioSender) Send(ctx context.Context, to, body string) error {
+	form := url.Values{"To": {to}, "From": {s.from}, "Body": {body}}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", s.baseURL, url.PathEscape(s.accountSID))
+	return resilience.Do(ctx, s.breaker, s.policy, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+		if err != nil {
+			return resilience.Permanent(fmt.Errorf("error building twilio request: %w", err))
+		}
+		req.SetBasicAuth(s.accountSID, s.authToken)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("error calling twilio: %w", err)
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return resilience.HTTPStatusError(fmt.Errorf("twilio returned status %d", resp.StatusCode), resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// vonageSender mengirim SMS lewat Vonage (Nexmo) SMS API.
+type vonageSender struct {
+	apiKey    string
+	apiSecret string
+	from      string
+	baseURL   string
+	client    *http.Client
+	breaker   *resilience.Breaker
+	policy    resilience.Policy
+}
+
+func (s *vonageSender) Provider() string {
+	return "vonage"
+}
+
+func (s *vonageSender) Send(ctx context.Context, to, body string) error {
+	// Vonage menerima nomor internasional tanpa awalan '+'.
+	form := url.Values{
+		"api_key": {s.apiKey}, "api_secret": {s.apiSecret},
+		"from": {s.from}, "to": {strings.TrimPrefix(to, "+")}, "text": {body},
+	}
+	return resilience.Do(ctx, s.breaker, s.policy, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/sms/json", strings.NewReader(form.Encode()))
+		if err != nil {
+			return resilience.Permanent(fmt.Errorf("error building vonage request: %w", err))
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("error calling vonage: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			return resilience.HTTPStatusError(fmt.Errorf("vonage returned status %d", resp.StatusCode), resp.StatusCode)
+		}
+		// Vonage menjawab 200 juga untuk pesan yang ditolak; status per pesan "0" = terkirim.
+		var result struct {
+			Messages []struct {
+				Status    string `json:"status"`
+				ErrorText string `json:"error-text"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&result); err != nil {
+			return fmt.Errorf("error decoding vonage response: %w", err)
+		}
+		for _, m := range result.Messages {
+			if m.Status != "0" {
+				// Status 1 = throttled (sementara); selain itu kesalahan permanen (nomor/kredensial).
+				err := fmt.Errorf("vonage rejected message: status %s: %s", m.Status, m.ErrorText)
+				if m.Status == "1" {
+					return err
+				}
+				return resilience.Permanent(err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,395 @@
+// internal/storage/s3.go
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+)
+
+// Batas masa berlaku URL presigned menurut SigV4.
+const maxPresignTTL = 7 * 24 * time.Hour
+
+// unsignedPayload dipakai URL presigned: isi body tidak ikut ditandatangani (integritas dijaga
+// header x-amz-checksum-sha256 yang ditandatangani, lihat PresignPut).
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Config adalah konfigurasi backend S3/MinIO.
+type S3Config struct {
+	Endpoint        string // mis. https://s3.ap-southeast-1.amazonaws.com atau http://localhost:9000
+	Region          string
+	Bucket          string
+	Prefix          string // Prefix key di bucket (opsional), mis. "attendance/"
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Kredensial sementara (opsional)
+	ForcePathStyle  bool   // endpoint/bucket/key (MinIO) alih-alih bucket.endpoint/key
+	SSE             string // "", "AES256" atau "aws:kms"
+	SSEKMSKeyID     string // Untuk SSE aws:kms (opsional; kosong = kunci default bucket)
+	Timeout         time.Duration
+}
+
+// s3Storage menyimpan objek di bucket S3-compatible lewat REST API dengan tanda tangan SigV4.
+type s3Storage struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// s3ConfigFromEnv membaca konfigurasi S3 dari environment variables (lihat NewStorageFromEnv).
+func s3ConfigFromEnv() S3Config {
+	region := configs.GetEnv("S3_REGION", "us-east-1")
+	return S3Config{
+		Endpoint:        configs.GetEnv("S3_ENDPOINT", "https://s3."+region+".amazonaws.com"),
+		Region:          region,
+		Bucket:          configs.GetEnv("S3_BUCKET", ""),
+		Prefix:          configs.GetEnv("S3_PREFIX", ""),
+		AccessKeyID:     configs.GetEnv("S3_ACCESS_KEY_ID", ""),
+		SecretAccessKey: configs.GetEnv("S3_SECRET_ACCESS_KEY", ""),
+		SessionToken:    configs.GetEnv("S3_SESSION_TOKEN", ""),
+		ForcePathStyle:  configs.GetEnvBool("S3_FORCE_PATH_STYLE", false),
+		SSE:             configs.GetEnv("S3_SSE", ""),
+		SSEKMSKeyID:     configs.GetEnv("S3_SSE_KMS_KEY_ID", ""),
+		Timeout:         configs.GetEnvDuration("S3_TIMEOUT", 30*time.Second),
+	}
+}
+
+// NewS3Storage membuat Storage berbasis bucket S3-compatible (AWS S3, MinIO, dll.).
+func NewS3Storage(cfg S3Config) (Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3_BUCKET must be set when STORAGE_BACKEND=s3")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY must be set when STORAGE_BACKEND=s3")
+	}
+	switch cfg.SSE {
+	case "", "AES256", "aws:kms":
+	default:
+		return nil, fmt.Errorf("unsupported S3_SSE '%s' (AES256 or aws:kms)", cfg.SSE)
+	}
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid S3_ENDPOINT %q", cfg.Endpoint)
+	}
+	if cfg.Prefix != "" && !strings.HasSuffix(cfg.Prefix, "/") {
+		cfg.Prefix += "/"
+	}
+	return &s3Storage{cfg: cfg, endpoint: endpoint, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+func (s *s3Storage) Backend() string {
+	return "s3"
+}
+
+// Put mengunggah objek. Isi dibaca ke memori agar bisa ditandatangani (SHA-256 payload);
+// file besar sebaiknya diunggah klien langsung lewat PresignPut.
+func (s *s3Storage) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("error reading %q: %w", key, err)
+	}
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	s.setSSE(header)
+	resp, err := s.do(ctx, http.MethodPut, key, nil, header, body)
+	if err != nil {
+		return fmt.Errorf("error storing %q: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *s3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete menghapus objek. S3 tidak membedakan objek yang tidak ada, jadi ErrNotFound tidak
+// pernah dikembalikan backend ini.
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting %q: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Stat membaca ukuran dan tipe konten objek (HEAD).
+func (s *s3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return &ObjectInfo{Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+// PresignGet membuat URL unduhan langsung dari bucket. FileName/ContentType (opsional)
+// menimpa header Content-Disposition/Content-Type response.
+func (s *s3Storage) PresignGet(_ context.Context, key string, ttl time.Duration, opts GetOptions) (string, error) {
+	query := url.Values{}
+	if opts.FileName != "" {
+		query.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": opts.FileName}))
+	}
+	if opts.ContentType != "" {
+		query.Set("response-content-type", opts.ContentType)
+	}
+	u, err := s.presign(http.MethodGet, key, query, nil, ttl, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return u, nil
+}
+
+// PresignPut membuat URL unggah langsung ke bucket. Tipe konten, ukuran, checksum SHA-256, dan
+// SSE ikut ditandatangani sehingga klien wajib mengirim header yang sama persis
+// (PresignedRequest.Headers) dan S3 menolak isi yang berbeda dari checksum.
+func (s *s3Storage) PresignPut(_ context.Context, key string, ttl time.Duration, opts PutOptions) (*PresignedRequest, error) {
+	digest, err := hex.DecodeString(opts.SHA256)
+	if err != nil || len(digest) != sha256.Size {
+		return nil, fmt.Errorf("invalid sha256 checksum")
+	}
+	header := http.Header{}
+	header.Set("Content-Type", opts.ContentType)
+	header.Set("Content-Length", strconv.FormatInt(opts.SizeBytes, 10))
+	header.Set("X-Amz-Checksum-Sha256", base64.StdEncoding.EncodeToString(digest))
+	s.setSSE(header)
+
+	now := time.Now()
+	u, err := s.presign(http.MethodPut, key, url.Values{}, header, ttl, now)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string, len(header))
+	for name := range header {
+		headers[name] = header.Get(name)
+	}
+	return &PresignedRequest{Method: http.MethodPut, URL: u, Headers: headers, ExpiresAt: now.Add(ttl)}, nil
+}
+
+func (s *s3Storage) setSSE(header http.Header) {
+	if s.cfg.SSE == "" {
+		return
+	}
+	header.Set("X-Amz-Server-Side-Encryption", s.cfg.SSE)
+	if s.cfg.SSE == "aws:kms" && s.cfg.SSEKMSKeyID != "" {
+		header.Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", s.cfg.SSEKMSKeyID)
+	}
+}
+
+// objectURL mengembalikan URL objek (path-style atau virtual-hosted) tanpa query.
+func (s *s3Storage) objectURL(key string) (*url.URL, error) {
+	if key == "" || strings.HasPrefix(key, "/") || slices.Contains(strings.Split(key, "/"), "..") {
+		return nil, fmt.Errorf("invalid storage key %q", key)
+	}
+	u := *s.endpoint
+	objectPath := "/" + s.cfg.Prefix + key
+	if s.cfg.ForcePathStyle {
+		objectPath = "/" + s.cfg.Bucket + objectPath
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+	u.Path = u.Path + objectPath
+	u.RawPath = s3EscapePath(u.Path)
+	return &u, nil
+}
+
+// do mengirim request bertanda tangan dan memetakan 404 ke ErrNotFound serta status non-2xx
+// lainnya ke error. Pemanggil wajib menutup resp.Body.
+func (s *s3Storage) do(ctx context.Context, method, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = s3CanonicalQuery(query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.ContentLength = int64(len(body))
+	if len(body) == 0 {
+		req.Body = http.NoBody
+	}
+	s.sign(req, sha256Hex(body), time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s: status %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return resp, nil
+}
+
+// sign menambahkan header Authorization SigV4 ke req.
+func (s *s3Storage) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+	header := req.Header.Clone()
+	header.Set("Host", req.URL.Host)
+	if req.ContentLength > 0 {
+		header.Set("Content-Length", strconv.FormatInt(req.ContentLength, 10))
+	}
+	signedHeaders, canonicalHeaders := s3CanonicalHeaders(header)
+	scope := s.scope(now)
+	signature := s.signature(now, scope, req.Method, req.URL.EscapedPath(), req.URL.RawQuery, canonicalHeaders, signedHeaders, payloadHash)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// presign membuat URL bertanda tangan (query string SigV4) yang berlaku selama ttl.
+func (s *s3Storage) presign(method, key string, query url.Values, header http.Header, ttl time.Duration, now time.Time) (string, error) {
+	if ttl <= 0 || ttl > maxPresignTTL {
+		return "", fmt.Errorf("presign ttl must be between 1s and %s", maxPresignTTL)
+	}
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	signing := http.Header{}
+	for name, values := range header {
+		signing[name] = values
+	}
+	signing.Set("Host", u.Host)
+	signedHeaders, canonicalHeaders := s3CanonicalHeaders(signing)
+
+	scope := s.scope(now)
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.cfg.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", now.UTC().Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	query.Set("X-Amz-SignedHeaders", signedHeaders)
+	if s.cfg.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+	rawQuery := s3CanonicalQuery(query)
+	signature := s.signature(now, scope, method, u.EscapedPath(), rawQuery, canonicalHeaders, signedHeaders, unsignedPayload)
+	u.RawQuery = rawQuery + "&X-Amz-Signature=" + signature
+	return u.String(), nil
+}
+
+func (s *s3Storage) scope(now time.Time) string {
+	return now.UTC().Format("20060102") + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+// signature menghitung tanda tangan SigV4 untuk canonical request.
+func (s *s3Storage) signature(now time.Time, scope, method, path, rawQuery, canonicalHeaders, signedHeaders, payloadHash string) string {
+	canonicalRequest := strings.Join([]string{method, path, rawQuery, canonicalHeaders, signedHeaders, payloadHash}, "\n")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256", now.UTC().Format("20060102T150405Z"), scope, sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+	key := []byte("AWS4" + s.cfg.SecretAccessKey)
+	for _, part := range []string{now.UTC().Format("20060102"), s.cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// s3CanonicalHeaders mengembalikan daftar header yang ditandatangani dan blok canonical
+// header-nya (nama huruf kecil, urut abjad).
+func s3CanonicalHeaders(header http.Header) (signed, canonical string) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		lower := strings.ToLower(name)
+		if lower == "authorization" || lower == "user-agent" || lower == "accept-encoding" {
+			continue
+		}
+		names = append(names, lower)
+	}
+	slices.Sort(names)
+	var b strings.Builder
+	for _, name := range names {
+		var values []string
+		for _, v := range header.Values(name) {
+			values = append(values, strings.Join(strings.Fields(v), " "))
+		}
+		b.WriteString(name + ":" + strings.Join(values, ",") + "\n")
+	}
+	return strings.Join(names, ";"), b.String()
+}
+
+// s3CanonicalQuery meng-encode query urut kunci dengan escaping RFC 3986 (spasi = %20).
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var parts []string
+	for _, k := range keys {
+		values := slices.Clone(query[k])
+		slices.Sort(values)
+		for _, v := range values {
+			parts = append(parts, s3Escape(k, true)+"="+s3Escape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3EscapePath meng-encode path objek tanpa meng-encode "/".
+func s3EscapePath(path string) string {
+	return s3Escape(path, false)
+}
+
+// s3Escape meng-encode semua byte selain karakter unreserved RFC 3986 (dan "/" jika
+// encodeSlash false).
+func s3Escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+var _ Presigner = (*s3Storage)(nil)
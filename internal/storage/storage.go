@@ -0,0 +1,186 @@
+// internal/storage/storage.go
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Storage adalah kontrak penyimpanan objek (file upload) yang dialamatkan dengan key,
+// mis. "documents/2025/01/<random>". Key dibuat oleh aplikasi, bukan dari input user.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Backend() string
+}
+
+// ErrNotFound dikembalikan Open/Delete jika objek tidak ada.
+var ErrNotFound = errors.New("storage object not found")
+
+// Presigner diimplementasikan backend yang mendukung transfer langsung antara klien dan
+// storage lewat URL bertanda tangan (mis. S3), sehingga file besar tidak melewati proses API.
+// Backend local tidak mendukungnya; pemanggil kembali ke Put/Open lewat API.
+type Presigner interface {
+	PresignGet(ctx context.Context, key string, ttl time.Duration, opts GetOptions) (string, error)
+	PresignPut(ctx context.Context, key string, ttl time.Duration, opts PutOptions) (*PresignedRequest, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+}
+
+// GetOptions menimpa header response unduhan presigned (opsional).
+type GetOptions struct {
+	FileName    string // Content-Disposition: attachment; filename=...
+	ContentType string
+}
+
+// PutOptions adalah isi yang dijanjikan klien untuk unggahan presigned; semuanya ikut
+// ditandatangani sehingga storage menolak unggahan yang berbeda.
+type PutOptions struct {
+	ContentType string
+	SizeBytes   int64
+	SHA256      string // Hex
+}
+
+// PresignedRequest adalah request yang harus dikirim klien langsung ke storage.
+type PresignedRequest struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"` // Wajib dikirim apa adanya
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// ObjectInfo adalah metadata objek tersimpan.
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// NewStorageFromEnv membuat Storage berdasarkan environment variables.
+//
+// Variabel Environment yang didukung:
+//   - STORAGE_BACKEND: 'local' (default, untuk development) atau 's3' (S3/MinIO).
+//   - STORAGE_LOCAL_DIR: Direktori root untuk backend local. Default: ./data/uploads.
+//   - S3_BUCKET: Nama bucket (wajib untuk s3).
+//   - S3_REGION: Region bucket. Default: us-east-1.
+//   - S3_ENDPOINT: Endpoint S3-compatible. Default: https://s3.<S3_REGION>.amazonaws.com.
+//   - S3_PREFIX: Prefix key di bucket (opsional).
+//   - S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY: Kredensial (wajib); S3_SESSION_TOKEN opsional.
+//   - S3_FORCE_PATH_STYLE: Alamat path-style (endpoint/bucket/key), umumnya untuk MinIO. Default: false.
+//   - S3_SSE: Enkripsi sisi server, 'AES256' atau 'aws:kms' (opsional); S3_SSE_KMS_KEY_ID untuk aws:kms.
+//   - S3_TIMEOUT: Timeout satu request ke S3. Default: 30s.
+func NewStorageFromEnv() (Storage, error) {
+	backend := strings.ToLower(configs.GetEnv("STORAGE_BACKEND", "local"))
+	switch backend {
+	case "local":
+		dir := configs.GetEnv("STORAGE_LOCAL_DIR", "./data/uploads")
+		s, err := NewLocalStorage(dir)
+		if err != nil {
+			return nil, err
+		}
+		zlog.Info().Str("backend", backend).Str("dir", dir).Msg("File storage initialized")
+		return s, nil
+	case "s3":
+		cfg := s3ConfigFromEnv()
+		s, err := NewS3Storage(cfg)
+		if err != nil {
+			return nil, err
+		}
+		zlog.Info().Str("backend", backend).Str("endpoint", cfg.Endpoint).Str("bucket", cfg.Bucket).Str("sse", cfg.SSE).Msg("File storage initialized")
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unsupported STORAGE_BACKEND '%s'", backend)
+	}
+}
+
+// localStorage menyimpan objek sebagai file di bawah satu direktori root.
+type localStorage struct {
+	root string
+}
+
+// NewLocalStorage membuat Storage berbasis filesystem; direktori root dibuat jika belum ada.
+func NewLocalStorage(root string) (Storage, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage dir %q: %w", root, err)
+	}
+	if err := os.MkdirAll(abs, 0o750); err != nil {
+		return nil, fmt.Errorf("error creating storage dir %q: %w", abs, err)
+	}
+	return &localStorage{root: abs}, nil
+}
+
+func (s *localStorage) Backend() string {
+	return "local"
+}
+
+// path memetakan key ke path file dan menolak key yang keluar dari root (path traversal).
+func (s *localStorage) path(key string) (string, error) {
+	p := filepath.Join(s.root, filepath.FromSlash(key))
+	if key == "" || !strings.HasPrefix(p, s.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return p, nil
+}
+
+// Put menulis ke file sementara lalu rename, sehingga pembaca tidak melihat file setengah jadi.
+func (s *localStorage) Put(ctx context.Context, key string, r io.Reader, _ string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return fmt.Errorf("error creating directory for %q: %w", key, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error creating temp file for %q: %w", key, err)
+	}
+	defer os.Remove(tmp.Name()) // No-op setelah rename berhasil
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing %q: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error closing %q: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("error storing %q: %w", key, err)
+	}
+	return nil
+}
+
+func (s *localStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *localStorage) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("error deleting %q: %w", key, err)
+	}
+	return nil
+}
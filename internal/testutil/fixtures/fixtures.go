@@ -0,0 +1,91 @@
+// internal/testutil/fixtures/fixtures.go
+
+// Package fixtures berisi builder input model yang valid untuk test repository/handler.
+// Setiap builder mengisi nilai default yang unik (username, email, nama shift) sehingga
+// aman dipanggil berkali-kali dalam satu database; opts mengubah field sesuai kebutuhan test.
+package fixtures
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// Role ID bawaan dari migrasi 000001 (INSERT INTO roles ... 'Admin', 'Employee').
+const (
+	RoleAdminID    = 1
+	RoleEmployeeID = 2
+)
+
+// DefaultPassword adalah password plaintext yang dipakai UserInput.
+const DefaultPassword = "secret123"
+
+// DateLayout adalah format tanggal jadwal (YYYY-MM-DD).
+const DateLayout = "2006-01-02"
+
+var seq atomic.Int64
+
+// next mengembalikan angka unik per proses untuk membedakan fixture.
+func next() int64 {
+	return seq.Add(1)
+}
+
+// UserInput membangun RegisterUserInput valid (role Employee).
+func UserInput(opts ...func(*models.RegisterUserInput)) *models.RegisterUserInput {
+	n := next()
+	phone := fmt.Sprintf("+62812%07d", n)
+	input := &models.RegisterUserInput{
+		Username:  fmt.Sprintf("user%d", n),
+		Password:  DefaultPassword,
+		Email:     fmt.Sprintf("user%d@example.test", n),
+		FirstName: "Test",
+		LastName:  fmt.Sprintf("User %d", n),
+		Phone:     &phone,
+		RoleID:    RoleEmployeeID,
+	}
+	for _, opt := range opts {
+		opt(input)
+	}
+	return input
+}
+
+// AsAdmin mengubah UserInput menjadi user ber-role Admin.
+func AsAdmin(input *models.RegisterUserInput) {
+	input.RoleID = RoleAdminID
+}
+
+// Shift membangun definisi shift valid (09:00-17:00).
+func Shift(opts ...func(*models.Shift)) *models.Shift {
+	shift := &models.Shift{
+		Name:      fmt.Sprintf("Shift %d", next()),
+		StartTime: "09:00:00",
+		EndTime:   "17:00:00",
+	}
+	for _, opt := range opts {
+		opt(shift)
+	}
+	return shift
+}
+
+// Role membangun role baru dengan nama unik.
+func Role(opts ...func(*models.Role)) *models.Role {
+	role := &models.Role{Name: fmt.Sprintf("Role %d", next())}
+	for _, opt := range opts {
+		opt(role)
+	}
+	return role
+}
+
+// Schedule membangun jadwal untuk user & shift pada tanggal tertentu.
+func Schedule(userID, shiftID int, date time.Time) *models.UserSchedule {
+	return &models.UserSchedule{UserID: userID, ShiftID: shiftID, Date: date.Format(DateLayout)}
+}
+
+// Day mengembalikan tanggal (UTC, jam 00:00) offset hari dari base.
+// Berguna untuk menyusun data rentang tanggal/pagination yang deterministik.
+func Day(base time.Time, offset int) time.Time {
+	y, m, d := base.UTC().Date()
+	return time.Date(y, m, d+offset, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,88 @@
+// internal/testutil/pgtest/container.go
+package pgtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// defaultContainerImage adalah image PostgreSQL container test; samakan dengan versi production.
+const defaultContainerImage = "postgres:16-alpine"
+
+// Container PostgreSQL dijalankan sekali per proses test (per paket) dan dipakai bersama oleh
+// semua test di paket itu; isolasi tetap per schema. Container dihentikan oleh reaper
+// testcontainers (Ryuk) setelah proses test selesai.
+var (
+	containerOnce sync.Once
+	containerDSN  string
+	containerErr  error
+)
+
+// databaseURL mengembalikan DSN database test: TEST_DATABASE_URL jika di-set, selain itu
+// container PostgreSQL dari testcontainers (image TEST_POSTGRES_IMAGE). Test di-skip jika
+// TEST_DATABASE_URL kosong dan Docker tidak tersedia atau TEST_CONTAINERS=false.
+func databaseURL(t testing.TB) string {
+	if dsn := os.Getenv("TEST_DATABASE_URL"); dsn != "" {
+		return dsn
+	}
+	if os.Getenv("TEST_CONTAINERS") == "false" {
+		t.Skip("TEST_DATABASE_URL not set and TEST_CONTAINERS=false; skipping repository integration test")
+	}
+	containerOnce.Do(func() {
+		containerDSN, containerErr = startContainer()
+	})
+	if containerErr != nil {
+		t.Skipf("TEST_DATABASE_URL not set and no PostgreSQL container available (%v); skipping repository integration test", containerErr)
+	}
+	return containerDSN
+}
+
+// startContainer menjalankan container PostgreSQL dan mengembalikan DSN-nya.
+func startContainer() (dsn string, err error) {
+	// testcontainers bisa panic jika Docker tidak ditemukan sama sekali.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("docker unavailable: %v", r)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		return "", fmt.Errorf("docker unavailable: %w", err)
+	}
+	if err := provider.Health(ctx); err != nil {
+		return "", fmt.Errorf("docker unavailable: %w", err)
+	}
+
+	image := os.Getenv("TEST_POSTGRES_IMAGE")
+	if image == "" {
+		image = defaultContainerImage
+	}
+	container, err := postgres.Run(ctx, image,
+		postgres.WithDatabase("attendance_test"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("postgres"),
+		testcontainers.WithWaitStrategy(
+			// Entrypoint image me-restart server sekali setelah inisialisasi, jadi tunggu log kedua.
+			wait.ForLog("database system is ready to accept connections").WithOccurrence(2).WithStartupTimeout(time.Minute),
+		),
+	)
+	if err != nil {
+		return "", fmt.Errorf("starting %s: %w", image, err)
+	}
+	dsn, err = container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		return "", fmt.Errorf("container connection string: %w", err)
+	}
+	return dsn, nil
+}
@@ -0,0 +1,137 @@
+// internal/testutil/repotest/announcements.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var announcementTests = []contractTest{
+	{"AnnouncementCRUD", testAnnouncementCRUD},
+	{"AnnouncementsPagination", testAnnouncementsPagination},
+	{"ActiveAnnouncementsForUser", testActiveAnnouncementsForUser},
+}
+
+func testAnnouncementCRUD(t *testing.T, db Repos) {
+	ctx := context.Background()
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	publishAt := time.Now().UTC().Truncate(time.Second)
+	expiresAt := publishAt.Add(24 * time.Hour)
+	id, err := db.Announcements.CreateAnnouncement(ctx, &models.Announcement{
+		Title: "Holiday", Body: "Office closed", PublishAt: publishAt, ExpiresAt: &expiresAt,
+		AudienceRoleIDs: []int{fixtures.RoleEmployeeID}, CreatedBy: &admin.ID,
+	})
+	require.NoError(t, err)
+
+	got, err := db.Announcements.GetAnnouncementByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Holiday", got.Title)
+	assert.True(t, publishAt.Equal(got.PublishAt))
+	require.NotNil(t, got.ExpiresAt)
+	assert.True(t, expiresAt.Equal(*got.ExpiresAt))
+	assert.Equal(t, []int{fixtures.RoleEmployeeID}, got.AudienceRoleIDs)
+	assert.Equal(t, &admin.ID, got.CreatedBy)
+
+	got.Title, got.ExpiresAt, got.AudienceRoleIDs = "Holiday (updated)", nil, nil
+	require.NoError(t, db.Announcements.UpdateAnnouncement(ctx, got))
+	updated, err := db.Announcements.GetAnnouncementByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Holiday (updated)", updated.Title)
+	assert.Nil(t, updated.ExpiresAt)
+	assert.Empty(t, updated.AudienceRoleIDs, "empty audience means everyone")
+
+	_, err = db.Announcements.CreateAnnouncement(ctx, &models.Announcement{Title: "Bad", Body: "x", PublishAt: publishAt, ExpiresAt: &publishAt})
+	assert.Error(t, err, "expiry must be after publish time")
+
+	require.NoError(t, db.Announcements.DeleteAnnouncement(ctx, id))
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "get deleted", call: func() error { _, err := db.Announcements.GetAnnouncementByID(ctx, id); return err }},
+		{name: "update unknown", call: func() error {
+			return db.Announcements.UpdateAnnouncement(ctx, &models.Announcement{ID: missingID, Title: "x", Body: "x", PublishAt: publishAt})
+		}},
+		{name: "delete unknown", call: func() error { return db.Announcements.DeleteAnnouncement(ctx, missingID) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), pgx.ErrNoRows)
+		})
+	}
+}
+
+func testAnnouncementsPagination(t *testing.T, db Repos) {
+	ctx := context.Background()
+	list := func(page, limit int) ([]models.Announcement, int, error) {
+		return db.Announcements.GetAllAnnouncements(ctx, page, limit)
+	}
+	checkEmptyPage(t, list)
+
+	base := time.Now().UTC().Truncate(time.Second)
+	var ids []int
+	for i := range 3 {
+		id, err := db.Announcements.CreateAnnouncement(ctx, &models.Announcement{Title: "Notice", Body: "Body", PublishAt: base.Add(time.Duration(i) * time.Hour)})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	checkPagination(t, 3, list, func(a models.Announcement) int { return a.ID })
+
+	announcements, _, err := list(1, 10)
+	require.NoError(t, err)
+	require.Len(t, announcements, 3)
+	assert.Equal(t, ids[2], announcements[0].ID, "latest publish time first")
+}
+
+func testActiveAnnouncementsForUser(t *testing.T, db Repos) {
+	ctx := context.Background()
+	employee := db.CreateUser(t)
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	now := time.Now().UTC().Truncate(time.Second)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	create := func(publishAt time.Time, expiresAt *time.Time, roles ...int) int {
+		id, err := db.Announcements.CreateAnnouncement(ctx, &models.Announcement{Title: "Notice", Body: "Body", PublishAt: publishAt, ExpiresAt: expiresAt, AudienceRoleIDs: roles})
+		require.NoError(t, err)
+		return id
+	}
+	everyone := create(past, nil)
+	employees := create(past.Add(time.Minute), &future, fixtures.RoleEmployeeID)
+	admins := create(past.Add(2*time.Minute), nil, fixtures.RoleAdminID)
+	create(future, nil)                                            // Belum tayang
+	create(past.Add(-time.Hour), &past)                            // Sudah kedaluwarsa
+	mixed := create(past, nil, fixtures.RoleEmployeeID, missingID) // Role yang tidak ada diabaikan
+
+	tests := []struct {
+		name   string
+		userID int
+		want   []int
+	}{
+		{name: "employee", userID: employee.ID, want: []int{employees, mixed, everyone}},
+		{name: "admin", userID: admin.ID, want: []int{admins, everyone}},
+		{name: "unknown user", userID: missingID, want: []int{everyone}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			announcements, total, err := db.Announcements.GetActiveAnnouncementsForUser(ctx, tt.userID, now, 1, 10)
+			require.NoError(t, err)
+			ids := make([]int, 0, len(announcements))
+			for _, a := range announcements {
+				ids = append(ids, a.ID)
+			}
+			assert.Equal(t, len(tt.want), total)
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+
+	checkPagination(t, 3, func(page, limit int) ([]models.Announcement, int, error) {
+		return db.Announcements.GetActiveAnnouncementsForUser(ctx, employee.ID, now, page, limit)
+	}, func(a models.Announcement) int { return a.ID })
+}
@@ -0,0 +1,68 @@
+// internal/testutil/repotest/app_instances.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var appInstanceTests = []contractTest{
+	{"AppInstanceHeartbeats", testAppInstanceHeartbeats},
+}
+
+func testAppInstanceHeartbeats(t *testing.T, db Repos) {
+	ctx := context.Background()
+	since := time.Now().Add(-time.Minute)
+	instances, err := db.AppInstances.GetAppInstances(ctx, since)
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+
+	for _, i := range []models.AppInstance{
+		{InstanceID: "api-b", AppVersion: "1.2.0", Hostname: "host-b"},
+		{InstanceID: "api-a", AppVersion: "1.1.0", Hostname: "host-a"},
+		{InstanceID: "api-c", AppVersion: "1.2.0", Hostname: "host-c"},
+	} {
+		require.NoError(t, db.AppInstances.UpsertAppInstance(ctx, &i))
+		assert.False(t, i.StartedAt.IsZero())
+		assert.False(t, i.LastSeenAt.IsZero())
+	}
+
+	// Heartbeat berikutnya memperbarui versi & last_seen_at tanpa mengubah started_at.
+	first := &models.AppInstance{InstanceID: "api-b", AppVersion: "1.2.0", Hostname: "host-b"}
+	require.NoError(t, db.AppInstances.UpsertAppInstance(ctx, first))
+	again := &models.AppInstance{InstanceID: "api-b", AppVersion: "1.2.1", Hostname: "host-b2"}
+	require.NoError(t, db.AppInstances.UpsertAppInstance(ctx, again))
+	assert.True(t, again.StartedAt.Equal(first.StartedAt))
+	assert.False(t, again.LastSeenAt.Before(first.LastSeenAt))
+
+	instances, err = db.AppInstances.GetAppInstances(ctx, since)
+	require.NoError(t, err)
+	ids := make([]string, len(instances))
+	for i, instance := range instances {
+		ids[i] = instance.InstanceID
+	}
+	assert.Equal(t, []string{"api-a", "api-c", "api-b"}, ids, "by version, then start")
+	assert.Equal(t, "host-b2", instances[2].Hostname)
+
+	instances, err = db.AppInstances.GetAppInstances(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, instances, "no heartbeat since the bound")
+
+	require.NoError(t, db.AppInstances.DeleteAppInstance(ctx, "api-a"))
+	require.NoError(t, db.AppInstances.DeleteAppInstance(ctx, "api-unknown"), "deleting an unknown instance is a no-op")
+
+	deleted, err := db.AppInstances.DeleteStaleAppInstances(ctx, since)
+	require.NoError(t, err)
+	assert.Zero(t, deleted, "recent heartbeats are kept")
+	deleted, err = db.AppInstances.DeleteStaleAppInstances(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	instances, err = db.AppInstances.GetAppInstances(ctx, since)
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+}
@@ -0,0 +1,424 @@
+// internal/testutil/repotest/attendance_ledger.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Skenario ledger absensi: event append-only, koreksi admin, dan segmen project.
+
+func testAttendanceLedger(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	checkIn := fixtures.Day(time.Now(), -1).Add(9 * time.Hour)
+	checkOut := checkIn.Add(8 * time.Hour)
+
+	_, err := db.Attendances.GetLastAttendance(ctx, user.ID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "user without attendance")
+
+	id := db.CheckIn(t, user.ID, checkIn)
+	note := "left early"
+	require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, checkOut, &note))
+	err = db.Attendances.UpdateCheckOut(ctx, id, checkOut, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already checked out")
+
+	got, err := db.Attendances.GetAttendanceByID(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, checkIn.Equal(got.CheckInAt))
+	require.NotNil(t, got.CheckOutAt)
+	assert.True(t, checkOut.Equal(*got.CheckOutAt))
+	assert.Equal(t, &note, got.Notes)
+
+	correctedIn := checkIn.Add(-30 * time.Minute)
+	corrected, err := db.Attendances.CorrectAttendance(ctx, id, &models.AttendanceCorrectionInput{CheckInAt: &correctedIn, Reason: "forgot to punch"}, admin.ID)
+	require.NoError(t, err)
+	assert.True(t, correctedIn.Equal(corrected.CheckInAt))
+	assert.Equal(t, &note, corrected.Notes, "fields not sent are kept")
+
+	events, err := db.Attendances.GetAttendanceEvents(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, models.AttendanceEventCheckIn, events[0].EventType)
+	assert.Equal(t, models.AttendanceEventCheckOut, events[1].EventType)
+	assert.Equal(t, models.AttendanceEventCorrection, events[2].EventType)
+	require.NotNil(t, events[2].Reason)
+	assert.Equal(t, "forgot to punch", *events[2].Reason)
+	assert.Equal(t, &admin.ID, events[2].ActorUserID)
+	assert.True(t, correctedIn.Equal(events[2].CheckInAt), "event holds the full snapshot")
+
+	segments, err := db.Attendances.GetAttendanceSegments(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+	assert.True(t, correctedIn.Equal(segments[0].StartedAt), "segment follows the corrected check-in")
+
+	backwards := correctedIn.Add(-time.Hour)
+	_, err = db.Attendances.CorrectAttendance(ctx, id, &models.AttendanceCorrectionInput{CheckOutAt: &backwards, Reason: "typo in time"}, admin.ID)
+	assert.ErrorIs(t, err, repository.ErrInvalidAttendanceTimes)
+
+	notFound := []struct {
+		name string
+		call func() error
+	}{
+		{name: "get", call: func() error { _, err := db.Attendances.GetAttendanceByID(ctx, missingID); return err }},
+		{name: "correct", call: func() error {
+			_, err := db.Attendances.CorrectAttendance(ctx, missingID, &models.AttendanceCorrectionInput{Reason: "missing"}, admin.ID)
+			return err
+		}},
+		{name: "events", call: func() error { _, err := db.Attendances.GetAttendanceEvents(ctx, missingID); return err }},
+		{name: "segments", call: func() error { _, err := db.Attendances.GetAttendanceSegments(ctx, missingID); return err }},
+	}
+	for _, tt := range notFound {
+		t.Run("unknown attendance "+tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), pgx.ErrNoRows)
+		})
+	}
+	err = db.Attendances.UpdateCheckOut(ctx, missingID, checkOut, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func testCheckInReferences(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	at := time.Now().Add(-time.Hour).Truncate(time.Second)
+	inactive, err := db.Projects.CreateProject(ctx, &models.Project{Code: "OLD", Name: "Old", IsActive: false})
+	require.NoError(t, err)
+	unknown := missingID
+
+	tests := []struct {
+		name      string
+		userID    int
+		projectID *int
+		wantErr   error
+	}{
+		{name: "unknown project", userID: user.ID, projectID: &unknown, wantErr: repository.ErrProjectUnavailable},
+		{name: "inactive project", userID: user.ID, projectID: &inactive, wantErr: repository.ErrProjectUnavailable},
+		{name: "unknown user", userID: missingID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Attendances.CreateCheckIn(ctx, tt.userID, at, nil, nil, tt.projectID, models.AttendanceModeOnsite)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	_, err = db.Attendances.GetLastAttendance(ctx, user.ID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "rejected check-ins leave no record")
+}
+
+func testSwitchProject(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	alpha := db.CreateProject(t, "ALPHA")
+	beta := db.CreateProject(t, "BETA")
+	day := fixtures.Day(time.Now(), -1)
+	checkIn := day.Add(8 * time.Hour)
+
+	_, err := db.Attendances.SwitchProject(ctx, user.ID, checkIn, alpha)
+	assert.ErrorIs(t, err, repository.ErrNotCheckedIn)
+
+	id, err := db.Attendances.CreateCheckIn(ctx, user.ID, checkIn, nil, nil, &alpha, models.AttendanceModeOnsite)
+	require.NoError(t, err)
+	_, err = db.Attendances.SwitchProject(ctx, user.ID, checkIn.Add(time.Hour), alpha)
+	assert.ErrorIs(t, err, repository.ErrAlreadyOnProject)
+	_, err = db.Attendances.SwitchProject(ctx, user.ID, checkIn.Add(time.Hour), missingID)
+	assert.ErrorIs(t, err, repository.ErrProjectUnavailable)
+
+	segment, err := db.Attendances.SwitchProject(ctx, user.ID, checkIn.Add(2*time.Hour), beta)
+	require.NoError(t, err)
+	assert.Equal(t, &beta, segment.ProjectID)
+	assert.Nil(t, segment.EndedAt)
+	require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, checkIn.Add(8*time.Hour), nil))
+
+	segments, err := db.Attendances.GetAttendanceSegments(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+	assert.Equal(t, &alpha, segments[0].ProjectID)
+	require.NotNil(t, segments[0].EndedAt)
+	assert.True(t, checkIn.Add(2*time.Hour).Equal(*segments[0].EndedAt))
+	require.NotNil(t, segments[1].EndedAt, "check-out closes the last segment")
+
+	hours, err := db.Attendances.GetProjectHours(ctx, day, fixtures.Day(day, 1), &user.ID)
+	require.NoError(t, err)
+	byProject := make(map[int]float64, len(hours))
+	for _, h := range hours {
+		require.NotNil(t, h.ProjectID)
+		byProject[*h.ProjectID] = h.TotalHours
+		assert.Equal(t, 1, h.Sessions)
+		assert.Equal(t, 1, h.Users)
+	}
+	assert.Equal(t, map[int]float64{alpha: 2, beta: 6}, byProject)
+}
+
+func testFaceChecks(t *testing.T, db Repos) {
+	ctx := context.Background()
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	checkEmptyPage(t, func(page, limit int) ([]models.FaceCheck, int, error) {
+		return db.Attendances.GetFaceChecks(ctx, "", page, limit)
+	})
+
+	base := fixtures.Day(time.Now(), -1).Add(8 * time.Hour)
+	pending, score := models.FaceReviewPending, 0.42
+	var ids []int
+	for i := range 3 {
+		user := db.CreateUser(t)
+		id := db.CheckIn(t, user.ID, base.Add(time.Duration(i)*time.Minute))
+		fc := &models.FaceCheck{AttendanceID: id, Provider: "test", Status: models.FaceMatchLowConfidence, Score: &score, ReviewStatus: &pending}
+		require.NoError(t, db.Attendances.RecordFaceCheck(ctx, fc))
+		assert.False(t, fc.CreatedAt.IsZero())
+		ids = append(ids, id)
+	}
+	err := db.Attendances.RecordFaceCheck(ctx, &models.FaceCheck{AttendanceID: ids[0], Provider: "test", Status: models.FaceMatchMatched})
+	assert.Error(t, err, "one face check per attendance")
+
+	checkPagination(t, 3, func(page, limit int) ([]models.FaceCheck, int, error) {
+		return db.Attendances.GetFaceChecks(ctx, models.FaceReviewPending, page, limit)
+	}, func(fc models.FaceCheck) int { return fc.AttendanceID })
+
+	reviewed, err := db.Attendances.ReviewFaceCheck(ctx, ids[0], models.FaceReviewApproved, admin.ID)
+	require.NoError(t, err)
+	require.NotNil(t, reviewed.ReviewStatus)
+	assert.Equal(t, models.FaceReviewApproved, *reviewed.ReviewStatus)
+	assert.Equal(t, &admin.ID, reviewed.ReviewedBy)
+	assert.NotNil(t, reviewed.ReviewedAt)
+
+	for _, tt := range []struct {
+		name string
+		id   int
+	}{
+		{name: "already reviewed", id: ids[0]},
+		{name: "unknown attendance", id: missingID},
+	} {
+		t.Run("review "+tt.name, func(t *testing.T) {
+			_, err := db.Attendances.ReviewFaceCheck(ctx, tt.id, models.FaceReviewRejected, admin.ID)
+			assert.ErrorIs(t, err, pgx.ErrNoRows)
+		})
+	}
+
+	_, total, err := db.Attendances.GetFaceChecks(ctx, models.FaceReviewPending, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	all, total, err := db.Attendances.GetFaceChecks(ctx, "", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.NotNil(t, all[0].User)
+	assert.NotEmpty(t, all[0].User.Username)
+}
+
+func testAttendanceTagsAndModes(t *testing.T, db Repos) {
+	ctx := context.Background()
+	alice := db.CreateUser(t)
+	bob := db.CreateUser(t)
+	day := fixtures.Day(time.Now(), -1)
+	from, to := day, fixtures.Day(day, 1)
+
+	tests := []struct {
+		user  int
+		start time.Time
+		hours int
+		mode  string
+		tags  []string
+	}{
+		{user: alice.ID, start: day.Add(8 * time.Hour), hours: 4, mode: models.AttendanceModeOnsite, tags: []string{"overtime", "travel"}},
+		{user: bob.ID, start: day.Add(9 * time.Hour), hours: 2, mode: models.AttendanceModeRemote, tags: []string{"overtime"}},
+		{user: alice.ID, start: day.Add(14 * time.Hour), hours: 3, mode: models.AttendanceModeRemote},
+	}
+	for _, tt := range tests {
+		id, err := db.Attendances.CreateCheckIn(ctx, tt.user, tt.start, nil, nil, nil, tt.mode)
+		require.NoError(t, err)
+		if tt.tags != nil {
+			require.NoError(t, db.Attendances.AddAttendanceTags(ctx, id, tt.tags))
+			require.NoError(t, db.Attendances.AddAttendanceTags(ctx, id, tt.tags), "existing tags are ignored")
+		}
+		require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, tt.start.Add(time.Duration(tt.hours)*time.Hour), nil))
+	}
+	// Sesi terbuka tidak ikut rekap.
+	db.CheckIn(t, bob.ID, day.Add(20*time.Hour))
+	assert.Error(t, db.Attendances.AddAttendanceTags(ctx, missingID, []string{"overtime"}), "unknown attendance")
+
+	tags, err := db.Attendances.GetTagHours(ctx, from, to, nil)
+	require.NoError(t, err)
+	gotTags := make(map[string]models.TagHours, len(tags))
+	for _, h := range tags {
+		gotTags[h.Tag] = h
+	}
+	assert.Equal(t, map[string]models.TagHours{
+		"overtime": {Tag: "overtime", Sessions: 2, Users: 2, TotalHours: 6},
+		"travel":   {Tag: "travel", Sessions: 1, Users: 1, TotalHours: 4},
+	}, gotTags)
+
+	tags, err = db.Attendances.GetTagHours(ctx, from, to, &bob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []models.TagHours{{Tag: "overtime", Sessions: 1, Users: 1, TotalHours: 2}}, tags)
+
+	modes, err := db.Attendances.GetModeHours(ctx, from, to, nil)
+	require.NoError(t, err)
+	gotModes := make(map[string]models.ModeHours, len(modes))
+	for _, h := range modes {
+		gotModes[h.Mode] = h
+	}
+	assert.Equal(t, map[string]models.ModeHours{
+		models.AttendanceModeOnsite: {Mode: models.AttendanceModeOnsite, Sessions: 1, Users: 1, TotalHours: 4},
+		models.AttendanceModeRemote: {Mode: models.AttendanceModeRemote, Sessions: 2, Users: 2, TotalHours: 5},
+	}, gotModes)
+
+	modes, err = db.Attendances.GetModeHours(ctx, fixtures.Day(day, -10), fixtures.Day(day, -9), nil)
+	require.NoError(t, err)
+	assert.Empty(t, modes, "range without sessions")
+}
+
+func testAttendancePings(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	checkIn := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+	field, err := db.Attendances.CreateCheckIn(ctx, user.ID, checkIn, nil, nil, nil, models.AttendanceModeField)
+	require.NoError(t, err)
+	onsite := db.CheckIn(t, other.ID, checkIn)
+
+	ping := func(id int, at time.Time) *models.AttendancePing {
+		return &models.AttendancePing{AttendanceID: id, RecordedAt: at, Latitude: -6.2, Longitude: 106.816666}
+	}
+	accuracy := 15
+	first := ping(field, checkIn.Add(10*time.Minute))
+	first.AccuracyM = &accuracy
+	require.NoError(t, db.Attendances.RecordAttendancePing(ctx, user.ID, first, time.Minute))
+	require.NoError(t, db.Attendances.RecordAttendancePing(ctx, user.ID, ping(field, checkIn.Add(20*time.Minute)), time.Minute))
+
+	tests := []struct {
+		name    string
+		userID  int
+		ping    *models.AttendancePing
+		wantErr error
+	}{
+		{name: "unknown attendance", userID: user.ID, ping: ping(missingID, checkIn.Add(time.Hour)), wantErr: pgx.ErrNoRows},
+		{name: "session of another user", userID: other.ID, ping: ping(field, checkIn.Add(time.Hour)), wantErr: pgx.ErrNoRows},
+		{name: "not a field session", userID: other.ID, ping: ping(onsite, checkIn.Add(time.Hour)), wantErr: repository.ErrNotFieldSession},
+		{name: "before check-in", userID: user.ID, ping: ping(field, checkIn.Add(-time.Minute)), wantErr: repository.ErrPingOutsideSession},
+		{name: "within the minimum interval", userID: user.ID, ping: ping(field, checkIn.Add(20*time.Minute+30*time.Second)), wantErr: repository.ErrPingTooSoon},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, db.Attendances.RecordAttendancePing(ctx, tt.userID, tt.ping, time.Minute), tt.wantErr)
+		})
+	}
+
+	pings, err := db.Attendances.GetAttendancePings(ctx, field)
+	require.NoError(t, err)
+	require.Len(t, pings, 2)
+	assert.True(t, first.RecordedAt.Equal(pings[0].RecordedAt))
+	assert.InDelta(t, -6.2, pings[0].Latitude, 1e-6)
+	assert.InDelta(t, 106.816666, pings[0].Longitude, 1e-6)
+	assert.Equal(t, &accuracy, pings[0].AccuracyM)
+	assert.Nil(t, pings[1].AccuracyM)
+
+	pings, err = db.Attendances.GetAttendancePings(ctx, missingID)
+	require.NoError(t, err)
+	assert.Empty(t, pings)
+
+	require.NoError(t, db.Attendances.UpdateCheckOut(ctx, field, checkIn.Add(time.Hour), nil))
+	err = db.Attendances.RecordAttendancePing(ctx, user.ID, ping(field, checkIn.Add(50*time.Minute)), time.Minute)
+	assert.ErrorIs(t, err, repository.ErrNotCheckedIn)
+
+	deleted, err := db.Attendances.DeleteAttendancePingsBefore(ctx, checkIn.Add(30*time.Minute), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted, "limited to one batch")
+	deleted, err = db.Attendances.DeleteAttendancePingsBefore(ctx, checkIn.Add(30*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	deleted, err = db.Attendances.DeleteAttendancePingsBefore(ctx, checkIn.Add(30*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func testAllAttendances(t *testing.T, db Repos) {
+	ctx := context.Background()
+	day := fixtures.Day(time.Now(), -1)
+	from, to := day, fixtures.Day(day, 1)
+	list := func(filter models.AttendanceReportFilter) pageFunc[models.Attendance] {
+		return func(page, limit int) ([]models.Attendance, int, error) {
+			return db.Attendances.GetAllAttendances(ctx, from, to, filter, page, limit)
+		}
+	}
+	checkEmptyPage(t, list(models.AttendanceReportFilter{}))
+
+	var ids []int
+	for i := range 3 {
+		user := db.CreateUser(t)
+		ids = append(ids, db.CheckIn(t, user.ID, day.Add(time.Duration(8+i)*time.Hour)))
+	}
+	// Di luar rentang tanggal.
+	db.CheckIn(t, db.CreateUser(t).ID, fixtures.Day(day, -3).Add(8*time.Hour))
+
+	checkPagination(t, 3, list(models.AttendanceReportFilter{}), func(a models.Attendance) int { return a.ID })
+
+	items, _, err := list(models.AttendanceReportFilter{})(1, 10)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, []int{ids[2], ids[1], ids[0]}, []int{items[0].ID, items[1].ID, items[2].ID}, "newest check-in first")
+	require.NotNil(t, items[0].User)
+	assert.NotEmpty(t, items[0].User.Username)
+
+	tests := []struct {
+		name      string
+		filter    models.AttendanceReportFilter
+		wantTotal int
+	}{
+		{name: "matching employment status", filter: models.AttendanceReportFilter{EmploymentStatus: models.EmploymentStatusPermanent}, wantTotal: 3},
+		{name: "other employment status", filter: models.AttendanceReportFilter{EmploymentStatus: models.EmploymentStatusIntern}},
+		{name: "disputed only without disputes", filter: models.AttendanceReportFilter{DisputedOnly: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, total, err := list(tt.filter)(1, 10)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
+func testExportAttendances(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+
+	exported, err := db.Attendances.ExportAttendancesByUser(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, exported)
+
+	base := fixtures.Day(time.Now(), -40).Add(8 * time.Hour)
+	for i := range 3 {
+		id := db.CheckIn(t, user.ID, fixtures.Day(base, i*10).Add(8*time.Hour))
+		require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, fixtures.Day(base, i*10).Add(16*time.Hour), nil))
+	}
+	db.CheckIn(t, other.ID, base)
+
+	exported, err = db.Attendances.ExportAttendancesByUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, exported, 3, "no pagination or date filter")
+	for _, a := range exported {
+		assert.Equal(t, user.ID, a.UserID)
+	}
+}
+
+func testAttendanceChangesSettle(t *testing.T, db Repos) {
+	user := db.CreateUser(t)
+	db.CheckIn(t, user.ID, time.Now().Add(-time.Hour))
+	checkUnsettledChangesWithheld(t, func(cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+		return db.Attendances.GetAttendanceChanges(context.Background(), cursor, limit)
+	})
+}
@@ -0,0 +1,162 @@
+// internal/testutil/repotest/attendances.go
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var attendanceTests = []contractTest{
+	{"CreateCheckInRejectsSecondOpenSession", testCreateCheckInRejectsSecondOpenSession},
+	{"GetAttendancesByUserPagination", testGetAttendancesByUserPagination},
+	{"QueuedCheckInFailures", testQueuedCheckInFailures},
+	{"Ledger", testAttendanceLedger},
+	{"CheckInReferences", testCheckInReferences},
+	{"SwitchProject", testSwitchProject},
+	{"FaceChecks", testFaceChecks},
+	{"TagsAndModes", testAttendanceTagsAndModes},
+	{"Pings", testAttendancePings},
+	{"AllAttendances", testAllAttendances},
+	{"Export", testExportAttendances},
+	{"ChangesSettle", testAttendanceChangesSettle},
+}
+
+func testCreateCheckInRejectsSecondOpenSession(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	now := time.Now().Truncate(time.Second)
+
+	db.CheckIn(t, user.ID, now.Add(-time.Hour))
+	// Unique index parsial uq_attendances_open_session (23505) dipetakan ke ErrAlreadyCheckedIn.
+	_, err := db.Attendances.CreateCheckIn(ctx, user.ID, now, nil, nil, nil, models.AttendanceModeOnsite)
+	assert.ErrorIs(t, err, repository.ErrAlreadyCheckedIn)
+
+	// Setelah check-out, check-in baru diterima lagi.
+	last, err := db.Attendances.GetLastAttendance(ctx, user.ID)
+	require.NoError(t, err)
+	require.NoError(t, db.Attendances.UpdateCheckOut(ctx, last.ID, now.Add(-time.Minute), nil))
+	_, err = db.Attendances.CreateCheckIn(ctx, user.ID, now, nil, nil, nil, models.AttendanceModeOnsite)
+	assert.NoError(t, err)
+}
+
+func testGetAttendancesByUserPagination(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	now := time.Now()
+
+	// Lima sesi selesai milik user (hari -5..-1) dan satu milik user lain yang tidak boleh terhitung.
+	for day := -5; day < 0; day++ {
+		checkIn := fixtures.Day(now, day).Add(9 * time.Hour)
+		id := db.CheckIn(t, user.ID, checkIn)
+		require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, checkIn.Add(8*time.Hour), nil))
+	}
+	db.CheckIn(t, other.ID, fixtures.Day(now, -1).Add(9*time.Hour))
+
+	start, end := fixtures.Day(now, -10), fixtures.Day(now, 1)
+	tests := []struct {
+		name        string
+		page, limit int
+		wantLen     int
+		wantFirst   int // Offset hari check-in record pertama (urutan terbaru dulu)
+	}{
+		{name: "first page", page: 1, limit: 2, wantLen: 2, wantFirst: -1},
+		{name: "middle page", page: 2, limit: 2, wantLen: 2, wantFirst: -3},
+		{name: "last partial page", page: 3, limit: 2, wantLen: 1, wantFirst: -5},
+		{name: "past the last page", page: 4, limit: 2, wantLen: 0},
+		{name: "page below 1 is the first page", page: 0, limit: 2, wantLen: 2, wantFirst: -1},
+		{name: "limit larger than total", page: 1, limit: 100, wantLen: 5, wantFirst: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := db.Attendances.GetAttendancesByUser(ctx, user.ID, start, end, tt.page, tt.limit)
+			require.NoError(t, err)
+			assert.Equal(t, 5, total)
+			require.Len(t, got, tt.wantLen)
+			if tt.wantLen > 0 {
+				assert.Equal(t, fixtures.Day(now, tt.wantFirst).Format(fixtures.DateLayout), got[0].CheckInAt.UTC().Format(fixtures.DateLayout))
+				assert.Equal(t, user.ID, got[0].UserID)
+			}
+		})
+	}
+
+	t.Run("no attendance in range", func(t *testing.T) {
+		got, total, err := db.Attendances.GetAttendancesByUser(ctx, user.ID, fixtures.Day(now, -30), fixtures.Day(now, -20), 1, 10)
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+}
+
+func testQueuedCheckInFailures(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	at := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+
+	var ids []int
+	for i := 0; i < 3; i++ {
+		f := &models.QueuedCheckInFailure{
+			UserID: user.ID, CheckInAt: at.Add(time.Duration(i) * time.Minute), Tags: []string{"onsite"},
+			Mode: models.AttendanceModeOnsite, Source: models.QueuedCheckInSourceDegraded, Error: "user already checked in",
+		}
+		require.NoError(t, db.Attendances.RecordQueuedCheckInFailure(ctx, f))
+		require.NotZero(t, f.ID)
+		ids = append(ids, f.ID)
+	}
+
+	resolved, err := db.Attendances.ResolveQueuedCheckInFailure(ctx, ids[0], admin.ID, "entered manually")
+	require.NoError(t, err)
+	require.NotNil(t, resolved.ResolvedAt)
+	assert.Equal(t, admin.ID, *resolved.ResolvedBy)
+
+	// Entri yang sudah ditandai tidak bisa ditandai lagi; ID yang tidak ada juga ErrNoRows.
+	_, err = db.Attendances.ResolveQueuedCheckInFailure(ctx, ids[0], admin.ID, "again")
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = db.Attendances.ResolveQueuedCheckInFailure(ctx, 999999, admin.ID, "missing")
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	open, yes := false, true
+	tests := []struct {
+		name      string
+		resolved  *bool
+		page      int
+		limit     int
+		wantTotal int
+		wantLen   int
+	}{
+		{name: "open", resolved: &open, page: 1, limit: 10, wantTotal: 2, wantLen: 2},
+		{name: "resolved", resolved: &yes, page: 1, limit: 10, wantTotal: 1, wantLen: 1},
+		{name: "all", page: 1, limit: 10, wantTotal: 3, wantLen: 3},
+		{name: "all second page", page: 2, limit: 2, wantTotal: 3, wantLen: 1},
+		{name: "past the last page", page: 3, limit: 2, wantTotal: 3, wantLen: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := db.Attendances.GetQueuedCheckInFailures(ctx, tt.resolved, tt.page, tt.limit)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, total)
+			assert.Len(t, got, tt.wantLen)
+		})
+	}
+
+	t.Run("unknown user", func(t *testing.T) {
+		err := db.Attendances.RecordQueuedCheckInFailure(ctx, &models.QueuedCheckInFailure{
+			UserID: 999999, CheckInAt: at, Mode: models.AttendanceModeOnsite, Source: models.QueuedCheckInSourceMaintenance, Error: "x",
+		})
+		var pgErr *pgconn.PgError
+		require.True(t, errors.As(err, &pgErr), "got %v", err)
+		assert.Equal(t, "23503", pgErr.Code)
+	})
+}
@@ -0,0 +1,125 @@
+// internal/testutil/repotest/audit.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var auditTests = []contractTest{
+	{"CreateAuditEntry", testCreateAuditEntry},
+	{"UserActivityCursor", testUserActivityCursor},
+	{"LoginHistoryMatch", testLoginHistoryMatch},
+}
+
+func testCreateAuditEntry(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+
+	entry := &models.AuditEntry{UserID: user.ID, ActorUserID: &admin.ID, Action: models.AuditPasswordChanged, Details: map[string]any{"via": "admin"}}
+	require.NoError(t, db.Audit.CreateAuditEntry(ctx, entry))
+	assert.NotZero(t, entry.ID)
+	assert.False(t, entry.CreatedAt.IsZero())
+	unknown := missingID
+
+	tests := []struct {
+		name  string
+		entry *models.AuditEntry
+	}{
+		{name: "unknown user", entry: &models.AuditEntry{UserID: missingID, Action: models.AuditPasswordChanged}},
+		{name: "unknown actor", entry: &models.AuditEntry{UserID: user.ID, ActorUserID: &unknown, Action: models.AuditPasswordChanged}},
+		{name: "malformed action", entry: &models.AuditEntry{UserID: user.ID, Action: "Password Changed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, db.Audit.CreateAuditEntry(ctx, tt.entry))
+		})
+	}
+
+	items, err := db.Audit.GetUserActivity(ctx, user.ID, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, items, 1, "rejected entries are not stored")
+	assert.Equal(t, models.ActivitySourceAuditLog, items[0].Source)
+	assert.Equal(t, entry.ID, items[0].SourceID)
+	assert.Equal(t, "auth", items[0].Category)
+	assert.Equal(t, &admin.ID, items[0].ActorUserID)
+	assert.Equal(t, "admin", items[0].Details["via"])
+}
+
+func testUserActivityCursor(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	items, err := db.Audit.GetUserActivity(ctx, user.ID, nil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	for _, action := range []string{models.AuditLoginSucceeded, models.AuditPasswordChanged, models.AuditLoginFailed} {
+		require.NoError(t, db.Audit.CreateAuditEntry(ctx, &models.AuditEntry{UserID: user.ID, Action: action}))
+	}
+	require.NoError(t, db.Audit.CreateAuditEntry(ctx, &models.AuditEntry{UserID: other.ID, Action: models.AuditLoginSucceeded}))
+	id := db.CheckIn(t, user.ID, time.Now().Add(-time.Hour))
+	require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, time.Now(), nil))
+
+	all, err := db.Audit.GetUserActivity(ctx, user.ID, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 5, "audit log and attendance ledger of this user only")
+	sources := map[string]int{}
+	for _, item := range all {
+		sources[item.Source]++
+	}
+	assert.Equal(t, map[string]int{models.ActivitySourceAuditLog: 3, models.ActivitySourceAttendanceEvents: 2}, sources)
+
+	// Halaman berukuran 2 dengan kursor entri terakhir menghasilkan urutan feed yang sama tanpa duplikat.
+	var paged []models.ActivityItem
+	var cursor *models.ActivityCursor
+	for range 4 {
+		page, err := db.Audit.GetUserActivity(ctx, user.ID, cursor, 2)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		assert.LessOrEqual(t, len(page), 2)
+		paged = append(paged, page...)
+		last := page[len(page)-1]
+		cursor = &models.ActivityCursor{OccurredAt: last.OccurredAt, Source: last.Source, SourceID: last.SourceID}
+	}
+	assert.Equal(t, all, paged)
+}
+
+func testLoginHistoryMatch(t *testing.T, db Repos) {
+	ctx := context.Background()
+	fresh := db.CreateUser(t)
+	legacy := db.CreateUser(t)
+	user := db.CreateUser(t)
+	require.NoError(t, db.Audit.CreateAuditEntry(ctx, &models.AuditEntry{UserID: legacy.ID, Action: models.AuditLoginSucceeded}))
+	require.NoError(t, db.Audit.CreateAuditEntry(ctx, &models.AuditEntry{UserID: user.ID, Action: models.AuditLoginSucceeded, Details: map[string]any{"device": "d1", "country": "ID"}}))
+	require.NoError(t, db.Audit.CreateAuditEntry(ctx, &models.AuditEntry{UserID: user.ID, Action: models.AuditLoginFailed, Details: map[string]any{"device": "d2", "country": "SG"}}))
+
+	tests := []struct {
+		name            string
+		userID          int
+		device, country string
+		want            models.LoginHistoryMatch
+	}{
+		{name: "no history", userID: fresh.ID, device: "d1", country: "ID", want: models.LoginHistoryMatch{KnownDevice: true, KnownCountry: true}},
+		{name: "history without device or country", userID: legacy.ID, device: "d9", country: "US", want: models.LoginHistoryMatch{HasHistory: true, KnownDevice: true, KnownCountry: true}},
+		{name: "known device and country", userID: user.ID, device: "d1", country: "ID", want: models.LoginHistoryMatch{HasHistory: true, KnownDevice: true, KnownCountry: true}},
+		{name: "only failed logins used the device", userID: user.ID, device: "d2", country: "SG", want: models.LoginHistoryMatch{HasHistory: true}},
+		{name: "unknown country", userID: user.ID, device: "d1", country: "", want: models.LoginHistoryMatch{HasHistory: true, KnownDevice: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := db.Audit.GetLoginHistoryMatch(ctx, tt.userID, tt.device, tt.country)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *match)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// internal/testutil/repotest/backups.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var backupTests = []contractTest{
+	{"CreateBackupOnlyOneActive", testCreateBackupOnlyOneActive},
+	{"BackupLifecycle", testBackupLifecycle},
+	{"BackupsPagination", testBackupsPagination},
+}
+
+func testCreateBackupOnlyOneActive(t *testing.T, db Repos) {
+	ctx := context.Background()
+	first := &models.Backup{}
+	require.NoError(t, db.Backups.CreateBackup(ctx, first))
+	assert.Equal(t, models.BackupPending, first.Status)
+
+	// Unique index parsial idx_backups_active (23505) dipetakan ke ErrBackupInProgress.
+	assert.ErrorIs(t, db.Backups.CreateBackup(ctx, &models.Backup{}), repository.ErrBackupInProgress)
+}
+
+func testBackupLifecycle(t *testing.T, db Repos) {
+	ctx := context.Background()
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	_, err := db.Backups.ClaimPendingBackup(ctx)
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "nothing pending")
+
+	backup := &models.Backup{RequestedBy: &admin.ID}
+	require.NoError(t, db.Backups.CreateBackup(ctx, backup))
+	assert.NotZero(t, backup.ID)
+	assert.False(t, backup.CreatedAt.IsZero())
+	result := models.BackupResult{StorageKey: "backups/1.gz", FileName: "1.gz", SizeBytes: 2048, SchemaVersion: 7, TableCount: 40, RowCount: 1200}
+	assert.ErrorIs(t, db.Backups.MarkBackupCompleted(ctx, backup.ID, result), pgx.ErrNoRows, "complete a pending backup")
+
+	claimed, err := db.Backups.ClaimPendingBackup(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, backup.ID, claimed.ID)
+	assert.Equal(t, models.BackupRunning, claimed.Status)
+	assert.NotNil(t, claimed.StartedAt)
+	_, err = db.Backups.ClaimPendingBackup(ctx)
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "already claimed")
+
+	require.NoError(t, db.Backups.MarkBackupCompleted(ctx, backup.ID, result))
+	got, err := db.Backups.GetBackupByID(ctx, backup.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BackupCompleted, got.Status)
+	assert.Equal(t, &result.StorageKey, got.StorageKey)
+	assert.Equal(t, &result.SizeBytes, got.SizeBytes)
+	assert.Equal(t, &result.SchemaVersion, got.SchemaVersion)
+	assert.Equal(t, &admin.ID, got.RequestedBy)
+	assert.NotNil(t, got.CompletedAt)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "get unknown backup", call: func() error { _, err := db.Backups.GetBackupByID(ctx, missingID); return err }},
+		{name: "complete unknown backup", call: func() error { return db.Backups.MarkBackupCompleted(ctx, missingID, result) }},
+		{name: "fail unknown backup", call: func() error { return db.Backups.MarkBackupFailed(ctx, missingID, "boom") }},
+		{name: "fail completed backup", call: func() error { return db.Backups.MarkBackupFailed(ctx, backup.ID, "boom") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), pgx.ErrNoRows)
+		})
+	}
+
+	stale := &models.Backup{}
+	require.NoError(t, db.Backups.CreateBackup(ctx, stale))
+	_, err = db.Backups.ClaimPendingBackup(ctx)
+	require.NoError(t, err)
+	failed, err := db.Backups.FailStaleBackups(ctx, time.Now().Add(-time.Hour), "process stopped")
+	require.NoError(t, err)
+	assert.Zero(t, failed, "started after the cutoff")
+	failed, err = db.Backups.FailStaleBackups(ctx, time.Now().Add(time.Hour), "process stopped")
+	require.NoError(t, err)
+	assert.Equal(t, 1, failed)
+	got, err = db.Backups.GetBackupByID(ctx, stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BackupFailed, got.Status)
+	require.NotNil(t, got.Error)
+	assert.Equal(t, "process stopped", *got.Error)
+
+	require.NoError(t, db.Backups.CreateBackup(ctx, &models.Backup{}), "a failed backup is not active")
+}
+
+func testBackupsPagination(t *testing.T, db Repos) {
+	ctx := context.Background()
+	list := func(page, limit int) ([]models.Backup, int, error) {
+		return db.Backups.GetBackups(ctx, page, limit)
+	}
+	checkEmptyPage(t, list)
+
+	var ids []int
+	for range 3 {
+		backup := &models.Backup{}
+		require.NoError(t, db.Backups.CreateBackup(ctx, backup))
+		require.NoError(t, db.Backups.MarkBackupFailed(ctx, backup.ID, "storage unavailable"))
+		ids = append(ids, backup.ID)
+	}
+	checkPagination(t, 3, list, func(b models.Backup) int { return b.ID })
+
+	backups, _, err := list(1, 10)
+	require.NoError(t, err)
+	require.Len(t, backups, 3)
+	assert.Equal(t, ids[2], backups[0].ID, "newest backup first")
+}
@@ -0,0 +1,129 @@
+// internal/testutil/repotest/debug_captures.go
+package repotest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var debugCaptureTests = []contractTest{
+	{"DebugCaptureLifecycle", testDebugCaptureLifecycle},
+	{"DebugCapturesFilter", testDebugCapturesFilter},
+}
+
+// debugCapture menyimpan rekaman request method/route milik userID yang kedaluwarsa pada expiresAt.
+func debugCapture(t *testing.T, db Repos, userID *int, method, route string, expiresAt time.Time) *models.DebugCapture {
+	t.Helper()
+	capture := &models.DebugCapture{UserID: userID, Method: method, Route: route, Path: route, Status: http.StatusOK, LatencyMs: 12, ExpiresAt: expiresAt}
+	require.NoError(t, db.DebugCaptures.CreateDebugCapture(context.Background(), capture))
+	return capture
+}
+
+func testDebugCaptureLifecycle(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	body := `{"ok":true}`
+	capture := &models.DebugCapture{
+		UserID: &user.ID, Method: http.MethodPost, Route: "/api/v1/user/attendance/checkin", Path: "/api/v1/user/attendance/checkin",
+		Status: http.StatusCreated, LatencyMs: 40, RequestHeaders: map[string]string{"Content-Type": "application/json"},
+		ResponseBody: &body, ExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, db.DebugCaptures.CreateDebugCapture(ctx, capture))
+	assert.NotZero(t, capture.ID)
+	assert.False(t, capture.CreatedAt.IsZero())
+
+	got, err := db.DebugCaptures.GetDebugCaptureByID(ctx, capture.ID)
+	require.NoError(t, err)
+	assert.Equal(t, capture.Route, got.Route)
+	assert.Equal(t, capture.RequestHeaders, got.RequestHeaders)
+	assert.Empty(t, got.ResponseHeaders, "nil headers are stored as an empty object")
+	assert.Equal(t, &body, got.ResponseBody)
+	assert.Nil(t, got.RequestBody)
+
+	expired := debugCapture(t, db, nil, http.MethodGet, "/health", time.Now().Add(-time.Minute))
+	unknown := missingID
+	tests := []struct {
+		name string
+		id   int64
+	}{
+		{name: "expired capture", id: expired.ID},
+		{name: "unknown capture", id: int64(missingID)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.DebugCaptures.GetDebugCaptureByID(ctx, tt.id)
+			assert.ErrorIs(t, err, pgx.ErrNoRows)
+		})
+	}
+	err = db.DebugCaptures.CreateDebugCapture(ctx, &models.DebugCapture{UserID: &unknown, Method: http.MethodGet, Route: "/", Path: "/", ExpiresAt: time.Now()})
+	assert.Error(t, err, "unknown user")
+
+	for range 2 {
+		debugCapture(t, db, nil, http.MethodGet, "/health", time.Now().Add(-2*time.Minute))
+	}
+	deleted, err := db.DebugCaptures.DeleteExpiredDebugCaptures(ctx, time.Now(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted, "one batch")
+	deleted, err = db.DebugCaptures.DeleteExpiredDebugCaptures(ctx, time.Now(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted, "the rest")
+	deleted, err = db.DebugCaptures.DeleteExpiredDebugCaptures(ctx, time.Now(), 2)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+	_, err = db.DebugCaptures.GetDebugCaptureByID(ctx, capture.ID)
+	assert.NoError(t, err, "unexpired captures are kept")
+}
+
+func testDebugCapturesFilter(t *testing.T, db Repos) {
+	ctx := context.Background()
+	list := func(filter models.DebugCaptureFilter) pageFunc[models.DebugCapture] {
+		return func(page, limit int) ([]models.DebugCapture, int, error) {
+			return db.DebugCaptures.GetDebugCaptures(ctx, filter, page, limit)
+		}
+	}
+	checkEmptyPage(t, list(models.DebugCaptureFilter{}))
+
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	expires := time.Now().Add(time.Hour)
+	debugCapture(t, db, &user.ID, http.MethodGet, "/api/v1/user/schedules", expires)
+	debugCapture(t, db, &user.ID, http.MethodPost, "/api/v1/user/attendance/checkin", expires)
+	debugCapture(t, db, &other.ID, http.MethodGet, "/api/v1/user/schedules", expires)
+	last := debugCapture(t, db, nil, http.MethodGet, "/health", expires)
+	debugCapture(t, db, &user.ID, http.MethodGet, "/api/v1/user/schedules", time.Now().Add(-time.Minute))
+
+	tests := []struct {
+		name   string
+		filter models.DebugCaptureFilter
+		want   int
+	}{
+		{name: "unfiltered skips expired captures", filter: models.DebugCaptureFilter{}, want: 4},
+		{name: "by user", filter: models.DebugCaptureFilter{UserID: &user.ID}, want: 2},
+		{name: "by route", filter: models.DebugCaptureFilter{Route: "/api/v1/user/schedules"}, want: 2},
+		{name: "by method", filter: models.DebugCaptureFilter{Method: http.MethodPost}, want: 1},
+		{name: "combined", filter: models.DebugCaptureFilter{UserID: &other.ID, Route: "/api/v1/user/schedules", Method: http.MethodGet}, want: 1},
+		{name: "no match", filter: models.DebugCaptureFilter{Route: "/missing"}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captures, total, err := list(tt.filter)(1, 10)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, total)
+			assert.Len(t, captures, tt.want)
+		})
+	}
+
+	captures, _, err := list(models.DebugCaptureFilter{})(1, 1)
+	require.NoError(t, err)
+	require.Len(t, captures, 1)
+	assert.Equal(t, last.ID, captures[0].ID, "newest first")
+	assert.Nil(t, captures[0].RequestHeaders, "headers are omitted from the list")
+	checkPagination(t, 4, list(models.DebugCaptureFilter{}), func(c models.DebugCapture) int64 { return c.ID })
+}
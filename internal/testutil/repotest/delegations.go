@@ -0,0 +1,124 @@
+// internal/testutil/repotest/delegations.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var delegationTests = []contractTest{
+	{"DelegationLifecycle", testDelegationLifecycle},
+	{"CreateDelegationRejected", testCreateDelegationRejected},
+}
+
+func testDelegationLifecycle(t *testing.T, db Repos) {
+	ctx := context.Background()
+	manager := db.CreateUser(t)
+	deputy := db.CreateUser(t)
+	outsider := db.CreateUser(t)
+	today := fixtures.Day(time.Now(), 0)
+	reason := "annual leave"
+	input := models.DelegationInput{
+		DelegateID: deputy.ID, StartDate: today.Format(fixtures.DateLayout),
+		EndDate: fixtures.Day(today, 6).Format(fixtures.DateLayout), Reason: &reason,
+	}
+	delegation, err := db.Delegations.CreateDelegation(ctx, manager.ID, input)
+	require.NoError(t, err)
+	assert.Equal(t, manager.ID, delegation.DelegatorID)
+	assert.Equal(t, input.StartDate, delegation.StartDate)
+	assert.Equal(t, input.EndDate, delegation.EndDate)
+	assert.Equal(t, &reason, delegation.Reason)
+	assert.Nil(t, delegation.RevokedAt)
+
+	tests := []struct {
+		name                    string
+		delegatorID, delegateID int
+		day                     time.Time
+		want                    bool
+	}{
+		{name: "first day", delegatorID: manager.ID, delegateID: deputy.ID, day: today, want: true},
+		{name: "last day", delegatorID: manager.ID, delegateID: deputy.ID, day: fixtures.Day(today, 6), want: true},
+		{name: "after the range", delegatorID: manager.ID, delegateID: deputy.ID, day: fixtures.Day(today, 7)},
+		{name: "other delegate", delegatorID: manager.ID, delegateID: outsider.ID, day: today},
+		{name: "reversed direction", delegatorID: deputy.ID, delegateID: manager.ID, day: today},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, err := db.Delegations.HasActiveDelegation(ctx, tt.delegatorID, tt.delegateID, tt.day)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, active)
+		})
+	}
+
+	for _, userID := range []int{manager.ID, deputy.ID} {
+		delegations, err := db.Delegations.GetDelegationsByUser(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, delegations, 1, "given and received delegations")
+		assert.Equal(t, delegation.ID, delegations[0].ID)
+	}
+	delegations, err := db.Delegations.GetDelegationsByUser(ctx, outsider.ID)
+	require.NoError(t, err)
+	assert.Empty(t, delegations)
+
+	revokeTests := []struct {
+		name        string
+		delegatorID int
+		id          int
+		wantErr     error
+	}{
+		{name: "by the delegate", delegatorID: deputy.ID, id: delegation.ID, wantErr: pgx.ErrNoRows},
+		{name: "unknown delegation", delegatorID: manager.ID, id: missingID, wantErr: pgx.ErrNoRows},
+	}
+	for _, tt := range revokeTests {
+		t.Run("revoke "+tt.name, func(t *testing.T) {
+			_, err := db.Delegations.RevokeDelegation(ctx, tt.id, tt.delegatorID)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+	revoked, err := db.Delegations.RevokeDelegation(ctx, delegation.ID, manager.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, revoked.RevokedAt)
+	_, err = db.Delegations.RevokeDelegation(ctx, delegation.ID, manager.ID)
+	assert.ErrorIs(t, err, repository.ErrDelegationRevoked)
+
+	active, err := db.Delegations.HasActiveDelegation(ctx, manager.ID, deputy.ID, today)
+	require.NoError(t, err)
+	assert.False(t, active, "revoked")
+}
+
+func testCreateDelegationRejected(t *testing.T, db Repos) {
+	ctx := context.Background()
+	manager := db.CreateUser(t)
+	inactive := db.CreateUser(t)
+	db.DeactivateUser(t, inactive.ID)
+	today := fixtures.Day(time.Now(), 0).Format(fixtures.DateLayout)
+	yesterday := fixtures.Day(time.Now(), -1).Format(fixtures.DateLayout)
+
+	tests := []struct {
+		name    string
+		input   models.DelegationInput
+		wantErr error
+	}{
+		{name: "unknown delegate", input: models.DelegationInput{DelegateID: missingID, StartDate: today, EndDate: today}, wantErr: pgx.ErrNoRows},
+		{name: "inactive delegate", input: models.DelegationInput{DelegateID: inactive.ID, StartDate: today, EndDate: today}, wantErr: pgx.ErrNoRows},
+		{name: "delegate to self", input: models.DelegationInput{DelegateID: manager.ID, StartDate: today, EndDate: today}},
+		{name: "end before start", input: models.DelegationInput{DelegateID: db.CreateUser(t).ID, StartDate: today, EndDate: yesterday}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Delegations.CreateDelegation(ctx, manager.ID, tt.input)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// internal/testutil/repotest/devices.go
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var deviceTests = []contractTest{
+	{"DeviceTokens", testDeviceTokens},
+	{"ShiftReminders", testShiftReminders},
+}
+
+func testDeviceTokens(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	tokens, err := db.Devices.GetDeviceTokensByUser(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	first, err := db.Devices.RegisterDeviceToken(ctx, user.ID, models.DevicePlatformFCM, "token-aaaa")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, first.UserID)
+	_, err = db.Devices.RegisterDeviceToken(ctx, user.ID, models.DevicePlatformAPNs, "token-bbbb")
+	require.NoError(t, err)
+	// Token yang sama didaftarkan akun lain: dipindahkan, bukan diduplikasi.
+	moved, err := db.Devices.RegisterDeviceToken(ctx, other.ID, models.DevicePlatformFCM, "token-aaaa")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, moved.ID)
+	assert.Equal(t, other.ID, moved.UserID)
+
+	tokens, err = db.Devices.GetDeviceTokensByUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "token-bbbb", tokens[0].Token)
+
+	_, err = db.Devices.RegisterDeviceToken(ctx, missingID, models.DevicePlatformFCM, "token-cccc")
+	assert.Error(t, err, "unknown user")
+
+	tests := []struct {
+		name   string
+		userID int
+		token  string
+	}{
+		{name: "token of another user", userID: user.ID, token: "token-aaaa"},
+		{name: "unknown token", userID: user.ID, token: "token-zzzz"},
+	}
+	for _, tt := range tests {
+		t.Run("delete "+tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, db.Devices.DeleteDeviceToken(ctx, tt.userID, tt.token), pgx.ErrNoRows)
+		})
+	}
+	require.NoError(t, db.Devices.DeleteDeviceToken(ctx, user.ID, "token-bbbb"))
+
+	_, err = db.Devices.RegisterDeviceToken(ctx, user.ID, models.DevicePlatformFCM, "token-dddd")
+	require.NoError(t, err)
+	deleted, err := db.Devices.DeleteDeviceTokens(ctx, []string{"token-aaaa", "token-dddd", "token-unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	deleted, err = db.Devices.DeleteDeviceTokens(ctx, []string{})
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func testShiftReminders(t *testing.T, db Repos) {
+	ctx := context.Background()
+	withDevice := db.CreateUser(t)
+	withoutDevice := db.CreateUser(t)
+	inactive := db.CreateUser(t)
+	for _, u := range []int{withDevice.ID, inactive.ID} {
+		_, err := db.Devices.RegisterDeviceToken(ctx, u, models.DevicePlatformFCM, fmt.Sprintf("token-user-%d", u))
+		require.NoError(t, err)
+	}
+	shift := db.CreateShift(t)
+	today := fixtures.Day(time.Now(), 0)
+	tomorrow := fixtures.Day(today, 1)
+	scheduleID := db.CreateSchedule(t, withDevice.ID, shift.ID, tomorrow)
+	db.CreateSchedule(t, withDevice.ID, shift.ID, fixtures.Day(today, 5)) // Di luar rentang
+	db.CreateSchedule(t, withoutDevice.ID, shift.ID, tomorrow)
+	db.CreateSchedule(t, inactive.ID, shift.ID, tomorrow)
+	db.DeactivateUser(t, inactive.ID)
+
+	reminders, err := db.Devices.GetPendingShiftReminders(ctx, today, tomorrow)
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, models.ShiftReminder{
+		ScheduleID: scheduleID, UserID: withDevice.ID, Date: tomorrow.Format(fixtures.DateLayout),
+		ShiftName: shift.Name, StartTime: shift.StartTime,
+	}, reminders[0])
+
+	sent, err := db.Devices.MarkShiftReminderSent(ctx, scheduleID)
+	require.NoError(t, err)
+	assert.True(t, sent)
+	sent, err = db.Devices.MarkShiftReminderSent(ctx, scheduleID)
+	require.NoError(t, err)
+	assert.False(t, sent, "already recorded")
+	_, err = db.Devices.MarkShiftReminderSent(ctx, missingID)
+	assert.Error(t, err, "unknown schedule")
+
+	reminders, err = db.Devices.GetPendingShiftReminders(ctx, today, tomorrow)
+	require.NoError(t, err)
+	assert.Empty(t, reminders)
+}
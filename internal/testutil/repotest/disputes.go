@@ -0,0 +1,137 @@
+// internal/testutil/repotest/disputes.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var disputeTests = []contractTest{
+	{"CreateDispute", testCreateDispute},
+	{"ResolveDispute", testResolveDispute},
+	{"DisputesPagination", testDisputesPagination},
+}
+
+func testCreateDispute(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	attendanceID := db.CheckIn(t, user.ID, time.Now().Add(-time.Hour))
+
+	_, err := db.Disputes.CreateDispute(ctx, attendanceID, user.ID, "forgot to check out")
+	require.NoError(t, err)
+
+	// Unique index parsial dispute open (23505) dipetakan ke ErrDisputeAlreadyOpen.
+	_, err = db.Disputes.CreateDispute(ctx, attendanceID, user.ID, "again")
+	assert.ErrorIs(t, err, repository.ErrDisputeAlreadyOpen)
+	// Record milik user lain tidak bisa di-dispute.
+	_, err = db.Disputes.CreateDispute(ctx, attendanceID, other.ID, "not mine")
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func testResolveDispute(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	attendanceID := db.CheckIn(t, user.ID, time.Now().Add(-time.Hour))
+	dispute, err := db.Disputes.CreateDispute(ctx, attendanceID, user.ID, "wrong check-in time")
+	require.NoError(t, err)
+	assert.Equal(t, models.DisputeOpen, dispute.Status)
+
+	got, err := db.Disputes.GetDisputeByID(ctx, dispute.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "wrong check-in time", got.Comment)
+	assert.Equal(t, attendanceID, got.AttendanceID)
+
+	resolved, err := db.Disputes.ResolveDispute(ctx, dispute.ID, admin.ID, models.DisputeResolved, "corrected")
+	require.NoError(t, err)
+	assert.Equal(t, models.DisputeResolved, resolved.Status)
+	assert.Equal(t, &admin.ID, resolved.ResolvedBy)
+	require.NotNil(t, resolved.ResolutionNote)
+	assert.Equal(t, "corrected", *resolved.ResolutionNote)
+	assert.NotNil(t, resolved.ResolvedAt)
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{name: "get unknown dispute", call: func() error { _, err := db.Disputes.GetDisputeByID(ctx, missingID); return err }, wantErr: pgx.ErrNoRows},
+		{name: "resolve unknown dispute", call: func() error {
+			_, err := db.Disputes.ResolveDispute(ctx, missingID, admin.ID, models.DisputeRejected, "none")
+			return err
+		}, wantErr: pgx.ErrNoRows},
+		{name: "resolve closed dispute", call: func() error {
+			_, err := db.Disputes.ResolveDispute(ctx, dispute.ID, admin.ID, models.DisputeRejected, "again")
+			return err
+		}, wantErr: repository.ErrDisputeNotOpen},
+		{name: "dispute unknown attendance", call: func() error {
+			_, err := db.Disputes.CreateDispute(ctx, missingID, user.ID, "missing")
+			return err
+		}, wantErr: pgx.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), tt.wantErr)
+		})
+	}
+
+	// Dispute yang sudah ditutup tidak menghalangi dispute baru atas record yang sama.
+	reopened, err := db.Disputes.CreateDispute(ctx, attendanceID, user.ID, "still wrong")
+	require.NoError(t, err)
+	disputes, err := db.Disputes.GetDisputesByUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, disputes, 2)
+	assert.Equal(t, reopened.ID, disputes[0].ID, "newest dispute first")
+
+	disputes, err = db.Disputes.GetDisputesByUser(ctx, admin.ID)
+	require.NoError(t, err)
+	assert.Empty(t, disputes)
+}
+
+func testDisputesPagination(t *testing.T, db Repos) {
+	ctx := context.Background()
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	list := func(status string) pageFunc[models.AttendanceDispute] {
+		return func(page, limit int) ([]models.AttendanceDispute, int, error) {
+			return db.Disputes.GetAllDisputes(ctx, status, page, limit)
+		}
+	}
+	checkEmptyPage(t, list(""))
+
+	var ids []int
+	for i := range 4 {
+		user := db.CreateUser(t)
+		attendanceID := db.CheckIn(t, user.ID, time.Now().Add(-time.Duration(i+1)*time.Hour))
+		dispute, err := db.Disputes.CreateDispute(ctx, attendanceID, user.ID, "please check")
+		require.NoError(t, err)
+		ids = append(ids, dispute.ID)
+	}
+	_, err := db.Disputes.ResolveDispute(ctx, ids[3], admin.ID, models.DisputeRejected, "fine as is")
+	require.NoError(t, err)
+
+	checkPagination(t, 3, list(models.DisputeOpen), func(d models.AttendanceDispute) int { return d.ID })
+	disputes, total, err := list("")(1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, ids[0], disputes[0].ID, "oldest dispute first")
+	_, total, err = list(models.DisputeResolved)(1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	open, err := db.Disputes.GetOpenDisputesBefore(ctx, time.Now().Add(time.Minute), 2)
+	require.NoError(t, err)
+	require.Len(t, open, 2, "limited")
+	assert.Equal(t, ids[0], open[0].ID)
+	open, err = db.Disputes.GetOpenDisputesBefore(ctx, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, open)
+}
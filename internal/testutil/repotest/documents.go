@@ -0,0 +1,102 @@
+// internal/testutil/repotest/documents.go
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var documentTests = []contractTest{
+	{"DocumentCRUD", testDocumentCRUD},
+	{"CreateDocumentConstraints", testCreateDocumentConstraints},
+}
+
+// document membangun metadata dokumen lampiran absensi dengan storage key tertentu.
+func document(ownerID, attendanceID int, storageKey string) *models.Document {
+	return &models.Document{
+		OwnerUserID: ownerID, SubjectType: models.DocumentSubjectAttendance, SubjectID: attendanceID,
+		FileName: "note.pdf", ContentType: "application/pdf", SizeBytes: 1024,
+		SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", StorageKey: storageKey,
+		ScanStatus: models.DocumentScanClean,
+	}
+}
+
+func testDocumentCRUD(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	docs, err := db.Documents.GetDocumentsBySubject(ctx, models.DocumentSubjectAttendance, missingID)
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+
+	first := document(user.ID, 7, "documents/1")
+	id, err := db.Documents.CreateDocument(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, id, first.ID)
+	assert.False(t, first.CreatedAt.IsZero())
+	second, err := db.Documents.CreateDocument(ctx, document(user.ID, 7, "documents/2"))
+	require.NoError(t, err)
+	_, err = db.Documents.CreateDocument(ctx, document(user.ID, 8, "documents/3"))
+	require.NoError(t, err)
+
+	got, err := db.Documents.GetDocumentByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, first.StorageKey, got.StorageKey)
+	assert.Equal(t, first.SHA256, got.SHA256)
+	assert.Equal(t, int64(1024), got.SizeBytes)
+
+	docs, err = db.Documents.GetDocumentsBySubject(ctx, models.DocumentSubjectAttendance, 7)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, []int{id, second}, []int{docs[0].ID, docs[1].ID})
+
+	require.NoError(t, db.Documents.DeleteDocument(ctx, id))
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "get deleted", call: func() error { _, err := db.Documents.GetDocumentByID(ctx, id); return err }},
+		{name: "delete twice", call: func() error { return db.Documents.DeleteDocument(ctx, id) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), pgx.ErrNoRows)
+		})
+	}
+}
+
+func testCreateDocumentConstraints(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	_, err := db.Documents.CreateDocument(ctx, document(user.ID, 1, "documents/taken"))
+	require.NoError(t, err)
+
+	unknownSubject := document(user.ID, 1, "documents/other")
+	unknownSubject.SubjectType = "invoice"
+	tests := []struct {
+		name    string
+		doc     *models.Document
+		wantErr error
+	}{
+		// Unique violation pada storage_key (23505) dipetakan ke ErrDocumentExists.
+		{name: "storage key reused", doc: document(user.ID, 2, "documents/taken"), wantErr: repository.ErrDocumentExists},
+		{name: "unknown owner", doc: document(missingID, 1, "documents/orphan")},
+		{name: "unknown subject type", doc: unknownSubject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Documents.CreateDocument(ctx, tt.doc)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NotErrorIs(t, err, repository.ErrDocumentExists)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// internal/testutil/repotest/escalations.go
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var escalationTests = []contractTest{
+	{"RecordEscalation", testRecordEscalation},
+}
+
+func testRecordEscalation(t *testing.T, db Repos) {
+	ctx := context.Background()
+	manager := db.CreateUser(t)
+	history, err := db.Escalations.GetEscalations(ctx, models.ApprovalTypeDispute, []int{1, 2})
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	steps := []*models.ApprovalEscalation{
+		{ItemType: models.ApprovalTypeDispute, ItemID: 1, Level: 1, Action: models.EscalationEscalated, EscalatedTo: &manager.ID},
+		{ItemType: models.ApprovalTypeDispute, ItemID: 1, Level: 2, Action: models.EscalationReminded},
+		{ItemType: models.ApprovalTypeDispute, ItemID: 2, Level: 1, Action: models.EscalationEscalated},
+		{ItemType: "other_type", ItemID: 1, Level: 1, Action: models.EscalationEscalated},
+	}
+	for _, e := range steps {
+		recorded, err := db.Escalations.RecordEscalation(ctx, e)
+		require.NoError(t, err)
+		assert.True(t, recorded)
+		assert.NotZero(t, e.ID)
+	}
+
+	// Level yang sama untuk item yang sama tidak dicatat dua kali (unique item & level).
+	duplicate := &models.ApprovalEscalation{ItemType: models.ApprovalTypeDispute, ItemID: 1, Level: 1, Action: models.EscalationReminded}
+	recorded, err := db.Escalations.RecordEscalation(ctx, duplicate)
+	require.NoError(t, err)
+	assert.False(t, recorded)
+	assert.Zero(t, duplicate.ID)
+
+	unknown := missingID
+	tests := []struct {
+		name string
+		e    *models.ApprovalEscalation
+	}{
+		{name: "unknown escalation target", e: &models.ApprovalEscalation{ItemType: models.ApprovalTypeDispute, ItemID: 3, Level: 1, Action: models.EscalationEscalated, EscalatedTo: &unknown}},
+		{name: "unknown action", e: &models.ApprovalEscalation{ItemType: models.ApprovalTypeDispute, ItemID: 3, Level: 1, Action: "ignored"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Escalations.RecordEscalation(ctx, tt.e)
+			assert.Error(t, err)
+		})
+	}
+
+	history, err = db.Escalations.GetEscalations(ctx, models.ApprovalTypeDispute, []int{1, 2, 3})
+	require.NoError(t, err)
+	require.Len(t, history, 2, "items without escalations are absent")
+	require.Len(t, history[1], 2)
+	assert.Equal(t, []int{1, 2}, []int{history[1][0].Level, history[1][1].Level}, "oldest first")
+	assert.Equal(t, &manager.ID, history[1][0].EscalatedTo)
+	require.Len(t, history[2], 1)
+}
@@ -0,0 +1,79 @@
+// internal/testutil/repotest/jobs.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jobTests = []contractTest{
+	{"JobRuns", testJobRuns},
+}
+
+func testJobRuns(t *testing.T, db Repos) {
+	ctx := context.Background()
+	jobs, err := db.Jobs.GetJobs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	require.NoError(t, db.Jobs.RegisterJobs(ctx, []models.JobDefinition{{Name: "sync", Interval: time.Hour}, {Name: "backup", Interval: 24 * time.Hour}}))
+	// Registrasi ulang hanya memperbarui interval; interval di bawah satu detik dibulatkan ke satu detik.
+	require.NoError(t, db.Jobs.RegisterJobs(ctx, []models.JobDefinition{{Name: "sync", Interval: time.Millisecond}}))
+	jobs, err = db.Jobs.GetJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, []string{"backup", "sync"}, []string{jobs[0].Name, jobs[1].Name}, "ordered by name")
+	assert.Equal(t, 1, jobs[1].IntervalSeconds)
+	assert.Nil(t, jobs[0].LastStatus)
+
+	tests := []struct {
+		name    string
+		job     string
+		trigger string
+		force   bool
+		want    bool
+	}{
+		{name: "new job is due", job: "backup", trigger: models.JobTriggerSchedule, want: true},
+		{name: "not due after starting", job: "backup", trigger: models.JobTriggerSchedule},
+		{name: "forced run", job: "backup", trigger: models.JobTriggerManual, force: true, want: true},
+		{name: "unknown job", job: "missing", trigger: models.JobTriggerManual, force: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started, err := db.Jobs.StartJobRun(ctx, tt.job, tt.trigger, tt.force)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, started)
+		})
+	}
+
+	runErr := "storage unavailable"
+	require.NoError(t, db.Jobs.FinishJobRun(ctx, "backup", 0, &runErr, 1500*time.Millisecond))
+	started, err := db.Jobs.StartJobRun(ctx, "backup", models.JobTriggerManual, true)
+	require.NoError(t, err)
+	require.True(t, started)
+	require.NoError(t, db.Jobs.FinishJobRun(ctx, "backup", 42, nil, 2*time.Second))
+	require.NoError(t, db.Jobs.FinishJobRun(ctx, "missing", 1, nil, time.Second), "unknown job is ignored")
+
+	jobs, err = db.Jobs.GetJobs(ctx)
+	require.NoError(t, err)
+	backup := jobs[0]
+	require.NotNil(t, backup.LastStatus)
+	assert.Equal(t, models.JobStatusSucceeded, *backup.LastStatus)
+	require.NotNil(t, backup.LastTrigger)
+	assert.Equal(t, models.JobTriggerManual, *backup.LastTrigger)
+	require.NotNil(t, backup.LastResult)
+	assert.Equal(t, 42, *backup.LastResult)
+	assert.Nil(t, backup.LastError)
+	require.NotNil(t, backup.LastDurationMs)
+	assert.Equal(t, int64(2000), *backup.LastDurationMs)
+	assert.Equal(t, 2, backup.RunCount)
+	assert.Equal(t, 1, backup.FailureCount)
+	assert.True(t, backup.NextRunAt.After(time.Now().Add(23*time.Hour)), "next run advanced by one interval")
+	assert.NotNil(t, backup.LastStartedAt)
+	assert.NotNil(t, backup.LastFinishedAt)
+}
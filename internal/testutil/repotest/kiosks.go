@@ -0,0 +1,102 @@
+// internal/testutil/repotest/kiosks.go
+package repotest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var kioskTests = []contractTest{
+	{"KioskLifecycle", testKioskLifecycle},
+	{"KioskAlreadyEnrolled", testKioskAlreadyEnrolled},
+}
+
+// kiosk membangun perangkat kiosk milik userID dengan hash fingerprint dari satu karakter berulang.
+func kiosk(userID int, fingerprint string) *models.KioskDevice {
+	return &models.KioskDevice{UserID: userID, Name: "Front desk", FingerprintHash: strings.Repeat(fingerprint, 64)}
+}
+
+func testKioskLifecycle(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	devices, err := db.Kiosks.GetKioskDevicesByUser(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, devices)
+
+	first := kiosk(user.ID, "a")
+	require.NoError(t, db.Kiosks.CreateKioskDevice(ctx, first))
+	assert.NotZero(t, first.ID)
+	assert.False(t, first.CreatedAt.IsZero())
+	second := kiosk(user.ID, "b")
+	require.NoError(t, db.Kiosks.CreateKioskDevice(ctx, second))
+	assert.Error(t, db.Kiosks.CreateKioskDevice(ctx, kiosk(missingID, "c")), "unknown user")
+
+	require.NoError(t, db.Kiosks.TouchKioskDevice(ctx, first.ID))
+	got, err := db.Kiosks.GetKioskDevice(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.FingerprintHash, got.FingerprintHash)
+	assert.NotNil(t, got.LastSeenAt)
+
+	devices, err = db.Kiosks.GetKioskDevicesByUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+	assert.Equal(t, second.ID, devices[0].ID, "newest first")
+
+	revoked, err := db.Kiosks.RevokeKioskDevice(ctx, first.ID, &user.ID, user.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, revoked.RevokedAt)
+	assert.Equal(t, &user.ID, revoked.RevokedBy)
+	// Admin (ownerID nil) bisa mencabut perangkat siapa pun.
+	_, err = db.Kiosks.RevokeKioskDevice(ctx, second.ID, nil, admin.ID)
+	require.NoError(t, err)
+
+	third := kiosk(user.ID, "c")
+	require.NoError(t, db.Kiosks.CreateKioskDevice(ctx, third))
+	tests := []struct {
+		name    string
+		id      int
+		ownerID *int
+	}{
+		{name: "device of another user", id: third.ID, ownerID: &other.ID},
+		{name: "already revoked", id: first.ID, ownerID: &user.ID},
+		{name: "unknown device", id: missingID},
+	}
+	for _, tt := range tests {
+		t.Run("revoke "+tt.name, func(t *testing.T) {
+			_, err := db.Kiosks.RevokeKioskDevice(ctx, tt.id, tt.ownerID, admin.ID)
+			assert.ErrorIs(t, err, pgx.ErrNoRows)
+		})
+	}
+
+	got, err = db.Kiosks.GetKioskDevice(ctx, first.ID)
+	require.NoError(t, err, "revoked devices are still readable")
+	assert.NotNil(t, got.RevokedAt)
+	_, err = db.Kiosks.GetKioskDevice(ctx, missingID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func testKioskAlreadyEnrolled(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	device := kiosk(user.ID, "a")
+	require.NoError(t, db.Kiosks.CreateKioskDevice(ctx, device))
+
+	// Unique index parsial fingerprint aktif per user (23505) dipetakan ke ErrKioskAlreadyEnrolled.
+	assert.ErrorIs(t, db.Kiosks.CreateKioskDevice(ctx, kiosk(user.ID, "a")), repository.ErrKioskAlreadyEnrolled)
+	require.NoError(t, db.Kiosks.CreateKioskDevice(ctx, kiosk(other.ID, "a")), "same fingerprint for another user")
+
+	_, err := db.Kiosks.RevokeKioskDevice(ctx, device.ID, &user.ID, user.ID)
+	require.NoError(t, err)
+	require.NoError(t, db.Kiosks.CreateKioskDevice(ctx, kiosk(user.ID, "a")), "re-enroll after revoking")
+}
@@ -0,0 +1,142 @@
+// internal/testutil/repotest/labor.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var laborTests = []contractTest{
+	{"HoursReports", testHoursReports},
+	{"HourlyRates", testHourlyRates},
+}
+
+func testHoursReports(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	rate := 10.0
+	require.NoError(t, db.Labor.SetUserHourlyRate(ctx, user.ID, &rate))
+	shift := db.CreateShift(t) // 09:00-17:00
+	now := time.Now()
+	day := fixtures.Day(now, -1)
+	db.CreateSchedule(t, user.ID, shift.ID, day)
+	checkIn := time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, time.Local)
+	id := db.CheckIn(t, user.ID, checkIn)
+	checkOut := checkIn.Add(6*time.Hour + 30*time.Minute)
+	require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, checkOut, nil))
+	start, end := fixtures.Day(now, -3), fixtures.Day(now, 1)
+
+	modes, err := db.Attendances.GetModeHours(ctx, start, end, nil)
+	require.NoError(t, err)
+	require.Len(t, modes, 1)
+	assert.Equal(t, models.AttendanceModeOnsite, modes[0].Mode)
+	assert.InDelta(t, 6.5, modes[0].TotalHours, 0.001)
+
+	projects, err := db.Attendances.GetProjectHours(ctx, start, end, &user.ID)
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Nil(t, projects[0].ProjectID)
+	assert.InDelta(t, 6.5, projects[0].TotalHours, 0.001)
+
+	groups, total, err := db.Labor.GetLaborCost(ctx, start, end, models.LaborGroupDay)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.NotNil(t, groups[0].Key)
+	assert.Equal(t, day.Format(fixtures.DateLayout), *groups[0].Key)
+	assert.InDelta(t, 8, total.ScheduledHours, 0.001)
+	assert.InDelta(t, 6.5, total.ActualHours, 0.001)
+	assert.InDelta(t, -15, total.CostVariance, 0.001)
+
+	headcounts, err := db.Schedules.GetShiftHeadcounts(ctx, start, end)
+	require.NoError(t, err)
+	require.Len(t, headcounts, 1)
+	assert.Equal(t, models.ShiftHeadcount{Date: day.Format(fixtures.DateLayout), ShiftID: shift.ID, ShiftName: shift.Name, Scheduled: 1, Attended: 1}, headcounts[0])
+
+	// Slot dihitung di UTC (nama zona yang dikenal kedua driver); sesi 09:00-15:30 lokal.
+	buckets, err := db.Attendances.GetOccupancy(ctx, day, day, models.OccupancyGranularityHour, "UTC", nil)
+	require.NoError(t, err)
+	assert.Len(t, buckets, 24*3)
+	present, want := 0, 0
+	for _, b := range buckets {
+		present += b.Headcount
+	}
+	for h := 0; h < 24; h++ {
+		slot := day.Add(time.Duration(h) * time.Hour)
+		if slot.Before(checkOut) && slot.Add(time.Hour).After(checkIn) {
+			want++
+		}
+	}
+	assert.Equal(t, want, present)
+}
+
+func testHourlyRates(t *testing.T, db Repos) {
+	ctx := context.Background()
+	userRate, roleRate := 20.0, 12.5
+	rated := db.CreateUser(t)
+	fallback := db.CreateUser(t)
+	unrated := db.CreateUser(t, fixtures.AsAdmin)
+	require.NoError(t, db.Labor.SetUserHourlyRate(ctx, rated.ID, &userRate))
+	require.NoError(t, db.Labor.SetRoleHourlyRate(ctx, fixtures.RoleEmployeeID, &roleRate))
+	require.NoError(t, db.Labor.SetUserHourlyRate(ctx, fallback.ID, nil), "clear a rate that was never set")
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "unknown user", call: func() error { return db.Labor.SetUserHourlyRate(ctx, missingID, &userRate) }},
+		{name: "unknown role", call: func() error { return db.Labor.SetRoleHourlyRate(ctx, missingID, &roleRate) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), pgx.ErrNoRows)
+		})
+	}
+
+	now := time.Now()
+	day := fixtures.Day(now, -1)
+	checkIn := time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, time.Local)
+	for _, user := range []*models.User{rated, fallback, unrated} {
+		id := db.CheckIn(t, user.ID, checkIn)
+		require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, checkIn.Add(2*time.Hour), nil))
+	}
+	start, end := fixtures.Day(now, -3), fixtures.Day(now, 1)
+
+	groups, total, err := db.Labor.GetLaborCost(ctx, start, end, models.LaborGroupRole)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, 3, total.Users)
+	assert.InDelta(t, 6, total.ActualHours, 0.001)
+	assert.InDelta(t, 2*userRate+2*roleRate, total.ActualCost, 0.001, "user rate overrides the role rate")
+	assert.InDelta(t, 2, total.UnratedActualHours, 0.001, "admin role has no rate")
+	for _, g := range groups {
+		require.NotNil(t, g.ID)
+		switch *g.ID {
+		case fixtures.RoleEmployeeID:
+			assert.Equal(t, 2, g.Users)
+			assert.InDelta(t, 2*userRate+2*roleRate, g.ActualCost, 0.001)
+		case fixtures.RoleAdminID:
+			assert.Equal(t, 1, g.Users)
+			assert.Zero(t, g.ActualCost)
+		default:
+			t.Errorf("unexpected role group %d", *g.ID)
+		}
+	}
+
+	require.NoError(t, db.Labor.SetRoleHourlyRate(ctx, fixtures.RoleEmployeeID, nil))
+	_, total, err = db.Labor.GetLaborCost(ctx, start, end, models.LaborGroupRole)
+	require.NoError(t, err)
+	assert.InDelta(t, 2*userRate, total.ActualCost, 0.001, "cleared role rate")
+
+	groups, total, err = db.Labor.GetLaborCost(ctx, fixtures.Day(now, -20), fixtures.Day(now, -10), models.LaborGroupDay)
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+	require.NotNil(t, total, "total row is always returned")
+	assert.Zero(t, total.ActualHours)
+}
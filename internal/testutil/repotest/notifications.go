@@ -0,0 +1,105 @@
+// internal/testutil/repotest/notifications.go
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var notificationTests = []contractTest{
+	{"NotificationInbox", testNotificationInbox},
+	{"NotificationsPagination", testNotificationsPagination},
+}
+
+// notify menambahkan notifikasi bertipe jadwal ke inbox user dan mengembalikan ID-nya.
+func notify(t *testing.T, db Repos, userID int) int {
+	t.Helper()
+	id, err := db.Notifications.CreateNotification(context.Background(), &models.Notification{
+		UserID: userID, Type: models.NotificationScheduleChanged, Title: "Schedule changed", Body: "See your schedule",
+		Data: map[string]string{"schedule_id": "1"},
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func testNotificationInbox(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	first := notify(t, db, user.ID)
+	notify(t, db, user.ID)
+	foreign := notify(t, db, other.ID)
+
+	_, err := db.Notifications.CreateNotification(ctx, &models.Notification{UserID: missingID, Type: models.NotificationScheduleChanged, Title: "x", Body: "x"})
+	assert.Error(t, err, "unknown user")
+
+	unread, err := db.Notifications.CountUnreadNotifications(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, unread)
+
+	read, err := db.Notifications.MarkNotificationRead(ctx, user.ID, first)
+	require.NoError(t, err)
+	require.NotNil(t, read.ReadAt)
+	assert.Equal(t, map[string]string{"schedule_id": "1"}, read.Data)
+	again, err := db.Notifications.MarkNotificationRead(ctx, user.ID, first)
+	require.NoError(t, err)
+	assert.True(t, read.ReadAt.Equal(*again.ReadAt), "first read time is kept")
+
+	for _, tt := range []struct {
+		name string
+		id   int
+	}{
+		{name: "notification of another user", id: foreign},
+		{name: "unknown notification", id: missingID},
+	} {
+		t.Run("mark "+tt.name, func(t *testing.T) {
+			_, err := db.Notifications.MarkNotificationRead(ctx, user.ID, tt.id)
+			assert.ErrorIs(t, err, pgx.ErrNoRows)
+		})
+	}
+
+	items, total, err := db.Notifications.GetNotificationsByUser(ctx, user.ID, true, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, items, 1)
+	assert.Nil(t, items[0].ReadAt)
+
+	marked, err := db.Notifications.MarkAllNotificationsRead(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, marked)
+	marked, err = db.Notifications.MarkAllNotificationsRead(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Zero(t, marked)
+
+	for userID, want := range map[int]int{user.ID: 0, other.ID: 1} {
+		unread, err := db.Notifications.CountUnreadNotifications(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, want, unread)
+	}
+}
+
+func testNotificationsPagination(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	list := func(page, limit int) ([]models.Notification, int, error) {
+		return db.Notifications.GetNotificationsByUser(ctx, user.ID, false, page, limit)
+	}
+	checkEmptyPage(t, list)
+
+	var ids []int
+	for range 3 {
+		ids = append(ids, notify(t, db, user.ID))
+	}
+	notify(t, db, db.CreateUser(t).ID)
+	checkPagination(t, 3, list, func(n models.Notification) int { return n.ID })
+
+	items, _, err := list(1, 10)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, ids[2], items[0].ID, "newest first")
+}
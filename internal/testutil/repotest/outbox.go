@@ -0,0 +1,119 @@
+// internal/testutil/repotest/outbox.go
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var outboxTests = []contractTest{
+	{"ProcessDueOutboxMessages", testProcessDueOutboxMessages},
+	{"DeadOutboxMessages", testDeadOutboxMessages},
+}
+
+func testProcessDueOutboxMessages(t *testing.T, db Repos) {
+	ctx := context.Background()
+	require.NoError(t, db.Outbox.EnqueueOutboxMessages(ctx, []models.OutboxMessage{
+		{Destination: "webhook", EventName: "a", Payload: []byte(`{"n":1}`)},
+		{Destination: "webhook", EventName: "b", Payload: []byte(`{"n":2}`)},
+	}))
+	backoff := func(int) time.Duration { return time.Hour }
+	deliver := func(_ context.Context, msg models.OutboxMessage) error {
+		if msg.EventName == "b" {
+			return errors.New("endpoint unavailable")
+		}
+		return nil
+	}
+
+	delivered, failed, err := db.Outbox.ProcessDueOutboxMessages(ctx, 10, 1, backoff, deliver)
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, 1, failed)
+
+	dead, total, err := db.Outbox.GetDeadOutboxMessages(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, dead, 1)
+	assert.JSONEq(t, `{"n":2}`, string(dead[0].Payload))
+
+	// Pesan yang dijadwalkan ulang belum jatuh tempo.
+	_, err = db.Outbox.RetryOutboxMessage(ctx, dead[0].ID)
+	require.NoError(t, err)
+	delivered, failed, err = db.Outbox.ProcessDueOutboxMessages(ctx, 10, 5, backoff, deliver)
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Equal(t, 1, failed)
+	delivered, failed, err = db.Outbox.ProcessDueOutboxMessages(ctx, 10, 5, backoff, deliver)
+	require.NoError(t, err)
+	assert.Zero(t, delivered+failed)
+}
+
+func testDeadOutboxMessages(t *testing.T, db Repos) {
+	ctx := context.Background()
+	list := func(page, limit int) ([]models.OutboxMessage, int, error) {
+		return db.Outbox.GetDeadOutboxMessages(ctx, page, limit)
+	}
+	checkEmptyPage(t, list)
+
+	require.NoError(t, db.Outbox.EnqueueOutboxMessages(ctx, nil), "nothing to enqueue")
+	require.NoError(t, db.Outbox.EnqueueOutboxMessages(ctx, []models.OutboxMessage{
+		{Destination: "webhook", EventName: "fail", Payload: []byte(`{"n":1}`)},
+		{Destination: "webhook", EventName: "fail", Payload: []byte(`{"n":2}`)},
+		{Destination: "webhook", EventName: "fail", Payload: []byte(`{"n":3}`)},
+		{Destination: "webhook", EventName: "ok", Payload: []byte(`{"n":4}`)},
+	}))
+	deliver := func(_ context.Context, msg models.OutboxMessage) error {
+		if msg.EventName == "fail" {
+			return errors.New("endpoint unavailable")
+		}
+		return nil
+	}
+	delivered, failed, err := db.Outbox.ProcessDueOutboxMessages(ctx, 10, 1, func(int) time.Duration { return time.Hour }, deliver)
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, 3, failed)
+
+	checkPagination(t, 3, list, func(m models.OutboxMessage) int64 { return m.ID })
+	dead, _, err := list(1, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 3)
+	assert.Equal(t, 1, dead[0].Attempts)
+	require.NotNil(t, dead[0].LastError)
+	assert.Contains(t, *dead[0].LastError, "endpoint unavailable")
+	assert.NotNil(t, dead[0].DeadAt)
+
+	retried, err := db.Outbox.RetryOutboxMessage(ctx, dead[0].ID)
+	require.NoError(t, err)
+	assert.Zero(t, retried.Attempts)
+	assert.Nil(t, retried.DeadAt)
+
+	tests := []struct {
+		name    string
+		id      int64
+		wantErr error
+	}{
+		{name: "retry queued message", id: retried.ID, wantErr: repository.ErrOutboxMessageNotDead},
+		{name: "retry unknown message", id: missingID, wantErr: pgx.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Outbox.RetryOutboxMessage(ctx, tt.id)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	deleted, err := db.Outbox.DeleteDeliveredOutboxMessages(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, deleted, "delivered after the cutoff")
+	deleted, err = db.Outbox.DeleteDeliveredOutboxMessages(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted, "dead letters are kept")
+}
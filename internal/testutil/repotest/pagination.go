@@ -0,0 +1,65 @@
+// internal/testutil/repotest/pagination.go
+package repotest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pageFunc adalah query list paginated yang diuji checkPagination (mengembalikan item & total).
+type pageFunc[T any] func(page, limit int) ([]T, int, error)
+
+// checkEmptyPage memastikan list pada tabel tanpa baris yang cocok mengembalikan halaman
+// kosong dengan total 0 (bukan error).
+func checkEmptyPage[T any](t *testing.T, list pageFunc[T]) {
+	t.Helper()
+	items, total, err := list(1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	assert.Zero(t, total)
+}
+
+// checkPagination menguji batas LIMIT/OFFSET list yang sudah berisi total baris (minimal 3):
+// halaman penuh, halaman terakhir yang terisi sebagian, halaman sesudah data habis, page < 1
+// (diperlakukan sebagai halaman pertama), dan limit lebih besar dari total. key mengembalikan
+// identitas item untuk memastikan halaman berurutan tidak tumpang tindih.
+func checkPagination[T any, K comparable](t *testing.T, total int, list pageFunc[T], key func(T) K) {
+	t.Helper()
+	require.GreaterOrEqual(t, total, 3, "checkPagination needs at least 3 rows")
+
+	tests := []struct {
+		name        string
+		page, limit int
+		wantLen     int
+	}{
+		{name: "full page", page: 1, limit: 2, wantLen: 2},
+		{name: "last partial page", page: (total + 1) / 2, limit: 2, wantLen: 2 - total%2},
+		{name: "past the last page", page: total + 1, limit: 1, wantLen: 0},
+		{name: "page zero is the first page", page: 0, limit: 1, wantLen: 1},
+		{name: "negative page is the first page", page: -1, limit: 1, wantLen: 1},
+		{name: "limit above total", page: 1, limit: total + 5, wantLen: total},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, count, err := list(tt.page, tt.limit)
+			require.NoError(t, err)
+			assert.Equal(t, total, count)
+			assert.Len(t, items, tt.wantLen)
+		})
+	}
+
+	t.Run("pages do not overlap", func(t *testing.T) {
+		seen := make(map[K]bool, total)
+		for page := 1; page <= total; page++ {
+			items, _, err := list(page, 1)
+			require.NoError(t, err)
+			require.Len(t, items, 1)
+			k := key(items[0])
+			assert.False(t, seen[k], "item %v returned on more than one page", k)
+			seen[k] = true
+		}
+		assert.Len(t, seen, total)
+	})
+}
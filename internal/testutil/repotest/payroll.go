@@ -0,0 +1,146 @@
+// internal/testutil/repotest/payroll.go
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var payrollTests = []contractTest{
+	{"PayrollPeriodLocksAttendance", testPayrollPeriodLocksAttendance},
+	{"PayrollPeriodLifecycle", testPayrollPeriodLifecycle},
+	{"PayrollPeriodsPagination", testPayrollPeriodsPagination},
+}
+
+func testPayrollPeriodLocksAttendance(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	now := time.Now()
+	period := &models.PayrollPeriod{
+		StartDate: fixtures.Day(now, -10).Format(fixtures.DateLayout),
+		EndDate:   fixtures.Day(now, -5).Format(fixtures.DateLayout),
+	}
+	id, err := db.Payroll.CreatePayrollPeriod(ctx, period)
+	require.NoError(t, err)
+
+	_, err = db.Payroll.CreatePayrollPeriod(ctx, &models.PayrollPeriod{
+		StartDate: fixtures.Day(now, -6).Format(fixtures.DateLayout),
+		EndDate:   fixtures.Day(now, -1).Format(fixtures.DateLayout),
+	})
+	assert.ErrorIs(t, err, repository.ErrPayrollPeriodOverlap)
+
+	_, err = db.Payroll.ClosePayrollPeriod(ctx, id, admin.ID)
+	require.NoError(t, err)
+	inPeriod := fixtures.Day(now, -7).Add(12 * time.Hour)
+	_, err = db.Attendances.CreateCheckIn(ctx, user.ID, inPeriod, nil, nil, nil, models.AttendanceModeOnsite)
+	var closed *repository.PayrollPeriodClosedError
+	require.True(t, errors.As(err, &closed), "got %v", err)
+	assert.Equal(t, id, closed.Period.ID)
+
+	db.CheckIn(t, user.ID, now.Add(-time.Hour))
+}
+
+func testPayrollPeriodLifecycle(t *testing.T, db Repos) {
+	ctx := context.Background()
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	now := time.Now()
+	start, end := fixtures.Day(now, -30).Format(fixtures.DateLayout), fixtures.Day(now, -16).Format(fixtures.DateLayout)
+	id, err := db.Payroll.CreatePayrollPeriod(ctx, &models.PayrollPeriod{StartDate: start, EndDate: end, CreatedBy: &admin.ID})
+	require.NoError(t, err)
+
+	got, err := db.Payroll.GetPayrollPeriodByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, start, got.StartDate)
+	assert.Equal(t, end, got.EndDate)
+	assert.Equal(t, models.PayrollPeriodOpen, got.Status)
+	assert.Equal(t, &admin.ID, got.CreatedBy)
+	assert.Nil(t, got.ClosedAt)
+
+	closed, err := db.Payroll.ClosePayrollPeriod(ctx, id, admin.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PayrollPeriodClosed, closed.Status)
+	assert.Equal(t, &admin.ID, closed.ClosedBy)
+	assert.NotNil(t, closed.ClosedAt)
+
+	reopened, err := db.Payroll.ReopenPayrollPeriod(ctx, id, admin.ID, "late timesheet")
+	require.NoError(t, err)
+	assert.Equal(t, models.PayrollPeriodOpen, reopened.Status)
+	assert.Equal(t, &admin.ID, reopened.ReopenedBy)
+	require.NotNil(t, reopened.ReopenReason)
+	assert.Equal(t, "late timesheet", *reopened.ReopenReason)
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{name: "get unknown period", call: func() error { _, err := db.Payroll.GetPayrollPeriodByID(ctx, missingID); return err }, wantErr: pgx.ErrNoRows},
+		{name: "close unknown period", call: func() error { _, err := db.Payroll.ClosePayrollPeriod(ctx, missingID, admin.ID); return err }, wantErr: pgx.ErrNoRows},
+		{name: "reopen unknown period", call: func() error {
+			_, err := db.Payroll.ReopenPayrollPeriod(ctx, missingID, admin.ID, "reason")
+			return err
+		}, wantErr: pgx.ErrNoRows},
+		{name: "reopen open period", call: func() error {
+			_, err := db.Payroll.ReopenPayrollPeriod(ctx, id, admin.ID, "again")
+			return err
+		}, wantErr: repository.ErrPayrollPeriodStatus},
+		{name: "overlapping period", call: func() error {
+			_, err := db.Payroll.CreatePayrollPeriod(ctx, &models.PayrollPeriod{StartDate: end, EndDate: fixtures.Day(now, -10).Format(fixtures.DateLayout)})
+			return err
+		}, wantErr: repository.ErrPayrollPeriodOverlap},
+		{name: "end before start", call: func() error {
+			_, err := db.Payroll.CreatePayrollPeriod(ctx, &models.PayrollPeriod{StartDate: fixtures.Day(now, -5).Format(fixtures.DateLayout), EndDate: fixtures.Day(now, -8).Format(fixtures.DateLayout)})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	_, err = db.Payroll.ClosePayrollPeriod(ctx, id, admin.ID)
+	require.NoError(t, err)
+	_, err = db.Payroll.ClosePayrollPeriod(ctx, id, admin.ID)
+	assert.ErrorIs(t, err, repository.ErrPayrollPeriodStatus, "close a closed period")
+}
+
+func testPayrollPeriodsPagination(t *testing.T, db Repos) {
+	ctx := context.Background()
+	list := func(page, limit int) ([]models.PayrollPeriod, int, error) {
+		return db.Payroll.GetAllPayrollPeriods(ctx, page, limit)
+	}
+	checkEmptyPage(t, list)
+
+	now := time.Now()
+	var ids []int
+	for i := range 3 {
+		id, err := db.Payroll.CreatePayrollPeriod(ctx, &models.PayrollPeriod{
+			StartDate: fixtures.Day(now, -30+i*7).Format(fixtures.DateLayout),
+			EndDate:   fixtures.Day(now, -24+i*7).Format(fixtures.DateLayout),
+		})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	checkPagination(t, 3, list, func(p models.PayrollPeriod) int { return p.ID })
+
+	periods, _, err := list(1, 10)
+	require.NoError(t, err)
+	require.Len(t, periods, 3)
+	assert.Equal(t, ids[2], periods[0].ID, "newest period first")
+	assert.Equal(t, ids[0], periods[2].ID)
+}
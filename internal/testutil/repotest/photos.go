@@ -0,0 +1,117 @@
+// internal/testutil/repotest/photos.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var photoTests = []contractTest{
+	{"PhotoLifecycle", testPhotoLifecycle},
+	{"PhotoQueues", testPhotoQueues},
+}
+
+// attendancePhoto mencatat check-in user dan upload foto mentahnya (status pending).
+func attendancePhoto(t *testing.T, db Repos, at time.Time) *models.AttendancePhoto {
+	t.Helper()
+	user := db.CreateUser(t)
+	key := "incoming/" + user.Username
+	photo := &models.AttendancePhoto{AttendanceID: db.CheckIn(t, user.ID, at), UserID: user.ID, IncomingKey: &key}
+	require.NoError(t, db.Photos.CreateAttendancePhoto(context.Background(), photo))
+	return photo
+}
+
+func testPhotoLifecycle(t *testing.T, db Repos) {
+	ctx := context.Background()
+	photo := attendancePhoto(t, db, time.Now().Add(-time.Hour))
+	assert.NotZero(t, photo.ID)
+	assert.Equal(t, models.AttendancePhotoPending, photo.Status)
+	assert.False(t, photo.CapturedAt.IsZero())
+
+	duplicate := &models.AttendancePhoto{AttendanceID: photo.AttendanceID, UserID: photo.UserID}
+	assert.Error(t, db.Photos.CreateAttendancePhoto(ctx, duplicate), "one photo per attendance")
+	assert.Error(t, db.Photos.CreateAttendancePhoto(ctx, &models.AttendancePhoto{AttendanceID: missingID, UserID: photo.UserID}), "unknown attendance")
+
+	require.NoError(t, db.Photos.MarkAttendancePhotoProcessed(ctx, photo.ID, "original/1", "thumb/1", 640, 480))
+	got, err := db.Photos.GetAttendancePhoto(ctx, photo.AttendanceID)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttendancePhotoProcessed, got.Status)
+	assert.Nil(t, got.IncomingKey)
+	require.NotNil(t, got.OriginalKey)
+	assert.Equal(t, "original/1", *got.OriginalKey)
+	require.NotNil(t, got.Width)
+	assert.Equal(t, 640, *got.Width)
+	assert.NotNil(t, got.ProcessedAt)
+
+	require.NoError(t, db.Photos.MarkAttendancePhotoPurged(ctx, photo.ID))
+	got, err = db.Photos.GetAttendancePhotoByID(ctx, photo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttendancePhotoPurged, got.Status)
+	assert.Nil(t, got.OriginalKey)
+	assert.Nil(t, got.ThumbnailKey)
+	assert.NotNil(t, got.PurgedAt)
+
+	failed := attendancePhoto(t, db, time.Now().Add(-2*time.Hour))
+	require.NoError(t, db.Photos.MarkAttendancePhotoFailed(ctx, failed.ID, "not an image"))
+	got, err = db.Photos.GetAttendancePhotoByID(ctx, failed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttendancePhotoFailed, got.Status)
+	assert.Nil(t, got.IncomingKey)
+	require.NotNil(t, got.Error)
+	assert.Equal(t, "not an image", *got.Error)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "get by unknown attendance", call: func() error { _, err := db.Photos.GetAttendancePhoto(ctx, missingID); return err }},
+		{name: "get unknown photo", call: func() error { _, err := db.Photos.GetAttendancePhotoByID(ctx, missingID); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), pgx.ErrNoRows)
+		})
+	}
+}
+
+func testPhotoQueues(t *testing.T, db Repos) {
+	ctx := context.Background()
+	pending, err := db.Photos.GetPendingAttendancePhotos(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	var photos []*models.AttendancePhoto
+	for i := range 3 {
+		photos = append(photos, attendancePhoto(t, db, time.Now().Add(-time.Duration(i+1)*time.Hour)))
+	}
+	require.NoError(t, db.Photos.MarkAttendancePhotoProcessed(ctx, photos[2].ID, "original/3", "thumb/3", 10, 10))
+
+	pending, err = db.Photos.GetPendingAttendancePhotos(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, photos[0].ID, pending[0].ID, "oldest first")
+	pending, err = db.Photos.GetPendingAttendancePhotos(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, pending, 1, "limited")
+
+	expired, err := db.Photos.GetExpiredAttendancePhotos(ctx, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired, "captured after the cutoff")
+	expired, err = db.Photos.GetExpiredAttendancePhotos(ctx, time.Now().Add(time.Hour), 2)
+	require.NoError(t, err)
+	require.Len(t, expired, 2, "limited")
+	assert.Equal(t, photos[0].ID, expired[0].ID)
+
+	for _, p := range photos {
+		require.NoError(t, db.Photos.MarkAttendancePhotoPurged(ctx, p.ID))
+	}
+	expired, err = db.Photos.GetExpiredAttendancePhotos(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired, "purged photos are skipped")
+}
@@ -0,0 +1,110 @@
+// internal/testutil/repotest/projects.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var projectTests = []contractTest{
+	{"ProjectCodeTaken", testProjectCodeTaken},
+	{"ProjectCRUD", testProjectCRUD},
+	{"DeleteProjectInUse", testDeleteProjectInUse},
+}
+
+func testProjectCodeTaken(t *testing.T, db Repos) {
+	ctx := context.Background()
+	_, err := db.Projects.CreateProject(ctx, &models.Project{Code: "ALPHA", Name: "Alpha", IsActive: true})
+	require.NoError(t, err)
+	betaID, err := db.Projects.CreateProject(ctx, &models.Project{Code: "BETA", Name: "Beta", IsActive: true})
+	require.NoError(t, err)
+
+	// Unique violation (23505) pada kode dipetakan ke ErrProjectCodeTaken, baik saat create maupun update.
+	_, err = db.Projects.CreateProject(ctx, &models.Project{Code: "ALPHA", Name: "Alpha 2", IsActive: true})
+	assert.ErrorIs(t, err, repository.ErrProjectCodeTaken)
+	err = db.Projects.UpdateProject(ctx, &models.Project{ID: betaID, Code: "ALPHA", Name: "Beta", IsActive: true})
+	assert.ErrorIs(t, err, repository.ErrProjectCodeTaken)
+}
+
+func testProjectCRUD(t *testing.T, db Repos) {
+	ctx := context.Background()
+	projects, err := db.Projects.GetAllProjects(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, projects)
+
+	costCenter := "CC-100"
+	betaID, err := db.Projects.CreateProject(ctx, &models.Project{Code: "BETA", Name: "Beta", CostCenter: &costCenter, IsActive: true})
+	require.NoError(t, err)
+	alphaID := db.CreateProject(t, "ALPHA")
+
+	got, err := db.Projects.GetProjectByID(ctx, betaID)
+	require.NoError(t, err)
+	assert.Equal(t, "BETA", got.Code)
+	assert.Equal(t, &costCenter, got.CostCenter)
+	assert.True(t, got.IsActive)
+
+	got.Name, got.CostCenter, got.IsActive = "Beta Renamed", nil, false
+	require.NoError(t, db.Projects.UpdateProject(ctx, got))
+	updated, err := db.Projects.GetProjectByID(ctx, betaID)
+	require.NoError(t, err)
+	assert.Equal(t, "Beta Renamed", updated.Name)
+	assert.Nil(t, updated.CostCenter)
+	assert.False(t, updated.IsActive)
+
+	tests := []struct {
+		name       string
+		activeOnly bool
+		wantIDs    []int
+	}{
+		{name: "all projects ordered by code", wantIDs: []int{alphaID, betaID}},
+		{name: "active only", activeOnly: true, wantIDs: []int{alphaID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projects, err := db.Projects.GetAllProjects(ctx, tt.activeOnly)
+			require.NoError(t, err)
+			ids := make([]int, 0, len(projects))
+			for _, p := range projects {
+				ids = append(ids, p.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+
+	require.NoError(t, db.Projects.DeleteProject(ctx, betaID))
+	notFound := []struct {
+		name string
+		call func() error
+	}{
+		{name: "get deleted", call: func() error { _, err := db.Projects.GetProjectByID(ctx, betaID); return err }},
+		{name: "update unknown", call: func() error {
+			return db.Projects.UpdateProject(ctx, &models.Project{ID: missingID, Code: "NONE", Name: "None"})
+		}},
+		{name: "delete unknown", call: func() error { return db.Projects.DeleteProject(ctx, missingID) }},
+	}
+	for _, tt := range notFound {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), pgx.ErrNoRows)
+		})
+	}
+}
+
+func testDeleteProjectInUse(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	projectID := db.CreateProject(t, "ALPHA")
+	_, err := db.Attendances.CreateCheckIn(ctx, user.ID, time.Now().Add(-time.Hour), nil, nil, &projectID, models.AttendanceModeOnsite)
+	require.NoError(t, err)
+
+	// Foreign key violation (23503) dari absensi/segmen dipetakan ke ErrProjectInUse.
+	assert.ErrorIs(t, db.Projects.DeleteProject(ctx, projectID), repository.ErrProjectInUse)
+	_, err = db.Projects.GetProjectByID(ctx, projectID)
+	assert.NoError(t, err, "project is kept")
+}
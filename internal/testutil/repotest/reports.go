@@ -0,0 +1,245 @@
+// internal/testutil/repotest/reports.go
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var reportTests = []contractTest{
+	{"ReportExportLifecycle", testReportExportLifecycle},
+	{"ReportExportQueues", testReportExportQueues},
+	{"ReportExportsPagination", testReportExportsPagination},
+	{"ReportSnapshots", testReportSnapshots},
+	{"SnapshotSessions", testSnapshotSessions},
+}
+
+// reportExport menyimpan permintaan ekspor users berstatus pending dengan retensi tertentu.
+func reportExport(t *testing.T, db Repos, requestedBy *int, retention time.Duration) *models.ReportExport {
+	t.Helper()
+	report := &models.ReportExport{ReportType: models.ReportTypeUsers, Format: "csv", RequestedBy: requestedBy, RetentionSeconds: int(retention.Seconds())}
+	require.NoError(t, db.Reports.CreateReportExport(context.Background(), report))
+	return report
+}
+
+// exportResult adalah hasil ekspor selesai dengan storage key tertentu.
+func exportResult(key string) models.ReportExportResult {
+	return models.ReportExportResult{StorageKey: key, FileName: "users.csv", ContentType: "text/csv", SizeBytes: 128, RowCount: 3}
+}
+
+func testReportExportLifecycle(t *testing.T, db Repos) {
+	ctx := context.Background()
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	report := reportExport(t, db, &admin.ID, time.Hour)
+	assert.NotZero(t, report.ID)
+	assert.Equal(t, models.ReportExportPending, report.Status)
+	assert.JSONEq(t, `{}`, string(report.Params), "params default to an empty object")
+	assert.False(t, report.CreatedAt.IsZero())
+
+	done, err := db.Reports.MarkReportExportCompleted(ctx, report.ID, exportResult("exports/1.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportExportCompleted, done.Status)
+	require.NotNil(t, done.StorageKey)
+	assert.Equal(t, "exports/1.csv", *done.StorageKey)
+	require.NotNil(t, done.RowCount)
+	assert.Equal(t, 3, *done.RowCount)
+	require.NotNil(t, done.CompletedAt)
+	require.NotNil(t, done.ExpiresAt)
+	assert.WithinDuration(t, done.CompletedAt.Add(time.Hour), *done.ExpiresAt, time.Second, "expires after the retention")
+
+	require.NoError(t, db.Reports.MarkReportExportPurged(ctx, report.ID))
+	got, err := db.Reports.GetReportExportByID(ctx, report.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportExportPurged, got.Status)
+	assert.Nil(t, got.StorageKey)
+	assert.NotNil(t, got.PurgedAt)
+
+	failed := reportExport(t, db, nil, time.Hour)
+	require.NoError(t, db.Reports.MarkReportExportFailed(ctx, failed.ID, "disk full"))
+	got, err = db.Reports.GetReportExportByID(ctx, failed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportExportFailed, got.Status)
+	require.NotNil(t, got.Error)
+	assert.Equal(t, "disk full", *got.Error)
+
+	// Transisi hanya berlaku dari pending; ekspor yang tidak ada juga pgx.ErrNoRows.
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "complete a failed export", call: func() error {
+			_, err := db.Reports.MarkReportExportCompleted(ctx, failed.ID, exportResult("exports/2.csv"))
+			return err
+		}},
+		{name: "fail a failed export", call: func() error { return db.Reports.MarkReportExportFailed(ctx, failed.ID, "again") }},
+		{name: "complete an unknown export", call: func() error {
+			_, err := db.Reports.MarkReportExportCompleted(ctx, missingID, exportResult("exports/3.csv"))
+			return err
+		}},
+		{name: "purge an unknown export", call: func() error { return db.Reports.MarkReportExportPurged(ctx, missingID) }},
+		{name: "get an unknown export", call: func() error { _, err := db.Reports.GetReportExportByID(ctx, missingID); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), pgx.ErrNoRows)
+		})
+	}
+
+	unknown := missingID
+	err = db.Reports.CreateReportExport(ctx, &models.ReportExport{ReportType: models.ReportTypeUsers, Format: "csv", RequestedBy: &unknown, RetentionSeconds: 60})
+	assert.Error(t, err, "unknown requester")
+}
+
+func testReportExportQueues(t *testing.T, db Repos) {
+	ctx := context.Background()
+	pending, err := db.Reports.GetPendingReportExports(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	var reports []*models.ReportExport
+	for range 3 {
+		reports = append(reports, reportExport(t, db, nil, time.Second))
+	}
+	pending, err = db.Reports.GetPendingReportExports(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, pending, 2, "limited")
+	assert.Equal(t, reports[0].ID, pending[0].ID, "oldest first")
+	assert.Equal(t, reports[1].ID, pending[1].ID)
+
+	long := reportExport(t, db, nil, 24*time.Hour)
+	for _, r := range []*models.ReportExport{reports[0], reports[1], long} {
+		_, err := db.Reports.MarkReportExportCompleted(ctx, r.ID, exportResult(fmt.Sprintf("exports/%d.csv", r.ID)))
+		require.NoError(t, err)
+	}
+	pending, err = db.Reports.GetPendingReportExports(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, reports[2].ID, pending[0].ID)
+
+	expired, err := db.Reports.GetExpiredReportExports(ctx, time.Now().Add(-time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired, "not yet expired")
+	expired, err = db.Reports.GetExpiredReportExports(ctx, time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 2, "pending exports and longer retention are skipped")
+	assert.Equal(t, reports[0].ID, expired[0].ID)
+	expired, err = db.Reports.GetExpiredReportExports(ctx, time.Now().Add(time.Minute), 1)
+	require.NoError(t, err)
+	assert.Len(t, expired, 1, "limited")
+
+	require.NoError(t, db.Reports.MarkReportExportPurged(ctx, reports[0].ID))
+	expired, err = db.Reports.GetExpiredReportExports(ctx, time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1, "purged exports are skipped")
+	assert.Equal(t, reports[1].ID, expired[0].ID)
+}
+
+func testReportExportsPagination(t *testing.T, db Repos) {
+	list := func(page, limit int) ([]models.ReportExport, int, error) {
+		return db.Reports.GetReportExports(context.Background(), page, limit)
+	}
+	checkEmptyPage(t, list)
+
+	var last int
+	for range 5 {
+		last = reportExport(t, db, nil, time.Hour).ID
+	}
+	reports, _, err := list(1, 1)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, last, reports[0].ID, "newest first")
+	checkPagination(t, 5, list, func(r models.ReportExport) int { return r.ID })
+}
+
+func testReportSnapshots(t *testing.T, db Repos) {
+	ctx := context.Background()
+	list := func(page, limit int) ([]models.ReportSnapshot, int, error) {
+		return db.Reports.GetReportSnapshots(ctx, page, limit)
+	}
+	checkEmptyPage(t, list)
+
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	start := fixtures.Day(time.Now(), -14).Format(fixtures.DateLayout)
+	end := fixtures.Day(time.Now(), -1).Format(fixtures.DateLayout)
+	periodID, err := db.Payroll.CreatePayrollPeriod(ctx, &models.PayrollPeriod{StartDate: start, EndDate: end, CreatedBy: &admin.ID})
+	require.NoError(t, err)
+
+	label := "Period close"
+	snapshot := &models.ReportSnapshot{
+		Label: &label, StartDate: start, EndDate: end,
+		PayrollPeriodID: &periodID, Checksum: strings.Repeat("c", 64), CreatedBy: &admin.ID,
+		Rows: []models.ReportSnapshotRow{
+			{UserID: admin.ID, Username: admin.Username, Sessions: 3, DaysWorked: 2, OpenSessions: 1},
+		},
+	}
+	require.NoError(t, db.Reports.CreateReportSnapshot(ctx, snapshot))
+	assert.NotZero(t, snapshot.ID)
+	assert.Equal(t, 1, snapshot.RowCount)
+	assert.False(t, snapshot.CreatedAt.IsZero())
+
+	got, err := db.Reports.GetReportSnapshotByID(ctx, snapshot.ID)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.StartDate, got.StartDate)
+	assert.Equal(t, snapshot.EndDate, got.EndDate)
+	assert.Equal(t, &periodID, got.PayrollPeriodID)
+	assert.Equal(t, snapshot.Checksum, got.Checksum)
+	assert.Equal(t, snapshot.Rows, got.Rows)
+
+	for range 2 {
+		require.NoError(t, db.Reports.CreateReportSnapshot(ctx, &models.ReportSnapshot{
+			StartDate: snapshot.StartDate, EndDate: snapshot.EndDate, Checksum: strings.Repeat("d", 64), Rows: []models.ReportSnapshotRow{},
+		}))
+	}
+	snapshots, _, err := list(1, 10)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3)
+	assert.Equal(t, snapshot.ID, snapshots[2].ID, "newest first")
+	assert.Empty(t, snapshots[2].Rows, "rows are omitted from the list")
+	checkPagination(t, 3, list, func(s models.ReportSnapshot) int { return s.ID })
+
+	// Periode payroll yang tidak ada (FK) dipetakan ke pgx.ErrNoRows.
+	unknown := missingID
+	err = db.Reports.CreateReportSnapshot(ctx, &models.ReportSnapshot{
+		StartDate: snapshot.StartDate, EndDate: snapshot.EndDate, PayrollPeriodID: &unknown, Checksum: strings.Repeat("e", 64),
+	})
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = db.Reports.GetReportSnapshotByID(ctx, missingID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func testSnapshotSessions(t *testing.T, db Repos) {
+	ctx := context.Background()
+	now := time.Now()
+	first := db.CreateUser(t)
+	second := db.CreateUser(t)
+
+	// Check-in siang hari (UTC) agar tanggalnya sama di zona waktu database mana pun.
+	early := db.CheckIn(t, first.ID, fixtures.Day(now, -3).Add(12*time.Hour))
+	require.NoError(t, db.Attendances.UpdateCheckOut(ctx, early, fixtures.Day(now, -3).Add(14*time.Hour), nil))
+	late := db.CheckIn(t, first.ID, fixtures.Day(now, -2).Add(12*time.Hour))
+	other := db.CheckIn(t, second.ID, fixtures.Day(now, -3).Add(11*time.Hour))
+	outside := db.CreateUser(t)
+	db.CheckIn(t, outside.ID, fixtures.Day(now, -5).Add(12*time.Hour))
+
+	sessions, err := db.Reports.GetSnapshotSessions(ctx, fixtures.Day(now, -3), fixtures.Day(now, -2))
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+	assert.Equal(t, []int{early, late, other}, []int{sessions[0].ID, sessions[1].ID, sessions[2].ID}, "by user, then check-in")
+	require.NotNil(t, sessions[0].User)
+	assert.Equal(t, first.Username, sessions[0].User.Username)
+	assert.NotNil(t, sessions[0].CheckOutAt)
+	assert.Nil(t, sessions[1].CheckOutAt, "open session")
+
+	sessions, err = db.Reports.GetSnapshotSessions(ctx, fixtures.Day(now, -30), fixtures.Day(now, -20))
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
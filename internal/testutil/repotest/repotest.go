@@ -0,0 +1,225 @@
+// internal/testutil/repotest/repotest.go
+
+// Package repotest berisi suite kontrak repository: skenario yang sama dijalankan terhadap setiap
+// driver (PostgreSQL lewat pgtest, SQLite lewat sqlitetest), sehingga perilaku yang diandalkan
+// handler (error sentinel, pemetaan constraint, pagination, urutan) dijamin sama di kedua backend.
+//
+// Entry point per driver cukup menyediakan database kosong yang sudah dimigrasi:
+//
+//	func TestRepositoryContract(t *testing.T) {
+//		repotest.RunRepositoryContract(t, func(t *testing.T) repotest.Repos { return pgtest.New(t).Repos })
+//	}
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+)
+
+// Repos adalah repository yang diuji suite. Nama field dipakai juga oleh pgtest.DB dan
+// sqlitetest.DB (yang meng-embed Repos), sehingga skenario sama untuk kedua driver.
+type Repos struct {
+	Tx repository.TxManager
+
+	Users         repository.UserRepository
+	Roles         repository.RoleRepository
+	Shifts        repository.ShiftRepository
+	Schedules     repository.ScheduleRepository
+	Attendances   repository.AttendanceRepository
+	Settings      repository.SettingsRepository
+	Announcements repository.AnnouncementRepository
+	Documents     repository.DocumentRepository
+	Audit         repository.AuditRepository
+	Payroll       repository.PayrollRepository
+	Projects      repository.ProjectRepository
+	SignOffs      repository.SignOffRepository
+	Disputes      repository.DisputeRepository
+	Devices       repository.DeviceRepository
+	Notifications repository.NotificationRepository
+	Outbox        repository.OutboxRepository
+	Delegations   repository.DelegationRepository
+	Escalations   repository.EscalationRepository
+	Labor         repository.LaborRepository
+	Jobs          repository.JobRepository
+	Verifications repository.VerificationRepository
+	Kiosks        repository.KioskRepository
+	Photos        repository.AttendancePhotoRepository
+	Reports       repository.ReportExportRepository
+	DebugCaptures repository.DebugCaptureRepository
+	Backups       repository.BackupRepository
+	AppInstances  repository.AppInstanceRepository
+}
+
+// New menyusun Repos dari kumpulan repository sebuah driver.
+func New(repos repository.Repositories) Repos {
+	return Repos{
+		Tx:            repos.Tx,
+		Users:         repos.Users,
+		Roles:         repos.Roles,
+		Shifts:        repos.Shifts,
+		Schedules:     repos.Schedules,
+		Attendances:   repos.Attendance,
+		Settings:      repos.Settings,
+		Announcements: repos.Announcements,
+		Documents:     repos.Documents,
+		Audit:         repos.Audit,
+		Payroll:       repos.Payroll,
+		Projects:      repos.Projects,
+		SignOffs:      repos.SignOffs,
+		Disputes:      repos.Disputes,
+		Devices:       repos.Devices,
+		Notifications: repos.Notifications,
+		Outbox:        repos.Outbox,
+		Delegations:   repos.Delegations,
+		Escalations:   repos.Escalations,
+		Labor:         repos.Labor,
+		Jobs:          repos.Jobs,
+		Verifications: repos.Verification,
+		Kiosks:        repos.Kiosks,
+		Photos:        repos.AttendancePhotos,
+		Reports:       repos.ReportExports,
+		DebugCaptures: repos.DebugCaptures,
+		Backups:       repos.Backups,
+		AppInstances:  repos.AppInstances,
+	}
+}
+
+// missingID adalah ID yang tidak pernah dipakai baris mana pun di database test (jalur not found & FK).
+const missingID = 999999
+
+// contractTest adalah satu skenario kontrak; run menerima database kosong miliknya sendiri.
+type contractTest struct {
+	name string
+	run  func(t *testing.T, r Repos)
+}
+
+// contractGroups mengelompokkan skenario per interface repository (nama subtest tingkat pertama).
+var contractGroups = []struct {
+	name  string
+	tests []contractTest
+}{
+	{"Users", userTests},
+	{"UserEmployment", userEmploymentTests},
+	{"UserPhone", userPhoneTests},
+	{"Roles", roleTests},
+	{"Shifts", shiftTests},
+	{"Schedules", scheduleTests},
+	{"Attendances", attendanceTests},
+	{"Settings", settingsTests},
+	{"Announcements", announcementTests},
+	{"Documents", documentTests},
+	{"Audit", auditTests},
+	{"Devices", deviceTests},
+	{"Notifications", notificationTests},
+	{"Delegations", delegationTests},
+	{"Escalations", escalationTests},
+	{"Jobs", jobTests},
+	{"Verifications", verificationTests},
+	{"Kiosks", kioskTests},
+	{"Photos", photoTests},
+	{"Payroll", payrollTests},
+	{"Projects", projectTests},
+	{"SignOffs", signOffTests},
+	{"Disputes", disputeTests},
+	{"Labor", laborTests},
+	{"Outbox", outboxTests},
+	{"Backups", backupTests},
+	{"Reports", reportTests},
+	{"DebugCaptures", debugCaptureTests},
+	{"AppInstances", appInstanceTests},
+}
+
+// RunRepositoryContract menjalankan semua skenario kontrak sebagai subtest <Interface>/<skenario>.
+// newRepos dipanggil sekali per skenario dan harus mengembalikan database kosong yang sudah
+// dimigrasi (boleh t.Skip jika database driver tidak tersedia).
+func RunRepositoryContract(t *testing.T, newRepos func(t *testing.T) Repos) {
+	for _, group := range contractGroups {
+		t.Run(group.name, func(t *testing.T) {
+			for _, tt := range group.tests {
+				t.Run(tt.name, func(t *testing.T) {
+					tt.run(t, newRepos(t))
+				})
+			}
+		})
+	}
+}
+
+// --- Seeder (melalui repository, sehingga jalur enkripsi PII ikut teruji) ---
+
+// CreateUser membuat user dari fixtures.UserInput dan mengembalikan record lengkapnya.
+func (r Repos) CreateUser(t testing.TB, opts ...func(*models.RegisterUserInput)) *models.User {
+	t.Helper()
+	ctx := context.Background()
+	input := fixtures.UserInput(opts...)
+	// Hash statis: test repository tidak memverifikasi password.
+	id, err := r.Users.CreateUser(ctx, input, "test-hash")
+	if err != nil {
+		t.Fatalf("repotest: create user %s: %v", input.Username, err)
+	}
+	user, err := r.Users.GetUserByID(ctx, id)
+	if err != nil {
+		t.Fatalf("repotest: reload user %d: %v", id, err)
+	}
+	return user
+}
+
+// CreateShift membuat shift dari fixtures.Shift.
+func (r Repos) CreateShift(t testing.TB, opts ...func(*models.Shift)) *models.Shift {
+	t.Helper()
+	ctx := context.Background()
+	shift := fixtures.Shift(opts...)
+	id, err := r.Shifts.CreateShift(ctx, shift)
+	if err != nil {
+		t.Fatalf("repotest: create shift %s: %v", shift.Name, err)
+	}
+	created, err := r.Shifts.GetShiftByID(ctx, id)
+	if err != nil {
+		t.Fatalf("repotest: reload shift %d: %v", id, err)
+	}
+	return created
+}
+
+// CreateSchedule menjadwalkan user pada shift di tanggal tertentu dan mengembalikan ID jadwal.
+func (r Repos) CreateSchedule(t testing.TB, userID, shiftID int, date time.Time) int {
+	t.Helper()
+	id, err := r.Schedules.CreateSchedule(context.Background(), fixtures.Schedule(userID, shiftID, date))
+	if err != nil {
+		t.Fatalf("repotest: create schedule for user %d on %s: %v", userID, date.Format(fixtures.DateLayout), err)
+	}
+	return id
+}
+
+// CheckIn mencatat check-in user (tanpa tautan jadwal) pada waktu tertentu dan mengembalikan ID absensi.
+func (r Repos) CheckIn(t testing.TB, userID int, at time.Time) int {
+	t.Helper()
+	id, err := r.Attendances.CreateCheckIn(context.Background(), userID, at, nil, nil, nil, models.AttendanceModeOnsite)
+	if err != nil {
+		t.Fatalf("repotest: check in user %d: %v", userID, err)
+	}
+	return id
+}
+
+// CreateProject membuat project aktif dengan kode tertentu dan mengembalikan ID-nya.
+func (r Repos) CreateProject(t testing.TB, code string) int {
+	t.Helper()
+	id, err := r.Projects.CreateProject(context.Background(), &models.Project{Code: code, Name: "Project " + code, IsActive: true})
+	if err != nil {
+		t.Fatalf("repotest: create project %s: %v", code, err)
+	}
+	return id
+}
+
+// DeactivateUser menonaktifkan user dengan menjadikannya kontraktor yang masa aksesnya sudah lewat.
+func (r Repos) DeactivateUser(t testing.TB, userID int) {
+	t.Helper()
+	until := fixtures.Day(time.Now(), -1).Format(fixtures.DateLayout)
+	input := &models.UpdateUserAccessInput{UserType: models.UserTypeContractor, ValidUntil: &until}
+	if err := r.Users.UpdateUserAccess(context.Background(), userID, input, time.Now()); err != nil {
+		t.Fatalf("repotest: deactivate user %d: %v", userID, err)
+	}
+}
@@ -0,0 +1,179 @@
+// internal/testutil/repotest/roles.go
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var roleTests = []contractTest{
+	{"CreateRoleDuplicateName", testCreateRoleDuplicateName},
+	{"CreateRoleUnknownParent", testCreateRoleUnknownParent},
+	{"SetRoleParentRejectsCycle", testSetRoleParentRejectsCycle},
+	{"RoleCRUD", testRoleCRUD},
+	{"DeleteRoleInUse", testDeleteRoleInUse},
+	{"GetInheritedRoles", testGetInheritedRoles},
+}
+
+func testCreateRoleDuplicateName(t *testing.T, db Repos) {
+	ctx := context.Background()
+	role := fixtures.Role()
+	_, err := db.Roles.CreateRole(ctx, role)
+	require.NoError(t, err)
+
+	_, err = db.Roles.CreateRole(ctx, &models.Role{Name: role.Name})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func testCreateRoleUnknownParent(t *testing.T, db Repos) {
+	missing := 999999
+	_, err := db.Roles.CreateRole(context.Background(), fixtures.Role(func(r *models.Role) { r.ParentID = &missing }))
+	assert.ErrorIs(t, err, repository.ErrParentRoleNotFound)
+}
+
+func testSetRoleParentRejectsCycle(t *testing.T, db Repos) {
+	ctx := context.Background()
+	parent := fixtures.Role()
+	parentID, err := db.Roles.CreateRole(ctx, parent)
+	require.NoError(t, err)
+	child := fixtures.Role(func(r *models.Role) { r.ParentID = &parentID })
+	childID, err := db.Roles.CreateRole(ctx, child)
+	require.NoError(t, err)
+
+	inherited, err := db.Roles.GetInheritedRoles(ctx, child.Name)
+	require.NoError(t, err)
+	assert.Equal(t, []string{parent.Name}, inherited)
+
+	_, err = db.Roles.SetRoleParent(ctx, parentID, &childID)
+	assert.ErrorIs(t, err, repository.ErrRoleCycle)
+	_, err = db.Roles.SetRoleParent(ctx, parentID, &parentID)
+	assert.ErrorIs(t, err, repository.ErrRoleCycle)
+
+	updated, err := db.Roles.SetRoleParent(ctx, childID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, updated.ParentID)
+}
+
+func testRoleCRUD(t *testing.T, db Repos) {
+	ctx := context.Background()
+	role := fixtures.Role()
+	id, err := db.Roles.CreateRole(ctx, role)
+	require.NoError(t, err)
+
+	got, err := db.Roles.GetRoleByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, role.Name, got.Name)
+	assert.Nil(t, got.ParentID)
+
+	roles, err := db.Roles.GetAllRoles(ctx)
+	require.NoError(t, err)
+	names := make([]string, 0, len(roles))
+	for _, r := range roles {
+		names = append(names, r.Name)
+	}
+	assert.Subset(t, names, []string{"Admin", "Employee", role.Name}, "seeded roles are listed")
+
+	require.NoError(t, db.Roles.UpdateRole(ctx, &models.Role{ID: id, Name: role.Name + " renamed"}))
+	got, err = db.Roles.GetRoleByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, role.Name+" renamed", got.Name)
+
+	err = db.Roles.UpdateRole(ctx, &models.Role{ID: id, Name: "Admin"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	require.NoError(t, db.Roles.DeleteRole(ctx, id))
+
+	notFound := []struct {
+		name string
+		call func() error
+	}{
+		{name: "get", call: func() error { _, err := db.Roles.GetRoleByID(ctx, id); return err }},
+		{name: "update", call: func() error { return db.Roles.UpdateRole(ctx, &models.Role{ID: id, Name: "Ghost role"}) }},
+		{name: "delete", call: func() error { return db.Roles.DeleteRole(ctx, id) }},
+		{name: "set parent", call: func() error { _, err := db.Roles.SetRoleParent(ctx, id, nil); return err }},
+	}
+	for _, tt := range notFound {
+		t.Run("unknown role "+tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), pgx.ErrNoRows)
+		})
+	}
+}
+
+func testDeleteRoleInUse(t *testing.T, db Repos) {
+	ctx := context.Background()
+	parentID, err := db.Roles.CreateRole(ctx, fixtures.Role())
+	require.NoError(t, err)
+	_, err = db.Roles.CreateRole(ctx, fixtures.Role(func(r *models.Role) { r.ParentID = &parentID }))
+	require.NoError(t, err)
+	db.CreateUser(t)
+
+	tests := []struct {
+		name    string
+		id      int
+		wantMsg string
+	}{
+		{name: "assigned to users", id: fixtures.RoleEmployeeID, wantMsg: "user(s) still assigned"},
+		{name: "inherited by roles", id: parentID, wantMsg: "role(s) still inherit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := db.Roles.DeleteRole(ctx, tt.id)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+			_, err = db.Roles.GetRoleByID(ctx, tt.id)
+			assert.NoError(t, err, "role is kept")
+		})
+	}
+}
+
+func testGetInheritedRoles(t *testing.T, db Repos) {
+	ctx := context.Background()
+	base := fixtures.Role()
+	baseID, err := db.Roles.CreateRole(ctx, base)
+	require.NoError(t, err)
+	mid := fixtures.Role(func(r *models.Role) { r.ParentID = &baseID })
+	midID, err := db.Roles.CreateRole(ctx, mid)
+	require.NoError(t, err)
+	top := fixtures.Role()
+	topID, err := db.Roles.CreateRole(ctx, top)
+	require.NoError(t, err)
+	updated, err := db.Roles.SetRoleParent(ctx, topID, &midID)
+	require.NoError(t, err)
+	require.NotNil(t, updated.ParentID)
+	assert.Equal(t, midID, *updated.ParentID)
+
+	tests := []struct {
+		name string
+		role string
+		want []string
+	}{
+		{name: "chain, nearest first", role: top.Name, want: []string{mid.Name, base.Name}},
+		{name: "root", role: base.Name, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.Roles.GetInheritedRoles(ctx, tt.role)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, got)
+			if len(tt.want) > 0 {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+
+	_, err = db.Roles.GetInheritedRoles(ctx, "No such role")
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	cleared, err := db.Roles.SetRoleParent(ctx, topID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, cleared.ParentID)
+}
@@ -0,0 +1,303 @@
+// internal/testutil/repotest/schedules.go
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var scheduleTests = []contractTest{
+	{"CreateScheduleConflicts", testCreateScheduleConflicts},
+	{"ScheduleDatesAndAttendance", testScheduleDatesAndAttendance},
+	{"ScheduleCRUD", testScheduleCRUD},
+	{"CreateScheduleUnknownReferences", testCreateScheduleUnknownReferences},
+	{"SchedulesPagination", testSchedulesPagination},
+	{"BulkDeleteSchedulesByID", testBulkDeleteSchedulesByID},
+	{"ShiftHeadcounts", testShiftHeadcounts},
+	{"ScheduleChangesSettle", testScheduleChangesSettle},
+}
+
+func testCreateScheduleConflicts(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	morning := db.CreateShift(t, func(s *models.Shift) { s.StartTime, s.EndTime = "08:00:00", "12:00:00" })
+	late := db.CreateShift(t, func(s *models.Shift) { s.StartTime, s.EndTime = "11:00:00", "15:00:00" })
+	evening := db.CreateShift(t, func(s *models.Shift) { s.StartTime, s.EndTime = "18:00:00", "22:00:00" })
+	date := fixtures.Day(time.Now(), 3)
+	db.CreateSchedule(t, user.ID, morning.ID, date)
+
+	t.Run("overlapping shift", func(t *testing.T) {
+		_, err := db.Schedules.CreateSchedule(ctx, fixtures.Schedule(user.ID, late.ID, date))
+		var conflict *repository.ScheduleConflictError
+		require.True(t, errors.As(err, &conflict), "got %v", err)
+		assert.Len(t, conflict.Conflicts, 1)
+	})
+	t.Run("second schedule on the same date", func(t *testing.T) {
+		// Tidak tumpang tindih, tetapi UNIQUE (user_id, date) (23505) tetap menolak.
+		_, err := db.Schedules.CreateSchedule(ctx, fixtures.Schedule(user.ID, evening.ID, date))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already has a schedule on "+date.Format(fixtures.DateLayout))
+	})
+	t.Run("other user on the same date", func(t *testing.T) {
+		other := db.CreateUser(t)
+		_, err := db.Schedules.CreateSchedule(ctx, fixtures.Schedule(other.ID, morning.ID, date))
+		assert.NoError(t, err)
+	})
+}
+
+func testScheduleDatesAndAttendance(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	shift := db.CreateShift(t)
+	now := time.Now()
+	start := fixtures.Day(now, -2)
+	for day := -2; day <= 0; day++ {
+		db.CreateSchedule(t, user.ID, shift.ID, fixtures.Day(now, day))
+	}
+	// Check-in tanpa tautan jadwal dicocokkan dengan tanggal lokalnya.
+	checkIn := time.Date(start.Year(), start.Month(), start.Day(), 9, 0, 0, 0, time.Local)
+	attID := db.CheckIn(t, user.ID, checkIn)
+	require.NoError(t, db.Attendances.UpdateCheckOut(ctx, attID, checkIn.Add(8*time.Hour), nil))
+
+	schedule, err := db.Schedules.GetScheduleByUserAndDate(ctx, user.ID, start)
+	require.NoError(t, err)
+	require.NotNil(t, schedule)
+	assert.Equal(t, start.Format(fixtures.DateLayout), schedule.Date)
+
+	schedules, total, err := db.Schedules.GetSchedulesByUserWithAttendance(ctx, user.ID, start, fixtures.Day(now, 0), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, schedules, 3)
+	assert.Equal(t, models.AttendanceStatusPresent, schedules[0].AttendanceStatus)
+	require.NotNil(t, schedules[0].Attendance)
+	assert.Equal(t, attID, schedules[0].Attendance.ID)
+	assert.Equal(t, models.AttendanceStatusMissing, schedules[1].AttendanceStatus)
+
+	t.Run("patch moves the date and bumps the version", func(t *testing.T) {
+		moved := fixtures.Day(now, 5).Format(fixtures.DateLayout)
+		version, err := db.Schedules.PatchSchedule(ctx, schedules[2].ID, &models.PatchScheduleInput{Date: &moved, Version: schedules[2].Version})
+		require.NoError(t, err)
+		assert.Equal(t, schedules[2].Version+1, version)
+		got, err := db.Schedules.GetScheduleByID(ctx, schedules[2].ID)
+		require.NoError(t, err)
+		assert.Equal(t, moved, got.Date)
+
+		_, err = db.Schedules.PatchSchedule(ctx, schedules[2].ID, &models.PatchScheduleInput{Date: &moved, Version: schedules[2].Version})
+		assert.ErrorIs(t, err, repository.ErrVersionConflict)
+	})
+	t.Run("bulk delete keeps schedules with attendance", func(t *testing.T) {
+		end := fixtures.Day(now, 0)
+		result, err := db.Schedules.BulkDeleteSchedules(ctx, nil, nil, &start, &end)
+		require.NoError(t, err)
+		assert.Equal(t, []int{schedules[1].ID}, result.DeletedIDs)
+		require.Len(t, result.Kept, 1)
+		assert.Equal(t, schedules[0].ID, result.Kept[0].ID)
+	})
+}
+
+func testScheduleCRUD(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	shift := db.CreateShift(t)
+	date := fixtures.Day(time.Now(), 1)
+	id := db.CreateSchedule(t, user.ID, shift.ID, date)
+	otherID := db.CreateSchedule(t, other.ID, shift.ID, date)
+
+	got, err := db.Schedules.GetScheduleByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, got.UserID)
+	assert.Equal(t, date.Format(fixtures.DateLayout), got.Date)
+	require.NotNil(t, got.Shift, "shift summary is joined")
+	assert.Equal(t, shift.Name, got.Shift.Name)
+
+	byDate, err := db.Schedules.GetScheduleByUserAndDate(ctx, user.ID, fixtures.Day(date, 1))
+	require.NoError(t, err)
+	assert.Nil(t, byDate, "no schedule on that date is not an error")
+
+	moved := &models.UserSchedule{ID: id, UserID: user.ID, ShiftID: shift.ID, Date: fixtures.Day(date, 1).Format(fixtures.DateLayout), Version: got.Version}
+	require.NoError(t, db.Schedules.UpdateSchedule(ctx, moved))
+	assert.Greater(t, moved.Version, got.Version)
+
+	exported, err := db.Schedules.ExportSchedulesByUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, exported, 1)
+	assert.Equal(t, moved.Date, exported[0].Date)
+
+	shiftID := shift.ID
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+		wantMsg string
+	}{
+		{name: "update stale version", call: func() error {
+			return db.Schedules.UpdateSchedule(ctx, &models.UserSchedule{ID: id, UserID: user.ID, ShiftID: shift.ID, Date: moved.Date, Version: got.Version})
+		}, wantErr: repository.ErrVersionConflict},
+		{name: "update unknown schedule", call: func() error {
+			return db.Schedules.UpdateSchedule(ctx, &models.UserSchedule{ID: missingID, UserID: user.ID, ShiftID: shift.ID, Date: "2031-01-01"})
+		}, wantErr: pgx.ErrNoRows},
+		{name: "update onto a taken date", call: func() error {
+			return db.Schedules.UpdateSchedule(ctx, &models.UserSchedule{ID: otherID, UserID: user.ID, ShiftID: shift.ID, Date: moved.Date})
+		}},
+		{name: "update invalid date", call: func() error {
+			return db.Schedules.UpdateSchedule(ctx, &models.UserSchedule{ID: id, UserID: user.ID, ShiftID: shift.ID, Date: "01/02/2030"})
+		}, wantMsg: "invalid date format"},
+		{name: "patch without fields", call: func() error {
+			_, err := db.Schedules.PatchSchedule(ctx, id, &models.PatchScheduleInput{})
+			return err
+		}, wantErr: repository.ErrNoFieldsToUpdate},
+		{name: "patch unknown schedule", call: func() error {
+			_, err := db.Schedules.PatchSchedule(ctx, missingID, &models.PatchScheduleInput{ShiftID: &shiftID})
+			return err
+		}, wantErr: pgx.ErrNoRows},
+		{name: "get unknown schedule", call: func() error {
+			_, err := db.Schedules.GetScheduleByID(ctx, missingID)
+			return err
+		}, wantErr: pgx.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+
+	require.NoError(t, db.Schedules.DeleteSchedule(ctx, id))
+	_, err = db.Schedules.GetScheduleByID(ctx, id)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+	assert.ErrorIs(t, db.Schedules.DeleteSchedule(ctx, id), pgx.ErrNoRows, "second delete")
+}
+
+func testCreateScheduleUnknownReferences(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	shift := db.CreateShift(t)
+	date := fixtures.Day(time.Now(), 1)
+	id := db.CreateSchedule(t, user.ID, shift.ID, fixtures.Day(date, 1))
+	unknown := missingID
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "create unknown user", call: func() error {
+			_, err := db.Schedules.CreateSchedule(ctx, fixtures.Schedule(missingID, shift.ID, date))
+			return err
+		}},
+		{name: "create unknown shift", call: func() error {
+			_, err := db.Schedules.CreateSchedule(ctx, fixtures.Schedule(user.ID, missingID, date))
+			return err
+		}},
+		{name: "patch unknown user", call: func() error {
+			_, err := db.Schedules.PatchSchedule(ctx, id, &models.PatchScheduleInput{UserID: &unknown})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid user_id")
+		})
+	}
+}
+
+func testSchedulesPagination(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	shift := db.CreateShift(t)
+	start := fixtures.Day(time.Now(), 1)
+	end := fixtures.Day(start, 3)
+	byUser := func(page, limit int) ([]models.UserSchedule, int, error) {
+		return db.Schedules.GetSchedulesByUser(ctx, user.ID, start, end, page, limit)
+	}
+	withAttendance := func(page, limit int) ([]models.UserSchedule, int, error) {
+		return db.Schedules.GetSchedulesByUserWithAttendance(ctx, user.ID, start, end, page, limit)
+	}
+	allUsers := func(page, limit int) ([]models.UserSchedule, int, error) {
+		return db.Schedules.GetSchedulesByDateRangeForAllUsers(ctx, start, end, page, limit)
+	}
+	checkEmptyPage(t, byUser)
+	checkEmptyPage(t, allUsers)
+
+	for day := 0; day < 4; day++ {
+		db.CreateSchedule(t, user.ID, shift.ID, fixtures.Day(start, day))
+	}
+	// Di luar rentang: tidak dihitung.
+	db.CreateSchedule(t, user.ID, shift.ID, fixtures.Day(start, 10))
+	db.CreateSchedule(t, other.ID, shift.ID, start)
+
+	id := func(s models.UserSchedule) int { return s.ID }
+	t.Run("by user", func(t *testing.T) { checkPagination(t, 4, byUser, id) })
+	t.Run("by user with attendance", func(t *testing.T) { checkPagination(t, 4, withAttendance, id) })
+	t.Run("all users", func(t *testing.T) { checkPagination(t, 5, allUsers, id) })
+
+	schedules, _, err := byUser(1, 10)
+	require.NoError(t, err)
+	for i := 1; i < len(schedules); i++ {
+		assert.Less(t, schedules[i-1].Date, schedules[i].Date, "ordered by date")
+	}
+}
+
+func testBulkDeleteSchedulesByID(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	shift := db.CreateShift(t)
+	first := db.CreateSchedule(t, user.ID, shift.ID, fixtures.Day(time.Now(), 1))
+	second := db.CreateSchedule(t, user.ID, shift.ID, fixtures.Day(time.Now(), 2))
+
+	result, err := db.Schedules.BulkDeleteSchedules(ctx, []int{first, missingID, second}, nil, nil, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{first, second}, result.DeletedIDs)
+	assert.Equal(t, 2, result.DeletedCount)
+	assert.Equal(t, []int{missingID}, result.NotFoundIDs)
+
+	found, err := db.Schedules.GetSchedulesByIDs(ctx, []int{first, second})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func testShiftHeadcounts(t *testing.T, db Repos) {
+	ctx := context.Background()
+	shift := db.CreateShift(t)
+	day := fixtures.Day(time.Now(), -1)
+	present := db.CreateUser(t)
+	absent := db.CreateUser(t)
+	scheduleID := db.CreateSchedule(t, present.ID, shift.ID, day)
+	db.CreateSchedule(t, absent.ID, shift.ID, day)
+	checkIn := time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, time.UTC)
+	_, err := db.Attendances.CreateCheckIn(ctx, present.ID, checkIn, nil, &scheduleID, nil, models.AttendanceModeOnsite)
+	require.NoError(t, err)
+
+	counts, err := db.Schedules.GetShiftHeadcounts(ctx, day, day)
+	require.NoError(t, err)
+	assert.Equal(t, []models.ShiftHeadcount{
+		{Date: day.Format(fixtures.DateLayout), ShiftID: shift.ID, ShiftName: shift.Name, Scheduled: 2, Attended: 1},
+	}, counts)
+
+	counts, err = db.Schedules.GetShiftHeadcounts(ctx, fixtures.Day(day, 1), fixtures.Day(day, 2))
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func testScheduleChangesSettle(t *testing.T, db Repos) {
+	db.CreateSchedule(t, db.CreateUser(t).ID, db.CreateShift(t).ID, fixtures.Day(time.Now(), 1))
+	checkUnsettledChangesWithheld(t, func(cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+		return db.Schedules.GetScheduleChanges(context.Background(), cursor, limit)
+	})
+}
@@ -0,0 +1,50 @@
+// internal/testutil/repotest/settings.go
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var settingsTests = []contractTest{
+	{"UpsertSettings", testUpsertSettings},
+}
+
+func testUpsertSettings(t *testing.T, db Repos) {
+	ctx := context.Background()
+	first := db.CreateUser(t, fixtures.AsAdmin)
+	second := db.CreateUser(t, fixtures.AsAdmin)
+	settings, err := db.Settings.GetAllSettings(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, settings)
+
+	require.NoError(t, db.Settings.UpsertSettings(ctx, map[string]string{"b.key": "1", "a.key": "x"}, first.ID))
+	require.NoError(t, db.Settings.UpsertSettings(ctx, map[string]string{"b.key": "2"}, second.ID))
+	require.NoError(t, db.Settings.UpsertSettings(ctx, map[string]string{}, second.ID), "nothing to save")
+
+	settings, err = db.Settings.GetAllSettings(ctx)
+	require.NoError(t, err)
+	require.Len(t, settings, 2)
+	want := []models.Setting{
+		{Key: "a.key", Value: "x", UpdatedBy: &first.ID},
+		{Key: "b.key", Value: "2", UpdatedBy: &second.ID},
+	}
+	for i, s := range settings {
+		assert.False(t, s.UpdatedAt.IsZero())
+		s.UpdatedAt = want[i].UpdatedAt
+		assert.Equal(t, want[i], s, "ordered by key, last write wins")
+	}
+
+	// Foreign key violation pada updated_by menggagalkan seluruh batch (satu transaksi).
+	err = db.Settings.UpsertSettings(ctx, map[string]string{"a.key": "y", "c.key": "z"}, missingID)
+	require.Error(t, err)
+	settings, err = db.Settings.GetAllSettings(ctx)
+	require.NoError(t, err)
+	require.Len(t, settings, 2)
+	assert.Equal(t, "x", settings[0].Value)
+}
@@ -0,0 +1,146 @@
+// internal/testutil/repotest/shifts.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var shiftTests = []contractTest{
+	{"ShiftCRUD", testShiftCRUD},
+	{"CreateShiftInvalidTime", testCreateShiftInvalidTime},
+	{"DeleteShiftInUse", testDeleteShiftInUse},
+	{"ShiftOverrides", testShiftOverrides},
+}
+
+func testShiftCRUD(t *testing.T, db Repos) {
+	ctx := context.Background()
+	shift := db.CreateShift(t)
+	assert.Equal(t, "09:00:00", shift.StartTime)
+	assert.Equal(t, "17:00:00", shift.EndTime)
+
+	shifts, err := db.Shifts.GetAllShifts(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, shiftIDs(shifts), shift.ID)
+
+	update := &models.Shift{ID: shift.ID, Name: shift.Name + " (late)", StartTime: "13:00:00", EndTime: "21:00:00", Version: shift.Version}
+	require.NoError(t, db.Shifts.UpdateShift(ctx, update))
+	assert.Greater(t, update.Version, shift.Version, "version is bumped")
+	got, err := db.Shifts.GetShiftByID(ctx, shift.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "13:00:00", got.StartTime)
+
+	tests := []struct {
+		name    string
+		shift   *models.Shift
+		wantErr error
+	}{
+		{name: "stale version", shift: &models.Shift{ID: shift.ID, Name: shift.Name, StartTime: "09:00:00", EndTime: "17:00:00", Version: shift.Version}, wantErr: repository.ErrVersionConflict},
+		{name: "unknown shift", shift: &models.Shift{ID: missingID, Name: shift.Name, StartTime: "09:00:00", EndTime: "17:00:00"}, wantErr: pgx.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, db.Shifts.UpdateShift(ctx, tt.shift), tt.wantErr)
+		})
+	}
+
+	require.NoError(t, db.Shifts.DeleteShift(ctx, shift.ID))
+	_, err = db.Shifts.GetShiftByID(ctx, shift.ID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+	assert.ErrorIs(t, db.Shifts.DeleteShift(ctx, shift.ID), pgx.ErrNoRows, "second delete")
+}
+
+func testCreateShiftInvalidTime(t *testing.T, db Repos) {
+	tests := []struct {
+		name       string
+		start, end string
+	}{
+		{name: "missing seconds", start: "09:00", end: "17:00:00"},
+		{name: "out of range", start: "09:00:00", end: "25:00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Shifts.CreateShift(context.Background(), fixtures.Shift(func(s *models.Shift) {
+				s.StartTime, s.EndTime = tt.start, tt.end
+			}))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid time format")
+		})
+	}
+}
+
+func testDeleteShiftInUse(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	shift := db.CreateShift(t)
+	db.CreateSchedule(t, user.ID, shift.ID, fixtures.Day(time.Now(), 1))
+
+	err := db.Shifts.DeleteShift(ctx, shift.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "still referenced")
+	_, err = db.Shifts.GetShiftByID(ctx, shift.ID)
+	assert.NoError(t, err, "shift is kept")
+}
+
+func testShiftOverrides(t *testing.T, db Repos) {
+	ctx := context.Background()
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	shift := db.CreateShift(t)
+	start, end := "07:00:00", "15:00:00"
+
+	past := &models.ShiftOverride{Name: "Past", StartDate: "2020-01-01", EndDate: "2020-01-31", StartOffsetMinutes: -60, EndOffsetMinutes: -60, CreatedBy: &admin.ID}
+	current := &models.ShiftOverride{Name: "Ramadan", ShiftID: &shift.ID, StartDate: "2030-03-01", EndDate: "2030-03-30", StartTime: &start, EndTime: &end, CreatedBy: &admin.ID}
+	for _, o := range []*models.ShiftOverride{past, current} {
+		id, err := db.Shifts.CreateShiftOverride(ctx, o)
+		require.NoError(t, err)
+		assert.Equal(t, o.ID, id)
+		assert.False(t, o.CreatedAt.IsZero())
+	}
+
+	unknown := missingID
+	_, err := db.Shifts.CreateShiftOverride(ctx, &models.ShiftOverride{Name: "Orphan", ShiftID: &unknown, StartDate: "2030-01-01", EndDate: "2030-01-02"})
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "unknown shift")
+	_, err = db.Shifts.CreateShiftOverride(ctx, &models.ShiftOverride{Name: "Backwards", StartDate: "2030-01-02", EndDate: "2030-01-01"})
+	assert.Error(t, err, "end before start violates the check constraint")
+
+	activeFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		activeFrom *time.Time
+		want       []int
+	}{
+		{name: "all, latest start first", want: []int{current.ID, past.ID}},
+		{name: "not ended yet", activeFrom: &activeFrom, want: []int{current.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overrides, err := db.Shifts.GetShiftOverrides(ctx, tt.activeFrom)
+			require.NoError(t, err)
+			ids := make([]int, 0, len(overrides))
+			for _, o := range overrides {
+				ids = append(ids, o.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+
+	require.NoError(t, db.Shifts.DeleteShiftOverride(ctx, past.ID))
+	assert.ErrorIs(t, db.Shifts.DeleteShiftOverride(ctx, past.ID), pgx.ErrNoRows, "second delete")
+}
+
+// shiftIDs mengembalikan ID shift dalam urutan aslinya.
+func shiftIDs(shifts []models.Shift) []int {
+	ids := make([]int, 0, len(shifts))
+	for _, s := range shifts {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
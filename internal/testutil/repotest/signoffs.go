@@ -0,0 +1,149 @@
+// internal/testutil/repotest/signoffs.go
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var signOffTests = []contractTest{
+	{"SignOffDay", testSignOffDay},
+	{"SignOffDayResults", testSignOffDayResults},
+	{"UnsignedDaysOrganization", testUnsignedDaysOrganization},
+}
+
+func testSignOffDay(t *testing.T, db Repos) {
+	ctx := context.Background()
+	manager := db.CreateUser(t)
+	member := db.CreateUser(t)
+	require.NoError(t, db.Users.SetUserManager(ctx, member.ID, &manager.ID))
+	shift := db.CreateShift(t)
+	now := time.Now()
+	day := fixtures.Day(now, -1)
+	db.CreateSchedule(t, member.ID, shift.ID, day)
+
+	unsigned, err := db.SignOffs.GetUnsignedDays(ctx, &manager.ID, fixtures.Day(now, -3), fixtures.Day(now, 0))
+	require.NoError(t, err)
+	require.Len(t, unsigned, 1)
+	assert.Equal(t, member.ID, unsigned[0].UserID)
+	assert.Equal(t, day.Format(fixtures.DateLayout), unsigned[0].WorkDate)
+
+	results, err := db.SignOffs.SignOffDay(ctx, manager.ID, manager.ID, day, nil, 15*time.Minute, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, member.ID, results[0].UserID)
+	assert.Equal(t, models.BulkStatusUpdated, results[0].Status)
+
+	unsigned, err = db.SignOffs.GetUnsignedDays(ctx, &manager.ID, fixtures.Day(now, -3), fixtures.Day(now, 0))
+	require.NoError(t, err)
+	assert.Empty(t, unsigned)
+
+	// Absensi pada tanggal yang sudah di-sign-off tidak bisa ditambahkan lagi.
+	local := time.Date(day.Year(), day.Month(), day.Day(), 10, 0, 0, 0, time.Local)
+	_, err = db.Attendances.CreateCheckIn(ctx, member.ID, local, nil, nil, nil, models.AttendanceModeOnsite)
+	var signed *repository.AttendanceSignedOffError
+	assert.True(t, errors.As(err, &signed), "got %v", err)
+}
+
+func testSignOffDayResults(t *testing.T, db Repos) {
+	ctx := context.Background()
+	manager := db.CreateUser(t)
+	done := db.CreateUser(t)
+	working := db.CreateUser(t)
+	outsider := db.CreateUser(t)
+	for _, member := range []int{done.ID, working.ID} {
+		require.NoError(t, db.Users.SetUserManager(ctx, member, &manager.ID))
+	}
+	day := fixtures.Day(time.Now(), -1)
+	local := time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, time.Local)
+	id := db.CheckIn(t, done.ID, local)
+	require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, local.Add(8*time.Hour), nil))
+	db.CheckIn(t, working.ID, local)
+
+	results, err := db.SignOffs.SignOffDay(ctx, manager.ID, manager.ID, day, []int{done.ID, working.ID, outsider.ID, missingID}, 15*time.Minute, nil)
+	require.NoError(t, err)
+	got := make(map[int]string, len(results))
+	for _, r := range results {
+		got[r.UserID] = r.Status
+	}
+	assert.Equal(t, map[int]string{
+		done.ID:     models.BulkStatusUpdated,
+		working.ID:  models.BulkStatusSkipped,
+		outsider.ID: models.BulkStatusNotFound,
+		missingID:   models.BulkStatusNotFound,
+	}, got)
+
+	note := "checked"
+	results, err = db.SignOffs.SignOffDay(ctx, manager.ID, manager.ID, day, []int{done.ID}, 15*time.Minute, &note)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, models.BulkStatusUnchanged, results[0].Status, "already signed off")
+
+	// Koreksi absensi pada tanggal yang sudah di-sign-off juga ditolak.
+	newIn := local.Add(-time.Hour)
+	_, err = db.Attendances.CorrectAttendance(ctx, id, &models.AttendanceCorrectionInput{CheckInAt: &newIn, Reason: "came earlier"}, manager.ID)
+	var signed *repository.AttendanceSignedOffError
+	assert.True(t, errors.As(err, &signed), "got %v", err)
+}
+
+func testUnsignedDaysOrganization(t *testing.T, db Repos) {
+	ctx := context.Background()
+	manager := db.CreateUser(t)
+	member := db.CreateUser(t)
+	loner := db.CreateUser(t)
+	require.NoError(t, db.Users.SetUserManager(ctx, member.ID, &manager.ID))
+	shift := db.CreateShift(t)
+	now := time.Now()
+	first, second := fixtures.Day(now, -2), fixtures.Day(now, -1)
+	db.CreateSchedule(t, member.ID, shift.ID, first)
+	db.CreateSchedule(t, loner.ID, shift.ID, second)
+	db.CheckIn(t, member.ID, time.Date(second.Year(), second.Month(), second.Day(), 10, 0, 0, 0, time.Local))
+
+	tests := []struct {
+		name      string
+		managerID *int
+		start     time.Time
+		want      []models.UnsignedDay
+	}{
+		{name: "whole organization", start: fixtures.Day(now, -3), want: []models.UnsignedDay{
+			{UserID: member.ID, Username: member.Username, ManagerID: &manager.ID, WorkDate: first.Format(fixtures.DateLayout)},
+			{UserID: loner.ID, Username: loner.Username, WorkDate: second.Format(fixtures.DateLayout)},
+			{UserID: member.ID, Username: member.Username, ManagerID: &manager.ID, WorkDate: second.Format(fixtures.DateLayout)},
+		}},
+		{name: "team only", managerID: &manager.ID, start: fixtures.Day(now, -3), want: []models.UnsignedDay{
+			{UserID: member.ID, Username: member.Username, ManagerID: &manager.ID, WorkDate: first.Format(fixtures.DateLayout)},
+			{UserID: member.ID, Username: member.Username, ManagerID: &manager.ID, WorkDate: second.Format(fixtures.DateLayout)},
+		}},
+		{name: "range without work days", start: fixtures.Day(now, -10), want: []models.UnsignedDay{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end := fixtures.Day(now, 0)
+			if len(tt.want) == 0 {
+				end = fixtures.Day(now, -5)
+			}
+			unsigned, err := db.SignOffs.GetUnsignedDays(ctx, tt.managerID, tt.start, end)
+			require.NoError(t, err)
+			if len(tt.want) == 0 {
+				assert.Empty(t, unsigned)
+				return
+			}
+			assert.ElementsMatch(t, tt.want, unsigned)
+			assert.True(t, sort.SliceIsSorted(unsigned, func(i, j int) bool {
+				if unsigned[i].WorkDate != unsigned[j].WorkDate {
+					return unsigned[i].WorkDate < unsigned[j].WorkDate
+				}
+				return unsigned[i].Username < unsigned[j].Username
+			}), "ordered by date then username")
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// internal/testutil/repotest/sync.go
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncFunc adalah query delta sync (GetUserChanges, GetAttendanceChanges, GetScheduleChanges).
+type syncFunc func(cursor models.SyncCursor, limit int) (*models.SyncBatch, error)
+
+// checkUnsettledChangesWithheld memastikan perubahan yang lebih baru dari SyncSettleSeconds tidak
+// dikirim (transaksi yang masih berjalan bisa commit di belakang watermark): batch kosong dan
+// cursor tidak bergerak, sehingga klien mengulang dari posisi yang sama.
+func checkUnsettledChangesWithheld(t *testing.T, changes syncFunc) {
+	t.Helper()
+	cursors := []struct {
+		name   string
+		cursor models.SyncCursor
+	}{
+		{name: "initial cursor", cursor: models.SyncCursor{}},
+		{name: "cursor in the past", cursor: models.SyncCursor{UpdatedSince: time.Now().Add(-time.Hour).UTC(), AfterID: 1}},
+	}
+	for _, tt := range cursors {
+		t.Run(tt.name, func(t *testing.T) {
+			batch, err := changes(tt.cursor, 10)
+			require.NoError(t, err)
+			assert.Empty(t, batch.Items)
+			assert.Empty(t, batch.Deleted)
+			assert.False(t, batch.HasMore)
+			assert.True(t, tt.cursor.UpdatedSince.Equal(batch.NextUpdatedSince), "watermark does not move")
+			assert.Equal(t, tt.cursor.AfterID, batch.NextAfterID)
+		})
+	}
+}
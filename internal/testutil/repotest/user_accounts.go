@@ -0,0 +1,296 @@
+// internal/testutil/repotest/user_accounts.go
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Skenario akun user di luar CRUD: sesi (token_version), undangan, ganti email & username, sync.
+
+func testSessions(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+
+	version, err := db.Users.GetTokenVersion(ctx, user.ID)
+	require.NoError(t, err)
+
+	revoked, err := db.Users.RevokeSessions(ctx, user.ID, version)
+	require.NoError(t, err)
+	assert.Equal(t, version+1, revoked)
+	got, err := db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.True(t, got.PasswordResetRequired)
+
+	// Tautan reset yang sama tidak bisa dipakai dua kali.
+	_, err = db.Users.RevokeSessions(ctx, user.ID, version)
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "stale version")
+	assert.ErrorIs(t, db.Users.ResetPassword(ctx, user.ID, "reset-hash", version), pgx.ErrNoRows, "stale version")
+
+	require.NoError(t, db.Users.ResetPassword(ctx, user.ID, "reset-hash", revoked))
+	got, err = db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.False(t, got.PasswordResetRequired)
+	assert.Equal(t, revoked+1, got.TokenVersion)
+
+	forced, err := db.Users.ForceRevokeSessions(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, revoked+2, forced)
+
+	require.NoError(t, db.Users.RequirePasswordChange(ctx, user.ID))
+	got, err = db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.True(t, got.MustChangePassword)
+
+	unknown := []struct {
+		name string
+		call func() error
+	}{
+		{name: "token version", call: func() error { _, err := db.Users.GetTokenVersion(ctx, missingID); return err }},
+		{name: "revoke", call: func() error { _, err := db.Users.RevokeSessions(ctx, missingID, 0); return err }},
+		{name: "force revoke", call: func() error { _, err := db.Users.ForceRevokeSessions(ctx, missingID); return err }},
+		{name: "require password change", call: func() error { return db.Users.RequirePasswordChange(ctx, missingID) }},
+	}
+	for _, tt := range unknown {
+		t.Run("unknown user "+tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), pgx.ErrNoRows)
+		})
+	}
+}
+
+func testInvitations(t *testing.T, db Repos) {
+	ctx := context.Background()
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	expires := time.Now().Add(24 * time.Hour).UTC()
+
+	inv, err := db.Users.CreateInvitation(ctx, "invitee@example.test", fixtures.RoleEmployeeID, admin.ID, expires)
+	require.NoError(t, err)
+	assert.Equal(t, "invitee@example.test", inv.Email)
+	assert.Equal(t, models.InvitationPending, inv.Status)
+	assert.Equal(t, "Employee", inv.RoleName)
+
+	got, err := db.Users.GetInvitationByID(ctx, inv.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "invitee@example.test", got.Email, "email is decrypted")
+	assert.Equal(t, models.InvitationPending, got.Status)
+
+	// Undangan baru untuk email yang sama mencabut undangan terbuka sebelumnya.
+	replacement, err := db.Users.CreateInvitation(ctx, "invitee@example.test", fixtures.RoleAdminID, admin.ID, expires)
+	require.NoError(t, err)
+	got, err = db.Users.GetInvitationByID(ctx, inv.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.InvitationRevoked, got.Status)
+
+	require.NoError(t, db.Users.RenewInvitation(ctx, replacement.ID, expires.Add(time.Hour)))
+	invitee := db.CreateUser(t)
+	require.NoError(t, db.Users.AcceptInvitation(ctx, replacement.ID, invitee.ID))
+	got, err = db.Users.GetInvitationByID(ctx, replacement.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.InvitationAccepted, got.Status)
+	require.NotNil(t, got.AcceptedUserID)
+	assert.Equal(t, invitee.ID, *got.AcceptedUserID)
+
+	expired, err := db.Users.CreateInvitation(ctx, "late@example.test", fixtures.RoleEmployeeID, admin.ID, time.Now().Add(-time.Hour).UTC())
+	require.NoError(t, err)
+	assert.Equal(t, models.InvitationExpired, expired.Status)
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{name: "email already registered", call: func() error {
+			_, err := db.Users.CreateInvitation(ctx, admin.Email, fixtures.RoleEmployeeID, admin.ID, expires)
+			return err
+		}, wantErr: repository.ErrEmailTaken},
+		{name: "unknown role", call: func() error {
+			_, err := db.Users.CreateInvitation(ctx, "norole@example.test", missingID, admin.ID, expires)
+			return err
+		}, wantErr: pgx.ErrNoRows},
+		{name: "get unknown", call: func() error { _, err := db.Users.GetInvitationByID(ctx, missingID); return err }, wantErr: pgx.ErrNoRows},
+		{name: "renew accepted", call: func() error { return db.Users.RenewInvitation(ctx, replacement.ID, expires) }, wantErr: pgx.ErrNoRows},
+		{name: "renew revoked", call: func() error { return db.Users.RenewInvitation(ctx, inv.ID, expires) }, wantErr: pgx.ErrNoRows},
+		{name: "revoke accepted", call: func() error { return db.Users.RevokeInvitation(ctx, replacement.ID) }, wantErr: pgx.ErrNoRows},
+		{name: "revoke twice", call: func() error { return db.Users.RevokeInvitation(ctx, inv.ID) }, wantErr: pgx.ErrNoRows},
+		{name: "accept twice", call: func() error { return db.Users.AcceptInvitation(ctx, replacement.ID, invitee.ID) }, wantErr: pgx.ErrNoRows},
+		{name: "accept expired", call: func() error { return db.Users.AcceptInvitation(ctx, expired.ID, invitee.ID) }, wantErr: pgx.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), tt.wantErr)
+		})
+	}
+
+	// Undangan kedaluwarsa masih bisa dikirim ulang (masa berlaku diperpanjang).
+	require.NoError(t, db.Users.RenewInvitation(ctx, expired.ID, expires))
+	got, err = db.Users.GetInvitationByID(ctx, expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.InvitationPending, got.Status)
+}
+
+func testInvitationsPagination(t *testing.T, db Repos) {
+	ctx := context.Background()
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	list := func(status string) pageFunc[models.Invitation] {
+		return func(page, limit int) ([]models.Invitation, int, error) {
+			return db.Users.GetInvitations(ctx, status, page, limit)
+		}
+	}
+	checkEmptyPage(t, list(""))
+
+	expires := time.Now().Add(24 * time.Hour).UTC()
+	for i := 0; i < 4; i++ {
+		_, err := db.Users.CreateInvitation(ctx, fmt.Sprintf("page%d@example.test", i), fixtures.RoleEmployeeID, admin.ID, expires)
+		require.NoError(t, err)
+	}
+	revoked, err := db.Users.CreateInvitation(ctx, "revoked@example.test", fixtures.RoleEmployeeID, admin.ID, expires)
+	require.NoError(t, err)
+	require.NoError(t, db.Users.RevokeInvitation(ctx, revoked.ID))
+
+	id := func(inv models.Invitation) int { return inv.ID }
+	t.Run("all", func(t *testing.T) { checkPagination(t, 5, list(""), id) })
+	t.Run("pending", func(t *testing.T) { checkPagination(t, 4, list(models.InvitationPending), id) })
+	t.Run("accepted", func(t *testing.T) { checkEmptyPage(t, list(models.InvitationAccepted)) })
+
+	// Terbaru dulu.
+	invitations, _, err := db.Users.GetInvitations(ctx, "", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+	assert.Equal(t, revoked.ID, invitations[0].ID)
+	assert.Equal(t, "revoked@example.test", invitations[0].Email)
+}
+
+func testEmailChange(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	expires := time.Now().Add(time.Hour).UTC()
+
+	_, err := db.Users.GetPendingEmailChange(ctx, user.ID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "no request yet")
+	assert.ErrorIs(t, db.Users.CancelEmailChange(ctx, user.ID), pgx.ErrNoRows, "nothing to cancel")
+
+	_, err = db.Users.CreateEmailChangeRequest(ctx, user.ID, other.Email, expires)
+	assert.ErrorIs(t, err, repository.ErrEmailTaken)
+	_, err = db.Users.CreateEmailChangeRequest(ctx, missingID, "ghost@example.test", expires)
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "unknown user")
+
+	first, err := db.Users.CreateEmailChangeRequest(ctx, user.ID, "first@example.test", expires)
+	require.NoError(t, err)
+	require.NoError(t, db.Users.CancelEmailChange(ctx, user.ID))
+	_, err = db.Users.GetEmailChangeByID(ctx, first.ID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "cancelled request is deleted")
+
+	// Permintaan baru menggantikan permintaan terbuka sebelumnya.
+	_, err = db.Users.CreateEmailChangeRequest(ctx, user.ID, "second@example.test", expires)
+	require.NoError(t, err)
+	req, err := db.Users.CreateEmailChangeRequest(ctx, user.ID, "changed@example.test", expires)
+	require.NoError(t, err)
+	pending, err := db.Users.GetPendingEmailChange(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, req.ID, pending.ID)
+	assert.Equal(t, "changed@example.test", pending.NewEmail, "new email is decrypted")
+
+	require.NoError(t, db.Users.RenewEmailChangeRequest(ctx, req.ID, expires.Add(time.Hour)))
+	require.NoError(t, db.Users.ConfirmEmailChange(ctx, req.ID))
+	got, err := db.Users.GetUserByEmail(ctx, "changed@example.test")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, got.ID)
+
+	assert.ErrorIs(t, db.Users.ConfirmEmailChange(ctx, req.ID), pgx.ErrNoRows, "confirm twice")
+	assert.ErrorIs(t, db.Users.RenewEmailChangeRequest(ctx, req.ID, expires), pgx.ErrNoRows, "renew confirmed")
+
+	expired, err := db.Users.CreateEmailChangeRequest(ctx, other.ID, "expired@example.test", time.Now().Add(-time.Minute).UTC())
+	require.NoError(t, err)
+	assert.ErrorIs(t, db.Users.ConfirmEmailChange(ctx, expired.ID), pgx.ErrNoRows, "confirm expired")
+}
+
+func testUsernameChange(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	oldUsername := user.Username
+	list := func(status string) pageFunc[models.UsernameChangeRequest] {
+		return func(page, limit int) ([]models.UsernameChangeRequest, int, error) {
+			return db.Users.GetUsernameChangeRequests(ctx, status, page, limit)
+		}
+	}
+	checkEmptyPage(t, list(""))
+
+	_, err := db.Users.CreateUsernameChangeRequest(ctx, user.ID, other.Username)
+	assert.ErrorIs(t, err, repository.ErrUsernameTaken)
+	_, err = db.Users.CreateUsernameChangeRequest(ctx, missingID, "ghost")
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "unknown user")
+
+	rejected, err := db.Users.CreateUsernameChangeRequest(ctx, other.ID, "other-renamed")
+	require.NoError(t, err)
+	note := "not allowed"
+	reviewed, err := db.Users.ReviewUsernameChangeRequest(ctx, rejected.ID, admin.ID, false, &note)
+	require.NoError(t, err)
+	assert.Equal(t, models.UsernameChangeRejected, reviewed.Status)
+	require.NotNil(t, reviewed.ReviewNote)
+	assert.Equal(t, note, *reviewed.ReviewNote)
+
+	_, err = db.Users.CreateUsernameChangeRequest(ctx, user.ID, "first-choice")
+	require.NoError(t, err)
+	req, err := db.Users.CreateUsernameChangeRequest(ctx, user.ID, "renamed")
+	require.NoError(t, err)
+	pending, err := db.Users.GetPendingUsernameChange(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, req.ID, pending.ID, "new request replaces the pending one")
+
+	t.Run("list pending", func(t *testing.T) {
+		requests, total, err := db.Users.GetUsernameChangeRequests(ctx, models.UsernameChangePending, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, requests, 1)
+		assert.Equal(t, oldUsername, requests[0].Username, "current username is joined")
+	})
+
+	approved, err := db.Users.ReviewUsernameChangeRequest(ctx, req.ID, admin.ID, true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.UsernameChangeApproved, approved.Status)
+	_, err = db.Users.GetPendingUsernameChange(ctx, user.ID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = db.Users.ReviewUsernameChangeRequest(ctx, req.ID, admin.ID, true, nil)
+	assert.ErrorIs(t, err, repository.ErrUsernameChangeReviewed)
+	_, err = db.Users.ReviewUsernameChangeRequest(ctx, missingID, admin.ID, true, nil)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	previous, err := db.Users.GetPreviousUsernames(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, previous, 1)
+	assert.Equal(t, oldUsername, previous[0].Username)
+
+	resolved, err := db.Users.ResolveUsername(ctx, oldUsername)
+	require.NoError(t, err)
+	assert.True(t, resolved.MatchedPrevious)
+	assert.Equal(t, user.ID, resolved.User.ID)
+	resolved, err = db.Users.ResolveUsername(ctx, "renamed")
+	require.NoError(t, err)
+	assert.False(t, resolved.MatchedPrevious)
+	_, err = db.Users.ResolveUsername(ctx, "never-used")
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = db.Users.CreateUsernameChangeRequest(ctx, admin.ID, "admin-renamed")
+	require.NoError(t, err)
+	checkPagination(t, 3, list(""), func(uc models.UsernameChangeRequest) int { return uc.ID })
+}
+
+func testUserChangesSettle(t *testing.T, db Repos) {
+	db.CreateUser(t)
+	checkUnsettledChangesWithheld(t, func(cursor models.SyncCursor, limit int) (*models.SyncBatch, error) {
+		return db.Users.GetUserChanges(context.Background(), cursor, limit)
+	})
+}
@@ -0,0 +1,321 @@
+// internal/testutil/repotest/user_employment.go
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var userEmploymentTests = []contractTest{
+	{"ProbationReviews", testProbationReviews},
+	{"DeactivateExpiredContractors", testDeactivateExpiredContractors},
+	{"UpdateWorkModes", testUpdateWorkModes},
+	{"UserSummariesByIDs", testUserSummariesByIDs},
+	{"ExportUsers", testExportUsers},
+	{"ReportingTree", testReportingTree},
+	{"EncryptLegacyPII", testEncryptLegacyPII},
+}
+
+// probation menjadikan user berstatus probation yang berakhir pada tanggal end.
+func probation(t *testing.T, db Repos, userID int, end time.Time) {
+	t.Helper()
+	date := end.Format(fixtures.DateLayout)
+	input := &models.UpdateEmploymentInput{EmploymentStatus: models.EmploymentStatusProbation, ProbationEnd: &date}
+	require.NoError(t, db.Users.UpdateUserEmployment(context.Background(), userID, input))
+}
+
+func testProbationReviews(t *testing.T, db Repos) {
+	ctx := context.Background()
+	now := time.Now()
+	ending := db.CreateUser(t)
+	endingEarlier := db.CreateUser(t)
+	later := db.CreateUser(t)
+	db.CreateUser(t) // Permanent (default)
+	probation(t, db, ending.ID, fixtures.Day(now, -1))
+	probation(t, db, endingEarlier.ID, fixtures.Day(now, -3))
+	probation(t, db, later.ID, fixtures.Day(now, 30))
+
+	hired := fixtures.Day(now, -90).Format(fixtures.DateLayout)
+	got, err := db.Users.GetUserByID(ctx, ending.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.EmploymentStatusProbation, got.EmploymentStatus)
+	require.NotNil(t, got.ProbationEnd)
+	assert.Equal(t, fixtures.Day(now, -1).Format(fixtures.DateLayout), *got.ProbationEnd)
+
+	reviews := func() []int {
+		users, err := db.Users.GetPendingProbationReviews(ctx, fixtures.Day(now, 0))
+		require.NoError(t, err)
+		ids := make([]int, len(users))
+		for i, u := range users {
+			ids[i] = u.ID
+		}
+		return ids
+	}
+	assert.Equal(t, []int{endingEarlier.ID, ending.ID}, reviews(), "earliest probation end first")
+
+	require.NoError(t, db.Users.MarkProbationNotified(ctx, []int{endingEarlier.ID}))
+	require.NoError(t, db.Users.MarkProbationNotified(ctx, nil), "no ids is a no-op")
+	assert.Equal(t, []int{ending.ID}, reviews(), "notified users are skipped")
+
+	// Tanggal akhir probation yang berubah mereset penanda notifikasi; tanggal sama tidak.
+	probation(t, db, ending.ID, fixtures.Day(now, -1))
+	require.NoError(t, db.Users.MarkProbationNotified(ctx, []int{ending.ID}))
+	probation(t, db, ending.ID, fixtures.Day(now, -1))
+	assert.Empty(t, reviews())
+	probation(t, db, endingEarlier.ID, fixtures.Day(now, -2))
+	assert.Equal(t, []int{endingEarlier.ID}, reviews())
+
+	permanent := &models.UpdateEmploymentInput{HireDate: &hired, EmploymentStatus: models.EmploymentStatusPermanent}
+	require.NoError(t, db.Users.UpdateUserEmployment(ctx, endingEarlier.ID, permanent))
+	assert.Empty(t, reviews(), "only users still on probation")
+	got, err = db.Users.GetUserByID(ctx, endingEarlier.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &hired, got.HireDate)
+	assert.Nil(t, got.ProbationEnd)
+
+	assert.ErrorIs(t, db.Users.UpdateUserEmployment(ctx, missingID, permanent), pgx.ErrNoRows)
+}
+
+func testDeactivateExpiredContractors(t *testing.T, db Repos) {
+	ctx := context.Background()
+	now := time.Now()
+	contractor := func(validUntil time.Time) int {
+		user := db.CreateUser(t)
+		until := validUntil.Format(fixtures.DateLayout)
+		input := &models.UpdateUserAccessInput{UserType: models.UserTypeContractor, ValidUntil: &until}
+		// Diset pada tanggal lampau sehingga user masih aktif sampai job berjalan.
+		require.NoError(t, db.Users.UpdateUserAccess(ctx, user.ID, input, fixtures.Day(now, -10)))
+		return user.ID
+	}
+	expired := contractor(fixtures.Day(now, -1))
+	contractor(fixtures.Day(now, 0)) // Masa akses inklusif: hari ini masih berlaku
+	contractor(fixtures.Day(now, 5))
+	db.CreateUser(t)
+
+	ids, err := db.Users.DeactivateExpiredContractors(ctx, fixtures.Day(now, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []int{expired}, ids)
+	got, err := db.Users.GetUserByID(ctx, expired)
+	require.NoError(t, err)
+	assert.False(t, got.IsActive)
+
+	ids, err = db.Users.DeactivateExpiredContractors(ctx, fixtures.Day(now, 0))
+	require.NoError(t, err)
+	assert.Empty(t, ids, "already inactive")
+}
+
+func testUpdateWorkModes(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	days := "mon,fri"
+	allowed := true
+
+	require.NoError(t, db.Users.UpdateWorkModes(ctx, user.ID, &days, nil))
+	got, err := db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, days, got.RemoteDays)
+	assert.False(t, got.FieldWorkAllowed)
+
+	require.NoError(t, db.Users.UpdateWorkModes(ctx, user.ID, nil, &allowed))
+	got, err = db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, days, got.RemoteDays, "nil keeps the current value")
+	assert.True(t, got.FieldWorkAllowed)
+
+	assert.ErrorIs(t, db.Users.UpdateWorkModes(ctx, missingID, &days, nil), pgx.ErrNoRows)
+}
+
+func testUserSummariesByIDs(t *testing.T, db Repos) {
+	ctx := context.Background()
+	first := db.CreateUser(t)
+	second := db.CreateUser(t)
+
+	tests := []struct {
+		name string
+		ids  []int
+		want []*models.User
+	}{
+		{name: "no ids", ids: nil, want: nil},
+		{name: "unknown ids are skipped", ids: []int{first.ID, missingID, second.ID}, want: []*models.User{first, second}},
+		{name: "duplicates", ids: []int{first.ID, first.ID}, want: []*models.User{first}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, err := db.Users.GetUserSummariesByIDs(ctx, tt.ids)
+			require.NoError(t, err)
+			require.NotNil(t, users)
+			require.Len(t, users, len(tt.want))
+			byID := make(map[int]models.User, len(users))
+			for _, u := range users {
+				byID[u.ID] = u
+			}
+			for _, want := range tt.want {
+				got := byID[want.ID]
+				assert.Equal(t, want.Username, got.Username)
+				assert.Equal(t, want.Email, got.Email, "email is decrypted")
+				assert.True(t, got.IsActive)
+			}
+		})
+	}
+}
+
+func testExportUsers(t *testing.T, db Repos) {
+	ctx := context.Background()
+	now := time.Now()
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	employee := db.CreateUser(t)
+	contractor := db.CreateUser(t)
+	db.DeactivateUser(t, contractor.ID)
+	probation(t, db, employee.ID, fixtures.Day(now, 30))
+	checkIn := now.Add(-2 * time.Hour).Truncate(time.Second)
+	id := db.CheckIn(t, employee.ID, checkIn)
+	require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, checkIn.Add(time.Hour), nil))
+
+	export := func(filter models.UserExportFilter) []models.UserExportRow {
+		rows := []models.UserExportRow{}
+		require.NoError(t, db.Users.ExportUsers(ctx, filter, func(row *models.UserExportRow) error {
+			rows = append(rows, *row)
+			return nil
+		}))
+		return rows
+	}
+	ids := func(rows []models.UserExportRow) []int {
+		out := make([]int, len(rows))
+		for i, r := range rows {
+			out[i] = r.User.ID
+		}
+		return out
+	}
+
+	all := export(models.UserExportFilter{})
+	require.Len(t, all, 3)
+	assert.Equal(t, []int{admin.ID, employee.ID, contractor.ID}, ids(all), "by id")
+	assert.Equal(t, admin.Email, all[0].User.Email, "PII is decrypted")
+	require.NotNil(t, all[0].User.Role)
+	assert.Equal(t, fixtures.RoleAdminID, all[0].User.Role.ID)
+	assert.Nil(t, all[0].LastActivityAt, "never checked in")
+	require.NotNil(t, all[1].LastActivityAt)
+	assert.WithinDuration(t, checkIn.Add(time.Hour), *all[1].LastActivityAt, time.Second, "latest of check-in and check-out")
+	assert.Empty(t, all[1].PreviousUsernames)
+
+	roleID := fixtures.RoleAdminID
+	inactive := false
+	tests := []struct {
+		name   string
+		filter models.UserExportFilter
+		want   []int
+	}{
+		{name: "by role", filter: models.UserExportFilter{RoleID: &roleID}, want: []int{admin.ID}},
+		{name: "by user type", filter: models.UserExportFilter{UserType: models.UserTypeContractor}, want: []int{contractor.ID}},
+		{name: "by employment status", filter: models.UserExportFilter{EmploymentStatus: models.EmploymentStatusProbation}, want: []int{employee.ID}},
+		{name: "inactive only", filter: models.UserExportFilter{IsActive: &inactive}, want: []int{contractor.ID}},
+		{name: "no match", filter: models.UserExportFilter{RoleID: &roleID, UserType: models.UserTypeContractor}, want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ids(export(tt.filter)))
+		})
+	}
+
+	// Error dari fn menghentikan iterasi dan diteruskan apa adanya.
+	errStop := errors.New("stop")
+	calls := 0
+	err := db.Users.ExportUsers(ctx, models.UserExportFilter{}, func(*models.UserExportRow) error {
+		calls++
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+}
+
+func testReportingTree(t *testing.T, db Repos) {
+	ctx := context.Background()
+	now := time.Now()
+	day := fixtures.Day(now, -1)
+	shift := db.CreateShift(t)
+
+	boss := db.CreateUser(t)
+	present := db.CreateUser(t)
+	checkedOut := db.CreateUser(t)
+	absent := db.CreateUser(t)
+	gone := db.CreateUser(t)
+	report := db.CreateUser(t)
+	for _, id := range []int{present.ID, checkedOut.ID, absent.ID, gone.ID} {
+		require.NoError(t, db.Users.SetUserManager(ctx, id, &boss.ID))
+	}
+	require.NoError(t, db.Users.SetUserManager(ctx, report.ID, &present.ID))
+	db.DeactivateUser(t, gone.ID)
+
+	db.CheckIn(t, present.ID, day.Add(12*time.Hour))
+	id := db.CheckIn(t, checkedOut.ID, day.Add(12*time.Hour))
+	require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, day.Add(14*time.Hour), nil))
+	db.CreateSchedule(t, absent.ID, shift.ID, day)
+
+	nodes, err := db.Users.GetReportingTree(ctx, &boss.ID, day)
+	require.NoError(t, err)
+	require.Len(t, nodes, 5, "inactive users are left out")
+	byID := make(map[int]models.OrgChartNode, len(nodes))
+	for i, n := range nodes {
+		byID[n.UserID] = n
+		if i > 0 {
+			assert.LessOrEqual(t, nodes[i-1].Depth, n.Depth, "by depth")
+		}
+	}
+	tests := []struct {
+		name      string
+		user      *models.User
+		depth     int
+		managerID *int
+		presence  string
+	}{
+		{name: "root", user: boss, depth: 0, presence: models.PresenceOff},
+		{name: "open session", user: present, depth: 1, managerID: &boss.ID, presence: models.PresencePresent},
+		{name: "checked out that day", user: checkedOut, depth: 1, managerID: &boss.ID, presence: models.PresenceCheckedOut},
+		{name: "scheduled without check-in", user: absent, depth: 1, managerID: &boss.ID, presence: models.PresenceAbsent},
+		{name: "indirect report", user: report, depth: 2, managerID: &present.ID, presence: models.PresenceOff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, ok := byID[tt.user.ID]
+			require.True(t, ok)
+			assert.Equal(t, tt.user.Username, n.Username)
+			assert.Equal(t, tt.depth, n.Depth)
+			assert.Equal(t, tt.managerID, n.ManagerID)
+			assert.Equal(t, tt.presence, n.Presence)
+		})
+	}
+
+	// Tanpa rootID: semua user aktif tanpa atasan menjadi akar.
+	nodes, err = db.Users.GetReportingTree(ctx, nil, day)
+	require.NoError(t, err)
+	assert.Len(t, nodes, 5)
+	nodes, err = db.Users.GetReportingTree(ctx, &report.ID, day)
+	require.NoError(t, err)
+	require.Len(t, nodes, 1, "subtree of a leaf")
+	assert.Zero(t, nodes[0].Depth)
+	nodes, err = db.Users.GetReportingTree(ctx, &gone.ID, day)
+	require.NoError(t, err)
+	assert.Empty(t, nodes, "inactive root")
+}
+
+func testEncryptLegacyPII(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+
+	// User yang dibuat lewat repository sudah terenkripsi dengan kunci aktif.
+	updated, err := db.Users.EncryptLegacyPII(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, updated)
+	got, err := db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, got.Email)
+	assert.Equal(t, user.Phone, got.Phone)
+}
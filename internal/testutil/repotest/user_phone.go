@@ -0,0 +1,150 @@
+// internal/testutil/repotest/user_phone.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var userPhoneTests = []contractTest{
+	{"PhoneOTP", testPhoneOTP},
+	{"PhoneOTPRejected", testPhoneOTPRejected},
+	{"SMSPreferences", testSMSPreferences},
+}
+
+// phoneOTP menyimpan kode OTP user untuk nomor phone yang berlaku sampai expiresAt.
+func phoneOTP(t *testing.T, db Repos, userID int, purpose, phone string, expiresAt time.Time) *models.PhoneOTP {
+	t.Helper()
+	otp := &models.PhoneOTP{UserID: userID, Purpose: purpose, CodeHash: "code-hash", ExpiresAt: expiresAt}
+	require.NoError(t, db.Users.CreatePhoneOTP(context.Background(), otp, phone))
+	return otp
+}
+
+func testPhoneOTP(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	expires := time.Now().Add(5 * time.Minute)
+
+	first := phoneOTP(t, db, user.ID, models.OTPPurposeVerifyPhone, *user.Phone, expires)
+	assert.NotZero(t, first.ID)
+	assert.False(t, first.CreatedAt.IsZero())
+	login := phoneOTP(t, db, user.ID, models.OTPPurposeLogin, *user.Phone, expires)
+
+	// Kode baru dengan tujuan sama menggantikan kode terbuka sebelumnya.
+	second := phoneOTP(t, db, user.ID, models.OTPPurposeVerifyPhone, *user.Phone, expires)
+	open, err := db.Users.GetOpenPhoneOTP(ctx, user.ID, models.OTPPurposeVerifyPhone)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, open.ID)
+	assert.Zero(t, open.Attempts)
+	_, err = db.Users.RecordPhoneOTPAttempt(ctx, first.ID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "replaced code is gone")
+
+	for want := 1; want <= 2; want++ {
+		attempts, err := db.Users.RecordPhoneOTPAttempt(ctx, second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, attempts)
+	}
+
+	require.NoError(t, db.Users.ConsumePhoneOTP(ctx, second.ID))
+	got, err := db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, got.PhoneVerifiedAt, "verify_phone marks the number verified")
+	_, err = db.Users.GetOpenPhoneOTP(ctx, user.ID, models.OTPPurposeVerifyPhone)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	open, err = db.Users.GetOpenPhoneOTP(ctx, user.ID, models.OTPPurposeLogin)
+	require.NoError(t, err, "other purposes are kept")
+	assert.Equal(t, login.ID, open.ID)
+	require.NoError(t, db.Users.ConsumePhoneOTP(ctx, login.ID))
+}
+
+func testPhoneOTPRejected(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	expires := time.Now().Add(5 * time.Minute)
+
+	consumed := phoneOTP(t, db, user.ID, models.OTPPurposeLogin, *user.Phone, expires)
+	require.NoError(t, db.Users.ConsumePhoneOTP(ctx, consumed.ID))
+	expired := phoneOTP(t, db, other.ID, models.OTPPurposeLogin, *other.Phone, time.Now().Add(-time.Minute))
+	// Kode dikirim ke nomor lain (nomor user berganti sesudahnya).
+	changed := phoneOTP(t, db, user.ID, models.OTPPurposeVerifyPhone, "+6281299999999", expires)
+	changedOther := phoneOTP(t, db, other.ID, models.OTPPurposeVerifyPhone, "+6281299999998", expires)
+
+	tests := []struct {
+		name    string
+		id      int
+		wantErr error
+	}{
+		{name: "already consumed", id: consumed.ID, wantErr: pgx.ErrNoRows},
+		{name: "expired", id: expired.ID, wantErr: pgx.ErrNoRows},
+		{name: "unknown code", id: missingID, wantErr: pgx.ErrNoRows},
+		{name: "phone changed", id: changed.ID, wantErr: repository.ErrPhoneChanged},
+		{name: "phone changed for another user", id: changedOther.ID, wantErr: repository.ErrPhoneChanged},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, db.Users.ConsumePhoneOTP(ctx, tt.id), tt.wantErr)
+		})
+	}
+
+	open, err := db.Users.GetOpenPhoneOTP(ctx, user.ID, models.OTPPurposeVerifyPhone)
+	require.NoError(t, err, "a rejected code stays open")
+	assert.Equal(t, changed.ID, open.ID)
+	got, err := db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.PhoneVerifiedAt)
+
+	err = db.Users.CreatePhoneOTP(ctx, &models.PhoneOTP{UserID: missingID, Purpose: models.OTPPurposeLogin, CodeHash: "x", ExpiresAt: expires}, "+6281200000000")
+	assert.ErrorIs(t, err, pgx.ErrNoRows, "unknown user")
+	_, err = db.Users.GetOpenPhoneOTP(ctx, other.ID, models.OTPPurposeLogin)
+	require.NoError(t, err, "expired codes are still open")
+}
+
+func testSMSPreferences(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	on, off := true, false
+
+	tests := []struct {
+		name    string
+		userID  int
+		input   models.UpdateSMSPreferencesInput
+		wantErr error
+	}{
+		{name: "enable two factor on an unverified phone", userID: user.ID, input: models.UpdateSMSPreferencesInput{TwoFactor: &on}, wantErr: repository.ErrPhoneNotVerified},
+		{name: "enable reminders on an unverified phone", userID: user.ID, input: models.UpdateSMSPreferencesInput{Reminders: &on, TwoFactor: &off}, wantErr: repository.ErrPhoneNotVerified},
+		{name: "disable on an unverified phone", userID: user.ID, input: models.UpdateSMSPreferencesInput{TwoFactor: &off, Reminders: &off}},
+		{name: "unknown user", userID: missingID, input: models.UpdateSMSPreferencesInput{TwoFactor: &off}, wantErr: pgx.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Users.UpdateSMSPreferences(ctx, tt.userID, &tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	otp := phoneOTP(t, db, user.ID, models.OTPPurposeVerifyPhone, *user.Phone, time.Now().Add(5*time.Minute))
+	require.NoError(t, db.Users.ConsumePhoneOTP(ctx, otp.ID))
+	updated, err := db.Users.UpdateSMSPreferences(ctx, user.ID, &models.UpdateSMSPreferencesInput{TwoFactor: &on})
+	require.NoError(t, err)
+	assert.True(t, updated.SMSTwoFactor)
+	assert.False(t, updated.SMSReminders)
+	assert.Equal(t, user.Email, updated.Email, "PII is decrypted")
+
+	updated, err = db.Users.UpdateSMSPreferences(ctx, user.ID, &models.UpdateSMSPreferencesInput{Reminders: &on})
+	require.NoError(t, err)
+	assert.True(t, updated.SMSTwoFactor, "nil keeps the current value")
+	assert.True(t, updated.SMSReminders)
+}
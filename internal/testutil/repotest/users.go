@@ -0,0 +1,348 @@
+// internal/testutil/repotest/users.go
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/repository"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var userTests = []contractTest{
+	{"CreateUserDuplicateUsername", testCreateUserDuplicateUsername},
+	{"UpdateUserByIDUniqueViolations", testUpdateUserByIDUniqueViolations},
+	{"GetAllUsersPagination", testGetAllUsersPagination},
+	{"GetUser", testGetUser},
+	{"CreateUserUnknownRole", testCreateUserUnknownRole},
+	{"DeleteUserByID", testDeleteUserByID},
+	{"UpdateUserByID", testUpdateUserByID},
+	{"PatchUserByID", testPatchUserByID},
+	{"BulkUpdateUserRole", testBulkUpdateUserRole},
+	{"UpdateUserProfileAndPassword", testUpdateUserProfileAndPassword},
+	{"AnonymizeUser", testAnonymizeUser},
+	{"SetUserManager", testSetUserManager},
+	{"Sessions", testSessions},
+	{"Invitations", testInvitations},
+	{"InvitationsPagination", testInvitationsPagination},
+	{"EmailChange", testEmailChange},
+	{"UsernameChange", testUsernameChange},
+	{"UserChangesSettle", testUserChangesSettle},
+}
+
+func testCreateUserDuplicateUsername(t *testing.T, db Repos) {
+	existing := db.CreateUser(t)
+
+	_, err := db.Users.CreateUser(context.Background(), fixtures.UserInput(func(in *models.RegisterUserInput) {
+		in.Username = existing.Username
+	}), "test-hash")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "username already taken")
+}
+
+func testUpdateUserByIDUniqueViolations(t *testing.T, db Repos) {
+	ctx := context.Background()
+	first := db.CreateUser(t)
+	second := db.CreateUser(t)
+
+	tests := []struct {
+		name    string
+		modify  func(in *models.AdminUpdateUserInput)
+		wantErr string
+	}{
+		{name: "username taken", modify: func(in *models.AdminUpdateUserInput) { in.Username = first.Username }, wantErr: "username already exists"},
+		// Email disimpan terenkripsi; keunikan dijaga users_email_hash_key.
+		{name: "email taken", modify: func(in *models.AdminUpdateUserInput) { in.Email = first.Email }, wantErr: "email already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &models.AdminUpdateUserInput{
+				Username: second.Username, Email: second.Email, FirstName: second.FirstName, LastName: second.LastName,
+				Phone: second.Phone, RoleID: second.RoleID,
+			}
+			tt.modify(input)
+			err := db.Users.UpdateUserByID(ctx, second.ID, input)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func testGetAllUsersPagination(t *testing.T, db Repos) {
+	ctx := context.Background()
+	// Migrasi bisa menanam user awal; hitung relatif terhadap jumlah yang sudah ada.
+	_, base, err := db.Users.GetAllUsers(ctx, 1, 1)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		db.CreateUser(t)
+	}
+
+	checkPagination(t, base+3, func(page, limit int) ([]models.User, int, error) {
+		users, total, err := db.Users.GetAllUsers(ctx, page, limit)
+		for _, u := range users {
+			assert.NotNil(t, u.Role, "role is joined")
+		}
+		return users, total, err
+	}, func(u models.User) int { return u.ID })
+}
+
+func testGetUser(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+
+	tests := []struct {
+		name string
+		get  func() (*models.User, error)
+	}{
+		{name: "by id", get: func() (*models.User, error) { return db.Users.GetUserByID(ctx, user.ID) }},
+		{name: "by username", get: func() (*models.User, error) { return db.Users.GetUserByUsername(ctx, user.Username) }},
+		// Email dicari lewat blind index, bukan ciphertext.
+		{name: "by email", get: func() (*models.User, error) { return db.Users.GetUserByEmail(ctx, user.Email) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, got.ID)
+			assert.Equal(t, user.Email, got.Email, "email is decrypted")
+			assert.Equal(t, user.Phone, got.Phone, "phone is decrypted")
+		})
+	}
+
+	notFound := []struct {
+		name string
+		get  func() (*models.User, error)
+	}{
+		{name: "unknown id", get: func() (*models.User, error) { return db.Users.GetUserByID(ctx, missingID) }},
+		{name: "unknown username", get: func() (*models.User, error) { return db.Users.GetUserByUsername(ctx, "nobody") }},
+		{name: "unknown email", get: func() (*models.User, error) { return db.Users.GetUserByEmail(ctx, "nobody@example.test") }},
+	}
+	for _, tt := range notFound {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.get()
+			assert.ErrorIs(t, err, pgx.ErrNoRows)
+		})
+	}
+}
+
+func testCreateUserUnknownRole(t *testing.T, db Repos) {
+	_, err := db.Users.CreateUser(context.Background(), fixtures.UserInput(func(in *models.RegisterUserInput) {
+		in.RoleID = missingID
+	}), "test-hash")
+	require.Error(t, err)
+}
+
+func testDeleteUserByID(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+
+	require.NoError(t, db.Users.DeleteUserByID(ctx, user.ID))
+	_, err := db.Users.GetUserByID(ctx, user.ID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+	assert.ErrorIs(t, db.Users.DeleteUserByID(ctx, user.ID), pgx.ErrNoRows, "second delete")
+}
+
+func testUpdateUserByID(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	input := func(version int) *models.AdminUpdateUserInput {
+		return &models.AdminUpdateUserInput{
+			Username: user.Username, Email: "renamed-" + user.Email, FirstName: "Renamed", LastName: user.LastName,
+			Phone: user.Phone, RoleID: fixtures.RoleAdminID, Version: version,
+		}
+	}
+
+	update := input(user.Version)
+	require.NoError(t, db.Users.UpdateUserByID(ctx, user.ID, update))
+	assert.Greater(t, update.Version, user.Version, "version is bumped")
+
+	got, err := db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", got.FirstName)
+	assert.Equal(t, "renamed-"+user.Email, got.Email)
+	assert.Equal(t, fixtures.RoleAdminID, got.RoleID)
+
+	tests := []struct {
+		name    string
+		id      int
+		version int
+		wantErr error
+	}{
+		{name: "stale version", id: user.ID, version: user.Version, wantErr: repository.ErrVersionConflict},
+		{name: "unknown user", id: missingID, version: 0, wantErr: pgx.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, db.Users.UpdateUserByID(ctx, tt.id, input(tt.version)), tt.wantErr)
+		})
+	}
+}
+
+func testPatchUserByID(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+	name := "Patched"
+	empty := ""
+	unknownRole := missingID
+
+	version, err := db.Users.PatchUserByID(ctx, user.ID, &models.AdminPatchUserInput{FirstName: &name, Phone: &empty, Version: user.Version})
+	require.NoError(t, err)
+	assert.Greater(t, version, user.Version)
+	got, err := db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Patched", got.FirstName)
+	assert.Equal(t, user.LastName, got.LastName, "fields not sent are kept")
+	assert.Nil(t, got.Phone, "empty phone clears the column")
+
+	tests := []struct {
+		name    string
+		id      int
+		input   *models.AdminPatchUserInput
+		wantErr error
+		wantMsg string
+	}{
+		{name: "no fields", id: user.ID, input: &models.AdminPatchUserInput{}, wantErr: repository.ErrNoFieldsToUpdate},
+		{name: "stale version", id: user.ID, input: &models.AdminPatchUserInput{FirstName: &name, Version: user.Version}, wantErr: repository.ErrVersionConflict},
+		{name: "unknown user", id: missingID, input: &models.AdminPatchUserInput{FirstName: &name}, wantErr: pgx.ErrNoRows},
+		{name: "username taken", id: user.ID, input: &models.AdminPatchUserInput{Username: &other.Username}, wantMsg: "username already exists"},
+		{name: "email taken", id: user.ID, input: &models.AdminPatchUserInput{Email: &other.Email}, wantMsg: "email already exists"},
+		{name: "unknown role", id: user.ID, input: &models.AdminPatchUserInput{RoleID: &unknownRole}, wantMsg: "invalid role_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Users.PatchUserByID(ctx, tt.id, tt.input)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
+
+func testBulkUpdateUserRole(t *testing.T, db Repos) {
+	ctx := context.Background()
+	employee := db.CreateUser(t)
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+
+	results, err := db.Users.BulkUpdateUserRole(ctx, []int{employee.ID, admin.ID, missingID, employee.ID}, fixtures.RoleAdminID)
+	require.NoError(t, err)
+	assert.Equal(t, []models.BulkItemResult{
+		{ID: employee.ID, Status: models.BulkStatusUpdated},
+		{ID: admin.ID, Status: models.BulkStatusUnchanged},
+		{ID: missingID, Status: models.BulkStatusNotFound},
+	}, results, "duplicates are dropped, input order is kept")
+
+	got, err := db.Users.GetUserByID(ctx, employee.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fixtures.RoleAdminID, got.RoleID)
+
+	_, err = db.Users.BulkUpdateUserRole(ctx, []int{employee.ID}, missingID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid role_id")
+}
+
+func testUpdateUserProfileAndPassword(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	other := db.CreateUser(t)
+
+	require.NoError(t, db.Users.UpdateUserPassword(ctx, user.ID, "new-hash"))
+	got, err := db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "new-hash", got.Password)
+	assert.ErrorIs(t, db.Users.UpdateUserPassword(ctx, missingID, "new-hash"), pgx.ErrNoRows)
+
+	profile := func(modify func(in *models.UpdateProfileInput)) *models.UpdateProfileInput {
+		in := &models.UpdateProfileInput{Username: user.Username, Email: user.Email, FirstName: "Self", LastName: user.LastName, Phone: user.Phone}
+		if modify != nil {
+			modify(in)
+		}
+		return in
+	}
+	require.NoError(t, db.Users.UpdateUserProfile(ctx, user.ID, profile(nil)))
+	got, err = db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Self", got.FirstName)
+
+	tests := []struct {
+		name    string
+		id      int
+		input   *models.UpdateProfileInput
+		wantErr error
+		wantMsg string
+	}{
+		{name: "unknown user", id: missingID, input: profile(nil), wantErr: pgx.ErrNoRows},
+		{name: "username taken", id: user.ID, input: profile(func(in *models.UpdateProfileInput) { in.Username = other.Username }), wantMsg: "username already exists"},
+		{name: "email taken", id: user.ID, input: profile(func(in *models.UpdateProfileInput) { in.Email = other.Email }), wantMsg: "email already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := db.Users.UpdateUserProfile(ctx, tt.id, tt.input)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
+
+func testAnonymizeUser(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+
+	require.NoError(t, db.Users.AnonymizeUser(ctx, user.ID))
+	got, err := db.Users.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, user.Username, got.Username)
+	assert.NotEqual(t, user.Email, got.Email)
+	assert.Nil(t, got.Phone)
+
+	assert.ErrorIs(t, db.Users.AnonymizeUser(ctx, user.ID), repository.ErrUserAlreadyAnonymized)
+	assert.ErrorIs(t, db.Users.AnonymizeUser(ctx, missingID), pgx.ErrNoRows)
+}
+
+func testSetUserManager(t *testing.T, db Repos) {
+	ctx := context.Background()
+	boss := db.CreateUser(t)
+	lead := db.CreateUser(t)
+	staff := db.CreateUser(t)
+	require.NoError(t, db.Users.SetUserManager(ctx, lead.ID, &boss.ID))
+	require.NoError(t, db.Users.SetUserManager(ctx, staff.ID, &lead.ID))
+
+	inLine, err := db.Users.IsInReportingLine(ctx, boss.ID, staff.ID)
+	require.NoError(t, err)
+	assert.True(t, inLine, "indirect report")
+	inLine, err = db.Users.IsInReportingLine(ctx, staff.ID, boss.ID)
+	require.NoError(t, err)
+	assert.False(t, inLine)
+
+	unknown := missingID
+	tests := []struct {
+		name      string
+		userID    int
+		managerID *int
+		wantErr   error
+	}{
+		{name: "self", userID: boss.ID, managerID: &boss.ID, wantErr: repository.ErrManagerCycle},
+		{name: "subordinate", userID: boss.ID, managerID: &staff.ID, wantErr: repository.ErrManagerCycle},
+		{name: "unknown manager", userID: staff.ID, managerID: &unknown, wantErr: pgx.ErrNoRows},
+		{name: "unknown user", userID: missingID, managerID: &boss.ID, wantErr: pgx.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, db.Users.SetUserManager(ctx, tt.userID, tt.managerID), tt.wantErr)
+		})
+	}
+
+	require.NoError(t, db.Users.SetUserManager(ctx, staff.ID, nil), "clearing the manager")
+	inLine, err = db.Users.IsInReportingLine(ctx, boss.ID, staff.ID)
+	require.NoError(t, err)
+	assert.False(t, inLine)
+}
@@ -0,0 +1,154 @@
+// internal/testutil/repotest/verifications.go
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var verificationTests = []contractTest{
+	{"VerificationLinks", testVerificationLinks},
+	{"VerificationAccesses", testVerificationAccesses},
+	{"EmploymentVerification", testEmploymentVerification},
+}
+
+// verificationLink membuat tautan verifikasi milik userID yang berlaku 72 jam.
+func verificationLink(t *testing.T, db Repos, userID int) *models.EmploymentVerificationLink {
+	t.Helper()
+	link := &models.EmploymentVerificationLink{UserID: userID, Recipient: "Bank", ExpiresAt: time.Now().Add(72 * time.Hour).UTC().Truncate(time.Second)}
+	require.NoError(t, db.Verifications.CreateVerificationLink(context.Background(), link))
+	return link
+}
+
+func testVerificationLinks(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	admin := db.CreateUser(t, fixtures.AsAdmin)
+	links, err := db.Verifications.GetVerificationLinksByUser(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	link := &models.EmploymentVerificationLink{UserID: user.ID, Recipient: "Landlord", IncludeAttendance: true, ExpiresAt: expiresAt, CreatedBy: &admin.ID}
+	require.NoError(t, db.Verifications.CreateVerificationLink(ctx, link))
+	assert.NotZero(t, link.ID)
+	assert.False(t, link.CreatedAt.IsZero())
+	newer := verificationLink(t, db, user.ID)
+
+	got, err := db.Verifications.GetVerificationLink(ctx, link.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Landlord", got.Recipient)
+	assert.True(t, got.IncludeAttendance)
+	assert.True(t, expiresAt.Equal(got.ExpiresAt))
+	assert.Equal(t, &admin.ID, got.CreatedBy)
+	assert.Zero(t, got.AccessCount)
+
+	links, err = db.Verifications.GetVerificationLinksByUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	assert.Equal(t, newer.ID, links[0].ID, "newest first")
+
+	revoked, err := db.Verifications.RevokeVerificationLink(ctx, link.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, revoked.RevokedAt)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "create for unknown user", call: func() error {
+			return db.Verifications.CreateVerificationLink(ctx, &models.EmploymentVerificationLink{UserID: missingID, Recipient: "Bank", ExpiresAt: expiresAt})
+		}},
+		{name: "get unknown link", call: func() error { _, err := db.Verifications.GetVerificationLink(ctx, missingID); return err }},
+		{name: "revoke twice", call: func() error { _, err := db.Verifications.RevokeVerificationLink(ctx, link.ID); return err }},
+		{name: "revoke unknown link", call: func() error { _, err := db.Verifications.RevokeVerificationLink(ctx, missingID); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), pgx.ErrNoRows)
+		})
+	}
+}
+
+func testVerificationAccesses(t *testing.T, db Repos) {
+	ctx := context.Background()
+	link := verificationLink(t, db, db.CreateUser(t).ID)
+	accesses, err := db.Verifications.GetVerificationAccesses(ctx, link.ID)
+	require.NoError(t, err)
+	assert.Empty(t, accesses)
+
+	agent := "curl/8"
+	for _, outcome := range []string{models.VerificationAccessGranted, models.VerificationAccessExpired, models.VerificationAccessGranted} {
+		access := &models.EmploymentVerificationAccess{LinkID: link.ID, IP: "203.0.113.7", UserAgent: &agent, Outcome: outcome}
+		require.NoError(t, db.Verifications.RecordVerificationAccess(ctx, access))
+		assert.NotZero(t, access.ID)
+	}
+
+	tests := []struct {
+		name   string
+		access *models.EmploymentVerificationAccess
+	}{
+		{name: "unknown link", access: &models.EmploymentVerificationAccess{LinkID: missingID, IP: "203.0.113.7", Outcome: models.VerificationAccessGranted}},
+		{name: "unknown outcome", access: &models.EmploymentVerificationAccess{LinkID: link.ID, IP: "203.0.113.7", Outcome: "denied"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, db.Verifications.RecordVerificationAccess(ctx, tt.access))
+		})
+	}
+
+	got, err := db.Verifications.GetVerificationLink(ctx, link.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.AccessCount, "only granted accesses are counted")
+	assert.NotNil(t, got.LastAccessedAt)
+
+	accesses, err = db.Verifications.GetVerificationAccesses(ctx, link.ID)
+	require.NoError(t, err)
+	require.Len(t, accesses, 3)
+	assert.Greater(t, accesses[0].ID, accesses[2].ID, "newest first")
+	assert.Equal(t, &agent, accesses[0].UserAgent)
+}
+
+func testEmploymentVerification(t *testing.T, db Repos) {
+	ctx := context.Background()
+	user := db.CreateUser(t)
+	inactive := db.CreateUser(t)
+	db.DeactivateUser(t, inactive.ID)
+	role, err := db.Roles.GetRoleByID(ctx, fixtures.RoleEmployeeID)
+	require.NoError(t, err)
+	now := time.Now()
+	// Dua sesi pada tanggal yang sama dihitung satu hari hadir.
+	for _, at := range []time.Time{fixtures.Day(now, -5).Add(11 * time.Hour), fixtures.Day(now, -5).Add(13 * time.Hour), fixtures.Day(now, -3).Add(12 * time.Hour)} {
+		id := db.CheckIn(t, user.ID, at)
+		require.NoError(t, db.Attendances.UpdateCheckOut(ctx, id, at.Add(time.Minute), nil))
+	}
+
+	v, err := db.Verifications.GetEmploymentVerification(ctx, user.ID, nil)
+	require.NoError(t, err)
+	assert.True(t, v.Employed)
+	assert.Equal(t, role.Name, v.Position)
+	assert.Equal(t, models.EmploymentStatusPermanent, v.EmploymentStatus)
+	assert.NotEmpty(t, v.EmployeeName)
+	assert.Nil(t, v.Attendance, "attendance not requested")
+
+	since := fixtures.Day(now, -30)
+	v, err = db.Verifications.GetEmploymentVerification(ctx, user.ID, &since)
+	require.NoError(t, err)
+	require.NotNil(t, v.Attendance)
+	assert.Equal(t, since.Format(fixtures.DateLayout), v.Attendance.PeriodStart)
+	assert.Equal(t, 2, v.Attendance.DaysPresent)
+
+	v, err = db.Verifications.GetEmploymentVerification(ctx, inactive.ID, nil)
+	require.NoError(t, err)
+	assert.False(t, v.Employed, "inactive user")
+
+	_, err = db.Verifications.GetEmploymentVerification(ctx, missingID, nil)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
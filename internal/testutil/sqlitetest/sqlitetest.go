@@ -0,0 +1,72 @@
+// internal/testutil/sqlitetest/sqlitetest.go
+
+//go:build sqlite
+
+// Package sqlitetest adalah harness test integrasi repository SQLite (build tag sqlite),
+// padanan pgtest untuk DB_DRIVER=sqlite.
+//
+// Setiap pemanggilan New membuka file database baru di t.TempDir(), menjalankan migrasi
+// migrations/sqlite, lalu menyediakan repository yang sudah terhubung. Tidak butuh Docker
+// maupun server database:
+//
+//	go test -tags sqlite ./internal/repository/sqlite/...
+package sqlitetest
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/database"
+	"github.com/rakaarfi/attendance-system-be/internal/pii"
+	sqliterepo "github.com/rakaarfi/attendance-system-be/internal/repository/sqlite"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/repotest"
+)
+
+// DB adalah database test terisolasi beserta repository yang siap dipakai (field & seeder
+// dari repotest.Repos, sama dengan pgtest.DB).
+type DB struct {
+	repotest.Repos
+
+	SQL       *sql.DB
+	Protector *pii.Protector
+}
+
+// New membuka database SQLite baru di direktori sementara test dan menjalankan migrasi.
+func New(t testing.TB) *DB {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	sqlDB, err := database.OpenSQLite(ctx, filepath.Join(t.TempDir(), "attendance_test.db"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("sqlitetest: open database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	protector, err := pii.NewProtector(staticKeys{})
+	if err != nil {
+		t.Fatalf("sqlitetest: pii protector: %v", err)
+	}
+	return &DB{
+		Repos:     repotest.New(sqliterepo.NewRepositories(sqlDB, protector)),
+		SQL:       sqlDB,
+		Protector: protector,
+	}
+}
+
+// --- Internal ---
+
+// staticKeys adalah KeyProvider dengan kunci tetap khusus test (bukan untuk production).
+type staticKeys struct{}
+
+func (staticKeys) Keyring() (pii.Keyring, error) {
+	return pii.Keyring{
+		ActiveKeyID: "test",
+		DataKeys:    map[string][]byte{"test": []byte(strings.Repeat("k", 32))},
+		IndexKey:    []byte(strings.Repeat("i", 32)),
+	}, nil
+}
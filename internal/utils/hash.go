@@ -1,27 +1,150 @@
 // internal/utils/hash.go
 package utils
 
-import "golang.org/x/crypto/bcrypt" // Paket Go standar (sub-repositori) untuk hashing password bcrypt.
+import (
+	"crypto/rand"     // Sumber salt acak yang aman secara kriptografis.
+	"crypto/subtle"   // Perbandingan constant-time (tahan timing attack).
+	"encoding/base64" // Encoding salt & hash di dalam string PHC.
+	"fmt"
+	"strings"
 
-// HashPassword menghasilkan hash bcrypt dari string password yang diberikan.
-// Menggunakan cost default bcrypt untuk keseimbangan antara keamanan dan performa.
-// Mengembalikan hash sebagai string atau error jika terjadi masalah saat hashing.
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"golang.org/x/crypto/argon2" // Implementasi Argon2id (sub-repositori Go).
+	"golang.org/x/crypto/bcrypt" // Masih dibutuhkan untuk memverifikasi hash lama (bcrypt).
+)
+
+// Argon2Params menampung parameter hashing Argon2id.
+type Argon2Params struct {
+	Memory      uint32 // Memori dalam KiB.
+	Iterations  uint32 // Jumlah iterasi (time cost).
+	Parallelism uint8  // Jumlah thread.
+	SaltLength  uint32 // Panjang salt dalam byte.
+	KeyLength   uint32 // Panjang hash yang dihasilkan dalam byte.
+}
+
+// argon2Params adalah parameter yang dipakai untuk hash baru dan pengecekan rehash.
+// Diisi sekali saat startup lewat ConfigurePasswordHashing (ARGON2_*), default OWASP.
+var argon2Params = argon2ParamsFromConfig(configs.DefaultPasswordHashConfig())
+
+// argon2ParamsFromConfig mengonversi konfigurasi yang sudah divalidasi menjadi Argon2Params.
+func argon2ParamsFromConfig(cfg configs.PasswordHashConfig) Argon2Params {
+	return Argon2Params{
+		Memory:      cfg.MemoryKB,
+		Iterations:  cfg.Iterations,
+		Parallelism: cfg.Parallelism,
+		SaltLength:  cfg.SaltLength,
+		KeyLength:   cfg.KeyLength,
+	}
+}
+
+// ConfigurePasswordHashing mengganti parameter Argon2id untuk hash baru. cfg harus berasal dari
+// configs.LoadPasswordHashConfig (sudah divalidasi). Dipanggil sekali saat startup, sebelum server
+// menerima request.
+func ConfigurePasswordHashing(cfg configs.PasswordHashConfig) {
+	argon2Params = argon2ParamsFromConfig(cfg)
+}
+
+// HashPassword menghasilkan hash Argon2id dari password yang diberikan.
+// Hash dikembalikan dalam format PHC standar:
+//
+//	$argon2id$v=19$m=65536,t=3,p=2$<salt-base64>$<hash-base64>
+//
+// sehingga parameter yang dipakai ikut tersimpan dan bisa diverifikasi ulang nanti.
 func HashPassword(password string) (string, error) {
-	// GenerateFromPassword meng-hash password menggunakan salt acak (sudah termasuk dalam hash).
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		// Error jarang terjadi kecuali ada masalah sistem atau password terlalu panjang.
-		return "", err // Kembalikan error asli
+	p := argon2Params
+
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("error generating salt: %w", err)
 	}
-	return string(bytes), nil // Kembalikan hash dalam bentuk string
+
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+
+	encoded := fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+	return encoded, nil
 }
 
-// CheckPasswordHash membandingkan password plaintext dengan hash bcrypt yang sudah ada.
-// Fungsi ini secara otomatis mengekstrak salt dari hash dan melakukan perbandingan yang aman.
-// Mengembalikan true jika password cocok dengan hash, false jika tidak atau jika ada error.
+// CheckPasswordHash membandingkan password plaintext dengan hash yang tersimpan.
+// Mendukung hash Argon2id (format baru) maupun bcrypt (format lama), sehingga user
+// lama tetap bisa login sebelum hash-nya dimigrasikan.
+// Mengembalikan true jika password cocok, false jika tidak atau jika hash rusak.
 func CheckPasswordHash(password, hash string) bool {
-	// CompareHashAndPassword adalah cara yang aman untuk membandingkan, tahan terhadap timing attacks.
+	if isArgon2Hash(hash) {
+		p, salt, key, err := decodeArgon2Hash(hash)
+		if err != nil {
+			return false
+		}
+		otherKey := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+		return subtle.ConstantTimeCompare(key, otherKey) == 1
+	}
+
+	// Fallback ke bcrypt untuk hash lama.
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	// Mengembalikan true hanya jika err adalah nil (tidak ada error, berarti cocok).
 	return err == nil
 }
+
+// PasswordNeedsRehash mengembalikan true jika hash tersimpan harus diperbarui:
+// hash masih bcrypt, atau hash Argon2id dibuat dengan parameter yang berbeda
+// dari konfigurasi saat ini. Dipanggil setelah login berhasil (rehash-on-login).
+func PasswordNeedsRehash(hash string) bool {
+	if !isArgon2Hash(hash) {
+		return true
+	}
+	p, _, key, err := decodeArgon2Hash(hash)
+	if err != nil {
+		return true
+	}
+	current := argon2Params
+	return p.Memory != current.Memory ||
+		p.Iterations != current.Iterations ||
+		p.Parallelism != current.Parallelism ||
+		uint32(len(key)) != current.KeyLength
+}
+
+// isArgon2Hash mengecek prefix format PHC Argon2id.
+func isArgon2Hash(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+// decodeArgon2Hash mem-parsing string PHC Argon2id menjadi parameter, salt, dan key.
+func decodeArgon2Hash(hash string) (p Argon2Params, salt, key []byte, err error) {
+	// Format: ["", "argon2id", "v=19", "m=..,t=..,p=..", "<salt>", "<key>"]
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		err = fmt.Errorf("invalid argon2 hash format")
+		return
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		err = fmt.Errorf("invalid argon2 version: %w", err)
+		return
+	}
+	if version != argon2.Version {
+		err = fmt.Errorf("incompatible argon2 version %d", version)
+		return
+	}
+
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		err = fmt.Errorf("invalid argon2 parameters: %w", err)
+		return
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		err = fmt.Errorf("invalid argon2 salt: %w", err)
+		return
+	}
+	p.SaltLength = uint32(len(salt))
+
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		err = fmt.Errorf("invalid argon2 key: %w", err)
+		return
+	}
+	p.KeyLength = uint32(len(key))
+
+	return p, salt, key, nil
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testHashConfig adalah parameter kecil agar test cepat; bentuk hash tetap sama dengan produksi.
+var testHashConfig = configs.PasswordHashConfig{MemoryKB: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+// withHashConfig memasang cfg selama test berjalan lalu mengembalikan parameter sebelumnya.
+func withHashConfig(t *testing.T, cfg configs.PasswordHashConfig) {
+	t.Helper()
+	previous := argon2Params
+	ConfigurePasswordHashing(cfg)
+	t.Cleanup(func() { argon2Params = previous })
+}
+
+func TestHashPassword(t *testing.T) {
+	withHashConfig(t, testHashConfig)
+
+	hash, err := HashPassword("s3cret!")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+
+	again, err := HashPassword("s3cret!")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, again, "salt must be random per hash")
+}
+
+func TestCheckPasswordHash(t *testing.T) {
+	withHashConfig(t, testHashConfig)
+
+	argonHash, err := HashPassword("s3cret!")
+	require.NoError(t, err)
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("s3cret!"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		password string
+		hash     string
+		want     bool
+	}{
+		{"argon2 match", "s3cret!", argonHash, true},
+		{"argon2 mismatch", "wrong", argonHash, false},
+		{"bcrypt legacy match", "s3cret!", string(bcryptHash), true},
+		{"bcrypt legacy mismatch", "wrong", string(bcryptHash), false},
+		{"malformed argon2", "s3cret!", "$argon2id$v=19$m=64", false},
+		{"unsupported argon2 version", "s3cret!", strings.Replace(argonHash, "v=19", "v=16", 1), false},
+		{"empty hash", "s3cret!", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CheckPasswordHash(tt.password, tt.hash))
+		})
+	}
+}
+
+func TestCheckPasswordHashAfterParamsChange(t *testing.T) {
+	withHashConfig(t, testHashConfig)
+	hash, err := HashPassword("s3cret!")
+	require.NoError(t, err)
+
+	// Hash lama tetap bisa diverifikasi karena parameternya tersimpan di string PHC.
+	changed := testHashConfig
+	changed.Iterations = 2
+	ConfigurePasswordHashing(changed)
+	assert.True(t, CheckPasswordHash("s3cret!", hash))
+}
+
+func TestPasswordNeedsRehash(t *testing.T) {
+	withHashConfig(t, testHashConfig)
+
+	current, err := HashPassword("s3cret!")
+	require.NoError(t, err)
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("s3cret!"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		hash   string
+		modify func(*configs.PasswordHashConfig)
+		want   bool
+	}{
+		{"current params", current, nil, false},
+		{"bcrypt upgraded to argon2", string(bcryptHash), nil, true},
+		{"malformed hash", "$argon2id$broken", nil, true},
+		{"memory changed", current, func(c *configs.PasswordHashConfig) { c.MemoryKB = 128 }, true},
+		{"iterations changed", current, func(c *configs.PasswordHashConfig) { c.Iterations = 2 }, true},
+		{"parallelism changed", current, func(c *configs.PasswordHashConfig) { c.Parallelism = 2 }, true},
+		{"key length changed", current, func(c *configs.PasswordHashConfig) { c.KeyLength = 64 }, true},
+		// Salt tidak memengaruhi kekuatan hash yang sudah ada.
+		{"salt length changed", current, func(c *configs.PasswordHashConfig) { c.SaltLength = 32 }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testHashConfig
+			if tt.modify != nil {
+				tt.modify(&cfg)
+			}
+			ConfigurePasswordHashing(cfg)
+			assert.Equal(t, tt.want, PasswordNeedsRehash(tt.hash))
+		})
+	}
+}
+
+func TestRehashUpgradesLegacyHash(t *testing.T) {
+	withHashConfig(t, testHashConfig)
+
+	// Alur rehash-on-login: hash bcrypt diganti hash Argon2id yang tidak perlu di-rehash lagi.
+	legacy, err := bcrypt.GenerateFromPassword([]byte("s3cret!"), bcrypt.MinCost)
+	require.NoError(t, err)
+	require.True(t, CheckPasswordHash("s3cret!", string(legacy)))
+	require.True(t, PasswordNeedsRehash(string(legacy)))
+
+	upgraded, err := HashPassword("s3cret!")
+	require.NoError(t, err)
+	assert.True(t, CheckPasswordHash("s3cret!", upgraded))
+	assert.False(t, PasswordNeedsRehash(upgraded))
+}
@@ -2,24 +2,47 @@
 package utils
 
 import (
-	"fmt"     // Untuk formatting error dan string
-	"os"      // Untuk membaca environment variable (JWT_SECRET)
-	"strconv" // Untuk konversi string ke integer (ExtractUserIDFromParam)
-	"strings" // Untuk manipulasi string (ExtractToken)
-	"time"    // Untuk menentukan waktu kedaluwarsa token
-
-	"github.com/gofiber/fiber/v2"    // Framework Fiber, digunakan untuk context (c *fiber.Ctx)
-	"github.com/golang-jwt/jwt/v5"   // Library populer untuk membuat dan memvalidasi JWT
-	zlog "github.com/rs/zerolog/log" // Logger global Zerolog
+	"crypto/sha256" // Untuk hash fingerprint perangkat kiosk
+	"encoding/hex"  // Untuk encoding hash fingerprint
+	"fmt"           // Untuk formatting error dan string
+	"os"            // Untuk membaca environment variable (JWT_SECRET)
+	"slices"        // Untuk memeriksa audience token tautan verifikasi & scope token
+	"strconv"       // Untuk konversi string ke integer (ExtractUserIDFromParam)
+	"strings"       // Untuk manipulasi string (ExtractToken)
+	"time"          // Untuk menentukan waktu kedaluwarsa token
+
+	"github.com/gofiber/fiber/v2"                      // Framework Fiber, digunakan untuk context (c *fiber.Ctx)
+	"github.com/golang-jwt/jwt/v5"                     // Library populer untuk membuat dan memvalidasi JWT
+	"github.com/rakaarfi/attendance-system-be/configs" // Konfigurasi masa berlaku, issuer & audience token
+	zlog "github.com/rs/zerolog/log"                   // Logger global Zerolog
 )
 
 // JwtClaims mendefinisikan struktur data (payload) yang akan disimpan di dalam token JWT.
 // Menyertakan RegisteredClaims standar JWT dan field custom (UserID, Username, Role).
 type JwtClaims struct {
-	UserID               int    `json:"user_id"`  // ID pengguna
-	Username             string `json:"username"` // Username pengguna
-	Role                 string `json:"role"`     // Role pengguna (misal: "Admin", "Employee")
-	jwt.RegisteredClaims        // Menyematkan claims standar JWT (ExpiresAt, IssuedAt, Issuer, dll.)
+	UserID               int              `json:"user_id"`                // ID pengguna
+	Username             string           `json:"username"`               // Username pengguna
+	Role                 string           `json:"role"`                   // Role pengguna (misal: "Admin", "Employee")
+	AccessUntil          *jwt.NumericDate `json:"access_until,omitempty"` // Akhir masa akses contractor (nil = tidak dibatasi)
+	TokenVersion         int              `json:"tv"`                     // users.token_version saat token dibuat (pencabutan sesi)
+	Scopes               []string         `json:"scp,omitempty"`          // Scope token terbatas (nil = token sesi penuh, lihat GenerateScopedJWT)
+	DeviceID             int              `json:"did,omitempty"`          // Perangkat kiosk pemilik token (0 = tidak terikat perangkat)
+	DeviceFingerprint    string           `json:"dfp,omitempty"`          // Hash fingerprint perangkat kiosk (HashDeviceFingerprint)
+	jwt.RegisteredClaims                  // Menyematkan claims standar JWT (ExpiresAt, IssuedAt, Issuer, dll.)
+}
+
+// AllowsScope melaporkan apakah token boleh dipakai untuk aksi yang menerima salah satu scopes.
+// Token sesi penuh (tanpa claim scp) selalu diizinkan.
+func (c *JwtClaims) AllowsScope(scopes ...string) bool {
+	if c.Scopes == nil {
+		return true
+	}
+	return slices.ContainsFunc(c.Scopes, func(s string) bool { return slices.Contains(scopes, s) })
+}
+
+// AccessExpired melaporkan apakah masa akses user (AccessUntil) sudah berakhir pada waktu now.
+func (c *JwtClaims) AccessExpired(now time.Time) bool {
+	return c.AccessUntil != nil && !now.Before(c.AccessUntil.Time)
 }
 
 // jwtSecret adalah kunci rahasia yang digunakan untuk menandatangani (sign) dan memverifikasi token JWT.
@@ -27,32 +50,108 @@ type JwtClaims struct {
 // Diinisialisasi saat paket dimuat.
 var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
 
+// jwtConfig adalah masa berlaku, issuer, audience, dan toleransi jam token (lihat ConfigureJWT).
+var jwtConfig = configs.DefaultJWTConfig()
+
+// ConfigureJWT mengganti konfigurasi token dan memuat kunci tanda tangan asimetris (RS256/EdDSA)
+// jika dikonfigurasi. Dipanggil sekali saat startup, sebelum server menerima request. Audience
+// tidak boleh sama dengan tujuan token sekali pakai (Purpose*) agar token tersebut tidak pernah
+// lolos sebagai token sesi.
+func ConfigureJWT(cfg configs.JWTConfig) error {
+	if slices.Contains(purposes, cfg.Audience) {
+		return fmt.Errorf("JWT_AUDIENCE '%s' is reserved", cfg.Audience)
+	}
+	var keys *sessionKeys
+	if cfg.SigningAlg != "" && cfg.SigningAlg != configs.JWTAlgHS256 {
+		loaded, err := loadSessionKeys(cfg)
+		if err != nil {
+			return err
+		}
+		keys = loaded
+		zlog.Info().Str("algorithm", cfg.SigningAlg).Str("kid", keys.kid).Int("keys", len(keys.public)).Msg("Session tokens signed with asymmetric key")
+	}
+	jwtConfig, sessionSigning = cfg, keys
+	return nil
+}
+
+// parserOptions mengembalikan opsi validasi yang berlaku untuk semua token: algoritma alg,
+// issuer yang dikonfigurasi, dan toleransi selisih jam untuk exp/nbf/iat.
+func parserOptions(alg jwt.SigningMethod, extra ...jwt.ParserOption) []jwt.ParserOption {
+	return append([]jwt.ParserOption{
+		jwt.WithValidMethods([]string{alg.Alg()}),
+		jwt.WithIssuer(jwtConfig.Issuer),
+		jwt.WithLeeway(jwtConfig.ClockSkew),
+		jwt.WithIssuedAt(),
+	}, extra...)
+}
+
 // GenerateJWT membuat string token JWT baru yang ditandatangani untuk user tertentu.
-// Menerima ID, username, dan role user sebagai input.
+// Menerima ID, username, dan role user sebagai input, serta accessUntil (akhir masa akses
+// contractor, nil jika tidak dibatasi) dan tokenVersion (users.token_version) yang diperiksa
+// middleware Protected() di setiap request.
 // Mengembalikan string token atau error jika proses signing gagal.
-func GenerateJWT(userID int, username, role string) (string, error) {
-	// Tentukan masa berlaku token (misal: 72 jam dari sekarang).
-	expirationTime := time.Now().Add(72 * time.Hour)
+func GenerateJWT(userID int, username, role string, accessUntil *time.Time, tokenVersion int) (string, error) {
+	// Token login berlaku selama JWT_EXPIRATION_HOURS (default 72 jam) dari sekarang.
+	return generateSessionToken(JwtClaims{UserID: userID, Username: username, Role: role, TokenVersion: tokenVersion}, accessUntil, time.Now().Add(jwtConfig.TTL))
+}
 
-	// Buat instance JwtClaims dengan data user dan claims standar.
-	claims := JwtClaims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime), // Waktu kedaluwarsa
-			IssuedAt:  jwt.NewNumericDate(time.Now()),     // Waktu token dibuat
-			NotBefore: jwt.NewNumericDate(time.Now()),     // Waktu token mulai valid (biasanya sama dengan IssuedAt)
-			Issuer:    "absensi-app",                      // Pengenal aplikasi yang mengeluarkan token (opsional)
-			// Subject: strconv.Itoa(userID), // ID User sebagai subject (opsional)
-		},
+// GenerateScopedJWT membuat token sesi terbatas untuk klien yang tidak butuh seluruh hak user
+// (mis. kiosk yang hanya boleh absen, integrasi laporan yang hanya membaca), berlaku sampai
+// expiresAt. Scope diperiksa middleware RequireScope; seperti token login, token ini terikat ke
+// tokenVersion sehingga ikut tidak berlaku saat sesi user dicabut.
+func GenerateScopedJWT(userID int, username, role string, accessUntil *time.Time, tokenVersion int, scopes []string, expiresAt time.Time) (string, error) {
+	if len(scopes) == 0 {
+		return "", fmt.Errorf("scoped token requires at least one scope")
+	}
+	return generateSessionToken(JwtClaims{UserID: userID, Username: username, Role: role, TokenVersion: tokenVersion, Scopes: scopes}, accessUntil, expiresAt)
+}
+
+// GenerateDeviceJWT membuat token terbatas yang terikat ke perangkat kiosk deviceID dengan hash
+// fingerprint fingerprintHash (lihat HashDeviceFingerprint). Token hanya diterima dari request
+// yang membawa fingerprint yang sama dan selama perangkat belum dicabut (middleware DeviceBound),
+// dan dirotasi otomatis selama dipakai sehingga masa berlakunya bisa dibuat singkat.
+func GenerateDeviceJWT(userID int, username, role string, accessUntil *time.Time, tokenVersion int, scopes []string, deviceID int, fingerprintHash string, expiresAt time.Time) (string, error) {
+	if len(scopes) == 0 || deviceID <= 0 || fingerprintHash == "" {
+		return "", fmt.Errorf("device token requires scopes and a device binding")
+	}
+	return generateSessionToken(JwtClaims{
+		UserID: userID, Username: username, Role: role, TokenVersion: tokenVersion, Scopes: scopes,
+		DeviceID: deviceID, DeviceFingerprint: fingerprintHash,
+	}, accessUntil, expiresAt)
+}
+
+// HashDeviceFingerprint mengembalikan SHA-256 (hex) fingerprint perangkat kiosk. Hanya hash yang
+// disimpan di database dan di claim token.
+func HashDeviceFingerprint(fingerprint string) string {
+	sum := sha256.Sum256([]byte(fingerprint))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateSessionToken menandatangani token sesi dari claims (data user, scope, perangkat)
+// dengan masa berlaku sampai expirationTime.
+func generateSessionToken(claims JwtClaims, accessUntil *time.Time, expirationTime time.Time) (string, error) {
+	// Lengkapi claims dengan claims standar.
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expirationTime), // Waktu kedaluwarsa
+		IssuedAt:  jwt.NewNumericDate(now),            // Waktu token dibuat
+		NotBefore: jwt.NewNumericDate(now),            // Waktu token mulai valid (biasanya sama dengan IssuedAt)
+		Issuer:    jwtConfig.Issuer,                   // Pengenal aplikasi yang mengeluarkan token (JWT_ISSUER)
+		// Subject: strconv.Itoa(userID), // ID User sebagai subject (opsional)
+	}
+	if jwtConfig.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{jwtConfig.Audience} // Penerima token (JWT_AUDIENCE)
+	}
+
+	if accessUntil != nil {
+		claims.AccessUntil = jwt.NewNumericDate(*accessUntil)
 	}
 
-	// Buat token baru dengan claims dan metode signing HS256 (HMAC SHA-256).
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// Buat token baru dengan claims dan metode signing JWT_SIGNING_ALG (default HS256).
+	token := jwt.NewWithClaims(sessionSigningMethod(), claims)
 
-	// Tandatangani token menggunakan jwtSecret.
-	signedToken, err := token.SignedString(jwtSecret)
+	// Tandatangani token menggunakan jwtSecret (HS256) atau kunci privat aktif (RS256/EdDSA).
+	signedToken, err := signSessionToken(token)
 	if err != nil {
 		// Log error jika signing gagal.
 		zlog.Error().Err(err).Msg("Error signing JWT token")
@@ -60,20 +159,21 @@ func GenerateJWT(userID int, username, role string) (string, error) {
 	}
 
 	// Log (debug) bahwa token berhasil dibuat.
-	zlog.Debug().Int("user_id", userID).Str("username", username).Str("role", role).Msg("Generated JWT token")
+	zlog.Debug().Int("user_id", claims.UserID).Str("username", claims.Username).Str("role", claims.Role).Strs("scopes", claims.Scopes).Msg("Generated JWT token")
 	return signedToken, nil // Kembalikan token string
 }
 
 // ValidateJWT memverifikasi token JWT string yang diberikan.
-// Mengecek signature, masa berlaku, dan mem-parsing claims ke dalam struct JwtClaims.
+// Mengecek signature, masa berlaku (exp wajib, nbf & iat jika ada, dengan toleransi
+// JWT_CLOCK_SKEW), issuer, dan audience, lalu mem-parsing claims ke dalam struct JwtClaims.
 // Mengembalikan pointer ke JwtClaims jika valid, atau error jika tidak valid.
 func ValidateJWT(tokenString string) (*JwtClaims, error) {
 	// Parse token string, validasi signature & expiry, dan decode claims ke struct JwtClaims.
 	token, err := jwt.ParseWithClaims(tokenString, &JwtClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// --- Validasi Metode Signing ---
-		// Sangat penting untuk memastikan token menggunakan algoritma yang diharapkan (HS256 dalam kasus ini).
-		// Mencegah serangan penggantian algoritma (misal: ke 'none').
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		// Sangat penting untuk memastikan token menggunakan algoritma yang diharapkan (JWT_SIGNING_ALG).
+		// Mencegah serangan penggantian algoritma (misal: ke 'none', atau HS256 dengan kunci publik).
+		if token.Method.Alg() != sessionSigningMethod().Alg() {
 			// Log peringatan jika algoritma tidak sesuai.
 			algo := "unknown"
 			if algStr, okAlg := token.Header["alg"].(string); okAlg {
@@ -82,9 +182,9 @@ func ValidateJWT(tokenString string) (*JwtClaims, error) {
 			zlog.Warn().Str("algorithm", algo).Msg("Unexpected signing method during JWT validation")
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		// Kembalikan secret key yang benar untuk verifikasi.
-		return jwtSecret, nil
-	})
+		// Kembalikan key yang benar untuk verifikasi (secret HS256 atau kunci publik sesuai kid).
+		return sessionVerifyKey(token)
+	}, sessionParserOptions()...)
 
 	// Handle error saat parsing/validasi awal (misal: format token salah, expired, signature mismatch).
 	if err != nil {
@@ -96,6 +196,13 @@ func ValidateJWT(tokenString string) (*JwtClaims, error) {
 	// Pastikan token secara keseluruhan valid (tidak expired, signature cocok)
 	// dan claims berhasil di-decode ke struct JwtClaims.
 	if claims, ok := token.Claims.(*JwtClaims); ok && token.Valid {
+		// Token sekali pakai (GeneratePurposeToken) selalu membawa audience tujuannya; tanpa
+		// JWT_AUDIENCE token sesi tidak punya audience, jadi token ber-audience ditolak. Dengan
+		// JWT_AUDIENCE, audience lain sudah ditolak parser.
+		if jwtConfig.Audience == "" && len(claims.Audience) > 0 {
+			zlog.Warn().Strs("audience", claims.Audience).Msg("Purpose token used as session token")
+			return nil, fmt.Errorf("invalid token")
+		}
 		// Log (debug) bahwa token valid.
 		zlog.Debug().Str("username", claims.Username).Int("user_id", claims.UserID).Msg("JWT token validated successfully")
 		return claims, nil // Kembalikan pointer ke claims yang valid
@@ -106,6 +213,273 @@ func ValidateJWT(tokenString string) (*JwtClaims, error) {
 	return nil, fmt.Errorf("invalid token")
 }
 
+// sessionParserOptions mengembalikan opsi validasi token sesi: exp wajib, dan audience wajib
+// sama dengan JWT_AUDIENCE jika dikonfigurasi.
+func sessionParserOptions() []jwt.ParserOption {
+	extra := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if jwtConfig.Audience != "" {
+		extra = append(extra, jwt.WithAudience(jwtConfig.Audience))
+	}
+	return parserOptions(sessionSigningMethod(), extra...)
+}
+
+// Tujuan token sekali pakai (claim audience) di luar token sesi. Token ini selalu HS256 dengan
+// JWT_SECRET apa pun JWT_SIGNING_ALG-nya, karena hanya divalidasi aplikasi ini.
+const (
+	PurposeLoginAlert    = "login_alert"    // Tautan "bukan saya" pada email peringatan login
+	PurposePasswordReset = "password_reset" // Reset password setelah sesi dicabut
+	PurposeLoginOTP      = "login_otp"      // Challenge login yang menunggu kode OTP SMS (2FA)
+
+	PurposeEmploymentVerification = "employment_verification" // Tautan publik verifikasi kepegawaian (lihat GenerateVerificationLinkToken)
+	PurposeAttendancePhoto        = "attendance_photo"        // URL sementara foto check-in (lihat GenerateMediaToken)
+	PurposeDocumentUpload         = "document_upload"         // Unggahan dokumen langsung ke storage (lihat GenerateUploadToken)
+	PurposeEmailChange            = "email_change"            // Tautan konfirmasi email baru (lihat GenerateEmailChangeToken)
+	PurposeInvitation             = "invitation"              // Tautan undangan registrasi (lihat GenerateInvitationToken)
+)
+
+// purposes adalah semua audience token sekali pakai; tidak boleh dipakai sebagai JWT_AUDIENCE.
+var purposes = []string{
+	PurposeLoginAlert, PurposePasswordReset, PurposeEmploymentVerification, PurposeAttendancePhoto, PurposeDocumentUpload,
+	PurposeEmailChange, PurposeLoginOTP, PurposeInvitation,
+}
+
+// GeneratePurposeToken membuat token bertanda tangan untuk satu tujuan (purpose) tertentu,
+// berlaku selama ttl. Token terikat ke tokenVersion user, sehingga otomatis tidak berlaku
+// setelah sesi dicabut atau password di-reset. Token ini ditolak oleh ValidateJWT.
+func GeneratePurposeToken(purpose string, userID, tokenVersion int, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := JwtClaims{
+		UserID:       userID,
+		TokenVersion: tokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{purpose},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    jwtConfig.Issuer,
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+	if err != nil {
+		return "", fmt.Errorf("error signing %s token: %w", purpose, err)
+	}
+	return signed, nil
+}
+
+// ValidatePurposeToken memverifikasi token dari GeneratePurposeToken untuk tujuan purpose.
+func ValidatePurposeToken(tokenString, purpose string) (*JwtClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JwtClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	}, parserOptions(jwt.SigningMethodHS256, jwt.WithAudience(purpose))...)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s token: %w", purpose, err)
+	}
+	claims, ok := token.Claims.(*JwtClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid %s token", purpose)
+	}
+	return claims, nil
+}
+
+// GenerateEmailChangeToken membuat token tautan konfirmasi permintaan ganti email requestID
+// (claim jti), berlaku sampai expiresAt. Seperti GeneratePurposeToken, token terikat tokenVersion
+// user sehingga tidak berlaku lagi setelah sesi dicabut.
+func GenerateEmailChangeToken(requestID, userID, tokenVersion int, expiresAt time.Time) (string, error) {
+	claims := JwtClaims{
+		UserID:       userID,
+		TokenVersion: tokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        strconv.Itoa(requestID),
+			Audience:  jwt.ClaimStrings{PurposeEmailChange},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    jwtConfig.Issuer,
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+	if err != nil {
+		return "", fmt.Errorf("error signing %s token: %w", PurposeEmailChange, err)
+	}
+	return signed, nil
+}
+
+// ValidateEmailChangeToken memverifikasi token dari GenerateEmailChangeToken dan mengembalikan
+// claims beserta ID permintaan ganti email.
+func ValidateEmailChangeToken(tokenString string) (*JwtClaims, int, error) {
+	claims, err := ValidatePurposeToken(tokenString, PurposeEmailChange)
+	if err != nil {
+		return nil, 0, err
+	}
+	requestID, err := strconv.Atoi(claims.ID)
+	if err != nil || requestID <= 0 {
+		return nil, 0, fmt.Errorf("invalid %s token id", PurposeEmailChange)
+	}
+	return claims, requestID, nil
+}
+
+// GenerateInvitationToken membuat token tautan undangan invitationID (claim jti), berlaku sampai
+// expiresAt. Token tidak membawa user (akun belum ada); undangan dibatalkan dengan mencabutnya.
+func GenerateInvitationToken(invitationID int, expiresAt time.Time) (string, error) {
+	claims := JwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        strconv.Itoa(invitationID),
+			Audience:  jwt.ClaimStrings{PurposeInvitation},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    jwtConfig.Issuer,
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+	if err != nil {
+		return "", fmt.Errorf("error signing %s token: %w", PurposeInvitation, err)
+	}
+	return signed, nil
+}
+
+// ValidateInvitationToken memverifikasi token dari GenerateInvitationToken dan mengembalikan ID
+// undangannya.
+func ValidateInvitationToken(tokenString string) (int, error) {
+	claims, err := ValidatePurposeToken(tokenString, PurposeInvitation)
+	if err != nil {
+		return 0, err
+	}
+	invitationID, err := strconv.Atoi(claims.ID)
+	if err != nil || invitationID <= 0 {
+		return 0, fmt.Errorf("invalid %s token id", PurposeInvitation)
+	}
+	return invitationID, nil
+}
+
+// GenerateVerificationLinkToken membuat token tautan publik verifikasi kepegawaian linkID milik
+// userID (claim jti = ID tautan), berlaku sampai expiresAt. Berbeda dengan GeneratePurposeToken,
+// token tidak terikat tokenVersion: tautan untuk pihak ketiga tetap berlaku walaupun sesi
+// karyawan dicabut, dan dibatalkan dengan mencabut tautannya.
+func GenerateVerificationLinkToken(linkID, userID int, expiresAt time.Time) (string, error) {
+	claims := JwtClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        strconv.Itoa(linkID),
+			Audience:  jwt.ClaimStrings{PurposeEmploymentVerification},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    jwtConfig.Issuer,
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+	if err != nil {
+		return "", fmt.Errorf("error signing %s token: %w", PurposeEmploymentVerification, err)
+	}
+	return signed, nil
+}
+
+// ParseVerificationLinkToken memverifikasi tanda tangan dan tujuan token dari
+// GenerateVerificationLinkToken lalu mengembalikan ID tautan dan user-nya. Masa berlaku sengaja
+// tidak diperiksa di sini agar akses ke tautan kedaluwarsa tetap tercatat; pemanggil wajib
+// memeriksa expires_at tautan di database.
+func ParseVerificationLinkToken(tokenString string) (linkID, userID int, err error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JwtClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithoutClaimsValidation())
+	if err != nil {
+		return 0, 0, fmt.Errorf("error parsing %s token: %w", PurposeEmploymentVerification, err)
+	}
+	claims, ok := token.Claims.(*JwtClaims)
+	if !ok || !token.Valid || !slices.Contains(claims.Audience, PurposeEmploymentVerification) {
+		return 0, 0, fmt.Errorf("invalid %s token", PurposeEmploymentVerification)
+	}
+	linkID, err = strconv.Atoi(claims.ID)
+	if err != nil || linkID <= 0 {
+		return 0, 0, fmt.Errorf("invalid %s token id", PurposeEmploymentVerification)
+	}
+	return linkID, claims.UserID, nil
+}
+
+// GenerateMediaToken membuat token URL sementara untuk satu varian (claim sub) foto check-in
+// photoID (claim jti), berlaku sampai expiresAt. Token tidak membawa user sehingga URL bisa
+// dipakai langsung di tag <img>; kerahasiaannya dijaga oleh masa berlaku yang singkat.
+func GenerateMediaToken(photoID int, variant string, expiresAt time.Time) (string, error) {
+	claims := JwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        strconv.Itoa(photoID),
+			Subject:   variant,
+			Audience:  jwt.ClaimStrings{PurposeAttendancePhoto},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    jwtConfig.Issuer,
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+	if err != nil {
+		return "", fmt.Errorf("error signing %s token: %w", PurposeAttendancePhoto, err)
+	}
+	return signed, nil
+}
+
+// ParseMediaToken memverifikasi token dari GenerateMediaToken (termasuk masa berlakunya) lalu
+// mengembalikan ID foto dan variannya.
+func ParseMediaToken(tokenString string) (photoID int, variant string, err error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JwtClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	}, parserOptions(jwt.SigningMethodHS256, jwt.WithAudience(PurposeAttendancePhoto), jwt.WithExpirationRequired())...)
+	if err != nil {
+		return 0, "", fmt.Errorf("error parsing %s token: %w", PurposeAttendancePhoto, err)
+	}
+	claims, ok := token.Claims.(*JwtClaims)
+	if !ok || !token.Valid {
+		return 0, "", fmt.Errorf("invalid %s token", PurposeAttendancePhoto)
+	}
+	photoID, err = strconv.Atoi(claims.ID)
+	if err != nil || photoID <= 0 {
+		return 0, "", fmt.Errorf("invalid %s token id", PurposeAttendancePhoto)
+	}
+	return photoID, claims.Subject, nil
+}
+
+// UploadClaims adalah isi token unggahan langsung: storage key (claim sub) beserta pemilik,
+// subjek, dan metadata file yang dijanjikan klien saat meminta URL unggah.
+type UploadClaims struct {
+	UserID      int    `json:"user_id"`
+	SubjectType string `json:"subject_type"`
+	SubjectID   int    `json:"subject_id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+	SHA256      string `json:"sha256"`
+	jwt.RegisteredClaims
+}
+
+// GenerateUploadToken membuat token unggahan langsung untuk storage key, berlaku sampai
+// expiresAt. Key dan metadata tidak bisa diubah klien tanpa merusak tanda tangan.
+func GenerateUploadToken(claims UploadClaims, key string, expiresAt time.Time) (string, error) {
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Subject:   key,
+		Audience:  jwt.ClaimStrings{PurposeDocumentUpload},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		Issuer:    jwtConfig.Issuer,
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+	if err != nil {
+		return "", fmt.Errorf("error signing %s token: %w", PurposeDocumentUpload, err)
+	}
+	return signed, nil
+}
+
+// ParseUploadToken memverifikasi token dari GenerateUploadToken (termasuk masa berlakunya).
+// Storage key ada di claims.Subject.
+func ParseUploadToken(tokenString string) (*UploadClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &UploadClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	}, parserOptions(jwt.SigningMethodHS256, jwt.WithAudience(PurposeDocumentUpload), jwt.WithExpirationRequired())...)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s token: %w", PurposeDocumentUpload, err)
+	}
+	claims, ok := token.Claims.(*UploadClaims)
+	if !ok || !token.Valid || claims.Subject == "" {
+		return nil, fmt.Errorf("invalid %s token", PurposeDocumentUpload)
+	}
+	return claims, nil
+}
+
 // ExtractToken adalah fungsi helper untuk mengambil token string dari header "Authorization".
 // Mengharapkan format "Bearer <token>".
 // Mengembalikan token string atau string kosong jika header tidak ada atau formatnya salah.
@@ -125,7 +499,7 @@ func ExtractToken(c *fiber.Ctx) string {
 	}
 
 	// Log peringatan jika format header salah.
-	zlog.Warn().Str("AuthorizationHeader", authHeader).Msg("Invalid Authorization header format (Expected 'Bearer <token>')")
+	zlog.Ctx(c.UserContext()).Warn().Int("header_length", len(authHeader)).Msg("Invalid Authorization header format (Expected 'Bearer <token>')")
 	return "" // Format salah
 }
 
@@ -137,7 +511,7 @@ func ExtractUserIDFromJWT(c *fiber.Ctx) (int, error) {
 	claims, ok := c.Locals("user").(*JwtClaims)
 	if !ok {
 		// Log error jika claims tidak ditemukan atau tipe salah (menandakan masalah aliran middleware).
-		zlog.Error().Str("path", c.Path()).Msg("Could not extract user claims from Fiber context (middleware issue?)")
+		zlog.Ctx(c.UserContext()).Error().Str("path", c.Path()).Msg("Could not extract user claims from Fiber context (middleware issue?)")
 		return 0, fmt.Errorf("could not extract user claims from context")
 	}
 	// Log (debug) ID yang diekstrak.
@@ -151,7 +525,7 @@ func ExtractUserIDFromJWT(c *fiber.Ctx) (int, error) {
 func ExtractRoleFromJWT(c *fiber.Ctx) (string, error) {
 	claims, ok := c.Locals("user").(*JwtClaims)
 	if !ok {
-		zlog.Error().Str("path", c.Path()).Msg("Could not extract user claims from Fiber context (middleware issue?)")
+		zlog.Ctx(c.UserContext()).Error().Str("path", c.Path()).Msg("Could not extract user claims from Fiber context (middleware issue?)")
 		return "", fmt.Errorf("could not extract user claims from context")
 	}
 	// zlog.Debug().Str("role", claims.Role).Msg("Extracted Role from JWT context")
@@ -164,13 +538,13 @@ func ExtractRoleFromJWT(c *fiber.Ctx) (string, error) {
 func ExtractUserIDFromParam(c *fiber.Ctx, paramName string) (int, error) { // Tambahkan paramName
 	idStr := c.Params(paramName) // Gunakan nama parameter yang dinamis
 	if idStr == "" {
-		zlog.Warn().Str("paramName", paramName).Str("path", c.Path()).Msg("Missing User ID parameter in URL path")
+		zlog.Ctx(c.UserContext()).Warn().Str("paramName", paramName).Str("path", c.Path()).Msg("Missing User ID parameter in URL path")
 		return 0, fmt.Errorf("missing user ID parameter '%s'", paramName)
 	}
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		// Log warning jika parameter bukan angka.
-		zlog.Warn().Err(err).Str("paramName", paramName).Str("value", idStr).Str("path", c.Path()).Msg("Invalid numeric value for User ID parameter")
+		zlog.Ctx(c.UserContext()).Warn().Err(err).Str("paramName", paramName).Str("value", idStr).Str("path", c.Path()).Msg("Invalid numeric value for User ID parameter")
 		return 0, fmt.Errorf("invalid user ID parameter '%s': not a number", paramName)
 	}
 	return id, nil // Kembalikan ID integer
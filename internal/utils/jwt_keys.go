@@ -0,0 +1,190 @@
+// internal/utils/jwt_keys.go
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rakaarfi/attendance-system-be/configs"
+)
+
+// JWK adalah satu kunci publik di JSON Web Key Set (RFC 7517). Field yang tidak relevan untuk
+// jenis kuncinya dikosongkan.
+type JWK struct {
+	Kty string `json:"kty"`           // RSA atau OKP (Ed25519)
+	Kid string `json:"kid"`           // Dicocokkan dengan header kid token
+	Use string `json:"use"`           // Selalu "sig"
+	Alg string `json:"alg"`           // RS256 atau EdDSA
+	N   string `json:"n,omitempty"`   // Modulus RSA (base64url)
+	E   string `json:"e,omitempty"`   // Eksponen RSA (base64url)
+	Crv string `json:"crv,omitempty"` // Kurva OKP (Ed25519)
+	X   string `json:"x,omitempty"`   // Kunci publik Ed25519 (base64url)
+}
+
+// JWKS adalah dokumen /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// sessionKeys adalah kunci tanda tangan token sesi saat JWT_SIGNING_ALG asimetris.
+// nil = HS256 dengan JWT_SECRET.
+type sessionKeys struct {
+	method  jwt.SigningMethod
+	kid     string
+	private crypto.Signer
+	public  map[string]crypto.PublicKey // kid -> kunci publik yang diterima (kunci aktif + kunci lama)
+	jwks    JWKS
+}
+
+var sessionSigning *sessionKeys
+
+// loadSessionKeys membaca kunci privat aktif dan kunci publik lama dari file PEM.
+func loadSessionKeys(cfg configs.JWTConfig) (*sessionKeys, error) {
+	raw, err := os.ReadFile(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading JWT_PRIVATE_KEY_FILE: %w", err)
+	}
+	keys := &sessionKeys{public: map[string]crypto.PublicKey{}, jwks: JWKS{Keys: []JWK{}}}
+	switch cfg.SigningAlg {
+	case configs.JWTAlgRS256:
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RS256 private key: %w", err)
+		}
+		keys.method, keys.private = jwt.SigningMethodRS256, key
+	case configs.JWTAlgEdDSA:
+		key, err := jwt.ParseEdPrivateKeyFromPEM(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EdDSA private key: %w", err)
+		}
+		signer, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("invalid EdDSA private key: not an Ed25519 key")
+		}
+		keys.method, keys.private = jwt.SigningMethodEdDSA, signer
+	default:
+		return nil, fmt.Errorf("JWT_SIGNING_ALG %s has no key pair", cfg.SigningAlg)
+	}
+
+	kid, err := keys.add(keys.private.Public(), cfg.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	keys.kid = kid
+	for _, file := range cfg.PreviousPublicKeyFiles {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading previous JWT public key %s: %w", file, err)
+		}
+		var public crypto.PublicKey
+		if keys.method == jwt.SigningMethodRS256 {
+			public, err = jwt.ParseRSAPublicKeyFromPEM(raw)
+		} else {
+			public, err = jwt.ParseEdPublicKeyFromPEM(raw)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous JWT public key %s: %w", file, err)
+		}
+		if _, err := keys.add(public, ""); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// add mendaftarkan kunci publik untuk validasi & JWKS dan mengembalikan kid-nya.
+func (k *sessionKeys) add(public crypto.PublicKey, kid string) (string, error) {
+	jwk := JWK{Use: "sig", Alg: k.method.Alg()}
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty, jwk.Crv = "OKP", "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	default:
+		return "", fmt.Errorf("unsupported JWT public key type %T", public)
+	}
+	if kid == "" {
+		kid = jwkThumbprint(jwk)
+	}
+	if _, exists := k.public[kid]; exists {
+		return "", fmt.Errorf("duplicate JWT key id '%s'", kid)
+	}
+	jwk.Kid = kid
+	k.public[kid] = public
+	k.jwks.Keys = append(k.jwks.Keys, jwk)
+	return kid, nil
+}
+
+// jwkThumbprint menghitung thumbprint SHA-256 RFC 7638 (member wajib, urut abjad).
+func jwkThumbprint(jwk JWK) string {
+	var canonical []byte
+	if jwk.Kty == "RSA" {
+		canonical, _ = json.Marshal(struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N})
+	} else {
+		canonical, _ = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X})
+	}
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// sessionSigningMethod mengembalikan algoritma tanda tangan token sesi yang aktif.
+func sessionSigningMethod() jwt.SigningMethod {
+	if sessionSigning == nil {
+		return jwt.SigningMethodHS256
+	}
+	return sessionSigning.method
+}
+
+// signSessionToken menandatangani token sesi dengan kunci aktif (header kid untuk kunci asimetris).
+func signSessionToken(token *jwt.Token) (string, error) {
+	if sessionSigning == nil {
+		return token.SignedString(jwtSecret)
+	}
+	token.Header["kid"] = sessionSigning.kid
+	return token.SignedString(sessionSigning.private)
+}
+
+// sessionVerifyKey memilih kunci untuk memverifikasi token sesi berdasarkan header kid.
+// Token tanpa kid diverifikasi dengan kunci aktif.
+func sessionVerifyKey(token *jwt.Token) (interface{}, error) {
+	if sessionSigning == nil {
+		return jwtSecret, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = sessionSigning.kid
+	}
+	key, ok := sessionSigning.public[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key '%s'", kid)
+	}
+	return key, nil
+}
+
+// PublicJWKS mengembalikan kunci publik token sesi untuk /.well-known/jwks.json. Kosong jika
+// token ditandatangani HS256 (secret tidak pernah dipublikasikan).
+func PublicJWKS() JWKS {
+	if sessionSigning == nil {
+		return JWKS{Keys: []JWK{}}
+	}
+	return sessionSigning.jwks
+}
@@ -33,7 +33,7 @@ func ParsePaginationParams(c *fiber.Ctx) PaginationQuery {
 	pageStr := c.Query("page", strconv.Itoa(DefaultPage))
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 { // Halaman tidak boleh kurang dari 1.
-		zlog.Warn().Str("page_query", pageStr).Msg("Invalid page query parameter, using default")
+		zlog.Ctx(c.UserContext()).Warn().Str("page_query", pageStr).Msg("Invalid page query parameter, using default")
 		page = DefaultPage
 	}
 
@@ -41,13 +41,13 @@ func ParsePaginationParams(c *fiber.Ctx) PaginationQuery {
 	limitStr := c.Query("limit", strconv.Itoa(DefaultLimit))
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit < 1 { // Limit tidak boleh kurang dari 1.
-		zlog.Warn().Str("limit_query", limitStr).Msg("Invalid limit query parameter, using default")
+		zlog.Ctx(c.UserContext()).Warn().Str("limit_query", limitStr).Msg("Invalid limit query parameter, using default")
 		limit = DefaultLimit
 	}
 
 	// Batasi limit ke MaxLimit.
 	if limit > MaxLimit {
-		zlog.Warn().Int("requested_limit", limit).Int("max_limit", MaxLimit).Msg("Requested limit exceeds maximum, capping")
+		zlog.Ctx(c.UserContext()).Warn().Int("requested_limit", limit).Int("max_limit", MaxLimit).Msg("Requested limit exceeds maximum, capping")
 		limit = MaxLimit
 	}
 
@@ -0,0 +1,120 @@
+// internal/virusscan/virusscan.go
+package virusscan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Scanner adalah hook pemindaian malware untuk file upload sebelum disimpan.
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) error
+	Provider() string
+}
+
+// ErrInfected dikembalikan Scan jika file terdeteksi mengandung malware.
+var ErrInfected = errors.New("file is infected")
+
+// clamdChunkSize adalah ukuran potongan data yang dikirim ke clamd (INSTREAM).
+const clamdChunkSize = 32 * 1024
+
+// NewScannerFromEnv membuat Scanner berdasarkan environment variables.
+// Mengembalikan nil jika pemindaian tidak diaktifkan (VIRUS_SCAN_PROVIDER kosong atau 'none').
+//
+// Variabel Environment yang didukung:
+//   - VIRUS_SCAN_PROVIDER: 'clamd' atau 'none' (default).
+//   - VIRUS_SCAN_CLAMD_ADDR: Alamat TCP clamd (wajib untuk clamd), mis. localhost:3310.
+//   - VIRUS_SCAN_TIMEOUT: Timeout satu pemindaian. Default: 30s.
+func NewScannerFromEnv() (Scanner, error) {
+	provider := strings.ToLower(configs.GetEnv("VIRUS_SCAN_PROVIDER", "none"))
+	switch provider {
+	case "none":
+		return nil, nil
+	case "clamd":
+		addr := configs.GetEnv("VIRUS_SCAN_CLAMD_ADDR", "")
+		if addr == "" {
+			return nil, fmt.Errorf("VIRUS_SCAN_CLAMD_ADDR must be set when VIRUS_SCAN_PROVIDER=clamd")
+		}
+		zlog.Info().Str("provider", provider).Str("addr", addr).Msg("Upload virus scanning enabled")
+		return &clamdScanner{addr: addr, timeout: configs.GetEnvDuration("VIRUS_SCAN_TIMEOUT", 30*time.Second)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported VIRUS_SCAN_PROVIDER '%s'", provider)
+	}
+}
+
+// clamdScanner memindai lewat protokol INSTREAM milik daemon ClamAV (clamd).
+type clamdScanner struct {
+	addr    string
+	timeout time.Duration
+}
+
+func (s *clamdScanner) Provider() string {
+	return "clamd"
+}
+
+func (s *clamdScanner) Scan(ctx context.Context, r io.Reader) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("error connecting to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("error starting clamd stream: %w", err)
+	}
+	buf := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return fmt.Errorf("error streaming to clamd: %w", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return fmt.Errorf("error streaming to clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("error reading file for scan: %w", readErr)
+		}
+	}
+	// Potongan berukuran 0 menandai akhir stream.
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return fmt.Errorf("error finishing clamd stream: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("error reading clamd reply: %w", err)
+	}
+	reply = strings.TrimRight(reply, "\x00\n")
+	switch {
+	case strings.HasSuffix(reply, "OK"):
+		return nil
+	case strings.HasSuffix(reply, "FOUND"):
+		return fmt.Errorf("%w: %s", ErrInfected, strings.TrimSpace(strings.TrimPrefix(reply, "stream:")))
+	default:
+		return fmt.Errorf("unexpected clamd reply: %q", reply)
+	}
+}
@@ -0,0 +1,59 @@
+// internal/worktime/calendar.go
+package worktime
+
+import (
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+// Calendar adalah kalender kerja organisasi: hari kerja dalam seminggu, hari kerja setengah
+// hari, dan tanggal libur (lihat settings.Store.WorkingCalendar). Dipakai sebagai pengganti
+// asumsi Senin-Jumat, mis. untuk organisasi dengan minggu kerja Minggu-Kamis.
+type Calendar struct {
+	WorkingDays map[time.Weekday]bool
+	HalfDays    map[time.Weekday]bool // Dihitung setengah hari kerja (mis. Sabtu)
+	Holidays    map[string]bool       // Tanggal libur (YYYY-MM-DD), selalu bukan hari kerja
+}
+
+// DayType mengembalikan jenis tanggal day (lihat models.CalendarDay*).
+func (c Calendar) DayType(day time.Time) string {
+	switch {
+	case c.Holidays[day.Format(dateLayout)]:
+		return models.CalendarDayHoliday
+	case c.HalfDays[day.Weekday()]:
+		return models.CalendarDayHalf
+	case c.WorkingDays[day.Weekday()]:
+		return models.CalendarDayWorking
+	}
+	return models.CalendarDayOff
+}
+
+// IsWorkingDay melaporkan apakah day adalah hari kerja (penuh atau setengah hari).
+func (c Calendar) IsWorkingDay(day time.Time) bool {
+	t := c.DayType(day)
+	return t == models.CalendarDayWorking || t == models.CalendarDayHalf
+}
+
+// WorkFraction mengembalikan porsi hari kerja day: 1, 0.5 (setengah hari), atau 0.
+func (c Calendar) WorkFraction(day time.Time) float64 {
+	switch c.DayType(day) {
+	case models.CalendarDayWorking:
+		return 1
+	case models.CalendarDayHalf:
+		return 0.5
+	}
+	return 0
+}
+
+// Days mengembalikan setiap tanggal dalam [start, end] beserta jenisnya.
+func (c Calendar) Days(start, end time.Time) []models.CalendarDay {
+	days := []models.CalendarDay{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, models.CalendarDay{
+			Date: day.Format(dateLayout), Weekday: day.Weekday().String(),
+			Type: c.DayType(day), WorkFraction: c.WorkFraction(day),
+		})
+	}
+	return days
+}
@@ -0,0 +1,97 @@
+// internal/worktime/worktime.go
+
+// Package worktime membagi jam kerja sesi absensi ke dalam kategori jam (regular, malam,
+// akhir pekan, hari libur) yang sering dibayar dengan tarif berbeda.
+//
+// Setiap menit kerja masuk tepat satu kategori dengan prioritas holiday > weekend > night > regular,
+// dihitung pada zona waktu Rules.Location. Sesi yang melewati tengah malam dibagi per tanggal,
+// sehingga shift malam Jumat-Sabtu misalnya terbagi antara jam malam dan jam akhir pekan.
+package worktime
+
+import (
+	"math"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+)
+
+const dateLayout = "2006-01-02"
+
+// Rules adalah aturan kategori jam kerja (lihat settings.Store.HourRules).
+type Rules struct {
+	Location *time.Location
+	// NightStart/NightEnd adalah jam malam dalam menit sejak 00:00, mis. 22:00-06:00 = 1320-360.
+	// NightStart == NightEnd berarti tidak ada jam malam.
+	NightStart, NightEnd int
+	Weekend              map[time.Weekday]bool
+	Holidays             map[string]bool // Tanggal libur (YYYY-MM-DD)
+}
+
+// Split membagi rentang [start, end) ke dalam kategori jam. Rentang kosong/terbalik menghasilkan nol.
+func (r Rules) Split(start, end time.Time) models.HoursBreakdown {
+	loc := r.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	var regular, night, weekend, holiday time.Duration
+	cursor := start.In(loc)
+	end = end.In(loc)
+	for cursor.Before(end) {
+		next := r.nextBoundary(cursor)
+		if next.After(end) {
+			next = end
+		}
+		span := next.Sub(cursor)
+		switch {
+		case r.Holidays[cursor.Format(dateLayout)]:
+			holiday += span
+		case r.Weekend[cursor.Weekday()]:
+			weekend += span
+		case r.isNight(cursor):
+			night += span
+		default:
+			regular += span
+		}
+		cursor = next
+	}
+	return models.HoursBreakdown{
+		RegularHours: hours(regular),
+		NightHours:   hours(night),
+		WeekendHours: hours(weekend),
+		HolidayHours: hours(holiday),
+		TotalHours:   hours(regular + night + weekend + holiday),
+	}
+}
+
+// nextBoundary adalah batas kategori berikutnya setelah t: tengah malam, awal atau akhir jam malam.
+func (r Rules) nextBoundary(t time.Time) time.Time {
+	y, m, d := t.Date()
+	next := time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+	if r.NightStart == r.NightEnd {
+		return next
+	}
+	for _, minute := range []int{r.NightStart, r.NightEnd} {
+		candidate := time.Date(y, m, d, minute/60, minute%60, 0, 0, t.Location())
+		if candidate.After(t) && candidate.Before(next) {
+			next = candidate
+		}
+	}
+	return next
+}
+
+// isNight melaporkan apakah jam lokal t berada di jam malam (jam malam boleh melewati tengah malam).
+func (r Rules) isNight(t time.Time) bool {
+	if r.NightStart == r.NightEnd {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if r.NightStart < r.NightEnd {
+		return minute >= r.NightStart && minute < r.NightEnd
+	}
+	return minute >= r.NightStart || minute < r.NightEnd
+}
+
+// hours mengubah durasi ke jam desimal (dua angka di belakang koma).
+func hours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
@@ -0,0 +1,97 @@
+// loadtest/hot_endpoints.js
+//
+// Profil load test k6 untuk endpoint "hot": check-in/check-out, riwayat absensi sendiri,
+// dan laporan absensi admin. Threshold di bawah adalah baseline; build gagal (exit code != 0)
+// jika latensi p95 atau error rate melewati batas, sehingga regresi performa dari perubahan
+// query terdeteksi.
+//
+// Jalankan: make loadtest (atau k6 run loadtest/hot_endpoints.js) terhadap server non-production.
+// Akun di bawah harus sudah ada; employee harus punya jadwal hari ini.
+// Naikkan RATE_LIMIT_USER_*/RATE_LIMIT_ADMIN_* di server target agar yang diukur handler, bukan limiter.
+//
+// Variabel:
+//   BASE_URL            (default http://localhost:3000/api/v1)
+//   ADMIN_USERNAME / ADMIN_PASSWORD
+//   EMPLOYEE_USERNAME / EMPLOYEE_PASSWORD
+
+import http from 'k6/http';
+import { check, fail, group } from 'k6';
+
+const BASE_URL = __ENV.BASE_URL || 'http://localhost:3000/api/v1';
+
+export const options = {
+  scenarios: {
+    employee_attendance: {
+      executor: 'constant-vus',
+      exec: 'employeeFlow',
+      vus: 10,
+      duration: '1m',
+    },
+    admin_report: {
+      executor: 'constant-arrival-rate',
+      exec: 'adminReport',
+      rate: 5,
+      timeUnit: '1s',
+      duration: '1m',
+      preAllocatedVUs: 5,
+    },
+  },
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{endpoint:checkin}': ['p(95)<200'],
+    'http_req_duration{endpoint:checkout}': ['p(95)<200'],
+    'http_req_duration{endpoint:my_attendance}': ['p(95)<150'],
+    'http_req_duration{endpoint:admin_report}': ['p(95)<400'],
+  },
+};
+
+function login(username, password) {
+  const res = http.post(`${BASE_URL}/auth/login`, JSON.stringify({ username, password }), {
+    headers: { 'Content-Type': 'application/json' },
+  });
+  if (res.status !== 200) {
+    fail(`login ${username} failed: ${res.status} ${res.body}`);
+  }
+  return res.json('data.token');
+}
+
+export function setup() {
+  return {
+    admin: login(__ENV.ADMIN_USERNAME, __ENV.ADMIN_PASSWORD),
+    employee: login(__ENV.EMPLOYEE_USERNAME, __ENV.EMPLOYEE_PASSWORD),
+  };
+}
+
+function params(token, endpoint, extra) {
+  return Object.assign(
+    {
+      headers: { Authorization: `Bearer ${token}`, 'Content-Type': 'application/json' },
+      tags: { endpoint },
+    },
+    extra || {},
+  );
+}
+
+// Semua VU memakai akun employee yang sama, sehingga 409 (sudah check-in / belum check-in)
+// adalah jawaban yang sah dan tidak dihitung sebagai kegagalan.
+const attendanceStatuses = http.expectedStatuses(200, 409);
+
+export function employeeFlow(data) {
+  group('attendance', () => {
+    const checkin = http.post(`${BASE_URL}/user/attendance/checkin`, '{}',
+      params(data.employee, 'checkin', { responseCallback: attendanceStatuses }));
+    check(checkin, { 'checkin handled': (r) => r.status === 200 || r.status === 409 });
+
+    const my = http.get(`${BASE_URL}/user/attendance/my`, params(data.employee, 'my_attendance'));
+    check(my, { 'my attendance 200': (r) => r.status === 200 });
+
+    const checkout = http.post(`${BASE_URL}/user/attendance/checkout`, '{}',
+      params(data.employee, 'checkout', { responseCallback: attendanceStatuses }));
+    check(checkout, { 'checkout handled': (r) => r.status === 200 || r.status === 409 });
+  });
+}
+
+export function adminReport(data) {
+  const res = http.get(`${BASE_URL}/admin/attendance/report?limit=50`, params(data.admin, 'admin_report'));
+  check(res, { 'report 200': (r) => r.status === 200 });
+}
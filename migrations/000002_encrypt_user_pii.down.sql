@@ -0,0 +1,17 @@
+-- Migrations Down
+-- PERINGATAN: data yang sudah terenkripsi TIDAK didekripsi oleh migrasi ini.
+-- Dekripsi kolom email terlebih dahulu dari aplikasi sebelum rollback.
+
+DROP INDEX IF EXISTS idx_users_phone_hash;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_national_id_hash_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_hash_key;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS national_id_hash,
+    DROP COLUMN IF EXISTS national_id,
+    DROP COLUMN IF EXISTS phone_hash,
+    DROP COLUMN IF EXISTS phone,
+    DROP COLUMN IF EXISTS email_hash;
+
+ALTER TABLE users ALTER COLUMN email TYPE VARCHAR(255);
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
@@ -0,0 +1,17 @@
+-- Migrations Up
+
+-- Kolom PII disimpan terenkripsi (AES-GCM di aplikasi), sehingga constraint UNIQUE
+-- dipindahkan ke kolom blind index (*_hash, HMAC-SHA256 hex).
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+ALTER TABLE users ALTER COLUMN email TYPE TEXT;
+
+ALTER TABLE users
+    ADD COLUMN email_hash VARCHAR(64) NULL, -- Diisi aplikasi; baris lama di-backfill saat startup
+    ADD COLUMN phone TEXT NULL,
+    ADD COLUMN phone_hash VARCHAR(64) NULL,
+    ADD COLUMN national_id TEXT NULL,
+    ADD COLUMN national_id_hash VARCHAR(64) NULL;
+
+ALTER TABLE users ADD CONSTRAINT users_email_hash_key UNIQUE (email_hash);
+ALTER TABLE users ADD CONSTRAINT users_national_id_hash_key UNIQUE (national_id_hash);
+CREATE INDEX idx_users_phone_hash ON users(phone_hash);
@@ -0,0 +1,4 @@
+-- Migrations Down
+-- PERINGATAN: data yang sudah dipseudonimkan TIDAK bisa dikembalikan.
+
+ALTER TABLE users DROP COLUMN IF EXISTS anonymized_at;
@@ -0,0 +1,5 @@
+-- Migrations Up
+
+-- Penanda user yang data pribadinya sudah dipseudonimkan (hak penghapusan GDPR).
+-- Baris user tetap ada agar riwayat absensi/jadwal untuk payroll tidak hilang.
+ALTER TABLE users ADD COLUMN anonymized_at TIMESTAMPTZ NULL;
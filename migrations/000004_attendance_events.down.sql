@@ -0,0 +1,6 @@
+-- Migrations Down
+-- PERINGATAN: riwayat koreksi absensi di ledger akan hilang.
+
+DROP TRIGGER IF EXISTS attendance_events_append_only ON attendance_events;
+DROP FUNCTION IF EXISTS attendance_events_append_only();
+DROP TABLE IF EXISTS attendance_events;
@@ -0,0 +1,59 @@
+-- Migrations Up
+
+-- Ledger append-only untuk absensi. Setiap perubahan (check-in, check-out, koreksi admin)
+-- dicatat sebagai event berisi snapshot lengkap record setelah perubahan; baris di tabel
+-- attendances hanyalah proyeksi dari event terakhir per attendance_id.
+CREATE TABLE attendance_events (
+    id BIGSERIAL PRIMARY KEY,
+    attendance_id INT NOT NULL,
+    user_id INT NOT NULL,
+    event_type VARCHAR(32) NOT NULL, -- check_in | check_out | correction | legacy_import
+    check_in_at TIMESTAMPTZ NOT NULL,
+    check_out_at TIMESTAMPTZ NULL,
+    notes TEXT NULL,
+    reason TEXT NULL,                -- Wajib untuk koreksi admin
+    actor_user_id INT NULL,          -- User/admin yang memicu event
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (attendance_id) REFERENCES attendances(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (event_type IN ('check_in', 'check_out', 'correction', 'legacy_import')),
+    CHECK (check_out_at IS NULL OR check_out_at >= check_in_at)
+);
+
+CREATE INDEX idx_attendance_events_attendance ON attendance_events(attendance_id, id);
+CREATE INDEX idx_attendance_events_user ON attendance_events(user_id);
+
+-- Tolak UPDATE/DELETE pada ledger. Pengecualian:
+--   * UPDATE yang hanya mengosongkan notes (redaksi data pribadi saat anonimisasi user),
+--   * UPDATE actor_user_id menjadi NULL (ON DELETE SET NULL saat actor dihapus),
+--   * DELETE berantai ketika baris attendances induknya sudah dihapus (hapus user).
+CREATE OR REPLACE FUNCTION attendance_events_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF (NEW.id, NEW.attendance_id, NEW.user_id, NEW.event_type, NEW.check_in_at, NEW.check_out_at, NEW.reason, NEW.created_at)
+               IS NOT DISTINCT FROM
+           (OLD.id, OLD.attendance_id, OLD.user_id, OLD.event_type, OLD.check_in_at, OLD.check_out_at, OLD.reason, OLD.created_at)
+           AND (NEW.notes IS NULL OR NEW.notes IS NOT DISTINCT FROM OLD.notes)
+           AND (NEW.actor_user_id IS NULL OR NEW.actor_user_id IS NOT DISTINCT FROM OLD.actor_user_id) THEN
+            RETURN NEW;
+        END IF;
+    ELSIF TG_OP = 'DELETE' THEN
+        IF NOT EXISTS (SELECT 1 FROM attendances WHERE id = OLD.attendance_id) THEN
+            RETURN OLD;
+        END IF;
+    END IF;
+    RAISE EXCEPTION 'attendance_events is append-only (% not allowed)', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER attendance_events_append_only
+BEFORE UPDATE OR DELETE ON attendance_events
+FOR EACH ROW
+EXECUTE FUNCTION attendance_events_append_only();
+
+-- Backfill: satu event snapshot untuk setiap record absensi yang sudah ada.
+INSERT INTO attendance_events (attendance_id, user_id, event_type, check_in_at, check_out_at, notes, actor_user_id, created_at)
+SELECT id, user_id, 'legacy_import', check_in_at, check_out_at, notes, user_id, COALESCE(updated_at, created_at, CURRENT_TIMESTAMP)
+FROM attendances
+ORDER BY id;
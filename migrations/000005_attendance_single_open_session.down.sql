@@ -0,0 +1,3 @@
+-- Migrations Down
+
+DROP INDEX IF EXISTS uq_attendances_open_session;
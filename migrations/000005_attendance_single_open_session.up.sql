@@ -0,0 +1,7 @@
+-- Migrations Up
+
+-- Maksimal satu sesi absensi terbuka (belum check-out) per user.
+-- Menjamin check-in paralel tidak bisa membuat sesi ganda walaupun keduanya lolos
+-- pengecekan GetLastAttendance di aplikasi. Migrasi gagal jika data lama sudah
+-- berisi sesi terbuka ganda; tutup sesi tersebut (koreksi admin) terlebih dahulu.
+CREATE UNIQUE INDEX uq_attendances_open_session ON attendances(user_id) WHERE check_out_at IS NULL;
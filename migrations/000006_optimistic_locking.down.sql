@@ -0,0 +1,11 @@
+-- Migrations Down
+
+DROP TRIGGER IF EXISTS bump_version_users ON users;
+DROP TRIGGER IF EXISTS bump_version_user_schedules ON user_schedules;
+DROP TRIGGER IF EXISTS bump_version_shifts ON shifts;
+
+DROP FUNCTION IF EXISTS trigger_bump_version();
+
+ALTER TABLE users DROP COLUMN IF EXISTS version;
+ALTER TABLE user_schedules DROP COLUMN IF EXISTS version;
+ALTER TABLE shifts DROP COLUMN IF EXISTS version;
@@ -0,0 +1,31 @@
+-- Migrations Up
+
+-- Kolom version untuk optimistic locking (If-Match / field "version" pada request update).
+-- Dinaikkan otomatis oleh trigger pada setiap UPDATE, sehingga semua jalur update
+-- (termasuk yang tidak memakai precondition) tetap membatalkan versi lama.
+ALTER TABLE shifts ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE user_schedules ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION trigger_bump_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bump_version_shifts
+BEFORE UPDATE ON shifts
+FOR EACH ROW
+EXECUTE FUNCTION trigger_bump_version();
+
+CREATE TRIGGER bump_version_user_schedules
+BEFORE UPDATE ON user_schedules
+FOR EACH ROW
+EXECUTE FUNCTION trigger_bump_version();
+
+CREATE TRIGGER bump_version_users
+BEFORE UPDATE ON users
+FOR EACH ROW
+EXECUTE FUNCTION trigger_bump_version();
@@ -0,0 +1,12 @@
+-- Migrations Up
+
+-- Pengaturan sistem yang bisa diubah saat runtime (GET/PUT /admin/settings).
+-- Nilai disimpan sebagai teks; tipe & validasi per key didefinisikan di internal/settings.
+-- Key yang belum pernah di-set memakai nilai default dari kode.
+CREATE TABLE settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_by INT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (updated_by) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,20 @@
+-- Migrations Up
+
+-- Pengumuman dari admin ke karyawan (mis. "Kantor tutup Jumat, tidak perlu check-in").
+-- Tampil ke user hanya dalam jendela publikasi [publish_at, expires_at) dan hanya untuk
+-- role yang ada di audience_role_ids (array kosong = semua user).
+CREATE TABLE announcements (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    publish_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMPTZ NULL,
+    audience_role_ids INT[] NOT NULL DEFAULT '{}',
+    created_by INT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (expires_at IS NULL OR expires_at > publish_at)
+);
+
+CREATE INDEX idx_announcements_publish_window ON announcements(publish_at, expires_at);
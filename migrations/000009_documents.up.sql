@@ -0,0 +1,23 @@
+-- Migrations Up
+
+-- Dokumen pendukung yang diunggah user (surat sakit, izin, dll.).
+-- Isi file disimpan di storage (lihat internal/storage); tabel ini hanya metadata.
+-- subject_type/subject_id menunjuk entitas yang didukung dokumen (saat ini: attendance).
+CREATE TABLE documents (
+    id SERIAL PRIMARY KEY,
+    owner_user_id INT NOT NULL,
+    subject_type VARCHAR(32) NOT NULL,
+    subject_id INT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    scan_status VARCHAR(16) NOT NULL, -- clean | skipped (pemindai tidak dikonfigurasi)
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (owner_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CHECK (subject_type IN ('attendance')),
+    CHECK (scan_status IN ('clean', 'skipped'))
+);
+
+CREATE INDEX idx_documents_subject ON documents(subject_type, subject_id);
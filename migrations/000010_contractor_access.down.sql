@@ -0,0 +1,12 @@
+-- Migrations Down
+
+DROP INDEX IF EXISTS idx_users_active_contractors;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_access_range_check,
+    DROP CONSTRAINT IF EXISTS users_contractor_validity_check,
+    DROP CONSTRAINT IF EXISTS users_user_type_check,
+    DROP COLUMN IF EXISTS is_active,
+    DROP COLUMN IF EXISTS access_valid_until,
+    DROP COLUMN IF EXISTS access_valid_from,
+    DROP COLUMN IF EXISTS user_type;
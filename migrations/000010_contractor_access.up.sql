@@ -0,0 +1,15 @@
+-- Migrations Up
+
+-- Tipe user dan masa berlaku akses. Kontraktor/visitor wajib punya access_valid_until
+-- (tanggal terakhir akses, inklusif); job ContractorExpiry menonaktifkan (is_active = FALSE)
+-- kontraktor yang masa aksesnya sudah lewat. Karyawan biasa tidak dibatasi tanggal.
+ALTER TABLE users
+    ADD COLUMN user_type VARCHAR(20) NOT NULL DEFAULT 'employee',
+    ADD COLUMN access_valid_from DATE NULL,
+    ADD COLUMN access_valid_until DATE NULL,
+    ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD CONSTRAINT users_user_type_check CHECK (user_type IN ('employee', 'contractor')),
+    ADD CONSTRAINT users_contractor_validity_check CHECK (user_type <> 'contractor' OR access_valid_until IS NOT NULL),
+    ADD CONSTRAINT users_access_range_check CHECK (access_valid_from IS NULL OR access_valid_until IS NULL OR access_valid_from <= access_valid_until);
+
+CREATE INDEX idx_users_active_contractors ON users(access_valid_until) WHERE user_type = 'contractor' AND is_active;
@@ -0,0 +1,13 @@
+-- Migrations Down
+
+DROP INDEX IF EXISTS idx_users_probation_pending;
+DROP INDEX IF EXISTS idx_users_employment_status;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_probation_range_check,
+    DROP CONSTRAINT IF EXISTS users_probation_end_check,
+    DROP CONSTRAINT IF EXISTS users_employment_status_check,
+    DROP COLUMN IF EXISTS probation_notified_at,
+    DROP COLUMN IF EXISTS probation_end,
+    DROP COLUMN IF EXISTS employment_status,
+    DROP COLUMN IF EXISTS hire_date;
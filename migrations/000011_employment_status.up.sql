@@ -0,0 +1,16 @@
+-- Migrations Up
+
+-- Data kepegawaian sebagai konteks laporan absensi: tanggal mulai kerja, status kepegawaian,
+-- dan akhir masa probation. probation_notified_at menandai HR sudah diberi tahu
+-- (job ProbationReview) sehingga notifikasi tidak terkirim berulang.
+ALTER TABLE users
+    ADD COLUMN hire_date DATE NULL,
+    ADD COLUMN employment_status VARCHAR(20) NOT NULL DEFAULT 'permanent',
+    ADD COLUMN probation_end DATE NULL,
+    ADD COLUMN probation_notified_at TIMESTAMPTZ NULL,
+    ADD CONSTRAINT users_employment_status_check CHECK (employment_status IN ('probation', 'permanent', 'fixed_term', 'intern', 'terminated')),
+    ADD CONSTRAINT users_probation_end_check CHECK (employment_status <> 'probation' OR probation_end IS NOT NULL),
+    ADD CONSTRAINT users_probation_range_check CHECK (hire_date IS NULL OR probation_end IS NULL OR probation_end >= hire_date);
+
+CREATE INDEX idx_users_employment_status ON users(employment_status);
+CREATE INDEX idx_users_probation_pending ON users(probation_end) WHERE employment_status = 'probation' AND probation_notified_at IS NULL;
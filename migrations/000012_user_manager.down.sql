@@ -0,0 +1,7 @@
+-- Migrations Down
+
+DROP INDEX IF EXISTS idx_users_manager_id;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_manager_not_self_check,
+    DROP COLUMN IF EXISTS manager_id;
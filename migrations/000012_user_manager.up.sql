@@ -0,0 +1,9 @@
+-- Migrations Up
+
+-- Garis pelaporan: setiap user boleh punya satu atasan langsung (manager_id).
+-- Siklus (A -> B -> A) dicegah di aplikasi (SetUserManager) dengan pengecekan rekursif.
+ALTER TABLE users
+    ADD COLUMN manager_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    ADD CONSTRAINT users_manager_not_self_check CHECK (manager_id IS NULL OR manager_id <> id);
+
+CREATE INDEX idx_users_manager_id ON users(manager_id);
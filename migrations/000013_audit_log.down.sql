@@ -0,0 +1,5 @@
+-- Migrations Down
+
+DROP TRIGGER IF EXISTS audit_user_schedules ON user_schedules;
+DROP FUNCTION IF EXISTS audit_user_schedule_change();
+DROP TABLE IF EXISTS audit_log;
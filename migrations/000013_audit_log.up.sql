@@ -0,0 +1,51 @@
+-- Migrations Up
+
+-- Audit log per user: login, perubahan profil/akun, dan perubahan jadwal. Menjadi sumber
+-- feed aktivitas admin (GET /admin/users/:userId/activity) bersama ledger attendance_events.
+-- action berformat "<kategori>.<aksi>", mis. auth.login_succeeded, profile.updated, schedule.deleted.
+-- details hanya berisi metadata (nama field yang diubah, IP, user agent), bukan nilai data pribadi.
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,            -- Subjek: user yang aktivitasnya dicatat
+    actor_user_id INT NULL,          -- User/admin yang memicu (NULL = sistem/tidak diketahui)
+    action VARCHAR(64) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (action ~ '^[a-z_]+\.[a-z_]+$')
+);
+
+CREATE INDEX idx_audit_log_user_created ON audit_log(user_id, created_at DESC);
+
+-- Perubahan jadwal dicatat oleh trigger agar semua jalur (termasuk bulk delete) tercakup.
+-- Saat user dihapus, jadwalnya ikut terhapus berantai; baris audit untuk user yang sudah
+-- tidak ada dilewati (akan ikut terhapus juga).
+CREATE OR REPLACE FUNCTION audit_user_schedule_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND EXISTS (SELECT 1 FROM users WHERE id = OLD.user_id) THEN
+        IF TG_OP = 'DELETE' OR NEW.user_id <> OLD.user_id THEN
+            INSERT INTO audit_log (user_id, action, details)
+            VALUES (OLD.user_id, 'schedule.deleted',
+                    jsonb_build_object('schedule_id', OLD.id, 'shift_id', OLD.shift_id, 'date', OLD.date));
+        ELSIF (NEW.shift_id, NEW.date) IS DISTINCT FROM (OLD.shift_id, OLD.date) THEN
+            INSERT INTO audit_log (user_id, action, details)
+            VALUES (NEW.user_id, 'schedule.updated',
+                    jsonb_build_object('schedule_id', NEW.id, 'shift_id', NEW.shift_id, 'date', NEW.date,
+                                       'previous_shift_id', OLD.shift_id, 'previous_date', OLD.date));
+        END IF;
+    END IF;
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.user_id <> OLD.user_id) THEN
+        INSERT INTO audit_log (user_id, action, details)
+        VALUES (NEW.user_id, 'schedule.created',
+                jsonb_build_object('schedule_id', NEW.id, 'shift_id', NEW.shift_id, 'date', NEW.date));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_user_schedules
+AFTER INSERT OR UPDATE OR DELETE ON user_schedules
+FOR EACH ROW
+EXECUTE FUNCTION audit_user_schedule_change();
@@ -0,0 +1,7 @@
+-- Migrations Down
+
+DROP INDEX IF EXISTS idx_audit_log_login_device;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS password_reset_required,
+    DROP COLUMN IF EXISTS token_version;
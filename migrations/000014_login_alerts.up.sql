@@ -0,0 +1,12 @@
+-- Migrations Up
+
+-- Pencabutan sesi & paksa reset password (tautan "bukan saya" pada email peringatan login).
+-- token_version ikut disematkan di JWT; menaikkannya membatalkan semua token yang sudah terbit.
+-- password_reset_required menolak login sampai user mengganti password lewat token reset.
+ALTER TABLE users
+    ADD COLUMN token_version INT NOT NULL DEFAULT 0,
+    ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Riwayat login sukses per perangkat, untuk mendeteksi login dari perangkat/negara baru.
+CREATE INDEX idx_audit_log_login_device ON audit_log(user_id, (details->>'device'))
+    WHERE action = 'auth.login_succeeded';
@@ -0,0 +1,6 @@
+-- Migrations Down
+
+DROP INDEX IF EXISTS idx_attendances_schedule_id;
+
+ALTER TABLE attendances
+    DROP COLUMN IF EXISTS schedule_id;
@@ -0,0 +1,20 @@
+-- Migrations Up
+
+-- Tautan absensi ke jadwal yang berlaku saat check-in, agar laporan tidak perlu menebak
+-- pasangan jadwal dari tanggal (yang bisa berubah jika jadwal diedit setelahnya).
+-- NULL untuk check-in tanpa jadwal; menjadi NULL jika jadwalnya dihapus.
+ALTER TABLE attendances
+    ADD COLUMN schedule_id INT NULL REFERENCES user_schedules(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_attendances_schedule_id ON attendances(schedule_id);
+
+-- Backfill data lama dengan pencocokan tanggal yang sebelumnya dipakai laporan.
+-- Trigger updated_at dimatikan sementara agar backfill tidak tampak sebagai perubahan absensi.
+ALTER TABLE attendances DISABLE TRIGGER set_timestamp_attendances;
+
+UPDATE attendances a
+SET schedule_id = us.id
+FROM user_schedules us
+WHERE us.user_id = a.user_id AND us.date = a.check_in_at::date AND a.schedule_id IS NULL;
+
+ALTER TABLE attendances ENABLE TRIGGER set_timestamp_attendances;
@@ -0,0 +1,31 @@
+-- Migrations Up
+
+-- Periode payroll. Setelah periode ditutup (status closed), absensi yang tanggal check-in-nya
+-- berada di dalam periode tidak bisa lagi diubah (check-in, check-out, koreksi) sampai periode
+-- dibuka kembali lewat alur reopen (wajib alasan, dicatat di kolom reopened_*).
+-- Periode tidak boleh tumpang tindih agar setiap tanggal masuk tepat satu periode.
+CREATE TABLE payroll_periods (
+    id SERIAL PRIMARY KEY,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'open',
+    closed_at TIMESTAMPTZ NULL,
+    closed_by INT NULL,
+    reopened_at TIMESTAMPTZ NULL,
+    reopened_by INT NULL,
+    reopen_reason TEXT NULL,
+    created_by INT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (closed_by) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (reopened_by) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (end_date >= start_date),
+    CHECK (status IN ('open', 'closed')),
+    CONSTRAINT excl_payroll_periods_overlap EXCLUDE USING gist (daterange(start_date, end_date, '[]') WITH &&)
+);
+
+CREATE TRIGGER set_timestamp_payroll_periods
+BEFORE UPDATE ON payroll_periods
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
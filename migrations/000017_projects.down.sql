@@ -0,0 +1,8 @@
+-- Migrations Down
+
+DROP INDEX IF EXISTS idx_attendances_project_checkin;
+
+ALTER TABLE attendances
+    DROP COLUMN IF EXISTS project_id;
+
+DROP TABLE IF EXISTS projects;
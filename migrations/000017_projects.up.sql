@@ -0,0 +1,24 @@
+-- Migrations Up
+
+-- Project / cost center yang dikelola admin. Karyawan boleh menandai sesi absensi dengan
+-- project saat check-in, sehingga jam kerja bisa direkap per project (tim billable).
+-- Project yang sudah dipakai absensi tidak bisa dihapus; nonaktifkan (is_active = false) saja.
+CREATE TABLE projects (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    cost_center VARCHAR(64) NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER set_timestamp_projects
+BEFORE UPDATE ON projects
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+ALTER TABLE attendances
+    ADD COLUMN project_id INT NULL REFERENCES projects(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_attendances_project_checkin ON attendances(project_id, check_in_at) WHERE project_id IS NOT NULL;
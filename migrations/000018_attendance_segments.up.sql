@@ -0,0 +1,26 @@
+-- Migrations Up
+
+-- Segmen project dalam satu sesi absensi. Check-in membuka segmen pertama (project dari
+-- check-in), POST /user/attendance/switch menutup segmen berjalan dan membuka segmen baru
+-- dengan project lain tanpa check-out, dan check-out menutup segmen terakhir.
+-- Rekap jam per project dihitung dari durasi segmen.
+CREATE TABLE attendance_segments (
+    id BIGSERIAL PRIMARY KEY,
+    attendance_id INT NOT NULL,
+    project_id INT NULL,             -- NULL = tanpa project
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ NULL,       -- NULL = segmen sedang berjalan
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (attendance_id) REFERENCES attendances(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX idx_attendance_segments_attendance ON attendance_segments(attendance_id, started_at);
+CREATE INDEX idx_attendance_segments_project ON attendance_segments(project_id) WHERE project_id IS NOT NULL;
+-- Paling banyak satu segmen berjalan per sesi absensi.
+CREATE UNIQUE INDEX uq_attendance_segments_open ON attendance_segments(attendance_id) WHERE ended_at IS NULL;
+
+-- Sesi yang sudah ada menjadi satu segmen penuh dengan project check-in-nya.
+INSERT INTO attendance_segments (attendance_id, project_id, started_at, ended_at)
+SELECT id, project_id, check_in_at, check_out_at FROM attendances;
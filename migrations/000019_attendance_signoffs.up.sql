@@ -0,0 +1,20 @@
+-- Migrations Up
+
+-- Sign-off harian oleh atasan: satu baris per karyawan per tanggal kerja. Setelah di-sign-off,
+-- absensi karyawan dengan tanggal check-in tersebut terkunci (check-in, check-out, switch project,
+-- koreksi). Pengecualian yang ditemukan saat sign-off (no show, terlambat, tanpa jadwal, dikoreksi)
+-- disimpan di kolom exceptions sebagai jejak verifikasi.
+CREATE TABLE attendance_signoffs (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    work_date DATE NOT NULL,
+    signed_by INT NULL,
+    signed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    exceptions TEXT[] NOT NULL DEFAULT '{}',
+    note TEXT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (signed_by) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT uq_attendance_signoffs_user_date UNIQUE (user_id, work_date)
+);
+
+CREATE INDEX idx_attendance_signoffs_work_date ON attendance_signoffs(work_date);
@@ -0,0 +1,30 @@
+-- Migrations Up
+
+-- Sanggahan (dispute) karyawan atas record absensinya sendiri. Atasan langsung dinotifikasi saat
+-- dispute dibuat; admin menyelesaikannya (resolved/rejected) dengan catatan. Satu record absensi
+-- hanya boleh punya satu dispute berstatus open.
+CREATE TABLE attendance_disputes (
+    id SERIAL PRIMARY KEY,
+    attendance_id INT NOT NULL,
+    user_id INT NOT NULL,
+    comment TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'open',
+    resolution_note TEXT NULL,
+    resolved_by INT NULL,
+    resolved_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (attendance_id) REFERENCES attendances(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (status IN ('open', 'resolved', 'rejected'))
+);
+
+CREATE UNIQUE INDEX uq_attendance_disputes_open ON attendance_disputes(attendance_id) WHERE status = 'open';
+CREATE INDEX idx_attendance_disputes_status ON attendance_disputes(status, created_at);
+CREATE INDEX idx_attendance_disputes_user_id ON attendance_disputes(user_id);
+
+CREATE TRIGGER set_timestamp_attendance_disputes
+BEFORE UPDATE ON attendance_disputes
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
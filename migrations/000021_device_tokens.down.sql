@@ -0,0 +1,4 @@
+-- Migrations Down
+
+DROP TABLE IF EXISTS push_reminders;
+DROP TABLE IF EXISTS device_tokens;
@@ -0,0 +1,31 @@
+-- Migrations Up
+
+-- Token perangkat untuk push notification (FCM/APNs). Satu token hanya milik satu user:
+-- registrasi ulang token yang sama dari akun lain memindahkannya. Token yang ditolak provider
+-- (tidak terdaftar lagi) dihapus otomatis oleh internal/push.
+CREATE TABLE device_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    platform VARCHAR(10) NOT NULL,
+    token TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CHECK (platform IN ('fcm', 'apns')),
+    CONSTRAINT uq_device_tokens_token UNIQUE (token)
+);
+
+CREATE INDEX idx_device_tokens_user_id ON device_tokens(user_id);
+
+CREATE TRIGGER set_timestamp_device_tokens
+BEFORE UPDATE ON device_tokens
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+-- Pengingat shift yang sudah dikirim (satu per jadwal), agar job pengingat tidak mengirim ulang
+-- setelah restart atau saat berjalan di beberapa instance.
+CREATE TABLE push_reminders (
+    schedule_id INT PRIMARY KEY,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (schedule_id) REFERENCES user_schedules(id) ON DELETE CASCADE
+);
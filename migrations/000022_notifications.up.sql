@@ -0,0 +1,19 @@
+-- Migrations Up
+
+-- Inbox notifikasi in-app per user. Diisi dari event yang sama dengan email/push (perubahan
+-- jadwal, pengingat shift, dispute), read_at NULL = belum dibaca.
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    data JSONB NOT NULL DEFAULT '{}',
+    read_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at DESC);
+-- Hitungan belum dibaca (badge) cukup memindai indeks parsial ini.
+CREATE INDEX idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
@@ -0,0 +1,18 @@
+-- Migrations Up
+
+-- Outbox domain event untuk streaming ke broker (Kafka/NATS). Event ditulis saat dipublish
+-- lalu diteruskan oleh job relay; published_at diisi setelah broker menerima event, sehingga
+-- event yang gagal dikirim akan dicoba lagi (at-least-once, konsumen harus idempoten via id).
+CREATE TABLE event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_name VARCHAR(100) NOT NULL,
+    user_id INT NULL, -- Subjek event; dipakai sebagai key partisi agar urutan per user terjaga
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMPTZ NULL
+);
+
+-- Relay hanya memindai event yang belum terkirim, urut id.
+CREATE INDEX idx_event_outbox_pending ON event_outbox(id) WHERE published_at IS NULL;
@@ -0,0 +1,14 @@
+-- Migrations Down
+
+DROP INDEX IF EXISTS idx_outbox_messages_dead;
+DROP INDEX IF EXISTS idx_outbox_messages_due;
+-- Hanya pesan streaming yang dikenal event_outbox.
+DELETE FROM outbox_messages WHERE destination <> 'stream';
+ALTER TABLE outbox_messages DROP COLUMN dead_at;
+ALTER TABLE outbox_messages DROP COLUMN available_at;
+ALTER TABLE outbox_messages DROP COLUMN destination;
+ALTER TABLE outbox_messages RENAME COLUMN delivered_at TO published_at;
+ALTER INDEX outbox_messages_pkey RENAME TO event_outbox_pkey;
+ALTER SEQUENCE outbox_messages_id_seq RENAME TO event_outbox_id_seq;
+ALTER TABLE outbox_messages RENAME TO event_outbox;
+CREATE INDEX idx_event_outbox_pending ON event_outbox(id) WHERE published_at IS NULL;
@@ -0,0 +1,19 @@
+-- Migrations Up
+
+-- Outbox umum untuk efek samping (notifikasi email/inbox/push, webhook, streaming broker).
+-- Pesan ditulis dalam transaksi yang sama dengan perubahan domain, satu baris per tujuan
+-- (destination), lalu dikirim oleh dispatcher. Pengiriman yang gagal dicoba lagi pada
+-- available_at (backoff); setelah batas percobaan pesan menjadi dead letter (dead_at).
+ALTER TABLE event_outbox RENAME TO outbox_messages;
+ALTER TABLE outbox_messages RENAME COLUMN published_at TO delivered_at;
+ALTER TABLE outbox_messages ADD COLUMN destination VARCHAR(50) NOT NULL DEFAULT 'stream';
+ALTER TABLE outbox_messages ALTER COLUMN destination DROP DEFAULT;
+ALTER TABLE outbox_messages ADD COLUMN available_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE outbox_messages ADD COLUMN dead_at TIMESTAMPTZ NULL;
+ALTER SEQUENCE event_outbox_id_seq RENAME TO outbox_messages_id_seq;
+ALTER INDEX event_outbox_pkey RENAME TO outbox_messages_pkey;
+
+DROP INDEX IF EXISTS idx_event_outbox_pending;
+-- Dispatcher hanya memindai pesan yang belum terkirim dan belum dead letter.
+CREATE INDEX idx_outbox_messages_due ON outbox_messages(available_at, id) WHERE delivered_at IS NULL AND dead_at IS NULL;
+CREATE INDEX idx_outbox_messages_dead ON outbox_messages(dead_at DESC) WHERE dead_at IS NOT NULL;
@@ -0,0 +1,21 @@
+-- Migrations Up
+
+-- Delegasi wewenang approval atasan (sign-off tim) ke user lain selama rentang tanggal, mis.
+-- saat cuti. Delegasi yang dicabut tetap disimpan (revoked_at) sebagai jejak audit.
+CREATE TABLE approval_delegations (
+    id SERIAL PRIMARY KEY,
+    delegator_id INT NOT NULL,
+    delegate_id INT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMPTZ NULL,
+    FOREIGN KEY (delegator_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (delegate_id) REFERENCES users(id) ON DELETE CASCADE,
+    CHECK (delegator_id <> delegate_id),
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_approval_delegations_delegator ON approval_delegations(delegator_id);
+CREATE INDEX idx_approval_delegations_delegate_active ON approval_delegations(delegate_id, start_date, end_date) WHERE revoked_at IS NULL;
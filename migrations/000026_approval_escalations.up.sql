@@ -0,0 +1,18 @@
+-- Migrations Up
+
+-- Riwayat eskalasi item approval yang melewati SLA (lihat internal/jobs/approval_escalation.go).
+-- Satu baris per langkah: item_type/item_id menunjuk item approval inbox (mis. attendance_dispute),
+-- escalated_to NULL berarti admin/HR. Unique (item, level) mencegah langkah ganda saat job
+-- berjalan di beberapa instance.
+CREATE TABLE approval_escalations (
+    id SERIAL PRIMARY KEY,
+    item_type VARCHAR(50) NOT NULL,
+    item_id INT NOT NULL,
+    level INT NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    escalated_to INT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (escalated_to) REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (action IN ('escalated', 'reminded')),
+    CONSTRAINT uq_approval_escalations_item_level UNIQUE (item_type, item_id, level)
+);
@@ -0,0 +1,4 @@
+-- Migrations Down
+
+ALTER TABLE users DROP COLUMN IF EXISTS hourly_rate;
+ALTER TABLE roles DROP COLUMN IF EXISTS hourly_rate;
@@ -0,0 +1,6 @@
+-- Migrations Up
+
+-- Tarif per jam (opsional) untuk estimasi biaya tenaga kerja (GET /admin/analytics/labor-cost).
+-- Tarif user mengalahkan tarif role; user tanpa keduanya dihitung sebagai jam tanpa tarif.
+ALTER TABLE roles ADD COLUMN hourly_rate NUMERIC(12, 2) NULL CHECK (hourly_rate >= 0);
+ALTER TABLE users ADD COLUMN hourly_rate NUMERIC(12, 2) NULL CHECK (hourly_rate >= 0);
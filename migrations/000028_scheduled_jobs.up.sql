@@ -0,0 +1,22 @@
+-- Migrations Up
+
+-- Definisi & status run terakhir job latar belakang (lihat internal/jobs/scheduler.go).
+-- Baris di-upsert setiap instance saat startup; next_run_at dibagi semua instance sehingga
+-- job hanya berjalan sekali per interval. Eksekusi diserialkan dengan advisory lock per job.
+CREATE TABLE scheduled_jobs (
+    name VARCHAR(100) PRIMARY KEY,
+    interval_seconds INT NOT NULL,
+    next_run_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_started_at TIMESTAMPTZ NULL,
+    last_finished_at TIMESTAMPTZ NULL,
+    last_status VARCHAR(20) NULL,
+    last_trigger VARCHAR(20) NULL,
+    last_result INT NULL,
+    last_error TEXT NULL,
+    last_duration_ms BIGINT NULL,
+    run_count INT NOT NULL DEFAULT 0,
+    failure_count INT NOT NULL DEFAULT 0,
+    CHECK (interval_seconds > 0),
+    CHECK (last_status IN ('running', 'succeeded', 'failed')),
+    CHECK (last_trigger IN ('schedule', 'manual'))
+);
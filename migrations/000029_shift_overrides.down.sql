@@ -0,0 +1,4 @@
+-- Migrations Down
+
+DROP FUNCTION IF EXISTS schedule_shift(INT, DATE);
+DROP TABLE IF EXISTS shift_overrides;
@@ -0,0 +1,49 @@
+-- Migrations Up
+
+-- Override shift musiman berbatas tanggal (mis. jam kerja Ramadan). Override berlaku otomatis
+-- untuk jadwal yang tanggalnya di dalam rentang, tanpa mengubah shift atau jadwal:
+--   - shift_id NULL = semua shift; jam digeser start/end_offset_minutes (mis. end -60 = pulang 1 jam lebih awal).
+--   - shift_id terisi boleh memakai jam absolut start_time/end_time sebagai pengganti offset.
+CREATE TABLE shift_overrides (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    shift_id INT NULL REFERENCES shifts(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    start_time TIME NULL,
+    end_time TIME NULL,
+    start_offset_minutes INT NOT NULL DEFAULT 0,
+    end_offset_minutes INT NOT NULL DEFAULT 0,
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date),
+    CHECK ((start_time IS NULL) = (end_time IS NULL)),
+    CHECK (start_time IS NULL OR shift_id IS NOT NULL),
+    CHECK (start_offset_minutes BETWEEN -720 AND 720 AND end_offset_minutes BETWEEN -720 AND 720)
+);
+
+CREATE INDEX idx_shift_overrides_dates ON shift_overrides (start_date, end_date);
+
+-- schedule_shift mengembalikan shift yang berlaku untuk jadwal p_shift_id pada p_date: jam shift
+-- setelah override yang aktif (override khusus shift mengalahkan override semua shift; jika
+-- sama-sama cocok, yang terbaru menang). override_id NULL jika tidak ada override.
+-- Dipakai lewat JOIN LATERAL oleh semua query yang menghitung jam jadwal (jadwal, terlambat,
+-- timesheet/biaya tenaga kerja, bentrok jadwal, pengingat shift).
+CREATE OR REPLACE FUNCTION schedule_shift(p_shift_id INT, p_date DATE)
+RETURNS TABLE (id INT, name VARCHAR, start_time TIME, end_time TIME, override_id INT)
+LANGUAGE sql STABLE AS $$
+    SELECT s.id, s.name,
+           COALESCE(o.start_time, s.start_time + make_interval(mins => COALESCE(o.start_offset_minutes, 0))),
+           COALESCE(o.end_time, s.end_time + make_interval(mins => COALESCE(o.end_offset_minutes, 0))),
+           o.id
+    FROM shifts s
+    LEFT JOIN LATERAL (
+        SELECT so.id, so.start_time, so.end_time, so.start_offset_minutes, so.end_offset_minutes
+        FROM shift_overrides so
+        WHERE (so.shift_id = s.id OR so.shift_id IS NULL)
+          AND p_date BETWEEN so.start_date AND so.end_date
+        ORDER BY so.shift_id IS NULL, so.id DESC
+        LIMIT 1
+    ) o ON true
+    WHERE s.id = p_shift_id
+$$;
@@ -0,0 +1,6 @@
+-- Migrations Down
+
+DROP TABLE IF EXISTS attendance_face_checks;
+DELETE FROM documents WHERE subject_type = 'profile_photo';
+ALTER TABLE documents DROP CONSTRAINT documents_subject_type_check;
+ALTER TABLE documents ADD CONSTRAINT documents_subject_type_check CHECK (subject_type IN ('attendance'));
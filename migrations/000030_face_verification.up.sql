@@ -0,0 +1,24 @@
+-- Migrations Up
+
+-- Foto profil disimpan sebagai dokumen (subject_type 'profile_photo', subject_id = user) dan
+-- menjadi foto acuan verifikasi wajah saat check-in.
+ALTER TABLE documents DROP CONSTRAINT documents_subject_type_check;
+ALTER TABLE documents ADD CONSTRAINT documents_subject_type_check CHECK (subject_type IN ('attendance', 'profile_photo'));
+
+-- Hasil verifikasi wajah per check-in (lihat internal/faceverify). review_status 'pending'
+-- menandai punch dengan keyakinan rendah/gagal diverifikasi yang menunggu tinjauan admin.
+CREATE TABLE attendance_face_checks (
+    attendance_id INT PRIMARY KEY REFERENCES attendances(id) ON DELETE CASCADE,
+    provider VARCHAR(32) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    score NUMERIC(5, 4) NULL,
+    review_status VARCHAR(20) NULL,
+    reviewed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (status IN ('matched', 'low_confidence', 'no_reference', 'missing_selfie', 'error')),
+    CHECK (score IS NULL OR score BETWEEN 0 AND 1),
+    CHECK (review_status IN ('pending', 'approved', 'rejected'))
+);
+
+CREATE INDEX idx_attendance_face_checks_pending ON attendance_face_checks(created_at) WHERE review_status = 'pending';
@@ -0,0 +1,4 @@
+-- Migrations Down
+
+DROP TABLE IF EXISTS employment_verification_accesses;
+DROP TABLE IF EXISTS employment_verification_links;
@@ -0,0 +1,31 @@
+-- Migrations Up
+
+-- Tautan publik verifikasi kepegawaian untuk pihak ketiga (bank, pemilik sewa). Token tautan
+-- ditandatangani (JWT) dan membawa ID tautan; baris ini menyimpan masa berlaku & pencabutan.
+CREATE TABLE employment_verification_links (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipient VARCHAR(200) NOT NULL, -- Pihak ketiga penerima tautan
+    include_attendance BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMPTZ NULL,
+    access_count INT NOT NULL DEFAULT 0, -- Akses yang dilayani (outcome granted)
+    last_accessed_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX idx_employment_verification_links_user ON employment_verification_links (user_id, created_at DESC);
+
+-- Audit setiap akses ke tautan, termasuk yang ditolak karena kedaluwarsa/dicabut.
+CREATE TABLE employment_verification_accesses (
+    id BIGSERIAL PRIMARY KEY,
+    link_id INT NOT NULL REFERENCES employment_verification_links(id) ON DELETE CASCADE,
+    accessed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ip VARCHAR(64) NOT NULL,
+    user_agent VARCHAR(255) NULL,
+    outcome VARCHAR(20) NOT NULL,
+    CHECK (outcome IN ('granted', 'expired', 'revoked'))
+);
+
+CREATE INDEX idx_employment_verification_accesses_link ON employment_verification_accesses (link_id, accessed_at DESC);
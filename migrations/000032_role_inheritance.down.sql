@@ -0,0 +1,5 @@
+-- Migrations Down
+
+DROP TRIGGER IF EXISTS roles_parent_cycle ON roles;
+DROP FUNCTION IF EXISTS check_role_parent_cycle();
+ALTER TABLE roles DROP COLUMN IF EXISTS parent_id;
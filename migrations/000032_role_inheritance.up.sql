@@ -0,0 +1,37 @@
+-- Migrations Up
+
+-- Hierarki role: role mewarisi hak akses parent-nya secara berantai (mis. Admin -> Manager ->
+-- Supervisor: Admin juga lolos pemeriksaan role Manager dan Supervisor). Lihat internal/rbac.
+ALTER TABLE roles
+    ADD COLUMN parent_id INT NULL REFERENCES roles(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT roles_parent_self_check CHECK (parent_id <> id);
+
+CREATE INDEX idx_roles_parent ON roles(parent_id) WHERE parent_id IS NOT NULL;
+
+-- Tolak perubahan parent yang membentuk siklus. Perubahan hierarki diserialkan dengan advisory
+-- lock transaksi agar dua perubahan bersamaan (A -> B dan B -> A) tidak lolos bersama.
+CREATE OR REPLACE FUNCTION check_role_parent_cycle()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.parent_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+    PERFORM pg_advisory_xact_lock(hashtext('roles.parent_id'));
+    IF EXISTS (
+        WITH RECURSIVE ancestors AS (
+            SELECT id, parent_id FROM roles WHERE id = NEW.parent_id
+            UNION
+            SELECT r.id, r.parent_id FROM roles r JOIN ancestors a ON r.id = a.parent_id
+        )
+        SELECT 1 FROM ancestors WHERE id = NEW.id
+    ) THEN
+        RAISE EXCEPTION 'role % cannot inherit from role %: inheritance cycle', NEW.id, NEW.parent_id
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'roles_parent_cycle_check';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER roles_parent_cycle
+BEFORE INSERT OR UPDATE OF parent_id ON roles
+FOR EACH ROW EXECUTE FUNCTION check_role_parent_cycle();
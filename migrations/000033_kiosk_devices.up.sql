@@ -0,0 +1,19 @@
+-- Migrations Up
+
+-- Perangkat kiosk/terminal absensi yang didaftarkan user. Token kiosk berumur pendek membawa ID
+-- perangkat dan hash fingerprint-nya; setiap request dicocokkan dengan baris ini, sehingga
+-- tablet yang hilang cukup dicabut (revoked_at) tanpa mencabut sesi lain user.
+CREATE TABLE kiosk_devices (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    fingerprint_hash CHAR(64) NOT NULL, -- SHA-256 (hex) fingerprint perangkat; fingerprint asli tidak disimpan
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMPTZ NULL,      -- Diperbarui saat token dirotasi
+    revoked_at TIMESTAMPTZ NULL,
+    revoked_by INT NULL REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Satu fingerprint hanya boleh terdaftar aktif sekali per user.
+CREATE UNIQUE INDEX uq_kiosk_devices_active_fingerprint ON kiosk_devices (user_id, fingerprint_hash) WHERE revoked_at IS NULL;
+CREATE INDEX idx_kiosk_devices_user ON kiosk_devices (user_id, created_at DESC);
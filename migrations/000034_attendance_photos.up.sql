@@ -0,0 +1,24 @@
+-- Migrations Up
+
+-- Foto check-in (selfie) yang disimpan untuk ditinjau admin (lihat internal/photos). Upload
+-- mentah disimpan terenkripsi di incoming_key sampai job pemrosesan membuat versi asli tanpa
+-- EXIF dan thumbnail (keduanya terenkripsi), lalu semua file dihapus job retensi (status purged).
+CREATE TABLE attendance_photos (
+    id SERIAL PRIMARY KEY,
+    attendance_id INT NOT NULL UNIQUE REFERENCES attendances(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    incoming_key VARCHAR(255) NULL,  -- Upload mentah (terenkripsi), dihapus setelah diproses
+    original_key VARCHAR(255) NULL,  -- Foto asli tanpa EXIF (terenkripsi)
+    thumbnail_key VARCHAR(255) NULL, -- Thumbnail (terenkripsi)
+    width INT NULL,
+    height INT NULL,
+    error TEXT NULL,                 -- Alasan status failed
+    captured_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMPTZ NULL,
+    purged_at TIMESTAMPTZ NULL,
+    CHECK (status IN ('pending', 'processed', 'failed', 'purged'))
+);
+
+CREATE INDEX idx_attendance_photos_pending ON attendance_photos (captured_at) WHERE status = 'pending';
+CREATE INDEX idx_attendance_photos_retained ON attendance_photos (captured_at) WHERE status <> 'purged';
@@ -0,0 +1,30 @@
+-- Migrations Up
+
+-- Ekspor laporan yang dibuat di background dan disimpan di storage (lihat internal/reports).
+-- File dihapus job cleanup setelah expires_at (status purged); unduhan sesudahnya dijawab 410.
+CREATE TABLE report_exports (
+    id SERIAL PRIMARY KEY,
+    report_type VARCHAR(50) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}', -- Filter laporan
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    retention_seconds INT NOT NULL,     -- Umur file sejak selesai dibuat
+    storage_key VARCHAR(255) NULL,
+    file_name VARCHAR(255) NULL,
+    content_type VARCHAR(100) NULL,
+    size_bytes BIGINT NULL,
+    row_count INT NULL,
+    error TEXT NULL,                    -- Alasan status failed
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMPTZ NULL,
+    expires_at TIMESTAMPTZ NULL,        -- completed_at + retensi
+    purged_at TIMESTAMPTZ NULL,
+    CHECK (format IN ('csv', 'xlsx')),
+    CHECK (status IN ('pending', 'completed', 'failed', 'purged')),
+    CHECK (retention_seconds > 0)
+);
+
+CREATE INDEX idx_report_exports_created_at ON report_exports (created_at DESC);
+CREATE INDEX idx_report_exports_pending ON report_exports (created_at) WHERE status = 'pending';
+CREATE INDEX idx_report_exports_expires_at ON report_exports (expires_at) WHERE status = 'completed';
@@ -0,0 +1,7 @@
+-- Migrations Down
+
+DROP TRIGGER IF EXISTS bump_token_versions_role_rename ON roles;
+DROP FUNCTION IF EXISTS trigger_bump_role_token_versions();
+
+DROP TRIGGER IF EXISTS bump_token_version_users ON users;
+DROP FUNCTION IF EXISTS trigger_bump_token_version();
@@ -0,0 +1,39 @@
+-- Migrations Up
+
+-- Perubahan hak akses langsung mencabut token yang sudah terbit: token_version dinaikkan saat
+-- role user berganti, user dinonaktifkan, atau dianonimkan. Lewat trigger agar semua jalur
+-- update (edit/patch user, bulk role, akses kontraktor, job kedaluwarsa) ikut tercakup.
+CREATE OR REPLACE FUNCTION trigger_bump_token_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.token_version = OLD.token_version AND (
+        NEW.role_id IS DISTINCT FROM OLD.role_id
+        OR (OLD.is_active AND NOT NEW.is_active)
+        OR (OLD.anonymized_at IS NULL AND NEW.anonymized_at IS NOT NULL)
+    ) THEN
+        NEW.token_version = OLD.token_version + 1;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bump_token_version_users
+BEFORE UPDATE ON users
+FOR EACH ROW
+EXECUTE FUNCTION trigger_bump_token_version();
+
+-- Klaim role di JWT berisi nama role; mengganti nama role membatalkan token anggotanya agar
+-- nama lama tidak terbawa ke role baru yang memakai nama yang sama.
+CREATE OR REPLACE FUNCTION trigger_bump_role_token_versions()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE users SET token_version = token_version + 1 WHERE role_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bump_token_versions_role_rename
+AFTER UPDATE OF name ON roles
+FOR EACH ROW
+WHEN (NEW.name IS DISTINCT FROM OLD.name)
+EXECUTE FUNCTION trigger_bump_role_token_versions();
@@ -0,0 +1,17 @@
+-- Migrations Up
+
+-- Ganti email oleh user sendiri menunggu konfirmasi dari alamat baru. Email baru disimpan
+-- terenkripsi seperti users.email (PII_ENCRYPTION_KEY) dengan blind index untuk cek duplikat.
+-- Maksimal satu permintaan terbuka per user; permintaan baru menggantikan yang lama.
+CREATE TABLE email_change_requests (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_email TEXT NOT NULL,
+    new_email_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(), -- Pengiriman tautan terakhir (jeda kirim ulang)
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    confirmed_at TIMESTAMPTZ NULL
+);
+
+CREATE UNIQUE INDEX idx_email_change_requests_open ON email_change_requests(user_id) WHERE confirmed_at IS NULL;
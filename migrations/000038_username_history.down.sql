@@ -0,0 +1,6 @@
+-- Migrations Down
+
+DROP TRIGGER IF EXISTS record_username_change_users ON users;
+DROP FUNCTION IF EXISTS record_username_change();
+DROP TABLE IF EXISTS username_change_requests;
+DROP TABLE IF EXISTS previous_usernames;
@@ -0,0 +1,54 @@
+-- Migrations Up
+
+-- Riwayat username: setiap username lama disimpan di previous_usernames agar rujukan lama
+-- (mis. di file ekspor atau laporan yang sudah diunduh) tetap bisa dipetakan ke user-nya.
+-- Diisi oleh trigger agar semua jalur rename (profil, edit/patch admin, persetujuan) tercakup;
+-- anonimisasi tidak dicatat dan riwayatnya dihapus bersama data pribadi lain.
+CREATE TABLE previous_usernames (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    username VARCHAR(100) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_previous_usernames_user ON previous_usernames(user_id, changed_at DESC);
+CREATE INDEX idx_previous_usernames_username ON previous_usernames(username);
+
+-- Permintaan ganti username yang menunggu persetujuan admin
+-- (pengaturan profile.username_change_policy = approval). Maksimal satu permintaan pending per user.
+CREATE TABLE username_change_requests (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_username VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ NULL,
+    review_note TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+CREATE UNIQUE INDEX idx_username_change_requests_pending ON username_change_requests(user_id) WHERE status = 'pending';
+CREATE INDEX idx_username_change_requests_status ON username_change_requests(status, created_at);
+
+-- details audit hanya merujuk baris riwayat (bukan nilai username), sesuai aturan audit_log.
+CREATE OR REPLACE FUNCTION record_username_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    history_id INT;
+BEGIN
+    IF NEW.username IS DISTINCT FROM OLD.username AND NEW.anonymized_at IS NULL THEN
+        INSERT INTO previous_usernames (user_id, username)
+        VALUES (OLD.id, OLD.username)
+        RETURNING id INTO history_id;
+        INSERT INTO audit_log (user_id, action, details)
+        VALUES (NEW.id, 'profile.username_changed', jsonb_build_object('previous_username_id', history_id));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_username_change_users
+AFTER UPDATE OF username ON users
+FOR EACH ROW
+EXECUTE FUNCTION record_username_change();
@@ -0,0 +1,9 @@
+-- Migrations Down
+
+DROP TABLE IF EXISTS phone_otps;
+DROP TRIGGER IF EXISTS reset_phone_verification_users ON users;
+DROP FUNCTION IF EXISTS trigger_reset_phone_verification();
+ALTER TABLE users
+    DROP COLUMN IF EXISTS sms_reminders,
+    DROP COLUMN IF EXISTS sms_two_factor,
+    DROP COLUMN IF EXISTS phone_verified_at;
@@ -0,0 +1,44 @@
+-- Migrations Up
+
+-- Verifikasi nomor HP lewat kode OTP SMS, untuk karyawan tanpa email kantor. Nomor yang sudah
+-- diverifikasi bisa dipakai sebagai faktor kedua login (sms_two_factor) dan untuk pengingat
+-- shift lewat SMS (sms_reminders).
+ALTER TABLE users
+    ADD COLUMN phone_verified_at TIMESTAMPTZ NULL,
+    ADD COLUMN sms_two_factor BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN sms_reminders BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Nomor HP yang berganti (lewat jalur mana pun) harus diverifikasi ulang; fitur SMS ikut
+-- dimatikan agar kode tidak terkirim ke nomor yang belum terbukti milik user.
+CREATE OR REPLACE FUNCTION trigger_reset_phone_verification()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.phone_hash IS DISTINCT FROM OLD.phone_hash THEN
+        NEW.phone_verified_at = NULL;
+        NEW.sms_two_factor = FALSE;
+        NEW.sms_reminders = FALSE;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reset_phone_verification_users
+BEFORE UPDATE ON users
+FOR EACH ROW
+EXECUTE FUNCTION trigger_reset_phone_verification();
+
+-- Kode OTP SMS. Hanya hash kode yang disimpan; phone_hash mengikat kode ke nomor saat dikirim.
+-- Maksimal satu kode terbuka per user & tujuan; kode baru menggantikan yang lama.
+CREATE TABLE phone_otps (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(20) NOT NULL, -- verify_phone | login
+    code_hash VARCHAR(64) NOT NULL,
+    phone_hash VARCHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    consumed_at TIMESTAMPTZ NULL
+);
+
+CREATE UNIQUE INDEX idx_phone_otps_open ON phone_otps(user_id, purpose) WHERE consumed_at IS NULL;
@@ -0,0 +1,23 @@
+-- Migrations Up
+
+-- Undangan onboarding: admin mengundang alamat email dengan role yang sudah ditentukan, dan
+-- penerima menyelesaikan registrasi lewat tautan bertoken. Dipakai saat registrasi publik
+-- dimatikan (pengaturan auth.registration_mode = invite_only). Email disimpan terenkripsi seperti
+-- users.email (PII_ENCRYPTION_KEY) dengan blind index untuk cek duplikat.
+-- Maksimal satu undangan terbuka per email; undangan baru menggantikan yang lama.
+CREATE TABLE user_invitations (
+    id SERIAL PRIMARY KEY,
+    email TEXT NOT NULL,
+    email_hash VARCHAR(64) NOT NULL,
+    role_id INT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    invited_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(), -- Pengiriman tautan terakhir (jeda kirim ulang)
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMPTZ NULL,
+    accepted_user_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMPTZ NULL
+);
+
+CREATE UNIQUE INDEX idx_user_invitations_open ON user_invitations(email_hash) WHERE accepted_at IS NULL AND revoked_at IS NULL;
+CREATE INDEX idx_user_invitations_created_at ON user_invitations(created_at);
@@ -0,0 +1,4 @@
+-- Migrations Down
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS must_change_password;
@@ -0,0 +1,6 @@
+-- Migrations Up
+
+-- Akun yang dibuat admin (POST /admin/users) bisa diwajibkan mengganti password sementara:
+-- login dengan password benar hanya menghasilkan token reset, belum token sesi.
+ALTER TABLE users
+    ADD COLUMN must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
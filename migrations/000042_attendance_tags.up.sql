@@ -0,0 +1,15 @@
+-- Migrations Up
+
+-- Tag absensi terstruktur (mis. wfh, client_visit, sick) di samping catatan bebas, agar laporan
+-- bisa merekap per tag. Tag yang boleh dipakai diatur lewat pengaturan attendance.tags; tag yang
+-- kemudian dihapus dari pengaturan tetap tersimpan pada record lama.
+CREATE TABLE attendance_tags (
+    attendance_id INT NOT NULL,
+    tag VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (attendance_id, tag),
+    FOREIGN KEY (attendance_id) REFERENCES attendances(id) ON DELETE CASCADE
+);
+
+-- Rekap per tag (GET /admin/attendance/report/tags).
+CREATE INDEX idx_attendance_tags_tag ON attendance_tags(tag);
@@ -0,0 +1,8 @@
+-- Migrations Down
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS field_work_allowed,
+    DROP COLUMN IF EXISTS remote_days;
+
+ALTER TABLE attendances
+    DROP COLUMN IF EXISTS mode;
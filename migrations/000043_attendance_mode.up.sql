@@ -0,0 +1,12 @@
+-- Migrations Up
+
+-- Mode kerja per sesi absensi: onsite (default), remote (WFH), atau field (tugas lapangan).
+ALTER TABLE attendances
+    ADD COLUMN mode VARCHAR(10) NOT NULL DEFAULT 'onsite'
+        CHECK (mode IN ('onsite', 'remote', 'field'));
+
+-- Kebijakan per user: hari boleh remote (sun..sat dipisah koma, kosong = tidak boleh) dan
+-- izin tugas lapangan. Onsite selalu boleh.
+ALTER TABLE users
+    ADD COLUMN remote_days VARCHAR(27) NOT NULL DEFAULT '',
+    ADD COLUMN field_work_allowed BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,17 @@
+-- Migrations Up
+
+-- Titik lokasi berkala selama sesi absensi mode field (POST /user/attendance/:id/ping).
+-- Disimpan ringkas: koordinat dalam mikroderajat (INT, presisi ~0,1 m) tanpa kolom id; satu
+-- titik per waktu rekam per sesi. Titik lama dihapus job retensi (route.retention_days).
+CREATE TABLE attendance_pings (
+    attendance_id INT NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    lat_e6 INT NOT NULL CHECK (lat_e6 BETWEEN -90000000 AND 90000000),
+    lon_e6 INT NOT NULL CHECK (lon_e6 BETWEEN -180000000 AND 180000000),
+    accuracy_m SMALLINT CHECK (accuracy_m >= 0),
+    PRIMARY KEY (attendance_id, recorded_at),
+    FOREIGN KEY (attendance_id) REFERENCES attendances(id) ON DELETE CASCADE
+);
+
+-- Job retensi menghapus titik berdasarkan waktu rekam.
+CREATE INDEX idx_attendance_pings_recorded_at ON attendance_pings(recorded_at);
@@ -0,0 +1,6 @@
+-- Migrations Down
+-- PERINGATAN: snapshot laporan yang sudah dibekukan akan hilang.
+
+DROP TRIGGER IF EXISTS report_snapshots_immutable ON report_snapshots;
+DROP FUNCTION IF EXISTS report_snapshots_immutable();
+DROP TABLE IF EXISTS report_snapshots;
@@ -0,0 +1,41 @@
+-- Migrations Up
+
+-- Snapshot ringkasan absensi satu periode (mis. tutup buku akhir bulan) yang dibekukan untuk
+-- payroll: baris ringkasan per user disimpan apa adanya bersama checksum SHA-256-nya, sehingga
+-- angka yang sudah diserahkan tidak berubah walaupun absensi dikoreksi setelahnya.
+CREATE TABLE report_snapshots (
+    id SERIAL PRIMARY KEY,
+    label VARCHAR(100),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    payroll_period_id INT REFERENCES payroll_periods(id) ON DELETE SET NULL,
+    rows JSONB NOT NULL,
+    row_count INT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_report_snapshots_created_at ON report_snapshots(created_at DESC);
+
+-- Snapshot tidak bisa diubah. Pengecualian: referensi yang dikosongkan ON DELETE SET NULL
+-- (periode payroll atau admin pembuatnya dihapus).
+CREATE OR REPLACE FUNCTION report_snapshots_immutable()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (NEW.id, NEW.label, NEW.start_date, NEW.end_date, NEW.rows, NEW.row_count, NEW.checksum, NEW.created_at)
+           IS NOT DISTINCT FROM
+       (OLD.id, OLD.label, OLD.start_date, OLD.end_date, OLD.rows, OLD.row_count, OLD.checksum, OLD.created_at)
+       AND (NEW.payroll_period_id IS NULL OR NEW.payroll_period_id IS NOT DISTINCT FROM OLD.payroll_period_id)
+       AND (NEW.created_by IS NULL OR NEW.created_by IS NOT DISTINCT FROM OLD.created_by) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'report_snapshots are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER report_snapshots_immutable
+BEFORE UPDATE ON report_snapshots
+FOR EACH ROW
+EXECUTE FUNCTION report_snapshots_immutable();
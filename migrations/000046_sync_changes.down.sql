@@ -0,0 +1,16 @@
+-- Migrations Down
+
+DROP TRIGGER IF EXISTS sync_tombstone_user_schedules ON user_schedules;
+DROP TRIGGER IF EXISTS sync_tombstone_attendances ON attendances;
+DROP TRIGGER IF EXISTS sync_tombstone_users ON users;
+DROP FUNCTION IF EXISTS record_sync_tombstone();
+DROP TABLE IF EXISTS sync_tombstones;
+
+DROP INDEX IF EXISTS idx_user_schedules_updated_at;
+DROP INDEX IF EXISTS idx_attendances_updated_at;
+DROP INDEX IF EXISTS idx_users_updated_at;
+ALTER TABLE attendances ALTER COLUMN updated_at DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN updated_at DROP NOT NULL;
+
+DROP TRIGGER IF EXISTS set_timestamp_user_schedules ON user_schedules;
+ALTER TABLE user_schedules DROP COLUMN IF EXISTS updated_at;
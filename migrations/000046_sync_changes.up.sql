@@ -0,0 +1,60 @@
+-- Migrations Up
+
+-- Sinkronisasi delta untuk sistem eksternal (GET /sync/*): perubahan dibaca berdasarkan
+-- updated_at, penghapusan dicatat sebagai tombstone. Urutan keyset (updated_at, id).
+
+-- user_schedules belum punya updated_at; isi awal dari created_at.
+ALTER TABLE user_schedules ADD COLUMN updated_at TIMESTAMPTZ;
+UPDATE user_schedules SET updated_at = COALESCE(created_at, CURRENT_TIMESTAMP);
+ALTER TABLE user_schedules
+    ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP,
+    ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE TRIGGER set_timestamp_user_schedules
+BEFORE UPDATE ON user_schedules
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+-- Baris lama tanpa updated_at tidak akan pernah terbaca oleh sync.
+UPDATE users SET updated_at = COALESCE(created_at, CURRENT_TIMESTAMP) WHERE updated_at IS NULL;
+UPDATE attendances SET updated_at = COALESCE(created_at, CURRENT_TIMESTAMP) WHERE updated_at IS NULL;
+ALTER TABLE users ALTER COLUMN updated_at SET NOT NULL;
+ALTER TABLE attendances ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX idx_users_updated_at ON users(updated_at, id);
+CREATE INDEX idx_attendances_updated_at ON attendances(updated_at, id);
+CREATE INDEX idx_user_schedules_updated_at ON user_schedules(updated_at, id);
+
+-- Tombstone record yang dihapus (termasuk yang terhapus lewat ON DELETE CASCADE).
+CREATE TABLE sync_tombstones (
+    entity VARCHAR(20) NOT NULL CHECK (entity IN ('users', 'attendances', 'user_schedules')),
+    entity_id INT NOT NULL,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (entity, entity_id)
+);
+
+CREATE INDEX idx_sync_tombstones_deleted_at ON sync_tombstones(entity, deleted_at, entity_id);
+
+CREATE OR REPLACE FUNCTION record_sync_tombstone()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO sync_tombstones (entity, entity_id) VALUES (TG_TABLE_NAME, OLD.id)
+    ON CONFLICT (entity, entity_id) DO UPDATE SET deleted_at = EXCLUDED.deleted_at;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER sync_tombstone_users
+AFTER DELETE ON users
+FOR EACH ROW
+EXECUTE FUNCTION record_sync_tombstone();
+
+CREATE TRIGGER sync_tombstone_attendances
+AFTER DELETE ON attendances
+FOR EACH ROW
+EXECUTE FUNCTION record_sync_tombstone();
+
+CREATE TRIGGER sync_tombstone_user_schedules
+AFTER DELETE ON user_schedules
+FOR EACH ROW
+EXECUTE FUNCTION record_sync_tombstone();
@@ -0,0 +1,28 @@
+-- Migrations Up
+
+-- Rekaman request/response untuk diagnosis masalah klien yang sulit direproduksi. Hanya diisi
+-- untuk route/user yang diaktifkan lewat pengaturan debug.capture_*; header kredensial dan field
+-- sensitif di body sudah di-redact sebelum disimpan. Baris dihapus job retensi setelah expires_at.
+CREATE TABLE debug_captures (
+    id BIGSERIAL PRIMARY KEY,
+    request_id VARCHAR(100),
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path TEXT NOT NULL,
+    query TEXT,
+    status INT NOT NULL,
+    latency_ms INT NOT NULL,
+    error TEXT,
+    ip VARCHAR(64),
+    request_headers JSONB NOT NULL DEFAULT '{}',
+    request_body TEXT,
+    response_headers JSONB NOT NULL DEFAULT '{}',
+    response_body TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_debug_captures_created_at ON debug_captures(created_at DESC);
+CREATE INDEX idx_debug_captures_user_id ON debug_captures(user_id, created_at DESC);
+CREATE INDEX idx_debug_captures_expires_at ON debug_captures(expires_at);
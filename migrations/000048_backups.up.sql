@@ -0,0 +1,23 @@
+-- Migrations Up
+
+-- Backup logis database yang diminta admin (POST /admin/maintenance/backup) dan dibuat job
+-- backups di storage (lihat internal/backup). Hanya satu backup yang boleh antre/berjalan.
+CREATE TABLE backups (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    schema_version BIGINT NULL,     -- Versi migrasi saat snapshot diambil
+    storage_key VARCHAR(255) NULL,
+    file_name VARCHAR(255) NULL,
+    size_bytes BIGINT NULL,
+    table_count INT NULL,
+    row_count BIGINT NULL,
+    error TEXT NULL,                -- Alasan status failed
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMPTZ NULL,
+    completed_at TIMESTAMPTZ NULL,
+    CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+
+CREATE INDEX idx_backups_created_at ON backups (created_at DESC);
+CREATE UNIQUE INDEX idx_backups_active ON backups ((true)) WHERE status IN ('pending', 'running');
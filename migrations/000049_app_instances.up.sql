@@ -0,0 +1,14 @@
+-- Migrations Up
+
+-- Heartbeat instance API yang sedang berjalan beserta versi aplikasinya (lihat internal/instances).
+-- Runner migrasi (cmd/migrate) menolak migrasi destruktif selama masih ada instance hidup dengan
+-- versi lain, agar rolling deploy multi-replika tidak merusak versi lama yang masih melayani.
+CREATE TABLE app_instances (
+    instance_id VARCHAR(64) PRIMARY KEY,   -- Acak per proses
+    app_version VARCHAR(100) NOT NULL,     -- APP_VERSION atau versi build
+    hostname VARCHAR(255) NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_app_instances_last_seen_at ON app_instances (last_seen_at);
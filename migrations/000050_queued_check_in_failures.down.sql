@@ -0,0 +1,3 @@
+-- Migrations Down
+
+DROP TABLE IF EXISTS queued_check_in_failures;
@@ -0,0 +1,24 @@
+-- Migrations Up
+
+-- Dead letter check-in yang ditampung saat mode degraded/maintenance (sudah dijawab 202) tetapi
+-- gagal dicatat saat dikirim ulang karena alasan selain database tidak tersedia (mis. jadwal
+-- dihapus, user sudah check-in lagi). Baris tanpa resolved_at menunggu tindak lanjut admin.
+CREATE TABLE queued_check_in_failures (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    check_in_at TIMESTAMPTZ NOT NULL,          -- Waktu check-in asli (saat request diterima)
+    notes TEXT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    project_id INT NULL,                       -- Tanpa FK: project bisa saja sudah dihapus
+    mode VARCHAR(20) NOT NULL DEFAULT '',
+    source VARCHAR(20) NOT NULL,               -- Buffer asal punch
+    error TEXT NOT NULL,                       -- Alasan gagal dicatat
+    failed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ NULL,
+    resolution_note TEXT NULL,
+    CHECK (source IN ('degraded', 'maintenance'))
+);
+
+CREATE INDEX idx_queued_check_in_failures_open ON queued_check_in_failures(failed_at) WHERE resolved_at IS NULL;
+CREATE INDEX idx_queued_check_in_failures_user_id ON queued_check_in_failures(user_id);
@@ -0,0 +1,46 @@
+-- Migrations Down (SQLite)
+
+-- Trigger ikut terhapus bersama tabelnya.
+DROP VIEW IF EXISTS schedule_shifts;
+DROP TABLE IF EXISTS app_instances;
+DROP TABLE IF EXISTS backups;
+DROP TABLE IF EXISTS debug_captures;
+DROP TABLE IF EXISTS sync_tombstones;
+DROP TABLE IF EXISTS user_invitations;
+DROP TABLE IF EXISTS phone_otps;
+DROP TABLE IF EXISTS username_change_requests;
+DROP TABLE IF EXISTS previous_usernames;
+DROP TABLE IF EXISTS email_change_requests;
+DROP TABLE IF EXISTS report_snapshots;
+DROP TABLE IF EXISTS report_exports;
+DROP TABLE IF EXISTS kiosk_devices;
+DROP TABLE IF EXISTS employment_verification_accesses;
+DROP TABLE IF EXISTS employment_verification_links;
+DROP TABLE IF EXISTS scheduled_jobs;
+DROP TABLE IF EXISTS approval_escalations;
+DROP TABLE IF EXISTS approval_delegations;
+DROP TABLE IF EXISTS outbox_messages;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS push_reminders;
+DROP TABLE IF EXISTS device_tokens;
+DROP TABLE IF EXISTS attendance_disputes;
+DROP TABLE IF EXISTS attendance_signoffs;
+DROP TABLE IF EXISTS payroll_periods;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS documents;
+DROP TABLE IF EXISTS announcements;
+DROP TABLE IF EXISTS settings;
+DROP TABLE IF EXISTS queued_check_in_failures;
+DROP TABLE IF EXISTS attendance_photos;
+DROP TABLE IF EXISTS attendance_face_checks;
+DROP TABLE IF EXISTS attendance_pings;
+DROP TABLE IF EXISTS attendance_tags;
+DROP TABLE IF EXISTS attendance_segments;
+DROP TABLE IF EXISTS attendance_events;
+DROP TABLE IF EXISTS attendances;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS user_schedules;
+DROP TABLE IF EXISTS shift_overrides;
+DROP TABLE IF EXISTS shifts;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS roles;
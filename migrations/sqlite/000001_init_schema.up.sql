@@ -0,0 +1,933 @@
+-- Migrations Up (SQLite)
+--
+-- Skema SQLite setara dengan hasil akhir migrations/*.up.sql (PostgreSQL), dipakai saat
+-- DB_DRIVER=sqlite. Penyesuaian tipe:
+--   * TIMESTAMPTZ -> TIMESTAMP berisi teks UTC 'YYYY-MM-DD HH:MM:SS.fffffffff' (urut leksikal = urut waktu;
+--     default strftime diberi nol tambahan agar lebarnya sama dengan nilai dari aplikasi).
+--   * DATE -> TEXT 'YYYY-MM-DD', TIME -> TEXT 'HH:MM:SS'.
+--   * Array (INT[]/TEXT[]) dan JSONB -> TEXT berisi JSON.
+--   * NUMERIC -> REAL.
+-- Trigger PL/pgSQL ditulis ulang sebagai trigger SQLite. Trigger BEFORE SQLite tidak bisa
+-- mengubah NEW, sehingga version/updated_at/token_version diperbarui oleh trigger AFTER UPDATE
+-- (RETURNING belum melihat perubahan ini; repository yang membutuhkannya menulis kolom itu sendiri).
+-- Cek siklus pewarisan role (CTE rekursif) dilakukan di repository karena body trigger
+-- SQLite tidak mendukung WITH.
+
+-- Tabel Peran
+CREATE TABLE roles (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    hourly_rate REAL NULL CHECK (hourly_rate >= 0),
+    parent_id INT NULL REFERENCES roles(id) ON DELETE RESTRICT,
+    CONSTRAINT roles_parent_self_check CHECK (parent_id <> id)
+);
+
+CREATE INDEX idx_roles_parent ON roles(parent_id) WHERE parent_id IS NOT NULL;
+
+-- Tabel Pengguna
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    password VARCHAR(255) NOT NULL,
+    email TEXT NOT NULL,
+    email_hash VARCHAR(64) NULL,
+    phone TEXT NULL,
+    phone_hash VARCHAR(64) NULL,
+    national_id TEXT NULL,
+    national_id_hash VARCHAR(64) NULL,
+    first_name VARCHAR(100),
+    last_name VARCHAR(100),
+    role_id INT NOT NULL REFERENCES roles(id),
+    anonymized_at TIMESTAMP NULL,
+    version INT NOT NULL DEFAULT 1,
+    user_type VARCHAR(20) NOT NULL DEFAULT 'employee',
+    access_valid_from TEXT NULL,
+    access_valid_until TEXT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    hire_date TEXT NULL,
+    employment_status VARCHAR(20) NOT NULL DEFAULT 'permanent',
+    probation_end TEXT NULL,
+    probation_notified_at TIMESTAMP NULL,
+    manager_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    token_version INT NOT NULL DEFAULT 0,
+    password_reset_required BOOLEAN NOT NULL DEFAULT FALSE,
+    hourly_rate REAL NULL CHECK (hourly_rate >= 0),
+    phone_verified_at TIMESTAMP NULL,
+    sms_two_factor BOOLEAN NOT NULL DEFAULT FALSE,
+    sms_reminders BOOLEAN NOT NULL DEFAULT FALSE,
+    must_change_password BOOLEAN NOT NULL DEFAULT FALSE,
+    remote_days VARCHAR(27) NOT NULL DEFAULT '',
+    field_work_allowed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CONSTRAINT users_email_hash_key UNIQUE (email_hash),
+    CONSTRAINT users_national_id_hash_key UNIQUE (national_id_hash),
+    CONSTRAINT users_user_type_check CHECK (user_type IN ('employee', 'contractor')),
+    CONSTRAINT users_contractor_validity_check CHECK (user_type <> 'contractor' OR access_valid_until IS NOT NULL),
+    CONSTRAINT users_access_range_check CHECK (access_valid_from IS NULL OR access_valid_until IS NULL OR access_valid_from <= access_valid_until),
+    CONSTRAINT users_employment_status_check CHECK (employment_status IN ('probation', 'permanent', 'fixed_term', 'intern', 'terminated')),
+    CONSTRAINT users_probation_end_check CHECK (employment_status <> 'probation' OR probation_end IS NOT NULL),
+    CONSTRAINT users_probation_range_check CHECK (hire_date IS NULL OR probation_end IS NULL OR probation_end >= hire_date),
+    CONSTRAINT users_manager_not_self_check CHECK (manager_id IS NULL OR manager_id <> id)
+);
+
+CREATE INDEX idx_users_role_id ON users(role_id);
+CREATE INDEX idx_users_phone_hash ON users(phone_hash);
+CREATE INDEX idx_users_active_contractors ON users(access_valid_until) WHERE user_type = 'contractor' AND is_active;
+CREATE INDEX idx_users_employment_status ON users(employment_status);
+CREATE INDEX idx_users_probation_pending ON users(probation_end) WHERE employment_status = 'probation' AND probation_notified_at IS NULL;
+CREATE INDEX idx_users_manager_id ON users(manager_id);
+CREATE INDEX idx_users_updated_at ON users(updated_at, id);
+
+-- Tabel Shift Kerja
+CREATE TABLE shifts (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    start_time TEXT NOT NULL,
+    end_time TEXT NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now'))
+);
+
+-- Override jam shift musiman (mis. Ramadan); shift_id NULL = berlaku untuk semua shift.
+CREATE TABLE shift_overrides (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    shift_id INT NULL REFERENCES shifts(id) ON DELETE CASCADE,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    start_time TEXT NULL,
+    end_time TEXT NULL,
+    start_offset_minutes INT NOT NULL DEFAULT 0,
+    end_offset_minutes INT NOT NULL DEFAULT 0,
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CHECK (end_date >= start_date),
+    CHECK ((start_time IS NULL) = (end_time IS NULL)),
+    CHECK (start_time IS NULL OR shift_id IS NOT NULL),
+    CHECK (start_offset_minutes BETWEEN -720 AND 720 AND end_offset_minutes BETWEEN -720 AND 720)
+);
+
+CREATE INDEX idx_shift_overrides_dates ON shift_overrides (start_date, end_date);
+
+-- Tabel Jadwal Pengguna
+CREATE TABLE user_schedules (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    shift_id INT NOT NULL REFERENCES shifts(id) ON DELETE RESTRICT,
+    date TEXT NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    UNIQUE (user_id, date) -- Mencegah jadwal ganda per hari
+);
+
+CREATE INDEX idx_user_schedules_date ON user_schedules(date);
+CREATE INDEX idx_user_schedules_updated_at ON user_schedules(updated_at, id);
+
+-- Shift efektif per jadwal setelah override musiman (PostgreSQL: fungsi schedule_shift yang
+-- dipakai lewat JOIN LATERAL). Override khusus shift mengalahkan override semua shift; jika
+-- sama-sama cocok, yang terbaru menang. override_id NULL jika tidak ada override.
+CREATE VIEW schedule_shifts AS
+SELECT us.id AS schedule_id, s.id, s.name,
+       COALESCE(o.start_time, time(s.start_time, COALESCE(o.start_offset_minutes, 0) || ' minutes')) AS start_time,
+       COALESCE(o.end_time, time(s.end_time, COALESCE(o.end_offset_minutes, 0) || ' minutes')) AS end_time,
+       o.id AS override_id
+FROM user_schedules us
+JOIN shifts s ON s.id = us.shift_id
+LEFT JOIN shift_overrides o ON o.id = (
+    SELECT so.id FROM shift_overrides so
+    WHERE (so.shift_id = s.id OR so.shift_id IS NULL) AND us.date BETWEEN so.start_date AND so.end_date
+    ORDER BY so.shift_id IS NULL, so.id DESC
+    LIMIT 1
+);
+
+-- Tabel Project
+CREATE TABLE projects (
+    id INTEGER PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    cost_center VARCHAR(64) NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now'))
+);
+
+-- Tabel Absensi
+CREATE TABLE attendances (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    schedule_id INT NULL REFERENCES user_schedules(id) ON DELETE SET NULL,
+    project_id INT NULL REFERENCES projects(id) ON DELETE RESTRICT,
+    check_in_at TIMESTAMP NOT NULL,
+    check_out_at TIMESTAMP NULL,
+    notes TEXT NULL,
+    mode VARCHAR(10) NOT NULL DEFAULT 'onsite' CHECK (mode IN ('onsite', 'remote', 'field')),
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now'))
+);
+
+CREATE INDEX idx_attendances_user_checkin ON attendances(user_id, check_in_at);
+CREATE UNIQUE INDEX uq_attendances_open_session ON attendances(user_id) WHERE check_out_at IS NULL;
+CREATE INDEX idx_attendances_schedule_id ON attendances(schedule_id);
+CREATE INDEX idx_attendances_project_checkin ON attendances(project_id, check_in_at) WHERE project_id IS NOT NULL;
+CREATE INDEX idx_attendances_updated_at ON attendances(updated_at, id);
+
+-- Riwayat absensi append-only
+CREATE TABLE attendance_events (
+    id INTEGER PRIMARY KEY,
+    attendance_id INT NOT NULL REFERENCES attendances(id) ON DELETE CASCADE,
+    user_id INT NOT NULL,
+    event_type VARCHAR(32) NOT NULL, -- check_in | check_out | correction | legacy_import
+    check_in_at TIMESTAMP NOT NULL,
+    check_out_at TIMESTAMP NULL,
+    notes TEXT NULL,
+    reason TEXT NULL,                -- Wajib untuk koreksi admin
+    actor_user_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CHECK (event_type IN ('check_in', 'check_out', 'correction', 'legacy_import')),
+    CHECK (check_out_at IS NULL OR check_out_at >= check_in_at)
+);
+
+CREATE INDEX idx_attendance_events_attendance ON attendance_events(attendance_id, id);
+CREATE INDEX idx_attendance_events_user ON attendance_events(user_id);
+
+-- Segmen kerja per project dalam satu sesi absensi
+CREATE TABLE attendance_segments (
+    id INTEGER PRIMARY KEY,
+    attendance_id INT NOT NULL REFERENCES attendances(id) ON DELETE CASCADE,
+    project_id INT NULL REFERENCES projects(id) ON DELETE RESTRICT, -- NULL = tanpa project
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP NULL,       -- NULL = segmen sedang berjalan
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX idx_attendance_segments_attendance ON attendance_segments(attendance_id, started_at);
+CREATE INDEX idx_attendance_segments_project ON attendance_segments(project_id) WHERE project_id IS NOT NULL;
+CREATE UNIQUE INDEX uq_attendance_segments_open ON attendance_segments(attendance_id) WHERE ended_at IS NULL;
+
+CREATE TABLE attendance_tags (
+    attendance_id INT NOT NULL REFERENCES attendances(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    PRIMARY KEY (attendance_id, tag)
+);
+
+CREATE INDEX idx_attendance_tags_tag ON attendance_tags(tag);
+
+CREATE TABLE attendance_pings (
+    attendance_id INT NOT NULL REFERENCES attendances(id) ON DELETE CASCADE,
+    recorded_at TIMESTAMP NOT NULL,
+    lat_e6 INT NOT NULL CHECK (lat_e6 BETWEEN -90000000 AND 90000000),
+    lon_e6 INT NOT NULL CHECK (lon_e6 BETWEEN -180000000 AND 180000000),
+    accuracy_m SMALLINT CHECK (accuracy_m >= 0),
+    PRIMARY KEY (attendance_id, recorded_at)
+);
+
+CREATE INDEX idx_attendance_pings_recorded_at ON attendance_pings(recorded_at);
+
+CREATE TABLE attendance_face_checks (
+    attendance_id INT PRIMARY KEY REFERENCES attendances(id) ON DELETE CASCADE,
+    provider VARCHAR(32) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    score REAL NULL,
+    review_status VARCHAR(20) NULL,
+    reviewed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CHECK (status IN ('matched', 'low_confidence', 'no_reference', 'missing_selfie', 'error')),
+    CHECK (score IS NULL OR score BETWEEN 0 AND 1),
+    CHECK (review_status IN ('pending', 'approved', 'rejected'))
+);
+
+CREATE INDEX idx_attendance_face_checks_pending ON attendance_face_checks(created_at) WHERE review_status = 'pending';
+
+CREATE TABLE attendance_photos (
+    id INTEGER PRIMARY KEY,
+    attendance_id INT NOT NULL UNIQUE REFERENCES attendances(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    incoming_key VARCHAR(255) NULL,  -- Upload mentah (terenkripsi), dihapus setelah diproses
+    original_key VARCHAR(255) NULL,  -- Foto asli tanpa EXIF (terenkripsi)
+    thumbnail_key VARCHAR(255) NULL, -- Thumbnail (terenkripsi)
+    width INT NULL,
+    height INT NULL,
+    error TEXT NULL,                 -- Alasan status failed
+    captured_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    processed_at TIMESTAMP NULL,
+    purged_at TIMESTAMP NULL,
+    CHECK (status IN ('pending', 'processed', 'failed', 'purged'))
+);
+
+CREATE INDEX idx_attendance_photos_pending ON attendance_photos (captured_at) WHERE status = 'pending';
+CREATE INDEX idx_attendance_photos_retained ON attendance_photos (captured_at) WHERE status <> 'purged';
+
+-- Check-in tertampung (mode degraded/maintenance) yang gagal dicatat
+CREATE TABLE queued_check_in_failures (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    check_in_at TIMESTAMP NOT NULL,            -- Waktu check-in asli (saat request diterima)
+    notes TEXT NULL,
+    tags TEXT NOT NULL DEFAULT '[]',
+    project_id INT NULL,                       -- Tanpa FK: project bisa saja sudah dihapus
+    mode VARCHAR(20) NOT NULL DEFAULT '',
+    source VARCHAR(20) NOT NULL,               -- Buffer asal punch
+    error TEXT NOT NULL,                       -- Alasan gagal dicatat
+    failed_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    resolved_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP NULL,
+    resolution_note TEXT NULL,
+    CHECK (source IN ('degraded', 'maintenance'))
+);
+
+CREATE INDEX idx_queued_check_in_failures_open ON queued_check_in_failures(failed_at) WHERE resolved_at IS NULL;
+CREATE INDEX idx_queued_check_in_failures_user_id ON queued_check_in_failures(user_id);
+
+CREATE TABLE settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now'))
+);
+
+CREATE TABLE announcements (
+    id INTEGER PRIMARY KEY,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    publish_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    expires_at TIMESTAMP NULL,
+    audience_role_ids TEXT NOT NULL DEFAULT '[]',
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CHECK (expires_at IS NULL OR expires_at > publish_at)
+);
+
+CREATE INDEX idx_announcements_publish_window ON announcements(publish_at, expires_at);
+
+CREATE TABLE documents (
+    id INTEGER PRIMARY KEY,
+    owner_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subject_type VARCHAR(32) NOT NULL,
+    subject_id INT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    scan_status VARCHAR(16) NOT NULL, -- clean | skipped (pemindai tidak dikonfigurasi)
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CONSTRAINT documents_subject_type_check CHECK (subject_type IN ('attendance', 'profile_photo')),
+    CHECK (scan_status IN ('clean', 'skipped'))
+);
+
+CREATE INDEX idx_documents_subject ON documents(subject_type, subject_id);
+
+CREATE TABLE audit_log (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,   -- Subjek: user yang aktivitasnya dicatat
+    actor_user_id INT NULL REFERENCES users(id) ON DELETE SET NULL, -- User/admin yang memicu (NULL = sistem/tidak diketahui)
+    action VARCHAR(64) NOT NULL,
+    details TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    -- Setara regex ^[a-z_]+\.[a-z_]+$
+    CHECK (action NOT GLOB '*[^a-z_.]*' AND action GLOB '?*.?*' AND action NOT GLOB '*.*.*')
+);
+
+CREATE INDEX idx_audit_log_user_created ON audit_log(user_id, created_at DESC);
+CREATE INDEX idx_audit_log_login_device ON audit_log(user_id, (details->>'device'))
+    WHERE action = 'auth.login_succeeded';
+
+CREATE TABLE payroll_periods (
+    id INTEGER PRIMARY KEY,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'open',
+    closed_at TIMESTAMP NULL,
+    closed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    reopened_at TIMESTAMP NULL,
+    reopened_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    reopen_reason TEXT NULL,
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CHECK (end_date >= start_date),
+    CHECK (status IN ('open', 'closed'))
+);
+
+CREATE TABLE attendance_signoffs (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    work_date TEXT NOT NULL,
+    signed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    signed_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    exceptions TEXT NOT NULL DEFAULT '[]',
+    note TEXT NULL,
+    CONSTRAINT uq_attendance_signoffs_user_date UNIQUE (user_id, work_date)
+);
+
+CREATE INDEX idx_attendance_signoffs_work_date ON attendance_signoffs(work_date);
+
+CREATE TABLE attendance_disputes (
+    id INTEGER PRIMARY KEY,
+    attendance_id INT NOT NULL REFERENCES attendances(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    comment TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'open',
+    resolution_note TEXT NULL,
+    resolved_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CHECK (status IN ('open', 'resolved', 'rejected'))
+);
+
+CREATE UNIQUE INDEX uq_attendance_disputes_open ON attendance_disputes(attendance_id) WHERE status = 'open';
+CREATE INDEX idx_attendance_disputes_status ON attendance_disputes(status, created_at);
+CREATE INDEX idx_attendance_disputes_user_id ON attendance_disputes(user_id);
+
+CREATE TABLE device_tokens (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform VARCHAR(10) NOT NULL,
+    token TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CHECK (platform IN ('fcm', 'apns')),
+    CONSTRAINT uq_device_tokens_token UNIQUE (token)
+);
+
+CREATE INDEX idx_device_tokens_user_id ON device_tokens(user_id);
+
+CREATE TABLE push_reminders (
+    schedule_id INT PRIMARY KEY REFERENCES user_schedules(id) ON DELETE CASCADE,
+    sent_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now'))
+);
+
+CREATE TABLE notifications (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    data TEXT NOT NULL DEFAULT '{}',
+    read_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now'))
+);
+
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+CREATE TABLE outbox_messages (
+    id INTEGER PRIMARY KEY,
+    destination VARCHAR(50) NOT NULL,
+    event_name VARCHAR(100) NOT NULL,
+    user_id INT NULL, -- Subjek event; dipakai sebagai key partisi agar urutan per user terjaga
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    available_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    delivered_at TIMESTAMP NULL,
+    dead_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_outbox_messages_due ON outbox_messages(available_at, id) WHERE delivered_at IS NULL AND dead_at IS NULL;
+CREATE INDEX idx_outbox_messages_dead ON outbox_messages(dead_at DESC) WHERE dead_at IS NOT NULL;
+
+CREATE TABLE approval_delegations (
+    id INTEGER PRIMARY KEY,
+    delegator_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    delegate_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    reason TEXT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    revoked_at TIMESTAMP NULL,
+    CHECK (delegator_id <> delegate_id),
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_approval_delegations_delegator ON approval_delegations(delegator_id);
+CREATE INDEX idx_approval_delegations_delegate_active ON approval_delegations(delegate_id, start_date, end_date) WHERE revoked_at IS NULL;
+
+CREATE TABLE approval_escalations (
+    id INTEGER PRIMARY KEY,
+    item_type VARCHAR(50) NOT NULL,
+    item_id INT NOT NULL,
+    level INT NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    escalated_to INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CHECK (action IN ('escalated', 'reminded')),
+    CONSTRAINT uq_approval_escalations_item_level UNIQUE (item_type, item_id, level)
+);
+
+CREATE TABLE scheduled_jobs (
+    name VARCHAR(100) PRIMARY KEY,
+    interval_seconds INT NOT NULL,
+    next_run_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    last_started_at TIMESTAMP NULL,
+    last_finished_at TIMESTAMP NULL,
+    last_status VARCHAR(20) NULL,
+    last_trigger VARCHAR(20) NULL,
+    last_result INT NULL,
+    last_error TEXT NULL,
+    last_duration_ms BIGINT NULL,
+    run_count INT NOT NULL DEFAULT 0,
+    failure_count INT NOT NULL DEFAULT 0,
+    CHECK (interval_seconds > 0),
+    CHECK (last_status IN ('running', 'succeeded', 'failed')),
+    CHECK (last_trigger IN ('schedule', 'manual'))
+);
+
+CREATE TABLE employment_verification_links (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipient VARCHAR(200) NOT NULL, -- Pihak ketiga penerima tautan
+    include_attendance BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP NOT NULL,
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    revoked_at TIMESTAMP NULL,
+    access_count INT NOT NULL DEFAULT 0, -- Akses yang dilayani (outcome granted)
+    last_accessed_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_employment_verification_links_user ON employment_verification_links (user_id, created_at DESC);
+
+CREATE TABLE employment_verification_accesses (
+    id INTEGER PRIMARY KEY,
+    link_id INT NOT NULL REFERENCES employment_verification_links(id) ON DELETE CASCADE,
+    accessed_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    ip VARCHAR(64) NOT NULL,
+    user_agent VARCHAR(255) NULL,
+    outcome VARCHAR(20) NOT NULL,
+    CHECK (outcome IN ('granted', 'expired', 'revoked'))
+);
+
+CREATE INDEX idx_employment_verification_accesses_link ON employment_verification_accesses (link_id, accessed_at DESC);
+
+CREATE TABLE kiosk_devices (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    fingerprint_hash CHAR(64) NOT NULL, -- SHA-256 (hex) fingerprint perangkat; fingerprint asli tidak disimpan
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    last_seen_at TIMESTAMP NULL,        -- Diperbarui saat token dirotasi
+    revoked_at TIMESTAMP NULL,
+    revoked_by INT NULL REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX uq_kiosk_devices_active_fingerprint ON kiosk_devices (user_id, fingerprint_hash) WHERE revoked_at IS NULL;
+CREATE INDEX idx_kiosk_devices_user ON kiosk_devices (user_id, created_at DESC);
+
+CREATE TABLE report_exports (
+    id INTEGER PRIMARY KEY,
+    report_type VARCHAR(50) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    params TEXT NOT NULL DEFAULT '{}', -- Filter laporan
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    retention_seconds INT NOT NULL,    -- Umur file sejak selesai dibuat
+    storage_key VARCHAR(255) NULL,
+    file_name VARCHAR(255) NULL,
+    content_type VARCHAR(100) NULL,
+    size_bytes BIGINT NULL,
+    row_count INT NULL,
+    error TEXT NULL,                   -- Alasan status failed
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    completed_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL,         -- completed_at + retensi
+    purged_at TIMESTAMP NULL,
+    CHECK (format IN ('csv', 'xlsx')),
+    CHECK (status IN ('pending', 'completed', 'failed', 'purged')),
+    CHECK (retention_seconds > 0)
+);
+
+CREATE INDEX idx_report_exports_created_at ON report_exports (created_at DESC);
+CREATE INDEX idx_report_exports_pending ON report_exports (created_at) WHERE status = 'pending';
+CREATE INDEX idx_report_exports_expires_at ON report_exports (expires_at) WHERE status = 'completed';
+
+CREATE TABLE report_snapshots (
+    id INTEGER PRIMARY KEY,
+    label VARCHAR(100),
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    payroll_period_id INT REFERENCES payroll_periods(id) ON DELETE SET NULL,
+    rows TEXT NOT NULL,
+    row_count INT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_report_snapshots_created_at ON report_snapshots(created_at DESC);
+
+CREATE TABLE email_change_requests (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_email TEXT NOT NULL,
+    new_email_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')), -- Pengiriman tautan terakhir (jeda kirim ulang)
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    confirmed_at TIMESTAMP NULL
+);
+
+CREATE UNIQUE INDEX idx_email_change_requests_open ON email_change_requests(user_id) WHERE confirmed_at IS NULL;
+
+CREATE TABLE previous_usernames (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    username VARCHAR(100) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now'))
+);
+
+CREATE INDEX idx_previous_usernames_user ON previous_usernames(user_id, changed_at DESC);
+CREATE INDEX idx_previous_usernames_username ON previous_usernames(username);
+
+CREATE TABLE username_change_requests (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_username VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP NULL,
+    review_note TEXT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+CREATE UNIQUE INDEX idx_username_change_requests_pending ON username_change_requests(user_id) WHERE status = 'pending';
+CREATE INDEX idx_username_change_requests_status ON username_change_requests(status, created_at);
+
+CREATE TABLE phone_otps (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(20) NOT NULL, -- verify_phone | login
+    code_hash VARCHAR(64) NOT NULL,
+    phone_hash VARCHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    consumed_at TIMESTAMP NULL
+);
+
+CREATE UNIQUE INDEX idx_phone_otps_open ON phone_otps(user_id, purpose) WHERE consumed_at IS NULL;
+
+CREATE TABLE user_invitations (
+    id INTEGER PRIMARY KEY,
+    email TEXT NOT NULL,
+    email_hash VARCHAR(64) NOT NULL,
+    role_id INT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    invited_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')), -- Pengiriman tautan terakhir (jeda kirim ulang)
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    accepted_at TIMESTAMP NULL,
+    accepted_user_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP NULL
+);
+
+CREATE UNIQUE INDEX idx_user_invitations_open ON user_invitations(email_hash) WHERE accepted_at IS NULL AND revoked_at IS NULL;
+CREATE INDEX idx_user_invitations_created_at ON user_invitations(created_at);
+
+CREATE TABLE sync_tombstones (
+    entity VARCHAR(20) NOT NULL CHECK (entity IN ('users', 'attendances', 'user_schedules')),
+    entity_id INT NOT NULL,
+    deleted_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    PRIMARY KEY (entity, entity_id)
+);
+
+CREATE INDEX idx_sync_tombstones_deleted_at ON sync_tombstones(entity, deleted_at, entity_id);
+
+CREATE TABLE debug_captures (
+    id INTEGER PRIMARY KEY,
+    request_id VARCHAR(100),
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path TEXT NOT NULL,
+    query TEXT,
+    status INT NOT NULL,
+    latency_ms INT NOT NULL,
+    error TEXT,
+    ip VARCHAR(64),
+    request_headers TEXT NOT NULL DEFAULT '{}',
+    request_body TEXT,
+    response_headers TEXT NOT NULL DEFAULT '{}',
+    response_body TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_debug_captures_created_at ON debug_captures(created_at DESC);
+CREATE INDEX idx_debug_captures_user_id ON debug_captures(user_id, created_at DESC);
+CREATE INDEX idx_debug_captures_expires_at ON debug_captures(expires_at);
+
+CREATE TABLE backups (
+    id INTEGER PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    schema_version BIGINT NULL,     -- Versi migrasi saat snapshot diambil
+    storage_key VARCHAR(255) NULL,
+    file_name VARCHAR(255) NULL,
+    size_bytes BIGINT NULL,
+    table_count INT NULL,
+    row_count BIGINT NULL,
+    error TEXT NULL,                -- Alasan status failed
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    started_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+
+CREATE INDEX idx_backups_created_at ON backups (created_at DESC);
+-- Paling banyak satu backup pending/running (PostgreSQL: unique index pada (true)).
+CREATE UNIQUE INDEX idx_backups_active ON backups ((status IN ('pending', 'running'))) WHERE status IN ('pending', 'running');
+
+CREATE TABLE app_instances (
+    instance_id VARCHAR(64) PRIMARY KEY,   -- Acak per proses
+    app_version VARCHAR(100) NOT NULL,     -- APP_VERSION atau versi build
+    hostname VARCHAR(255) NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now')),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000', 'now'))
+);
+
+CREATE INDEX idx_app_instances_last_seen_at ON app_instances (last_seen_at);
+
+-- --- Trigger ---
+
+-- users: version & updated_at (optimistic locking, sinkronisasi), token_version saat hak akses
+-- berubah (role, nonaktif, anonimisasi), dan reset verifikasi nomor HP saat nomor berganti.
+-- Nilai yang di-set eksplisit oleh statement UPDATE dipertahankan.
+CREATE TRIGGER users_after_update
+AFTER UPDATE ON users
+FOR EACH ROW
+BEGIN
+    UPDATE users SET
+        version = CASE WHEN NEW.version = OLD.version THEN OLD.version + 1 ELSE NEW.version END,
+        updated_at = CASE WHEN NEW.updated_at = OLD.updated_at THEN strftime('%Y-%m-%d %H:%M:%f000000', 'now') ELSE NEW.updated_at END,
+        token_version = CASE
+            WHEN NEW.token_version = OLD.token_version AND (
+                NEW.role_id IS NOT OLD.role_id
+                OR (OLD.is_active AND NOT NEW.is_active)
+                OR (OLD.anonymized_at IS NULL AND NEW.anonymized_at IS NOT NULL)
+            ) THEN OLD.token_version + 1
+            ELSE NEW.token_version END,
+        phone_verified_at = CASE WHEN NEW.phone_hash IS NOT OLD.phone_hash THEN NULL ELSE NEW.phone_verified_at END,
+        sms_two_factor = CASE WHEN NEW.phone_hash IS NOT OLD.phone_hash THEN FALSE ELSE NEW.sms_two_factor END,
+        sms_reminders = CASE WHEN NEW.phone_hash IS NOT OLD.phone_hash THEN FALSE ELSE NEW.sms_reminders END
+    WHERE id = NEW.id;
+END;
+
+-- Riwayat username: simpan username lama dan catat audit log (bukan untuk anonimisasi).
+CREATE TRIGGER record_username_change_users
+AFTER UPDATE OF username ON users
+FOR EACH ROW
+WHEN NEW.username IS NOT OLD.username AND NEW.anonymized_at IS NULL
+BEGIN
+    INSERT INTO previous_usernames (user_id, username) VALUES (OLD.id, OLD.username);
+    INSERT INTO audit_log (user_id, action, details)
+    VALUES (NEW.id, 'profile.username_changed', json_object('previous_username_id', last_insert_rowid()));
+END;
+
+-- Rename role mengubah klaim token: sesi user dengan role tersebut dicabut.
+CREATE TRIGGER bump_token_versions_role_rename
+AFTER UPDATE OF name ON roles
+FOR EACH ROW
+WHEN NEW.name IS NOT OLD.name
+BEGIN
+    UPDATE users SET token_version = token_version + 1 WHERE role_id = NEW.id;
+END;
+
+CREATE TRIGGER shifts_after_update
+AFTER UPDATE ON shifts
+FOR EACH ROW
+BEGIN
+    UPDATE shifts SET
+        version = CASE WHEN NEW.version = OLD.version THEN OLD.version + 1 ELSE NEW.version END,
+        updated_at = CASE WHEN NEW.updated_at = OLD.updated_at THEN strftime('%Y-%m-%d %H:%M:%f000000', 'now') ELSE NEW.updated_at END
+    WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER user_schedules_after_update
+AFTER UPDATE ON user_schedules
+FOR EACH ROW
+BEGIN
+    UPDATE user_schedules SET
+        version = CASE WHEN NEW.version = OLD.version THEN OLD.version + 1 ELSE NEW.version END,
+        updated_at = CASE WHEN NEW.updated_at = OLD.updated_at THEN strftime('%Y-%m-%d %H:%M:%f000000', 'now') ELSE NEW.updated_at END
+    WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER set_timestamp_attendances
+AFTER UPDATE ON attendances
+FOR EACH ROW
+WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE attendances SET updated_at = strftime('%Y-%m-%d %H:%M:%f000000', 'now') WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER set_timestamp_payroll_periods
+AFTER UPDATE ON payroll_periods
+FOR EACH ROW
+WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE payroll_periods SET updated_at = strftime('%Y-%m-%d %H:%M:%f000000', 'now') WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER set_timestamp_projects
+AFTER UPDATE ON projects
+FOR EACH ROW
+WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE projects SET updated_at = strftime('%Y-%m-%d %H:%M:%f000000', 'now') WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER set_timestamp_attendance_disputes
+AFTER UPDATE ON attendance_disputes
+FOR EACH ROW
+WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE attendance_disputes SET updated_at = strftime('%Y-%m-%d %H:%M:%f000000', 'now') WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER set_timestamp_device_tokens
+AFTER UPDATE ON device_tokens
+FOR EACH ROW
+WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE device_tokens SET updated_at = strftime('%Y-%m-%d %H:%M:%f000000', 'now') WHERE id = NEW.id;
+END;
+
+-- Periode payroll tidak boleh tumpang tindih (PostgreSQL: EXCLUDE USING gist).
+CREATE TRIGGER excl_payroll_periods_overlap_insert
+BEFORE INSERT ON payroll_periods
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM payroll_periods WHERE start_date <= NEW.end_date AND end_date >= NEW.start_date)
+BEGIN
+    SELECT RAISE(ABORT, 'excl_payroll_periods_overlap');
+END;
+
+CREATE TRIGGER excl_payroll_periods_overlap_update
+BEFORE UPDATE OF start_date, end_date ON payroll_periods
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM payroll_periods WHERE id <> NEW.id AND start_date <= NEW.end_date AND end_date >= NEW.start_date)
+BEGIN
+    SELECT RAISE(ABORT, 'excl_payroll_periods_overlap');
+END;
+
+-- attendance_events append-only: hanya pengosongan notes/actor (anonimisasi, hapus user) yang
+-- boleh diubah, dan baris hanya terhapus bersama absensinya.
+CREATE TRIGGER attendance_events_append_only_update
+BEFORE UPDATE ON attendance_events
+FOR EACH ROW
+WHEN NOT (
+    NEW.id IS OLD.id AND NEW.attendance_id IS OLD.attendance_id AND NEW.user_id IS OLD.user_id
+    AND NEW.event_type IS OLD.event_type AND NEW.check_in_at IS OLD.check_in_at
+    AND NEW.check_out_at IS OLD.check_out_at AND NEW.reason IS OLD.reason AND NEW.created_at IS OLD.created_at
+    AND (NEW.notes IS NULL OR NEW.notes IS OLD.notes)
+    AND (NEW.actor_user_id IS NULL OR NEW.actor_user_id IS OLD.actor_user_id)
+)
+BEGIN
+    SELECT RAISE(ABORT, 'attendance_events is append-only (UPDATE not allowed)');
+END;
+
+CREATE TRIGGER attendance_events_append_only_delete
+BEFORE DELETE ON attendance_events
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM attendances WHERE id = OLD.attendance_id)
+BEGIN
+    SELECT RAISE(ABORT, 'attendance_events is append-only (DELETE not allowed)');
+END;
+
+-- report_snapshots tidak bisa diubah; hanya FK yang boleh menjadi NULL (ON DELETE SET NULL).
+CREATE TRIGGER report_snapshots_immutable
+BEFORE UPDATE ON report_snapshots
+FOR EACH ROW
+WHEN NOT (
+    NEW.id IS OLD.id AND NEW.label IS OLD.label AND NEW.start_date IS OLD.start_date
+    AND NEW.end_date IS OLD.end_date AND NEW.rows IS OLD.rows AND NEW.row_count IS OLD.row_count
+    AND NEW.checksum IS OLD.checksum AND NEW.created_at IS OLD.created_at
+    AND (NEW.payroll_period_id IS NULL OR NEW.payroll_period_id IS OLD.payroll_period_id)
+    AND (NEW.created_by IS NULL OR NEW.created_by IS OLD.created_by)
+)
+BEGIN
+    SELECT RAISE(ABORT, 'report_snapshots are immutable');
+END;
+
+-- Audit perubahan jadwal (schedule.created/updated/deleted). Penghapusan akibat user dihapus
+-- tidak dicatat karena audit log user ikut terhapus.
+CREATE TRIGGER audit_user_schedules_insert
+AFTER INSERT ON user_schedules
+FOR EACH ROW
+BEGIN
+    INSERT INTO audit_log (user_id, action, details)
+    VALUES (NEW.user_id, 'schedule.created',
+            json_object('schedule_id', NEW.id, 'shift_id', NEW.shift_id, 'date', NEW.date));
+END;
+
+CREATE TRIGGER audit_user_schedules_delete
+AFTER DELETE ON user_schedules
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM users WHERE id = OLD.user_id)
+BEGIN
+    INSERT INTO audit_log (user_id, action, details)
+    VALUES (OLD.user_id, 'schedule.deleted',
+            json_object('schedule_id', OLD.id, 'shift_id', OLD.shift_id, 'date', OLD.date));
+END;
+
+CREATE TRIGGER audit_user_schedules_update
+AFTER UPDATE OF shift_id, date ON user_schedules
+FOR EACH ROW
+WHEN NEW.user_id = OLD.user_id AND (NEW.shift_id IS NOT OLD.shift_id OR NEW.date IS NOT OLD.date)
+BEGIN
+    INSERT INTO audit_log (user_id, action, details)
+    VALUES (NEW.user_id, 'schedule.updated',
+            json_object('schedule_id', NEW.id, 'shift_id', NEW.shift_id, 'date', NEW.date,
+                        'previous_shift_id', OLD.shift_id, 'previous_date', OLD.date));
+END;
+
+CREATE TRIGGER audit_user_schedules_reassign
+AFTER UPDATE OF user_id ON user_schedules
+FOR EACH ROW
+WHEN NEW.user_id <> OLD.user_id
+BEGIN
+    INSERT INTO audit_log (user_id, action, details)
+    SELECT OLD.user_id, 'schedule.deleted',
+           json_object('schedule_id', OLD.id, 'shift_id', OLD.shift_id, 'date', OLD.date)
+    WHERE EXISTS (SELECT 1 FROM users WHERE id = OLD.user_id);
+    INSERT INTO audit_log (user_id, action, details)
+    VALUES (NEW.user_id, 'schedule.created',
+            json_object('schedule_id', NEW.id, 'shift_id', NEW.shift_id, 'date', NEW.date));
+END;
+
+-- Tombstone untuk sinkronisasi klien offline (GET /sync/changes).
+CREATE TRIGGER sync_tombstone_users
+AFTER DELETE ON users
+FOR EACH ROW
+BEGIN
+    INSERT OR REPLACE INTO sync_tombstones (entity, entity_id) VALUES ('users', OLD.id);
+END;
+
+CREATE TRIGGER sync_tombstone_attendances
+AFTER DELETE ON attendances
+FOR EACH ROW
+BEGIN
+    INSERT OR REPLACE INTO sync_tombstones (entity, entity_id) VALUES ('attendances', OLD.id);
+END;
+
+CREATE TRIGGER sync_tombstone_user_schedules
+AFTER DELETE ON user_schedules
+FOR EACH ROW
+BEGIN
+    INSERT OR REPLACE INTO sync_tombstones (entity, entity_id) VALUES ('user_schedules', OLD.id);
+END;
+
+-- Data awal
+INSERT INTO roles (name) VALUES ('Admin'), ('Employee');
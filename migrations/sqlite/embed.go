@@ -0,0 +1,13 @@
+// migrations/sqlite/embed.go
+
+// Package sqlite menyematkan migrasi skema SQLite (DB_DRIVER=sqlite) ke binary. Migrasi
+// dijalankan otomatis saat aplikasi start (lihat database.NewSQLiteDB); versinya dicatat di
+// schema_migrations dengan format yang sama seperti migrasi PostgreSQL.
+package sqlite
+
+import "embed"
+
+// FS berisi file *.up.sql dan *.down.sql di direktori ini.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,110 @@
+// tests/e2e/bench.go
+package e2e
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/configs"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+)
+
+// Benchmark adalah benchmark satu endpoint "hot" di atas Env yang sudah di-seed.
+// Dipakai untuk menangkap regresi performa akibat perubahan query (bandingkan dengan benchstat).
+type Benchmark struct {
+	Name string
+	Run  func(b *testing.B, env *Env, seed *BenchSeed)
+	// MaxPerOp adalah batas rata-rata waktu per iterasi; benchmark gagal jika melewatinya.
+	// Nilainya baseline yang sama dengan threshold p95 k6 di loadtest/hot_endpoints.js.
+	MaxPerOp time.Duration
+}
+
+// Benchmarks adalah daftar endpoint yang paling sering dipanggil di production.
+var Benchmarks = []Benchmark{
+	{Name: "CheckInCheckOut", Run: benchCheckInCheckOut, MaxPerOp: 400 * time.Millisecond}, // Dua request (2 x 200ms)
+	{Name: "MyAttendance", Run: benchMyAttendance, MaxPerOp: 150 * time.Millisecond},
+	{Name: "AttendanceReport", Run: benchAttendanceReport, MaxPerOp: 400 * time.Millisecond},
+}
+
+// benchHistoryDays adalah jumlah hari riwayat absensi per employee yang di-seed.
+const benchHistoryDays = 30
+
+// BenchSeed adalah data awal benchmark: satu admin dan satu employee terjadwal hari ini
+// dengan riwayat absensi benchHistoryDays hari ke belakang.
+type BenchSeed struct {
+	Admin    Account
+	Employee Account
+}
+
+// RunBenchmarks menjalankan semua Benchmarks sebagai sub-benchmark dari BenchmarkHotEndpoints
+// (bench_test.go):
+//
+//	func BenchmarkHotEndpoints(b *testing.B) { e2e.RunBenchmarks(b) }
+//
+// Sub-benchmark yang rata-rata per iterasinya melewati MaxPerOp gagal, sehingga `make bench`
+// keluar dengan exit code != 0. BENCH_THRESHOLD_FACTOR (default 1) mengalikan semua batas,
+// mis. untuk runner CI yang lebih lambat.
+// Rate limit grup user/admin dinaikkan agar benchmark mengukur handler, bukan limiter.
+func RunBenchmarks(b *testing.B) {
+	factor := configs.GetEnvFloat("BENCH_THRESHOLD_FACTOR", 1)
+	if factor <= 0 {
+		b.Fatalf("e2e: BENCH_THRESHOLD_FACTOR must be greater than 0")
+	}
+	for _, name := range []string{"USER", "ADMIN"} {
+		b.Setenv("RATE_LIMIT_"+name+"_READ_MAX", "1000000000")
+		b.Setenv("RATE_LIMIT_"+name+"_WRITE_MAX", "1000000000")
+	}
+	for _, bm := range Benchmarks {
+		b.Run(bm.Name, func(b *testing.B) {
+			env := NewEnv(b)
+			seed := seedBench(b, env)
+			b.ReportAllocs()
+			b.ResetTimer()
+			bm.Run(b, env, seed)
+			// Setelah b.Loop selesai, timer berhenti dan b.N berisi jumlah iterasi.
+			if bm.MaxPerOp > 0 && b.N > 0 {
+				limit := time.Duration(float64(bm.MaxPerOp) * factor)
+				if perOp := b.Elapsed() / time.Duration(b.N); perOp > limit {
+					b.Errorf("e2e: %s took %s/op, above the %s threshold", bm.Name, perOp, limit)
+				}
+			}
+		})
+	}
+}
+
+func seedBench(b *testing.B, env *Env) *BenchSeed {
+	b.Helper()
+	seed := &BenchSeed{Admin: env.SignUp(b, fixtures.AsAdmin), Employee: env.SignUp(b)}
+	shift := env.DB.CreateShift(b)
+	now := time.Now()
+	for day := -benchHistoryDays; day < 0; day++ {
+		checkIn := fixtures.Day(now, day).Add(9 * time.Hour)
+		attendanceID := env.DB.CheckIn(b, seed.Employee.ID, checkIn)
+		if err := env.DB.Attendances.UpdateCheckOut(b.Context(), attendanceID, checkIn.Add(8*time.Hour), nil); err != nil {
+			b.Fatalf("e2e: seed check-out: %v", err)
+		}
+	}
+	env.DB.CreateSchedule(b, seed.Employee.ID, shift.ID, now)
+	return seed
+}
+
+func benchCheckInCheckOut(b *testing.B, env *Env, seed *BenchSeed) {
+	for b.Loop() {
+		Expect(b, env.Do(b, http.MethodPost, Path("/user/attendance/checkin"), seed.Employee.Token, models.CheckInInput{}), http.StatusOK)
+		Expect(b, env.Do(b, http.MethodPost, Path("/user/attendance/checkout"), seed.Employee.Token, models.CheckOutInput{}), http.StatusOK)
+	}
+}
+
+func benchMyAttendance(b *testing.B, env *Env, seed *BenchSeed) {
+	for b.Loop() {
+		Expect(b, env.Do(b, http.MethodGet, Path("/user/attendance/my"), seed.Employee.Token, nil), http.StatusOK)
+	}
+}
+
+func benchAttendanceReport(b *testing.B, env *Env, seed *BenchSeed) {
+	for b.Loop() {
+		Expect(b, env.Do(b, http.MethodGet, Path("/admin/attendance/report?limit=%d", 50), seed.Admin.Token, nil), http.StatusOK)
+	}
+}
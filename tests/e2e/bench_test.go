@@ -0,0 +1,10 @@
+package e2e_test
+
+import (
+	"testing"
+
+	"github.com/rakaarfi/attendance-system-be/tests/e2e"
+)
+
+// BenchmarkHotEndpoints menjalankan benchmark endpoint hot beserta threshold-nya (make bench).
+func BenchmarkHotEndpoints(b *testing.B) { e2e.RunBenchmarks(b) }
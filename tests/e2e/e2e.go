@@ -0,0 +1,240 @@
+// tests/e2e/e2e.go
+
+// Package e2e berisi skenario end-to-end API: aplikasi Fiber lengkap (middleware global +
+// rute v1) dijalankan in-process di atas database test terisolasi dari pgtest, lalu
+// skenario memanggil endpoint lewat app.Test seperti klien sungguhan.
+//
+// Skenario didaftarkan di Scenarios dan dijalankan dengan Run(t) dari TestE2E (e2e_test.go):
+//
+//	func TestE2E(t *testing.T) { e2e.Run(t) }
+//
+// Butuh JWT_SECRET dan database test (TEST_DATABASE_URL atau container, lihat
+// internal/testutil/pgtest); jika tidak tersedia, skenario di-skip.
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rakaarfi/attendance-system-be/configs"
+	v1 "github.com/rakaarfi/attendance-system-be/internal/api/v1"
+	"github.com/rakaarfi/attendance-system-be/internal/api/v1/handlers"
+	"github.com/rakaarfi/attendance-system-be/internal/backup"
+	"github.com/rakaarfi/attendance-system-be/internal/debugcapture"
+	"github.com/rakaarfi/attendance-system-be/internal/diagnostics"
+	"github.com/rakaarfi/attendance-system-be/internal/events"
+	"github.com/rakaarfi/attendance-system-be/internal/events/subscribers"
+	"github.com/rakaarfi/attendance-system-be/internal/inbox"
+	"github.com/rakaarfi/attendance-system-be/internal/jobs"
+	"github.com/rakaarfi/attendance-system-be/internal/lock"
+	"github.com/rakaarfi/attendance-system-be/internal/maintenance"
+	appmiddleware "github.com/rakaarfi/attendance-system-be/internal/middleware"
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/outbox"
+	"github.com/rakaarfi/attendance-system-be/internal/rbac"
+	"github.com/rakaarfi/attendance-system-be/internal/reports"
+	"github.com/rakaarfi/attendance-system-be/internal/session"
+	"github.com/rakaarfi/attendance-system-be/internal/settings"
+	"github.com/rakaarfi/attendance-system-be/internal/storage"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/pgtest"
+)
+
+// requestTimeoutMs adalah batas waktu app.Test per request (termasuk query DB).
+const requestTimeoutMs = 10_000
+
+// Env adalah satu instance aplikasi beserta database test-nya.
+type Env struct {
+	App    *fiber.App
+	DB     *pgtest.DB
+	Outbox *outbox.Dispatcher // Tidak berjalan di background; skenario memanggil Outbox.RunOnce
+}
+
+// NewEnv membangun aplikasi Fiber dengan wiring yang sama seperti cmd/api (tanpa CAPTCHA & Swagger).
+func NewEnv(t testing.TB) *Env {
+	t.Helper()
+	if os.Getenv("JWT_SECRET") == "" {
+		t.Skip("JWT_SECRET not set; skipping end-to-end scenarios")
+	}
+	db := pgtest.New(t)
+	// Skenario mendaftarkan admin lewat /auth/register, jadi role Admin dibuka untuk registrasi.
+	if _, err := db.Pool.Exec(context.Background(), `INSERT INTO settings (key, value) VALUES ($1, 'admin')`, settings.KeyRegistrationRoles); err != nil {
+		t.Fatalf("e2e: registration roles: %v", err)
+	}
+
+	settingsStore := settings.NewStore(db.Settings)
+	maintenanceMode := maintenance.NewControllerFromEnv(settingsStore)
+	// Tanpa cache versi sesi agar pencabutan langsung terlihat; peringatan login tidak dikirim.
+	sessionVersions := session.NewVersionCache(db.Users, 0)
+	roleHierarchy := rbac.NewResolver(db.Roles, 0)
+	// Notifikasi user hanya ke inbox in-app (tanpa push, email, maupun webhook).
+	userInbox := inbox.NewDispatcher(db.Notifications, nil)
+	eventBus := events.NewInProcessBus()
+	eventBus.Subscribe("audit", subscribers.Audit(db.Audit), events.Audited...)
+	outboxDispatcher := outbox.NewDispatcherFromEnv(db.Outbox)
+	outboxDispatcher.Register("notifications", outbox.EventHandler(subscribers.NewNotifications(db.Users, userInbox, nil).Handle), subscribers.NotificationEvents...)
+	eventBus.Subscribe("outbox", outboxDispatcher.Enqueue)
+	authHandler := handlers.NewAuthHandler(db.Users, db.Roles, settingsStore, eventBus, nil, sessionVersions, nil)
+	adminHandler := handlers.NewAdminHandler(db.Shifts, db.Schedules, db.Attendances, db.Users, db.Roles, roleHierarchy, settingsStore, db.Audit, eventBus, db.Tx, sessionVersions)
+	userHandler := handlers.NewUserHandler(db.Attendances, db.Schedules, db.Users, db.Shifts, eventBus, db.Tx, nil, maintenanceMode, nil, nil, nil, settingsStore)
+	announcementHandler := handlers.NewAnnouncementHandler(db.Announcements, db.Roles)
+	fileStorage, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("e2e: file storage: %v", err)
+	}
+	documentHandler := handlers.NewDocumentHandler(db.Documents, db.Attendances, fileStorage, nil)
+	orgHandler := handlers.NewOrgHandler(db.Users)
+	payrollHandler := handlers.NewPayrollHandler(db.Payroll, db.Users, eventBus)
+	projectHandler := handlers.NewProjectHandler(db.Projects)
+	signOffHandler := handlers.NewSignOffHandler(db.SignOffs, db.Delegations, settingsStore, eventBus)
+	delegationHandler := handlers.NewDelegationHandler(db.Delegations, eventBus)
+	disputeHandler := handlers.NewDisputeHandler(db.Disputes, eventBus, db.Tx)
+	approvalHandler := handlers.NewApprovalHandler(db.Disputes, db.Escalations, eventBus, db.Tx)
+	deviceHandler := handlers.NewDeviceHandler(db.Devices)
+	notificationHandler := handlers.NewNotificationHandler(db.Notifications)
+	outboxHandler := handlers.NewOutboxHandler(db.Outbox)
+	laborHandler := handlers.NewLaborHandler(db.Labor)
+	forecastHandler := handlers.NewForecastHandler(db.Schedules, settingsStore)
+	jobScheduler := jobs.NewSchedulerFromEnv(db.Jobs, lock.NewPostgres(db.Pool))
+	jobHandler := handlers.NewJobHandler(jobScheduler)
+	verificationHandler := handlers.NewVerificationHandler(db.Verifications, eventBus)
+	kioskHandler := handlers.NewKioskHandler(db.Kiosks, db.Users, settingsStore, eventBus)
+	photoHandler := handlers.NewPhotoHandler(db.Photos, nil)
+	reportHandler := handlers.NewReportHandler(db.Reports, db.Payroll, reports.NewService(db.Reports, fileStorage, 7*24*time.Hour), fileStorage, settingsStore)
+	emailChangeHandler := handlers.NewEmailChangeHandler(db.Users, nil, eventBus)
+	usernameChangeHandler := handlers.NewUsernameChangeHandler(db.Users, eventBus, db.Tx)
+	phoneHandler := handlers.NewPhoneHandler(db.Users, nil, eventBus)
+	invitationHandler := handlers.NewInvitationHandler(db.Users, nil, eventBus, db.Tx)
+	routeHandler := handlers.NewRouteHandler(db.Attendances, db.Users, settingsStore, eventBus)
+	syncHandler := handlers.NewSyncHandler(db.Users, db.Attendances, db.Schedules)
+	debugCaptureHandler := handlers.NewDebugCaptureHandler(db.DebugCaptures)
+	backupHandler := handlers.NewBackupHandler(db.Backups, backup.NewService(db.Pool, db.Backups, fileStorage, time.Hour), jobScheduler, fileStorage)
+	systemHandler := handlers.NewSystemHandler(diagnostics.NewServiceFromEnv(db.Pool, fileStorage, jobScheduler))
+	var requestCapturer appmiddleware.RequestCapturer
+	if recorder := debugcapture.NewRecorderFromEnv(db.DebugCaptures, settingsStore); recorder != nil {
+		requestCapturer = recorder
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	securityCfg, err := configs.LoadSecurityConfig()
+	if err != nil {
+		t.Fatalf("e2e: security config: %v", err)
+	}
+	appmiddleware.SetupGlobalMiddleware(app, securityCfg, nil)
+	v1.SetupRoutes(app, authHandler, adminHandler, userHandler, announcementHandler, documentHandler, orgHandler, payrollHandler, projectHandler, signOffHandler, delegationHandler, disputeHandler, approvalHandler, deviceHandler, notificationHandler, outboxHandler, laborHandler, forecastHandler, jobHandler, verificationHandler, kioskHandler, photoHandler, reportHandler, emailChangeHandler, usernameChangeHandler, phoneHandler, invitationHandler, routeHandler, syncHandler, debugCaptureHandler, backupHandler, handlers.NewMaintenanceHandler(maintenanceMode, db.AppInstances), systemHandler, nil, sessionVersions, roleHierarchy, db.Kiosks, requestCapturer, maintenanceMode, nil, nil)
+
+	return &Env{App: app, DB: db, Outbox: outboxDispatcher}
+}
+
+// Result adalah response HTTP yang sudah dibaca; Body berisi JSON mentah.
+type Result struct {
+	Status int
+	Body   []byte
+}
+
+// Decode mengurai Body ke v; test gagal jika body bukan JSON yang sesuai.
+func (r Result) Decode(t testing.TB, v any) {
+	t.Helper()
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("e2e: decode response (status %d): %v; body: %s", r.Status, err, r.Body)
+	}
+}
+
+// Response mengurai Body sebagai models.Response standar.
+func (r Result) Response(t testing.TB) models.Response {
+	t.Helper()
+	var resp models.Response
+	r.Decode(t, &resp)
+	return resp
+}
+
+// Do mengirim request ke aplikasi. body di-encode sebagai JSON jika tidak nil;
+// token (JWT) dikirim sebagai Bearer jika tidak kosong.
+func (e *Env) Do(t testing.TB, method, path, token string, body any) Result {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("e2e: encode request body: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+	resp, err := e.App.Test(req, requestTimeoutMs)
+	if err != nil {
+		t.Fatalf("e2e: %s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("e2e: read response of %s %s: %v", method, path, err)
+	}
+	return Result{Status: resp.StatusCode, Body: data}
+}
+
+// Expect memastikan status response sesuai; jika tidak, test gagal dengan body sebagai konteks.
+func Expect(t testing.TB, r Result, status int) Result {
+	t.Helper()
+	if r.Status != status {
+		t.Fatalf("e2e: expected status %d, got %d; body: %s", status, r.Status, r.Body)
+	}
+	return r
+}
+
+// DispatchOutbox mengirim semua pesan outbox yang jatuh tempo (notifikasi dll.) secara sinkron.
+func (e *Env) DispatchOutbox(t testing.TB) {
+	t.Helper()
+	if _, err := e.Outbox.RunOnce(context.Background()); err != nil {
+		t.Fatalf("e2e: dispatch outbox: %v", err)
+	}
+}
+
+// Account adalah user yang sudah terdaftar & login lewat API.
+type Account struct {
+	ID       int
+	Username string
+	Token    string
+}
+
+// SignUp mendaftarkan user lewat /auth/register lalu login untuk mendapatkan token.
+func (e *Env) SignUp(t testing.TB, opts ...func(*models.RegisterUserInput)) Account {
+	t.Helper()
+	input := fixtures.UserInput(opts...)
+	Expect(t, e.Do(t, http.MethodPost, "/api/v1/auth/register", "", input), http.StatusCreated)
+
+	login := Expect(t, e.Do(t, http.MethodPost, "/api/v1/auth/login", "", models.LoginUserInput{
+		Username: input.Username, Password: input.Password,
+	}), http.StatusOK)
+	var body struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	login.Decode(t, &body)
+
+	user := Expect(t, e.Do(t, http.MethodGet, "/api/v1/user/profile", body.Data.Token, nil), http.StatusOK)
+	var profile struct {
+		Data models.User `json:"data"`
+	}
+	user.Decode(t, &profile)
+	return Account{ID: profile.Data.ID, Username: input.Username, Token: body.Data.Token}
+}
+
+// Path menyusun path API v1 dengan format gaya fmt.Sprintf.
+func Path(format string, args ...any) string {
+	return "/api/v1" + fmt.Sprintf(format, args...)
+}
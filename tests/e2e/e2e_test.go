@@ -0,0 +1,10 @@
+package e2e_test
+
+import (
+	"testing"
+
+	"github.com/rakaarfi/attendance-system-be/tests/e2e"
+)
+
+// TestE2E menjalankan semua skenario end-to-end (di-skip tanpa TEST_DATABASE_URL & JWT_SECRET).
+func TestE2E(t *testing.T) { e2e.Run(t) }
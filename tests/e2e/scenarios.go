@@ -0,0 +1,757 @@
+// tests/e2e/scenarios.go
+package e2e
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rakaarfi/attendance-system-be/internal/models"
+	"github.com/rakaarfi/attendance-system-be/internal/testutil/fixtures"
+	"github.com/rakaarfi/attendance-system-be/internal/utils"
+)
+
+// Scenario adalah satu alur end-to-end yang berjalan di Env-nya sendiri.
+type Scenario struct {
+	Name string
+	Run  func(t *testing.T, env *Env)
+}
+
+// Scenarios adalah daftar alur yang rawan regresi. Tambahkan skenario baru di sini.
+var Scenarios = []Scenario{
+	{Name: "CheckInCheckOutAppearsInReport", Run: checkInCheckOutAppearsInReport},
+	{Name: "DoubleCheckInRejected", Run: doubleCheckInRejected},
+	{Name: "CheckInWithoutScheduleRejected", Run: checkInWithoutScheduleRejected},
+	{Name: "SchedulesWithAttendanceAdherence", Run: schedulesWithAttendanceAdherence},
+	{Name: "ScheduleConflictRejected", Run: scheduleConflictRejected},
+	{Name: "OvernightShiftOverlapRejected", Run: overnightShiftOverlapRejected},
+	{Name: "RoleAuthorization", Run: roleAuthorization},
+	{Name: "ExpiredContractorCannotLogin", Run: expiredContractorCannotLogin},
+	{Name: "ReportingLineAndTeam", Run: reportingLineAndTeam},
+	{Name: "UserActivityFeed", Run: userActivityFeed},
+	{Name: "DeniedLoginRevokesSessions", Run: deniedLoginRevokesSessions},
+	{Name: "ClosedPayrollPeriodLocksAttendance", Run: closedPayrollPeriodLocksAttendance},
+	{Name: "ProjectTaggedHours", Run: projectTaggedHours},
+	{Name: "ProjectSwitchSegments", Run: projectSwitchSegments},
+	{Name: "SupervisorSignOffLocksDay", Run: supervisorSignOffLocksDay},
+	{Name: "DelegatedSignOff", Run: delegatedSignOff},
+	{Name: "AttendanceDisputeResolution", Run: attendanceDisputeResolution},
+	{Name: "DeviceTokenRegistration", Run: deviceTokenRegistration},
+	{Name: "NotificationInbox", Run: notificationInbox},
+	{Name: "ApprovalInboxBulkDecision", Run: approvalInboxBulkDecision},
+}
+
+// Run menjalankan semua Scenarios sebagai subtest, masing-masing dengan database terisolasi.
+func Run(t *testing.T) {
+	for _, sc := range Scenarios {
+		t.Run(sc.Name, func(t *testing.T) {
+			sc.Run(t, NewEnv(t))
+		})
+	}
+}
+
+// today adalah tanggal hari ini (zona waktu lokal server, sama seperti handler check-in).
+func today() string {
+	return time.Now().Format(fixtures.DateLayout)
+}
+
+// scheduleToday membuat shift baru dan menjadwalkan employee hari ini lewat API admin.
+func scheduleToday(t *testing.T, env *Env, admin, employee Account) {
+	t.Helper()
+	shift := Expect(t, env.Do(t, http.MethodPost, Path("/admin/shifts"), admin.Token, fixtures.Shift()), http.StatusCreated)
+	var created struct {
+		Data struct {
+			ShiftID int `json:"shift_id"`
+		} `json:"data"`
+	}
+	shift.Decode(t, &created)
+	if created.Data.ShiftID == 0 {
+		t.Fatalf("e2e: shift id missing in response: %s", shift.Body)
+	}
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/schedules"), admin.Token, models.UserSchedule{
+		UserID: employee.ID, ShiftID: created.Data.ShiftID, Date: today(),
+	}), http.StatusCreated)
+}
+
+func checkInCheckOutAppearsInReport(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	scheduleToday(t, env, admin, employee)
+
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkout"), employee.Token, models.CheckOutInput{}), http.StatusOK)
+
+	report := Expect(t, env.Do(t, http.MethodGet, Path("/admin/attendance/report?start_date=%s&end_date=%s", today(), today()), admin.Token, nil), http.StatusOK)
+	var body struct {
+		Data []models.Attendance `json:"data"`
+	}
+	report.Decode(t, &body)
+	for _, att := range body.Data {
+		if att.UserID == employee.ID {
+			if att.CheckOutAt == nil {
+				t.Fatalf("attendance %d in report has no check-out", att.ID)
+			}
+			return
+		}
+	}
+	t.Fatalf("attendance for user %d not found in report: %s", employee.ID, report.Body)
+}
+
+func doubleCheckInRejected(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	scheduleToday(t, env, admin, employee)
+
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusConflict)
+}
+
+func checkInWithoutScheduleRejected(t *testing.T, env *Env) {
+	employee := env.SignUp(t)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusForbidden)
+}
+
+func schedulesWithAttendanceAdherence(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	scheduleToday(t, env, admin, employee)
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(fixtures.DateLayout)
+	shift := env.DB.CreateShift(t)
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/schedules"), admin.Token, models.UserSchedule{
+		UserID: employee.ID, ShiftID: shift.ID, Date: tomorrow,
+	}), http.StatusCreated)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusOK)
+
+	list := Expect(t, env.Do(t, http.MethodGet, Path("/admin/users/%d/schedules?start_date=%s&end_date=%s&include=attendance", employee.ID, today(), tomorrow), admin.Token, nil), http.StatusOK)
+	var body struct {
+		Data []models.UserSchedule `json:"data"`
+	}
+	list.Decode(t, &body)
+	if len(body.Data) != 2 {
+		t.Fatalf("expected 2 schedules, got: %s", list.Body)
+	}
+	if body.Data[0].AttendanceStatus != models.AttendanceStatusPresent || body.Data[0].Attendance == nil {
+		t.Fatalf("expected today's schedule to carry the check-in, got: %s", list.Body)
+	}
+	// Check-in menyimpan tautan ke jadwal yang berlaku saat itu.
+	if id := body.Data[0].Attendance.ScheduleID; id == nil || *id != body.Data[0].ID {
+		t.Fatalf("expected check-in to be linked to schedule %d, got: %s", body.Data[0].ID, list.Body)
+	}
+	if body.Data[1].AttendanceStatus != models.AttendanceStatusMissing || body.Data[1].Attendance != nil {
+		t.Fatalf("expected tomorrow's schedule to be missing attendance, got: %s", list.Body)
+	}
+	Expect(t, env.Do(t, http.MethodGet, Path("/admin/users/%d/schedules?include=shifts", employee.ID), admin.Token, nil), http.StatusBadRequest)
+}
+
+func scheduleConflictRejected(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	scheduleToday(t, env, admin, employee)
+
+	// Jadwal kedua untuk user & tanggal yang sama harus ditolak (409).
+	shift := env.DB.CreateShift(t)
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/schedules"), admin.Token, models.UserSchedule{
+		UserID: employee.ID, ShiftID: shift.ID, Date: today(),
+	}), http.StatusConflict)
+}
+
+func overnightShiftOverlapRejected(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	night := env.DB.CreateShift(t, func(s *models.Shift) { s.StartTime, s.EndTime = "22:00:00", "06:00:00" })
+	early := env.DB.CreateShift(t, func(s *models.Shift) { s.StartTime, s.EndTime = "05:00:00", "13:00:00" })
+	morning := env.DB.CreateShift(t, func(s *models.Shift) { s.StartTime, s.EndTime = "06:00:00", "14:00:00" })
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(fixtures.DateLayout)
+
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/schedules"), admin.Token, models.UserSchedule{
+		UserID: employee.ID, ShiftID: night.ID, Date: today(),
+	}), http.StatusCreated)
+
+	// Shift malam hari ini berakhir 06:00 besok: shift 05:00 besok bertabrakan (409 + daftar konflik).
+	conflict := Expect(t, env.Do(t, http.MethodPost, Path("/admin/schedules"), admin.Token, models.UserSchedule{
+		UserID: employee.ID, ShiftID: early.ID, Date: tomorrow,
+	}), http.StatusConflict)
+	var body struct {
+		Data struct {
+			Conflicts []models.ScheduleConflict `json:"conflicts"`
+		} `json:"data"`
+	}
+	conflict.Decode(t, &body)
+	if len(body.Data.Conflicts) != 1 || body.Data.Conflicts[0].ShiftID != night.ID || body.Data.Conflicts[0].Date != today() {
+		t.Fatalf("expected the overnight schedule as the only conflict, got: %s", conflict.Body)
+	}
+
+	// Shift yang dimulai tepat saat shift malam berakhir tidak bertabrakan.
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/schedules"), admin.Token, models.UserSchedule{
+		UserID: employee.ID, ShiftID: morning.ID, Date: tomorrow,
+	}), http.StatusCreated)
+}
+
+func roleAuthorization(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+
+	Expect(t, env.Do(t, http.MethodGet, Path("/admin/users"), "", nil), http.StatusUnauthorized)
+	Expect(t, env.Do(t, http.MethodGet, Path("/admin/users"), employee.Token, nil), http.StatusForbidden)
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/shifts"), employee.Token, fixtures.Shift()), http.StatusForbidden)
+	Expect(t, env.Do(t, http.MethodGet, Path("/admin/users"), admin.Token, nil), http.StatusOK)
+	// Admin juga boleh mengakses rute /user.
+	Expect(t, env.Do(t, http.MethodGet, Path("/user/profile"), admin.Token, nil), http.StatusOK)
+}
+
+func expiredContractorCannotLogin(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	contractor := env.SignUp(t)
+	login := models.LoginUserInput{Username: contractor.Username, Password: fixtures.DefaultPassword}
+
+	yesterday := time.Now().AddDate(0, 0, -1).Format(fixtures.DateLayout)
+	Expect(t, env.Do(t, http.MethodPut, Path("/admin/users/%d/access", contractor.ID), admin.Token, models.UpdateUserAccessInput{
+		UserType: models.UserTypeContractor, ValidUntil: &yesterday,
+	}), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodPost, "/api/v1/auth/login", "", login), http.StatusForbidden)
+
+	// Memperpanjang kontrak mengaktifkan kembali akses.
+	nextWeek := time.Now().AddDate(0, 0, 7).Format(fixtures.DateLayout)
+	Expect(t, env.Do(t, http.MethodPut, Path("/admin/users/%d/access", contractor.ID), admin.Token, models.UpdateUserAccessInput{
+		UserType: models.UserTypeContractor, ValidUntil: &nextWeek,
+	}), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodPost, "/api/v1/auth/login", "", login), http.StatusOK)
+}
+
+func reportingLineAndTeam(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	manager := env.SignUp(t)
+	report := env.SignUp(t)
+
+	Expect(t, env.Do(t, http.MethodPut, Path("/admin/users/%d/manager", report.ID), admin.Token, models.SetManagerInput{ManagerID: &manager.ID}), http.StatusOK)
+	// Manager tidak boleh menjadi bawahan dari bawahannya sendiri.
+	Expect(t, env.Do(t, http.MethodPut, Path("/admin/users/%d/manager", manager.ID), admin.Token, models.SetManagerInput{ManagerID: &report.ID}), http.StatusConflict)
+
+	team := Expect(t, env.Do(t, http.MethodGet, Path("/user/team"), manager.Token, nil), http.StatusOK)
+	var body struct {
+		Data models.OrgChartNode `json:"data"`
+	}
+	team.Decode(t, &body)
+	if body.Data.UserID != manager.ID || len(body.Data.Reports) != 1 || body.Data.Reports[0].UserID != report.ID {
+		t.Fatalf("unexpected team tree: %s", team.Body)
+	}
+	if body.Data.Reports[0].Presence != models.PresenceOff {
+		t.Fatalf("expected unscheduled report to be %q, got %q", models.PresenceOff, body.Data.Reports[0].Presence)
+	}
+}
+
+func userActivityFeed(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	scheduleToday(t, env, admin, employee)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusOK)
+
+	feed := Expect(t, env.Do(t, http.MethodGet, Path("/admin/users/%d/activity", employee.ID), admin.Token, nil), http.StatusOK)
+	var body struct {
+		Data struct {
+			Items []models.ActivityItem `json:"items"`
+		} `json:"data"`
+	}
+	feed.Decode(t, &body)
+	// Terbaru dulu: check-in, jadwal yang dibuat admin, lalu login saat sign-up.
+	want := []string{"attendance.check_in", models.AuditScheduleCreated, models.AuditLoginSucceeded}
+	if len(body.Data.Items) != len(want) {
+		t.Fatalf("expected %d activity items, got: %s", len(want), feed.Body)
+	}
+	for i, action := range want {
+		if body.Data.Items[i].Action != action {
+			t.Fatalf("activity item %d: expected %q, got %q", i, action, body.Data.Items[i].Action)
+		}
+	}
+}
+
+func deniedLoginRevokesSessions(t *testing.T, env *Env) {
+	employee := env.SignUp(t)
+	login := models.LoginUserInput{Username: employee.Username, Password: fixtures.DefaultPassword}
+
+	// Tautan "bukan saya" sama seperti yang dikirim di email peringatan login (token_version awal 0).
+	alertToken, err := utils.GeneratePurposeToken(utils.PurposeLoginAlert, employee.ID, 0, time.Hour)
+	if err != nil {
+		t.Fatalf("generate login alert token: %v", err)
+	}
+	// Token tujuan khusus tidak bisa dipakai sebagai token sesi.
+	Expect(t, env.Do(t, http.MethodGet, Path("/user/profile"), alertToken, nil), http.StatusUnauthorized)
+
+	denied := Expect(t, env.Do(t, http.MethodPost, "/api/v1/auth/login-alerts/deny", "", models.DenyLoginInput{Token: alertToken}), http.StatusOK)
+	var body struct {
+		Data struct {
+			ResetToken string `json:"reset_token"`
+		} `json:"data"`
+	}
+	denied.Decode(t, &body)
+
+	// Sesi lama dicabut, login ditolak sampai password di-reset, dan tautan tidak bisa dipakai ulang.
+	Expect(t, env.Do(t, http.MethodGet, Path("/user/profile"), employee.Token, nil), http.StatusUnauthorized)
+	Expect(t, env.Do(t, http.MethodPost, "/api/v1/auth/login", "", login), http.StatusForbidden)
+	Expect(t, env.Do(t, http.MethodPost, "/api/v1/auth/login-alerts/deny", "", models.DenyLoginInput{Token: alertToken}), http.StatusUnauthorized)
+
+	reset := models.ResetPasswordInput{Token: body.Data.ResetToken, NewPassword: "new-" + fixtures.DefaultPassword}
+	Expect(t, env.Do(t, http.MethodPost, "/api/v1/auth/password/reset", "", reset), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodPost, "/api/v1/auth/password/reset", "", reset), http.StatusUnauthorized)
+	Expect(t, env.Do(t, http.MethodPost, "/api/v1/auth/login", "", models.LoginUserInput{
+		Username: employee.Username, Password: reset.NewPassword,
+	}), http.StatusOK)
+}
+
+func closedPayrollPeriodLocksAttendance(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	scheduleToday(t, env, admin, employee)
+	checkIn := Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusOK)
+	var checkedIn struct {
+		Data struct {
+			AttendanceID int `json:"attendance_id"`
+		} `json:"data"`
+	}
+	checkIn.Decode(t, &checkedIn)
+
+	created := Expect(t, env.Do(t, http.MethodPost, Path("/admin/payroll/periods"), admin.Token, models.PayrollPeriodInput{
+		StartDate: today(), EndDate: today(),
+	}), http.StatusCreated)
+	var period struct {
+		Data models.PayrollPeriod `json:"data"`
+	}
+	created.Decode(t, &period)
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/payroll/periods/%d/close", period.Data.ID), admin.Token, nil), http.StatusOK)
+
+	// Check-out dan koreksi dalam periode yang ditutup ditolak 409 dengan kode error.
+	rejected := Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkout"), employee.Token, models.CheckOutInput{}), http.StatusConflict)
+	var body struct {
+		Data struct {
+			Code string `json:"code"`
+		} `json:"data"`
+	}
+	rejected.Decode(t, &body)
+	if body.Data.Code != models.PayrollPeriodClosedCode {
+		t.Fatalf("expected error code %s, got: %s", models.PayrollPeriodClosedCode, rejected.Body)
+	}
+	note := "late entry"
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/attendance/%d/corrections", checkedIn.Data.AttendanceID), admin.Token, models.AttendanceCorrectionInput{
+		Notes: &note, Reason: "fix after payroll",
+	}), http.StatusConflict)
+
+	// Reopen wajib konfirmasi password admin.
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/payroll/periods/%d/reopen", period.Data.ID), admin.Token, models.ReopenPayrollPeriodInput{
+		Reason: "missed overtime", Password: "wrong-password",
+	}), http.StatusForbidden)
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/payroll/periods/%d/reopen", period.Data.ID), admin.Token, models.ReopenPayrollPeriodInput{
+		Reason: "missed overtime", Password: fixtures.DefaultPassword,
+	}), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkout"), employee.Token, models.CheckOutInput{}), http.StatusOK)
+}
+
+func projectTaggedHours(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	scheduleToday(t, env, admin, employee)
+
+	inactive := false
+	archived := Expect(t, env.Do(t, http.MethodPost, Path("/admin/projects"), admin.Token, models.ProjectInput{
+		Code: "OLD", Name: "Archived project", IsActive: &inactive,
+	}), http.StatusCreated)
+	created := Expect(t, env.Do(t, http.MethodPost, Path("/admin/projects"), admin.Token, models.ProjectInput{
+		Code: "ACME", Name: "Acme rollout",
+	}), http.StatusCreated)
+	var old, project struct {
+		Data models.Project `json:"data"`
+	}
+	archived.Decode(t, &old)
+	created.Decode(t, &project)
+
+	// Project nonaktif tidak bisa dipilih saat check-in.
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{ProjectID: &old.Data.ID}), http.StatusBadRequest)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{ProjectID: &project.Data.ID}), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkout"), employee.Token, models.CheckOutInput{}), http.StatusOK)
+
+	report := Expect(t, env.Do(t, http.MethodGet, Path("/admin/attendance/report/projects?start_date=%s&end_date=%s&user_id=%d", today(), today(), employee.ID), admin.Token, nil), http.StatusOK)
+	var body struct {
+		Data []models.ProjectHours `json:"data"`
+	}
+	report.Decode(t, &body)
+	if len(body.Data) != 1 || body.Data[0].ProjectID == nil || *body.Data[0].ProjectID != project.Data.ID || body.Data[0].Sessions != 1 {
+		t.Fatalf("expected one session on project %d, got: %s", project.Data.ID, report.Body)
+	}
+	// Project yang sudah dipakai absensi hanya bisa dinonaktifkan.
+	Expect(t, env.Do(t, http.MethodDelete, Path("/admin/projects/%d", project.Data.ID), admin.Token, nil), http.StatusConflict)
+}
+
+func projectSwitchSegments(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	scheduleToday(t, env, admin, employee)
+
+	var first, second struct {
+		Data models.Project `json:"data"`
+	}
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/projects"), admin.Token, models.ProjectInput{
+		Code: "ALPHA", Name: "Alpha",
+	}), http.StatusCreated).Decode(t, &first)
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/projects"), admin.Token, models.ProjectInput{
+		Code: "BETA", Name: "Beta",
+	}), http.StatusCreated).Decode(t, &second)
+
+	// Switch tanpa sesi terbuka ditolak.
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/switch"), employee.Token, models.SwitchProjectInput{ProjectID: second.Data.ID}), http.StatusConflict)
+
+	checkIn := Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{ProjectID: &first.Data.ID}), http.StatusOK)
+	var session struct {
+		Data struct {
+			AttendanceID int `json:"attendance_id"`
+		} `json:"data"`
+	}
+	checkIn.Decode(t, &session)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/switch"), employee.Token, models.SwitchProjectInput{ProjectID: first.Data.ID}), http.StatusConflict)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/switch"), employee.Token, models.SwitchProjectInput{ProjectID: second.Data.ID}), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkout"), employee.Token, models.CheckOutInput{}), http.StatusOK)
+
+	segments := Expect(t, env.Do(t, http.MethodGet, Path("/admin/attendance/%d/segments", session.Data.AttendanceID), admin.Token, nil), http.StatusOK)
+	var list struct {
+		Data []models.AttendanceSegment `json:"data"`
+	}
+	segments.Decode(t, &list)
+	if len(list.Data) != 2 || list.Data[1].EndedAt == nil {
+		t.Fatalf("expected two closed segments, got: %s", segments.Body)
+	}
+
+	report := Expect(t, env.Do(t, http.MethodGet, Path("/admin/attendance/report/projects?start_date=%s&end_date=%s&user_id=%d", today(), today(), employee.ID), admin.Token, nil), http.StatusOK)
+	var body struct {
+		Data []models.ProjectHours `json:"data"`
+	}
+	report.Decode(t, &body)
+	if len(body.Data) != 2 {
+		t.Fatalf("expected hours on both projects, got: %s", report.Body)
+	}
+}
+
+func supervisorSignOffLocksDay(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	manager := env.SignUp(t)
+	employee := env.SignUp(t)
+	outsider := env.SignUp(t)
+	Expect(t, env.Do(t, http.MethodPut, Path("/admin/users/%d/manager", employee.ID), admin.Token, models.SetManagerInput{ManagerID: &manager.ID}), http.StatusOK)
+	scheduleToday(t, env, admin, employee)
+
+	// User tanpa bawahan tidak bisa sign-off.
+	Expect(t, env.Do(t, http.MethodPost, Path("/manager/attendance/sign-off"), outsider.Token, models.SignOffInput{Date: today()}), http.StatusForbidden)
+
+	checkIn := Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusOK)
+	var checkedIn struct {
+		Data struct {
+			AttendanceID int `json:"attendance_id"`
+		} `json:"data"`
+	}
+	checkIn.Decode(t, &checkedIn)
+
+	type signOffBody struct {
+		Data struct {
+			Results []models.SignOffResult `json:"results"`
+		} `json:"data"`
+	}
+	// Sesi yang masih terbuka dilewati; anggota di luar tim dilaporkan not_found.
+	var first signOffBody
+	Expect(t, env.Do(t, http.MethodPost, Path("/manager/attendance/sign-off"), manager.Token, models.SignOffInput{
+		Date: today(), UserIDs: []int{employee.ID, outsider.ID},
+	}), http.StatusOK).Decode(t, &first)
+	statuses := map[int]string{}
+	for _, r := range first.Data.Results {
+		statuses[r.UserID] = r.Status
+	}
+	if statuses[employee.ID] != models.BulkStatusSkipped || statuses[outsider.ID] != models.BulkStatusNotFound {
+		t.Fatalf("unexpected sign-off results: %+v", first.Data.Results)
+	}
+
+	unsigned := Expect(t, env.Do(t, http.MethodGet, Path("/manager/attendance/unsigned?start_date=%s&end_date=%s", today(), today()), manager.Token, nil), http.StatusOK)
+	var days struct {
+		Data []models.UnsignedDay `json:"data"`
+	}
+	unsigned.Decode(t, &days)
+	if len(days.Data) != 1 || days.Data[0].UserID != employee.ID {
+		t.Fatalf("expected one unsigned day for user %d, got: %s", employee.ID, unsigned.Body)
+	}
+
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkout"), employee.Token, models.CheckOutInput{}), http.StatusOK)
+	var second signOffBody
+	Expect(t, env.Do(t, http.MethodPost, Path("/manager/attendance/sign-off"), manager.Token, models.SignOffInput{Date: today()}), http.StatusOK).Decode(t, &second)
+	if len(second.Data.Results) != 1 || second.Data.Results[0].Status != models.BulkStatusUpdated {
+		t.Fatalf("expected employee to be signed off, got: %+v", second.Data.Results)
+	}
+
+	// Hari yang sudah di-sign-off terkunci untuk check-in dan koreksi.
+	rejected := Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusConflict)
+	var body struct {
+		Data struct {
+			Code string `json:"code"`
+		} `json:"data"`
+	}
+	rejected.Decode(t, &body)
+	if body.Data.Code != models.AttendanceSignedOffCode {
+		t.Fatalf("expected error code %s, got: %s", models.AttendanceSignedOffCode, rejected.Body)
+	}
+	note := "late entry"
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/attendance/%d/corrections", checkedIn.Data.AttendanceID), admin.Token, models.AttendanceCorrectionInput{
+		Notes: &note, Reason: "fix after sign-off",
+	}), http.StatusConflict)
+
+	report := Expect(t, env.Do(t, http.MethodGet, Path("/admin/attendance/report/unsigned?start_date=%s&end_date=%s&manager_id=%d", today(), today(), manager.ID), admin.Token, nil), http.StatusOK)
+	report.Decode(t, &days)
+	if len(days.Data) != 0 {
+		t.Fatalf("expected no unsigned days after sign-off, got: %s", report.Body)
+	}
+}
+
+func attendanceDisputeResolution(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	other := env.SignUp(t)
+	scheduleToday(t, env, admin, employee)
+	checkIn := Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusOK)
+	var checkedIn struct {
+		Data struct {
+			AttendanceID int `json:"attendance_id"`
+		} `json:"data"`
+	}
+	checkIn.Decode(t, &checkedIn)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkout"), employee.Token, models.CheckOutInput{}), http.StatusOK)
+
+	// Record milik user lain tidak bisa disanggah.
+	input := models.DisputeInput{Comment: "I left at 18:00, not earlier"}
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/%d/dispute", checkedIn.Data.AttendanceID), other.Token, input), http.StatusNotFound)
+	created := Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/%d/dispute", checkedIn.Data.AttendanceID), employee.Token, input), http.StatusCreated)
+	var dispute struct {
+		Data models.AttendanceDispute `json:"data"`
+	}
+	created.Decode(t, &dispute)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/%d/dispute", checkedIn.Data.AttendanceID), employee.Token, input), http.StatusConflict)
+
+	// Laporan admin menandai record dengan dispute open.
+	report := Expect(t, env.Do(t, http.MethodGet, Path("/admin/attendance/report?start_date=%s&end_date=%s&disputed=true", today(), today()), admin.Token, nil), http.StatusOK)
+	var body struct {
+		Data []models.Attendance `json:"data"`
+	}
+	report.Decode(t, &body)
+	if len(body.Data) != 1 || body.Data[0].OpenDisputeID == nil || *body.Data[0].OpenDisputeID != dispute.Data.ID {
+		t.Fatalf("expected disputed record %d in report, got: %s", checkedIn.Data.AttendanceID, report.Body)
+	}
+
+	resolve := models.ResolveDisputeInput{Status: models.DisputeResolved, Note: "Check-out corrected"}
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/attendance/disputes/%d/resolve", dispute.Data.ID), admin.Token, resolve), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/attendance/disputes/%d/resolve", dispute.Data.ID), admin.Token, resolve), http.StatusConflict)
+
+	mine := Expect(t, env.Do(t, http.MethodGet, Path("/user/attendance/disputes"), employee.Token, nil), http.StatusOK)
+	var list struct {
+		Data []models.AttendanceDispute `json:"data"`
+	}
+	mine.Decode(t, &list)
+	if len(list.Data) != 1 || list.Data[0].Status != models.DisputeResolved {
+		t.Fatalf("expected one resolved dispute, got: %s", mine.Body)
+	}
+}
+
+// deviceTokenRegistration: token perangkat push didaftarkan per user; token yang sama dari
+// akun lain berpindah pemilik, dan hanya pemilik saat ini yang bisa melepasnya.
+func deviceTokenRegistration(t *testing.T, env *Env) {
+	employee := env.SignUp(t)
+	other := env.SignUp(t)
+
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/devices"), employee.Token, models.RegisterDeviceInput{Platform: "sms", Token: "device-token-123"}), http.StatusBadRequest)
+	input := models.RegisterDeviceInput{Platform: models.DevicePlatformFCM, Token: "device-token-123"}
+	created := Expect(t, env.Do(t, http.MethodPost, Path("/user/devices"), employee.Token, input), http.StatusCreated)
+	var device struct {
+		Data models.DeviceToken `json:"data"`
+	}
+	created.Decode(t, &device)
+	if device.Data.UserID != employee.ID || device.Data.Platform != models.DevicePlatformFCM {
+		t.Fatalf("unexpected registered device: %s", created.Body)
+	}
+
+	// Perangkat berganti akun: token dipindahkan ke user yang login terakhir.
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/devices"), other.Token, input), http.StatusCreated)
+	Expect(t, env.Do(t, http.MethodDelete, Path("/user/devices/%s", input.Token), employee.Token, nil), http.StatusNotFound)
+	Expect(t, env.Do(t, http.MethodDelete, Path("/user/devices/%s", input.Token), other.Token, nil), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodDelete, Path("/user/devices/%s", input.Token), other.Token, nil), http.StatusNotFound)
+}
+
+// notificationInbox: jadwal yang dibuat admin masuk ke inbox karyawan (lewat outbox) sebagai
+// notifikasi belum dibaca; mark-read hanya berlaku untuk pemilik, mark-all mengosongkan hitungan belum dibaca.
+func notificationInbox(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	other := env.SignUp(t)
+	scheduleToday(t, env, admin, employee)
+	env.DispatchOutbox(t)
+
+	inbox := Expect(t, env.Do(t, http.MethodGet, Path("/user/notifications?unread=true"), employee.Token, nil), http.StatusOK)
+	var list struct {
+		Data []models.Notification `json:"data"`
+	}
+	inbox.Decode(t, &list)
+	if len(list.Data) != 1 || list.Data[0].Type != models.NotificationScheduleChanged || list.Data[0].Data["date"] != today() {
+		t.Fatalf("expected one schedule notification, got: %s", inbox.Body)
+	}
+	assertUnread := func(want int) {
+		t.Helper()
+		resp := Expect(t, env.Do(t, http.MethodGet, Path("/user/notifications/unread-count"), employee.Token, nil), http.StatusOK)
+		var body struct {
+			Data struct {
+				Unread int `json:"unread"`
+			} `json:"data"`
+		}
+		resp.Decode(t, &body)
+		if body.Data.Unread != want {
+			t.Fatalf("expected %d unread notifications, got: %s", want, resp.Body)
+		}
+	}
+	assertUnread(1)
+
+	id := list.Data[0].ID
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/notifications/%d/read", id), other.Token, nil), http.StatusNotFound)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/notifications/%d/read", id), employee.Token, nil), http.StatusOK)
+	assertUnread(0)
+
+	// Jadwal kedua (besok) menambah satu notifikasi baru; mark-all membersihkannya.
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(fixtures.DateLayout)
+	shift := Expect(t, env.Do(t, http.MethodPost, Path("/admin/shifts"), admin.Token, fixtures.Shift()), http.StatusCreated)
+	var created struct {
+		Data struct {
+			ShiftID int `json:"shift_id"`
+		} `json:"data"`
+	}
+	shift.Decode(t, &created)
+	Expect(t, env.Do(t, http.MethodPost, Path("/admin/schedules"), admin.Token, models.UserSchedule{
+		UserID: employee.ID, ShiftID: created.Data.ShiftID, Date: tomorrow,
+	}), http.StatusCreated)
+	env.DispatchOutbox(t)
+	assertUnread(1)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/notifications/read-all"), employee.Token, nil), http.StatusOK)
+	assertUnread(0)
+}
+
+// approvalInboxBulkDecision: dispute open muncul di approval inbox beserta jumlahnya; bulk
+// approve memproses item per item (tipe tidak dikenal dilewati) dan mengosongkan antrean.
+func approvalInboxBulkDecision(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	employee := env.SignUp(t)
+	scheduleToday(t, env, admin, employee)
+	checkIn := Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusOK)
+	var checkedIn struct {
+		Data struct {
+			AttendanceID int `json:"attendance_id"`
+		} `json:"data"`
+	}
+	checkIn.Decode(t, &checkedIn)
+	created := Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/%d/dispute", checkedIn.Data.AttendanceID), employee.Token,
+		models.DisputeInput{Comment: "Check-in time is wrong"}), http.StatusCreated)
+	var dispute struct {
+		Data models.AttendanceDispute `json:"data"`
+	}
+	created.Decode(t, &dispute)
+
+	type inbox struct {
+		Data struct {
+			Counts map[string]int        `json:"counts"`
+			Items  []models.ApprovalItem `json:"items"`
+		} `json:"data"`
+	}
+	pending := Expect(t, env.Do(t, http.MethodGet, Path("/admin/approvals"), admin.Token, nil), http.StatusOK)
+	var before inbox
+	pending.Decode(t, &before)
+	if before.Data.Counts[models.ApprovalTypeDispute] != 1 || len(before.Data.Items) != 1 || before.Data.Items[0].ID != dispute.Data.ID {
+		t.Fatalf("expected the dispute in the approval inbox, got: %s", pending.Body)
+	}
+	Expect(t, env.Do(t, http.MethodGet, Path("/admin/approvals?type=unknown"), admin.Token, nil), http.StatusBadRequest)
+	Expect(t, env.Do(t, http.MethodGet, Path("/admin/approvals"), employee.Token, nil), http.StatusForbidden)
+
+	decided := Expect(t, env.Do(t, http.MethodPost, Path("/admin/approvals/bulk"), admin.Token, models.BulkApprovalInput{
+		Action: models.ApprovalApprove, Note: "Verified with the gate log",
+		Items: []models.ApprovalRef{{Type: models.ApprovalTypeDispute, ID: dispute.Data.ID}, {Type: "leave_request", ID: 1}},
+	}), http.StatusOK)
+	var result struct {
+		Data struct {
+			Summary map[string]int `json:"summary"`
+		} `json:"data"`
+	}
+	decided.Decode(t, &result)
+	if result.Data.Summary[models.BulkStatusUpdated] != 1 || result.Data.Summary[models.BulkStatusSkipped] != 1 {
+		t.Fatalf("expected one updated and one skipped item, got: %s", decided.Body)
+	}
+
+	after := Expect(t, env.Do(t, http.MethodGet, Path("/admin/approvals"), admin.Token, nil), http.StatusOK)
+	var empty inbox
+	after.Decode(t, &empty)
+	if empty.Data.Counts[models.ApprovalTypeDispute] != 0 || len(empty.Data.Items) != 0 {
+		t.Fatalf("expected an empty approval inbox, got: %s", after.Body)
+	}
+}
+
+// delegatedSignOff: pemegang delegasi aktif men-sign-off tim atasan lewat on_behalf_of dan
+// tercatat sebagai penandatangan; tanpa delegasi (atau setelah dicabut) ditolak 403.
+func delegatedSignOff(t *testing.T, env *Env) {
+	admin := env.SignUp(t, fixtures.AsAdmin)
+	manager := env.SignUp(t)
+	deputy := env.SignUp(t)
+	employee := env.SignUp(t)
+	Expect(t, env.Do(t, http.MethodPut, Path("/admin/users/%d/manager", employee.ID), admin.Token, models.SetManagerInput{ManagerID: &manager.ID}), http.StatusOK)
+	scheduleToday(t, env, admin, employee)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkin"), employee.Token, models.CheckInInput{}), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/attendance/checkout"), employee.Token, models.CheckOutInput{}), http.StatusOK)
+
+	signOff := models.SignOffInput{Date: today(), OnBehalfOf: &manager.ID}
+	Expect(t, env.Do(t, http.MethodPost, Path("/manager/attendance/sign-off"), deputy.Token, signOff), http.StatusForbidden)
+
+	Expect(t, env.Do(t, http.MethodPost, Path("/user/delegations"), manager.Token, models.DelegationInput{
+		DelegateID: manager.ID, StartDate: today(), EndDate: today(),
+	}), http.StatusBadRequest)
+	created := Expect(t, env.Do(t, http.MethodPost, Path("/user/delegations"), manager.Token, models.DelegationInput{
+		DelegateID: deputy.ID, StartDate: today(), EndDate: time.Now().AddDate(0, 0, 7).Format(fixtures.DateLayout),
+	}), http.StatusCreated)
+	var delegation struct {
+		Data models.ApprovalDelegation `json:"data"`
+	}
+	created.Decode(t, &delegation)
+
+	unsigned := Expect(t, env.Do(t, http.MethodGet, Path("/manager/attendance/unsigned?start_date=%s&end_date=%s&on_behalf_of=%d", today(), today(), manager.ID), deputy.Token, nil), http.StatusOK)
+	var days struct {
+		Data []models.UnsignedDay `json:"data"`
+	}
+	unsigned.Decode(t, &days)
+	if len(days.Data) != 1 || days.Data[0].UserID != employee.ID {
+		t.Fatalf("expected the employee's unsigned day for the deputy, got: %s", unsigned.Body)
+	}
+	var signed struct {
+		Data struct {
+			Summary map[string]int `json:"summary"`
+		} `json:"data"`
+	}
+	Expect(t, env.Do(t, http.MethodPost, Path("/manager/attendance/sign-off"), deputy.Token, signOff), http.StatusOK).Decode(t, &signed)
+	if signed.Data.Summary[models.BulkStatusUpdated] != 1 {
+		t.Fatalf("expected the deputy to sign off one user, got: %+v", signed.Data.Summary)
+	}
+
+	// Feed aktivitas karyawan mencatat pemegang delegasi sebagai pelaku atas nama atasan.
+	activity := Expect(t, env.Do(t, http.MethodGet, Path("/admin/users/%d/activity", employee.ID), admin.Token, nil), http.StatusOK)
+	var feed struct {
+		Data struct {
+			Items []models.ActivityItem `json:"items"`
+		} `json:"data"`
+	}
+	activity.Decode(t, &feed)
+	found := false
+	for _, item := range feed.Data.Items {
+		if item.Action == models.AuditAttendanceSigned && item.ActorUserID != nil && *item.ActorUserID == deputy.ID && item.Details["on_behalf_of"] == float64(manager.ID) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a delegated sign-off in the activity feed, got: %s", activity.Body)
+	}
+
+	Expect(t, env.Do(t, http.MethodDelete, Path("/user/delegations/%d", delegation.Data.ID), deputy.Token, nil), http.StatusNotFound)
+	Expect(t, env.Do(t, http.MethodDelete, Path("/user/delegations/%d", delegation.Data.ID), manager.Token, nil), http.StatusOK)
+	Expect(t, env.Do(t, http.MethodDelete, Path("/user/delegations/%d", delegation.Data.ID), manager.Token, nil), http.StatusConflict)
+	Expect(t, env.Do(t, http.MethodGet, Path("/manager/attendance/unsigned?on_behalf_of=%d", manager.ID), deputy.Token, nil), http.StatusForbidden)
+}